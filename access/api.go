@@ -28,6 +28,7 @@ type API interface {
 	SendTransaction(ctx context.Context, tx *flow.TransactionBody) error
 	GetTransaction(ctx context.Context, id flow.Identifier) (*flow.TransactionBody, error)
 	GetTransactionResult(ctx context.Context, id flow.Identifier) (*TransactionResult, error)
+	GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier, offset uint, limit uint) ([]*TransactionResult, error)
 
 	GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error)
 	GetAccountAtLatestBlock(ctx context.Context, address flow.Address) (*flow.Account, error)
@@ -45,11 +46,12 @@ type API interface {
 
 // TODO: Combine this with flow.TransactionResult?
 type TransactionResult struct {
-	Status       flow.TransactionStatus
-	StatusCode   uint
-	Events       []flow.Event
-	ErrorMessage string
-	BlockID      flow.Identifier
+	Status        flow.TransactionStatus
+	StatusCode    uint
+	Events        []flow.Event
+	ErrorMessage  string
+	BlockID       flow.Identifier
+	TransactionID flow.Identifier
 }
 
 func TransactionResultToMessage(result *TransactionResult) *access.TransactionResultResponse {
@@ -9,6 +9,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	accessresults "github.com/onflow/flow-go/access/protobuf"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
 )
@@ -221,6 +222,39 @@ func (h *Handler) GetTransactionResult(
 	return TransactionResultToMessage(result), nil
 }
 
+// GetTransactionResultsByBlockID gets a page of the results of the transactions of a block, in the order
+// of the block's collections.
+func (h *Handler) GetTransactionResultsByBlockID(
+	ctx context.Context,
+	req *accessresults.GetTransactionResultsByBlockIDRequest,
+) (*accessresults.TransactionResultsResponse, error) {
+	blockID, err := convert.BlockID(req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := h.api.GetTransactionResultsByBlockID(ctx, blockID, uint(req.GetOffset()), uint(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*accessresults.TransactionResult, 0, len(results))
+	for _, result := range results {
+		messages = append(messages, &accessresults.TransactionResult{
+			Status:        entities.TransactionStatus(result.Status),
+			StatusCode:    uint32(result.StatusCode),
+			ErrorMessage:  result.ErrorMessage,
+			Events:        convert.EventsToMessages(result.Events),
+			BlockId:       result.BlockID[:],
+			TransactionId: result.TransactionID[:],
+		})
+	}
+
+	return &accessresults.TransactionResultsResponse{
+		TransactionResults: messages,
+	}, nil
+}
+
 // GetAccount returns an account by address at the latest sealed block.
 func (h *Handler) GetAccount(
 	ctx context.Context,
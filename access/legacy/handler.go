@@ -10,6 +10,7 @@ import (
 
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/access/legacy/convert"
+	legacyaccessresults "github.com/onflow/flow-go/access/legacy/protobuf"
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -197,6 +198,36 @@ func (h *Handler) GetTransactionResult(
 	return convert.TransactionResultToMessage(*result), nil
 }
 
+// GetTransactionResultsByBlockID gets a page of the results of the transactions of a block, in the order
+// of the block's collections.
+func (h *Handler) GetTransactionResultsByBlockID(
+	ctx context.Context,
+	req *legacyaccessresults.GetTransactionResultsByBlockIDRequest,
+) (*legacyaccessresults.TransactionResultsResponse, error) {
+	blockID := convert.MessageToIdentifier(req.GetBlockId())
+
+	results, err := h.api.GetTransactionResultsByBlockID(ctx, blockID, uint(req.GetOffset()), uint(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*legacyaccessresults.TransactionResult, 0, len(results))
+	for _, result := range results {
+		messages = append(messages, &legacyaccessresults.TransactionResult{
+			Status:        entitiesproto.TransactionStatus(result.Status),
+			StatusCode:    uint32(result.StatusCode),
+			ErrorMessage:  result.ErrorMessage,
+			Events:        convert.EventsToMessages(result.Events),
+			BlockId:       result.BlockID[:],
+			TransactionId: result.TransactionID[:],
+		})
+	}
+
+	return &legacyaccessresults.TransactionResultsResponse{
+		TransactionResults: messages,
+	}, nil
+}
+
 // GetAccount returns an account by address at the latest sealed block.
 func (h *Handler) GetAccount(
 	ctx context.Context,
@@ -203,15 +203,9 @@ func (v *TransactionValidator) checkExpiry(tx *flow.TransactionBody) error {
 		return fmt.Errorf("could not get finalized header: %w", err)
 	}
 
-	diff := final.Height - ref.Height
-	// check for overflow
-	if ref.Height > final.Height {
-		diff = 0
-	}
-
 	// discard transactions that are expired, or that will expire sooner than
 	// our configured buffer allows
-	if uint(diff) > v.options.Expiry-v.options.ExpiryBuffer {
+	if flow.IsExpired(ref.Height, final.Height, uint64(v.options.Expiry-v.options.ExpiryBuffer)) {
 		return ExpiredTransactionError{
 			RefHeight:   ref.Height,
 			FinalHeight: final.Height,
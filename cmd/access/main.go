@@ -2,27 +2,35 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
-	"time"
 
+	"github.com/dgraph-io/badger/v2"
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
 
 	"github.com/onflow/flow/protobuf/go/flow/access"
+	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
 
 	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/config"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
 	"github.com/onflow/flow-go/consensus/hotstuff/verification"
 	recovery "github.com/onflow/flow-go/consensus/recovery/protocol"
 	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/archive"
+	"github.com/onflow/flow-go/engine/access/indexer"
 	"github.com/onflow/flow-go/engine/access/ingestion"
 	pingeng "github.com/onflow/flow-go/engine/access/ping"
+	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
 	followereng "github.com/onflow/flow-go/engine/common/follower"
 	"github.com/onflow/flow-go/engine/common/requester"
 	synceng "github.com/onflow/flow-go/engine/common/synchronization"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/encodable"
 	"github.com/onflow/flow-go/model/encoding"
 	"github.com/onflow/flow-go/model/flow"
@@ -34,6 +42,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/signature"
 	"github.com/onflow/flow-go/module/synchronization"
+	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
 	storage "github.com/onflow/flow-go/storage/badger"
@@ -43,25 +52,29 @@ import (
 func main() {
 
 	var (
-		blockLimit                   uint
-		collectionLimit              uint
-		receiptLimit                 uint
+		conf                         = config.DefaultAccess()
 		collectionGRPCPort           uint
 		executionGRPCPort            uint
 		pingEnabled                  bool
 		nodeInfoFile                 string
 		apiRatelimits                map[string]int
 		apiBurstlimits               map[string]int
+		sealedResultCheck            string
 		followerState                protocol.MutableState
 		ingestEng                    *ingestion.Engine
 		requestEng                   *requester.Engine
 		followerEng                  *followereng.Engine
 		syncCore                     *synchronization.Core
-		rpcConf                      rpc.Config
 		rpcEng                       *rpc.Engine
 		collectionRPC                access.AccessAPIClient
 		executionNodeAddress         string // deprecated
 		historicalAccessRPCs         []access.AccessAPIClient
+		archiveDirs                  []string
+		archiveScriptAddrs           []string
+		archives                     *archive.Registry
+		stateIndexerCheckpoint       string
+		stateDeltaSource             *indexer.StateDeltaSource
+		indexedScripts               *indexer.ScriptExecutor
 		err                          error
 		conCache                     *buffer.PendingBlocks // pending block cache for follower
 		transactionTimings           *stdmap.TransactionTimings
@@ -75,25 +88,27 @@ func main() {
 		logTxTimeToFinalizedExecuted bool
 		retryEnabled                 bool
 		rpcMetricsEnabled            bool
+		publicNetworkAddress         string
+		publicNetwork                *p2p.Network
+		publicSyncCore               *synchronization.Core
+		restListenAddr               string
 	)
 
 	cmd.FlowNode(flow.RoleAccess.String()).
+		Config(&conf).
 		ExtraFlags(func(flags *pflag.FlagSet) {
-			flags.UintVar(&receiptLimit, "receipt-limit", 1000, "maximum number of execution receipts in the memory pool")
-			flags.UintVar(&collectionLimit, "collection-limit", 1000, "maximum number of collections in the memory pool")
-			flags.UintVar(&blockLimit, "block-limit", 1000, "maximum number of result blocks in the memory pool")
 			flags.UintVar(&collectionGRPCPort, "collection-ingress-port", 9000, "the grpc ingress port for all collection nodes")
 			flags.UintVar(&executionGRPCPort, "execution-ingress-port", 9000, "the grpc ingress port for all execution nodes")
-			flags.StringVarP(&rpcConf.GRPCListenAddr, "rpc-addr", "r", "localhost:9000", "the address the gRPC server listens on")
-			flags.StringVarP(&rpcConf.HTTPListenAddr, "http-addr", "h", "localhost:8000", "the address the http proxy server listens on")
-			flags.StringVarP(&rpcConf.CollectionAddr, "static-collection-ingress-addr", "", "", "the address (of the collection node) to send transactions to")
+			flags.StringVarP(&conf.RPC.GRPCListenAddr, "rpc-addr", "r", "localhost:9000", "the address the gRPC server listens on")
+			flags.StringVarP(&conf.RPC.HTTPListenAddr, "http-addr", "h", "localhost:8000", "the address the http proxy server listens on")
+			flags.StringVar(&restListenAddr, "rest-addr", "", "the address the REST API server listens on (empty disables the REST API)")
+			flags.StringVarP(&conf.RPC.CollectionAddr, "static-collection-ingress-addr", "", "", "the address (of the collection node) to send transactions to")
 			flags.StringVarP(&executionNodeAddress, "script-addr", "s", "localhost:9000", "the address (of the execution node) forward the script to")
-			flags.StringVarP(&rpcConf.HistoricalAccessAddrs, "historical-access-addr", "", "", "comma separated rpc addresses for historical access nodes")
-			flags.DurationVar(&rpcConf.CollectionClientTimeout, "collection-client-timeout", 3*time.Second, "grpc client timeout for a collection node")
-			flags.DurationVar(&rpcConf.ExecutionClientTimeout, "execution-client-timeout", 3*time.Second, "grpc client timeout for an execution node")
-			flags.UintVar(&rpcConf.MaxHeightRange, "rpc-max-height-range", backend.DefaultMaxHeightRange, "maximum size for height range requests")
-			flags.StringSliceVar(&rpcConf.PreferredExecutionNodeIDs, "preferred-execution-node-ids", nil, "comma separated list of execution nodes ids to choose from when making an upstream call e.g. b4a4dbdcd443d...,fb386a6a... etc.")
-			flags.StringSliceVar(&rpcConf.FixedExecutionNodeIDs, "fixed-execution-node-ids", nil, "comma separated list of execution nodes ids to choose from when making an upstream call if no matching preferred execution id is found e.g. b4a4dbdcd443d...,fb386a6a... etc.")
+			flags.StringVarP(&conf.RPC.HistoricalAccessAddrs, "historical-access-addr", "", "", "comma separated rpc addresses for historical access nodes")
+			flags.StringSliceVar(&archiveDirs, "archive-dirs", nil, "comma separated list of directories containing imported data of past sporks to serve historical queries from (enables archive mode)")
+			flags.StringSliceVar(&archiveScriptAddrs, "archive-script-addrs", nil, "comma separated list of addresses of the execution nodes to forward scripts against each archive to, in the order of --archive-dirs (an empty address disables script execution for the archive)")
+			flags.StringSliceVar(&conf.RPC.PreferredExecutionNodeIDs, "preferred-execution-node-ids", nil, "comma separated list of execution nodes ids to choose from when making an upstream call e.g. b4a4dbdcd443d...,fb386a6a... etc.")
+			flags.StringSliceVar(&conf.RPC.FixedExecutionNodeIDs, "fixed-execution-node-ids", nil, "comma separated list of execution nodes ids to choose from when making an upstream call if no matching preferred execution id is found e.g. b4a4dbdcd443d...,fb386a6a... etc.")
 			flags.BoolVar(&logTxTimeToFinalized, "log-tx-time-to-finalized", false, "log transaction time to finalized")
 			flags.BoolVar(&logTxTimeToExecuted, "log-tx-time-to-executed", false, "log transaction time to executed")
 			flags.BoolVar(&logTxTimeToFinalizedExecuted, "log-tx-time-to-finalized-executed", false, "log transaction time to finalized and executed")
@@ -103,6 +118,9 @@ func main() {
 			flags.StringVarP(&nodeInfoFile, "node-info-file", "", "", "full path to a json file which provides more details about nodes when reporting its reachability metrics")
 			flags.StringToIntVar(&apiRatelimits, "api-rate-limits", nil, "per second rate limits for Access API methods e.g. Ping=300,GetTransaction=500 etc.")
 			flags.StringToIntVar(&apiBurstlimits, "api-burst-limits", nil, "burst limits for Access API methods e.g. Ping=100,GetTransaction=100 etc.")
+			flags.StringVar(&publicNetworkAddress, "public-network-address", "", "address of the public network which unstaked observers follow the chain through (empty disables the public network)")
+			flags.StringVar(&stateIndexerCheckpoint, "state-indexer-checkpoint", "", "path to the checkpoint of the root execution state the state indexer starts from (defaults to the root checkpoint in the bootstrap directory)")
+			flags.StringVar(&sealedResultCheck, "sealed-result-check", "disabled", "check of transaction results served from execution nodes against the sealed results of their blocks: disabled, flag (log contradicting results) or refuse (don't serve contradicting results)")
 		}).
 		Module("mutable follower state", func(node *cmd.FlowNodeBuilder) error {
 			// For now, we only support state implementations from package badger.
@@ -122,20 +140,20 @@ func main() {
 		}).
 		Module("collection node client", func(node *cmd.FlowNodeBuilder) error {
 			// collection node address is optional (if not specified, collection nodes will be chosen at random)
-			if strings.TrimSpace(rpcConf.CollectionAddr) == "" {
+			if strings.TrimSpace(conf.RPC.CollectionAddr) == "" {
 				node.Logger.Info().Msg("using a dynamic collection node address")
 				return nil
 			}
 
 			node.Logger.Info().
-				Str("collection_node", rpcConf.CollectionAddr).
+				Str("collection_node", conf.RPC.CollectionAddr).
 				Msg("using the static collection node address")
 
 			collectionRPCConn, err := grpc.Dial(
-				rpcConf.CollectionAddr,
+				conf.RPC.CollectionAddr,
 				grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(grpcutils.DefaultMaxMsgSize)),
 				grpc.WithInsecure(),
-				backend.WithClientUnaryInterceptor(rpcConf.CollectionClientTimeout))
+				backend.WithClientUnaryInterceptor(conf.RPC.CollectionClientTimeout))
 			if err != nil {
 				return err
 			}
@@ -143,7 +161,7 @@ func main() {
 			return nil
 		}).
 		Module("historical access node clients", func(node *cmd.FlowNodeBuilder) error {
-			addrs := strings.Split(rpcConf.HistoricalAccessAddrs, ",")
+			addrs := strings.Split(conf.RPC.HistoricalAccessAddrs, ",")
 			for _, addr := range addrs {
 				if strings.TrimSpace(addr) == "" {
 					continue
@@ -161,12 +179,64 @@ func main() {
 			}
 			return nil
 		}).
+		Module("archive data sources", func(node *cmd.FlowNodeBuilder) error {
+			if len(archiveDirs) == 0 {
+				return nil
+			}
+			if len(archiveScriptAddrs) > len(archiveDirs) {
+				return fmt.Errorf("more archive script addresses (%d) than archive directories (%d)", len(archiveScriptAddrs), len(archiveDirs))
+			}
+
+			archives, err = archive.NewRegistry()
+			if err != nil {
+				return err
+			}
+			for i, dir := range archiveDirs {
+				var scripts archive.ScriptExecutor
+				if i < len(archiveScriptAddrs) && archiveScriptAddrs[i] != "" {
+					scriptConn, err := grpc.Dial(
+						archiveScriptAddrs[i],
+						grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(grpcutils.DefaultMaxMsgSize)),
+						grpc.WithInsecure())
+					if err != nil {
+						return fmt.Errorf("could not connect to archive execution node at %s: %w", archiveScriptAddrs[i], err)
+					}
+					scripts = archive.NewExecutionNodeScriptExecutor(execproto.NewExecutionAPIClient(scriptConn))
+				}
+
+				db, err := badger.Open(badger.DefaultOptions(dir).WithReadOnly(true).WithLogger(nil))
+				if err != nil {
+					return fmt.Errorf("could not open archive at %s: %w", dir, err)
+				}
+				source, err := archive.ImportStorageSource(node.Metrics.Cache, db, scripts)
+				if err != nil {
+					return fmt.Errorf("could not import archive at %s: %w", dir, err)
+				}
+				err = archives.Register(source)
+				if err != nil {
+					return fmt.Errorf("could not register archive at %s: %w", dir, err)
+				}
+
+				first, last := source.Range()
+				node.Logger.Info().
+					Str("dir", dir).
+					Uint64("first_height", first).
+					Uint64("last_height", last).
+					Msg("serving archived spork data")
+			}
+			return nil
+		}).
 		Module("block cache", func(node *cmd.FlowNodeBuilder) error {
 			conCache = buffer.NewPendingBlocks()
 			return nil
 		}).
 		Module("sync core", func(node *cmd.FlowNodeBuilder) error {
 			syncCore, err = synchronization.New(node.Logger, synchronization.DefaultConfig())
+			if err != nil {
+				return err
+			}
+			// the public sync engine only serves the observers, so it gets its own core
+			publicSyncCore, err = synchronization.New(node.Logger, synchronization.DefaultConfig())
 			return err
 		}).
 		Module("transaction timing mempools", func(node *cmd.FlowNodeBuilder) error {
@@ -197,18 +267,99 @@ func main() {
 			pingMetrics = metrics.NewPingCollector()
 			return nil
 		}).
+		Component("archive data sources", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			// the registry closes the archive databases on shutdown
+			if archives == nil {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			return archives, nil
+		}).
+		Component("state delta source", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if !conf.StateIndexer {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+
+			// state deltas are requested from the execution nodes scripts are sent to, if any are configured
+			var trusted flow.IdentifierList
+			for _, ids := range [][]string{conf.RPC.PreferredExecutionNodeIDs, conf.RPC.FixedExecutionNodeIDs} {
+				for _, idStr := range ids {
+					id, err := flow.HexStringToIdentifier(idStr)
+					if err != nil {
+						return nil, fmt.Errorf("invalid execution node ID %s: %w", idStr, err)
+					}
+					trusted = append(trusted, id)
+				}
+			}
+			syncFilter := filter.Any
+			if len(trusted) > 0 {
+				syncFilter = filter.HasNodeID(trusted...)
+			}
+
+			stateDeltaSource, err = indexer.NewStateDeltaSource(
+				node.Logger,
+				node.Network,
+				node.Me,
+				node.State,
+				node.Storage.Seals,
+				node.Storage.Results,
+				syncFilter,
+				conf.StateIndexerMaxRange,
+				conf.StateIndexerRetryInterval,
+			)
+			return stateDeltaSource, err
+		}).
+		Component("execution state indexer", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if !conf.StateIndexer {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+
+			// the index starts with the sealed state of the root block, read from the root checkpoint
+			checkpoint := stateIndexerCheckpoint
+			if checkpoint == "" {
+				checkpoint = filepath.Join(node.BaseConfig.BootstrapDir, bootstrap.PathRootCheckpoint)
+			}
+			root, err := node.Storage.Headers.ByBlockID(node.RootSeal.BlockID)
+			if err != nil {
+				return nil, fmt.Errorf("could not get sealed root block: %w", err)
+			}
+			source := indexer.NewCheckpointSource(root.ID(), node.RootSeal.FinalState, checkpoint, stateDeltaSource)
+
+			stateIndexer := indexer.New(
+				node.Logger,
+				metrics.NewExecutionStateIndexerCollector(),
+				node.State,
+				node.Storage.Headers,
+				storage.NewRegisterIndex(node.DB),
+				source,
+				root.Height,
+				indexer.DefaultIndexInterval,
+			)
+
+			vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+			vmCtx := fvm.NewContext(node.Logger, node.FvmOptions...)
+			indexedScripts = indexer.NewScriptExecutor(vm, vmCtx, node.Storage.Headers, stateIndexer)
+
+			return stateIndexer, nil
+		}).
 		Component("RPC engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			conf.RPC.SealedResultCheck, err = backend.ParseSealedResultCheck(sealedResultCheck)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sealed result check: %w", err)
+			}
 			rpcEng = rpc.New(
 				node.Logger,
 				node.State,
-				rpcConf,
+				conf.RPC,
 				collectionRPC,
 				historicalAccessRPCs,
+				archives,
+				indexedScripts,
 				node.Storage.Blocks,
 				node.Storage.Headers,
 				node.Storage.Collections,
 				node.Storage.Transactions,
 				node.Storage.Receipts,
+				node.Storage.Seals,
 				node.RootChainID,
 				transactionMetrics,
 				collectionGRPCPort,
@@ -220,6 +371,12 @@ func main() {
 			)
 			return rpcEng, nil
 		}).
+		Component("REST engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if restListenAddr == "" {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			return rest.New(node.Logger, rpcEng.API(), node.RootChainID.Chain(), restListenAddr, rest.DefaultRequestTimeout), nil
+		}).
 		Component("ingestion engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
 			requestEng, err = requester.New(
 				node.Logger,
@@ -318,6 +475,41 @@ func main() {
 			}
 			return sync, nil
 		}).
+		Component("public network", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if publicNetworkAddress == "" {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			publicNetwork, err = node.InitPublicNetwork(publicNetworkAddress)
+			if err != nil {
+				return nil, fmt.Errorf("could not create public network: %w", err)
+			}
+			return publicNetwork, nil
+		}).
+		Component("public sync engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if publicNetwork == nil {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			// serves the sync requests of the observers, without requesting any blocks from them
+			sync, err := synceng.New(
+				node.Logger,
+				node.Metrics.Engine,
+				publicNetwork,
+				node.Me,
+				node.State,
+				node.Storage.Blocks,
+				followerEng,
+				publicSyncCore,
+				synceng.WithChannel(engine.PublicSyncCommittee),
+				synceng.WithTargets(func() (flow.IdentifierList, error) {
+					return nil, nil
+				}),
+				synceng.WithUnicastRequests(),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not create public synchronization engine: %w", err)
+			}
+			return sync, nil
+		}).
 		Component("ping engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
 			ping, err := pingeng.New(
 				node.Logger,
@@ -37,6 +37,7 @@ var (
 	flagEpochCounter                uint64
 	flagServiceAccountPublicKeyJSON string
 	flagGenesisTokenSupply          string
+	flagRegisterSnapshot            string
 )
 
 // PartnerStakes ...
@@ -99,6 +100,8 @@ func addFinalizeCmdFlags() {
 		"encoded json of public key for the service account")
 	finalizeCmd.Flags().StringVar(&flagGenesisTokenSupply, "genesis-token-supply", "10000000.00000000",
 		"genesis flow token supply")
+	finalizeCmd.Flags().StringVar(&flagRegisterSnapshot, "register-snapshot", "",
+		"path to a register snapshot (as exported by the util tool) imported into the generated execution state")
 }
 
 func finalize(cmd *cobra.Command, args []string) {
@@ -138,10 +141,11 @@ func finalize(cmd *cobra.Command, args []string) {
 		if err != nil {
 			log.Fatal().Err(err).Msg("invalid genesis token supply")
 		}
-		commit, err = run.GenerateExecutionState(
+		commit, err = run.GenerateExecutionStateWithSnapshot(
 			filepath.Join(flagOutdir, model.DirnameExecutionState),
 			serviceAccountPublicKey,
 			parseChainID(flagRootChain).Chain(),
+			flagRegisterSnapshot,
 			fvm.WithInitialTokenSupply(value),
 			fvm.WithMinimumStorageReservation(fvm.DefaultMinimumStorageReservation),
 			fvm.WithAccountCreationFee(fvm.DefaultAccountCreationFee),
@@ -1,6 +1,8 @@
 package run
 
 import (
+	"io"
+
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/crypto"
@@ -35,6 +37,28 @@ func GenerateExecutionState(
 	chain flow.Chain,
 	bootstrapOptions ...fvm.BootstrapProcedureOption,
 ) (flow.StateCommitment, error) {
+	return GenerateExecutionStateWithSnapshot(dbDir, accountKey, chain, "", bootstrapOptions...)
+}
+
+// GenerateExecutionStateWithSnapshot generates the execution state like GenerateExecutionState, and
+// imports the register snapshot at the given path on top of it. An empty path imports no snapshot.
+func GenerateExecutionStateWithSnapshot(
+	dbDir string,
+	accountKey flow.AccountPublicKey,
+	chain flow.Chain,
+	snapshotPath string,
+	bootstrapOptions ...fvm.BootstrapProcedureOption,
+) (flow.StateCommitment, error) {
+	var snapshot io.Reader
+	if snapshotPath != "" {
+		snapshotFile, err := bootstrap.OpenRegisterSnapshot(snapshotPath)
+		if err != nil {
+			return flow.DummyStateCommitment, err
+		}
+		defer snapshotFile.Close()
+		snapshot = snapshotFile
+	}
+
 	metricsCollector := &metrics.NoopCollector{}
 
 	diskWal, err := wal.NewDiskWAL(zerolog.Nop(), nil, metricsCollector, dbDir, 100, pathfinder.PathByteSize, wal.SegmentSize)
@@ -51,10 +75,11 @@ func GenerateExecutionState(
 	}
 
 	return bootstrap.NewBootstrapper(
-		zerolog.Nop()).BootstrapLedger(
+		zerolog.Nop()).BootstrapLedgerWithSnapshot(
 		ledgerStorage,
 		accountKey,
 		chain,
+		snapshot,
 		bootstrapOptions...,
 	)
 }
@@ -2,15 +2,14 @@ package main
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/config"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
-	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 	"github.com/onflow/flow-go/consensus/hotstuff/verification"
 	recovery "github.com/onflow/flow-go/consensus/recovery/protocol"
 	"github.com/onflow/flow-go/engine"
@@ -46,22 +45,12 @@ import (
 func main() {
 
 	var (
-		txLimit                                uint
-		maxCollectionSize                      uint
-		maxCollectionByteSize                  uint64
-		maxCollectionTotalGas                  uint64
-		builderExpiryBuffer                    uint
-		builderPayerRateLimit                  float64
-		builderUnlimitedPayers                 []string
-		hotstuffTimeout                        time.Duration
-		hotstuffMinTimeout                     time.Duration
-		hotstuffTimeoutIncreaseFactor          float64
-		hotstuffTimeoutDecreaseFactor          float64
-		hotstuffTimeoutVoteAggregationFraction float64
-		blockRateDelay                         time.Duration
+		conf                          = config.DefaultCollection()
+		builderUnlimitedPayers        []string
+		builderSequenceNumberOrdering bool
+		builderPriorityOrdering       bool
 
 		followerState protocol.MutableState
-		ingestConf    ingest.Config
 		ingressConf   ingress.Config
 
 		pools          *epochpool.TransactionPools // epoch-scoped transaction pools
@@ -76,54 +65,18 @@ func main() {
 	)
 
 	cmd.FlowNode(flow.RoleCollection.String()).
+		Config(&conf).
 		ExtraFlags(func(flags *pflag.FlagSet) {
-			flags.UintVar(&txLimit, "tx-limit", 50000,
-				"maximum number of transactions in the memory pool")
 			flags.StringVarP(&ingressConf.ListenAddr, "ingress-addr", "i", "localhost:9000",
 				"the address the ingress server listens on")
 			flags.BoolVar(&ingressConf.RpcMetricsEnabled, "rpc-metrics-enabled", false,
 				"whether to enable the rpc metrics")
-			flags.Uint64Var(&ingestConf.MaxGasLimit, "ingest-max-gas-limit", flow.DefaultMaxTransactionGasLimit,
-				"maximum per-transaction computation limit (gas limit)")
-			flags.Uint64Var(&ingestConf.MaxTransactionByteSize, "ingest-max-tx-byte-size", flow.DefaultMaxTransactionByteSize,
-				"maximum per-transaction byte size")
-			flags.Uint64Var(&ingestConf.MaxCollectionByteSize, "ingest-max-col-byte-size", flow.DefaultMaxCollectionByteSize,
-				"maximum per-collection byte size")
-			flags.BoolVar(&ingestConf.CheckScriptsParse, "ingest-check-scripts-parse", true,
-				"whether we check that inbound transactions are parse-able")
-			flags.UintVar(&ingestConf.ExpiryBuffer, "ingest-expiry-buffer", 30,
-				"expiry buffer for inbound transactions")
-			flags.UintVar(&ingestConf.PropagationRedundancy, "ingest-tx-propagation-redundancy", 10,
-				"how many additional cluster members we propagate transactions to")
-			flags.Uint64Var(&ingestConf.MaxAddressIndex, "ingest-max-address-index", 10_000_000,
-				"the maximum address index allowed in transactions")
-			flags.UintVar(&builderExpiryBuffer, "builder-expiry-buffer", builder.DefaultExpiryBuffer,
-				"expiry buffer for transactions in proposed collections")
-			flags.Float64Var(&builderPayerRateLimit, "builder-rate-limit", builder.DefaultMaxPayerTransactionRate, // no rate limiting
-				"rate limit for each payer (transactions/collection)")
 			flags.StringSliceVar(&builderUnlimitedPayers, "builder-unlimited-payers", []string{}, // no unlimited payers
 				"set of payer addresses which are omitted from rate limiting")
-			flags.UintVar(&maxCollectionSize, "builder-max-collection-size", flow.DefaultMaxCollectionSize,
-				"maximum number of transactions in proposed collections")
-			flags.Uint64Var(&maxCollectionByteSize, "builder-max-collection-byte-size", flow.DefaultMaxCollectionByteSize,
-				"maximum byte size of the proposed collection")
-			flags.Uint64Var(&maxCollectionTotalGas, "builder-max-collection-total-gas", flow.DefaultMaxCollectionTotalGas,
-				"maximum total amount of maxgas of transactions in proposed collections")
-			flags.DurationVar(&hotstuffTimeout, "hotstuff-timeout", 60*time.Second,
-				"the initial timeout for the hotstuff pacemaker")
-			flags.DurationVar(&hotstuffMinTimeout, "hotstuff-min-timeout", 2500*time.Millisecond,
-				"the lower timeout bound for the hotstuff pacemaker")
-			flags.Float64Var(&hotstuffTimeoutIncreaseFactor, "hotstuff-timeout-increase-factor",
-				timeout.DefaultConfig.TimeoutIncrease,
-				"multiplicative increase of timeout value in case of time out event")
-			flags.Float64Var(&hotstuffTimeoutDecreaseFactor, "hotstuff-timeout-decrease-factor",
-				timeout.DefaultConfig.TimeoutDecrease,
-				"multiplicative decrease of timeout value in case of progress")
-			flags.Float64Var(&hotstuffTimeoutVoteAggregationFraction, "hotstuff-timeout-vote-aggregation-fraction",
-				timeout.DefaultConfig.VoteAggregationTimeoutFraction,
-				"additional fraction of replica timeout that the primary will wait for votes")
-			flags.DurationVar(&blockRateDelay, "block-rate-delay", 250*time.Millisecond,
-				"the delay to broadcast block proposal in order to control block production rate")
+			flags.BoolVar(&builderSequenceNumberOrdering, "builder-sequence-number-ordering", false,
+				"whether to order the transactions of each proposal key by sequence number in proposed collections")
+			flags.BoolVar(&builderPriorityOrdering, "builder-priority-ordering", false,
+				"whether to include the transactions closest to expiry first in proposed collections")
 		}).
 		Module("mutable follower state", func(node *cmd.FlowNodeBuilder) error {
 			// For now, we only support state implementations from package badger.
@@ -142,7 +95,7 @@ func main() {
 			return err
 		}).
 		Module("transactions mempool", func(node *cmd.FlowNodeBuilder) error {
-			create := func() mempool.Transactions { return stdmap.NewTransactions(txLimit) }
+			create := func() mempool.Transactions { return stdmap.NewTransactions(conf.TxLimit) }
 			pools = epochpool.NewTransactionPools(create)
 			err := node.Metrics.Mempool.Register(metrics.ResourceTransaction, pools.CombinedSize)
 			return err
@@ -258,7 +211,7 @@ func main() {
 				node.Me,
 				node.RootChainID.Chain(),
 				pools,
-				ingestConf,
+				conf.Ingest,
 			)
 			return ing, err
 		}).
@@ -306,18 +259,28 @@ func main() {
 				unlimitedPayers = append(unlimitedPayers, payerAddr)
 			}
 
+			builderOpts := []builder.Opt{
+				builder.WithMaxCollectionSize(conf.MaxCollectionSize),
+				builder.WithMaxCollectionByteSize(conf.MaxCollectionByteSize),
+				builder.WithMaxCollectionTotalGas(conf.MaxCollectionTotalGas),
+				builder.WithExpiryBuffer(conf.BuilderExpiryBuffer),
+				builder.WithMaxPayerTransactionRate(conf.BuilderPayerRateLimit),
+				builder.WithUnlimitedPayers(unlimitedPayers...),
+			}
+			if builderSequenceNumberOrdering {
+				builderOpts = append(builderOpts, builder.WithSequenceNumberOrdering(conf.BuilderFutureSequenceNumberDelay))
+			}
+			if builderPriorityOrdering {
+				builderOpts = append(builderOpts, builder.WithTransactionOrdering(builder.TransactionOrderPriority))
+			}
+
 			builderFactory, err := factories.NewBuilderFactory(
 				node.DB,
 				node.Storage.Headers,
 				node.Tracer,
 				colMetrics,
 				push,
-				builder.WithMaxCollectionSize(maxCollectionSize),
-				builder.WithMaxCollectionByteSize(maxCollectionByteSize),
-				builder.WithMaxCollectionTotalGas(maxCollectionTotalGas),
-				builder.WithExpiryBuffer(builderExpiryBuffer),
-				builder.WithMaxPayerTransactionRate(builderPayerRateLimit),
-				builder.WithUnlimitedPayers(unlimitedPayers...),
+				builderOpts...,
 			)
 			if err != nil {
 				return nil, err
@@ -353,12 +316,12 @@ func main() {
 				node.Me,
 				node.DB,
 				node.State,
-				consensus.WithBlockRateDelay(blockRateDelay),
-				consensus.WithInitialTimeout(hotstuffTimeout),
-				consensus.WithMinTimeout(hotstuffMinTimeout),
-				consensus.WithVoteAggregationTimeoutFraction(hotstuffTimeoutVoteAggregationFraction),
-				consensus.WithTimeoutIncreaseFactor(hotstuffTimeoutIncreaseFactor),
-				consensus.WithTimeoutDecreaseFactor(hotstuffTimeoutDecreaseFactor),
+				consensus.WithBlockRateDelay(conf.HotStuff.BlockRateDelay),
+				consensus.WithInitialTimeout(conf.HotStuff.Timeout),
+				consensus.WithMinTimeout(conf.HotStuff.MinTimeout),
+				consensus.WithVoteAggregationTimeoutFraction(conf.HotStuff.VoteAggregationTimeoutFraction),
+				consensus.WithTimeoutIncreaseFactor(conf.HotStuff.TimeoutIncreaseFactor),
+				consensus.WithTimeoutDecreaseFactor(conf.HotStuff.TimeoutDecreaseFactor),
 			)
 			if err != nil {
 				return nil, err
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+
+	"github.com/onflow/flow-go/engine/access/rpc"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+)
+
+// Access holds the tunables of the engines of access nodes. The RPC configuration also
+// holds the addresses of the servers and upstream nodes, which are bound to flags by the
+// access node itself, as they aren't tunables.
+type Access struct {
+	ReceiptLimit    uint `mapstructure:"receipt-limit"`
+	CollectionLimit uint `mapstructure:"collection-limit"`
+	BlockLimit      uint `mapstructure:"block-limit"`
+
+	StateIndexer              bool          `mapstructure:"state-indexer"`
+	StateIndexerMaxRange      uint64        `mapstructure:"state-indexer-max-range"`
+	StateIndexerRetryInterval time.Duration `mapstructure:"state-indexer-retry-interval"`
+
+	RPC rpc.Config `mapstructure:",squash"`
+}
+
+// DefaultAccess returns the default tunables of access nodes.
+func DefaultAccess() Access {
+	return Access{
+		ReceiptLimit:    1000,
+		CollectionLimit: 1000,
+		BlockLimit:      1000,
+
+		StateIndexerMaxRange:      100,
+		StateIndexerRetryInterval: 10 * time.Second,
+
+		RPC: rpc.Config{
+			CollectionClientTimeout: 3 * time.Second,
+			ExecutionClientTimeout:  3 * time.Second,
+			MaxHeightRange:          backend.DefaultMaxHeightRange,
+		},
+	}
+}
+
+func (a *Access) Flags(flags *pflag.FlagSet) {
+	flags.UintVar(&a.ReceiptLimit, "receipt-limit", a.ReceiptLimit, "maximum number of execution receipts in the memory pool")
+	flags.UintVar(&a.CollectionLimit, "collection-limit", a.CollectionLimit, "maximum number of collections in the memory pool")
+	flags.UintVar(&a.BlockLimit, "block-limit", a.BlockLimit, "maximum number of result blocks in the memory pool")
+	flags.BoolVar(&a.StateIndexer, "state-indexer", a.StateIndexer, "index the execution state of sealed blocks from the state deltas of execution nodes, and execute scripts against it locally")
+	flags.Uint64Var(&a.StateIndexerMaxRange, "state-indexer-max-range", a.StateIndexerMaxRange, "maximum number of blocks whose state deltas are requested at once")
+	flags.DurationVar(&a.StateIndexerRetryInterval, "state-indexer-retry-interval", a.StateIndexerRetryInterval, "interval after which missing state deltas are requested again")
+	flags.DurationVar(&a.RPC.CollectionClientTimeout, "collection-client-timeout", a.RPC.CollectionClientTimeout, "grpc client timeout for a collection node")
+	flags.DurationVar(&a.RPC.ExecutionClientTimeout, "execution-client-timeout", a.RPC.ExecutionClientTimeout, "grpc client timeout for an execution node")
+	flags.UintVar(&a.RPC.MaxHeightRange, "rpc-max-height-range", a.RPC.MaxHeightRange, "maximum size for height range requests")
+}
+
+func (a *Access) Validate() error {
+	var errs *multierror.Error
+
+	if a.ReceiptLimit == 0 || a.CollectionLimit == 0 || a.BlockLimit == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("memory pool limits must be positive"))
+	}
+	if a.RPC.CollectionClientTimeout <= 0 || a.RPC.ExecutionClientTimeout <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("collection-client-timeout and execution-client-timeout must be positive"))
+	}
+	if a.StateIndexer && (a.StateIndexerMaxRange == 0 || a.StateIndexerRetryInterval <= 0) {
+		errs = multierror.Append(errs, fmt.Errorf("state-indexer-max-range and state-indexer-retry-interval must be positive if the state indexer is enabled"))
+	}
+	if a.RPC.MaxHeightRange == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("rpc-max-height-range must be positive"))
+	}
+
+	return errs.ErrorOrNil()
+}
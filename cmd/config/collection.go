@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+	"github.com/onflow/flow-go/engine/collection/ingest"
+	"github.com/onflow/flow-go/model/flow"
+	builder "github.com/onflow/flow-go/module/builder/collection"
+)
+
+// Collection holds the tunables of the engines of collection nodes.
+type Collection struct {
+	TxLimit uint `mapstructure:"tx-limit"`
+
+	Ingest ingest.Config `mapstructure:",squash"`
+
+	MaxCollectionSize                uint          `mapstructure:"builder-max-collection-size"`
+	MaxCollectionByteSize            uint64        `mapstructure:"builder-max-collection-byte-size"`
+	MaxCollectionTotalGas            uint64        `mapstructure:"builder-max-collection-total-gas"`
+	BuilderExpiryBuffer              uint          `mapstructure:"builder-expiry-buffer"`
+	BuilderPayerRateLimit            float64       `mapstructure:"builder-rate-limit"`
+	BuilderFutureSequenceNumberDelay time.Duration `mapstructure:"builder-future-sequence-number-delay"`
+
+	HotStuff `mapstructure:",squash"`
+}
+
+// DefaultCollection returns the default tunables of collection nodes.
+func DefaultCollection() Collection {
+	return Collection{
+		TxLimit: 50000,
+		Ingest: ingest.Config{
+			ExpiryBuffer:           30,
+			MaxGasLimit:            flow.DefaultMaxTransactionGasLimit,
+			CheckScriptsParse:      true,
+			MaxAddressIndex:        10_000_000,
+			PropagationRedundancy:  10,
+			MaxTransactionByteSize: flow.DefaultMaxTransactionByteSize,
+			MaxCollectionByteSize:  flow.DefaultMaxCollectionByteSize,
+		},
+		MaxCollectionSize:                flow.DefaultMaxCollectionSize,
+		MaxCollectionByteSize:            flow.DefaultMaxCollectionByteSize,
+		MaxCollectionTotalGas:            flow.DefaultMaxCollectionTotalGas,
+		BuilderExpiryBuffer:              builder.DefaultExpiryBuffer,
+		BuilderPayerRateLimit:            builder.DefaultMaxPayerTransactionRate, // no rate limiting
+		BuilderFutureSequenceNumberDelay: 2 * time.Second,
+		HotStuff: HotStuff{
+			Timeout:                        60 * time.Second,
+			MinTimeout:                     2500 * time.Millisecond,
+			TimeoutIncreaseFactor:          timeout.DefaultConfig.TimeoutIncrease,
+			TimeoutDecreaseFactor:          timeout.DefaultConfig.TimeoutDecrease,
+			VoteAggregationTimeoutFraction: timeout.DefaultConfig.VoteAggregationTimeoutFraction,
+			BlockRateDelay:                 250 * time.Millisecond,
+		},
+	}
+}
+
+func (c *Collection) Flags(flags *pflag.FlagSet) {
+	flags.UintVar(&c.TxLimit, "tx-limit", c.TxLimit,
+		"maximum number of transactions in the memory pool")
+	flags.Uint64Var(&c.Ingest.MaxGasLimit, "ingest-max-gas-limit", c.Ingest.MaxGasLimit,
+		"maximum per-transaction computation limit (gas limit)")
+	flags.Uint64Var(&c.Ingest.MaxTransactionByteSize, "ingest-max-tx-byte-size", c.Ingest.MaxTransactionByteSize,
+		"maximum per-transaction byte size")
+	flags.Uint64Var(&c.Ingest.MaxCollectionByteSize, "ingest-max-col-byte-size", c.Ingest.MaxCollectionByteSize,
+		"maximum per-collection byte size")
+	flags.BoolVar(&c.Ingest.CheckScriptsParse, "ingest-check-scripts-parse", c.Ingest.CheckScriptsParse,
+		"whether we check that inbound transactions are parse-able")
+	flags.UintVar(&c.Ingest.ExpiryBuffer, "ingest-expiry-buffer", c.Ingest.ExpiryBuffer,
+		"expiry buffer for inbound transactions")
+	flags.UintVar(&c.Ingest.PropagationRedundancy, "ingest-tx-propagation-redundancy", c.Ingest.PropagationRedundancy,
+		"how many additional cluster members we propagate transactions to")
+	flags.Uint64Var(&c.Ingest.MaxAddressIndex, "ingest-max-address-index", c.Ingest.MaxAddressIndex,
+		"the maximum address index allowed in transactions")
+	flags.UintVar(&c.BuilderExpiryBuffer, "builder-expiry-buffer", c.BuilderExpiryBuffer,
+		"expiry buffer for transactions in proposed collections")
+	flags.Float64Var(&c.BuilderPayerRateLimit, "builder-rate-limit", c.BuilderPayerRateLimit,
+		"rate limit for each payer (transactions/collection)")
+	flags.UintVar(&c.MaxCollectionSize, "builder-max-collection-size", c.MaxCollectionSize,
+		"maximum number of transactions in proposed collections")
+	flags.Uint64Var(&c.MaxCollectionByteSize, "builder-max-collection-byte-size", c.MaxCollectionByteSize,
+		"maximum byte size of the proposed collection")
+	flags.Uint64Var(&c.MaxCollectionTotalGas, "builder-max-collection-total-gas", c.MaxCollectionTotalGas,
+		"maximum total amount of maxgas of transactions in proposed collections")
+	flags.DurationVar(&c.BuilderFutureSequenceNumberDelay, "builder-future-sequence-number-delay", c.BuilderFutureSequenceNumberDelay,
+		"how long to hold back transactions following a gap in the sequence numbers of their proposal key")
+	c.HotStuff.Flags(flags)
+}
+
+func (c *Collection) Validate() error {
+	var errs *multierror.Error
+
+	if c.TxLimit == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("tx-limit must be positive"))
+	}
+	if c.Ingest.MaxTransactionByteSize > c.Ingest.MaxCollectionByteSize {
+		errs = multierror.Append(errs, fmt.Errorf("ingest-max-tx-byte-size (%d) exceeds ingest-max-col-byte-size (%d)",
+			c.Ingest.MaxTransactionByteSize, c.Ingest.MaxCollectionByteSize))
+	}
+	if c.MaxCollectionSize == 0 || c.MaxCollectionByteSize == 0 || c.MaxCollectionTotalGas == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("collection size limits of the builder must be positive"))
+	}
+	if c.BuilderPayerRateLimit < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("builder-rate-limit must be non-negative"))
+	}
+	if c.BuilderFutureSequenceNumberDelay < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("builder-future-sequence-number-delay must be non-negative"))
+	}
+	err := c.HotStuff.Validate()
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	return errs.ErrorOrNil()
+}
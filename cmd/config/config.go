@@ -0,0 +1,101 @@
+// Package config declares the tunables of the engines of each node role, like retry intervals,
+// memory pool limits and thresholds, in typed sections. The sections bind their fields to the
+// command line flags of the nodes and can be loaded from YAML, TOML or JSON files, whose keys
+// are the names of the flags.
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Section is a typed group of tunables of a node role.
+type Section interface {
+	// Flags binds the fields of the section to command line flags, using their current
+	// values as defaults.
+	Flags(flags *pflag.FlagSet)
+
+	// Validate checks that the values of the section are within bounds and consistent
+	// with each other.
+	Validate() error
+}
+
+// Load reads the configuration file at the given path into the section and validates it.
+// The format of the file is derived from its extension. Tunables which aren't set in the
+// file keep their values, so that loading a file into a section with default values only
+// overrides the tunables listed in the file.
+func Load(path string, section Section) error {
+	v, err := read(path)
+	if err != nil {
+		return err
+	}
+
+	err = v.Unmarshal(section)
+	if err != nil {
+		return fmt.Errorf("could not decode configuration file %s: %w", path, err)
+	}
+
+	err = section.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Apply sets the flags which weren't set on the command line to the values of the
+// configuration file at the given path, so that the command line takes precedence over
+// the file. It applies to all flags, including those not covered by a typed section.
+// Keys which don't match a flag are rejected, to catch typos in the file. Flags taking
+// key=value pairs are given as strings in the file, like on the command line, since the
+// keys of nested tables lose their case when read.
+func Apply(path string, flags *pflag.FlagSet) error {
+	v, err := read(path)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range v.AllKeys() {
+		flag := flags.Lookup(key)
+		if flag == nil {
+			return fmt.Errorf("unknown key in configuration file %s: %s", path, key)
+		}
+		if flag.Changed {
+			continue
+		}
+		err = flags.Set(key, flagValue(v.Get(key)))
+		if err != nil {
+			return fmt.Errorf("invalid value for %s in configuration file %s: %w", key, path, err)
+		}
+	}
+
+	return nil
+}
+
+func read(path string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	if err != nil {
+		return nil, fmt.Errorf("could not read configuration file %s: %w", path, err)
+	}
+	return v, nil
+}
+
+// flagValue formats a value of a configuration file the way the flags parse it, with lists
+// as comma-separated values.
+func flagValue(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
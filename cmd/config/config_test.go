@@ -0,0 +1,122 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err)
+	return path
+}
+
+// TestDefaults verifies that the default tunables of all node roles are valid.
+func TestDefaults(t *testing.T) {
+	for _, role := range flow.Roles() {
+		section, err := ForRole(role)
+		require.NoError(t, err)
+		assert.NoError(t, section.Validate(), role.String())
+	}
+}
+
+// TestLoad verifies that configuration files in different formats override the tunables
+// they list, while the other tunables keep their defaults.
+func TestLoad(t *testing.T) {
+	files := map[string]string{
+		"consensus.yaml": "seal-limit: 500\nhotstuff-timeout: 30s\nrequired-construction-seal-approvals: 1\n",
+		"consensus.toml": "seal-limit = 500\nhotstuff-timeout = \"30s\"\nrequired-construction-seal-approvals = 1\n",
+		"consensus.json": `{"seal-limit": 500, "hotstuff-timeout": "30s", "required-construction-seal-approvals": 1}`,
+	}
+
+	unittest.RunWithTempDir(t, func(dir string) {
+		for name, content := range files {
+			t.Run(name, func(t *testing.T) {
+				conf := DefaultConsensus()
+				err := Load(writeFile(t, dir, name, content), &conf)
+				require.NoError(t, err)
+
+				assert.Equal(t, uint(500), conf.SealLimit)
+				assert.Equal(t, 30*time.Second, conf.HotStuff.Timeout)
+				assert.Equal(t, uint(1), conf.RequiredApprovalsForSealConstruction)
+				assert.Equal(t, DefaultConsensus().GuaranteeLimit, conf.GuaranteeLimit)
+				assert.Equal(t, DefaultConsensus().HotStuff.MinTimeout, conf.HotStuff.MinTimeout)
+			})
+		}
+	})
+}
+
+// TestLoad_Invalid verifies that loading a configuration file fails if the resulting
+// tunables are inconsistent.
+func TestLoad_Invalid(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		conf := DefaultConsensus()
+		path := writeFile(t, dir, "consensus.yaml", "chunk-alpha: 1\nrequired-construction-seal-approvals: 2\n")
+		err := Load(path, &conf)
+		assert.Error(t, err)
+	})
+}
+
+// TestApply verifies that the configuration file sets the flags which aren't set on the
+// command line, including flags outside of the typed sections.
+func TestApply(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		conf := DefaultVerification()
+		var contexts []string
+		flags := pflag.NewFlagSet("verification", pflag.ContinueOnError)
+		conf.Flags(flags)
+		flags.StringSliceVar(&contexts, "execution-contexts", nil, "")
+
+		err := flags.Parse([]string{"--chunk-limit=20"})
+		require.NoError(t, err)
+
+		path := writeFile(t, dir, "verification.yaml", "chunk-limit: 10\nreceipt-limit: 50\nrequest-interval: 1s\nexecution-contexts: [\"1:a\", \"2:b\"]\n")
+		err = Apply(path, flags)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint(20), conf.ChunkLimit) // the command line takes precedence
+		assert.Equal(t, uint(50), conf.ReceiptLimit)
+		assert.Equal(t, time.Second, conf.RequestInterval)
+		assert.Equal(t, []string{"1:a", "2:b"}, contexts)
+
+		t.Run("unknown key", func(t *testing.T) {
+			path := writeFile(t, dir, "typo.yaml", "chunk-limt: 10\n")
+			err := Apply(path, flags)
+			assert.Error(t, err)
+		})
+	})
+}
+
+// TestDiff verifies that the differences between two sections are listed by the names
+// of the flags, including the tunables of embedded sections.
+func TestDiff(t *testing.T) {
+	left := DefaultCollection()
+	right := DefaultCollection()
+	right.TxLimit = 10
+	right.Ingest.ExpiryBuffer = 5
+	right.HotStuff.BlockRateDelay = time.Second
+
+	diffs, err := Diff(&left, &right)
+	require.NoError(t, err)
+	assert.Equal(t, []Difference{
+		{Key: "tx-limit", Left: left.TxLimit, Right: uint(10)},
+		{Key: "ingest-expiry-buffer", Left: left.Ingest.ExpiryBuffer, Right: uint(5)},
+		{Key: "block-rate-delay", Left: left.HotStuff.BlockRateDelay, Right: time.Second},
+	}, diffs)
+
+	t.Run("different sections", func(t *testing.T) {
+		consensus := DefaultConsensus()
+		_, err := Diff(&left, &consensus)
+		assert.Error(t, err)
+	})
+}
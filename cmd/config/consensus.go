@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+	"github.com/onflow/flow-go/engine/consensus/sealing"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/chunks"
+	"github.com/onflow/flow-go/module/validation"
+)
+
+// Consensus holds the tunables of the engines of consensus nodes.
+type Consensus struct {
+	GuaranteeLimit       uint `mapstructure:"guarantee-limit"`
+	ResultLimit          uint `mapstructure:"result-limit"`
+	ApprovalLimit        uint `mapstructure:"approval-limit"`
+	SealLimit            uint `mapstructure:"seal-limit"`
+	PendingReceiptsLimit uint `mapstructure:"pending-receipts-limit"`
+
+	MinInterval                      time.Duration `mapstructure:"min-interval"`
+	MaxInterval                      time.Duration `mapstructure:"max-interval"`
+	BlockTimestampTolerance          time.Duration `mapstructure:"block-timestamp-tolerance"`
+	MaxSealPerBlock                  uint          `mapstructure:"max-seal-per-block"`
+	MaxGuaranteePerBlock             uint          `mapstructure:"max-guarantee-per-block"`
+	MaxPayloadByteSize               uint64        `mapstructure:"max-payload-byte-size"`
+	BuildDeadline                    time.Duration `mapstructure:"build-deadline"`
+	FinalizedGuaranteeReferencesOnly bool          `mapstructure:"finalized-guarantee-references-only"`
+
+	HotStuff `mapstructure:",squash"`
+
+	ChunkAlpha                           uint    `mapstructure:"chunk-alpha"`
+	RequiredApprovalsForSealVerification uint    `mapstructure:"required-verification-seal-approvals"`
+	RequiredApprovalsForSealConstruction uint    `mapstructure:"required-construction-seal-approvals"`
+	ApprovalWorkers                      uint    `mapstructure:"approval-workers"`
+	ApprovalQueueCapacity                uint    `mapstructure:"approval-queue-capacity"`
+	ApprovalRateLimit                    float64 `mapstructure:"approval-rate-limit"`
+	ApprovalRateBurst                    int     `mapstructure:"approval-rate-burst"`
+}
+
+// DefaultConsensus returns the default tunables of consensus nodes.
+func DefaultConsensus() Consensus {
+	return Consensus{
+		GuaranteeLimit:          1000,
+		ResultLimit:             10000,
+		ApprovalLimit:           1000,
+		SealLimit:               10000,
+		PendingReceiptsLimit:    10000,
+		MinInterval:             time.Millisecond,
+		MaxInterval:             90 * time.Second,
+		BlockTimestampTolerance: 0,
+		MaxSealPerBlock:         100,
+		MaxGuaranteePerBlock:    100,
+		MaxPayloadByteSize:      flow.DefaultMaxPayloadByteSize,
+		BuildDeadline:           time.Second,
+		HotStuff: HotStuff{
+			Timeout:                        60 * time.Second,
+			MinTimeout:                     2500 * time.Millisecond,
+			TimeoutIncreaseFactor:          timeout.DefaultConfig.TimeoutIncrease,
+			TimeoutDecreaseFactor:          timeout.DefaultConfig.TimeoutDecrease,
+			VoteAggregationTimeoutFraction: 0.6,
+			BlockRateDelay:                 500 * time.Millisecond,
+		},
+		ChunkAlpha:                           chunks.DefaultChunkAssignmentAlpha,
+		RequiredApprovalsForSealVerification: validation.DefaultRequiredApprovalsForSealValidation,
+		RequiredApprovalsForSealConstruction: sealing.DefaultRequiredApprovalsForSealConstruction,
+		ApprovalWorkers:                      4,
+		ApprovalQueueCapacity:                10000,
+		ApprovalRateLimit:                    0,
+		ApprovalRateBurst:                    100,
+	}
+}
+
+func (c *Consensus) Flags(flags *pflag.FlagSet) {
+	flags.UintVar(&c.GuaranteeLimit, "guarantee-limit", c.GuaranteeLimit, "maximum number of guarantees in the memory pool")
+	flags.UintVar(&c.ResultLimit, "result-limit", c.ResultLimit, "maximum number of execution results in the memory pool")
+	flags.UintVar(&c.ApprovalLimit, "approval-limit", c.ApprovalLimit, "maximum number of result approvals in the memory pool")
+	flags.UintVar(&c.SealLimit, "seal-limit", c.SealLimit, "maximum number of block seals in the memory pool")
+	flags.UintVar(&c.PendingReceiptsLimit, "pending-receipts-limit", c.PendingReceiptsLimit, "maximum number of pending receipts in the mempool")
+	flags.DurationVar(&c.MinInterval, "min-interval", c.MinInterval, "the minimum amount of time between two blocks")
+	flags.DurationVar(&c.MaxInterval, "max-interval", c.MaxInterval, "the maximum amount of time between two blocks")
+	flags.DurationVar(&c.BlockTimestampTolerance, "block-timestamp-tolerance", c.BlockTimestampTolerance, "how far block timestamps may be ahead of the local clock (0 to use the tolerance of the chain)")
+	flags.UintVar(&c.MaxSealPerBlock, "max-seal-per-block", c.MaxSealPerBlock, "the maximum number of seals to be included in a block")
+	flags.UintVar(&c.MaxGuaranteePerBlock, "max-guarantee-per-block", c.MaxGuaranteePerBlock, "the maximum number of collection guarantees to be included in a block")
+	flags.Uint64Var(&c.MaxPayloadByteSize, "max-payload-byte-size", c.MaxPayloadByteSize, "the maximum byte size of a block payload")
+	flags.DurationVar(&c.BuildDeadline, "build-deadline", c.BuildDeadline, "the maximum time spent on selecting receipts and seals for a block payload, after which the best payload so far is proposed (0 disables the deadline)")
+	flags.BoolVar(&c.FinalizedGuaranteeReferencesOnly, "finalized-guarantee-references-only", c.FinalizedGuaranteeReferencesOnly, "whether to only include collection guarantees referencing finalized blocks, rather than any ancestor of the block being built on")
+	c.HotStuff.Flags(flags)
+	flags.UintVar(&c.ChunkAlpha, "chunk-alpha", c.ChunkAlpha, "number of verifiers that should be assigned to each chunk")
+	flags.UintVar(&c.RequiredApprovalsForSealVerification, "required-verification-seal-approvals", c.RequiredApprovalsForSealVerification, "minimum number of approvals that are required to verify a seal")
+	flags.UintVar(&c.RequiredApprovalsForSealConstruction, "required-construction-seal-approvals", c.RequiredApprovalsForSealConstruction, "minimum number of approvals that are required to construct a seal")
+	flags.UintVar(&c.ApprovalWorkers, "approval-workers", c.ApprovalWorkers, "number of workers verifying result approvals in parallel in the sealing engine")
+	flags.UintVar(&c.ApprovalQueueCapacity, "approval-queue-capacity", c.ApprovalQueueCapacity, "maximum number of result approvals of each kind (broadcast or requested) waiting for verification in the sealing engine, beyond which they are dropped")
+	flags.Float64Var(&c.ApprovalRateLimit, "approval-rate-limit", c.ApprovalRateLimit, "maximum number of result approvals per second accepted from each node by the sealing engine (0 disables the limit)")
+	flags.IntVar(&c.ApprovalRateBurst, "approval-rate-burst", c.ApprovalRateBurst, "maximum burst of result approvals accepted from each node by the sealing engine, if the rate is limited")
+}
+
+func (c *Consensus) Validate() error {
+	var errs *multierror.Error
+
+	if c.GuaranteeLimit == 0 || c.ResultLimit == 0 || c.ApprovalLimit == 0 || c.SealLimit == 0 || c.PendingReceiptsLimit == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("memory pool limits must be positive"))
+	}
+	if c.MinInterval > c.MaxInterval {
+		errs = multierror.Append(errs, fmt.Errorf("min-interval (%s) exceeds max-interval (%s)", c.MinInterval, c.MaxInterval))
+	}
+	if c.BlockTimestampTolerance < 0 || c.BuildDeadline < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("block-timestamp-tolerance and build-deadline must be non-negative"))
+	}
+	err := c.HotStuff.Validate()
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	// we need to ensure `requiredApprovalsForSealVerification <= requiredApprovalsForSealConstruction <= chunkAlpha`
+	if c.RequiredApprovalsForSealVerification > c.RequiredApprovalsForSealConstruction {
+		errs = multierror.Append(errs, fmt.Errorf("required-verification-seal-approvals (%d) exceeds required-construction-seal-approvals (%d)",
+			c.RequiredApprovalsForSealVerification, c.RequiredApprovalsForSealConstruction))
+	}
+	if c.RequiredApprovalsForSealConstruction > c.ChunkAlpha {
+		errs = multierror.Append(errs, fmt.Errorf("required-construction-seal-approvals (%d) exceeds chunk-alpha (%d)",
+			c.RequiredApprovalsForSealConstruction, c.ChunkAlpha))
+	}
+	if c.ApprovalWorkers == 0 || c.ApprovalQueueCapacity == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("approval-workers and approval-queue-capacity must be positive"))
+	}
+	if c.ApprovalRateLimit < 0 || (c.ApprovalRateLimit > 0 && c.ApprovalRateBurst <= 0) {
+		errs = multierror.Append(errs, fmt.Errorf("approval-rate-limit must be non-negative, with a positive approval-rate-burst if the rate is limited"))
+	}
+
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Difference is a tunable with different values in two sections.
+type Difference struct {
+	Key   string
+	Left  interface{}
+	Right interface{}
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %v != %v", d.Key, d.Left, d.Right)
+}
+
+// Diff lists the tunables with different values in two sections of the same type, keyed
+// by the names of their flags and in the order of their declaration. It allows comparing
+// the configurations of the same role in different environments.
+func Diff(left, right Section) ([]Difference, error) {
+	l := reflect.Indirect(reflect.ValueOf(left))
+	r := reflect.Indirect(reflect.ValueOf(right))
+	if l.Type() != r.Type() || l.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only compare sections of the same type (%T, %T)", left, right)
+	}
+
+	var diffs []Difference
+	diffFields(l, r, &diffs)
+	return diffs, nil
+}
+
+func diffFields(left, right reflect.Value, diffs *[]Difference) {
+	for i := 0; i < left.NumField(); i++ {
+		field := left.Type().Field(i)
+		key := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+
+		// embedded sections are squashed into the keys of the enclosing section
+		if key == "" && field.Type.Kind() == reflect.Struct {
+			diffFields(left.Field(i), right.Field(i), diffs)
+			continue
+		}
+		if key == "" {
+			continue
+		}
+
+		l := left.Field(i).Interface()
+		r := right.Field(i).Interface()
+		if !reflect.DeepEqual(l, r) {
+			*diffs = append(*diffs, Difference{Key: key, Left: l, Right: r})
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+
+	"github.com/onflow/flow-go/engine/execution/computation"
+)
+
+// Execution holds the tunables of the engines of execution nodes.
+type Execution struct {
+	MTrieCacheSize         uint32        `mapstructure:"mtrie-cache-size"`
+	LedgerMaxPathsPerQuery int           `mapstructure:"ledger-max-paths-per-query"`
+	LedgerMaxProofSize     int           `mapstructure:"ledger-max-proof-size"`
+	QueryReplicaCapacity   uint32        `mapstructure:"ledger-query-replica-capacity"`
+	QueryReplicaMaxLag     time.Duration `mapstructure:"ledger-query-replica-max-lag"`
+	PruningRetainedBlocks  uint64        `mapstructure:"ledger-pruning-retained-blocks"`
+
+	WALSyncInterval          time.Duration `mapstructure:"wal-sync-interval"`
+	CheckpointDistance       uint          `mapstructure:"checkpoint-distance"`
+	CheckpointsToKeep        uint          `mapstructure:"checkpoints-to-keep"`
+	CheckpointMaxIncremental uint          `mapstructure:"checkpoint-max-incremental-chain"`
+	CheckpointWriteRateLimit int           `mapstructure:"checkpoint-write-rate-limit"`
+	CheckpointMaxBlockRate   float64       `mapstructure:"checkpoint-max-block-rate"`
+	CheckpointMaxDelay       time.Duration `mapstructure:"checkpoint-max-delay"`
+
+	StateDeltasLimit            uint   `mapstructure:"state-deltas-limit"`
+	CadenceExecutionCache       uint   `mapstructure:"cadence-execution-cache"`
+	RuntimePoolSize             uint   `mapstructure:"runtime-pool-size"`
+	ChunkDataPackCacheSize      uint   `mapstructure:"chdp-cache"`
+	ChunkDataPackCacheDir       string `mapstructure:"chdp-cache-dir"`
+	ChunkDataPackCacheBytes     uint64 `mapstructure:"chdp-cache-bytes"`
+	TransactionResultsCacheSize uint   `mapstructure:"transaction-results-cache-size"`
+	ExecutionJournal            bool   `mapstructure:"execution-journal"`
+
+	RequestInterval    time.Duration `mapstructure:"request-interval"`
+	ScriptLogThreshold time.Duration `mapstructure:"script-log-threshold"`
+	ScriptCacheSize    uint          `mapstructure:"script-cache-size"`
+	ScriptCacheTTL     time.Duration `mapstructure:"script-cache-ttl"`
+	SyncThreshold      int           `mapstructure:"sync-threshold"`
+	StateSync          bool          `mapstructure:"state-sync"`
+	StateSyncMaxRange  uint64        `mapstructure:"state-sync-max-range"`
+	StateSyncInterval  time.Duration `mapstructure:"state-sync-interval"`
+}
+
+// DefaultExecution returns the default tunables of execution nodes.
+func DefaultExecution() Execution {
+	return Execution{
+		MTrieCacheSize:              500,
+		LedgerMaxPathsPerQuery:      0,
+		LedgerMaxProofSize:          0,
+		QueryReplicaCapacity:        100,
+		QueryReplicaMaxLag:          time.Second,
+		PruningRetainedBlocks:       0,
+		WALSyncInterval:             100 * time.Millisecond,
+		CheckpointDistance:          40,
+		CheckpointsToKeep:           5,
+		CheckpointMaxIncremental:    10,
+		CheckpointWriteRateLimit:    0,
+		CheckpointMaxBlockRate:      0,
+		CheckpointMaxDelay:          10 * time.Minute,
+		StateDeltasLimit:            100,
+		CadenceExecutionCache:       computation.DefaultProgramsCacheSize,
+		RuntimePoolSize:             0, // share a single runtime
+		ChunkDataPackCacheSize:      100,
+		ChunkDataPackCacheDir:       "",
+		ChunkDataPackCacheBytes:     1 << 30,
+		TransactionResultsCacheSize: 10000,
+		ExecutionJournal:            false,
+		RequestInterval:             60 * time.Second,
+		ScriptLogThreshold:          computation.DefaultScriptLogThreshold,
+		ScriptCacheSize:             computation.DefaultScriptCacheSize,
+		ScriptCacheTTL:              computation.DefaultScriptCacheTTL,
+		SyncThreshold:               100,
+		StateSyncMaxRange:           100,
+		StateSyncInterval:           10 * time.Second,
+	}
+}
+
+func (e *Execution) Flags(flags *pflag.FlagSet) {
+	flags.Uint32Var(&e.MTrieCacheSize, "mtrie-cache-size", e.MTrieCacheSize, "cache size for MTrie")
+	flags.IntVar(&e.LedgerMaxPathsPerQuery, "ledger-max-paths-per-query", e.LedgerMaxPathsPerQuery, "maximum number of registers read by a single ledger query (0 for unlimited)")
+	flags.IntVar(&e.LedgerMaxProofSize, "ledger-max-proof-size", e.LedgerMaxProofSize, "maximum size in bytes of the proof of a single ledger query (0 for unlimited)")
+	flags.Uint32Var(&e.QueryReplicaCapacity, "ledger-query-replica-capacity", e.QueryReplicaCapacity, "number of tries held by the query replica of the ledger")
+	flags.DurationVar(&e.QueryReplicaMaxLag, "ledger-query-replica-max-lag", e.QueryReplicaMaxLag, "maximum time queries wait for the query replica to catch up, before falling back to the ledger")
+	flags.Uint64Var(&e.PruningRetainedBlocks, "ledger-pruning-retained-blocks", e.PruningRetainedBlocks, "number of blocks below the latest sealed block whose states are retained in the ledger, older states are pruned (0 to disable pruning)")
+	flags.DurationVar(&e.WALSyncInterval, "wal-sync-interval", e.WALSyncInterval, "interval between syncs of the WAL for the periodic sync policy")
+	flags.UintVar(&e.CheckpointDistance, "checkpoint-distance", e.CheckpointDistance, "number of WAL segments between checkpoints")
+	flags.UintVar(&e.CheckpointsToKeep, "checkpoints-to-keep", e.CheckpointsToKeep, "number of recent checkpoints to keep (0 to keep all)")
+	flags.UintVar(&e.CheckpointMaxIncremental, "checkpoint-max-incremental-chain", e.CheckpointMaxIncremental, "maximum number of consecutive incremental checkpoints before a full checkpoint is created")
+	flags.IntVar(&e.CheckpointWriteRateLimit, "checkpoint-write-rate-limit", e.CheckpointWriteRateLimit, "maximum rate in bytes per second at which checkpoints are written (0 for unlimited)")
+	flags.Float64Var(&e.CheckpointMaxBlockRate, "checkpoint-max-block-rate", e.CheckpointMaxBlockRate, "postpone checkpoints while more blocks per second are finalized (0 to never postpone)")
+	flags.DurationVar(&e.CheckpointMaxDelay, "checkpoint-max-delay", e.CheckpointMaxDelay, "maximum time a checkpoint is postponed due to high block rate")
+	flags.UintVar(&e.StateDeltasLimit, "state-deltas-limit", e.StateDeltasLimit, "maximum number of state deltas in the memory pool")
+	flags.UintVar(&e.CadenceExecutionCache, "cadence-execution-cache", e.CadenceExecutionCache, "cache size for Cadence execution")
+	flags.UintVar(&e.RuntimePoolSize, "runtime-pool-size", e.RuntimePoolSize, "number of Cadence runtimes pooled for reuse by transactions and scripts (0 to share a single runtime)")
+	flags.UintVar(&e.ChunkDataPackCacheSize, "chdp-cache", e.ChunkDataPackCacheSize, "cache size for Chunk Data Packs")
+	flags.StringVar(&e.ChunkDataPackCacheDir, "chdp-cache-dir", e.ChunkDataPackCacheDir, "directory of the on-disk cache holding the chunk data packs of unsealed blocks instead of the database of the node, from which chunk data pack requests are served (empty to disable the cache)")
+	flags.Uint64Var(&e.ChunkDataPackCacheBytes, "chdp-cache-bytes", e.ChunkDataPackCacheBytes, "maximum size in bytes of the on-disk cache of chunk data packs, beyond which the chunk data packs of the lowest heights are moved to the database of the node")
+	flags.UintVar(&e.TransactionResultsCacheSize, "transaction-results-cache-size", e.TransactionResultsCacheSize, "number of transaction results to be cached")
+	flags.BoolVar(&e.ExecutionJournal, "execution-journal", e.ExecutionJournal, "checkpoint the executed collections of blocks to resume their execution after a restart")
+	flags.DurationVar(&e.RequestInterval, "request-interval", e.RequestInterval, "the interval between requests for the requester engine")
+	flags.DurationVar(&e.ScriptLogThreshold, "script-log-threshold", e.ScriptLogThreshold, "threshold for logging script execution")
+	flags.UintVar(&e.ScriptCacheSize, "script-cache-size", e.ScriptCacheSize, "number of script results cached per script, arguments and block (0 to disable caching)")
+	flags.DurationVar(&e.ScriptCacheTTL, "script-cache-ttl", e.ScriptCacheTTL, "time after which cached script results expire")
+	flags.IntVar(&e.SyncThreshold, "sync-threshold", e.SyncThreshold, "the maximum number of sealed and unexecuted blocks before triggering state syncing")
+	flags.BoolVar(&e.StateSync, "state-sync", e.StateSync, "sync the execution state of sealed blocks from the state deltas of other execution nodes, and serve the state deltas of executed blocks")
+	flags.Uint64Var(&e.StateSyncMaxRange, "state-sync-max-range", e.StateSyncMaxRange, "maximum number of blocks whose state deltas are requested, or served, at once")
+	flags.DurationVar(&e.StateSyncInterval, "state-sync-interval", e.StateSyncInterval, "interval between checks whether state deltas should be requested")
+}
+
+func (e *Execution) Validate() error {
+	var errs *multierror.Error
+
+	if e.MTrieCacheSize == 0 || e.QueryReplicaCapacity == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("mtrie-cache-size and ledger-query-replica-capacity must be positive"))
+	}
+	if e.LedgerMaxPathsPerQuery < 0 || e.LedgerMaxProofSize < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("ledger query limits must be non-negative"))
+	}
+	if e.WALSyncInterval <= 0 || e.CheckpointDistance == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("wal-sync-interval and checkpoint-distance must be positive"))
+	}
+	if e.CheckpointWriteRateLimit < 0 || e.CheckpointMaxBlockRate < 0 || e.CheckpointMaxDelay < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("checkpoint rate limits and delays must be non-negative"))
+	}
+	if e.StateDeltasLimit == 0 || e.ChunkDataPackCacheSize == 0 || e.TransactionResultsCacheSize == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("memory pool and cache sizes must be positive"))
+	}
+	if e.ChunkDataPackCacheDir != "" && e.ChunkDataPackCacheBytes == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("chdp-cache-bytes must be positive if the chunk data pack cache is enabled"))
+	}
+	if e.RequestInterval <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("request-interval must be positive"))
+	}
+	if e.ScriptCacheSize > 0 && e.ScriptCacheTTL <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("script-cache-ttl must be positive"))
+	}
+	if e.SyncThreshold < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("sync-threshold must be non-negative"))
+	}
+	if e.StateSync && (e.StateSyncMaxRange == 0 || e.StateSyncInterval <= 0) {
+		errs = multierror.Append(errs, fmt.Errorf("state-sync-max-range and state-sync-interval must be positive if state syncing is enabled"))
+	}
+
+	return errs.ErrorOrNil()
+}
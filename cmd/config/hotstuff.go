@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+)
+
+// HotStuff holds the tunables of the HotStuff pacemaker, shared by the main consensus and the
+// collection clusters.
+type HotStuff struct {
+	Timeout                        time.Duration `mapstructure:"hotstuff-timeout"`
+	MinTimeout                     time.Duration `mapstructure:"hotstuff-min-timeout"`
+	TimeoutIncreaseFactor          float64       `mapstructure:"hotstuff-timeout-increase-factor"`
+	TimeoutDecreaseFactor          float64       `mapstructure:"hotstuff-timeout-decrease-factor"`
+	VoteAggregationTimeoutFraction float64       `mapstructure:"hotstuff-timeout-vote-aggregation-fraction"`
+	BlockRateDelay                 time.Duration `mapstructure:"block-rate-delay"`
+}
+
+func (h *HotStuff) Flags(flags *pflag.FlagSet) {
+	flags.DurationVar(&h.Timeout, "hotstuff-timeout", h.Timeout, "the initial timeout for the hotstuff pacemaker")
+	flags.DurationVar(&h.MinTimeout, "hotstuff-min-timeout", h.MinTimeout, "the lower timeout bound for the hotstuff pacemaker")
+	flags.Float64Var(&h.TimeoutIncreaseFactor, "hotstuff-timeout-increase-factor", h.TimeoutIncreaseFactor, "multiplicative increase of timeout value in case of time out event")
+	flags.Float64Var(&h.TimeoutDecreaseFactor, "hotstuff-timeout-decrease-factor", h.TimeoutDecreaseFactor, "multiplicative decrease of timeout value in case of progress")
+	flags.Float64Var(&h.VoteAggregationTimeoutFraction, "hotstuff-timeout-vote-aggregation-fraction", h.VoteAggregationTimeoutFraction, "additional fraction of replica timeout that the primary will wait for votes")
+	flags.DurationVar(&h.BlockRateDelay, "block-rate-delay", h.BlockRateDelay, "the delay to broadcast block proposal in order to control block production rate")
+}
+
+// Validate checks the tunables against the bounds enforced by the pacemaker.
+func (h *HotStuff) Validate() error {
+	_, err := timeout.NewConfig(
+		h.Timeout,
+		h.MinTimeout,
+		h.VoteAggregationTimeoutFraction,
+		h.TimeoutIncreaseFactor,
+		h.TimeoutDecreaseFactor,
+		h.BlockRateDelay,
+	)
+	if err != nil {
+		return fmt.Errorf("invalid hotstuff configuration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ForRole returns the section with the default tunables of the node role.
+func ForRole(role flow.Role) (Section, error) {
+	switch role {
+	case flow.RoleCollection:
+		section := DefaultCollection()
+		return &section, nil
+	case flow.RoleConsensus:
+		section := DefaultConsensus()
+		return &section, nil
+	case flow.RoleExecution:
+		section := DefaultExecution()
+		return &section, nil
+	case flow.RoleVerification:
+		section := DefaultVerification()
+		return &section, nil
+	case flow.RoleAccess:
+		section := DefaultAccess()
+		return &section, nil
+	default:
+		return nil, fmt.Errorf("no configuration section for role %s", role)
+	}
+}
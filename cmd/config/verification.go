@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+
+	"github.com/onflow/flow-go/module/chunks"
+)
+
+// Verification holds the tunables of the engines of verification nodes.
+type Verification struct {
+	ReceiptLimit uint `mapstructure:"receipt-limit"`
+	ChunkLimit   uint `mapstructure:"chunk-limit"`
+	ChunkAlpha   uint `mapstructure:"chunk-alpha"`
+
+	// RequestInterval is the interval at which the match engine retries requesting
+	// chunk data packs, following issue 3443.
+	RequestInterval time.Duration `mapstructure:"request-interval"`
+	// ProcessInterval is the interval at which the finder engine processes the
+	// execution receipts ready to process, following issue 3443.
+	ProcessInterval time.Duration `mapstructure:"process-interval"`
+	// FailureThreshold is the number of requests for a missing chunk data pack, after
+	// which the match engine gives up. The default accounts for a single 24-hour
+	// failure of an execution node.
+	FailureThreshold int `mapstructure:"failure-threshold"`
+}
+
+// DefaultVerification returns the default tunables of verification nodes.
+func DefaultVerification() Verification {
+	return Verification{
+		ReceiptLimit:     1000,
+		ChunkLimit:       10000,
+		ChunkAlpha:       chunks.DefaultChunkAssignmentAlpha,
+		RequestInterval:  5000 * time.Millisecond,
+		ProcessInterval:  1000 * time.Millisecond,
+		FailureThreshold: 17500,
+	}
+}
+
+func (v *Verification) Flags(flags *pflag.FlagSet) {
+	flags.UintVar(&v.ReceiptLimit, "receipt-limit", v.ReceiptLimit, "maximum number of execution receipts in the memory pool")
+	flags.UintVar(&v.ChunkLimit, "chunk-limit", v.ChunkLimit, "maximum number of chunk states in the memory pool")
+	flags.UintVar(&v.ChunkAlpha, "chunk-alpha", v.ChunkAlpha, "number of verifiers that should be assigned to each chunk")
+	flags.DurationVar(&v.RequestInterval, "request-interval", v.RequestInterval, "the interval at which missing chunk data packs are requested again")
+	flags.DurationVar(&v.ProcessInterval, "process-interval", v.ProcessInterval, "the interval at which execution receipts ready to process are processed")
+	flags.IntVar(&v.FailureThreshold, "failure-threshold", v.FailureThreshold, "number of requests for a missing chunk data pack before giving up")
+}
+
+func (v *Verification) Validate() error {
+	var errs *multierror.Error
+
+	if v.ReceiptLimit == 0 || v.ChunkLimit == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("memory pool limits must be positive"))
+	}
+	if v.ChunkAlpha == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("chunk-alpha must be positive"))
+	}
+	if v.RequestInterval <= 0 || v.ProcessInterval <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("request-interval and process-interval must be positive"))
+	}
+	if v.FailureThreshold <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("failure-threshold must be positive"))
+	}
+
+	return errs.ErrorOrNil()
+}
@@ -11,11 +11,11 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/config"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/blockproducer"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
-	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 	"github.com/onflow/flow-go/consensus/hotstuff/persister"
 	"github.com/onflow/flow-go/consensus/hotstuff/verification"
 	recovery "github.com/onflow/flow-go/consensus/recovery/protocol"
@@ -32,6 +32,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/admin"
 	"github.com/onflow/flow-go/module/buffer"
 	builder "github.com/onflow/flow-go/module/builder/consensus"
 	chmodule "github.com/onflow/flow-go/module/chunks"
@@ -43,6 +44,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/signature"
 	"github.com/onflow/flow-go/module/synchronization"
+	"github.com/onflow/flow-go/module/timesync"
 	"github.com/onflow/flow-go/module/validation"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
@@ -53,25 +55,12 @@ import (
 func main() {
 
 	var (
-		guaranteeLimit                         uint
-		resultLimit                            uint
-		approvalLimit                          uint
-		sealLimit                              uint
-		pendngReceiptsLimit                    uint
-		minInterval                            time.Duration
-		maxInterval                            time.Duration
-		maxSealPerBlock                        uint
-		maxGuaranteePerBlock                   uint
-		hotstuffTimeout                        time.Duration
-		hotstuffMinTimeout                     time.Duration
-		hotstuffTimeoutIncreaseFactor          float64
-		hotstuffTimeoutDecreaseFactor          float64
-		hotstuffTimeoutVoteAggregationFraction float64
-		blockRateDelay                         time.Duration
-		chunkAlpha                             uint
-		requiredApprovalsForSealVerification   uint
-		requiredApprovalsForSealConstruction   uint
-		emergencySealing                       bool
+		conf                  = config.DefaultConsensus()
+		fairGuaranteeOrdering bool
+		emergencySealing      bool
+		approvalDecaySchedule string
+		persistSeals          bool
+		verifySpocks          bool
 
 		err               error
 		mutableState      protocol.MutableState
@@ -89,31 +78,20 @@ func main() {
 		conMetrics        module.ConsensusMetrics
 		mainMetrics       module.HotstuffMetrics
 		receiptValidator  module.ReceiptValidator
+		validatedResults  *validation.ValidatedResults
 		approvalValidator module.ApprovalValidator
 		chunkAssigner     *chmodule.ChunkAssigner
+		spockVerifier     *chmodule.SpockVerifier
 	)
 
 	cmd.FlowNode(flow.RoleConsensus.String()).
+		Config(&conf).
 		ExtraFlags(func(flags *pflag.FlagSet) {
-			flags.UintVar(&guaranteeLimit, "guarantee-limit", 1000, "maximum number of guarantees in the memory pool")
-			flags.UintVar(&resultLimit, "result-limit", 10000, "maximum number of execution results in the memory pool")
-			flags.UintVar(&approvalLimit, "approval-limit", 1000, "maximum number of result approvals in the memory pool")
-			flags.UintVar(&sealLimit, "seal-limit", 10000, "maximum number of block seals in the memory pool")
-			flags.UintVar(&pendngReceiptsLimit, "pending-receipts-limit", 10000, "maximum number of pending receipts in the mempool")
-			flags.DurationVar(&minInterval, "min-interval", time.Millisecond, "the minimum amount of time between two blocks")
-			flags.DurationVar(&maxInterval, "max-interval", 90*time.Second, "the maximum amount of time between two blocks")
-			flags.UintVar(&maxSealPerBlock, "max-seal-per-block", 100, "the maximum number of seals to be included in a block")
-			flags.UintVar(&maxGuaranteePerBlock, "max-guarantee-per-block", 100, "the maximum number of collection guarantees to be included in a block")
-			flags.DurationVar(&hotstuffTimeout, "hotstuff-timeout", 60*time.Second, "the initial timeout for the hotstuff pacemaker")
-			flags.DurationVar(&hotstuffMinTimeout, "hotstuff-min-timeout", 2500*time.Millisecond, "the lower timeout bound for the hotstuff pacemaker")
-			flags.Float64Var(&hotstuffTimeoutIncreaseFactor, "hotstuff-timeout-increase-factor", timeout.DefaultConfig.TimeoutIncrease, "multiplicative increase of timeout value in case of time out event")
-			flags.Float64Var(&hotstuffTimeoutDecreaseFactor, "hotstuff-timeout-decrease-factor", timeout.DefaultConfig.TimeoutDecrease, "multiplicative decrease of timeout value in case of progress")
-			flags.Float64Var(&hotstuffTimeoutVoteAggregationFraction, "hotstuff-timeout-vote-aggregation-fraction", 0.6, "additional fraction of replica timeout that the primary will wait for votes")
-			flags.DurationVar(&blockRateDelay, "block-rate-delay", 500*time.Millisecond, "the delay to broadcast block proposal in order to control block production rate")
-			flags.UintVar(&chunkAlpha, "chunk-alpha", chmodule.DefaultChunkAssignmentAlpha, "number of verifiers that should be assigned to each chunk")
-			flags.UintVar(&requiredApprovalsForSealVerification, "required-verification-seal-approvals", validation.DefaultRequiredApprovalsForSealValidation, "minimum number of approvals that are required to verify a seal")
-			flags.UintVar(&requiredApprovalsForSealConstruction, "required-construction-seal-approvals", sealing.DefaultRequiredApprovalsForSealConstruction, "minimum number of approvals that are required to construct a seal")
+			flags.BoolVar(&fairGuaranteeOrdering, "fair-guarantee-ordering", true, "whether to prioritize collection guarantees with older reference blocks and take turns across clusters, rather than using the mempool order")
 			flags.BoolVar(&emergencySealing, "emergency-sealing-active", sealing.DefaultEmergencySealingActive, "(de)activation of emergency sealing")
+			flags.StringVar(&approvalDecaySchedule, "approval-decay-schedule", "", "comma-separated <unsealed blocks>:<required approvals> steps by which the approvals required for sealing decay for results that remain unsealed, e.g. 200:1,400:0 (overrides emergency-sealing-active)")
+			flags.BoolVar(&persistSeals, "persist-seals", false, "whether to persist the candidate seals of the seals mempool in the database, so that they are replayed after a restart")
+			flags.BoolVar(&verifySpocks, "verify-spocks", false, "whether to verify the SPoCKs of approvals against the receipts for the approved result, discarding approvals with mismatching SPoCKs")
 		}).
 		Module("consensus node metrics", func(node *cmd.FlowNodeBuilder) error {
 			conMetrics = metrics.NewConsensusCollector(node.Tracer, node.MetricsRegisterer)
@@ -127,17 +105,21 @@ func main() {
 				return fmt.Errorf("only implementations of type badger.State are currenlty supported but read-only state has type %T", node.State)
 			}
 
-			// We need to ensure `requiredApprovalsForSealVerification <= requiredApprovalsForSealConstruction <= chunkAlpha`
-			if requiredApprovalsForSealVerification > requiredApprovalsForSealConstruction {
-				return fmt.Errorf("invalid consensus parameters: requiredApprovalsForSealVerification > requiredApprovalsForSealConstruction")
-			}
-			if requiredApprovalsForSealConstruction > chunkAlpha {
-				return fmt.Errorf("invalid consensus parameters: requiredApprovalsForSealConstruction > chunkAlpha")
+			chunkAssigner, err = chmodule.NewChunkAssigner(conf.ChunkAlpha, node.State)
+			if err != nil {
+				return fmt.Errorf("could not instantiate assignment algorithm for chunk verification: %w", err)
 			}
 
-			chunkAssigner, err = chmodule.NewChunkAssigner(chunkAlpha, node.State)
+			validatedResults, err = validation.NewValidatedResults(node.Metrics.Cache, validation.DefaultValidatedResultsCacheSize)
 			if err != nil {
-				return fmt.Errorf("could not instantiate assignment algorithm for chunk verification: %w", err)
+				return fmt.Errorf("could not initialize cache of validated results: %w", err)
+			}
+
+			if verifySpocks {
+				spockVerifier, err = chmodule.NewSpockVerifier(node.State, node.Metrics.Cache, chmodule.DefaultSpockVerificationCacheSize)
+				if err != nil {
+					return fmt.Errorf("could not initialize SPoCK verifier: %w", err)
+				}
 			}
 
 			receiptValidator = validation.NewReceiptValidator(
@@ -146,7 +128,8 @@ func main() {
 				node.Storage.Index,
 				node.Storage.Results,
 				node.Storage.Seals,
-				signature.NewAggregationVerifier(encoding.ExecutionReceiptTag))
+				signature.NewAggregationVerifier(encoding.ExecutionReceiptTag),
+				validatedResults)
 
 			resultApprovalSigVerifier := signature.NewAggregationVerifier(encoding.ResultApprovalTag)
 
@@ -162,10 +145,10 @@ func main() {
 				node.Storage.Seals,
 				chunkAssigner,
 				resultApprovalSigVerifier,
-				requiredApprovalsForSealVerification,
+				conf.RequiredApprovalsForSealVerification,
 				conMetrics)
 
-			mutableState, err = badgerState.NewFullConsensusState(
+			fullState, err := badgerState.NewFullConsensusState(
 				state,
 				node.Storage.Index,
 				node.Storage.Payloads,
@@ -173,18 +156,38 @@ func main() {
 				node.ProtocolEvents,
 				receiptValidator,
 				sealValidator)
-			return err
+			if err != nil {
+				return err
+			}
+			if conf.BlockTimestampTolerance > 0 {
+				fullState = fullState.WithBlockTimestampTolerance(conf.BlockTimestampTolerance)
+			}
+			mutableState = fullState
+			return nil
 		}).
 		Module("random beacon key", func(node *cmd.FlowNodeBuilder) error {
 			privateDKGData, err = loadDKGPrivateData(node.BaseConfig.BootstrapDir, node.NodeID)
 			return err
 		}).
 		Module("collection guarantees mempool", func(node *cmd.FlowNodeBuilder) error {
-			guarantees, err = stdmap.NewGuarantees(guaranteeLimit)
+			// evict the guarantees with the oldest reference blocks first, as they are the
+			// first to expire, instead of guarantees the builder might include next
+			heights := stdmap.NewLowestHeightFirst(func(entity flow.Entity) (uint64, bool) {
+				guarantee := entity.(*flow.CollectionGuarantee)
+				header, err := node.Storage.Headers.ByBlockID(guarantee.ReferenceBlockID)
+				if err != nil {
+					return 0, false
+				}
+				return header.Height, true
+			})
+			guarantees, err = stdmap.NewGuarantees(conf.GuaranteeLimit,
+				stdmap.WithEvictionPolicy(heights),
+				stdmap.WithEjectionMetrics(metrics.ResourceGuarantee, node.Metrics.Mempool),
+			)
 			return err
 		}).
 		Module("execution results mempool", func(node *cmd.FlowNodeBuilder) error {
-			results, err = stdmap.NewIncorporatedResults(resultLimit)
+			results, err = stdmap.NewIncorporatedResults(conf.ResultLimit)
 			return err
 		}).
 		Module("execution receipts mempool", func(node *cmd.FlowNodeBuilder) error {
@@ -194,25 +197,53 @@ func main() {
 			if err != nil {
 				return fmt.Errorf("could not register backend metric: %w", err)
 			}
+			// exports the execution forks, with the latest sealed result as their root
+			node.Admin.Register("/execution-forks", admin.JSONHandler(func() (interface{}, error) {
+				sealedResult, _, err := node.State.Sealed().SealedResult()
+				if err != nil {
+					return nil, fmt.Errorf("could not get sealed result: %w", err)
+				}
+				return receipts.Export(sealedResult.ID()), nil
+			}))
 			return nil
 		}).
 		Module("result approvals mempool", func(node *cmd.FlowNodeBuilder) error {
-			approvals, err = stdmap.NewApprovals(approvalLimit)
+			approvals, err = stdmap.NewApprovals(conf.ApprovalLimit)
 			return err
 		}).
 		Module("block seals mempool", func(node *cmd.FlowNodeBuilder) error {
 			// use a custom ejector so we don't eject seals that would break
 			// the chain of seals
 			ejector := ejectors.NewLatestIncorporatedResultSeal(node.Storage.Headers)
-			resultSeals := stdmap.NewIncorporatedResultSeals(stdmap.WithLimit(sealLimit), stdmap.WithEject(ejector.Eject))
+			resultSeals := stdmap.NewIncorporatedResultSeals(
+				stdmap.WithLimit(conf.SealLimit),
+				stdmap.WithEject(ejector.Eject),
+				stdmap.WithEjectionMetrics(metrics.ResourceSeal, node.Metrics.Mempool),
+			)
 			seals, err = consensusMempools.NewExecStateForkSuppressor(consensusMempools.LogForkAndCrash(node.Logger), resultSeals, node.DB, node.Logger)
 			if err != nil {
 				return fmt.Errorf("failed to wrap seals mempool into ExecStateForkSuppressor: %w", err)
 			}
+			if !persistSeals {
+				return nil
+			}
+
+			// replay the seals persisted before the restart, so sealing doesn't stall until the
+			// approvals are collected again
+			persistentSeals := consensusMempools.NewPersistentSeals(seals, node.DB, node.Logger)
+			sealed, err := node.State.Sealed().Head()
+			if err != nil {
+				return fmt.Errorf("could not get last sealed block: %w", err)
+			}
+			_, err = persistentSeals.Replay(node.Storage.Headers, sealed.Height)
+			if err != nil {
+				return fmt.Errorf("could not replay persisted seals: %w", err)
+			}
+			seals = persistentSeals
 			return nil
 		}).
 		Module("pending receipts mempool", func(node *cmd.FlowNodeBuilder) error {
-			pendingReceipts = stdmap.NewPendingReceipts(pendngReceiptsLimit)
+			pendingReceipts = stdmap.NewPendingReceipts(conf.PendingReceiptsLimit)
 			return nil
 		}).
 		Module("hotstuff main metrics", func(node *cmd.FlowNodeBuilder) error {
@@ -241,6 +272,19 @@ func main() {
 				return nil, err
 			}
 
+			// the approval decay schedule replaces the emergency sealing flag, which
+			// corresponds to dropping the required approvals to zero at once
+			var approvalDecay sealing.ApprovalDecaySchedule
+			if emergencySealing {
+				approvalDecay = sealing.EmergencySealingSchedule()
+			}
+			if approvalDecaySchedule != "" {
+				approvalDecay, err = sealing.ParseApprovalDecaySchedule(approvalDecaySchedule)
+				if err != nil {
+					return nil, fmt.Errorf("invalid approval decay schedule: %w", err)
+				}
+			}
+
 			match, err := sealing.NewEngine(
 				node.Logger,
 				node.Metrics.Engine,
@@ -262,11 +306,17 @@ func main() {
 				chunkAssigner,
 				receiptValidator,
 				approvalValidator,
-				requiredApprovalsForSealConstruction,
-				emergencySealing,
+				conf.RequiredApprovalsForSealConstruction,
+				approvalDecay,
+				sealing.WithApprovalWorkers(conf.ApprovalWorkers),
+				sealing.WithApprovalQueueCapacity(conf.ApprovalQueueCapacity),
+				sealing.WithApprovalRateLimit(conf.ApprovalRateLimit, conf.ApprovalRateBurst),
+				sealing.WithValidatedResults(validatedResults),
+				sealing.WithSpockVerifier(spockVerifier),
 			)
 
 			receiptRequester.WithHandle(match.HandleReceipt)
+			node.ProtocolEvents.AddConsumer(match)
 
 			return match, err
 		}).
@@ -326,11 +376,16 @@ func main() {
 			if err != nil {
 				return nil, fmt.Errorf("could not initialize compliance engine: %w", err)
 			}
+			comp = comp.WithTimeSync(timesync.NewObserver(metrics.NewClockSkewCollector(), timesync.DefaultMaxAge))
 
 			// initialize the block builder
+			guaranteeOrdering := builder.GuaranteeOrderMempool
+			if fairGuaranteeOrdering {
+				guaranteeOrdering = builder.GuaranteeOrderFair
+			}
 			var build module.Builder
 			build = builder.NewBuilder(
-				node.Metrics.Mempool,
+				conMetrics,
 				node.DB,
 				mutableState,
 				node.Storage.Headers,
@@ -342,10 +397,14 @@ func main() {
 				seals,
 				receipts,
 				node.Tracer,
-				builder.WithMinInterval(minInterval),
-				builder.WithMaxInterval(maxInterval),
-				builder.WithMaxSealCount(maxSealPerBlock),
-				builder.WithMaxGuaranteeCount(maxGuaranteePerBlock),
+				builder.WithMinInterval(conf.MinInterval),
+				builder.WithMaxInterval(conf.MaxInterval),
+				builder.WithMaxSealCount(conf.MaxSealPerBlock),
+				builder.WithMaxGuaranteeCount(conf.MaxGuaranteePerBlock),
+				builder.WithGuaranteeOrdering(guaranteeOrdering),
+				builder.WithMaxPayloadByteSize(conf.MaxPayloadByteSize),
+				builder.WithBuildDeadline(conf.BuildDeadline),
+				builder.WithFinalizedGuaranteeReferencesOnly(conf.FinalizedGuaranteeReferencesOnly),
 			)
 			build = blockproducer.NewMetricsWrapper(build, mainMetrics) // wrapper for measuring time spent building block payload component
 
@@ -425,12 +484,12 @@ func main() {
 				node.RootQC,
 				finalized,
 				pending,
-				consensus.WithInitialTimeout(hotstuffTimeout),
-				consensus.WithMinTimeout(hotstuffMinTimeout),
-				consensus.WithVoteAggregationTimeoutFraction(hotstuffTimeoutVoteAggregationFraction),
-				consensus.WithTimeoutIncreaseFactor(hotstuffTimeoutIncreaseFactor),
-				consensus.WithTimeoutDecreaseFactor(hotstuffTimeoutDecreaseFactor),
-				consensus.WithBlockRateDelay(blockRateDelay),
+				consensus.WithInitialTimeout(conf.HotStuff.Timeout),
+				consensus.WithMinTimeout(conf.HotStuff.MinTimeout),
+				consensus.WithVoteAggregationTimeoutFraction(conf.HotStuff.VoteAggregationTimeoutFraction),
+				consensus.WithTimeoutIncreaseFactor(conf.HotStuff.TimeoutIncreaseFactor),
+				consensus.WithTimeoutDecreaseFactor(conf.HotStuff.TimeoutDecreaseFactor),
+				consensus.WithBlockRateDelay(conf.HotStuff.BlockRateDelay),
 			)
 			if err != nil {
 				return nil, fmt.Errorf("could not initialize hotstuff engine: %w", err)
@@ -8,9 +8,11 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/dgraph-io/badger/v2"
 	"github.com/spf13/pflag"
 
 	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/config"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
 	"github.com/onflow/flow-go/consensus/hotstuff/verification"
@@ -23,16 +25,20 @@ import (
 	"github.com/onflow/flow-go/engine/execution/checker"
 	"github.com/onflow/flow-go/engine/execution/computation"
 	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
 	exeprovider "github.com/onflow/flow-go/engine/execution/provider"
 	"github.com/onflow/flow-go/engine/execution/rpc"
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
+	"github.com/onflow/flow-go/engine/execution/statesync"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/fvm/extralog"
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
 	ledger "github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/pruner"
 	wal "github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/ledger/remote"
 	bootstrapFilenames "github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/encodable"
 	"github.com/onflow/flow-go/model/encoding"
@@ -46,74 +52,81 @@ import (
 	chainsync "github.com/onflow/flow-go/module/synchronization"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
+	flowstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/badger"
+	sutil "github.com/onflow/flow-go/storage/util"
 )
 
 func main() {
 
 	var (
-		followerState               protocol.MutableState
-		ledgerStorage               *ledger.Ledger
-		events                      *storage.Events
-		serviceEvents               *storage.ServiceEvents
-		txResults                   *storage.TransactionResults
-		results                     *storage.ExecutionResults
-		receipts                    *storage.ExecutionReceipts
-		myReceipts                  *storage.MyExecutionReceipts
-		providerEngine              *exeprovider.Engine
-		checkerEng                  *checker.Engine
-		syncCore                    *chainsync.Core
-		pendingBlocks               *buffer.PendingBlocks // used in follower engine
-		deltas                      *ingestion.Deltas
-		syncEngine                  *synchronization.Engine
-		followerEng                 *followereng.Engine // to sync blocks from consensus nodes
-		computationManager          *computation.Manager
-		collectionRequester         *requester.Engine
-		ingestionEng                *ingestion.Engine
-		rpcConf                     rpc.Config
-		err                         error
-		executionState              state.ExecutionState
-		triedir                     string
-		collector                   module.ExecutionMetrics
-		mTrieCacheSize              uint32
-		transactionResultsCacheSize uint
-		checkpointDistance          uint
-		checkpointsToKeep           uint
-		stateDeltasLimit            uint
-		cadenceExecutionCache       uint
-		chdpCacheSize               uint
-		requestInterval             time.Duration
-		preferredExeNodeIDStr       string
-		syncByBlocks                bool
-		syncFast                    bool
-		syncThreshold               int
-		extensiveLog                bool
-		checkStakedAtBlock          func(blockID flow.Identifier) (bool, error)
-		diskWAL                     *wal.DiskWAL
-		scriptLogThreshold          time.Duration
+		conf                  = config.DefaultExecution()
+		followerState         protocol.MutableState
+		ledgerStorage         *ledger.Ledger
+		ledgerReplica         *ledger.Replica
+		events                *storage.Events
+		serviceEvents         *storage.ServiceEvents
+		txResults             *storage.TransactionResults
+		results               *storage.ExecutionResults
+		receipts              *storage.ExecutionReceipts
+		myReceipts            *storage.MyExecutionReceipts
+		providerEngine        *exeprovider.Engine
+		checkerEng            *checker.Engine
+		syncCore              *chainsync.Core
+		pendingBlocks         *buffer.PendingBlocks // used in follower engine
+		deltas                *ingestion.Deltas
+		syncEngine            *synchronization.Engine
+		followerEng           *followereng.Engine // to sync blocks from consensus nodes
+		computationManager    *computation.Manager
+		collectionRequester   *requester.Engine
+		ingestionEng          *ingestion.Engine
+		rpcConf               rpc.Config
+		ledgerRPCConf         remote.Config
+		err                   error
+		executionState        state.ExecutionState
+		queryState            state.ReadOnlyExecutionState
+		triedir               string
+		collector             module.ExecutionMetrics
+		queryReplica          bool
+		batchTrieUpdates      bool
+		walSyncPolicy         string
+		walRecordConfig       wal.RecordConfig
+		checkpointMode        string
+		featureFlags          []string
+		executionVersion      uint32
+		preferredExeNodeIDStr string
+		preferredExeFilter    flow.IdentityFilter
+		syncByBlocks          bool
+		syncFast              bool
+		extensiveLog          bool
+		checkStakedAtBlock    func(blockID flow.Identifier) (bool, error)
+		diskWAL               *wal.DiskWAL
+		chunkDataPacks        *storage.ChunkDataPacks
+		chunkDataPackCache    *storage.ChunkDataPackCache
 	)
 
 	cmd.FlowNode(flow.RoleExecution.String()).
+		Config(&conf).
 		ExtraFlags(func(flags *pflag.FlagSet) {
 			homedir, _ := os.UserHomeDir()
 			datadir := filepath.Join(homedir, ".flow", "execution")
 
 			flags.StringVarP(&rpcConf.ListenAddr, "rpc-addr", "i", "localhost:9000", "the address the gRPC server listens on")
 			flags.BoolVar(&rpcConf.RpcMetricsEnabled, "rpc-metrics-enabled", false, "whether to enable the rpc metrics")
+			flags.StringVar(&ledgerRPCConf.ListenAddr, "ledger-rpc-addr", "", "the address the read-only gRPC server of the ledger listens on, empty to disable")
 			flags.StringVar(&triedir, "triedir", datadir, "directory to store the execution State")
-			flags.Uint32Var(&mTrieCacheSize, "mtrie-cache-size", 500, "cache size for MTrie")
-			flags.UintVar(&checkpointDistance, "checkpoint-distance", 40, "number of WAL segments between checkpoints")
-			flags.UintVar(&checkpointsToKeep, "checkpoints-to-keep", 5, "number of recent checkpoints to keep (0 to keep all)")
-			flags.UintVar(&stateDeltasLimit, "state-deltas-limit", 100, "maximum number of state deltas in the memory pool")
-			flags.UintVar(&cadenceExecutionCache, "cadence-execution-cache", computation.DefaultProgramsCacheSize, "cache size for Cadence execution")
-			flags.UintVar(&chdpCacheSize, "chdp-cache", 100, "cache size for Chunk Data Packs")
-			flags.DurationVar(&requestInterval, "request-interval", 60*time.Second, "the interval between requests for the requester engine")
-			flags.DurationVar(&scriptLogThreshold, "script-log-threshold", computation.DefaultScriptLogThreshold, "threshold for logging script execution")
+			flags.BoolVar(&queryReplica, "ledger-query-replica", false, "serve script executions and account queries from a read-only replica of the ledger")
+			flags.BoolVar(&batchTrieUpdates, "batch-trie-updates", false, "apply the register updates of all chunks of a block as a single trie update")
+			flags.StringVar(&walSyncPolicy, "wal-sync-policy", wal.SyncNone.String(), "when to sync WAL records to disk: none, record, batch (group commit of concurrent records) or periodic")
+			flags.BoolVar(&walRecordConfig.Checksum, "wal-checksum", false, "write WAL records with a CRC32 checksum")
+			flags.BoolVar(&walRecordConfig.Compress, "wal-compression", false, "compress WAL records with zstd, implies checksums")
+			flags.BoolVar(&walRecordConfig.TruncateCorrupted, "wal-truncate-corrupted", false, "on startup, truncate the WAL at the first record failing checksum or header validation instead of failing")
+			flags.StringVar(&checkpointMode, "checkpoint-mode", wal.CheckpointFull.String(), "how checkpoints are created: full or incremental (only the tries created since the previous checkpoint)")
+			flags.StringSliceVar(&featureFlags, "fvm-feature-flags", nil, fmt.Sprintf("feature flags enabled in the virtual machine, reported in execution receipts (known flags: %v)", fvm.FeatureFlags()))
+			flags.Uint32Var(&executionVersion, "execution-version", 0, "version of the execution behaviour reported in execution receipts")
 			flags.StringVar(&preferredExeNodeIDStr, "preferred-exe-node-id", "", "node ID for preferred execution node used for state sync")
-			flags.UintVar(&transactionResultsCacheSize, "transaction-results-cache-size", 10000, "number of transaction results to be cached")
 			flags.BoolVar(&syncByBlocks, "sync-by-blocks", true, "deprecated, sync by blocks instead of execution state deltas")
 			flags.BoolVar(&syncFast, "sync-fast", false, "fast sync allows execution node to skip fetching collection during state syncing, and rely on state syncing to catch up")
-			flags.IntVar(&syncThreshold, "sync-threshold", 100, "the maximum number of sealed and unexecuted blocks before triggering state syncing")
 			flags.BoolVar(&extensiveLog, "extensive-logging", false, "extensive logging logs tx contents and block headers")
 		}).
 		Module("mutable follower state", func(node *cmd.FlowNodeBuilder) error {
@@ -146,12 +159,16 @@ func main() {
 			myReceipts = storage.NewMyExecutionReceipts(node.Metrics.Cache, node.DB, receipts)
 			return nil
 		}).
+		Module("chunk data pack storage", func(node *cmd.FlowNodeBuilder) error {
+			chunkDataPacks = storage.NewChunkDataPacks(node.Metrics.Cache, node.DB, conf.ChunkDataPackCacheSize)
+			return nil
+		}).
 		Module("pending block cache", func(node *cmd.FlowNodeBuilder) error {
 			pendingBlocks = buffer.NewPendingBlocks() // for following main chain consensus
 			return nil
 		}).
 		Module("state deltas mempool", func(node *cmd.FlowNodeBuilder) error {
-			deltas, err = ingestion.NewDeltas(stateDeltasLimit)
+			deltas, err = ingestion.NewDeltas(conf.StateDeltasLimit)
 			return err
 		}).
 		Module("stake checking function", func(node *cmd.FlowNodeBuilder) error {
@@ -161,7 +178,12 @@ func main() {
 			return nil
 		}).
 		Component("Write-Ahead Log", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
-			diskWAL, err = wal.NewDiskWAL(node.Logger.With().Str("subcomponent", "wal").Logger(), node.MetricsRegisterer, collector, triedir, int(mTrieCacheSize), pathfinder.PathByteSize, wal.SegmentSize)
+			policy, err := wal.ParseSyncPolicy(walSyncPolicy)
+			if err != nil {
+				return nil, err
+			}
+			syncConfig := wal.SyncConfig{Policy: policy, Interval: conf.WALSyncInterval}
+			diskWAL, err = wal.NewDiskWALWithConfig(node.Logger.With().Str("subcomponent", "wal").Logger(), node.MetricsRegisterer, collector, triedir, int(conf.MTrieCacheSize), pathfinder.PathByteSize, wal.SegmentSize, syncConfig, walRecordConfig)
 			return diskWAL, err
 		}).
 		Component("execution state ledger", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
@@ -199,7 +221,10 @@ func main() {
 				}
 			}
 
-			ledgerStorage, err = ledger.NewLedger(diskWAL, int(mTrieCacheSize), collector, node.Logger.With().Str("subcomponent", "ledger").Logger(), ledger.DefaultPathFinderVersion)
+			ledgerStorage, err = ledger.NewLedger(diskWAL, int(conf.MTrieCacheSize), collector, node.Logger.With().Str("subcomponent", "ledger").Logger(), ledger.DefaultPathFinderVersion,
+				ledger.WithMaxPathsPerQuery(conf.LedgerMaxPathsPerQuery),
+				ledger.WithMaxProofSize(conf.LedgerMaxProofSize),
+			)
 			return ledgerStorage, err
 		}).
 		Component("execution state ledger WAL compactor", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
@@ -208,10 +233,67 @@ func main() {
 			if err != nil {
 				return nil, fmt.Errorf("cannot create checkpointer: %w", err)
 			}
-			compactor := wal.NewCompactor(checkpointer, 10*time.Second, checkpointDistance, checkpointsToKeep)
+			mode, err := wal.ParseCheckpointMode(checkpointMode)
+			if err != nil {
+				return nil, err
+			}
+			opts := []wal.CompactorOption{
+				wal.WithLogger(node.Logger),
+				wal.WithWriteRateLimit(conf.CheckpointWriteRateLimit),
+				wal.WithCheckpointMode(mode, conf.CheckpointMaxIncremental),
+			}
+			if conf.CheckpointMaxBlockRate > 0 {
+				blockRate := ingestion.NewBlockRateMeter(time.Minute)
+				node.ProtocolEvents.AddConsumer(blockRate)
+				opts = append(opts, wal.WithOffPeakScheduling(blockRate, conf.CheckpointMaxBlockRate, conf.CheckpointMaxDelay))
+			}
+			compactor := wal.NewCompactor(checkpointer, 10*time.Second, conf.CheckpointDistance, conf.CheckpointsToKeep, opts...)
 
 			return compactor, nil
 		}).
+		Component("execution state ledger gRPC server", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if ledgerRPCConf.ListenAddr == "" {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			return remote.NewServer(node.Logger, ledgerStorage, ledgerRPCConf), nil
+		}).
+		Component("execution state ledger query replica", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if !queryReplica {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+
+			ledgerReplica, err = ledgerStorage.NewReplica(int(conf.QueryReplicaCapacity), conf.QueryReplicaMaxLag, collector)
+			if err != nil {
+				return nil, fmt.Errorf("cannot create query replica: %w", err)
+			}
+			return ledgerReplica, nil
+		}).
+		Component("chunk data pack cache", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if conf.ChunkDataPackCacheDir == "" {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			err := os.MkdirAll(conf.ChunkDataPackCacheDir, 0700)
+			if err != nil {
+				return nil, fmt.Errorf("could not create chunk data pack cache dir: %w", err)
+			}
+			// the cache has its own database, so that its chunk data packs are dropped without
+			// compacting the database of the node; the chunk data packs of unsealed blocks are held
+			// by the cache only, so its database is opened like the database of the node
+			opts := badger.DefaultOptions(conf.ChunkDataPackCacheDir).
+				WithLogger(sutil.NewLogger(node.Logger)).
+				WithValueLogFileSize(128 << 23).
+				WithValueLogMaxEntries(100000)
+			db, err := badger.Open(opts)
+			if err != nil {
+				return nil, fmt.Errorf("could not open chunk data pack cache database: %w", err)
+			}
+			chunkDataPackCache, err = storage.NewChunkDataPackCache(node.Logger, db, conf.ChunkDataPackCacheBytes, chunkDataPacks)
+			if err != nil {
+				return nil, err
+			}
+			// the cache is done after the engines using it, as components are done in reverse order
+			return chunkDataPackCache, nil
+		}).
 		Component("provider engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
 			extraLogPath := path.Join(triedir, "extralogs")
 			err := os.MkdirAll(extraLogPath, 0777)
@@ -221,12 +303,26 @@ func main() {
 
 			extralog.ExtraLogDumpPath = extraLogPath
 
-			rt := fvm.NewInterpreterRuntime()
-
-			vm := fvm.NewVirtualMachine(rt)
-			vmCtx := fvm.NewContext(node.Logger, node.FvmOptions...)
+			var vm *fvm.VirtualMachine
+			if conf.RuntimePoolSize > 0 {
+				vm = fvm.NewVirtualMachineWithRuntimeProvider(fvm.NewInterpreterRuntimePool(int(conf.RuntimePoolSize)))
+			} else {
+				vm = fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+			}
+			featureFlagOptions, err := fvm.FeatureFlagOptions(featureFlags)
+			if err != nil {
+				return nil, fmt.Errorf("invalid feature flags: %w", err)
+			}
+			vmCtx := fvm.NewContextFromParent(fvm.NewContext(node.Logger, node.FvmOptions...), featureFlagOptions...)
 
-			committer := committer.NewLedgerViewCommitter(ledgerStorage, node.Tracer)
+			var viewCommitter computer.ViewCommitter = committer.NewLedgerViewCommitter(ledgerStorage, node.Tracer)
+			if batchTrieUpdates {
+				viewCommitter = committer.NewBatchLedgerViewCommitter(ledgerStorage, node.Tracer)
+			}
+			var computerOpts []computer.Option
+			if conf.ExecutionJournal {
+				computerOpts = append(computerOpts, computer.WithExecutionJournal(storage.NewExecutionJournal(node.DB)))
+			}
 			manager, err := computation.New(
 				node.Logger,
 				collector,
@@ -235,22 +331,29 @@ func main() {
 				node.State,
 				vm,
 				vmCtx,
-				cadenceExecutionCache,
-				committer,
-				scriptLogThreshold,
+				conf.CadenceExecutionCache,
+				viewCommitter,
+				conf.ScriptLogThreshold,
+				conf.ScriptCacheSize,
+				conf.ScriptCacheTTL,
+				computerOpts...,
 			)
 			if err != nil {
 				return nil, err
 			}
 			computationManager = manager
 
-			chunkDataPacks := storage.NewChunkDataPacks(node.Metrics.Cache, node.DB, chdpCacheSize)
+			// the execution state stores the chunk data packs in the cache, if it is enabled
+			var cache flowstorage.ChunkDataPackCache
+			if chunkDataPackCache != nil {
+				cache = chunkDataPackCache
+			}
 			stateCommitments := storage.NewCommits(node.Metrics.Cache, node.DB)
 
 			// Needed for gRPC server, make sure to assign to main scoped vars
 			events = storage.NewEvents(node.Metrics.Cache, node.DB)
 			serviceEvents = storage.NewServiceEvents(node.Metrics.Cache, node.DB)
-			txResults = storage.NewTransactionResults(node.Metrics.Cache, node.DB, transactionResultsCacheSize)
+			txResults = storage.NewTransactionResults(node.Metrics.Cache, node.DB, conf.TransactionResultsCacheSize)
 
 			executionState = state.NewExecutionState(
 				ledgerStorage,
@@ -259,6 +362,7 @@ func main() {
 				node.Storage.Headers,
 				node.Storage.Collections,
 				chunkDataPacks,
+				cache,
 				results,
 				receipts,
 				myReceipts,
@@ -269,6 +373,27 @@ func main() {
 				node.Tracer,
 			)
 
+			queryState = executionState
+			if ledgerReplica != nil {
+				queryState = state.NewExecutionState(
+					ledgerReplica,
+					stateCommitments,
+					node.Storage.Blocks,
+					node.Storage.Headers,
+					node.Storage.Collections,
+					chunkDataPacks,
+					cache,
+					results,
+					receipts,
+					myReceipts,
+					events,
+					serviceEvents,
+					txResults,
+					node.DB,
+					node.Tracer,
+				)
+			}
+
 			providerEngine, err = exeprovider.New(
 				node.Logger,
 				node.Tracer,
@@ -279,8 +404,14 @@ func main() {
 				collector,
 				checkStakedAtBlock,
 			)
+			if err != nil {
+				return nil, err
+			}
+			if chunkDataPackCache != nil {
+				providerEngine = providerEngine.WithChunkDataPackCache(chunkDataPackCache)
+			}
 
-			return providerEngine, err
+			return providerEngine, nil
 		}).
 		Component("checker engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
 			checkerEng = checker.New(
@@ -297,10 +428,10 @@ func main() {
 				filter.HasRole(flow.RoleCollection),
 				func() flow.Entity { return &flow.Collection{} },
 				// we are manually triggering batches in execution, but lets still send off a batch once a minute, as a safety net for the sake of retries
-				requester.WithBatchInterval(requestInterval),
+				requester.WithBatchInterval(conf.RequestInterval),
 			)
 
-			preferredExeFilter := filter.Any
+			preferredExeFilter = filter.Any
 			preferredExeNodeID, err := flow.HexStringToIdentifier(preferredExeNodeIDStr)
 			if err == nil {
 				node.Logger.Info().Hex("prefered_exe_node_id", preferredExeNodeID[:]).Msg("starting with preferred exe sync node")
@@ -327,20 +458,54 @@ func main() {
 				node.Tracer,
 				extensiveLog,
 				preferredExeFilter,
-				deltas,
-				syncThreshold,
 				syncFast,
 				checkStakedAtBlock,
+				state.NewStatePinner(ledgerStorage),
 			)
 
 			// TODO: we should solve these mutual dependencies better
 			// => https://github.com/dapperlabs/flow-go/issues/4360
 			collectionRequester = collectionRequester.WithHandle(ingestionEng.OnCollection)
+			ingestionEng = ingestionEng.WithQueryState(queryState)
+			ingestionEng = ingestionEng.WithExecutionMetadata(flow.ExecutionMetadata{
+				Version:   executionVersion,
+				FlagsHash: flow.FeatureFlagsHash(featureFlags),
+			})
+			if conf.PruningRetainedBlocks > 0 {
+				ingestionEng = ingestionEng.WithLedgerPruner(pruner.New(node.Logger, ledgerStorage.Forest(), conf.PruningRetainedBlocks, collector))
+			}
+			if chunkDataPackCache != nil {
+				ingestionEng = ingestionEng.WithChunkDataPackCache(chunkDataPackCache)
+			}
+			if conf.StateSync {
+				ingestionEng = ingestionEng.WithPersistedStateInteractions()
+			}
 
 			node.ProtocolEvents.AddConsumer(ingestionEng)
 
 			return ingestionEng, err
 		}).
+		Component("state sync engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if !conf.StateSync {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			return statesync.New(
+				node.Logger,
+				node.Network,
+				node.Me,
+				node.State,
+				node.Storage.Seals,
+				results,
+				executionState,
+				ledgerStorage,
+				deltas,
+				preferredExeFilter,
+				ingestionEng.OnStateDeltaApplied,
+				statesync.WithThreshold(uint64(conf.SyncThreshold)),
+				statesync.WithMaxRange(conf.StateSyncMaxRange),
+				statesync.WithCheckInterval(conf.StateSyncInterval),
+			)
+		}).
 		Component("follower engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
 
 			// initialize cleaner for DB
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/config"
+	"github.com/onflow/flow-go/consensus"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	recovery "github.com/onflow/flow-go/consensus/recovery/protocol"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/rpc"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	followereng "github.com/onflow/flow-go/engine/common/follower"
+	synceng "github.com/onflow/flow-go/engine/common/synchronization"
+	"github.com/onflow/flow-go/model/encodable"
+	"github.com/onflow/flow-go/model/encoding"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/buffer"
+	finalizer "github.com/onflow/flow-go/module/finalizer/consensus"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/signature"
+	"github.com/onflow/flow-go/module/synchronization"
+	"github.com/onflow/flow-go/state/protocol"
+	badgerState "github.com/onflow/flow-go/state/protocol/badger"
+	storage "github.com/onflow/flow-go/storage/badger"
+)
+
+// The observer is an unstaked node which follows the chain through the public network of its upstream
+// access nodes, and serves reads of the chain through the Access API. It doesn't ingest collections, and
+// forwards scripts and transactions like the access nodes do.
+func main() {
+
+	var (
+		conf               = config.DefaultAccess()
+		collectionGRPCPort uint
+		executionGRPCPort  uint
+		sealedResultCheck  string
+		followerState      protocol.MutableState
+		followerEng        *followereng.Engine
+		syncCore           *synchronization.Core
+		conCache           *buffer.PendingBlocks // pending block cache for follower
+		err                error
+	)
+
+	cmd.FlowObserverNode().
+		Config(&conf).
+		ExtraFlags(func(flags *pflag.FlagSet) {
+			flags.UintVar(&collectionGRPCPort, "collection-ingress-port", 9000, "the grpc ingress port for all collection nodes")
+			flags.UintVar(&executionGRPCPort, "execution-ingress-port", 9000, "the grpc ingress port for all execution nodes")
+			flags.StringVarP(&conf.RPC.GRPCListenAddr, "rpc-addr", "r", "localhost:9000", "the address the gRPC server listens on")
+			flags.StringVarP(&conf.RPC.HTTPListenAddr, "http-addr", "h", "localhost:8000", "the address the http proxy server listens on")
+			flags.StringSliceVar(&conf.RPC.PreferredExecutionNodeIDs, "preferred-execution-node-ids", nil, "comma separated list of execution nodes ids to choose from when making an upstream call e.g. b4a4dbdcd443d...,fb386a6a... etc.")
+			flags.StringSliceVar(&conf.RPC.FixedExecutionNodeIDs, "fixed-execution-node-ids", nil, "comma separated list of execution nodes ids to choose from when making an upstream call if no matching preferred execution id is found e.g. b4a4dbdcd443d...,fb386a6a... etc.")
+			flags.StringVar(&sealedResultCheck, "sealed-result-check", "disabled", "check of transaction results served from execution nodes against the sealed results of their blocks: disabled, flag (log contradicting results) or refuse (don't serve contradicting results)")
+		}).
+		Module("mutable follower state", func(node *cmd.FlowNodeBuilder) error {
+			// For now, we only support state implementations from package badger.
+			// If we ever support different implementations, the following can be replaced by a type-aware factory
+			state, ok := node.State.(*badgerState.State)
+			if !ok {
+				return fmt.Errorf("only implementations of type badger.State are currenlty supported but read-only state has type %T", node.State)
+			}
+			followerState, err = badgerState.NewFollowerState(
+				state,
+				node.Storage.Index,
+				node.Storage.Payloads,
+				node.Tracer,
+				node.ProtocolEvents,
+			)
+			return err
+		}).
+		Module("block cache", func(node *cmd.FlowNodeBuilder) error {
+			conCache = buffer.NewPendingBlocks()
+			return nil
+		}).
+		Module("sync core", func(node *cmd.FlowNodeBuilder) error {
+			syncCore, err = synchronization.New(node.Logger, synchronization.DefaultConfig())
+			return err
+		}).
+		Component("RPC engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			conf.RPC.SealedResultCheck, err = backend.ParseSealedResultCheck(sealedResultCheck)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sealed result check: %w", err)
+			}
+			rpcEng := rpc.New(
+				node.Logger,
+				node.State,
+				conf.RPC,
+				nil,
+				nil,
+				nil,
+				nil,
+				node.Storage.Blocks,
+				node.Storage.Headers,
+				node.Storage.Collections,
+				node.Storage.Transactions,
+				node.Storage.Receipts,
+				node.Storage.Seals,
+				node.RootChainID,
+				metrics.NewNoopCollector(),
+				collectionGRPCPort,
+				executionGRPCPort,
+				false,
+				false,
+				nil,
+				nil,
+			)
+			return rpcEng, nil
+		}).
+		Component("follower engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+
+			// initialize cleaner for DB
+			cleaner := storage.NewCleaner(node.Logger, node.DB, metrics.NewCleanerCollector(), flow.DefaultValueLogGCFrequency)
+
+			// create a finalizer that will handle updating the protocol
+			// state when the follower detects newly finalized blocks
+			final := finalizer.NewFinalizer(node.DB, node.Storage.Headers, followerState)
+
+			// initialize the staking & beacon verifiers, signature joiner
+			staking := signature.NewAggregationVerifier(encoding.ConsensusVoteTag)
+			beacon := signature.NewThresholdVerifier(encoding.RandomBeaconTag)
+			merger := signature.NewCombiner(encodable.ConsensusVoteSigLen, encodable.RandomBeaconSigLen)
+
+			// initialize consensus committee's membership state
+			// This committee state is for the HotStuff follower, which follows the MAIN CONSENSUS Committee
+			// Note: node.Me.NodeID() is not part of the consensus committee
+			committee, err := committees.NewConsensusCommittee(node.State, node.Me.NodeID())
+			if err != nil {
+				return nil, fmt.Errorf("could not create Committee state for main consensus: %w", err)
+			}
+
+			// initialize the verifier for the protocol consensus
+			verifier := verification.NewCombinedVerifier(committee, staking, beacon, merger)
+
+			finalized, pending, err := recovery.FindLatest(node.State, node.Storage.Headers)
+			if err != nil {
+				return nil, fmt.Errorf("could not find latest finalized block and pending blocks to recover consensus follower: %w", err)
+			}
+
+			// the observer doesn't ingest collections, so nothing needs to be notified of finalized blocks
+			followerCore, err := consensus.NewFollower(node.Logger, committee, node.Storage.Headers, final, verifier, notifications.NewNoopConsumer(), node.RootBlock.Header, node.RootQC, finalized, pending)
+			if err != nil {
+				return nil, fmt.Errorf("could not initialize follower core: %w", err)
+			}
+
+			followerEng, err = followereng.New(
+				node.Logger,
+				node.Network,
+				node.Me,
+				node.Metrics.Engine,
+				node.Metrics.Mempool,
+				cleaner,
+				node.Storage.Headers,
+				node.Storage.Payloads,
+				followerState,
+				conCache,
+				followerCore,
+				syncCore,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not create follower engine: %w", err)
+			}
+
+			return followerEng, nil
+		}).
+		Component("sync engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			// synchronizes with the upstream access nodes through their public network
+			upstreams := node.Network.Identity
+			sync, err := synceng.New(
+				node.Logger,
+				node.Metrics.Engine,
+				node.Network,
+				node.Me,
+				node.State,
+				node.Storage.Blocks,
+				followerEng,
+				syncCore,
+				synceng.WithChannel(engine.PublicSyncCommittee),
+				synceng.WithTargets(func() (flow.IdentifierList, error) {
+					identities, err := upstreams()
+					if err != nil {
+						return nil, err
+					}
+					targets := make(flow.IdentifierList, 0, len(identities))
+					for nodeID := range identities {
+						targets = append(targets, nodeID)
+					}
+					return targets, nil
+				}),
+				synceng.WithUnicastRequests(),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not create synchronization engine: %w", err)
+			}
+			return sync, nil
+		}).
+		Run()
+}
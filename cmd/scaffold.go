@@ -17,17 +17,20 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/onflow/flow-go/cmd/build"
+	"github.com/onflow/flow-go/cmd/config"
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/admin"
 	"github.com/onflow/flow-go/module/local"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/network"
 	jsoncodec "github.com/onflow/flow-go/network/codec/json"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/dns"
 	"github.com/onflow/flow-go/network/topology"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
@@ -54,12 +57,19 @@ type BaseConfig struct {
 	datadir          string
 	level            string
 	metricsPort      uint
+	adminAddr        string
 	BootstrapDir     string
 	profilerEnabled  bool
 	profilerDir      string
 	profilerInterval time.Duration
 	profilerDuration time.Duration
 	tracerEnabled    bool
+	configFile       string
+	streamPool       p2p.StreamPoolConfig
+	dns              dns.Config
+	unstaked         bool     // whether the node runs without a staked identity, see FlowObserverNode
+	upstreamPeers    []string // upstream access nodes of unstaked nodes, as <node ID>@<address>
+	networkKeyPath   string   // networking key file of unstaked nodes
 }
 
 type Metrics struct {
@@ -113,6 +123,7 @@ type FlowNodeBuilder struct {
 	BaseConfig        BaseConfig
 	NodeID            flow.Identifier
 	flags             *pflag.FlagSet
+	sections          []config.Section
 	Logger            zerolog.Logger
 	Me                *local.Local
 	Tracer            module.Tracer
@@ -124,6 +135,8 @@ type FlowNodeBuilder struct {
 	State             protocol.State
 	Middleware        *p2p.Middleware
 	Network           *p2p.Network
+	Resolver          *dns.Resolver
+	Admin             *admin.Server
 	MsgValidators     []network.MessageValidator
 	FvmOptions        []fvm.Option
 	modules           []namedModuleFunc
@@ -153,6 +166,8 @@ func (fnb *FlowNodeBuilder) baseFlags() {
 	fnb.flags.StringVarP(&fnb.BaseConfig.datadir, "datadir", "d", datadir, "directory to store the protocol state")
 	fnb.flags.StringVarP(&fnb.BaseConfig.level, "loglevel", "l", "info", "level for logging output")
 	fnb.flags.UintVarP(&fnb.BaseConfig.metricsPort, "metricport", "m", 8080, "port for /metrics endpoint")
+	fnb.flags.StringVar(&fnb.BaseConfig.adminAddr, "admin-addr", "",
+		"address for the admin endpoints, which expose the internal state of the node to operators (disabled if empty)")
 	fnb.flags.BoolVar(&fnb.BaseConfig.profilerEnabled, "profiler-enabled", false, "whether to enable the auto-profiler")
 	fnb.flags.StringVar(&fnb.BaseConfig.profilerDir, "profiler-dir", "profiler", "directory to create auto-profiler profiles")
 	fnb.flags.DurationVar(&fnb.BaseConfig.profilerInterval, "profiler-interval", 15*time.Minute,
@@ -161,7 +176,26 @@ func (fnb *FlowNodeBuilder) baseFlags() {
 		"the duration to run the auto-profile for")
 	fnb.flags.BoolVar(&fnb.BaseConfig.tracerEnabled, "tracer-enabled", false,
 		"whether to enable tracer")
-
+	fnb.flags.StringVar(&fnb.BaseConfig.configFile, "config", "",
+		"path to a YAML, TOML or JSON file with the values of the flags, which are overridden by the command line")
+	fnb.flags.UintVar(&fnb.BaseConfig.streamPool.MaxIdlePerPeer, "unicast-max-idle-streams", p2p.DefaultStreamPoolConfig.MaxIdlePerPeer,
+		"maximum number of idle unicast streams kept per peer for reuse (0 to create a stream per message)")
+	fnb.flags.DurationVar(&fnb.BaseConfig.streamPool.IdleTimeout, "unicast-stream-idle-timeout", p2p.DefaultStreamPoolConfig.IdleTimeout,
+		"time after which idle unicast streams are closed")
+	fnb.flags.DurationVar(&fnb.BaseConfig.dns.TTL, "dns-cache-ttl", dns.DefaultConfig.TTL,
+		"time for which the resolved addresses of node hostnames are cached before being resolved again")
+	fnb.flags.DurationVar(&fnb.BaseConfig.dns.NegativeTTL, "dns-negative-cache-ttl", dns.DefaultConfig.NegativeTTL,
+		"time for which failed lookups of node hostnames are cached before being retried")
+	fnb.flags.DurationVar(&fnb.BaseConfig.dns.LookupTimeout, "dns-lookup-timeout", dns.DefaultConfig.LookupTimeout,
+		"maximum time spent resolving a node hostname")
+
+}
+
+func (fnb *FlowNodeBuilder) enqueueResolverInit() {
+	fnb.Component("dns resolver", func(builder *FlowNodeBuilder) (module.ReadyDoneAware, error) {
+		fnb.Resolver = dns.NewResolver(fnb.Logger, fnb.Metrics.Network, fnb.BaseConfig.dns)
+		return fnb.Resolver, nil
+	})
 }
 
 func (fnb *FlowNodeBuilder) enqueueNetworkInit() {
@@ -174,20 +208,6 @@ func (fnb *FlowNodeBuilder) enqueueNetworkInit() {
 			myAddr = fnb.BaseConfig.bindAddr
 		}
 
-		// setup the Ping provider to return the software version and the finalized block height
-		pingProvider := p2p.PingInfoProviderImpl{
-			SoftwareVersionFun: func() string {
-				return build.Semver()
-			},
-			SealedBlockHeightFun: func() (uint64, error) {
-				head, err := fnb.State.Sealed().Head()
-				if err != nil {
-					return 0, err
-				}
-				return head.Height, nil
-			},
-		}
-
 		libP2PNodeFactory, err := p2p.DefaultLibP2PNodeFactory(fnb.Logger.Level(zerolog.ErrorLevel),
 			fnb.Me.NodeID(),
 			myAddr,
@@ -195,7 +215,8 @@ func (fnb *FlowNodeBuilder) enqueueNetworkInit() {
 			fnb.RootBlock.ID().String(),
 			p2p.DefaultMaxPubSubMsgSize,
 			fnb.Metrics.Network,
-			pingProvider)
+			fnb.pingInfoProvider(),
+			fnb.Resolver)
 		if err != nil {
 			return nil, fmt.Errorf("could not generate libp2p node factory: %w", err)
 		}
@@ -205,21 +226,32 @@ func (fnb *FlowNodeBuilder) enqueueNetworkInit() {
 			fnb.Me.NodeID(),
 			fnb.Metrics.Network,
 			fnb.RootBlock.ID().String(),
+			fnb.BaseConfig.streamPool,
 			fnb.MsgValidators...)
 
-		participants, err := fnb.State.Final().Identities(p2p.NetworkingSetFilter)
-		if err != nil {
-			return nil, fmt.Errorf("could not get network identities: %w", err)
-		}
-
 		// creates topology, topology manager, and subscription managers
 		//
 		// topology
 		// subscription manager
 		subscriptionManager := p2p.NewChannelSubscriptionManager(fnb.Middleware)
-		top, err := topology.NewTopicBasedTopology(fnb.NodeID, fnb.Logger, fnb.State)
-		if err != nil {
-			return nil, fmt.Errorf("could not create topology: %w", err)
+		var participants flow.IdentityList
+		var top network.Topology
+		if fnb.BaseConfig.unstaked {
+			// unstaked nodes only connect to their upstream access nodes
+			participants, err = fnb.upstreamIdentities()
+			if err != nil {
+				return nil, fmt.Errorf("could not get upstream identities: %w", err)
+			}
+			top = topology.NewFullyConnectedTopology()
+		} else {
+			participants, err = fnb.State.Final().Identities(p2p.NetworkingSetFilter)
+			if err != nil {
+				return nil, fmt.Errorf("could not get network identities: %w", err)
+			}
+			top, err = topology.NewTopicBasedTopology(fnb.NodeID, fnb.Logger, fnb.State)
+			if err != nil {
+				return nil, fmt.Errorf("could not create topology: %w", err)
+			}
 		}
 		topologyCache := topology.NewCache(fnb.Logger, top)
 
@@ -239,6 +271,11 @@ func (fnb *FlowNodeBuilder) enqueueNetworkInit() {
 
 		fnb.Network = net
 
+		// the identities of the upstream access nodes of unstaked nodes are fixed
+		if fnb.BaseConfig.unstaked {
+			return net, err
+		}
+
 		idRefresher := p2p.NewNodeIDRefresher(fnb.Logger, fnb.State, net.SetIDs)
 		idEvents := gadgets.NewIdentityDeltas(idRefresher.OnIdentityTableChanged)
 		fnb.ProtocolEvents.AddConsumer(idEvents)
@@ -247,6 +284,22 @@ func (fnb *FlowNodeBuilder) enqueueNetworkInit() {
 	})
 }
 
+// pingInfoProvider returns the Ping provider, which returns the software version and the sealed block height.
+func (fnb *FlowNodeBuilder) pingInfoProvider() p2p.PingInfoProvider {
+	return p2p.PingInfoProviderImpl{
+		SoftwareVersionFun: func() string {
+			return build.Semver()
+		},
+		SealedBlockHeightFun: func() (uint64, error) {
+			head, err := fnb.State.Sealed().Head()
+			if err != nil {
+				return 0, err
+			}
+			return head.Height, nil
+		},
+	}
+}
+
 func (fnb *FlowNodeBuilder) enqueueMetricsServerInit() {
 	fnb.Component("metrics server", func(builder *FlowNodeBuilder) (module.ReadyDoneAware, error) {
 		server := metrics.NewServer(fnb.Logger, fnb.BaseConfig.metricsPort, fnb.BaseConfig.profilerEnabled)
@@ -254,6 +307,12 @@ func (fnb *FlowNodeBuilder) enqueueMetricsServerInit() {
 	})
 }
 
+func (fnb *FlowNodeBuilder) enqueueAdminServerInit() {
+	fnb.Component("admin server", func(builder *FlowNodeBuilder) (module.ReadyDoneAware, error) {
+		return fnb.Admin, nil
+	})
+}
+
 func (fnb *FlowNodeBuilder) registerBadgerMetrics() {
 	metrics.RegisterBadgerMetrics()
 }
@@ -268,6 +327,27 @@ func (fnb *FlowNodeBuilder) parseAndPrintFlags() {
 	// parse configuration parameters
 	pflag.Parse()
 
+	// fill in the flags not set on the command line from the configuration file
+	if fnb.BaseConfig.configFile != "" {
+		err := config.Apply(fnb.BaseConfig.configFile, fnb.flags)
+		if err != nil {
+			fnb.Logger.Fatal().Err(err).Msg("could not apply configuration file")
+		}
+	}
+
+	for _, section := range fnb.sections {
+		err := section.Validate()
+		if err != nil {
+			fnb.Logger.Fatal().Err(err).Msg("invalid configuration")
+		}
+	}
+	if fnb.BaseConfig.streamPool.MaxIdlePerPeer > 0 && fnb.BaseConfig.streamPool.IdleTimeout <= 0 {
+		fnb.Logger.Fatal().Msg("invalid configuration: unicast-stream-idle-timeout must be positive")
+	}
+	if fnb.BaseConfig.dns.TTL <= 0 || fnb.BaseConfig.dns.NegativeTTL <= 0 || fnb.BaseConfig.dns.LookupTimeout <= 0 {
+		fnb.Logger.Fatal().Msg("invalid configuration: dns-cache-ttl, dns-negative-cache-ttl and dns-lookup-timeout must be positive")
+	}
+
 	// print all flags
 	log := fnb.Logger.Info()
 
@@ -283,6 +363,11 @@ func (fnb *FlowNodeBuilder) printBuildVersionDetails() {
 }
 
 func (fnb *FlowNodeBuilder) initNodeInfo() {
+	if fnb.BaseConfig.unstaked {
+		fnb.initUnstakedNodeInfo()
+		return
+	}
+
 	if fnb.BaseConfig.nodeIDHex == notSet {
 		fnb.Logger.Fatal().Msg("cannot start without node ID")
 	}
@@ -350,6 +435,12 @@ func (fnb *FlowNodeBuilder) initMetrics() {
 	})
 }
 
+// initAdmin creates the admin server, so that the modules and components of the node can
+// register their admin endpoints before it starts.
+func (fnb *FlowNodeBuilder) initAdmin() {
+	fnb.Admin = admin.NewServer(fnb.Logger, fnb.BaseConfig.adminAddr)
+}
+
 func (fnb *FlowNodeBuilder) initProfiler() {
 	if !fnb.BaseConfig.profilerEnabled {
 		return
@@ -474,20 +565,6 @@ func (fnb *FlowNodeBuilder) initState() {
 		)
 		fnb.MustNot(err).Msg("could not open flow state")
 		fnb.State = state
-
-		// Verify root block in protocol state is consistent with bootstrap information stored on-disk.
-		// Inconsistencies can happen when the bootstrap root block is updated (because of new spork),
-		// but the protocol state is not updated, so they don't match
-		// when this happens during a spork, we could try deleting the protocol state database.
-		// TODO: revisit this check when implementing Epoch
-		rootBlockFromState, err := state.Params().Root()
-		fnb.MustNot(err).Msg("could not load root block from protocol state")
-		if fnb.RootBlock.ID() != rootBlockFromState.ID() {
-			fnb.Logger.Fatal().Msgf("mismatching root block ID, protocol state block ID: %v, bootstrap root block ID: %v",
-				rootBlockFromState.ID(),
-				fnb.RootBlock.ID(),
-			)
-		}
 	} else {
 		// Bootstrap!
 		fnb.Logger.Info().Msg("bootstrapping empty protocol state")
@@ -514,6 +591,30 @@ func (fnb *FlowNodeBuilder) initState() {
 			Msg("genesis state bootstrapped")
 	}
 
+	// Verify root block in protocol state is consistent with bootstrap information stored on-disk.
+	// Inconsistencies can happen when the bootstrap root block is updated (because of new spork),
+	// but the protocol state is not updated, so they don't match
+	// when this happens during a spork, we could try deleting the protocol state database.
+	// TODO: revisit this check when implementing Epoch
+	fnb.verifyChainCompatibility()
+
+	if fnb.BaseConfig.unstaked {
+		fnb.initUnstakedLocal()
+	} else {
+		fnb.initLocal()
+	}
+
+	lastFinalized, err := fnb.State.Final().Head()
+	fnb.MustNot(err).Msg("could not get last finalized block header")
+	fnb.Logger.Info().
+		Hex("block_id", logging.Entity(lastFinalized)).
+		Uint64("height", lastFinalized.Height).
+		Msg("last finalized block")
+}
+
+// initLocal verifies that the identity of the node is consistent with the protocol state,
+// and initializes the local module with it.
+func (fnb *FlowNodeBuilder) initLocal() {
 	// Verify that my ID (as given in the configuration) is known to the network
 	// (i.e. protocol state). There are two cases that will cause the following error:
 	// 1) used the wrong node id, which is not part of the identity list of the finalized state
@@ -550,13 +651,44 @@ func (fnb *FlowNodeBuilder) initState() {
 
 	fnb.Me, err = local.New(self, fnb.stakingKey)
 	fnb.MustNot(err).Msg("could not initialize local")
+}
 
-	lastFinalized, err := fnb.State.Final().Head()
-	fnb.MustNot(err).Msg("could not get last finalized block header")
-	fnb.Logger.Info().
-		Hex("block_id", logging.Entity(lastFinalized)).
-		Uint64("height", lastFinalized.Height).
-		Msg("last finalized block")
+// verifyChainCompatibility verifies that the chain of the protocol state in the database is
+// supported by this binary, and matches the chain ID and root block of the bootstrap information
+// stored on-disk. Otherwise, the node refuses to start, rather than generating wrong addresses
+// and events, e.g. when pointed at the database of another network.
+func (fnb *FlowNodeBuilder) verifyChainCompatibility() {
+	chainID, err := fnb.State.Params().ChainID()
+	fnb.MustNot(err).Msg("could not load chain ID from protocol state")
+	rootBlockFromState, err := fnb.State.Params().Root()
+	fnb.MustNot(err).Msg("could not load root block from protocol state")
+
+	err = chainID.Validate()
+	if err != nil {
+		fnb.Logger.Fatal().Err(err).
+			Str("datadir", fnb.BaseConfig.datadir).
+			Msg("protocol state is for a chain incompatible with this binary")
+	}
+
+	if chainID != fnb.RootChainID {
+		fnb.Logger.Fatal().
+			Str("datadir", fnb.BaseConfig.datadir).
+			Str("bootstrapdir", fnb.BaseConfig.BootstrapDir).
+			Msgf("mismatching chain ID, protocol state chain ID: %v, bootstrap chain ID: %v",
+				chainID,
+				fnb.RootChainID,
+			)
+	}
+
+	if fnb.RootBlock.ID() != rootBlockFromState.ID() {
+		fnb.Logger.Fatal().
+			Str("datadir", fnb.BaseConfig.datadir).
+			Str("bootstrapdir", fnb.BaseConfig.BootstrapDir).
+			Msgf("mismatching root block ID, protocol state block ID: %v, bootstrap root block ID: %v",
+				rootBlockFromState.ID(),
+				fnb.RootBlock.ID(),
+			)
+	}
 }
 
 func (fnb *FlowNodeBuilder) initFvmOptions() {
@@ -632,6 +764,14 @@ func (fnb *FlowNodeBuilder) ExtraFlags(f func(*pflag.FlagSet)) *FlowNodeBuilder
 	return fnb
 }
 
+// Config binds the tunables of the typed configuration section to flags, which are
+// validated once the flags and the configuration file are parsed.
+func (fnb *FlowNodeBuilder) Config(section config.Section) *FlowNodeBuilder {
+	section.Flags(fnb.flags)
+	fnb.sections = append(fnb.sections, section)
+	return fnb
+}
+
 // Module enables setting up dependencies of the engine with the builder context.
 func (fnb *FlowNodeBuilder) Module(name string, f func(builder *FlowNodeBuilder) error) *FlowNodeBuilder {
 	fnb.modules = append(fnb.modules, namedModuleFunc{
@@ -685,10 +825,14 @@ func FlowNode(role string) *FlowNodeBuilder {
 
 	builder.baseFlags()
 
+	builder.enqueueResolverInit()
+
 	builder.enqueueNetworkInit()
 
 	builder.enqueueMetricsServerInit()
 
+	builder.enqueueAdminServerInit()
+
 	builder.registerBadgerMetrics()
 
 	builder.enqueueTracer()
@@ -716,6 +860,8 @@ func (fnb *FlowNodeBuilder) Run() {
 
 	fnb.initLogger()
 
+	fnb.initAdmin()
+
 	fnb.initProfiler()
 
 	fnb.initDB()
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/local"
+	jsoncodec "github.com/onflow/flow-go/network/codec/json"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/topology"
+	"github.com/onflow/flow-go/utils/io"
+)
+
+// FlowObserverNode creates a new node builder for an unstaked observer. Observers have no identity in the
+// protocol state: they derive their node ID from their networking key, and follow the chain by synchronizing
+// with their upstream access nodes through the public network of the access nodes, so that third parties can
+// serve reads of the chain.
+func FlowObserverNode() *FlowNodeBuilder {
+	builder := FlowNode("observer")
+	builder.BaseConfig.unstaked = true
+
+	builder.flags.StringSliceVar(&builder.BaseConfig.upstreamPeers, "upstream-peers", nil,
+		"upstream access nodes to follow the chain through, as <node ID>@<public network address>")
+	builder.flags.StringVar(&builder.BaseConfig.networkKeyPath, "networking-key", "",
+		"file of the hex encoded networking key, which is generated if it doesn't exist (default: new key on each start)")
+
+	return builder
+}
+
+// initUnstakedNodeInfo loads or generates the networking key of an unstaked node, and derives its node ID
+// from it.
+func (fnb *FlowNodeBuilder) initUnstakedNodeInfo() {
+	networkKey, err := loadNetworkingKey(fnb.BaseConfig.networkKeyPath)
+	if err != nil {
+		fnb.Logger.Fatal().Err(err).Msg("could not load networking key")
+	}
+
+	nodeID, err := p2p.UnstakedNodeID(networkKey.PublicKey())
+	if err != nil {
+		fnb.Logger.Fatal().Err(err).Msg("could not derive node ID from networking key")
+	}
+
+	fnb.NodeID = nodeID
+	fnb.BaseConfig.nodeIDHex = nodeID.String()
+	fnb.networkKey = networkKey
+}
+
+// initUnstakedLocal initializes the local module of an unstaked node. It has no staking key, so it can't
+// sign any messages.
+func (fnb *FlowNodeBuilder) initUnstakedLocal() {
+	if fnb.BaseConfig.bindAddr == notSet {
+		fnb.Logger.Fatal().Msg("cannot start unstaked node without bind address")
+	}
+
+	self := &flow.Identity{
+		NodeID:        fnb.NodeID,
+		Address:       fnb.BaseConfig.bindAddr,
+		Role:          flow.RoleAccess,
+		NetworkPubKey: fnb.networkKey.PublicKey(),
+	}
+
+	var err error
+	fnb.Me, err = local.New(self, nil)
+	fnb.MustNot(err).Msg("could not initialize local")
+}
+
+// upstreamIdentities returns the identities of the upstream access nodes of an unstaked node, with the
+// addresses of their public networks.
+func (fnb *FlowNodeBuilder) upstreamIdentities() (flow.IdentityList, error) {
+	if len(fnb.BaseConfig.upstreamPeers) == 0 {
+		return nil, fmt.Errorf("no upstream access nodes configured")
+	}
+
+	upstreams := make(flow.IdentityList, 0, len(fnb.BaseConfig.upstreamPeers))
+	for _, peer := range fnb.BaseConfig.upstreamPeers {
+		parts := strings.SplitN(peer, "@", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid upstream access node %q, expected <node ID>@<address>", peer)
+		}
+		nodeID, err := flow.HexStringToIdentifier(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid node ID of upstream access node %q: %w", peer, err)
+		}
+
+		identity, err := fnb.State.Final().Identity(nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get identity of upstream access node %x: %w", nodeID, err)
+		}
+		if identity.Role != flow.RoleAccess {
+			return nil, fmt.Errorf("upstream node %x is not an access node, but %s", nodeID, identity.Role)
+		}
+
+		upstream := *identity
+		upstream.Address = parts[1]
+		upstreams = append(upstreams, &upstream)
+	}
+
+	return upstreams, nil
+}
+
+// InitPublicNetwork creates the public network of a staked access node, which unstaked observers connect
+// to on the given address. It uses the networking key of the access node, so that the observers can verify
+// they are connected to it. As public networks only support unicast messaging, engines serving observers
+// should only respond to their requests.
+func (fnb *FlowNodeBuilder) InitPublicNetwork(address string) (*p2p.Network, error) {
+	libP2PNodeFactory, err := p2p.PublicLibP2PNodeFactory(fnb.Logger.Level(zerolog.ErrorLevel),
+		fnb.Me.NodeID(),
+		address,
+		fnb.networkKey,
+		fnb.RootBlock.ID().String(),
+		p2p.DefaultMaxPubSubMsgSize,
+		fnb.Metrics.Network,
+		fnb.pingInfoProvider(),
+		fnb.Resolver)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate libp2p node factory: %w", err)
+	}
+
+	mw := p2p.NewPublicMiddleware(fnb.Logger.Level(zerolog.ErrorLevel),
+		libP2PNodeFactory,
+		fnb.Me.NodeID(),
+		fnb.Metrics.Network,
+		fnb.RootBlock.ID().String(),
+		fnb.BaseConfig.streamPool,
+		fnb.MsgValidators...)
+
+	// the observers connect to the public network, hence there are no participants to connect to
+	net, err := p2p.NewNetwork(fnb.Logger,
+		jsoncodec.NewCodec(),
+		flow.IdentityList{},
+		fnb.Me,
+		mw,
+		10e6,
+		topology.NewFullyConnectedTopology(),
+		p2p.NewChannelSubscriptionManager(mw),
+		fnb.Metrics.Network)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize public network: %w", err)
+	}
+
+	return net, nil
+}
+
+// loadNetworkingKey loads the hex encoded networking key from the file at the given path. If there is no
+// such file, a new key is generated and written to it. Without path, a new key is generated.
+func loadNetworkingKey(path string) (crypto.PrivateKey, error) {
+	if path != "" && io.FileExists(path) {
+		data, err := io.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read networking key: %w", err)
+		}
+		encoded, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode networking key: %w", err)
+		}
+		return crypto.DecodePrivateKey(crypto.ECDSAP256, encoded)
+	}
+
+	seed := make([]byte, crypto.KeyGenSeedMinLenECDSAP256)
+	_, err := rand.Read(seed)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate seed: %w", err)
+	}
+	key, err := crypto.GeneratePrivateKey(crypto.ECDSAP256, seed)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate networking key: %w", err)
+	}
+
+	if path != "" {
+		err = io.WriteFile(path, []byte(hex.EncodeToString(key.Encode())))
+		if err != nil {
+			return nil, fmt.Errorf("could not write networking key: %w", err)
+		}
+	}
+
+	return key, nil
+}
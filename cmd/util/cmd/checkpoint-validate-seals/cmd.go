@@ -0,0 +1,101 @@
+package checkpoint_validate_seals
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+)
+
+var (
+	flagCheckpoint string
+	flagDatadir    string
+	flagFromHeight uint64
+	flagToHeight   uint64
+)
+
+var Cmd = &cobra.Command{
+	Use:   "checkpoint-validate-seals",
+	Short: "Validates that the sealed state commitments of a protocol state database are tries of a checkpoint",
+	Run:   run,
+}
+
+func init() {
+
+	Cmd.Flags().StringVar(&flagCheckpoint, "checkpoint", "",
+		"checkpoint file to validate")
+	_ = Cmd.MarkFlagRequired("checkpoint")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().Uint64Var(&flagFromHeight, "from-height", 0,
+		"height of the first finalized block whose seals are validated (default: root block)")
+
+	Cmd.Flags().Uint64Var(&flagToHeight, "to-height", 0,
+		"height of the last finalized block whose seals are validated (default: latest finalized block)")
+}
+
+func run(*cobra.Command, []string) {
+
+	flattenedForest, err := wal.LoadCheckpoint(flagCheckpoint)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error while loading checkpoint")
+	}
+
+	commitments, err := CheckpointCommitments(flattenedForest)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid checkpoint")
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+	state, err := common.InitProtocolState(db, storages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init protocol state")
+	}
+
+	from := flagFromHeight
+	if from == 0 {
+		root, err := state.Params().Root()
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not get root block")
+		}
+		from = root.Height
+	}
+
+	to := flagToHeight
+	if to == 0 {
+		final, err := state.Final().Head()
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not get finalized block")
+		}
+		to = final.Height
+	}
+
+	log.Info().
+		Int("tries", len(commitments)).
+		Uint64("from_height", from).
+		Uint64("to_height", to).
+		Msg("validating seals against checkpoint")
+
+	mismatch, matched, err := ValidateSeals(commitments, storages.Headers, storages.Blocks, from, to)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not validate seals")
+	}
+
+	if mismatch != nil {
+		log.Fatal().
+			Uint("matched_seals", matched).
+			Uint64("sealed_height", mismatch.Height).
+			Hex("sealed_block_id", mismatch.BlockID[:]).
+			Hex("sealed_commitment", mismatch.Commitment[:]).
+			Msg("sealed state commitment not found in checkpoint")
+	}
+
+	log.Info().Uint("matched_seals", matched).Msg("all seals match the checkpoint")
+}
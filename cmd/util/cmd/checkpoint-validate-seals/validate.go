@@ -0,0 +1,69 @@
+package checkpoint_validate_seals
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Mismatch describes a sealed block whose sealed state commitment isn't the root hash of any trie
+// of the checkpoint.
+type Mismatch struct {
+	Height     uint64               // height of the sealed block
+	BlockID    flow.Identifier      // ID of the sealed block
+	Commitment flow.StateCommitment // final state of the seal
+}
+
+// CheckpointCommitments returns the root hashes of the tries of the checkpoint as state commitments.
+func CheckpointCommitments(forest *flattener.FlattenedForest) (map[flow.StateCommitment]struct{}, error) {
+	commitments := make(map[flow.StateCommitment]struct{}, len(forest.Tries))
+	for _, trie := range forest.Tries {
+		commitment, err := flow.ToStateCommitment(trie.RootHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root hash %x: %w", trie.RootHash, err)
+		}
+		commitments[commitment] = struct{}{}
+	}
+	return commitments, nil
+}
+
+// ValidateSeals checks that the final state of every seal included in the finalized blocks from
+// height `from` to height `to` (inclusive) is among the given checkpoint commitments. Seals are
+// checked in the order they were included, hence by increasing height of the sealed block. It
+// returns the first mismatch, or nil if all seals match, along with the number of matching seals.
+func ValidateSeals(
+	commitments map[flow.StateCommitment]struct{},
+	headers storage.Headers,
+	blocks storage.Blocks,
+	from uint64,
+	to uint64,
+) (*Mismatch, uint, error) {
+
+	matched := uint(0)
+	for height := from; height <= to; height++ {
+		block, err := blocks.ByHeight(height)
+		if err != nil {
+			return nil, matched, fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+		for _, seal := range block.Payload.Seals {
+			_, ok := commitments[seal.FinalState]
+			if ok {
+				matched++
+				continue
+			}
+			sealed, err := headers.ByBlockID(seal.BlockID)
+			if err != nil {
+				return nil, matched, fmt.Errorf("could not get sealed block %x: %w", seal.BlockID, err)
+			}
+			return &Mismatch{
+				Height:     sealed.Height,
+				BlockID:    seal.BlockID,
+				Commitment: seal.FinalState,
+			}, matched, nil
+		}
+	}
+
+	return nil, matched, nil
+}
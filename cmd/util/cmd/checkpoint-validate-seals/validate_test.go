@@ -0,0 +1,68 @@
+package checkpoint_validate_seals
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestValidateSeals(t *testing.T) {
+
+	// block 1 seals blocks 0 and 1, block 2 seals block 2
+	sealed := make([]*flow.Header, 0, 3)
+	for height := uint64(0); height < 3; height++ {
+		header := unittest.BlockHeaderFixture()
+		header.Height = height
+		sealed = append(sealed, &header)
+	}
+	seals := make([]*flow.Seal, 0, len(sealed))
+	for _, header := range sealed {
+		seals = append(seals, unittest.Seal.Fixture(unittest.Seal.WithBlock(header)))
+	}
+	block0 := unittest.BlockFixture()
+	block1 := unittest.BlockFixture()
+	block1.Payload.Seals = seals[:2]
+	block2 := unittest.BlockFixture()
+	block2.Payload.Seals = seals[2:]
+
+	headers := &storage.Headers{}
+	blocks := &storage.Blocks{}
+	for _, header := range sealed {
+		headers.On("ByBlockID", header.ID()).Return(header, nil)
+	}
+	blocks.On("ByHeight", uint64(0)).Return(&block0, nil)
+	blocks.On("ByHeight", uint64(1)).Return(&block1, nil)
+	blocks.On("ByHeight", uint64(2)).Return(&block2, nil)
+
+	t.Run("all seals match", func(t *testing.T) {
+		commitments := make(map[flow.StateCommitment]struct{})
+		for _, seal := range seals {
+			commitments[seal.FinalState] = struct{}{}
+		}
+		commitments[unittest.StateCommitmentFixture()] = struct{}{}
+
+		mismatch, matched, err := ValidateSeals(commitments, headers, blocks, 0, 2)
+		require.NoError(t, err)
+		assert.Nil(t, mismatch)
+		assert.Equal(t, uint(3), matched)
+	})
+
+	t.Run("reports first mismatch", func(t *testing.T) {
+		commitments := map[flow.StateCommitment]struct{}{
+			seals[0].FinalState: {},
+		}
+
+		mismatch, matched, err := ValidateSeals(commitments, headers, blocks, 0, 2)
+		require.NoError(t, err)
+		require.NotNil(t, mismatch)
+		assert.Equal(t, uint(1), matched)
+		assert.Equal(t, uint64(1), mismatch.Height)
+		assert.Equal(t, sealed[1].ID(), mismatch.BlockID)
+		assert.Equal(t, seals[1].FinalState, mismatch.Commitment)
+	})
+}
@@ -0,0 +1,74 @@
+package diff_config
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/config"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	flagRole  string
+	flagLeft  string
+	flagRight string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "diff-config",
+	Short: "Lists the tunables of a node role which differ between two configuration files",
+	Run:   run,
+}
+
+func init() {
+
+	Cmd.Flags().StringVar(&flagRole, "role", "",
+		"role of the nodes the configuration files are for")
+	_ = Cmd.MarkFlagRequired("role")
+
+	Cmd.Flags().StringVar(&flagLeft, "left", "",
+		"first configuration file")
+	_ = Cmd.MarkFlagRequired("left")
+
+	Cmd.Flags().StringVar(&flagRight, "right", "",
+		"second configuration file")
+	_ = Cmd.MarkFlagRequired("right")
+}
+
+func run(*cobra.Command, []string) {
+
+	role, err := flow.ParseRole(flagRole)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid role")
+	}
+
+	left := load(role, flagLeft)
+	right := load(role, flagRight)
+
+	diffs, err := config.Diff(left, right)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not compare configurations")
+	}
+
+	for _, diff := range diffs {
+		fmt.Println(diff)
+	}
+}
+
+// load reads the configuration file on top of the defaults of the role, so that tunables
+// missing in one of the files are compared with their defaults.
+func load(role flow.Role, path string) config.Section {
+	section, err := config.ForRole(role)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create configuration")
+	}
+
+	err = config.Load(path, section)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not load configuration")
+	}
+
+	return section
+}
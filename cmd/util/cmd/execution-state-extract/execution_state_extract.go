@@ -64,6 +64,8 @@ func extractExecutionState(dir string,
 		reporters = []ledger.Reporter{
 			mgr.ContractReporter{Log: log, OutputDir: outputDir},
 			mgr.StorageReporter{Log: log, OutputDir: outputDir},
+			mgr.ValueReporter{Log: log, OutputDir: outputDir},
+			mgr.StorageFormatReporter{Log: log, OutputDir: outputDir},
 		}
 	}
 	newState, err := led.ExportCheckpointAt(
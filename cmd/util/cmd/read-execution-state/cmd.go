@@ -7,6 +7,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
+	diff_registers "github.com/onflow/flow-go/cmd/util/cmd/read-execution-state/diff-registers"
 	list_accounts "github.com/onflow/flow-go/cmd/util/cmd/read-execution-state/list-accounts"
 	list_tries "github.com/onflow/flow-go/cmd/util/cmd/read-execution-state/list-tries"
 	list_wals "github.com/onflow/flow-go/cmd/util/cmd/read-execution-state/list-wals"
@@ -41,6 +42,7 @@ func addSubcommands() {
 	Cmd.AddCommand(list_tries.Init(loadExecutionState))
 	Cmd.AddCommand(list_accounts.Init(loadExecutionState))
 	Cmd.AddCommand(list_wals.Init())
+	Cmd.AddCommand(diff_registers.Init(loadExecutionState))
 }
 
 func loadExecutionState() *mtrie.Forest {
@@ -0,0 +1,103 @@
+package diff_registers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	executionState "github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var cmd = &cobra.Command{
+	Use:   "diff-registers",
+	Short: "Lists the registers whose values differ between two state commitments, e.g. before and after a block",
+	Run:   run,
+}
+
+var stateLoader func() *mtrie.Forest = nil
+var flagStateCommitmentBefore string
+var flagStateCommitmentAfter string
+var flagAddresses []string
+
+func Init(f func() *mtrie.Forest) *cobra.Command {
+	stateLoader = f
+
+	cmd.Flags().StringVar(&flagStateCommitmentBefore, "state-commitment-before", "",
+		"State commitment to diff from (64 chars, hex-encoded)")
+	_ = cmd.MarkFlagRequired("state-commitment-before")
+
+	cmd.Flags().StringVar(&flagStateCommitmentAfter, "state-commitment-after", "",
+		"State commitment to diff to (64 chars, hex-encoded)")
+	_ = cmd.MarkFlagRequired("state-commitment-after")
+
+	cmd.Flags().StringSliceVar(&flagAddresses, "addresses", nil,
+		"Comma separated list of hex-encoded addresses to only list the registers of")
+
+	return cmd
+}
+
+// registerDiff is the JSON encoding of a differing register
+type registerDiff struct {
+	Owner      string `json:"owner"`
+	Controller string `json:"controller"`
+	Key        string `json:"key"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+}
+
+func parseStateCommitment(raw string) flow.StateCommitment {
+	stateCommitmentBytes, err := hex.DecodeString(raw)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid flag, cannot decode")
+	}
+
+	stateCommitment, err := flow.ToStateCommitment(stateCommitmentBytes)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("invalid number of bytes, got %d expected %d", len(stateCommitmentBytes), len(stateCommitment))
+	}
+	return stateCommitment
+}
+
+func run(*cobra.Command, []string) {
+	startTime := time.Now()
+
+	before := parseStateCommitment(flagStateCommitmentBefore)
+	after := parseStateCommitment(flagStateCommitmentAfter)
+
+	addresses := make([]flow.Address, 0, len(flagAddresses))
+	for _, address := range flagAddresses {
+		addresses = append(addresses, flow.HexToAddress(address))
+	}
+
+	forest := stateLoader()
+
+	diffs, err := executionState.Diff(forest, before, after, addresses...)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot diff state commitments")
+	}
+
+	for _, diff := range diffs {
+		b, err := json.Marshal(registerDiff{
+			Owner:      hex.EncodeToString([]byte(diff.ID.Owner)),
+			Controller: hex.EncodeToString([]byte(diff.ID.Controller)),
+			Key:        hex.EncodeToString([]byte(diff.ID.Key)),
+			Before:     hex.EncodeToString(diff.Before),
+			After:      hex.EncodeToString(diff.After),
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("error while marshalling register diff")
+		}
+
+		fmt.Println(string(b))
+	}
+
+	duration := time.Since(startTime)
+
+	log.Info().Int("registers", len(diffs)).Float64("total_time_s", duration.Seconds()).Msg("finished")
+}
@@ -2,6 +2,7 @@ package list_accounts
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -89,12 +90,13 @@ func run(*cobra.Command, []string) {
 			},
 		}
 
-		payload, err := forest.Read(read)
+		payload, err := forest.Read(context.Background(), read)
 		if err != nil {
 			return nil, err
 		}
 
-		return payload[0].Value, nil
+		// the payloads read from the forest are shared with the trie
+		return payload[0].Value.DeepCopy(), nil
 	})
 
 	sth := state.NewStateHolder(state.NewState(ldg))
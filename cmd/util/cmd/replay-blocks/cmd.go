@@ -0,0 +1,159 @@
+package replay_blocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/replay"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+)
+
+var (
+	flagExecutionStateDir string
+	flagDatadir           string
+	flagStartHeight       uint64
+	flagEndHeight         uint64
+	flagFeatureFlags      []string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "replay-blocks",
+	Short: "Re-executes finalized blocks from storage and reports the first divergence from their stored execution results",
+	Run:   run,
+}
+
+func init() {
+
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where WAL logs are written")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().Uint64Var(&flagStartHeight, "start-height", 0,
+		"height of the first finalized block to replay")
+	_ = Cmd.MarkFlagRequired("start-height")
+
+	Cmd.Flags().Uint64Var(&flagEndHeight, "end-height", 0,
+		"height of the last finalized block to replay")
+	_ = Cmd.MarkFlagRequired("end-height")
+
+	Cmd.Flags().StringSliceVar(&flagFeatureFlags, "fvm-feature-flags", nil,
+		fmt.Sprintf("feature flags the replayed blocks were executed with (known flags: %v)", fvm.FeatureFlags()))
+}
+
+func run(*cobra.Command, []string) {
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+	state, err := common.InitProtocolState(db, storages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init protocol state")
+	}
+
+	chainID, err := state.Params().ChainID()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get chain ID")
+	}
+
+	diskWal, err := wal.NewDiskWAL(
+		zerolog.Nop(),
+		nil,
+		metrics.NewNoopCollector(),
+		flagExecutionStateDir,
+		complete.DefaultCacheSize,
+		pathfinder.PathByteSize,
+		wal.SegmentSize,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create disk WAL")
+	}
+	defer func() {
+		<-diskWal.Done()
+	}()
+
+	led, err := complete.NewLedger(diskWal, complete.DefaultCacheSize, &metrics.NoopCollector{}, log.Logger, complete.DefaultPathFinderVersion)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create ledger from write-a-head logs and checkpoints")
+	}
+	// the states of the replayed blocks must not be recorded to the write-ahead log of the node
+	diskWal.PauseRecord()
+
+	// same options as the virtual machine of execution nodes
+	vmOpts := []fvm.Option{
+		fvm.WithChain(chainID.Chain()),
+		fvm.WithBlocks(fvm.NewBlockFinder(storages.Headers)),
+		fvm.WithAccountStorageLimit(true),
+	}
+	if chainID == flow.Testnet {
+		vmOpts = append(vmOpts,
+			fvm.WithRestrictedAccountCreation(false),
+			fvm.WithRestrictedDeployment(false),
+			fvm.WithTransactionFeesEnabled(true),
+		)
+	}
+	featureFlagOptions, err := fvm.FeatureFlagOptions(flagFeatureFlags)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid feature flags")
+	}
+	vmOpts = append(vmOpts, featureFlagOptions...)
+
+	vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+	vmCtx := fvm.NewContext(log.Logger, vmOpts...)
+
+	blockComputer, err := computer.NewBlockComputer(
+		vm,
+		vmCtx,
+		metrics.NewNoopCollector(),
+		trace.NewNoopTracer(),
+		log.Logger,
+		committer.NewLedgerViewCommitter(led, trace.NewNoopTracer()),
+		state,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create block computer")
+	}
+
+	replayer := replay.NewReplayer(
+		log.Logger,
+		blockComputer,
+		led,
+		storages.Blocks,
+		storages.Collections,
+		storages.Commits,
+		storages.Results,
+	)
+
+	divergence, err := replayer.ReplayBlocks(context.Background(), flagStartHeight, flagEndHeight)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not replay blocks")
+	}
+	if divergence != nil {
+		log.Fatal().
+			Hex("block_id", divergence.BlockID[:]).
+			Uint64("height", divergence.Height).
+			Int("chunk_index", divergence.ChunkIndex).
+			Str("stored", divergence.Stored).
+			Str("replayed", divergence.Replayed).
+			Msgf("replayed block diverges: %s", divergence.Reason)
+	}
+
+	log.Info().Uint64("start_height", flagStartHeight).Uint64("end_height", flagEndHeight).Msg("replayed blocks match their stored results")
+}
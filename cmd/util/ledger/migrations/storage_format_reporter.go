@@ -0,0 +1,90 @@
+package migrations
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// reports on the accounts whose storage is still on an old storage format version
+type StorageFormatReporter struct {
+	Log       zerolog.Logger
+	OutputDir string
+}
+
+func (r StorageFormatReporter) filename() string {
+	return path.Join(r.OutputDir, fmt.Sprintf("storage_format_report_%d.csv", int32(time.Now().Unix())))
+}
+
+func (r StorageFormatReporter) Report(payload []ledger.Payload) error {
+	fn := r.filename()
+	r.Log.Info().Msgf("Running Storage Format Reporter. Saving output to %s.", fn)
+
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = f.Close()
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	writer := bufio.NewWriter(f)
+	defer func() {
+		err = writer.Flush()
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	l := newView(payload)
+	accounts := state.NewAccounts(state.NewStateHolder(state.NewState(l)))
+
+	versions := make(map[uint64]int)
+	for _, p := range payload {
+		id, err := keyToRegisterID(p.Key)
+		if err != nil {
+			return err
+		}
+		if len([]byte(id.Owner)) != flow.AddressLength {
+			// not an address
+			continue
+		}
+		if id.Key != state.KeyExists {
+			continue
+		}
+		address := flow.BytesToAddress([]byte(id.Owner))
+		version, err := accounts.GetStorageFormatVersion(address)
+		if err != nil {
+			r.Log.Err(err).Msg("Cannot get storage format version")
+			return err
+		}
+		versions[version]++
+
+		if version >= state.CurrentStorageFormatVersion {
+			continue
+		}
+		_, err = writer.WriteString(fmt.Sprintf("%s,%d\n", address.Hex(), version))
+		if err != nil {
+			return err
+		}
+	}
+
+	for version, count := range versions {
+		r.Log.Info().Uint64("version", version).Int("accounts", count).Msg("Accounts on storage format version")
+	}
+	r.Log.Info().Msg("Storage Format Reporter Done.")
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package migrations
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// InspectedValue is a register decoded as a stored Cadence value
+type InspectedValue struct {
+	RegisterID flow.RegisterID
+	Version    uint16            // encoding version of the value
+	Type       string            // type of the value, empty if it could not be decoded
+	Value      interpreter.Value // nil if the value could not be decoded
+	Err        error             // error decoding the value, if any
+}
+
+// TypeStats are the statistics of the stored values of one type
+type TypeStats struct {
+	Count int
+	Size  uint64 // total size of the encoded values in bytes
+}
+
+// StorageSummary summarizes the Cadence values stored in registers
+type StorageSummary struct {
+	Registers int                   // number of inspected registers
+	NonValues int                   // registers which don't hold Cadence values, e.g. account keys and code
+	Corrupt   int                   // registers whose values could not be decoded
+	Types     map[string]*TypeStats // statistics of the decoded values by type
+}
+
+// IsStoredValueKey returns whether the register with the given key holds a Cadence value,
+// i.e. a value stored in one of the path domains of an account, or a contract value.
+func IsStoredValueKey(key string) bool {
+	if key == "contract" {
+		return true
+	}
+	for _, domain := range []common.PathDomain{
+		common.PathDomainStorage,
+		common.PathDomainPrivate,
+		common.PathDomainPublic,
+	} {
+		if strings.HasPrefix(key, domain.Identifier()+"\x1F") {
+			return true
+		}
+	}
+	return strings.HasPrefix(key, "contract\x1F")
+}
+
+// InspectValue decodes the Cadence value stored in the payload using the decoder of the interpreter.
+// Values which can't be decoded, because their encoding is unknown or corrupt, are returned with
+// the decoding error instead of failing. An error is only returned if the payload doesn't hold a
+// Cadence value.
+func InspectValue(payload ledger.Payload) (*InspectedValue, error) {
+	id, err := keyToRegisterID(payload.Key)
+	if err != nil {
+		return nil, err
+	}
+	if !IsStoredValueKey(id.Key) {
+		return nil, fmt.Errorf("register %s does not hold a Cadence value", id)
+	}
+
+	storedData, version := interpreter.StripMagic(payload.Value)
+	inspected := &InspectedValue{
+		RegisterID: id,
+		Version:    version,
+	}
+
+	if version > interpreter.CurrentEncodingVersion {
+		inspected.Err = fmt.Errorf("unknown encoding version %d", version)
+		return inspected, nil
+	}
+
+	value, err := decodeValue(storedData, id, version)
+	if err != nil {
+		inspected.Err = err
+		return inspected, nil
+	}
+
+	inspected.Value = value
+	inspected.Type = valueTypeName(value)
+	return inspected, nil
+}
+
+// decodeValue decodes the stored data, recovering from panics of the decoder on corrupt data
+func decodeValue(data []byte, id flow.RegisterID, version uint16) (value interpreter.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("decoding value panicked: %v", r)
+		}
+	}()
+
+	decodeFunction := interpreter.DecodeValue
+	if version <= 3 {
+		decodeFunction = interpreter.DecodeValueV3
+	}
+
+	owner := common.BytesToAddress([]byte(id.Owner))
+	value, err = decodeFunction(data, &owner, []string{id.Key}, version, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+	return value, nil
+}
+
+// valueTypeName returns the type ID of composite values, and the name of the value kind otherwise
+func valueTypeName(value interpreter.Value) string {
+	if composite, ok := value.(*interpreter.CompositeValue); ok {
+		return string(composite.TypeID())
+	}
+	name := fmt.Sprintf("%T", value)
+	name = strings.TrimPrefix(name, "*")
+	return strings.TrimPrefix(name, "interpreter.")
+}
+
+// SummarizeValues decodes the Cadence values stored in the payloads and collects statistics per type.
+func SummarizeValues(payloads []ledger.Payload) *StorageSummary {
+	summary := &StorageSummary{
+		Types: make(map[string]*TypeStats),
+	}
+
+	for _, p := range payloads {
+		summary.Registers++
+
+		inspected, err := InspectValue(p)
+		if err != nil {
+			summary.NonValues++
+			continue
+		}
+		if inspected.Err != nil {
+			summary.Corrupt++
+			continue
+		}
+
+		stats, ok := summary.Types[inspected.Type]
+		if !ok {
+			stats = &TypeStats{}
+			summary.Types[inspected.Type] = stats
+		}
+		stats.Count++
+		stats.Size += uint64(len(p.Value))
+	}
+
+	return summary
+}
+
+// reports on the types of the stored Cadence values
+type ValueReporter struct {
+	Log       zerolog.Logger
+	OutputDir string
+}
+
+func (r ValueReporter) filename() string {
+	return path.Join(r.OutputDir, fmt.Sprintf("value_report_%d.csv", int32(time.Now().Unix())))
+}
+
+func (r ValueReporter) Report(payload []ledger.Payload) error {
+	fn := r.filename()
+	r.Log.Info().Msgf("Running Value Reporter. Saving output to %s.", fn)
+
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = f.Close()
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	writer := bufio.NewWriter(f)
+	defer func() {
+		err = writer.Flush()
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	summary := SummarizeValues(payload)
+
+	types := make([]string, 0, len(summary.Types))
+	for typ := range summary.Types {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+
+	for _, typ := range types {
+		stats := summary.Types[typ]
+		_, err = writer.WriteString(fmt.Sprintf("%s,%d,%d\n", typ, stats.Count, stats.Size))
+		if err != nil {
+			return err
+		}
+	}
+
+	r.Log.Info().
+		Int("registers", summary.Registers).
+		Int("non_values", summary.NonValues).
+		Int("corrupt", summary.Corrupt).
+		Msg("Value Reporter Done.")
+
+	return nil
+}
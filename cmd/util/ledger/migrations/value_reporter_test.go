@@ -0,0 +1,68 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/cmd/util/ledger/migrations"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+)
+
+func TestSummarizeValues(t *testing.T) {
+
+	payload := func(key string, value []byte) ledger.Payload {
+		return ledger.Payload{
+			Key: ledger.Key{
+				KeyParts: []ledger.KeyPart{
+					ledger.NewKeyPart(state.KeyPartOwner, []byte{0x42}),
+					ledger.NewKeyPart(state.KeyPartController, []byte{}),
+					ledger.NewKeyPart(state.KeyPartKey, []byte(key)),
+				},
+			},
+			Value: value,
+		}
+	}
+
+	encoded, _, err := interpreter.EncodeValue(interpreter.NewStringValue("test"), nil, true, nil)
+	require.NoError(t, err)
+	stored := interpreter.PrependMagic(encoded, interpreter.CurrentEncodingVersion)
+
+	payloads := []ledger.Payload{
+		payload("storage\x1Fa", stored),
+		payload("public\x1Fb", stored),
+		// corrupt value
+		payload("storage\x1Fc", interpreter.PrependMagic([]byte{0xff, 0x00}, interpreter.CurrentEncodingVersion)),
+		// unknown encoding version
+		payload("storage\x1Fd", interpreter.PrependMagic(encoded, interpreter.CurrentEncodingVersion+1)),
+		// not a Cadence value
+		payload("exists", []byte{0x1}),
+	}
+
+	inspected, err := migrations.InspectValue(payloads[0])
+	require.NoError(t, err)
+	require.NoError(t, inspected.Err)
+	require.Equal(t, "StringValue", inspected.Type)
+	require.Equal(t, interpreter.CurrentEncodingVersion, inspected.Version)
+
+	inspected, err = migrations.InspectValue(payloads[2])
+	require.NoError(t, err)
+	require.Error(t, inspected.Err)
+	require.Nil(t, inspected.Value)
+
+	_, err = migrations.InspectValue(payloads[4])
+	require.Error(t, err)
+
+	summary := migrations.SummarizeValues(payloads)
+	require.Equal(t, 5, summary.Registers)
+	require.Equal(t, 1, summary.NonValues)
+	require.Equal(t, 2, summary.Corrupt)
+	require.Equal(t,
+		map[string]*migrations.TypeStats{
+			"StringValue": {Count: 2, Size: 2 * uint64(len(stored))},
+		},
+		summary.Types,
+	)
+}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"fmt"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/pflag"
 
 	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/config"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
 	"github.com/onflow/flow-go/consensus/hotstuff/verification"
@@ -33,31 +35,12 @@ import (
 	storage "github.com/onflow/flow-go/storage/badger"
 )
 
-const (
-	// requestInterval represents the time interval in milliseconds that the
-	// match engine retries sending resource requests to the network
-	// this value is set following this issue (3443)
-	requestInterval = 5000 * time.Millisecond
-
-	// processInterval represents the time interval in milliseconds that the
-	// finder engine iterates over the execution receipts ready to process
-	// this value is set following this issue (3443)
-	processInterval = 1000 * time.Millisecond
-
-	// failureThreshold represents the number of retries match engine sends
-	// at `requestInterval` milliseconds for each of the missing resources.
-	// When it reaches the threshold ingest engine makes a missing challenge for the resources.
-	// This value is currently set to account for a single 24-hour failure of an Execution node.
-	failureThreshold = 17500
-)
-
 func main() {
 	var (
 		followerState       protocol.MutableState
 		err                 error
-		receiptLimit        uint                       // size of execution-receipt/result related mempools
-		chunkAlpha          uint                       // number of verifiers assigned per chunk
-		chunkLimit          uint                       // size of chunk-related mempools
+		conf                = config.DefaultVerification()
+		executionContexts   []string                   // execution configurations chunks can be verified with
 		cachedReceipts      *stdmap.ReceiptDataPacks   // used in finder engine
 		pendingReceipts     *stdmap.ReceiptDataPacks   // used in finder engine
 		readyReceipts       *stdmap.ReceiptDataPacks   // used in finder engine
@@ -80,10 +63,9 @@ func main() {
 	)
 
 	cmd.FlowNode(flow.RoleVerification.String()).
+		Config(&conf).
 		ExtraFlags(func(flags *pflag.FlagSet) {
-			flags.UintVar(&receiptLimit, "receipt-limit", 1000, "maximum number of execution receipts in the memory pool")
-			flags.UintVar(&chunkLimit, "chunk-limit", 10000, "maximum number of chunk states in the memory pool")
-			flags.UintVar(&chunkAlpha, "chunk-alpha", chunks.DefaultChunkAssignmentAlpha, "number of verifiers that should be assigned to each chunk")
+			flags.StringSliceVar(&executionContexts, "execution-contexts", nil, "execution configurations reported in receipts which chunks can be verified with, as <version>:<flag>+<flag> (e.g. 1:account-freeze+transaction-fees)")
 		}).
 		Module("mutable follower state", func(node *cmd.FlowNodeBuilder) error {
 			// For now, we only support state implementations from package badger.
@@ -106,7 +88,7 @@ func main() {
 			return nil
 		}).
 		Module("cached execution receipts mempool", func(node *cmd.FlowNodeBuilder) error {
-			cachedReceipts, err = stdmap.NewReceiptDataPacks(receiptLimit)
+			cachedReceipts, err = stdmap.NewReceiptDataPacks(conf.ReceiptLimit)
 			if err != nil {
 				return err
 			}
@@ -119,7 +101,7 @@ func main() {
 			return nil
 		}).
 		Module("pending execution receipts mempool", func(node *cmd.FlowNodeBuilder) error {
-			pendingReceipts, err = stdmap.NewReceiptDataPacks(receiptLimit)
+			pendingReceipts, err = stdmap.NewReceiptDataPacks(conf.ReceiptLimit)
 			if err != nil {
 				return err
 			}
@@ -132,7 +114,7 @@ func main() {
 			return nil
 		}).
 		Module("ready execution receipts mempool", func(node *cmd.FlowNodeBuilder) error {
-			readyReceipts, err = stdmap.NewReceiptDataPacks(receiptLimit)
+			readyReceipts, err = stdmap.NewReceiptDataPacks(conf.ReceiptLimit)
 			if err != nil {
 				return err
 			}
@@ -145,7 +127,7 @@ func main() {
 			return nil
 		}).
 		Module("pending execution receipts ids by block mempool", func(node *cmd.FlowNodeBuilder) error {
-			receiptIDsByBlock, err = stdmap.NewIdentifierMap(receiptLimit)
+			receiptIDsByBlock, err = stdmap.NewIdentifierMap(conf.ReceiptLimit)
 			if err != nil {
 				return err
 			}
@@ -159,7 +141,7 @@ func main() {
 			return nil
 		}).
 		Module("execution receipt ids by result mempool", func(node *cmd.FlowNodeBuilder) error {
-			receiptIDsByResult, err = stdmap.NewIdentifierMap(receiptLimit)
+			receiptIDsByResult, err = stdmap.NewIdentifierMap(conf.ReceiptLimit)
 			if err != nil {
 				return err
 			}
@@ -173,7 +155,7 @@ func main() {
 			return nil
 		}).
 		Module("chunk ids by result mempool", func(node *cmd.FlowNodeBuilder) error {
-			chunkIDsByResult, err = stdmap.NewIdentifierMap(chunkLimit)
+			chunkIDsByResult, err = stdmap.NewIdentifierMap(conf.ChunkLimit)
 			if err != nil {
 				return err
 			}
@@ -187,7 +169,7 @@ func main() {
 			return nil
 		}).
 		Module("cached block ids mempool", func(node *cmd.FlowNodeBuilder) error {
-			blockIDsCache, err = stdmap.NewIdentifiers(receiptLimit)
+			blockIDsCache, err = stdmap.NewIdentifiers(conf.ReceiptLimit)
 			if err != nil {
 				return err
 			}
@@ -201,7 +183,7 @@ func main() {
 			return nil
 		}).
 		Module("pending results mempool", func(node *cmd.FlowNodeBuilder) error {
-			pendingResults = stdmap.NewResultDataPacks(receiptLimit)
+			pendingResults = stdmap.NewResultDataPacks(conf.ReceiptLimit)
 
 			// registers size method of backend for metrics
 			err = node.Metrics.Mempool.Register(metrics.ResourcePendingResult, pendingResults.Size)
@@ -211,7 +193,7 @@ func main() {
 			return nil
 		}).
 		Module("pending chunks mempool", func(node *cmd.FlowNodeBuilder) error {
-			pendingChunks = match.NewChunks(chunkLimit)
+			pendingChunks = match.NewChunks(conf.ChunkLimit)
 
 			err = node.Metrics.Mempool.Register(metrics.ResourcePendingChunk, pendingChunks.Size)
 			if err != nil {
@@ -220,7 +202,7 @@ func main() {
 			return nil
 		}).
 		Module("processed results ids mempool", func(node *cmd.FlowNodeBuilder) error {
-			processedResultsIDs, err = stdmap.NewIdentifiers(receiptLimit)
+			processedResultsIDs, err = stdmap.NewIdentifiers(conf.ReceiptLimit)
 			if err != nil {
 				return err
 			}
@@ -232,7 +214,7 @@ func main() {
 			return nil
 		}).
 		Module("discarded results ids mempool", func(node *cmd.FlowNodeBuilder) error {
-			discardedResultIDs, err = stdmap.NewIdentifiers(receiptLimit)
+			discardedResultIDs, err = stdmap.NewIdentifiers(conf.ReceiptLimit)
 			if err != nil {
 				return err
 			}
@@ -267,7 +249,11 @@ func main() {
 			rt := fvm.NewInterpreterRuntime()
 			vm := fvm.NewVirtualMachine(rt)
 			vmCtx := fvm.NewContext(node.Logger, node.FvmOptions...)
-			chunkVerifier := chunks.NewChunkVerifier(vm, vmCtx)
+			registry, err := contextRegistry(executionContexts)
+			if err != nil {
+				return nil, fmt.Errorf("invalid execution contexts: %w", err)
+			}
+			chunkVerifier := chunks.NewChunkVerifierWithRegistry(vm, vmCtx, registry)
 			approvalStorage := storage.NewResultApprovals(node.Metrics.Cache, node.DB)
 			verifierEng, err = verifier.New(
 				node.Logger,
@@ -281,7 +267,7 @@ func main() {
 			return verifierEng, err
 		}).
 		Component("match engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
-			assigner, err := chunks.NewChunkAssigner(chunkAlpha, node.State)
+			assigner, err := chunks.NewChunkAssigner(conf.ChunkAlpha, node.State)
 			if err != nil {
 				return nil, err
 			}
@@ -297,8 +283,8 @@ func main() {
 				node.State,
 				pendingChunks,
 				headerStorage,
-				requestInterval,
-				failureThreshold)
+				conf.RequestInterval,
+				conf.FailureThreshold)
 			return matchEng, err
 		}).
 		Component("finder engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
@@ -318,7 +304,7 @@ func main() {
 				receiptIDsByBlock,
 				receiptIDsByResult,
 				blockIDsCache,
-				processInterval)
+				conf.ProcessInterval)
 			return finderEng, err
 		}).
 		Component("follower engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
@@ -396,3 +382,27 @@ func main() {
 		}).
 		Run()
 }
+
+// contextRegistry creates a registry of the given execution configurations, each of which is
+// formatted as <version>:<flag>+<flag>.
+func contextRegistry(configurations []string) (*chunks.ContextRegistry, error) {
+	registry := chunks.NewContextRegistry()
+	for _, configuration := range configurations {
+		parts := strings.SplitN(configuration, ":", 2)
+		version, err := strconv.ParseUint(parts[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of execution context %q: %w", configuration, err)
+		}
+
+		var flags []string
+		if len(parts) == 2 && parts[1] != "" {
+			flags = strings.Split(parts[1], "+")
+		}
+
+		_, err = registry.RegisterFeatureFlags(uint32(version), flags)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
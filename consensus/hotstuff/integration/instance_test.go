@@ -174,7 +174,8 @@ func NewInstance(t require.TestingT, options ...Option) *Instance {
 
 	// check on stop condition, stop the tests as soon as entering a certain view
 	in.persist.On("PutStarted", mock.Anything).Return(nil)
-	in.persist.On("PutVoted", mock.Anything).Return(nil)
+	in.persist.On("GetVotedBlock").Return(uint64(0), flow.ZeroID, nil)
+	in.persist.On("PutVotedBlock", mock.Anything, mock.Anything).Return(nil)
 
 	// program the hotstuff signer behaviour
 	in.signer.On("CreateProposal", mock.Anything).Return(
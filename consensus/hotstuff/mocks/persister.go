@@ -2,7 +2,10 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	flow "github.com/onflow/flow-go/model/flow"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Persister is an autogenerated mock type for the Persister type
 type Persister struct {
@@ -51,6 +54,36 @@ func (_m *Persister) GetVoted() (uint64, error) {
 	return r0, r1
 }
 
+// GetVotedBlock provides a mock function with given fields:
+func (_m *Persister) GetVotedBlock() (uint64, flow.Identifier, error) {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 flow.Identifier
+	if rf, ok := ret.Get(1).(func() flow.Identifier); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(flow.Identifier)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // PutStarted provides a mock function with given fields: view
 func (_m *Persister) PutStarted(view uint64) error {
 	ret := _m.Called(view)
@@ -78,3 +111,17 @@ func (_m *Persister) PutVoted(view uint64) error {
 
 	return r0
 }
+
+// PutVotedBlock provides a mock function with given fields: view, blockID
+func (_m *Persister) PutVotedBlock(view uint64, blockID flow.Identifier) error {
+	ret := _m.Called(view, blockID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64, flow.Identifier) error); ok {
+		r0 = rf(view, blockID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
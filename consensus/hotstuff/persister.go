@@ -1,5 +1,9 @@
 package hotstuff
 
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
 // Persister is responsible for persisting state we need to bootstrap after a
 // restart or crash.
 type Persister interface {
@@ -10,9 +14,17 @@ type Persister interface {
 	// GetVoted will retrieve the last voted view.
 	GetVoted() (uint64, error)
 
+	// GetVotedBlock will retrieve the last voted view and the ID of the block voted
+	// for at that view, which is the ZeroID if the block is unknown.
+	GetVotedBlock() (uint64, flow.Identifier, error)
+
 	// PutStarted persists the last started view.
 	PutStarted(view uint64) error
 
 	// PutVoted persists the last voted view.
 	PutVoted(view uint64) error
+
+	// PutVotedBlock atomically persists the last voted view and the ID of the block
+	// voted for at that view.
+	PutVotedBlock(view uint64, blockID flow.Identifier) error
 }
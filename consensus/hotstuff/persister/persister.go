@@ -1,9 +1,13 @@
 package persister
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/dgraph-io/badger/v2"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 )
 
@@ -37,6 +41,29 @@ func (p *Persister) GetVoted() (uint64, error) {
 	return view, err
 }
 
+// GetVotedBlock returns the last persisted voted view, and the ID of the block we voted
+// for at that view. The block ID is the ZeroID if only the view was persisted.
+func (p *Persister) GetVotedBlock() (uint64, flow.Identifier, error) {
+	var view uint64
+	var blockID flow.Identifier
+	err := p.db.View(func(tx *badger.Txn) error {
+		err := operation.RetrieveVotedView(p.chainID, &view)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve voted view: %w", err)
+		}
+		err = operation.RetrieveVotedBlock(p.chainID, &blockID)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			blockID = flow.ZeroID
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve voted block: %w", err)
+		}
+		return nil
+	})
+	return view, blockID, err
+}
+
 // PutStarted persists the view when we start it in hotstuff.
 func (p *Persister) PutStarted(view uint64) error {
 	return operation.RetryOnConflict(p.db.Update, operation.UpdateStartedView(p.chainID, view))
@@ -46,3 +73,22 @@ func (p *Persister) PutStarted(view uint64) error {
 func (p *Persister) PutVoted(view uint64) error {
 	return operation.RetryOnConflict(p.db.Update, operation.UpdateVotedView(p.chainID, view))
 }
+
+// PutVotedBlock persists the view and the ID of the block when we voted in hotstuff,
+// in a single transaction.
+func (p *Persister) PutVotedBlock(view uint64, blockID flow.Identifier) error {
+	return operation.RetryOnConflict(p.db.Update, func(tx *badger.Txn) error {
+		err := operation.UpdateVotedView(p.chainID, view)(tx)
+		if err != nil {
+			return fmt.Errorf("could not update voted view: %w", err)
+		}
+		err = operation.UpdateVotedBlock(p.chainID, blockID)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			err = operation.InsertVotedBlock(p.chainID, blockID)(tx)
+		}
+		if err != nil {
+			return fmt.Errorf("could not persist voted block: %w", err)
+		}
+		return nil
+	})
+}
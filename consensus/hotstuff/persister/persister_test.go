@@ -0,0 +1,46 @@
+package persister
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestVotedBlock verifies that the voted view and block are persisted together, and that
+// the voted block is unknown for databases bootstrapped with only the voted view.
+func TestVotedBlock(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		chainID := flow.Emulator
+		err := db.Update(operation.InsertVotedView(chainID, 5))
+		require.NoError(t, err)
+
+		persist := New(db, chainID)
+		view, blockID, err := persist.GetVotedBlock()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), view)
+		assert.Equal(t, flow.ZeroID, blockID)
+
+		for _, view := range []uint64{6, 7} {
+			votedID := unittest.IdentifierFixture()
+			err = persist.PutVotedBlock(view, votedID)
+			require.NoError(t, err)
+
+			// a restarted persister reads the same vote from the database
+			restarted := New(db, chainID)
+			votedView, blockID, err := restarted.GetVotedBlock()
+			require.NoError(t, err)
+			assert.Equal(t, view, votedView)
+			assert.Equal(t, votedID, blockID)
+
+			votedView, err = restarted.GetVoted()
+			require.NoError(t, err)
+			assert.Equal(t, view, votedView)
+		}
+	})
+}
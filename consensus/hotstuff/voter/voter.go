@@ -6,6 +6,7 @@ import (
 
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
 )
 
 // Voter produces votes for the given block
@@ -51,7 +52,19 @@ func (v *Voter) ProduceVoteIfVotable(block *model.Block, curView uint64) (*model
 		return nil, model.NoVoteError{Msg: "not for current view"}
 	}
 
+	// the in-memory view might be behind the persisted one, for instance if the node
+	// restarted with stale state, so the persisted vote is consulted before every vote
+	votedView, votedBlockID, err := v.persist.GetVotedBlock()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve last voted: %w", err)
+	}
+	if votedView > v.lastVotedView {
+		v.lastVotedView = votedView
+	}
 	if curView <= v.lastVotedView {
+		if curView == votedView && votedBlockID != flow.ZeroID {
+			return nil, model.NoVoteError{Msg: fmt.Sprintf("not above the last voted view, already voted for block %x", votedBlockID)}
+		}
 		return nil, model.NoVoteError{Msg: "not above the last voted view"}
 	}
 
@@ -59,7 +72,7 @@ func (v *Voter) ProduceVoteIfVotable(block *model.Block, curView uint64) (*model
 	// member. HotStuff will ask for a vote for the first block of the next epoch, even if we are unstaked in
 	// the next epoch.
 	// These votes can't be used to produce valid QCs.
-	_, err := v.committee.Identity(block.BlockID, v.committee.Self())
+	_, err = v.committee.Identity(block.BlockID, v.committee.Self())
 	if errors.Is(model.ErrInvalidSigner, err) {
 		return nil, model.NoVoteError{Msg: "not voting committee member for block"}
 	}
@@ -73,9 +86,10 @@ func (v *Voter) ProduceVoteIfVotable(block *model.Block, curView uint64) (*model
 	}
 
 	// vote for the current view has been produced, update lastVotedView
-	// to prevent from voting for the same view again
+	// to prevent from voting for the same view again; the vote is only
+	// released once it is persisted, so that a restart can't double-vote
 	v.lastVotedView = curView
-	err = v.persist.PutVoted(curView)
+	err = v.persist.PutVotedBlock(curView, block.BlockID)
 	if err != nil {
 		return nil, fmt.Errorf("could not persist last voted: %w", err)
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff/helper"
 	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -21,6 +22,7 @@ func TestProduceVote(t *testing.T) {
 	t.Run("should not vote for block with its view below the last voted view", testBelowLastVotedView)
 	t.Run("should not vote for the same view again", testVotingAgain)
 	t.Run("should not vote while not a committee member", testVotingWhileNonCommitteeMember)
+	t.Run("should not vote for a view persisted as voted", testPersistedVotedView)
 }
 
 func createVoter(t *testing.T, blockView uint64, lastVotedView uint64, isBlockSafe, isCommitteeMember bool) (*model.Block, *model.Vote, *Voter) {
@@ -31,7 +33,8 @@ func createVoter(t *testing.T, blockView uint64, lastVotedView uint64, isBlockSa
 	forks.On("IsSafeBlock", block).Return(isBlockSafe)
 
 	persist := &mocks.Persister{}
-	persist.On("PutVoted", mock.Anything).Return(nil)
+	persist.On("GetVotedBlock").Return(lastVotedView, flow.ZeroID, nil)
+	persist.On("PutVotedBlock", mock.Anything, mock.Anything).Return(nil)
 
 	signer := &mocks.SignerVerifier{}
 	signer.On("CreateVote", mock.Anything).Return(expectVote, nil)
@@ -159,3 +162,21 @@ func makeVote(block *model.Block) *model.Vote {
 		SigData: nil, // signature doesn't matter in this test case
 	}
 }
+
+// testPersistedVotedView verifies that the voter refuses to vote for a view it has already
+// voted for according to the persisted state, even if its in-memory state was lost.
+func testPersistedVotedView(t *testing.T) {
+	blockView, curView, lastVotedView, isBlockSafe, isCommitteeMember := uint64(3), uint64(3), uint64(0), true, true
+
+	// create voter which lost track of its last vote in memory
+	block, _, voter := createVoter(t, blockView, lastVotedView, isBlockSafe, isCommitteeMember)
+	persist := &mocks.Persister{}
+	persist.On("GetVotedBlock").Return(curView, block.BlockID, nil)
+	voter.persist = persist
+
+	_, err := voter.ProduceVoteIfVotable(block, curView)
+	require.Error(t, err)
+	require.True(t, model.IsNoVoteError(err))
+	require.Contains(t, err.Error(), "not above the last voted view")
+	persist.AssertNotCalled(t, "PutVotedBlock", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,156 @@
+package consensus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// maxTimeoutIncreases is the number of the most recent timeout increases kept by the tracker.
+const maxTimeoutIncreases = 16
+
+// replicaTimeoutBuckets are the upper bounds of the buckets of the replica timeout histogram.
+var replicaTimeoutBuckets = []time.Duration{
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+}
+
+// PacemakerStatus is a snapshot of the state of the pacemaker of a HotStuff participant.
+type PacemakerStatus struct {
+	CurView          uint64               // the view the participant is in
+	TimeoutMode      model.TimeoutMode    // the mode of the active timeout
+	TimeoutView      uint64               // the view of the active timeout
+	TimeoutStarted   time.Time            // the time the active timeout started
+	TimeoutDuration  time.Duration        // the duration of the active timeout
+	Timeouts         uint64               // the number of views which timed out
+	TimeoutIncreases []TimeoutIncrease    // the most recent timeout increases, oldest first
+	TimeoutHistogram []TimeoutBucketCount // the distribution of the replica timeout durations
+}
+
+// TimeoutIncrease is an increase of the replica timeout after the participant timed out in a view.
+type TimeoutIncrease struct {
+	View     uint64        // the view the participant timed out in
+	Time     time.Time     // the time the increased timeout started
+	Previous time.Duration // the replica timeout of the view which timed out
+	Next     time.Duration // the increased replica timeout
+}
+
+// TimeoutBucketCount counts the replica timeouts with a duration up to the upper bound of the
+// bucket. As for prometheus histograms, the counts are cumulative, and the last bucket has an
+// infinite upper bound, represented by zero.
+type TimeoutBucketCount struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// PacemakerStatusTracker is a consumer of the HotStuff notifications which keeps track of the
+// pacemaker, so that its status can be queried without scraping logs.
+type PacemakerStatusTracker struct {
+	// inherit from noop consumer in order to satisfy the full interface
+	notifications.NoopConsumer
+
+	mu               sync.RWMutex
+	curView          uint64
+	timer            model.TimerInfo
+	timeouts         uint64
+	replicaTimeout   time.Duration
+	timeoutIncreases []TimeoutIncrease
+	histogram        []uint64
+}
+
+// NewPacemakerStatusTracker creates a new tracker of the pacemaker status.
+func NewPacemakerStatusTracker() *PacemakerStatusTracker {
+	return &PacemakerStatusTracker{
+		histogram: make([]uint64, len(replicaTimeoutBuckets)+1),
+	}
+}
+
+func (t *PacemakerStatusTracker) OnEnteringView(view uint64, leader flow.Identifier) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.curView = view
+}
+
+func (t *PacemakerStatusTracker) OnReachedTimeout(info *model.TimerInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timeouts++
+}
+
+func (t *PacemakerStatusTracker) OnStartingTimeout(info *model.TimerInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timer = *info
+
+	if info.Mode != model.ReplicaTimeout {
+		return
+	}
+
+	if t.replicaTimeout != 0 && info.Duration > t.replicaTimeout {
+		increase := TimeoutIncrease{
+			View:     info.View - 1,
+			Time:     info.StartTime,
+			Previous: t.replicaTimeout,
+			Next:     info.Duration,
+		}
+		if len(t.timeoutIncreases) == maxTimeoutIncreases {
+			t.timeoutIncreases = append(t.timeoutIncreases[:0], t.timeoutIncreases[1:]...)
+		}
+		t.timeoutIncreases = append(t.timeoutIncreases, increase)
+	}
+	t.replicaTimeout = info.Duration
+
+	bucket := len(replicaTimeoutBuckets)
+	for i, upperBound := range replicaTimeoutBuckets {
+		if info.Duration <= upperBound {
+			bucket = i
+			break
+		}
+	}
+	t.histogram[bucket]++
+}
+
+// PacemakerStatus returns a snapshot of the pacemaker status.
+func (t *PacemakerStatusTracker) PacemakerStatus() PacemakerStatus {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	increases := make([]TimeoutIncrease, len(t.timeoutIncreases))
+	copy(increases, t.timeoutIncreases)
+
+	histogram := make([]TimeoutBucketCount, 0, len(t.histogram))
+	var count uint64
+	for i, bucketCount := range t.histogram {
+		count += bucketCount
+		var upperBound time.Duration
+		if i < len(replicaTimeoutBuckets) {
+			upperBound = replicaTimeoutBuckets[i]
+		}
+		histogram = append(histogram, TimeoutBucketCount{
+			UpperBound: upperBound,
+			Count:      count,
+		})
+	}
+
+	return PacemakerStatus{
+		CurView:          t.curView,
+		TimeoutMode:      t.timer.Mode,
+		TimeoutView:      t.timer.View,
+		TimeoutStarted:   t.timer.StartTime,
+		TimeoutDuration:  t.timer.Duration,
+		Timeouts:         t.timeouts,
+		TimeoutIncreases: increases,
+		TimeoutHistogram: histogram,
+	}
+}
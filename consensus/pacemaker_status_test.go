@@ -0,0 +1,86 @@
+package consensus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestPacemakerStatusTracker tests that the tracker reports the view, the active timeout, the timeout
+// increases and the distribution of the replica timeouts of the pacemaker.
+func TestPacemakerStatusTracker(t *testing.T) {
+	tracker := consensus.NewPacemakerStatusTracker()
+	start := time.Now().UTC()
+
+	startView := func(view uint64, duration time.Duration) {
+		tracker.OnEnteringView(view, unittest.IdentifierFixture())
+		tracker.OnStartingTimeout(&model.TimerInfo{
+			Mode:      model.ReplicaTimeout,
+			View:      view,
+			StartTime: start.Add(time.Duration(view) * time.Second),
+			Duration:  duration,
+		})
+	}
+
+	// view 1 times out, and the timeout doubles for view 2
+	startView(1, time.Second)
+	tracker.OnReachedTimeout(&model.TimerInfo{Mode: model.ReplicaTimeout, View: 1})
+	startView(2, 2*time.Second)
+
+	// view 2 makes progress, so the timeout decreases for view 3
+	startView(3, 1500*time.Millisecond)
+
+	// the participant is the leader of view 4 and collects votes for the block of view 3
+	tracker.OnStartingTimeout(&model.TimerInfo{
+		Mode:      model.VoteCollectionTimeout,
+		View:      3,
+		StartTime: start.Add(4 * time.Second),
+		Duration:  time.Second,
+	})
+
+	status := tracker.PacemakerStatus()
+	assert.Equal(t, uint64(3), status.CurView)
+	assert.Equal(t, model.VoteCollectionTimeout, status.TimeoutMode)
+	assert.Equal(t, uint64(3), status.TimeoutView)
+	assert.Equal(t, start.Add(4*time.Second), status.TimeoutStarted)
+	assert.Equal(t, time.Second, status.TimeoutDuration)
+	assert.Equal(t, uint64(1), status.Timeouts)
+
+	require.Len(t, status.TimeoutIncreases, 1)
+	assert.Equal(t, consensus.TimeoutIncrease{
+		View:     1,
+		Time:     start.Add(2 * time.Second),
+		Previous: time.Second,
+		Next:     2 * time.Second,
+	}, status.TimeoutIncreases[0])
+
+	// the vote collection timeout is not counted in the histogram
+	require.Len(t, status.TimeoutHistogram, 8)
+	assert.Equal(t, consensus.TimeoutBucketCount{UpperBound: 500 * time.Millisecond, Count: 0}, status.TimeoutHistogram[0])
+	assert.Equal(t, consensus.TimeoutBucketCount{UpperBound: time.Second, Count: 1}, status.TimeoutHistogram[1])
+	assert.Equal(t, consensus.TimeoutBucketCount{UpperBound: 2 * time.Second, Count: 3}, status.TimeoutHistogram[2])
+	assert.Equal(t, consensus.TimeoutBucketCount{UpperBound: 0, Count: 3}, status.TimeoutHistogram[7])
+
+	t.Run("keeps the most recent timeout increases", func(t *testing.T) {
+		tracker := consensus.NewPacemakerStatusTracker()
+		for view := uint64(1); view <= 20; view++ {
+			tracker.OnStartingTimeout(&model.TimerInfo{
+				Mode:     model.ReplicaTimeout,
+				View:     view,
+				Duration: time.Duration(view) * time.Second,
+			})
+		}
+
+		status := tracker.PacemakerStatus()
+		require.Len(t, status.TimeoutIncreases, 16)
+		assert.Equal(t, uint64(4), status.TimeoutIncreases[0].View)
+		assert.Equal(t, uint64(19), status.TimeoutIncreases[15].View)
+		assert.Equal(t, uint64(20), status.TimeoutHistogram[7].Count)
+	})
+}
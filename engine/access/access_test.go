@@ -486,8 +486,8 @@ func (suite *Suite) TestGetSealedTransaction() {
 
 		handler := access.NewHandler(backend, suite.chainID.Chain())
 
-		rpcEng := rpc.New(suite.log, suite.state, rpc.Config{}, nil, nil, blocks, headers, collections, transactions,
-			receipts, suite.chainID, metrics, 0, 0, false, false, nil, nil)
+		rpcEng := rpc.New(suite.log, suite.state, rpc.Config{}, nil, nil, nil, nil, blocks, headers, collections, transactions,
+			receipts, nil, suite.chainID, metrics, 0, 0, false, false, nil, nil)
 
 		// create the ingest engine
 		ingestEng, err := ingestion.New(suite.log, suite.net, suite.state, suite.me, suite.request, blocks, headers, collections,
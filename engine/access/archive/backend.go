@@ -0,0 +1,343 @@
+package archive
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Backend implements the Access API for an archival access node. Queries for
+// blocks, collections, transactions, events and scripts that fall within the
+// heights covered by the registry are served from the imported archive sources.
+// All other queries are forwarded to the live backend.
+type Backend struct {
+	access.API
+	registry       *Registry
+	maxHeightRange uint
+}
+
+var _ access.API = (*Backend)(nil)
+
+// NewBackend creates a new archival backend serving historical queries from the given
+// registry, and forwarding all other queries to the live backend. Event queries spanning more
+// than maxHeightRange blocks are rejected, as they are by the live backend.
+func NewBackend(live access.API, registry *Registry, maxHeightRange uint) *Backend {
+	return &Backend{
+		API:            live,
+		registry:       registry,
+		maxHeightRange: maxHeightRange,
+	}
+}
+
+func (b *Backend) GetBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.Header, error) {
+	source, err := b.registry.ByHeight(height)
+	if errors.Is(err, storage.ErrNotFound) {
+		return b.API.GetBlockHeaderByHeight(ctx, height)
+	}
+	header, err := source.HeaderByHeight(height)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return header, nil
+}
+
+func (b *Backend) GetBlockHeaderByID(ctx context.Context, id flow.Identifier) (*flow.Header, error) {
+	header, err := b.API.GetBlockHeaderByID(ctx, id)
+	if status.Code(err) != codes.NotFound {
+		return header, err
+	}
+	_, header, err = b.registry.ByBlockID(id)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return header, nil
+}
+
+func (b *Backend) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	source, err := b.registry.ByHeight(height)
+	if errors.Is(err, storage.ErrNotFound) {
+		return b.API.GetBlockByHeight(ctx, height)
+	}
+	block, err := source.BlockByHeight(height)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return block, nil
+}
+
+func (b *Backend) GetBlockByID(ctx context.Context, id flow.Identifier) (*flow.Block, error) {
+	block, err := b.API.GetBlockByID(ctx, id)
+	if status.Code(err) != codes.NotFound {
+		return block, err
+	}
+	source, _, err := b.registry.ByBlockID(id)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	block, err = source.BlockByID(id)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return block, nil
+}
+
+func (b *Backend) GetCollectionByID(ctx context.Context, id flow.Identifier) (*flow.LightCollection, error) {
+	collection, err := b.API.GetCollectionByID(ctx, id)
+	if status.Code(err) != codes.NotFound {
+		return collection, err
+	}
+	_, collection, err = b.registry.ByCollectionID(id)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return collection, nil
+}
+
+func (b *Backend) GetTransaction(ctx context.Context, id flow.Identifier) (*flow.TransactionBody, error) {
+	tx, err := b.API.GetTransaction(ctx, id)
+	if status.Code(err) != codes.NotFound {
+		return tx, err
+	}
+	_, tx, err = b.registry.ByTransactionID(id)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return tx, nil
+}
+
+func (b *Backend) GetTransactionResult(ctx context.Context, id flow.Identifier) (*access.TransactionResult, error) {
+	result, err := b.API.GetTransactionResult(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// the live backend reports transactions it doesn't know about as unknown
+	if result.Status != flow.TransactionStatusUnknown {
+		return result, nil
+	}
+
+	source, _, err := b.registry.ByTransactionID(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, convertError(err)
+	}
+	archived, err := source.TransactionResultByID(id)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return archived, nil
+}
+
+// GetTransactionResultsByBlockID serves the results of archived blocks from the archive sources,
+// and forwards the query for all other blocks to the live backend.
+func (b *Backend) GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier, offset uint, limit uint) ([]*access.TransactionResult, error) {
+	source, _, err := b.registry.ByBlockID(blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return b.API.GetTransactionResultsByBlockID(ctx, blockID, offset, limit)
+	}
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if limit == 0 || limit > backend.MaxTransactionResultsPerPage {
+		limit = backend.MaxTransactionResultsPerPage
+	}
+	results, err := source.TransactionResultsByBlockID(blockID, offset, limit)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return results, nil
+}
+
+func (b *Backend) ExecuteScriptAtBlockHeight(ctx context.Context, blockHeight uint64, script []byte, arguments [][]byte) ([]byte, error) {
+	source, err := b.registry.ByHeight(blockHeight)
+	if errors.Is(err, storage.ErrNotFound) {
+		return b.API.ExecuteScriptAtBlockHeight(ctx, blockHeight, script, arguments)
+	}
+	header, err := source.HeaderByHeight(blockHeight)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	value, err := source.ExecuteScriptAtBlockID(ctx, header.ID(), script, arguments)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return value, nil
+}
+
+func (b *Backend) ExecuteScriptAtBlockID(ctx context.Context, blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, error) {
+	source, _, err := b.registry.ByBlockID(blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return b.API.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+	}
+	if err != nil {
+		return nil, convertError(err)
+	}
+	value, err := source.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return value, nil
+}
+
+// GetEventsForHeightRange serves the events of all heights covered by the registry from the
+// archive sources, and forwards each contiguous range of remaining heights to the live backend.
+func (b *Backend) GetEventsForHeightRange(ctx context.Context, eventType string, startHeight, endHeight uint64) ([]flow.BlockEvents, error) {
+	if endHeight < startHeight {
+		return nil, status.Error(codes.InvalidArgument, "invalid start or end height")
+	}
+	// compare the difference rather than the end height, which would overflow for the maximum end height
+	if endHeight-startHeight >= uint64(b.maxHeightRange) {
+		return nil, status.Errorf(codes.InvalidArgument, "requested block range (%d) exceeded maximum (%d)",
+			endHeight-startHeight+1, b.maxHeightRange)
+	}
+
+	first, last, ok := b.registry.Range()
+	if !ok || endHeight < first || startHeight > last {
+		return b.API.GetEventsForHeightRange(ctx, eventType, startHeight, endHeight)
+	}
+
+	var results []flow.BlockEvents
+	var liveStart *uint64
+	flushLive := func(liveEnd uint64) error {
+		if liveStart == nil {
+			return nil
+		}
+		events, err := b.API.GetEventsForHeightRange(ctx, eventType, *liveStart, liveEnd)
+		if err != nil {
+			return err
+		}
+		results = append(results, events...)
+		liveStart = nil
+		return nil
+	}
+
+	// iterate over offsets, as incrementing the height past the maximum end height would wrap around
+	for offset := uint64(0); offset <= endHeight-startHeight; offset++ {
+		height := startHeight + offset
+		source, err := b.registry.ByHeight(height)
+		if errors.Is(err, storage.ErrNotFound) {
+			if liveStart == nil {
+				h := height
+				liveStart = &h
+			}
+			continue
+		}
+
+		err = flushLive(height - 1)
+		if err != nil {
+			return nil, err
+		}
+
+		header, err := source.HeaderByHeight(height)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		blockEvents, err := archivedBlockEvents(source, header, eventType)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, blockEvents)
+	}
+
+	err := flushLive(endHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetEventsForBlockIDs serves the events of all blocks contained in the registry from the
+// archive sources, and forwards the remaining block IDs to the live backend. The order of
+// the requested block IDs is preserved.
+func (b *Backend) GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flow.Identifier) ([]flow.BlockEvents, error) {
+	if uint(len(blockIDs)) > b.maxHeightRange {
+		return nil, status.Errorf(codes.InvalidArgument, "requested block range (%d) exceeded maximum (%d)",
+			len(blockIDs), b.maxHeightRange)
+	}
+
+	archived := make(map[flow.Identifier]flow.BlockEvents)
+	var liveIDs []flow.Identifier
+
+	for _, blockID := range blockIDs {
+		source, header, err := b.registry.ByBlockID(blockID)
+		if errors.Is(err, storage.ErrNotFound) {
+			liveIDs = append(liveIDs, blockID)
+			continue
+		}
+		if err != nil {
+			return nil, convertError(err)
+		}
+		blockEvents, err := archivedBlockEvents(source, header, eventType)
+		if err != nil {
+			return nil, err
+		}
+		archived[blockID] = blockEvents
+	}
+
+	if len(archived) == 0 {
+		return b.API.GetEventsForBlockIDs(ctx, eventType, blockIDs)
+	}
+
+	live := make(map[flow.Identifier]flow.BlockEvents)
+	if len(liveIDs) > 0 {
+		liveEvents, err := b.API.GetEventsForBlockIDs(ctx, eventType, liveIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, blockEvents := range liveEvents {
+			live[blockEvents.BlockID] = blockEvents
+		}
+	}
+
+	results := make([]flow.BlockEvents, 0, len(blockIDs))
+	for _, blockID := range blockIDs {
+		if blockEvents, ok := archived[blockID]; ok {
+			results = append(results, blockEvents)
+			continue
+		}
+		if blockEvents, ok := live[blockID]; ok {
+			results = append(results, blockEvents)
+		}
+	}
+
+	return results, nil
+}
+
+func archivedBlockEvents(source Source, header *flow.Header, eventType string) (flow.BlockEvents, error) {
+	blockID := header.ID()
+	events, err := source.Events(blockID, flow.EventType(eventType))
+	if err != nil {
+		return flow.BlockEvents{}, convertError(err)
+	}
+	return flow.BlockEvents{
+		BlockID:        blockID,
+		BlockHeight:    header.Height,
+		BlockTimestamp: header.Timestamp,
+		Events:         events,
+	}, nil
+}
+
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		return status.Errorf(codes.NotFound, "not found in archive: %v", err)
+	}
+	if errors.Is(err, ErrScriptExecutionNotSupported) {
+		return status.Errorf(codes.Unimplemented, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "failed to query archive: %v", err)
+}
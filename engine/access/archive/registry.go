@@ -0,0 +1,188 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Registry maps ranges of block heights to the archive sources serving them.
+// Height ranges of registered sources must not overlap. Sources implementing io.Closer
+// are closed when the registry is done.
+type Registry struct {
+	mu      sync.RWMutex
+	sources []Source // ordered by first height
+}
+
+// NewRegistry creates a new registry containing the given sources.
+func NewRegistry(sources ...Source) (*Registry, error) {
+	r := &Registry{}
+	for _, source := range sources {
+		err := r.Register(source)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds a source to the registry. It returns an error if the height range
+// of the source overlaps with the height range of an already registered source.
+func (r *Registry) Register(source Source) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first, last := source.Range()
+	for _, existing := range r.sources {
+		existingFirst, existingLast := existing.Range()
+		if first <= existingLast && existingFirst <= last {
+			return fmt.Errorf("height range [%d, %d] overlaps with registered range [%d, %d]",
+				first, last, existingFirst, existingLast)
+		}
+	}
+
+	r.sources = append(r.sources, source)
+	sort.Slice(r.sources, func(i, j int) bool {
+		first1, _ := r.sources[i].Range()
+		first2, _ := r.sources[j].Range()
+		return first1 < first2
+	})
+
+	return nil
+}
+
+// Range returns the lowest and highest heights served by the registry. The
+// returned bool is false if no sources are registered.
+func (r *Registry) Range() (uint64, uint64, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.sources) == 0 {
+		return 0, 0, false
+	}
+	first, _ := r.sources[0].Range()
+	_, last := r.sources[len(r.sources)-1].Range()
+	return first, last, true
+}
+
+// ByHeight returns the source serving the given height.
+// It returns storage.ErrNotFound if no registered source serves the height.
+func (r *Registry) ByHeight(height uint64) (Source, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// find the first source whose last height is not below the given height
+	index := sort.Search(len(r.sources), func(i int) bool {
+		_, last := r.sources[i].Range()
+		return last >= height
+	})
+	if index == len(r.sources) {
+		return nil, storage.ErrNotFound
+	}
+
+	source := r.sources[index]
+	first, _ := source.Range()
+	if height < first {
+		return nil, storage.ErrNotFound
+	}
+	return source, nil
+}
+
+// ByBlockID returns the source containing the block with the given ID.
+// It returns storage.ErrNotFound if no registered source contains the block.
+func (r *Registry) ByBlockID(blockID flow.Identifier) (Source, *flow.Header, error) {
+	for _, source := range r.all() {
+		header, err := source.HeaderByID(blockID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not look up block %x: %w", blockID, err)
+		}
+		return source, header, nil
+	}
+	return nil, nil, storage.ErrNotFound
+}
+
+// ByTransactionID returns the source containing the transaction with the given ID.
+// It returns storage.ErrNotFound if no registered source contains the transaction.
+func (r *Registry) ByTransactionID(txID flow.Identifier) (Source, *flow.TransactionBody, error) {
+	for _, source := range r.all() {
+		tx, err := source.TransactionByID(txID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not look up transaction %x: %w", txID, err)
+		}
+		return source, tx, nil
+	}
+	return nil, nil, storage.ErrNotFound
+}
+
+// ByCollectionID returns the source containing the collection with the given ID.
+// It returns storage.ErrNotFound if no registered source contains the collection.
+func (r *Registry) ByCollectionID(collID flow.Identifier) (Source, *flow.LightCollection, error) {
+	for _, source := range r.all() {
+		collection, err := source.CollectionByID(collID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not look up collection %x: %w", collID, err)
+		}
+		return source, collection, nil
+	}
+	return nil, nil, storage.ErrNotFound
+}
+
+// Ready returns a channel that is closed immediately, as the sources are ready once registered.
+func (r *Registry) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
+// Done closes all registered sources implementing io.Closer, and returns a channel that is
+// closed once they are closed.
+func (r *Registry) Done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = r.Close()
+	}()
+	return done
+}
+
+// Close closes all registered sources implementing io.Closer.
+func (r *Registry) Close() error {
+	var errs *multierror.Error
+	for _, source := range r.all() {
+		closer, ok := source.(io.Closer)
+		if !ok {
+			continue
+		}
+		err := closer.Close()
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// all returns a copy of the registered sources, ordered by height.
+func (r *Registry) all() []Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sources := make([]Source, len(r.sources))
+	copy(sources, r.sources)
+	return sources
+}
@@ -0,0 +1,269 @@
+package archive
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// memorySource is an in-memory archive source used for testing.
+type memorySource struct {
+	first   uint64
+	last    uint64
+	headers map[uint64]*flow.Header
+	events  map[flow.Identifier][]flow.Event
+	results map[flow.Identifier][]*access.TransactionResult
+}
+
+func newMemorySource(first, last uint64) *memorySource {
+	s := &memorySource{
+		first:   first,
+		last:    last,
+		headers: make(map[uint64]*flow.Header),
+		events:  make(map[flow.Identifier][]flow.Event),
+		results: make(map[flow.Identifier][]*access.TransactionResult),
+	}
+	for height := first; height <= last; height++ {
+		header := unittest.BlockHeaderFixture()
+		header.Height = height
+		s.headers[height] = &header
+		s.events[header.ID()] = []flow.Event{
+			unittest.EventFixture(flow.EventAccountCreated, 0, 0, unittest.IdentifierFixture()),
+		}
+		for i := 0; i < 3; i++ {
+			s.results[header.ID()] = append(s.results[header.ID()], &access.TransactionResult{
+				Status:        flow.TransactionStatusSealed,
+				BlockID:       header.ID(),
+				TransactionID: unittest.IdentifierFixture(),
+			})
+		}
+	}
+	return s
+}
+
+func (s *memorySource) Range() (uint64, uint64) {
+	return s.first, s.last
+}
+
+func (s *memorySource) HeaderByID(blockID flow.Identifier) (*flow.Header, error) {
+	for _, header := range s.headers {
+		if header.ID() == blockID {
+			return header, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *memorySource) HeaderByHeight(height uint64) (*flow.Header, error) {
+	header, ok := s.headers[height]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return header, nil
+}
+
+func (s *memorySource) BlockByID(flow.Identifier) (*flow.Block, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (s *memorySource) BlockByHeight(uint64) (*flow.Block, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (s *memorySource) CollectionByID(flow.Identifier) (*flow.LightCollection, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (s *memorySource) TransactionByID(flow.Identifier) (*flow.TransactionBody, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (s *memorySource) TransactionResultByID(flow.Identifier) (*access.TransactionResult, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (s *memorySource) TransactionResultsByBlockID(blockID flow.Identifier, offset uint, limit uint) ([]*access.TransactionResult, error) {
+	results, ok := s.results[blockID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	if offset >= uint(len(results)) {
+		return []*access.TransactionResult{}, nil
+	}
+	end := offset + limit
+	if end > uint(len(results)) {
+		end = uint(len(results))
+	}
+	return results[offset:end], nil
+}
+
+func (s *memorySource) Events(blockID flow.Identifier, _ flow.EventType) ([]flow.Event, error) {
+	return s.events[blockID], nil
+}
+
+func (s *memorySource) ExecuteScriptAtBlockID(context.Context, flow.Identifier, []byte, [][]byte) ([]byte, error) {
+	return nil, ErrScriptExecutionNotSupported
+}
+
+func TestRegistry(t *testing.T) {
+
+	spork1 := newMemorySource(0, 9)
+	spork2 := newMemorySource(20, 29)
+
+	registry, err := NewRegistry(spork2, spork1)
+	require.NoError(t, err)
+
+	t.Run("range covers all sources", func(t *testing.T) {
+		first, last, ok := registry.Range()
+		require.True(t, ok)
+		assert.Equal(t, uint64(0), first)
+		assert.Equal(t, uint64(29), last)
+	})
+
+	t.Run("lookup by height", func(t *testing.T) {
+		source, err := registry.ByHeight(5)
+		require.NoError(t, err)
+		assert.Equal(t, spork1, source)
+
+		source, err = registry.ByHeight(29)
+		require.NoError(t, err)
+		assert.Equal(t, spork2, source)
+
+		// height in between sporks is not served
+		_, err = registry.ByHeight(15)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		// height above all sporks is not served
+		_, err = registry.ByHeight(30)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("lookup by block ID", func(t *testing.T) {
+		expected := spork2.headers[25]
+		source, header, err := registry.ByBlockID(expected.ID())
+		require.NoError(t, err)
+		assert.Equal(t, spork2, source)
+		assert.Equal(t, expected, header)
+
+		_, _, err = registry.ByBlockID(unittest.IdentifierFixture())
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("overlapping ranges are rejected", func(t *testing.T) {
+		err := registry.Register(newMemorySource(9, 12))
+		assert.Error(t, err)
+
+		err = registry.Register(newMemorySource(10, 19))
+		assert.NoError(t, err)
+	})
+}
+
+// liveAPI is a stub of the live backend serving events for any requested heights.
+type liveAPI struct {
+	access.API
+	ranges [][2]uint64
+}
+
+func (l *liveAPI) GetEventsForHeightRange(_ context.Context, _ string, startHeight, endHeight uint64) ([]flow.BlockEvents, error) {
+	l.ranges = append(l.ranges, [2]uint64{startHeight, endHeight})
+	var results []flow.BlockEvents
+	for height := startHeight; height <= endHeight; height++ {
+		results = append(results, flow.BlockEvents{BlockHeight: height})
+	}
+	return results, nil
+}
+
+func TestBackend_GetEventsForHeightRange(t *testing.T) {
+
+	spork1 := newMemorySource(10, 19)
+	registry, err := NewRegistry(spork1)
+	require.NoError(t, err)
+
+	live := &liveAPI{}
+	backend := NewBackend(live, registry, 100)
+
+	results, err := backend.GetEventsForHeightRange(context.Background(), string(flow.EventAccountCreated), 5, 24)
+	require.NoError(t, err)
+	require.Len(t, results, 20)
+
+	// heights are returned in order, regardless of where they were served from
+	for i, blockEvents := range results {
+		assert.Equal(t, uint64(5+i), blockEvents.BlockHeight)
+	}
+
+	// archived heights are served with their events
+	for _, blockEvents := range results[5:15] {
+		assert.Equal(t, spork1.events[blockEvents.BlockID], blockEvents.Events)
+	}
+
+	// the heights outside of the archive are forwarded to the live backend
+	assert.Equal(t, [][2]uint64{{5, 9}, {20, 24}}, live.ranges)
+}
+
+func TestBackend_HeightRangeLimit(t *testing.T) {
+
+	registry, err := NewRegistry(newMemorySource(10, 19))
+	require.NoError(t, err)
+
+	live := &liveAPI{}
+	backend := NewBackend(live, registry, 5)
+
+	t.Run("range within limit", func(t *testing.T) {
+		results, err := backend.GetEventsForHeightRange(context.Background(), string(flow.EventAccountCreated), 10, 14)
+		require.NoError(t, err)
+		assert.Len(t, results, 5)
+	})
+
+	t.Run("range exceeding limit", func(t *testing.T) {
+		_, err := backend.GetEventsForHeightRange(context.Background(), string(flow.EventAccountCreated), 10, 15)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	// the end height must neither overflow the range check nor the iteration
+	t.Run("maximum end height", func(t *testing.T) {
+		_, err := backend.GetEventsForHeightRange(context.Background(), string(flow.EventAccountCreated), 0, math.MaxUint64)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = backend.GetEventsForHeightRange(context.Background(), string(flow.EventAccountCreated), 15, math.MaxUint64)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("block IDs exceeding limit", func(t *testing.T) {
+		_, err := backend.GetEventsForBlockIDs(context.Background(), string(flow.EventAccountCreated), unittest.IdentifierListFixture(6))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	assert.Empty(t, live.ranges)
+}
+
+func TestBackend_GetTransactionResultsByBlockID(t *testing.T) {
+
+	spork1 := newMemorySource(10, 19)
+	registry, err := NewRegistry(spork1)
+	require.NoError(t, err)
+
+	backend := NewBackend(&liveAPI{}, registry, 100)
+	blockID := spork1.headers[15].ID()
+
+	results, err := backend.GetTransactionResultsByBlockID(context.Background(), blockID, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, spork1.results[blockID], results)
+
+	results, err = backend.GetTransactionResultsByBlockID(context.Background(), blockID, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, spork1.results[blockID][1:2], results)
+
+	results, err = backend.GetTransactionResultsByBlockID(context.Background(), blockID, 3, 10)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,39 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+
+	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ExecutionNodeScriptExecutor executes scripts on an execution node still serving the
+// execution state of a past spork.
+type ExecutionNodeScriptExecutor struct {
+	client execproto.ExecutionAPIClient
+}
+
+var _ ScriptExecutor = (*ExecutionNodeScriptExecutor)(nil)
+
+// NewExecutionNodeScriptExecutor creates a new script executor forwarding scripts to the given
+// execution node client.
+func NewExecutionNodeScriptExecutor(client execproto.ExecutionAPIClient) *ExecutionNodeScriptExecutor {
+	return &ExecutionNodeScriptExecutor{
+		client: client,
+	}
+}
+
+func (e *ExecutionNodeScriptExecutor) ExecuteScriptAtBlockID(ctx context.Context, blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, error) {
+	req := &execproto.ExecuteScriptAtBlockIDRequest{
+		BlockId:   blockID[:],
+		Script:    script,
+		Arguments: arguments,
+	}
+	resp, err := e.client.ExecuteScriptAtBlockID(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute script on archive execution node: %w", err)
+	}
+	return resp.GetValue(), nil
+}
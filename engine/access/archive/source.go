@@ -0,0 +1,254 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// ErrScriptExecutionNotSupported is returned when a script is executed against a
+// source which has no script executor configured.
+var ErrScriptExecutionNotSupported = errors.New("script execution is not supported by this archive source")
+
+// Source provides read access to the data of a past spork. Each source serves a
+// contiguous range of finalized block heights.
+type Source interface {
+	// Range returns the first and last (inclusive) block heights served by the source.
+	Range() (uint64, uint64)
+
+	HeaderByID(blockID flow.Identifier) (*flow.Header, error)
+	HeaderByHeight(height uint64) (*flow.Header, error)
+
+	BlockByID(blockID flow.Identifier) (*flow.Block, error)
+	BlockByHeight(height uint64) (*flow.Block, error)
+
+	CollectionByID(collID flow.Identifier) (*flow.LightCollection, error)
+
+	TransactionByID(txID flow.Identifier) (*flow.TransactionBody, error)
+	TransactionResultByID(txID flow.Identifier) (*access.TransactionResult, error)
+	// TransactionResultsByBlockID returns the results of the transactions of the given block in the
+	// order of execution, starting at the given offset and returning at most limit results.
+	TransactionResultsByBlockID(blockID flow.Identifier, offset uint, limit uint) ([]*access.TransactionResult, error)
+
+	Events(blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error)
+
+	ExecuteScriptAtBlockID(ctx context.Context, blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, error)
+}
+
+// ScriptExecutor executes scripts against the execution state of a past spork.
+type ScriptExecutor interface {
+	ExecuteScriptAtBlockID(ctx context.Context, blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, error)
+}
+
+// StorageSource is a Source backed by the imported database export of a past spork.
+type StorageSource struct {
+	db           *badger.DB // the imported database, closed with the source
+	first        uint64
+	last         uint64
+	headers      storage.Headers
+	blocks       storage.Blocks
+	collections  storage.Collections
+	transactions storage.Transactions
+	results      storage.TransactionResults
+	events       storage.Events
+	scripts      ScriptExecutor
+}
+
+// NewStorageSource creates a new archive source from the given storage, serving blocks between
+// first and last height (inclusive). The script executor is optional; if it is nil, script
+// execution requests will fail with ErrScriptExecutionNotSupported.
+func NewStorageSource(all *storage.All, first uint64, last uint64, scripts ScriptExecutor) (*StorageSource, error) {
+	if first > last {
+		return nil, fmt.Errorf("invalid height range [%d, %d]", first, last)
+	}
+
+	s := &StorageSource{
+		first:        first,
+		last:         last,
+		headers:      all.Headers,
+		blocks:       all.Blocks,
+		collections:  all.Collections,
+		transactions: all.Transactions,
+		results:      all.TransactionResults,
+		events:       all.Events,
+		scripts:      scripts,
+	}
+	return s, nil
+}
+
+// ImportStorageSource creates a new archive source from a database exported from a past spork.
+// The served height range is the range between the root and the latest finalized block of the
+// exported protocol state.
+func ImportStorageSource(metrics module.CacheMetrics, db *badger.DB, scripts ScriptExecutor) (*StorageSource, error) {
+	var first, last uint64
+	err := db.View(operation.RetrieveRootHeight(&first))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve root height of archive: %w", err)
+	}
+	err = db.View(operation.RetrieveFinalizedHeight(&last))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve finalized height of archive: %w", err)
+	}
+
+	source, err := NewStorageSource(bstorage.InitAll(metrics, db), first, last, scripts)
+	if err != nil {
+		return nil, err
+	}
+	source.db = db
+	return source, nil
+}
+
+// Close closes the imported database of the source.
+func (s *StorageSource) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func (s *StorageSource) Range() (uint64, uint64) {
+	return s.first, s.last
+}
+
+func (s *StorageSource) HeaderByID(blockID flow.Identifier) (*flow.Header, error) {
+	header, err := s.headers.ByBlockID(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.contains(header.Height) {
+		return nil, storage.ErrNotFound
+	}
+	return header, nil
+}
+
+func (s *StorageSource) HeaderByHeight(height uint64) (*flow.Header, error) {
+	if !s.contains(height) {
+		return nil, storage.ErrNotFound
+	}
+	return s.headers.ByHeight(height)
+}
+
+func (s *StorageSource) BlockByID(blockID flow.Identifier) (*flow.Block, error) {
+	block, err := s.blocks.ByID(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.contains(block.Header.Height) {
+		return nil, storage.ErrNotFound
+	}
+	return block, nil
+}
+
+func (s *StorageSource) BlockByHeight(height uint64) (*flow.Block, error) {
+	if !s.contains(height) {
+		return nil, storage.ErrNotFound
+	}
+	return s.blocks.ByHeight(height)
+}
+
+func (s *StorageSource) CollectionByID(collID flow.Identifier) (*flow.LightCollection, error) {
+	return s.collections.LightByID(collID)
+}
+
+func (s *StorageSource) TransactionByID(txID flow.Identifier) (*flow.TransactionBody, error) {
+	return s.transactions.ByID(txID)
+}
+
+// TransactionResultByID returns the result of the given transaction. Since only finalized
+// and executed blocks are part of an archive, all transactions found are reported as sealed.
+func (s *StorageSource) TransactionResultByID(txID flow.Identifier) (*access.TransactionResult, error) {
+	collection, err := s.collections.LightByTransactionID(txID)
+	if err != nil {
+		return nil, fmt.Errorf("could not find collection for transaction: %w", err)
+	}
+	block, err := s.blocks.ByCollectionID(collection.ID())
+	if err != nil {
+		return nil, fmt.Errorf("could not find block for collection: %w", err)
+	}
+	return s.transactionResult(block.ID(), txID)
+}
+
+// TransactionResultsByBlockID returns the results of the transactions of the given block, in the
+// order of the block's collections.
+func (s *StorageSource) TransactionResultsByBlockID(blockID flow.Identifier, offset uint, limit uint) ([]*access.TransactionResult, error) {
+	block, err := s.BlockByID(blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	var txIDs []flow.Identifier
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := s.collections.LightByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("could not find collection: %w", err)
+		}
+		txIDs = append(txIDs, collection.Transactions...)
+	}
+	if offset >= uint(len(txIDs)) {
+		return []*access.TransactionResult{}, nil
+	}
+	end := offset + limit
+	if end > uint(len(txIDs)) {
+		end = uint(len(txIDs))
+	}
+
+	results := make([]*access.TransactionResult, 0, end-offset)
+	for _, txID := range txIDs[offset:end] {
+		result, err := s.transactionResult(blockID, txID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// transactionResult returns the result of the given transaction of the given block.
+func (s *StorageSource) transactionResult(blockID flow.Identifier, txID flow.Identifier) (*access.TransactionResult, error) {
+	result, err := s.results.ByBlockIDTransactionID(blockID, txID)
+	if err != nil {
+		return nil, fmt.Errorf("could not find transaction result: %w", err)
+	}
+	events, err := s.events.ByBlockIDTransactionID(blockID, txID)
+	if err != nil {
+		return nil, fmt.Errorf("could not find transaction events: %w", err)
+	}
+
+	var statusCode uint
+	if result.ErrorMessage != "" {
+		statusCode = 1
+	}
+
+	return &access.TransactionResult{
+		Status:        flow.TransactionStatusSealed,
+		StatusCode:    statusCode,
+		Events:        events,
+		ErrorMessage:  result.ErrorMessage,
+		BlockID:       blockID,
+		TransactionID: txID,
+	}, nil
+}
+
+func (s *StorageSource) Events(blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error) {
+	return s.events.ByBlockIDEventType(blockID, eventType)
+}
+
+func (s *StorageSource) ExecuteScriptAtBlockID(ctx context.Context, blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, error) {
+	if s.scripts == nil {
+		return nil, ErrScriptExecutionNotSupported
+	}
+	return s.scripts.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+}
+
+func (s *StorageSource) contains(height uint64) bool {
+	return height >= s.first && height <= s.last
+}
@@ -0,0 +1,64 @@
+package indexer
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Backend implements the Access API for an access node running the execution state
+// indexer. Scripts at heights covered by the register index are executed locally,
+// all other queries are forwarded to the wrapped backend.
+type Backend struct {
+	access.API
+	scripts *ScriptExecutor
+}
+
+var _ access.API = (*Backend)(nil)
+
+// NewBackend creates a new backend executing scripts locally when possible, and
+// forwarding all other queries to the given backend.
+func NewBackend(api access.API, scripts *ScriptExecutor) *Backend {
+	return &Backend{
+		API:     api,
+		scripts: scripts,
+	}
+}
+
+func (b *Backend) ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, arguments [][]byte) ([]byte, error) {
+	header, err := b.API.GetLatestBlockHeader(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if !b.scripts.CanExecuteAtHeight(header.Height) {
+		return b.API.ExecuteScriptAtLatestBlock(ctx, script, arguments)
+	}
+	return b.executeLocally(b.scripts.ExecuteScriptAtBlockHeight(ctx, header.Height, script, arguments))
+}
+
+func (b *Backend) ExecuteScriptAtBlockHeight(ctx context.Context, blockHeight uint64, script []byte, arguments [][]byte) ([]byte, error) {
+	if !b.scripts.CanExecuteAtHeight(blockHeight) {
+		return b.API.ExecuteScriptAtBlockHeight(ctx, blockHeight, script, arguments)
+	}
+	return b.executeLocally(b.scripts.ExecuteScriptAtBlockHeight(ctx, blockHeight, script, arguments))
+}
+
+func (b *Backend) ExecuteScriptAtBlockID(ctx context.Context, blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, error) {
+	header, err := b.API.GetBlockHeaderByID(ctx, blockID)
+	if err != nil || !b.scripts.CanExecuteAtHeight(header.Height) {
+		return b.API.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+	}
+	return b.executeLocally(b.scripts.ExecuteScriptAtBlockHeight(ctx, header.Height, script, arguments))
+}
+
+// executeLocally converts the result of a local script execution to the response of the access API.
+func (b *Backend) executeLocally(value []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to execute the script: %v", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,88 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// CheckpointSource provides the full execution state of the root block, read from the root
+// checkpoint, so that the register index starts with every register of the root state. The
+// execution data of all other blocks is provided by the wrapped source.
+type CheckpointSource struct {
+	ExecutionDataSource
+	rootID     flow.Identifier
+	rootState  flow.StateCommitment
+	checkpoint string
+}
+
+var _ ExecutionDataSource = (*CheckpointSource)(nil)
+
+// NewCheckpointSource creates a source reading the execution state of the root block with the
+// given ID and sealed state commitment from the given checkpoint file.
+func NewCheckpointSource(rootID flow.Identifier, rootState flow.StateCommitment, checkpoint string, source ExecutionDataSource) *CheckpointSource {
+	return &CheckpointSource{
+		ExecutionDataSource: source,
+		rootID:              rootID,
+		rootState:           rootState,
+		checkpoint:          checkpoint,
+	}
+}
+
+// ExecutionData returns all registers of the root state for the root block, and the execution
+// data of the wrapped source for all other blocks.
+func (c *CheckpointSource) ExecutionData(ctx context.Context, blockID flow.Identifier) (*ExecutionData, error) {
+	if blockID != c.rootID {
+		return c.ExecutionDataSource.ExecutionData(ctx, blockID)
+	}
+
+	registers, err := ReadCheckpointRegisters(c.checkpoint, c.rootState)
+	if err != nil {
+		return nil, fmt.Errorf("could not read root state from checkpoint: %w", err)
+	}
+
+	return &ExecutionData{
+		BlockID:         blockID,
+		RegisterUpdates: registers,
+	}, nil
+}
+
+// ReadCheckpointRegisters returns all registers of the trie with the given state commitment in
+// the given checkpoint file. Rebuilding the trie checks that its root hash matches the state
+// commitment, so the registers are authenticated by it.
+func ReadCheckpointRegisters(checkpoint string, commit flow.StateCommitment) (flow.RegisterEntries, error) {
+	forest, err := wal.LoadCheckpointTrie(checkpoint, ledger.RootHash(commit))
+	if err != nil {
+		return nil, fmt.Errorf("could not load checkpoint %s: %w", checkpoint, err)
+	}
+	tries, err := flattener.RebuildTries(forest)
+	if err != nil {
+		return nil, fmt.Errorf("could not rebuild trie: %w", err)
+	}
+	if len(tries) != 1 {
+		return nil, fmt.Errorf("checkpoint resolved to %d tries", len(tries))
+	}
+
+	var registers flow.RegisterEntries
+	err = tries[0].IteratePayloads(func(_ ledger.Path, payload *ledger.Payload) error {
+		registerID, err := state.KeyToRegisterID(payload.Key)
+		if err != nil {
+			return fmt.Errorf("could not convert key of payload: %w", err)
+		}
+		registers = append(registers, flow.RegisterEntry{
+			Key:   registerID,
+			Value: flow.RegisterValue(payload.Value),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return registers, nil
+}
@@ -0,0 +1,81 @@
+package indexer_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/access/indexer"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// writeCheckpoint writes a checkpoint of a trie holding the given registers, and returns the
+// state commitment of the trie
+func writeCheckpoint(t *testing.T, dir string, name string, registers flow.RegisterEntries) flow.StateCommitment {
+	payloads := make([]ledger.Payload, 0, len(registers))
+	for _, entry := range registers {
+		payloads = append(payloads, *ledger.NewPayload(state.RegisterIDToKey(entry.Key), ledger.Value(entry.Value)))
+	}
+	paths, err := pathfinder.PathsFromPayloads(payloads, complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	tr, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads)
+	require.NoError(t, err)
+	flattened, err := flattener.FlattenTrie(tr)
+	require.NoError(t, err)
+
+	writer, err := wal.CreateCheckpointWriterForFile(dir, name)
+	require.NoError(t, err)
+	require.NoError(t, wal.StoreCheckpoint(flattened.ToFlattenedForestWithASingleTrie(), writer))
+	require.NoError(t, writer.Close())
+
+	return flow.StateCommitment(tr.RootHash())
+}
+
+func TestCheckpointSource(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		registers := flow.RegisterEntries{
+			{Key: flow.NewRegisterID("owner", "controller", "a"), Value: []byte("1")},
+			{Key: flow.NewRegisterID("owner", "controller", "b"), Value: []byte("2")},
+			{Key: flow.NewRegisterID("other", "", "c"), Value: []byte("3")},
+		}
+		commit := writeCheckpoint(t, dir, "root.checkpoint", registers)
+		checkpoint := filepath.Join(dir, "root.checkpoint")
+
+		rootID := unittest.IdentifierFixture()
+		childID := unittest.IdentifierFixture()
+		child := &indexer.ExecutionData{BlockID: childID}
+		source := indexer.NewCheckpointSource(rootID, commit, checkpoint, &executionDataSource{
+			data: map[flow.Identifier]*indexer.ExecutionData{childID: child},
+		})
+
+		t.Run("serves root state from checkpoint", func(t *testing.T) {
+			data, err := source.ExecutionData(context.Background(), rootID)
+			require.NoError(t, err)
+			assert.Equal(t, rootID, data.BlockID)
+			assert.ElementsMatch(t, registers, data.RegisterUpdates)
+		})
+
+		t.Run("serves other blocks from wrapped source", func(t *testing.T) {
+			data, err := source.ExecutionData(context.Background(), childID)
+			require.NoError(t, err)
+			assert.Equal(t, child, data)
+		})
+
+		t.Run("rejects checkpoint of other state", func(t *testing.T) {
+			_, err := indexer.ReadCheckpointRegisters(checkpoint, unittest.StateCommitmentFixture())
+			assert.Error(t, err)
+		})
+	})
+}
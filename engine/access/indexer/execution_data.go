@@ -0,0 +1,27 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ErrExecutionDataNotAvailable is returned by an execution data source if the execution
+// data of a block is not (yet) available.
+var ErrExecutionDataNotAvailable = errors.New("execution data not available")
+
+// ExecutionData is the part of the execution data of a sealed block which is relevant to
+// the register index.
+type ExecutionData struct {
+	BlockID         flow.Identifier
+	RegisterUpdates flow.RegisterEntries
+}
+
+// ExecutionDataSource provides the execution data of sealed blocks, like the register updates
+// in the state deltas served by execution nodes.
+type ExecutionDataSource interface {
+	// ExecutionData returns the execution data of the given block. It returns
+	// ErrExecutionDataNotAvailable if the data of the block can not be retrieved yet.
+	ExecutionData(ctx context.Context, blockID flow.Identifier) (*ExecutionData, error)
+}
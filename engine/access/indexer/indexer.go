@@ -0,0 +1,194 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ErrHeightNotIndexed is returned when registers are requested at a height which is not
+// covered by the register index.
+var ErrHeightNotIndexed = errors.New("height not indexed")
+
+// DefaultIndexInterval is the default interval at which the indexer checks for newly sealed blocks.
+const DefaultIndexInterval = time.Second
+
+// Indexer tails the execution data of sealed blocks and maintains a register index,
+// which allows the access node to read registers at any indexed height without
+// querying execution nodes.
+//
+// Blocks are indexed in order of height, starting at the configured start height.
+// The execution data of the start block is expected to contain the full execution
+// state at that height (for instance loaded from a checkpoint), subsequent blocks
+// only need to contain the registers they updated.
+type Indexer struct {
+	unit        *engine.Unit
+	log         zerolog.Logger
+	metrics     module.ExecutionStateIndexerMetrics
+	state       protocol.State
+	headers     storage.Headers
+	registers   storage.RegisterIndex
+	source      ExecutionDataSource
+	startHeight uint64
+	interval    time.Duration
+}
+
+// New creates a new indexer which starts indexing at the given height, unless the
+// register index already contains blocks, in which case it resumes after the latest one.
+func New(
+	log zerolog.Logger,
+	metrics module.ExecutionStateIndexerMetrics,
+	state protocol.State,
+	headers storage.Headers,
+	registers storage.RegisterIndex,
+	source ExecutionDataSource,
+	startHeight uint64,
+	interval time.Duration,
+) *Indexer {
+	return &Indexer{
+		unit:        engine.NewUnit(),
+		log:         log.With().Str("engine", "state_indexer").Logger(),
+		metrics:     metrics,
+		state:       state,
+		headers:     headers,
+		registers:   registers,
+		source:      source,
+		startHeight: startHeight,
+		interval:    interval,
+	}
+}
+
+// Ready returns a ready channel that is closed once the indexer has started
+// tailing sealed blocks.
+func (i *Indexer) Ready() <-chan struct{} {
+	i.unit.LaunchPeriodically(i.indexSealed, i.interval, 0)
+	return i.unit.Ready()
+}
+
+// Done returns a done channel that is closed once the indexer has stopped.
+func (i *Indexer) Done() <-chan struct{} {
+	return i.unit.Done()
+}
+
+// GetRegisterAtHeight returns the value of the given register at the given height.
+// It returns ErrHeightNotIndexed if the height is outside of the indexed range.
+// Registers which were never written have an empty value.
+func (i *Indexer) GetRegisterAtHeight(registerID flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+	if !i.IsIndexed(height) {
+		return nil, fmt.Errorf("could not read register at height %d: %w", height, ErrHeightNotIndexed)
+	}
+
+	value, err := i.registers.ValueAtHeight(registerID, height)
+	if errors.Is(err, storage.ErrNotFound) {
+		return flow.RegisterValue{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read register (%s) at height %d: %w", registerID.String(), height, err)
+	}
+
+	return value, nil
+}
+
+// IsIndexed returns whether the given height is covered by the register index.
+func (i *Indexer) IsIndexed(height uint64) bool {
+	if height < i.startHeight {
+		return false
+	}
+	latest, err := i.registers.LatestHeight()
+	if err != nil {
+		return false
+	}
+	return height <= latest
+}
+
+// indexSealed indexes all sealed blocks which have not been indexed yet and
+// reports how far the index lags behind the sealed height.
+func (i *Indexer) indexSealed() {
+	sealed, err := i.state.Sealed().Head()
+	if err != nil {
+		i.log.Error().Err(err).Msg("could not get sealed head")
+		return
+	}
+
+	next, err := i.nextHeight()
+	if err != nil {
+		i.log.Error().Err(err).Msg("could not get next height to index")
+		return
+	}
+
+	for ; next <= sealed.Height; next++ {
+		select {
+		case <-i.unit.Quit():
+			return
+		default:
+		}
+
+		err := i.indexHeight(next)
+		if errors.Is(err, ErrExecutionDataNotAvailable) {
+			i.log.Debug().Uint64("height", next).Msg("execution data not available yet")
+			break
+		}
+		if err != nil {
+			i.log.Error().Err(err).Uint64("height", next).Msg("could not index block")
+			break
+		}
+	}
+
+	// next is the first height which is not indexed
+	var lag uint64
+	if sealed.Height >= next {
+		lag = sealed.Height - next + 1
+	}
+	i.metrics.IndexerLag(lag)
+}
+
+// indexHeight adds the register updates of the block at the given height to the index.
+func (i *Indexer) indexHeight(height uint64) error {
+	start := time.Now()
+
+	header, err := i.headers.ByHeight(height)
+	if err != nil {
+		return fmt.Errorf("could not get header: %w", err)
+	}
+	blockID := header.ID()
+
+	data, err := i.source.ExecutionData(i.unit.Ctx(), blockID)
+	if err != nil {
+		return fmt.Errorf("could not get execution data for block (%x): %w", blockID, err)
+	}
+
+	err = i.registers.Store(height, data.RegisterUpdates)
+	if err != nil {
+		return fmt.Errorf("could not index registers of block (%x): %w", blockID, err)
+	}
+
+	i.metrics.BlockIndexed(height, len(data.RegisterUpdates), time.Since(start))
+
+	i.log.Debug().
+		Uint64("height", height).
+		Hex("block_id", blockID[:]).
+		Int("registers", len(data.RegisterUpdates)).
+		Msg("block indexed")
+
+	return nil
+}
+
+// nextHeight returns the height of the next block to index.
+func (i *Indexer) nextHeight() (uint64, error) {
+	latest, err := i.registers.LatestHeight()
+	if errors.Is(err, storage.ErrNotFound) {
+		return i.startHeight, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return latest + 1, nil
+}
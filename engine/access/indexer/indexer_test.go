@@ -0,0 +1,140 @@
+package indexer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/access/indexer"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	metricsmock "github.com/onflow/flow-go/module/mock"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// executionDataSource serves execution data from memory
+type executionDataSource struct {
+	data map[flow.Identifier]*indexer.ExecutionData
+}
+
+func (s *executionDataSource) ExecutionData(_ context.Context, blockID flow.Identifier) (*indexer.ExecutionData, error) {
+	data, ok := s.data[blockID]
+	if !ok {
+		return nil, indexer.ErrExecutionDataNotAvailable
+	}
+	return data, nil
+}
+
+func TestIndexer(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		reg := flow.NewRegisterID("owner", "controller", "key")
+
+		// build a chain of blocks, with execution data available for the first three blocks only
+		headers := new(storagemock.Headers)
+		source := &executionDataSource{data: make(map[flow.Identifier]*indexer.ExecutionData)}
+		parent := unittest.BlockHeaderFixture()
+		parent.Height = 100
+		blocks := unittest.ChainFixtureFrom(4, &parent)
+		for i, block := range blocks {
+			header := block.Header
+			headers.On("ByHeight", header.Height).Return(header, nil)
+			if i < 3 {
+				source.data[header.ID()] = &indexer.ExecutionData{
+					BlockID:         header.ID(),
+					RegisterUpdates: flow.RegisterEntries{{Key: reg, Value: []byte{byte(i)}}},
+				}
+			}
+		}
+		first := blocks[0].Header.Height
+		last := blocks[len(blocks)-1].Header.Height
+
+		snapshot := new(protocol.Snapshot)
+		snapshot.On("Head").Return(blocks[len(blocks)-1].Header, nil)
+		state := new(protocol.State)
+		state.On("Sealed").Return(snapshot)
+
+		idx := indexer.New(
+			unittest.Logger(),
+			metrics.NewNoopCollector(),
+			state,
+			headers,
+			bstorage.NewRegisterIndex(db),
+			source,
+			first,
+			10*time.Millisecond,
+		)
+
+		unittest.AssertClosesBefore(t, idx.Ready(), time.Second)
+		defer unittest.AssertClosesBefore(t, idx.Done(), time.Second)
+
+		require.Eventually(t, func() bool {
+			return idx.IsIndexed(first + 2)
+		}, time.Second, 10*time.Millisecond)
+
+		// the last sealed block has no execution data yet, so it can not be indexed
+		assert.False(t, idx.IsIndexed(last))
+
+		value, err := idx.GetRegisterAtHeight(reg, first+1)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue{1}, value)
+
+		// registers which were never written are empty
+		value, err = idx.GetRegisterAtHeight(flow.NewRegisterID("owner", "controller", "other"), first+1)
+		require.NoError(t, err)
+		assert.Empty(t, value)
+
+		_, err = idx.GetRegisterAtHeight(reg, last)
+		assert.True(t, errors.Is(err, indexer.ErrHeightNotIndexed))
+
+		_, err = idx.GetRegisterAtHeight(reg, first-1)
+		assert.True(t, errors.Is(err, indexer.ErrHeightNotIndexed))
+	})
+}
+
+func TestIndexer_LagMetrics(t *testing.T) {
+	registers := new(storagemock.RegisterIndex)
+	registers.On("LatestHeight").Return(uint64(0), storage.ErrNotFound)
+
+	header := unittest.BlockHeaderFixture()
+	header.Height = 5
+	snapshot := new(protocol.Snapshot)
+	snapshot.On("Head").Return(&header, nil)
+	state := new(protocol.State)
+	state.On("Sealed").Return(snapshot)
+
+	headers := new(storagemock.Headers)
+	headers.On("ByHeight", mock.Anything).Return(&header, nil)
+
+	source := &executionDataSource{data: make(map[flow.Identifier]*indexer.ExecutionData)}
+
+	// no execution data is available, so all blocks starting at height 3 lag behind
+	lagReported := make(chan uint64, 1)
+	collector := new(metricsmock.ExecutionStateIndexerMetrics)
+	collector.On("IndexerLag", mock.Anything).Run(func(args mock.Arguments) {
+		select {
+		case lagReported <- args.Get(0).(uint64):
+		default:
+		}
+	})
+
+	idx := indexer.New(unittest.Logger(), collector, state, headers, registers, source, 3, 10*time.Millisecond)
+	unittest.AssertClosesBefore(t, idx.Ready(), time.Second)
+	defer unittest.AssertClosesBefore(t, idx.Done(), time.Second)
+
+	select {
+	case lag := <-lagReported:
+		assert.Equal(t, uint64(3), lag)
+	case <-time.After(time.Second):
+		t.Fatal("lag was not reported")
+	}
+}
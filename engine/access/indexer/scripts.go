@@ -0,0 +1,99 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/seed"
+	"github.com/onflow/flow-go/storage"
+)
+
+// VirtualMachine runs procedures
+type VirtualMachine interface {
+	Run(fvm.Context, fvm.Procedure, state.View, *programs.Programs) error
+}
+
+// ScriptExecutor executes scripts locally, reading the execution state from the register index.
+type ScriptExecutor struct {
+	vm      VirtualMachine
+	vmCtx   fvm.Context
+	headers storage.Headers
+	indexer *Indexer
+}
+
+// NewScriptExecutor creates a new script executor reading registers from the given indexer.
+func NewScriptExecutor(vm VirtualMachine, vmCtx fvm.Context, headers storage.Headers, indexer *Indexer) *ScriptExecutor {
+	return &ScriptExecutor{
+		vm:      vm,
+		vmCtx:   vmCtx,
+		headers: headers,
+		indexer: indexer,
+	}
+}
+
+// CanExecuteAtHeight returns whether the execution state at the given height is indexed.
+func (s *ScriptExecutor) CanExecuteAtHeight(height uint64) bool {
+	return s.indexer.IsIndexed(height)
+}
+
+// ExecuteScriptAtBlockID executes the script against the execution state at the given block.
+func (s *ScriptExecutor) ExecuteScriptAtBlockID(ctx context.Context, blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, error) {
+	header, err := s.headers.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get header: %w", err)
+	}
+	return s.executeScript(header, script, arguments)
+}
+
+// ExecuteScriptAtBlockHeight executes the script against the execution state at the given height.
+func (s *ScriptExecutor) ExecuteScriptAtBlockHeight(ctx context.Context, height uint64, script []byte, arguments [][]byte) ([]byte, error) {
+	header, err := s.headers.ByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("could not get header: %w", err)
+	}
+	return s.executeScript(header, script, arguments)
+}
+
+func (s *ScriptExecutor) executeScript(header *flow.Header, code []byte, arguments [][]byte) ([]byte, error) {
+	if !s.indexer.IsIndexed(header.Height) {
+		return nil, fmt.Errorf("could not execute script at height %d: %w", header.Height, ErrHeightNotIndexed)
+	}
+
+	view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		return s.indexer.GetRegisterAtHeight(flow.NewRegisterID(owner, controller, key), header.Height)
+	})
+
+	blockOpts := []fvm.Option{fvm.WithBlockHeader(header)}
+
+	// as on execution nodes, scripts draw from the random source of their block when it can be derived
+	randomSource, err := seed.ExecutionRandomSource(header)
+	if err == nil {
+		blockOpts = append(blockOpts, fvm.WithBlockRandomSource(randomSource))
+	}
+
+	blockCtx := fvm.NewContextFromParent(s.vmCtx, blockOpts...)
+	script := fvm.Script(code).WithArguments(arguments...)
+
+	err = s.vm.Run(blockCtx, script, view, programs.NewEmptyPrograms())
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute script (internal error): %w", err)
+	}
+
+	if script.Err != nil {
+		return nil, fmt.Errorf("failed to execute script at block (%s): %s", header.ID(), script.Err.Error())
+	}
+
+	encodedValue, err := jsoncdc.Encode(script.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode runtime value: %w", err)
+	}
+
+	return encodedValue, nil
+}
@@ -0,0 +1,319 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/engine/execution/statesync"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// StateDeltaSource provides the execution data of sealed blocks from the state deltas served by
+// the state sync engines of execution nodes. When the execution data of a block is not available,
+// the state deltas of a range of blocks starting at it are requested from an execution node.
+//
+// The state deltas are checked against the sealed results of their blocks, which commit to their
+// start and end states and to their events. The register updates can not be checked without the
+// execution state, so, as for the scripts executed by them, the execution nodes the state deltas
+// are requested from are trusted to serve correct register updates.
+type StateDeltaSource struct {
+	unit          *engine.Unit
+	log           zerolog.Logger
+	me            module.Local
+	state         protocol.State
+	seals         storage.Seals
+	results       storage.ExecutionResults
+	con           network.Conduit
+	syncFilter    flow.IdentityFilter // filter of the execution nodes to request state deltas from
+	maxRange      uint64
+	retryInterval time.Duration
+
+	mu          sync.Mutex
+	data        map[flow.Identifier]*receivedData // execution data of the requested blocks, until it is indexed
+	fromHeight  uint64                            // first height of the latest request
+	toHeight    uint64                            // last height of the latest request
+	requestedAt time.Time
+}
+
+// receivedData is the execution data of a block received in a state delta
+type receivedData struct {
+	*ExecutionData
+	height uint64
+}
+
+var _ ExecutionDataSource = (*StateDeltaSource)(nil)
+
+// NewStateDeltaSource creates a source requesting the state deltas of up to maxRange blocks at
+// once from the execution nodes matching the filter. A request is repeated after the retry
+// interval, if the state delta of the block to index is still missing.
+func NewStateDeltaSource(
+	log zerolog.Logger,
+	net module.Network,
+	me module.Local,
+	state protocol.State,
+	seals storage.Seals,
+	results storage.ExecutionResults,
+	syncFilter flow.IdentityFilter,
+	maxRange uint64,
+	retryInterval time.Duration,
+) (*StateDeltaSource, error) {
+
+	s := &StateDeltaSource{
+		unit:          engine.NewUnit(),
+		log:           log.With().Str("engine", "state_delta_source").Logger(),
+		me:            me,
+		state:         state,
+		seals:         seals,
+		results:       results,
+		syncFilter:    syncFilter,
+		maxRange:      maxRange,
+		retryInterval: retryInterval,
+		data:          make(map[flow.Identifier]*receivedData),
+	}
+
+	con, err := net.Register(engine.SyncExecution, s)
+	if err != nil {
+		return nil, fmt.Errorf("could not register state delta source: %w", err)
+	}
+	s.con = con
+
+	return s, nil
+}
+
+// Ready returns a channel that will close when the engine has
+// successfully started.
+func (s *StateDeltaSource) Ready() <-chan struct{} {
+	return s.unit.Ready()
+}
+
+// Done returns a channel that will close when the engine has
+// successfully stopped.
+func (s *StateDeltaSource) Done() <-chan struct{} {
+	return s.unit.Done()
+}
+
+// ExecutionData returns the execution data of the given sealed block, once its state delta was
+// received. Otherwise, it requests the state deltas of the block and its descendants, unless they
+// were requested within the retry interval, and returns ErrExecutionDataNotAvailable.
+func (s *StateDeltaSource) ExecutionData(_ context.Context, blockID flow.Identifier) (*ExecutionData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// blocks are indexed only once, so their data is dropped once it was handed out
+	received, ok := s.data[blockID]
+	if ok {
+		delete(s.data, blockID)
+		return received.ExecutionData, nil
+	}
+
+	header, err := s.state.AtBlockID(blockID).Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get header: %w", err)
+	}
+	requested := header.Height >= s.fromHeight && header.Height <= s.toHeight
+	if requested && time.Since(s.requestedAt) < s.retryInterval {
+		return nil, ErrExecutionDataNotAvailable
+	}
+
+	err = s.request(header.Height)
+	if err != nil {
+		return nil, fmt.Errorf("could not request state deltas: %w", err)
+	}
+
+	return nil, ErrExecutionDataNotAvailable
+}
+
+// request requests the state deltas of up to maxRange sealed blocks, starting at the given height,
+// from an execution node.
+func (s *StateDeltaSource) request(fromHeight uint64) error {
+	sealed, err := s.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not get sealed block: %w", err)
+	}
+	toHeight := sealed.Height
+	if toHeight < fromHeight || toHeight-fromHeight >= s.maxRange {
+		toHeight = fromHeight + s.maxRange - 1
+	}
+
+	peers, err := s.state.Final().Identities(filter.And(
+		filter.HasRole(flow.RoleExecution),
+		filter.HasStake(true),
+		s.syncFilter,
+	))
+	if err != nil {
+		return fmt.Errorf("could not get execution nodes: %w", err)
+	}
+	if len(peers) == 0 {
+		return fmt.Errorf("no execution node to request state deltas from")
+	}
+	target := peers.Sample(1)[0]
+
+	req := &messages.ExecutionStateSyncRequest{
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	}
+	err = s.con.Unicast(req, target.NodeID)
+	if err != nil {
+		return fmt.Errorf("could not send state sync request: %w", err)
+	}
+
+	// the data of blocks outside of the new range is not requested anymore
+	for blockID, received := range s.data {
+		if received.height < fromHeight || received.height > toHeight {
+			delete(s.data, blockID)
+		}
+	}
+	s.fromHeight = fromHeight
+	s.toHeight = toHeight
+	s.requestedAt = time.Now()
+
+	s.log.Debug().
+		Hex("target_id", logging.ID(target.NodeID)).
+		Uint64("from_height", fromHeight).
+		Uint64("to_height", toHeight).
+		Msg("requested state deltas")
+
+	return nil
+}
+
+// SubmitLocal submits an event originating on the local node.
+func (s *StateDeltaSource) SubmitLocal(event interface{}) {
+	s.Submit(s.me.NodeID(), event)
+}
+
+// Submit submits the given event from the node with the given origin ID
+// for processing in a non-blocking manner. It returns instantly and logs
+// a potential processing error internally when done.
+func (s *StateDeltaSource) Submit(originID flow.Identifier, event interface{}) {
+	s.unit.Launch(func() {
+		err := s.Process(originID, event)
+		if err != nil {
+			engine.LogError(s.log, err)
+		}
+	})
+}
+
+// ProcessLocal processes an event originating on the local node.
+func (s *StateDeltaSource) ProcessLocal(event interface{}) error {
+	return s.Process(s.me.NodeID(), event)
+}
+
+// Process processes the given event from the node with the given origin ID in
+// a blocking manner. It returns the potential processing error when done.
+func (s *StateDeltaSource) Process(originID flow.Identifier, event interface{}) error {
+	return s.unit.Do(func() error {
+		return s.process(originID, event)
+	})
+}
+
+func (s *StateDeltaSource) process(originID flow.Identifier, event interface{}) error {
+	switch v := event.(type) {
+	case *messages.ExecutionStateDelta:
+		err := s.onStateDelta(originID, v)
+		if err != nil {
+			return fmt.Errorf("could not handle state delta: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid event type (%T)", event)
+	}
+}
+
+// onStateDelta validates the state delta of a requested block against the sealed result of the
+// block, and holds the register updates of the block until it is indexed.
+func (s *StateDeltaSource) onStateDelta(originID flow.Identifier, stateDelta *messages.ExecutionStateDelta) error {
+	identity, err := s.state.Final().Identity(originID)
+	if protocol.IsIdentityNotFound(err) {
+		return engine.NewInvalidInputErrorf("unknown origin %x", originID)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get identity of origin %x: %w", originID, err)
+	}
+	if identity.Role != flow.RoleExecution || identity.Stake == 0 || !s.syncFilter(identity) {
+		return engine.NewInvalidInputErrorf("origin %x is not a trusted execution node (role: %s)", originID, identity.Role)
+	}
+
+	header := stateDelta.Block.Header
+	blockID := stateDelta.ID()
+
+	s.mu.Lock()
+	requested := header.Height >= s.fromHeight && header.Height <= s.toHeight
+	s.mu.Unlock()
+	if !requested {
+		s.log.Debug().
+			Hex("origin_id", logging.ID(originID)).
+			Uint64("block_height", header.Height).
+			Msg("dropping state delta of block which was not requested")
+		return nil
+	}
+
+	finalized, err := s.state.AtHeight(header.Height).Head()
+	if err != nil {
+		return fmt.Errorf("could not get finalized block at height %d: %w", header.Height, err)
+	}
+	if finalized.ID() != blockID {
+		return engine.NewInvalidInputErrorf("state delta of block %x not finalized at height %d", blockID, header.Height)
+	}
+
+	seal, err := s.seals.FinalizedSealForBlock(blockID)
+	if err != nil {
+		return fmt.Errorf("could not get seal of block %x: %w", blockID, err)
+	}
+	result, err := s.results.ByID(seal.ResultID)
+	if err != nil {
+		return fmt.Errorf("could not get sealed result %x: %w", seal.ResultID, err)
+	}
+
+	err = statesync.ValidateStateDelta(stateDelta, result)
+	if err != nil {
+		return engine.NewInvalidInputErrorf("invalid state delta of block %x: %v", blockID, err)
+	}
+
+	// the updates of later chunks overwrite the updates of earlier chunks to the same registers
+	updates := delta.NewDelta()
+	for _, snapshot := range stateDelta.StateInteractions {
+		updates.MergeWith(snapshot.Delta)
+	}
+	ids, values := updates.RegisterUpdates()
+	entries := make(flow.RegisterEntries, 0, len(ids))
+	for i, id := range ids {
+		entries = append(entries, flow.RegisterEntry{Key: id, Value: values[i]})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the range might have moved on while the state delta was validated
+	if header.Height < s.fromHeight || header.Height > s.toHeight {
+		return nil
+	}
+	s.data[blockID] = &receivedData{
+		ExecutionData: &ExecutionData{
+			BlockID:         blockID,
+			RegisterUpdates: entries,
+		},
+		height: header.Height,
+	}
+
+	s.log.Debug().
+		Hex("origin_id", logging.ID(originID)).
+		Hex("block_id", blockID[:]).
+		Uint64("block_height", header.Height).
+		Int("registers", len(entries)).
+		Msg("state delta received")
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package indexer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/indexer"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	mockprotocol "github.com/onflow/flow-go/state/protocol/mock"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// stateDeltaFixture is an access node requesting the state delta of a sealed block, whose sealed
+// result has two chunks, from an execution node.
+type stateDeltaFixture struct {
+	source     *indexer.StateDeltaSource
+	con        *mocknetwork.Conduit
+	exe        *flow.Identity
+	other      *flow.Identity
+	block      *flow.Block
+	stateDelta *messages.ExecutionStateDelta
+}
+
+func newStateDeltaFixture(t *testing.T) *stateDeltaFixture {
+	parent := unittest.BlockHeaderFixture()
+	block := unittest.BlockWithParentFixture(&parent)
+	block.SetPayload(flow.EmptyPayload())
+	result := unittest.ExecutionResultFixture(unittest.WithBlock(&block), unittest.WithChunks(2))
+	result.Chunks[1].StartState = result.Chunks[0].EndState
+	for _, chunk := range result.Chunks {
+		chunk.EventCollection = flow.EventsList{}.Hash()
+	}
+	seal := &flow.Seal{BlockID: block.ID(), ResultID: result.ID()}
+
+	// both chunks write the same register, the update of the second chunk is the final value
+	first := delta.NewDelta()
+	first.Set("owner", "controller", "a", []byte("1"))
+	first.Set("owner", "controller", "b", []byte("2"))
+	second := delta.NewDelta()
+	second.Set("owner", "controller", "a", []byte("3"))
+
+	startState := result.Chunks[0].StartState
+	stateDelta := &messages.ExecutionStateDelta{
+		ExecutableBlock: entity.ExecutableBlock{
+			Block:               &block,
+			StartState:          &startState,
+			CompleteCollections: map[flow.Identifier]*entity.CompleteCollection{},
+		},
+		StateInteractions: []*delta.Snapshot{{Delta: first}, {Delta: second}},
+		EndState:          result.Chunks[1].EndState,
+	}
+
+	exe := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+	other := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+	me := unittest.IdentityFixture(unittest.WithRole(flow.RoleAccess))
+
+	ps := new(mockprotocol.State)
+	final := new(mockprotocol.Snapshot)
+	final.On("Identity", exe.NodeID).Return(exe, nil)
+	final.On("Identity", other.NodeID).Return(other, nil)
+	final.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return flow.IdentityList{exe, other}.Filter(selector)
+		},
+		nil,
+	)
+	ps.On("Final").Return(final)
+	sealed := new(mockprotocol.Snapshot)
+	sealed.On("Head").Return(block.Header, nil)
+	ps.On("Sealed").Return(sealed)
+	ps.On("AtHeight", block.Header.Height).Return(sealed)
+	ps.On("AtBlockID", block.ID()).Return(sealed)
+
+	seals := new(storagemock.Seals)
+	seals.On("FinalizedSealForBlock", block.ID()).Return(seal, nil)
+	results := new(storagemock.ExecutionResults)
+	results.On("ByID", result.ID()).Return(result, nil)
+
+	local := new(mockmodule.Local)
+	local.On("NodeID").Return(me.NodeID)
+
+	con := new(mocknetwork.Conduit)
+	net := new(mockmodule.Network)
+	net.On("Register", engine.SyncExecution, mock.Anything).Return(con, nil)
+
+	// only the first execution node is trusted
+	source, err := indexer.NewStateDeltaSource(
+		unittest.Logger(),
+		net,
+		local,
+		ps,
+		seals,
+		results,
+		filter.HasNodeID(exe.NodeID),
+		10,
+		time.Hour,
+	)
+	require.NoError(t, err)
+
+	return &stateDeltaFixture{
+		source:     source,
+		con:        con,
+		exe:        exe,
+		other:      other,
+		block:      &block,
+		stateDelta: stateDelta,
+	}
+}
+
+// request requests the execution data of the block, which is not received yet
+func (f *stateDeltaFixture) request(t *testing.T) {
+	req := &messages.ExecutionStateSyncRequest{FromHeight: f.block.Header.Height, ToHeight: f.block.Header.Height}
+	f.con.On("Unicast", req, f.exe.NodeID).Return(nil).Once()
+
+	_, err := f.source.ExecutionData(context.Background(), f.block.ID())
+	require.True(t, errors.Is(err, indexer.ErrExecutionDataNotAvailable))
+
+	f.con.AssertExpectations(t)
+}
+
+func TestStateDeltaSource(t *testing.T) {
+
+	t.Run("requests missing state deltas from trusted execution nodes", func(t *testing.T) {
+		f := newStateDeltaFixture(t)
+		f.request(t)
+
+		// the request is not repeated within the retry interval
+		_, err := f.source.ExecutionData(context.Background(), f.block.ID())
+		require.True(t, errors.Is(err, indexer.ErrExecutionDataNotAvailable))
+		f.con.AssertNumberOfCalls(t, "Unicast", 1)
+	})
+
+	t.Run("serves register updates of requested state deltas", func(t *testing.T) {
+		f := newStateDeltaFixture(t)
+		f.request(t)
+
+		err := f.source.Process(f.exe.NodeID, f.stateDelta)
+		require.NoError(t, err)
+
+		data, err := f.source.ExecutionData(context.Background(), f.block.ID())
+		require.NoError(t, err)
+		assert.Equal(t, f.block.ID(), data.BlockID)
+		assert.Equal(t, flow.RegisterEntries{
+			{Key: flow.NewRegisterID("owner", "controller", "a"), Value: []byte("3")},
+			{Key: flow.NewRegisterID("owner", "controller", "b"), Value: []byte("2")},
+		}, data.RegisterUpdates)
+	})
+
+	t.Run("drops state deltas which were not requested", func(t *testing.T) {
+		f := newStateDeltaFixture(t)
+
+		err := f.source.Process(f.exe.NodeID, f.stateDelta)
+		require.NoError(t, err)
+
+		f.request(t)
+	})
+
+	t.Run("rejects state deltas of untrusted execution nodes", func(t *testing.T) {
+		f := newStateDeltaFixture(t)
+		f.request(t)
+
+		err := f.source.Process(f.other.NodeID, f.stateDelta)
+		require.True(t, engine.IsInvalidInputError(err))
+	})
+
+	t.Run("rejects state deltas contradicting the sealed result", func(t *testing.T) {
+		f := newStateDeltaFixture(t)
+		f.request(t)
+
+		f.stateDelta.EndState = unittest.StateCommitmentFixture()
+		err := f.source.Process(f.exe.NodeID, f.stateDelta)
+		require.True(t, engine.IsInvalidInputError(err))
+	})
+}
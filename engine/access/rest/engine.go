@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// DefaultRequestTimeout is the default timeout of the requests served by the REST API.
+const DefaultRequestTimeout = 30 * time.Second
+
+// Engine serves the Access API as a REST/JSON API alongside the gRPC API of access nodes. The routes are
+// described by the OpenAPI specification served at /v1/openapi.json.
+type Engine struct {
+	unit       *engine.Unit
+	log        zerolog.Logger
+	httpServer *http.Server
+}
+
+// New returns a new REST engine, which serves the given API on the given address.
+func New(log zerolog.Logger, api access.API, chain flow.Chain, address string, timeout time.Duration) *Engine {
+	log = log.With().Str("engine", "rest").Logger()
+
+	return &Engine{
+		unit: engine.NewUnit(),
+		log:  log,
+		httpServer: &http.Server{
+			Addr:         address,
+			Handler:      http.TimeoutHandler(NewRouter(log, api, chain), timeout, timeoutMessage),
+			ReadTimeout:  timeout,
+			WriteTimeout: timeout + time.Second, // leaves time for the timeout response
+		},
+	}
+}
+
+// Ready returns a ready channel that is closed once the engine has started serving.
+func (e *Engine) Ready() <-chan struct{} {
+	e.unit.Launch(e.serve)
+	return e.unit.Ready()
+}
+
+// Done returns a done channel that is closed once the engine has stopped serving.
+func (e *Engine) Done() <-chan struct{} {
+	return e.unit.Done(func() {
+		err := e.httpServer.Shutdown(context.Background())
+		if err != nil {
+			e.log.Error().Err(err).Msg("error stopping rest server")
+		}
+	})
+}
+
+// serve starts the REST server
+func (e *Engine) serve() {
+	log := e.log.With().Str("rest_address", e.httpServer.Addr).Logger()
+
+	log.Info().Msg("starting rest server on address")
+
+	err := e.httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return
+	}
+	if err != nil {
+		log.Err(err).Msg("failed to start the rest server")
+	}
+}
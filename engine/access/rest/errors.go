@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// timeoutMessage is the response body of requests which time out, see http.TimeoutHandler.
+var timeoutMessage = fmt.Sprintf(`{"code":%d,"message":"request timed out"}`, http.StatusServiceUnavailable)
+
+// Error is the response body of failed requests.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// statusCode maps the gRPC status errors returned by the Access API backend, and by the validation of
+// requests, to the HTTP status codes of the REST API, so that both APIs report the same errors.
+func statusCode(err error) int {
+	st, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded, codes.Canceled:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorMessage returns the message of the error reported to clients. Internal errors are reported
+// with their messages, as the gRPC API does.
+func errorMessage(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err.Error()
+	}
+	return st.Message()
+}
+
+// invalidArgument returns an error for invalid requests.
+func invalidArgument(format string, args ...interface{}) error {
+	return status.Errorf(codes.InvalidArgument, format, args...)
+}
+
+// notFound returns an error for requests which match no route.
+func notFound(r *http.Request) error {
+	return status.Errorf(codes.NotFound, "no route for %s %s", r.Method, r.URL.Path)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code := statusCode(err)
+	writeJSON(w, code, Error{
+		Code:    code,
+		Message: errorMessage(err),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,282 @@
+package rest
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	grpcutils "github.com/onflow/flow-go/utils/grpc"
+)
+
+// handlers implements the routes of the REST API on top of the Access API, which validates requests
+// and reports errors the same way as for the gRPC API.
+type handlers struct {
+	api   access.API
+	chain flow.Chain
+}
+
+func (h *handlers) getNetworkParameters(r *request) (interface{}, error) {
+	params := h.api.GetNetworkParameters(r.Context())
+	return NetworkParameters{
+		ChainID: params.ChainID.String(),
+	}, nil
+}
+
+func (h *handlers) getLatestBlock(r *request) (interface{}, error) {
+	sealed := true
+	if raw := r.query("sealed"); raw != "" {
+		var err error
+		sealed, err = strconv.ParseBool(raw)
+		if err != nil {
+			return nil, invalidArgument("invalid sealed %q: must be a boolean", raw)
+		}
+	}
+
+	block, err := h.api.GetLatestBlock(r.Context(), sealed)
+	if err != nil {
+		return nil, err
+	}
+	return blockFromFlow(block), nil
+}
+
+func (h *handlers) getBlockByHeight(r *request) (interface{}, error) {
+	height, ok, err := r.uintQuery("height")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, invalidArgument("missing height")
+	}
+
+	block, err := h.api.GetBlockByHeight(r.Context(), height)
+	if err != nil {
+		return nil, err
+	}
+	return blockFromFlow(block), nil
+}
+
+func (h *handlers) getBlockByID(r *request) (interface{}, error) {
+	blockID, err := identifier("block id", r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := h.api.GetBlockByID(r.Context(), blockID)
+	if err != nil {
+		return nil, err
+	}
+	return blockFromFlow(block), nil
+}
+
+func (h *handlers) getTransactionResultsByBlockID(r *request) (interface{}, error) {
+	blockID, err := identifier("block id", r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+	offset, limit, err := r.page()
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := h.api.GetTransactionResultsByBlockID(r.Context(), blockID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]TransactionResult, 0, len(results))
+	for _, result := range results {
+		items = append(items, transactionResultFromAccess(result))
+	}
+	return newPage(items, uint(len(items)), offset, limit), nil
+}
+
+func (h *handlers) getCollectionByID(r *request) (interface{}, error) {
+	collectionID, err := identifier("collection id", r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := h.api.GetCollectionByID(r.Context(), collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return collectionFromFlow(collectionID, collection), nil
+}
+
+func (h *handlers) sendTransaction(r *request) (interface{}, error) {
+	var tx Transaction
+	err := decodeBody(r, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := transactionToFlow(tx, h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.api.SendTransaction(r.Context(), body)
+	if err != nil {
+		return nil, err
+	}
+	return TransactionID{ID: body.ID().String()}, nil
+}
+
+func (h *handlers) getTransactionByID(r *request) (interface{}, error) {
+	txID, err := identifier("transaction id", r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := h.api.GetTransaction(r.Context(), txID)
+	if err != nil {
+		return nil, err
+	}
+	return transactionFromFlow(tx), nil
+}
+
+func (h *handlers) getTransactionResult(r *request) (interface{}, error) {
+	txID, err := identifier("transaction id", r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.api.GetTransactionResult(r.Context(), txID)
+	if err != nil {
+		return nil, err
+	}
+	return transactionResultFromAccess(result), nil
+}
+
+func (h *handlers) getAccount(r *request) (interface{}, error) {
+	addr, err := address("address", r.param("address"), h.chain)
+	if err != nil {
+		return nil, err
+	}
+	height, atHeight, err := r.uintQuery("block_height")
+	if err != nil {
+		return nil, err
+	}
+
+	var account *flow.Account
+	if atHeight {
+		account, err = h.api.GetAccountAtBlockHeight(r.Context(), addr, height)
+	} else {
+		account, err = h.api.GetAccountAtLatestBlock(r.Context(), addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return accountFromFlow(account), nil
+}
+
+func (h *handlers) executeScript(r *request) (interface{}, error) {
+	var script Script
+	err := decodeBody(r, &script)
+	if err != nil {
+		return nil, err
+	}
+
+	height, atHeight, err := r.uintQuery("block_height")
+	if err != nil {
+		return nil, err
+	}
+	rawBlockID := r.query("block_id")
+	if atHeight && rawBlockID != "" {
+		return nil, invalidArgument("block_id and block_height are mutually exclusive")
+	}
+
+	var value []byte
+	switch {
+	case rawBlockID != "":
+		blockID, err := identifier("block_id", rawBlockID)
+		if err != nil {
+			return nil, err
+		}
+		value, err = h.api.ExecuteScriptAtBlockID(r.Context(), blockID, script.Script, script.Arguments)
+		if err != nil {
+			return nil, err
+		}
+	case atHeight:
+		value, err = h.api.ExecuteScriptAtBlockHeight(r.Context(), height, script.Script, script.Arguments)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		value, err = h.api.ExecuteScriptAtLatestBlock(r.Context(), script.Script, script.Arguments)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ScriptResult{Value: value}, nil
+}
+
+// getEvents returns the events of a type in a height range, or in a list of blocks, grouped by block.
+// The pages are pages of blocks.
+func (h *handlers) getEvents(r *request) (interface{}, error) {
+	eventType := r.query("type")
+	if strings.TrimSpace(eventType) == "" {
+		return nil, invalidArgument("missing type")
+	}
+	offset, limit, err := r.page()
+	if err != nil {
+		return nil, err
+	}
+
+	startHeight, hasStart, err := r.uintQuery("start_height")
+	if err != nil {
+		return nil, err
+	}
+	endHeight, hasEnd, err := r.uintQuery("end_height")
+	if err != nil {
+		return nil, err
+	}
+	rawBlockIDs := r.query("block_ids")
+
+	var blockEvents []flow.BlockEvents
+	switch {
+	case rawBlockIDs != "" && (hasStart || hasEnd):
+		return nil, invalidArgument("block_ids and height ranges are mutually exclusive")
+	case rawBlockIDs != "":
+		var blockIDs []flow.Identifier
+		for _, raw := range strings.Split(rawBlockIDs, ",") {
+			blockID, err := identifier("block_ids", raw)
+			if err != nil {
+				return nil, err
+			}
+			blockIDs = append(blockIDs, blockID)
+		}
+		blockEvents, err = h.api.GetEventsForBlockIDs(r.Context(), eventType, blockIDs)
+		if err != nil {
+			return nil, err
+		}
+	case hasStart && hasEnd:
+		blockEvents, err = h.api.GetEventsForHeightRange(r.Context(), eventType, startHeight, endHeight)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, invalidArgument("either block_ids, or start_height and end_height are required")
+	}
+
+	items := make([]BlockEvents, 0, limit)
+	for i := offset; i < uint(len(blockEvents)) && i < offset+limit; i++ {
+		items = append(items, blockEventsFromFlow(blockEvents[i]))
+	}
+	return newPage(items, uint(len(items)), offset, limit), nil
+}
+
+// decodeBody decodes the JSON body of the request, which is limited to the maximum message size of the
+// gRPC API.
+func decodeBody(r *request, body interface{}) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, grpcutils.DefaultMaxMsgSize))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(body)
+	if err != nil {
+		return invalidArgument("invalid request body: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package rest
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// The JSON models of the REST API. Identifiers, addresses and public keys are hex encoded, other binary
+// data, like scripts, arguments and event payloads, is base64 encoded.
+
+type NetworkParameters struct {
+	ChainID string `json:"chain_id"`
+}
+
+type BlockHeader struct {
+	ID          string    `json:"id"`
+	ParentID    string    `json:"parent_id"`
+	Height      uint64    `json:"height"`
+	View        uint64    `json:"view"`
+	Timestamp   time.Time `json:"timestamp"`
+	PayloadHash string    `json:"payload_hash"`
+	ProposerID  string    `json:"proposer_id"`
+}
+
+type CollectionGuarantee struct {
+	CollectionID string   `json:"collection_id"`
+	SignerIDs    []string `json:"signer_ids"`
+}
+
+type BlockSeal struct {
+	BlockID    string `json:"block_id"`
+	ResultID   string `json:"result_id"`
+	FinalState string `json:"final_state"`
+}
+
+type Block struct {
+	Header               BlockHeader           `json:"header"`
+	CollectionGuarantees []CollectionGuarantee `json:"collection_guarantees"`
+	Seals                []BlockSeal           `json:"seals"`
+}
+
+type Collection struct {
+	ID             string   `json:"id"`
+	TransactionIDs []string `json:"transaction_ids"`
+}
+
+type ProposalKey struct {
+	Address        string `json:"address"`
+	KeyIndex       uint64 `json:"key_index"`
+	SequenceNumber uint64 `json:"sequence_number"`
+}
+
+type TransactionSignature struct {
+	Address   string `json:"address"`
+	KeyIndex  uint64 `json:"key_index"`
+	Signature []byte `json:"signature"`
+}
+
+type Transaction struct {
+	ID                 string                 `json:"id,omitempty"` // ignored in sent transactions
+	Script             []byte                 `json:"script"`
+	Arguments          [][]byte               `json:"arguments"`
+	ReferenceBlockID   string                 `json:"reference_block_id"`
+	GasLimit           uint64                 `json:"gas_limit"`
+	ProposalKey        ProposalKey            `json:"proposal_key"`
+	Payer              string                 `json:"payer"`
+	Authorizers        []string               `json:"authorizers"`
+	PayloadSignatures  []TransactionSignature `json:"payload_signatures"`
+	EnvelopeSignatures []TransactionSignature `json:"envelope_signatures"`
+}
+
+type TransactionID struct {
+	ID string `json:"id"`
+}
+
+type Event struct {
+	Type             string `json:"type"`
+	TransactionID    string `json:"transaction_id"`
+	TransactionIndex uint32 `json:"transaction_index"`
+	EventIndex       uint32 `json:"event_index"`
+	Payload          []byte `json:"payload"`
+}
+
+type TransactionResult struct {
+	TransactionID string  `json:"transaction_id"`
+	BlockID       string  `json:"block_id"`
+	Status        string  `json:"status"`
+	StatusCode    uint    `json:"status_code"`
+	ErrorMessage  string  `json:"error_message"`
+	Events        []Event `json:"events"`
+}
+
+type AccountKey struct {
+	Index            int    `json:"index"`
+	PublicKey        string `json:"public_key"`
+	SigningAlgorithm string `json:"signing_algorithm"`
+	HashingAlgorithm string `json:"hashing_algorithm"`
+	SequenceNumber   uint64 `json:"sequence_number"`
+	Weight           int    `json:"weight"`
+	Revoked          bool   `json:"revoked"`
+}
+
+type Account struct {
+	Address   string            `json:"address"`
+	Balance   uint64            `json:"balance"`
+	Keys      []AccountKey      `json:"keys"`
+	Contracts map[string][]byte `json:"contracts"`
+}
+
+type Script struct {
+	Script    []byte   `json:"script"`
+	Arguments [][]byte `json:"arguments"`
+}
+
+type ScriptResult struct {
+	Value []byte `json:"value"`
+}
+
+type BlockEvents struct {
+	BlockID        string    `json:"block_id"`
+	BlockHeight    uint64    `json:"block_height"`
+	BlockTimestamp time.Time `json:"block_timestamp"`
+	Events         []Event   `json:"events"`
+}
+
+// Page is a page of the items of paginated routes. The next offset is set if there may be more items.
+type Page struct {
+	Items      interface{} `json:"items"`
+	NextOffset *uint       `json:"next_offset,omitempty"`
+}
+
+func newPage(items interface{}, count uint, offset uint, limit uint) Page {
+	page := Page{
+		Items: items,
+	}
+	if count == limit {
+		next := offset + limit
+		page.NextOffset = &next
+	}
+	return page
+}
+
+func blockHeaderFromFlow(header *flow.Header) BlockHeader {
+	return BlockHeader{
+		ID:          header.ID().String(),
+		ParentID:    header.ParentID.String(),
+		Height:      header.Height,
+		View:        header.View,
+		Timestamp:   header.Timestamp,
+		PayloadHash: header.PayloadHash.String(),
+		ProposerID:  header.ProposerID.String(),
+	}
+}
+
+func blockFromFlow(block *flow.Block) Block {
+	guarantees := make([]CollectionGuarantee, 0, len(block.Payload.Guarantees))
+	for _, guarantee := range block.Payload.Guarantees {
+		guarantees = append(guarantees, CollectionGuarantee{
+			CollectionID: guarantee.CollectionID.String(),
+			SignerIDs:    identifiersFromFlow(guarantee.SignerIDs),
+		})
+	}
+
+	seals := make([]BlockSeal, 0, len(block.Payload.Seals))
+	for _, seal := range block.Payload.Seals {
+		seals = append(seals, BlockSeal{
+			BlockID:    seal.BlockID.String(),
+			ResultID:   seal.ResultID.String(),
+			FinalState: hex.EncodeToString(seal.FinalState[:]),
+		})
+	}
+
+	return Block{
+		Header:               blockHeaderFromFlow(block.Header),
+		CollectionGuarantees: guarantees,
+		Seals:                seals,
+	}
+}
+
+func collectionFromFlow(id flow.Identifier, collection *flow.LightCollection) Collection {
+	return Collection{
+		ID:             id.String(),
+		TransactionIDs: identifiersFromFlow(collection.Transactions),
+	}
+}
+
+func transactionFromFlow(tx *flow.TransactionBody) Transaction {
+	authorizers := make([]string, 0, len(tx.Authorizers))
+	for _, authorizer := range tx.Authorizers {
+		authorizers = append(authorizers, authorizer.Hex())
+	}
+
+	return Transaction{
+		ID:               tx.ID().String(),
+		Script:           tx.Script,
+		Arguments:        tx.Arguments,
+		ReferenceBlockID: tx.ReferenceBlockID.String(),
+		GasLimit:         tx.GasLimit,
+		ProposalKey: ProposalKey{
+			Address:        tx.ProposalKey.Address.Hex(),
+			KeyIndex:       tx.ProposalKey.KeyIndex,
+			SequenceNumber: tx.ProposalKey.SequenceNumber,
+		},
+		Payer:              tx.Payer.Hex(),
+		Authorizers:        authorizers,
+		PayloadSignatures:  signaturesFromFlow(tx.PayloadSignatures),
+		EnvelopeSignatures: signaturesFromFlow(tx.EnvelopeSignatures),
+	}
+}
+
+func signaturesFromFlow(signatures []flow.TransactionSignature) []TransactionSignature {
+	converted := make([]TransactionSignature, 0, len(signatures))
+	for _, signature := range signatures {
+		converted = append(converted, TransactionSignature{
+			Address:   signature.Address.Hex(),
+			KeyIndex:  signature.KeyIndex,
+			Signature: signature.Signature,
+		})
+	}
+	return converted
+}
+
+// transactionToFlow converts a sent transaction, validating its addresses against the chain. The
+// signatures are added once all signers are set, so that their signer indices are assigned.
+func transactionToFlow(tx Transaction, chain flow.Chain) (*flow.TransactionBody, error) {
+	referenceBlockID, err := identifier("reference_block_id", tx.ReferenceBlockID)
+	if err != nil {
+		return nil, err
+	}
+	proposer, err := address("proposal_key.address", tx.ProposalKey.Address, chain)
+	if err != nil {
+		return nil, err
+	}
+	payer, err := address("payer", tx.Payer, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	body := flow.NewTransactionBody().
+		SetScript(tx.Script).
+		SetReferenceBlockID(referenceBlockID).
+		SetGasLimit(tx.GasLimit).
+		SetProposalKey(proposer, tx.ProposalKey.KeyIndex, tx.ProposalKey.SequenceNumber).
+		SetPayer(payer)
+	body.Arguments = tx.Arguments
+
+	for _, raw := range tx.Authorizers {
+		authorizer, err := address("authorizers", raw, chain)
+		if err != nil {
+			return nil, err
+		}
+		body.AddAuthorizer(authorizer)
+	}
+	for _, signature := range tx.PayloadSignatures {
+		signer, err := address("payload_signatures.address", signature.Address, chain)
+		if err != nil {
+			return nil, err
+		}
+		body.AddPayloadSignature(signer, signature.KeyIndex, signature.Signature)
+	}
+	for _, signature := range tx.EnvelopeSignatures {
+		signer, err := address("envelope_signatures.address", signature.Address, chain)
+		if err != nil {
+			return nil, err
+		}
+		body.AddEnvelopeSignature(signer, signature.KeyIndex, signature.Signature)
+	}
+
+	return body, nil
+}
+
+func eventsFromFlow(events []flow.Event) []Event {
+	converted := make([]Event, 0, len(events))
+	for _, event := range events {
+		converted = append(converted, Event{
+			Type:             string(event.Type),
+			TransactionID:    event.TransactionID.String(),
+			TransactionIndex: event.TransactionIndex,
+			EventIndex:       event.EventIndex,
+			Payload:          event.Payload,
+		})
+	}
+	return converted
+}
+
+func transactionResultFromAccess(result *access.TransactionResult) TransactionResult {
+	return TransactionResult{
+		TransactionID: result.TransactionID.String(),
+		BlockID:       result.BlockID.String(),
+		Status:        result.Status.String(),
+		StatusCode:    result.StatusCode,
+		ErrorMessage:  result.ErrorMessage,
+		Events:        eventsFromFlow(result.Events),
+	}
+}
+
+func accountFromFlow(account *flow.Account) Account {
+	keys := make([]AccountKey, 0, len(account.Keys))
+	for _, key := range account.Keys {
+		keys = append(keys, AccountKey{
+			Index:            key.Index,
+			PublicKey:        hex.EncodeToString(key.PublicKey.Encode()),
+			SigningAlgorithm: key.SignAlgo.String(),
+			HashingAlgorithm: key.HashAlgo.String(),
+			SequenceNumber:   key.SeqNumber,
+			Weight:           key.Weight,
+			Revoked:          key.Revoked,
+		})
+	}
+
+	return Account{
+		Address:   account.Address.Hex(),
+		Balance:   account.Balance,
+		Keys:      keys,
+		Contracts: account.Contracts,
+	}
+}
+
+func blockEventsFromFlow(blockEvents flow.BlockEvents) BlockEvents {
+	return BlockEvents{
+		BlockID:        blockEvents.BlockID.String(),
+		BlockHeight:    blockEvents.BlockHeight,
+		BlockTimestamp: blockEvents.BlockTimestamp,
+		Events:         eventsFromFlow(blockEvents.Events),
+	}
+}
+
+func identifiersFromFlow(ids []flow.Identifier) []string {
+	converted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		converted = append(converted, id.String())
+	}
+	return converted
+}
+
+// identifier parses the hex encoded identifier of the named parameter.
+func identifier(name string, raw string) (flow.Identifier, error) {
+	id, err := flow.HexStringToIdentifier(raw)
+	if err != nil {
+		return flow.ZeroID, invalidArgument("invalid %s %q: %v", name, raw, err)
+	}
+	return id, nil
+}
+
+// address parses the hex encoded address of the named parameter, and validates it against the chain.
+func address(name string, raw string, chain flow.Chain) (flow.Address, error) {
+	decoded, err := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+	if err != nil || len(decoded) == 0 || len(decoded) > flow.AddressLength {
+		return flow.EmptyAddress, invalidArgument("invalid %s %q: must be a hex encoded address", name, raw)
+	}
+	addr := flow.BytesToAddress(decoded)
+	if !chain.IsValid(addr) {
+		return flow.EmptyAddress, invalidArgument("invalid %s %s: not an address of chain %s", name, addr, chain)
+	}
+	return addr, nil
+}
@@ -0,0 +1,333 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// maxPageSize is the maximum number of items returned by paginated routes.
+const maxPageSize = 100
+
+// request is a request matched to a route, along with the path parameters of the route.
+type request struct {
+	*http.Request
+	params map[string]string
+}
+
+// param returns the path parameter with the given name.
+func (r *request) param(name string) string {
+	return r.params[name]
+}
+
+// query returns the query parameter with the given name.
+func (r *request) query(name string) string {
+	return r.URL.Query().Get(name)
+}
+
+// uintQuery returns the query parameter with the given name as an unsigned integer, and whether it is set.
+func (r *request) uintQuery(name string) (uint64, bool, error) {
+	raw := r.query(name)
+	if raw == "" {
+		return 0, false, nil
+	}
+	value, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, false, invalidArgument("invalid %s %q: must be an unsigned integer", name, raw)
+	}
+	return value, true, nil
+}
+
+// page returns the offset and the limit of paginated requests. The limit defaults to, and is at most,
+// the maximum page size.
+func (r *request) page() (uint, uint, error) {
+	offset, _, err := r.uintQuery("offset")
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, ok, err := r.uintQuery("limit")
+	if err != nil {
+		return 0, 0, err
+	}
+	if !ok {
+		limit = maxPageSize
+	}
+	if limit == 0 || limit > maxPageSize {
+		return 0, 0, invalidArgument("invalid limit %d: must be between 1 and %d", limit, maxPageSize)
+	}
+	return uint(offset), uint(limit), nil
+}
+
+// handlerFunc handles a request, and returns the response body, which is encoded as JSON.
+type handlerFunc func(r *request) (interface{}, error)
+
+// route is a route of the REST API. The pattern is matched against the path of requests segment by segment,
+// with the segments in braces matching any value, which is passed to the handler as path parameter.
+type route struct {
+	name    string
+	method  string
+	pattern string
+	summary string
+	query   []string // the query parameters, for the OpenAPI specification
+	paged   bool     // whether the route is paginated by the offset and limit query parameters
+	handler handlerFunc
+}
+
+// Router routes the requests of the REST API to their handlers.
+type Router struct {
+	log    zerolog.Logger
+	routes []route
+}
+
+// NewRouter returns a router which serves the given API.
+func NewRouter(log zerolog.Logger, api access.API, chain flow.Chain) *Router {
+	h := &handlers{
+		api:   api,
+		chain: chain,
+	}
+
+	router := &Router{
+		log: log,
+	}
+	router.routes = []route{
+		{
+			name:    "getNetworkParameters",
+			method:  http.MethodGet,
+			pattern: "/v1/network/parameters",
+			summary: "Gets the parameters of the network",
+			handler: h.getNetworkParameters,
+		},
+		// the latest block has to be matched before the blocks by ID
+		{
+			name:    "getLatestBlock",
+			method:  http.MethodGet,
+			pattern: "/v1/blocks/latest",
+			summary: "Gets the latest finalized, or sealed, block",
+			query:   []string{"sealed"},
+			handler: h.getLatestBlock,
+		},
+		{
+			name:    "getBlockByHeight",
+			method:  http.MethodGet,
+			pattern: "/v1/blocks",
+			summary: "Gets the block at a height",
+			query:   []string{"height"},
+			handler: h.getBlockByHeight,
+		},
+		{
+			name:    "getBlockByID",
+			method:  http.MethodGet,
+			pattern: "/v1/blocks/{id}",
+			summary: "Gets a block by its ID",
+			handler: h.getBlockByID,
+		},
+		{
+			name:    "getTransactionResultsByBlockID",
+			method:  http.MethodGet,
+			pattern: "/v1/blocks/{id}/transaction_results",
+			summary: "Gets the results of the transactions of a block",
+			paged:   true,
+			handler: h.getTransactionResultsByBlockID,
+		},
+		{
+			name:    "getCollectionByID",
+			method:  http.MethodGet,
+			pattern: "/v1/collections/{id}",
+			summary: "Gets a collection by its ID",
+			handler: h.getCollectionByID,
+		},
+		{
+			name:    "sendTransaction",
+			method:  http.MethodPost,
+			pattern: "/v1/transactions",
+			summary: "Sends a transaction",
+			handler: h.sendTransaction,
+		},
+		{
+			name:    "getTransactionByID",
+			method:  http.MethodGet,
+			pattern: "/v1/transactions/{id}",
+			summary: "Gets a transaction by its ID",
+			handler: h.getTransactionByID,
+		},
+		{
+			name:    "getTransactionResult",
+			method:  http.MethodGet,
+			pattern: "/v1/transactions/{id}/result",
+			summary: "Gets the result of a transaction",
+			handler: h.getTransactionResult,
+		},
+		{
+			name:    "getAccount",
+			method:  http.MethodGet,
+			pattern: "/v1/accounts/{address}",
+			summary: "Gets an account at the latest sealed block, or at a height",
+			query:   []string{"block_height"},
+			handler: h.getAccount,
+		},
+		{
+			name:    "executeScript",
+			method:  http.MethodPost,
+			pattern: "/v1/scripts",
+			summary: "Executes a script at the latest sealed block, or at a block by ID or height",
+			query:   []string{"block_id", "block_height"},
+			handler: h.executeScript,
+		},
+		{
+			name:    "getEvents",
+			method:  http.MethodGet,
+			pattern: "/v1/events",
+			summary: "Gets the events of a type in a height range, or in blocks by ID, grouped by block",
+			query:   []string{"type", "start_height", "end_height", "block_ids"},
+			paged:   true,
+			handler: h.getEvents,
+		},
+		{
+			name:    "getOpenAPISpecification",
+			method:  http.MethodGet,
+			pattern: "/v1/openapi.json",
+			summary: "Gets the OpenAPI specification of the REST API",
+			handler: router.openAPI,
+		},
+	}
+
+	return router
+}
+
+// ServeHTTP serves the request with the handler of the first matching route.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, route := range rt.routes {
+		params, ok := match(route.pattern, r.URL.Path)
+		if !ok {
+			continue
+		}
+		if r.Method != route.method {
+			continue
+		}
+
+		body, err := route.handler(&request{Request: r, params: params})
+		if err != nil {
+			code := statusCode(err)
+			if code == http.StatusInternalServerError {
+				rt.log.Error().Err(err).Str("route", route.name).Msg("could not serve request")
+			}
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, body)
+		return
+	}
+
+	writeError(w, notFound(r))
+}
+
+// match matches the path against the pattern of a route, and returns the path parameters.
+func match(pattern string, path string) (map[string]string, bool) {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = pathSegments[i]
+			continue
+		}
+		if segment != pathSegments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// openAPI returns the OpenAPI specification of the routes.
+func (rt *Router) openAPI(_ *request) (interface{}, error) {
+	paths := make(map[string]map[string]interface{})
+	for _, route := range rt.routes {
+		var parameters []map[string]interface{}
+		for _, segment := range strings.Split(route.pattern, "/") {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				parameters = append(parameters, openAPIParameter(segment[1:len(segment)-1], "path", true))
+			}
+		}
+		for _, name := range route.query {
+			parameters = append(parameters, openAPIParameter(name, "query", false))
+		}
+		if route.paged {
+			parameters = append(parameters,
+				openAPIParameter("offset", "query", false),
+				openAPIParameter("limit", "query", false),
+			)
+		}
+
+		operation := map[string]interface{}{
+			"operationId": route.name,
+			"summary":     route.summary,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "OK",
+				},
+				"default": map[string]interface{}{
+					"description": "Error",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"},
+						},
+					},
+				},
+			},
+		}
+		if len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+		if route.method == http.MethodPost {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{},
+				},
+			}
+		}
+
+		if paths[route.pattern] == nil {
+			paths[route.pattern] = make(map[string]interface{})
+		}
+		paths[route.pattern][strings.ToLower(route.method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Flow Access API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"code":    map[string]interface{}{"type": "integer"},
+						"message": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func openAPIParameter(name string, in string, required bool) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     name,
+		"in":       in,
+		"required": required,
+		"schema":   map[string]interface{}{"type": "string"},
+	}
+}
@@ -0,0 +1,179 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// stubAPI is a stub of the Access API serving a single block and its events.
+type stubAPI struct {
+	access.API
+	block  *flow.Block
+	events []flow.BlockEvents
+	sent   *flow.TransactionBody
+}
+
+func (s *stubAPI) GetBlockByID(_ context.Context, id flow.Identifier) (*flow.Block, error) {
+	if id != s.block.ID() {
+		return nil, status.Errorf(codes.NotFound, "block %v not found", id)
+	}
+	return s.block, nil
+}
+
+func (s *stubAPI) GetBlockByHeight(_ context.Context, height uint64) (*flow.Block, error) {
+	if height != s.block.Header.Height {
+		return nil, status.Errorf(codes.NotFound, "block at height %d not found", height)
+	}
+	return s.block, nil
+}
+
+func (s *stubAPI) GetEventsForHeightRange(_ context.Context, _ string, _, _ uint64) ([]flow.BlockEvents, error) {
+	return s.events, nil
+}
+
+func (s *stubAPI) SendTransaction(_ context.Context, tx *flow.TransactionBody) error {
+	s.sent = tx
+	return nil
+}
+
+func serve(t *testing.T, router *Router, method string, target string, body interface{}, response interface{}) int {
+	var encoded bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&encoded).Encode(body))
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, target, &encoded))
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(response))
+	return recorder.Code
+}
+
+func TestRouter(t *testing.T) {
+	block := unittest.BlockFixture()
+	api := &stubAPI{
+		block: &block,
+	}
+	for height := uint64(10); height < 15; height++ {
+		api.events = append(api.events, flow.BlockEvents{BlockHeight: height})
+	}
+	chain := flow.Testnet.Chain()
+	router := NewRouter(zerolog.Nop(), api, chain)
+
+	t.Run("block by ID", func(t *testing.T) {
+		var response Block
+		code := serve(t, router, http.MethodGet, "/v1/blocks/"+block.ID().String(), nil, &response)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, block.ID().String(), response.Header.ID)
+		assert.Equal(t, block.Header.Height, response.Header.Height)
+		assert.Len(t, response.CollectionGuarantees, len(block.Payload.Guarantees))
+	})
+
+	t.Run("block by height", func(t *testing.T) {
+		var response Block
+		code := serve(t, router, http.MethodGet, "/v1/blocks?height="+strconv.FormatUint(block.Header.Height, 10), nil, &response)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, block.ID().String(), response.Header.ID)
+	})
+
+	t.Run("errors of the backend", func(t *testing.T) {
+		var response Error
+		code := serve(t, router, http.MethodGet, "/v1/blocks/"+unittest.IdentifierFixture().String(), nil, &response)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Contains(t, response.Message, "not found")
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		var response Error
+		code := serve(t, router, http.MethodGet, "/v1/blocks/invalid", nil, &response)
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code = serve(t, router, http.MethodGet, "/v1/events?type=A.0x1.Test&start_height=10&end_height=14&limit=1000", nil, &response)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("unknown routes", func(t *testing.T) {
+		var response Error
+		code := serve(t, router, http.MethodGet, "/v1/unknown", nil, &response)
+		assert.Equal(t, http.StatusNotFound, code)
+
+		code = serve(t, router, http.MethodDelete, "/v1/blocks/"+block.ID().String(), nil, &response)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("paginated events", func(t *testing.T) {
+		var response struct {
+			Items      []BlockEvents `json:"items"`
+			NextOffset *uint         `json:"next_offset"`
+		}
+		code := serve(t, router, http.MethodGet, "/v1/events?type=A.0x1.Test&start_height=10&end_height=14&limit=3", nil, &response)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, response.Items, 3)
+		assert.Equal(t, uint64(10), response.Items[0].BlockHeight)
+		require.NotNil(t, response.NextOffset)
+		assert.Equal(t, uint(3), *response.NextOffset)
+
+		response.Items, response.NextOffset = nil, nil
+		code = serve(t, router, http.MethodGet, "/v1/events?type=A.0x1.Test&start_height=10&end_height=14&limit=3&offset=3", nil, &response)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, response.Items, 2)
+		assert.Equal(t, uint64(13), response.Items[0].BlockHeight)
+		assert.Nil(t, response.NextOffset)
+	})
+
+	t.Run("send transaction", func(t *testing.T) {
+		address := chain.ServiceAddress().Hex()
+		tx := Transaction{
+			Script:           []byte("transaction {}"),
+			ReferenceBlockID: block.ID().String(),
+			GasLimit:         100,
+			ProposalKey:      ProposalKey{Address: address},
+			Payer:            address,
+			Authorizers:      []string{address},
+			EnvelopeSignatures: []TransactionSignature{
+				{Address: address, Signature: []byte{1, 2, 3}},
+			},
+		}
+
+		var response TransactionID
+		code := serve(t, router, http.MethodPost, "/v1/transactions", tx, &response)
+		require.Equal(t, http.StatusOK, code)
+		require.NotNil(t, api.sent)
+		assert.Equal(t, api.sent.ID().String(), response.ID)
+		assert.Equal(t, 0, api.sent.EnvelopeSignatures[0].SignerIndex)
+
+		// addresses of other chains are rejected
+		tx.Payer = flow.Mainnet.Chain().ServiceAddress().Hex()
+		var errResponse Error
+		code = serve(t, router, http.MethodPost, "/v1/transactions", tx, &errResponse)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("OpenAPI specification", func(t *testing.T) {
+		var response struct {
+			Paths map[string]map[string]interface{} `json:"paths"`
+		}
+		code := serve(t, router, http.MethodGet, "/v1/openapi.json", nil, &response)
+		require.Equal(t, http.StatusOK, code)
+		for _, route := range router.routes {
+			assert.Contains(t, response.Paths[route.pattern], strings.ToLower(route.method))
+		}
+	})
+}
@@ -155,6 +155,19 @@ func New(
 	return b
 }
 
+// CheckSealedResults sets the mode in which the transaction results served from execution nodes
+// are checked against the sealed results of their blocks, which are looked up in the given seals.
+func (b *Backend) CheckSealedResults(mode SealedResultCheck, seals storage.Seals) {
+	b.backendTransactions.sealedResults = newSealedResultChecker(
+		mode,
+		b.state,
+		seals,
+		b.executionReceipts,
+		b.connFactory,
+		b.backendTransactions.log,
+	)
+}
+
 func identifierList(ids []string) (flow.IdentifierList, error) {
 	idList := make(flow.IdentifierList, len(ids))
 	for i, idStr := range ids {
@@ -19,6 +19,7 @@ import (
 	access "github.com/onflow/flow-go/engine/access/mock"
 	backendmock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	executionresults "github.com/onflow/flow-go/engine/execution/rpc/protobuf"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -666,6 +667,221 @@ func (suite *Suite) TestTransactionResultUnknown() {
 	suite.Assert().Equal(flow.TransactionStatusUnknown, result.Status)
 }
 
+// TestGetTransactionResultsByBlockID tests that the results of the transactions of a block are
+// returned page by page, in the order of the block's collections
+func (suite *Suite) TestGetTransactionResultsByBlockID() {
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+
+	ctx := context.Background()
+	collection := unittest.CollectionFixture(3)
+	block := unittest.BlockFixture()
+	block.Header.Height = 2
+	block.Payload.Guarantees = []*flow.CollectionGuarantee{{CollectionID: collection.ID()}}
+	headBlock := unittest.BlockFixture()
+	headBlock.Header.Height = block.Header.Height + 1 // block under test is sealed
+
+	suite.snapshot.
+		On("Head").
+		Return(headBlock.Header, nil)
+
+	blockID := block.ID()
+	light := collection.Light()
+	suite.blocks.
+		On("ByID", blockID).
+		Return(&block, nil)
+	suite.collections.
+		On("LightByID", collection.ID()).
+		Return(&light, nil)
+
+	_, fixedENIDs := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(fixedENIDs, nil)
+
+	// the execution node returns the results of each page at once
+	execResults := make([]*executionresults.TransactionResult, 0, len(collection.Transactions))
+	for _, tx := range collection.Transactions {
+		txID := tx.ID()
+		execResults = append(execResults, &executionresults.TransactionResult{TransactionId: txID[:]})
+	}
+	execResultsClient := new(access.ExecutionResultsAPIClient)
+	execResultsClient.
+		On("GetTransactionResultsByBlockID", ctx, &executionresults.GetTransactionResultsByBlockIDRequest{BlockId: blockID[:], Offset: 0, Limit: 2}).
+		Return(&executionresults.GetTransactionResultsByBlockIDResponse{TransactionResults: execResults[:2]}, nil).
+		Once()
+	execResultsClient.
+		On("GetTransactionResultsByBlockID", ctx, &executionresults.GetTransactionResultsByBlockIDRequest{BlockId: blockID[:], Offset: 2, Limit: 1}).
+		Return(&executionresults.GetTransactionResultsByBlockIDResponse{TransactionResults: execResults[2:]}, nil).
+		Once()
+
+	connFactory := new(backendmock.ConnectionFactory)
+	connFactory.On("GetExecutionResultsAPIClient", mock.Anything).Return(execResultsClient, &mockCloser{}, nil)
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		suite.blocks,
+		suite.headers,
+		suite.collections,
+		suite.transactions,
+		suite.receipts,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		connFactory,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		flow.IdentifierList(fixedENIDs.NodeIDs()).Strings(),
+		suite.log,
+	)
+
+	firstPage, err := backend.GetTransactionResultsByBlockID(ctx, blockID, 0, 2)
+	suite.checkResponse(firstPage, err)
+	suite.Require().Len(firstPage, 2)
+
+	lastPage, err := backend.GetTransactionResultsByBlockID(ctx, blockID, 2, 2)
+	suite.checkResponse(lastPage, err)
+	suite.Require().Len(lastPage, 1)
+
+	for i, result := range append(firstPage, lastPage...) {
+		suite.Assert().Equal(collection.Transactions[i].ID(), result.TransactionID)
+		suite.Assert().Equal(blockID, result.BlockID)
+		suite.Assert().Equal(flow.TransactionStatusSealed, result.Status)
+	}
+
+	execResultsClient.AssertExpectations(suite.T())
+	suite.assertAllExpectations()
+}
+
+// TestSealedResultCheck tests that results served by execution nodes which didn't commit to the
+// sealed result of a block, or whose events don't match the sealed result, are refused in refuse
+// mode, and only flagged in flag mode
+func (suite *Suite) TestSealedResultCheck() {
+	ctx := context.Background()
+	state := new(protocol.State)
+	snapshot := new(protocol.Snapshot)
+	params := new(protocol.Params)
+
+	root := unittest.BlockHeaderFixture()
+	root.Height = 0
+	block := unittest.BlockFixture()
+	block.Header.Height = 10
+	blockID := block.ID()
+
+	// the block has a transaction in each of its two chunks, and the first one emits two events
+	txIDs := []flow.Identifier{unittest.IdentifierFixture(), unittest.IdentifierFixture()}
+	events := [][]flow.Event{
+		{
+			unittest.EventFixture(flow.EventAccountCreated, 0, 0, txIDs[0]),
+			unittest.EventFixture(flow.EventAccountCreated, 0, 1, txIDs[0]),
+		},
+		{},
+	}
+	execResults := make([]*executionresults.TransactionResult, 0, len(txIDs))
+	for i, txID := range txIDs {
+		execResults = append(execResults, &executionresults.TransactionResult{
+			TransactionId: txID[:],
+			Events:        convert.EventsToMessages(events[i]),
+		})
+	}
+
+	// the sealed receipt and a receipt of another execution node committing to a different result
+	sealedReceipt := unittest.ReceiptForBlockFixture(&block)
+	sealedReceipt.ExecutionResult.Chunks = unittest.ChunkListFixture(2, blockID)
+	for i, chunk := range sealedReceipt.ExecutionResult.Chunks {
+		chunk.NumberOfTransactions = 1
+		chunk.EventCollection = flow.EventsList(events[i]).Hash()
+	}
+	otherReceipt := unittest.ReceiptForBlockFixture(&block)
+	seal := &flow.Seal{BlockID: blockID, ResultID: sealedReceipt.ExecutionResult.ID()}
+	sealedNode := unittest.IdentityFixture(unittest.WithNodeID(sealedReceipt.ExecutorID), unittest.WithRole(flow.RoleExecution))
+	otherNode := unittest.IdentityFixture(unittest.WithNodeID(otherReceipt.ExecutorID), unittest.WithRole(flow.RoleExecution))
+
+	params.On("Root").Return(&root, nil)
+	state.On("Params").Return(params)
+	state.On("AtBlockID", blockID).Return(snapshot)
+	snapshot.On("Head").Return(block.Header, nil)
+	seals := new(storagemock.Seals)
+	seals.On("FinalizedSealForBlock", blockID).Return(seal, nil)
+	suite.receipts.
+		On("ByBlockID", blockID).
+		Return(flow.ExecutionReceiptList{sealedReceipt, otherReceipt}, nil)
+
+	// the execution node serves the results of all transactions of the block at once
+	execResultsClient := new(access.ExecutionResultsAPIClient)
+	execResultsClient.
+		On("GetTransactionResultsByBlockID", ctx, &executionresults.GetTransactionResultsByBlockIDRequest{BlockId: blockID[:]}).
+		Return(&executionresults.GetTransactionResultsByBlockIDResponse{TransactionResults: execResults}, nil).
+		Once()
+	connFactory := new(backendmock.ConnectionFactory)
+	connFactory.On("GetExecutionResultsAPIClient", sealedNode.Address).Return(execResultsClient, &mockCloser{}, nil)
+
+	refusing := newSealedResultChecker(SealedResultCheckRefuse, state, seals, suite.receipts, connFactory, suite.log)
+	suite.Assert().True(refusing.accept(ctx, blockID, sealedNode, map[flow.Identifier][]flow.Event{txIDs[0]: events[0]}))
+	suite.Assert().True(refusing.accept(ctx, blockID, sealedNode, map[flow.Identifier][]flow.Event{txIDs[1]: events[1]}))
+	// the events don't match the events the sealed result commits to
+	suite.Assert().False(refusing.accept(ctx, blockID, sealedNode, map[flow.Identifier][]flow.Event{txIDs[0]: events[0][:1]}))
+	suite.Assert().False(refusing.accept(ctx, blockID, sealedNode, map[flow.Identifier][]flow.Event{unittest.IdentifierFixture(): nil}))
+	// the execution node didn't commit to the sealed result
+	suite.Assert().False(refusing.accept(ctx, blockID, otherNode, map[flow.Identifier][]flow.Event{txIDs[0]: events[0]}))
+
+	flagging := newSealedResultChecker(SealedResultCheckFlag, state, seals, suite.receipts, connFactory, suite.log)
+	suite.Assert().True(flagging.accept(ctx, blockID, otherNode, map[flow.Identifier][]flow.Event{txIDs[0]: events[0]}))
+
+	execResultsClient.AssertExpectations(suite.T())
+	suite.assertAllExpectations()
+}
+
+// TestSealedResultCheckEventCollection tests that the events served by an execution node which
+// committed to the sealed result of a block are refused, if they don't match the event collections
+// of the chunks of the sealed result
+func (suite *Suite) TestSealedResultCheckEventCollection() {
+	ctx := context.Background()
+	state := new(protocol.State)
+	snapshot := new(protocol.Snapshot)
+	params := new(protocol.Params)
+
+	root := unittest.BlockHeaderFixture()
+	root.Height = 0
+	block := unittest.BlockFixture()
+	block.Header.Height = 10
+	blockID := block.ID()
+
+	txID := unittest.IdentifierFixture()
+	events := []flow.Event{unittest.EventFixture(flow.EventAccountCreated, 0, 0, txID)}
+
+	// the sealed result commits to a block without events
+	receipt := unittest.ReceiptForBlockFixture(&block)
+	receipt.ExecutionResult.Chunks = unittest.ChunkListFixture(1, blockID)
+	receipt.ExecutionResult.Chunks[0].NumberOfTransactions = 1
+	receipt.ExecutionResult.Chunks[0].EventCollection = flow.EventsList{}.Hash()
+	seal := &flow.Seal{BlockID: blockID, ResultID: receipt.ExecutionResult.ID()}
+	execNode := unittest.IdentityFixture(unittest.WithNodeID(receipt.ExecutorID), unittest.WithRole(flow.RoleExecution))
+
+	params.On("Root").Return(&root, nil)
+	state.On("Params").Return(params)
+	state.On("AtBlockID", blockID).Return(snapshot)
+	snapshot.On("Head").Return(block.Header, nil)
+	seals := new(storagemock.Seals)
+	seals.On("FinalizedSealForBlock", blockID).Return(seal, nil)
+	suite.receipts.
+		On("ByBlockID", blockID).
+		Return(flow.ExecutionReceiptList{receipt}, nil)
+
+	execResultsClient := new(access.ExecutionResultsAPIClient)
+	execResultsClient.
+		On("GetTransactionResultsByBlockID", ctx, &executionresults.GetTransactionResultsByBlockIDRequest{BlockId: blockID[:]}).
+		Return(&executionresults.GetTransactionResultsByBlockIDResponse{TransactionResults: []*executionresults.TransactionResult{
+			{TransactionId: txID[:], Events: convert.EventsToMessages(events)},
+		}}, nil)
+	connFactory := new(backendmock.ConnectionFactory)
+	connFactory.On("GetExecutionResultsAPIClient", execNode.Address).Return(execResultsClient, &mockCloser{}, nil)
+
+	refusing := newSealedResultChecker(SealedResultCheckRefuse, state, seals, suite.receipts, connFactory, suite.log)
+	suite.Assert().False(refusing.accept(ctx, blockID, execNode, map[flow.Identifier][]flow.Event{txID: events}))
+
+	suite.assertAllExpectations()
+}
+
 func (suite *Suite) TestGetLatestFinalizedBlock() {
 	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
 
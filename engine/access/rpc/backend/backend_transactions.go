@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	executionresults "github.com/onflow/flow-go/engine/execution/rpc/protobuf"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/state/protocol"
@@ -24,6 +26,10 @@ import (
 
 const collectionNodesToTry uint = 3
 
+// MaxTransactionResultsPerPage is the maximum number of transaction results returned at once by
+// GetTransactionResultsByBlockID.
+const MaxTransactionResultsPerPage uint = 50
+
 type backendTransactions struct {
 	staticCollectionRPC  accessproto.AccessAPIClient // rpc client tied to a fixed collection node
 	transactions         storage.Transactions
@@ -38,6 +44,7 @@ type backendTransactions struct {
 	connFactory          ConnectionFactory
 
 	previousAccessNodes []accessproto.AccessAPIClient
+	sealedResults       *sealedResultChecker // nil if results are not checked against sealed results
 	log                 zerolog.Logger
 }
 
@@ -254,6 +261,89 @@ func (b *backendTransactions) GetTransactionResult(
 	}, nil
 }
 
+// GetTransactionResultsByBlockID returns the results of the transactions of the given block, in the
+// order of the block's collections, paginated by `offset` and `limit`. Fewer than `limit` results are
+// returned for the last page. The limit is capped at MaxTransactionResultsPerPage.
+func (b *backendTransactions) GetTransactionResultsByBlockID(
+	ctx context.Context,
+	blockID flow.Identifier,
+	offset uint,
+	limit uint,
+) ([]*access.TransactionResult, error) {
+
+	if limit == 0 || limit > MaxTransactionResultsPerPage {
+		limit = MaxTransactionResultsPerPage
+	}
+
+	block, err := b.blocks.ByID(blockID)
+	if err != nil {
+		return nil, convertStorageError(err)
+	}
+
+	var txIDs []flow.Identifier
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := b.collections.LightByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, convertStorageError(err)
+		}
+		txIDs = append(txIDs, collection.Transactions...)
+	}
+	if offset >= uint(len(txIDs)) {
+		return []*access.TransactionResult{}, nil
+	}
+	end := offset + limit
+	if end > uint(len(txIDs)) {
+		end = uint(len(txIDs))
+	}
+
+	// fetch the results of the whole page from one execution node at once. The execution node
+	// returns the results in execution order, which starts with the transactions of the block's
+	// collections in the same order as above
+	executed := true
+	resp, err := b.getTransactionResultsFromExecutionNode(ctx, blockID, offset, end-offset)
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			return nil, err
+		}
+		// no result yet, indicate that the block has not been executed
+		executed = false
+	}
+	if executed && len(resp.GetTransactionResults()) != int(end-offset) {
+		return nil, status.Errorf(codes.Internal, "execution node returned %d results, expected %d",
+			len(resp.GetTransactionResults()), end-offset)
+	}
+
+	// the block is known, so the status doesn't depend on the transaction
+	txStatus, err := b.deriveTransactionStatus(nil, executed, block)
+	if err != nil {
+		return nil, convertStorageError(err)
+	}
+
+	results := make([]*access.TransactionResult, 0, end-offset)
+	for i, txID := range txIDs[offset:end] {
+		result := &access.TransactionResult{
+			Status:        txStatus,
+			BlockID:       blockID,
+			TransactionID: txID,
+		}
+
+		if executed {
+			txResult := resp.GetTransactionResults()[i]
+			if !bytes.Equal(txResult.GetTransactionId(), txID[:]) {
+				return nil, status.Errorf(codes.Internal, "execution node returned result of transaction %x at index %d, expected %v",
+					txResult.GetTransactionId(), offset+uint(i), txID)
+			}
+			result.StatusCode = uint(txResult.GetStatusCode())
+			result.Events = convert.MessagesToEvents(txResult.GetEvents())
+			result.ErrorMessage = txResult.GetErrorMessage()
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
 // deriveTransactionStatus derives the transaction status based on current protocol state
 func (b *backendTransactions) deriveTransactionStatus(
 	tx *flow.TransactionBody,
@@ -463,6 +553,41 @@ func (b *backendTransactions) getTransactionResultFromExecutionNode(
 	return events, resp.GetStatusCode(), resp.GetErrorMessage(), nil
 }
 
+// getTransactionResultsFromExecutionNode fetches a page of the results of the given block from one of
+// the execution nodes which executed it.
+func (b *backendTransactions) getTransactionResultsFromExecutionNode(
+	ctx context.Context,
+	blockID flow.Identifier,
+	offset uint,
+	limit uint,
+) (*executionresults.GetTransactionResultsByBlockIDResponse, error) {
+
+	req := executionresults.GetTransactionResultsByBlockIDRequest{
+		BlockId: blockID[:],
+		Offset:  uint32(offset),
+		Limit:   uint32(limit),
+	}
+
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	if err != nil {
+		// if no execution receipt were found, return a NotFound GRPC error
+		if errors.As(err, &InsufficientExecutionReceipts{}) {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to retrieve results from any execution node: %v", err)
+	}
+
+	resp, err := b.getTransactionResultsFromAnyExeNode(ctx, execNodes, req)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "failed to retrieve results from execution node: %v", err)
+	}
+
+	return resp, nil
+}
+
 func (b *backendTransactions) NotifyFinalizedBlockHeight(height uint64) {
 	b.retry.Retry(height)
 }
@@ -472,6 +597,12 @@ func (b *backendTransactions) getTransactionResultFromAnyExeNode(ctx context.Con
 	// try to execute the script on one of the execution nodes
 	for _, execNode := range execNodes {
 		resp, err := b.tryGetTransactionResult(ctx, execNode, req)
+		if err == nil && b.sealedResults != nil && !b.sealedResults.accept(ctx, flow.HashToID(req.GetBlockId()), execNode, map[flow.Identifier][]flow.Event{
+			flow.HashToID(req.GetTransactionId()): convert.MessagesToEvents(resp.GetEvents()),
+		}) {
+			errors = multierror.Append(errors, fmt.Errorf("result of execution node %v contradicts sealed result", execNode.NodeID))
+			continue
+		}
 		if err == nil {
 			b.log.Debug().
 				Str("execution_node", execNode.String()).
@@ -500,3 +631,48 @@ func (b *backendTransactions) tryGetTransactionResult(ctx context.Context, execN
 	}
 	return resp, nil
 }
+
+func (b *backendTransactions) getTransactionResultsFromAnyExeNode(ctx context.Context, execNodes flow.IdentityList, req executionresults.GetTransactionResultsByBlockIDRequest) (*executionresults.GetTransactionResultsByBlockIDResponse, error) {
+	var errors *multierror.Error
+	for _, execNode := range execNodes {
+		resp, err := b.tryGetTransactionResults(ctx, execNode, req)
+		if err == nil && b.sealedResults != nil && !b.sealedResults.accept(ctx, flow.HashToID(req.GetBlockId()), execNode, servedEvents(resp)) {
+			errors = multierror.Append(errors, fmt.Errorf("result of execution node %v contradicts sealed result", execNode.NodeID))
+			continue
+		}
+		if err == nil {
+			b.log.Debug().
+				Str("execution_node", execNode.String()).
+				Hex("block_id", req.GetBlockId()).
+				Msg("Successfully got transaction results")
+			return resp, nil
+		}
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
+		errors = multierror.Append(errors, err)
+	}
+	return nil, errors.ErrorOrNil()
+}
+
+func (b *backendTransactions) tryGetTransactionResults(ctx context.Context, execNode *flow.Identity, req executionresults.GetTransactionResultsByBlockIDRequest) (*executionresults.GetTransactionResultsByBlockIDResponse, error) {
+	execRPCClient, closer, err := b.connFactory.GetExecutionResultsAPIClient(execNode.Address)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	resp, err := execRPCClient.GetTransactionResultsByBlockID(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// servedEvents returns the events of the transaction results served by an execution node, by transaction.
+func servedEvents(resp *executionresults.GetTransactionResultsByBlockIDResponse) map[flow.Identifier][]flow.Event {
+	events := make(map[flow.Identifier][]flow.Event, len(resp.GetTransactionResults()))
+	for _, txResult := range resp.GetTransactionResults() {
+		events[flow.HashToID(txResult.GetTransactionId())] = convert.MessagesToEvents(txResult.GetEvents())
+	}
+	return events
+}
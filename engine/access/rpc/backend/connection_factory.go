@@ -11,6 +11,7 @@ import (
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 	"google.golang.org/grpc"
 
+	executionresults "github.com/onflow/flow-go/engine/execution/rpc/protobuf"
 	grpcutils "github.com/onflow/flow-go/utils/grpc"
 )
 
@@ -21,6 +22,7 @@ const defaultClientTimeout = 3 * time.Second
 type ConnectionFactory interface {
 	GetAccessAPIClient(address string) (access.AccessAPIClient, io.Closer, error)
 	GetExecutionAPIClient(address string) (execution.ExecutionAPIClient, io.Closer, error)
+	GetExecutionResultsAPIClient(address string) (executionresults.ExecutionResultsAPIClient, io.Closer, error)
 }
 
 type ConnectionFactoryImpl struct {
@@ -79,6 +81,24 @@ func (cf *ConnectionFactoryImpl) GetExecutionAPIClient(address string) (executio
 	return executionAPIClient, closer, nil
 }
 
+// GetExecutionResultsAPIClient returns a client for the results API served by execution nodes in addition
+// to the execution API, on the same port.
+func (cf *ConnectionFactoryImpl) GetExecutionResultsAPIClient(address string) (executionresults.ExecutionResultsAPIClient, io.Closer, error) {
+
+	grpcAddress, err := getGRPCAddress(address, cf.ExecutionGRPCPort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := cf.createConnection(grpcAddress, cf.ExecutionNodeGRPCTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	executionResultsAPIClient := executionresults.NewExecutionResultsAPIClient(conn)
+	closer := io.Closer(conn)
+	return executionResultsAPIClient, closer, nil
+}
+
 // getExecutionNodeAddress translates flow.Identity address to the GRPC address of the node by switching the port to the
 // GRPC port from the libp2p port
 func getGRPCAddress(address string, grpcPort uint) (string, error) {
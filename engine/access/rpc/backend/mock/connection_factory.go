@@ -7,6 +7,8 @@ import (
 
 	execution "github.com/onflow/flow/protobuf/go/flow/execution"
 
+	executionresults "github.com/onflow/flow-go/engine/execution/rpc/protobuf"
+
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
@@ -80,3 +82,35 @@ func (_m *ConnectionFactory) GetExecutionAPIClient(address string) (execution.Ex
 
 	return r0, r1, r2
 }
+
+// GetExecutionResultsAPIClient provides a mock function with given fields: address
+func (_m *ConnectionFactory) GetExecutionResultsAPIClient(address string) (executionresults.ExecutionResultsAPIClient, io.Closer, error) {
+	ret := _m.Called(address)
+
+	var r0 executionresults.ExecutionResultsAPIClient
+	if rf, ok := ret.Get(0).(func(string) executionresults.ExecutionResultsAPIClient); ok {
+		r0 = rf(address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(executionresults.ExecutionResultsAPIClient)
+		}
+	}
+
+	var r1 io.Closer
+	if rf, ok := ret.Get(1).(func(string) io.Closer); ok {
+		r1 = rf(address)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.Closer)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(address)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
@@ -0,0 +1,259 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	executionresults "github.com/onflow/flow-go/engine/execution/rpc/protobuf"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// SealedResultCheck is the mode in which the results served from execution nodes are checked
+// against the sealed result of their block.
+type SealedResultCheck int
+
+const (
+	// SealedResultCheckDisabled serves results without checking them.
+	SealedResultCheckDisabled SealedResultCheck = iota
+	// SealedResultCheckFlag logs results served by execution nodes which didn't commit to the
+	// sealed result of their block, but serves them.
+	SealedResultCheckFlag
+	// SealedResultCheckRefuse refuses to serve results from execution nodes which didn't commit
+	// to the sealed result of their block, and tries other execution nodes instead.
+	SealedResultCheckRefuse
+)
+
+// ParseSealedResultCheck parses the mode of checking results against sealed results, which is
+// one of "disabled", "flag" or "refuse".
+func ParseSealedResultCheck(mode string) (SealedResultCheck, error) {
+	switch mode {
+	case "disabled":
+		return SealedResultCheckDisabled, nil
+	case "flag":
+		return SealedResultCheckFlag, nil
+	case "refuse":
+		return SealedResultCheckRefuse, nil
+	default:
+		return SealedResultCheckDisabled, fmt.Errorf("invalid sealed result check mode %q", mode)
+	}
+}
+
+// sealedResultsCacheSize is the number of sealed result IDs cached by block.
+const sealedResultsCacheSize = 1000
+
+// verifiedEventsCacheSize is the number of blocks and execution nodes for which the hashes of the
+// events checked against the sealed result are cached.
+const verifiedEventsCacheSize = 1000
+
+// sealedResultChecker checks that the execution nodes serving results for a sealed block have
+// committed to the sealed result of the block, and that the events they serve are the events the
+// sealed result commits to. This protects clients from execution nodes serving results they didn't
+// commit to, or results of a fork which wasn't sealed.
+// Results for blocks which aren't sealed yet can't be checked.
+type sealedResultChecker struct {
+	mode        SealedResultCheck
+	state       protocol.State
+	seals       storage.Seals
+	receipts    storage.ExecutionReceipts
+	connFactory ConnectionFactory
+	sealed      *lru.Cache // sealed result ID by block ID
+	verified    *lru.Cache // hashes of the events of each transaction by verifiedEventsKey
+	log         zerolog.Logger
+}
+
+// verifiedEventsKey identifies the events of a block served by an execution node.
+type verifiedEventsKey struct {
+	blockID    flow.Identifier
+	executorID flow.Identifier
+}
+
+func newSealedResultChecker(
+	mode SealedResultCheck,
+	state protocol.State,
+	seals storage.Seals,
+	receipts storage.ExecutionReceipts,
+	connFactory ConnectionFactory,
+	log zerolog.Logger,
+) *sealedResultChecker {
+	sealed, _ := lru.New(sealedResultsCacheSize)
+	verified, _ := lru.New(verifiedEventsCacheSize)
+	return &sealedResultChecker{
+		mode:        mode,
+		state:       state,
+		seals:       seals,
+		receipts:    receipts,
+		connFactory: connFactory,
+		sealed:      sealed,
+		verified:    verified,
+		log:         log,
+	}
+}
+
+// accept returns whether the events of the given transactions of the given block, served by the
+// given execution node, may be served to clients. Results contradicting the sealed result are
+// logged. Results which can't be checked are only refused in refuse mode.
+func (c *sealedResultChecker) accept(ctx context.Context, blockID flow.Identifier, execNode *flow.Identity, events map[flow.Identifier][]flow.Event) bool {
+	if c.mode == SealedResultCheckDisabled {
+		return true
+	}
+
+	consistent, err := c.consistentWithSealedResult(ctx, blockID, execNode, events)
+	if err != nil {
+		c.log.Warn().Err(err).
+			Hex("block_id", blockID[:]).
+			Hex("execution_node_id", execNode.NodeID[:]).
+			Msg("could not check result against sealed result")
+		return c.mode != SealedResultCheckRefuse
+	}
+	if !consistent {
+		c.log.Error().
+			Hex("block_id", blockID[:]).
+			Hex("execution_node_id", execNode.NodeID[:]).
+			Bool("refused", c.mode == SealedResultCheckRefuse).
+			Msg("execution node served result contradicting the sealed result")
+		return c.mode != SealedResultCheckRefuse
+	}
+	return true
+}
+
+// consistentWithSealedResult returns whether the execution node committed to the sealed result of
+// the given block and the given events of its transactions match the sealed result, or true if the
+// block isn't sealed yet.
+func (c *sealedResultChecker) consistentWithSealedResult(ctx context.Context, blockID flow.Identifier, execNode *flow.Identity, events map[flow.Identifier][]flow.Event) (bool, error) {
+	sealedResultID, sealed, err := c.sealedResultID(blockID)
+	if err != nil {
+		return false, fmt.Errorf("could not get sealed result of block %v: %w", blockID, err)
+	}
+	if !sealed {
+		return true, nil
+	}
+
+	receipts, err := c.receipts.ByBlockID(blockID)
+	if err != nil {
+		return false, fmt.Errorf("could not get execution receipts of block %v: %w", blockID, err)
+	}
+	var result *flow.ExecutionResult
+	for _, receipt := range receipts {
+		if receipt.ExecutorID == execNode.NodeID && receipt.ExecutionResult.ID() == sealedResultID {
+			result = &receipt.ExecutionResult
+			break
+		}
+	}
+	if result == nil {
+		return false, nil
+	}
+
+	eventHashes, verified, err := c.verifiedEvents(ctx, execNode, result)
+	if err != nil {
+		return false, fmt.Errorf("could not check events of block %v against sealed result: %w", blockID, err)
+	}
+	if !verified {
+		return false, nil
+	}
+	for txID, txEvents := range events {
+		eventsHash, ok := eventHashes[txID]
+		if !ok || flow.EventsList(txEvents).Hash() != eventsHash {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// verifiedEvents returns the hashes of the events of each transaction of the given sealed result,
+// as served by the given execution node, and whether the events of each chunk match the event
+// collection of the chunk. The events of all transactions of the block are fetched from the
+// execution node once, as the event collections commit to the events of whole chunks.
+func (c *sealedResultChecker) verifiedEvents(ctx context.Context, execNode *flow.Identity, result *flow.ExecutionResult) (map[flow.Identifier]flow.Identifier, bool, error) {
+	key := verifiedEventsKey{blockID: result.BlockID, executorID: execNode.NodeID}
+	if eventHashes, ok := c.verified.Get(key); ok {
+		return eventHashes.(map[flow.Identifier]flow.Identifier), true, nil
+	}
+
+	var total uint64
+	for _, chunk := range result.Chunks {
+		total += chunk.NumberOfTransactions
+	}
+
+	client, closer, err := c.connFactory.GetExecutionResultsAPIClient(execNode.Address)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not connect to execution node: %w", err)
+	}
+	defer closer.Close()
+
+	txResults := make([]*executionresults.TransactionResult, 0, total)
+	for uint64(len(txResults)) < total {
+		resp, err := client.GetTransactionResultsByBlockID(ctx, &executionresults.GetTransactionResultsByBlockIDRequest{
+			BlockId: result.BlockID[:],
+			Offset:  uint32(len(txResults)),
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("could not get transaction results: %w", err)
+		}
+		if len(resp.GetTransactionResults()) == 0 {
+			break
+		}
+		txResults = append(txResults, resp.GetTransactionResults()...)
+	}
+	if uint64(len(txResults)) != total {
+		return nil, false, nil
+	}
+
+	// the transaction results are in execution order, so the transactions of the chunks follow each other
+	eventHashes := make(map[flow.Identifier]flow.Identifier, total)
+	for _, chunk := range result.Chunks {
+		var chunkEvents flow.EventsList
+		for _, txResult := range txResults[:chunk.NumberOfTransactions] {
+			txEvents := convert.MessagesToEvents(txResult.GetEvents())
+			eventHashes[flow.HashToID(txResult.GetTransactionId())] = flow.EventsList(txEvents).Hash()
+			chunkEvents = append(chunkEvents, txEvents...)
+		}
+		if chunkEvents.Hash() != chunk.EventCollection {
+			return nil, false, nil
+		}
+		txResults = txResults[chunk.NumberOfTransactions:]
+	}
+
+	c.verified.Add(key, eventHashes)
+	return eventHashes, true, nil
+}
+
+// sealedResultID returns the ID of the sealed result of the given block, and whether the block
+// is sealed. Blocks which aren't finalized are never sealed.
+func (c *sealedResultChecker) sealedResultID(blockID flow.Identifier) (flow.Identifier, bool, error) {
+	if resultID, ok := c.sealed.Get(blockID); ok {
+		return resultID.(flow.Identifier), true, nil
+	}
+
+	header, err := c.state.AtBlockID(blockID).Head()
+	if err != nil {
+		return flow.ZeroID, false, fmt.Errorf("could not get block: %w", err)
+	}
+	root, err := c.state.Params().Root()
+	if err != nil {
+		return flow.ZeroID, false, fmt.Errorf("could not get root block: %w", err)
+	}
+	if header.Height <= root.Height {
+		// the root result is sealed by the root seal, without receipts of execution nodes
+		return flow.ZeroID, false, nil
+	}
+
+	// the seals of finalized blocks are indexed by the blocks they seal, so blocks which aren't
+	// sealed yet, or are on an orphaned fork, have no indexed seal
+	seal, err := c.seals.FinalizedSealForBlock(blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return flow.ZeroID, false, nil
+	}
+	if err != nil {
+		return flow.ZeroID, false, fmt.Errorf("could not get seal of block: %w", err)
+	}
+
+	c.sealed.Add(blockID, seal.ResultID)
+	return seal.ResultID, true, nil
+}
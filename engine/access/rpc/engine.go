@@ -16,7 +16,11 @@ import (
 
 	"github.com/onflow/flow-go/access"
 	legacyaccess "github.com/onflow/flow-go/access/legacy"
+	legacyaccessresults "github.com/onflow/flow-go/access/legacy/protobuf"
+	accessresults "github.com/onflow/flow-go/access/protobuf"
 	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/archive"
+	"github.com/onflow/flow-go/engine/access/indexer"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
@@ -27,16 +31,17 @@ import (
 
 // Config defines the configurable options for the access node server
 type Config struct {
-	GRPCListenAddr            string        // the GRPC server address as ip:port
-	HTTPListenAddr            string        // the HTTP web proxy address as ip:port
-	CollectionAddr            string        // the address of the upstream collection node
-	HistoricalAccessAddrs     string        // the list of all access nodes from previous spork
-	MaxMsgSize                int           // GRPC max message size
-	ExecutionClientTimeout    time.Duration // execution API GRPC client timeout
-	CollectionClientTimeout   time.Duration // collection API GRPC client timeout
-	MaxHeightRange            uint          // max size of height range requests
-	PreferredExecutionNodeIDs []string      // preferred list of upstream execution node IDs
-	FixedExecutionNodeIDs     []string      // fixed list of execution node IDs to choose from if no node node ID can be chosen from the PreferredExecutionNodeIDs
+	GRPCListenAddr            string                    // the GRPC server address as ip:port
+	HTTPListenAddr            string                    // the HTTP web proxy address as ip:port
+	CollectionAddr            string                    // the address of the upstream collection node
+	HistoricalAccessAddrs     string                    // the list of all access nodes from previous spork
+	MaxMsgSize                int                       // GRPC max message size
+	ExecutionClientTimeout    time.Duration             `mapstructure:"execution-client-timeout"`  // execution API GRPC client timeout
+	CollectionClientTimeout   time.Duration             `mapstructure:"collection-client-timeout"` // collection API GRPC client timeout
+	MaxHeightRange            uint                      `mapstructure:"rpc-max-height-range"`      // max size of height range requests
+	PreferredExecutionNodeIDs []string                  // preferred list of upstream execution node IDs
+	FixedExecutionNodeIDs     []string                  // fixed list of execution node IDs to choose from if no node node ID can be chosen from the PreferredExecutionNodeIDs
+	SealedResultCheck         backend.SealedResultCheck // check of transaction results from execution nodes against sealed results
 }
 
 // Engine implements a gRPC server with a simplified version of the Observation API.
@@ -44,6 +49,7 @@ type Engine struct {
 	unit        *engine.Unit
 	log         zerolog.Logger
 	backend     *backend.Backend // the gRPC service implementation
+	api         access.API       // the API served, which is the backend or the archive backend on top of it
 	grpcServer  *grpc.Server     // the gRPC server
 	httpServer  *http.Server
 	config      Config
@@ -56,11 +62,14 @@ func New(log zerolog.Logger,
 	config Config,
 	collectionRPC accessproto.AccessAPIClient,
 	historicalAccessNodes []accessproto.AccessAPIClient,
+	archives *archive.Registry, // optional, archived data of past sporks served by this node
+	indexedScripts *indexer.ScriptExecutor, // optional, executes scripts locally against the indexed execution state
 	blocks storage.Blocks,
 	headers storage.Headers,
 	collections storage.Collections,
 	transactions storage.Transactions,
 	executionReceipts storage.ExecutionReceipts,
+	seals storage.Seals,
 	chainID flow.ChainID,
 	transactionMetrics module.TransactionMetrics,
 	collectionGRPCPort uint,
@@ -135,20 +144,33 @@ func New(log zerolog.Logger,
 		config.FixedExecutionNodeIDs,
 		log,
 	)
+	backend.CheckSealedResults(config.SealedResultCheck, seals)
+
+	var api access.API = backend
+
+	// with the execution state indexer, scripts at indexed heights are executed locally
+	if indexedScripts != nil {
+		api = indexer.NewBackend(api, indexedScripts)
+	}
+
+	// in archive mode, historical queries are served from the imported archives
+	if archives != nil {
+		api = archive.NewBackend(api, archives, config.MaxHeightRange)
+	}
 
 	eng := &Engine{
 		log:        log,
 		unit:       engine.NewUnit(),
 		backend:    backend,
+		api:        api,
 		grpcServer: grpcServer,
 		httpServer: httpServer,
 		config:     config,
 	}
 
-	accessproto.RegisterAccessAPIServer(
-		eng.grpcServer,
-		access.NewHandler(backend, chainID.Chain()),
-	)
+	handler := access.NewHandler(api, chainID.Chain())
+	accessproto.RegisterAccessAPIServer(eng.grpcServer, handler)
+	accessresults.RegisterTransactionResultsAPIServer(eng.grpcServer, handler)
 
 	if rpcMetricsEnabled {
 		// Not interested in legacy metrics, so initialize here
@@ -157,10 +179,9 @@ func New(log zerolog.Logger,
 	}
 
 	// Register legacy gRPC handlers for backwards compatibility, to be removed at a later date
-	legacyaccessproto.RegisterAccessAPIServer(
-		eng.grpcServer,
-		legacyaccess.NewHandler(backend, chainID.Chain()),
-	)
+	legacyHandler := legacyaccess.NewHandler(api, chainID.Chain())
+	legacyaccessproto.RegisterAccessAPIServer(eng.grpcServer, legacyHandler)
+	legacyaccessresults.RegisterTransactionResultsAPIServer(eng.grpcServer, legacyHandler)
 
 	return eng
 }
@@ -201,6 +222,11 @@ func (e *Engine) GRPCAddress() net.Addr {
 	return e.grpcAddress
 }
 
+// API returns the Access API served by the engine, so that other servers can serve the same API.
+func (e *Engine) API() access.API {
+	return e.api
+}
+
 // process processes the given ingestion engine event. Events that are given
 // to this function originate within the expulsion engine on the node with the
 // given origin ID.
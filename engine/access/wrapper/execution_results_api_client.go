@@ -0,0 +1,15 @@
+package wrapper
+
+import (
+	executionresults "github.com/onflow/flow-go/engine/execution/rpc/protobuf"
+)
+
+// ExecutionResultsAPIClient allows for generation of a mock (via mockery) for the ExecutionResultsAPIClient
+// served by execution nodes in addition to the execution API
+type ExecutionResultsAPIClient interface {
+	executionresults.ExecutionResultsAPIClient
+}
+
+type ExecutionResultsAPIServer interface {
+	executionresults.ExecutionResultsAPIServer
+}
@@ -106,6 +106,9 @@ const (
 	syncClusterPrefix = network.Channel("sync-cluster") // dynamic channel, use ChannelSyncCluster function
 	SyncExecution     = network.Channel("sync-execution")
 
+	// Channels of the public network, which unstaked observers connect to
+	PublicSyncCommittee = network.Channel("public-sync-committee")
+
 	// Channels for actively pushing entities to subscribers
 	PushTransactions = network.Channel("push-transactions")
 	PushGuarantees   = network.Channel("push-guarantees")
@@ -149,7 +152,10 @@ func initializeChannelRoleMap() {
 
 	// Channels for protocols actively synchronizing state across nodes
 	channelRoleMap[SyncCommittee] = flow.RoleList{flow.RoleConsensus}
-	channelRoleMap[SyncExecution] = flow.RoleList{flow.RoleExecution}
+	channelRoleMap[SyncExecution] = flow.RoleList{flow.RoleExecution, flow.RoleAccess}
+
+	// Channels of the public network
+	channelRoleMap[PublicSyncCommittee] = flow.RoleList{flow.RoleAccess}
 
 	// Channels for actively pushing entities to subscribers
 	channelRoleMap[PushTransactions] = flow.RoleList{flow.RoleCollection}
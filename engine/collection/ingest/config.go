@@ -4,24 +4,25 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 )
 
-// Config defines configuration for the transaction ingest engine.
+// Config defines configuration for the transaction ingest engine. The fields are tagged
+// with the names of the flags setting them.
 type Config struct {
 	// how much buffer time there is between a transaction being ingested by a
 	// collection node and being included in a collection and block
-	ExpiryBuffer uint
+	ExpiryBuffer uint `mapstructure:"ingest-expiry-buffer"`
 	// the maximum transaction gas limit
-	MaxGasLimit uint64
+	MaxGasLimit uint64 `mapstructure:"ingest-max-gas-limit"`
 	// whether or not we check that transaction scripts are parse-able
-	CheckScriptsParse bool
+	CheckScriptsParse bool `mapstructure:"ingest-check-scripts-parse"`
 	// the maximum address index we accept
-	MaxAddressIndex uint64
+	MaxAddressIndex uint64 `mapstructure:"ingest-max-address-index"`
 	// how many extra nodes in the responsible cluster we propagate transactions to
 	// (we always send to at least one)
-	PropagationRedundancy uint
+	PropagationRedundancy uint `mapstructure:"ingest-tx-propagation-redundancy"`
 	// the maximum transaction byte size limit
-	MaxTransactionByteSize uint64
+	MaxTransactionByteSize uint64 `mapstructure:"ingest-max-tx-byte-size"`
 	// maximum collection byte size, it acts as hard limit max for the tx size.
-	MaxCollectionByteSize uint64
+	MaxCollectionByteSize uint64 `mapstructure:"ingest-max-col-byte-size"`
 }
 
 func DefaultConfig() Config {
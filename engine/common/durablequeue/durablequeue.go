@@ -0,0 +1,263 @@
+package durablequeue
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	mathbits "math/bits"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/common/fifoqueue"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// checksumSize is the size of the CRC32 checksum prefixed to each stored element.
+const checksumSize = 4
+
+var crc32Table = crc32.MakeTable(crc32.Castagnoli)
+
+// errCorrupted is returned when a stored element fails validation.
+var errCorrupted = errors.New("corrupted element")
+
+// DurableQueue implements a FIFO queue of binary elements with max capacity, which is
+// persisted in the database, so that its elements survive restarts of the node.
+// Engines requiring at-least-once processing peek the element at the head of the queue
+// with `Head`, process it, and only then remove it with `Pop`. Hence, elements are
+// re-processed if the node crashes between processing and removing them.
+//
+// Each element is stored with a checksum. Elements which fail validation when they
+// reach the head of the queue are logged and discarded, so that a corrupted element
+// does not block processing of the elements after it.
+//
+// Elements that exceed the queue's max capacity are dropped. By default, the
+// theoretical capacity equals to the largest `int` value (platform dependent).
+// Capacity and a length observer can be set at construction time via the options
+// `WithCapacity` and `WithLengthObserver`, in the same way as for the in-memory
+// `fifoqueue.FifoQueue`.
+//
+// Caution:
+// * the QueueLengthObserver must be non-blocking
+// * a queue name must only be used by a single queue instance at a time
+type DurableQueue struct {
+	mu             sync.Mutex
+	log            zerolog.Logger
+	db             *badger.DB
+	name           string
+	head           uint64 // index of the element at the head of the queue
+	tail           uint64 // index of the next element pushed to the queue
+	maxCapacity    int
+	lengthObserver fifoqueue.QueueLengthObserver
+}
+
+// ConstructorOption are optional arguments for the `NewDurableQueue`
+// constructor to specify properties of the DurableQueue.
+type ConstructorOption func(*DurableQueue) error
+
+// WithCapacity is a constructor option for NewDurableQueue. It specifies the
+// max number of elements the queue can hold, including the elements which
+// were persisted before a restart.
+func WithCapacity(capacity int) ConstructorOption {
+	return func(queue *DurableQueue) error {
+		if capacity < 1 {
+			return fmt.Errorf("capacity for durable queue must be positive")
+		}
+		queue.maxCapacity = capacity
+		return nil
+	}
+}
+
+// WithLengthObserver is a constructor option for NewDurableQueue. Each time the
+// queue's length changes, the queue calls the provided callback with the new
+// length. By default, the QueueLengthObserver is a NoOp.
+func WithLengthObserver(callback fifoqueue.QueueLengthObserver) ConstructorOption {
+	return func(queue *DurableQueue) error {
+		if callback == nil {
+			return fmt.Errorf("nil is not a valid QueueLengthObserver")
+		}
+		queue.lengthObserver = callback
+		return nil
+	}
+}
+
+// NewDurableQueue creates the queue with the given name, and restores the
+// elements persisted for it in the database.
+func NewDurableQueue(log zerolog.Logger, db *badger.DB, name string, options ...ConstructorOption) (*DurableQueue, error) {
+	// maximum value for platform-specific int: https://yourbasic.org/golang/max-min-int-uint/
+	maxInt := 1<<(mathbits.UintSize-1) - 1
+
+	queue := &DurableQueue{
+		log:            log.With().Str("component", "durable_queue").Str("queue", name).Logger(),
+		db:             db,
+		name:           name,
+		maxCapacity:    maxInt,
+		lengthObserver: func(int) { /* noop */ },
+	}
+	for _, opt := range options {
+		err := opt(queue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply constructor option to durable queue: %w", err)
+		}
+	}
+
+	var first, last uint64
+	err := db.View(operation.LookupQueueBounds(name, &first, &last))
+	if errors.Is(err, storage.ErrNotFound) {
+		return queue, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not restore durable queue %s: %w", name, err)
+	}
+
+	queue.head = first
+	queue.tail = last + 1
+
+	queue.log.Info().
+		Uint64("head", queue.head).
+		Uint64("tail", queue.tail).
+		Msg("durable queue restored")
+
+	return queue, nil
+}
+
+// Push appends the given element to the tail of the queue, and returns whether
+// it was added. If queue capacity is reached, the element is dropped.
+func (q *DurableQueue) Push(element []byte) (bool, error) {
+	length, pushed, err := q.push(element)
+	if err != nil {
+		return false, err
+	}
+
+	if pushed {
+		q.lengthObserver(length)
+	}
+	return pushed, nil
+}
+
+func (q *DurableQueue) push(element []byte) (int, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	length := q.len()
+	if length >= q.maxCapacity {
+		return length, false, nil
+	}
+
+	err := q.db.Update(operation.InsertQueueElement(q.name, q.tail, encode(element)))
+	if err != nil {
+		return length, false, fmt.Errorf("could not persist element at index %d: %w", q.tail, err)
+	}
+	q.tail++
+
+	return q.len(), true, nil
+}
+
+// Head peeks the element at the head of the queue (without removing the head).
+// If the queue is empty, (nil, false, nil) is returned.
+func (q *DurableQueue) Head() ([]byte, bool, error) {
+	q.mu.Lock()
+	element, ok, err := q.validHead()
+	q.mu.Unlock()
+
+	return element, ok, err
+}
+
+// Pop removes and returns the queue's head element.
+// If the queue is empty, (nil, false, nil) is returned.
+func (q *DurableQueue) Pop() ([]byte, bool, error) {
+	element, length, ok, err := q.pop()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	q.lengthObserver(length)
+	return element, true, nil
+}
+
+func (q *DurableQueue) pop() ([]byte, int, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	element, ok, err := q.validHead()
+	if err != nil || !ok {
+		return nil, q.len(), false, err
+	}
+
+	err = q.db.Update(operation.RemoveQueueElement(q.name, q.head))
+	if err != nil {
+		return nil, q.len(), false, fmt.Errorf("could not remove element at index %d: %w", q.head, err)
+	}
+	q.head++
+
+	return element, q.len(), true, nil
+}
+
+// validHead returns the valid element at the head of the queue, discarding all
+// corrupted elements before it, and whether the queue holds a valid element.
+// The caller must hold the lock.
+func (q *DurableQueue) validHead() ([]byte, bool, error) {
+	for q.head < q.tail {
+		element, err := q.retrieve(q.head)
+		if err == nil {
+			return element, true, nil
+		}
+		if !errors.Is(err, errCorrupted) && !errors.Is(err, storage.ErrNotFound) {
+			return nil, false, fmt.Errorf("could not retrieve element at index %d: %w", q.head, err)
+		}
+
+		q.log.Warn().Err(err).
+			Uint64("index", q.head).
+			Msg("discarding corrupted element of durable queue")
+
+		err = q.db.Update(operation.RemoveQueueElement(q.name, q.head))
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, false, fmt.Errorf("could not remove corrupted element at index %d: %w", q.head, err)
+		}
+		q.head++
+	}
+
+	return nil, false, nil
+}
+
+// retrieve reads the element at the given index and validates its checksum.
+func (q *DurableQueue) retrieve(index uint64) ([]byte, error) {
+	var stored []byte
+	err := q.db.View(operation.RetrieveQueueElement(q.name, index, &stored))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stored) < checksumSize {
+		return nil, fmt.Errorf("element too short for checksum: %w", errCorrupted)
+	}
+	element := stored[checksumSize:]
+	if binary.BigEndian.Uint32(stored) != crc32.Checksum(element, crc32Table) {
+		return nil, fmt.Errorf("checksum mismatch: %w", errCorrupted)
+	}
+
+	return element, nil
+}
+
+// Len returns the current length of the queue, including corrupted elements
+// which have not been discarded yet.
+func (q *DurableQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.len()
+}
+
+func (q *DurableQueue) len() int {
+	return int(q.tail - q.head)
+}
+
+// encode prefixes the element with its checksum.
+func encode(element []byte) []byte {
+	stored := make([]byte, checksumSize, checksumSize+len(element))
+	binary.BigEndian.PutUint32(stored, crc32.Checksum(element, crc32Table))
+	return append(stored, element...)
+}
@@ -0,0 +1,129 @@
+package durablequeue
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestPushAndPop(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		queue, err := NewDurableQueue(zerolog.Nop(), db, "queue")
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			pushed, err := queue.Push([]byte{byte(i)})
+			require.NoError(t, err)
+			require.True(t, pushed)
+		}
+		require.Equal(t, 10, queue.Len())
+
+		for i := 0; i < 10; i++ {
+			head, ok, err := queue.Head()
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, []byte{byte(i)}, head)
+
+			element, ok, err := queue.Pop()
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, []byte{byte(i)}, element)
+		}
+		require.Equal(t, 0, queue.Len())
+
+		_, ok, err := queue.Pop()
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+// Test the elements of a queue are restored after a restart, and are independent of other queues.
+func TestRestore(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		queue, err := NewDurableQueue(zerolog.Nop(), db, "queue")
+		require.NoError(t, err)
+		other, err := NewDurableQueue(zerolog.Nop(), db, "other")
+		require.NoError(t, err)
+
+		for i := 0; i < 5; i++ {
+			_, err := queue.Push([]byte{byte(i)})
+			require.NoError(t, err)
+		}
+		_, err = other.Push([]byte{42})
+		require.NoError(t, err)
+
+		_, _, err = queue.Pop()
+		require.NoError(t, err)
+
+		restored, err := NewDurableQueue(zerolog.Nop(), db, "queue")
+		require.NoError(t, err)
+		require.Equal(t, 4, restored.Len())
+
+		for i := 1; i < 5; i++ {
+			element, ok, err := restored.Pop()
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, []byte{byte(i)}, element)
+		}
+
+		// elements pushed after restoring follow the restored elements
+		_, err = restored.Push([]byte{5})
+		require.NoError(t, err)
+		element, ok, err := restored.Pop()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, []byte{5}, element)
+	})
+}
+
+func TestCapacity(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		var lengths []int
+		queue, err := NewDurableQueue(zerolog.Nop(), db, "queue",
+			WithCapacity(2),
+			WithLengthObserver(func(length int) { lengths = append(lengths, length) }),
+		)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			pushed, err := queue.Push([]byte{byte(i)})
+			require.NoError(t, err)
+			require.Equal(t, i < 2, pushed)
+		}
+		require.Equal(t, 2, queue.Len())
+		require.Equal(t, []int{1, 2}, lengths)
+
+		_, err = NewDurableQueue(zerolog.Nop(), db, "queue", WithCapacity(0))
+		require.Error(t, err)
+	})
+}
+
+// Test corrupted elements are discarded when they reach the head of the queue.
+func TestCorruptedElement(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		queue, err := NewDurableQueue(zerolog.Nop(), db, "queue")
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err := queue.Push([]byte{byte(i)})
+			require.NoError(t, err)
+		}
+
+		// replace the second element with one whose checksum doesn't match
+		require.NoError(t, db.Update(operation.RemoveQueueElement("queue", 1)))
+		require.NoError(t, db.Update(operation.InsertQueueElement("queue", 1, []byte{0, 0, 0, 0, 1})))
+
+		for _, expected := range []byte{0, 2} {
+			element, ok, err := queue.Pop()
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, []byte{expected}, element)
+		}
+		require.Equal(t, 0, queue.Len())
+	})
+}
@@ -1,6 +1,7 @@
 package requester
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/rand"
@@ -17,6 +18,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/seed"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
@@ -46,6 +48,8 @@ type Engine struct {
 	handle   HandleFunc
 	items    map[flow.Identifier]*Item
 	requests map[uint64]*messages.EntityRequest
+	sampler  *rand.Rand      // selects providers, seeded per finalized block
+	seededAt flow.Identifier // ID of the finalized block the sampler was seeded at
 }
 
 // New creates a new requester engine, operating on the provided network channel, and requesting entities from a node
@@ -263,7 +267,8 @@ func (e *Engine) dispatchRequest() (bool, error) {
 	defer e.unit.Unlock()
 
 	// get the current top-level set of valid providers
-	providers, err := e.state.Final().Identities(e.selector)
+	final := e.state.Final()
+	providers, err := final.Identities(e.selector)
 	if err != nil {
 		return false, fmt.Errorf("could not get providers: %w", err)
 	}
@@ -309,7 +314,11 @@ func (e *Engine) dispatchRequest() (bool, error) {
 			if len(providers) == 0 {
 				return false, fmt.Errorf("no valid providers available")
 			}
-			providerID = providers.Sample(1)[0].NodeID
+			sampler, err := e.providerSampler(final)
+			if err != nil {
+				return false, fmt.Errorf("could not get provider sampler: %w", err)
+			}
+			providerID = providers[sampler.Intn(len(providers))].NodeID
 		}
 
 		// add item to list and set retry parameters
@@ -387,6 +396,29 @@ func (e *Engine) process(originID flow.Identifier, message interface{}) error {
 	}
 }
 
+// providerSampler returns the random number generator selecting the providers of requests, seeded
+// with the requester sampling seed of the given finalized state. The generator is seeded again
+// whenever a new block is finalized.
+func (e *Engine) providerSampler(final protocol.Snapshot) (*rand.Rand, error) {
+	head, err := final.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized header: %w", err)
+	}
+	blockID := head.ID()
+	if e.sampler != nil && e.seededAt == blockID {
+		return e.sampler, nil
+	}
+
+	source, err := seed.RequesterSampling(final, e.me.NodeID())
+	if err != nil {
+		return nil, fmt.Errorf("could not derive sampling seed: %w", err)
+	}
+	e.sampler = rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(source[:8]))))
+	e.seededAt = blockID
+
+	return e.sampler, nil
+}
+
 func (e *Engine) onEntityResponse(originID flow.Identifier, res *messages.EntityResponse) error {
 
 	// check that the response comes from a valid provider
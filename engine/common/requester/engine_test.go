@@ -13,8 +13,10 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/indices"
 	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module/metrics"
+	module "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -55,6 +57,9 @@ func TestDispatchRequestVarious(t *testing.T) {
 		},
 		nil,
 	)
+	head := unittest.BlockHeaderFixture()
+	final.On("Head").Return(&head, nil)
+	final.On("Seed", seedIndices()...).Return(unittest.RandomBytes(32), nil)
 
 	state := &protocol.State{}
 	state.On("Final").Return(final)
@@ -122,10 +127,14 @@ func TestDispatchRequestVarious(t *testing.T) {
 		},
 	).Return(nil)
 
+	me := &module.Local{}
+	me.On("NodeID").Return(unittest.IdentifierFixture())
+
 	request := Engine{
 		unit:     engine.NewUnit(),
 		metrics:  metrics.NewNoopCollector(),
 		cfg:      cfg,
+		me:       me,
 		state:    state,
 		con:      con,
 		items:    items,
@@ -159,6 +168,9 @@ func TestDispatchRequestBatchSize(t *testing.T) {
 		},
 		nil,
 	)
+	head := unittest.BlockHeaderFixture()
+	final.On("Head").Return(&head, nil)
+	final.On("Seed", seedIndices()...).Return(unittest.RandomBytes(32), nil)
 
 	state := &protocol.State{}
 	state.On("Final").Return(final)
@@ -193,10 +205,14 @@ func TestDispatchRequestBatchSize(t *testing.T) {
 		},
 	).Return(nil)
 
+	me := &module.Local{}
+	me.On("NodeID").Return(unittest.IdentifierFixture())
+
 	request := Engine{
 		unit:     engine.NewUnit(),
 		metrics:  metrics.NewNoopCollector(),
 		cfg:      cfg,
+		me:       me,
 		state:    state,
 		con:      con,
 		items:    items,
@@ -210,6 +226,95 @@ func TestDispatchRequestBatchSize(t *testing.T) {
 	con.AssertExpectations(t)
 }
 
+func TestDispatchRequestSampling(t *testing.T) {
+
+	identities := unittest.IdentityListFixture(16)
+	head := unittest.BlockHeaderFixture()
+
+	final := &protocol.Snapshot{}
+	final.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return identities.Filter(selector)
+		},
+		nil,
+	)
+	final.On("Head").Return(&head, nil)
+	// each requester derives its sampling seed once per finalized block
+	final.On("Seed", seedIndices()...).Return(unittest.RandomBytes(32), nil).Twice()
+
+	state := &protocol.State{}
+	state.On("Final").Return(final)
+
+	me := &module.Local{}
+	me.On("NodeID").Return(unittest.IdentifierFixture())
+
+	cfg := Config{
+		BatchInterval:  24 * time.Hour,
+		BatchThreshold: 1,
+		RetryInitial:   24 * time.Hour,
+		RetryFunction:  RetryLinear(1),
+		RetryAttempts:  1,
+		RetryMaximum:   24 * time.Hour,
+	}
+
+	// dispatches one request per item and returns the providers the requests were sent to
+	sample := func() []flow.Identifier {
+		var providerIDs []flow.Identifier
+		con := &mocknetwork.Conduit{}
+		con.On("Unicast", mock.Anything, mock.Anything).Run(
+			func(args mock.Arguments) {
+				providerIDs = append(providerIDs, args.Get(1).(flow.Identifier))
+			},
+		).Return(nil)
+
+		items := make(map[flow.Identifier]*Item)
+		for i := 0; i < 8; i++ {
+			item := &Item{
+				EntityID:      unittest.IdentifierFixture(),
+				NumAttempts:   0,
+				LastRequested: time.Time{},
+				RetryAfter:    cfg.RetryInitial,
+				ExtraSelector: filter.Any,
+			}
+			items[item.EntityID] = item
+		}
+
+		request := Engine{
+			unit:     engine.NewUnit(),
+			metrics:  metrics.NewNoopCollector(),
+			cfg:      cfg,
+			me:       me,
+			state:    state,
+			con:      con,
+			items:    items,
+			requests: make(map[uint64]*messages.EntityRequest),
+			selector: filter.Any,
+		}
+		for range items {
+			dispatched, err := request.dispatchRequest()
+			require.NoError(t, err)
+			require.True(t, dispatched)
+		}
+
+		return providerIDs
+	}
+
+	// the providers are sampled deterministically from the seed of the finalized block
+	first := sample()
+	require.Len(t, first, 8)
+	assert.Equal(t, first, sample())
+
+	final.AssertExpectations(t)
+}
+
+func seedIndices() []interface{} {
+	args := make([]interface{}, 0, len(indices.ProtocolRequesterSampling))
+	for _, index := range indices.ProtocolRequesterSampling {
+		args = append(args, index)
+	}
+	return args
+}
+
 func TestOnEntityResponseValid(t *testing.T) {
 
 	identities := unittest.IdentityListFixture(16)
@@ -2,17 +2,25 @@ package synchronization
 
 import (
 	"time"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
 )
 
 type Config struct {
 	pollInterval time.Duration
 	scanInterval time.Duration
+	channel      network.Channel
+	targets      func() (flow.IdentifierList, error)
+	unicast      bool
 }
 
 func DefaultConfig() *Config {
 	return &Config{
 		pollInterval: 8 * time.Second,
 		scanInterval: 2 * time.Second,
+		channel:      engine.SyncCommittee,
 	}
 }
 
@@ -32,3 +40,28 @@ func WithScanInterval(interval time.Duration) OptionFunc {
 		cfg.scanInterval = interval
 	}
 }
+
+// WithChannel sets a custom channel to synchronize on, such as the public channel
+// which unstaked observers synchronize with access nodes on.
+func WithChannel(channel network.Channel) OptionFunc {
+	return func(cfg *Config) {
+		cfg.channel = channel
+	}
+}
+
+// WithTargets sets a custom function returning the nodes we send our requests to,
+// instead of the consensus nodes of the finalized state. The requests are skipped
+// while it returns no nodes.
+func WithTargets(targets func() (flow.IdentifierList, error)) OptionFunc {
+	return func(cfg *Config) {
+		cfg.targets = targets
+	}
+}
+
+// WithUnicastRequests sends our requests to the sampled nodes one by one, instead of
+// multicasting them, for networks which only support unicast messaging.
+func WithUnicastRequests() OptionFunc {
+	return func(cfg *Config) {
+		cfg.unicast = true
+	}
+}
@@ -37,6 +37,8 @@ type Engine struct {
 
 	pollInterval time.Duration
 	scanInterval time.Duration
+	targets      func() (flow.IdentifierList, error) // nodes to send requests to
+	unicast      bool                                // whether requests are unicast rather than multicast
 	core         module.SyncCore
 }
 
@@ -70,10 +72,15 @@ func New(
 		core:         core,
 		pollInterval: opt.pollInterval,
 		scanInterval: opt.scanInterval,
+		targets:      opt.targets,
+		unicast:      opt.unicast,
+	}
+	if e.targets == nil {
+		e.targets = e.consensusNodes
 	}
 
 	// register the engine with the network layer and store the conduit
-	con, err := net.Register(engine.SyncCommittee, e)
+	con, err := net.Register(opt.channel, e)
 	if err != nil {
 		return nil, fmt.Errorf("could not register engine: %w", err)
 	}
@@ -398,14 +405,12 @@ func (e *Engine) pollHeight() error {
 		return fmt.Errorf("could not get last finalized header: %w", err)
 	}
 
-	// get all of the consensus nodes from the state
-	participants, err := e.state.Final().Identities(filter.And(
-		filter.HasRole(flow.RoleConsensus),
-		filter.Not(filter.HasNodeID(e.me.NodeID())),
-	))
-
+	participants, err := e.targets()
 	if err != nil {
-		return fmt.Errorf("could not send get consensus identities: %w", err)
+		return fmt.Errorf("could not get participants: %w", err)
+	}
+	if len(participants) == 0 {
+		return nil
 	}
 
 	// send the request for synchronization
@@ -413,7 +418,7 @@ func (e *Engine) pollHeight() error {
 		Nonce:  rand.Uint64(),
 		Height: final.Height,
 	}
-	err = e.con.Multicast(req, synccore.DefaultPollNodes, participants.NodeIDs()...)
+	err = e.multicast(req, synccore.DefaultPollNodes, participants)
 	if err != nil {
 		return fmt.Errorf("could not send sync request: %w", err)
 	}
@@ -426,13 +431,13 @@ func (e *Engine) pollHeight() error {
 // sendRequests sends a request for each range and batch.
 func (e *Engine) sendRequests(ranges []flow.Range, batches []flow.Batch) error {
 
-	participants, err := e.state.Final().Identities(filter.And(
-		filter.HasRole(flow.RoleConsensus),
-		filter.Not(filter.HasNodeID(e.me.NodeID())),
-	))
+	participants, err := e.targets()
 	if err != nil {
 		return fmt.Errorf("could not get participants: %w", err)
 	}
+	if len(participants) == 0 {
+		return nil
+	}
 
 	var errs error
 	for _, ran := range ranges {
@@ -441,7 +446,7 @@ func (e *Engine) sendRequests(ranges []flow.Range, batches []flow.Batch) error {
 			FromHeight: ran.From,
 			ToHeight:   ran.To,
 		}
-		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, participants.NodeIDs()...)
+		err := e.multicast(req, synccore.DefaultBlockRequestNodes, participants)
 		if err != nil {
 			errs = multierror.Append(errs, fmt.Errorf("could not submit range request: %w", err))
 			continue
@@ -460,7 +465,7 @@ func (e *Engine) sendRequests(ranges []flow.Range, batches []flow.Batch) error {
 			Nonce:    rand.Uint64(),
 			BlockIDs: batch.BlockIDs,
 		}
-		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, participants.NodeIDs()...)
+		err := e.multicast(req, synccore.DefaultBlockRequestNodes, participants)
 		if err != nil {
 			errs = multierror.Append(errs, fmt.Errorf("could not submit batch request: %w", err))
 			continue
@@ -471,3 +476,32 @@ func (e *Engine) sendRequests(ranges []flow.Range, batches []flow.Batch) error {
 
 	return errs
 }
+
+// consensusNodes returns the consensus nodes of the finalized state, which we send
+// our requests to by default.
+func (e *Engine) consensusNodes() (flow.IdentifierList, error) {
+	participants, err := e.state.Final().Identities(filter.And(
+		filter.HasRole(flow.RoleConsensus),
+		filter.Not(filter.HasNodeID(e.me.NodeID())),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("could not get consensus identities: %w", err)
+	}
+	return participants.NodeIDs(), nil
+}
+
+// multicast sends the request to the given number of nodes sampled from the participants.
+func (e *Engine) multicast(req interface{}, num uint, participants flow.IdentifierList) error {
+	if !e.unicast {
+		return e.con.Multicast(req, num, participants...)
+	}
+
+	var errs error
+	for _, targetID := range flow.Sample(num, participants...) {
+		err := e.con.Unicast(req, targetID)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("could not send request to %x: %w", targetID, err))
+		}
+	}
+	return errs
+}
@@ -378,6 +378,35 @@ func (ss *SyncSuite) TestPollHeight() {
 	ss.con.AssertExpectations(ss.T())
 }
 
+// TestPollHeight_UnicastTargets checks that the sync requests are unicast to the custom
+// targets, such as the upstream access nodes of an observer.
+func (ss *SyncSuite) TestPollHeight_UnicastTargets() {
+
+	targets := unittest.IdentifierListFixture(2)
+	ss.e.targets = func() (flow.IdentifierList, error) {
+		return targets, nil
+	}
+	ss.e.unicast = true
+
+	for _, targetID := range targets {
+		ss.con.On("Unicast", mock.Anything, targetID).Return(nil).Run(
+			func(args mock.Arguments) {
+				req := args.Get(0).(*messages.SyncRequest)
+				require.Equal(ss.T(), ss.head.Height, req.Height, "request should contain finalized height")
+			},
+		).Once()
+	}
+	err := ss.e.pollHeight()
+	ss.Require().Nil(err)
+	ss.con.AssertExpectations(ss.T())
+
+	// no requests are sent without targets
+	targets = nil
+	err = ss.e.pollHeight()
+	ss.Require().Nil(err)
+	ss.con.AssertNumberOfCalls(ss.T(), "Unicast", 2)
+}
+
 func (ss *SyncSuite) TestSendRequests() {
 
 	ranges := unittest.RangeListFixture(1)
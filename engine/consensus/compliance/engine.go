@@ -14,6 +14,7 @@ import (
 	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/timesync"
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/state/protocol"
@@ -81,9 +82,14 @@ func NewEngine(
 		log.With().Str("compliance", "engine").Logger(),
 		engine.Pattern{
 			Match: func(msg *engine.Message) bool {
-				_, ok := msg.Payload.(*messages.BlockProposal)
+				proposal, ok := msg.Payload.(*messages.BlockProposal)
 				if ok {
 					core.metrics.MessageReceived(metrics.EngineCompliance, metrics.MessageBlockProposal)
+					// the timestamps of fresh proposals reflect the clocks of their proposers, in
+					// contrast to the timestamps of synced blocks
+					if core.timeSync != nil {
+						core.timeSync.Observe(proposal.Header.ProposerID, proposal.Header.Timestamp)
+					}
 				}
 				return ok
 			},
@@ -155,6 +161,13 @@ func (e *Engine) WithConsensus(hot module.HotStuff) *Engine {
 	return e
 }
 
+// WithTimeSync observes the clock skew of the proposers of incoming block proposals with the
+// given observer.
+func (e *Engine) WithTimeSync(observer *timesync.Observer) *Engine {
+	e.core.timeSync = observer
+	return e
+}
+
 // Ready returns a ready channel that is closed once the engine has fully
 // started. For consensus engine, this is true once the underlying consensus
 // algorithm has started.
@@ -251,10 +251,7 @@ func (e *Engine) validateExpiry(guarantee *flow.CollectionGuarantee) error {
 
 	// if head has advanced beyond the block referenced by the collection guarantee by more than 'expiry' number of blocks,
 	// then reject the collection
-	if ref.Height > final.Height {
-		return nil // the reference block is newer than the latest finalized one
-	}
-	if final.Height-ref.Height > flow.DefaultTransactionExpiry {
+	if flow.IsExpired(ref.Height, final.Height, flow.DefaultTransactionExpiry) {
 		return engine.NewOutdatedInputErrorf("collection guarantee expired ref_height=%d final_height=%d", ref.Height, final.Height)
 	}
 
@@ -0,0 +1,92 @@
+package sealing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ApprovalDecayStep lowers the number of approvals required per chunk to seal a result, once the
+// result has remained unsealed for the given number of finalized blocks.
+type ApprovalDecayStep struct {
+	UnsealedBlocks    uint64 // number of finalized blocks since the block incorporating the result
+	RequiredApprovals uint   // number of approvals required per chunk from then on
+}
+
+// ApprovalDecaySchedule is the schedule by which the number of approvals required to seal a result
+// decays, when the approval process hangs behind finalization. The steps are ordered by increasing
+// number of unsealed blocks, and require a decreasing number of approvals.
+// ATTENTION: sealing with less than the required approvals is NOT BFT compatible. The schedule is
+// a temporary measure while seal & verification is under development; an empty schedule disables it.
+type ApprovalDecaySchedule []ApprovalDecayStep
+
+// EmergencySealingSchedule returns the schedule of emergency sealing: results which remained
+// unsealed for DefaultEmergencySealingThreshold finalized blocks are sealed without approvals.
+func EmergencySealingSchedule() ApprovalDecaySchedule {
+	return ApprovalDecaySchedule{
+		{UnsealedBlocks: DefaultEmergencySealingThreshold, RequiredApprovals: 0},
+	}
+}
+
+// ParseApprovalDecaySchedule parses a schedule from a comma-separated list of
+// `<unsealed blocks>:<required approvals>` steps, e.g. "200:1,400:0".
+func ParseApprovalDecaySchedule(s string) (ApprovalDecaySchedule, error) {
+	var schedule ApprovalDecaySchedule
+	if s == "" {
+		return schedule, nil
+	}
+	for _, step := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(step), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid approval decay step %q", step)
+		}
+		unsealedBlocks, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number of unsealed blocks in approval decay step %q: %w", step, err)
+		}
+		requiredApprovals, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number of required approvals in approval decay step %q: %w", step, err)
+		}
+		schedule = append(schedule, ApprovalDecayStep{
+			UnsealedBlocks:    unsealedBlocks,
+			RequiredApprovals: uint(requiredApprovals),
+		})
+	}
+	err := schedule.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Validate checks that the steps of the schedule are ordered by strictly increasing number of
+// unsealed blocks, and strictly decreasing number of required approvals.
+func (s ApprovalDecaySchedule) Validate() error {
+	for i := 1; i < len(s); i++ {
+		if s[i].UnsealedBlocks <= s[i-1].UnsealedBlocks {
+			return fmt.Errorf("approval decay step %d doesn't follow step %d (unsealed blocks: %d <= %d)",
+				i, i-1, s[i].UnsealedBlocks, s[i-1].UnsealedBlocks)
+		}
+		if s[i].RequiredApprovals >= s[i-1].RequiredApprovals {
+			return fmt.Errorf("approval decay step %d doesn't lower the required approvals of step %d (%d >= %d)",
+				i, i-1, s[i].RequiredApprovals, s[i-1].RequiredApprovals)
+		}
+	}
+	return nil
+}
+
+// RequiredApprovals returns the number of approvals required per chunk for a result which has
+// remained unsealed for the given number of finalized blocks, where `required` is the number of
+// approvals required without decay.
+func (s ApprovalDecaySchedule) RequiredApprovals(required uint, unsealedBlocks uint64) uint {
+	for _, step := range s {
+		if unsealedBlocks < step.UnsealedBlocks {
+			break
+		}
+		if step.RequiredApprovals < required {
+			required = step.RequiredApprovals
+		}
+	}
+	return required
+}
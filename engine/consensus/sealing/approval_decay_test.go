@@ -0,0 +1,61 @@
+package sealing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseApprovalDecaySchedule(t *testing.T) {
+
+	t.Run("empty schedule", func(t *testing.T) {
+		schedule, err := ParseApprovalDecaySchedule("")
+		require.NoError(t, err)
+		assert.Empty(t, schedule)
+	})
+
+	t.Run("valid schedule", func(t *testing.T) {
+		schedule, err := ParseApprovalDecaySchedule("200:2, 300:1,400:0")
+		require.NoError(t, err)
+		assert.Equal(t, ApprovalDecaySchedule{
+			{UnsealedBlocks: 200, RequiredApprovals: 2},
+			{UnsealedBlocks: 300, RequiredApprovals: 1},
+			{UnsealedBlocks: 400, RequiredApprovals: 0},
+		}, schedule)
+	})
+
+	t.Run("malformed step", func(t *testing.T) {
+		_, err := ParseApprovalDecaySchedule("200-1")
+		assert.Error(t, err)
+	})
+
+	t.Run("unordered steps", func(t *testing.T) {
+		_, err := ParseApprovalDecaySchedule("400:0,200:1")
+		assert.Error(t, err)
+	})
+
+	t.Run("non-decreasing approvals", func(t *testing.T) {
+		_, err := ParseApprovalDecaySchedule("200:1,400:1")
+		assert.Error(t, err)
+	})
+}
+
+func TestApprovalDecayScheduleRequiredApprovals(t *testing.T) {
+	schedule := ApprovalDecaySchedule{
+		{UnsealedBlocks: 200, RequiredApprovals: 2},
+		{UnsealedBlocks: 400, RequiredApprovals: 0},
+	}
+
+	assert.Equal(t, uint(3), schedule.RequiredApprovals(3, 0))
+	assert.Equal(t, uint(3), schedule.RequiredApprovals(3, 199))
+	assert.Equal(t, uint(2), schedule.RequiredApprovals(3, 200))
+	assert.Equal(t, uint(2), schedule.RequiredApprovals(3, 399))
+	assert.Equal(t, uint(0), schedule.RequiredApprovals(3, 400))
+
+	// steps never raise the number of required approvals
+	assert.Equal(t, uint(1), schedule.RequiredApprovals(1, 200))
+
+	// an empty schedule never decays
+	assert.Equal(t, uint(3), ApprovalDecaySchedule(nil).RequiredApprovals(3, 1000))
+}
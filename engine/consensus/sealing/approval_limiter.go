@@ -0,0 +1,38 @@
+package sealing
+
+import (
+	"golang.org/x/time/rate"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// approvalRateLimiter limits the rate of approvals accepted from each origin, so that a single
+// node flooding the engine with approvals can't crowd out the approvals of other nodes.
+// The zero value doesn't limit the rate. It is NOT concurrency safe.
+type approvalRateLimiter struct {
+	limit    rate.Limit
+	burst    int
+	limiters map[flow.Identifier]*rate.Limiter
+}
+
+func newApprovalRateLimiter(limit float64, burst int) approvalRateLimiter {
+	return approvalRateLimiter{
+		limit:    rate.Limit(limit),
+		burst:    burst,
+		limiters: make(map[flow.Identifier]*rate.Limiter),
+	}
+}
+
+// Allow returns whether an approval from the given origin is within the rate limit.
+// Messages are only delivered from staked nodes, which bounds the number of limiters.
+func (l *approvalRateLimiter) Allow(originID flow.Identifier) bool {
+	if l.limit <= 0 {
+		return true
+	}
+	limiter, ok := l.limiters[originID]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[originID] = limiter
+	}
+	return limiter.Allow()
+}
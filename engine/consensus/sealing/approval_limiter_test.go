@@ -0,0 +1,33 @@
+package sealing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestApprovalRateLimiter(t *testing.T) {
+
+	t.Run("no limit", func(t *testing.T) {
+		var limiter approvalRateLimiter
+		originID := unittest.IdentifierFixture()
+		for i := 0; i < 1000; i++ {
+			assert.True(t, limiter.Allow(originID))
+		}
+	})
+
+	t.Run("limit per origin", func(t *testing.T) {
+		// a tiny rate, so that no tokens are replenished during the test
+		limiter := newApprovalRateLimiter(0.001, 3)
+		flooding := unittest.IdentifierFixture()
+		for i := 0; i < 3; i++ {
+			assert.True(t, limiter.Allow(flooding))
+		}
+		assert.False(t, limiter.Allow(flooding))
+
+		// other origins aren't affected
+		assert.True(t, limiter.Allow(unittest.IdentifierFixture()))
+	})
+}
@@ -0,0 +1,87 @@
+package sealing
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// VerifyApprovalFunc verifies an approval received from the given origin. It returns whether the
+// approval is valid, and an error only for unexpected failures. It must be concurrency safe.
+type VerifyApprovalFunc func(originID flow.Identifier, approval *flow.ResultApproval) (bool, error)
+
+// ApprovalResultFunc is called with the outcome of the verification of an approval.
+type ApprovalResultFunc func(originID flow.Identifier, approval *flow.ResultApproval, valid bool, err error)
+
+type approvalTask struct {
+	originID flow.Identifier
+	approval *flow.ResultApproval
+}
+
+// approvalVerifier verifies approvals in a bounded pool of workers, so that the expensive signature
+// and SPoCK verifications of an approval flood don't stall the processing of receipts and the sealing
+// checks. Approvals are queued up to a fixed capacity, beyond which they are dropped, and requested
+// approvals are verified before broadcast ones. The outcome of each verification is passed to a
+// callback, which is run by the worker that verified the approval.
+type approvalVerifier struct {
+	verify    VerifyApprovalFunc
+	onResult  ApprovalResultFunc
+	requested chan approvalTask              // approvals requested by the sealing core, which take priority
+	broadcast chan approvalTask              // approvals broadcast by verification nodes
+	onLength  func(requested, broadcast int) // observer of the numbers of queued approvals, if any
+}
+
+func newApprovalVerifier(capacity uint, verify VerifyApprovalFunc, onResult ApprovalResultFunc, onLength func(requested, broadcast int)) *approvalVerifier {
+	if onLength == nil {
+		onLength = func(int, int) {}
+	}
+	return &approvalVerifier{
+		verify:    verify,
+		onResult:  onResult,
+		requested: make(chan approvalTask, capacity),
+		broadcast: make(chan approvalTask, capacity),
+		onLength:  onLength,
+	}
+}
+
+// Submit queues the approval for verification without blocking. It returns false if the queue is
+// full and the approval was dropped.
+func (v *approvalVerifier) Submit(originID flow.Identifier, approval *flow.ResultApproval, requested bool) bool {
+	queue := v.broadcast
+	if requested {
+		queue = v.requested
+	}
+	select {
+	case queue <- approvalTask{originID: originID, approval: approval}:
+		v.onLength(len(v.requested), len(v.broadcast))
+		return true
+	default:
+		return false
+	}
+}
+
+// Len returns the number of approvals waiting for verification.
+func (v *approvalVerifier) Len() int {
+	return len(v.requested) + len(v.broadcast)
+}
+
+// Run verifies queued approvals until the quit channel is closed. It is run by each worker of the pool.
+func (v *approvalVerifier) Run(quit <-chan struct{}) {
+	for {
+		var task approvalTask
+		select {
+		case task = <-v.requested:
+		case <-quit:
+			return
+		default:
+			select {
+			case task = <-v.requested:
+			case task = <-v.broadcast:
+			case <-quit:
+				return
+			}
+		}
+		v.onLength(len(v.requested), len(v.broadcast))
+
+		valid, err := v.verify(task.originID, task.approval)
+		v.onResult(task.originID, task.approval, valid, err)
+	}
+}
@@ -0,0 +1,118 @@
+package sealing
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestApprovalVerifier(t *testing.T) {
+
+	t.Run("bounded queues", func(t *testing.T) {
+		noop := func(flow.Identifier, *flow.ResultApproval, bool, error) {}
+		verifier := newApprovalVerifier(2, nil, noop, nil)
+		originID := unittest.IdentifierFixture()
+
+		for i := 0; i < 2; i++ {
+			assert.True(t, verifier.Submit(originID, unittest.ResultApprovalFixture(), false))
+		}
+		assert.False(t, verifier.Submit(originID, unittest.ResultApprovalFixture(), false))
+
+		// requested approvals are queued separately
+		assert.True(t, verifier.Submit(originID, unittest.ResultApprovalFixture(), true))
+		assert.Equal(t, 3, verifier.Len())
+	})
+
+	t.Run("requested approvals first", func(t *testing.T) {
+		var verified []*flow.ResultApproval
+		verify := func(_ flow.Identifier, approval *flow.ResultApproval) (bool, error) {
+			verified = append(verified, approval)
+			return true, nil
+		}
+		results := make(chan struct{}, 4)
+		onResult := func(flow.Identifier, *flow.ResultApproval, bool, error) { results <- struct{}{} }
+		verifier := newApprovalVerifier(10, verify, onResult, nil)
+
+		originID := unittest.IdentifierFixture()
+		broadcast := unittest.ResultApprovalFixture()
+		requested := unittest.ResultApprovalFixture()
+		require.True(t, verifier.Submit(originID, broadcast, false))
+		require.True(t, verifier.Submit(originID, requested, true))
+
+		// a single worker, stopped once both approvals are verified
+		quit := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			verifier.Run(quit)
+			close(done)
+		}()
+		<-results
+		<-results
+		close(quit)
+		<-done
+
+		assert.Equal(t, []*flow.ResultApproval{requested, broadcast}, verified)
+	})
+
+	t.Run("results of the workers", func(t *testing.T) {
+		invalid := unittest.ResultApprovalFixture()
+		failing := unittest.ResultApprovalFixture()
+		verify := func(_ flow.Identifier, approval *flow.ResultApproval) (bool, error) {
+			switch approval {
+			case invalid:
+				return false, nil
+			case failing:
+				return false, fmt.Errorf("unexpected failure")
+			}
+			return true, nil
+		}
+
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			valid    int
+			invalids int
+			errs     int
+		)
+		onResult := func(_ flow.Identifier, _ *flow.ResultApproval, ok bool, err error) {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				errs++
+			case ok:
+				valid++
+			default:
+				invalids++
+			}
+		}
+		verifier := newApprovalVerifier(100, verify, onResult, nil)
+
+		quit := make(chan struct{})
+		defer close(quit)
+		for i := 0; i < 4; i++ {
+			go verifier.Run(quit)
+		}
+
+		originID := unittest.IdentifierFixture()
+		wg.Add(52)
+		for i := 0; i < 50; i++ {
+			require.True(t, verifier.Submit(originID, unittest.ResultApprovalFixture(), i%2 == 0))
+		}
+		require.True(t, verifier.Submit(originID, invalid, false))
+		require.True(t, verifier.Submit(originID, failing, true))
+		wg.Wait()
+
+		assert.Equal(t, 50, valid)
+		assert.Equal(t, 1, invalids)
+		assert.Equal(t, 1, errs)
+		assert.Equal(t, 0, verifier.Len())
+	})
+}
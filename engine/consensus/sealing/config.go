@@ -0,0 +1,70 @@
+package sealing
+
+import (
+	"github.com/onflow/flow-go/module/chunks"
+	"github.com/onflow/flow-go/module/validation"
+)
+
+// Config is the configuration of the sealing engine.
+type Config struct {
+	ApprovalWorkers       uint                         // number of workers verifying and storing approvals in parallel
+	ApprovalQueueCapacity uint                         // maximum number of broadcast, and of requested, approvals waiting for verification
+	ApprovalRateLimit     float64                      // maximum rate of approvals queued per origin and second, 0 means no limit
+	ApprovalBurst         int                          // maximum burst of approvals queued per origin, if the rate is limited
+	ValidatedResults      *validation.ValidatedResults // cache of validated results shared with the receipt validator, if any
+	SpockVerifier         *chunks.SpockVerifier        // verifier of the SPoCKs of receipts and approvals, if any
+}
+
+// defaultApprovalWorkers is the default number of approval workers.
+const defaultApprovalWorkers = 4
+
+// defaultApprovalQueueCapacity is the default maximum number of broadcast, and of requested,
+// approvals waiting for verification.
+const defaultApprovalQueueCapacity = 10000
+
+// defaultApprovalBurst is the default burst of approvals per origin, if the rate is limited.
+const defaultApprovalBurst = 100
+
+type OptionFunc func(*Config)
+
+// WithApprovalWorkers sets the number of workers verifying and storing approvals in parallel,
+// separately from the processing of receipts.
+func WithApprovalWorkers(workers uint) OptionFunc {
+	return func(cfg *Config) {
+		cfg.ApprovalWorkers = workers
+	}
+}
+
+// WithApprovalQueueCapacity sets the maximum number of approvals waiting for verification by the
+// approval workers, separately for broadcast and requested approvals. Approvals beyond the capacity
+// are dropped, so that an approval flood doesn't exhaust the memory of the node.
+func WithApprovalQueueCapacity(capacity uint) OptionFunc {
+	return func(cfg *Config) {
+		cfg.ApprovalQueueCapacity = capacity
+	}
+}
+
+// WithApprovalRateLimit limits the rate and burst of approvals queued for each origin. Approvals
+// exceeding the limit are dropped before being queued. A rate of 0 disables the limit.
+func WithApprovalRateLimit(limit float64, burst int) OptionFunc {
+	return func(cfg *Config) {
+		cfg.ApprovalRateLimit = limit
+		cfg.ApprovalBurst = burst
+	}
+}
+
+// WithValidatedResults sets the cache of validated results shared with the receipt validator,
+// which the engine invalidates on epoch transitions.
+func WithValidatedResults(validatedResults *validation.ValidatedResults) OptionFunc {
+	return func(cfg *Config) {
+		cfg.ValidatedResults = validatedResults
+	}
+}
+
+// WithSpockVerifier sets the verifier of the SPoCKs of receipts and approvals. Approvals whose
+// SPoCK matches none of the receipts for the approved result are discarded.
+func WithSpockVerifier(verifier *chunks.SpockVerifier) OptionFunc {
+	return func(cfg *Config) {
+		cfg.SpockVerifier = verifier
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/chunks"
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
@@ -41,7 +42,8 @@ const DefaultRequiredApprovalsForSealConstruction = 0
 const DefaultEmergencySealingThreshold = 400
 
 // DefaultEmergencySealingActive is a flag which indicates when emergency sealing is active, this is a temporary measure
-// to make fire fighting easier while seal & verification is under development.
+// to make fire fighting easier while seal & verification is under development. When active, and no other approval
+// decay schedule is configured, the EmergencySealingSchedule is used.
 const DefaultEmergencySealingActive = false
 
 // Core implements the core algorithms of the sealing protocol, i.e.
@@ -83,7 +85,9 @@ type Core struct {
 	approvalValidator                    module.ApprovalValidator        // used to validate ResultApprovals
 	requestTracker                       *RequestTracker                 // used to keep track of number of approval requests, and blackout periods, by chunk
 	approvalRequestsThreshold            uint64                          // threshold for re-requesting approvals: min height difference between the latest finalized block and the block incorporating a result
-	emergencySealingActive               bool                            // flag which indicates if emergency sealing is active or not. NOTE: this is temporary while sealing & verification is under development
+	approvalDecay                        ApprovalDecaySchedule           // schedule by which the required approvals decay for results which remain unsealed. NOTE: this is temporary while sealing & verification is under development
+	decayedApprovals                     map[flow.Identifier]uint        // decayed number of required approvals by incorporated result, so that each drop is reported once
+	spocks                               *chunks.SpockVerifier           // used to verify the SPoCKs of receipts and approvals, if any
 }
 
 func NewCore(
@@ -107,9 +111,14 @@ func NewCore(
 	receiptValidator module.ReceiptValidator,
 	approvalValidator module.ApprovalValidator,
 	requiredApprovalsForSealConstruction uint,
-	emergencySealingActive bool,
+	approvalDecay ApprovalDecaySchedule,
 	approvalConduit network.Conduit,
 ) (*Core, error) {
+	err := approvalDecay.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval decay schedule: %w", err)
+	}
+
 	c := &Core{
 		log:                                  log.With().Str("engine", "sealing.Core").Logger(),
 		coreMetrics:                          coreMetrics,
@@ -136,7 +145,8 @@ func NewCore(
 		approvalValidator:                    approvalValidator,
 		requestTracker:                       NewRequestTracker(10, 30),
 		approvalRequestsThreshold:            10,
-		emergencySealingActive:               emergencySealingActive,
+		approvalDecay:                        approvalDecay,
+		decayedApprovals:                     make(map[flow.Identifier]uint),
 		approvalConduit:                      approvalConduit,
 	}
 
@@ -292,6 +302,17 @@ func (c *Core) processReceipt(receipt *flow.ExecutionReceipt) (bool, error) {
 		return false, fmt.Errorf("failed to store receipt: %w", err)
 	}
 
+	// The SPoCKs of receipts committing to the same result should match. As we can't tell which of
+	// the execution nodes is faulty, we keep the receipt and only report the mismatch.
+	if c.spocks != nil {
+		err = c.spocks.AddReceipt(receipt, head.Height)
+		if chunks.IsSpockMismatchError(err) {
+			log.Error().Err(err).Msg("SPoCKs of execution receipt mismatch receipts for the same result")
+		} else if err != nil {
+			return false, fmt.Errorf("failed to verify SPoCKs of receipt: %w", err)
+		}
+	}
+
 	// ATTENTION:
 	//
 	// In phase 2, we artificially create IncorporatedResults from incoming
@@ -359,19 +380,12 @@ func (c *Core) storeIncorporatedResult(receipt *flow.ExecutionReceipt) (bool, er
 	return true, nil
 }
 
-// OnApproval processes a new result approval.
+// OnApproval verifies and stores a new result approval.
+// Concurrency safe.
 func (c *Core) OnApproval(originID flow.Identifier, approval *flow.ResultApproval) error {
 	err := c.onApproval(originID, approval)
 	if err != nil {
-		marshalled, err := json.Marshal(approval)
-		if err != nil {
-			marshalled = []byte("json_marshalling_failed")
-		}
-		c.log.Error().Err(err).
-			Hex("origin", logging.ID(originID)).
-			Hex("approval_id", logging.Entity(approval)).
-			Str("approval", string(marshalled)).
-			Msgf("unexpected error processing result approval")
+		c.logApprovalError(originID, approval, err)
 		return fmt.Errorf("internal error processing result approval %x: %w", approval.ID(), err)
 	}
 	return nil
@@ -379,6 +393,54 @@ func (c *Core) OnApproval(originID flow.Identifier, approval *flow.ResultApprova
 
 // OnApproval processes a new result approval.
 func (c *Core) onApproval(originID flow.Identifier, approval *flow.ResultApproval) error {
+	valid, err := c.verifyApproval(originID, approval)
+	if err != nil || !valid {
+		return err
+	}
+	return c.storeApproval(approval)
+}
+
+// VerifyApproval verifies a new result approval, including the signatures and SPoCK of the
+// approval, without storing it. It returns whether the approval is valid; invalid approvals are
+// logged and should be discarded. Errors indicate an unexpected problem in the protocol logic,
+// and should be treated as fatal.
+// Concurrency safe: the sealing engine calls it from its pool of approval verification workers.
+func (c *Core) VerifyApproval(originID flow.Identifier, approval *flow.ResultApproval) (bool, error) {
+	valid, err := c.verifyApproval(originID, approval)
+	if err != nil {
+		c.logApprovalError(originID, approval, err)
+		return false, fmt.Errorf("internal error verifying result approval %x: %w", approval.ID(), err)
+	}
+	return valid, nil
+}
+
+// StoreApproval stores a result approval which was verified by VerifyApproval, so that it counts
+// towards the sealing of the approved chunk. Errors should be treated as fatal.
+// Concurrency safe: the sealing engine calls it from its pool of approval verification workers.
+func (c *Core) StoreApproval(approval *flow.ResultApproval) error {
+	err := c.storeApproval(approval)
+	if err != nil {
+		c.logApprovalError(approval.Body.ApproverID, approval, err)
+		return fmt.Errorf("internal error storing result approval %x: %w", approval.ID(), err)
+	}
+	return nil
+}
+
+func (c *Core) logApprovalError(originID flow.Identifier, approval *flow.ResultApproval, err error) {
+	marshalled, jsonErr := json.Marshal(approval)
+	if jsonErr != nil {
+		marshalled = []byte("json_marshalling_failed")
+	}
+	c.log.Error().Err(err).
+		Hex("origin", logging.ID(originID)).
+		Hex("approval_id", logging.Entity(approval)).
+		Str("approval", string(marshalled)).
+		Msgf("unexpected error processing result approval")
+}
+
+// verifyApproval checks the origin, signatures and SPoCK of the approval. It returns whether the
+// approval is valid, and an error only for unexpected failures.
+func (c *Core) verifyApproval(originID flow.Identifier, approval *flow.ResultApproval) (bool, error) {
 	startTime := time.Now()
 	approvalSpan := c.tracer.StartSpan(approval.ID(), trace.CONMatchOnApproval)
 	defer func() {
@@ -400,32 +462,48 @@ func (c *Core) onApproval(originID flow.Identifier, approval *flow.ResultApprova
 	// networking key.
 	if approval.Body.ApproverID != originID {
 		log.Debug().Msg("discarding approvals from invalid origin")
-		return nil
+		return false, nil
 	}
 
 	err := c.approvalValidator.Validate(approval)
 	if err != nil {
 		if engine.IsOutdatedInputError(err) {
 			log.Debug().Msg("discarding approval for already sealed and finalized block height")
-			return nil
+			return false, nil
 		} else if engine.IsUnverifiableInputError(err) {
 			log.Debug().Msg("discarding unverifiable approval")
-			return nil
+			return false, nil
 		} else if engine.IsInvalidInputError(err) {
 			log.Err(err).Msg("discarding invalid approval")
-			return nil
+			return false, nil
 		} else {
-			return err
+			return false, err
 		}
 	}
 
+	if c.spocks != nil {
+		err = c.spocks.VerifyApproval(approval)
+		if chunks.IsSpockMismatchError(err) {
+			log.Err(err).Msg("discarding approval with mismatching SPoCK")
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to verify SPoCK of approval: %w", err)
+		}
+	}
+
+	return true, nil
+}
+
+// storeApproval adds a verified approval to the memory pool.
+func (c *Core) storeApproval(approval *flow.ResultApproval) error {
 	// store in the memory pool (it won't be added if it is already in there).
 	added, err := c.approvals.Add(approval)
 	if err != nil {
 		return fmt.Errorf("error storing approval in mempool: %w", err)
 	}
 	if !added {
-		log.Debug().Msg("skipping approval already in mempool")
+		c.log.Debug().Hex("approval_id", logging.Entity(approval)).Msg("skipping approval already in mempool")
 		return nil
 	}
 	c.mempool.MempoolEntries(metrics.ResourceApproval, c.approvals.Size())
@@ -574,9 +652,12 @@ func (c *Core) sealableResults() (flow.IncorporatedResultList, *tracker.SealingT
 
 	// go through the results mempool and check which ones we can construct a candidate seal for
 	var results []*flow.IncorporatedResult
+	pending := make(map[flow.Identifier]struct{})
 	for _, incorporatedResult := range c.incorporatedResults.All() {
+		pending[incorporatedResult.ID()] = struct{}{}
+
 		// Can we seal following the happy-path protocol, i.e. do we have sufficient approvals?
-		sealingStatus, err := c.hasEnoughApprovals(incorporatedResult)
+		sealingStatus, err := c.hasEnoughApprovals(incorporatedResult, c.requiredApprovalsForSealConstruction)
 		if state.IsNoValidChildBlockError(err) {
 			continue
 		}
@@ -614,6 +695,13 @@ func (c *Core) sealableResults() (flow.IncorporatedResultList, *tracker.SealingT
 		results = append(results, incorporatedResult) // add the result to the results that should be sealed
 	}
 
+	// forget about the decayed approvals of results which are not pending anymore
+	for incorporatedResultID := range c.decayedApprovals {
+		if _, ok := pending[incorporatedResultID]; !ok {
+			delete(c.decayedApprovals, incorporatedResultID)
+		}
+	}
+
 	return results, sealingTracker, nil
 }
 
@@ -625,6 +713,7 @@ func (c *Core) sealableResults() (flow.IncorporatedResultList, *tracker.SealingT
 // the approval is from an authorized Verifiers (at the block which incorporates
 // the result). Approvals from all authorized Verifiers are added to
 // IncorporatedResult (which internally de-duplicates Approvals).
+// The number of approvals required per chunk is given by `requiredApprovals`.
 // Returns:
 // * sealingRecord: a record holding information about the incorporatedResult's sealing status
 // * error:
@@ -632,9 +721,9 @@ func (c *Core) sealableResults() (flow.IncorporatedResultList, *tracker.SealingT
 //     have a child yet. Then, the chunk assignment cannot be computed.
 //   - All other errors are unexpected and symptoms of internal bugs, uncovered edge cases,
 //     or a corrupted internal node state. These are all fatal failures.
-func (c *Core) hasEnoughApprovals(incorporatedResult *flow.IncorporatedResult) (*tracker.SealingRecord, error) {
+func (c *Core) hasEnoughApprovals(incorporatedResult *flow.IncorporatedResult, requiredApprovals uint) (*tracker.SealingRecord, error) {
 	// shortcut: if we don't require any approvals, any incorporatedResult has enough approvals
-	if c.requiredApprovalsForSealConstruction == 0 {
+	if requiredApprovals == 0 {
 		return tracker.NewRecordWithSufficientApprovals(incorporatedResult), nil
 	}
 
@@ -664,7 +753,7 @@ func (c *Core) hasEnoughApprovals(incorporatedResult *flow.IncorporatedResult) (
 	resultID := incorporatedResult.Result.ID()
 	for _, chunk := range incorporatedResult.Result.Chunks {
 		// if we already have collected a sufficient number of approvals, we don't need to re-check
-		if incorporatedResult.NumberSignatures(chunk.Index) >= c.requiredApprovalsForSealConstruction {
+		if incorporatedResult.NumberSignatures(chunk.Index) >= requiredApprovals {
 			continue
 		}
 
@@ -687,7 +776,7 @@ func (c *Core) hasEnoughApprovals(incorporatedResult *flow.IncorporatedResult) (
 		}
 
 		// abort checking approvals for incorporatedResult if current chunk has insufficient approvals
-		if incorporatedResult.NumberSignatures(chunk.Index) < c.requiredApprovalsForSealConstruction {
+		if incorporatedResult.NumberSignatures(chunk.Index) < requiredApprovals {
 			return tracker.NewRecordWithInsufficientApprovals(incorporatedResult, chunk.Index), nil
 		}
 	}
@@ -698,10 +787,12 @@ func (c *Core) hasEnoughApprovals(incorporatedResult *flow.IncorporatedResult) (
 
 // emergencySealable determines whether an incorporated Result qualifies for "emergency sealing".
 // ATTENTION: this is a temporary solution, which is NOT BFT compatible. When the approval process
-// hangs far enough behind finalization (measured in finalized but unsealed blocks), emergency
-// sealing kicks in. This will be removed when implementation of seal & verification is finished.
+// hangs far enough behind finalization (measured in finalized but unsealed blocks), the number of
+// required approvals decays according to the approval decay schedule, and the result qualifies
+// for emergency sealing if it has enough approvals for the decayed number. This will be removed
+// when implementation of seal & verification is finished.
 func (c *Core) emergencySealable(result *flow.IncorporatedResult, finalized *flow.Header) (bool, error) {
-	if !c.emergencySealingActive {
+	if len(c.approvalDecay) == 0 {
 		return false, nil
 	}
 
@@ -709,10 +800,36 @@ func (c *Core) emergencySealable(result *flow.IncorporatedResult, finalized *flo
 	if err != nil {
 		return false, fmt.Errorf("could not get block %v: %w", result.IncorporatedBlockID, err)
 	}
-	// Criterion for emergency sealing:
-	// there must be at least DefaultEmergencySealingThreshold number of blocks between
-	// the block that _incorporates_ result and the latest finalized block
-	return incorporatedBlock.Height+DefaultEmergencySealingThreshold <= finalized.Height, nil
+	// the number of blocks between the block that _incorporates_ result and the latest
+	// finalized block determines how far the required approvals have decayed
+	var unsealedBlocks uint64
+	if finalized.Height > incorporatedBlock.Height {
+		unsealedBlocks = finalized.Height - incorporatedBlock.Height
+	}
+	requiredApprovals := c.approvalDecay.RequiredApprovals(c.requiredApprovalsForSealConstruction, unsealedBlocks)
+	if requiredApprovals >= c.requiredApprovalsForSealConstruction {
+		return false, nil
+	}
+
+	// report each drop of the required approvals once per incorporated result
+	incorporatedResultID := result.ID()
+	previous, decayed := c.decayedApprovals[incorporatedResultID]
+	if !decayed || requiredApprovals < previous {
+		c.decayedApprovals[incorporatedResultID] = requiredApprovals
+		c.metrics.ApprovalThresholdDecayed()
+		c.log.Warn().
+			Hex("result_id", logging.Entity(result.Result)).
+			Hex("incorporated_block_id", result.IncorporatedBlockID[:]).
+			Uint64("unsealed_blocks", unsealedBlocks).
+			Uint("required_approvals", requiredApprovals).
+			Msg("required approvals for sealing result decayed")
+	}
+
+	sealingStatus, err := c.hasEnoughApprovals(result, requiredApprovals)
+	if err != nil {
+		return false, fmt.Errorf("could not check decayed approvals: %w", err)
+	}
+	return sealingStatus.SufficientApprovalsForSealing, nil
 }
 
 // resultHasMultipleReceipts implements an additional _temporary_ safety measure:
@@ -772,7 +889,7 @@ func (c *Core) sealResult(incorporatedResult *flow.IncorporatedResult) error {
 		return fmt.Errorf("processing malformed result, whose correctness should have been enforced before: %w", err)
 	}
 
-	// TODO: Check SPoCK proofs
+	// SPoCKs of the approvals were checked against the receipts when processing the approvals
 
 	// generate & store seal
 	seal := &flow.Seal{
@@ -837,11 +954,42 @@ func (c *Core) clearPools(sealedIDs []flow.Identifier) error {
 	// a finalized seal. Results of sufficient height for forks that conflict with the finalized fork
 	// are retained in the mempool. However, such orphaned forks do not grow anymore and their
 	// results will be progressively flushed out with increasing sealed-finalized height.
-	err = c.receipts.PruneUpToHeight(sealed.Height)
+	prunedBelowSealed, err := c.receipts.PruneUpToHeight(sealed.Height)
 	if err != nil {
 		return fmt.Errorf("failed to clean receipts mempool: %w", err)
 	}
 
+	// As orphaned forks are only flushed out once the sealed height passes them, we also prune
+	// the results for orphaned blocks up to the finalized height, and the results conflicting
+	// with the sealed result right away. Otherwise, they accumulate while sealing is stalled.
+	sealedResult, _, err := c.state.Sealed().SealedResult()
+	if err != nil {
+		return fmt.Errorf("could not get sealed result: %w", err)
+	}
+	finalized := func(height uint64) (flow.Identifier, bool) {
+		header, err := c.headersDB.ByHeight(height)
+		if err != nil {
+			// keep the results if we can't tell whether their block is orphaned
+			return flow.ZeroID, false
+		}
+		return header.ID(), true
+	}
+	prunedUnsealable, err := c.receipts.PruneUnsealable(sealedResult.ID(), sealed.Height, finalized)
+	if err != nil {
+		return fmt.Errorf("failed to prune unsealable results from receipts mempool: %w", err)
+	}
+	if c.spocks != nil {
+		c.spocks.PruneUpToHeight(sealed.Height)
+	}
+	if prunedBelowSealed.Total()+prunedUnsealable.Total() > 0 {
+		c.log.Debug().
+			Uint64("sealed_height", sealed.Height).
+			Uint("below_sealed_height", prunedBelowSealed.BelowLowestHeight).
+			Uint("conflicting_seal", prunedUnsealable.ConflictingSeal).
+			Uint("orphaned", prunedUnsealable.Orphaned).
+			Msg("pruned receipts mempool")
+	}
+
 	// for each memory pool, clear if the related block is no longer relevant or
 	// if the seal was already built for it (except for seals themselves)
 	for _, result := range c.incorporatedResults.All() {
@@ -0,0 +1,155 @@
+package sealing
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+	"github.com/onflow/flow-go/module/metrics"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/module/trace"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// sealingShape describes the synthetic load the sealing core is benchmarked with.
+type sealingShape struct {
+	results           int     // number of incorporated results in the mempool
+	chunks            uint    // number of chunks of each result
+	verifiers         int     // number of authorized verification nodes
+	assigned          int     // number of verification nodes assigned to each chunk
+	approvalRate      float64 // probability of an assigned verification node to have approved its chunk
+	requiredApprovals uint    // number of approvals required for each chunk
+}
+
+// BenchmarkSealableResults drives the sealing core with synthetic mempools of incorporated results and
+// approvals of different sizes and shapes. Besides the latency of determining the sealable results, it
+// reports the fraction of the results which are sealable.
+func BenchmarkSealableResults(b *testing.B) {
+	shapes := []struct {
+		name  string
+		shape sealingShape
+	}{
+		{"baseline", sealingShape{results: 100, chunks: 10, verifiers: 10, assigned: 3, approvalRate: 1, requiredApprovals: 1}},
+		{"result_backlog", sealingShape{results: 1000, chunks: 10, verifiers: 10, assigned: 3, approvalRate: 1, requiredApprovals: 1}},
+		{"many_chunks", sealingShape{results: 100, chunks: 100, verifiers: 10, assigned: 3, approvalRate: 1, requiredApprovals: 1}},
+		{"low_approval_rate", sealingShape{results: 100, chunks: 10, verifiers: 10, assigned: 3, approvalRate: 0.3, requiredApprovals: 1}},
+		{"high_required_approvals", sealingShape{results: 100, chunks: 10, verifiers: 20, assigned: 5, approvalRate: 0.9, requiredApprovals: 3}},
+	}
+
+	for _, s := range shapes {
+		b.Run(s.name, func(b *testing.B) {
+			benchmarkSealableResults(b, s.shape)
+		})
+	}
+}
+
+func benchmarkSealableResults(b *testing.B, shape sealingShape) {
+	rng := rand.New(rand.NewSource(1)) // deterministic shapes across runs
+	noop := metrics.NewNoopCollector()
+
+	verifiers := unittest.IdentityListFixture(shape.verifiers, unittest.WithRole(flow.RoleVerification))
+	final := unittest.BlockHeaderFixture()
+	snapshot := &protocol.Snapshot{}
+	snapshot.On("Head").Return(&final, nil)
+	snapshot.On("Identities", mock.Anything).Return(verifiers, nil)
+	state := &protocol.State{}
+	state.On("Final").Return(snapshot)
+	state.On("Sealed").Return(snapshot)
+	state.On("AtHeight", mock.Anything).Return(snapshot)
+	state.On("AtBlockID", mock.Anything).Return(snapshot)
+
+	approvals, err := stdmap.NewApprovals(uint(shape.results) * uint(shape.chunks) * uint(shape.assigned))
+	require.NoError(b, err)
+
+	// each result has two receipts from different execution nodes, chunks assigned to a sample of the
+	// verification nodes, and approvals from the assigned verification nodes according to the approval rate
+	results := make([]*flow.ExecutionResult, 0, shape.results)
+	assignments := make(map[*flow.ExecutionResult]*chunks.Assignment, shape.results)
+	receipts := make(map[flow.Identifier]flow.ExecutionReceiptList, shape.results)
+	for i := 0; i < shape.results; i++ {
+		result := unittest.ExecutionResultFixture(unittest.WithChunks(shape.chunks))
+		resultID := result.ID()
+		results = append(results, result)
+		receipts[result.BlockID] = flow.ExecutionReceiptList{
+			unittest.ExecutionReceiptFixture(unittest.WithResult(result)),
+			unittest.ExecutionReceiptFixture(unittest.WithResult(result)),
+		}
+
+		assignment := chunks.NewAssignment()
+		for _, chunk := range result.Chunks {
+			assigned := verifiers.Sample(uint(shape.assigned)).NodeIDs()
+			assignment.Add(chunk, assigned)
+			for _, verifierID := range assigned {
+				if rng.Float64() >= shape.approvalRate {
+					continue
+				}
+				_, err := approvals.Add(unittest.ResultApprovalFixture(
+					unittest.WithExecutionResultID(resultID),
+					unittest.WithApproverID(verifierID),
+					unittest.WithChunk(chunk.Index),
+				))
+				require.NoError(b, err)
+			}
+		}
+		assignments[result] = assignment
+	}
+
+	assigner := &mockmodule.ChunkAssigner{}
+	assigner.On("Assign", mock.Anything, mock.Anything).Return(
+		func(result *flow.ExecutionResult, _ flow.Identifier) *chunks.Assignment {
+			return assignments[result]
+		},
+		nil,
+	)
+	receiptsDB := &storage.ExecutionReceipts{}
+	receiptsDB.On("ByBlockID", mock.Anything).Return(
+		func(blockID flow.Identifier) flow.ExecutionReceiptList {
+			return receipts[blockID]
+		},
+		nil,
+	)
+
+	core := &Core{
+		log:                                  zerolog.Nop(),
+		tracer:                               trace.NewNoopTracer(),
+		coreMetrics:                          noop,
+		mempool:                              noop,
+		metrics:                              noop,
+		state:                                state,
+		receiptsDB:                           receiptsDB,
+		approvals:                            approvals,
+		assigner:                             assigner,
+		requiredApprovalsForSealConstruction: shape.requiredApprovals,
+		decayedApprovals:                     make(map[flow.Identifier]uint),
+	}
+
+	var sealable flow.IncorporatedResultList
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// incorporated results cache the approvals matched to their chunks, hence each iteration
+		// starts from fresh incorporated results
+		b.StopTimer()
+		incorporatedResults, err := stdmap.NewIncorporatedResults(uint(shape.results))
+		require.NoError(b, err)
+		for _, result := range results {
+			_, err := incorporatedResults.Add(flow.NewIncorporatedResult(result.BlockID, result))
+			require.NoError(b, err)
+		}
+		core.incorporatedResults = incorporatedResults
+		b.StartTimer()
+
+		sealable, _, err = core.sealableResults()
+		require.NoError(b, err)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(len(sealable))/float64(shape.results), "sealable_ratio")
+}
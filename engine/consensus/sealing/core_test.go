@@ -110,7 +110,7 @@ func (ms *SealingSuite) SetupTest() {
 		requestTracker:                       NewRequestTracker(1, 3),
 		approvalRequestsThreshold:            10,
 		requiredApprovalsForSealConstruction: RequiredApprovalsForSealConstructionTestingValue,
-		emergencySealingActive:               false,
+		decayedApprovals:                     make(map[flow.Identifier]uint),
 		approvalValidator:                    ms.approvalValidator,
 	}
 }
@@ -547,7 +547,7 @@ func (ms *SealingSuite) TestSealableResultsInsufficientApprovals() {
 // that are deep enough but still without verifications.
 func (ms *SealingSuite) TestSealableResultsEmergencySealingMultipleCandidates() {
 	// make sure that emergency sealing is enabled
-	ms.sealing.emergencySealingActive = true
+	ms.sealing.approvalDecay = EmergencySealingSchedule()
 	emergencySealingCandidates := make([]flow.Identifier, 10)
 
 	for i := range emergencySealingCandidates {
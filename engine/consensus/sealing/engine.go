@@ -15,6 +15,7 @@ import (
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/events"
 	"github.com/onflow/flow-go/storage"
 )
 
@@ -26,11 +27,9 @@ type Event struct {
 // defaultReceiptQueueCapacity maximum capacity of receipts queue
 const defaultReceiptQueueCapacity = 10000
 
-// defaultApprovalQueueCapacity maximum capacity of approvals queue
-const defaultApprovalQueueCapacity = 10000
-
-// defaultApprovalResponseQueueCapacity maximum capacity of approval requests queue
-const defaultApprovalResponseQueueCapacity = 10000
+// sealingCheckFallbackInterval is the interval of sealing checks in absence of any events,
+// e.g. to recover from missed notifications or to request missing receipts and approvals
+const sealingCheckFallbackInterval = 10 * time.Second
 
 type (
 	EventSink chan *Event // Channel to push pending events
@@ -39,8 +38,14 @@ type (
 // Engine is a wrapper for sealing `Core` which implements logic for
 // queuing and filtering network messages which later will be processed by sealing engine.
 // Purpose of this struct is to provide an efficient way how to consume messages from network layer and pass
-// them to `Core`. Engine runs 2 separate gorourtines that perform pre-processing and consuming messages by Core.
+// them to `Core`. Engine runs 2 separate gorourtines that perform pre-processing and consuming messages by Core,
+// plus a bounded pool of approval workers which verify approvals in parallel and store the valid ones, so that
+// a flood of approvals doesn't stall the processing of receipts and the sealing checks. Approvals are rate
+// limited per origin before being queued for verification.
+// Sealing checks are event-driven: they are triggered by processed messages and finalized blocks, with a
+// low-frequency fallback tick.
 type Engine struct {
+	events.Noop                          // satisfy protocol events consumer interface
 	unit                                 *engine.Unit
 	log                                  zerolog.Logger
 	me                                   module.Local
@@ -48,13 +53,13 @@ type Engine struct {
 	cacheMetrics                         module.MempoolMetrics
 	engineMetrics                        module.EngineMetrics
 	receiptSink                          EventSink
-	approvalSink                         EventSink
-	requestedApprovalSink                EventSink
 	pendingReceipts                      *fifoqueue.FifoQueue // TODO replace with engine.FifoMessageStore
-	pendingApprovals                     *fifoqueue.FifoQueue
-	pendingRequestedApprovals            *fifoqueue.FifoQueue
+	approvals                            *approvalVerifier    // verifies approvals in the pool of approval workers
 	pendingEventSink                     EventSink
+	sealingCheckNotifier                 chan struct{} // pending sealing check, capacity 1 to coalesce notifications
 	requiredApprovalsForSealConstruction uint
+	cfg                                  Config
+	approvalLimiter                      approvalRateLimiter // only accessed by processEvents
 }
 
 // NewEngine constructs new `EngineEngine` which runs on it's own unit.
@@ -79,7 +84,28 @@ func NewEngine(log zerolog.Logger,
 	receiptValidator module.ReceiptValidator,
 	approvalValidator module.ApprovalValidator,
 	requiredApprovalsForSealConstruction uint,
-	emergencySealingActive bool) (*Engine, error) {
+	approvalDecay ApprovalDecaySchedule,
+	options ...OptionFunc) (*Engine, error) {
+
+	cfg := Config{
+		ApprovalWorkers:       defaultApprovalWorkers,
+		ApprovalQueueCapacity: defaultApprovalQueueCapacity,
+		ApprovalRateLimit:     0, // no rate limit
+		ApprovalBurst:         defaultApprovalBurst,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	if cfg.ApprovalWorkers < 1 {
+		return nil, fmt.Errorf("at least one approval worker is required")
+	}
+	if cfg.ApprovalQueueCapacity < 1 {
+		return nil, fmt.Errorf("approval queue capacity must be positive")
+	}
+	if cfg.ApprovalRateLimit < 0 {
+		return nil, fmt.Errorf("invalid negative approval rate limit (%f)", cfg.ApprovalRateLimit)
+	}
+
 	e := &Engine{
 		unit:                                 engine.NewUnit(),
 		log:                                  log,
@@ -88,10 +114,11 @@ func NewEngine(log zerolog.Logger,
 		engineMetrics:                        engineMetrics,
 		cacheMetrics:                         mempool,
 		receiptSink:                          make(EventSink),
-		approvalSink:                         make(EventSink),
-		requestedApprovalSink:                make(EventSink),
 		pendingEventSink:                     make(EventSink),
+		sealingCheckNotifier:                 make(chan struct{}, 1),
 		requiredApprovalsForSealConstruction: requiredApprovalsForSealConstruction,
+		cfg:                                  cfg,
+		approvalLimiter:                      newApprovalRateLimiter(cfg.ApprovalRateLimit, cfg.ApprovalBurst),
 	}
 
 	// FIFO queue for inbound receipts
@@ -104,24 +131,6 @@ func NewEngine(log zerolog.Logger,
 		return nil, fmt.Errorf("failed to create queue for inbound receipts: %w", err)
 	}
 
-	// FIFO queue for broadcasted approvals
-	e.pendingApprovals, err = fifoqueue.NewFifoQueue(
-		fifoqueue.WithCapacity(defaultApprovalQueueCapacity),
-		fifoqueue.WithLengthObserver(func(len int) { mempool.MempoolEntries(metrics.ResourceApprovalQueue, uint(len)) }),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create queue for inbound approvals: %w", err)
-	}
-
-	// FiFo queue for requested approvals
-	e.pendingRequestedApprovals, err = fifoqueue.NewFifoQueue(
-		fifoqueue.WithCapacity(defaultApprovalResponseQueueCapacity),
-		fifoqueue.WithLengthObserver(func(len int) { mempool.MempoolEntries(metrics.ResourceApprovalResponseQueue, uint(len)) }),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create queue for requested approvals: %w", err)
-	}
-
 	// register engine with the receipt provider
 	_, err = net.Register(engine.ReceiveReceipts, e)
 	if err != nil {
@@ -142,10 +151,18 @@ func NewEngine(log zerolog.Logger,
 
 	e.core, err = NewCore(log, engineMetrics, tracer, mempool, conMetrics, state, me, receiptRequester, receiptsDB, headersDB,
 		indexDB, incorporatedResults, receipts, approvals, seals, pendingReceipts, assigner, receiptValidator, approvalValidator,
-		requiredApprovalsForSealConstruction, emergencySealingActive, approvalConduit)
+		requiredApprovalsForSealConstruction, approvalDecay, approvalConduit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init sealing engine: %w", err)
 	}
+	e.core.spocks = cfg.SpockVerifier
+
+	// bounded queues of broadcast and requested approvals, verified by the approval workers
+	e.approvals = newApprovalVerifier(cfg.ApprovalQueueCapacity, e.core.VerifyApproval, e.onApprovalVerified,
+		func(requested, broadcast int) {
+			mempool.MempoolEntries(metrics.ResourceApprovalResponseQueue, uint(requested))
+			mempool.MempoolEntries(metrics.ResourceApprovalQueue, uint(broadcast))
+		})
 
 	return e, nil
 }
@@ -169,12 +186,6 @@ func (e *Engine) processEvents() {
 		if val, ok := e.pendingReceipts.Head(); ok {
 			return val.(*Event), e.receiptSink, e.pendingReceipts
 		}
-		if val, ok := e.pendingRequestedApprovals.Head(); ok {
-			return val.(*Event), e.requestedApprovalSink, e.pendingRequestedApprovals
-		}
-		if val, ok := e.pendingApprovals.Head(); ok {
-			return val.(*Event), e.approvalSink, e.pendingApprovals
-		}
 		return nil, nil, nil
 	}
 
@@ -192,7 +203,8 @@ func (e *Engine) processEvents() {
 	}
 }
 
-// processPendingEvent saves pending event in corresponding queue for further processing by `Core`.
+// processPendingEvent saves pending event in corresponding queue for further processing by `Core`, or by
+// the approval workers.
 // While this function runs in separate goroutine it shouldn't do heavy processing to maintain efficient data polling/pushing.
 func (e *Engine) processPendingEvent(event *Event) {
 	switch event.Msg.(type) {
@@ -205,14 +217,27 @@ func (e *Engine) processPendingEvent(event *Event) {
 			// if we don't require approvals to construct a seal, don't even process approvals.
 			return
 		}
-		e.pendingApprovals.Push(event)
+		e.queueApproval(event.OriginID, event.Msg.(*flow.ResultApproval), false)
 	case *messages.ApprovalResponse:
 		e.engineMetrics.MessageReceived(metrics.EngineSealing, metrics.MessageResultApproval)
 		if e.requiredApprovalsForSealConstruction < 1 {
 			// if we don't require approvals to construct a seal, don't even process approvals.
 			return
 		}
-		e.pendingRequestedApprovals.Push(event)
+		e.queueApproval(event.OriginID, &event.Msg.(*messages.ApprovalResponse).Approval, true)
+	}
+}
+
+// queueApproval queues the approval for verification by the approval workers, unless its origin
+// exceeds the approval rate limit. When the approval workers can't keep up, the queue fills up and
+// further approvals are dropped, until the workers catch up.
+func (e *Engine) queueApproval(originID flow.Identifier, approval *flow.ResultApproval, requested bool) {
+	if !e.approvalLimiter.Allow(originID) {
+		e.log.Debug().Hex("origin_id", originID[:]).Msg("dropping approval exceeding rate limit of origin")
+		return
+	}
+	if !e.approvals.Submit(originID, approval, requested) {
+		e.log.Debug().Hex("origin_id", originID[:]).Msg("dropping approval as approval queue is full")
 	}
 }
 
@@ -221,34 +246,31 @@ func (e *Engine) consumeEvents() {
 	// Context:
 	// We expect a lot more Approvals compared to blocks or receipts. However, the level of
 	// information only changes significantly with new blocks or new receipts.
-	// We used to kick off the sealing check after every approval and receipt. In cases where
-	// the sealing check takes a lot more time than processing the actual messages (which we
-	// assume for the current implementation), we incur a large overhead as we check a lot
-	// of conditions, which only change with new blocks or new receipts.
-	// TEMPORARY FIX: to avoid sealing checks to monopolize the engine and delay processing
-	// of receipts and approvals. Specifically, we schedule sealing checks every 2 seconds.
-	checkSealingTicker := make(chan struct{})
-	defer close(checkSealingTicker)
-	e.unit.LaunchPeriodically(func() {
-		checkSealingTicker <- struct{}{}
-	}, 2*time.Second, 10*time.Second)
+	// In cases where the sealing check takes a lot more time than processing the actual
+	// messages (which we assume for the current implementation), checking for sealing after
+	// every message incurs a large overhead. Therefore, processed messages and finalized blocks
+	// only notify a pending sealing check, and notifications are coalesced until the check is
+	// performed. Queued messages are processed first, so that a sealing check covers all
+	// messages which were available when it started.
+	e.unit.LaunchPeriodically(e.notifySealingCheck, sealingCheckFallbackInterval, sealingCheckFallbackInterval)
 
 	for {
 		var err error
 		select {
 		case event := <-e.receiptSink:
-			err = e.core.OnReceipt(event.OriginID, event.Msg.(*flow.ExecutionReceipt))
-			e.engineMetrics.MessageHandled(metrics.EngineSealing, metrics.MessageExecutionReceipt)
-		case event := <-e.approvalSink:
-			err = e.core.OnApproval(event.OriginID, event.Msg.(*flow.ResultApproval))
-			e.engineMetrics.MessageHandled(metrics.EngineSealing, metrics.MessageResultApproval)
-		case event := <-e.requestedApprovalSink:
-			err = e.core.OnApproval(event.OriginID, &event.Msg.(*messages.ApprovalResponse).Approval)
-			e.engineMetrics.MessageHandled(metrics.EngineSealing, metrics.MessageResultApproval)
-		case <-checkSealingTicker:
-			err = e.core.CheckSealing()
+			err = e.processReceipt(event)
 		case <-e.unit.Quit():
 			return
+		default:
+			// no queued messages, wait for messages or a sealing check
+			select {
+			case event := <-e.receiptSink:
+				err = e.processReceipt(event)
+			case <-e.sealingCheckNotifier:
+				err = e.core.CheckSealing()
+			case <-e.unit.Quit():
+				return
+			}
 		}
 		if err != nil {
 			// Public methods of `Core` are supposed to handle all errors internally.
@@ -259,6 +281,49 @@ func (e *Engine) consumeEvents() {
 	}
 }
 
+func (e *Engine) processReceipt(event *Event) error {
+	err := e.core.OnReceipt(event.OriginID, event.Msg.(*flow.ExecutionReceipt))
+	e.engineMetrics.MessageHandled(metrics.EngineSealing, metrics.MessageExecutionReceipt)
+	e.notifySealingCheck()
+	return err
+}
+
+// onApprovalVerified is called by the approval workers with the outcome of the verification of an
+// approval. Valid approvals are stored, and trigger a sealing check.
+func (e *Engine) onApprovalVerified(_ flow.Identifier, approval *flow.ResultApproval, valid bool, err error) {
+	e.engineMetrics.MessageHandled(metrics.EngineSealing, metrics.MessageResultApproval)
+	if err == nil && valid {
+		err = e.core.StoreApproval(approval)
+		e.notifySealingCheck()
+	}
+	if err != nil {
+		// see consumeEvents
+		e.log.Fatal().Err(err).Msgf("fatal internal error in sealing core logic")
+	}
+}
+
+// notifySealingCheck schedules a sealing check, unless one is pending already.
+func (e *Engine) notifySealingCheck() {
+	select {
+	case e.sealingCheckNotifier <- struct{}{}:
+	default:
+	}
+}
+
+// BlockFinalized implements protocol.Consumer. Finalized blocks can make results sealable,
+// so they trigger a sealing check.
+func (e *Engine) BlockFinalized(*flow.Header) {
+	e.notifySealingCheck()
+}
+
+// EpochTransition implements protocol.Consumer. The cache of validated results is invalidated
+// on epoch transitions.
+func (e *Engine) EpochTransition(uint64, *flow.Header) {
+	if e.cfg.ValidatedResults != nil {
+		e.cfg.ValidatedResults.Invalidate()
+	}
+}
+
 // SubmitLocal submits an event originating on the local node.
 func (e *Engine) SubmitLocal(event interface{}) {
 	e.Submit(e.me.NodeID(), event)
@@ -296,7 +361,7 @@ func (e *Engine) HandleReceipt(originID flow.Identifier, receipt flow.Entity) {
 // upon initialization.
 func (e *Engine) Ready() <-chan struct{} {
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(2 + int(e.cfg.ApprovalWorkers))
 	e.unit.Launch(func() {
 		wg.Done()
 		e.processEvents()
@@ -305,6 +370,12 @@ func (e *Engine) Ready() <-chan struct{} {
 		wg.Done()
 		e.consumeEvents()
 	})
+	for i := uint(0); i < e.cfg.ApprovalWorkers; i++ {
+		e.unit.Launch(func() {
+			wg.Done()
+			e.approvals.Run(e.unit.Quit())
+		})
+	}
 	return e.unit.Ready(func() {
 		wg.Wait()
 	})
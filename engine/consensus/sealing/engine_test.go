@@ -47,8 +47,6 @@ func (ms *SealingEngineSuite) SetupTest() {
 	ms.receiptValidator = &mockmodule.ReceiptValidator{}
 	ms.approvalValidator = &mockmodule.ApprovalValidator{}
 
-	approvalsProvider := make(chan *Event)
-	approvalResponseProvider := make(chan *Event)
 	receiptsProvider := make(chan *Event)
 
 	ms.engine = &Engine{
@@ -78,20 +76,18 @@ func (ms *SealingEngineSuite) SetupTest() {
 			requestTracker:                       NewRequestTracker(1, 3),
 			approvalRequestsThreshold:            10,
 			requiredApprovalsForSealConstruction: RequiredApprovalsForSealConstructionTestingValue,
-			emergencySealingActive:               false,
+			decayedApprovals:                     make(map[flow.Identifier]uint),
 		},
-		approvalSink:                         approvalsProvider,
-		requestedApprovalSink:                approvalResponseProvider,
 		receiptSink:                          receiptsProvider,
 		pendingEventSink:                     make(chan *Event),
 		engineMetrics:                        metrics,
 		cacheMetrics:                         metrics,
 		requiredApprovalsForSealConstruction: RequiredApprovalsForSealConstructionTestingValue,
+		cfg:                                  Config{ApprovalWorkers: 2},
 	}
 
 	ms.engine.pendingReceipts, _ = fifoqueue.NewFifoQueue()
-	ms.engine.pendingApprovals, _ = fifoqueue.NewFifoQueue()
-	ms.engine.pendingRequestedApprovals, _ = fifoqueue.NewFifoQueue()
+	ms.engine.approvals = newApprovalVerifier(100, ms.engine.core.VerifyApproval, ms.engine.onApprovalVerified, nil)
 
 	<-ms.engine.Ready()
 }
@@ -0,0 +1,143 @@
+package committer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	execState "github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// BatchLedgerViewCommitter commits views like the LedgerViewCommitter, and can batch the views
+// of a block into a single ledger update. The state commitments and proofs of the views in a batch
+// are derived from pending states of the ledger, which are never applied to the ledger.
+type BatchLedgerViewCommitter struct {
+	*LedgerViewCommitter
+	ldg ledger.PendingUpdateLedger
+}
+
+func NewBatchLedgerViewCommitter(ldg ledger.PendingUpdateLedger, tracer module.Tracer) *BatchLedgerViewCommitter {
+	return &BatchLedgerViewCommitter{
+		LedgerViewCommitter: NewLedgerViewCommitter(ldg, tracer),
+		ldg:                 ldg,
+	}
+}
+
+// NewBatch returns a batch committing the views of a block starting at the given state.
+func (s *BatchLedgerViewCommitter) NewBatch(startState flow.StateCommitment) computer.ViewCommitterBatch {
+	return &LedgerViewCommitterBatch{
+		ldg:        s.ldg,
+		startState: startState,
+		state:      startState,
+	}
+}
+
+// LedgerViewCommitterBatch accumulates the register updates of the views of a block, which are
+// applied to the ledger as a single update on Commit. Views must be committed in order, each on
+// the state commitment of the previous view.
+//
+// Each view is derived as a pending update on top of the pending state of the previous view, so
+// committing a view only hashes the registers it updates. The intermediate state commitments are
+// only returned to the caller, as the end states of the chunks and the states of their proofs:
+// they are never added to the ledger, and can't be read from it once the batch is committed.
+type LedgerViewCommitterBatch struct {
+	ldg        ledger.PendingUpdateLedger
+	startState flow.StateCommitment
+	state      flow.StateCommitment
+	pending    ledger.PendingState // pending state of the last committed view, nil before the first view
+}
+
+func (b *LedgerViewCommitterBatch) CommitView(view state.View, baseState flow.StateCommitment) (newCommit flow.StateCommitment, proof []byte, err error) {
+	if baseState != b.state {
+		return flow.DummyStateCommitment, nil, fmt.Errorf("cannot commit view on state %x, batch is at state %x", baseState, b.state)
+	}
+
+	if b.pending == nil {
+		b.pending, err = b.ldg.NewPendingState(ledger.State(b.startState))
+		if err != nil {
+			return flow.DummyStateCommitment, nil, fmt.Errorf("cannot create pending state: %w", err)
+		}
+	}
+
+	// the proofs are collected at the state before the view, pending states are immutable
+	base := b.pending
+
+	var err1, err2 error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		proof, err2 = b.collectProofs(view, base)
+		wg.Done()
+	}()
+
+	newCommit, err1 = b.commitView(view, base)
+	wg.Wait()
+
+	if err1 != nil {
+		err = multierror.Append(err, err1)
+	}
+	if err2 != nil {
+		err = multierror.Append(err, err2)
+	}
+	return
+}
+
+// commitView derives the pending state resulting from the register updates of the view, and adds them to the batch.
+func (b *LedgerViewCommitterBatch) commitView(view state.View, base ledger.PendingState) (flow.StateCommitment, error) {
+	ids, values := view.RegisterUpdates()
+	keys := execState.RegisterIDSToKeys(ids)
+	vals := execState.RegisterValuesToValues(values)
+
+	update, err := ledger.NewUpdate(base.State(), keys, vals)
+	if err != nil {
+		return flow.DummyStateCommitment, fmt.Errorf("cannot create ledger update: %w", err)
+	}
+
+	pending, err := base.Update(update)
+	if err != nil {
+		return flow.DummyStateCommitment, err
+	}
+
+	b.pending = pending
+	b.state = flow.StateCommitment(pending.State())
+	return b.state, nil
+}
+
+func (b *LedgerViewCommitterBatch) collectProofs(view state.View, base ledger.PendingState) (proof []byte, err error) {
+	allIds := view.AllRegisters()
+	keys := make([]ledger.Key, len(allIds))
+	for i, id := range allIds {
+		keys[i] = execState.RegisterIDToKey(id)
+	}
+
+	query, err := ledger.NewQuery(base.State(), keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create ledger query: %w", err)
+	}
+
+	return base.Prove(query)
+}
+
+// Commit applies the register updates of all committed views to the ledger as a single update, by
+// applying the pending state of the last view, so that the updates are not derived again.
+func (b *LedgerViewCommitterBatch) Commit() (flow.StateCommitment, error) {
+	if b.pending == nil {
+		return b.state, nil
+	}
+
+	newState, err := b.pending.Apply()
+	if err != nil {
+		return flow.DummyStateCommitment, fmt.Errorf("cannot apply pending state: %w", err)
+	}
+
+	if flow.StateCommitment(newState) != b.state {
+		return flow.DummyStateCommitment, fmt.Errorf("committed state %x differs from derived state %x", newState, b.state)
+	}
+	return b.state, nil
+}
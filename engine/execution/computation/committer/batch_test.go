@@ -0,0 +1,111 @@
+package committer_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	execState "github.com/onflow/flow-go/engine/execution/state"
+	fvmUtils "github.com/onflow/flow-go/fvm/utils"
+	led "github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+)
+
+func TestBatchLedgerViewCommitter(t *testing.T) {
+
+	newLedger := func() *complete.Ledger {
+		ledger, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		return ledger
+	}
+
+	// views of the chunks of a block, overwriting and deleting registers of previous chunks
+	views := make([]*fvmUtils.SimpleView, 3)
+	for i := range views {
+		views[i] = fvmUtils.NewSimpleView()
+		for j := 0; j < 10; j++ {
+			err := views[i].Set("owner", "controller", fmt.Sprintf("key-%d", i*5+j), []byte{byte(i), byte(j)})
+			require.NoError(t, err)
+		}
+		_, err := views[i].Get("owner", "controller", "key-100")
+		require.NoError(t, err)
+	}
+	err := views[2].Delete("owner", "controller", "key-0")
+	require.NoError(t, err)
+
+	t.Run("batch matches separate commits", func(t *testing.T) {
+		ledger := newLedger()
+		separate := committer.NewLedgerViewCommitter(ledger, trace.NewNoopTracer())
+
+		batchLedger := newLedger()
+		batchCommitter := committer.NewBatchLedgerViewCommitter(batchLedger, trace.NewNoopTracer())
+
+		startState := flow.StateCommitment(ledger.InitialState())
+		require.Equal(t, startState, flow.StateCommitment(batchLedger.InitialState()))
+
+		batch := batchCommitter.NewBatch(startState)
+
+		state, batchState := startState, startState
+		var intermediateStates []flow.StateCommitment
+		for _, view := range views {
+			var proof, batchProof []byte
+			state, proof, err = separate.CommitView(view, state)
+			require.NoError(t, err)
+
+			batchState, batchProof, err = batch.CommitView(view, batchState)
+			require.NoError(t, err)
+
+			require.Equal(t, state, batchState)
+			require.Equal(t, proof, batchProof)
+			intermediateStates = append(intermediateStates, batchState)
+		}
+
+		// intermediate states are not applied to the ledger until the batch is committed
+		require.Equal(t, 1, batchLedger.ForestSize())
+
+		committed, err := batch.Commit()
+		require.NoError(t, err)
+		require.Equal(t, state, committed)
+		require.Equal(t, 2, batchLedger.ForestSize())
+
+		query, err := led.NewQuery(led.State(committed), nil)
+		require.NoError(t, err)
+		_, err = batchLedger.Get(query)
+		require.NoError(t, err)
+
+		// the intermediate states of the chunks are never applied to the ledger
+		key := execState.RegisterIDToKey(flow.NewRegisterID("owner", "controller", "key-0"))
+		for _, intermediate := range intermediateStates[:len(intermediateStates)-1] {
+			query, err := led.NewQuery(led.State(intermediate), []led.Key{key})
+			require.NoError(t, err)
+			_, err = batchLedger.Get(query)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("views must be committed in order", func(t *testing.T) {
+		ledger := newLedger()
+		batch := committer.NewBatchLedgerViewCommitter(ledger, trace.NewNoopTracer()).
+			NewBatch(flow.StateCommitment(ledger.InitialState()))
+
+		_, _, err := batch.CommitView(views[0], flow.DummyStateCommitment)
+		require.Error(t, err)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		ledger := newLedger()
+		startState := flow.StateCommitment(ledger.InitialState())
+		batch := committer.NewBatchLedgerViewCommitter(ledger, trace.NewNoopTracer()).NewBatch(startState)
+
+		committed, err := batch.Commit()
+		require.NoError(t, err)
+		require.Equal(t, startState, committed)
+	})
+}
@@ -11,15 +11,20 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/uber/jaeger-client-go"
 
+	"github.com/onflow/flow-go/crypto/hash"
 	"github.com/onflow/flow-go/engine/execution"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/fvm/programs"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/mempool/entity"
 	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/seed"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
@@ -34,19 +39,54 @@ type ViewCommitter interface {
 	CommitView(state.View, flow.StateCommitment) (flow.StateCommitment, []byte, error)
 }
 
+// BatchViewCommitter is implemented by committers which can accumulate the views of all
+// collections of a block and apply them to the ledger as a single update.
+type BatchViewCommitter interface {
+	ViewCommitter
+	// NewBatch returns a committer for the views of a block starting at the given state
+	NewBatch(startState flow.StateCommitment) ViewCommitterBatch
+}
+
+// ViewCommitterBatch commits the views of a block. Committing a view derives its state
+// commitment and collects its proofs, while the register updates of all views are only
+// applied to the ledger once the batch is committed.
+type ViewCommitterBatch interface {
+	ViewCommitter
+	// Commit applies the register updates of all committed views and returns the resulting state commitment
+	Commit() (flow.StateCommitment, error)
+}
+
 // A BlockComputer executes the transactions in a block.
 type BlockComputer interface {
 	ExecuteBlock(context.Context, *entity.ExecutableBlock, state.View, *programs.Programs) (*execution.ComputationResult, error)
 }
 
 type blockComputer struct {
-	vm             VirtualMachine
-	vmCtx          fvm.Context
-	metrics        module.ExecutionMetrics
-	tracer         module.Tracer
-	log            zerolog.Logger
-	systemChunkCtx fvm.Context
-	committer      ViewCommitter
+	vm              VirtualMachine
+	vmCtx           fvm.Context
+	metrics         module.ExecutionMetrics
+	tracer          module.Tracer
+	log             zerolog.Logger
+	systemChunkCtx  fvm.Context
+	systemContracts fvm.SystemContracts
+	committer       ViewCommitter
+	protoState      protocol.State
+	journal         storage.ExecutionJournal // optional, checkpoints of the executed collections of blocks
+	// hashes of the scripts of the system contract calls, by activation
+	systemScripts map[*fvm.SystemContractActivation]flow.Identifier
+}
+
+// Option configures a block computer.
+type Option func(*blockComputer)
+
+// WithExecutionJournal persists a checkpoint of the results of every executed collection of a block
+// to the journal, so that the execution of a block interrupted by a restart is resumed from its last
+// executed collection instead of from scratch. The checkpoints of a block are discarded once it is
+// executed.
+func WithExecutionJournal(journal storage.ExecutionJournal) Option {
+	return func(e *blockComputer) {
+		e.journal = journal
+	}
 }
 
 // NewBlockComputer creates a new block executor.
@@ -57,6 +97,8 @@ func NewBlockComputer(
 	tracer module.Tracer,
 	logger zerolog.Logger,
 	committer ViewCommitter,
+	protoState protocol.State,
+	opts ...Option,
 ) (BlockComputer, error) {
 
 	systemChunkCtx := fvm.NewContextFromParent(
@@ -67,15 +109,32 @@ func NewBlockComputer(
 		fvm.WithTransactionProcessors(fvm.NewTransactionInvocator(logger)),
 	)
 
-	return &blockComputer{
-		vm:             vm,
-		vmCtx:          vmCtx,
-		metrics:        metrics,
-		tracer:         tracer,
-		log:            logger,
-		systemChunkCtx: systemChunkCtx,
-		committer:      committer,
-	}, nil
+	systemContracts := fvm.SystemContractsForChain(vmCtx.Chain)
+	// calls are keyed by activation, as different versions of a call can share its name
+	systemScripts := make(map[*fvm.SystemContractActivation]flow.Identifier, len(systemContracts.Activations))
+	for i := range systemContracts.Activations {
+		activation := &systemContracts.Activations[i]
+		script := activation.Call.Script(systemContracts.Chain)
+		systemScripts[activation] = flow.HashToID(hash.NewSHA3_256().ComputeHash(script))
+	}
+
+	e := &blockComputer{
+		vm:              vm,
+		vmCtx:           vmCtx,
+		metrics:         metrics,
+		tracer:          tracer,
+		log:             logger,
+		systemChunkCtx:  systemChunkCtx,
+		systemContracts: systemContracts,
+		committer:       committer,
+		protoState:      protoState,
+		systemScripts:   systemScripts,
+	}
+	for _, apply := range opts {
+		apply(e)
+	}
+
+	return e, nil
 }
 
 // ExecuteBlock executes a block and returns the resulting chunks.
@@ -118,27 +177,59 @@ func (e *blockComputer) executeBlock(
 		return nil, fmt.Errorf("executable block start state is not set")
 	}
 
-	blockCtx := fvm.NewContextFromParent(e.vmCtx, fvm.WithBlockHeader(block.Block.Header))
+	// verification nodes derive the same random source from the header when verifying the chunks
+	randomSource, err := seed.ExecutionRandomSource(block.Block.Header)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive random source for block: %w", err)
+	}
+
+	// the system chunk makes the service contract calls activated for the epoch of the block
+	epochCounter, err := e.protoState.AtBlockID(block.ID()).Epochs().Current().Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch of block: %w", err)
+	}
+
+	blockCtx := fvm.NewContextFromParent(
+		e.vmCtx,
+		fvm.WithBlockHeader(block.Block.Header),
+		fvm.WithBlockRandomSource(randomSource),
+	)
+	systemChunkCtx := fvm.NewContextFromParent(
+		e.systemChunkCtx,
+		fvm.WithBlockHeader(block.Block.Header),
+		fvm.WithBlockRandomSource(randomSource),
+	)
 	collections := block.Collections()
 	res := &execution.ComputationResult{
 		ExecutableBlock:    block,
 		Events:             make([]flow.Event, 0),
+		EventsHashes:       make([]flow.Identifier, 0, len(collections)+1),
+		ComputationUsed:    make([]uint64, 0, len(collections)+1),
+		TransactionCounts:  make([]uint64, 0, len(collections)+1),
 		ServiceEvents:      make([]flow.Event, 0),
 		TransactionResults: make([]flow.TransactionResult, 0),
 		StateCommitments:   make([]flow.StateCommitment, 0),
 		Proofs:             make([][]byte, 0),
+		TransactionErrors:  make(map[errors.ErrorCode]int),
 	}
 
 	var txIndex uint32
-	var err error
 	var wg sync.WaitGroup
 	wg.Add(1)
 
 	stateCommitments := make([]flow.StateCommitment, 0, len(collections)+1)
 	proofs := make([][]byte, 0, len(collections)+1)
 
+	// commit the views of the block as a single update, if the committer supports it
+	committer := e.committer
+	var batch ViewCommitterBatch
+	if batchCommitter, ok := e.committer.(BatchViewCommitter); ok {
+		batch = batchCommitter.NewBatch(*block.StartState)
+		committer = batch
+	}
+
 	bc := blockCommitter{
-		committer: e.committer,
+		committer: committer,
 		blockSpan: blockSpan,
 		tracer:    e.tracer,
 		state:     *block.StartState,
@@ -157,12 +248,44 @@ func (e *blockComputer) executeBlock(
 		wg.Done()
 	}()
 
-	for _, collection := range collections {
+	// resume the execution of the block after the collections executed before a restart, if any
+	checkpoints := e.checkpoints(block)
+	if len(checkpoints) > 0 {
+		// the programs of the parent block may be outdated by the contracts updated in the executed
+		// collections, whose own programs are lost
+		programs.ForceCleanup()
+	}
+	journaling := e.journal != nil
+
+	for i, collection := range collections {
+		if i < len(checkpoints) {
+			checkpoint := checkpoints[i]
+			colView := delta.NewViewFromSnapshot(stateView.(*delta.View).Peek, checkpoint.StateSnapshot)
+			res.AddCheckpoint(checkpoint)
+			txIndex = checkpoint.NextTransactionIndex
+			bc.Commit(colView)
+			err = stateView.MergeView(colView)
+			if err != nil {
+				return nil, fmt.Errorf("cannot merge restored view: %w", err)
+			}
+			continue
+		}
+
 		colView := stateView.NewChild()
+		mark := markResults(res)
 		txIndex, err = e.executeCollection(blockSpan, txIndex, blockCtx, colView, programs, collection, res)
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute collection: %w", err)
 		}
+		if journaling {
+			err = e.journal.Checkpoint(block.ID(), mark.checkpoint(i, txIndex, res))
+			if err != nil {
+				// the execution can only be resumed from consecutive checkpoints
+				e.log.Warn().Err(err).Hex("block_id", logging.Entity(block)).Int("collection_index", i).
+					Msg("could not checkpoint executed collection, stopping checkpoints of the block")
+				journaling = false
+			}
+		}
 		bc.Commit(colView)
 		err = stateView.MergeView(colView)
 		if err != nil {
@@ -173,7 +296,7 @@ func (e *blockComputer) executeBlock(
 	// executing system chunk
 	e.log.Debug().Hex("block_id", logging.Entity(block)).Msg("executing system chunk")
 	colView := stateView.NewChild()
-	_, err = e.executeSystemCollection(blockSpan, txIndex, colView, programs, res)
+	_, err = e.executeSystemCollection(blockSpan, txIndex, systemChunkCtx, e.systemContracts.Active(epochCounter), colView, programs, res)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute system chunk transaction: %w", err)
 	}
@@ -186,16 +309,117 @@ func (e *blockComputer) executeBlock(
 	// close the views and wait for all views to be committed
 	close(bc.views)
 	wg.Wait()
+
+	if batch != nil {
+		_, err = batch.Commit()
+		if err != nil {
+			return nil, fmt.Errorf("cannot commit block updates: %w", err)
+		}
+	}
+	// the reads of the collections restored from checkpoints aren't counted
 	res.StateReads = stateView.(*delta.View).ReadsCount()
 	res.StateCommitments = stateCommitments
 	res.Proofs = proofs
 
+	if e.journal != nil {
+		err = e.journal.Discard(block.ID())
+		if err != nil {
+			e.log.Warn().Err(err).Hex("block_id", logging.Entity(block)).Msg("could not discard checkpoints of executed block")
+		}
+	}
+
 	return res, nil
 }
 
+// checkpoints returns the checkpoints of the collections of the block executed before a restart. Only a
+// sequence of checkpoints of the first collections of the block can be resumed from, other checkpoints
+// are discarded.
+func (e *blockComputer) checkpoints(block *entity.ExecutableBlock) []*execution.CollectionCheckpoint {
+	if e.journal == nil {
+		return nil
+	}
+
+	log := e.log.With().Hex("block_id", logging.Entity(block)).Logger()
+
+	checkpoints, err := e.journal.Checkpoints(block.ID())
+	if err != nil {
+		log.Warn().Err(err).Msg("could not retrieve checkpoints of block, executing it from scratch")
+		return nil
+	}
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	valid := len(checkpoints) <= len(block.CompleteCollections)
+	for i, checkpoint := range checkpoints {
+		if checkpoint.CollectionIndex != i || checkpoint.StateSnapshot == nil {
+			valid = false
+		}
+	}
+	if !valid {
+		log.Warn().Int("checkpoints", len(checkpoints)).Msg("discarding inconsistent checkpoints of block, executing it from scratch")
+		err = e.journal.Discard(block.ID())
+		if err != nil {
+			log.Warn().Err(err).Msg("could not discard checkpoints of block")
+		}
+		return nil
+	}
+
+	log.Info().Int("executed_collections", len(checkpoints)).Msg("resuming execution of block from checkpoints")
+	return checkpoints
+}
+
+// resultsMark marks the results of a block computed before the execution of a collection, so that the
+// results of the collection can be checkpointed once it is executed.
+type resultsMark struct {
+	events             int
+	serviceEvents      int
+	transactionResults int
+	transactionErrors  map[errors.ErrorCode]int
+}
+
+func markResults(res *execution.ComputationResult) resultsMark {
+	transactionErrors := make(map[errors.ErrorCode]int, len(res.TransactionErrors))
+	for code, count := range res.TransactionErrors {
+		transactionErrors[code] = count
+	}
+	return resultsMark{
+		events:             len(res.Events),
+		serviceEvents:      len(res.ServiceEvents),
+		transactionResults: len(res.TransactionResults),
+		transactionErrors:  transactionErrors,
+	}
+}
+
+// checkpoint returns the checkpoint of the results of the collection computed since the mark.
+func (m resultsMark) checkpoint(collectionIndex int, nextTxIndex uint32, res *execution.ComputationResult) *execution.CollectionCheckpoint {
+	transactionErrors := make(map[errors.ErrorCode]int)
+	for code, count := range res.TransactionErrors {
+		if failed := count - m.transactionErrors[code]; failed > 0 {
+			transactionErrors[code] = failed
+		}
+	}
+
+	last := len(res.EventsHashes) - 1
+	return &execution.CollectionCheckpoint{
+		CollectionIndex:      collectionIndex,
+		NextTransactionIndex: nextTxIndex,
+		StateSnapshot:        res.StateSnapshots[len(res.StateSnapshots)-1],
+		Events:               res.Events[m.events:],
+		EventsHash:           res.EventsHashes[last],
+		ServiceEvents:        res.ServiceEvents[m.serviceEvents:],
+		TransactionResults:   res.TransactionResults[m.transactionResults:],
+		ComputationUsed:      res.ComputationUsed[last],
+		TransactionCount:     res.TransactionCounts[last],
+		TransactionErrors:    transactionErrors,
+	}
+}
+
 func (e *blockComputer) executeSystemCollection(
 	blockSpan opentracing.Span,
 	txIndex uint32,
+	systemChunkCtx fvm.Context,
+	activations []*fvm.SystemContractActivation,
 	collectionView state.View,
 	programs *programs.Programs,
 	res *execution.ComputationResult,
@@ -204,15 +428,36 @@ func (e *blockComputer) executeSystemCollection(
 	colSpan := e.tracer.StartSpanFromParent(blockSpan, trace.EXEComputeSystemCollection)
 	defer colSpan.Finish()
 
-	serviceAddress := e.vmCtx.Chain.ServiceAddress()
-	tx := fvm.SystemChunkTransaction(serviceAddress)
-	err := e.executeTransaction(tx, colSpan, collectionView, programs, e.systemChunkCtx, txIndex, res)
-	txIndex++
-	if err != nil {
-		return txIndex, err
+	eventsStart := len(res.Events)
+	gasStart := res.GasUsed
+
+	// every call is a separate transaction, so a failing call doesn't affect the others
+	for _, activation := range activations {
+		call := activation.Call
+		tx, err := e.executeTransaction(call.Transaction(e.systemContracts.Chain), e.systemScripts[activation], colSpan, collectionView, programs, systemChunkCtx, txIndex, res)
+		txIndex++
+		if err != nil {
+			return txIndex, err
+		}
+		if tx.Err == nil {
+			continue
+		}
+
+		e.log.Warn().
+			Str("call", call.Name).
+			Str("error_message", tx.Err.Error()).
+			Msg("system contract call failed")
+
+		event, err := fvm.SystemContractCallFailedEvent(call, tx)
+		if err != nil {
+			return txIndex, fmt.Errorf("could not report failed system contract call %s: %w", call.Name, err)
+		}
+		res.AddServiceEvents([]flow.Event{event})
 	}
+	res.AddEventsHash(flow.EventsList(res.Events[eventsStart:]).Hash())
+	res.AddCollectionStats(res.GasUsed-gasStart, uint64(len(activations)))
 	res.AddStateSnapshot(collectionView.(*delta.View).Interactions())
-	return txIndex, err
+	return txIndex, nil
 }
 
 func (e *blockComputer) executeCollection(
@@ -242,13 +487,17 @@ func (e *blockComputer) executeCollection(
 	}()
 
 	txCtx := fvm.NewContextFromParent(blockCtx, fvm.WithMetricsReporter(e.metrics), fvm.WithTracer(e.tracer))
+	eventsStart := len(res.Events)
+	gasStart := res.GasUsed
 	for _, txBody := range collection.Transactions {
-		err := e.executeTransaction(txBody, colSpan, collectionView, programs, txCtx, txIndex, res)
+		_, err := e.executeTransaction(txBody, flow.ZeroID, colSpan, collectionView, programs, txCtx, txIndex, res)
 		txIndex++
 		if err != nil {
 			return txIndex, err
 		}
 	}
+	res.AddEventsHash(flow.EventsList(res.Events[eventsStart:]).Hash())
+	res.AddCollectionStats(res.GasUsed-gasStart, uint64(len(collection.Transactions)))
 	res.AddStateSnapshot(collectionView.(*delta.View).Interactions())
 	e.log.Info().Str("collectionID", collection.Guarantee.CollectionID.String()).
 		Str("blockID", collection.Guarantee.ReferenceBlockID.String()).
@@ -259,32 +508,32 @@ func (e *blockComputer) executeCollection(
 	return txIndex, nil
 }
 
+// executeTransaction executes a transaction of the block. The hash of the script of system transactions
+// labels their metrics, it is the zero ID for the transactions of users.
 func (e *blockComputer) executeTransaction(
 	txBody *flow.TransactionBody,
+	systemScriptHash flow.Identifier,
 	colSpan opentracing.Span,
 	collectionView state.View,
 	programs *programs.Programs,
 	ctx fvm.Context,
 	txIndex uint32,
 	res *execution.ComputationResult,
-) error {
+) (*fvm.TransactionProcedure, error) {
 
 	startedAt := time.Now()
 	var txSpan opentracing.Span
 	var traceID string
-	// call tracing
-	txSpan = e.tracer.StartSpanFromParent(colSpan, trace.EXEComputeTransaction)
+	// call tracing, the spans of the transaction are tagged with its ID to correlate them
+	// with the spans of the transaction in other engines
+	txTag := opentracing.Tag{Key: trace.TransactionIDTag, Value: txBody.ID().String()}
+	txSpan = e.tracer.StartSpanFromParent(colSpan, trace.EXEComputeTransaction, txTag)
 
 	if sc, ok := txSpan.Context().(jaeger.SpanContext); ok {
 		traceID = sc.TraceID().String()
 	}
 
-	defer func() {
-		txSpan.LogFields(
-			log.String("transaction.ID", txBody.ID().String()),
-		)
-		txSpan.Finish()
-	}()
+	defer txSpan.Finish()
 
 	e.log.Debug().
 		Hex("tx_id", logging.Entity(txBody)).
@@ -297,7 +546,7 @@ func (e *blockComputer) executeTransaction(
 
 	err := e.vm.Run(ctx, tx, txView, programs)
 	if err != nil {
-		return fmt.Errorf("failed to execute transaction: %w", err)
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
 	}
 
 	txResult := flow.TransactionResult{
@@ -306,6 +555,7 @@ func (e *blockComputer) executeTransaction(
 
 	if tx.Err != nil {
 		txResult.ErrorMessage = tx.Err.Error()
+		res.AddTransactionError(tx.Err.Code())
 		e.log.Debug().
 			Hex("tx_id", logging.Entity(txBody)).
 			Str("error_message", tx.Err.Error()).
@@ -317,14 +567,14 @@ func (e *blockComputer) executeTransaction(
 			Msg("transaction executed successfully")
 	}
 
-	mergeSpan := e.tracer.StartSpanFromParent(txSpan, trace.EXEMergeTransactionView)
+	mergeSpan := e.tracer.StartSpanFromParent(txSpan, trace.EXEMergeTransactionView, txTag)
 	defer mergeSpan.Finish()
 
 	// always merge the view, fvm take cares of reverting changes
 	// of failed transaction invocation
 	err = collectionView.MergeView(txView)
 	if err != nil {
-		return fmt.Errorf("merging tx view to collection view failed: %w", err)
+		return nil, fmt.Errorf("merging tx view to collection view failed: %w", err)
 	}
 
 	res.AddEvents(tx.Events)
@@ -332,12 +582,16 @@ func (e *blockComputer) executeTransaction(
 	res.AddTransactionResult(&txResult)
 	res.AddGasUsed(tx.GasUsed)
 
+	if e.metrics != nil {
+		e.metrics.ExecutionTransactionExecuted(time.Since(startedAt), tx.GasUsed, systemScriptHash)
+	}
+
 	e.log.Info().
 		Str("txHash", tx.ID.String()).
 		Str("traceID", traceID).
 		Int64("timeSpentInMS", time.Since(startedAt).Milliseconds()).
 		Msg("transaction executed")
-	return nil
+	return tx, nil
 }
 
 type blockCommitter struct {
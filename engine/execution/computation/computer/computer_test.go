@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime"
@@ -18,6 +19,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/engine/execution"
 	"github.com/onflow/flow-go/engine/execution/computation/committer"
 	"github.com/onflow/flow-go/engine/execution/computation/computer"
 	computermock "github.com/onflow/flow-go/engine/execution/computation/computer/mock"
@@ -53,7 +56,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 			Return(nil, nil, nil).
 			Times(2 + 1) // 2 txs in collection + system chunk
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer)
+		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer, unittest.ProtocolStateAtEpoch(0))
 		require.NoError(t, err)
 
 		// create a block with 1 collection with 2 transactions
@@ -77,7 +80,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 		vm := new(computermock.VirtualMachine)
 		committer := new(computermock.ViewCommitter)
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer)
+		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer, unittest.ProtocolStateAtEpoch(0))
 		require.NoError(t, err)
 
 		// create an empty block
@@ -104,18 +107,44 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 		vm.AssertExpectations(t)
 	})
 
+	t.Run("reports metrics of transactions", func(t *testing.T) {
+		execCtx := fvm.NewContext(zerolog.Nop())
+		collector := &transactionMetrics{NoopCollector: metrics.NewNoopCollector()}
+
+		exe, err := computer.NewBlockComputer(&journalVM{}, execCtx, collector, trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), unittest.ProtocolStateAtEpoch(0))
+		require.NoError(t, err)
+
+		block := generateBlock(2, 2, rag)
+
+		view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+			return nil, nil
+		})
+
+		_, err = exe.ExecuteBlock(context.Background(), block, view, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+
+		// the system transactions are labeled by the hashes of their scripts
+		var expected []flow.Identifier
+		for _, call := range fvm.SystemContractsForChain(execCtx.Chain).Calls(0) {
+			expected = append(expected, flow.HashToID(hash.NewSHA3_256().ComputeHash(call.Script(execCtx.Chain))))
+		}
+		assert.Equal(t, 4, collector.userTransactions)
+		assert.Equal(t, expected, collector.systemScripts)
+	})
+
 	t.Run("multiple collections", func(t *testing.T) {
 		execCtx := fvm.NewContext(zerolog.Nop())
 
 		vm := new(computermock.VirtualMachine)
 		committer := new(computermock.ViewCommitter)
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer)
+		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer, unittest.ProtocolStateAtEpoch(0))
 		require.NoError(t, err)
 
 		collectionCount := 2
 		transactionsPerCollection := 2
 		eventsPerTransaction := 2
+		computationPerTransaction := uint64(3)
 		totalTransactionCount := (collectionCount * transactionsPerCollection) + 1 //+1 for system chunk
 		totalEventCount := eventsPerTransaction * totalTransactionCount
 
@@ -130,6 +159,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 				tx.Err = fvmErrors.NewInvalidAddressErrorf(flow.Address{}, "no payer address provided")
 				// create dummy events
 				tx.Events = generateEvents(eventsPerTransaction, tx.TxIndex)
+				tx.GasUsed = computationPerTransaction
 			}).
 			Return(nil).
 			Times(totalTransactionCount)
@@ -162,6 +192,26 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 			}
 		}
 
+		// events should have been hashed by collection, the system collection being last
+		eventsPerCollection := eventsPerTransaction * transactionsPerCollection
+		require.Len(t, result.EventsHashes, collectionCount+1)
+		for i := 0; i < collectionCount; i++ {
+			events := flow.EventsList(result.Events[i*eventsPerCollection : (i+1)*eventsPerCollection])
+			assert.Equal(t, events.Hash(), result.EventsHashes[i])
+		}
+		systemEvents := flow.EventsList(result.Events[collectionCount*eventsPerCollection:])
+		assert.Equal(t, systemEvents.Hash(), result.EventsHashes[collectionCount])
+
+		// computation used and transactions should have been counted by collection
+		require.Len(t, result.ComputationUsed, collectionCount+1)
+		require.Len(t, result.TransactionCounts, collectionCount+1)
+		for i := 0; i < collectionCount; i++ {
+			assert.Equal(t, uint64(transactionsPerCollection), result.TransactionCounts[i])
+			assert.Equal(t, uint64(transactionsPerCollection)*computationPerTransaction, result.ComputationUsed[i])
+		}
+		assert.Equal(t, uint64(1), result.TransactionCounts[collectionCount])
+		assert.Equal(t, computationPerTransaction, result.ComputationUsed[collectionCount])
+
 		expectedResults := make([]flow.TransactionResult, 0)
 		for _, c := range block.CompleteCollections {
 			for _, t := range c.Transactions {
@@ -238,7 +288,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 
 		vm := fvm.NewVirtualMachine(emittingRuntime)
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter())
+		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), unittest.ProtocolStateAtEpoch(0))
 		require.NoError(t, err)
 
 		//vm.On("Run", mock.Anything, mock.Anything, mock.Anything).
@@ -299,7 +349,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 
 		vm := fvm.NewVirtualMachine(rt)
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter())
+		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), unittest.ProtocolStateAtEpoch(0))
 		require.NoError(t, err)
 
 		const collectionCount = 2
@@ -368,7 +418,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 
 		vm := fvm.NewVirtualMachine(rt)
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter())
+		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), unittest.ProtocolStateAtEpoch(0))
 		require.NoError(t, err)
 
 		block := generateBlock(collectionCount, transactionCount, rag)
@@ -383,6 +433,105 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 	})
 }
 
+// journalVM executes transactions by writing a register and emitting an event, and crashes on the
+// given transaction.
+type journalVM struct {
+	crashOn  flow.Identifier
+	executed int
+}
+
+func (vm *journalVM) Run(_ fvm.Context, proc fvm.Procedure, view state.View, _ *programs.Programs) error {
+	tx := proc.(*fvm.TransactionProcedure)
+	if tx.ID == vm.crashOn {
+		return fmt.Errorf("crashed on transaction %v", tx.ID)
+	}
+	vm.executed++
+	tx.Events = generateEvents(1, tx.TxIndex)
+	tx.GasUsed = uint64(tx.TxIndex)
+	return view.Set(string(tx.ID[:]), "", "", flow.RegisterValue{byte(tx.TxIndex)})
+}
+
+// transactionMetrics records the transactions reported as executed.
+type transactionMetrics struct {
+	*metrics.NoopCollector
+	userTransactions int
+	systemScripts    []flow.Identifier
+}
+
+func (m *transactionMetrics) ExecutionTransactionExecuted(_ time.Duration, _ uint64, systemScriptHash flow.Identifier) {
+	if systemScriptHash == flow.ZeroID {
+		m.userTransactions++
+		return
+	}
+	m.systemScripts = append(m.systemScripts, systemScriptHash)
+}
+
+// memoryJournal is an in-memory execution journal.
+type memoryJournal map[flow.Identifier][]*execution.CollectionCheckpoint
+
+func (j memoryJournal) Checkpoint(blockID flow.Identifier, checkpoint *execution.CollectionCheckpoint) error {
+	j[blockID] = append(j[blockID], checkpoint)
+	return nil
+}
+
+func (j memoryJournal) Checkpoints(blockID flow.Identifier) ([]*execution.CollectionCheckpoint, error) {
+	return j[blockID], nil
+}
+
+func (j memoryJournal) Discard(blockID flow.Identifier) error {
+	delete(j, blockID)
+	return nil
+}
+
+func TestBlockExecutor_ExecutionJournal(t *testing.T) {
+
+	rag := &RandomAddressGenerator{}
+
+	collectionCount := 3
+	transactionCount := 2
+	block := generateBlock(collectionCount, transactionCount, rag)
+
+	execute := func(vm *journalVM, opts ...computer.Option) (*execution.ComputationResult, error) {
+		exe, err := computer.NewBlockComputer(vm, fvm.NewContext(zerolog.Nop()), metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), unittest.ProtocolStateAtEpoch(0), opts...)
+		require.NoError(t, err)
+
+		view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+			return nil, nil
+		})
+		return exe.ExecuteBlock(context.Background(), block, view, programs.NewEmptyPrograms())
+	}
+
+	// the results of executing the block without interruption
+	expected, err := execute(&journalVM{})
+	require.NoError(t, err)
+
+	journal := make(memoryJournal)
+
+	// crash on the first transaction of the last collection
+	crashOn := block.Collections()[collectionCount-1].Transactions[0].ID()
+	_, err = execute(&journalVM{crashOn: crashOn}, computer.WithExecutionJournal(journal))
+	require.Error(t, err)
+	require.Len(t, journal[block.ID()], collectionCount-1)
+
+	// resume the execution of the block after the checkpointed collections
+	vm := &journalVM{}
+	result, err := execute(vm, computer.WithExecutionJournal(journal))
+	require.NoError(t, err)
+	assert.Equal(t, transactionCount+1, vm.executed) // last collection + system chunk
+	assert.Empty(t, journal)
+
+	assert.Equal(t, expected.Events, result.Events)
+	assert.Equal(t, expected.EventsHashes, result.EventsHashes)
+	assert.Equal(t, expected.TransactionResults, result.TransactionResults)
+	assert.Equal(t, expected.ComputationUsed, result.ComputationUsed)
+	assert.Equal(t, expected.TransactionCounts, result.TransactionCounts)
+	require.Len(t, result.StateSnapshots, len(expected.StateSnapshots))
+	for i := range expected.StateSnapshots {
+		assert.Equal(t, expected.StateSnapshots[i].Delta, result.StateSnapshots[i].Delta)
+		assert.Equal(t, expected.StateSnapshots[i].SpockSecret, result.StateSnapshots[i].SpockSecret)
+	}
+}
+
 type testRuntime struct {
 	executeScript      func(runtime.Script, runtime.Context) (cadence.Value, error)
 	executeTransaction func(runtime.Script, runtime.Context) error
@@ -478,7 +627,7 @@ func Test_FreezeAccountChecksAreIncluded(t *testing.T) {
 	err = accounts.Create([]flow.AccountPublicKey{key.PublicKey(1000)}, address)
 	require.NoError(t, err)
 
-	exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter())
+	exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), unittest.ProtocolStateAtEpoch(0))
 	require.NoError(t, err)
 
 	block := generateBlockWithVisitor(1, 1, fag, func(txBody *flow.TransactionBody) {
@@ -518,7 +667,7 @@ func Test_ExecutingSystemCollection(t *testing.T) {
 		Return(nil, nil, nil).
 		Times(1) // only system chunk
 
-	exe, err := computer.NewBlockComputer(vm, execCtx, nil, trace.NewNoopTracer(), zerolog.Nop(), committer)
+	exe, err := computer.NewBlockComputer(vm, execCtx, nil, trace.NewNoopTracer(), zerolog.Nop(), committer, unittest.ProtocolStateAtEpoch(0))
 	require.NoError(t, err)
 
 	// create empty block, it will have system collection attached while executing
@@ -554,7 +703,8 @@ func generateBlockWithVisitor(collectionCount, transactionCount int, addressGene
 
 	block := flow.Block{
 		Header: &flow.Header{
-			View: 42,
+			View:           42,
+			ParentVoterSig: unittest.CombinedSignatureFixture(2),
 		},
 		Payload: &flow.Payload{
 			Guarantees: guarantees,
@@ -19,6 +19,7 @@ import (
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/mempool/entity"
 	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/seed"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
@@ -48,6 +49,7 @@ type Manager struct {
 	vmCtx              fvm.Context
 	blockComputer      computer.BlockComputer
 	programsCache      *ProgramsCache
+	scriptCache        *ScriptCache
 	scriptLogThreshold time.Duration
 }
 
@@ -62,6 +64,9 @@ func New(
 	programsCacheSize uint,
 	committer computer.ViewCommitter,
 	scriptLogThreshold time.Duration,
+	scriptCacheSize uint,
+	scriptCacheTTL time.Duration,
+	opts ...computer.Option,
 ) (*Manager, error) {
 	log := logger.With().Str("engine", "computation").Logger()
 
@@ -72,6 +77,8 @@ func New(
 		tracer,
 		log.With().Str("component", "block_computer").Logger(),
 		committer,
+		protoState,
+		opts...,
 	)
 
 	if err != nil {
@@ -83,6 +90,11 @@ func New(
 		return nil, fmt.Errorf("cannot create programs cache: %w", err)
 	}
 
+	scriptCache, err := NewScriptCache(scriptCacheSize, scriptCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create script cache: %w", err)
+	}
+
 	e := Manager{
 		log:                log,
 		me:                 me,
@@ -91,6 +103,7 @@ func New(
 		vmCtx:              vmCtx,
 		blockComputer:      blockComputer,
 		programsCache:      programsCache,
+		scriptCache:        scriptCache,
 		scriptLogThreshold: scriptLogThreshold,
 	}
 
@@ -106,13 +119,28 @@ func (e *Manager) getChildProgramsOrEmpty(blockID flow.Identifier) *programs.Pro
 }
 
 func (e *Manager) ExecuteScript(code []byte, arguments [][]byte, blockHeader *flow.Header, view state.View) ([]byte, error) {
-	blockCtx := fvm.NewContextFromParent(e.vmCtx, fvm.WithBlockHeader(blockHeader))
+	blockID := blockHeader.ID()
+	cached, ok := e.scriptCache.Get(blockID, code, arguments)
+	if ok {
+		return cached, nil
+	}
+
+	blockOpts := []fvm.Option{fvm.WithBlockHeader(blockHeader)}
+
+	// scripts draw from the random source of their block, unless it can't be derived, like for
+	// the root block, in which case the virtual machine falls back to seeding from the block ID
+	randomSource, err := seed.ExecutionRandomSource(blockHeader)
+	if err == nil {
+		blockOpts = append(blockOpts, fvm.WithBlockRandomSource(randomSource))
+	}
+
+	blockCtx := fvm.NewContextFromParent(e.vmCtx, blockOpts...)
 
 	script := fvm.Script(code).WithArguments(arguments...)
 
-	programs := e.getChildProgramsOrEmpty(blockHeader.ID())
+	programs := e.getChildProgramsOrEmpty(blockID)
 
-	err := func() (err error) {
+	err = func() (err error) {
 
 		start := time.Now()
 
@@ -153,7 +181,7 @@ func (e *Manager) ExecuteScript(code []byte, arguments [][]byte, blockHeader *fl
 	}
 
 	if script.Err != nil {
-		return nil, fmt.Errorf("failed to execute script at block (%s): %s", blockHeader.ID(), script.Err.Error())
+		return nil, fmt.Errorf("failed to execute script at block (%s): %s", blockID, script.Err.Error())
 	}
 
 	encodedValue, err := jsoncdc.Encode(script.Value)
@@ -161,6 +189,8 @@ func (e *Manager) ExecuteScript(code []byte, arguments [][]byte, blockHeader *fl
 		return nil, fmt.Errorf("failed to encode runtime value: %w", err)
 	}
 
+	e.scriptCache.Set(blockID, code, arguments, encodedValue)
+
 	return encodedValue, nil
 }
 
@@ -202,6 +232,9 @@ func (e *Manager) ComputeBlock(
 
 	e.programsCache.Set(block.ID(), toInsert)
 
+	// clients move on to query the newly executed block
+	e.scriptCache.Invalidate()
+
 	e.log.Debug().
 		Hex("block_id", logging.Entity(result.ExecutableBlock.Block)).
 		Msg("computed block result")
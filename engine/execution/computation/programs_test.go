@@ -78,7 +78,8 @@ func TestPrograms_TestContractUpdates(t *testing.T) {
 
 	block := flow.Block{
 		Header: &flow.Header{
-			View: 26,
+			View:           26,
+			ParentVoterSig: unittest.CombinedSignatureFixture(2),
 		},
 		Payload: &flow.Payload{
 			Guarantees: []*flow.CollectionGuarantee{&guarantee},
@@ -99,7 +100,7 @@ func TestPrograms_TestContractUpdates(t *testing.T) {
 	me := new(module.Local)
 	me.On("NodeID").Return(flow.ZeroID)
 
-	blockComputer, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter())
+	blockComputer, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), unittest.ProtocolStateAtEpoch(0))
 	require.NoError(t, err)
 
 	programsCache, err := NewProgramsCache(10)
@@ -166,7 +167,7 @@ func TestPrograms_TestBlockForks(t *testing.T) {
 	me := new(module.Local)
 	me.On("NodeID").Return(flow.ZeroID)
 
-	blockComputer, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter())
+	blockComputer, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), unittest.ProtocolStateAtEpoch(0))
 	require.NoError(t, err)
 
 	programsCache, err := NewProgramsCache(10)
@@ -193,7 +194,8 @@ func TestPrograms_TestBlockForks(t *testing.T) {
 	t.Run("executing block1 (no collection)", func(t *testing.T) {
 		block1 = &flow.Block{
 			Header: &flow.Header{
-				View: 1,
+				View:           1,
+				ParentVoterSig: unittest.CombinedSignatureFixture(2),
 			},
 			Payload: &flow.Payload{
 				Guarantees: []*flow.CollectionGuarantee{},
@@ -346,8 +348,9 @@ func createTestBlockAndRun(t *testing.T, engine *Manager, parentBlock *flow.Bloc
 
 	block := &flow.Block{
 		Header: &flow.Header{
-			ParentID: parentBlock.ID(),
-			View:     parentBlock.Header.Height + 1,
+			ParentID:       parentBlock.ID(),
+			View:           parentBlock.Header.Height + 1,
+			ParentVoterSig: unittest.CombinedSignatureFixture(2),
 		},
 		Payload: &flow.Payload{
 			Guarantees: []*flow.CollectionGuarantee{&guarantee},
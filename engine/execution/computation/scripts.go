@@ -0,0 +1,120 @@
+package computation
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const DefaultScriptCacheSize = 1000
+
+var DefaultScriptCacheTTL = 30 * time.Second
+
+// scriptCacheKey identifies the execution of a script with the given arguments at a block.
+type scriptCacheKey struct {
+	blockID    flow.Identifier
+	scriptHash flow.Identifier
+	argsHash   flow.Identifier
+}
+
+// scriptCacheEntry is the encoded result of a script, along with the time it was cached at.
+type scriptCacheEntry struct {
+	value    []byte
+	cachedAt time.Time
+}
+
+// ScriptCache caches the results of scripts executed at blocks, so that the scripts which are executed
+// repeatedly against the same block, like the read-only scripts of access nodes against the latest sealed
+// block, aren't executed again. The cached results expire after the TTL, and are dropped once a new block
+// is executed, as clients move on to query the new block. A nil ScriptCache caches nothing.
+// It is concurrency safe.
+type ScriptCache struct {
+	cache *lru.Cache // nil if caching is disabled
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewScriptCache creates a cache of up to the given number of script results, which expire after the given
+// TTL. Caching is disabled for size 0.
+func NewScriptCache(size uint, ttl time.Duration) (*ScriptCache, error) {
+	c := &ScriptCache{
+		ttl: ttl,
+		now: time.Now,
+	}
+	if size == 0 {
+		return c, nil
+	}
+
+	cache, err := lru.New(int(size))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create LRU cache: %w", err)
+	}
+	c.cache = cache
+	return c, nil
+}
+
+// Get returns the cached result of the script with the given arguments at the block, if any.
+func (sc *ScriptCache) Get(blockID flow.Identifier, code []byte, arguments [][]byte) ([]byte, bool) {
+	if sc == nil || sc.cache == nil {
+		return nil, false
+	}
+
+	key := newScriptCacheKey(blockID, code, arguments)
+	cached, ok := sc.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry := cached.(scriptCacheEntry)
+	if sc.now().Sub(entry.cachedAt) >= sc.ttl {
+		sc.cache.Remove(key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set caches the result of the script with the given arguments at the block.
+func (sc *ScriptCache) Set(blockID flow.Identifier, code []byte, arguments [][]byte, value []byte) {
+	if sc == nil || sc.cache == nil {
+		return
+	}
+
+	sc.cache.Add(newScriptCacheKey(blockID, code, arguments), scriptCacheEntry{
+		value:    value,
+		cachedAt: sc.now(),
+	})
+}
+
+// Invalidate drops all cached results.
+func (sc *ScriptCache) Invalidate() {
+	if sc == nil || sc.cache == nil {
+		return
+	}
+	sc.cache.Purge()
+}
+
+func newScriptCacheKey(blockID flow.Identifier, code []byte, arguments [][]byte) scriptCacheKey {
+	hasher := hash.NewSHA3_256()
+	scriptHash := flow.HashToID(hasher.ComputeHash(code))
+
+	// hashes the arguments along with their lengths, so that different splits of the same bytes
+	// into arguments don't collide
+	hasher.Reset()
+	for _, argument := range arguments {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(argument)))
+		_, _ = hasher.Write(length[:])
+		_, _ = hasher.Write(argument)
+	}
+	argsHash := flow.HashToID(hasher.SumHash())
+
+	return scriptCacheKey{
+		blockID:    blockID,
+		scriptHash: scriptHash,
+		argsHash:   argsHash,
+	}
+}
@@ -0,0 +1,98 @@
+package computation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestScriptCache(t *testing.T) {
+	now := time.Now()
+	cache, err := NewScriptCache(10, time.Minute)
+	require.NoError(t, err)
+	cache.now = func() time.Time { return now }
+
+	blockID := unittest.IdentifierFixture()
+	code := []byte("pub fun main(a: Int, b: Int): Int { return a + b }")
+	args := [][]byte{[]byte("1"), []byte("23")}
+	cache.Set(blockID, code, args, []byte("24"))
+
+	value, ok := cache.Get(blockID, code, args)
+	require.True(t, ok)
+	assert.Equal(t, []byte("24"), value)
+
+	t.Run("different block, script or arguments", func(t *testing.T) {
+		_, ok := cache.Get(unittest.IdentifierFixture(), code, args)
+		assert.False(t, ok)
+		_, ok = cache.Get(blockID, []byte("pub fun main() {}"), args)
+		assert.False(t, ok)
+		_, ok = cache.Get(blockID, code, [][]byte{[]byte("12"), []byte("3")})
+		assert.False(t, ok)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		_, ok := cache.Get(blockID, code, args)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalidation", func(t *testing.T) {
+		cache.Set(blockID, code, args, []byte("24"))
+		cache.Invalidate()
+		_, ok := cache.Get(blockID, code, args)
+		assert.False(t, ok)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cache, err := NewScriptCache(0, time.Minute)
+		require.NoError(t, err)
+		cache.Set(blockID, code, args, []byte("24"))
+		_, ok := cache.Get(blockID, code, args)
+		assert.False(t, ok)
+	})
+}
+
+// TestExecuteScript_CachedResults verifies that repeated scripts at the same block are served
+// from the cache until a new block is executed.
+func TestExecuteScript_CachedResults(t *testing.T) {
+	vm := &countingVM{}
+	manager, err := New(zerolog.Nop(), nil, nil, nil, nil, vm, fvm.NewContext(zerolog.Nop()), DefaultProgramsCacheSize, committer.NewNoopViewCommitter(), time.Second, DefaultScriptCacheSize, time.Minute)
+	require.NoError(t, err)
+
+	view := delta.NewView(func(_, _, _ string) (flow.RegisterValue, error) {
+		return nil, nil
+	})
+	header := unittest.BlockHeaderFixture()
+
+	for i := 0; i < 3; i++ {
+		_, err = manager.ExecuteScript([]byte("whatever"), nil, &header, view)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, vm.runs)
+
+	manager.scriptCache.Invalidate()
+	_, err = manager.ExecuteScript([]byte("whatever"), nil, &header, view)
+	require.NoError(t, err)
+	assert.Equal(t, 2, vm.runs)
+}
+
+type countingVM struct {
+	LongRunningVM
+	runs int
+}
+
+func (c *countingVM) Run(f fvm.Context, procedure fvm.Procedure, view state.View, p2 *programs.Programs) error {
+	c.runs++
+	return c.LongRunningVM.Run(f, procedure, view, p2)
+}
@@ -45,7 +45,7 @@ func TestExecutionFlow(t *testing.T) {
 	identities := unittest.CompleteIdentitySet(colID, conID, exeID, verID)
 
 	// create execution node
-	exeNode := testutil.ExecutionNode(t, hub, exeID, identities, 21, chainID)
+	exeNode := testutil.ExecutionNode(t, hub, exeID, identities, chainID)
 	exeNode.Ready()
 	defer exeNode.Done()
 
@@ -297,7 +297,7 @@ func TestExecutionStateSyncMultipleExecutionNodes(t *testing.T) {
 	defer collectionNode.Done()
 	consensusNode := testutil.GenericNode(t, hub, conID, identities, chainID)
 	defer consensusNode.Done()
-	exe1Node := testutil.ExecutionNode(t, hub, exe1ID, identities, 27, chainID)
+	exe1Node := testutil.ExecutionNode(t, hub, exe1ID, identities, chainID)
 	exe1Node.Ready()
 	defer exe1Node.Done()
 
@@ -430,7 +430,7 @@ func TestBroadcastToMultipleVerificationNodes(t *testing.T) {
 
 	identities := unittest.CompleteIdentitySet(colID, conID, exeID, ver1ID, ver2ID)
 
-	exeNode := testutil.ExecutionNode(t, hub, exeID, identities, 21, chainID)
+	exeNode := testutil.ExecutionNode(t, hub, exeID, identities, chainID)
 	exeNode.Ready()
 	defer exeNode.Done()
 
@@ -492,7 +492,7 @@ func TestBroadcastToMultipleVerificationNodes(t *testing.T) {
 //
 // 	identities := unittest.CompleteIdentitySet(colID, exeID, ver1ID, ver2ID)
 //
-// 	exeNode := testutil.ExecutionNode(t, hub, exeID, identities, 21, chainID)
+// 	exeNode := testutil.ExecutionNode(t, hub, exeID, identities, chainID)
 // 	defer exeNode.Done()
 //
 // 	genesis, err := exeNode.State.AtHeight(0).Head()
@@ -0,0 +1,58 @@
+package ingestion
+
+import (
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	psEvents "github.com/onflow/flow-go/state/protocol/events"
+)
+
+// BlockRateMeter measures the rate of finalized blocks over a sliding window. As every
+// finalized block is executed, the rate approximates the execution load of the node.
+type BlockRateMeter struct {
+	psEvents.Noop // satisfy protocol events consumer interface
+
+	mu     sync.Mutex
+	window time.Duration
+	times  []time.Time // finalization times within the window, oldest first
+	now    func() time.Time
+}
+
+// NewBlockRateMeter creates a meter for the block rate over the given window.
+func NewBlockRateMeter(window time.Duration) *BlockRateMeter {
+	return &BlockRateMeter{
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// BlockFinalized records the finalization of a block.
+func (m *BlockRateMeter) BlockFinalized(*flow.Header) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.prune(now)
+	m.times = append(m.times, now)
+}
+
+// BlockRate returns the number of blocks finalized per second within the window.
+func (m *BlockRateMeter) BlockRate() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(m.now())
+	return float64(len(m.times)) / m.window.Seconds()
+}
+
+// prune drops the finalization times which are outside of the window.
+// Caller must hold the lock.
+func (m *BlockRateMeter) prune(now time.Time) {
+	cutoff := now.Add(-m.window)
+	i := 0
+	for i < len(m.times) && !m.times[i].After(cutoff) {
+		i++
+	}
+	m.times = m.times[i:]
+}
@@ -0,0 +1,31 @@
+package ingestion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestBlockRateMeter(t *testing.T) {
+
+	now := time.Now()
+	meter := NewBlockRateMeter(10 * time.Second)
+	meter.now = func() time.Time { return now }
+
+	require.Zero(t, meter.BlockRate())
+
+	header := unittest.BlockHeaderFixture()
+	for i := 0; i < 20; i++ {
+		meter.BlockFinalized(&header)
+		now = now.Add(time.Second)
+	}
+
+	// only the blocks of the last 10 seconds are counted
+	require.Equal(t, 0.9, meter.BlockRate())
+
+	now = now.Add(time.Minute)
+	require.Zero(t, meter.BlockRate())
+}
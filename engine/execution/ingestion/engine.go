@@ -20,15 +20,14 @@ import (
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/engine/execution/utils"
+	"github.com/onflow/flow-go/ledger/complete/pruner"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
-	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/mempool/entity"
 	"github.com/onflow/flow-go/module/mempool/queue"
 	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/module/trace"
-	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/state/protocol"
 	psEvents "github.com/onflow/flow-go/state/protocol/events"
 	"github.com/onflow/flow-go/storage"
@@ -54,16 +53,19 @@ type Engine struct {
 	providerEngine     provider.ProviderEngine
 	mempool            *Mempool
 	execState          state.ExecutionState
+	queryState         state.ReadOnlyExecutionState // serves script executions and account queries
 	metrics            module.ExecutionMetrics
 	tracer             module.Tracer
 	extensiveLogging   bool
 	spockHasher        hash.Hasher
-	syncThreshold      int                 // the threshold for how many sealed unexecuted blocks to trigger state syncing.
 	syncFilter         flow.IdentityFilter // specify the filter to sync state from
-	syncConduit        network.Conduit     // sending state syncing requests
-	syncDeltas         mempool.Deltas      // storing the synced state deltas
 	syncFast           bool                // sync fast allows execution node to skip fetching collection during state syncing, and rely on state syncing to catch up
 	checkStakedAtBlock func(blockID flow.Identifier) (bool, error)
+	statePinner        *state.StatePinner         // pins the end states of unsealed blocks, optional
+	ledgerPruner       *pruner.Pruner             // prunes the tries of historical states from the ledger, optional
+	chunkDataPackCache storage.ChunkDataPackCache // cache of the chunk data packs of unsealed blocks, optional
+	executionMetadata  flow.ExecutionMetadata     // configuration of the virtual machine reported in receipts
+	persistSnapshots   bool                       // persists the state interactions of executed blocks, to serve their state deltas
 }
 
 func New(
@@ -84,10 +86,9 @@ func New(
 	tracer module.Tracer,
 	extLog bool,
 	syncFilter flow.IdentityFilter,
-	syncDeltas mempool.Deltas,
-	syncThreshold int,
 	syncFast bool,
 	checkStakedAtBlock func(blockID flow.Identifier) (bool, error),
+	statePinner *state.StatePinner,
 ) (*Engine, error) {
 	log := logger.With().Str("engine", "ingestion").Logger()
 
@@ -110,24 +111,16 @@ func New(
 		providerEngine:     providerEngine,
 		mempool:            mempool,
 		execState:          execState,
+		queryState:         execState,
 		metrics:            metrics,
 		tracer:             tracer,
 		extensiveLogging:   extLog,
 		syncFilter:         syncFilter,
-		syncThreshold:      syncThreshold,
-		syncDeltas:         syncDeltas,
 		syncFast:           syncFast,
 		checkStakedAtBlock: checkStakedAtBlock,
+		statePinner:        statePinner,
 	}
 
-	// move to state syncing engine
-	syncConduit, err := net.Register(engine.SyncExecution, &eng)
-	if err != nil {
-		return nil, fmt.Errorf("could not register execution blockSync engine: %w", err)
-	}
-
-	eng.syncConduit = syncConduit
-
 	return &eng, nil
 }
 
@@ -541,6 +534,9 @@ func (e *Engine) executeBlock(ctx context.Context, executableBlock *entity.Execu
 	e.metrics.FinishBlockReceivedToExecuted(executableBlock.ID())
 	e.metrics.ExecutionGasUsedPerBlock(computationResult.GasUsed)
 	e.metrics.ExecutionStateReadsPerBlock(computationResult.StateReads)
+	for code, count := range computationResult.TransactionErrors {
+		e.metrics.ExecutionTransactionErrorsPerBlock(uint16(code), count)
+	}
 
 	finalState, receipt, err := e.handleComputationResult(ctx, computationResult, *executableBlock.StartState)
 	if errors.Is(err, storage.ErrDataMismatch) {
@@ -560,6 +556,33 @@ func (e *Engine) executeBlock(ctx context.Context, executableBlock *entity.Execu
 		e.log.Fatal().Err(err).Msg("could not get sealed block before broadcasting")
 	}
 
+	if e.statePinner != nil {
+		err = e.statePinner.BlockExecuted(executableBlock.Block.Header, finalState)
+		if err != nil {
+			e.log.Fatal().Err(err).Msg("could not pin end state of executed block")
+		}
+		err = e.statePinner.BlockSealed(lastSealed)
+		if err != nil {
+			e.log.Err(err).Msg("could not unpin end states of sealed blocks")
+		}
+	}
+
+	if e.ledgerPruner != nil {
+		e.ledgerPruner.BlockExecuted(executableBlock.Block.Header, finalState)
+		err = e.ledgerPruner.BlockSealed(lastSealed)
+		if err != nil {
+			e.log.Err(err).Msg("could not prune historical states")
+		}
+	}
+
+	// the chunk data packs of sealed blocks are no longer requested by verification nodes
+	if e.chunkDataPackCache != nil {
+		err = e.chunkDataPackCache.PruneUpToHeight(lastSealed.Height)
+		if err != nil {
+			e.log.Err(err).Msg("could not prune chunk data packs of sealed blocks from cache")
+		}
+	}
+
 	isExecutedBlockSealed := executableBlock.Block.Header.Height <= lastSealed.Height
 	broadcasted := false
 
@@ -697,30 +720,7 @@ func (e *Engine) executeBlockIfComplete(eb *entity.ExecutableBlock) bool {
 		return false
 	}
 
-	// if the eb has parent statecommitment, and we have the delta for this block
-	// then apply the delta
-	// note the block ID is the delta's ID
-	// delta, found := e.syncDeltas.ByBlockID(eb.Block.ID())
-	// if found {
-	// 	// double check before applying the state delta
-	// 	if bytes.Equal(eb.StartState, delta.ExecutableBlock.StartState) {
-	// 		e.unit.Launch(func() {
-	// 			e.applyStateDelta(delta)
-	// 		})
-	// 		return true
-	// 	}
-	//
-	// 	// if state delta is invalid, remove the delta and log error
-	// 	e.log.Error().
-	// 		Hex("block_start_state", eb.StartState).
-	// 		Hex("delta_start_state", delta.ExecutableBlock.StartState).
-	// 		Msg("can not apply the state delta, the start state does not match")
-	//
-	// 	e.syncDeltas.Rem(eb.Block.ID())
-	// }
-
-	// if don't have the delta, then check if everything is ready for executing
-	// the block
+	// check if everything is ready for executing the block
 	if eb.IsComplete() {
 
 		if e.extensiveLogging {
@@ -789,6 +789,7 @@ func (e *Engine) handleCollection(originID flow.Identifier, collection *flow.Col
 				return nil
 			}
 
+			var mismatched []flow.Identifier
 			for _, executableBlock := range blockByCollectionID.ExecutableBlocks {
 				blockID := executableBlock.ID()
 
@@ -803,6 +804,23 @@ func (e *Engine) handleCollection(originID flow.Identifier, collection *flow.Col
 					continue
 				}
 
+				// the collection ID matches the guarantee, so a mismatch with the declared
+				// metadata, which is covered by the ID of the block, means that the guarantors
+				// declared wrong metadata, and that the block is invalid
+				// TODO: slash the guarantors once slashing is available
+				guarantee := completeCollection.Guarantee
+				if !guarantee.IsLegacy() && (guarantee.TransactionCount != uint(collection.Len()) || guarantee.ByteSize != collection.ByteSize()) {
+					lg.Error().
+						Hex("block_id", blockID[:]).
+						Uint("declared_transactions", guarantee.TransactionCount).
+						Int("transactions", collection.Len()).
+						Uint64("declared_byte_size", guarantee.ByteSize).
+						Uint64("byte_size", collection.ByteSize()).
+						Msg("collection does not match metadata of its guarantee, not executing block")
+					mismatched = append(mismatched, blockID)
+					continue
+				}
+
 				// update the transactions of the collection
 				// Note: it's guaranteed the transactions are for this collection, because
 				// the collection id matches with the CollectionID from the collection guarantee
@@ -820,6 +838,10 @@ func (e *Engine) handleCollection(originID flow.Identifier, collection *flow.Col
 			// index.
 			backdata.Rem(collID)
 
+			if len(mismatched) > 0 {
+				return fmt.Errorf("collection does not match metadata of its guarantee in blocks %v", mismatched)
+			}
+
 			return nil
 		},
 	)
@@ -964,6 +986,78 @@ func (e *Engine) matchOrRequestCollections(
 	return nil
 }
 
+// WithQueryState serves script executions and account queries from the given state, instead
+// of the execution state used to execute blocks.
+func (e *Engine) WithQueryState(queryState state.ReadOnlyExecutionState) *Engine {
+	e.queryState = queryState
+	return e
+}
+
+// WithLedgerPruner prunes the states of executed blocks from the ledger, once they are sealed
+// long enough.
+func (e *Engine) WithLedgerPruner(ledgerPruner *pruner.Pruner) *Engine {
+	e.ledgerPruner = ledgerPruner
+	return e
+}
+
+// WithChunkDataPackCache prunes the chunk data packs of sealed blocks from the given cache, which
+// holds the chunk data packs of executed blocks stored by the execution state.
+func (e *Engine) WithChunkDataPackCache(cache storage.ChunkDataPackCache) *Engine {
+	e.chunkDataPackCache = cache
+	return e
+}
+
+// WithExecutionMetadata reports the given configuration of the virtual machine in the execution
+// receipts, so that verification nodes verify the chunks with the same configuration.
+func (e *Engine) WithExecutionMetadata(metadata flow.ExecutionMetadata) *Engine {
+	e.executionMetadata = metadata
+	return e
+}
+
+// WithPersistedStateInteractions persists the state interactions of the executed blocks, so that
+// the state sync engine serves the state deltas of the blocks to lagging execution nodes.
+func (e *Engine) WithPersistedStateInteractions() *Engine {
+	e.persistSnapshots = true
+	return e
+}
+
+// OnStateDeltaApplied is called by the state sync engine once the state delta of a block was
+// applied, instead of executing the block. The block is then handled as an executed block, unless
+// its execution has already started.
+func (e *Engine) OnStateDeltaApplied(blockID flow.Identifier, endState flow.StateCommitment) {
+	var applied *entity.ExecutableBlock
+	err := e.mempool.Run(
+		func(
+			blockByCollection *stdmap.BlockByCollectionBackdata,
+			executionQueues *stdmap.QueuesBackdata,
+		) error {
+			// only the heads of the queues have executed parents
+			executionQueue, exists := executionQueues.ByID(blockID)
+			if !exists {
+				return nil
+			}
+			executableBlock := executionQueue.Head.Item.(*entity.ExecutableBlock)
+			if executableBlock.Executing {
+				return nil
+			}
+			executableBlock.Executing = true
+			applied = executableBlock
+			return nil
+		})
+	if err != nil {
+		e.log.Err(err).Hex("block_id", blockID[:]).Msg("could not find block of applied state delta")
+		return
+	}
+	if applied == nil {
+		return
+	}
+
+	err = e.onBlockExecuted(applied, endState)
+	if err != nil {
+		e.log.Err(err).Msg("failed in process block's children")
+	}
+}
+
 func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, error) {
 
 	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
@@ -976,7 +1070,7 @@ func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, argu
 		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
-	blockView := e.execState.NewView(stateCommit)
+	blockView := e.queryState.NewView(stateCommit)
 
 	if e.extensiveLogging {
 		args := make([]string, 0)
@@ -1005,7 +1099,7 @@ func (e *Engine) GetAccount(ctx context.Context, addr flow.Address, blockID flow
 		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
-	blockView := e.execState.NewView(stateCommit)
+	blockView := e.queryState.NewView(stateCommit)
 
 	return e.computationManager.GetAccount(addr, block, blockView)
 }
@@ -1054,8 +1148,16 @@ func (e *Engine) saveExecutionResults(
 	originalState := startState
 	blockID := result.ExecutableBlock.ID()
 
-	// no need to persist the state interactions, since they are used only by state
-	// syncing, which is currently disabled
+	if e.persistSnapshots {
+		interactions := make([]*delta.Snapshot, 0, len(result.StateSnapshots))
+		for _, snapshot := range result.StateSnapshots {
+			interactions = append(interactions, &snapshot.Snapshot)
+		}
+		err := e.execState.PersistStateInteractions(childCtx, blockID, interactions)
+		if err != nil {
+			return nil, fmt.Errorf("cannot persist state interactions: %w", err)
+		}
+	}
 
 	chunks := make([]*flow.Chunk, len(result.StateCommitments))
 	chdps := make([]*flow.ChunkDataPack, len(result.StateCommitments))
@@ -1078,7 +1180,8 @@ func (e *Engine) saveExecutionResults(
 			collectionID = flow.ZeroID
 		}
 
-		chunk := generateChunk(i, startState, endState, collectionID, blockID)
+		chunk := generateChunk(i, startState, endState, result.EventsHashes[i], blockID,
+			result.ComputationUsed[i], result.TransactionCounts[i])
 
 		// chunkDataPack
 		chdps[i] = generateChunkDataPack(chunk, collectionID, result.Proofs[i])
@@ -1144,20 +1247,16 @@ func (e *Engine) logExecutableBlock(eb *entity.ExecutableBlock) {
 // generateChunk creates a chunk from the provided computation data.
 func generateChunk(colIndex int,
 	startState, endState flow.StateCommitment,
-	colID, blockID flow.Identifier) *flow.Chunk {
+	eventsHash, blockID flow.Identifier,
+	computationUsed, numberOfTransactions uint64) *flow.Chunk {
 	return &flow.Chunk{
 		ChunkBody: flow.ChunkBody{
-			CollectionIndex: uint(colIndex),
-			StartState:      startState,
-			// TODO: include real, event collection hash, currently using the collection ID to generate a different Chunk ID
-			// Otherwise, the chances of there being chunks with the same ID before all these TODOs are done is large, since
-			// startState stays the same if blocks are empty
-			EventCollection: colID,
-			BlockID:         blockID,
-			// TODO: record gas used
-			TotalComputationUsed: 0,
-			// TODO: record number of txs
-			NumberOfTransactions: 0,
+			CollectionIndex:      uint(colIndex),
+			StartState:           startState,
+			EventCollection:      eventsHash,
+			BlockID:              blockID,
+			TotalComputationUsed: computationUsed,
+			NumberOfTransactions: numberOfTransactions,
 		},
 		Index:    uint64(colIndex),
 		EndState: endState,
@@ -1220,6 +1319,7 @@ func (e *Engine) generateExecutionReceipt(
 	receipt := &flow.ExecutionReceipt{
 		ExecutionResult:   *result,
 		Spocks:            spocks,
+		ExecutionMetadata: e.executionMetadata,
 		ExecutorSignature: crypto.Signature{},
 		ExecutorID:        e.me.NodeID(),
 	}
@@ -14,7 +14,6 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/crypto"
-	engineCommon "github.com/onflow/flow-go/engine"
 	computation "github.com/onflow/flow-go/engine/execution/computation/mock"
 	provider "github.com/onflow/flow-go/engine/execution/provider/mock"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
@@ -77,7 +76,6 @@ func runWithEngine(t *testing.T, f func(testingContext)) {
 	// initialize the mocks and engine
 	conduit := &mocknetwork.Conduit{}
 	collectionConduit := &mocknetwork.Conduit{}
-	syncConduit := &mocknetwork.Conduit{}
 
 	// generates signing identity including staking key for signing
 	seed := make([]byte, crypto.KeyGenSeedMinLenBLSBLS12381)
@@ -138,11 +136,6 @@ func runWithEngine(t *testing.T, f func(testingContext)) {
 
 	request.EXPECT().Force().Return().AnyTimes()
 
-	net.EXPECT().Register(gomock.Eq(engineCommon.SyncExecution), gomock.AssignableToTypeOf(engine)).Return(syncConduit, nil)
-
-	deltas, err := NewDeltas(1000)
-	require.NoError(t, err)
-
 	checkStakedAtBlock := func(blockID flow.Identifier) (bool, error) {
 		return stateProtocol.IsNodeStakedAt(protocolState.AtBlockID(blockID), myIdentity.NodeID)
 	}
@@ -165,10 +158,9 @@ func runWithEngine(t *testing.T, f func(testingContext)) {
 		tracer,
 		false,
 		filter.Any,
-		deltas,
-		10,
 		false,
 		checkStakedAtBlock,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -351,10 +343,12 @@ func (ctx *testingContext) mockStateCommitsWithMap(commits map[flow.Identifier]f
 func TestChunkIndexIsSet(t *testing.T) {
 
 	i := mathRand.Int()
-	chunk := generateChunk(i, unittest.StateCommitmentFixture(), unittest.StateCommitmentFixture(), unittest.IdentifierFixture(), unittest.IdentifierFixture())
+	chunk := generateChunk(i, unittest.StateCommitmentFixture(), unittest.StateCommitmentFixture(), unittest.IdentifierFixture(), unittest.IdentifierFixture(), 21, 42)
 
 	assert.Equal(t, i, int(chunk.Index))
 	assert.Equal(t, i, int(chunk.CollectionIndex))
+	assert.Equal(t, uint64(21), chunk.TotalComputationUsed)
+	assert.Equal(t, uint64(42), chunk.NumberOfTransactions)
 }
 
 func TestExecuteOneBlock(t *testing.T) {
@@ -497,6 +491,31 @@ func TestBlocksArentExecutedMultipleTimes_multipleBlockEnqueue(t *testing.T) {
 	})
 }
 
+func TestCollectionMismatchingGuaranteeMetadata(t *testing.T) {
+	runWithEngine(t, func(ctx testingContext) {
+
+		executableBlock := unittest.ExecutableBlockFixture([][]flow.Identifier{{unittest.IdentifierFixture()}})
+		completeCollection := executableBlock.Collections()[0]
+		collection := completeCollection.Collection()
+
+		// the guarantee declares more transactions than the collection has
+		completeCollection.Transactions = nil
+		completeCollection.Guarantee.TransactionCount++
+
+		ctx.engine.mempool.BlockByCollection.Add(&entity.BlocksByCollection{
+			CollectionID:     collection.ID(),
+			ExecutableBlocks: map[flow.Identifier]*entity.ExecutableBlock{executableBlock.ID(): executableBlock},
+		})
+		ctx.collections.EXPECT().Store(&collection)
+
+		err := ctx.engine.handleCollection(unittest.IdentifierFixture(), &collection)
+		require.Error(t, err)
+
+		// the collection is not accepted for the block, so the block is not executed
+		require.False(t, completeCollection.IsCompleted())
+	})
+}
+
 func TestBlocksArentExecutedMultipleTimes_collectionArrival(t *testing.T) {
 	runWithEngine(t, func(ctx testingContext) {
 
@@ -931,9 +950,6 @@ func newIngestionEngine(t *testing.T, ps *mocks.ProtocolState, es *mocks.Executi
 	ctrl := gomock.NewController(t)
 	net := module.NewMockNetwork(ctrl)
 	request := module.NewMockRequester(ctrl)
-	syncConduit := &mocknetwork.Conduit{}
-	var engine *Engine
-	net.EXPECT().Register(gomock.Eq(engineCommon.SyncExecution), gomock.AssignableToTypeOf(engine)).Return(syncConduit, nil)
 
 	// generates signing identity including staking key for signing
 	seed := make([]byte, crypto.KeyGenSeedMinLenBLSBLS12381)
@@ -953,14 +969,11 @@ func newIngestionEngine(t *testing.T, ps *mocks.ProtocolState, es *mocks.Executi
 	computationManager := new(computation.ComputationManager)
 	providerEngine := new(provider.ProviderEngine)
 
-	deltas, err := NewDeltas(10)
-	require.NoError(t, err)
-
 	checkStakedAtBlock := func(blockID flow.Identifier) (bool, error) {
 		return stateProtocol.IsNodeStakedAt(ps.AtBlockID(blockID), myIdentity.NodeID)
 	}
 
-	engine, err = New(
+	engine, err := New(
 		log,
 		net,
 		me,
@@ -978,10 +991,9 @@ func newIngestionEngine(t *testing.T, ps *mocks.ProtocolState, es *mocks.Executi
 		tracer,
 		false,
 		filter.Any,
-		deltas,
-		10,
 		false,
 		checkStakedAtBlock,
+		nil,
 	)
 
 	require.NoError(t, err)
@@ -2,6 +2,7 @@ package execution
 
 import (
 	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/mempool/entity"
 )
@@ -21,16 +22,62 @@ type ComputationResult struct {
 	StateCommitments   []flow.StateCommitment
 	Proofs             [][]byte
 	Events             []flow.Event
+	EventsHashes       []flow.Identifier // hash of the events of each collection, including the system collection
+	ComputationUsed    []uint64          // computation used by each collection, including the system collection
+	TransactionCounts  []uint64          // number of transactions of each collection, including the system collection
 	ServiceEvents      []flow.Event
 	TransactionResults []flow.TransactionResult
 	GasUsed            uint64
 	StateReads         uint64
+	TransactionErrors  map[errors.ErrorCode]int // number of failed transactions by error code
+}
+
+// CollectionCheckpoint is the part of the computation result of a block contributed by one of its
+// collections, which is persisted once the collection is executed, so that the execution of the block
+// can be resumed from its last executed collection after a restart.
+type CollectionCheckpoint struct {
+	CollectionIndex      int                  // index of the collection in the block
+	NextTransactionIndex uint32               // index of the first transaction of the next collection
+	StateSnapshot        *delta.SpockSnapshot // interactions of the collection with the state
+	Events               []flow.Event
+	EventsHash           flow.Identifier
+	ServiceEvents        []flow.Event
+	TransactionResults   []flow.TransactionResult
+	ComputationUsed      uint64
+	TransactionCount     uint64
+	TransactionErrors    map[errors.ErrorCode]int // number of failed transactions of the collection by error code
+}
+
+// AddCheckpoint adds the results of an executed collection restored from its checkpoint.
+func (cr *ComputationResult) AddCheckpoint(checkpoint *CollectionCheckpoint) {
+	cr.AddEvents(checkpoint.Events)
+	cr.AddEventsHash(checkpoint.EventsHash)
+	cr.AddServiceEvents(checkpoint.ServiceEvents)
+	cr.TransactionResults = append(cr.TransactionResults, checkpoint.TransactionResults...)
+	cr.AddGasUsed(checkpoint.ComputationUsed)
+	cr.AddCollectionStats(checkpoint.ComputationUsed, checkpoint.TransactionCount)
+	cr.AddStateSnapshot(checkpoint.StateSnapshot)
+	for code, count := range checkpoint.TransactionErrors {
+		if cr.TransactionErrors == nil {
+			cr.TransactionErrors = make(map[errors.ErrorCode]int)
+		}
+		cr.TransactionErrors[code] += count
+	}
 }
 
 func (cr *ComputationResult) AddEvents(inp []flow.Event) {
 	cr.Events = append(cr.Events, inp...)
 }
 
+func (cr *ComputationResult) AddEventsHash(inp flow.Identifier) {
+	cr.EventsHashes = append(cr.EventsHashes, inp)
+}
+
+func (cr *ComputationResult) AddCollectionStats(computationUsed uint64, transactionCount uint64) {
+	cr.ComputationUsed = append(cr.ComputationUsed, computationUsed)
+	cr.TransactionCounts = append(cr.TransactionCounts, transactionCount)
+}
+
 func (cr *ComputationResult) AddServiceEvents(inp []flow.Event) {
 	cr.ServiceEvents = append(cr.ServiceEvents, inp...)
 }
@@ -39,6 +86,13 @@ func (cr *ComputationResult) AddTransactionResult(inp *flow.TransactionResult) {
 	cr.TransactionResults = append(cr.TransactionResults, *inp)
 }
 
+func (cr *ComputationResult) AddTransactionError(code errors.ErrorCode) {
+	if cr.TransactionErrors == nil {
+		cr.TransactionErrors = make(map[errors.ErrorCode]int)
+	}
+	cr.TransactionErrors[code]++
+}
+
 func (cr *ComputationResult) AddGasUsed(inp uint64) {
 	cr.GasUsed += inp
 }
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/engine"
@@ -39,6 +41,7 @@ type Engine struct {
 	chunksConduit      network.Conduit
 	metrics            module.ExecutionMetrics
 	checkStakedAtBlock func(blockID flow.Identifier) (bool, error)
+	chunkDataPackCache storage.ChunkDataPackCache // cache of the chunk data packs of recent blocks, optional
 }
 
 func New(
@@ -81,6 +84,13 @@ func New(
 	return &eng, nil
 }
 
+// WithChunkDataPackCache serves the chunk data pack requests for recent blocks from the given cache,
+// falling back to the execution state for the chunk data packs missing from the cache.
+func (e *Engine) WithChunkDataPackCache(cache storage.ChunkDataPackCache) *Engine {
+	e.chunkDataPackCache = cache
+	return e
+}
+
 func (e *Engine) SubmitLocal(event interface{}) {
 	e.Submit(e.me.NodeID(), event)
 }
@@ -125,6 +135,12 @@ func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 			return fmt.Errorf("could not answer chunk data request: %w", err)
 		}
 		return err
+	case *messages.ChunkDataBatchRequest:
+		err := e.onChunkDataBatchRequest(ctx, originID, v)
+		if err != nil {
+			return fmt.Errorf("could not answer chunk data batch request: %w", err)
+		}
+		return nil
 	default:
 		return fmt.Errorf("invalid event type (%T)", event)
 	}
@@ -152,7 +168,7 @@ func (e *Engine) onChunkDataRequest(
 	// increases collector metric
 	e.metrics.ChunkDataPackRequested()
 
-	cdp, err := e.execState.ChunkDataPackByChunkID(ctx, chunkID)
+	cdp, err := e.chunkDataPack(ctx, chunkID)
 	// we might be behind when we don't have the requested chunk.
 	// if this happen, log it and return nil
 	if errors.Is(err, storage.ErrNotFound) {
@@ -198,6 +214,56 @@ func (e *Engine) onChunkDataRequest(
 	return nil
 }
 
+// onChunkDataBatchRequest receives a request for the chunk data packs of several chunks from the
+// requester `originID`, and answers each of them as a separate chunk data pack request. Failing to
+// answer one chunk doesn't prevent answering the others.
+func (e *Engine) onChunkDataBatchRequest(
+	ctx context.Context,
+	originID flow.Identifier,
+	req *messages.ChunkDataBatchRequest,
+) error {
+
+	if len(req.ChunkIDs) > messages.MaxChunkDataBatchSize {
+		return engine.NewInvalidInputErrorf("too many chunks in batch request (%d > %d)", len(req.ChunkIDs), messages.MaxChunkDataBatchSize)
+	}
+
+	e.log.Debug().
+		Hex("origin_id", logging.ID(originID)).
+		Int("chunks", len(req.ChunkIDs)).
+		Msg("received chunk data pack batch request")
+
+	var result *multierror.Error
+	for _, chunkID := range req.ChunkIDs {
+		err := e.onChunkDataRequest(ctx, originID, &messages.ChunkDataRequest{
+			ChunkID: chunkID,
+			Nonce:   req.Nonce,
+		})
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// chunkDataPack retrieves the chunk data pack from the cache of recent chunk data packs, if any, or
+// from the execution state.
+func (e *Engine) chunkDataPack(ctx context.Context, chunkID flow.Identifier) (*flow.ChunkDataPack, error) {
+	if e.chunkDataPackCache != nil {
+		start := time.Now()
+		cdp, err := e.chunkDataPackCache.ByChunkID(chunkID)
+		e.metrics.ChunkDataPackCacheLookup(err == nil, time.Since(start))
+		if err == nil {
+			return cdp, nil
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			e.log.Warn().Err(err).Hex("chunk_id", logging.ID(chunkID)).Msg("could not retrieve cached chunk data pack")
+		}
+	}
+
+	return e.execState.ChunkDataPackByChunkID(ctx, chunkID)
+}
+
 func (e *Engine) ensureStaked(chunkID flow.Identifier, originID flow.Identifier) (*flow.Identity, error) {
 
 	blockID, err := e.execState.GetBlockIDByChunkID(chunkID)
@@ -10,13 +10,17 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/engine"
 	state "github.com/onflow/flow-go/engine/execution/state/mock"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module/metrics"
+	mockmodule "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	"github.com/onflow/flow-go/state/protocol"
 	mockprotocol "github.com/onflow/flow-go/state/protocol/mock"
+	storageerr "github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -209,6 +213,51 @@ func TestProviderEngine_onChunkDataRequest(t *testing.T) {
 		execState.AssertExpectations(t)
 	})
 
+	t.Run("served from chunk data pack cache", func(t *testing.T) {
+		ps := new(mockprotocol.State)
+		ss := new(mockprotocol.Snapshot)
+		con := new(mocknetwork.Conduit)
+		execState := new(state.ExecutionState)
+		cache := new(storagemock.ChunkDataPackCache)
+		collector := new(mockmodule.ExecutionMetrics)
+
+		e := Engine{state: ps, chunksConduit: con, execState: execState, metrics: collector, checkStakedAtBlock: func(_ flow.Identifier) (bool, error) { return true, nil }}
+		e.WithChunkDataPackCache(cache)
+
+		originIdentity := unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification))
+		blockID := unittest.IdentifierFixture()
+		ps.On("AtBlockID", blockID).Return(ss)
+		ss.On("Identity", originIdentity.NodeID).Return(originIdentity, nil)
+		con.On("Unicast", mock.Anything, originIdentity.NodeID).Return(nil)
+		collector.On("ChunkDataPackRequested")
+
+		// a recent chunk data pack is served from the cache
+		cached := unittest.ChunkDataPackFixture(unittest.IdentifierFixture())
+		cached.CollectionID = flow.ZeroID
+		cache.On("ByChunkID", cached.ChunkID).Return(cached, nil).Once()
+		collector.On("ChunkDataPackCacheLookup", true, mock.Anything).Once()
+		execState.On("GetBlockIDByChunkID", cached.ChunkID).Return(blockID, nil)
+
+		err := e.onChunkDataRequest(context.Background(), originIdentity.NodeID, &messages.ChunkDataRequest{ChunkID: cached.ChunkID})
+		require.NoError(t, err)
+
+		// other chunk data packs are retrieved from the execution state
+		stored := unittest.ChunkDataPackFixture(unittest.IdentifierFixture())
+		stored.CollectionID = flow.ZeroID
+		cache.On("ByChunkID", stored.ChunkID).Return(nil, storageerr.ErrNotFound).Once()
+		collector.On("ChunkDataPackCacheLookup", false, mock.Anything).Once()
+		execState.On("ChunkDataPackByChunkID", mock.Anything, stored.ChunkID).Return(stored, nil).Once()
+		execState.On("GetBlockIDByChunkID", stored.ChunkID).Return(blockID, nil)
+
+		err = e.onChunkDataRequest(context.Background(), originIdentity.NodeID, &messages.ChunkDataRequest{ChunkID: stored.ChunkID})
+		require.NoError(t, err)
+
+		cache.AssertExpectations(t)
+		collector.AssertExpectations(t)
+		execState.AssertExpectations(t)
+		con.AssertNumberOfCalls(t, "Unicast", 2)
+	})
+
 	t.Run("reply to chunk data pack request only when staked", func(t *testing.T) {
 
 		ps := new(mockprotocol.State)
@@ -276,4 +325,63 @@ func TestProviderEngine_onChunkDataRequest(t *testing.T) {
 		con.AssertExpectations(t)
 		execState.AssertExpectations(t)
 	})
+
+	t.Run("batch request", func(t *testing.T) {
+		ps := new(mockprotocol.State)
+		ss := new(mockprotocol.Snapshot)
+		con := new(mocknetwork.Conduit)
+
+		execState := new(state.ExecutionState)
+
+		e := Engine{state: ps, chunksConduit: con, execState: execState, metrics: metrics.NewNoopCollector(), checkStakedAtBlock: func(_ flow.Identifier) (bool, error) { return true, nil }}
+
+		originIdentity := unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification))
+		blockID := unittest.IdentifierFixture()
+		collection := unittest.CollectionFixture(1)
+
+		ps.On("AtBlockID", blockID).Return(ss)
+		ss.On("Identity", originIdentity.NodeID).Return(originIdentity, nil)
+
+		// the first chunk is unknown, the others are answered
+		chunkIDs := unittest.IdentifierListFixture(3)
+		execState.
+			On("ChunkDataPackByChunkID", mock.Anything, chunkIDs[0]).
+			Return(nil, errors.New("not found!"))
+		for _, chunkID := range chunkIDs[1:] {
+			chunkDataPack := unittest.ChunkDataPackFixture(chunkID)
+			execState.On("GetBlockIDByChunkID", chunkID).Return(blockID, nil)
+			execState.On("ChunkDataPackByChunkID", mock.Anything, chunkID).Return(chunkDataPack, nil)
+			execState.On("GetCollection", chunkDataPack.CollectionID).Return(&collection, nil)
+		}
+
+		answered := flow.IdentifierList{}
+		con.On("Unicast", mock.Anything, originIdentity.NodeID).
+			Run(func(args mock.Arguments) {
+				res, ok := args[0].(*messages.ChunkDataResponse)
+				require.True(t, ok)
+				answered = append(answered, res.ChunkDataPack.ChunkID)
+			}).
+			Return(nil).Times(len(chunkIDs) - 1)
+
+		req := &messages.ChunkDataBatchRequest{
+			ChunkIDs: chunkIDs,
+			Nonce:    rand.Uint64(),
+		}
+		err := e.onChunkDataBatchRequest(context.Background(), originIdentity.NodeID, req)
+		assert.Error(t, err)
+		assert.ElementsMatch(t, chunkIDs[1:], answered)
+
+		// oversized batches are rejected
+		req = &messages.ChunkDataBatchRequest{
+			ChunkIDs: unittest.IdentifierListFixture(messages.MaxChunkDataBatchSize + 1),
+			Nonce:    rand.Uint64(),
+		}
+		err = e.onChunkDataBatchRequest(context.Background(), originIdentity.NodeID, req)
+		assert.True(t, engine.IsInvalidInputError(err))
+
+		ps.AssertExpectations(t)
+		ss.AssertExpectations(t)
+		con.AssertExpectations(t)
+		execState.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,172 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Divergence is the first difference found between the results of a replayed block and its stored
+// execution result.
+type Divergence struct {
+	BlockID    flow.Identifier
+	Height     uint64
+	ChunkIndex int    // index of the diverging chunk
+	Reason     string // what diverges, e.g. the end state or the events of the chunk
+	Stored     string // the stored value
+	Replayed   string // the value produced by the replay
+}
+
+func (d *Divergence) String() string {
+	return fmt.Sprintf("block %v at height %d diverges at chunk %d: %s is %s, stored %s",
+		d.BlockID, d.Height, d.ChunkIndex, d.Reason, d.Replayed, d.Stored)
+}
+
+// Replayer re-executes blocks from storage to check that their execution is deterministic. Each block
+// is executed from the stored end state of its parent, which must be held by the ledger, with the
+// collections stored for the block, and its results are compared chunk by chunk to the stored
+// execution result of the block.
+type Replayer struct {
+	log           zerolog.Logger
+	blockComputer computer.BlockComputer
+	ldg           ledger.Ledger
+	blocks        storage.Blocks
+	collections   storage.Collections
+	commits       storage.Commits
+	results       storage.ExecutionResults
+}
+
+// NewReplayer creates a new replayer. The block computer must commit the views of the executed
+// collections to the given ledger, so that the state commitments of the chunks are produced.
+func NewReplayer(
+	log zerolog.Logger,
+	blockComputer computer.BlockComputer,
+	ldg ledger.Ledger,
+	blocks storage.Blocks,
+	collections storage.Collections,
+	commits storage.Commits,
+	results storage.ExecutionResults,
+) *Replayer {
+	return &Replayer{
+		log:           log.With().Str("component", "replayer").Logger(),
+		blockComputer: blockComputer,
+		ldg:           ldg,
+		blocks:        blocks,
+		collections:   collections,
+		commits:       commits,
+		results:       results,
+	}
+}
+
+// ReplayBlocks re-executes the finalized blocks from the start height to the end height, both
+// included, and returns the first divergence from their stored results, or nil if all replayed blocks
+// match their stored results.
+func (r *Replayer) ReplayBlocks(ctx context.Context, startHeight uint64, endHeight uint64) (*Divergence, error) {
+	if startHeight > endHeight {
+		return nil, fmt.Errorf("start height %d is above end height %d", startHeight, endHeight)
+	}
+
+	for height := startHeight; height <= endHeight; height++ {
+		divergence, err := r.replayBlock(ctx, height)
+		if err != nil {
+			return nil, fmt.Errorf("could not replay block at height %d: %w", height, err)
+		}
+		if divergence != nil {
+			return divergence, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *Replayer) replayBlock(ctx context.Context, height uint64) (*Divergence, error) {
+	block, err := r.blocks.ByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve block: %w", err)
+	}
+	blockID := block.ID()
+
+	executableBlock, err := r.executableBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := r.results.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve stored execution result: %w", err)
+	}
+
+	view := delta.NewView(state.LedgerGetRegister(r.ldg, *executableBlock.StartState))
+	replayed, err := r.blockComputer.ExecuteBlock(ctx, executableBlock, view, programs.NewEmptyPrograms())
+	if err != nil {
+		return nil, fmt.Errorf("could not execute block: %w", err)
+	}
+
+	divergence := func(chunkIndex int, reason string, stored, replayed string) *Divergence {
+		return &Divergence{
+			BlockID:    blockID,
+			Height:     height,
+			ChunkIndex: chunkIndex,
+			Reason:     reason,
+			Stored:     stored,
+			Replayed:   replayed,
+		}
+	}
+
+	if len(replayed.StateCommitments) != len(stored.Chunks) {
+		return divergence(len(stored.Chunks), "number of chunks", fmt.Sprint(len(stored.Chunks)), fmt.Sprint(len(replayed.StateCommitments))), nil
+	}
+	for i, chunk := range stored.Chunks {
+		if replayed.EventsHashes[i] != chunk.EventCollection {
+			return divergence(i, "events hash", chunk.EventCollection.String(), replayed.EventsHashes[i].String()), nil
+		}
+		if replayed.StateCommitments[i] != chunk.EndState {
+			return divergence(i, "end state", fmt.Sprintf("%x", chunk.EndState), fmt.Sprintf("%x", replayed.StateCommitments[i])), nil
+		}
+	}
+
+	r.log.Info().
+		Uint64("height", height).
+		Hex("block_id", blockID[:]).
+		Int("chunks", len(stored.Chunks)).
+		Msg("replayed block matches stored result")
+
+	return nil, nil
+}
+
+// executableBlock returns the block with its stored collections, starting from the stored end state
+// of its parent.
+func (r *Replayer) executableBlock(block *flow.Block) (*entity.ExecutableBlock, error) {
+	startState, err := r.commits.ByBlockID(block.Header.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve end state of parent block: %w", err)
+	}
+
+	completeCollections := make(map[flow.Identifier]*entity.CompleteCollection, len(block.Payload.Guarantees))
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := r.collections.ByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve collection %v: %w", guarantee.CollectionID, err)
+		}
+		completeCollections[guarantee.ID()] = &entity.CompleteCollection{
+			Guarantee:    guarantee,
+			Transactions: collection.Transactions,
+		}
+	}
+
+	return &entity.ExecutableBlock{
+		Block:               block,
+		CompleteCollections: completeCollections,
+		StartState:          &startState,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package replay_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution"
+	computermock "github.com/onflow/flow-go/engine/execution/computation/computer/mock"
+	"github.com/onflow/flow-go/engine/execution/replay"
+	ledgermock "github.com/onflow/flow-go/ledger/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReplayBlocks(t *testing.T) {
+	blocks := new(storagemock.Blocks)
+	collections := new(storagemock.Collections)
+	commits := new(storagemock.Commits)
+	results := new(storagemock.ExecutionResults)
+	blockComputer := new(computermock.BlockComputer)
+
+	// a chain of three blocks with a collection each, whose stored results have a chunk per collection
+	// and a system chunk
+	parent := unittest.BlockHeaderFixture()
+	commits.On("ByBlockID", parent.ID()).Return(unittest.StateCommitmentFixture(), nil)
+
+	var chain []*flow.Block
+	var computed []*execution.ComputationResult
+	for i := 0; i < 3; i++ {
+		collection := unittest.CollectionFixture(1)
+		guarantee := &flow.CollectionGuarantee{CollectionID: collection.ID()}
+		block := unittest.BlockWithParentFixture(&parent)
+		block.Payload.Guarantees = []*flow.CollectionGuarantee{guarantee}
+		block.Header.PayloadHash = block.Payload.Hash()
+		chain = append(chain, &block)
+		parent = *block.Header
+
+		result := unittest.ExecutionResultFixture(unittest.WithBlock(&block))
+		computation := &execution.ComputationResult{}
+		for _, chunk := range result.Chunks {
+			computation.StateCommitments = append(computation.StateCommitments, chunk.EndState)
+			computation.EventsHashes = append(computation.EventsHashes, chunk.EventCollection)
+		}
+		computed = append(computed, computation)
+
+		blocks.On("ByHeight", block.Header.Height).Return(&block, nil)
+		collections.On("ByID", collection.ID()).Return(&collection, nil)
+		commits.On("ByBlockID", block.ID()).Return(result.Chunks[1].EndState, nil)
+		results.On("ByBlockID", block.ID()).Return(result, nil)
+		blockComputer.On("ExecuteBlock", mock.Anything, mock.MatchedBy(func(eb *entity.ExecutableBlock) bool {
+			return eb.ID() == block.ID()
+		}), mock.Anything, mock.Anything).Return(computation, nil)
+	}
+
+	replayer := replay.NewReplayer(zerolog.Nop(), blockComputer, new(ledgermock.Ledger), blocks, collections, commits, results)
+	start := chain[0].Header.Height
+	end := chain[2].Header.Height
+
+	t.Run("matching blocks", func(t *testing.T) {
+		divergence, err := replayer.ReplayBlocks(context.Background(), start, end)
+		require.NoError(t, err)
+		assert.Nil(t, divergence)
+	})
+
+	t.Run("diverging block", func(t *testing.T) {
+		computed[1].StateCommitments[1] = unittest.StateCommitmentFixture()
+		computed[2].EventsHashes[0] = unittest.IdentifierFixture()
+
+		divergence, err := replayer.ReplayBlocks(context.Background(), start, end)
+		require.NoError(t, err)
+		require.NotNil(t, divergence)
+		assert.Equal(t, chain[1].ID(), divergence.BlockID)
+		assert.Equal(t, chain[1].Header.Height, divergence.Height)
+		assert.Equal(t, 1, divergence.ChunkIndex)
+		assert.Equal(t, "end state", divergence.Reason)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net"
+	"sort"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/rs/zerolog"
@@ -16,6 +17,8 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
+	executionresults "github.com/onflow/flow-go/engine/execution/rpc/protobuf"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
 	grpcutils "github.com/onflow/flow-go/utils/grpc"
@@ -87,6 +90,7 @@ func New(
 	}
 
 	execution.RegisterExecutionAPIServer(eng.server, eng.handler)
+	executionresults.RegisterExecutionResultsAPIServer(eng.server, eng.handler)
 
 	return eng
 }
@@ -258,6 +262,142 @@ func (h *handler) GetTransactionResult(
 	}, nil
 }
 
+// MaxTransactionResultsPerPage is the maximum number of transaction results returned at once.
+const MaxTransactionResultsPerPage = 500
+
+// GetTransactionResultsByBlockID returns the results of the transactions executed in the given block,
+// paginated by `offset` and `limit`, so that all results of a block can be fetched in a few calls
+// instead of one call per transaction. Results are in execution order, that is the transactions of
+// the block's collections followed by the system transactions, and fewer than `limit` results are
+// returned for the last page. The limit is capped at MaxTransactionResultsPerPage.
+func (h *handler) GetTransactionResultsByBlockID(
+	_ context.Context,
+	req *executionresults.GetTransactionResultsByBlockIDRequest,
+) (*executionresults.GetTransactionResultsByBlockIDResponse, error) {
+
+	blockID, err := convert.BlockID(req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	offset := uint(req.GetOffset())
+	limit := uint(req.GetLimit())
+	if limit == 0 || limit > MaxTransactionResultsPerPage {
+		limit = MaxTransactionResultsPerPage
+	}
+
+	// check if block has been executed
+	if _, err := h.exeResults.ByBlockID(blockID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "results for block ID %s does not exist", blockID)
+		}
+		return nil, status.Errorf(codes.Internal, "results for block ID %s could not be retrieved", blockID)
+	}
+
+	txResults, err := h.transactionResults.ByBlockID(blockID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get transaction results: %v", err)
+	}
+	if offset >= uint(len(txResults)) {
+		return &executionresults.GetTransactionResultsByBlockIDResponse{}, nil
+	}
+	end := offset + limit
+	if end > uint(len(txResults)) {
+		end = uint(len(txResults))
+	}
+	txResults = txResults[offset:end]
+
+	// lookup the events of the whole block at once, and group them by transaction
+	blockEvents, err := h.events.ByBlockID(blockID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get events for block: %v", err)
+	}
+	eventsByTx := make(map[flow.Identifier][]flow.Event)
+	for _, event := range blockEvents {
+		eventsByTx[event.TransactionID] = append(eventsByTx[event.TransactionID], event)
+	}
+
+	results := make([]*executionresults.TransactionResult, 0, len(txResults))
+	for _, txResult := range txResults {
+		var statusCode uint32 = 0 // 1 indicates an error and 0 indicates no error, like for GetTransactionResult
+		if txResult.ErrorMessage != "" {
+			statusCode = 1
+		}
+		txID := txResult.TransactionID
+		results = append(results, &executionresults.TransactionResult{
+			TransactionId: txID[:],
+			StatusCode:    statusCode,
+			ErrorMessage:  txResult.ErrorMessage,
+			Events:        convert.EventsToMessages(eventsByTx[txID]),
+		})
+	}
+
+	return &executionresults.GetTransactionResultsByBlockIDResponse{
+		TransactionResults: results,
+	}, nil
+}
+
+// GetTransactionErrorSummary classifies the failed transactions of the given block by error code,
+// so that the individual error messages don't need to be inspected. Classes are ordered by error code
+// and the transactions of a class by their index in the block. Errors which do not carry an error code
+// are grouped under code 0.
+func (h *handler) GetTransactionErrorSummary(
+	_ context.Context,
+	req *executionresults.GetTransactionErrorSummaryRequest,
+) (*executionresults.GetTransactionErrorSummaryResponse, error) {
+
+	blockID, err := convert.BlockID(req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	// check if block has been executed
+	if _, err := h.exeResults.ByBlockID(blockID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "results for block ID %s does not exist", blockID)
+		}
+		return nil, status.Errorf(codes.Internal, "results for block ID %s could not be retrieved", blockID)
+	}
+
+	// the results are in execution order, so the transactions of each class are ordered by index
+	txResults, err := h.transactionResults.ByBlockID(blockID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get transaction results: %v", err)
+	}
+
+	classes := make(map[fvmerrors.ErrorCode]*executionresults.TransactionErrorClass)
+	for _, txResult := range txResults {
+		if txResult.ErrorMessage == "" {
+			continue
+		}
+
+		code, _ := fvmerrors.ErrorCodeFromMessage(txResult.ErrorMessage)
+		class, ok := classes[code]
+		if !ok {
+			class = &executionresults.TransactionErrorClass{
+				Code:         uint32(code),
+				ErrorMessage: txResult.ErrorMessage, // the error message of the first failed transaction of this class
+			}
+			classes[code] = class
+		}
+		txID := txResult.TransactionID
+		class.Count++
+		class.TransactionIds = append(class.TransactionIds, txID[:])
+	}
+
+	summary := make([]*executionresults.TransactionErrorClass, 0, len(classes))
+	for _, class := range classes {
+		summary = append(summary, class)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Code < summary[j].Code
+	})
+
+	return &executionresults.GetTransactionErrorSummaryResponse{
+		ErrorClasses: summary,
+	}, nil
+}
+
 // eventResult creates EventsResponse_Result from flow.Event for the given blockID
 func (h *handler) eventResult(blockID flow.Identifier,
 	flowEvents []flow.Event) (*execution.GetEventsForBlockIDsResponse_Result, error) {
@@ -16,6 +16,8 @@ import (
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	ingestion "github.com/onflow/flow-go/engine/execution/ingestion/mock"
+	executionresults "github.com/onflow/flow-go/engine/execution/rpc/protobuf"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/model/flow"
 	realstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/mock"
@@ -424,3 +426,137 @@ func (suite *Suite) TestGetTransactionResult() {
 		suite.events.AssertExpectations(suite.T())
 	})
 }
+
+// TestGetTransactionErrorSummary tests that failed transactions of a block are classified by error code
+func (suite *Suite) TestGetTransactionErrorSummary() {
+
+	block := unittest.BlockFixture()
+	bID := block.ID()
+
+	seqNumberErr := fvmerrors.NewInvalidProposalSeqNumberError(unittest.AddressFixture(), 0, 2, 1)
+	storageErr := fvmerrors.NewStorageCapacityExceededError(unittest.AddressFixture(), 200, 100)
+
+	txResults := []flow.TransactionResult{
+		{TransactionID: unittest.IdentifierFixture(), ErrorMessage: ""},
+		{TransactionID: unittest.IdentifierFixture(), ErrorMessage: storageErr.Error()},
+		{TransactionID: unittest.IdentifierFixture(), ErrorMessage: seqNumberErr.Error()},
+		{TransactionID: unittest.IdentifierFixture(), ErrorMessage: seqNumberErr.Error()},
+		{TransactionID: unittest.IdentifierFixture(), ErrorMessage: "unknown error"},
+	}
+
+	suite.Run("executed block", func() {
+		suite.exeResults.On("ByBlockID", bID).Return(nil, nil).Once()
+		suite.txResults.On("ByBlockID", bID).Return(txResults, nil).Once()
+
+		handler := &handler{
+			exeResults:         suite.exeResults,
+			transactionResults: suite.txResults,
+		}
+
+		resp, err := handler.GetTransactionErrorSummary(context.Background(),
+			&executionresults.GetTransactionErrorSummaryRequest{BlockId: bID[:]})
+		suite.Require().NoError(err)
+		summary := resp.ErrorClasses
+		suite.Require().Len(summary, 3)
+
+		// classes are ordered by error code, and their transactions by index in the block
+		suite.Assert().Equal(uint32(0), summary[0].Code)
+		suite.Assert().Equal(uint32(1), summary[0].Count)
+		suite.Assert().Equal(uint32(fvmerrors.ErrCodeInvalidProposalSeqNumberError), summary[1].Code)
+		suite.Assert().Equal(uint32(2), summary[1].Count)
+		suite.Assert().Equal([][]byte{txResults[2].TransactionID[:], txResults[3].TransactionID[:]}, summary[1].TransactionIds)
+		suite.Assert().Equal(seqNumberErr.Error(), summary[1].ErrorMessage)
+		suite.Assert().Equal(uint32(fvmerrors.ErrCodeStorageCapacityExceeded), summary[2].Code)
+		suite.Assert().Equal(uint32(1), summary[2].Count)
+	})
+
+	suite.Run("block not executed", func() {
+		unknownID := unittest.IdentifierFixture()
+		suite.exeResults.On("ByBlockID", unknownID).Return(nil, realstorage.ErrNotFound).Once()
+
+		handler := &handler{
+			exeResults:         suite.exeResults,
+			transactionResults: suite.txResults,
+		}
+
+		_, err := handler.GetTransactionErrorSummary(context.Background(),
+			&executionresults.GetTransactionErrorSummaryRequest{BlockId: unknownID[:]})
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+}
+
+// TestGetTransactionResultsByBlockID tests that the results of a block's transactions are returned
+// with their events, page by page, in execution order
+func (suite *Suite) TestGetTransactionResultsByBlockID() {
+
+	block := unittest.BlockFixture()
+	bID := block.ID()
+
+	txIDs := unittest.IdentifierListFixture(5)
+	txResults := make([]flow.TransactionResult, 0, len(txIDs))
+	events := make([]flow.Event, 0, len(txIDs))
+	for i, txID := range txIDs {
+		txResult := flow.TransactionResult{TransactionID: txID}
+		if i%2 == 0 {
+			txResult.ErrorMessage = "failed"
+		}
+		txResults = append(txResults, txResult)
+		events = append(events, unittest.EventFixture(flow.EventAccountCreated, uint32(i), 0, txID))
+	}
+
+	suite.Run("executed block", func() {
+		suite.exeResults.On("ByBlockID", bID).Return(nil, nil)
+		suite.txResults.On("ByBlockID", bID).Return(txResults, nil)
+		suite.events.On("ByBlockID", bID).Return(events, nil)
+
+		handler := &handler{
+			events:             suite.events,
+			exeResults:         suite.exeResults,
+			transactionResults: suite.txResults,
+		}
+
+		firstPage, err := handler.GetTransactionResultsByBlockID(context.Background(),
+			&executionresults.GetTransactionResultsByBlockIDRequest{BlockId: bID[:], Offset: 0, Limit: 3})
+		suite.Require().NoError(err)
+		suite.Require().Len(firstPage.TransactionResults, 3)
+
+		lastPage, err := handler.GetTransactionResultsByBlockID(context.Background(),
+			&executionresults.GetTransactionResultsByBlockIDRequest{BlockId: bID[:], Offset: 3, Limit: 3})
+		suite.Require().NoError(err)
+		suite.Require().Len(lastPage.TransactionResults, 2)
+
+		// the results are in the order they were executed in
+		for i, result := range append(firstPage.TransactionResults, lastPage.TransactionResults...) {
+			suite.Assert().Equal(txIDs[i][:], result.TransactionId)
+			suite.Require().Len(result.Events, 1)
+			suite.Assert().Equal(result.TransactionId, result.Events[0].TransactionId)
+			suite.Assert().Equal(txResults[i].ErrorMessage, result.ErrorMessage)
+			if result.ErrorMessage != "" {
+				suite.Assert().Equal(uint32(1), result.StatusCode)
+			} else {
+				suite.Assert().Equal(uint32(0), result.StatusCode)
+			}
+		}
+
+		beyond, err := handler.GetTransactionResultsByBlockID(context.Background(),
+			&executionresults.GetTransactionResultsByBlockIDRequest{BlockId: bID[:], Offset: 5, Limit: 3})
+		suite.Require().NoError(err)
+		suite.Assert().Empty(beyond.TransactionResults)
+	})
+
+	suite.Run("block not executed", func() {
+		unknownID := unittest.IdentifierFixture()
+		suite.exeResults.On("ByBlockID", unknownID).Return(nil, realstorage.ErrNotFound).Once()
+
+		handler := &handler{
+			exeResults:         suite.exeResults,
+			transactionResults: suite.txResults,
+		}
+
+		_, err := handler.GetTransactionResultsByBlockID(context.Background(),
+			&executionresults.GetTransactionResultsByBlockIDRequest{BlockId: unknownID[:], Limit: 10})
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+}
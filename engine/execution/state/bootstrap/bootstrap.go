@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/rs/zerolog"
@@ -33,6 +34,20 @@ func (b *Bootstrapper) BootstrapLedger(
 	servicePublicKey flow.AccountPublicKey,
 	chain flow.Chain,
 	opts ...fvm.BootstrapProcedureOption,
+) (flow.StateCommitment, error) {
+	return b.BootstrapLedgerWithSnapshot(ledger, servicePublicKey, chain, nil, opts...)
+}
+
+// BootstrapLedgerWithSnapshot bootstraps the ledger like BootstrapLedger, then imports the registers
+// of the given snapshot (see OpenRegisterSnapshot) on top of the bootstrapped state, e.g. to carry
+// over the migrated user state of the previous spork. Registers of the snapshot overwrite the
+// registers set during bootstrapping. A nil snapshot imports no registers.
+func (b *Bootstrapper) BootstrapLedgerWithSnapshot(
+	ledger ledger.Ledger,
+	servicePublicKey flow.AccountPublicKey,
+	chain flow.Chain,
+	snapshot io.Reader,
+	opts ...fvm.BootstrapProcedureOption,
 ) (flow.StateCommitment, error) {
 	view := delta.NewView(state.LedgerGetRegister(ledger, flow.StateCommitment(ledger.InitialState())))
 	programs := programs.NewEmptyPrograms()
@@ -52,6 +67,14 @@ func (b *Bootstrapper) BootstrapLedger(
 		return flow.DummyStateCommitment, err
 	}
 
+	if snapshot != nil {
+		count, err := importRegisterSnapshot(view, snapshot)
+		if err != nil {
+			return flow.DummyStateCommitment, fmt.Errorf("could not import register snapshot: %w", err)
+		}
+		b.logger.Info().Int("registers", count).Msg("imported register snapshot")
+	}
+
 	newStateCommitment, err := state.CommitDelta(ledger, view.Delta(), flow.StateCommitment(ledger.InitialState()))
 	if err != nil {
 		return flow.DummyStateCommitment, err
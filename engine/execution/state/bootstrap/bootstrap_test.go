@@ -1,14 +1,18 @@
 package bootstrap
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"testing"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger"
 	completeLedger "github.com/onflow/flow-go/ledger/complete"
 	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
 	"github.com/onflow/flow-go/model/flow"
@@ -76,3 +80,37 @@ func TestBootstrapLedger_ZeroTokenSupply(t *testing.T) {
 		}
 	})
 }
+
+func TestBootstrapLedgerWithSnapshot(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dbDir string) {
+
+		chain := flow.Mainnet.Chain()
+
+		metricsCollector := &metrics.NoopCollector{}
+		wal := &fixtures.NoopWAL{}
+		ls, err := completeLedger.NewLedger(wal, 100, metricsCollector, zerolog.Nop(), completeLedger.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		owner := string(chain.ServiceAddress().Bytes())
+		registerID := flow.NewRegisterID(owner, "", "migrated")
+		value := ledger.Value("migrated value")
+
+		var snapshot bytes.Buffer
+		err = json.NewEncoder(&snapshot).Encode(ledger.NewPayload(state.RegisterIDToKey(registerID), value))
+		require.NoError(t, err)
+
+		stateCommitment, err := NewBootstrapper(zerolog.Nop()).BootstrapLedgerWithSnapshot(
+			ls,
+			unittest.ServiceAccountPublicKey,
+			chain,
+			&snapshot,
+			fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply),
+		)
+		require.NoError(t, err)
+		assert.NotEqual(t, unittest.GenesisStateCommitment, stateCommitment)
+
+		imported, err := state.LedgerGetRegister(ls, stateCommitment)(registerID.Owner, registerID.Controller, registerID.Key)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(value), imported)
+	})
+}
@@ -0,0 +1,77 @@
+package bootstrap
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// OpenRegisterSnapshot opens a register snapshot, as written by the `export-json-execution-state`
+// util command: one JSON encoded ledger payload per line, gzip compressed if the file name ends
+// with `.gz`. The caller is responsible for closing the returned reader.
+func OpenRegisterSnapshot(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open register snapshot: %w", err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+	reader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("could not decompress register snapshot: %w", err)
+	}
+	return &gzipFile{Reader: reader, file: file}, nil
+}
+
+// gzipFile closes both the gzip reader and the underlying file.
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	err := g.Reader.Close()
+	if err != nil {
+		_ = g.file.Close()
+		return err
+	}
+	return g.file.Close()
+}
+
+// importRegisterSnapshot sets every register of the snapshot in the given view, overwriting the
+// registers already set. It returns the number of imported registers.
+func importRegisterSnapshot(view *delta.View, snapshot io.Reader) (int, error) {
+	decoder := json.NewDecoder(snapshot)
+	count := 0
+	for {
+		var payload ledger.Payload
+		err := decoder.Decode(&payload)
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("could not decode register %d of snapshot: %w", count, err)
+		}
+		id, err := state.KeyToRegisterID(payload.Key)
+		if err != nil {
+			return count, fmt.Errorf("invalid register %d of snapshot: %w", count, err)
+		}
+		err = view.Set(id.Owner, id.Controller, id.Key, flow.RegisterValue(payload.Value))
+		if err != nil {
+			return count, fmt.Errorf("could not set register %d of snapshot: %w", count, err)
+		}
+		count++
+	}
+}
@@ -23,7 +23,18 @@ type View struct {
 	// TODO we can add a flag to disable capturing SpocksSecret
 	// for views other than collection views to improve performance
 	spockSecretHasher hash.Hasher
+	spockSecret       []byte // SPoCK secret of views restored from snapshots, which can't be written to
 	readFunc          GetRegisterFunc
+	savepoints        []savepoint // savepoints held on the view, from the outermost to the innermost
+}
+
+// SavepointID identifies a savepoint held on a view.
+type SavepointID int
+
+// savepoint records the entries of the delta as they were at the savepoint, for the registers
+// updated since, so that they can be restored on rollback.
+type savepoint struct {
+	undo map[string]*flow.RegisterEntry // entries before their first update, nil if the register wasn't in the delta
 }
 
 type Snapshot struct {
@@ -51,6 +62,22 @@ func NewView(readFunc GetRegisterFunc) *View {
 	}
 }
 
+// NewViewFromSnapshot restores a view with the interactions captured by the snapshot, like the views
+// of executed collections persisted before a restart. As the SPoCK secret of the snapshot can't be
+// extended, the restored view can be read from and merged into other views, but not written to.
+func NewViewFromSnapshot(readFunc GetRegisterFunc, snapshot *SpockSnapshot) *View {
+	v := NewView(readFunc)
+	for s, value := range snapshot.Delta.Data {
+		v.delta.Data[s] = value
+	}
+	for s, id := range snapshot.Reads {
+		v.regTouchSet[s] = id
+	}
+	v.spockSecret = make([]byte, len(snapshot.SpockSecret))
+	copy(v.spockSecret, snapshot.SpockSecret)
+	return v
+}
+
 // Snapshot returns copy of current state of interactions with a View
 func (v *View) Interactions() *SpockSnapshot {
 
@@ -68,7 +95,7 @@ func (v *View) Interactions() *SpockSnapshot {
 		reads[i] = id
 	}
 
-	spockSecHashSum := v.spockSecretHasher.SumHash()
+	spockSecHashSum := v.SpockSecret()
 	var spockSecret = make([]byte, len(spockSecHashSum))
 	copy(spockSecret, spockSecHashSum)
 
@@ -104,6 +131,83 @@ func (v *View) NewChild() state.View {
 
 func (v *View) DropDelta() {
 	v.delta = NewDelta()
+	v.savepoints = nil
+}
+
+// Savepoint marks the current state of the delta, so that later updates can be rolled back to it,
+// like the partial effects of a failed step of a transaction, without setting up a child view.
+// Savepoints nest: a savepoint taken while others are held is released or rolled back before them.
+func (v *View) Savepoint() SavepointID {
+	v.savepoints = append(v.savepoints, savepoint{undo: make(map[string]*flow.RegisterEntry)})
+	return SavepointID(len(v.savepoints) - 1)
+}
+
+// RollbackTo reverts the delta to its state at the given savepoint, and releases the savepoints taken
+// after it. The savepoint itself is kept, so that a retried step can be rolled back again.
+//
+// Only the delta is reverted: the registers touched, the reads count and the SPoCK secret still
+// account for the interactions rolled back, as they happened during the execution, and the values
+// read may have decided its outcome, just like the interactions of a dropped delta.
+func (v *View) RollbackTo(id SavepointID) error {
+	if id < 0 || int(id) >= len(v.savepoints) {
+		return fmt.Errorf("cannot roll back to savepoint %d: no such savepoint held", id)
+	}
+
+	for i := len(v.savepoints) - 1; i >= int(id); i-- {
+		for key, entry := range v.savepoints[i].undo {
+			if entry == nil {
+				delete(v.delta.Data, key)
+				continue
+			}
+			v.delta.Data[key] = *entry
+		}
+	}
+
+	v.savepoints = v.savepoints[:id+1]
+	v.savepoints[id].undo = make(map[string]*flow.RegisterEntry)
+	return nil
+}
+
+// ReleaseSavepoint releases the given savepoint, and the savepoints taken after it, keeping the
+// updates made since. They can still be rolled back to an enclosing savepoint.
+func (v *View) ReleaseSavepoint(id SavepointID) error {
+	if id < 0 || int(id) >= len(v.savepoints) {
+		return fmt.Errorf("cannot release savepoint %d: no such savepoint held", id)
+	}
+
+	// the enclosing savepoint takes over the entries of the released ones which it doesn't have yet;
+	// these registers weren't updated between the two savepoints, so the entries are the same
+	if id > 0 {
+		enclosing := v.savepoints[id-1].undo
+		for _, released := range v.savepoints[id:] {
+			for key, entry := range released.undo {
+				if _, ok := enclosing[key]; !ok {
+					enclosing[key] = entry
+				}
+			}
+		}
+	}
+
+	v.savepoints = v.savepoints[:id]
+	return nil
+}
+
+// recordUndo records the current entry of the register in the innermost savepoint, if any, before
+// the register is first updated since that savepoint.
+func (v *View) recordUndo(key string) {
+	if len(v.savepoints) == 0 {
+		return
+	}
+	undo := v.savepoints[len(v.savepoints)-1].undo
+	if _, ok := undo[key]; ok {
+		return
+	}
+	entry, ok := v.delta.Data[key]
+	if !ok {
+		undo[key] = nil
+		return
+	}
+	undo[key] = &entry
 }
 
 func (v *View) AllRegisters() []flow.RegisterID {
@@ -172,11 +276,15 @@ func (v *View) Set(owner, controller, key string, value flow.RegisterValue) erro
 
 	v.regTouchSet[registerID.String()] = registerID
 	// add key value to delta
+	v.recordUndo(registerID.String())
 	v.delta.Set(owner, controller, key, value)
 	return nil
 }
 
 func (v *View) updateSpock(value []byte) error {
+	if v.spockSecret != nil {
+		return fmt.Errorf("cannot update spock secret of a view restored from a snapshot")
+	}
 	_, err := v.spockSecretHasher.Write(value)
 	if err != nil {
 		return fmt.Errorf("error updating spock secret data: %w", err)
@@ -231,6 +339,9 @@ func (v *View) MergeView(ch state.View) error {
 	if err != nil {
 		return fmt.Errorf("can not merge view: %w", err)
 	}
+	for key := range child.delta.Data {
+		v.recordUndo(key)
+	}
 	v.delta.MergeWith(child.delta)
 
 	v.readsCount += child.readsCount
@@ -254,6 +365,9 @@ func (v *View) ReadsCount() uint64 {
 
 // SpockSecret returns the secret value for SPoCK
 func (v *View) SpockSecret() []byte {
+	if v.spockSecret != nil {
+		return v.spockSecret
+	}
 	return v.spockSecretHasher.SumHash()
 }
 
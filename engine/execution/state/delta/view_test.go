@@ -561,7 +561,180 @@ func TestView_Reads(t *testing.T) {
 	})
 }
 
+func TestView_FromSnapshot(t *testing.T) {
+	readFunc := func(owner, controller, key string) (flow.RegisterValue, error) {
+		return nil, nil
+	}
+
+	v := delta.NewView(readFunc)
+	err := v.Set("fruit", "", "", flow.RegisterValue("apple"))
+	require.NoError(t, err)
+	_, err = v.Get("vegetable", "", "")
+	require.NoError(t, err)
+
+	restored := delta.NewViewFromSnapshot(readFunc, v.Interactions())
+	assert.Equal(t, v.Interactions(), restored.Interactions())
+	assert.Equal(t, v.SpockSecret(), restored.SpockSecret())
+
+	b, err := restored.Get("fruit", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, flow.RegisterValue("apple"), b)
+
+	// merging the restored view is the same as merging the original view
+	parent := delta.NewView(readFunc)
+	err = parent.MergeView(v)
+	require.NoError(t, err)
+	restoredParent := delta.NewView(readFunc)
+	err = restoredParent.MergeView(restored)
+	require.NoError(t, err)
+	assert.Equal(t, parent.Interactions(), restoredParent.Interactions())
+
+	// the SPoCK secret of restored views can't be extended
+	assert.Panics(t, func() {
+		_ = restored.Set("fruit", "", "", flow.RegisterValue("orange"))
+	})
+}
+
 func hashIt(spock hash.Hasher, value []byte) error {
 	_, err := spock.Write(value)
 	return err
 }
+
+func TestView_Savepoints(t *testing.T) {
+	readFunc := func(owner, controller, key string) (flow.RegisterValue, error) {
+		if owner == "fruit" {
+			return flow.RegisterValue("orange"), nil
+		}
+		return nil, nil
+	}
+
+	get := func(t *testing.T, v *delta.View, owner string) flow.RegisterValue {
+		b, err := v.Get(owner, "", "")
+		require.NoError(t, err)
+		return b
+	}
+
+	t.Run("RollbackTo", func(t *testing.T) {
+		v := delta.NewView(readFunc)
+		err := v.Set("fruit", "", "", flow.RegisterValue("apple"))
+		require.NoError(t, err)
+		err = v.Set("vegetable", "", "", flow.RegisterValue("carrot"))
+		require.NoError(t, err)
+
+		sp := v.Savepoint()
+		err = v.Set("fruit", "", "", flow.RegisterValue("pear"))
+		require.NoError(t, err)
+		err = v.Set("fruit", "", "", flow.RegisterValue("plum"))
+		require.NoError(t, err)
+		err = v.Delete("vegetable", "", "")
+		require.NoError(t, err)
+		err = v.Set("nut", "", "", flow.RegisterValue("walnut"))
+		require.NoError(t, err)
+
+		err = v.RollbackTo(sp)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("apple"), get(t, v, "fruit"))
+		assert.Equal(t, flow.RegisterValue("carrot"), get(t, v, "vegetable"))
+		assert.Nil(t, get(t, v, "nut"))
+		assert.Len(t, v.Delta().Data, 2)
+
+		// the savepoint is kept, so that a retry can be rolled back again
+		err = v.Set("fruit", "", "", flow.RegisterValue("kiwi"))
+		require.NoError(t, err)
+		err = v.RollbackTo(sp)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("apple"), get(t, v, "fruit"))
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		v := delta.NewView(readFunc)
+
+		outer := v.Savepoint()
+		err := v.Set("fruit", "", "", flow.RegisterValue("apple"))
+		require.NoError(t, err)
+
+		inner := v.Savepoint()
+		err = v.Set("fruit", "", "", flow.RegisterValue("pear"))
+		require.NoError(t, err)
+		err = v.Set("vegetable", "", "", flow.RegisterValue("carrot"))
+		require.NoError(t, err)
+
+		err = v.RollbackTo(inner)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("apple"), get(t, v, "fruit"))
+		assert.Nil(t, get(t, v, "vegetable"))
+
+		// released updates can still be rolled back to the enclosing savepoint
+		err = v.Set("vegetable", "", "", flow.RegisterValue("leek"))
+		require.NoError(t, err)
+		err = v.ReleaseSavepoint(inner)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("leek"), get(t, v, "vegetable"))
+
+		err = v.RollbackTo(inner)
+		assert.Error(t, err)
+
+		err = v.RollbackTo(outer)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("orange"), get(t, v, "fruit"))
+		assert.Nil(t, get(t, v, "vegetable"))
+		assert.Empty(t, v.Delta().Data)
+	})
+
+	t.Run("RollbackTo releases later savepoints", func(t *testing.T) {
+		v := delta.NewView(readFunc)
+
+		outer := v.Savepoint()
+		inner := v.Savepoint()
+		err := v.RollbackTo(outer)
+		require.NoError(t, err)
+
+		err = v.ReleaseSavepoint(inner)
+		assert.Error(t, err)
+		err = v.ReleaseSavepoint(outer)
+		require.NoError(t, err)
+		err = v.RollbackTo(outer)
+		assert.Error(t, err)
+	})
+
+	t.Run("MergeView", func(t *testing.T) {
+		v := delta.NewView(readFunc)
+		err := v.Set("fruit", "", "", flow.RegisterValue("apple"))
+		require.NoError(t, err)
+
+		sp := v.Savepoint()
+		child := v.NewChild()
+		err = child.Set("fruit", "", "", flow.RegisterValue("pear"))
+		require.NoError(t, err)
+		err = child.Set("vegetable", "", "", flow.RegisterValue("carrot"))
+		require.NoError(t, err)
+		err = v.MergeView(child)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("pear"), get(t, v, "fruit"))
+
+		err = v.RollbackTo(sp)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("apple"), get(t, v, "fruit"))
+		assert.Nil(t, get(t, v, "vegetable"))
+	})
+
+	t.Run("interactions are kept", func(t *testing.T) {
+		v := delta.NewView(readFunc)
+
+		sp := v.Savepoint()
+		_, err := v.Get("fruit", "", "")
+		require.NoError(t, err)
+		err = v.Set("vegetable", "", "", flow.RegisterValue("carrot"))
+		require.NoError(t, err)
+		interactions := v.Interactions()
+		reads := v.ReadsCount()
+
+		// the rolled back execution read and wrote the registers, which the interactions account for
+		err = v.RollbackTo(sp)
+		require.NoError(t, err)
+		assert.Empty(t, v.Delta().Data)
+		assert.Equal(t, interactions.Reads, v.Interactions().Reads)
+		assert.Equal(t, interactions.SpockSecret, v.SpockSecret())
+		assert.Equal(t, reads, v.ReadsCount())
+	})
+}
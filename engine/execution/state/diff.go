@@ -0,0 +1,75 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// RegisterDiff is a register whose value differs between two states.
+type RegisterDiff struct {
+	ID     flow.RegisterID
+	Before flow.RegisterValue // nil if the register doesn't exist in the first state
+	After  flow.RegisterValue // nil if the register doesn't exist in the second state
+}
+
+// Diff returns the registers whose values differ between the states with the given commitments, which
+// must be held by the forest. With an address filter, only the registers owned by the given addresses
+// are returned. The tries of the states are walked side by side, skipping the sub-tries they share, so
+// diffing the states before and after a block only visits the registers the block updated. It is used
+// to debug execution forks and to explain what a block changed.
+func Diff(forest *mtrie.Forest, commitA, commitB flow.StateCommitment, addressFilter ...flow.Address) ([]RegisterDiff, error) {
+	trieA, err := forest.GetTrie(ledger.RootHash(commitA))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find trie of state %x: %w", commitA, err)
+	}
+	trieB, err := forest.GetTrie(ledger.RootHash(commitB))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find trie of state %x: %w", commitB, err)
+	}
+
+	owners := make(map[string]struct{}, len(addressFilter))
+	for _, address := range addressFilter {
+		owners[string(address.Bytes())] = struct{}{}
+	}
+
+	var diffs []RegisterDiff
+	err = trie.DiffPayloads(trieA, trieB, func(_ ledger.Path, before, after *ledger.Payload) error {
+		payload := after
+		if payload == nil {
+			payload = before
+		}
+		registerID, err := KeyToRegisterID(payload.Key)
+		if err != nil {
+			return fmt.Errorf("cannot convert key of differing register: %w", err)
+		}
+
+		if len(owners) > 0 {
+			if _, ok := owners[registerID.Owner]; !ok {
+				return nil
+			}
+		}
+
+		diffs = append(diffs, RegisterDiff{
+			ID:     registerID,
+			Before: registerValue(before),
+			After:  registerValue(after),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot diff states %x and %x: %w", commitA, commitB, err)
+	}
+
+	return diffs, nil
+}
+
+func registerValue(payload *ledger.Payload) flow.RegisterValue {
+	if payload == nil {
+		return nil
+	}
+	return flow.RegisterValue(payload.Value)
+}
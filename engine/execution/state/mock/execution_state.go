@@ -216,6 +216,34 @@ func (_m *ExecutionState) PersistExecutionState(ctx context.Context, header *flo
 	return r0
 }
 
+// PersistStateDelta provides a mock function with given fields: ctx, stateDelta, result
+func (_m *ExecutionState) PersistStateDelta(ctx context.Context, stateDelta *messages.ExecutionStateDelta, result *flow.ExecutionResult) error {
+	ret := _m.Called(ctx, stateDelta, result)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *messages.ExecutionStateDelta, *flow.ExecutionResult) error); ok {
+		r0 = rf(ctx, stateDelta, result)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PersistStateInteractions provides a mock function with given fields: ctx, blockID, interactions
+func (_m *ExecutionState) PersistStateInteractions(ctx context.Context, blockID flow.Identifier, interactions []*delta.Snapshot) error {
+	ret := _m.Called(ctx, blockID, interactions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, []*delta.Snapshot) error); ok {
+		r0 = rf(ctx, blockID, interactions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RetrieveStateDelta provides a mock function with given fields: _a0, _a1
 func (_m *ExecutionState) RetrieveStateDelta(_a0 context.Context, _a1 flow.Identifier) (*messages.ExecutionStateDelta, error) {
 	ret := _m.Called(_a0, _a1)
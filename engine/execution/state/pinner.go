@@ -0,0 +1,76 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// StatePinner pins the end states of executed blocks in the ledger until the blocks are sealed,
+// so that the ledger doesn't evict states which are still needed to execute their descendants.
+type StatePinner struct {
+	ledger ledger.PinningLedger
+
+	mu           sync.Mutex
+	pinned       map[uint64][]flow.StateCommitment // pinned end states by block height
+	sealedHeight uint64
+}
+
+// NewStatePinner creates a pinner for the states of the given ledger.
+func NewStatePinner(ledger ledger.PinningLedger) *StatePinner {
+	return &StatePinner{
+		ledger: ledger,
+		pinned: make(map[uint64][]flow.StateCommitment),
+	}
+}
+
+// BlockExecuted pins the end state of the executed block, unless a descendant of its height
+// is sealed already.
+func (p *StatePinner) BlockExecuted(header *flow.Header, endState flow.StateCommitment) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if header.Height < p.sealedHeight {
+		return nil
+	}
+
+	err := p.ledger.Pin(ledger.State(endState))
+	if err != nil {
+		return fmt.Errorf("could not pin end state of block %x: %w", header.ID(), err)
+	}
+	p.pinned[header.Height] = append(p.pinned[header.Height], endState)
+
+	return nil
+}
+
+// BlockSealed unpins the end states of all blocks below the height of the sealed block.
+// The states at the sealed height remain pinned, as the children of the sealed block are
+// executed on top of its end state.
+func (p *StatePinner) BlockSealed(header *flow.Header) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if header.Height <= p.sealedHeight {
+		return nil
+	}
+	p.sealedHeight = header.Height
+
+	for height, states := range p.pinned {
+		if height >= header.Height {
+			continue
+		}
+		for i, state := range states {
+			err := p.ledger.Unpin(ledger.State(state))
+			if err != nil {
+				// keep the states which are still pinned
+				p.pinned[height] = states[i:]
+				return fmt.Errorf("could not unpin end state of block at height %d: %w", height, err)
+			}
+		}
+		delete(p.pinned, height)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	ledgermock "github.com/onflow/flow-go/ledger/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestStatePinner(t *testing.T) {
+
+	headerAt := func(height uint64) *flow.Header {
+		header := unittest.BlockHeaderFixture()
+		header.Height = height
+		return &header
+	}
+
+	t.Run("unpins states below sealed height", func(t *testing.T) {
+		ldg := new(ledgermock.PinningLedger)
+		pinner := state.NewStatePinner(ldg)
+
+		states := make([]flow.StateCommitment, 4)
+		for height := range states {
+			states[height] = unittest.StateCommitmentFixture()
+			ldg.On("Pin", ledger.State(states[height])).Return(nil).Once()
+			require.NoError(t, pinner.BlockExecuted(headerAt(uint64(height)), states[height]))
+		}
+
+		ldg.On("Unpin", ledger.State(states[0])).Return(nil).Once()
+		ldg.On("Unpin", ledger.State(states[1])).Return(nil).Once()
+		require.NoError(t, pinner.BlockSealed(headerAt(2)))
+
+		// sealing the same height again doesn't unpin anything
+		require.NoError(t, pinner.BlockSealed(headerAt(2)))
+
+		ldg.AssertExpectations(t)
+		ldg.AssertNotCalled(t, "Unpin", ledger.State(states[2]))
+		ldg.AssertNotCalled(t, "Unpin", ledger.State(states[3]))
+	})
+
+	t.Run("doesn't pin states of blocks below sealed height", func(t *testing.T) {
+		ldg := new(ledgermock.PinningLedger)
+		pinner := state.NewStatePinner(ldg)
+
+		require.NoError(t, pinner.BlockSealed(headerAt(10)))
+		require.NoError(t, pinner.BlockExecuted(headerAt(9), unittest.StateCommitmentFixture()))
+
+		ldg.AssertNotCalled(t, "Pin", mock.Anything)
+	})
+}
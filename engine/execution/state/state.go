@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/dgraph-io/badger/v2"
 
@@ -39,7 +40,8 @@ type ReadOnlyExecutionState interface {
 	// StateCommitmentByBlockID returns the final state commitment for the provided block ID.
 	StateCommitmentByBlockID(context.Context, flow.Identifier) (flow.StateCommitment, error)
 
-	// ChunkDataPackByChunkID retrieve a chunk data pack given the chunk ID.
+	// ChunkDataPackByChunkID retrieve a chunk data pack given the chunk ID. The chunk data packs held
+	// by the chunk data pack cache are not retrieved.
 	ChunkDataPackByChunkID(context.Context, flow.Identifier) (*flow.ChunkDataPack, error)
 
 	GetExecutionResultID(context.Context, flow.Identifier) (flow.Identifier, error)
@@ -64,6 +66,16 @@ type ExecutionState interface {
 	UpdateHighestExecutedBlockIfHigher(context.Context, *flow.Header) error
 
 	PersistExecutionState(ctx context.Context, header *flow.Header, endState flow.StateCommitment, chunkDataPacks []*flow.ChunkDataPack, executionReceipt *flow.ExecutionReceipt, events []flow.Event, serviceEvents []flow.Event, results []flow.TransactionResult) error
+
+	// PersistStateInteractions persists the state interactions of the chunks of an executed block,
+	// so that the state delta of the block can be served to other execution nodes.
+	PersistStateInteractions(ctx context.Context, blockID flow.Identifier, interactions []*delta.Snapshot) error
+
+	// PersistStateDelta persists the execution state of a block synced from the state delta of
+	// another execution node, instead of executing the block, together with the sealed result of
+	// the block. The register updates of the delta must already be applied to the ledger, and the
+	// events of the delta must match the result.
+	PersistStateDelta(ctx context.Context, stateDelta *messages.ExecutionStateDelta, result *flow.ExecutionResult) error
 }
 
 const (
@@ -80,6 +92,7 @@ type state struct {
 	headers            storage.Headers
 	collections        storage.Collections
 	chunkDataPacks     storage.ChunkDataPacks
+	chunkDataPackCache storage.ChunkDataPackCache // holds the chunk data packs of recent blocks instead of chunkDataPacks, optional
 	results            storage.ExecutionResults
 	receipts           storage.ExecutionReceipts
 	myReceipts         storage.MyExecutionReceipts
@@ -97,7 +110,25 @@ func RegisterIDToKey(reg flow.RegisterID) ledger.Key {
 	})
 }
 
-// NewExecutionState returns a new execution state access layer for the given ledger storage.
+// KeyToRegisterID converts a ledger key back to the register ID it was created from.
+func KeyToRegisterID(key ledger.Key) (flow.RegisterID, error) {
+	if len(key.KeyParts) != 3 ||
+		key.KeyParts[0].Type != KeyPartOwner ||
+		key.KeyParts[1].Type != KeyPartController ||
+		key.KeyParts[2].Type != KeyPartKey {
+		return flow.RegisterID{}, fmt.Errorf("key not in expected format %s", key.String())
+	}
+
+	return flow.NewRegisterID(
+		string(key.KeyParts[0].Value),
+		string(key.KeyParts[1].Value),
+		string(key.KeyParts[2].Value),
+	), nil
+}
+
+// NewExecutionState returns a new execution state access layer for the given ledger storage. If a
+// chunk data pack cache is given, the chunk data packs of executed blocks are stored in the cache
+// rather than in the chunk data pack storage.
 func NewExecutionState(
 	ls ledger.Ledger,
 	commits storage.Commits,
@@ -105,6 +136,7 @@ func NewExecutionState(
 	headers storage.Headers,
 	collections storage.Collections,
 	chunkDataPacks storage.ChunkDataPacks,
+	chunkDataPackCache storage.ChunkDataPackCache,
 	results storage.ExecutionResults,
 	receipts storage.ExecutionReceipts,
 	myReceipts storage.MyExecutionReceipts,
@@ -122,6 +154,7 @@ func NewExecutionState(
 		headers:            headers,
 		collections:        collections,
 		chunkDataPacks:     chunkDataPacks,
+		chunkDataPackCache: chunkDataPackCache,
 		results:            results,
 		receipts:           receipts,
 		myReceipts:         myReceipts,
@@ -333,13 +366,23 @@ func (s *state) PersistExecutionState(ctx context.Context, header *flow.Header,
 	batch := badgerstorage.NewBatch(s.db)
 
 	sp, _ := s.tracer.StartSpanFromContext(ctx, trace.EXEPersistChunkDataPack)
-	for _, chunkDataPack := range chunkDataPacks {
-		err := s.chunkDataPacks.BatchStore(chunkDataPack, batch)
+	if s.chunkDataPackCache != nil {
+		// the cache has its own database, the chunk data packs are stored before the batch, so that
+		// they are available once the block is known to be executed
+		err := s.chunkDataPackCache.Store(header.Height, chunkDataPacks)
 		if err != nil {
-			return fmt.Errorf("cannot store chunk data pack: %w", err)
+			return fmt.Errorf("cannot cache chunk data packs: %w", err)
+		}
+	}
+	for _, chunkDataPack := range chunkDataPacks {
+		if s.chunkDataPackCache == nil {
+			err := s.chunkDataPacks.BatchStore(chunkDataPack, batch)
+			if err != nil {
+				return fmt.Errorf("cannot store chunk data pack: %w", err)
+			}
 		}
 
-		err = s.headers.BatchIndexByChunkID(header.ID(), chunkDataPack.ChunkID, batch)
+		err := s.headers.BatchIndexByChunkID(header.ID(), chunkDataPack.ChunkID, batch)
 		if err != nil {
 			return fmt.Errorf("cannot index chunk data pack by blockID: %w", err)
 		}
@@ -400,6 +443,83 @@ func (s *state) PersistExecutionState(ctx context.Context, header *flow.Header,
 	return nil
 }
 
+func (s *state) PersistStateInteractions(ctx context.Context, blockID flow.Identifier, interactions []*delta.Snapshot) error {
+	err := operation.RetryOnConflict(s.db.Update, operation.InsertExecutionStateInteractions(blockID, interactions))
+	if err != nil {
+		return fmt.Errorf("cannot persist state interactions: %w", err)
+	}
+	return nil
+}
+
+func (s *state) PersistStateDelta(ctx context.Context, stateDelta *messages.ExecutionStateDelta, result *flow.ExecutionResult) error {
+
+	header := stateDelta.Block.Header
+	blockID := header.ID()
+
+	// the events are served to clients as the events of the block, so they must be the sealed ones
+	err := CheckStateDeltaEvents(stateDelta, result)
+	if err != nil {
+		return fmt.Errorf("invalid events of state delta: %w", err)
+	}
+
+	// the collections are stored as for executed blocks, so that the state delta can be served again
+	for _, completeCollection := range stateDelta.CompleteCollections {
+		collection := completeCollection.Collection()
+		err := s.collections.Store(&collection)
+		if err != nil {
+			return fmt.Errorf("cannot store collection: %w", err)
+		}
+	}
+
+	err = s.PersistStateInteractions(ctx, blockID, stateDelta.StateInteractions)
+	if err != nil {
+		return err
+	}
+
+	batch := badgerstorage.NewBatch(s.db)
+
+	err = s.commits.BatchStore(blockID, stateDelta.EndState, batch)
+	if err != nil {
+		return fmt.Errorf("cannot store state commitment: %w", err)
+	}
+
+	err = s.events.BatchStore(blockID, stateDelta.Events, batch)
+	if err != nil {
+		return fmt.Errorf("cannot store events: %w", err)
+	}
+	err = s.serviceEvents.BatchStore(blockID, stateDelta.ServiceEvents, batch)
+	if err != nil {
+		return fmt.Errorf("cannot store service events: %w", err)
+	}
+
+	err = s.transactionResults.BatchStore(blockID, stateDelta.TransactionResults, batch)
+	if err != nil {
+		return fmt.Errorf("cannot store transaction result: %w", err)
+	}
+
+	// the sealed result takes the place of the result the node would have computed, there is no
+	// receipt of the node for the block
+	err = s.results.BatchStore(result, batch)
+	if err != nil {
+		return fmt.Errorf("cannot store execution result: %w", err)
+	}
+	err = s.results.BatchIndex(blockID, result.ID(), batch)
+	if err != nil {
+		return fmt.Errorf("cannot index execution result: %w", err)
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("batch flush error: %w", err)
+	}
+
+	err = s.UpdateHighestExecutedBlockIfHigher(ctx, header)
+	if err != nil {
+		return fmt.Errorf("cannot update highest executed block: %w", err)
+	}
+	return nil
+}
+
 func (s *state) RetrieveStateDelta(ctx context.Context, blockID flow.Identifier) (*messages.ExecutionStateDelta, error) {
 	block, err := s.blocks.ByID(blockID)
 	if err != nil {
@@ -447,7 +567,7 @@ func (s *state) RetrieveStateDelta(ctx context.Context, blockID flow.Identifier)
 			return fmt.Errorf("cannot lookup events: %w", err)
 		}
 
-		err = operation.LookupTransactionResultsByBlockID(blockID, &txResults)(txn)
+		err = operation.LookupTransactionResultsByBlockIDInExecutionOrder(blockID, &txResults)(txn)
 		if err != nil {
 			return fmt.Errorf("cannot lookup transaction errors: %w", err)
 		}
@@ -554,3 +674,56 @@ func IsBlockExecuted(ctx context.Context, state ReadOnlyExecutionState, block fl
 
 	return false, err
 }
+
+// CheckStateDeltaEvents checks the events of the state delta against the sealed result of its block.
+// The events emitted by the transactions of each chunk must hash to the event collection of the
+// chunk, and the service events must match the service events of the result.
+func CheckStateDeltaEvents(stateDelta *messages.ExecutionStateDelta, result *flow.ExecutionResult) error {
+
+	// the events are stored by transaction ID, so they are put back into execution order first,
+	// where the transactions of the chunks follow each other
+	events := executionOrder(stateDelta.Events)
+	var txEnd uint64
+	for i, chunk := range result.Chunks {
+		txEnd += chunk.NumberOfTransactions
+		end := 0
+		for end < len(events) && uint64(events[end].TransactionIndex) < txEnd {
+			end++
+		}
+		if flow.EventsList(events[:end]).Hash() != chunk.EventCollection {
+			return fmt.Errorf("events of chunk %d do not match event collection of sealed result", i)
+		}
+		events = events[end:]
+	}
+	if len(events) > 0 {
+		return fmt.Errorf("%d events not emitted by the transactions of any chunk", len(events))
+	}
+
+	if len(stateDelta.ServiceEvents) != len(result.ServiceEvents) {
+		return fmt.Errorf("%d service events for %d service events of sealed result", len(stateDelta.ServiceEvents), len(result.ServiceEvents))
+	}
+	for i, event := range executionOrder(stateDelta.ServiceEvents) {
+		converted, err := flow.ConvertServiceEvent(event)
+		if err != nil {
+			return fmt.Errorf("could not convert service event %d (%s): %w", i, event.Type, err)
+		}
+		if flow.MakeID(*converted) != flow.MakeID(result.ServiceEvents[i]) {
+			return fmt.Errorf("service event %d (%s) does not match sealed result", i, event.Type)
+		}
+	}
+
+	return nil
+}
+
+// executionOrder returns a copy of the events of a block, sorted in the order they were emitted.
+func executionOrder(events []flow.Event) []flow.Event {
+	sorted := make([]flow.Event, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].TransactionIndex != sorted[j].TransactionIndex {
+			return sorted[i].TransactionIndex < sorted[j].TransactionIndex
+		}
+		return sorted[i].EventIndex < sorted[j].EventIndex
+	})
+	return sorted
+}
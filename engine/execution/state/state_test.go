@@ -50,7 +50,7 @@ func prepareTest(f func(t *testing.T, es state.ExecutionState, l *ledger.Ledger)
 			myReceipts := new(storage.MyExecutionReceipts)
 
 			es := state.NewExecutionState(
-				ls, stateCommitments, blocks, headers, collections, chunkDataPacks, results, receipts, myReceipts, events, serviceEvents, txResults, badgerDB, trace.NewNoopTracer(),
+				ls, stateCommitments, blocks, headers, collections, chunkDataPacks, nil, results, receipts, myReceipts, events, serviceEvents, txResults, badgerDB, trace.NewNoopTracer(),
 			)
 
 			f(t, es, ls)
@@ -189,3 +189,50 @@ func TestExecutionStateWithTrieStorage(t *testing.T) {
 	}))
 
 }
+
+func TestDiff(t *testing.T) {
+	owner1 := string(flow.HexToAddress("01").Bytes())
+	owner2 := string(flow.HexToAddress("02").Bytes())
+
+	prepareTest(func(t *testing.T, es state.ExecutionState, l *ledger.Ledger) {
+		sc1, err := es.StateCommitmentByBlockID(context.Background(), flow.Identifier{})
+		require.NoError(t, err)
+
+		view1 := es.NewView(sc1)
+		require.NoError(t, view1.Set(owner1, "", "balance", flow.RegisterValue("1")))
+		require.NoError(t, view1.Set(owner1, "", "storage_used", flow.RegisterValue("100")))
+		require.NoError(t, view1.Set(owner2, "", "balance", flow.RegisterValue("2")))
+		sc2, err := state.CommitDelta(l, view1.Delta(), sc1)
+		require.NoError(t, err)
+
+		// updates a register of each owner, rewrites a register with its value and adds a register
+		view2 := es.NewView(sc2)
+		require.NoError(t, view2.Set(owner1, "", "balance", flow.RegisterValue("3")))
+		require.NoError(t, view2.Set(owner1, "", "storage_used", flow.RegisterValue("100")))
+		require.NoError(t, view2.Set(owner2, "", "balance", flow.RegisterValue("4")))
+		require.NoError(t, view2.Set(owner2, "", "contract_names", flow.RegisterValue("Test")))
+		sc3, err := state.CommitDelta(l, view2.Delta(), sc2)
+		require.NoError(t, err)
+
+		diffs, err := state.Diff(l.Forest(), sc2, sc3)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []state.RegisterDiff{
+			{ID: flow.NewRegisterID(owner1, "", "balance"), Before: flow.RegisterValue("1"), After: flow.RegisterValue("3")},
+			{ID: flow.NewRegisterID(owner2, "", "balance"), Before: flow.RegisterValue("2"), After: flow.RegisterValue("4")},
+			{ID: flow.NewRegisterID(owner2, "", "contract_names"), After: flow.RegisterValue("Test")},
+		}, diffs)
+
+		diffs, err = state.Diff(l.Forest(), sc2, sc3, flow.HexToAddress("01"))
+		require.NoError(t, err)
+		assert.Equal(t, []state.RegisterDiff{
+			{ID: flow.NewRegisterID(owner1, "", "balance"), Before: flow.RegisterValue("1"), After: flow.RegisterValue("3")},
+		}, diffs)
+
+		diffs, err = state.Diff(l.Forest(), sc3, sc3)
+		require.NoError(t, err)
+		assert.Empty(t, diffs)
+
+		_, err = state.Diff(l.Forest(), sc3, flow.DummyStateCommitment)
+		assert.Error(t, err)
+	})(t)
+}
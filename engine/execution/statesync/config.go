@@ -0,0 +1,44 @@
+package statesync
+
+import (
+	"time"
+)
+
+type Config struct {
+	threshold     uint64
+	maxRange      uint64
+	checkInterval time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		threshold:     100,
+		maxRange:      100,
+		checkInterval: 10 * time.Second,
+	}
+}
+
+type OptionFunc func(*Config)
+
+// WithThreshold sets the number of sealed and unexecuted blocks beyond which the state deltas of
+// the blocks are requested from other execution nodes.
+func WithThreshold(threshold uint64) OptionFunc {
+	return func(cfg *Config) {
+		cfg.threshold = threshold
+	}
+}
+
+// WithMaxRange sets the maximum number of blocks whose state deltas are requested by a single state
+// sync request, or served for a single request of another execution node.
+func WithMaxRange(maxRange uint64) OptionFunc {
+	return func(cfg *Config) {
+		cfg.maxRange = maxRange
+	}
+}
+
+// WithCheckInterval sets the interval at which we check whether state deltas should be requested.
+func WithCheckInterval(interval time.Duration) OptionFunc {
+	return func(cfg *Config) {
+		cfg.checkInterval = interval
+	}
+}
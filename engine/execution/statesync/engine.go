@@ -0,0 +1,524 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// Engine syncs the execution state of a lagging execution node from the state deltas of other
+// execution nodes, instead of executing every sealed block it lags behind. The state deltas are
+// validated against the sealed results of their blocks, and their register updates are applied to
+// the ledger. The engine serves the state deltas of the blocks executed by the node to other
+// execution nodes as well, and to access nodes, which index the execution state from them.
+type Engine struct {
+	unit       *engine.Unit
+	log        zerolog.Logger
+	me         module.Local
+	state      protocol.State
+	seals      storage.Seals
+	results    storage.ExecutionResults
+	execState  state.ExecutionState
+	ledger     ledger.Ledger
+	deltas     mempool.Deltas      // state deltas validated against sealed results, waiting for their parents to be executed
+	syncFilter flow.IdentityFilter // filter of the execution nodes to request state deltas from
+	onApplied  func(blockID flow.Identifier, endState flow.StateCommitment)
+	con        network.Conduit
+	config     *Config
+	applyLock  sync.Mutex // state deltas are applied one at a time, in the order of their blocks
+}
+
+// New creates a state sync engine. The onApplied callback is called once the state delta of a
+// block was applied, so that the block is handled as an executed block.
+func New(
+	log zerolog.Logger,
+	net module.Network,
+	me module.Local,
+	state protocol.State,
+	seals storage.Seals,
+	results storage.ExecutionResults,
+	execState state.ExecutionState,
+	ldg ledger.Ledger,
+	deltas mempool.Deltas,
+	syncFilter flow.IdentityFilter,
+	onApplied func(blockID flow.Identifier, endState flow.StateCommitment),
+	opts ...OptionFunc,
+) (*Engine, error) {
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if onApplied == nil {
+		onApplied = func(flow.Identifier, flow.StateCommitment) {}
+	}
+
+	e := &Engine{
+		unit:       engine.NewUnit(),
+		log:        log.With().Str("engine", "state_sync").Logger(),
+		me:         me,
+		state:      state,
+		seals:      seals,
+		results:    results,
+		execState:  execState,
+		ledger:     ldg,
+		deltas:     deltas,
+		syncFilter: syncFilter,
+		onApplied:  onApplied,
+		config:     config,
+	}
+
+	con, err := net.Register(engine.SyncExecution, e)
+	if err != nil {
+		return nil, fmt.Errorf("could not register state sync engine: %w", err)
+	}
+	e.con = con
+
+	return e, nil
+}
+
+// Ready returns a channel that will close when the engine has
+// successfully started.
+func (e *Engine) Ready() <-chan struct{} {
+	e.unit.LaunchPeriodically(e.checkSyncStart, e.config.checkInterval, 0)
+	return e.unit.Ready()
+}
+
+// Done returns a channel that will close when the engine has
+// successfully stopped.
+func (e *Engine) Done() <-chan struct{} {
+	return e.unit.Done()
+}
+
+// SubmitLocal submits an event originating on the local node.
+func (e *Engine) SubmitLocal(event interface{}) {
+	e.Submit(e.me.NodeID(), event)
+}
+
+// Submit submits the given event from the node with the given origin ID
+// for processing in a non-blocking manner. It returns instantly and logs
+// a potential processing error internally when done.
+func (e *Engine) Submit(originID flow.Identifier, event interface{}) {
+	e.unit.Launch(func() {
+		err := e.Process(originID, event)
+		if err != nil {
+			engine.LogError(e.log, err)
+		}
+	})
+}
+
+// ProcessLocal processes an event originating on the local node.
+func (e *Engine) ProcessLocal(event interface{}) error {
+	return e.Process(e.me.NodeID(), event)
+}
+
+// Process processes the given event from the node with the given origin ID in
+// a blocking manner. It returns the potential processing error when done.
+func (e *Engine) Process(originID flow.Identifier, event interface{}) error {
+	return e.unit.Do(func() error {
+		return e.process(originID, event)
+	})
+}
+
+func (e *Engine) process(originID flow.Identifier, event interface{}) error {
+	switch v := event.(type) {
+	case *messages.ExecutionStateSyncRequest:
+		err := e.onSyncRequest(originID, v)
+		if err != nil {
+			return fmt.Errorf("could not answer state sync request: %w", err)
+		}
+		return nil
+	case *messages.ExecutionStateDelta:
+		err := e.onStateDelta(originID, v)
+		if err != nil {
+			return fmt.Errorf("could not handle state delta: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid event type (%T)", event)
+	}
+}
+
+// ensureStakedNode checks that the origin is a staked node of one of the given roles.
+func (e *Engine) ensureStakedNode(originID flow.Identifier, roles ...flow.Role) error {
+	identity, err := e.state.Final().Identity(originID)
+	if protocol.IsIdentityNotFound(err) {
+		return engine.NewInvalidInputErrorf("unknown origin %x", originID)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get identity of origin %x: %w", originID, err)
+	}
+	if !flow.RoleList(roles).Contains(identity.Role) || identity.Stake == 0 {
+		return engine.NewInvalidInputErrorf("origin %x is not a staked node of roles %s (role: %s)", originID, roles, identity.Role)
+	}
+	return nil
+}
+
+// onSyncRequest sends the state deltas of the requested sealed blocks to the requester, up to the
+// first block whose state delta is missing. Access nodes request state deltas to index the
+// execution state.
+func (e *Engine) onSyncRequest(originID flow.Identifier, req *messages.ExecutionStateSyncRequest) error {
+	err := e.ensureStakedNode(originID, flow.RoleExecution, flow.RoleAccess)
+	if err != nil {
+		return err
+	}
+	if req.FromHeight > req.ToHeight {
+		return engine.NewInvalidInputErrorf("invalid height range [%d, %d]", req.FromHeight, req.ToHeight)
+	}
+
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not get sealed block: %w", err)
+	}
+	toHeight := req.ToHeight
+	if toHeight > sealed.Height {
+		toHeight = sealed.Height
+	}
+	if toHeight-req.FromHeight >= e.config.maxRange {
+		toHeight = req.FromHeight + e.config.maxRange - 1
+	}
+
+	log := e.log.With().
+		Hex("origin_id", logging.ID(originID)).
+		Uint64("from_height", req.FromHeight).
+		Uint64("to_height", toHeight).
+		Logger()
+
+	sent := 0
+	for height := req.FromHeight; height <= toHeight; height++ {
+		header, err := e.state.AtHeight(height).Head()
+		if err != nil {
+			return fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+
+		stateDelta, err := e.execState.RetrieveStateDelta(e.unit.Ctx(), header.ID())
+		// the block is not executed yet, or its state interactions were not persisted
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Debug().Uint64("height", height).Msg("state delta not found")
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve state delta at height %d: %w", height, err)
+		}
+
+		err = e.con.Unicast(stateDelta, originID)
+		if err != nil {
+			return fmt.Errorf("could not send state delta at height %d: %w", height, err)
+		}
+		sent++
+	}
+
+	log.Info().Int("sent", sent).Msg("state sync request answered")
+
+	return nil
+}
+
+// onStateDelta validates the state delta of a sealed block against the sealed result of the block,
+// and applies it once the parent of the block is executed.
+func (e *Engine) onStateDelta(originID flow.Identifier, stateDelta *messages.ExecutionStateDelta) error {
+	err := e.ensureStakedNode(originID, flow.RoleExecution)
+	if err != nil {
+		return err
+	}
+
+	header := stateDelta.Block.Header
+	blockID := stateDelta.ID()
+
+	log := e.log.With().
+		Hex("origin_id", logging.ID(originID)).
+		Hex("block_id", blockID[:]).
+		Uint64("block_height", header.Height).
+		Logger()
+
+	if e.deltas.Has(blockID) {
+		return nil
+	}
+
+	executed, err := state.IsBlockExecuted(e.unit.Ctx(), e.execState, blockID)
+	if err != nil {
+		return fmt.Errorf("could not check whether block is executed: %w", err)
+	}
+	if executed {
+		log.Debug().Msg("block already executed, dropping state delta")
+		return nil
+	}
+
+	// state deltas are only accepted for sealed blocks, whose results are final
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not get sealed block: %w", err)
+	}
+	if header.Height > sealed.Height {
+		return engine.NewInvalidInputErrorf("state delta of unsealed block %x at height %d", blockID, header.Height)
+	}
+	finalized, err := e.state.AtHeight(header.Height).Head()
+	if err != nil {
+		return fmt.Errorf("could not get finalized block at height %d: %w", header.Height, err)
+	}
+	if finalized.ID() != blockID {
+		return engine.NewInvalidInputErrorf("state delta of block %x not finalized at height %d", blockID, header.Height)
+	}
+
+	result, err := e.sealedResult(header)
+	if err != nil {
+		return fmt.Errorf("could not get sealed result of block %x: %w", blockID, err)
+	}
+
+	err = ValidateStateDelta(stateDelta, result)
+	if err != nil {
+		return engine.NewInvalidInputErrorf("invalid state delta of block %x: %v", blockID, err)
+	}
+
+	e.deltas.Add(stateDelta)
+
+	log.Debug().Msg("state delta validated")
+
+	return e.applyStateDeltas(blockID)
+}
+
+// sealedResult returns the sealed result of the given sealed and finalized block, from the seal of
+// the block in the finalized blocks.
+func (e *Engine) sealedResult(header *flow.Header) (*flow.ExecutionResult, error) {
+	seal, err := e.seals.FinalizedSealForBlock(header.ID())
+	if err != nil {
+		return nil, fmt.Errorf("could not get seal of block: %w", err)
+	}
+	result, err := e.results.ByID(seal.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed result %x: %w", seal.ResultID, err)
+	}
+	return result, nil
+}
+
+// ValidateStateDelta checks that the state delta is consistent with the sealed result of its block.
+// The register updates of the chunks are checked against the result when they are applied.
+// The transaction results of the delta are not checked, as the result doesn't commit to them.
+func ValidateStateDelta(stateDelta *messages.ExecutionStateDelta, result *flow.ExecutionResult) error {
+	block := stateDelta.Block
+	if result.BlockID != block.ID() {
+		return fmt.Errorf("result for block %x", result.BlockID)
+	}
+	if block.Payload.Hash() != block.Header.PayloadHash {
+		return fmt.Errorf("payload does not match payload hash of header")
+	}
+
+	if len(stateDelta.CompleteCollections) != len(block.Payload.Guarantees) {
+		return fmt.Errorf("%d collections for %d guarantees", len(stateDelta.CompleteCollections), len(block.Payload.Guarantees))
+	}
+	for _, guarantee := range block.Payload.Guarantees {
+		completeCollection, ok := stateDelta.CompleteCollections[guarantee.ID()]
+		if !ok {
+			return fmt.Errorf("missing collection %x", guarantee.CollectionID)
+		}
+		if completeCollection.Collection().ID() != guarantee.CollectionID {
+			return fmt.Errorf("collection does not match guarantee of collection %x", guarantee.CollectionID)
+		}
+	}
+
+	if len(result.Chunks) == 0 {
+		return fmt.Errorf("sealed result without chunks")
+	}
+	if len(stateDelta.StateInteractions) != len(result.Chunks) {
+		return fmt.Errorf("%d state interactions for %d chunks", len(stateDelta.StateInteractions), len(result.Chunks))
+	}
+	if stateDelta.StartState == nil || *stateDelta.StartState != result.Chunks[0].StartState {
+		return fmt.Errorf("start state does not match sealed result")
+	}
+	endState, err := result.FinalStateCommitment()
+	if err != nil {
+		return fmt.Errorf("could not get final state of sealed result: %w", err)
+	}
+	if stateDelta.EndState != endState {
+		return fmt.Errorf("end state does not match sealed result")
+	}
+
+	err = state.CheckStateDeltaEvents(stateDelta, result)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// applyStateDeltas applies the pending state delta of the given block once its parent is executed,
+// followed by the pending state deltas of its descendants.
+func (e *Engine) applyStateDeltas(blockID flow.Identifier) error {
+	e.applyLock.Lock()
+	defer e.applyLock.Unlock()
+
+	for {
+		stateDelta, ok := e.deltas.ByBlockID(blockID)
+		if !ok {
+			return nil
+		}
+
+		header := stateDelta.Block.Header
+		startState, err := e.execState.StateCommitmentByBlockID(e.unit.Ctx(), header.ParentID)
+		// the state delta is applied once the parent is executed, or its state delta applied
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not get state commitment of parent: %w", err)
+		}
+
+		err = e.applyStateDelta(stateDelta, startState)
+		if err != nil {
+			return fmt.Errorf("could not apply state delta of block %x: %w", blockID, err)
+		}
+
+		// the blocks of state deltas are finalized, so the child is the finalized block above
+		child, err := e.state.AtHeight(header.Height + 1).Head()
+		if err != nil {
+			return fmt.Errorf("could not get finalized block at height %d: %w", header.Height+1, err)
+		}
+		blockID = child.ID()
+	}
+}
+
+// applyStateDelta applies the register updates of the state delta to the ledger, starting at the
+// end state of the parent, and persists the execution state of the block.
+func (e *Engine) applyStateDelta(stateDelta *messages.ExecutionStateDelta, startState flow.StateCommitment) error {
+	header := stateDelta.Block.Header
+	blockID := stateDelta.ID()
+	defer e.deltas.Rem(blockID)
+
+	log := e.log.With().
+		Hex("block_id", blockID[:]).
+		Uint64("block_height", header.Height).
+		Logger()
+
+	// the block might have been executed since the state delta was received
+	executed, err := state.IsBlockExecuted(e.unit.Ctx(), e.execState, blockID)
+	if err != nil {
+		return fmt.Errorf("could not check whether block is executed: %w", err)
+	}
+	if executed {
+		return nil
+	}
+
+	result, err := e.sealedResult(header)
+	if err != nil {
+		return fmt.Errorf("could not get sealed result: %w", err)
+	}
+
+	// the start state of the delta is the start state of the sealed result, so the parent state of
+	// the node differs from the sealed state of the parent
+	if *stateDelta.StartState != startState {
+		log.Error().
+			Hex("parent_state", startState[:]).
+			Hex("sealed_parent_state", stateDelta.StartState[:]).
+			Msg("state of parent does not match sealed result, dropping state delta")
+		return nil
+	}
+
+	commit := startState
+	for i, snapshot := range stateDelta.StateInteractions {
+		commit, err = state.CommitDelta(e.ledger, snapshot.Delta, commit)
+		if err != nil {
+			return fmt.Errorf("could not commit register updates of chunk %d: %w", i, err)
+		}
+		if commit != result.Chunks[i].EndState {
+			return engine.NewInvalidInputErrorf("register updates of chunk %d do not match end state of sealed result", i)
+		}
+	}
+
+	err = e.execState.PersistStateDelta(e.unit.Ctx(), stateDelta, result)
+	if err != nil {
+		return fmt.Errorf("could not persist state delta: %w", err)
+	}
+
+	log.Info().Hex("final_state", commit[:]).Msg("state delta applied")
+
+	e.onApplied(blockID, commit)
+
+	return nil
+}
+
+// checkSyncStart requests the state deltas of the sealed and unexecuted blocks from another
+// execution node, when the node lags behind the sealed blocks by more than the threshold.
+func (e *Engine) checkSyncStart() {
+	executedHeight, _, err := e.execState.GetHighestExecutedBlockID(e.unit.Ctx())
+	if err != nil {
+		e.log.Err(err).Msg("could not get highest executed block")
+		return
+	}
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		e.log.Err(err).Msg("could not get sealed block")
+		return
+	}
+	if sealed.Height <= executedHeight {
+		return
+	}
+
+	// the pending state delta of the next block is applied, in case its parent was executed
+	// after the state delta was received
+	next, err := e.state.AtHeight(executedHeight + 1).Head()
+	if err != nil {
+		e.log.Err(err).Msg("could not get next unexecuted block")
+		return
+	}
+	err = e.applyStateDeltas(next.ID())
+	if err != nil {
+		e.log.Err(err).Msg("could not apply pending state deltas")
+	}
+
+	if sealed.Height-executedHeight <= e.config.threshold {
+		return
+	}
+
+	fromHeight := executedHeight + 1
+	toHeight := sealed.Height
+	if toHeight-fromHeight >= e.config.maxRange {
+		toHeight = fromHeight + e.config.maxRange - 1
+	}
+
+	peers, err := e.state.Final().Identities(filter.And(
+		filter.HasRole(flow.RoleExecution),
+		filter.HasStake(true),
+		filter.Not(filter.HasNodeID(e.me.NodeID())),
+		e.syncFilter,
+	))
+	if err != nil {
+		e.log.Err(err).Msg("could not get execution nodes")
+		return
+	}
+	if len(peers) == 0 {
+		e.log.Warn().Msg("no execution node to request state deltas from")
+		return
+	}
+	target := peers.Sample(1)[0]
+
+	req := &messages.ExecutionStateSyncRequest{
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	}
+	err = e.con.Unicast(req, target.NodeID)
+	if err != nil {
+		e.log.Err(err).Msg("could not send state sync request")
+		return
+	}
+
+	e.log.Info().
+		Hex("target_id", logging.ID(target.NodeID)).
+		Uint64("from_height", fromHeight).
+		Uint64("to_height", toHeight).
+		Msg("requested state deltas")
+}
@@ -0,0 +1,273 @@
+package statesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/execution/ingestion"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	state "github.com/onflow/flow-go/engine/execution/state/mock"
+	"github.com/onflow/flow-go/ledger"
+	mockledger "github.com/onflow/flow-go/ledger/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	mockprotocol "github.com/onflow/flow-go/state/protocol/mock"
+	storageerr "github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// syncFixture is a lagging execution node, which executed the parent of a sealed block, and receives
+// the state delta of the sealed block from an execution node.
+type syncFixture struct {
+	engine     *Engine
+	execState  *state.ExecutionState
+	ledger     *mockledger.Ledger
+	con        *mocknetwork.Conduit
+	deltas     *ingestion.Deltas
+	origin     *flow.Identity
+	parent     *flow.Block
+	block      *flow.Block
+	result     *flow.ExecutionResult
+	stateDelta *messages.ExecutionStateDelta
+	applied    []flow.Identifier
+}
+
+func newSyncFixture(t *testing.T, originRole flow.Role) *syncFixture {
+	parent := unittest.BlockFixture()
+	collection := unittest.CompleteCollectionFixture()
+	block := unittest.BlockWithParentFixture(parent.Header)
+	block.SetPayload(unittest.PayloadFixture(unittest.WithGuarantees(collection.Guarantee)))
+	result := unittest.ExecutionResultFixture(unittest.WithBlock(&block))
+	result.Chunks[1].StartState = result.Chunks[0].EndState
+	// the events of the chunks are out of execution order, as they are stored by transaction ID
+	events := []flow.Event{
+		unittest.EventFixture(flow.EventAccountCreated, 50, 0, unittest.IdentifierFixture()),
+		unittest.EventFixture(flow.EventAccountCreated, 3, 0, unittest.IdentifierFixture()),
+		unittest.EventFixture(flow.EventAccountCreated, 0, 1, unittest.IdentifierFixture()),
+		unittest.EventFixture(flow.EventAccountCreated, 0, 0, unittest.IdentifierFixture()),
+	}
+	result.Chunks[0].EventCollection = flow.EventsList{events[3], events[2], events[1]}.Hash()
+	result.Chunks[1].EventCollection = flow.EventsList{events[0]}.Hash()
+	sealing := unittest.BlockWithParentFixture(block.Header)
+	sealing.SetPayload(flow.Payload{Seals: []*flow.Seal{{BlockID: block.ID(), ResultID: result.ID()}}})
+
+	startState := result.Chunks[0].StartState
+	stateDelta := &messages.ExecutionStateDelta{
+		ExecutableBlock: entity.ExecutableBlock{
+			Block:               &block,
+			StartState:          &startState,
+			CompleteCollections: map[flow.Identifier]*entity.CompleteCollection{collection.Guarantee.ID(): collection},
+		},
+		StateInteractions: []*delta.Snapshot{unittest.StateInteractionsFixture(), unittest.StateInteractionsFixture()},
+		EndState:          result.Chunks[1].EndState,
+		Events:            events,
+	}
+
+	origin := unittest.IdentityFixture(unittest.WithRole(originRole))
+	me := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+
+	ps := new(mockprotocol.State)
+	final := new(mockprotocol.Snapshot)
+	final.On("Identity", origin.NodeID).Return(origin, nil)
+	ps.On("Final").Return(final)
+	sealed := new(mockprotocol.Snapshot)
+	sealed.On("Head").Return(block.Header, nil)
+	ps.On("Sealed").Return(sealed)
+	for _, b := range []*flow.Block{&parent, &block, &sealing} {
+		snapshot := new(mockprotocol.Snapshot)
+		snapshot.On("Head").Return(b.Header, nil)
+		ps.On("AtHeight", b.Header.Height).Return(snapshot)
+	}
+
+	seals := new(storagemock.Seals)
+	seals.On("FinalizedSealForBlock", block.ID()).Return(sealing.Payload.Seals[0], nil)
+	results := new(storagemock.ExecutionResults)
+	results.On("ByID", result.ID()).Return(result, nil)
+
+	execState := new(state.ExecutionState)
+	execState.On("StateCommitmentByBlockID", mock.Anything, parent.ID()).Return(startState, nil)
+	execState.On("StateCommitmentByBlockID", mock.Anything, block.ID()).Return(nil, storageerr.ErrNotFound)
+
+	deltas, err := ingestion.NewDeltas(10)
+	require.NoError(t, err)
+
+	local := new(mockmodule.Local)
+	local.On("NodeID").Return(me.NodeID)
+
+	f := &syncFixture{
+		execState:  execState,
+		ledger:     new(mockledger.Ledger),
+		con:        new(mocknetwork.Conduit),
+		deltas:     deltas,
+		origin:     origin,
+		parent:     &parent,
+		block:      &block,
+		result:     result,
+		stateDelta: stateDelta,
+	}
+	f.engine = &Engine{
+		unit:       engine.NewUnit(),
+		me:         local,
+		state:      ps,
+		seals:      seals,
+		results:    results,
+		execState:  execState,
+		ledger:     f.ledger,
+		deltas:     deltas,
+		syncFilter: func(*flow.Identity) bool { return true },
+		onApplied: func(blockID flow.Identifier, _ flow.StateCommitment) {
+			f.applied = append(f.applied, blockID)
+		},
+		con:    f.con,
+		config: DefaultConfig(),
+	}
+	return f
+}
+
+func TestEngine_onSyncRequest(t *testing.T) {
+
+	t.Run("serves state deltas of sealed blocks", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleExecution)
+		parentDelta := &messages.ExecutionStateDelta{ExecutableBlock: entity.ExecutableBlock{Block: f.parent}}
+		f.execState.On("RetrieveStateDelta", mock.Anything, f.parent.ID()).Return(parentDelta, nil)
+		f.execState.On("RetrieveStateDelta", mock.Anything, f.block.ID()).Return(f.stateDelta, nil)
+		f.con.On("Unicast", parentDelta, f.origin.NodeID).Return(nil).Once()
+		f.con.On("Unicast", f.stateDelta, f.origin.NodeID).Return(nil).Once()
+
+		// the requested range is clipped to the sealed height
+		req := &messages.ExecutionStateSyncRequest{FromHeight: f.parent.Header.Height, ToHeight: f.block.Header.Height + 10}
+		err := f.engine.onSyncRequest(f.origin.NodeID, req)
+		require.NoError(t, err)
+
+		f.con.AssertExpectations(t)
+	})
+
+	t.Run("serves state deltas to access nodes", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleAccess)
+		f.execState.On("RetrieveStateDelta", mock.Anything, f.block.ID()).Return(f.stateDelta, nil)
+		f.con.On("Unicast", f.stateDelta, f.origin.NodeID).Return(nil).Once()
+
+		req := &messages.ExecutionStateSyncRequest{FromHeight: f.block.Header.Height, ToHeight: f.block.Header.Height}
+		err := f.engine.onSyncRequest(f.origin.NodeID, req)
+		require.NoError(t, err)
+
+		f.con.AssertExpectations(t)
+	})
+
+	t.Run("stops at missing state delta", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleExecution)
+		f.execState.On("RetrieveStateDelta", mock.Anything, f.parent.ID()).Return(nil, storageerr.ErrNotFound)
+
+		req := &messages.ExecutionStateSyncRequest{FromHeight: f.parent.Header.Height, ToHeight: f.block.Header.Height}
+		err := f.engine.onSyncRequest(f.origin.NodeID, req)
+		require.NoError(t, err)
+
+		f.con.AssertNotCalled(t, "Unicast", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects requests of other nodes", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleConsensus)
+
+		req := &messages.ExecutionStateSyncRequest{FromHeight: f.parent.Header.Height, ToHeight: f.block.Header.Height}
+		err := f.engine.onSyncRequest(f.origin.NodeID, req)
+		require.True(t, engine.IsInvalidInputError(err))
+
+		f.execState.AssertNotCalled(t, "RetrieveStateDelta", mock.Anything, mock.Anything)
+	})
+}
+
+func TestEngine_onStateDelta(t *testing.T) {
+
+	t.Run("applies valid state delta", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleExecution)
+		f.ledger.On("Set", mock.Anything).Return(ledger.State(f.result.Chunks[0].EndState), nil).Once()
+		f.ledger.On("Set", mock.Anything).Return(ledger.State(f.result.Chunks[1].EndState), nil).Once()
+		f.execState.On("PersistStateDelta", mock.Anything, f.stateDelta, f.result).Return(nil).Once()
+
+		err := f.engine.onStateDelta(f.origin.NodeID, f.stateDelta)
+		require.NoError(t, err)
+
+		f.ledger.AssertExpectations(t)
+		f.execState.AssertExpectations(t)
+		assert.Equal(t, []flow.Identifier{f.block.ID()}, f.applied)
+		assert.Equal(t, uint(0), f.deltas.Size())
+	})
+
+	t.Run("waits for parent to be executed", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleExecution)
+		f.execState = new(state.ExecutionState)
+		f.execState.On("StateCommitmentByBlockID", mock.Anything, mock.Anything).Return(nil, storageerr.ErrNotFound)
+		f.engine.execState = f.execState
+
+		err := f.engine.onStateDelta(f.origin.NodeID, f.stateDelta)
+		require.NoError(t, err)
+
+		assert.True(t, f.deltas.Has(f.block.ID()))
+		assert.Empty(t, f.applied)
+	})
+
+	t.Run("rejects state delta not matching sealed result", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleExecution)
+		f.stateDelta.EndState = unittest.StateCommitmentFixture()
+
+		err := f.engine.onStateDelta(f.origin.NodeID, f.stateDelta)
+		require.True(t, engine.IsInvalidInputError(err))
+
+		assert.Equal(t, uint(0), f.deltas.Size())
+		f.ledger.AssertNotCalled(t, "Set", mock.Anything)
+	})
+
+	t.Run("rejects events not matching sealed result", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleExecution)
+		// the event is moved from the first to the second chunk
+		f.stateDelta.Events[1].TransactionIndex = 43
+
+		err := f.engine.onStateDelta(f.origin.NodeID, f.stateDelta)
+		require.True(t, engine.IsInvalidInputError(err))
+
+		assert.Equal(t, uint(0), f.deltas.Size())
+		f.ledger.AssertNotCalled(t, "Set", mock.Anything)
+	})
+
+	t.Run("rejects service events not matching sealed result", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleExecution)
+		f.result.ServiceEvents = []flow.ServiceEvent{unittest.EpochSetupFixture().ServiceEvent()}
+
+		err := f.engine.onStateDelta(f.origin.NodeID, f.stateDelta)
+		require.True(t, engine.IsInvalidInputError(err))
+
+		assert.Equal(t, uint(0), f.deltas.Size())
+		f.ledger.AssertNotCalled(t, "Set", mock.Anything)
+	})
+
+	t.Run("rejects register updates not matching sealed result", func(t *testing.T) {
+		f := newSyncFixture(t, flow.RoleExecution)
+		f.ledger.On("Set", mock.Anything).Return(ledger.State(unittest.StateCommitmentFixture()), nil).Once()
+
+		err := f.engine.onStateDelta(f.origin.NodeID, f.stateDelta)
+		require.True(t, engine.IsInvalidInputError(err))
+
+		f.execState.AssertNotCalled(t, "PersistStateDelta", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, f.applied)
+		assert.Equal(t, uint(0), f.deltas.Size())
+	})
+
+	t.Run("rejects state delta from other nodes", func(t *testing.T) {
+		for _, role := range []flow.Role{flow.RoleVerification, flow.RoleAccess} {
+			f := newSyncFixture(t, role)
+
+			err := f.engine.onStateDelta(f.origin.NodeID, f.stateDelta)
+			require.True(t, engine.IsInvalidInputError(err))
+
+			assert.Equal(t, uint(0), f.deltas.Size())
+		}
+	})
+}
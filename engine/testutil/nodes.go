@@ -259,8 +259,10 @@ func ConsensusNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	assigner, err := chunks.NewChunkAssigner(chunks.DefaultChunkAssignmentAlpha, node.State)
 	require.Nil(t, err)
 
+	validatedResults, err := validation.NewValidatedResults(node.Metrics, validation.DefaultValidatedResultsCacheSize)
+	require.NoError(t, err)
 	receiptValidator := validation.NewReceiptValidator(node.State, node.Headers, node.Index, resultsDB, node.Seals,
-		signature.NewAggregationVerifier(encoding.ExecutionReceiptTag))
+		signature.NewAggregationVerifier(encoding.ExecutionReceiptTag), validatedResults)
 	approvalValidator := validation.NewApprovalValidator(node.State, signature.NewAggregationVerifier(encoding.ResultApprovalTag))
 
 	sealingEngine, err := sealing.NewEngine(
@@ -285,7 +287,8 @@ func ConsensusNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 		receiptValidator,
 		approvalValidator,
 		validation.DefaultRequiredApprovalsForSealValidation,
-		sealing.DefaultEmergencySealingActive)
+		nil,
+		sealing.WithValidatedResults(validatedResults))
 	require.Nil(t, err)
 
 	return testmock.ConsensusNode{
@@ -322,7 +325,7 @@ type CheckerMock struct {
 	notifications.NoopConsumer // satisfy the FinalizationConsumer interface
 }
 
-func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identities []*flow.Identity, syncThreshold int, chainID flow.ChainID) testmock.ExecutionNode {
+func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identities []*flow.Identity, chainID flow.ChainID) testmock.ExecutionNode {
 	node := GenericNode(t, hub, identity, identities, chainID)
 
 	transactionsStorage := storage.NewTransactions(node.Metrics, node.DB)
@@ -372,7 +375,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	require.NoError(t, err)
 
 	execState := executionState.NewExecutionState(
-		ls, commitsStorage, node.Blocks, node.Headers, collectionsStorage, chunkDataPackStorage, results, receipts, myReceipts, eventsStorage, serviceEventsStorage, txResultStorage, node.DB, node.Tracer,
+		ls, commitsStorage, node.Blocks, node.Headers, collectionsStorage, chunkDataPackStorage, nil, results, receipts, myReceipts, eventsStorage, serviceEventsStorage, txResultStorage, node.DB, node.Tracer,
 	)
 
 	requestEngine, err := requester.New(
@@ -413,6 +416,8 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 		computation.DefaultProgramsCacheSize,
 		committer,
 		computation.DefaultScriptLogThreshold,
+		computation.DefaultScriptCacheSize,
+		computation.DefaultScriptCacheTTL,
 	)
 	require.NoError(t, err)
 
@@ -423,9 +428,6 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	syncCore, err := chainsync.New(node.Log, chainsync.DefaultConfig())
 	require.NoError(t, err)
 
-	deltas, err := ingestion.NewDeltas(1000)
-	require.NoError(t, err)
-
 	checkerEngine := &CheckerMock{}
 
 	rootHead, rootQC := getRoot(t, &node)
@@ -447,10 +449,9 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 		node.Tracer,
 		false,
 		filter.Any,
-		deltas,
-		syncThreshold,
 		false,
 		checkStakedAtBlock,
+		executionState.NewStatePinner(ls),
 	)
 	require.NoError(t, err)
 	requestEngine.WithHandle(ingestionEngine.OnCollection)
@@ -916,6 +917,7 @@ func NewVerificationNode(t testing.TB,
 			node.Headers,
 			node.Results,
 			node.Receipts,
+			storage.NewResultApprovals(node.Metrics, node.DB),
 			node.RequesterEngine,
 		)
 	}
@@ -935,12 +937,14 @@ func NewVerificationNode(t testing.TB,
 			node.Me,
 			node.State,
 			assigner,
+			node.Results,
 			node.ChunksQueue,
 			node.ChunkConsumer)
 	}
 
 	if node.BlockConsumer == nil {
 		node.BlockConsumer, _, err = blockconsumer.NewBlockConsumer(node.Log,
+			collector,
 			node.ProcessedBlockHeight,
 			node.Blocks,
 			node.State,
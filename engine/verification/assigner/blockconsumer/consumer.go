@@ -17,10 +17,18 @@ import (
 // BlockConsumer listens to the OnFinalizedBlock event
 // and notifies the consumer to check in the job queue
 // (i.e., its block reader) for new block jobs.
+//
+// On each finalized block, the consumer reports the height of the last processed block and
+// the number of finalized blocks waiting to be processed to the verification metrics. It also
+// logs the processed height it resumes from when started.
 type BlockConsumer struct {
-	consumer     module.JobConsumer
-	defaultIndex uint64
-	unit         *engine.Unit
+	log             zerolog.Logger
+	metrics         module.VerificationMetrics
+	consumer        module.JobConsumer
+	processedHeight storage.ConsumerProgress
+	state           protocol.State
+	defaultIndex    uint64
+	unit            *engine.Unit
 }
 
 // defaultProcessedIndex returns the last sealed block height from the protocol state.
@@ -38,6 +46,7 @@ func defaultProcessedIndex(state protocol.State) (uint64, error) {
 // NewBlockConsumer creates a new consumer and returns the default processed
 // index for initializing the processed index in storage.
 func NewBlockConsumer(log zerolog.Logger,
+	metrics module.VerificationMetrics,
 	processedHeight storage.ConsumerProgress,
 	blocks storage.Blocks,
 	state protocol.State,
@@ -61,9 +70,13 @@ func NewBlockConsumer(log zerolog.Logger,
 	}
 
 	blockConsumer := &BlockConsumer{
-		consumer:     consumer,
-		defaultIndex: defaultIndex,
-		unit:         engine.NewUnit(),
+		log:             lg,
+		metrics:         metrics,
+		consumer:        consumer,
+		processedHeight: processedHeight,
+		state:           state,
+		defaultIndex:    defaultIndex,
+		unit:            engine.NewUnit(),
 	}
 	worker.withBlockConsumer(blockConsumer)
 
@@ -76,6 +89,30 @@ func (c *BlockConsumer) NotifyJobIsDone(jobID module.JobID) {
 	c.consumer.NotifyJobIsDone(jobID)
 }
 
+// reportProgress reports the height of the last processed block, and the number of finalized blocks
+// which are not processed yet. It is only invoked once per finalized block, rather than once per
+// processed job, as it reads the finalized head from the protocol state.
+func (c *BlockConsumer) reportProgress() {
+	processed, err := c.processedHeight.ProcessedIndex()
+	if err != nil {
+		c.log.Error().Err(err).Msg("could not read processed height")
+		return
+	}
+
+	final, err := c.state.Final().Head()
+	if err != nil {
+		c.log.Error().Err(err).Msg("could not read finalized height")
+		return
+	}
+
+	backlog := uint64(0)
+	if final.Height > processed {
+		backlog = final.Height - processed
+	}
+
+	c.metrics.OnBlockConsumerProgress(processed, backlog)
+}
+
 // OnFinalizedBlock implements FinalizationConsumer, and is invoked by the follower engine whenever
 // a new block is finalized.
 // In this implementation for block consumer, invoking OnFinalizedBlock is enough to only notify the consumer
@@ -83,7 +120,10 @@ func (c *BlockConsumer) NotifyJobIsDone(jobID module.JobID) {
 // The consumer retrieves the new blocks from its block reader module, hence it does not need to use the parameter
 // of OnFinalizedBlock here.
 func (c *BlockConsumer) OnFinalizedBlock(*model.Block) {
-	c.unit.Launch(c.consumer.Check)
+	c.unit.Launch(func() {
+		c.consumer.Check()
+		c.reportProgress()
+	})
 }
 
 // OnBlockIncorporated is to implement FinalizationConsumer
@@ -98,6 +138,16 @@ func (c *BlockConsumer) Ready() <-chan struct{} {
 		panic(fmt.Errorf("could not start block consumer for finder engine: %w", err))
 	}
 
+	processed, err := c.processedHeight.ProcessedIndex()
+	if err != nil {
+		panic(fmt.Errorf("could not read processed height of block consumer: %w", err))
+	}
+	c.log.Info().
+		Uint64("processed_height", processed).
+		Uint64("default_height", c.defaultIndex).
+		Msg("block consumer resumed from processed height")
+	c.reportProgress()
+
 	ready := make(chan struct{})
 	close(ready)
 	return ready
@@ -129,6 +129,7 @@ func withConsumer(
 		}
 
 		consumer, _, err := blockconsumer.NewBlockConsumer(unittest.Logger(),
+			collector,
 			processedHeight,
 			s.Storage.Blocks,
 			s.State,
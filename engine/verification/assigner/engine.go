@@ -30,6 +30,7 @@ type Engine struct {
 	me                    module.Local
 	state                 protocol.State
 	assigner              module.ChunkAssigner      // to determine chunks this node should verify.
+	results               storage.ExecutionResults  // to retrieve the results whose chunk assignment is explained.
 	chunksQueue           storage.ChunksQueue       // to store chunks to be verified.
 	newChunkListener      module.NewJobListener     // to notify chunk queue consumer about a new chunk.
 	blockConsumerNotifier module.ProcessingNotifier // to report a block has been processed.
@@ -42,6 +43,7 @@ func New(
 	me module.Local,
 	state protocol.State,
 	assigner module.ChunkAssigner,
+	results storage.ExecutionResults,
 	chunksQueue storage.ChunksQueue,
 	newChunkListener module.NewJobListener,
 ) *Engine {
@@ -53,6 +55,7 @@ func New(
 		me:               me,
 		state:            state,
 		assigner:         assigner,
+		results:          results,
 		chunksQueue:      chunksQueue,
 		newChunkListener: newChunkListener,
 	}
@@ -255,6 +258,28 @@ func (e *Engine) chunkAssignments(ctx context.Context, result *flow.ExecutionRes
 	return mine, nil
 }
 
+// ExplainAssignment returns the chunk assignment of the execution result with the given ID to all verification nodes,
+// along with the source of randomness and the verifiers it is computed from, so that verification operators can check
+// why their node was (or wasn't) assigned particular chunks. The assignment is computed the same way as for the
+// chunks this node verifies.
+func (e *Engine) ExplainAssignment(resultID flow.Identifier) (*chunks.AssignmentExplanation, error) {
+	result, err := e.results.ByID(resultID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve result: %w", err)
+	}
+
+	// TODO remove shortcut which is only applicable during Sealing Phase 2
+	// Details: see chunkAssignments
+	incorporatingBlock := result.BlockID
+
+	explanation, err := e.assigner.Explain(result, incorporatingBlock)
+	if err != nil {
+		return nil, fmt.Errorf("could not explain chunk assignment: %w", err)
+	}
+
+	return explanation, nil
+}
+
 // stakedAsVerification checks whether this instance of verification node has staked at specified block ID.
 // It returns true and nil if verification node is staked at referenced block ID, and returns false and nil otherwise.
 // It returns false and error if it could not extract the stake of node as a verification node at the specified block.
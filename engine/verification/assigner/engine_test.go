@@ -27,6 +27,7 @@ type AssignerEngineTestSuite struct {
 	metrics          *module.VerificationMetrics
 	tracer           *trace.NoopTracer
 	assigner         *module.ChunkAssigner
+	results          *storage.ExecutionResults
 	chunksQueue      *storage.ChunksQueue
 	newChunkListener *module.NewJobListener
 	notifier         *module.ProcessingNotifier
@@ -71,6 +72,7 @@ func SetupTest(options ...func(suite *AssignerEngineTestSuite)) *AssignerEngineT
 		metrics:          &module.VerificationMetrics{},
 		tracer:           trace.NewNoopTracer(),
 		assigner:         &module.ChunkAssigner{},
+		results:          &storage.ExecutionResults{},
 		chunksQueue:      &storage.ChunksQueue{},
 		newChunkListener: &module.NewJobListener{},
 		verIdentity:      unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification)),
@@ -112,6 +114,7 @@ func NewAssignerEngine(s *AssignerEngineTestSuite) *assigner.Engine {
 		s.me,
 		s.state,
 		s.assigner,
+		s.results,
 		s.chunksQueue,
 		s.newChunkListener)
 
@@ -144,6 +147,9 @@ func TestAssignerEngine(t *testing.T) {
 	t.Run("chunk queue unhappy path duplicate", func(t *testing.T) {
 		chunkQueueUnhappyPathDuplicate(t)
 	})
+	t.Run("explain assignment", func(t *testing.T) {
+		explainAssignment(t)
+	})
 }
 
 // newBlockHappyPath evaluates that passing a new finalized block to assigner engine that contains
@@ -383,3 +389,42 @@ func chunkQueueUnhappyPathDuplicate(t *testing.T) {
 	// job listener should not be notified as no new chunk is added.
 	s.newChunkListener.AssertNotCalled(t, "Check")
 }
+
+// explainAssignment evaluates that the assigner engine explains the chunk assignment of a result
+// with the chunk assigner, using the same source of randomness as for assigning chunks to this node.
+func explainAssignment(t *testing.T) {
+	s := SetupTest()
+	e := NewAssignerEngine(s)
+
+	containerBlock, assignment := createContainerBlock(
+		vertestutils.WithChunks(
+			vertestutils.WithAssignee(s.myID())))
+	result := containerBlock.Payload.Results[0]
+	resultID := result.ID()
+	s.results.On("ByID", resultID).Return(result, nil).Once()
+
+	explanation := &chunks.AssignmentExplanation{
+		ResultID:  resultID,
+		BlockID:   result.BlockID,
+		Seed:      unittest.SeedFixture(32),
+		Alpha:     1,
+		Verifiers: flow.IdentifierList{s.myID()},
+	}
+	for _, chunk := range result.Chunks {
+		explanation.Chunks = append(explanation.Chunks, chunks.ChunkVerifiers{
+			ChunkIndex: chunk.Index,
+			ChunkID:    chunk.ID(),
+			Verifiers:  assignment.Verifiers(chunk),
+		})
+	}
+	s.assigner.On("Explain", result, result.BlockID).Return(explanation, nil).Once()
+
+	explained, err := e.ExplainAssignment(resultID)
+	require.NoError(t, err)
+	require.Equal(t, explanation, explained)
+	require.ElementsMatch(t, assignment.ByNodeID(s.myID()), explained.ChunksOf(s.myID()))
+
+	mock.AssertExpectationsForObjects(t, s.results, s.assigner)
+	// explaining an assignment should not store any chunk
+	s.chunksQueue.AssertNotCalled(t, "StoreChunkLocator", mock.Anything)
+}
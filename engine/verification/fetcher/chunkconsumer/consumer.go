@@ -1,18 +1,19 @@
 package chunkconsumer
 
 import (
-	"fmt"
-
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/engine/verification/fetcher"
-	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/jobqueue"
 	"github.com/onflow/flow-go/storage"
 )
 
 const (
 	DefaultJobIndex = uint64(0)
+
+	// DefaultMaxAttempts is the number of attempts to run a chunk job, before it is
+	// reported as dead letter.
+	DefaultMaxAttempts = uint(3)
 )
 
 // ChunkConsumer consumes the jobs from the job queue, and pass it to the
@@ -20,7 +21,7 @@ const (
 // It wraps the generic job consumer in order to be used as a ReadyDoneAware
 // on startup
 type ChunkConsumer struct {
-	consumer module.JobConsumer
+	*jobqueue.ComponentConsumer
 }
 
 func NewChunkConsumer(
@@ -36,7 +37,8 @@ func NewChunkConsumer(
 	jobs := &ChunkJobs{locators: chunksQueue}
 
 	lg := log.With().Str("module", "chunk_consumer").Logger()
-	consumer := jobqueue.NewConsumer(lg, jobs, processedIndex, worker, maxProcessing)
+	consumer := jobqueue.NewComponentConsumer(lg, jobs, processedIndex, worker, maxProcessing, DefaultJobIndex,
+		jobqueue.WithRetries(DefaultMaxAttempts, jobqueue.NewLogDeadLetters(lg)))
 
 	chunkConsumer := &ChunkConsumer{consumer}
 
@@ -44,30 +46,3 @@ func NewChunkConsumer(
 
 	return chunkConsumer
 }
-
-func (c *ChunkConsumer) NotifyJobIsDone(jobID module.JobID) {
-	c.consumer.NotifyJobIsDone(jobID)
-}
-
-func (c ChunkConsumer) Check() {
-	c.consumer.Check()
-}
-
-func (c *ChunkConsumer) Ready() <-chan struct{} {
-	err := c.consumer.Start(DefaultJobIndex)
-	if err != nil {
-		panic(fmt.Errorf("could not start the chunk consumer for match engine: %w", err))
-	}
-
-	ready := make(chan struct{})
-	close(ready)
-	return ready
-}
-
-func (c *ChunkConsumer) Done() <-chan struct{} {
-	c.consumer.Stop()
-
-	ready := make(chan struct{})
-	close(ready)
-	return ready
-}
@@ -0,0 +1,137 @@
+package fetcher
+
+import (
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+)
+
+// ConflictingChunkDataPacksConsumer consumes the evidence of execution nodes providing different chunk data packs
+// for the same chunk, as detected by the fetcher engine.
+type ConflictingChunkDataPacksConsumer interface {
+	// OnConflictingChunkDataPacks is called by the fetcher engine every time it receives a valid chunk data pack
+	// that differs from the ones received before for the same chunk.
+	// It should not be blocking, as it is called on the handling path of chunk data packs.
+	OnConflictingChunkDataPacks(evidence *verification.ConflictingChunkDataPacks)
+}
+
+// receivedChunk keeps the distinct versions of the chunk data pack received for a chunk.
+type receivedChunk struct {
+	status   *verification.ChunkStatus
+	height   uint64                                                 // height of the block of the chunk
+	first    *verification.ChunkDataPackVersion                     // version pushed to the verifier through the chunk consumer
+	versions map[flow.Identifier]*verification.ChunkDataPackVersion // all versions by checksum of their chunk data pack
+}
+
+// chunkDataPackVersions keeps the versions of the chunk data packs received for the chunks that have been
+// pushed to the verifier, until their blocks are sealed. Execution nodes may keep responding to requests
+// after the first chunk data pack of a chunk is received, and a chunk data pack differing from the first one
+// is evidence of conflicting execution.
+type chunkDataPackVersions struct {
+	sync.Mutex
+	chunks map[flow.Identifier]*receivedChunk
+}
+
+func newChunkDataPackVersions() *chunkDataPackVersions {
+	return &chunkDataPackVersions{
+		chunks: make(map[flow.Identifier]*receivedChunk),
+	}
+}
+
+// add records the chunk data pack of the chunk that is pushed to the verifier as its first version.
+func (v *chunkDataPackVersions) add(status *verification.ChunkStatus,
+	height uint64,
+	originID flow.Identifier,
+	chunkDataPack *flow.ChunkDataPack,
+	collection *flow.Collection) {
+
+	first := &verification.ChunkDataPackVersion{
+		ChunkDataPack: chunkDataPack,
+		Collection:    collection,
+		OriginIDs:     flow.IdentifierList{originID},
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	v.chunks[chunkDataPack.ChunkID] = &receivedChunk{
+		status:   status,
+		height:   height,
+		first:    first,
+		versions: map[flow.Identifier]*verification.ChunkDataPackVersion{chunkDataPack.Checksum(): first},
+	}
+}
+
+// byID returns the status of the chunk with the given ID, if its first chunk data pack has been received.
+func (v *chunkDataPackVersions) byID(chunkID flow.Identifier) (*verification.ChunkStatus, bool) {
+	v.Lock()
+	defer v.Unlock()
+
+	received, ok := v.chunks[chunkID]
+	if !ok {
+		return nil, false
+	}
+	return received.status, true
+}
+
+// addVersion records a further chunk data pack received for a chunk. It returns the evidence of conflicting
+// chunk data packs if the chunk data pack differs from all versions received before for the chunk, and
+// nil otherwise.
+func (v *chunkDataPackVersions) addVersion(originID flow.Identifier,
+	chunkDataPack *flow.ChunkDataPack,
+	collection *flow.Collection) *verification.ConflictingChunkDataPacks {
+
+	v.Lock()
+	defer v.Unlock()
+
+	received, ok := v.chunks[chunkDataPack.ChunkID]
+	if !ok {
+		return nil
+	}
+
+	checksum := chunkDataPack.Checksum()
+	version, ok := received.versions[checksum]
+	if ok {
+		if !version.OriginIDs.Contains(originID) {
+			version.OriginIDs = append(version.OriginIDs, originID)
+		}
+		return nil
+	}
+
+	version = &verification.ChunkDataPackVersion{
+		ChunkDataPack: chunkDataPack,
+		Collection:    collection,
+		OriginIDs:     flow.IdentifierList{originID},
+	}
+	received.versions[checksum] = version
+
+	return &verification.ConflictingChunkDataPacks{
+		ChunkIndex:      received.status.ChunkIndex,
+		ExecutionResult: received.status.ExecutionResult,
+		First:           copyVersion(received.first),
+		Conflicting:     copyVersion(version),
+	}
+}
+
+// copyVersion returns a copy of the version, so that the origins of the versions in the evidence are
+// not changed by chunk data packs received later.
+func copyVersion(version *verification.ChunkDataPackVersion) *verification.ChunkDataPackVersion {
+	return &verification.ChunkDataPackVersion{
+		ChunkDataPack: version.ChunkDataPack,
+		Collection:    version.Collection,
+		OriginIDs:     version.OriginIDs.Copy(),
+	}
+}
+
+// pruneUpToHeight removes the chunks of the blocks at or below the given height.
+func (v *chunkDataPackVersions) pruneUpToHeight(height uint64) {
+	v.Lock()
+	defer v.Unlock()
+
+	for chunkID, received := range v.chunks {
+		if received.height <= height {
+			delete(v.chunks, chunkID)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/opentracing/opentracing-go"
@@ -32,6 +33,10 @@ import (
 // On receiving an assigned chunk, the engine requests their chunk data pack through the requester that is attached to it.
 // On receiving a chunk data pack response, the fetcher engine validates it, and shapes a verifiable chunk out of it, and passes it
 // to the verifier engine.
+//
+// Chunk data packs that arrive for a chunk after its first one are validated and compared against the ones received before,
+// until the block of the chunk is sealed. A chunk data pack differing from all previous ones is reported as conflicting
+// execution evidence, and the chunk is verified against it as well.
 type Engine struct {
 	// common
 	unit  *engine.Unit
@@ -47,11 +52,14 @@ type Engine struct {
 	headers       storage.Headers           // used to fetch the block header for building verifiable chunk data.
 	results       storage.ExecutionResults  // used to retrieve execution result of an assigned chunk.
 	receipts      storage.ExecutionReceipts // used to find executor ids of a chunk, for requesting chunk data pack.
+	approvals     storage.ResultApprovals   // used to skip the chunks that have already been verified and approved.
+	versions      *chunkDataPackVersions    // keeps the chunk data packs of chunks pushed to verifier, for detecting conflicting ones.
 
 	// output interfaces
-	verifier              network.Engine            // used to push verifiable chunk down the verification pipeline.
-	requester             ChunkDataPackRequester    // used to request chunk data packs from network.
-	chunkConsumerNotifier module.ProcessingNotifier // used to notify chunk consumer that it is done processing a chunk.
+	verifier              network.Engine                    // used to push verifiable chunk down the verification pipeline.
+	requester             ChunkDataPackRequester            // used to request chunk data packs from network.
+	chunkConsumerNotifier module.ProcessingNotifier         // used to notify chunk consumer that it is done processing a chunk.
+	conflictConsumer      ConflictingChunkDataPacksConsumer // used to report conflicting chunk data packs (optional).
 }
 
 func New(
@@ -64,6 +72,7 @@ func New(
 	headers storage.Headers,
 	results storage.ExecutionResults,
 	receipts storage.ExecutionReceipts,
+	approvals storage.ResultApprovals,
 	requester ChunkDataPackRequester,
 ) *Engine {
 	e := &Engine{
@@ -77,6 +86,8 @@ func New(
 		headers:       headers,
 		results:       results,
 		receipts:      receipts,
+		approvals:     approvals,
+		versions:      newChunkDataPackVersions(),
 		requester:     requester,
 	}
 
@@ -92,6 +103,12 @@ func (e *Engine) WithChunkConsumerNotifier(notifier module.ProcessingNotifier) {
 	e.chunkConsumerNotifier = notifier
 }
 
+// WithConflictingChunkDataPacksConsumer sets the consumer the fetcher engine reports the evidence of
+// conflicting chunk data packs to.
+func (e *Engine) WithConflictingChunkDataPacksConsumer(consumer ConflictingChunkDataPacksConsumer) {
+	e.conflictConsumer = consumer
+}
+
 // Ready initializes the engine and returns a channel that is closed when the initialization is done
 func (e *Engine) Ready() <-chan struct{} {
 	if e.chunkConsumerNotifier == nil {
@@ -179,15 +196,28 @@ func (e *Engine) processAssignedChunkWithTracing(chunk *flow.Chunk, result *flow
 func (e *Engine) processAssignedChunk(chunk *flow.Chunk, result *flow.ExecutionResult) (bool, error) {
 	// skips processing a chunk if it belongs to a sealed block.
 	chunkID := chunk.ID()
-	sealed, err := e.blockIsSealed(chunk.ChunkBody.BlockID)
+	sealed, lastSealedHeight, err := e.blockIsSealed(chunk.ChunkBody.BlockID)
 	if err != nil {
 		return false, fmt.Errorf("could not determine whether block has been sealed: %w", err)
 	}
+	// chunk data packs of sealed blocks are no longer compared for conflicts.
+	e.versions.pruneUpToHeight(lastSealedHeight)
 	if sealed {
 		e.chunkConsumerNotifier.Notify(chunkID) // tells consumer that we are done with this chunk.
 		return false, nil
 	}
 
+	// skips processing a chunk if it has already been verified and approved, i.e., the chunk consumer hands it
+	// over again after a restart of the node, as it had not been marked processed by then.
+	approved, err := e.chunkIsApproved(result.ID(), chunk.Index)
+	if err != nil {
+		return false, fmt.Errorf("could not determine whether chunk has been approved: %w", err)
+	}
+	if approved {
+		e.chunkConsumerNotifier.Notify(chunkID) // tells consumer that we are done with this chunk.
+		return false, nil
+	}
+
 	// adds chunk status as a pending chunk to mempool.
 	status := &verification.ChunkStatus{
 		ChunkIndex:      chunk.Index,
@@ -215,8 +245,8 @@ func (e *Engine) processAssignedChunk(chunk *flow.Chunk, result *flow.ExecutionR
 }
 
 // HandleChunkDataPack is called by the chunk requester module everytime a new requested chunk data pack arrives.
-// The chunks are supposed to be deduplicated by the requester.
-// So invocation of this method indicates arrival of a distinct requested chunk.
+// The first chunk data pack arriving for a pending chunk is pushed to the verifier through the chunk consumer pipeline,
+// while the further ones are compared against it for detecting conflicting chunk data packs.
 func (e *Engine) HandleChunkDataPack(originID flow.Identifier, chunkDataPack *flow.ChunkDataPack, collection *flow.Collection) {
 	lg := e.log.With().
 		Hex("origin_id", logging.ID(originID)).
@@ -230,7 +260,7 @@ func (e *Engine) HandleChunkDataPack(originID flow.Identifier, chunkDataPack *fl
 	// make sure we still need it
 	status, exists := e.pendingChunks.ByID(chunkDataPack.ChunkID)
 	if !exists {
-		lg.Debug().Msg("could not fetch pending status from mempool, dropping chunk data")
+		e.handleFurtherChunkDataPack(lg, originID, chunkDataPack, collection)
 		return
 	}
 
@@ -294,7 +324,7 @@ func (e *Engine) handleChunkDataPackWithTracing(
 			return
 		}
 
-		processed, err = e.handleValidatedChunkDataPack(ctx, status, chunkDataPack, collection)
+		processed, err = e.handleValidatedChunkDataPack(ctx, originID, status, chunkDataPack, collection)
 		if err != nil {
 			ferr = fmt.Errorf("could not handle validated chunk data pack: %w", err)
 			return
@@ -308,6 +338,7 @@ func (e *Engine) handleChunkDataPackWithTracing(
 // verifier engine.
 // Boolean return value determines whether verifiable chunk pushed to verifier or not.
 func (e *Engine) handleValidatedChunkDataPack(ctx context.Context,
+	originID flow.Identifier,
 	status *verification.ChunkStatus,
 	chunkDataPack *flow.ChunkDataPack,
 	collection *flow.Collection) (bool, error) {
@@ -322,8 +353,15 @@ func (e *Engine) handleValidatedChunkDataPack(ctx context.Context,
 		return false, nil
 	}
 
+	// keeps the chunk data pack for comparing it against the ones arriving later for the chunk.
+	header, err := e.headers.ByBlockID(chunk.BlockID)
+	if err != nil {
+		return false, fmt.Errorf("could not get block header: %w", err)
+	}
+	e.versions.add(status, header.Height, originID, chunkDataPack, collection)
+
 	// pushes chunk data pack to verifier, and waits for it to be verified.
-	err := e.pushToVerifierWithTracing(ctx, chunk, status.ExecutionResult, chunkDataPack, collection)
+	err = e.pushToVerifierWithTracing(ctx, chunk, status.ExecutionResult, chunkDataPack, collection)
 	if err != nil {
 		return false, fmt.Errorf("could not push the chunk to verifier engine")
 	}
@@ -331,6 +369,62 @@ func (e *Engine) handleValidatedChunkDataPack(ctx context.Context,
 	return true, nil
 }
 
+// handleFurtherChunkDataPack handles a chunk data pack arriving for a chunk that is no longer pending.
+// If the chunk has been pushed to the verifier with a different chunk data pack, the chunk data pack is reported
+// as conflicting execution evidence, and the chunk is verified against it too. Otherwise, the chunk data pack is dropped.
+func (e *Engine) handleFurtherChunkDataPack(lg zerolog.Logger,
+	originID flow.Identifier,
+	chunkDataPack *flow.ChunkDataPack,
+	collection *flow.Collection) {
+
+	status, exists := e.versions.byID(chunkDataPack.ChunkID)
+	if !exists {
+		lg.Debug().Msg("could not fetch pending status from mempool, dropping chunk data")
+		return
+	}
+
+	lg = lg.With().
+		Hex("block_id", logging.ID(status.ExecutionResult.BlockID)).
+		Hex("result_id", logging.ID(status.ExecutionResult.ID())).
+		Uint64("chunk_index", status.ChunkIndex).
+		Logger()
+
+	err := e.validateChunkDataPack(status.ChunkIndex, originID, chunkDataPack, collection, status.ExecutionResult)
+	if err != nil {
+		lg.Error().Err(err).Msg("could not validate further chunk data pack")
+		return
+	}
+
+	evidence := e.versions.addVersion(originID, chunkDataPack, collection)
+	if evidence == nil {
+		lg.Debug().Msg("further chunk data pack matches a previous one, dropping chunk data")
+		return
+	}
+
+	e.metrics.OnConflictingChunkDataPacksAtFetcher()
+	lg.Error().
+		Strs("first_origin_ids", evidence.First.OriginIDs.Strings()).
+		Hex("first_checksum", logging.ID(evidence.First.ChunkDataPack.Checksum())).
+		Hex("conflicting_checksum", logging.ID(chunkDataPack.Checksum())).
+		Msg("conflicting execution evidence: execution nodes provided different chunk data packs for the same chunk")
+
+	if e.conflictConsumer != nil {
+		e.conflictConsumer.OnConflictingChunkDataPacks(evidence)
+	}
+
+	// the chunk is verified against the conflicting chunk data pack as well, so that
+	// the faults of the chunk data pack are reported by the verifier.
+	chunk := status.ExecutionResult.Chunks[status.ChunkIndex]
+	err = e.pushToVerifier(chunk, status.ExecutionResult, chunkDataPack, collection)
+	if err != nil {
+		lg.Fatal().Err(err).Msg("could not push conflicting chunk data pack to verifier engine")
+		return
+	}
+
+	e.metrics.OnVerifiableChunkSentToVerifier()
+	lg.Info().Msg("verifiable chunk of conflicting chunk data pack pushed to verifier engine")
+}
+
 // validateChunkDataPackWithTracing encapsulates the logic of validating a chunk data pack with tracing enabled.
 func (e *Engine) validateChunkDataPackWithTracing(ctx context.Context,
 	chunkIndex uint64,
@@ -458,8 +552,8 @@ func (e Engine) validateStakedExecutionNodeAtBlockID(senderID flow.Identifier, b
 // NotifyChunkDataPackSealed is called by the ChunkDataPackRequester to notify the ChunkDataPackHandler (i.e.,
 // this fetcher engine) that the chunk ID has been sealed and hence the requester will no longer request it.
 //
-// When the requester calls this callback method, it will never returns a chunk data pack for this chunk ID to the handler (i.e.,
-// through HandleChunkDataPack).
+// When the requester calls this callback method, it will never request a chunk data pack for this chunk ID again, though
+// chunk data packs of requests sent before may still arrive at the handler (i.e., through HandleChunkDataPack).
 func (e *Engine) NotifyChunkDataPackSealed(chunkID flow.Identifier) {
 	// we need to report that the job has been finished eventually
 	status, exists := e.pendingChunks.ByID(chunkID)
@@ -537,14 +631,32 @@ func (e *Engine) makeVerifiableChunkData(chunk *flow.Chunk,
 		return nil, fmt.Errorf("could not compute end state of chunk: %w", err)
 	}
 
+	// the epoch selects the service contract calls of the system chunk
+	var epochCounter uint64
+	if isSystemChunk {
+		epochCounter, err = e.state.AtBlockID(chunk.BlockID).Epochs().Current().Counter()
+		if err != nil {
+			return nil, fmt.Errorf("could not get epoch of block: %w", err)
+		}
+	}
+
+	// the execution metadata of the receipts selects the context the chunk is verified in
+	receipts, err := e.receipts.ByBlockID(chunk.BlockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve receipts for block: %v: %w", chunk.BlockID, err)
+	}
+
 	return &verification.VerifiableChunkData{
-		IsSystemChunk: isSystemChunk,
-		Chunk:         chunk,
-		Header:        header,
-		Result:        result,
-		Collection:    collection,
-		ChunkDataPack: chunkDataPack,
-		EndState:      endState,
+		IsSystemChunk:     isSystemChunk,
+		Chunk:             chunk,
+		Header:            header,
+		Result:            result,
+		Collection:        collection,
+		ChunkDataPack:     chunkDataPack,
+		EndState:          endState,
+		TxOffset:          TransactionOffset(result, chunk.Index),
+		EpochCounter:      epochCounter,
+		ExecutionMetadata: executionMetadataOf(receipts, result.ID()),
 	}, nil
 }
 
@@ -592,21 +704,36 @@ func (e *Engine) getAgreeAndDisagreeExecutors(blockID flow.Identifier, resultID
 	return agrees, disagrees, nil
 }
 
-// blockIsSealed returns true if the block at specified height by block ID is sealed.
-func (e Engine) blockIsSealed(blockID flow.Identifier) (bool, error) {
+// blockIsSealed returns true if the block at specified height by block ID is sealed, along with the height of
+// the last sealed block.
+func (e Engine) blockIsSealed(blockID flow.Identifier) (bool, uint64, error) {
 	// TODO: as an optimization, we can keep record of last sealed height on a local variable.
 	header, err := e.headers.ByBlockID(blockID)
 	if err != nil {
-		return false, fmt.Errorf("could not get block header: %w", err)
+		return false, 0, fmt.Errorf("could not get block header: %w", err)
 	}
 
 	lastSealed, err := e.state.Sealed().Head()
 	if err != nil {
-		return false, fmt.Errorf("could not get last sealed: %w", err)
+		return false, 0, fmt.Errorf("could not get last sealed: %w", err)
 	}
 
 	sealed := header.Height <= lastSealed.Height
-	return sealed, nil
+	return sealed, lastSealed.Height, nil
+}
+
+// chunkIsApproved returns true if a result approval has been generated and stored for the chunk of the given
+// result, i.e., the chunk has already been verified and approved.
+func (e Engine) chunkIsApproved(resultID flow.Identifier, chunkIndex uint64) (bool, error) {
+	_, err := e.approvals.ByChunk(resultID, chunkIndex)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve result approval: %w", err)
+	}
+
+	return true, nil
 }
 
 // executorsOf segregates the executors of the given receipts based on the given execution result id.
@@ -629,6 +756,26 @@ func executorsOf(receipts []*flow.ExecutionReceipt, resultID flow.Identifier) (f
 	return agrees, disagrees
 }
 
+// executionMetadataOf returns the distinct execution metadata of the given receipts committing to
+// the given execution result id, in the order of the receipts.
+func executionMetadataOf(receipts []*flow.ExecutionReceipt, resultID flow.Identifier) []flow.ExecutionMetadata {
+	var metadata []flow.ExecutionMetadata
+	seen := make(map[flow.ExecutionMetadata]struct{})
+
+	for _, receipt := range receipts {
+		if receipt.ExecutionResult.ID() != resultID {
+			continue
+		}
+		if _, ok := seen[receipt.ExecutionMetadata]; ok {
+			continue
+		}
+		seen[receipt.ExecutionMetadata] = struct{}{}
+		metadata = append(metadata, receipt.ExecutionMetadata)
+	}
+
+	return metadata
+}
+
 // EndStateCommitment computes the end state of the given chunk.
 func EndStateCommitment(result *flow.ExecutionResult, chunkIndex uint64, systemChunk bool) (flow.StateCommitment, error) {
 	var endState flow.StateCommitment
@@ -647,6 +794,22 @@ func EndStateCommitment(result *flow.ExecutionResult, chunkIndex uint64, systemC
 	return endState, nil
 }
 
+// TransactionOffset returns the index of the first transaction of the chunk with the given index
+// within its block, which is the number of transactions of the chunks preceding it in the result.
+// The number of transactions of each chunk is checked against its collection when verifying the
+// chunk, so the offset doesn't rely on the transaction counts declared by the collection guarantees.
+// The system chunk follows all collections of the block.
+func TransactionOffset(result *flow.ExecutionResult, chunkIndex uint64) uint32 {
+	var offset uint32
+	for _, chunk := range result.Chunks {
+		if chunk.Index >= chunkIndex {
+			break
+		}
+		offset += uint32(chunk.NumberOfTransactions)
+	}
+	return offset
+}
+
 // IsSystemChunk returns true if `chunkIndex` points to a system chunk in `result`.
 // Otherwise, it returns false.
 // In the current version, a chunk is a system chunk if it is the last chunk of the
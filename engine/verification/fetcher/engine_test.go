@@ -22,6 +22,7 @@ import (
 	"github.com/onflow/flow-go/network/mocknetwork"
 	flowprotocol "github.com/onflow/flow-go/state/protocol"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	flowstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -38,6 +39,7 @@ type FetcherEngineTestSuite struct {
 	chunkConsumerNotifier *module.ProcessingNotifier          // to report a chunk has been processed
 	results               *storage.ExecutionResults           // to retrieve execution result of an assigned chunk
 	receipts              *storage.ExecutionReceipts          // used to find executor of the chunk
+	approvals             *storage.ResultApprovals            // used to find the chunks that have already been approved
 	requester             *mockfetcher.ChunkDataPackRequester // used to request chunk data packs from network
 }
 
@@ -54,6 +56,7 @@ func setupTest() *FetcherEngineTestSuite {
 		chunkConsumerNotifier: &module.ProcessingNotifier{},
 		results:               &storage.ExecutionResults{},
 		receipts:              &storage.ExecutionReceipts{},
+		approvals:             &storage.ResultApprovals{},
 		requester:             &mockfetcher.ChunkDataPackRequester{},
 	}
 
@@ -73,6 +76,7 @@ func newFetcherEngine(s *FetcherEngineTestSuite) *fetcher.Engine {
 		s.headers,
 		s.results,
 		s.receipts,
+		s.approvals,
 		s.requester)
 
 	e.WithChunkConsumerNotifier(s.chunkConsumerNotifier)
@@ -131,6 +135,7 @@ func testProcessAssignChunkHappyPath(t *testing.T, chunkNum int, assignedNum int
 
 	// mocks resources on fetcher engine side.
 	mockResultsByIDs(s.results, []*flow.ExecutionResult{result})
+	mockApprovalsByChunk(s.approvals, statuses, false)
 	mockPendingChunksAdd(t, s.pendingChunks, statuses, true)
 	mockPendingChunksRem(t, s.pendingChunks, statuses, true)
 	mockPendingChunksByID(s.pendingChunks, statuses)
@@ -230,6 +235,7 @@ func TestProcessAssignChunkSealedAfterRequest(t *testing.T) {
 
 	// mocks resources on fetcher engine side.
 	mockResultsByIDs(s.results, []*flow.ExecutionResult{result})
+	mockApprovalsByChunk(s.approvals, statuses, false)
 	mockPendingChunksAdd(t, s.pendingChunks, statuses, true)
 	mockPendingChunksRem(t, s.pendingChunks, statuses, true)
 	mockPendingChunksByID(s.pendingChunks, statuses)
@@ -426,6 +432,73 @@ func TestChunkResponse_MissingStatus(t *testing.T) {
 	s.state.AssertNotCalled(t, "AtBlockID")
 }
 
+// TestChunkResponse_ConflictingChunkDataPacks evaluates that chunk data packs arriving for a chunk after its first one
+// are compared against it:
+// - A chunk data pack identical to the first one is dropped.
+// - A chunk data pack differing from the first one is reported as conflicting execution evidence, with the origins of
+// both versions attached, and the chunk is verified against it as well, without notifying the chunk consumer again.
+func TestChunkResponse_ConflictingChunkDataPacks(t *testing.T) {
+	s := setupTest()
+	e := newFetcherEngine(s)
+	consumer := &mockfetcher.ConflictingChunkDataPacksConsumer{}
+	e.WithConflictingChunkDataPacksConsumer(consumer)
+
+	// creates a result with 2 chunks, which one of those chunks is assigned to this fetcher engine
+	// also, the result has been created by three execution nodes, while the other one has a conflicting result with it.
+	block, result, statuses, _ := completeChunkStatusListFixture(t, 2, 1)
+	_, _, agrees, _ := mockReceiptsBlockID(t, block.ID(), s.receipts, result, 3, 1)
+	mockBlockSealingStatus(s.state, s.headers, block.Header, false)
+	mockStateAtBlockIDForIdentities(s.state, block.ID(), agrees)
+
+	// the chunk is pending only until its first chunk data pack arrives.
+	status := statuses[0]
+	chunkID := status.ID()
+	s.pendingChunks.On("ByID", chunkID).Return(status, true).Once()
+	s.pendingChunks.On("ByID", chunkID).Return(nil, false)
+	mockPendingChunksRem(t, s.pendingChunks, statuses, true)
+
+	chunkDataPacks, collections, _ := verifiableChunkFixture(statuses.Chunks(), block, result)
+	first := chunkDataPacks[chunkID]
+	conflicting := *first
+	conflicting.Proof = []byte{'c'}
+
+	var verified []*flow.ChunkDataPack
+	s.verifier.On("ProcessLocal", mock.Anything).Run(func(args mock.Arguments) {
+		vc, ok := args[0].(*verification.VerifiableChunkData)
+		require.True(t, ok)
+		verified = append(verified, vc.ChunkDataPack)
+	}).Return(nil).Twice()
+
+	var evidence *verification.ConflictingChunkDataPacks
+	consumer.On("OnConflictingChunkDataPacks", mock.Anything).Run(func(args mock.Arguments) {
+		var ok bool
+		evidence, ok = args[0].(*verification.ConflictingChunkDataPacks)
+		require.True(t, ok)
+	}).Return().Once()
+
+	s.metrics.On("OnChunkDataPackArrivedAtFetcher").Return().Times(3)
+	s.metrics.On("OnVerifiableChunkSentToVerifier").Return().Twice()
+	s.metrics.On("OnConflictingChunkDataPacksAtFetcher").Return().Once()
+	mockChunkConsumerNotifier(t, s.chunkConsumerNotifier, flow.IdentifierList{status.ChunkLocatorID()})
+
+	e.HandleChunkDataPack(agrees[0].NodeID, first, collections[chunkID])
+	e.HandleChunkDataPack(agrees[1].NodeID, first, collections[chunkID])
+	e.HandleChunkDataPack(agrees[2].NodeID, &conflicting, collections[chunkID])
+
+	mock.AssertExpectationsForObjects(t, s.pendingChunks, s.verifier, s.chunkConsumerNotifier, s.metrics, consumer)
+
+	// the chunk is verified against both versions of its chunk data pack.
+	require.Equal(t, []*flow.ChunkDataPack{first, &conflicting}, verified)
+
+	require.NotNil(t, evidence)
+	require.Equal(t, chunkID, evidence.ChunkID())
+	require.Equal(t, result.ID(), evidence.ExecutionResult.ID())
+	require.Equal(t, first, evidence.First.ChunkDataPack)
+	require.ElementsMatch(t, flow.IdentifierList{agrees[0].NodeID, agrees[1].NodeID}, evidence.First.OriginIDs)
+	require.Equal(t, &conflicting, evidence.Conflicting.ChunkDataPack)
+	require.Equal(t, flow.IdentifierList{agrees[2].NodeID}, evidence.Conflicting.OriginIDs)
+}
+
 // TestSkipChunkOfSealedBlock evaluates that if fetcher engine receives a chunk belonging to a sealed block,
 // it drops it without processing it any further and and notifies consumer
 // that it is done with processing that chunk.
@@ -457,6 +530,70 @@ func TestSkipChunkOfSealedBlock(t *testing.T) {
 	s.pendingChunks.AssertNotCalled(t, "Add")
 }
 
+// TestSkipApprovedChunk evaluates that if fetcher engine receives a chunk that has already been verified and approved,
+// e.g., when the chunk consumer hands it over again after a restart, it drops it without requesting its chunk data pack
+// and notifies consumer that it is done with processing that chunk.
+// TestTransactionOffset evaluates that the transaction offset of a chunk is the number of transactions
+// of the chunks preceding it in the execution result.
+func TestTransactionOffset(t *testing.T) {
+	blockID := unittest.IdentifierFixture()
+	result := unittest.ExecutionResultFixture()
+	result.Chunks = flow.ChunkList{}
+	for i, count := range []uint64{3, 0, 5, 1} {
+		chunk := unittest.ChunkFixture(blockID, uint(i))
+		chunk.Index = uint64(i)
+		chunk.NumberOfTransactions = count
+		result.Chunks.Insert(chunk)
+	}
+
+	require.Equal(t, uint32(0), fetcher.TransactionOffset(result, 0))
+	require.Equal(t, uint32(3), fetcher.TransactionOffset(result, 1))
+	require.Equal(t, uint32(3), fetcher.TransactionOffset(result, 2))
+	require.Equal(t, uint32(8), fetcher.TransactionOffset(result, 3))
+}
+
+func TestSkipApprovedChunk(t *testing.T) {
+	s := setupTest()
+	e := newFetcherEngine(s)
+
+	// creates a single chunk locator of an unsealed block, and mocks it as approved.
+	block, result, statuses, locators := completeChunkStatusListFixture(t, 2, 1)
+	s.metrics.On("OnAssignedChunkReceivedAtFetcher").Return().Once()
+
+	mockBlockSealingStatus(s.state, s.headers, block.Header, false)
+	mockResultsByIDs(s.results, []*flow.ExecutionResult{result})
+	mockApprovalsByChunk(s.approvals, statuses, true)
+
+	// expects processing notifier being invoked upon approved chunk detected,
+	// which means the termination of processing the chunk on fetcher engine side.
+	mockChunkConsumerNotifier(t, s.chunkConsumerNotifier, flow.GetIDs(statuses))
+
+	e.ProcessAssignedChunk(locators[0])
+
+	mock.AssertExpectationsForObjects(t, s.results, s.approvals, s.metrics)
+	// we should not request the chunk data pack of an approved chunk.
+	s.requester.AssertNotCalled(t, "Request")
+	// we should not try adding an approved chunk to chunk status mempool.
+	s.pendingChunks.AssertNotCalled(t, "Add")
+	s.verifier.AssertNotCalled(t, "ProcessLocal")
+}
+
+// mockApprovalsByChunk mocks the approvals storage for querying the approvals of the given chunk statuses, as approved or
+// not approved.
+func mockApprovalsByChunk(approvals *storage.ResultApprovals, list []*verification.ChunkStatus, approved bool) {
+	for _, status := range list {
+		resultID := status.ExecutionResult.ID()
+		if approved {
+			approval := unittest.ResultApprovalFixture(
+				unittest.WithExecutionResultID(resultID),
+				unittest.WithChunk(status.ChunkIndex))
+			approvals.On("ByChunk", resultID, status.ChunkIndex).Return(approval, nil)
+		} else {
+			approvals.On("ByChunk", resultID, status.ChunkIndex).Return(nil, flowstorage.ErrNotFound)
+		}
+	}
+}
+
 // mockResultsByIDs mocks the results storage for affirmative querying of result IDs.
 // Each result should be queried by the specified number of times.
 func mockResultsByIDs(results *storage.ExecutionResults, list []*flow.ExecutionResult) {
@@ -511,7 +648,11 @@ func mockReceiptsBlockID(t *testing.T,
 	return agreeReceipts, disagreeReceipts, agreeExecutors, disagreeExecutors
 }
 
+// testEpochCounter is the counter of the epoch of the blocks mocked in the state.
+const testEpochCounter = uint64(1)
+
 // mockStateAtBlockIDForIdentities is a test helper that mocks state at the block ID with the given execution nodes identities.
+// The block is part of the epoch with the testEpochCounter.
 func mockStateAtBlockIDForIdentities(state *protocol.State, blockID flow.Identifier, participants flow.IdentityList) {
 	snapshot := &protocol.Snapshot{}
 	state.On("AtBlockID", blockID).Return(snapshot)
@@ -519,6 +660,12 @@ func mockStateAtBlockIDForIdentities(state *protocol.State, blockID flow.Identif
 	for _, id := range participants {
 		snapshot.On("Identity", id.NodeID).Return(id, nil)
 	}
+
+	epoch := &protocol.Epoch{}
+	epoch.On("Counter").Return(testEpochCounter, nil)
+	epochs := &protocol.EpochQuery{}
+	epochs.On("Current").Return(epoch)
+	snapshot.On("Epochs").Return(epochs).Maybe()
 }
 
 // mockStateAtBlockIDForMissingIdentities is a test helper that mocks state at the block ID with the given execution nodes identities as
@@ -660,6 +807,10 @@ func mockVerifierEngine(t *testing.T,
 		require.NoError(t, err)
 
 		require.Equal(t, endState, vc.EndState)
+		require.Equal(t, expected.TxOffset, vc.TxOffset)
+		require.Equal(t, expected.EpochCounter, vc.EpochCounter)
+		// receipts of the fixtures report legacy execution metadata
+		require.Equal(t, []flow.ExecutionMetadata{{}}, vc.ExecutionMetadata)
 		wg.Done()
 	}).Return(nil).Times(len(verifiableChunks))
 
@@ -770,9 +921,12 @@ func verifiableChunkFixture(chunks flow.ChunkList, block *flow.Block, result *fl
 
 		chunkDataPack := chunkDataPacks[chunkID]
 
+		// only system chunks carry the epoch, which selects their calls
+		var epochCounter uint64
 		if fetcher.IsSystemChunk(chunk.Index, result) {
 			chunkDataPack.CollectionID = flow.ZeroID
 			collections[chunkID] = &flow.Collection{Transactions: nil}
+			epochCounter = testEpochCounter
 		}
 
 		verifiableChunks[chunkID] = &verification.VerifiableChunkData{
@@ -781,6 +935,8 @@ func verifiableChunkFixture(chunks flow.ChunkList, block *flow.Block, result *fl
 			Result:        result,
 			Collection:    collections[chunkID],
 			ChunkDataPack: chunkDataPack,
+			TxOffset:      fetcher.TransactionOffset(result, chunk.Index),
+			EpochCounter:  epochCounter,
 		}
 	}
 
@@ -19,12 +19,15 @@ type ChunkDataPackRequester interface {
 type ChunkDataPackHandler interface {
 	// HandleChunkDataPack is called by the ChunkDataPackRequester anytime a new requested chunk arrives.
 	// It contains the logic of handling the chunk data pack.
+	//
+	// Chunk data packs are not deduplicated by the requester, so that the handler can detect execution nodes
+	// providing different chunk data packs for the same chunk.
 	HandleChunkDataPack(originID flow.Identifier, chunkDataPack *flow.ChunkDataPack, collection *flow.Collection)
 
 	// NotifyChunkDataPackSealed is called by the ChunkDataPackRequester to notify the ChunkDataPackHandler that the chunk ID has been sealed and
 	// hence the requester will no longer request it.
 	//
-	// When the requester calls this callback method, it will never returns a chunk data pack for this chunk ID to the handler (i.e.,
-	// through HandleChunkDataPack).
+	// When the requester calls this callback method, it will never request a chunk data pack for this chunk ID again, though
+	// chunk data packs of requests sent before may still arrive at the handler (i.e., through HandleChunkDataPack).
 	NotifyChunkDataPackSealed(chunkID flow.Identifier)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/verification/fetcher"
 	"github.com/onflow/flow-go/model/chunks"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/messages"
@@ -597,6 +598,15 @@ func (e *Engine) matchChunk(
 		return fmt.Errorf("could not find block header: %w", err)
 	}
 
+	// the epoch selects the service contract calls of the system chunk
+	var epochCounter uint64
+	if isSystemChunk {
+		epochCounter, err = e.state.AtBlockID(blockID).Epochs().Current().Counter()
+		if err != nil {
+			return fmt.Errorf("could not find epoch of block: %w", err)
+		}
+	}
+
 	// creates a verifiable chunk for assigned chunk
 	vchunk := &verification.VerifiableChunkData{
 		IsSystemChunk: isSystemChunk,
@@ -606,6 +616,8 @@ func (e *Engine) matchChunk(
 		Collection:    collection,
 		ChunkDataPack: chunkDataPack,
 		EndState:      endState,
+		TxOffset:      fetcher.TransactionOffset(result, chunk.Index),
+		EpochCounter:  epochCounter,
 	}
 
 	err = e.verifier.ProcessLocal(vchunk)
@@ -105,6 +105,13 @@ func (suite *MatchEngineTestSuite) SetupTest() {
 	suite.state = state
 	suite.sealed = sealed
 
+	// all blocks are in the first epoch, which selects the calls of system chunks
+	epoch := &protocol.Epoch{}
+	epoch.On("Counter").Return(uint64(0), nil)
+	epochs := &protocol.EpochQuery{}
+	epochs.On("Current").Return(epoch)
+	suite.snapshot.On("Epochs").Return(epochs).Maybe()
+
 	// setup other dependencies
 	suite.results = stdmap.NewResultDataPacks(10)
 	suite.verifier = &mocknetwork.Engine{}
@@ -0,0 +1,43 @@
+package requester
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// requestBatches groups the chunk data pack requests that are qualified for dispatching at one
+// round of the requester by the execution nodes they are dispatched to.
+type requestBatches struct {
+	byTarget map[flow.Identifier]flow.IdentifierList // chunk IDs to request, by execution node.
+	targets  flow.IdentifierList                     // execution nodes, in the order they were first added.
+}
+
+func newRequestBatches() *requestBatches {
+	return &requestBatches{
+		byTarget: make(map[flow.Identifier]flow.IdentifierList),
+	}
+}
+
+// add adds the chunk ID to the batches of each of the execution nodes.
+func (b *requestBatches) add(chunkID flow.Identifier, targetIDs flow.IdentifierList) {
+	for _, targetID := range targetIDs {
+		chunkIDs, ok := b.byTarget[targetID]
+		if !ok {
+			b.targets = append(b.targets, targetID)
+		}
+		b.byTarget[targetID] = append(chunkIDs, chunkID)
+	}
+}
+
+// split returns the chunk IDs to request from the execution node, in batches of at most size chunk IDs.
+func (b *requestBatches) split(targetID flow.Identifier, size int) []flow.IdentifierList {
+	chunkIDs := b.byTarget[targetID]
+	batches := make([]flow.IdentifierList, 0, (len(chunkIDs)+size-1)/size)
+	for start := 0; start < len(chunkIDs); start += size {
+		end := start + size
+		if end > len(chunkIDs) {
+			end = len(chunkIDs)
+		}
+		batches = append(batches, chunkIDs[start:end])
+	}
+	return batches
+}
@@ -23,3 +23,28 @@ func RetryAfterQualifier(_ uint64, lastAttempt time.Time, retryAfter time.Durati
 	nextTry := lastAttempt.Add(retryAfter)
 	return nextTry.Before(time.Now())
 }
+
+// ExponentialBackoffQualifier only qualifies a chunk request if an exponentially growing interval has been elapsed since
+// the last time this request has been dispatched. The interval starts at baseInterval after the first attempt, doubles with
+// every further attempt, and is capped at maxInterval. Requests that have never been dispatched are qualified instantly.
+//
+// In contrast to RetryAfterQualifier, the interval is derived from the number of attempts, so that the back-off does not rely
+// on the updater of the request history.
+func ExponentialBackoffQualifier(baseInterval time.Duration, maxInterval time.Duration) RequestQualifierFunc {
+	return func(attempts uint64, lastAttempt time.Time, _ time.Duration) bool {
+		if attempts == 0 {
+			return true
+		}
+
+		interval := baseInterval
+		for i := uint64(1); i < attempts && interval < maxInterval; i++ {
+			interval *= 2
+		}
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+
+		nextTry := lastAttempt.Add(interval)
+		return nextTry.Before(time.Now())
+	}
+}
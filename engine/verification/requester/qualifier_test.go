@@ -0,0 +1,27 @@
+package requester
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestExponentialBackoffQualifier evaluates that the back-off interval doubles with each attempt up to its maximum.
+func TestExponentialBackoffQualifier(t *testing.T) {
+	qualifier := ExponentialBackoffQualifier(time.Minute, 10*time.Minute)
+
+	// requests never dispatched are qualified instantly
+	require.True(t, qualifier(0, time.Now(), 0))
+
+	// first attempt backs off for the base interval
+	require.False(t, qualifier(1, time.Now().Add(-30*time.Second), 0))
+	require.True(t, qualifier(1, time.Now().Add(-2*time.Minute), 0))
+
+	// third attempt backs off for 4 times the base interval
+	require.False(t, qualifier(3, time.Now().Add(-3*time.Minute), 0))
+	require.True(t, qualifier(3, time.Now().Add(-5*time.Minute), 0))
+
+	// interval is capped at the maximum
+	require.True(t, qualifier(100, time.Now().Add(-11*time.Minute), 0))
+}
@@ -45,6 +45,8 @@ type Engine struct {
 	pendingRequests  mempool.ChunkRequests                  // used to track requested chunks.
 	reqQualifierFunc RequestQualifierFunc                   // used to decide whether to dispatch a request at a certain cycle.
 	reqUpdaterFunc   mempool.ChunkRequestHistoryUpdaterFunc // used to atomically update chunk request info on mempool.
+	rotation         *targetRotation                        // used to rotate the execution nodes asked for chunk data packs between attempts.
+	batchSize        uint                                   // maximum number of chunks requested in one batch request, zero if requests are not batched.
 }
 
 func New(log zerolog.Logger,
@@ -69,6 +71,7 @@ func New(log zerolog.Logger,
 		pendingRequests:  pendingRequests,
 		reqUpdaterFunc:   reqUpdaterFunc,
 		reqQualifierFunc: reqQualifierFunc,
+		rotation:         newTargetRotation(),
 	}
 
 	con, err := net.Register(engine.RequestChunks, e)
@@ -84,6 +87,17 @@ func (e *Engine) WithChunkDataPackHandler(handler fetcher.ChunkDataPackHandler)
 	e.handler = handler
 }
 
+// WithBatchRequests makes the engine group the chunk data pack requests qualified at each round by
+// their target execution nodes, and dispatch them as batch requests of at most batchSize chunks,
+// instead of dispatching a request for each chunk. The batch size is capped at the maximum size
+// execution nodes answer.
+func (e *Engine) WithBatchRequests(batchSize uint) {
+	if batchSize > messages.MaxChunkDataBatchSize {
+		batchSize = messages.MaxChunkDataBatchSize
+	}
+	e.batchSize = batchSize
+}
+
 // SubmitLocal submits an event originating on the local node.
 func (e *Engine) SubmitLocal(event interface{}) {
 	e.log.Fatal().Msg("engine is not supposed to be invoked on SubmitLocal")
@@ -166,6 +180,8 @@ func (e *Engine) handleChunkDataPackWithTracing(originID flow.Identifier, chunkD
 }
 
 // handleChunkDataPack sends the received chunk data pack and its collection to the registered handler, and cleans up its request status.
+// Chunk data packs of chunks that are no longer requested are sent to the handler as well, since they may be conflicting with
+// the one received first.
 func (e *Engine) handleChunkDataPack(originID flow.Identifier, chunkDataPack *flow.ChunkDataPack, collection *flow.Collection) {
 	chunkID := chunkDataPack.ChunkID
 	collectionID := collection.ID()
@@ -177,11 +193,12 @@ func (e *Engine) handleChunkDataPack(originID flow.Identifier, chunkDataPack *fl
 
 	e.metrics.OnChunkDataPackResponseReceivedFromNetwork()
 
-	// makes sure we still need this chunk, and we will not process duplicate chunk data packs.
+	// stops requesting this chunk, further chunk data packs of it are compared by the handler against the first one.
 	removed := e.pendingRequests.Rem(chunkID)
-	if !removed {
-		lg.Debug().Msg("chunk request status not found in mempool to be removed, dropping chunk")
-		return
+	if removed {
+		e.rotation.forget(chunkID)
+	} else {
+		lg.Debug().Msg("chunk request status not found in mempool to be removed, sending chunk as a further chunk data pack")
 	}
 
 	e.handler.HandleChunkDataPack(originID, chunkDataPack, collection)
@@ -231,13 +248,26 @@ func (e *Engine) onTimer() {
 			Msg("could not determine whether block has been sealed")
 	}
 
+	// drops the rotation of targets for requests that are no longer pending
+	e.rotation.prune(pendingReqs)
+
+	// with batching, qualified requests are collected during this round and dispatched at its end
+	var batches *requestBatches
+	if e.batchSize > 0 {
+		batches = newRequestBatches()
+	}
+
 	for _, request := range pendingReqs {
-		e.handleChunkDataPackRequestWithTracing(request, lastSealed.Height)
+		e.handleChunkDataPackRequestWithTracing(request, lastSealed.Height, batches)
+	}
+
+	if batches != nil {
+		e.dispatchBatches(batches)
 	}
 }
 
 // handleChunkDataPackRequestWithTracing encapsulates the logic of dispatching chunk data request in network with tracing enabled.
-func (e *Engine) handleChunkDataPackRequestWithTracing(request *verification.ChunkDataPackRequest, lastSealedHeight uint64) {
+func (e *Engine) handleChunkDataPackRequestWithTracing(request *verification.ChunkDataPackRequest, lastSealedHeight uint64, batches *requestBatches) {
 	span, ok := e.tracer.GetSpan(request.ChunkID, trace.VERProcessChunkDataPackRequest)
 	if !ok {
 		span = e.tracer.StartSpan(request.ChunkID, trace.VERProcessChunkDataPackRequest)
@@ -247,12 +277,13 @@ func (e *Engine) handleChunkDataPackRequestWithTracing(request *verification.Chu
 
 	ctx := opentracing.ContextWithSpan(e.unit.Ctx(), span)
 	e.tracer.WithSpanFromContext(ctx, trace.VERRequesterHandleChunkDataRequest, func() {
-		e.handleChunkDataPackRequest(ctx, request, lastSealedHeight)
+		e.handleChunkDataPackRequest(ctx, request, lastSealedHeight, batches)
 	})
 }
 
 // handleChunkDataPackRequest encapsulates the logic of dispatching the chunk data pack request to the network.
-func (e *Engine) handleChunkDataPackRequest(ctx context.Context, request *verification.ChunkDataPackRequest, lastSealedHeight uint64) {
+// If batches is not nil, the qualified request is added to the batches of its targets instead of being dispatched.
+func (e *Engine) handleChunkDataPackRequest(ctx context.Context, request *verification.ChunkDataPackRequest, lastSealedHeight uint64, batches *requestBatches) {
 	lg := e.log.With().
 		Hex("chunk_id", logging.ID(request.ID())).
 		Uint64("block_height", request.Height).
@@ -261,6 +292,7 @@ func (e *Engine) handleChunkDataPackRequest(ctx context.Context, request *verifi
 	// if block has been sealed, then we can finish
 	if request.Height <= lastSealedHeight {
 		removed := e.pendingRequests.Rem(request.ID())
+		e.rotation.forget(request.ID())
 		e.handler.NotifyChunkDataPackSealed(request.ID())
 		lg.Info().
 			Bool("removed", removed).
@@ -274,6 +306,12 @@ func (e *Engine) handleChunkDataPackRequest(ctx context.Context, request *verifi
 		return
 	}
 
+	if batches != nil {
+		batches.add(request.ChunkID, e.rotation.next(request, int(e.requestTargets)))
+		lg.Debug().Msg("chunk data pack request added to batches")
+		return
+	}
+
 	err := e.requestChunkDataPackWithTracing(ctx, request)
 	if err != nil {
 		lg.Error().Err(err).Msg("could not request chunk data pack")
@@ -305,8 +343,9 @@ func (e *Engine) requestChunkDataPack(request *verification.ChunkDataPackRequest
 		Nonce:   rand.Uint64(), // prevent the request from being deduplicated by the receiver
 	}
 
-	// publishes the chunk data request to the network
-	targetIDs := request.SampleTargets(int(e.requestTargets))
+	// publishes the chunk data request to the network, rotating the targets between attempts
+	// so that execution nodes which did not respond before are not asked again right away
+	targetIDs := e.rotation.next(request, int(e.requestTargets))
 	err := e.con.Publish(req, targetIDs...)
 	if err != nil {
 		return fmt.Errorf("could not publish chunk data pack request for chunk (id=%s): %w", request.ChunkID, err)
@@ -315,6 +354,43 @@ func (e *Engine) requestChunkDataPack(request *verification.ChunkDataPackRequest
 	return nil
 }
 
+// dispatchBatches dispatches the batch requests to each of their execution nodes, and updates the request
+// history of each chunk that has been requested from at least one execution node.
+func (e *Engine) dispatchBatches(batches *requestBatches) {
+	dispatched := make(map[flow.Identifier]struct{})
+	for _, targetID := range batches.targets {
+		for _, chunkIDs := range batches.split(targetID, int(e.batchSize)) {
+			req := &messages.ChunkDataBatchRequest{
+				ChunkIDs: chunkIDs,
+				Nonce:    rand.Uint64(), // prevent the request from being deduplicated by the receiver
+			}
+			err := e.con.Unicast(req, targetID)
+			if err != nil {
+				e.log.Error().
+					Err(err).
+					Hex("target_id", logging.ID(targetID)).
+					Int("chunks", len(chunkIDs)).
+					Msg("could not request chunk data pack batch")
+				continue
+			}
+			for _, chunkID := range chunkIDs {
+				dispatched[chunkID] = struct{}{}
+			}
+		}
+	}
+
+	for chunkID := range dispatched {
+		attempts, lastAttempt, retryAfter, updated := e.onRequestDispatched(chunkID)
+		e.log.Info().
+			Hex("chunk_id", logging.ID(chunkID)).
+			Bool("pending_request_updated", updated).
+			Uint64("attempts_made", attempts).
+			Time("last_attempt", lastAttempt).
+			Dur("retry_after", retryAfter).
+			Msg("chunk data pack requested in batch")
+	}
+}
+
 // canDispatchRequest returns whether chunk data request for this chunk ID can be dispatched.
 func (e *Engine) canDispatchRequest(chunkID flow.Identifier) bool {
 	attempts, lastAttempt, retryAfter, exists := e.pendingRequests.RequestHistory(chunkID)
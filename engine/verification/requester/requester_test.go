@@ -143,10 +143,10 @@ func TestHandleChunkDataPack_HappyPath_Multiple(t *testing.T) {
 }
 
 // TestHandleChunkDataPack_NonExistingRequest evaluates that failing to remove a received chunk data pack's request
-// from the memory terminates the procedure of handling a chunk data pack without passing it to the handler.
-// The request for a chunk data pack may be removed from the memory if duplicate copies of a requested chunk data pack arrive
-// concurrently. Then the mutex lock on pending requests mempool allows only one of those requested chunk data packs to remove the
-// request and pass to handler. While handling the other ones gracefully terminated.
+// from the memory still passes the chunk data pack to the handler.
+// The request for a chunk data pack may be removed from the memory if several copies of a requested chunk data pack arrive
+// concurrently or later on. Then the mutex lock on pending requests mempool allows only one of those requested chunk data packs to
+// remove the request, while the other ones are passed to the handler for detecting conflicting chunk data packs.
 func TestHandleChunkDataPack_FailedRequestRemoval(t *testing.T) {
 	s := setupTest()
 	e := newRequesterEngine(t, s)
@@ -160,11 +160,14 @@ func TestHandleChunkDataPack_FailedRequestRemoval(t *testing.T) {
 	s.pendingRequests.On("Rem", response.ChunkDataPack.ChunkID).Return(false).Once()
 	s.metrics.On("OnChunkDataPackResponseReceivedFromNetwork").Return().Once()
 
+	// the chunk data pack is still sent to the handler, so that it is compared against the one received first.
+	s.handler.On("HandleChunkDataPack", originID, &response.ChunkDataPack, &response.Collection).Return().Once()
+	s.metrics.On("OnChunkDataPackSentToFetcher").Return().Once()
+
 	err := e.Process(originID, response)
 	require.Nil(t, err)
 
-	testifymock.AssertExpectationsForObjects(t, s.pendingRequests, s.con, s.metrics)
-	s.handler.AssertNotCalled(t, "HandleChunkDataPack")
+	testifymock.AssertExpectationsForObjects(t, s.pendingRequests, s.con, s.metrics, s.handler)
 }
 
 // TestRequestPendingChunkSealedBlock evaluates that requester engine drops pending requests for chunks belonging to
@@ -396,6 +399,51 @@ func TestDispatchingRequests_Hybrid(t *testing.T) {
 	unittest.RequireCloseBefore(t, e.Done(), time.Second, "could not stop engine on time")
 }
 
+// TestDispatchingBatchRequests evaluates that with batching enabled, the requester groups the chunk requests qualified
+// at a round by their target execution nodes, and dispatches a single batch request to each of them.
+func TestDispatchingBatchRequests(t *testing.T) {
+	s := setupTest()
+	e := newRequesterEngine(t, s)
+	e.WithBatchRequests(100)
+
+	// creates 10 chunk requests with the same 2 agree targets, hence each chunk is requested from both of them.
+	agrees := unittest.IdentifierListFixture(2)
+	disagrees := unittest.IdentifierListFixture(3)
+	requests := unittest.ChunkDataPackRequestListFixture(10,
+		unittest.WithHeightGreaterThan(5),
+		unittest.WithAgrees(agrees),
+		unittest.WithDisagrees(disagrees))
+	vertestutils.MockLastSealedHeight(s.state, 5)
+	s.pendingRequests.On("All").Return(requests)
+
+	qualifyWG := mockPendingRequestInfoAndUpdate(t,
+		s.pendingRequests, flow.GetIDs(requests), flow.IdentifierList{}, flow.IdentifierList{}, 1)
+	s.metrics.On("OnChunkDataPackRequestDispatchedInNetwork").Return().Times(len(requests))
+
+	// each agree target receives one batch request for all chunks
+	conduitWG := &sync.WaitGroup{}
+	conduitWG.Add(len(agrees))
+	s.con.On("Unicast", testifymock.Anything, testifymock.Anything).Run(func(args testifymock.Arguments) {
+		req, ok := args[0].(*messages.ChunkDataBatchRequest)
+		require.True(t, ok)
+		require.ElementsMatch(t, flow.GetIDs(requests), req.ChunkIDs)
+
+		targetID, ok := args[1].(flow.Identifier)
+		require.True(t, ok)
+		require.Contains(t, agrees, targetID)
+
+		conduitWG.Done()
+	}).Return(nil).Times(len(agrees))
+
+	unittest.RequireCloseBefore(t, e.Ready(), time.Second, "could not start engine on time")
+
+	unittest.RequireReturnsBefore(t, qualifyWG.Wait, time.Duration(2)*s.retryInterval, "could not check chunk requests qualification on time")
+	unittest.RequireReturnsBefore(t, conduitWG.Wait, time.Duration(2)*s.retryInterval, "could not request chunks from network")
+
+	unittest.RequireCloseBefore(t, e.Done(), time.Second, "could not stop engine on time")
+	testifymock.AssertExpectationsForObjects(t, s.pendingRequests, s.con, s.metrics)
+}
+
 // chunkToCollectionIdMap is a test helper that extracts a chunkID -> collectionID map from chunk data responses.
 func chunkToCollectionIdMap(t *testing.T, responses []*messages.ChunkDataResponse) map[flow.Identifier]flow.Identifier {
 	chunkCollectionMap := make(map[flow.Identifier]flow.Identifier)
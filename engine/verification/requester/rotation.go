@@ -0,0 +1,74 @@
+package requester
+
+import (
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/verification"
+)
+
+// targetRotation keeps track of the execution nodes that have been asked for a chunk data pack, so that
+// each attempt of requesting the chunk is dispatched to execution nodes that have not been asked yet. A
+// request is only retried if the previous attempt did not get a response, hence the nodes asked before
+// are the ones that failed to respond. Once all eligible execution nodes have been asked, the rotation
+// starts over.
+type targetRotation struct {
+	sync.Mutex
+	asked map[flow.Identifier]flow.IdentifierList // execution nodes asked for the chunk data pack, by chunk ID.
+}
+
+func newTargetRotation() *targetRotation {
+	return &targetRotation{
+		asked: make(map[flow.Identifier]flow.IdentifierList),
+	}
+}
+
+// next returns the execution nodes to ask for the chunk data pack of the request at this attempt, and
+// records them as asked.
+func (r *targetRotation) next(request *verification.ChunkDataPackRequest, count int) flow.IdentifierList {
+	r.Lock()
+	defer r.Unlock()
+
+	asked := r.asked[request.ChunkID]
+	targets := request.SampleTargetsExcluding(count, asked)
+
+	eligible := request.Targets.Filter(filter.Not(filter.HasNodeID(request.Disagrees...)))
+	for _, targetID := range targets {
+		if !asked.Contains(targetID) {
+			asked = append(asked, targetID)
+		}
+	}
+	if len(eligible.Filter(filter.Not(filter.HasNodeID(asked...)))) == 0 {
+		// every eligible execution node has been asked, the next round only excludes the current targets.
+		asked = targets.Copy()
+	}
+	r.asked[request.ChunkID] = asked
+
+	return targets
+}
+
+// forget drops the execution nodes asked for the chunk data pack of the chunk.
+func (r *targetRotation) forget(chunkID flow.Identifier) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.asked, chunkID)
+}
+
+// prune drops the execution nodes asked for the chunk data packs of all chunks that are not pending anymore,
+// e.g., because their requests were ejected from the mempool.
+func (r *targetRotation) prune(pending verification.ChunkDataPackRequestList) {
+	r.Lock()
+	defer r.Unlock()
+
+	lookup := make(map[flow.Identifier]struct{}, len(pending))
+	for _, request := range pending {
+		lookup[request.ChunkID] = struct{}{}
+	}
+	for chunkID := range r.asked {
+		if _, ok := lookup[chunkID]; !ok {
+			delete(r.asked, chunkID)
+		}
+	}
+}
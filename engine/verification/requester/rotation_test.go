@@ -0,0 +1,77 @@
+package requester
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestTargetRotation_Agrees evaluates that the rotation asks execution nodes agreeing with the result of the chunk
+// that have not been asked before, and starts over once all of them have been asked.
+func TestTargetRotation_Agrees(t *testing.T) {
+	agrees := unittest.IdentifierListFixture(4)
+	disagrees := unittest.IdentifierListFixture(2)
+	request := unittest.ChunkDataPackRequestFixture(unittest.IdentifierFixture(),
+		unittest.WithAgrees(agrees),
+		unittest.WithDisagrees(disagrees))
+	rotation := newTargetRotation()
+
+	first := rotation.next(request, 2)
+	second := rotation.next(request, 2)
+	require.Len(t, first, 2)
+	require.Len(t, second, 2)
+	require.ElementsMatch(t, agrees, append(first.Copy(), second...))
+
+	// all agrees have been asked, hence the rotation starts over excluding the last targets
+	third := rotation.next(request, 2)
+	require.ElementsMatch(t, first, third)
+}
+
+// TestTargetRotation_Backups evaluates that the rotation always asks the only execution node agreeing with the result
+// of the chunk, and rotates the backup targets among the execution nodes that have not sent a receipt, while never
+// asking the ones disagreeing with the result.
+func TestTargetRotation_Backups(t *testing.T) {
+	agrees := unittest.IdentifierListFixture(1)
+	disagrees := unittest.IdentifierListFixture(2)
+	request := unittest.ChunkDataPackRequestFixture(unittest.IdentifierFixture(),
+		unittest.WithAgrees(agrees),
+		unittest.WithDisagrees(disagrees))
+	others := unittest.IdentityListFixture(3, unittest.WithRole(flow.RoleExecution))
+	request.Targets = append(request.Targets, others...)
+	rotation := newTargetRotation()
+
+	backups := flow.IdentifierList{}
+	for i := 0; i < len(others); i++ {
+		targets := rotation.next(request, 2)
+		require.Len(t, targets, 2)
+		require.Contains(t, targets, agrees[0])
+		for _, targetID := range targets {
+			require.NotContains(t, disagrees, targetID)
+			if targetID != agrees[0] {
+				require.NotContains(t, backups, targetID)
+				backups = append(backups, targetID)
+			}
+		}
+	}
+	require.ElementsMatch(t, others.NodeIDs(), backups)
+}
+
+// TestTargetRotation_Prune evaluates that pruning drops the asked execution nodes of chunks that are no longer pending.
+func TestTargetRotation_Prune(t *testing.T) {
+	requests := unittest.ChunkDataPackRequestListFixture(2, unittest.WithAgrees(unittest.IdentifierListFixture(2)))
+	rotation := newTargetRotation()
+	for _, request := range requests {
+		rotation.next(request, 1)
+	}
+
+	rotation.prune(verification.ChunkDataPackRequestList{requests[0]})
+	require.Contains(t, rotation.asked, requests[0].ChunkID)
+	require.NotContains(t, rotation.asked, requests[1].ChunkID)
+
+	rotation.forget(requests[0].ChunkID)
+	require.Empty(t, rotation.asked)
+}
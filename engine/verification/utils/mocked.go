@@ -32,3 +32,27 @@ func (m *MockAssigner) Assign(result *flow.ExecutionResult, blockID flow.Identif
 
 	return a, nil
 }
+
+// Explain returns the assignment of the input chunks to the verifier node, without any source of randomness
+func (m *MockAssigner) Explain(result *flow.ExecutionResult, blockID flow.Identifier) (*chmodel.AssignmentExplanation, error) {
+	a, err := m.Assign(result, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &chmodel.AssignmentExplanation{
+		ResultID:  result.ID(),
+		BlockID:   blockID,
+		Alpha:     1,
+		Verifiers: flow.IdentifierList{m.me},
+	}
+	for _, c := range result.Chunks {
+		explanation.Chunks = append(explanation.Chunks, chmodel.ChunkVerifiers{
+			ChunkIndex: c.Index,
+			ChunkID:    c.ID(),
+			Verifiers:  a.Verifiers(c),
+		})
+	}
+
+	return explanation, nil
+}
@@ -208,6 +208,7 @@ func ExecutionResultFixture(t *testing.T, chunkCount int, chain flow.Chain, refB
 	spockSecrets := make([][]byte, 0)
 	chunks := make([]*flow.Chunk, 0)
 	chunkDataPacks := make([]*flow.ChunkDataPack, 0)
+	serviceEvents := make([]flow.ServiceEvent, 0)
 
 	var payload flow.Payload
 	var referenceBlock flow.Block
@@ -246,7 +247,7 @@ func ExecutionResultFixture(t *testing.T, chunkCount int, chain flow.Chain, refB
 		programs := programs.NewEmptyPrograms()
 
 		// create BlockComputer
-		bc, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), log, committer)
+		bc, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), log, committer, unittest.ProtocolStateAtEpoch(0))
 		require.NoError(t, err)
 
 		completeColls := make(map[flow.Identifier]*entity.CompleteCollection)
@@ -287,6 +288,13 @@ func ExecutionResultFixture(t *testing.T, chunkCount int, chain flow.Chain, refB
 		computationResult, err := bc.ExecuteBlock(context.Background(), executableBlock, view, programs)
 		require.NoError(t, err)
 
+		// the service events are claimed by the result the same way as by execution nodes
+		for _, event := range computationResult.ServiceEvents {
+			converted, err := flow.ConvertServiceEvent(event)
+			require.NoError(t, err)
+			serviceEvents = append(serviceEvents, *converted)
+		}
+
 		for i, stateSnapshot := range computationResult.StateSnapshots {
 
 			ids, values := view.Delta().RegisterUpdates()
@@ -313,17 +321,12 @@ func ExecutionResultFixture(t *testing.T, chunkCount int, chain flow.Chain, refB
 
 			chunk := &flow.Chunk{
 				ChunkBody: flow.ChunkBody{
-					CollectionIndex: uint(i),
-					StartState:      startStateCommitment,
-					// TODO: include real, event collection hash, currently using the collection ID to generate a different Chunk ID
-					// Otherwise, the chances of there being chunks with the same ID before all these TODOs are done is large, since
-					// startState stays the same if blocks are empty
-					EventCollection: collectionID,
-					BlockID:         executableBlock.ID(),
-					// TODO: record gas used
-					TotalComputationUsed: 0,
-					// TODO: record number of txs
-					NumberOfTransactions: 0,
+					CollectionIndex:      uint(i),
+					StartState:           startStateCommitment,
+					EventCollection:      computationResult.EventsHashes[i],
+					BlockID:              executableBlock.ID(),
+					TotalComputationUsed: computationResult.ComputationUsed[i],
+					NumberOfTransactions: computationResult.TransactionCounts[i],
 				},
 				Index:    uint64(i),
 				EndState: flow.StateCommitment(endStateCommitment),
@@ -362,8 +365,9 @@ func ExecutionResultFixture(t *testing.T, chunkCount int, chain flow.Chain, refB
 	}
 
 	result := &flow.ExecutionResult{
-		BlockID: blockID,
-		Chunks:  chunks,
+		BlockID:       blockID,
+		Chunks:        chunks,
+		ServiceEvents: serviceEvents,
 	}
 
 	return result, &ExecutionReceiptData{
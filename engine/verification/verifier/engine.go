@@ -181,11 +181,12 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 	span, _ := e.tracer.StartSpanFromContext(ctx, trace.VERVerChunkVerify)
 
 	var spockSecret []byte
+	var report *chmodels.ChunkVerificationReport
 	var chFault chmodels.ChunkFault
 	if vc.IsSystemChunk {
-		spockSecret, chFault, err = e.chVerif.SystemChunkVerify(vc)
+		spockSecret, report, chFault, err = e.chVerif.SystemChunkVerify(vc)
 	} else {
-		spockSecret, chFault, err = e.chVerif.Verify(vc)
+		spockSecret, report, chFault, err = e.chVerif.Verify(vc)
 	}
 	span.Finish()
 	// Any err means that something went wrong when verify the chunk
@@ -194,6 +195,13 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 		return fmt.Errorf("cannot verify chunk: %w", err)
 	}
 
+	// the report is missing if the transactions of the chunk could not be executed
+	if report != nil {
+		e.logReport(log, report)
+		e.metrics.OnChunkVerifiedAtVerifier(len(report.Transactions), report.FailedTransactions(),
+			report.ComputationUsed(), report.Duration)
+	}
+
 	// if any fault found with the chunk
 	if chFault != nil {
 		switch chFault.(type) {
@@ -205,6 +213,15 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 		case *chmodels.CFInvalidVerifiableChunk:
 			// TODO raise challenge
 			e.log.Error().Msg(chFault.String())
+		case *chmodels.CFInvalidEventsCollection:
+			// TODO raise challenge
+			e.log.Warn().Msg(chFault.String())
+		case *chmodels.CFInvalidServiceEvents:
+			// TODO raise challenge
+			e.log.Warn().Msg(chFault.String())
+		case *chmodels.CFInvalidChunkMetadata:
+			// TODO raise challenge
+			e.log.Warn().Msg(chFault.String())
 		default:
 			return engine.NewInvalidInputErrorf("unknown type of chunk fault is received (type: %T) : %v",
 				chFault, chFault.String())
@@ -253,6 +270,31 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 	return nil
 }
 
+// logReport logs the outcome of executing the transactions of a chunk, with the details of
+// each transaction at debug level.
+func (e *Engine) logReport(log zerolog.Logger, report *chmodels.ChunkVerificationReport) {
+	log.Info().
+		Int("transactions", len(report.Transactions)).
+		Int("failed_transactions", report.FailedTransactions()).
+		Uint64("computation_used", report.ComputationUsed()).
+		Int("register_touches", report.RegisterTouches()).
+		Dur("duration", report.Duration).
+		Msg("transactions of chunk executed")
+
+	for i, tx := range report.Transactions {
+		log.Debug().
+			Int("tx_index", i).
+			Hex("tx_id", logging.ID(tx.TransactionID)).
+			Bool("failed", tx.Failed).
+			Str("error", tx.ErrorMessage).
+			Uint64("computation_used", tx.ComputationUsed).
+			Int("register_touches", tx.RegisterTouches).
+			Int("register_updates", tx.RegisterUpdates).
+			Dur("duration", tx.Duration).
+			Msg("transaction of chunk executed")
+	}
+}
+
 // GenerateResultApproval generates result approval for specific chunk of an execution receipt.
 func (e *Engine) GenerateResultApproval(chunkIndex uint64,
 	execResultID flow.Identifier,
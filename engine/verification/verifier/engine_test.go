@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -154,6 +155,8 @@ func (suite *VerifierEngineTestSuite) TestVerifyHappyPath() {
 	// mocks metrics
 	// reception of verifiable chunk
 	suite.metrics.On("OnVerifiableChunkReceivedAtVerifierEngine").Return()
+	// execution of the transactions of the chunk, as reported by the chunk verifier
+	suite.metrics.On("OnChunkVerifiedAtVerifier", 2, 1, uint64(15), time.Second).Return().Once()
 	// emission of result approval
 	suite.metrics.On("OnResultApprovalDispatchedInNetwork").Return()
 
@@ -181,6 +184,7 @@ func (suite *VerifierEngineTestSuite) TestVerifyHappyPath() {
 	suite.Assert().NoError(err)
 	suite.ss.AssertExpectations(suite.T())
 	suite.pushCon.AssertExpectations(suite.T())
+	suite.metrics.AssertExpectations(suite.T())
 
 }
 
@@ -224,30 +228,39 @@ func (suite *VerifierEngineTestSuite) TestVerifyUnhappyPaths() {
 type ChunkVerifierMock struct {
 }
 
-func (v ChunkVerifierMock) Verify(vc *verification.VerifiableChunkData) ([]byte, chmodel.ChunkFault, error) {
+func (v ChunkVerifierMock) Verify(vc *verification.VerifiableChunkData) ([]byte, *chmodel.ChunkVerificationReport, chmodel.ChunkFault, error) {
 	if vc.IsSystemChunk {
-		return nil, nil, fmt.Errorf("wrong method invoked for verifying system chunk")
+		return nil, nil, nil, fmt.Errorf("wrong method invoked for verifying system chunk")
 	}
 
 	switch vc.Chunk.Index {
 	case 0:
-		return []byte{}, nil, nil
+		report := &chmodel.ChunkVerificationReport{
+			ChunkIndex: vc.Chunk.Index,
+			ResultID:   vc.Result.ID(),
+			Transactions: []chmodel.TransactionReport{
+				{TransactionID: unittest.IdentifierFixture(), ComputationUsed: 10, RegisterTouches: 3, RegisterUpdates: 1},
+				{TransactionID: unittest.IdentifierFixture(), ComputationUsed: 5, Failed: true, ErrorMessage: "test failure"},
+			},
+			Duration: time.Second,
+		}
+		return []byte{}, report, nil, nil
 	// return error
 	case 1:
-		return nil, chmodel.NewCFMissingRegisterTouch(
+		return nil, nil, chmodel.NewCFMissingRegisterTouch(
 			[]string{"test missing register touch"},
 			vc.Chunk.Index,
 			vc.Result.ID()), nil
 
 	case 2:
-		return nil, chmodel.NewCFInvalidVerifiableChunk(
+		return nil, nil, chmodel.NewCFInvalidVerifiableChunk(
 			"test",
 			errors.New("test invalid verifiable chunk"),
 			vc.Chunk.Index,
 			vc.Result.ID()), nil
 
 	case 3:
-		return nil, chmodel.NewCFNonMatchingFinalState(
+		return nil, nil, chmodel.NewCFNonMatchingFinalState(
 			unittest.StateCommitmentFixture(),
 			unittest.StateCommitmentFixture(),
 			vc.Chunk.Index,
@@ -256,14 +269,14 @@ func (v ChunkVerifierMock) Verify(vc *verification.VerifiableChunkData) ([]byte,
 	// TODO add cases for challenges
 	// return successful by default
 	default:
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 
 }
 
-func (v ChunkVerifierMock) SystemChunkVerify(vc *verification.VerifiableChunkData) ([]byte, chmodel.ChunkFault, error) {
+func (v ChunkVerifierMock) SystemChunkVerify(vc *verification.VerifiableChunkData) ([]byte, *chmodel.ChunkVerificationReport, chmodel.ChunkFault, error) {
 	if !vc.IsSystemChunk {
-		return nil, nil, fmt.Errorf("wrong method invoked for verifying non-system chunk")
+		return nil, nil, nil, fmt.Errorf("wrong method invoked for verifying non-system chunk")
 	}
-	return nil, nil, nil
+	return nil, nil, nil, nil
 }
@@ -0,0 +1,182 @@
+package fvm
+
+import (
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+
+	"github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/state"
+)
+
+// accountKeyValueDeclarations declares the functions giving transactions access to the account key
+// operations which the AuthAccount.keys type of Cadence doesn't provide:
+//
+//	getAccountKeyCount(_ account: AuthAccount): UInt64
+//	getAccountKeyCreationHeight(_ account: AuthAccount, _ keyIndex: Int): UInt64?
+//	getAccountKeyLabel(_ account: AuthAccount, _ keyIndex: Int): String?
+//	setAccountKeyLabel(_ account: AuthAccount, _ keyIndex: Int, _ label: String)
+//
+// The functions take an AuthAccount rather than an address, so that only the signers of a transaction
+// can use them on their accounts. The key count only includes keys which are not revoked, and the
+// creation height and label are nil if there is no key at the given index.
+func accountKeyValueDeclarations(env *hostEnv) []runtime.ValueDeclaration {
+
+	accountParameter := &sema.Parameter{
+		Label:          sema.ArgumentLabelNotRequired,
+		Identifier:     "account",
+		TypeAnnotation: sema.NewTypeAnnotation(sema.AuthAccountType),
+	}
+	keyIndexParameter := &sema.Parameter{
+		Label:          sema.ArgumentLabelNotRequired,
+		Identifier:     "keyIndex",
+		TypeAnnotation: sema.NewTypeAnnotation(sema.IntType),
+	}
+
+	getAccountKeyCount := accountKeyFunctionDeclaration(
+		"getAccountKeyCount",
+		[]*sema.Parameter{accountParameter},
+		sema.UInt64Type,
+		func(invocation interpreter.Invocation) interpreter.Value {
+			address := authAccountAddress("getAccountKeyCount", invocation.Arguments[0])
+
+			count, err := env.GetActiveAccountKeyCount(address)
+			if err != nil {
+				panic(err)
+			}
+
+			return interpreter.UInt64Value(count)
+		},
+	)
+
+	getAccountKeyCreationHeight := accountKeyFunctionDeclaration(
+		"getAccountKeyCreationHeight",
+		[]*sema.Parameter{accountParameter, keyIndexParameter},
+		&sema.OptionalType{Type: sema.UInt64Type},
+		func(invocation interpreter.Invocation) interpreter.Value {
+			metadata := accountKeyMetadata(env, "getAccountKeyCreationHeight", invocation)
+			if metadata == nil {
+				return interpreter.NilValue{}
+			}
+
+			return interpreter.NewSomeValueOwningNonCopying(interpreter.UInt64Value(metadata.CreationHeight))
+		},
+	)
+
+	getAccountKeyLabel := accountKeyFunctionDeclaration(
+		"getAccountKeyLabel",
+		[]*sema.Parameter{accountParameter, keyIndexParameter},
+		&sema.OptionalType{Type: sema.StringType},
+		func(invocation interpreter.Invocation) interpreter.Value {
+			metadata := accountKeyMetadata(env, "getAccountKeyLabel", invocation)
+			if metadata == nil {
+				return interpreter.NilValue{}
+			}
+
+			return interpreter.NewSomeValueOwningNonCopying(interpreter.NewStringValue(metadata.Label))
+		},
+	)
+
+	setAccountKeyLabel := accountKeyFunctionDeclaration(
+		"setAccountKeyLabel",
+		[]*sema.Parameter{
+			accountParameter,
+			keyIndexParameter,
+			{
+				Label:          sema.ArgumentLabelNotRequired,
+				Identifier:     "label",
+				TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+			},
+		},
+		sema.VoidType,
+		func(invocation interpreter.Invocation) interpreter.Value {
+			address := authAccountAddress("setAccountKeyLabel", invocation.Arguments[0])
+			keyIndex := accountKeyIndex("setAccountKeyLabel", invocation.Arguments[1])
+
+			label, ok := invocation.Arguments[2].(*interpreter.StringValue)
+			if !ok {
+				panic(errors.NewValueErrorf(invocation.Arguments[2].String(interpreter.StringResults{}),
+					"third argument of setAccountKeyLabel must be a string"))
+			}
+
+			// keep the creation height of the key
+			metadata, err := env.GetAccountKeyMetadata(address, keyIndex)
+			if err != nil {
+				panic(err)
+			}
+			if metadata == nil {
+				metadata = &state.AccountPublicKeyMetadata{}
+			}
+			metadata.Label = label.Str
+
+			err = env.SetAccountKeyMetadata(address, keyIndex, *metadata)
+			if err != nil {
+				panic(err)
+			}
+
+			return interpreter.VoidValue{}
+		},
+	)
+
+	return []runtime.ValueDeclaration{
+		getAccountKeyCount,
+		getAccountKeyCreationHeight,
+		getAccountKeyLabel,
+		setAccountKeyLabel,
+	}
+}
+
+func accountKeyFunctionDeclaration(
+	name string,
+	parameters []*sema.Parameter,
+	returnType sema.Type,
+	function interpreter.HostFunction,
+) runtime.ValueDeclaration {
+	return runtime.ValueDeclaration{
+		Name: name,
+		Type: &sema.FunctionType{
+			Parameters:           parameters,
+			ReturnTypeAnnotation: sema.NewTypeAnnotation(returnType),
+		},
+		Kind:           common.DeclarationKindFunction,
+		IsConstant:     true,
+		ArgumentLabels: nil,
+		Value:          interpreter.NewHostFunctionValue(function),
+	}
+}
+
+// accountKeyMetadata returns the metadata of the key of the account given as the first argument of the
+// invocation, at the index given as the second argument. It returns nil if there is no such key.
+func accountKeyMetadata(env *hostEnv, function string, invocation interpreter.Invocation) *state.AccountPublicKeyMetadata {
+	address := authAccountAddress(function, invocation.Arguments[0])
+	keyIndex := accountKeyIndex(function, invocation.Arguments[1])
+
+	metadata, err := env.GetAccountKeyMetadata(address, keyIndex)
+	if err != nil {
+		panic(err)
+	}
+
+	return metadata
+}
+
+func authAccountAddress(function string, value interpreter.Value) runtime.Address {
+	if account, ok := value.(*interpreter.CompositeValue); ok {
+		if address, ok := account.GetField(sema.AuthAccountAddressField).(interpreter.AddressValue); ok {
+			return runtime.Address(address)
+		}
+	}
+
+	panic(errors.NewValueErrorf(value.String(interpreter.StringResults{}),
+		"first argument of %s must be an account", function))
+}
+
+func accountKeyIndex(function string, value interpreter.Value) int {
+	keyIndex, ok := value.(interpreter.IntValue)
+	if !ok {
+		panic(errors.NewValueErrorf(value.String(interpreter.StringResults{}),
+			"second argument of %s must be a key index", function))
+	}
+
+	return keyIndex.ToInt()
+}
@@ -1204,6 +1204,100 @@ func TestGetAccountKey(t *testing.T) {
 	)
 }
 
+const accountKeyMetadataTransaction = `
+transaction(keyIndex: Int) {
+  prepare(signer: AuthAccount) {
+    setAccountKeyLabel(signer, keyIndex, "rotated")
+    log(getAccountKeyCount(signer))
+    log(getAccountKeyCreationHeight(signer, keyIndex))
+    log(getAccountKeyLabel(signer, keyIndex))
+    log(getAccountKeyLabel(signer, keyIndex + 1))
+  }
+}
+`
+
+func TestAccountKeyMetadata(t *testing.T) {
+
+	header := unittest.BlockHeaderFixture()
+	header.Height = 42
+
+	options := []fvm.Option{
+		fvm.WithRestrictedAccountCreation(false),
+		fvm.WithTransactionProcessors(fvm.NewTransactionInvocator(zerolog.Nop())),
+		fvm.WithCadenceLogging(true),
+		fvm.WithAccountKeyMetadataEnabled(true),
+		fvm.WithBlockHeader(&header),
+	}
+
+	t.Run("Metadata of added keys",
+		newVMTest().withContextOptions(options...).
+			run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, view state.View, programs *programs.Programs) {
+				address := createAccount(t, vm, chain, ctx, view, programs)
+
+				const keyCount = 2
+				const keyIndex = keyCount - 1
+
+				for i := 0; i < keyCount; i++ {
+					_ = addAccountKey(t, vm, ctx, view, programs, address, accountKeyAPIVersionV2)
+				}
+
+				keyIndexArg, err := jsoncdc.Encode(cadence.NewInt(keyIndex))
+				require.NoError(t, err)
+
+				// revoke the first key, which isn't counted anymore
+				revokeIndexArg, err := jsoncdc.Encode(cadence.NewInt(0))
+				require.NoError(t, err)
+
+				txBody := flow.NewTransactionBody().
+					SetScript([]byte(revokeAccountKeyTransaction)).
+					AddArgument(revokeIndexArg).
+					AddAuthorizer(address)
+
+				tx := fvm.Transaction(txBody, 0)
+
+				err = vm.Run(ctx, tx, view, programs)
+				require.NoError(t, err)
+				require.NoError(t, tx.Err)
+
+				txBody = flow.NewTransactionBody().
+					SetScript([]byte(accountKeyMetadataTransaction)).
+					AddArgument(keyIndexArg).
+					AddAuthorizer(address)
+
+				tx = fvm.Transaction(txBody, 1)
+
+				err = vm.Run(ctx, tx, view, programs)
+				require.NoError(t, err)
+				require.NoError(t, tx.Err)
+
+				require.Equal(t, []string{"1", "42", `"rotated"`, "nil"}, tx.Logs)
+			}),
+	)
+
+	t.Run("Functions are not declared when disabled",
+		newVMTest().withContextOptions(options...).
+			run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, view state.View, programs *programs.Programs) {
+				address := createAccount(t, vm, chain, ctx, view, programs)
+				_ = addAccountKey(t, vm, ctx, view, programs, address, accountKeyAPIVersionV2)
+
+				keyIndexArg, err := jsoncdc.Encode(cadence.NewInt(0))
+				require.NoError(t, err)
+
+				txBody := flow.NewTransactionBody().
+					SetScript([]byte(accountKeyMetadataTransaction)).
+					AddArgument(keyIndexArg).
+					AddAuthorizer(address)
+
+				tx := fvm.Transaction(txBody, 0)
+
+				disabledCtx := fvm.NewContextFromParent(ctx, fvm.WithAccountKeyMetadataEnabled(false))
+				err = vm.Run(disabledCtx, tx, view, programs)
+				require.NoError(t, err)
+				require.Error(t, tx.Err)
+			}),
+	)
+}
+
 func TestAccountBalanceFields(t *testing.T) {
 	t.Run("Get balance works",
 		newVMTest().withContextOptions(
@@ -6,12 +6,14 @@ import (
 
 	"github.com/onflow/cadence"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-core-contracts/lib/go/contracts"
 
 	"github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/fvm/programs"
 	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/fvm/utils"
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -33,6 +35,24 @@ type BootstrapProcedure struct {
 	transactionFee            cadence.UFix64
 	minimumStorageReservation cadence.UFix64
 	storagePerFlow            cadence.UFix64
+
+	// the accounts created by the last run
+	report *BootstrapReport
+}
+
+// BootstrapReport lists the accounts created by a bootstrap procedure, together with the
+// contracts deployed to them. It is meant to be consumed by tools such as SDKs and emulators,
+// so that they don't have to hardcode the addresses of the system accounts.
+type BootstrapReport struct {
+	Chain    flow.ChainID             `json:"chain"`
+	Accounts []BootstrapReportAccount `json:"accounts"`
+}
+
+// BootstrapReportAccount is an account created by a bootstrap procedure.
+type BootstrapReportAccount struct {
+	Name      string       `json:"name"`
+	Address   flow.Address `json:"address"`
+	Contracts []string     `json:"contracts"`
 }
 
 type BootstrapProcedureOption func(*BootstrapProcedure) *BootstrapProcedure
@@ -128,6 +148,9 @@ func (b *BootstrapProcedure) Run(vm *VirtualMachine, ctx Context, sth *state.Sta
 	b.ctx = NewContextFromParent(ctx, WithRestrictedDeployment(false))
 	b.sth = sth
 	b.programs = programs
+	b.report = &BootstrapReport{
+		Chain: flow.ChainID(ctx.Chain.String()),
+	}
 
 	// initialize the account addressing state
 	b.accounts = state.NewAccounts(b.sth)
@@ -135,10 +158,14 @@ func (b *BootstrapProcedure) Run(vm *VirtualMachine, ctx Context, sth *state.Sta
 	b.addressGenerator = addressGenerator
 
 	service := b.createServiceAccount(b.serviceAccountPublicKey)
+	b.reportAccount("ServiceAccount", service, "FlowStorageFees", "FlowServiceAccount")
 
 	fungibleToken := b.deployFungibleToken()
+	b.reportAccount("FungibleToken", fungibleToken, "FungibleToken")
 	flowToken := b.deployFlowToken(service, fungibleToken)
+	b.reportAccount("FlowToken", flowToken, "FlowToken")
 	feeContract := b.deployFlowFees(service, fungibleToken, flowToken)
+	b.reportAccount("FlowFees", feeContract, "FlowFees")
 	b.deployStorageFees(service, fungibleToken, flowToken)
 
 	if b.initialTokenSupply > 0 {
@@ -152,6 +179,39 @@ func (b *BootstrapProcedure) Run(vm *VirtualMachine, ctx Context, sth *state.Sta
 	return nil
 }
 
+// Report returns the accounts created by the last run of the procedure, or nil if it
+// hasn't run yet.
+func (b *BootstrapProcedure) Report() *BootstrapReport {
+	return b.report
+}
+
+func (b *BootstrapProcedure) reportAccount(name string, address flow.Address, contracts ...string) {
+	b.report.Accounts = append(b.report.Accounts, BootstrapReportAccount{
+		Name:      name,
+		Address:   address,
+		Contracts: contracts,
+	})
+}
+
+// DryRunBootstrap runs the bootstrap procedure for the given chain against an empty
+// in-memory view and returns the report of the created accounts. No ledger is touched.
+func DryRunBootstrap(
+	chain flow.Chain,
+	serviceAccountPublicKey flow.AccountPublicKey,
+	opts ...BootstrapProcedureOption,
+) (*BootstrapReport, error) {
+	vm := NewVirtualMachine(NewInterpreterRuntime())
+	ctx := NewContext(zerolog.Nop(), WithChain(chain))
+	bootstrap := Bootstrap(serviceAccountPublicKey, opts...)
+
+	err := vm.Run(ctx, bootstrap, utils.NewSimpleView(), programs.NewEmptyPrograms())
+	if err != nil {
+		return nil, fmt.Errorf("could not run bootstrap procedure: %w", err)
+	}
+
+	return bootstrap.Report(), nil
+}
+
 func (b *BootstrapProcedure) createAccount() flow.Address {
 	address, err := b.addressGenerator.NextAddress()
 	if err != nil {
@@ -0,0 +1,36 @@
+package fvm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestDryRunBootstrap(t *testing.T) {
+	for _, chainID := range flow.ChainIDs() {
+		chainID := chainID
+		t.Run(chainID.String(), func(t *testing.T) {
+			chain := chainID.Chain()
+
+			report, err := fvm.DryRunBootstrap(chain, unittest.ServiceAccountPublicKey)
+			require.NoError(t, err)
+			assert.Equal(t, chainID, report.Chain)
+
+			addresses := make(map[string]flow.Address)
+			for _, account := range report.Accounts {
+				addresses[account.Name] = account.Address
+			}
+			assert.Equal(t, map[string]flow.Address{
+				"ServiceAccount": chain.ServiceAddress(),
+				"FungibleToken":  fvm.FungibleTokenAddress(chain),
+				"FlowToken":      fvm.FlowTokenAddress(chain),
+				"FlowFees":       fvm.FlowFeesAddress(chain),
+			}, addresses)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package fvm
 
 import (
+	"io"
+
+	"github.com/onflow/cadence/runtime"
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/fvm/crypto"
@@ -20,9 +23,13 @@ type Context struct {
 	MaxStateKeySize                  uint64
 	MaxStateValueSize                uint64
 	MaxStateInteractionSize          uint64
+	MaxRegisterReads                 uint64
+	MaxRegisterWrites                uint64
 	EventCollectionByteSizeLimit     uint64
 	MaxNumOfTxRetries                uint8
 	BlockHeader                      *flow.Header
+	BlockRandomSource                []byte
+	TransactionRandomSourceKMAC      bool
 	ServiceAccountEnabled            bool
 	RestrictedAccountCreationEnabled bool
 	RestrictedDeploymentEnabled      bool
@@ -32,7 +39,14 @@ type Context struct {
 	EventCollectionEnabled           bool
 	ServiceEventCollectionEnabled    bool
 	AccountFreezeAvailable           bool
+	AccountKeyMetadataEnabled        bool
+	StorageFormatVersioning          bool
 	ExtensiveTracing                 bool
+	DebugReference                   ExecutionReference
+	ExecutionRecorder                *ExecutionRecorder
+	PayerRateLimiter                 PayerRateLimiter
+	CoverageReport                   *runtime.CoverageReport
+	RuntimeProvider                  RuntimeProvider
 	SignatureVerifier                crypto.SignatureVerifier
 	TransactionProcessors            []TransactionProcessor
 	ScriptProcessors                 []ScriptProcessor
@@ -75,9 +89,13 @@ func defaultContext(logger zerolog.Logger) Context {
 		MaxStateKeySize:                  state.DefaultMaxKeySize,
 		MaxStateValueSize:                state.DefaultMaxValueSize,
 		MaxStateInteractionSize:          state.DefaultMaxInteractionSize,
+		MaxRegisterReads:                 state.DefaultMaxRegisterReads,
+		MaxRegisterWrites:                state.DefaultMaxRegisterWrites,
 		EventCollectionByteSizeLimit:     DefaultEventCollectionByteSizeLimit,
 		MaxNumOfTxRetries:                DefaultMaxNumOfTxRetries,
 		BlockHeader:                      nil,
+		BlockRandomSource:                nil,
+		TransactionRandomSourceKMAC:      false,
 		ServiceAccountEnabled:            true,
 		RestrictedAccountCreationEnabled: true,
 		RestrictedDeploymentEnabled:      true,
@@ -85,7 +103,14 @@ func defaultContext(logger zerolog.Logger) Context {
 		EventCollectionEnabled:           true,
 		ServiceEventCollectionEnabled:    false,
 		AccountFreezeAvailable:           false,
+		AccountKeyMetadataEnabled:        false,
+		StorageFormatVersioning:          false,
 		ExtensiveTracing:                 false,
+		DebugReference:                   nil,
+		ExecutionRecorder:                nil,
+		PayerRateLimiter:                 nil,
+		CoverageReport:                   nil,
+		RuntimeProvider:                  nil,
 		SignatureVerifier:                crypto.NewDefaultSignatureVerifier(),
 		TransactionProcessors: []TransactionProcessor{
 			NewTransactionAccountFrozenChecker(),
@@ -145,6 +170,25 @@ func WithMaxStateInteractionSize(limit uint64) Option {
 	}
 }
 
+// WithMaxRegisterReads sets the limit on the number of distinct registers of a single address read
+// by a transaction.
+func WithMaxRegisterReads(limit uint64) Option {
+	return func(ctx Context) Context {
+		ctx.MaxRegisterReads = limit
+		return ctx
+	}
+}
+
+// WithMaxRegisterWrites sets the limit on the number of distinct registers of a single address written
+// by a transaction. Together with the size limits of registers, it bounds the size of the trie updates
+// of transactions.
+func WithMaxRegisterWrites(limit uint64) Option {
+	return func(ctx Context) Context {
+		ctx.MaxRegisterWrites = limit
+		return ctx
+	}
+}
+
 // WithEventCollectionSizeLimit sets the event collection byte size limit for a virtual machine context.
 func WithEventCollectionSizeLimit(limit uint64) Option {
 	return func(ctx Context) Context {
@@ -155,8 +199,7 @@ func WithEventCollectionSizeLimit(limit uint64) Option {
 
 // WithBlockHeader sets the block header for a virtual machine context.
 //
-// The VM uses the header to provide current block information to the Cadence runtime,
-// as well as to seed the pseudorandom number generator.
+// The VM uses the header to provide current block information to the Cadence runtime.
 func WithBlockHeader(header *flow.Header) Option {
 	return func(ctx Context) Context {
 		ctx.BlockHeader = header
@@ -164,6 +207,32 @@ func WithBlockHeader(header *flow.Header) Option {
 	}
 }
 
+// WithBlockRandomSource sets the source of randomness for a virtual machine context.
+//
+// The source should be derived from the random beacon of the protocol state. The VM uses it,
+// together with the index and ID of each transaction, to seed a pseudorandom number generator
+// per transaction, so that transactions within a block can't predict each other's randomness.
+// Without a random source, the block ID of the block header is used as the source, and without
+// a block header, the Cadence unsafeRandom function is not supported.
+func WithBlockRandomSource(source []byte) Option {
+	return func(ctx Context) Context {
+		ctx.BlockRandomSource = source
+		return ctx
+	}
+}
+
+// WithTransactionRandomSourceKMAC enables or disables deriving the source of randomness of transactions
+// with a KMAC, keyed with the domain separation tag of the transaction random source.
+//
+// Without this option, the source is derived with the legacy SHA3-256 hash, which blocks executed
+// before the activation of the KMAC derivation must be executed with to reproduce their results.
+func WithTransactionRandomSourceKMAC(enabled bool) Option {
+	return func(ctx Context) Context {
+		ctx.TransactionRandomSourceKMAC = enabled
+		return ctx
+	}
+}
+
 // WithAccountFreezeAvailable sets availability of account freeze function for a virtual machine context.
 //
 // With this option set to true, a setAccountFreeze function will be enabled for transactions processed by the VM
@@ -174,6 +243,29 @@ func WithAccountFreezeAvailable(accountFreezeAvailable bool) Option {
 	}
 }
 
+// WithAccountKeyMetadataEnabled enables or disables account key metadata for a virtual machine context.
+//
+// With this option set to true, the height at which a key is added is recorded in the metadata of the key,
+// and transactions can count the active keys of an account and read and label its keys through the
+// functions declared by accountKeyValueDeclarations.
+func WithAccountKeyMetadataEnabled(enabled bool) Option {
+	return func(ctx Context) Context {
+		ctx.AccountKeyMetadataEnabled = enabled
+		return ctx
+	}
+}
+
+// WithStorageFormatVersioning enables or disables recording the storage format version of new accounts.
+//
+// Accounts created without this option have no storage format version, and are considered
+// to be on the legacy storage format.
+func WithStorageFormatVersioning(enabled bool) Option {
+	return func(ctx Context) Context {
+		ctx.StorageFormatVersioning = enabled
+		return ctx
+	}
+}
+
 // WithServiceEventCollectionEnabled enables service event collection
 func WithServiceEventCollectionEnabled() Option {
 	return func(ctx Context) Context {
@@ -190,6 +282,70 @@ func WithExtensiveTracing() Option {
 	}
 }
 
+// WithDebugReference enables the debug mode, in which the register updates of every
+// executed transaction are compared against the reference, and differences are reported.
+//
+// This mode is meant for investigating execution forks and should not be used in production.
+func WithDebugReference(reference ExecutionReference) Option {
+	return func(ctx Context) Context {
+		ctx.DebugReference = reference
+		return ctx
+	}
+}
+
+// WithExecutionRecording enables the recording of executed transactions, in which every
+// transaction is written to w together with the registers it read, so it can be replayed
+// offline with debug.ReplayTransaction.
+//
+// Recorded transactions are run without the programs cache, so the reads of contract code are
+// recorded too, and only transactions which ran without a fatal error are recorded. It is meant
+// for reproducing failures and should not be used in production.
+func WithExecutionRecording(w io.Writer) Option {
+	return func(ctx Context) Context {
+		ctx.ExecutionRecorder = NewExecutionRecorder(w)
+		return ctx
+	}
+}
+
+// WithPayerRateLimiter sets the limiter of transactions per payer and block for a virtual machine context.
+//
+// The limit is checked by the transaction signature verifier for every transaction executed
+// within a block, against the count of transactions of the payer kept in the execution state.
+// Transactions exceeding the limit fail with a PayerRateLimitExceededError.
+func WithPayerRateLimiter(limiter PayerRateLimiter) Option {
+	return func(ctx Context) Context {
+		ctx.PayerRateLimiter = limiter
+		return ctx
+	}
+}
+
+// WithCoverageReport enables the collection of Cadence statement coverage for a virtual machine context.
+//
+// The coverage of all procedures run in the context is aggregated into the given report,
+// which is also exposed on the procedures. The procedures are run on a dedicated runtime
+// collecting the coverage, and as the report isn't safe for concurrent use, they must not be
+// run concurrently. Collecting coverage slows down the execution, so it is meant for testing
+// contracts and should not be used in production.
+func WithCoverageReport(report *runtime.CoverageReport) Option {
+	return func(ctx Context) Context {
+		ctx.CoverageReport = report
+		return ctx
+	}
+}
+
+// WithRuntimeProvider sets the provider of the runtimes procedures are run with in a virtual
+// machine context, overriding the runtime of the virtual machine.
+//
+// This allows swapping in experimental runtimes, like compiled Cadence or instrumentation
+// wrappers, for benchmarking. The runtimes must implement the same semantics as the Cadence
+// interpreter runtime, as the execution results would differ otherwise.
+func WithRuntimeProvider(provider RuntimeProvider) Option {
+	return func(ctx Context) Context {
+		ctx.RuntimeProvider = provider
+		return ctx
+	}
+}
+
 // WithBlocks sets the block storage provider for a virtual machine context.
 //
 // The VM uses the block storage provider to provide historical block information to
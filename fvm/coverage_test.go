@@ -0,0 +1,124 @@
+package fvm_test
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// coverageRecordingRuntime records the coverage reports set on the wrapped runtime.
+type coverageRecordingRuntime struct {
+	runtime.Runtime
+	reports []*runtime.CoverageReport
+}
+
+func (r *coverageRecordingRuntime) SetCoverageReport(report *runtime.CoverageReport) {
+	r.reports = append(r.reports, report)
+	r.Runtime.SetCoverageReport(report)
+}
+
+func TestCoverageReport(t *testing.T) {
+
+	rt := &coverageRecordingRuntime{Runtime: fvm.NewInterpreterRuntime()}
+	chain := flow.Mainnet.Chain()
+	vm := fvm.NewVirtualMachine(rt)
+
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+	ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+	t.Run("script", func(t *testing.T) {
+		report := runtime.NewCoverageReport()
+		coverageCtx := fvm.NewContextFromParent(ctx, fvm.WithCoverageReport(report))
+
+		script := fvm.Script([]byte(`
+			pub fun main(): Int {
+				let a = 1
+				return a + 1
+			}
+		`))
+		err := vm.Run(coverageCtx, script, ledger, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, script.Err)
+
+		require.Same(t, report, script.CoverageReport)
+		require.NotEmpty(t, report.Coverage)
+
+		// the coverage is collected on a dedicated runtime
+		require.Empty(t, rt.reports)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		report := runtime.NewCoverageReport()
+		coverageCtx := fvm.NewContextFromParent(ctx, fvm.WithCoverageReport(report))
+
+		txBody := flow.NewTransactionBody().
+			SetScript([]byte(`transaction { execute { let a = 1 } }`))
+		err := testutil.SignTransactionAsServiceAccount(txBody, 0, chain)
+		require.NoError(t, err)
+
+		tx := fvm.Transaction(txBody, 0)
+		err = vm.Run(coverageCtx, tx, ledger, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+
+		require.Same(t, report, tx.CoverageReport)
+		require.NotEmpty(t, report.Coverage)
+		require.Empty(t, rt.reports)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		script := fvm.Script([]byte(`pub fun main(): Int { return 1 }`))
+		err := vm.Run(ctx, script, ledger, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+
+		require.Nil(t, script.CoverageReport)
+		require.Empty(t, rt.reports)
+	})
+
+	report := runtime.NewCoverageReport()
+
+	// the storage limiter checks the storage capacity of accounts by running nested scripts
+	// in the context of the transaction, which must not block on the coverage collection
+	t.Run("storage limits", newVMTest().
+		withBootstrapProcedureOptions(
+			fvm.WithAccountCreationFee(fvm.DefaultAccountCreationFee),
+			fvm.WithMinimumStorageReservation(fvm.DefaultMinimumStorageReservation),
+			fvm.WithStorageMBPerFLOW(fvm.DefaultStorageMBPerFLOW),
+		).
+		withContextOptions(
+			fvm.WithAccountStorageLimit(true),
+			fvm.WithCoverageReport(report),
+		).
+		run(
+			func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, view state.View, programs *programs.Programs) {
+				txBody := flow.NewTransactionBody().
+					SetScript([]byte(`
+						transaction {
+							prepare(signer: AuthAccount) {
+								signer.save(1, to: /storage/coverage)
+							}
+						}
+					`)).
+					AddAuthorizer(chain.ServiceAddress())
+				err := testutil.SignTransactionAsServiceAccount(txBody, 0, chain)
+				require.NoError(t, err)
+
+				tx := fvm.Transaction(txBody, 0)
+				err = vm.Run(ctx, tx, view, programs)
+				require.NoError(t, err)
+				require.NoError(t, tx.Err)
+
+				require.Same(t, report, tx.CoverageReport)
+				require.NotEmpty(t, report.Coverage)
+			},
+		),
+	)
+}
@@ -0,0 +1,111 @@
+package fvm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/fvm/utils"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ExecutionReference provides the register updates of a reference execution, which
+// executed transactions are compared against when the debug mode is enabled.
+type ExecutionReference interface {
+	// View returns the reference view holding the register updates of the given transaction.
+	// It returns false if no reference is available for the transaction.
+	View(txID flow.Identifier) (state.View, bool)
+	// Report is called with the differences found between the execution of the given
+	// transaction and its reference.
+	Report(txID flow.Identifier, report utils.Report)
+}
+
+// ExecutionReferenceSnapshot is an in-memory ExecutionReference, which collects the
+// reports of all transactions that differ from their reference.
+type ExecutionReferenceSnapshot struct {
+	mu      sync.Mutex
+	views   map[flow.Identifier]state.View
+	reports map[flow.Identifier]utils.Report
+}
+
+// NewExecutionReferenceSnapshot creates a new reference snapshot from the given
+// reference views, keyed by transaction ID.
+func NewExecutionReferenceSnapshot(views map[flow.Identifier]state.View) *ExecutionReferenceSnapshot {
+	return &ExecutionReferenceSnapshot{
+		views:   views,
+		reports: make(map[flow.Identifier]utils.Report),
+	}
+}
+
+func (s *ExecutionReferenceSnapshot) View(txID flow.Identifier) (state.View, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	view, ok := s.views[txID]
+	return view, ok
+}
+
+func (s *ExecutionReferenceSnapshot) Report(txID flow.Identifier, report utils.Report) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reports[txID] = report
+}
+
+// Reports returns the reports of all transactions whose execution differed from the reference.
+func (s *ExecutionReferenceSnapshot) Reports() map[flow.Identifier]utils.Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reports := make(map[flow.Identifier]utils.Report, len(s.reports))
+	for txID, report := range s.reports {
+		reports[txID] = report
+	}
+	return reports
+}
+
+// TransactionRecording is a portable record of a transaction execution. It holds all
+// inputs of the execution, including the value of every register it depended on, so
+// the transaction can be re-executed offline against the recorded snapshot.
+type TransactionRecording struct {
+	ChainID           flow.ChainID
+	Transaction       *flow.TransactionBody
+	TxIndex           uint32
+	BlockHeader       *flow.Header
+	BlockRandomSource []byte
+	// Reads contains the registers the execution depended on, with the values they had
+	// before the execution.
+	Reads flow.RegisterEntries
+	// Updates contains the register updates of the execution.
+	Updates flow.RegisterEntries
+	// ErrorMessage is the message of the transaction error, empty if the transaction succeeded.
+	ErrorMessage string
+}
+
+// ExecutionRecorder writes the recordings of executed transactions as a stream of JSON
+// documents, one per transaction. It is safe for concurrent use.
+type ExecutionRecorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewExecutionRecorder creates a new recorder writing to the given writer.
+func NewExecutionRecorder(w io.Writer) *ExecutionRecorder {
+	return &ExecutionRecorder{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Record writes the given recording.
+func (r *ExecutionRecorder) Record(recording *TransactionRecording) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.encoder.Encode(recording)
+	if err != nil {
+		return fmt.Errorf("could not write recording of transaction %x: %w", recording.Transaction.ID(), err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Package debug provides tooling to reproduce transaction executions offline,
+// based on the recordings written by the virtual machine when execution
+// recording is enabled (see fvm.WithExecutionRecording).
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/utils"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ErrRecordingNotFound is returned when no recording exists for a transaction.
+var ErrRecordingNotFound = errors.New("transaction recording not found")
+
+// ReplayResult is the outcome of replaying a recorded transaction.
+type ReplayResult struct {
+	// Procedure is the replayed transaction, holding its events, logs and error.
+	Procedure *fvm.TransactionProcedure
+	// View holds the register updates of the replay.
+	View *delta.View
+	// MissingRegisters contains the registers read by the replay which are not part
+	// of the recording. They are read as empty, so the replay likely diverged.
+	MissingRegisters []flow.RegisterID
+	// Report contains the differences between the register updates of the replay and
+	// the recorded updates.
+	Report utils.Report
+}
+
+// ReadRecordings reads all transaction recordings from the given reader.
+func ReadRecordings(r io.Reader) ([]*fvm.TransactionRecording, error) {
+	var recordings []*fvm.TransactionRecording
+	decoder := json.NewDecoder(r)
+	for {
+		var recording fvm.TransactionRecording
+		err := decoder.Decode(&recording)
+		if errors.Is(err, io.EOF) {
+			return recordings, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode recording: %w", err)
+		}
+		recordings = append(recordings, &recording)
+	}
+}
+
+// ReplayTransaction re-executes the recorded transaction with the given ID against the
+// registers of its recording. The recordings are read from r, typically a file written
+// by a node with execution recording enabled. The given options are applied on top of
+// the chain, block header and random source of the recording, and should match the
+// configuration of the recording node.
+func ReplayTransaction(log zerolog.Logger, r io.Reader, txID flow.Identifier, opts ...fvm.Option) (*ReplayResult, error) {
+	recordings, err := ReadRecordings(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recording := range recordings {
+		if recording.Transaction.ID() == txID {
+			return Replay(log, recording, opts...)
+		}
+	}
+
+	return nil, fmt.Errorf("could not replay transaction %x: %w", txID, ErrRecordingNotFound)
+}
+
+// Replay re-executes the given recording against its recorded registers.
+func Replay(log zerolog.Logger, recording *fvm.TransactionRecording, opts ...fvm.Option) (*ReplayResult, error) {
+	reads := make(map[string]flow.RegisterValue, len(recording.Reads))
+	for _, entry := range recording.Reads {
+		reads[entry.Key.String()] = entry.Value
+	}
+
+	var missing []flow.RegisterID
+	view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		id := flow.NewRegisterID(owner, controller, key)
+		value, ok := reads[id.String()]
+		if !ok {
+			missing = append(missing, id)
+			return nil, nil
+		}
+		return value, nil
+	})
+
+	ctx := fvm.NewContext(log, append([]fvm.Option{
+		fvm.WithChain(recording.ChainID.Chain()),
+		fvm.WithBlockHeader(recording.BlockHeader),
+		fvm.WithBlockRandomSource(recording.BlockRandomSource),
+	}, opts...)...)
+
+	vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+	tx := fvm.Transaction(recording.Transaction, recording.TxIndex)
+	err := vm.Run(ctx, tx, view, programs.NewEmptyPrograms())
+	if err != nil {
+		return nil, fmt.Errorf("could not replay transaction %x: %w", tx.ID, err)
+	}
+
+	recorded := delta.NewView(delta.AlwaysEmptyGetRegisterFunc)
+	for _, entry := range recording.Updates {
+		err = recorded.Set(entry.Key.Owner, entry.Key.Controller, entry.Key.Key, entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply recorded update: %w", err)
+		}
+	}
+
+	return &ReplayResult{
+		Procedure:        tx,
+		View:             view,
+		MissingRegisters: missing,
+		Report:           utils.DiffViews(view, recorded),
+	}, nil
+}
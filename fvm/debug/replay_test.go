@@ -0,0 +1,102 @@
+package debug_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/debug"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReplayTransaction(t *testing.T) {
+
+	rt := fvm.NewInterpreterRuntime()
+	chain := flow.Mainnet.Chain()
+	vm := fvm.NewVirtualMachine(rt)
+
+	txBody := flow.NewTransactionBody().
+		SetScript([]byte(`
+			transaction {
+				prepare(signer: AuthAccount) {
+					signer.save("replay", to: /storage/replay)
+				}
+			}
+		`)).
+		AddAuthorizer(chain.ServiceAddress())
+	err := testutil.SignTransactionAsServiceAccount(txBody, 0, chain)
+	require.NoError(t, err)
+
+	header := unittest.BlockHeaderFixture()
+
+	var recordings bytes.Buffer
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+	ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+	recordingCtx := fvm.NewContextFromParent(ctx,
+		fvm.WithBlockHeader(&header),
+		fvm.WithExecutionRecording(&recordings),
+	)
+	tx := fvm.Transaction(txBody, 0)
+	err = vm.Run(recordingCtx, tx, ledger.NewChild(), programs.NewEmptyPrograms())
+	require.NoError(t, err)
+	require.NoError(t, tx.Err)
+
+	t.Run("recording", func(t *testing.T) {
+		recorded, err := debug.ReadRecordings(bytes.NewReader(recordings.Bytes()))
+		require.NoError(t, err)
+		require.Len(t, recorded, 1)
+
+		recording := recorded[0]
+		require.Equal(t, txBody.ID(), recording.Transaction.ID())
+		require.Equal(t, header.ID(), recording.BlockHeader.ID())
+		require.Equal(t, chain.ChainID(), recording.ChainID)
+		require.NotEmpty(t, recording.Reads)
+		require.NotEmpty(t, recording.Updates)
+		require.Empty(t, recording.ErrorMessage)
+	})
+
+	t.Run("replay matches recording", func(t *testing.T) {
+		result, err := debug.ReplayTransaction(zerolog.Nop(), bytes.NewReader(recordings.Bytes()), txBody.ID())
+		require.NoError(t, err)
+
+		require.NoError(t, result.Procedure.Err)
+		require.Empty(t, result.MissingRegisters)
+		require.True(t, result.Report.IsEmpty(), result.Report.String())
+	})
+
+	t.Run("programs cache is kept", func(t *testing.T) {
+		location := common.AddressLocation{Address: common.Address(chain.ServiceAddress()), Name: "Cached"}
+		cache := programs.NewEmptyPrograms()
+		cache.Set(location, nil, nil)
+
+		tx := fvm.Transaction(txBody, 0)
+		err := vm.Run(recordingCtx, tx, ledger.NewChild(), cache)
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+
+		_, _, cached := cache.Get(location)
+		require.True(t, cached)
+		require.False(t, cache.Cleaned())
+	})
+
+	t.Run("without programs cache", func(t *testing.T) {
+		tx := fvm.Transaction(txBody, 0)
+		err := vm.Run(recordingCtx, tx, ledger.NewChild(), nil)
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		_, err := debug.ReplayTransaction(zerolog.Nop(), bytes.NewReader(recordings.Bytes()), unittest.IdentifierFixture())
+		require.True(t, errors.Is(err, debug.ErrRecordingNotFound))
+	})
+}
@@ -0,0 +1,61 @@
+package fvm_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/fvm/utils"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestDebugReference(t *testing.T) {
+
+	rt := fvm.NewInterpreterRuntime()
+	chain := flow.Mainnet.Chain()
+	vm := fvm.NewVirtualMachine(rt)
+
+	txBody := flow.NewTransactionBody().
+		SetScript([]byte(`transaction { execute {} }`))
+	err := testutil.SignTransactionAsServiceAccount(txBody, 0, chain)
+	require.NoError(t, err)
+
+	t.Run("reports differences to reference", func(t *testing.T) {
+		// an empty reference, as if the transaction didn't update any registers
+		reference := fvm.NewExecutionReferenceSnapshot(map[flow.Identifier]state.View{
+			txBody.ID(): utils.NewSimpleView(),
+		})
+
+		ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain), fvm.WithDebugReference(reference))
+		ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+		tx := fvm.Transaction(txBody, 0)
+		err := vm.Run(ctx, tx, ledger.NewChild(), programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+
+		reports := reference.Reports()
+		require.Contains(t, reports, txBody.ID())
+		report := reports[txBody.ID()]
+		require.NotEmpty(t, report.OnlyInA)
+		require.Empty(t, report.OnlyInB)
+	})
+
+	t.Run("ignores transactions without reference", func(t *testing.T) {
+		reference := fvm.NewExecutionReferenceSnapshot(map[flow.Identifier]state.View{})
+
+		ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain), fvm.WithDebugReference(reference))
+		ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+		tx := fvm.Transaction(txBody, 0)
+		err := vm.Run(ctx, tx, ledger.NewChild(), programs.NewEmptyPrograms())
+		require.NoError(t, err)
+
+		require.Empty(t, reference.Reports())
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/onflow/flow-go/fvm/utils"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/state/protocol/seed"
 	"github.com/onflow/flow-go/storage"
 )
 
@@ -88,22 +89,48 @@ func newEnvironment(ctx Context, vm *VirtualMachine, sth *state.StateHolder, pro
 	)
 	env.contracts = contracts
 
-	if ctx.BlockHeader != nil {
-		env.seedRNG(ctx.BlockHeader)
-	}
-
 	return env
 }
 
-func (e *hostEnv) seedRNG(header *flow.Header) {
-	// Seed the random number generator with entropy created from the block header ID. The random number generator will
-	// be used by the UnsafeRandom function.
-	id := header.ID()
-	source := rand.NewSource(int64(binary.BigEndian.Uint64(id[:])))
+// seedRNG seeds the random number generator used by the UnsafeRandom function.
+//
+// The seed is derived from the block random source, the transaction index and the transaction ID,
+// so that every transaction in a block draws from its own deterministic sequence. The derivation
+// uses the KMAC of the seed package if the context enables it, and the legacy SHA3-256 hash otherwise.
+// If no block random source is available, the block ID is used as the source instead, and
+// without a block header, the generator is not seeded.
+func (e *hostEnv) seedRNG(txIndex uint32, txID flow.Identifier) error {
+	e.rng = nil
+
+	randomSource := e.ctx.BlockRandomSource
+	if len(randomSource) == 0 {
+		if e.ctx.BlockHeader == nil {
+			return nil
+		}
+		blockID := e.ctx.BlockHeader.ID()
+		randomSource = blockID[:]
+	}
+
+	txSource := seed.LegacyTransactionRandomSource(randomSource, txIndex, txID)
+	if e.ctx.TransactionRandomSourceKMAC {
+		var err error
+		txSource, err = seed.TransactionRandomSource(randomSource, txIndex, txID)
+		if err != nil {
+			return fmt.Errorf("could not derive transaction random source: %w", err)
+		}
+	}
+	source := rand.NewSource(int64(binary.BigEndian.Uint64(txSource[:8])))
 	e.rng = rand.New(source)
+
+	return nil
 }
 
-func (e *hostEnv) setTransaction(tx *flow.TransactionBody, txIndex uint32) {
+func (e *hostEnv) setTransaction(tx *flow.TransactionBody, txIndex uint32) error {
+	err := e.seedRNG(txIndex, tx.ID())
+	if err != nil {
+		return err
+	}
+
 	e.transactionEnv = newTransactionEnv(
 		e.vm,
 		e.ctx,
@@ -116,6 +143,8 @@ func (e *hostEnv) setTransaction(tx *flow.TransactionBody, txIndex uint32) {
 		tx,
 		txIndex,
 	)
+
+	return nil
 }
 
 // GetAuthorizedAccountsForContractUpdates returns a list of addresses that
@@ -169,6 +198,11 @@ func (e *hostEnv) isTraceable() bool {
 	return e.ctx.Tracer != nil && e.transactionEnv != nil && e.transactionEnv.traceSpan != nil
 }
 
+// transactionTag tags the spans of the phases of the execution of the transaction with its ID.
+func (e *hostEnv) transactionTag() opentracing.Tag {
+	return opentracing.Tag{Key: trace.TransactionIDTag, Value: e.transactionEnv.txID.String()}
+}
+
 func (e *hostEnv) GetValue(owner, key []byte) ([]byte, error) {
 	var valueByteSize int
 	if e.isTraceable() {
@@ -813,6 +847,68 @@ func (e *hostEnv) RevokeAccountKey(address runtime.Address, index int) (*runtime
 	return e.transactionEnv.RevokeAccountKey(address, index)
 }
 
+// GetAccountKeyMetadata returns the metadata of an account key, such as the height it was created at and its label.
+func (e *hostEnv) GetAccountKeyMetadata(address runtime.Address, index int) (*state.AccountPublicKeyMetadata, error) {
+	if e.isTraceable() {
+		sp := e.ctx.Tracer.StartSpanFromParent(e.transactionEnv.traceSpan, trace.FVMEnvGetAccountKeyMetadata)
+		defer sp.Finish()
+	}
+
+	if e.transactionEnv == nil {
+		return nil, errors.NewOperationNotSupportedError("GetAccountKeyMetadata")
+	}
+
+	metadata, err := e.transactionEnv.GetAccountKeyMetadata(address, index)
+	if err != nil {
+		return nil, fmt.Errorf("getting account key metadata failed: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// SetAccountKeyMetadata stores the metadata of an account key.
+func (e *hostEnv) SetAccountKeyMetadata(address runtime.Address, index int, metadata state.AccountPublicKeyMetadata) error {
+	if e.isTraceable() {
+		sp := e.ctx.Tracer.StartSpanFromParent(e.transactionEnv.traceSpan, trace.FVMEnvSetAccountKeyMetadata)
+		defer sp.Finish()
+	}
+
+	if e.transactionEnv == nil {
+		return errors.NewOperationNotSupportedError("SetAccountKeyMetadata")
+	}
+
+	err := e.accounts.CheckAccountNotFrozen(flow.Address(address))
+	if err != nil {
+		return fmt.Errorf("setting account key metadata failed: %w", err)
+	}
+
+	err = e.transactionEnv.SetAccountKeyMetadata(address, index, metadata)
+	if err != nil {
+		return fmt.Errorf("setting account key metadata failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveAccountKeyCount returns the number of account keys which are not revoked.
+func (e *hostEnv) GetActiveAccountKeyCount(address runtime.Address) (uint64, error) {
+	if e.isTraceable() {
+		sp := e.ctx.Tracer.StartSpanFromParent(e.transactionEnv.traceSpan, trace.FVMEnvGetActiveAccountKeyCount)
+		defer sp.Finish()
+	}
+
+	if e.transactionEnv == nil {
+		return 0, errors.NewOperationNotSupportedError("GetActiveAccountKeyCount")
+	}
+
+	count, err := e.transactionEnv.GetActiveAccountKeyCount(address)
+	if err != nil {
+		return 0, fmt.Errorf("counting account keys failed: %w", err)
+	}
+
+	return count, nil
+}
+
 func (e *hostEnv) UpdateAccountContractCode(address runtime.Address, name string, code []byte) (err error) {
 	if e.isTraceable() {
 		sp := e.ctx.Tracer.StartSpanFromParent(e.transactionEnv.traceSpan, trace.FVMEnvUpdateAccountContractCode)
@@ -905,6 +1001,7 @@ func (e *hostEnv) ProgramParsed(location common.Location, duration time.Duration
 					Fields: []traceLog.Field{traceLog.String("location", locStr)},
 				},
 			},
+			e.transactionTag(),
 		)
 	}
 	e.metrics.ProgramParsed(location, duration)
@@ -921,6 +1018,7 @@ func (e *hostEnv) ProgramChecked(location common.Location, duration time.Duratio
 				Fields: []traceLog.Field{traceLog.String("location", locStr)},
 			},
 			},
+			e.transactionTag(),
 		)
 	}
 	e.metrics.ProgramChecked(location, duration)
@@ -937,6 +1035,7 @@ func (e *hostEnv) ProgramInterpreted(location common.Location, duration time.Dur
 				Fields: []traceLog.Field{traceLog.String("location", locStr)},
 			},
 			},
+			e.transactionTag(),
 		)
 	}
 	e.metrics.ProgramInterpreted(location, duration)
@@ -1049,6 +1148,13 @@ func (e *transactionEnv) CreateAccount(payer runtime.Address) (address runtime.A
 		return address, fmt.Errorf("creating account failed: %w", err)
 	}
 
+	if e.ctx.StorageFormatVersioning {
+		err = e.accounts.SetStorageFormatVersion(flowAddress, state.CurrentStorageFormatVersion)
+		if err != nil {
+			return address, fmt.Errorf("setting storage format version of account failed: %w", err)
+		}
+	}
+
 	if e.ctx.ServiceAccountEnabled {
 		txErr, err := e.vm.invokeMetaTransaction(
 			e.ctx,
@@ -1097,7 +1203,17 @@ func (e *transactionEnv) RemoveAccountContractCode(address runtime.Address, name
 // This function returns an error if the specified account does not exist or
 // if the key insertion fails.
 func (e *transactionEnv) AddEncodedAccountKey(address runtime.Address, encodedPublicKey []byte) (err error) {
-	return e.accountKeys.AddEncodedAccountKey(address, encodedPublicKey)
+	err = e.accountKeys.AddEncodedAccountKey(address, encodedPublicKey)
+	if err != nil {
+		return err
+	}
+
+	count, err := e.accounts.GetPublicKeyCount(flow.Address(address))
+	if err != nil {
+		return err
+	}
+
+	return e.setAccountKeyCreationHeight(address, int(count-1))
 }
 
 // RemoveAccountKey revokes a public key by index from an existing account.
@@ -1120,7 +1236,29 @@ func (e *transactionEnv) AddAccountKey(address runtime.Address,
 	*runtime.AccountKey,
 	error,
 ) {
-	return e.accountKeys.AddAccountKey(address, publicKey, hashAlgo, weight)
+	accountKey, err := e.accountKeys.AddAccountKey(address, publicKey, hashAlgo, weight)
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.setAccountKeyCreationHeight(address, accountKey.KeyIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountKey, nil
+}
+
+// setAccountKeyCreationHeight records the height of the block being executed as the creation height of
+// the key at the given index, if account key metadata is enabled.
+func (e *transactionEnv) setAccountKeyCreationHeight(address runtime.Address, keyIndex int) error {
+	if !e.ctx.AccountKeyMetadataEnabled || e.ctx.BlockHeader == nil {
+		return nil
+	}
+
+	return e.accountKeys.SetAccountKeyMetadata(address, keyIndex, state.AccountPublicKeyMetadata{
+		CreationHeight: e.ctx.BlockHeader.Height,
+	})
 }
 
 // RevokeAccountKey revokes a public key by index from an existing account,
@@ -1141,3 +1279,20 @@ func (e *transactionEnv) RevokeAccountKey(address runtime.Address, keyIndex int)
 func (e *transactionEnv) GetAccountKey(address runtime.Address, keyIndex int) (*runtime.AccountKey, error) {
 	return e.accountKeys.GetAccountKey(address, keyIndex)
 }
+
+// GetAccountKeyMetadata retrieves the metadata of a public key by index from an existing account.
+//
+// This function returns nil metadata with no errors, if a key doesn't exist at the given index.
+func (e *transactionEnv) GetAccountKeyMetadata(address runtime.Address, keyIndex int) (*state.AccountPublicKeyMetadata, error) {
+	return e.accountKeys.GetAccountKeyMetadata(address, keyIndex)
+}
+
+// SetAccountKeyMetadata stores the metadata of a public key by index on an existing account.
+func (e *transactionEnv) SetAccountKeyMetadata(address runtime.Address, keyIndex int, metadata state.AccountPublicKeyMetadata) error {
+	return e.accountKeys.SetAccountKeyMetadata(address, keyIndex, metadata)
+}
+
+// GetActiveAccountKeyCount returns the number of keys of an existing account which are not revoked.
+func (e *transactionEnv) GetActiveAccountKeyCount(address runtime.Address) (uint64, error) {
+	return e.accountKeys.GetActiveAccountKeyCount(address)
+}
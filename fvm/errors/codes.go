@@ -8,6 +8,18 @@ func (ec ErrorCode) String() string {
 	return fmt.Sprintf("[Error Code: %d]", ec)
 }
 
+// ErrorCodeFromMessage returns the error code of a transaction error, given the error message
+// as it is stored in the transaction result. It returns false if the message does not start
+// with an error code.
+func ErrorCodeFromMessage(msg string) (ErrorCode, bool) {
+	var code uint16
+	_, err := fmt.Sscanf(msg, "[Error Code: %d]", &code)
+	if err != nil {
+		return 0, false
+	}
+	return ErrorCode(code), true
+}
+
 type FailureCode uint16
 
 func (fc FailureCode) String() string {
@@ -36,6 +48,9 @@ const (
 	ErrCodeInvalidProposalSeqNumberError ErrorCode = 1007
 	ErrCodeInvalidPayloadSignatureError  ErrorCode = 1008
 	ErrCodeInvalidEnvelopeSignatureError ErrorCode = 1009
+	ErrCodeInvalidTxArgumentCountError   ErrorCode = 1010
+	ErrCodeInvalidTxAuthorizerCountError ErrorCode = 1011
+	ErrCodePayerRateLimitExceededError   ErrorCode = 1012
 
 	// base errors 1050 - 1100
 	ErrCodeFVMInternalError            ErrorCode = 1050
@@ -58,6 +73,7 @@ const (
 	ErrCodeStateKeySizeLimitError             ErrorCode = 1107
 	ErrCodeStateValueSizeLimitError           ErrorCode = 1108
 	ErrCodeTransactionFeeDeductionFailedError ErrorCode = 1109
+	ErrCodeRegisterCountLimitExceededError    ErrorCode = 1110
 
 	// accounts errors 1200 - 1250
 	// ErrCodeAccountError              ErrorCode = 1200 - reserved
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
 )
 
 func TestErrorHandeling(t *testing.T) {
@@ -37,3 +39,21 @@ func TestErrorHandeling(t *testing.T) {
 		require.NotNil(t, vmErr)
 	})
 }
+
+func TestErrorCodeFromMessage(t *testing.T) {
+
+	t.Run("transaction error", func(t *testing.T) {
+		err := NewInvalidProposalSeqNumberError(flow.HexToAddress("01"), 0, 2, 1)
+		code, ok := ErrorCodeFromMessage(err.Error())
+		require.True(t, ok)
+		require.Equal(t, ErrCodeInvalidProposalSeqNumberError, code)
+	})
+
+	t.Run("message without error code", func(t *testing.T) {
+		_, ok := ErrorCodeFromMessage("some unknown error")
+		require.False(t, ok)
+
+		_, ok = ErrorCodeFromMessage("")
+		require.False(t, ok)
+	})
+}
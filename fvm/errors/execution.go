@@ -201,6 +201,29 @@ func (e *LedgerIntractionLimitExceededError) Code() ErrorCode {
 	return ErrCodeLedgerIntractionLimitExceededError
 }
 
+// RegisterCountLimitExceededError is returned when a tx reads or writes more distinct registers of an
+// address than allowed
+type RegisterCountLimitExceededError struct {
+	owner  string
+	access string
+	count  uint64
+	limit  uint64
+}
+
+// NewRegisterCountLimitExceededError constructs a RegisterCountLimitExceededError
+func NewRegisterCountLimitExceededError(owner string, access string, count, limit uint64) *RegisterCountLimitExceededError {
+	return &RegisterCountLimitExceededError{owner: owner, access: access, count: count, limit: limit}
+}
+
+func (e *RegisterCountLimitExceededError) Error() string {
+	return fmt.Sprintf("%s number of registers of owner %x %s has exceeded the limit (count: %d, limit %d)", e.Code().String(), e.owner, e.access, e.count, e.limit)
+}
+
+// Code returns the error code for this error
+func (e *RegisterCountLimitExceededError) Code() ErrorCode {
+	return ErrCodeRegisterCountLimitExceededError
+}
+
 // OperationNotSupportedError is generated when an operation (e.g. getting block info) is
 // not supported in the current environment.
 type OperationNotSupportedError struct {
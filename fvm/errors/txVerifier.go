@@ -266,3 +266,65 @@ func (e InvalidEnvelopeSignatureError) Code() ErrorCode {
 func (e InvalidEnvelopeSignatureError) Unwrap() error {
 	return e.err
 }
+
+// InvalidTxArgumentCountError indicates that a transaction provides more arguments than allowed by the network.
+type InvalidTxArgumentCountError struct {
+	count   uint64
+	maximum uint64
+}
+
+// NewInvalidTxArgumentCountError constructs a new InvalidTxArgumentCountError
+func NewInvalidTxArgumentCountError(count, maximum uint64) *InvalidTxArgumentCountError {
+	return &InvalidTxArgumentCountError{count: count, maximum: maximum}
+}
+
+func (e InvalidTxArgumentCountError) Error() string {
+	return fmt.Sprintf("%s transaction argument count (%d) exceeds the maximum number of arguments allowed for a transaction (%d)", e.Code().String(), e.count, e.maximum)
+}
+
+// Code returns the error code for this error type
+func (e InvalidTxArgumentCountError) Code() ErrorCode {
+	return ErrCodeInvalidTxArgumentCountError
+}
+
+// InvalidTxAuthorizerCountError indicates that a transaction lists more authorizers than allowed by the network.
+type InvalidTxAuthorizerCountError struct {
+	count   uint64
+	maximum uint64
+}
+
+// NewInvalidTxAuthorizerCountError constructs a new InvalidTxAuthorizerCountError
+func NewInvalidTxAuthorizerCountError(count, maximum uint64) *InvalidTxAuthorizerCountError {
+	return &InvalidTxAuthorizerCountError{count: count, maximum: maximum}
+}
+
+func (e InvalidTxAuthorizerCountError) Error() string {
+	return fmt.Sprintf("%s transaction authorizer count (%d) exceeds the maximum number of authorizers allowed for a transaction (%d)", e.Code().String(), e.count, e.maximum)
+}
+
+// Code returns the error code for this error type
+func (e InvalidTxAuthorizerCountError) Code() ErrorCode {
+	return ErrCodeInvalidTxAuthorizerCountError
+}
+
+// PayerRateLimitExceededError indicates that the payer of a transaction exceeded the number of
+// transactions it is allowed to have executed in a block.
+type PayerRateLimitExceededError struct {
+	payer  flow.Address
+	height uint64
+	limit  uint64
+}
+
+// NewPayerRateLimitExceededError constructs a new PayerRateLimitExceededError
+func NewPayerRateLimitExceededError(payer flow.Address, height, limit uint64) *PayerRateLimitExceededError {
+	return &PayerRateLimitExceededError{payer: payer, height: height, limit: limit}
+}
+
+func (e PayerRateLimitExceededError) Error() string {
+	return fmt.Sprintf("%s payer %s exceeded the maximum number of transactions allowed in block %d (%d)", e.Code().String(), e.payer, e.height, e.limit)
+}
+
+// Code returns the error code for this error type
+func (e PayerRateLimitExceededError) Code() ErrorCode {
+	return ErrCodePayerRateLimitExceededError
+}
@@ -0,0 +1,49 @@
+package fvm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Names of the feature flags, which enable coordinated changes of the execution behaviour
+// within a spork.
+const (
+	FeatureAccountFreeze           = "account-freeze"
+	FeatureAccountKeyMetadata      = "account-key-metadata"
+	FeatureStorageFormatVersioning = "storage-format-versioning"
+	FeatureTransactionFees         = "transaction-fees"
+	FeatureTransactionRandomKMAC   = "transaction-random-kmac"
+)
+
+// featureFlags maps the names of the feature flags to the context options enabling them.
+var featureFlags = map[string]Option{
+	FeatureAccountFreeze:           WithAccountFreezeAvailable(true),
+	FeatureAccountKeyMetadata:      WithAccountKeyMetadataEnabled(true),
+	FeatureStorageFormatVersioning: WithStorageFormatVersioning(true),
+	FeatureTransactionFees:         WithTransactionFeesEnabled(true),
+	FeatureTransactionRandomKMAC:   WithTransactionRandomSourceKMAC(true),
+}
+
+// FeatureFlags returns the names of all known feature flags, in lexicographic order.
+func FeatureFlags() []string {
+	names := make([]string, 0, len(featureFlags))
+	for name := range featureFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FeatureFlagOptions returns the context options enabling the given feature flags, and an
+// error if any of the flags is unknown.
+func FeatureFlagOptions(flags []string) ([]Option, error) {
+	options := make([]Option, 0, len(flags))
+	for _, flag := range flags {
+		option, ok := featureFlags[flag]
+		if !ok {
+			return nil, fmt.Errorf("unknown feature flag: %s (known flags: %v)", flag, FeatureFlags())
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
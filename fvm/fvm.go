@@ -10,6 +10,7 @@ import (
 	errors "github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/fvm/programs"
 	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/fvm/utils"
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -27,6 +28,18 @@ func NewInterpreterRuntime() runtime.Runtime {
 // A VirtualMachine augments the Cadence runtime with Flow host functionality.
 type VirtualMachine struct {
 	Runtime runtime.Runtime
+
+	// runtimeProvider provides the runtime for each procedure, if the virtual machine isn't
+	// created with a single runtime
+	runtimeProvider RuntimeProvider
+
+	// bound is set on the virtual machines running a single procedure with a runtime borrowed
+	// from a provider, or dedicated to collecting coverage, so nested runs keep their runtime
+	bound bool
+
+	// coverageReport is the report collecting the coverage of the runtime, if the virtual
+	// machine is dedicated to collecting coverage
+	coverageReport *runtime.CoverageReport
 }
 
 // NewVirtualMachine creates a new virtual machine instance with the provided runtime.
@@ -36,13 +49,41 @@ func NewVirtualMachine(rt runtime.Runtime) *VirtualMachine {
 	}
 }
 
+// NewVirtualMachineWithRuntimeProvider creates a new virtual machine instance running each procedure
+// with a runtime borrowed from the provided runtime provider.
+func NewVirtualMachineWithRuntimeProvider(provider RuntimeProvider) *VirtualMachine {
+	return &VirtualMachine{
+		runtimeProvider: provider,
+	}
+}
+
 // Run runs a procedure against a ledger in the given context.
 func (vm *VirtualMachine) Run(ctx Context, proc Procedure, v state.View, programs *programs.Programs) (err error) {
 
+	if ctx.CoverageReport != nil && ctx.CoverageReport != vm.coverageReport {
+		// collect the coverage on a dedicated runtime, so runs without coverage on the shared
+		// runtime are not reported. Nested runs of the procedure reuse the dedicated machine.
+		coverageVM := newCoverageVirtualMachine(ctx.CoverageReport)
+		setCoverageReport(proc, ctx.CoverageReport)
+		return coverageVM.Run(ctx, proc, v, programs)
+	}
+
+	if provider := vm.runtimeProviderFor(ctx); provider != nil {
+		rt := provider.BorrowRuntime()
+		defer provider.ReturnRuntime(rt)
+		return boundVirtualMachine(rt).Run(ctx, proc, v, programs)
+	}
+
+	if tx, ok := proc.(*TransactionProcedure); ok && ctx.ExecutionRecorder != nil {
+		return vm.runRecorded(ctx, tx, v, programs)
+	}
+
 	st := state.NewState(v,
 		state.WithMaxKeySizeAllowed(ctx.MaxStateKeySize),
 		state.WithMaxValueSizeAllowed(ctx.MaxStateValueSize),
-		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize))
+		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize),
+		state.WithMaxRegisterReadsAllowed(ctx.MaxRegisterReads),
+		state.WithMaxRegisterWritesAllowed(ctx.MaxRegisterWrites))
 	sth := state.NewStateHolder(st)
 
 	defer func() {
@@ -65,15 +106,154 @@ func (vm *VirtualMachine) Run(ctx Context, proc Procedure, v state.View, program
 		return err
 	}
 
+	if ctx.DebugReference != nil {
+		vm.compareWithReference(ctx, proc, v)
+	}
+
 	return nil
 }
 
+// runtimeProviderFor returns the provider of the runtime to run a procedure with in the given
+// context, or nil if the procedure is run with the runtime of the virtual machine.
+func (vm *VirtualMachine) runtimeProviderFor(ctx Context) RuntimeProvider {
+	if vm.bound {
+		return nil
+	}
+	if ctx.RuntimeProvider != nil {
+		return ctx.RuntimeProvider
+	}
+	return vm.runtimeProvider
+}
+
+// boundVirtualMachine creates a virtual machine running a single procedure with the given runtime.
+func boundVirtualMachine(rt runtime.Runtime) *VirtualMachine {
+	return &VirtualMachine{
+		Runtime: rt,
+		bound:   true,
+	}
+}
+
+// newCoverageVirtualMachine creates a virtual machine with a dedicated runtime collecting
+// coverage in the given report.
+func newCoverageVirtualMachine(report *runtime.CoverageReport) *VirtualMachine {
+	rt := NewInterpreterRuntime()
+	rt.SetCoverageReport(report)
+	return &VirtualMachine{
+		Runtime:        rt,
+		bound:          true,
+		coverageReport: report,
+	}
+}
+
+// setCoverageReport exposes the coverage report collecting the coverage of a procedure on the procedure.
+func setCoverageReport(proc Procedure, report *runtime.CoverageReport) {
+	switch p := proc.(type) {
+	case *TransactionProcedure:
+		p.CoverageReport = report
+	case *ScriptProcedure:
+		p.CoverageReport = report
+	}
+}
+
+// compareWithReference compares the register updates of an executed transaction with the
+// updates of the reference execution, and reports any differences.
+func (vm *VirtualMachine) compareWithReference(ctx Context, proc Procedure, v state.View) {
+	tx, ok := proc.(*TransactionProcedure)
+	if !ok {
+		return
+	}
+
+	reference, ok := ctx.DebugReference.View(tx.ID)
+	if !ok {
+		return
+	}
+
+	report := utils.DiffViews(v, reference)
+	if report.IsEmpty() {
+		return
+	}
+
+	ctx.DebugReference.Report(tx.ID, report)
+	ctx.Logger.Warn().
+		Hex("tx_id", tx.ID[:]).
+		Uint32("tx_index", tx.TxIndex).
+		Int("mismatched", len(report.Mismatched)).
+		Int("only_executed", len(report.OnlyInA)).
+		Int("only_reference", len(report.OnlyInB)).
+		Msgf("execution differs from reference:\n%s", report.String())
+}
+
+// runRecorded runs a transaction and records it. The transaction is run with empty programs,
+// as cached programs would hide the reads of contract code from the recording. The cache of the
+// caller is left intact, unless the transaction invalidated it.
+func (vm *VirtualMachine) runRecorded(ctx Context, tx *TransactionProcedure, v state.View, cache *programs.Programs) error {
+	recording := state.NewRecordingView(v)
+	recordingPrograms := programs.NewEmptyPrograms()
+
+	recordingCtx := ctx
+	recordingCtx.ExecutionRecorder = nil
+
+	err := vm.Run(recordingCtx, tx, recording, recordingPrograms)
+
+	if cache != nil && recordingPrograms.Cleaned() {
+		cache.ForceCleanup()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	vm.recordExecution(ctx, tx, recording)
+
+	return nil
+}
+
+// recordExecution writes the recording of an executed transaction. Failures to write
+// the recording are logged, but don't affect the execution.
+func (vm *VirtualMachine) recordExecution(ctx Context, tx *TransactionProcedure, recording *state.RecordingView) {
+	ids, values := recording.RegisterUpdates()
+	updates := make(flow.RegisterEntries, 0, len(ids))
+	for i, id := range ids {
+		updates = append(updates, flow.RegisterEntry{Key: id, Value: values[i]})
+	}
+
+	errorMessage := ""
+	if tx.Err != nil {
+		errorMessage = tx.Err.Error()
+	}
+
+	err := ctx.ExecutionRecorder.Record(&TransactionRecording{
+		ChainID:           ctx.Chain.ChainID(),
+		Transaction:       tx.Transaction,
+		TxIndex:           tx.TxIndex,
+		BlockHeader:       ctx.BlockHeader,
+		BlockRandomSource: ctx.BlockRandomSource,
+		Reads:             recording.Reads(),
+		Updates:           updates,
+		ErrorMessage:      errorMessage,
+	})
+	if err != nil {
+		ctx.Logger.Error().
+			Err(err).
+			Hex("tx_id", tx.ID[:]).
+			Msg("failed to record transaction execution")
+	}
+}
+
 // GetAccount returns an account by address or an error if none exists.
 func (vm *VirtualMachine) GetAccount(ctx Context, address flow.Address, v state.View, programs *programs.Programs) (*flow.Account, error) {
+	if provider := vm.runtimeProviderFor(ctx); provider != nil {
+		rt := provider.BorrowRuntime()
+		defer provider.ReturnRuntime(rt)
+		vm = boundVirtualMachine(rt)
+	}
+
 	st := state.NewState(v,
 		state.WithMaxKeySizeAllowed(ctx.MaxStateKeySize),
 		state.WithMaxValueSizeAllowed(ctx.MaxStateValueSize),
-		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize))
+		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize),
+		state.WithMaxRegisterReadsAllowed(ctx.MaxRegisterReads),
+		state.WithMaxRegisterWritesAllowed(ctx.MaxRegisterWrites))
 
 	sth := state.NewStateHolder(st)
 	account, err := getAccount(vm, ctx, sth, programs, address)
@@ -83,6 +263,32 @@ func (vm *VirtualMachine) GetAccount(ctx Context, address flow.Address, v state.
 	return account, nil
 }
 
+// GetStorageFormatVersion returns the version of the format the storage of the account is encoded in.
+func (vm *VirtualMachine) GetStorageFormatVersion(ctx Context, address flow.Address, v state.View) (uint64, error) {
+	st := state.NewState(v,
+		state.WithMaxKeySizeAllowed(ctx.MaxStateKeySize),
+		state.WithMaxValueSizeAllowed(ctx.MaxStateValueSize),
+		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize),
+		state.WithMaxRegisterReadsAllowed(ctx.MaxRegisterReads),
+		state.WithMaxRegisterWritesAllowed(ctx.MaxRegisterWrites))
+
+	accounts := state.NewAccounts(state.NewStateHolder(st))
+
+	exists, err := accounts.Exists(address)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get account: %w", err)
+	}
+	if !exists {
+		return 0, errors.NewAccountNotFoundError(address)
+	}
+
+	version, err := accounts.GetStorageFormatVersion(address)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get storage format version: %w", err)
+	}
+	return version, nil
+}
+
 // invokeMetaTransaction invokes a meta transaction inside the context of an outer transaction.
 //
 // Errors that occur in a meta transaction are propagated as a single error that can be
@@ -3,8 +3,10 @@ package fvm_test
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	mathrand "math/rand"
 	"strconv"
 	"testing"
 
@@ -28,6 +30,7 @@ import (
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/fvm/utils"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/seed"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -1165,10 +1168,11 @@ func TestBlockContext_UnsafeRandom(t *testing.T) {
 		zerolog.Nop(),
 		fvm.WithChain(chain),
 		fvm.WithBlockHeader(&header),
+		fvm.WithBlockRandomSource([]byte("random source")),
 		fvm.WithCadenceLogging(true),
 	)
 
-	t.Run("works as transaction", func(t *testing.T) {
+	runRandomTx := func(t *testing.T, ctx fvm.Context, txIndex uint32) *fvm.TransactionProcedure {
 		txBody := flow.NewTransactionBody().
 			SetScript([]byte(`
                 transaction {
@@ -1183,20 +1187,98 @@ func TestBlockContext_UnsafeRandom(t *testing.T) {
 		require.NoError(t, err)
 
 		ledger := testutil.RootBootstrappedLedger(vm, ctx)
-		require.NoError(t, err)
 
-		tx := fvm.Transaction(txBody, 0)
+		tx := fvm.Transaction(txBody, txIndex)
 
 		err = vm.Run(ctx, tx, ledger, programs.NewEmptyPrograms())
-		assert.NoError(t, err)
+		require.NoError(t, err)
 
-		assert.NoError(t, tx.Err)
+		return tx
+	}
 
+	parseRandom := func(t *testing.T, tx *fvm.TransactionProcedure) uint64 {
+		require.NoError(t, tx.Err)
 		require.Len(t, tx.Logs, 1)
 
 		num, err := strconv.ParseUint(tx.Logs[0], 10, 64)
 		require.NoError(t, err)
-		require.Equal(t, uint64(0xb9c618010e32a0fb), num)
+		return num
+	}
+
+	t.Run("works as transaction", func(t *testing.T) {
+		tx := runRandomTx(t, ctx, 0)
+		parseRandom(t, tx)
+	})
+
+	t.Run("is deterministic for the same transaction", func(t *testing.T) {
+		first := parseRandom(t, runRandomTx(t, ctx, 0))
+		second := parseRandom(t, runRandomTx(t, ctx, 0))
+		require.Equal(t, first, second)
+	})
+
+	t.Run("differs between transaction indices", func(t *testing.T) {
+		first := parseRandom(t, runRandomTx(t, ctx, 0))
+		second := parseRandom(t, runRandomTx(t, ctx, 1))
+		require.NotEqual(t, first, second)
+	})
+
+	t.Run("differs between random sources", func(t *testing.T) {
+		otherCtx := fvm.NewContextFromParent(ctx, fvm.WithBlockRandomSource([]byte("other random source")))
+
+		first := parseRandom(t, runRandomTx(t, ctx, 0))
+		second := parseRandom(t, runRandomTx(t, otherCtx, 0))
+		require.NotEqual(t, first, second)
+	})
+
+	t.Run("uses the legacy source by default", func(t *testing.T) {
+		tx := runRandomTx(t, ctx, 3)
+
+		txSource := seed.LegacyTransactionRandomSource([]byte("random source"), 3, tx.ID)
+		rng := mathrand.New(mathrand.NewSource(int64(binary.BigEndian.Uint64(txSource[:8]))))
+		buf := make([]byte, 8)
+		_, _ = rng.Read(buf)
+
+		require.Equal(t, binary.LittleEndian.Uint64(buf), parseRandom(t, tx))
+	})
+
+	t.Run("differs with the KMAC source", func(t *testing.T) {
+		kmacCtx := fvm.NewContextFromParent(ctx, fvm.WithTransactionRandomSourceKMAC(true))
+
+		first := parseRandom(t, runRandomTx(t, ctx, 0))
+		second := parseRandom(t, runRandomTx(t, kmacCtx, 0))
+		require.NotEqual(t, first, second)
+
+		again := parseRandom(t, runRandomTx(t, kmacCtx, 0))
+		require.Equal(t, second, again)
+	})
+
+	t.Run("falls back to block ID without random source", func(t *testing.T) {
+		noSourceCtx := fvm.NewContextFromParent(ctx, fvm.WithBlockRandomSource(nil))
+
+		first := parseRandom(t, runRandomTx(t, noSourceCtx, 0))
+		second := parseRandom(t, runRandomTx(t, noSourceCtx, 0))
+		require.Equal(t, first, second)
+	})
+
+	t.Run("works as script without random source", func(t *testing.T) {
+		scriptCtx := fvm.NewContextFromParent(ctx, fvm.WithBlockRandomSource(nil))
+		ledger := testutil.RootBootstrappedLedger(vm, scriptCtx)
+
+		script := fvm.Script([]byte(`
+			pub fun main(): UInt64 {
+				return unsafeRandom()
+			}
+		`))
+		err := vm.Run(scriptCtx, script, ledger, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, script.Err)
+	})
+
+	t.Run("fails without block header", func(t *testing.T) {
+		noHeaderCtx := fvm.NewContextFromParent(ctx, fvm.WithBlockHeader(nil), fvm.WithBlockRandomSource(nil))
+
+		tx := runRandomTx(t, noHeaderCtx, 0)
+		require.Error(t, tx.Err)
 	})
 }
 
@@ -96,7 +96,7 @@ func (h *AccountKeyHandler) RevokeAccountKey(address runtime.Address, keyIndex i
 	}
 
 	var publicKey flow.AccountPublicKey
-	publicKey, err = h.accounts.GetPublicKey(accountAddress, uint64(keyIndex))
+	publicKey, err = h.accounts.RevokePublicKey(accountAddress, uint64(keyIndex))
 	if err != nil {
 		// If a key is not found at a given index, then return a nil key with no errors.
 		// This is to be inline with the Cadence runtime. Otherwise Cadence runtime cannot
@@ -107,14 +107,6 @@ func (h *AccountKeyHandler) RevokeAccountKey(address runtime.Address, keyIndex i
 		return nil, fmt.Errorf("revoking account key failed: %w", err)
 	}
 
-	// mark this key as revoked
-	publicKey.Revoked = true
-
-	_, err = h.accounts.SetPublicKey(accountAddress, uint64(keyIndex), publicKey)
-	if err != nil {
-		return nil, fmt.Errorf("revoking account key failed: %w", err)
-	}
-
 	// Prepare account key to return
 	signAlgo := crypto.CryptoToRuntimeSigningAlgorithm(publicKey.SignAlgo)
 	if signAlgo == runtime.SignatureAlgorithmUnknown {
@@ -257,18 +249,100 @@ func (e *AccountKeyHandler) RemoveAccountKey(address runtime.Address, keyIndex i
 	}
 
 	var publicKey flow.AccountPublicKey
-	publicKey, err = e.accounts.GetPublicKey(accountAddress, uint64(keyIndex))
+	publicKey, err = e.accounts.RevokePublicKey(accountAddress, uint64(keyIndex))
 	if err != nil {
 		return nil, fmt.Errorf("remove account key failed: %w", err)
 	}
 
-	// mark this key as revoked
-	publicKey.Revoked = true
-
-	encodedPublicKey, err = e.accounts.SetPublicKey(accountAddress, uint64(keyIndex), publicKey)
+	encodedPublicKey, err = flow.EncodeAccountPublicKey(publicKey)
 	if err != nil {
 		return nil, fmt.Errorf("remove account key failed: %w", err)
 	}
 
 	return encodedPublicKey, nil
 }
+
+// GetAccountKeyMetadata retrieves the metadata of a public key by index from an existing account.
+//
+// This function returns nil metadata with no errors, if a key doesn't exist at the given index.
+// An error is returned if the specified account does not exist, or if the metadata retrieval fails.
+func (h *AccountKeyHandler) GetAccountKeyMetadata(address runtime.Address, keyIndex int) (*state.AccountPublicKeyMetadata, error) {
+	accountAddress := flow.Address(address)
+
+	ok, err := h.accounts.Exists(accountAddress)
+	if err != nil {
+		return nil, fmt.Errorf("getting account key metadata failed: %w", err)
+	}
+
+	if !ok {
+		issue := errors.NewAccountNotFoundError(accountAddress)
+		return nil, fmt.Errorf("getting account key metadata failed: %w", issue)
+	}
+
+	// Don't return an error for invalid key indices
+	if keyIndex < 0 {
+		return nil, nil
+	}
+
+	metadata, err := h.accounts.GetPublicKeyMetadata(accountAddress, uint64(keyIndex))
+	if err != nil {
+		if errors.IsAccountAccountPublicKeyNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting account key metadata failed: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+// SetAccountKeyMetadata stores the metadata of a public key by index on an existing account.
+//
+// This function returns an error if the specified account does not exist, the
+// provided index is not valid, or if storing the metadata fails.
+func (h *AccountKeyHandler) SetAccountKeyMetadata(address runtime.Address, keyIndex int, metadata state.AccountPublicKeyMetadata) error {
+	accountAddress := flow.Address(address)
+
+	ok, err := h.accounts.Exists(accountAddress)
+	if err != nil {
+		return fmt.Errorf("setting account key metadata failed: %w", err)
+	}
+
+	if !ok {
+		issue := errors.NewAccountNotFoundError(accountAddress)
+		return fmt.Errorf("setting account key metadata failed: %w", issue)
+	}
+
+	if keyIndex < 0 {
+		err = errors.NewValueErrorf(fmt.Sprint(keyIndex), "key index must be positive")
+		return fmt.Errorf("setting account key metadata failed: %w", err)
+	}
+
+	err = h.accounts.SetPublicKeyMetadata(accountAddress, uint64(keyIndex), metadata)
+	if err != nil {
+		return fmt.Errorf("setting account key metadata failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveAccountKeyCount returns the number of keys of an existing account which are not revoked.
+func (h *AccountKeyHandler) GetActiveAccountKeyCount(address runtime.Address) (uint64, error) {
+	accountAddress := flow.Address(address)
+
+	ok, err := h.accounts.Exists(accountAddress)
+	if err != nil {
+		return 0, fmt.Errorf("counting account keys failed: %w", err)
+	}
+
+	if !ok {
+		issue := errors.NewAccountNotFoundError(accountAddress)
+		return 0, fmt.Errorf("counting account keys failed: %w", issue)
+	}
+
+	count, err := h.accounts.GetActivePublicKeyCount(accountAddress)
+	if err != nil {
+		return 0, fmt.Errorf("counting account keys failed: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,102 @@
+package fvm
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// PayerRateLimiter limits the number of transactions a payer can have executed per block.
+//
+// The limit is checked by the transaction signature verifier once the signatures of a
+// transaction are verified, so that a transaction can't consume the quota of a payer
+// without its signature. As its decision changes the execution result, the limiter must
+// be deterministic and configured identically on all nodes executing the same blocks.
+type PayerRateLimiter interface {
+	// Limit returns the number of transactions the payer is allowed to have executed in the
+	// block at the given height.
+	Limit(payer flow.Address, height uint64) uint64
+}
+
+// PerBlockPayerRateLimiter allows each payer a fixed number of transactions per block.
+type PerBlockPayerRateLimiter struct {
+	limit uint64
+}
+
+// NewPerBlockPayerRateLimiter creates a limiter allowing each payer the given number of
+// transactions per block.
+func NewPerBlockPayerRateLimiter(limit uint64) *PerBlockPayerRateLimiter {
+	return &PerBlockPayerRateLimiter{
+		limit: limit,
+	}
+}
+
+func (l *PerBlockPayerRateLimiter) Limit(flow.Address, uint64) uint64 {
+	return l.limit
+}
+
+// keyPayerTransactionCounts is the key of the global register counting the transactions of
+// each payer in the block being executed. The register holds the height of the block, followed
+// by the address and count of each payer with transactions in the block, in the order of their
+// first transaction.
+//
+// The counts are kept in the execution state rather than in memory, so that they only depend on
+// the transactions preceding the transaction in its block. Re-executing a block, or executing
+// another fork at the same height, starts from the counts of the parent state, and verification
+// nodes read the counts of a chunk from its chunk data pack. The register is global rather than
+// owned by the payer, so that the counts are not charged to the storage of the payers, and it only
+// ever holds the counts of a single block, as they are reset with the first transaction of the
+// next block.
+const keyPayerTransactionCounts = "payer_tx_counts"
+
+const payerTransactionCountSize = flow.AddressLength + 8
+
+// countPayerTransaction counts a transaction of the payer in the block at the given height. It
+// returns a *errors.PayerRateLimitExceededError if the payer already had the given number of
+// transactions executed in the block.
+func countPayerTransaction(sth *state.StateHolder, payer flow.Address, height uint64, limit uint64) error {
+	value, err := sth.State().Get("", "", keyPayerTransactionCounts)
+	if err != nil {
+		return fmt.Errorf("cannot get transaction counts of payers: %w", err)
+	}
+
+	// counts of previous blocks are dropped
+	if len(value) < 8 || (len(value)-8)%payerTransactionCountSize != 0 ||
+		binary.BigEndian.Uint64(value[:8]) != height {
+		value = make([]byte, 8)
+		binary.BigEndian.PutUint64(value, height)
+	}
+
+	var count uint64
+	offset := -1
+	for i := 8; i < len(value); i += payerTransactionCountSize {
+		if flow.BytesToAddress(value[i:i+flow.AddressLength]) == payer {
+			offset = i + flow.AddressLength
+			count = binary.BigEndian.Uint64(value[offset : offset+8])
+			break
+		}
+	}
+
+	if count >= limit {
+		return errors.NewPayerRateLimitExceededError(payer, height, limit)
+	}
+
+	updated := make([]byte, len(value), len(value)+payerTransactionCountSize)
+	copy(updated, value)
+	if offset < 0 {
+		updated = append(updated, payer.Bytes()...)
+		offset = len(updated)
+		updated = append(updated, make([]byte, 8)...)
+	}
+	binary.BigEndian.PutUint64(updated[offset:offset+8], count+1)
+
+	err = sth.State().Set("", "", keyPayerTransactionCounts, updated)
+	if err != nil {
+		return fmt.Errorf("cannot set transaction count of payer %s: %w", payer, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package fvm_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestTransactionVerifier_PayerRateLimiter(t *testing.T) {
+
+	rt := fvm.NewInterpreterRuntime()
+	chain := flow.Mainnet.Chain()
+	vm := fvm.NewVirtualMachine(rt)
+
+	header := unittest.BlockHeaderFixture()
+	ctx := fvm.NewContext(
+		zerolog.Nop(),
+		fvm.WithChain(chain),
+		fvm.WithBlockHeader(&header),
+		fvm.WithPayerRateLimiter(fvm.NewPerBlockPayerRateLimiter(1)),
+	)
+	ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+	run := func(ctx fvm.Context, view state.View, seqNum uint64) *fvm.TransactionProcedure {
+		txBody := flow.NewTransactionBody().
+			SetScript([]byte(`transaction { execute {} }`))
+		err := testutil.SignTransactionAsServiceAccount(txBody, seqNum, chain)
+		require.NoError(t, err)
+
+		tx := fvm.Transaction(txBody, uint32(seqNum))
+		err = vm.Run(ctx, tx, view, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		return tx
+	}
+
+	t.Run("limits transactions per payer", func(t *testing.T) {
+		block := ledger.NewChild()
+
+		tx := run(ctx, block, 0)
+		require.NoError(t, tx.Err)
+
+		tx = run(ctx, block, 1)
+		require.Error(t, tx.Err)
+		require.Equal(t, errors.ErrCodePayerRateLimitExceededError, tx.Err.Code())
+	})
+
+	t.Run("counts are kept in the block's state", func(t *testing.T) {
+		// re-executing the block, or executing another fork at the same height,
+		// starts from the counts of the parent state
+		for i := 0; i < 2; i++ {
+			tx := run(ctx, ledger.NewChild(), 0)
+			require.NoError(t, tx.Err)
+		}
+	})
+
+	t.Run("counts are reset for new height", func(t *testing.T) {
+		block := ledger.NewChild()
+
+		tx := run(ctx, block, 0)
+		require.NoError(t, tx.Err)
+
+		next := unittest.BlockHeaderWithParentFixture(&header)
+		nextCtx := fvm.NewContextFromParent(ctx, fvm.WithBlockHeader(&next))
+
+		tx = run(nextCtx, block.NewChild(), 1)
+		require.NoError(t, tx.Err)
+	})
+	t.Run("counts are not stored in the payer's account", func(t *testing.T) {
+		block := ledger.NewChild()
+		payer := string(chain.ServiceAddress().Bytes())
+
+		tx := run(ctx, block, 0)
+		require.NoError(t, tx.Err)
+
+		counts, err := block.Get("", "", "payer_tx_counts")
+		require.NoError(t, err)
+		require.Len(t, counts, 8+flow.AddressLength+8)
+
+		count, err := block.Get(payer, payer, "payer_tx_count")
+		require.NoError(t, err)
+		require.Empty(t, count)
+	})
+}
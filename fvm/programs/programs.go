@@ -106,6 +106,14 @@ func (p *Programs) HasChanges() bool {
 	return len(p.programs) > 0 || p.cleaned
 }
 
+// Cleaned indicates if the programs have been cleaned up, discarding all cached programs
+func (p *Programs) Cleaned() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.cleaned
+}
+
 // ForceCleanup is used to force a complete cleanup
 // It exists temporarily to facilitate a temporary measure which can retry
 // a transaction in case checking fails
@@ -0,0 +1,112 @@
+package fvm
+
+import (
+	"sync"
+
+	"github.com/onflow/cadence/runtime"
+)
+
+// RuntimeProvider provides the Cadence runtimes the virtual machine runs procedures with, so that
+// alternate runtimes, like compiled Cadence or instrumentation wrappers, can be swapped in without
+// changing the virtual machine.
+//
+// A runtime is borrowed for each procedure run by the virtual machine, and returned once the
+// procedure ran. Procedures run in the context of another procedure, like the scripts checking
+// the storage limits of a transaction, use the runtime of the outer procedure.
+type RuntimeProvider interface {
+	// BorrowRuntime returns a runtime to run a procedure with. The runtime is used by a single
+	// procedure until it is returned.
+	BorrowRuntime() runtime.Runtime
+
+	// ReturnRuntime returns a runtime borrowed with BorrowRuntime, once the procedure ran.
+	ReturnRuntime(rt runtime.Runtime)
+}
+
+// SharedRuntimeProvider provides the same runtime to all procedures. The runtime must be safe for
+// running procedures concurrently, as the Cadence interpreter runtime is.
+type SharedRuntimeProvider struct {
+	rt runtime.Runtime
+}
+
+// NewSharedRuntimeProvider creates a provider sharing the given runtime among all procedures.
+func NewSharedRuntimeProvider(rt runtime.Runtime) *SharedRuntimeProvider {
+	return &SharedRuntimeProvider{
+		rt: rt,
+	}
+}
+
+func (p *SharedRuntimeProvider) BorrowRuntime() runtime.Runtime {
+	return p.rt
+}
+
+func (p *SharedRuntimeProvider) ReturnRuntime(runtime.Runtime) {}
+
+// PooledRuntimeProvider provides a runtime of its own to each procedure, for runtimes which can't be
+// shared by procedures running concurrently. Runtimes are created on demand, and the runtimes returned
+// by procedures are pooled for reuse by later procedures, up to the given pool size.
+type PooledRuntimeProvider struct {
+	mu         sync.Mutex
+	newRuntime func() runtime.Runtime
+	reset      func(rt runtime.Runtime)
+	size       int
+	pool       []runtime.Runtime
+}
+
+// NewPooledRuntimeProvider creates a provider creating runtimes with the given function, and pooling at
+// most size returned runtimes.
+func NewPooledRuntimeProvider(size int, newRuntime func() runtime.Runtime) *PooledRuntimeProvider {
+	return &PooledRuntimeProvider{
+		newRuntime: newRuntime,
+		size:       size,
+		pool:       make([]runtime.Runtime, 0, size),
+	}
+}
+
+// NewPooledRuntimeProviderWithReset creates a provider like NewPooledRuntimeProvider, which resets the
+// runtimes returned by procedures with the given function before pooling them, so that configuration
+// changed by one procedure doesn't leak into the procedures reusing the runtime.
+func NewPooledRuntimeProviderWithReset(size int, newRuntime func() runtime.Runtime, reset func(rt runtime.Runtime)) *PooledRuntimeProvider {
+	p := NewPooledRuntimeProvider(size, newRuntime)
+	p.reset = reset
+	return p
+}
+
+// NewInterpreterRuntimePool creates a provider pooling at most size Cadence interpreter runtimes created
+// with NewInterpreterRuntime, so that procedures run in the hot path, like the transactions of a block,
+// don't each set up a runtime of their own.
+func NewInterpreterRuntimePool(size int) *PooledRuntimeProvider {
+	return NewPooledRuntimeProviderWithReset(size, NewInterpreterRuntime, ResetInterpreterRuntime)
+}
+
+// ResetInterpreterRuntime restores the configuration of a runtime created with NewInterpreterRuntime.
+// The interpreter runtime keeps no state between procedures besides its configuration.
+func ResetInterpreterRuntime(rt runtime.Runtime) {
+	rt.SetCoverageReport(nil)
+	rt.SetContractUpdateValidationEnabled(true)
+}
+
+func (p *PooledRuntimeProvider) BorrowRuntime() runtime.Runtime {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.pool) == 0 {
+		return p.newRuntime()
+	}
+
+	rt := p.pool[len(p.pool)-1]
+	p.pool = p.pool[:len(p.pool)-1]
+	return rt
+}
+
+func (p *PooledRuntimeProvider) ReturnRuntime(rt runtime.Runtime) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.pool) >= p.size {
+		return
+	}
+	if p.reset != nil {
+		p.reset(rt)
+	}
+	p.pool = append(p.pool, rt)
+}
@@ -0,0 +1,155 @@
+package fvm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// countingRuntimeProvider counts the runtimes borrowed from and returned to the wrapped provider.
+type countingRuntimeProvider struct {
+	fvm.RuntimeProvider
+	borrowed int
+	returned int
+}
+
+func (p *countingRuntimeProvider) BorrowRuntime() runtime.Runtime {
+	p.borrowed++
+	return p.RuntimeProvider.BorrowRuntime()
+}
+
+func (p *countingRuntimeProvider) ReturnRuntime(rt runtime.Runtime) {
+	p.returned++
+	p.RuntimeProvider.ReturnRuntime(rt)
+}
+
+func TestRuntimeProvider(t *testing.T) {
+
+	chain := flow.Mainnet.Chain()
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+	ledger := testutil.RootBootstrappedLedger(fvm.NewVirtualMachine(fvm.NewInterpreterRuntime()), ctx)
+
+	script := func() *fvm.ScriptProcedure {
+		return fvm.Script([]byte(`pub fun main(): Int { return 42 }`))
+	}
+
+	t.Run("virtual machine", func(t *testing.T) {
+		provider := &countingRuntimeProvider{
+			RuntimeProvider: fvm.NewSharedRuntimeProvider(fvm.NewInterpreterRuntime()),
+		}
+		vm := fvm.NewVirtualMachineWithRuntimeProvider(provider)
+
+		proc := script()
+		err := vm.Run(ctx, proc, ledger, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, proc.Err)
+
+		assert.Equal(t, 1, provider.borrowed)
+		assert.Equal(t, 1, provider.returned)
+	})
+
+	t.Run("context overrides virtual machine runtime", func(t *testing.T) {
+		vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+
+		provider := &countingRuntimeProvider{
+			RuntimeProvider: fvm.NewSharedRuntimeProvider(fvm.NewInterpreterRuntime()),
+		}
+		providerCtx := fvm.NewContextFromParent(ctx, fvm.WithRuntimeProvider(provider))
+
+		proc := script()
+		err := vm.Run(providerCtx, proc, ledger, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, proc.Err)
+
+		assert.Equal(t, 1, provider.borrowed)
+		assert.Equal(t, 1, provider.returned)
+	})
+
+	t.Run("nested scripts", func(t *testing.T) {
+		provider := &countingRuntimeProvider{
+			RuntimeProvider: fvm.NewPooledRuntimeProvider(1, fvm.NewInterpreterRuntime),
+		}
+		vm := fvm.NewVirtualMachineWithRuntimeProvider(provider)
+
+		// the balance is read by a nested script, run with the runtime of the outer script
+		proc := fvm.Script([]byte(fmt.Sprintf(`
+			pub fun main(): UFix64 {
+				return getAccount(0x%s).balance
+			}
+		`, chain.ServiceAddress())))
+		err := vm.Run(ctx, proc, ledger, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, proc.Err)
+
+		assert.Equal(t, 1, provider.borrowed)
+		assert.Equal(t, 1, provider.returned)
+	})
+}
+
+func TestPooledRuntimeProvider(t *testing.T) {
+	created := 0
+	provider := fvm.NewPooledRuntimeProvider(1, func() runtime.Runtime {
+		created++
+		return fvm.NewInterpreterRuntime()
+	})
+
+	rt1 := provider.BorrowRuntime()
+	rt2 := provider.BorrowRuntime()
+	require.NotSame(t, rt1, rt2)
+	require.Equal(t, 2, created)
+
+	// only one returned runtime is kept for reuse
+	provider.ReturnRuntime(rt1)
+	provider.ReturnRuntime(rt2)
+
+	rt3 := provider.BorrowRuntime()
+	require.Same(t, rt1, rt3)
+	require.Equal(t, 2, created)
+
+	provider.BorrowRuntime()
+	require.Equal(t, 3, created)
+}
+
+// configuredRuntime records the configuration set on the wrapped runtime.
+type configuredRuntime struct {
+	runtime.Runtime
+	coverageReport                  *runtime.CoverageReport
+	contractUpdateValidationEnabled bool
+}
+
+func (r *configuredRuntime) SetCoverageReport(coverageReport *runtime.CoverageReport) {
+	r.coverageReport = coverageReport
+}
+
+func (r *configuredRuntime) SetContractUpdateValidationEnabled(enabled bool) {
+	r.contractUpdateValidationEnabled = enabled
+}
+
+func TestPooledRuntimeProviderWithReset(t *testing.T) {
+	provider := fvm.NewPooledRuntimeProviderWithReset(1, func() runtime.Runtime {
+		return &configuredRuntime{
+			Runtime:                         fvm.NewInterpreterRuntime(),
+			contractUpdateValidationEnabled: true,
+		}
+	}, fvm.ResetInterpreterRuntime)
+
+	rt := provider.BorrowRuntime()
+	rt.SetCoverageReport(runtime.NewCoverageReport())
+	rt.SetContractUpdateValidationEnabled(false)
+	provider.ReturnRuntime(rt)
+
+	// the runtime is reused with the configuration of a new runtime
+	reused := provider.BorrowRuntime()
+	require.Same(t, rt, reused)
+	assert.Nil(t, reused.(*configuredRuntime).coverageReport)
+	assert.True(t, reused.(*configuredRuntime).contractUpdateValidationEnabled)
+}
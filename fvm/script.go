@@ -30,6 +30,8 @@ type ScriptProcedure struct {
 	Events    []flow.Event
 	GasUsed   uint64
 	Err       errors.Error
+	// CoverageReport holds the coverage collected when running with WithCoverageReport.
+	CoverageReport *runtime.CoverageReport
 }
 
 type ScriptProcessor interface {
@@ -74,6 +76,13 @@ func (i ScriptInvocator) Process(
 	programs *programs.Programs,
 ) error {
 	env := newEnvironment(ctx, vm, sth, programs)
+
+	// scripts are not part of a block, so the generator is seeded with the block random source only
+	err := env.seedRNG(0, flow.ZeroID)
+	if err != nil {
+		return err
+	}
+
 	location := common.ScriptLocation(proc.ID[:])
 	value, err := vm.Runtime.ExecuteScript(
 		runtime.Script{
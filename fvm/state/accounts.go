@@ -20,17 +20,38 @@ const (
 	KeyCode               = "code"
 	KeyContractNames      = "contract_names"
 	KeyPublicKeyCount     = "public_key_count"
+	KeyRevokedKeyCount    = "public_key_revoked_count"
 	KeyStorageUsed        = "storage_used"
 	KeyAccountFrozen      = "frozen"
+	KeyStorageFormat      = "storage_format_version"
 	uint64StorageSize     = 8
 	AccountFrozenValue    = 1
 	AccountNotFrozenValue = 0
 )
 
+const (
+	// LegacyStorageFormatVersion is the storage format version of accounts created
+	// before storage format versions were tracked, which have no version register
+	LegacyStorageFormatVersion uint64 = 0
+	// CurrentStorageFormatVersion is the storage format version of new accounts
+	CurrentStorageFormatVersion uint64 = 1
+)
+
 func keyPublicKey(index uint64) string {
 	return fmt.Sprintf("public_key_%d", index)
 }
 
+func keyPublicKeyMetadata(index uint64) string {
+	return fmt.Sprintf("public_key_metadata_%d", index)
+}
+
+// AccountPublicKeyMetadata is the additional information stored alongside an account public key.
+// Keys added before metadata was introduced have empty metadata.
+type AccountPublicKeyMetadata struct {
+	CreationHeight uint64 // height of the block in which the key was added
+	Label          string // user defined label of the key, e.g. to tell keys apart when rotating them
+}
+
 type Accounts struct {
 	stateHolder *StateHolder
 }
@@ -195,11 +216,101 @@ func (a *Accounts) SetPublicKey(
 		return nil, errors.NewValueErrorf(string(encoded), "invalid public key value: %w", err)
 	}
 
+	err = a.updateRevokedPublicKeyCount(address, keyIndex, publicKey.Revoked)
+	if err != nil {
+		return nil, err
+	}
+
 	err = a.setValue(address, true, keyPublicKey(keyIndex), encodedPublicKey)
 
 	return encodedPublicKey, err
 }
 
+// getRevokedPublicKeyCount returns the number of revoked public keys of the account, and false if the
+// counter was not initialized yet.
+func (a *Accounts) getRevokedPublicKeyCount(address flow.Address) (uint64, bool, error) {
+	countBytes, err := a.getValue(address, true, KeyRevokedKeyCount)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(countBytes) == 0 {
+		return 0, false, nil
+	}
+
+	if len(countBytes) != uint64StorageSize {
+		return 0, false, fmt.Errorf(
+			"retrieved revoked public key count bytes (hex-encoded): %x does not represent valid uint64",
+			countBytes,
+		)
+	}
+
+	return binary.BigEndian.Uint64(countBytes), true, nil
+}
+
+func (a *Accounts) setRevokedPublicKeyCount(address flow.Address, count uint64) error {
+	countBytes := make([]byte, uint64StorageSize)
+	binary.BigEndian.PutUint64(countBytes, count)
+
+	return a.setValue(address, true, KeyRevokedKeyCount, countBytes)
+}
+
+// updateRevokedPublicKeyCount keeps the revoked public key counter of the account up to date, before the
+// key at the given index is set. The counter is only written when the revoked status of the key changes,
+// and is initialized from the keys the first time a key of the account is revoked or un-revoked.
+func (a *Accounts) updateRevokedPublicKeyCount(address flow.Address, keyIndex uint64, revoked bool) error {
+	encodedPublicKey, err := a.getValue(address, true, keyPublicKey(keyIndex))
+	if err != nil {
+		return err
+	}
+
+	wasRevoked := false
+	if len(encodedPublicKey) > 0 {
+		publicKey, err := flow.DecodeAccountPublicKey(encodedPublicKey, keyIndex)
+		if err != nil {
+			return fmt.Errorf("failed to decode public key: %w", err)
+		}
+		wasRevoked = publicKey.Revoked
+	}
+
+	if revoked == wasRevoked {
+		return nil
+	}
+
+	count, ok, err := a.getRevokedPublicKeyCount(address)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		count, err = a.countRevokedPublicKeys(address)
+		if err != nil {
+			return err
+		}
+	}
+
+	if revoked {
+		return a.setRevokedPublicKeyCount(address, count+1)
+	}
+	return a.setRevokedPublicKeyCount(address, count-1)
+}
+
+// countRevokedPublicKeys counts the revoked public keys of the account by reading all of its keys.
+func (a *Accounts) countRevokedPublicKeys(address flow.Address) (uint64, error) {
+	var revoked uint64
+	err := a.ForEachPublicKey(address, func(publicKey flow.AccountPublicKey) error {
+		if publicKey.Revoked {
+			revoked++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return revoked, nil
+}
+
 func (a *Accounts) SetAllPublicKeys(address flow.Address, publicKeys []flow.AccountPublicKey) error {
 	for i, publicKey := range publicKeys {
 		_, err := a.SetPublicKey(address, uint64(i), publicKey)
@@ -236,6 +347,126 @@ func (a *Accounts) AppendPublicKey(address flow.Address, publicKey flow.AccountP
 	return a.setPublicKeyCount(address, count+1)
 }
 
+// RevokePublicKey marks the public key at the given index as revoked and returns the revoked key.
+// Revoking a key which is already revoked has no effect.
+func (a *Accounts) RevokePublicKey(address flow.Address, keyIndex uint64) (flow.AccountPublicKey, error) {
+	publicKey, err := a.GetPublicKey(address, keyIndex)
+	if err != nil {
+		return flow.AccountPublicKey{}, err
+	}
+
+	if publicKey.Revoked {
+		return publicKey, nil
+	}
+
+	publicKey.Revoked = true
+
+	_, err = a.SetPublicKey(address, keyIndex, publicKey)
+	if err != nil {
+		return flow.AccountPublicKey{}, err
+	}
+
+	return publicKey, nil
+}
+
+// ForEachPublicKey calls f for each public key of the account, in order of key index.
+// Keys are read one at a time, so that callers which stop early (by returning an error)
+// don't need to load all keys of the account.
+func (a *Accounts) ForEachPublicKey(address flow.Address, f func(publicKey flow.AccountPublicKey) error) error {
+	count, err := a.GetPublicKeyCount(address)
+	if err != nil {
+		return fmt.Errorf("failed to get public key count of account: %w", err)
+	}
+
+	for i := uint64(0); i < count; i++ {
+		publicKey, err := a.GetPublicKey(address, i)
+		if err != nil {
+			return err
+		}
+
+		err = f(publicKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetActivePublicKeyCount returns the number of public keys of the account which are not revoked.
+//
+// The revoked keys are counted as they are revoked. Accounts which never revoked a key since the counter
+// was introduced have their keys read instead, without writing the counter.
+func (a *Accounts) GetActivePublicKeyCount(address flow.Address) (uint64, error) {
+	count, err := a.GetPublicKeyCount(address)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked, ok, err := a.getRevokedPublicKeyCount(address)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		revoked, err = a.countRevokedPublicKeys(address)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return count - revoked, nil
+}
+
+// GetPublicKeyMetadata returns the metadata of the public key at the given index.
+func (a *Accounts) GetPublicKeyMetadata(address flow.Address, keyIndex uint64) (AccountPublicKeyMetadata, error) {
+	count, err := a.GetPublicKeyCount(address)
+	if err != nil {
+		return AccountPublicKeyMetadata{}, err
+	}
+
+	if keyIndex >= count {
+		return AccountPublicKeyMetadata{}, errors.NewAccountPublicKeyNotFoundError(address, keyIndex)
+	}
+
+	encoded, err := a.getValue(address, true, keyPublicKeyMetadata(keyIndex))
+	if err != nil {
+		return AccountPublicKeyMetadata{}, err
+	}
+
+	var metadata AccountPublicKeyMetadata
+	if len(encoded) == 0 {
+		return metadata, nil
+	}
+
+	err = cbor.Unmarshal(encoded, &metadata)
+	if err != nil {
+		return AccountPublicKeyMetadata{}, fmt.Errorf("cannot decode public key metadata %x: %w", encoded, err)
+	}
+
+	return metadata, nil
+}
+
+// SetPublicKeyMetadata stores the metadata of the public key at the given index.
+func (a *Accounts) SetPublicKeyMetadata(address flow.Address, keyIndex uint64, metadata AccountPublicKeyMetadata) error {
+	count, err := a.GetPublicKeyCount(address)
+	if err != nil {
+		return err
+	}
+
+	if keyIndex >= count {
+		return errors.NewAccountPublicKeyNotFoundError(address, keyIndex)
+	}
+
+	encoded, err := cbor.Marshal(metadata)
+	if err != nil {
+		msg := fmt.Sprintf("cannot encode public key metadata: %v", metadata)
+		return errors.NewEncodingFailuref(msg, err)
+	}
+
+	return a.setValue(address, true, keyPublicKeyMetadata(keyIndex), encoded)
+}
+
 func IsValidAccountKeySignAlgo(algo crypto.SigningAlgorithm) bool {
 	switch algo {
 	case crypto.ECDSAP256, crypto.ECDSASecp256k1:
@@ -573,6 +804,40 @@ func (a *Accounts) SetAccountFrozen(address flow.Address, frozen bool) error {
 	return a.setValue(address, false, KeyAccountFrozen, val)
 }
 
+// GetStorageFormatVersion returns the version of the format the account's storage is encoded in.
+// Accounts without a storage format version register are on the legacy format.
+func (a *Accounts) GetStorageFormatVersion(address flow.Address) (uint64, error) {
+	version, err := a.getValue(address, false, KeyStorageFormat)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(version) == 0 {
+		return LegacyStorageFormatVersion, nil
+	}
+
+	storageFormatVersion, _, err := readUint64(version)
+	if err != nil {
+		return 0, fmt.Errorf("account %s storage format version is not initialized correctly: %w", address.Hex(), err)
+	}
+	return storageFormatVersion, nil
+}
+
+// SetStorageFormatVersion records the version of the format the account's storage is encoded in,
+// e.g. after the storage of the account is migrated.
+func (a *Accounts) SetStorageFormatVersion(address flow.Address, version uint64) error {
+	ok, err := a.Exists(address)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.NewAccountNotFoundError(address)
+	}
+
+	return a.setValue(address, false, KeyStorageFormat, uint64ToBinary(version))
+}
+
 // handy function to error out if account is frozen
 func (a *Accounts) CheckAccountNotFrozen(address flow.Address) error {
 	frozen, err := a.GetAccountFrozen(address)
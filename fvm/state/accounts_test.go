@@ -9,6 +9,7 @@ import (
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/fvm/utils"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestAccounts_Create(t *testing.T) {
@@ -139,6 +140,214 @@ func TestAccounts_GetPublicKeys(t *testing.T) {
 	})
 }
 
+func TestAccounts_RevokePublicKey(t *testing.T) {
+	view := utils.NewSimpleView()
+	sth := state.NewStateHolder(state.NewState(view))
+	accounts := state.NewAccounts(sth)
+	address := flow.HexToAddress("01")
+
+	keys := make([]flow.AccountPublicKey, 3)
+	for i := range keys {
+		privateKey, err := unittest.AccountKeyDefaultFixture()
+		require.NoError(t, err)
+		keys[i] = privateKey.PublicKey(1000)
+	}
+
+	err := accounts.Create(keys, address)
+	require.NoError(t, err)
+
+	count, err := accounts.GetActivePublicKeyCount(address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count)
+
+	t.Run("revoke existing key", func(t *testing.T) {
+		revoked, err := accounts.RevokePublicKey(address, 1)
+		require.NoError(t, err)
+		require.True(t, revoked.Revoked)
+
+		stored, err := accounts.GetPublicKey(address, 1)
+		require.NoError(t, err)
+		require.True(t, stored.Revoked)
+
+		// revoking doesn't remove the key
+		total, err := accounts.GetPublicKeyCount(address)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), total)
+
+		count, err := accounts.GetActivePublicKeyCount(address)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), count)
+	})
+
+	t.Run("revoke revoked key", func(t *testing.T) {
+		revoked, err := accounts.RevokePublicKey(address, 1)
+		require.NoError(t, err)
+		require.True(t, revoked.Revoked)
+
+		count, err := accounts.GetActivePublicKeyCount(address)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), count)
+	})
+
+	t.Run("revoke non-existent key", func(t *testing.T) {
+		_, err := accounts.RevokePublicKey(address, 3)
+		require.Error(t, err)
+		require.True(t, errors.IsAccountAccountPublicKeyNotFoundError(err))
+	})
+}
+
+func TestAccounts_GetActivePublicKeyCount(t *testing.T) {
+	view := utils.NewSimpleView()
+	sth := state.NewStateHolder(state.NewState(view))
+	accounts := state.NewAccounts(sth)
+	address := flow.HexToAddress("01")
+
+	keys := make([]flow.AccountPublicKey, 3)
+	for i := range keys {
+		privateKey, err := unittest.AccountKeyDefaultFixture()
+		require.NoError(t, err)
+		keys[i] = privateKey.PublicKey(1000)
+	}
+
+	err := accounts.Create(keys, address)
+	require.NoError(t, err)
+
+	owner := string(address.Bytes())
+
+	// counting the keys doesn't write the counter
+	count, err := accounts.GetActivePublicKeyCount(address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count)
+
+	counter, err := view.Get(owner, owner, state.KeyRevokedKeyCount)
+	require.NoError(t, err)
+	require.Empty(t, counter)
+
+	// the counter is written once a key is revoked
+	_, err = accounts.RevokePublicKey(address, 0)
+	require.NoError(t, err)
+
+	counter, err = view.Get(owner, owner, state.KeyRevokedKeyCount)
+	require.NoError(t, err)
+	require.NotEmpty(t, counter)
+
+	count, err = accounts.GetActivePublicKeyCount(address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+
+	// accounts which revoked keys before the counter was introduced have their keys counted
+	err = view.Set(owner, owner, state.KeyRevokedKeyCount, nil)
+	require.NoError(t, err)
+
+	count, err = accounts.GetActivePublicKeyCount(address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+
+	counter, err = view.Get(owner, owner, state.KeyRevokedKeyCount)
+	require.NoError(t, err)
+	require.Empty(t, counter)
+
+	// and the counter is initialized from the keys on the next revocation
+	_, err = accounts.RevokePublicKey(address, 2)
+	require.NoError(t, err)
+
+	count, err = accounts.GetActivePublicKeyCount(address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), count)
+
+	privateKey, err := unittest.AccountKeyDefaultFixture()
+	require.NoError(t, err)
+	err = accounts.AppendPublicKey(address, privateKey.PublicKey(1000))
+	require.NoError(t, err)
+
+	unrevoked := keys[0]
+	unrevoked.Revoked = false
+	_, err = accounts.SetPublicKey(address, 0, unrevoked)
+	require.NoError(t, err)
+
+	count, err = accounts.GetActivePublicKeyCount(address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count)
+}
+
+func TestAccounts_PublicKeyMetadata(t *testing.T) {
+	view := utils.NewSimpleView()
+	sth := state.NewStateHolder(state.NewState(view))
+	accounts := state.NewAccounts(sth)
+	address := flow.HexToAddress("01")
+
+	privateKey, err := unittest.AccountKeyDefaultFixture()
+	require.NoError(t, err)
+
+	err = accounts.Create([]flow.AccountPublicKey{privateKey.PublicKey(1000)}, address)
+	require.NoError(t, err)
+
+	t.Run("key without metadata", func(t *testing.T) {
+		metadata, err := accounts.GetPublicKeyMetadata(address, 0)
+		require.NoError(t, err)
+		require.Equal(t, state.AccountPublicKeyMetadata{}, metadata)
+	})
+
+	t.Run("set and get metadata", func(t *testing.T) {
+		expected := state.AccountPublicKeyMetadata{
+			CreationHeight: 42,
+			Label:          "hot wallet",
+		}
+		err := accounts.SetPublicKeyMetadata(address, 0, expected)
+		require.NoError(t, err)
+
+		metadata, err := accounts.GetPublicKeyMetadata(address, 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, metadata)
+	})
+
+	t.Run("non-existent key", func(t *testing.T) {
+		err := accounts.SetPublicKeyMetadata(address, 1, state.AccountPublicKeyMetadata{Label: "missing"})
+		require.True(t, errors.IsAccountAccountPublicKeyNotFoundError(err))
+
+		_, err = accounts.GetPublicKeyMetadata(address, 1)
+		require.True(t, errors.IsAccountAccountPublicKeyNotFoundError(err))
+	})
+}
+
+func TestAccounts_StorageFormatVersion(t *testing.T) {
+	view := utils.NewSimpleView()
+	sth := state.NewStateHolder(state.NewState(view))
+	accounts := state.NewAccounts(sth)
+	address := flow.HexToAddress("01")
+
+	err := accounts.Create(nil, address)
+	require.NoError(t, err)
+
+	t.Run("account without version is on legacy format", func(t *testing.T) {
+		version, err := accounts.GetStorageFormatVersion(address)
+		require.NoError(t, err)
+		require.Equal(t, state.LegacyStorageFormatVersion, version)
+	})
+
+	t.Run("set and get version", func(t *testing.T) {
+		storageUsedBefore, err := accounts.GetStorageUsed(address)
+		require.NoError(t, err)
+
+		err = accounts.SetStorageFormatVersion(address, state.CurrentStorageFormatVersion)
+		require.NoError(t, err)
+
+		version, err := accounts.GetStorageFormatVersion(address)
+		require.NoError(t, err)
+		require.Equal(t, state.CurrentStorageFormatVersion, version)
+
+		// the version register counts towards the storage used by the account
+		storageUsedAfter, err := accounts.GetStorageUsed(address)
+		require.NoError(t, err)
+		require.Greater(t, storageUsedAfter, storageUsedBefore)
+	})
+
+	t.Run("non-existent account", func(t *testing.T) {
+		err := accounts.SetStorageFormatVersion(flow.HexToAddress("02"), state.CurrentStorageFormatVersion)
+		require.True(t, errors.IsAccountNotFoundError(err))
+	})
+}
+
 // Some old account could be created without key count register
 // we recreate it in a test
 func TestAccounts_GetWithNoKeysCounter(t *testing.T) {
@@ -0,0 +1,155 @@
+package state
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// peeker is implemented by views which can read a register without recording
+// the read as an interaction of the execution.
+type peeker interface {
+	Peek(owner, controller, key string) (flow.RegisterValue, error)
+}
+
+// RecordingView wraps a view and records the value every register had before
+// it was first interacted with, i.e. the snapshot of registers an execution
+// depends on. Children of a recording view record into the same snapshot.
+type RecordingView struct {
+	view     View
+	root     View
+	recorder *readRecorder
+}
+
+type readRecorder struct {
+	sync.Mutex
+	reads   map[string]flow.RegisterEntry
+	written map[string]struct{}
+}
+
+// NewRecordingView creates a new recording view on top of the given view.
+func NewRecordingView(view View) *RecordingView {
+	return &RecordingView{
+		view: view,
+		root: view,
+		recorder: &readRecorder{
+			reads:   make(map[string]flow.RegisterEntry),
+			written: make(map[string]struct{}),
+		},
+	}
+}
+
+// Reads returns the recorded registers with the values they had before the
+// execution, sorted by register ID.
+func (v *RecordingView) Reads() flow.RegisterEntries {
+	v.recorder.Lock()
+	defer v.recorder.Unlock()
+
+	reads := make(flow.RegisterEntries, 0, len(v.recorder.reads))
+	for _, entry := range v.recorder.reads {
+		reads = append(reads, entry)
+	}
+	sort.Sort(reads)
+	return reads
+}
+
+func (v *RecordingView) NewChild() View {
+	return &RecordingView{
+		view:     v.view.NewChild(),
+		root:     v.root,
+		recorder: v.recorder,
+	}
+}
+
+func (v *RecordingView) MergeView(child View) error {
+	if recording, ok := child.(*RecordingView); ok {
+		child = recording.view
+	}
+	return v.view.MergeView(child)
+}
+
+func (v *RecordingView) DropDelta() {
+	v.view.DropDelta()
+}
+
+func (v *RecordingView) RegisterUpdates() ([]flow.RegisterID, []flow.RegisterValue) {
+	return v.view.RegisterUpdates()
+}
+
+func (v *RecordingView) AllRegisters() []flow.RegisterID {
+	return v.view.AllRegisters()
+}
+
+func (v *RecordingView) Get(owner, controller, key string) (flow.RegisterValue, error) {
+	value, err := v.view.Get(owner, controller, key)
+	if err != nil {
+		return nil, err
+	}
+
+	v.recorder.Lock()
+	defer v.recorder.Unlock()
+
+	// once a register was written, reads return the written value instead of
+	// the value the execution started with
+	id := flow.NewRegisterID(owner, controller, key)
+	if v.recorder.recordable(id) {
+		v.recorder.reads[id.String()] = flow.RegisterEntry{Key: id, Value: value}
+	}
+	return value, nil
+}
+
+func (v *RecordingView) Set(owner, controller, key string, value flow.RegisterValue) error {
+	err := v.recordBeforeWrite(owner, controller, key)
+	if err != nil {
+		return err
+	}
+	return v.view.Set(owner, controller, key, value)
+}
+
+func (v *RecordingView) Touch(owner, controller, key string) error {
+	return v.view.Touch(owner, controller, key)
+}
+
+func (v *RecordingView) Delete(owner, controller, key string) error {
+	err := v.recordBeforeWrite(owner, controller, key)
+	if err != nil {
+		return err
+	}
+	return v.view.Delete(owner, controller, key)
+}
+
+// recordBeforeWrite records the value of a register which is written before it
+// was read, as a rolled back write can be followed by a read of the original
+// value. The value is only recorded if the root view can be read without
+// affecting the execution (e.g. the SPoCK secret of a delta view).
+func (v *RecordingView) recordBeforeWrite(owner, controller, key string) error {
+	v.recorder.Lock()
+	defer v.recorder.Unlock()
+
+	id := flow.NewRegisterID(owner, controller, key)
+	if !v.recorder.recordable(id) {
+		return nil
+	}
+	v.recorder.written[id.String()] = struct{}{}
+
+	root, ok := v.root.(peeker)
+	if !ok {
+		return nil
+	}
+	value, err := root.Peek(owner, controller, key)
+	if err != nil {
+		return err
+	}
+	v.recorder.reads[id.String()] = flow.RegisterEntry{Key: id, Value: value}
+	return nil
+}
+
+// recordable returns true if the register was neither read nor written yet.
+func (r *readRecorder) recordable(id flow.RegisterID) bool {
+	if _, ok := r.reads[id.String()]; ok {
+		return false
+	}
+	_, ok := r.written[id.String()]
+	return !ok
+}
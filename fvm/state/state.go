@@ -14,6 +14,10 @@ const (
 	DefaultMaxKeySize         = 16_000        // ~16KB
 	DefaultMaxValueSize       = 256_000_000   // ~256MB
 	DefaultMaxInteractionSize = 2_000_000_000 // ~2GB
+
+	// limits on the number of distinct registers of a single address read or written by a transaction
+	DefaultMaxRegisterReads  = 100_000
+	DefaultMaxRegisterWrites = 100_000
 )
 
 type mapKey struct {
@@ -30,6 +34,11 @@ type State struct {
 	maxKeySizeAllowed     uint64
 	maxValueSizeAllowed   uint64
 	maxInteractionAllowed uint64
+	readRegisters         map[mapKey]struct{} // distinct registers read, which weren't updated before
+	registerReads         map[string]uint64   // number of distinct registers read, by owner
+	registerWrites        map[string]uint64   // number of distinct registers written, by owner
+	maxRegisterReads      uint64
+	maxRegisterWrites     uint64
 	ReadCounter           uint64
 	WriteCounter          uint64
 	TotalBytesRead        uint64
@@ -44,6 +53,11 @@ func defaultState(view View) *State {
 		maxKeySizeAllowed:     DefaultMaxKeySize,
 		maxValueSizeAllowed:   DefaultMaxValueSize,
 		maxInteractionAllowed: DefaultMaxInteractionSize,
+		readRegisters:         make(map[mapKey]struct{}),
+		registerReads:         make(map[string]uint64),
+		registerWrites:        make(map[string]uint64),
+		maxRegisterReads:      DefaultMaxRegisterReads,
+		maxRegisterWrites:     DefaultMaxRegisterWrites,
 	}
 }
 
@@ -86,6 +100,22 @@ func WithMaxInteractionSizeAllowed(limit uint64) func(st *State) *State {
 	}
 }
 
+// WithMaxRegisterReadsAllowed sets limit on the number of distinct registers of a single address read
+func WithMaxRegisterReadsAllowed(limit uint64) func(st *State) *State {
+	return func(st *State) *State {
+		st.maxRegisterReads = limit
+		return st
+	}
+}
+
+// WithMaxRegisterWritesAllowed sets limit on the number of distinct registers of a single address written
+func WithMaxRegisterWritesAllowed(limit uint64) func(st *State) *State {
+	return func(st *State) *State {
+		st.maxRegisterWrites = limit
+		return st
+	}
+}
+
 // InteractionUsed returns the amount of ledger interaction (total ledger byte read + total ledger byte written)
 func (s *State) InteractionUsed() uint64 {
 	return s.TotalBytesRead + s.TotalBytesWritten
@@ -108,10 +138,19 @@ func (s *State) Get(owner, controller, key string) (flow.RegisterValue, error) {
 	}
 
 	// if not part of recent updates count them as read
-	if _, ok := s.updateSize[mapKey{owner, controller, key}]; !ok {
+	mapKey := mapKey{owner, controller, key}
+	if _, ok := s.updateSize[mapKey]; !ok {
 		s.ReadCounter++
 		s.TotalBytesRead += uint64(len(owner) +
 			len(controller) + len(key) + len(value))
+
+		if _, ok := s.readRegisters[mapKey]; !ok {
+			s.readRegisters[mapKey] = struct{}{}
+			s.registerReads[owner]++
+			if err := s.checkRegisterCount(owner); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return value, s.checkMaxInteraction()
@@ -142,6 +181,11 @@ func (s *State) Set(owner, controller, key string, value flow.RegisterValue) err
 	if old, ok := s.updateSize[mapKey]; ok {
 		s.WriteCounter--
 		s.TotalBytesWritten -= old
+	} else {
+		s.registerWrites[owner]++
+		if err := s.checkRegisterCount(owner); err != nil {
+			return err
+		}
 	}
 
 	updateSize := uint64(len(owner) + len(controller) + len(key) + len(value))
@@ -167,6 +211,8 @@ func (s *State) NewChild() *State {
 		WithMaxKeySizeAllowed(s.maxKeySizeAllowed),
 		WithMaxValueSizeAllowed(s.maxValueSizeAllowed),
 		WithMaxInteractionSizeAllowed(s.maxInteractionAllowed),
+		WithMaxRegisterReadsAllowed(s.maxRegisterReads),
+		WithMaxRegisterWritesAllowed(s.maxRegisterWrites),
 	)
 }
 
@@ -182,10 +228,33 @@ func (s *State) MergeState(other *State) error {
 		s.updatedAddresses[k] = v
 	}
 
-	// apply update sizes
+	// apply distinct register reads and update sizes
+	for k := range other.readRegisters {
+		if _, ok := s.readRegisters[k]; ok {
+			continue
+		}
+		if _, ok := s.updateSize[k]; ok {
+			continue
+		}
+		s.readRegisters[k] = struct{}{}
+		s.registerReads[k.owner]++
+	}
 	for k, v := range other.updateSize {
+		if _, ok := s.updateSize[k]; !ok {
+			s.registerWrites[k.owner]++
+		}
 		s.updateSize[k] = v
 	}
+	for owner := range other.registerReads {
+		if err := s.checkRegisterCount(owner); err != nil {
+			return err
+		}
+	}
+	for owner := range other.registerWrites {
+		if err := s.checkRegisterCount(owner); err != nil {
+			return err
+		}
+	}
 
 	// update ledger interactions
 	s.ReadCounter += other.ReadCounter
@@ -213,6 +282,18 @@ func (s *State) checkMaxInteraction() error {
 	return nil
 }
 
+// checkRegisterCount checks the number of distinct registers of the owner read and written, which bounds
+// the size of the trie updates of transactions regardless of the size of the registers
+func (s *State) checkRegisterCount(owner string) error {
+	if reads := s.registerReads[owner]; reads > s.maxRegisterReads {
+		return errors.NewRegisterCountLimitExceededError(owner, "read", reads, s.maxRegisterReads)
+	}
+	if writes := s.registerWrites[owner]; writes > s.maxRegisterWrites {
+		return errors.NewRegisterCountLimitExceededError(owner, "written", writes, s.maxRegisterWrites)
+	}
+	return nil
+}
+
 func (s *State) checkSize(owner, controller, key string, value flow.RegisterValue) error {
 	keySize := uint64(len(owner) + len(controller) + len(key))
 	valueSize := uint64(len(value))
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/fvm/utils"
 )
@@ -195,6 +196,64 @@ func TestState_MaxInteraction(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestState_MaxRegisterCount(t *testing.T) {
+	view := utils.NewSimpleView()
+
+	t.Run("reads", func(t *testing.T) {
+		st := state.NewState(view, state.WithMaxRegisterReadsAllowed(2))
+
+		_, err := st.Get("A", "", "1")
+		require.NoError(t, err)
+		_, err = st.Get("A", "", "2")
+		require.NoError(t, err)
+
+		// registers read again and registers of other addresses aren't counted
+		_, err = st.Get("A", "", "1")
+		require.NoError(t, err)
+		_, err = st.Get("B", "", "1")
+		require.NoError(t, err)
+
+		// registers updated before aren't read from storage
+		err = st.Set("A", "", "3", []byte{'A'})
+		require.NoError(t, err)
+		_, err = st.Get("A", "", "3")
+		require.NoError(t, err)
+
+		_, err = st.Get("A", "", "4")
+		require.Error(t, err)
+		var limitErr *errors.RegisterCountLimitExceededError
+		require.ErrorAs(t, err, &limitErr)
+	})
+
+	t.Run("writes", func(t *testing.T) {
+		st := state.NewState(view, state.WithMaxRegisterWritesAllowed(2))
+
+		err := st.Set("A", "", "1", []byte{'A'})
+		require.NoError(t, err)
+		err = st.Set("A", "", "1", []byte{'B'})
+		require.NoError(t, err)
+		err = st.Set("B", "", "1", []byte{'A'})
+		require.NoError(t, err)
+
+		// registers written by children count once merged
+		child := st.NewChild()
+		err = child.Set("A", "", "1", []byte{'C'})
+		require.NoError(t, err)
+		err = child.Set("A", "", "2", []byte{'A'})
+		require.NoError(t, err)
+		err = st.MergeState(child)
+		require.NoError(t, err)
+
+		child = st.NewChild()
+		err = child.Set("A", "", "3", []byte{'A'})
+		require.NoError(t, err)
+		err = st.MergeState(child)
+		require.Error(t, err)
+		var limitErr *errors.RegisterCountLimitExceededError
+		require.ErrorAs(t, err, &limitErr)
+	})
+}
+
 func TestState_IsFVMStateKey(t *testing.T) {
 	require.True(t, state.IsFVMStateKey("", "", "uuid"))
 	require.True(t, state.IsFVMStateKey("Address", "Address", state.KeyPublicKeyCount))
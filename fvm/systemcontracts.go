@@ -0,0 +1,216 @@
+package fvm
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/stdlib"
+
+	"github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SystemContractCall is a call of a service contract made by the system chunk.
+//
+// Every call is executed as a separate transaction authorized by the service account, so a
+// failing call neither reverts nor prevents the other calls of the system chunk.
+type SystemContractCall struct {
+	// Name identifies the call, e.g. in the events reporting its failure.
+	Name string
+	// Script returns the code of the transaction making the call on the given chain.
+	Script func(chain flow.Chain) []byte
+}
+
+// Transaction returns the transaction making the call on the given chain.
+func (c SystemContractCall) Transaction(chain flow.Chain) *flow.TransactionBody {
+	return flow.NewTransactionBody().
+		SetScript(c.Script(chain)).
+		AddAuthorizer(chain.ServiceAddress())
+}
+
+const epochHeartbeatTransactionTemplate = `
+import FlowServiceAccount from 0x%s
+transaction {
+  prepare(serviceAccount: AuthAccount) { 
+
+  }
+
+  execute {
+    // TODO: replace with call to service account heartbeat
+ 	log("pulse")
+  }
+} 
+`
+
+// EpochHeartbeatCall is the call of the epoch heartbeat, which advances the epoch state
+// machine of the service account.
+var EpochHeartbeatCall = SystemContractCall{
+	Name: "EpochHeartbeat",
+	Script: func(chain flow.Chain) []byte {
+		return []byte(fmt.Sprintf(epochHeartbeatTransactionTemplate, chain.ServiceAddress()))
+	},
+}
+
+const feeBurnTransactionTemplate = `
+import FlowFees from 0x%s
+import FlowToken from 0x%s
+
+transaction {
+  prepare(serviceAccount: AuthAccount) {
+    let feesAdmin = serviceAccount.borrow<&FlowFees.Administrator>(from: /storage/flowFeesAdmin)
+      ?? panic("Could not borrow reference to the fees administrator")
+    let tokenAdmin = serviceAccount.borrow<&FlowToken.Administrator>(from: /storage/flowTokenAdmin)
+      ?? panic("Could not borrow reference to the token administrator")
+
+    let fees <- feesAdmin.withdrawTokensFromFeeVault(amount: FlowFees.getFeeBalance())
+    let burner <- tokenAdmin.createNewBurner()
+    burner.burnTokens(from: <-fees)
+    destroy burner
+  }
+}
+`
+
+// FeeBurnCall is the call burning the transaction fees collected in the fee vault during the block.
+var FeeBurnCall = SystemContractCall{
+	Name: "FeeBurn",
+	Script: func(chain flow.Chain) []byte {
+		return []byte(fmt.Sprintf(feeBurnTransactionTemplate, FlowFeesAddress(chain), FlowTokenAddress(chain)))
+	},
+}
+
+// SystemContractActivation enables a call in the system chunks of the blocks of an epoch and of
+// all following epochs.
+type SystemContractActivation struct {
+	Call      SystemContractCall
+	FromEpoch uint64 // counter of the first epoch making the call
+}
+
+// defaultSystemContractActivations are the calls of chains without a specific configuration.
+var defaultSystemContractActivations = []SystemContractActivation{
+	{Call: EpochHeartbeatCall},
+}
+
+// Epochs from which the transaction fees collected during a block are burnt by the system chunk.
+// Burning starts on testnet together with the fee schedule of its next spork, and only once the
+// first epoch transition of an emulator chain happened, so that blocks executed before remain
+// reproducible.
+const (
+	testnetFeeBurnEpoch  uint64 = 20
+	emulatorFeeBurnEpoch uint64 = 1
+)
+
+// systemContractActivations holds the calls of the system chunk per chain. Calls of further
+// service contracts (e.g. the version beacon) are activated on a chain from the epoch on which
+// their contract is deployed.
+var systemContractActivations = map[flow.ChainID][]SystemContractActivation{
+	flow.Mainnet: {
+		{Call: EpochHeartbeatCall},
+	},
+	flow.Testnet: {
+		{Call: EpochHeartbeatCall},
+		{Call: FeeBurnCall, FromEpoch: testnetFeeBurnEpoch},
+	},
+	flow.Emulator: {
+		{Call: EpochHeartbeatCall},
+		{Call: FeeBurnCall, FromEpoch: emulatorFeeBurnEpoch},
+	},
+	flow.MonotonicEmulator: {
+		{Call: EpochHeartbeatCall},
+		{Call: FeeBurnCall, FromEpoch: emulatorFeeBurnEpoch},
+	},
+}
+
+// SystemContracts describes the service contract calls made by the system chunk of a chain.
+type SystemContracts struct {
+	Chain       flow.Chain
+	Activations []SystemContractActivation
+}
+
+// SystemContractsForChain returns the system contracts configured for the given chain.
+func SystemContractsForChain(chain flow.Chain) SystemContracts {
+	activations, ok := systemContractActivations[chain.ChainID()]
+	if !ok {
+		activations = defaultSystemContractActivations
+	}
+	return SystemContracts{
+		Chain:       chain,
+		Activations: activations,
+	}
+}
+
+// Active returns the activations whose calls are made by the system chunks of the blocks of the
+// given epoch, in order. The returned activations point into the activations of s, so they can be
+// told apart even when they activate calls of the same name, e.g. two versions of a call.
+func (s SystemContracts) Active(epochCounter uint64) []*SystemContractActivation {
+	active := make([]*SystemContractActivation, 0, len(s.Activations))
+	for i := range s.Activations {
+		if s.Activations[i].FromEpoch <= epochCounter {
+			active = append(active, &s.Activations[i])
+		}
+	}
+	return active
+}
+
+// Calls returns the calls made by the system chunks of the blocks of the given epoch, in the
+// order of their activations.
+func (s SystemContracts) Calls(epochCounter uint64) []SystemContractCall {
+	active := s.Active(epochCounter)
+	calls := make([]SystemContractCall, 0, len(active))
+	for _, activation := range active {
+		calls = append(calls, activation.Call)
+	}
+	return calls
+}
+
+// Transactions returns the transactions of the system chunks of the blocks of the given epoch,
+// one per call and in the order of the calls.
+func (s SystemContracts) Transactions(epochCounter uint64) []*flow.TransactionBody {
+	calls := s.Calls(epochCounter)
+	txs := make([]*flow.TransactionBody, 0, len(calls))
+	for _, call := range calls {
+		txs = append(txs, call.Transaction(s.Chain))
+	}
+	return txs
+}
+
+var systemContractCallFailedType = &cadence.EventType{
+	Location:            stdlib.FlowLocation{},
+	QualifiedIdentifier: "SystemContractCallFailed",
+	Fields: []cadence.Field{
+		{
+			Identifier: "name",
+			Type:       cadence.StringType{},
+		},
+		{
+			Identifier: "error",
+			Type:       cadence.StringType{},
+		},
+	},
+}
+
+// SystemContractCallFailedEvent returns the service event reporting the failure of the given call,
+// which was executed as the given failed transaction.
+func SystemContractCallFailedEvent(call SystemContractCall, tx *TransactionProcedure) (flow.Event, error) {
+	if tx.Err == nil {
+		return flow.Event{}, fmt.Errorf("system contract call %s did not fail", call.Name)
+	}
+
+	event := cadence.NewEvent([]cadence.Value{
+		cadence.NewString(call.Name),
+		cadence.NewString(tx.Err.Error()),
+	}).WithType(systemContractCallFailedType)
+
+	payload, err := jsoncdc.Encode(event)
+	if err != nil {
+		return flow.Event{}, errors.NewEncodingFailuref("failed to json encode a cadence event: %w", err)
+	}
+
+	return flow.Event{
+		Type:             flow.EventSystemContractCallFailed,
+		TransactionID:    tx.ID,
+		TransactionIndex: tx.TxIndex,
+		EventIndex:       uint32(len(tx.Events)),
+		Payload:          payload,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package fvm_test
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestSystemContracts(t *testing.T) {
+
+	rt := fvm.NewInterpreterRuntime()
+	chain := flow.Testnet.Chain()
+	vm := fvm.NewVirtualMachine(rt)
+
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+	ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+	systemChunkCtx := fvm.NewContextFromParent(ctx,
+		fvm.WithServiceEventCollectionEnabled(),
+		fvm.WithTransactionProcessors(fvm.NewTransactionInvocator(zerolog.Nop())),
+	)
+
+	t.Run("calls are executed as separate transactions", func(t *testing.T) {
+		contracts := fvm.SystemContractsForChain(chain)
+		// all calls are made from the epoch of the latest activation
+		epoch := contracts.Activations[len(contracts.Activations)-1].FromEpoch
+		calls := contracts.Calls(epoch)
+		require.Equal(t, []string{fvm.EpochHeartbeatCall.Name, fvm.FeeBurnCall.Name}, callNames(calls))
+
+		txs := contracts.Transactions(epoch)
+		require.Len(t, txs, len(calls))
+
+		for i, txBody := range txs {
+			require.Equal(t, []flow.Address{chain.ServiceAddress()}, txBody.Authorizers)
+
+			tx := fvm.Transaction(txBody, uint32(i))
+			err := vm.Run(systemChunkCtx, tx, ledger.NewChild(), programs.NewEmptyPrograms())
+			require.NoError(t, err)
+			require.NoError(t, tx.Err, calls[i].Name)
+		}
+	})
+
+	t.Run("calls are configured per chain", func(t *testing.T) {
+		calls := fvm.SystemContractsForChain(flow.Mainnet.Chain()).Calls(0)
+		require.Equal(t, []string{fvm.EpochHeartbeatCall.Name}, callNames(calls))
+	})
+
+	t.Run("fees are not burnt before activation", func(t *testing.T) {
+		for _, chainID := range []flow.ChainID{flow.Testnet, flow.Emulator, flow.MonotonicEmulator} {
+			calls := fvm.SystemContractsForChain(chainID.Chain()).Calls(0)
+			require.Equal(t, []string{fvm.EpochHeartbeatCall.Name}, callNames(calls), chainID)
+		}
+	})
+
+	t.Run("calls are activated per epoch", func(t *testing.T) {
+		contracts := fvm.SystemContracts{
+			Chain: chain,
+			Activations: []fvm.SystemContractActivation{
+				{Call: fvm.EpochHeartbeatCall},
+				{Call: fvm.FeeBurnCall, FromEpoch: 2},
+			},
+		}
+
+		require.Equal(t, []string{fvm.EpochHeartbeatCall.Name}, callNames(contracts.Calls(1)))
+		require.Equal(t, []string{fvm.EpochHeartbeatCall.Name, fvm.FeeBurnCall.Name}, callNames(contracts.Calls(2)))
+		require.Len(t, contracts.Transactions(1), 1)
+		require.Len(t, contracts.Transactions(3), 2)
+	})
+
+	t.Run("versions of a call are told apart", func(t *testing.T) {
+		upgraded := fvm.SystemContractCall{
+			Name: fvm.FeeBurnCall.Name,
+			Script: func(chain flow.Chain) []byte {
+				return []byte(`transaction { prepare(serviceAccount: AuthAccount) {} }`)
+			},
+		}
+		contracts := fvm.SystemContracts{
+			Chain: chain,
+			Activations: []fvm.SystemContractActivation{
+				{Call: fvm.FeeBurnCall},
+				{Call: upgraded, FromEpoch: 2},
+			},
+		}
+
+		active := contracts.Active(2)
+		require.Len(t, active, 2)
+		require.Same(t, &contracts.Activations[0], active[0])
+		require.Same(t, &contracts.Activations[1], active[1])
+		require.NotEqual(t, active[0].Call.Script(chain), active[1].Call.Script(chain))
+	})
+
+	t.Run("failed call is reported", func(t *testing.T) {
+		call := fvm.SystemContractCall{
+			Name: "Failing",
+			Script: func(chain flow.Chain) []byte {
+				return []byte(`transaction { prepare(serviceAccount: AuthAccount) { panic("failed") } }`)
+			},
+		}
+
+		tx := fvm.Transaction(call.Transaction(chain), 0)
+		err := vm.Run(systemChunkCtx, tx, ledger.NewChild(), programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.Error(t, tx.Err)
+
+		event, err := fvm.SystemContractCallFailedEvent(call, tx)
+		require.NoError(t, err)
+		require.Equal(t, flow.EventSystemContractCallFailed, event.Type)
+		require.Equal(t, tx.ID, event.TransactionID)
+
+		decoded, err := jsoncdc.Decode(event.Payload)
+		require.NoError(t, err)
+		fields := decoded.(cadence.Event).Fields
+		require.Equal(t, cadence.NewString(call.Name), fields[0])
+		require.Equal(t, cadence.NewString(tx.Err.Error()), fields[1])
+
+		// the failure is reported as a service event in the execution result
+		serviceEvent, err := flow.ConvertServiceEvent(event)
+		require.NoError(t, err)
+		require.Equal(t, flow.ServiceEventSystemContractCallFailed, serviceEvent.Type)
+		require.Equal(t, &flow.SystemContractCallFailed{Call: call.Name, Error: tx.Err.Error()}, serviceEvent.Event)
+	})
+
+	t.Run("successful call is not reported", func(t *testing.T) {
+		tx := fvm.Transaction(fvm.EpochHeartbeatCall.Transaction(chain), 0)
+		err := vm.Run(systemChunkCtx, tx, ledger.NewChild(), programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+
+		_, err = fvm.SystemContractCallFailedEvent(fvm.EpochHeartbeatCall, tx)
+		require.Error(t, err)
+	})
+}
+
+func callNames(calls []fvm.SystemContractCall) []string {
+	names := make([]string, 0, len(calls))
+	for _, call := range calls {
+		names = append(names, call.Name)
+	}
+	return names
+}
@@ -1,6 +1,7 @@
 package fvm
 
 import (
+	"github.com/onflow/cadence/runtime"
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/onflow/flow-go/fvm/errors"
@@ -32,6 +33,8 @@ type TransactionProcedure struct {
 	Err           errors.Error
 	Retried       int
 	TraceSpan     opentracing.Span
+	// CoverageReport holds the coverage collected when running with WithCoverageReport.
+	CoverageReport *runtime.CoverageReport
 }
 
 func (proc *TransactionProcedure) SetTraceSpan(traceSpan opentracing.Span) {
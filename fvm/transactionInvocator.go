@@ -121,12 +121,15 @@ func (i *TransactionInvocator) Process(
 			env = newEnvironment(*ctx, vm, sth, programs)
 		}
 
-		env.setTransaction(proc.Transaction, proc.TxIndex)
+		err := env.setTransaction(proc.Transaction, proc.TxIndex)
+		if err != nil {
+			return fmt.Errorf("transaction invocation failed: %w", err)
+		}
 		env.setTraceSpan(span)
 
 		location := common.TransactionLocation(proc.ID[:])
 
-		err := vm.Runtime.ExecuteTransaction(
+		err = vm.Runtime.ExecuteTransaction(
 			runtime.Script{
 				Source:    proc.Transaction.Script,
 				Arguments: proc.Transaction.Arguments,
@@ -298,6 +301,10 @@ func valueDeclarations(ctx *Context, env *hostEnv) []runtime.ValueDeclaration {
 
 		predeclaredValues = append(predeclaredValues, setAccountFrozen)
 	}
+
+	if ctx.AccountKeyMetadataEnabled {
+		predeclaredValues = append(predeclaredValues, accountKeyValueDeclarations(env)...)
+	}
 	return predeclaredValues
 }
 
@@ -0,0 +1,78 @@
+package fvm
+
+import (
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/module/trace"
+)
+
+const (
+	DefaultMaxTransactionByteSize      = 1_500_000 // 1.5MB
+	DefaultMaxTransactionArgumentCount = 100
+	DefaultMaxTransactionAuthorizers   = 100
+)
+
+// TransactionSizeLimiter rejects transactions whose payload exceeds the configured
+// byte size, argument count or authorizer count limits.
+//
+// The limiter is expected to be placed at the front of the transaction processors,
+// so that oversized transactions are rejected before any script parsing or state access happens.
+// A limit of zero disables the corresponding check.
+type TransactionSizeLimiter struct {
+	maxTxByteSize  uint64
+	maxArgs        uint64
+	maxAuthorizers uint64
+}
+
+func NewTransactionSizeLimiter(maxTxByteSize, maxArgs, maxAuthorizers uint64) *TransactionSizeLimiter {
+	return &TransactionSizeLimiter{
+		maxTxByteSize:  maxTxByteSize,
+		maxArgs:        maxArgs,
+		maxAuthorizers: maxAuthorizers,
+	}
+}
+
+func (l *TransactionSizeLimiter) Process(
+	_ *VirtualMachine,
+	ctx *Context,
+	proc *TransactionProcedure,
+	_ *state.StateHolder,
+	_ *programs.Programs,
+) error {
+
+	if ctx.Tracer != nil && proc.TraceSpan != nil {
+		span := ctx.Tracer.StartSpanFromParent(proc.TraceSpan, trace.FVMSizeLimitCheckTransaction)
+		span.LogFields(
+			log.String("transaction.ID", proc.ID.String()),
+		)
+		defer span.Finish()
+	}
+
+	tx := proc.Transaction
+
+	if l.maxTxByteSize > 0 {
+		txByteSize := uint64(tx.ByteSize())
+		if txByteSize > l.maxTxByteSize {
+			return errors.NewInvalidTxByteSizeError(txByteSize, l.maxTxByteSize)
+		}
+	}
+
+	if l.maxArgs > 0 {
+		argCount := uint64(len(tx.Arguments))
+		if argCount > l.maxArgs {
+			return errors.NewInvalidTxArgumentCountError(argCount, l.maxArgs)
+		}
+	}
+
+	if l.maxAuthorizers > 0 {
+		authorizerCount := uint64(len(tx.Authorizers))
+		if authorizerCount > l.maxAuthorizers {
+			return errors.NewInvalidTxAuthorizerCountError(authorizerCount, l.maxAuthorizers)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package fvm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/fvm/utils"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestTransactionSizeLimiter(t *testing.T) {
+
+	process := func(limiter *fvm.TransactionSizeLimiter, tx *flow.TransactionBody) error {
+		sth := state.NewStateHolder(state.NewState(utils.NewSimpleView()))
+		proc := fvm.Transaction(tx, 0)
+		return limiter.Process(nil, &fvm.Context{}, proc, sth, programs.NewEmptyPrograms())
+	}
+
+	t.Run("transaction within limits", func(t *testing.T) {
+		tx := flow.NewTransactionBody().
+			SetScript([]byte("transaction {}")).
+			AddArgument([]byte("1")).
+			AddAuthorizer(flow.HexToAddress("01"))
+
+		err := process(fvm.NewTransactionSizeLimiter(1000, 1, 1), tx)
+		require.NoError(t, err)
+	})
+
+	t.Run("oversized transaction", func(t *testing.T) {
+		tx := flow.NewTransactionBody().
+			SetScript(make([]byte, 2000))
+
+		err := process(fvm.NewTransactionSizeLimiter(1000, 0, 0), tx)
+		require.Error(t, err)
+		require.Equal(t, errors.ErrCodeInvalidTxByteSizeError, err.(errors.Error).Code())
+	})
+
+	t.Run("too many arguments", func(t *testing.T) {
+		tx := flow.NewTransactionBody().
+			SetScript([]byte("transaction {}")).
+			AddArgument([]byte("1")).
+			AddArgument([]byte("2"))
+
+		err := process(fvm.NewTransactionSizeLimiter(0, 1, 0), tx)
+		require.Error(t, err)
+		require.Equal(t, errors.ErrCodeInvalidTxArgumentCountError, err.(errors.Error).Code())
+	})
+
+	t.Run("too many authorizers", func(t *testing.T) {
+		tx := flow.NewTransactionBody().
+			SetScript([]byte("transaction {}")).
+			AddAuthorizer(flow.HexToAddress("01")).
+			AddAuthorizer(flow.HexToAddress("02"))
+
+		err := process(fvm.NewTransactionSizeLimiter(0, 0, 1), tx)
+		require.Error(t, err)
+		require.Equal(t, errors.ErrCodeInvalidTxAuthorizerCountError, err.(errors.Error).Code())
+	})
+
+	t.Run("zero limits disable checks", func(t *testing.T) {
+		tx := flow.NewTransactionBody().
+			SetScript(make([]byte, 2000)).
+			AddArgument([]byte("1")).
+			AddArgument([]byte("2")).
+			AddAuthorizer(flow.HexToAddress("01")).
+			AddAuthorizer(flow.HexToAddress("02"))
+
+		err := process(fvm.NewTransactionSizeLimiter(0, 0, 0), tx)
+		require.NoError(t, err)
+	})
+}
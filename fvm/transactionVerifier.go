@@ -118,6 +118,15 @@ func (v *TransactionSignatureVerifier) verifyTransactionSignatures(
 		return errors.NewAccountAuthorizationErrorf(tx.Payer, msg)
 	}
 
+	// only count transactions with valid signatures, so nobody but the payer can consume its quota
+	if ctx.PayerRateLimiter != nil && ctx.BlockHeader != nil {
+		limit := ctx.PayerRateLimiter.Limit(tx.Payer, ctx.BlockHeader.Height)
+		err = countPayerTransaction(sth, tx.Payer, ctx.BlockHeader.Height, limit)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
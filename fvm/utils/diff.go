@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// RegisterDiff captures the values of a single register in the two compared views.
+// A nil value indicates that the register was not updated in the corresponding view.
+type RegisterDiff struct {
+	ID flow.RegisterID
+	A  flow.RegisterValue
+	B  flow.RegisterValue
+}
+
+// Report is a register-level diff between the updates of two views.
+type Report struct {
+	// Mismatched contains registers updated in both views, but with different values.
+	Mismatched []RegisterDiff
+	// OnlyInA contains registers only updated in the first view.
+	OnlyInA []RegisterDiff
+	// OnlyInB contains registers only updated in the second view.
+	OnlyInB []RegisterDiff
+}
+
+// IsEmpty returns true if the compared views have identical register updates.
+func (r Report) IsEmpty() bool {
+	return len(r.Mismatched) == 0 && len(r.OnlyInA) == 0 && len(r.OnlyInB) == 0
+}
+
+// String returns a human readable representation of the report, listing
+// every differing register with its values in both views.
+func (r Report) String() string {
+	if r.IsEmpty() {
+		return "no register differences"
+	}
+
+	var sb strings.Builder
+	write := func(title string, diffs []RegisterDiff) {
+		if len(diffs) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(&sb, "%s (%d):\n", title, len(diffs))
+		for _, diff := range diffs {
+			_, _ = fmt.Fprintf(&sb, "  %s\n    a: %x\n    b: %x\n", diff.ID.String(), diff.A, diff.B)
+		}
+	}
+
+	write("mismatched registers", r.Mismatched)
+	write("registers only updated in a", r.OnlyInA)
+	write("registers only updated in b", r.OnlyInB)
+
+	return sb.String()
+}
+
+// DiffViews compares the register updates of the two given views and returns a
+// report of all registers whose updated values differ. Registers are reported in
+// a deterministic order.
+func DiffViews(a, b state.View) Report {
+	updatesA := registerUpdates(a)
+	updatesB := registerUpdates(b)
+
+	var report Report
+	for key, entryA := range updatesA {
+		entryB, ok := updatesB[key]
+		if !ok {
+			report.OnlyInA = append(report.OnlyInA, RegisterDiff{ID: entryA.Key, A: entryA.Value})
+			continue
+		}
+		if !bytes.Equal(entryA.Value, entryB.Value) {
+			report.Mismatched = append(report.Mismatched, RegisterDiff{ID: entryA.Key, A: entryA.Value, B: entryB.Value})
+		}
+	}
+	for key, entryB := range updatesB {
+		if _, ok := updatesA[key]; !ok {
+			report.OnlyInB = append(report.OnlyInB, RegisterDiff{ID: entryB.Key, B: entryB.Value})
+		}
+	}
+
+	sortDiffs(report.Mismatched)
+	sortDiffs(report.OnlyInA)
+	sortDiffs(report.OnlyInB)
+
+	return report
+}
+
+func registerUpdates(view state.View) map[string]flow.RegisterEntry {
+	ids, values := view.RegisterUpdates()
+	updates := make(map[string]flow.RegisterEntry, len(ids))
+	for i, id := range ids {
+		updates[fullKey(id.Owner, id.Controller, id.Key)] = flow.RegisterEntry{Key: id, Value: values[i]}
+	}
+	return updates
+}
+
+func sortDiffs(diffs []RegisterDiff) {
+	sort.Slice(diffs, func(i, j int) bool {
+		return fullKey(diffs[i].ID.Owner, diffs[i].ID.Controller, diffs[i].ID.Key) <
+			fullKey(diffs[j].ID.Owner, diffs[j].ID.Controller, diffs[j].ID.Key)
+	})
+}
@@ -0,0 +1,59 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/fvm/utils"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestDiffViews(t *testing.T) {
+
+	t.Run("identical views", func(t *testing.T) {
+		a := utils.NewSimpleView()
+		b := utils.NewSimpleView()
+
+		require.NoError(t, a.Set("owner", "controller", "key", []byte{1}))
+		require.NoError(t, b.Set("owner", "controller", "key", []byte{1}))
+
+		report := utils.DiffViews(a, b)
+		require.True(t, report.IsEmpty())
+	})
+
+	t.Run("differing views", func(t *testing.T) {
+		a := utils.NewSimpleView()
+		b := utils.NewSimpleView()
+
+		require.NoError(t, a.Set("owner", "controller", "same", []byte{1}))
+		require.NoError(t, b.Set("owner", "controller", "same", []byte{1}))
+
+		require.NoError(t, a.Set("owner", "controller", "changed", []byte{1}))
+		require.NoError(t, b.Set("owner", "controller", "changed", []byte{2}))
+
+		require.NoError(t, a.Set("owner", "controller", "a", []byte{3}))
+		require.NoError(t, b.Set("owner", "controller", "b", []byte{4}))
+
+		report := utils.DiffViews(a, b)
+		require.False(t, report.IsEmpty())
+
+		require.Equal(t, []utils.RegisterDiff{{
+			ID: flow.NewRegisterID("owner", "controller", "changed"),
+			A:  []byte{1},
+			B:  []byte{2},
+		}}, report.Mismatched)
+
+		require.Equal(t, []utils.RegisterDiff{{
+			ID: flow.NewRegisterID("owner", "controller", "a"),
+			A:  []byte{3},
+		}}, report.OnlyInA)
+
+		require.Equal(t, []utils.RegisterDiff{{
+			ID: flow.NewRegisterID("owner", "controller", "b"),
+			B:  []byte{4},
+		}}, report.OnlyInB)
+
+		require.Contains(t, report.String(), "mismatched registers (1)")
+	})
+}
@@ -4,6 +4,7 @@ go 1.13
 
 require (
 	cloud.google.com/go/storage v1.10.0
+	github.com/DataDog/zstd v1.4.1
 	github.com/HdrHistogram/hdrhistogram-go v0.9.0 // indirect
 	github.com/bsipos/thist v1.0.0
 	github.com/btcsuite/btcd v0.20.1-beta
@@ -95,10 +95,11 @@ func QueryToTrieRead(q *ledger.Query, version uint8) (*ledger.TrieRead, error) {
 }
 
 // PayloadsToValues extracts values from an slice of payload
+// the values are copied, as the payloads may be shared with a trie and must not be modified
 func PayloadsToValues(payloads []*ledger.Payload) ([]ledger.Value, error) {
-	ret := make([]ledger.Value, 0)
+	ret := make([]ledger.Value, 0, len(payloads))
 	for _, p := range payloads {
-		ret = append(ret, p.Value)
+		ret = append(ret, p.Value.DeepCopy())
 	}
 	return ret, nil
 }
@@ -0,0 +1,225 @@
+package complete
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+// The state export format streams the registers of a trie in ascending order of paths:
+//   - a header holding the magic bytes, the version and the root hash of the trie
+//   - chunks of registers, each holding the number of registers, the length of the chunk body,
+//     the body and a CRC32 checksum of the body. Each register in the body is encoded as
+//     its path followed by its encoded payload.
+//   - an empty chunk, marking the end of the export
+const exportMagicBytes uint16 = 0x2138
+const exportVersion uint16 = 0x01
+
+// exportChunkSize is the number of registers per chunk of a state export
+const exportChunkSize = 1000
+
+// ExportState streams the registers of the trie at the given state to the writer, in ascending
+// order of paths, so that exporting the same state always results in the same output.
+func (l *Ledger) ExportState(state ledger.State, w io.Writer) error {
+	t, err := l.forest.GetTrie(ledger.RootHash(state))
+	if err != nil {
+		return fmt.Errorf("cannot find the target trie: %w", err)
+	}
+
+	writer := bufio.NewWriter(w)
+
+	rootHash := t.RootHash()
+	header := make([]byte, 0, 2+2+len(rootHash))
+	header = utils.AppendUint16(header, exportMagicBytes)
+	header = utils.AppendUint16(header, exportVersion)
+	header = append(header, rootHash[:]...)
+	_, err = writer.Write(header)
+	if err != nil {
+		return fmt.Errorf("cannot write export header: %w", err)
+	}
+
+	var body []byte
+	count := 0
+	err = t.IteratePayloads(func(path ledger.Path, payload *ledger.Payload) error {
+		body = append(body, path[:]...)
+		body = utils.AppendLongData(body, encoding.EncodePayload(payload))
+		count++
+		if count < exportChunkSize {
+			return nil
+		}
+		err := writeExportChunk(writer, count, body)
+		body = body[:0]
+		count = 0
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot export registers: %w", err)
+	}
+
+	if count > 0 {
+		err = writeExportChunk(writer, count, body)
+		if err != nil {
+			return fmt.Errorf("cannot export registers: %w", err)
+		}
+	}
+
+	// empty chunk marks the end of the export
+	err = writeExportChunk(writer, 0, nil)
+	if err != nil {
+		return fmt.Errorf("cannot write end of export: %w", err)
+	}
+
+	return writer.Flush()
+}
+
+func writeExportChunk(w io.Writer, count int, body []byte) error {
+	buf := make([]byte, 0, 4+4)
+	buf = utils.AppendUint32(buf, uint32(count))
+	buf = utils.AppendUint32(buf, uint32(len(body)))
+	_, err := w.Write(buf)
+	if err != nil {
+		return fmt.Errorf("cannot write chunk header: %w", err)
+	}
+
+	_, err = w.Write(body)
+	if err != nil {
+		return fmt.Errorf("cannot write chunk: %w", err)
+	}
+
+	_, err = w.Write(utils.AppendUint32(nil, crc32.ChecksumIEEE(body)))
+	if err != nil {
+		return fmt.Errorf("cannot write chunk checksum: %w", err)
+	}
+	return nil
+}
+
+// ImportState reads a state export and adds the trie holding its registers to the ledger.
+// The trie is built chunk by chunk, so the registers are never held in memory besides the trie.
+// The chunks are recorded in the write-ahead log as updates, so that the imported state is
+// restored with the ledger. It returns the imported state, which is verified to match the root
+// hash of the export.
+func (l *Ledger) ImportState(r io.Reader) (ledger.State, error) {
+	reader := bufio.NewReader(r)
+
+	header := make([]byte, 2+2+hash.HashLen)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return ledger.State(hash.DummyHash), fmt.Errorf("cannot read export header: %w", err)
+	}
+	magicBytes, rest, _ := utils.ReadUint16(header)
+	version, rest, _ := utils.ReadUint16(rest)
+	if magicBytes != exportMagicBytes {
+		return ledger.State(hash.DummyHash), fmt.Errorf("unknown export format. Magic constant %x does not match expected %x", magicBytes, exportMagicBytes)
+	}
+	if version != exportVersion {
+		return ledger.State(hash.DummyHash), fmt.Errorf("unsupported export version %x", version)
+	}
+	expectedRootHash, err := hash.ToHash(rest)
+	if err != nil {
+		return ledger.State(hash.DummyHash), fmt.Errorf("cannot read root hash: %w", err)
+	}
+
+	imported := trie.NewEmptyMTrie()
+	var lastPath *ledger.Path
+	for chunk := 0; ; chunk++ {
+		paths, payloads, err := readExportChunk(reader)
+		if err != nil {
+			return ledger.State(hash.DummyHash), fmt.Errorf("cannot read chunk %d: %w", chunk, err)
+		}
+		if len(paths) == 0 {
+			break
+		}
+
+		// registers are exported in ascending order of paths, which also rules out duplicates
+		for i := range paths {
+			if lastPath != nil && bytes.Compare(lastPath[:], paths[i][:]) >= 0 {
+				return ledger.State(hash.DummyHash), fmt.Errorf("registers of chunk %d are not in ascending order of paths", chunk)
+			}
+			lastPath = &paths[i]
+		}
+
+		payloadPtrs := make([]*ledger.Payload, len(payloads))
+		for i := range payloads {
+			payloadPtrs[i] = &payloads[i]
+		}
+		err = l.wal.RecordUpdate(&ledger.TrieUpdate{RootHash: imported.RootHash(), Paths: paths, Payloads: payloadPtrs})
+		if err != nil {
+			return ledger.State(hash.DummyHash), fmt.Errorf("cannot record chunk %d in LedgerWAL: %w", chunk, err)
+		}
+
+		imported, err = trie.NewTrieWithUpdatedRegisters(imported, paths, payloads)
+		if err != nil {
+			return ledger.State(hash.DummyHash), fmt.Errorf("cannot import chunk %d: %w", chunk, err)
+		}
+	}
+
+	rootHash := imported.RootHash()
+	if rootHash != ledger.RootHash(expectedRootHash) {
+		return ledger.State(hash.DummyHash), fmt.Errorf("imported state %x does not match exported state %x", rootHash[:], expectedRootHash[:])
+	}
+
+	err = l.forest.AddTrie(imported)
+	if err != nil {
+		return ledger.State(hash.DummyHash), fmt.Errorf("cannot add imported trie: %w", err)
+	}
+
+	return ledger.State(rootHash), nil
+}
+
+func readExportChunk(r io.Reader) ([]ledger.Path, []ledger.Payload, error) {
+	buf := make([]byte, 4+4)
+	_, err := io.ReadFull(r, buf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read chunk header: %w", err)
+	}
+	count, rest, _ := utils.ReadUint32(buf)
+	length, _, _ := utils.ReadUint32(rest)
+
+	body := make([]byte, length+4)
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read chunk: %w", err)
+	}
+	checksum, _, _ := utils.ReadUint32(body[length:])
+	body = body[:length]
+	if crc32.ChecksumIEEE(body) != checksum {
+		return nil, nil, fmt.Errorf("chunk checksum failed")
+	}
+
+	paths := make([]ledger.Path, 0, count)
+	payloads := make([]ledger.Payload, 0, count)
+	for i := uint32(0); i < count; i++ {
+		pathBytes, rest, err := utils.ReadSlice(body, ledger.PathLen)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read path of register %d: %w", i, err)
+		}
+		path, err := ledger.ToPath(pathBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read path of register %d: %w", i, err)
+		}
+		encPayload, rest, err := utils.ReadLongData(rest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read payload of register %d: %w", i, err)
+		}
+		payload, err := encoding.DecodePayload(encPayload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot decode payload of register %d: %w", i, err)
+		}
+		paths = append(paths, path)
+		payloads = append(payloads, *payload)
+		body = rest
+	}
+	if len(body) > 0 {
+		return nil, nil, fmt.Errorf("chunk has %d trailing bytes", len(body))
+	}
+
+	return paths, payloads, nil
+}
@@ -1,8 +1,12 @@
 package complete
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -21,6 +25,10 @@ import (
 const DefaultCacheSize = 1000
 const DefaultPathFinderVersion = 1
 
+// DefaultMemoryReportInterval is the default number of updates between two reports of the memory
+// usage of the forest. Computing the memory usage walks all nodes of the forest.
+const DefaultMemoryReportInterval = 100
+
 // Ledger (complete) is a fast memory-efficient fork-aware thread-safe trie-based key/value storage.
 // Ledger holds an array of registers (key-value pairs) and keeps tracks of changes over a limited time.
 // Each register is referenced by an ID (key) and holds a value (byte slice).
@@ -30,7 +38,7 @@ const DefaultPathFinderVersion = 1
 // Ledger is fork-aware which means any update can be applied at any previous state which forms a tree of tries (forest).
 // The forest is in memory but all changes (e.g. register updates) are captured inside write-ahead-logs for crash recovery reasons.
 // In order to limit the memory usage and maintain the performance storage only keeps a limited number of
-// tries and purge the old ones (LRU-based); in other words, Ledger is not designed to be used
+// tries and purge the old ones which are not pinned (LRU-based); in other words, Ledger is not designed to be used
 // for archival usage but make it possible for other software components to reconstruct very old tries using write-ahead logs.
 type Ledger struct {
 	forest            *mtrie.Forest
@@ -38,6 +46,50 @@ type Ledger struct {
 	metrics           module.LedgerMetrics
 	logger            zerolog.Logger
 	pathFinderVersion uint8
+	readLimits        mtrie.ReadLimits
+	// unpins holds the states which were unpinned by the users of the ledger, but
+	// whose updates are not covered by a checkpoint yet
+	unpins   []pendingUnpin
+	unpinsMu sync.Mutex
+	// replica serves queries from a copy of the forest, if created
+	replica   *Replica
+	replicaMu sync.RWMutex
+	// memoryReportInterval is the number of updates between two reports of the memory usage
+	// of the forest, reporting is disabled if zero
+	memoryReportInterval uint64
+	updates              uint64 // number of updates, accessed atomically
+	reportingMemory      int32  // set while the memory usage is computed, accessed atomically
+}
+
+// pendingUnpin is a state to unpin once a checkpoint covers the given WAL segment.
+type pendingUnpin struct {
+	rootHash ledger.RootHash
+	segment  int
+}
+
+// Option configures a complete ledger.
+type Option func(*Ledger)
+
+// WithMaxPathsPerQuery limits the number of keys of a single read or proof query.
+func WithMaxPathsPerQuery(limit int) Option {
+	return func(l *Ledger) {
+		l.readLimits.MaxPaths = limit
+	}
+}
+
+// WithMaxProofSize limits the approximate size in bytes of the proof of a single query.
+func WithMaxProofSize(limit int) Option {
+	return func(l *Ledger) {
+		l.readLimits.MaxProofSize = limit
+	}
+}
+
+// WithMemoryReportInterval sets the number of updates between two reports of the memory usage
+// of the forest. Zero disables reporting the memory usage.
+func WithMemoryReportInterval(updates uint64) Option {
+	return func(l *Ledger) {
+		l.memoryReportInterval = updates
+	}
 }
 
 // NewLedger creates a new in-memory trie-backed ledger storage with persistence.
@@ -46,7 +98,8 @@ func NewLedger(
 	capacity int,
 	metrics module.LedgerMetrics,
 	log zerolog.Logger,
-	pathFinderVer uint8) (*Ledger, error) {
+	pathFinderVer uint8,
+	opts ...Option) (*Ledger, error) {
 
 	forest, err := mtrie.NewForest(capacity, metrics, func(evictedTrie *trie.MTrie) error {
 		return wal.RecordDelete(evictedTrie.RootHash())
@@ -58,12 +111,17 @@ func NewLedger(
 	logger := log.With().Str("ledger", "complete").Logger()
 
 	storage := &Ledger{
-		forest:            forest,
-		wal:               wal,
-		metrics:           metrics,
-		logger:            logger,
-		pathFinderVersion: pathFinderVer,
+		forest:               forest,
+		wal:                  wal,
+		metrics:              metrics,
+		logger:               logger,
+		pathFinderVersion:    pathFinderVer,
+		memoryReportInterval: DefaultMemoryReportInterval,
 	}
+	for _, apply := range opts {
+		apply(storage)
+	}
+	forest.SetReadLimits(storage.readLimits)
 
 	// pause records to prevent double logging trie removals
 	wal.PauseRecord()
@@ -76,9 +134,6 @@ func NewLedger(
 
 	wal.UnpauseRecord()
 
-	// TODO update to proper value once https://github.com/onflow/flow-go/pull/3720 is merged
-	metrics.ForestApproxMemorySize(0)
-
 	return storage, nil
 }
 
@@ -105,6 +160,7 @@ func (l *Ledger) InitialState() ledger.State {
 
 // Get read the values of the given keys at the given state
 // it returns the values in the same order as given registerIDs and errors (if any)
+// the read is aborted when the context of the query is done, or if it exceeds the read limits
 func (l *Ledger) Get(query *ledger.Query) (values []ledger.Value, err error) {
 	start := time.Now()
 	paths, err := pathfinder.KeysToPaths(query.Keys(), l.pathFinderVersion)
@@ -112,7 +168,7 @@ func (l *Ledger) Get(query *ledger.Query) (values []ledger.Value, err error) {
 		return nil, err
 	}
 	trieRead := &ledger.TrieRead{RootHash: ledger.RootHash(query.State()), Paths: paths}
-	payloads, err := l.forest.Read(trieRead)
+	payloads, err := l.forest.Read(query.Context(), trieRead)
 	if err != nil {
 		return nil, err
 	}
@@ -168,8 +224,8 @@ func (l *Ledger) Set(update *ledger.Update) (newState ledger.State, err error) {
 		return ledger.State(hash.DummyHash), fmt.Errorf("error while writing LedgerWAL: %w", walError)
 	}
 
-	// TODO update to proper value once https://github.com/onflow/flow-go/pull/3720 is merged
-	l.metrics.ForestApproxMemorySize(0)
+	l.publishToReplica(newRootHash)
+	l.reportMemoryUsage(newRootHash)
 
 	elapsed := time.Since(start)
 	l.metrics.UpdateDuration(elapsed)
@@ -187,7 +243,58 @@ func (l *Ledger) Set(update *ledger.Update) (newState ledger.State, err error) {
 	return ledger.State(newRootHash), nil
 }
 
+// publishToReplica publishes the trie with the given root hash to the replica, if any.
+func (l *Ledger) publishToReplica(rootHash ledger.RootHash) {
+	l.replicaMu.RLock()
+	replica := l.replica
+	l.replicaMu.RUnlock()
+	if replica == nil {
+		return
+	}
+
+	newTrie, err := l.forest.GetTrie(rootHash)
+	if err != nil {
+		// the trie was evicted already, so queries for it fall back to the ledger anyway
+		l.logger.Warn().Err(err).Hex("root_hash", rootHash[:]).Msg("could not publish trie to replica")
+		return
+	}
+	replica.publish(newTrie)
+}
+
+// reportMemoryUsage reports the approximate memory usage of the forest in the background, once
+// every memoryReportInterval updates. Reports are skipped while the previous one is still computed.
+func (l *Ledger) reportMemoryUsage(latest ledger.RootHash) {
+	if l.memoryReportInterval == 0 {
+		return
+	}
+	if atomic.AddUint64(&l.updates, 1)%l.memoryReportInterval != 0 {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&l.reportingMemory, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&l.reportingMemory, 0)
+
+		usage, err := l.forest.MemoryUsage()
+		if err != nil {
+			l.logger.Warn().Err(err).Msg("could not compute memory usage of forest")
+			return
+		}
+
+		l.metrics.ForestApproxMemorySize(usage.Bytes)
+		l.metrics.ForestInterimNodeCount(usage.InterimNodes)
+		l.metrics.ForestLeafPayloadSize(usage.PayloadBytes)
+		l.metrics.ForestSharedMemorySavings(usage.SharedBytes)
+		if bytes, ok := usage.TrieBytes[latest]; ok {
+			l.metrics.LatestTrieApproxMemorySize(bytes)
+		}
+	}()
+}
+
 // Prove provides proofs for a ledger query and errors (if any)
+// proving is aborted when the context of the query is done, or if it exceeds the read limits
 func (l *Ledger) Prove(query *ledger.Query) (proof ledger.Proof, err error) {
 
 	paths, err := pathfinder.KeysToPaths(query.Keys(), l.pathFinderVersion)
@@ -196,7 +303,7 @@ func (l *Ledger) Prove(query *ledger.Query) (proof ledger.Proof, err error) {
 	}
 
 	trieRead := &ledger.TrieRead{RootHash: ledger.RootHash(query.State()), Paths: paths}
-	batchProof, err := l.forest.Proofs(trieRead)
+	batchProof, err := l.forest.Proofs(query.Context(), trieRead)
 	if err != nil {
 		return nil, fmt.Errorf("could not get proofs: %w", err)
 	}
@@ -210,6 +317,66 @@ func (l *Ledger) Prove(query *ledger.Query) (proof ledger.Proof, err error) {
 	return ledger.Proof(proofToGo), err
 }
 
+// Pin retains the trie of the given state in memory until it is unpinned.
+func (l *Ledger) Pin(state ledger.State) error {
+	err := l.forest.Pin(ledger.RootHash(state))
+	if err != nil {
+		return fmt.Errorf("cannot pin state: %w", err)
+	}
+	return nil
+}
+
+// Unpin releases a pin of the trie of the given state. The pin is only released once
+// a checkpoint covers the WAL segments recorded so far, so that an evicted trie can be
+// restored from the checkpoint instead of replaying its updates.
+func (l *Ledger) Unpin(state ledger.State) error {
+	_, last, err := l.wal.Segments()
+	if err != nil {
+		return fmt.Errorf("cannot get WAL segments: %w", err)
+	}
+
+	l.unpinsMu.Lock()
+	defer l.unpinsMu.Unlock()
+
+	l.unpins = append(l.unpins, pendingUnpin{rootHash: ledger.RootHash(state), segment: last})
+
+	return l.releaseCheckpointedUnpins()
+}
+
+// releaseCheckpointedUnpins unpins the tries whose updates are covered by the latest checkpoint.
+// Caller must hold the unpins lock.
+func (l *Ledger) releaseCheckpointedUnpins() error {
+	checkpointer, err := l.wal.NewCheckpointer()
+	if err != nil {
+		return fmt.Errorf("cannot create checkpointer: %w", err)
+	}
+
+	// without checkpointer, the WAL is not persisted and tries can't be restored anyway
+	latestCheckpoint := math.MaxInt32
+	if checkpointer != nil {
+		latestCheckpoint, err = checkpointer.LatestCheckpoint()
+		if err != nil {
+			return fmt.Errorf("cannot get latest checkpoint: %w", err)
+		}
+	}
+
+	pending := l.unpins[:0]
+	for _, unpin := range l.unpins {
+		if unpin.segment > latestCheckpoint {
+			pending = append(pending, unpin)
+			continue
+		}
+		err = l.forest.Unpin(unpin.rootHash)
+		if err != nil {
+			// the trie was removed from the forest explicitly, nothing left to release
+			l.logger.Warn().Err(err).Hex("root_hash", unpin.rootHash[:]).Msg("could not unpin state")
+		}
+	}
+	l.unpins = pending
+
+	return nil
+}
+
 // MemSize return the amount of memory used by ledger
 // TODO implement an approximate MemSize method
 func (l *Ledger) MemSize() (int64, error) {
@@ -221,6 +388,12 @@ func (l *Ledger) ForestSize() int {
 	return l.forest.Size()
 }
 
+// Forest returns the forest of tries held by the ledger, for maintenance like pruning.
+// Tries must not be added to the forest directly, as they would be missing in the WAL.
+func (l *Ledger) Forest() *mtrie.Forest {
+	return l.forest
+}
+
 // Checkpointer returns a checkpointer instance
 func (l *Ledger) Checkpointer() (*wal.Checkpointer, error) {
 	checkpointer, err := l.wal.NewCheckpointer()
@@ -348,6 +521,35 @@ func (l *Ledger) DumpTrieAsJSON(state ledger.State, writer io.Writer) error {
 	return trie.DumpAsJSON(writer)
 }
 
+// IterateAccountRegisters calls fn with the key and value of every register of an account at the
+// given state, i.e. every register whose key contains the given owner key part. The registers are
+// streamed from the trie, so migrations and audits don't need to load the whole state in memory.
+// As paths are hashes of the keys, the registers of an account are spread over the whole trie, so
+// all leaves are visited, but only the payloads of the account are passed on. The iteration stops
+// at the first error returned by fn.
+func (l *Ledger) IterateAccountRegisters(state ledger.State, owner ledger.KeyPart, fn func(key ledger.Key, value ledger.Value) error) error {
+	trie, err := l.forest.GetTrie(ledger.RootHash(state))
+	if err != nil {
+		return fmt.Errorf("cannot find the target trie: %w", err)
+	}
+	return trie.IteratePayloads(func(_ ledger.Path, payload *ledger.Payload) error {
+		if !hasKeyPart(payload.Key, owner) {
+			return nil
+		}
+		return fn(payload.Key, payload.Value)
+	})
+}
+
+// hasKeyPart returns whether the key has a part equal to the given one
+func hasKeyPart(key ledger.Key, part ledger.KeyPart) bool {
+	for _, kp := range key.KeyParts {
+		if kp.Type == part.Type {
+			return bytes.Equal(kp.Value, part.Value)
+		}
+	}
+	return false
+}
+
 // this operation should only be used for exporting
 func (l *Ledger) keepOnlyOneTrie(state ledger.State) error {
 	// don't write things to WALs
@@ -81,6 +81,37 @@ func TestLedger_Update(t *testing.T) {
 			assert.Equal(t, v, retValues[i])
 		}
 	})
+
+	t.Run("returned values are copies", func(t *testing.T) {
+
+		wal := &fixtures.NoopWAL{}
+		led, err := complete.NewLedger(wal, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		u := utils.UpdateFixture()
+		u.SetState(led.InitialState())
+
+		newSc, err := led.Set(u)
+		require.NoError(t, err)
+
+		// duplicated keys are read from the same payload of the trie
+		keys := append(append([]ledger.Key{}, u.Keys()...), u.Keys()[0])
+		q, err := ledger.NewQuery(newSc, keys)
+		require.NoError(t, err)
+
+		retValues, err := led.Get(q)
+		require.NoError(t, err)
+
+		// modifying the returned values doesn't modify the ledger, nor the other values
+		retValues[0][0] = 'X'
+		assert.Equal(t, u.Values()[0], retValues[2])
+
+		retValues, err = led.Get(q)
+		require.NoError(t, err)
+		for i, v := range u.Values() {
+			assert.Equal(t, v, retValues[i])
+		}
+	})
 }
 
 func TestLedger_Get(t *testing.T) {
@@ -191,6 +222,304 @@ func TestLedger_Proof(t *testing.T) {
 	})
 }
 
+func TestLedger_PendingState(t *testing.T) {
+
+	newLedger := func() *complete.Ledger {
+		led, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		return led
+	}
+
+	// consecutive updates, the later ones overwriting registers of the earlier ones
+	keys := utils.RandomUniqueKeys(20, 2, 1, 10)
+	values := make([][]ledger.Value, 3)
+	for i := range values {
+		values[i] = utils.RandomValues(10, 1, 32)
+	}
+
+	t.Run("pending states match applied updates", func(t *testing.T) {
+		led := newLedger()
+		pendingLed := newLedger()
+
+		state := led.InitialState()
+		pending, err := pendingLed.NewPendingState(pendingLed.InitialState())
+		require.NoError(t, err)
+
+		var allKeys []ledger.Key
+		var allValues []ledger.Value
+		for i, vals := range values {
+			updateKeys := keys[i*5 : i*5+10]
+
+			// proofs at the state before the update
+			query, err := ledger.NewQuery(state, updateKeys)
+			require.NoError(t, err)
+			expectedProof, err := led.Prove(query)
+			require.NoError(t, err)
+			pendingProof, err := pending.Prove(query)
+			require.NoError(t, err)
+			require.Equal(t, expectedProof, pendingProof)
+
+			update, err := ledger.NewUpdate(state, updateKeys, vals)
+			require.NoError(t, err)
+			state, err = led.Set(update)
+			require.NoError(t, err)
+
+			pending, err = pending.Update(update)
+			require.NoError(t, err)
+			require.Equal(t, state, pending.State())
+
+			allKeys = append(allKeys, updateKeys...)
+			allValues = append(allValues, vals...)
+		}
+
+		// pending states are not added to the ledger
+		require.Equal(t, 1, pendingLed.ForestSize())
+
+		// applying all updates at once results in the last pending state
+		update, err := ledger.NewUpdate(pendingLed.InitialState(), allKeys, allValues)
+		require.NoError(t, err)
+		newState, err := pendingLed.Set(update)
+		require.NoError(t, err)
+		require.Equal(t, pending.State(), newState)
+	})
+
+	t.Run("applying records a single update", func(t *testing.T) {
+		var records []*ledger.TrieUpdate
+		w := &LongRunningDummyWAL{
+			updateFn: func(update *ledger.TrieUpdate) error {
+				records = append(records, update)
+				return nil
+			},
+		}
+		led, err := complete.NewLedger(w, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		pending, err := led.NewPendingState(led.InitialState())
+		require.NoError(t, err)
+		for i, vals := range values {
+			update, err := ledger.NewUpdate(pending.State(), keys[i*5:i*5+10], vals)
+			require.NoError(t, err)
+			pending, err = pending.Update(update)
+			require.NoError(t, err)
+		}
+		require.Empty(t, records)
+
+		newState, err := pending.Apply()
+		require.NoError(t, err)
+		require.Equal(t, pending.State(), newState)
+		require.Equal(t, 2, led.ForestSize())
+
+		// the writes of all updates are recorded as a single update of the initial state
+		require.Len(t, records, 1)
+		require.Equal(t, ledger.RootHash(led.InitialState()), records[0].RootHash)
+		require.Len(t, records[0].Paths, len(values)*10)
+
+		query, err := ledger.NewQuery(newState, keys[10:])
+		require.NoError(t, err)
+		retrieved, err := led.Get(query)
+		require.NoError(t, err)
+		require.Equal(t, values[2], retrieved)
+	})
+
+	t.Run("applied state is replayed from the WAL", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			diskWal, err := wal.NewDiskWAL(zerolog.Nop(), nil, &metrics.NoopCollector{}, dir, 100, pathfinder.PathByteSize, wal.SegmentSize)
+			require.NoError(t, err)
+			led, err := complete.NewLedger(diskWal, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			pending, err := led.NewPendingState(led.InitialState())
+			require.NoError(t, err)
+			for i, vals := range values {
+				update, err := ledger.NewUpdate(pending.State(), keys[i*5:i*5+10], vals)
+				require.NoError(t, err)
+				pending, err = pending.Update(update)
+				require.NoError(t, err)
+			}
+			newState, err := pending.Apply()
+			require.NoError(t, err)
+
+			<-diskWal.Done()
+			<-led.Done()
+
+			diskWal2, err := wal.NewDiskWAL(zerolog.Nop(), nil, &metrics.NoopCollector{}, dir, 100, pathfinder.PathByteSize, wal.SegmentSize)
+			require.NoError(t, err)
+			led2, err := complete.NewLedger(diskWal2, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			// the later writes to the overwritten registers are retained
+			query, err := ledger.NewQuery(newState, keys)
+			require.NoError(t, err)
+			retrieved, err := led2.Get(query)
+			require.NoError(t, err)
+			require.Equal(t, values[0][:5], retrieved[:5])
+			require.Equal(t, values[1][:5], retrieved[5:10])
+			require.Equal(t, values[2], retrieved[10:])
+
+			<-diskWal2.Done()
+			<-led2.Done()
+		})
+	})
+
+	t.Run("updates must be on the pending state", func(t *testing.T) {
+		led := newLedger()
+		pending, err := led.NewPendingState(led.InitialState())
+		require.NoError(t, err)
+
+		update, err := ledger.NewUpdate(ledger.State(unittest.StateCommitmentFixture()), keys[:10], values[0])
+		require.NoError(t, err)
+		_, err = pending.Update(update)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		led := newLedger()
+		_, err := led.NewPendingState(ledger.State(unittest.StateCommitmentFixture()))
+		require.Error(t, err)
+	})
+}
+
+func TestLedger_IterateAccountRegisters(t *testing.T) {
+	led, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	ownerKey := func(owner string, key string) ledger.Key {
+		return ledger.NewKey([]ledger.KeyPart{
+			ledger.NewKeyPart(0, []byte(owner)),
+			ledger.NewKeyPart(1, []byte("")),
+			ledger.NewKeyPart(2, []byte(key)),
+		})
+	}
+
+	keys := []ledger.Key{
+		ownerKey("alice", "balance"),
+		ownerKey("alice", "code"),
+		ownerKey("bob", "balance"),
+		ownerKey("", "uuid"),
+	}
+	values := []ledger.Value{
+		ledger.Value("1"),
+		ledger.Value("2"),
+		ledger.Value("3"),
+		ledger.Value("4"),
+	}
+	update, err := ledger.NewUpdate(led.InitialState(), keys, values)
+	require.NoError(t, err)
+	state, err := led.Set(update)
+	require.NoError(t, err)
+
+	t.Run("streams registers of account", func(t *testing.T) {
+		registers := make(map[string]ledger.Value)
+		err := led.IterateAccountRegisters(state, ledger.NewKeyPart(0, []byte("alice")), func(key ledger.Key, value ledger.Value) error {
+			registers[string(key.KeyParts[2].Value)] = value
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]ledger.Value{
+			"balance": ledger.Value("1"),
+			"code":    ledger.Value("2"),
+		}, registers)
+	})
+
+	t.Run("stops at error", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := led.IterateAccountRegisters(state, ledger.NewKeyPart(0, []byte("alice")), func(ledger.Key, ledger.Value) error {
+			calls++
+			return stop
+		})
+		require.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		err := led.IterateAccountRegisters(ledger.State(unittest.StateCommitmentFixture()), ledger.NewKeyPart(0, []byte("alice")), func(ledger.Key, ledger.Value) error {
+			return nil
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestLedger_ExportImportState(t *testing.T) {
+	newLedger := func(t *testing.T) *complete.Ledger {
+		led, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		return led
+	}
+
+	led := newLedger(t)
+	state := led.InitialState()
+	var keys []ledger.Key
+	var values []ledger.Value
+	// more registers than fit into a single chunk
+	for i := 0; i < 3; i++ {
+		updateKeys := utils.RandomUniqueKeys(700, 2, 10, 20)
+		updateValues := utils.RandomValues(700, 1, 32)
+		update, err := ledger.NewUpdate(state, updateKeys, updateValues)
+		require.NoError(t, err)
+		state, err = led.Set(update)
+		require.NoError(t, err)
+		keys = append(keys, updateKeys...)
+		values = append(values, updateValues...)
+	}
+
+	var export bytes.Buffer
+	err := led.ExportState(state, &export)
+	require.NoError(t, err)
+
+	t.Run("import restores state", func(t *testing.T) {
+		imported := newLedger(t)
+		importedState, err := imported.ImportState(bytes.NewReader(export.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, state, importedState)
+
+		query, err := ledger.NewQuery(importedState, keys)
+		require.NoError(t, err)
+		retValues, err := imported.Get(query)
+		require.NoError(t, err)
+		for i, v := range values {
+			assert.Equal(t, v, retValues[i])
+		}
+	})
+
+	t.Run("export is deterministic", func(t *testing.T) {
+		imported := newLedger(t)
+		importedState, err := imported.ImportState(bytes.NewReader(export.Bytes()))
+		require.NoError(t, err)
+
+		var reexport bytes.Buffer
+		err = imported.ExportState(importedState, &reexport)
+		require.NoError(t, err)
+		require.Equal(t, export.Bytes(), reexport.Bytes())
+	})
+
+	t.Run("empty state", func(t *testing.T) {
+		var emptyExport bytes.Buffer
+		err := led.ExportState(led.InitialState(), &emptyExport)
+		require.NoError(t, err)
+
+		importedState, err := newLedger(t).ImportState(&emptyExport)
+		require.NoError(t, err)
+		require.Equal(t, led.InitialState(), importedState)
+	})
+
+	t.Run("detects modified data", func(t *testing.T) {
+		modified := make([]byte, export.Len())
+		copy(modified, export.Bytes())
+		// modify a byte in the body of the first chunk
+		modified[len(modified)/2]++
+
+		_, err := newLedger(t).ImportState(bytes.NewReader(modified))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "checksum")
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		err := led.ExportState(ledger.State(unittest.StateCommitmentFixture()), &bytes.Buffer{})
+		require.Error(t, err)
+	})
+}
+
 func Test_WAL(t *testing.T) {
 	numInsPerStep := 2
 	keyNumberOfParts := 10
@@ -1,6 +1,7 @@
 package flattener_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,9 +63,9 @@ func TestForestStoreAndLoad(t *testing.T) {
 	assert.Equal(t, mForest, newForest)
 
 	read := &ledger.TrieRead{RootHash: rootHash, Paths: paths}
-	retPayloads, err := mForest.Read(read)
+	retPayloads, err := mForest.Read(context.Background(), read)
 	require.NoError(t, err)
-	newRetPayloads, err := newForest.Read(read)
+	newRetPayloads, err := newForest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, retPayloads[i].Equals(newRetPayloads[i]))
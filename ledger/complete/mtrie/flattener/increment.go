@@ -0,0 +1,181 @@
+package flattener
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+// FlattenedForestIncrement represents a Forest as a flattened data structure relative to a base
+// forest. Only nodes which are not part of the base forest are stored, subtries which are unchanged
+// since the base forest are referenced by the hash of their root node.
+//
+// Nodes are referenced by index, with the following index space:
+//   - 0 means nil
+//   - 1 to len(Refs) reference the nodes of the base forest with the hashes in Refs
+//   - len(Refs)+1 and above reference the new nodes in Nodes, i.e. Nodes[i-len(Refs)]
+//
+// As in FlattenedForest, the 0th element of Nodes is nil and the nodes are listed in an
+// order which satisfies the Descendents-First-Relationship.
+type FlattenedForestIncrement struct {
+	Refs  []hash.Hash
+	Nodes []*StorableNode
+	Tries []*StorableTrie
+}
+
+// FlattenForestIncrement returns the FlattenedForestIncrement of the forest relative to the given
+// base tries, which contains all tries of the forest, but only the nodes which are not part of
+// the base tries. Nodes are compared by identity, so the tries of the forest are expected to be
+// derived from the base tries by updates.
+func FlattenForestIncrement(f *mtrie.Forest, base []*trie.MTrie) (*FlattenedForestIncrement, error) {
+	tries, err := f.GetTries()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get cached tries root hashes: %w", err)
+	}
+
+	baseNodes := make(map[*node.Node]struct{})
+	for _, t := range base {
+		for itr := NewNodeIterator(t); itr.Next(); {
+			baseNodes[itr.Value()] = struct{}{}
+		}
+	}
+
+	// first pass: index the base nodes referenced by the new nodes, so that the
+	// new nodes can be indexed following the references
+	allNodes := make(node2indexMap)
+	allNodes[nil] = 0 // 0th element is nil
+	var refs []hash.Hash
+	var reference func(n *node.Node)
+	reference = func(n *node.Node) {
+		if _, has := allNodes[n]; has {
+			return
+		}
+		if _, isBase := baseNodes[n]; isBase {
+			refs = append(refs, n.Hash())
+			allNodes[n] = uint64(len(refs))
+			return
+		}
+		reference(n.LeftChild())
+		reference(n.RightChild())
+	}
+	for _, t := range tries {
+		reference(t.RootNode())
+	}
+
+	// second pass: flatten the new nodes in descendants-first order
+	storableNodes := []*StorableNode{nil} // 0th element is nil
+	var flatten func(n *node.Node) error
+	flatten = func(n *node.Node) error {
+		if _, has := allNodes[n]; has {
+			return nil
+		}
+		err := flatten(n.LeftChild())
+		if err != nil {
+			return err
+		}
+		err = flatten(n.RightChild())
+		if err != nil {
+			return err
+		}
+		allNodes[n] = uint64(len(refs) + len(storableNodes))
+		storableNode, err := toStorableNode(n, allNodes)
+		if err != nil {
+			return fmt.Errorf("failed to construct storable node: %w", err)
+		}
+		storableNodes = append(storableNodes, storableNode)
+		return nil
+	}
+
+	storableTries := make([]*StorableTrie, 0, len(tries))
+	for _, t := range tries {
+		err := flatten(t.RootNode())
+		if err != nil {
+			return nil, err
+		}
+		storableTrie, err := toStorableTrie(t, allNodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct storable trie: %w", err)
+		}
+		storableTries = append(storableTries, storableTrie)
+	}
+
+	return &FlattenedForestIncrement{
+		Refs:  refs,
+		Nodes: storableNodes,
+		Tries: storableTries,
+	}, nil
+}
+
+// ApplyIncrement returns the FlattenedForest holding the tries of the increment, whose references
+// are resolved against the nodes of the base forest. The nodes of the base forest are retained,
+// so that the indices of the base nodes don't change.
+func ApplyIncrement(base *FlattenedForest, increment *FlattenedForestIncrement) (*FlattenedForest, error) {
+	refIndex := make(map[hash.Hash]uint64, len(increment.Refs))
+	for _, ref := range increment.Refs {
+		refIndex[ref] = 0
+	}
+	for i := 1; i < len(base.Nodes); i++ {
+		nodeHash, err := hash.ToHash(base.Nodes[i].HashValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode a hash of a storableNode %w", err)
+		}
+		if index, ok := refIndex[nodeHash]; ok && index == 0 {
+			refIndex[nodeHash] = uint64(i)
+		}
+	}
+
+	refs := uint64(len(increment.Refs))
+	baseNodes := uint64(len(base.Nodes))
+	resolve := func(index uint64) (uint64, error) {
+		switch {
+		case index == 0:
+			return 0, nil
+		case index <= refs:
+			ref := increment.Refs[index-1]
+			resolved := refIndex[ref]
+			if resolved == 0 {
+				return 0, fmt.Errorf("referenced node with hash %x is missing in base forest", ref[:])
+			}
+			return resolved, nil
+		default:
+			// the increment's nodes follow the base nodes, without its 0th element
+			return baseNodes + index - refs - 1, nil
+		}
+	}
+
+	nodes := make([]*StorableNode, 0, len(base.Nodes)+len(increment.Nodes)-1)
+	nodes = append(nodes, base.Nodes...)
+	for i := 1; i < len(increment.Nodes); i++ {
+		storableNode := *increment.Nodes[i]
+		var err error
+		storableNode.LIndex, err = resolve(storableNode.LIndex)
+		if err != nil {
+			return nil, err
+		}
+		storableNode.RIndex, err = resolve(storableNode.RIndex)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, &storableNode)
+	}
+
+	tries := make([]*StorableTrie, 0, len(increment.Tries))
+	for _, storableTrie := range increment.Tries {
+		rootIndex, err := resolve(storableTrie.RootIndex)
+		if err != nil {
+			return nil, err
+		}
+		tries = append(tries, &StorableTrie{
+			RootIndex: rootIndex,
+			RootHash:  storableTrie.RootHash,
+		})
+	}
+
+	return &FlattenedForest{
+		Nodes: nodes,
+		Tries: tries,
+	}, nil
+}
@@ -1,11 +1,12 @@
 package mtrie
 
 import (
-	"encoding/hex"
-	"errors"
+	"bytes"
+	"context"
 	"fmt"
-
-	lru "github.com/hashicorp/golang-lru"
+	"sort"
+	"sync"
+	"sync/atomic"
 
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/hash"
@@ -13,55 +14,70 @@ import (
 	"github.com/onflow/flow-go/module"
 )
 
+// readBatchSize is the number of paths read or proven at once, between which a
+// cancellation of the query is detected.
+const readBatchSize = 1024
+
+// ReadLimits bounds the resources a single read or proof query can consume, so
+// that one huge query cannot monopolize the forest. Zero values disable a limit.
+type ReadLimits struct {
+	// MaxPaths is the maximum number of paths in a single query.
+	MaxPaths int
+	// MaxProofSize is the maximum approximate size in bytes of a batch proof.
+	MaxProofSize int
+}
+
 // Forest holds several in-memory tries. As Forest is a storage-abstraction layer,
 // we assume that all registers are addressed via paths of pre-defined uniform length.
 //
 // Forest has a limit, the forestCapacity, on the number of tries it is able to store.
-// If more tries are added than the capacity, the Least Recently Used trie is
-// removed (evicted) from the Forest. THIS IS A ROUGH HEURISTIC as it might evict
-// tries that are still needed. In fully matured Flow, we will have an
-// explicit eviction policy.
+// If more tries are added than the capacity, the Least Recently Used trie which is
+// not pinned is removed (evicted) from the Forest. Users of the forest pin the tries
+// they still need, e.g. the execution node pins the states of all blocks which are
+// not sealed yet, and unpin them once they are no longer needed. Pinned tries are
+// never evicted: if all tries are pinned, the forest grows beyond its capacity.
 //
-// TODO: Storage Eviction Policy for Forest
-//       For the execution node: we only evict on sealing a result.
+// Forest is safe for concurrent use: looking up tries shares a read lock, so that
+// concurrent block executions and checkpointing don't block each other, while
+// adding and removing tries is exclusive.
 type Forest struct {
+	// clock is the logical clock for the last use of tries, accessed atomically.
+	// It is the first field to be 64-bit aligned on 32-bit platforms.
+	clock uint64
 	// tries stores all MTries in the forest. It is NOT a CACHE in the conventional sense:
 	// there is no mechanism to load a trie from disk in case of a cache miss. Missing a
 	// needed trie in the forest might cause a fatal application logic error.
-	tries          *lru.Cache
+	tries map[ledger.RootHash]*forestEntry
+	// mu guards the tries and their pins. The last use of a trie is tracked atomically,
+	// so that it can be updated under the read lock.
+	mu             sync.RWMutex
 	forestCapacity int
 	onTreeEvicted  func(tree *trie.MTrie) error
 	metrics        module.LedgerMetrics
+	limits         ReadLimits
+}
+
+// forestEntry is a trie stored in the forest.
+type forestEntry struct {
+	// lastUsed is the logical time the trie was last used, accessed atomically
+	lastUsed uint64
+	trie     *trie.MTrie
+	// pins counts the pins of the trie, as the same trie can be pinned for several reasons
+	// (e.g. empty blocks don't change the state of their parent)
+	pins uint
 }
 
 // NewForest returns a new instance of memory forest.
 //
-// CAUTION on forestCapacity: the specified capacity MUST be SUFFICIENT to store all needed MTries in the forest.
-// If more tries are added than the capacity, the Least Recently Used trie is removed (evicted) from the Forest.
-// THIS IS A ROUGH HEURISTIC as it might evict tries that are still needed.
-// Make sure you chose a sufficiently large forestCapacity, such that, when reaching the capacity, the
-// Least Recently Used trie will never be needed again.
+// CAUTION on forestCapacity: when reaching the capacity, the Least Recently Used trie which is not pinned
+// is removed (evicted) from the Forest. Tries which are still needed MUST be pinned, otherwise they might be evicted.
 func NewForest(forestCapacity int, metrics module.LedgerMetrics, onTreeEvicted func(tree *trie.MTrie) error) (*Forest, error) {
-	// init LRU cache as a SHORTCUT for a usage-related storage eviction policy
-	var cache *lru.Cache
-	var err error
-	if onTreeEvicted != nil {
-		cache, err = lru.NewWithEvict(forestCapacity, func(key interface{}, value interface{}) {
-			trie, ok := value.(*trie.MTrie)
-			if !ok {
-				panic(fmt.Sprintf("cache contains item of type %T", value))
-			}
-			//TODO Log error
-			_ = onTreeEvicted(trie)
-		})
-	} else {
-		cache, err = lru.New(forestCapacity)
-	}
-	if err != nil {
-		return nil, fmt.Errorf("cannot create forest cache: %w", err)
+	if forestCapacity <= 0 {
+		return nil, fmt.Errorf("forest capacity must be positive, got %d", forestCapacity)
 	}
 
-	forest := &Forest{tries: cache,
+	forest := &Forest{
+		tries:          make(map[ledger.RootHash]*forestEntry),
 		forestCapacity: forestCapacity,
 		onTreeEvicted:  onTreeEvicted,
 		metrics:        metrics,
@@ -69,34 +85,55 @@ func NewForest(forestCapacity int, metrics module.LedgerMetrics, onTreeEvicted f
 
 	// add trie with no allocated registers
 	emptyTrie := trie.NewEmptyMTrie()
-	err = forest.AddTrie(emptyTrie)
+	err := forest.AddTrie(emptyTrie)
 	if err != nil {
 		return nil, fmt.Errorf("adding empty trie to forest failed: %w", err)
 	}
 	return forest, nil
 }
 
-// Read reads values for an slice of paths and returns values and error (if any)
+// SetReadLimits sets the limits applied to every read and proof query.
+func (f *Forest) SetReadLimits(limits ReadLimits) {
+	f.limits = limits
+}
+
+// Read reads values for an slice of paths and returns values and error (if any).
+// The read is aborted with the context's error if the context is cancelled, and with
+// ledger.ErrQueryTooLarge if the query exceeds the read limits of the forest.
+// The returned payloads are shared with the trie and with other reads (including
+// duplicated paths of the same read), hence they MUST NOT be modified. Callers handing
+// out the read values, such as the ledger, copy them.
 // TODO: can be optimized further if we don't care about changing the order of the input r.Paths
-func (f *Forest) Read(r *ledger.TrieRead) ([]*ledger.Payload, error) {
+func (f *Forest) Read(ctx context.Context, r *ledger.TrieRead) ([]*ledger.Payload, error) {
 
 	if len(r.Paths) == 0 {
 		return []*ledger.Payload{}, nil
 	}
 
+	err := f.checkPathLimit(len(r.Paths))
+	if err != nil {
+		return nil, err
+	}
+
 	// lookup the trie by rootHash
 	trie, err := f.GetTrie(r.RootHash)
 	if err != nil {
 		return nil, err
 	}
 
+	return f.readTrie(ctx, trie, r.Paths)
+}
+
+// readTrie reads the payloads of the given paths from the given trie, as Read does.
+func (f *Forest) readTrie(ctx context.Context, stateTrie *trie.MTrie, paths []ledger.Path) ([]*ledger.Payload, error) {
+
 	// deduplicate keys:
 	// Generally, we expect the VM to deduplicate reads and writes. Hence, the following is a pre-caution.
 	// TODO: We could take out the following de-duplication logic
 	//       Which increases the cost for duplicates but reduces read complexity without duplicates.
-	deduplicatedPaths := make([]ledger.Path, 0, len(r.Paths))
+	deduplicatedPaths := make([]ledger.Path, 0, len(paths))
 	pathOrgIndex := make(map[ledger.Path][]int)
-	for i, path := range r.Paths {
+	for i, path := range paths {
 		// only collect duplicated keys once
 		indices, ok := pathOrgIndex[path]
 		if !ok { // deduplication here is optional
@@ -106,16 +143,28 @@ func (f *Forest) Read(r *ledger.TrieRead) ([]*ledger.Payload, error) {
 		pathOrgIndex[path] = append(indices, i)
 	}
 
-	payloads := trie.UnsafeRead(deduplicatedPaths) // this sorts deduplicatedPaths IN-PLACE
+	// read in batches, so a cancelled query is detected while it is in progress
+	payloads := make([]*ledger.Payload, 0, len(deduplicatedPaths))
+	for start := 0; start < len(deduplicatedPaths); start += readBatchSize {
+		err := ctx.Err()
+		if err != nil {
+			return nil, fmt.Errorf("read cancelled: %w", err)
+		}
+		end := start + readBatchSize
+		if end > len(deduplicatedPaths) {
+			end = len(deduplicatedPaths)
+		}
+		payloads = append(payloads, stateTrie.UnsafeRead(deduplicatedPaths[start:end])...) // this sorts the batch of paths IN-PLACE
+	}
 
 	// reconstruct the payloads in the same key order that called the method
-	orderedPayloads := make([]*ledger.Payload, len(r.Paths))
+	orderedPayloads := make([]*ledger.Payload, len(paths))
 	totalPayloadSize := 0
 	for i, p := range deduplicatedPaths {
 		payload := payloads[i]
 		indices := pathOrgIndex[p]
 		for _, j := range indices {
-			orderedPayloads[j] = payload.DeepCopy()
+			orderedPayloads[j] = payload
 		}
 		totalPayloadSize += len(indices) * payload.Size()
 	}
@@ -129,6 +178,50 @@ func (f *Forest) Read(r *ledger.TrieRead) ([]*ledger.Payload, error) {
 // In case there are multiple updates to the same register, Update will persist the latest
 // written value.
 func (f *Forest) Update(u *ledger.TrieUpdate) (ledger.RootHash, error) {
+	prepared, err := f.PrepareUpdate(u)
+	if err != nil {
+		return ledger.RootHash(hash.DummyHash), err
+	}
+	return f.ApplyPrepared(prepared)
+}
+
+// PreparedUpdate is a trie update whose writes are deduplicated and sorted by path,
+// so that it can be applied to the forest without further preparation.
+type PreparedUpdate struct {
+	RootHash    ledger.RootHash  // root hash of the trie to update
+	Paths       []ledger.Path    // updated paths, without duplicates and in ascending order
+	Payloads    []ledger.Payload // payloads of the updated paths
+	PayloadSize int              // total size of the payloads
+}
+
+// PrepareUpdate deduplicates the writes of the update, retaining the value of the last write to
+// each register, and sorts them by path. Preparing an update doesn't access the tries of the forest,
+// so updates can be prepared concurrently, e.g. while the previous update is applied.
+func (f *Forest) PrepareUpdate(u *ledger.TrieUpdate) (*PreparedUpdate, error) {
+	if len(u.Paths) != len(u.Payloads) {
+		return nil, fmt.Errorf("update has %d paths but %d payloads", len(u.Paths), len(u.Payloads))
+	}
+	for i, payload := range u.Payloads {
+		if payload == nil {
+			return nil, fmt.Errorf("payload at index %d of update is nil", i)
+		}
+	}
+
+	paths, payloads, payloadSize := deduplicateUpdate(u)
+
+	// the trie update partitions the paths by their bits, which requires no swaps for sorted paths
+	sort.Sort(pathsWithPayloads{paths: paths, payloads: payloads})
+
+	return &PreparedUpdate{
+		RootHash:    u.RootHash,
+		Paths:       paths,
+		Payloads:    payloads,
+		PayloadSize: payloadSize,
+	}, nil
+}
+
+// ApplyPrepared applies the prepared update to the forest, and returns the root hash of the updated trie.
+func (f *Forest) ApplyPrepared(u *PreparedUpdate) (ledger.RootHash, error) {
 	emptyHash := ledger.RootHash(hash.DummyHash)
 
 	parentTrie, err := f.GetTrie(u.RootHash)
@@ -140,8 +233,49 @@ func (f *Forest) Update(u *ledger.TrieUpdate) (ledger.RootHash, error) {
 		return u.RootHash, nil
 	}
 
-	// Deduplicate writes to the same register: we only retain the value of the last write
-	// Generally, we expect the VM to deduplicate reads and writes.
+	// TODO rename metrics names
+	f.metrics.UpdateValuesSize(uint64(u.PayloadSize))
+
+	newTrie, err := trie.NewTrieWithUpdatedRegisters(parentTrie, u.Paths, u.Payloads)
+	if err != nil {
+		return emptyHash, fmt.Errorf("constructing updated trie failed: %w", err)
+	}
+
+	f.metrics.LatestTrieRegCount(newTrie.AllocatedRegCount())
+	f.metrics.LatestTrieRegCountDiff(newTrie.AllocatedRegCount() - parentTrie.AllocatedRegCount())
+	f.metrics.LatestTrieMaxDepth(uint64(newTrie.MaxDepth()))
+	f.metrics.LatestTrieMaxDepthDiff(uint64(newTrie.MaxDepth() - parentTrie.MaxDepth()))
+
+	err = f.AddTrie(newTrie)
+	if err != nil {
+		return emptyHash, fmt.Errorf("adding updated trie to forest failed: %w", err)
+	}
+
+	return ledger.RootHash(newTrie.RootHash()), nil
+}
+
+// pathsWithPayloads sorts paths in ascending order, together with their payloads
+type pathsWithPayloads struct {
+	paths    []ledger.Path
+	payloads []ledger.Payload
+}
+
+func (p pathsWithPayloads) Len() int {
+	return len(p.paths)
+}
+
+func (p pathsWithPayloads) Less(i, j int) bool {
+	return bytes.Compare(p.paths[i][:], p.paths[j][:]) < 0
+}
+
+func (p pathsWithPayloads) Swap(i, j int) {
+	p.paths[i], p.paths[j] = p.paths[j], p.paths[i]
+	p.payloads[i], p.payloads[j] = p.payloads[j], p.payloads[i]
+}
+
+// deduplicateUpdate deduplicates writes to the same register: we only retain the value of the last write.
+// Generally, we expect the VM to deduplicate reads and writes.
+func deduplicateUpdate(u *ledger.TrieUpdate) ([]ledger.Path, []ledger.Payload, int) {
 	deduplicatedPaths := make([]ledger.Path, 0, len(u.Paths))
 	deduplicatedPayloads := make([]ledger.Payload, 0, len(u.Paths))
 	payloadMap := make(map[ledger.Path]int) // index into deduplicatedPaths, deduplicatedPayloads with register update
@@ -160,38 +294,44 @@ func (f *Forest) Update(u *ledger.TrieUpdate) (ledger.RootHash, error) {
 			totalPayloadSize += payload.Size()
 		}
 	}
+	return deduplicatedPaths, deduplicatedPayloads, totalPayloadSize
+}
 
-	// TODO rename metrics names
-	f.metrics.UpdateValuesSize(uint64(totalPayloadSize))
+// Proofs returns a batch proof for the given paths.
+// Proving is aborted with the context's error if the context is cancelled, and with
+// ledger.ErrQueryTooLarge if the query or the proof exceeds the read limits of the forest.
+func (f *Forest) Proofs(ctx context.Context, r *ledger.TrieRead) (*ledger.TrieBatchProof, error) {
 
-	newTrie, err := trie.NewTrieWithUpdatedRegisters(parentTrie, deduplicatedPaths, deduplicatedPayloads)
-	if err != nil {
-		return emptyHash, fmt.Errorf("constructing updated trie failed: %w", err)
+	// no path, empty batchproof
+	if len(r.Paths) == 0 {
+		return ledger.NewTrieBatchProof(), nil
 	}
 
-	f.metrics.LatestTrieRegCount(newTrie.AllocatedRegCount())
-	f.metrics.LatestTrieRegCountDiff(newTrie.AllocatedRegCount() - parentTrie.AllocatedRegCount())
-	f.metrics.LatestTrieMaxDepth(uint64(newTrie.MaxDepth()))
-	f.metrics.LatestTrieMaxDepthDiff(uint64(newTrie.MaxDepth() - parentTrie.MaxDepth()))
-
-	err = f.AddTrie(newTrie)
+	stateTrie, err := f.GetTrie(r.RootHash)
 	if err != nil {
-		return emptyHash, fmt.Errorf("adding updated trie to forest failed: %w", err)
+		return nil, err
 	}
 
-	return ledger.RootHash(newTrie.RootHash()), nil
+	return f.TrieProofs(ctx, stateTrie, r.Paths)
 }
 
-// Proofs returns a batch proof for the given paths
-func (f *Forest) Proofs(r *ledger.TrieRead) (*ledger.TrieBatchProof, error) {
+// TrieProofs returns a batch proof for the given paths in the given trie, which doesn't need to
+// be held by the forest, e.g. a trie derived from a trie of the forest by pending updates.
+// As Proofs, proving is aborted if the context is cancelled or the read limits are exceeded.
+func (f *Forest) TrieProofs(ctx context.Context, stateTrie *trie.MTrie, paths []ledger.Path) (*ledger.TrieBatchProof, error) {
 
 	// no path, empty batchproof
-	if len(r.Paths) == 0 {
+	if len(paths) == 0 {
 		return ledger.NewTrieBatchProof(), nil
 	}
 
+	err := f.checkPathLimit(len(paths))
+	if err != nil {
+		return nil, err
+	}
+
 	// look up for non existing paths
-	retPayloads, err := f.Read(r)
+	retPayloads, err := f.readTrie(ctx, stateTrie, paths)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +340,7 @@ func (f *Forest) Proofs(r *ledger.TrieRead) (*ledger.TrieBatchProof, error) {
 	notFoundPaths := make([]ledger.Path, 0)
 	notFoundPayloads := make([]ledger.Payload, 0)
 	pathOrgIndex := make(map[ledger.Path][]int)
-	for i, path := range r.Paths {
+	for i, path := range paths {
 		// only collect duplicated keys once
 		if _, ok := pathOrgIndex[path]; !ok {
 			deduplicatedPaths = append(deduplicatedPaths, path)
@@ -217,11 +357,6 @@ func (f *Forest) Proofs(r *ledger.TrieRead) (*ledger.TrieBatchProof, error) {
 		}
 	}
 
-	stateTrie, err := f.GetTrie(r.RootHash)
-	if err != nil {
-		return nil, err
-	}
-
 	// if we have to insert empty values
 	if len(notFoundPaths) > 0 {
 		newTrie, err := trie.NewTrieWithUpdatedRegisters(stateTrie, notFoundPaths, notFoundPayloads)
@@ -230,8 +365,8 @@ func (f *Forest) Proofs(r *ledger.TrieRead) (*ledger.TrieBatchProof, error) {
 		}
 
 		// rootHash shouldn't change
-		if newTrie.RootHash() != r.RootHash {
-			return nil, fmt.Errorf("root hash has changed during the operation %x, %x", newTrie.RootHash(), r.RootHash)
+		if newTrie.RootHash() != stateTrie.RootHash() {
+			return nil, fmt.Errorf("root hash has changed during the operation %x, %x", newTrie.RootHash(), stateTrie.RootHash())
 		}
 		stateTrie = newTrie
 	}
@@ -243,10 +378,29 @@ func (f *Forest) Proofs(r *ledger.TrieRead) (*ledger.TrieBatchProof, error) {
 		p.Inclusion = false
 	}
 
-	stateTrie.UnsafeProofs(deduplicatedPaths, bp.Proofs)
+	// prove in batches, so a cancelled query or an oversized proof is detected while it is in progress
+	proofSize := 0
+	for start := 0; start < len(deduplicatedPaths); start += readBatchSize {
+		err = ctx.Err()
+		if err != nil {
+			return nil, fmt.Errorf("proof cancelled: %w", err)
+		}
+		end := start + readBatchSize
+		if end > len(deduplicatedPaths) {
+			end = len(deduplicatedPaths)
+		}
+		stateTrie.UnsafeProofs(deduplicatedPaths[start:end], bp.Proofs[start:end])
+
+		for _, p := range bp.Proofs[start:end] {
+			proofSize += approxProofSize(p)
+		}
+		if f.limits.MaxProofSize > 0 && proofSize > f.limits.MaxProofSize {
+			return nil, ledger.NewErrQueryTooLarge("proof size", proofSize, f.limits.MaxProofSize)
+		}
+	}
 
 	// reconstruct the proofs in the same key order that called the method
-	retbp := ledger.NewTrieBatchProofWithEmptyProofs(len(r.Paths))
+	retbp := ledger.NewTrieBatchProofWithEmptyProofs(len(paths))
 	for i, p := range deduplicatedPaths {
 		for _, j := range pathOrgIndex[p] {
 			retbp.Proofs[j] = bp.Proofs[i]
@@ -256,40 +410,96 @@ func (f *Forest) Proofs(r *ledger.TrieRead) (*ledger.TrieBatchProof, error) {
 	return retbp, nil
 }
 
+// checkPathLimit returns an error if the number of paths exceeds the read limits.
+func (f *Forest) checkPathLimit(paths int) error {
+	if f.limits.MaxPaths > 0 && paths > f.limits.MaxPaths {
+		return ledger.NewErrQueryTooLarge("number of paths", paths, f.limits.MaxPaths)
+	}
+	return nil
+}
+
+// approxProofSize returns the approximate size of the encoded proof.
+func approxProofSize(p *ledger.TrieProof) int {
+	size := ledger.PathLen + len(p.Flags) + len(p.Interims)*hash.HashLen
+	if p.Payload != nil {
+		size += p.Payload.Size()
+	}
+	return size
+}
+
 // GetTrie returns trie at specific rootHash
 // warning, use this function for read-only operation
 func (f *Forest) GetTrie(rootHash ledger.RootHash) (*trie.MTrie, error) {
-	// if in memory
-	if ent, found := f.tries.Get(rootHash); found {
-		trie, ok := ent.(*trie.MTrie)
-		if !ok {
-			return nil, fmt.Errorf("forest contains an element of a wrong type")
-		}
-		return trie, nil
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	entry, found := f.tries[rootHash]
+	if !found {
+		return nil, fmt.Errorf("trie with the given rootHash [%x] not found", rootHash)
 	}
-	return nil, fmt.Errorf("trie with the given rootHash [%x] not found", rootHash)
+	f.touch(entry)
+	return entry.trie, nil
+}
+
+// touch marks the trie of the entry as most recently used.
+func (f *Forest) touch(entry *forestEntry) {
+	atomic.StoreUint64(&entry.lastUsed, atomic.AddUint64(&f.clock, 1))
 }
 
-// GetTries returns list of currently cached tree root hashes
+// GetTries returns all tries of the forest, from the least to the most recently used.
+// The tries are a consistent snapshot of the forest, even if tries are added concurrently.
 func (f *Forest) GetTries() ([]*trie.MTrie, error) {
-	// ToDo needs concurrency safety
-	keys := f.tries.Keys()
-	tries := make([]*trie.MTrie, 0, len(keys))
-	for _, key := range keys {
-		t, ok := f.tries.Get(key)
-		if !ok {
-			return nil, errors.New("concurrent Forest modification")
-		}
-		trie, ok := t.(*trie.MTrie)
-		if !ok {
-			return nil, errors.New("forest contains an element of a wrong type")
-		}
-		tries = append(tries, trie)
+	f.mu.RLock()
+	entries := make([]*forestEntry, 0, len(f.tries))
+	for _, entry := range f.tries {
+		entries = append(entries, entry)
+	}
+	f.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return atomic.LoadUint64(&entries[i].lastUsed) < atomic.LoadUint64(&entries[j].lastUsed)
+	})
+
+	tries := make([]*trie.MTrie, 0, len(entries))
+	for _, entry := range entries {
+		tries = append(tries, entry.trie)
 	}
 	return tries, nil
 }
 
-// AddTries adds a trie to the forest
+// ForestMemoryUsage is the approximate memory usage of the tries of a forest.
+type ForestMemoryUsage struct {
+	trie.MemoryUsage                            // usage of all tries, counting nodes shared by several tries once
+	SharedBytes      uint64                     // memory saved by sharing subtries between tries
+	TrieBytes        map[ledger.RootHash]uint64 // memory used by each trie on its own
+}
+
+// MemoryUsage walks all nodes of the forest and returns their approximate memory usage.
+// It is expensive for large forests, and should only be called infrequently.
+func (f *Forest) MemoryUsage() (*ForestMemoryUsage, error) {
+	tries, err := f.GetTries()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get tries of forest: %w", err)
+	}
+
+	combined, trieBytes := trie.CombinedMemoryUsage(tries)
+
+	usage := &ForestMemoryUsage{
+		MemoryUsage: combined,
+		TrieBytes:   make(map[ledger.RootHash]uint64, len(tries)),
+	}
+	var separateBytes uint64
+	for i, t := range tries {
+		usage.TrieBytes[t.RootHash()] = trieBytes[i]
+		separateBytes += trieBytes[i]
+	}
+	usage.SharedBytes = separateBytes - combined.Bytes
+
+	return usage, nil
+}
+
+// AddTries adds the tries to the forest one by one. If adding a trie fails,
+// the tries added before remain in the forest.
 func (f *Forest) AddTries(newTries []*trie.MTrie) error {
 	for _, t := range newTries {
 		err := f.AddTrie(t)
@@ -300,35 +510,134 @@ func (f *Forest) AddTries(newTries []*trie.MTrie) error {
 	return nil
 }
 
-// AddTrie adds a trie to the forest
+// AddTrie adds a trie to the forest. If the forest exceeds its capacity, the least
+// recently used tries which are not pinned are evicted. The added trie itself is
+// never evicted, so that it can still be pinned by the caller.
 func (f *Forest) AddTrie(newTrie *trie.MTrie) error {
-	if newTrie == nil {
-		return nil
-	}
+	return f.AddTriesAtomically([]*trie.MTrie{newTrie})
+}
 
-	// TODO: check Thread safety
-	rootHash := newTrie.RootHash()
-	if storedTrie, found := f.tries.Get(rootHash); found {
-		trie, ok := storedTrie.(*trie.MTrie)
-		if !ok {
-			return fmt.Errorf("forest contains an element of a wrong type")
+// AddTriesAtomically adds either all the tries to the forest or, if any of them conflicts
+// with a stored trie, none of them. Concurrent readers observe either none or all of the
+// tries. The added tries are not evicted to make room for each other.
+func (f *Forest) AddTriesAtomically(newTries []*trie.MTrie) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	added := make(map[ledger.RootHash]*trie.MTrie, len(newTries))
+	for _, newTrie := range newTries {
+		if newTrie == nil {
+			continue
+		}
+		rootHash := newTrie.RootHash()
+		storedTrie, found := added[rootHash]
+		if entry, stored := f.tries[rootHash]; stored {
+			storedTrie, found = entry.trie, true
 		}
-		if trie.Equals(newTrie) {
-			return nil
+		if found {
+			if storedTrie.Equals(newTrie) {
+				continue
+			}
+			return fmt.Errorf("forest already contains a tree with same root hash but other properties")
 		}
-		return fmt.Errorf("forest already contains a tree with same root hash but other properties")
+		added[rootHash] = newTrie
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	for rootHash, newTrie := range added {
+		entry := &forestEntry{trie: newTrie}
+		f.touch(entry)
+		f.tries[rootHash] = entry
 	}
-	f.tries.Add(rootHash, newTrie)
-	f.metrics.ForestNumberOfTrees(uint64(f.tries.Len()))
+	f.evict(added)
+	f.metrics.ForestNumberOfTrees(uint64(len(f.tries)))
 
 	return nil
 }
 
-// RemoveTrie removes a trie to the forest
+// evict removes the least recently used tries which are not pinned until the forest
+// is within its capacity again. The tries with the given root hashes are retained.
+// Caller must hold the write lock.
+func (f *Forest) evict(retain map[ledger.RootHash]*trie.MTrie) {
+	for len(f.tries) > f.forestCapacity {
+		var oldest *forestEntry
+		var oldestRootHash ledger.RootHash
+		for rootHash, entry := range f.tries {
+			if _, ok := retain[rootHash]; ok || entry.pins > 0 {
+				continue
+			}
+			if oldest == nil || atomic.LoadUint64(&entry.lastUsed) < atomic.LoadUint64(&oldest.lastUsed) {
+				oldest, oldestRootHash = entry, rootHash
+			}
+		}
+		if oldest == nil {
+			// all tries are pinned, retain them beyond the capacity
+			return
+		}
+
+		delete(f.tries, oldestRootHash)
+		if f.onTreeEvicted != nil {
+			//TODO Log error
+			_ = f.onTreeEvicted(oldest.trie)
+		}
+	}
+}
+
+// RemoveTrie removes a trie to the forest, regardless of whether it is pinned
 func (f *Forest) RemoveTrie(rootHash ledger.RootHash) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	// TODO remove from the file as well
-	f.tries.Remove(rootHash)
-	f.metrics.ForestNumberOfTrees(uint64(f.tries.Len()))
+	delete(f.tries, rootHash)
+	f.metrics.ForestNumberOfTrees(uint64(len(f.tries)))
+}
+
+// Pin prevents the trie with the given root hash from being evicted, until it is unpinned
+// as often as it was pinned. The trie must be stored in the forest.
+func (f *Forest) Pin(rootHash ledger.RootHash) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, found := f.tries[rootHash]
+	if !found {
+		return fmt.Errorf("cannot pin trie with the given rootHash [%x]: trie not found", rootHash)
+	}
+	entry.pins++
+	return nil
+}
+
+// Unpin releases a pin of the trie with the given root hash. Once all its pins are
+// released, the trie can be evicted again when the forest exceeds its capacity.
+func (f *Forest) Unpin(rootHash ledger.RootHash) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, found := f.tries[rootHash]
+	if !found || entry.pins == 0 {
+		return fmt.Errorf("cannot unpin trie with the given rootHash [%x]: trie not pinned", rootHash)
+	}
+	entry.pins--
+	if entry.pins > 0 {
+		return nil
+	}
+
+	// tries retained beyond the capacity can be evicted now
+	f.evict(nil)
+	f.metrics.ForestNumberOfTrees(uint64(len(f.tries)))
+	return nil
+}
+
+// IsPinned returns whether the trie with the given root hash is pinned.
+func (f *Forest) IsPinned(rootHash ledger.RootHash) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	entry, found := f.tries[rootHash]
+	return found && entry.pins > 0
 }
 
 // GetEmptyRootHash returns the rootHash of empty Trie
@@ -338,19 +647,26 @@ func (f *Forest) GetEmptyRootHash() ledger.RootHash {
 
 // MostRecentTouchedRootHash returns the rootHash of the most recently touched trie
 func (f *Forest) MostRecentTouchedRootHash() (ledger.RootHash, error) {
-	keys := f.tries.Keys()
-	if len(keys) > 0 {
-		encodedRootHash := keys[len(keys)-1].(string)
-		rootHashBytes, err := hex.DecodeString(encodedRootHash)
-		if err != nil {
-			return ledger.RootHash(hash.DummyHash), fmt.Errorf("failed to decode the root string: %w", err)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var mostRecent *forestEntry
+	var mostRecentRootHash ledger.RootHash
+	for rootHash, entry := range f.tries {
+		if mostRecent == nil || atomic.LoadUint64(&entry.lastUsed) > atomic.LoadUint64(&mostRecent.lastUsed) {
+			mostRecent, mostRecentRootHash = entry, rootHash
 		}
-		return ledger.ToRootHash(rootHashBytes)
 	}
-	return ledger.RootHash(hash.DummyHash), fmt.Errorf("no trie is stored in the forest")
+	if mostRecent == nil {
+		return ledger.RootHash(hash.DummyHash), fmt.Errorf("no trie is stored in the forest")
+	}
+	return mostRecentRootHash, nil
 }
 
 // Size returns the number of active tries in this store
 func (f *Forest) Size() int {
-	return f.tries.Len()
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.tries)
 }
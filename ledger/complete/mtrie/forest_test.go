@@ -2,8 +2,11 @@ package mtrie
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
@@ -66,7 +69,7 @@ func TestTrieUpdate(t *testing.T) {
 	require.NoError(t, err)
 
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[0]), encoding.EncodePayload(payloads[0])))
 }
@@ -125,7 +128,7 @@ func TestLeftEmptyInsert(t *testing.T) {
 	paths = []ledger.Path{p1, p2, p3}
 	payloads = []*ledger.Payload{v1, v2, v3}
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloads[i])))
@@ -187,7 +190,7 @@ func TestRightEmptyInsert(t *testing.T) {
 	paths = []ledger.Path{p1, p2, p3}
 	payloads = []*ledger.Payload{v1, v2, v3}
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloads[i])))
@@ -247,7 +250,7 @@ func TestExpansionInsert(t *testing.T) {
 	paths = []ledger.Path{p1, p2}
 	payloads = []*ledger.Payload{v1, v2}
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloads[i])))
@@ -319,7 +322,7 @@ func TestFullHouseInsert(t *testing.T) {
 	paths = []ledger.Path{p1, p2, p3}
 	payloads = []*ledger.Payload{v1, v2, v3}
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloads[i])))
@@ -363,7 +366,7 @@ func TestLeafInsert(t *testing.T) {
 	fmt.Println(updatedTrie.String())
 
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloads[i])))
@@ -401,7 +404,7 @@ func TestOverrideValue(t *testing.T) {
 	require.NoError(t, err)
 
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[0]), encoding.EncodePayload(payloads[0])))
 
@@ -434,12 +437,57 @@ func TestDuplicateOverride(t *testing.T) {
 
 	paths = []ledger.Path{p0}
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[0]), encoding.EncodePayload(v2)))
 
 }
 
+// TestPrepareUpdate tests that a prepared update holds the deduplicated writes sorted by path,
+// and results in the same trie as applying the update directly
+func TestPrepareUpdate(t *testing.T) {
+
+	forest, err := NewForest(5, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	p0 := pathByUint8s([]uint8{uint8(200), uint8(74)})
+	p1 := pathByUint8s([]uint8{uint8(53), uint8(74)})
+	p2 := pathByUint8s([]uint8{uint8(116), uint8(22)})
+	v0 := payloadBySlices([]byte{'A'}, []byte{'A'})
+	v1 := payloadBySlices([]byte{'B'}, []byte{'B'})
+	v2 := payloadBySlices([]byte{'C'}, []byte{'C'})
+	v3 := payloadBySlices([]byte{'D'}, []byte{'D'})
+
+	paths := []ledger.Path{p0, p1, p2, p0}
+	payloads := []*ledger.Payload{v0, v1, v2, v3}
+	update := &ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: paths, Payloads: payloads}
+
+	prepared, err := forest.PrepareUpdate(update)
+	require.NoError(t, err)
+	require.Equal(t, []ledger.Path{p1, p2, p0}, prepared.Paths)
+	require.Equal(t, []ledger.Payload{*v1, *v2, *v3}, prepared.Payloads)
+	require.Equal(t, v1.Size()+v2.Size()+v3.Size(), prepared.PayloadSize)
+
+	preparedRoot, err := forest.ApplyPrepared(prepared)
+	require.NoError(t, err)
+
+	otherForest, err := NewForest(5, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+	updatedRoot, err := otherForest.Update(update)
+	require.NoError(t, err)
+	require.Equal(t, updatedRoot, preparedRoot)
+
+	read := &ledger.TrieRead{RootHash: preparedRoot, Paths: []ledger.Path{p0}}
+	retPayloads, err := forest.Read(context.Background(), read)
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[0]), encoding.EncodePayload(v3)))
+
+	// updates with mismatching paths and payloads are rejected
+	update = &ledger.TrieUpdate{RootHash: preparedRoot, Paths: []ledger.Path{p0, p1}, Payloads: []*ledger.Payload{v0}}
+	_, err = forest.PrepareUpdate(update)
+	require.Error(t, err)
+}
+
 // TestReadSafety check if payload returned from a forest are safe against modification,
 // ie. copy of the data is returned, instead of a slice
 func TestReadSafety(t *testing.T) {
@@ -456,7 +504,7 @@ func TestReadSafety(t *testing.T) {
 	require.NoError(t, err)
 
 	read := &ledger.TrieRead{RootHash: baseRoot, Paths: paths}
-	data, err := forest.Read(read)
+	data, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 
 	require.Len(t, data, 1)
@@ -466,7 +514,7 @@ func TestReadSafety(t *testing.T) {
 	data[0].Value = []byte("new value")
 
 	// read again
-	data2, err := forest.Read(read)
+	data2, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	require.Len(t, data2, 1)
 	require.Equal(t, v0, data2[0])
@@ -491,14 +539,14 @@ func TestReadOrder(t *testing.T) {
 	require.NoError(t, err)
 
 	read := &ledger.TrieRead{RootHash: testRoot, Paths: []ledger.Path{p1, p2}}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	require.Equal(t, len(retPayloads), len(payloads))
 	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[0]), encoding.EncodePayload(payloads[0])))
 	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[1]), encoding.EncodePayload(payloads[1])))
 
 	read = &ledger.TrieRead{RootHash: testRoot, Paths: []ledger.Path{p2, p1}}
-	retPayloads, err = forest.Read(read)
+	retPayloads, err = forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	require.Equal(t, len(retPayloads), len(payloads))
 	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[1]), encoding.EncodePayload(payloads[0])))
@@ -538,7 +586,7 @@ func TestMixRead(t *testing.T) {
 	expectedPayloads := []*ledger.Payload{v1, v2, v3, v4}
 
 	read := &ledger.TrieRead{RootHash: baseRoot, Paths: readPaths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(expectedPayloads[i])))
@@ -566,7 +614,7 @@ func TestReadWithDuplicatedKeys(t *testing.T) {
 	paths = []ledger.Path{p1, p2, p3}
 	expectedPayloads := []*ledger.Payload{v1, v2, v1}
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	require.Equal(t, len(expectedPayloads), len(retPayloads))
 	for i := range paths {
@@ -590,7 +638,7 @@ func TestReadNonExistingPath(t *testing.T) {
 
 	p2 := pathByUint8s([]uint8{uint8(116), uint8(129)})
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: []ledger.Path{p2}}
-	retPayloads, err := forest.Read(read)
+	retPayloads, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	require.True(t, retPayloads[0].IsEmpty())
 }
@@ -634,21 +682,21 @@ func TestForkingUpdates(t *testing.T) {
 
 	// Verify payloads are preserved
 	read := &ledger.TrieRead{RootHash: baseRoot, Paths: paths}
-	retPayloads, err := forest.Read(read) // reading from original Trie
+	retPayloads, err := forest.Read(context.Background(), read) // reading from original Trie
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloads[i])))
 	}
 
 	readA := &ledger.TrieRead{RootHash: updatedRootA, Paths: pathsA}
-	retPayloads, err = forest.Read(readA) // reading from updatedTrieA
+	retPayloads, err = forest.Read(context.Background(), readA) // reading from updatedTrieA
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloadsA[i])))
 	}
 
 	readB := &ledger.TrieRead{RootHash: updatedRootB, Paths: pathsB}
-	retPayloads, err = forest.Read(readB) // reading from updatedTrieB
+	retPayloads, err = forest.Read(context.Background(), readB) // reading from updatedTrieB
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloadsB[i])))
@@ -685,14 +733,14 @@ func TestIdenticalUpdateAppliedTwice(t *testing.T) {
 	paths = []ledger.Path{p1, p2, p3}
 	payloads = []*ledger.Payload{v1, v2, v3}
 	read := &ledger.TrieRead{RootHash: updatedRootA, Paths: paths}
-	retPayloadsA, err := forest.Read(read)
+	retPayloadsA, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloadsA[i]), encoding.EncodePayload(payloads[i])))
 	}
 
 	read = &ledger.TrieRead{RootHash: updatedRootB, Paths: paths}
-	retPayloadsB, err := forest.Read(read)
+	retPayloadsB, err := forest.Read(context.Background(), read)
 	require.NoError(t, err)
 	for i := range paths {
 		require.True(t, bytes.Equal(encoding.EncodePayload(retPayloadsB[i]), encoding.EncodePayload(payloads[i])))
@@ -734,7 +782,7 @@ func TestRandomUpdateReadProof(t *testing.T) {
 			}
 		}
 		read := &ledger.TrieRead{RootHash: activeRoot, Paths: nonExistingPaths}
-		retPayloads, err := forest.Read(read)
+		retPayloads, err := forest.Read(context.Background(), read)
 		require.NoError(t, err, "error reading - non existing paths")
 		for _, p := range retPayloads {
 			require.True(t, p.IsEmpty())
@@ -747,7 +795,7 @@ func TestRandomUpdateReadProof(t *testing.T) {
 
 		// test read
 		read = &ledger.TrieRead{RootHash: activeRoot, Paths: paths}
-		retPayloads, err = forest.Read(read)
+		retPayloads, err = forest.Read(context.Background(), read)
 		require.NoError(t, err, "error reading")
 		for i := range payloads {
 			require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[i]), encoding.EncodePayload(payloads[i])))
@@ -759,7 +807,7 @@ func TestRandomUpdateReadProof(t *testing.T) {
 		proofPaths = append(proofPaths, nonExistingPaths...)
 
 		read = &ledger.TrieRead{RootHash: activeRoot, Paths: proofPaths}
-		batchProof, err := forest.Proofs(read)
+		batchProof, err := forest.Proofs(context.Background(), read)
 		require.NoError(t, err, "error generating proofs")
 		require.True(t, proof.VerifyTrieBatchProof(batchProof, ledger.State(activeRoot)))
 
@@ -776,7 +824,7 @@ func TestRandomUpdateReadProof(t *testing.T) {
 		}
 
 		read = &ledger.TrieRead{RootHash: activeRoot, Paths: allPaths}
-		retPayloads, err = forest.Read(read)
+		retPayloads, err = forest.Read(context.Background(), read)
 		require.NoError(t, err)
 		for i, v := range allPayloads {
 			require.True(t, bytes.Equal(encoding.EncodePayload(v), encoding.EncodePayload(retPayloads[i])))
@@ -807,12 +855,100 @@ func TestProofGenerationInclusion(t *testing.T) {
 	updatedRoot, err := forest.Update(update)
 	require.NoError(t, err)
 	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
-	proofs, err := forest.Proofs(read)
+	proofs, err := forest.Proofs(context.Background(), read)
 
 	require.NoError(t, err)
 	require.True(t, proof.VerifyTrieBatchProof(proofs, ledger.State(updatedRoot)))
 }
 
+// TestReadInBatches verifies that reads and proofs spanning multiple batches
+// return the payloads in the order of the requested paths.
+func TestReadInBatches(t *testing.T) {
+	forest, err := NewForest(5, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	paths := utils.RandomPaths(3*readBatchSize + 7)
+	payloads := utils.RandomPayloads(len(paths), 10, 20)
+	update := &ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: paths, Payloads: payloads}
+	updatedRoot, err := forest.Update(update)
+	require.NoError(t, err)
+
+	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
+	retPayloads, err := forest.Read(context.Background(), read)
+	require.NoError(t, err)
+	require.Len(t, retPayloads, len(paths))
+	for i := range paths {
+		require.True(t, bytes.Equal(encoding.EncodePayload(payloads[i]), encoding.EncodePayload(retPayloads[i])))
+	}
+
+	proofs, err := forest.Proofs(context.Background(), read)
+	require.NoError(t, err)
+	require.True(t, proof.VerifyTrieBatchProof(proofs, ledger.State(updatedRoot)))
+}
+
+// TestReadLimits verifies that queries exceeding the read limits are rejected.
+func TestReadLimits(t *testing.T) {
+	forest, err := NewForest(5, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	paths := utils.RandomPaths(10)
+	payloads := utils.RandomPayloads(len(paths), 10, 20)
+	update := &ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: paths, Payloads: payloads}
+	updatedRoot, err := forest.Update(update)
+	require.NoError(t, err)
+	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
+
+	t.Run("max paths", func(t *testing.T) {
+		forest.SetReadLimits(ReadLimits{MaxPaths: len(paths) - 1})
+		defer forest.SetReadLimits(ReadLimits{})
+
+		_, err := forest.Read(context.Background(), read)
+		require.True(t, errors.Is(err, ledger.ErrQueryTooLarge{}))
+
+		_, err = forest.Proofs(context.Background(), read)
+		require.True(t, errors.Is(err, ledger.ErrQueryTooLarge{}))
+
+		forest.SetReadLimits(ReadLimits{MaxPaths: len(paths)})
+		_, err = forest.Read(context.Background(), read)
+		require.NoError(t, err)
+	})
+
+	t.Run("max proof size", func(t *testing.T) {
+		forest.SetReadLimits(ReadLimits{MaxProofSize: ledger.PathLen})
+		defer forest.SetReadLimits(ReadLimits{})
+
+		_, err := forest.Proofs(context.Background(), read)
+		require.True(t, errors.Is(err, ledger.ErrQueryTooLarge{}))
+
+		// reads are not affected by the proof size limit
+		_, err = forest.Read(context.Background(), read)
+		require.NoError(t, err)
+	})
+}
+
+// TestReadCancellation verifies that reads and proofs are aborted when their
+// context is cancelled.
+func TestReadCancellation(t *testing.T) {
+	forest, err := NewForest(5, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	paths := utils.RandomPaths(10)
+	payloads := utils.RandomPayloads(len(paths), 10, 20)
+	update := &ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: paths, Payloads: payloads}
+	updatedRoot, err := forest.Update(update)
+	require.NoError(t, err)
+	read := &ledger.TrieRead{RootHash: updatedRoot, Paths: paths}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = forest.Read(ctx, read)
+	require.True(t, errors.Is(err, context.Canceled))
+
+	_, err = forest.Proofs(ctx, read)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
 func payloadBySlices(keydata []byte, valuedata []byte) *ledger.Payload {
 	key := ledger.Key{KeyParts: []ledger.KeyPart{{Type: 0, Value: keydata}}}
 	value := ledger.Value(valuedata)
@@ -824,3 +960,265 @@ func pathByUint8s(inputs []uint8) ledger.Path {
 	copy(b[:], inputs)
 	return b
 }
+
+// TestPinnedTriesAreNotEvicted tests that the forest only evicts tries which are not pinned,
+// and grows beyond its capacity if all tries are pinned.
+func TestPinnedTriesAreNotEvicted(t *testing.T) {
+
+	var evicted []ledger.RootHash
+	forest, err := NewForest(3, &metrics.NoopCollector{}, func(tree *trie.MTrie) error {
+		evicted = append(evicted, tree.RootHash())
+		return nil
+	})
+	require.NoError(t, err)
+
+	// chain of updates on top of the empty trie
+	update := func(parent ledger.RootHash, i uint8) ledger.RootHash {
+		paths := []ledger.Path{pathByUint8s([]uint8{i, i})}
+		payloads := []*ledger.Payload{payloadBySlices([]byte{i}, []byte{i})}
+		rootHash, err := forest.Update(&ledger.TrieUpdate{RootHash: parent, Paths: paths, Payloads: payloads})
+		require.NoError(t, err)
+		return rootHash
+	}
+
+	emptyRootHash := forest.GetEmptyRootHash()
+	require.NoError(t, forest.Pin(emptyRootHash))
+
+	root1 := update(emptyRootHash, 1)
+	require.NoError(t, forest.Pin(root1))
+	root2 := update(root1, 2)
+	require.NoError(t, forest.Pin(root2))
+	require.Equal(t, 3, forest.Size())
+
+	// all tries are pinned, the forest grows beyond its capacity
+	root3 := update(root2, 3)
+	require.Equal(t, 4, forest.Size())
+	require.Empty(t, evicted)
+
+	// the new trie is retained, the least recently used unpinned trie is evicted
+	root4 := update(root3, 4)
+	require.Equal(t, 4, forest.Size())
+	require.Equal(t, []ledger.RootHash{root3}, evicted)
+
+	// unpinning evicts the trie once the forest is beyond its capacity
+	require.NoError(t, forest.Unpin(root1))
+	require.Equal(t, 3, forest.Size())
+	require.Equal(t, []ledger.RootHash{root3, root1}, evicted)
+	_, err = forest.GetTrie(root1)
+	require.Error(t, err)
+
+	// a trie pinned twice has to be unpinned twice
+	require.NoError(t, forest.Pin(root2))
+	require.NoError(t, forest.Unpin(root2))
+	require.True(t, forest.IsPinned(root2))
+	require.NoError(t, forest.Unpin(root2))
+	require.False(t, forest.IsPinned(root2))
+
+	_, err = forest.GetTrie(root4)
+	require.NoError(t, err)
+
+	// only stored and pinned tries can be pinned and unpinned
+	require.Error(t, forest.Pin(root1))
+	require.Error(t, forest.Unpin(root4))
+}
+
+// TestAddTriesAtomically tests adding several tries to the forest at once.
+func TestAddTriesAtomically(t *testing.T) {
+
+	forest, err := NewForest(2, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	newTrie := func(i uint8) *trie.MTrie {
+		paths := []ledger.Path{pathByUint8s([]uint8{i, i})}
+		payloads := []ledger.Payload{*payloadBySlices([]byte{i}, []byte{i})}
+		updated, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads)
+		require.NoError(t, err)
+		return updated
+	}
+
+	// the added tries are retained beyond the capacity
+	tries := []*trie.MTrie{newTrie(1), newTrie(2), newTrie(3)}
+	err = forest.AddTriesAtomically(tries)
+	require.NoError(t, err)
+	require.Equal(t, 3, forest.Size())
+	for _, added := range tries {
+		_, err := forest.GetTrie(added.RootHash())
+		require.NoError(t, err)
+	}
+
+	// adding the same tries again is a no-op
+	err = forest.AddTriesAtomically(tries)
+	require.NoError(t, err)
+	require.Equal(t, 3, forest.Size())
+}
+
+// TestConcurrentForestAccess tests that tries can be added and read concurrently,
+// e.g. by block execution and checkpointing.
+func TestConcurrentForestAccess(t *testing.T) {
+
+	forest, err := NewForest(20, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	emptyRootHash := forest.GetEmptyRootHash()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rootHash := emptyRootHash
+			for j := 0; j < 10; j++ {
+				paths := utils.RandomPaths(5)
+				payloads := utils.RandomPayloads(5, 1, 10)
+				updated, err := forest.Update(&ledger.TrieUpdate{RootHash: rootHash, Paths: paths, Payloads: payloads})
+				if err != nil {
+					// the parent was evicted by a concurrent update, start over
+					rootHash = emptyRootHash
+					continue
+				}
+				rootHash = updated
+				_, _ = forest.Read(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
+			}
+		}()
+	}
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				tries, err := forest.GetTries()
+				require.NoError(t, err)
+				require.NotEmpty(t, tries)
+				_, err = forest.MostRecentTouchedRootHash()
+				require.NoError(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	require.LessOrEqual(t, forest.Size(), 20)
+}
+
+// TestMemoryUsage tests the memory usage of the forest counts subtries shared by tries once
+func TestMemoryUsage(t *testing.T) {
+
+	forest, err := NewForest(5, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	p1 := pathByUint8s([]uint8{uint8(53), uint8(74)})
+	p2 := pathByUint8s([]uint8{uint8(116), uint8(22)})
+	p3 := pathByUint8s([]uint8{uint8(200), uint8(74)})
+	v1 := payloadBySlices([]byte{'A'}, []byte{'A'})
+	v2 := payloadBySlices([]byte{'B'}, []byte{'B'})
+	v3 := payloadBySlices([]byte{'C'}, []byte{'C'})
+
+	update := &ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: []ledger.Path{p1, p2}, Payloads: []*ledger.Payload{v1, v2}}
+	root1, err := forest.Update(update)
+	require.NoError(t, err)
+
+	// p1 and p2 are in the left subtrie of the root, which is shared by both tries
+	update = &ledger.TrieUpdate{RootHash: root1, Paths: []ledger.Path{p3}, Payloads: []*ledger.Payload{v3}}
+	root2, err := forest.Update(update)
+	require.NoError(t, err)
+
+	trie1, err := forest.GetTrie(root1)
+	require.NoError(t, err)
+	trie2, err := forest.GetTrie(root2)
+	require.NoError(t, err)
+
+	usage1 := trie1.MemoryUsage()
+	require.Equal(t, uint64(2), usage1.Leaves)
+	require.Equal(t, uint64(v1.Size()+v2.Size()), usage1.PayloadBytes)
+	require.Equal(t, usage1.Bytes, trie1.EstimatedMemorySize())
+
+	usage2 := trie2.MemoryUsage()
+	require.Equal(t, uint64(3), usage2.Leaves)
+
+	usage, err := forest.MemoryUsage()
+	require.NoError(t, err)
+	require.Equal(t, usage1.Bytes, usage.TrieBytes[root1])
+	require.Equal(t, usage2.Bytes, usage.TrieBytes[root2])
+	require.Equal(t, uint64(0), usage.TrieBytes[forest.GetEmptyRootHash()])
+
+	require.Equal(t, uint64(3), usage.Leaves)
+	require.Equal(t, uint64(v1.Size()+v2.Size()+v3.Size()), usage.PayloadBytes)
+	require.Greater(t, usage.SharedBytes, uint64(0))
+	require.Equal(t, usage1.Bytes+usage2.Bytes, usage.Bytes+usage.SharedBytes)
+}
+
+// TestReadSharesPayloads tests reads return the payloads stored in the trie without copying them
+func TestReadSharesPayloads(t *testing.T) {
+
+	forest, err := NewForest(5, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	p1 := pathByUint8s([]uint8{uint8(53), uint8(74)})
+	p2 := pathByUint8s([]uint8{uint8(116), uint8(22)})
+	v1 := payloadBySlices([]byte{'A'}, []byte{'A'})
+	v2 := payloadBySlices([]byte{'B'}, []byte{'B'})
+
+	update := &ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: []ledger.Path{p1, p2}, Payloads: []*ledger.Payload{v1, v2}}
+	root, err := forest.Update(update)
+	require.NoError(t, err)
+
+	read := &ledger.TrieRead{RootHash: root, Paths: []ledger.Path{p1, p2, p1}}
+	first, err := forest.Read(context.Background(), read)
+	require.NoError(t, err)
+	require.True(t, first[0].Equals(v1))
+	require.True(t, first[1].Equals(v2))
+
+	// duplicated paths and repeated reads return the same payload
+	require.Same(t, first[0], first[2])
+	read = &ledger.TrieRead{RootHash: root, Paths: []ledger.Path{p1}}
+	second, err := forest.Read(context.Background(), read)
+	require.NoError(t, err)
+	require.Same(t, first[0], second[0])
+
+	// payloads of the update are copied into the trie, so modifying them doesn't change the trie
+	v1.Value[0] = 'X'
+	require.Equal(t, ledger.Value{'A'}, second[0].Value)
+}
+
+// BenchmarkForestRead benchmarks reads of large registers, including duplicated paths.
+// The "with copies" benchmarks copy the read payloads, as reads did before payloads were shared,
+// to compare the allocations:
+//   go test -bench=BenchmarkForestRead -benchmem
+func BenchmarkForestRead(b *testing.B) {
+	for _, valueSize := range []int{32, 1024, 64 * 1024} {
+		forest, err := NewForest(5, &metrics.NoopCollector{}, nil)
+		require.NoError(b, err)
+
+		paths := utils.RandomPaths(1000)
+		payloads := utils.RandomPayloads(len(paths), valueSize, valueSize+1)
+		update := &ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: paths, Payloads: payloads}
+		root, err := forest.Update(update)
+		require.NoError(b, err)
+
+		// read every register twice
+		read := &ledger.TrieRead{RootHash: root, Paths: append(append([]ledger.Path{}, paths...), paths...)}
+
+		b.Run(fmt.Sprintf("value size %d", valueSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := forest.Read(context.Background(), read)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("value size %d with copies", valueSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				payloads, err := forest.Read(context.Background(), read)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for j, payload := range payloads {
+					payloads[j] = payload.DeepCopy()
+				}
+			}
+		})
+	}
+}
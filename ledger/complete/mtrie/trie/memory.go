@@ -0,0 +1,98 @@
+package trie
+
+import (
+	"unsafe"
+
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+)
+
+// nodeSize is the size of a node in memory, without its payload
+var nodeSize = uint64(unsafe.Sizeof(node.Node{}))
+
+// MemoryUsage holds the approximate memory used by the nodes of a trie, or of several tries,
+// in which case nodes shared by several tries are counted once.
+type MemoryUsage struct {
+	InterimNodes uint64 // number of interim nodes
+	Leaves       uint64 // number of leaves
+	PayloadBytes uint64 // size in bytes of the payloads held by the leaves
+	Bytes        uint64 // approximate size in bytes of all nodes, including their payloads
+}
+
+// NodeMemorySize returns the approximate memory used by the given node, including its payload
+// but not its children.
+func NodeMemorySize(n *node.Node) uint64 {
+	size := nodeSize
+	if n.IsLeaf() && n.Payload() != nil {
+		size += uint64(n.Payload().Size())
+	}
+	return size
+}
+
+// add accounts the given node, without its children.
+func (u *MemoryUsage) add(n *node.Node) uint64 {
+	size := NodeMemorySize(n)
+	if n.IsLeaf() {
+		u.Leaves++
+		u.PayloadBytes += size - nodeSize
+	} else {
+		u.InterimNodes++
+	}
+	u.Bytes += size
+	return size
+}
+
+// MemoryUsage walks all nodes of the trie and returns their approximate memory usage.
+// Concurrency safe (as Tries are immutable structures by convention)
+func (mt *MTrie) MemoryUsage() MemoryUsage {
+	var usage MemoryUsage
+
+	var walk func(n *node.Node)
+	walk = func(n *node.Node) {
+		if n == nil {
+			return
+		}
+		usage.add(n)
+		walk(n.LeftChild())
+		walk(n.RightChild())
+	}
+	walk(mt.root)
+
+	return usage
+}
+
+// EstimatedMemorySize returns the approximate memory in bytes used by the trie, ignoring
+// that subtries may be shared with other tries.
+// Concurrency safe (as Tries are immutable structures by convention)
+func (mt *MTrie) EstimatedMemorySize() uint64 {
+	return mt.MemoryUsage().Bytes
+}
+
+// CombinedMemoryUsage returns the approximate memory usage of the given tries, counting nodes
+// shared by several tries once, as well as the approximate memory in bytes used by each trie
+// on its own. The difference between the combined usage and the sum of the usage of each trie
+// is the memory saved by sharing subtries.
+// Every node is only walked once, but the size of the subtrie of each node is remembered
+// during the walk, so memory proportional to the number of distinct nodes is allocated.
+func CombinedMemoryUsage(tries []*MTrie) (combined MemoryUsage, trieBytes []uint64) {
+	subtrieBytes := make(map[*node.Node]uint64)
+
+	var walk func(n *node.Node) uint64
+	walk = func(n *node.Node) uint64 {
+		if n == nil {
+			return 0
+		}
+		if bytes, ok := subtrieBytes[n]; ok {
+			return bytes
+		}
+		bytes := combined.add(n) + walk(n.LeftChild()) + walk(n.RightChild())
+		subtrieBytes[n] = bytes
+		return bytes
+	}
+
+	trieBytes = make([]uint64, len(tries))
+	for i, t := range tries {
+		trieBytes[i] = walk(t.root)
+	}
+
+	return combined, trieBytes
+}
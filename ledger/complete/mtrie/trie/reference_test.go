@@ -0,0 +1,319 @@
+package trie_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/bitutils"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+// The recursive implementations of trie updates and proofs below are kept as a reference
+// for the iterative implementations of the trie package, which must produce identical tries
+// and proofs. The benchmarks compare the latency of both implementations.
+
+// referenceUpdate is the recursive implementation of the trie update.
+func referenceUpdate(
+	nodeHeight int, parentNode *node.Node,
+	paths []ledger.Path, payloads []ledger.Payload, compactLeaf *node.Node,
+) *node.Node {
+	if len(paths) == 0 {
+		if compactLeaf != nil {
+			return node.NewLeaf(*compactLeaf.Path(), compactLeaf.Payload(), nodeHeight)
+		}
+		return parentNode
+	}
+
+	if len(paths) == 1 && parentNode == nil && compactLeaf == nil {
+		return node.NewLeaf(paths[0], &payloads[0], nodeHeight)
+	}
+
+	if parentNode != nil && parentNode.IsLeaf() {
+		found := false
+		parentPath := *parentNode.Path()
+		for i, p := range paths {
+			if p == parentPath {
+				if len(paths) == 1 {
+					if !parentNode.Payload().Equals(&payloads[i]) {
+						return node.NewLeaf(paths[i], &payloads[i], nodeHeight)
+					}
+					return parentNode
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			compactLeaf = parentNode
+		}
+	}
+
+	depth := ledger.NodeMaxHeight - nodeHeight
+	partitionIndex := referenceSplit(paths, depth, func(i, j int) {
+		payloads[i], payloads[j] = payloads[j], payloads[i]
+	})
+	lpaths, rpaths := paths[:partitionIndex], paths[partitionIndex:]
+	lpayloads, rpayloads := payloads[:partitionIndex], payloads[partitionIndex:]
+
+	var lcompactLeaf, rcompactLeaf *node.Node
+	if compactLeaf != nil {
+		path := *compactLeaf.Path()
+		if bitutils.Bit(path[:], depth) == 0 {
+			lcompactLeaf = compactLeaf
+		} else {
+			rcompactLeaf = compactLeaf
+		}
+	}
+
+	var lchildParent, rchildParent *node.Node
+	if parentNode != nil {
+		lchildParent = parentNode.LeftChild()
+		rchildParent = parentNode.RightChild()
+	}
+
+	var lChild, rChild *node.Node
+	if len(lpaths) < 16 || len(rpaths) < 16 {
+		lChild = referenceUpdate(nodeHeight-1, lchildParent, lpaths, lpayloads, lcompactLeaf)
+		rChild = referenceUpdate(nodeHeight-1, rchildParent, rpaths, rpayloads, rcompactLeaf)
+	} else {
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lChild = referenceUpdate(nodeHeight-1, lchildParent, lpaths, lpayloads, lcompactLeaf)
+		}()
+		rChild = referenceUpdate(nodeHeight-1, rchildParent, rpaths, rpayloads, rcompactLeaf)
+		wg.Wait()
+	}
+
+	if lChild == lchildParent && rChild == rchildParent {
+		return parentNode
+	}
+	return node.NewInterimNode(nodeHeight, lChild, rChild)
+}
+
+// referenceProve is the recursive implementation of the trie proofs.
+func referenceProve(head *node.Node, paths []ledger.Path, proofs []*ledger.TrieProof) {
+	if len(paths) == 0 || head == nil {
+		return
+	}
+
+	if head.IsLeaf() {
+		for i, path := range paths {
+			if *head.Path() == path {
+				proofs[i].Path = *head.Path()
+				proofs[i].Payload = head.Payload()
+				proofs[i].Inclusion = true
+			}
+		}
+		return
+	}
+
+	for _, p := range proofs {
+		p.Steps++
+	}
+
+	depth := ledger.NodeMaxHeight - head.Height()
+	partitionIndex := referenceSplit(paths, depth, func(i, j int) {
+		proofs[i], proofs[j] = proofs[j], proofs[i]
+	})
+	lpaths, rpaths := paths[:partitionIndex], paths[partitionIndex:]
+	lproofs, rproofs := proofs[:partitionIndex], proofs[partitionIndex:]
+
+	addReferenceSiblingHash(head.RightChild(), depth, lproofs)
+	addReferenceSiblingHash(head.LeftChild(), depth, rproofs)
+
+	if len(lpaths) < 64 || len(rpaths) < 64 {
+		referenceProve(head.LeftChild(), lpaths, lproofs)
+		referenceProve(head.RightChild(), rpaths, rproofs)
+		return
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		referenceProve(head.LeftChild(), lpaths, lproofs)
+	}()
+	referenceProve(head.RightChild(), rpaths, rproofs)
+	wg.Wait()
+}
+
+func addReferenceSiblingHash(siblingTrie *node.Node, depth int, proofs []*ledger.TrieProof) {
+	if siblingTrie == nil || len(proofs) == 0 {
+		return
+	}
+	nodeHash := siblingTrie.Hash()
+	if nodeHash == ledger.GetDefaultHashForHeight(siblingTrie.Height()) {
+		return
+	}
+	for _, p := range proofs {
+		bitutils.SetBit(p.Flags, depth)
+		p.Interims = append(p.Interims, nodeHash)
+	}
+}
+
+// referenceSplit partitions the paths by the bit at the given index, and applies the same
+// permutation with the given swap function.
+func referenceSplit(paths []ledger.Path, bitIndex int, swap func(i, j int)) int {
+	i := 0
+	for j, path := range paths {
+		if bitutils.Bit(path[:], bitIndex) == 0 {
+			paths[i], paths[j] = paths[j], paths[i]
+			swap(i, j)
+			i++
+		}
+	}
+	return i
+}
+
+func emptyProofs(n int) []*ledger.TrieProof {
+	proofs := make([]*ledger.TrieProof, n)
+	for i := range proofs {
+		proofs[i] = ledger.NewTrieProof()
+		proofs[i].Flags = make([]byte, ledger.PathLen)
+	}
+	return proofs
+}
+
+func randomWrites(n int) ([]ledger.Path, []ledger.Payload) {
+	paths := utils.RandomPaths(n)
+	payloads := make([]ledger.Payload, n)
+	for i := range payloads {
+		payloads[i] = *utils.LightPayload(uint16(rand.Intn(65536)), uint16(rand.Intn(65536)))
+	}
+	return paths, payloads
+}
+
+func copyWrites(paths []ledger.Path, payloads []ledger.Payload) ([]ledger.Path, []ledger.Payload) {
+	return append([]ledger.Path(nil), paths...), append([]ledger.Payload(nil), payloads...)
+}
+
+// Test_IterativeMatchesReference checks that the iterative update and proof generation
+// produce the same tries and proofs as the recursive reference implementations.
+func Test_IterativeMatchesReference(t *testing.T) {
+	rand.Seed(1)
+
+	parentPaths, parentPayloads := randomWrites(10000)
+	parent, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), parentPaths, parentPayloads)
+	require.NoError(t, err)
+
+	// update new registers, overwrite registers and re-write registers with their current value
+	paths, payloads := randomWrites(5000)
+	for i := 0; i < 1000; i++ {
+		paths = append(paths, parentPaths[i])
+		payloads = append(payloads, *utils.LightPayload(uint16(i), uint16(i)))
+	}
+	paths = append(paths, parentPaths[1000:2000]...)
+	payloads = append(payloads, parentPayloads[1000:2000]...)
+
+	t.Run("update", func(t *testing.T) {
+		iterativePaths, iterativePayloads := copyWrites(paths, payloads)
+		updated, err := trie.NewTrieWithUpdatedRegisters(parent, iterativePaths, iterativePayloads)
+		require.NoError(t, err)
+
+		referencePaths, referencePayloads := copyWrites(paths, payloads)
+		reference := referenceUpdate(ledger.NodeMaxHeight, parent.RootNode(), referencePaths, referencePayloads, nil)
+
+		require.Equal(t, reference.Hash(), updated.RootNode().Hash())
+		require.Equal(t, reference.RegCount(), updated.AllocatedRegCount())
+		require.Equal(t, reference.MaxDepth(), updated.MaxDepth())
+		require.True(t, updated.IsAValidTrie())
+	})
+
+	t.Run("unchanged update", func(t *testing.T) {
+		unchangedPaths, unchangedPayloads := copyWrites(parentPaths, parentPayloads)
+		updated, err := trie.NewTrieWithUpdatedRegisters(parent, unchangedPaths, unchangedPayloads)
+		require.NoError(t, err)
+		require.Same(t, parent.RootNode(), updated.RootNode())
+	})
+
+	t.Run("proofs", func(t *testing.T) {
+		// prove existing and missing registers
+		provenPaths, _ := randomWrites(1000)
+		provenPaths = append(provenPaths, parentPaths[:1000]...)
+
+		iterativePaths := append([]ledger.Path(nil), provenPaths...)
+		iterativeProofs := emptyProofs(len(iterativePaths))
+		parent.UnsafeProofs(iterativePaths, iterativeProofs)
+
+		referencePaths := append([]ledger.Path(nil), provenPaths...)
+		referenceProofs := emptyProofs(len(referencePaths))
+		referenceProve(parent.RootNode(), referencePaths, referenceProofs)
+
+		require.Equal(t, proofsByPath(referencePaths, referenceProofs), proofsByPath(iterativePaths, iterativeProofs))
+	})
+}
+
+func proofsByPath(paths []ledger.Path, proofs []*ledger.TrieProof) map[ledger.Path]*ledger.TrieProof {
+	byPath := make(map[ledger.Path]*ledger.TrieProof, len(paths))
+	for i, path := range paths {
+		byPath[path] = proofs[i]
+	}
+	return byPath
+}
+
+// benchmarkRegisters is the number of registers updated and proven by the benchmarks.
+const benchmarkRegisters = 1 << 20
+
+// Benchmark_Update compares the latency of the iterative and the recursive trie update
+// for 1M register updates.
+func Benchmark_Update(b *testing.B) {
+	rand.Seed(1)
+	paths, payloads := randomWrites(benchmarkRegisters)
+	emptyTrie := trie.NewEmptyMTrie()
+
+	b.Run("iterative", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := trie.NewTrieWithUpdatedRegisters(emptyTrie, paths, payloads)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("recursive", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = referenceUpdate(ledger.NodeMaxHeight, emptyTrie.RootNode(), paths, payloads, nil)
+		}
+	})
+}
+
+// Benchmark_Proofs compares the latency of the iterative and the recursive proof generation
+// for 1M registers.
+func Benchmark_Proofs(b *testing.B) {
+	rand.Seed(1)
+	paths, payloads := randomWrites(benchmarkRegisters)
+	populated, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("iterative", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			proofs := emptyProofs(len(paths))
+			b.StartTimer()
+			populated.UnsafeProofs(paths, proofs)
+		}
+	})
+
+	b.Run("recursive", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			proofs := emptyProofs(len(paths))
+			b.StartTimer()
+			referenceProve(populated.RootNode(), paths, proofs)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package trie
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/bitutils"
+	"github.com/onflow/flow-go/ledger/common/hash"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
 )
 
@@ -192,57 +194,179 @@ func NewTrieWithUpdatedRegisters(parentTrie *MTrie, updatedPaths []ledger.Path,
 	return updatedTrie, nil
 }
 
+// updateFrame is a pending node update on the explicit stack of `update`.
+type updateFrame struct {
+	nodeHeight  int
+	parentNode  *node.Node
+	paths       []ledger.Path
+	payloads    []ledger.Payload
+	compactLeaf *node.Node
+
+	// set once the updates of the children are scheduled
+	expanded     bool
+	lchildParent *node.Node
+	rchildParent *node.Node
+	// lChildResult receives the left child, if it is updated in a separate goroutine
+	lChildResult chan *node.Node
+}
+
+// parallelUpdateThreshold is the minimum number of updated registers in both sub-tries
+// of a node for updating the left sub-trie in a separate goroutine.
+const parallelUpdateThreshold = 16
+
 // update traverses the subtree and updates the stored registers
 // CAUTION: while updating, `paths` and `payloads` are permuted IN-PLACE for optimized processing.
 // UNSAFE: method requires the following conditions to be satisfied:
 //   * paths all share the same common prefix [0 : mt.maxHeight-1 - nodeHeight)
 //     (excluding the bit at index headHeight)
 //   * paths are NOT duplicated
+//
+// The traversal is iterative: pending node updates are kept on an explicit stack, and the
+// updated children of a node are collected on a result stack until the node is rebuilt.
+// As the traversal is depth-first, both stacks are bounded by the trie height and
+// allocated upfront.
 func update(
 	nodeHeight int, parentNode *node.Node,
 	paths []ledger.Path, payloads []ledger.Payload, compactLeaf *node.Node,
 ) *node.Node {
+	// each level holds at most an expanded frame and the pending frame of its right child
+	stack := make([]updateFrame, 0, 2*nodeHeight+3)
+	// each level holds at most the updated left child of an expanded frame
+	results := make([]*node.Node, 0, nodeHeight+2)
+
+	stack = append(stack, updateFrame{
+		nodeHeight:  nodeHeight,
+		parentNode:  parentNode,
+		paths:       paths,
+		payloads:    payloads,
+		compactLeaf: compactLeaf,
+	})
+
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		f := &stack[top]
+
+		if f.expanded {
+			// the children are updated: the right child is on top of the results,
+			// the left child is below or computed by a separate goroutine
+			rChild := results[len(results)-1]
+			results = results[:len(results)-1]
+			var lChild *node.Node
+			if f.lChildResult != nil {
+				lChild = <-f.lChildResult
+			} else {
+				lChild = results[len(results)-1]
+				results = results[:len(results)-1]
+			}
+
+			// mitigate storage exhaustion attack: avoids creating a new node when the exact same
+			// payload is re-written at a register.
+			updated := f.parentNode
+			if lChild != f.lchildParent || rChild != f.rchildParent {
+				updated = node.NewInterimNode(f.nodeHeight, lChild, rChild)
+			}
+			stack = stack[:top]
+			results = append(results, updated)
+			continue
+		}
+
+		if updated, done := f.terminal(); done {
+			stack = stack[:top]
+			results = append(results, updated.build(f.nodeHeight))
+			continue
+		}
+
+		left, right := f.split()
+		f.expanded = true
+
+		if len(left.paths) < parallelUpdateThreshold || len(right.paths) < parallelUpdateThreshold {
+			// runtime optimization: if there are _no_ updates for either left or right sub-tree, proceed single-threaded.
+			// The left child is pushed last, so it is updated first and its result ends up below the right child.
+			stack = append(stack, right, left)
+			continue
+		}
+
+		// runtime optimization: process the left child is a separate goroutine
+		lChildResult := make(chan *node.Node, 1)
+		f.lChildResult = lChildResult
+		go func() {
+			lChildResult <- update(left.nodeHeight, left.parentNode, left.paths, left.payloads, left.compactLeaf)
+		}()
+		stack = append(stack, right)
+	}
+
+	return results[0]
+}
+
+// updatedSubtrie is the result of updates which end the traversal of the trie: either an
+// existing node, or a new leaf holding the given path and payload.
+type updatedSubtrie struct {
+	node      *node.Node
+	isNewLeaf bool
+	path      ledger.Path
+	payload   *ledger.Payload
+}
+
+// build returns the root node of the updated sub-trie at the given height.
+func (u updatedSubtrie) build(nodeHeight int) *node.Node {
+	if u.isNewLeaf {
+		return node.NewLeaf(u.path, u.payload, nodeHeight)
+	}
+	return u.node
+}
+
+// terminal handles the updates of a frame which end the traversal of the trie. It returns
+// the updated sub-trie and true if the traversal ends. Otherwise, it returns false and the
+// children of the node need to be updated. In this case, a leaf which is not updated is
+// carried down the traversal as the frame's compact leaf.
+func (f *updateFrame) terminal() (updatedSubtrie, bool) {
 	// No new paths to write
-	if len(paths) == 0 {
+	if len(f.paths) == 0 {
 		// check is a compactLeaf from a higher height is still left.
-		if compactLeaf != nil {
+		if f.compactLeaf != nil {
 			// create a new node for the compact leaf path and payload. The old node shouldn't
 			// be recycled as it is still used by the tree copy before the update.
-			return node.NewLeaf(*compactLeaf.Path(), compactLeaf.Payload(), nodeHeight)
+			return newLeafSubtrie(*f.compactLeaf.Path(), f.compactLeaf.Payload()), true
 		}
-		return parentNode
+		return updatedSubtrie{node: f.parentNode}, true
 	}
 
-	if len(paths) == 1 && parentNode == nil && compactLeaf == nil {
-		return node.NewLeaf(paths[0], &payloads[0], nodeHeight)
+	if len(f.paths) == 1 && f.parentNode == nil && f.compactLeaf == nil {
+		return newLeafSubtrie(f.paths[0], &f.payloads[0]), true
 	}
 
-	if parentNode != nil && parentNode.IsLeaf() { // if we're here then compactLeaf == nil
+	if f.parentNode != nil && f.parentNode.IsLeaf() { // if we're here then compactLeaf == nil
 		// check if the parent node path is among the updated paths
-		found := false
-		parentPath := *parentNode.Path()
-		for i, p := range paths {
+		parentPath := *f.parentNode.Path()
+		for i, p := range f.paths {
 			if p == parentPath {
-				// the case where the recursion stops: only one path to update
-				if len(paths) == 1 {
-					if !parentNode.Payload().Equals(&payloads[i]) {
-						return node.NewLeaf(paths[i], &payloads[i], nodeHeight)
+				// the case where the traversal stops: only one path to update
+				if len(f.paths) == 1 {
+					if !f.parentNode.Payload().Equals(&f.payloads[i]) {
+						return newLeafSubtrie(f.paths[i], &f.payloads[i]), true
 					}
 					// avoid creating a new node when the same payload is written
-					return parentNode
+					return updatedSubtrie{node: f.parentNode}, true
 				}
-				// the case where the recursion carries on: len(paths)>1
-				found = true
-				break
+				// the case where the traversal carries on: len(paths)>1
+				return updatedSubtrie{}, false
 			}
 		}
-		if !found {
-			// if the parent node carries a path not included in the input path, then the parent node
-			// represents a compact leaf that needs to be carried down the recursion.
-			compactLeaf = parentNode
-		}
+		// if the parent node carries a path not included in the input path, then the parent node
+		// represents a compact leaf that needs to be carried down the traversal.
+		f.compactLeaf = f.parentNode
 	}
 
+	return updatedSubtrie{}, false
+}
+
+func newLeafSubtrie(path ledger.Path, payload *ledger.Payload) updatedSubtrie {
+	return updatedSubtrie{isNewLeaf: true, path: path, payload: payload}
+}
+
+// split partitions the updates of a frame, which doesn't end the traversal, into the
+// updates of its left and right children.
+func (f *updateFrame) split() (updateFrame, updateFrame) {
 	// in the remaining code: the registers to update are strictly larger than 1:
 	//   - either len(paths)>1
 	//   - or len(paths) == 1 and compactLeaf!= nil
@@ -250,55 +374,44 @@ func update(
 	// Split paths and payloads to recurse:
 	// lpaths contains all paths that have `0` at the partitionIndex
 	// rpaths contains all paths that have `1` at the partitionIndex
-	depth := ledger.NodeMaxHeight - nodeHeight // distance to the tree root
-	partitionIndex := splitByPath(paths, payloads, depth)
-	lpaths, rpaths := paths[:partitionIndex], paths[partitionIndex:]
-	lpayloads, rpayloads := payloads[:partitionIndex], payloads[partitionIndex:]
+	depth := ledger.NodeMaxHeight - f.nodeHeight // distance to the tree root
+	partitionIndex := splitByPath(f.paths, f.payloads, depth)
+	lpaths, rpaths := f.paths[:partitionIndex], f.paths[partitionIndex:]
+	lpayloads, rpayloads := f.payloads[:partitionIndex], f.payloads[partitionIndex:]
 
 	// check if there is a compact leaf that needs to get deep to height 0
 	var lcompactLeaf, rcompactLeaf *node.Node
-	if compactLeaf != nil {
+	if f.compactLeaf != nil {
 		// if yes, check which branch it will go to.
-		path := *compactLeaf.Path()
+		path := *f.compactLeaf.Path()
 		if bitutils.Bit(path[:], depth) == 0 {
-			lcompactLeaf = compactLeaf
+			lcompactLeaf = f.compactLeaf
 		} else {
-			rcompactLeaf = compactLeaf
+			rcompactLeaf = f.compactLeaf
 		}
 	}
 
 	// set the parent node children
-	var lchildParent, rchildParent *node.Node
-	if parentNode != nil {
-		lchildParent = parentNode.LeftChild()
-		rchildParent = parentNode.RightChild()
-	}
-
-	// recurse over each branch
-	var lChild, rChild *node.Node
-	parallelRecursionThreshold := 16
-	if len(lpaths) < parallelRecursionThreshold || len(rpaths) < parallelRecursionThreshold {
-		// runtime optimization: if there are _no_ updates for either left or right sub-tree, proceed single-threaded
-		lChild = update(nodeHeight-1, lchildParent, lpaths, lpayloads, lcompactLeaf)
-		rChild = update(nodeHeight-1, rchildParent, rpaths, rpayloads, rcompactLeaf)
-	} else {
-		// runtime optimization: process the left child is a separate thread
-		wg := sync.WaitGroup{}
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			lChild = update(nodeHeight-1, lchildParent, lpaths, lpayloads, lcompactLeaf)
-		}()
-		rChild = update(nodeHeight-1, rchildParent, rpaths, rpayloads, rcompactLeaf)
-		wg.Wait()
-	}
-
-	// mitigate storage exhaustion attack: avoids creating a new node when the exact same
-	// payload is re-written at a register.
-	if lChild == lchildParent && rChild == rchildParent {
-		return parentNode
-	}
-	return node.NewInterimNode(nodeHeight, lChild, rChild)
+	if f.parentNode != nil {
+		f.lchildParent = f.parentNode.LeftChild()
+		f.rchildParent = f.parentNode.RightChild()
+	}
+
+	left := updateFrame{
+		nodeHeight:  f.nodeHeight - 1,
+		parentNode:  f.lchildParent,
+		paths:       lpaths,
+		payloads:    lpayloads,
+		compactLeaf: lcompactLeaf,
+	}
+	right := updateFrame{
+		nodeHeight:  f.nodeHeight - 1,
+		parentNode:  f.rchildParent,
+		paths:       rpaths,
+		payloads:    rpayloads,
+		compactLeaf: rcompactLeaf,
+	}
+	return left, right
 }
 
 // UnsafeProofs provides proofs for the given paths.
@@ -308,6 +421,18 @@ func (mt *MTrie) UnsafeProofs(paths []ledger.Path, proofs []*ledger.TrieProof) {
 	prove(mt.root, paths, proofs)
 }
 
+// proveFrame is a pending sub-trie on the explicit stack of `proveSubtrie`.
+type proveFrame struct {
+	head   *node.Node
+	paths  []ledger.Path
+	proofs []*ledger.TrieProof
+}
+
+// parallelProofThreshold is the minimum number of proven registers in both sub-tries of a
+// node for proving the left sub-trie in a separate goroutine. It avoids the parallelization
+// going too deep in the traversal.
+const parallelProofThreshold = 64
+
 // prove traverses the subtree and stores proofs for the given register paths in
 // the provided `proofs` slice
 // CAUTION: while updating, `paths` and `proofs` are permuted IN-PLACE for optimized processing.
@@ -315,65 +440,79 @@ func (mt *MTrie) UnsafeProofs(paths []ledger.Path, proofs []*ledger.TrieProof) {
 //   * paths all share the same common prefix [0 : mt.maxHeight-1 - nodeHeight)
 //     (excluding the bit at index headHeight)
 func prove(head *node.Node, paths []ledger.Path, proofs []*ledger.TrieProof) {
-	// check for empty paths
-	if len(paths) == 0 {
-		return
-	}
+	var wg sync.WaitGroup
+	proveSubtrie(head, paths, proofs, &wg)
+	wg.Wait()
+}
 
-	// we've reached the end of a trie
-	// and path is not found (noninclusion proof)
-	if head == nil {
-		// by default, proofs are non-inclusion proofs
-		return
-	}
+// proveSubtrie iteratively traverses the sub-trie with a depth-first search on an explicit
+// stack. Sub-tries proven in separate goroutines are tracked by the given wait group.
+func proveSubtrie(head *node.Node, paths []ledger.Path, proofs []*ledger.TrieProof, wg *sync.WaitGroup) {
+	// each level holds at most the pending frame of a right child
+	stack := make([]proveFrame, 0, ledger.NodeMaxHeight+2)
+	stack = append(stack, proveFrame{head: head, paths: paths, proofs: proofs})
 
-	// we've reached a leaf
-	if head.IsLeaf() {
-		for i, path := range paths {
-			// value matches (inclusion proof)
-			if *head.Path() == path {
-				proofs[i].Path = *head.Path()
-				proofs[i].Payload = head.Payload()
-				proofs[i].Inclusion = true
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// check for empty paths
+		if len(f.paths) == 0 {
+			continue
+		}
+
+		// we've reached the end of a trie
+		// and path is not found (noninclusion proof)
+		if f.head == nil {
+			// by default, proofs are non-inclusion proofs
+			continue
+		}
+
+		// we've reached a leaf
+		if f.head.IsLeaf() {
+			for i, path := range f.paths {
+				// value matches (inclusion proof)
+				if *f.head.Path() == path {
+					f.proofs[i].Path = *f.head.Path()
+					f.proofs[i].Payload = f.head.Payload()
+					f.proofs[i].Inclusion = true
+				}
 			}
+			// by default, proofs are non-inclusion proofs
+			continue
 		}
-		// by default, proofs are non-inclusion proofs
-		return
-	}
 
-	// increment steps for all the proofs
-	for _, p := range proofs {
-		p.Steps++
-	}
+		// increment steps for all the proofs
+		for _, p := range f.proofs {
+			p.Steps++
+		}
 
-	// partition step to quick sort the paths:
-	// lpaths contains all paths that have `0` at the partitionIndex
-	// rpaths contains all paths that have `1` at the partitionIndex
-	depth := ledger.NodeMaxHeight - head.Height() // distance to the tree root
-	partitionIndex := splitTrieProofsByPath(paths, proofs, depth)
-	lpaths, rpaths := paths[:partitionIndex], paths[partitionIndex:]
-	lproofs, rproofs := proofs[:partitionIndex], proofs[partitionIndex:]
+		// partition step to quick sort the paths:
+		// lpaths contains all paths that have `0` at the partitionIndex
+		// rpaths contains all paths that have `1` at the partitionIndex
+		depth := ledger.NodeMaxHeight - f.head.Height() // distance to the tree root
+		partitionIndex := splitTrieProofsByPath(f.paths, f.proofs, depth)
+		lpaths, rpaths := f.paths[:partitionIndex], f.paths[partitionIndex:]
+		lproofs, rproofs := f.proofs[:partitionIndex], f.proofs[partitionIndex:]
 
-	parallelRecursionThreshold := 64 // threshold to avoid the parallelization going too deep in the recursion
-	if len(lpaths) < parallelRecursionThreshold || len(rpaths) < parallelRecursionThreshold {
-		// runtime optimization: below the parallelRecursionThreshold, we proceed single-threaded
-		addSiblingTrieHashToProofs(head.RightChild(), depth, lproofs)
-		prove(head.LeftChild(), lpaths, lproofs)
+		addSiblingTrieHashToProofs(f.head.RightChild(), depth, lproofs)
+		addSiblingTrieHashToProofs(f.head.LeftChild(), depth, rproofs)
+
+		left := proveFrame{head: f.head.LeftChild(), paths: lpaths, proofs: lproofs}
+		right := proveFrame{head: f.head.RightChild(), paths: rpaths, proofs: rproofs}
+
+		if len(lpaths) < parallelProofThreshold || len(rpaths) < parallelProofThreshold {
+			// runtime optimization: below the parallelProofThreshold, we proceed single-threaded
+			stack = append(stack, right, left)
+			continue
+		}
 
-		addSiblingTrieHashToProofs(head.LeftChild(), depth, rproofs)
-		prove(head.RightChild(), rpaths, rproofs)
-	} else {
-		wg := sync.WaitGroup{}
 		wg.Add(1)
 		go func() {
-			addSiblingTrieHashToProofs(head.RightChild(), depth, lproofs)
-			prove(head.LeftChild(), lpaths, lproofs)
-			wg.Done()
+			defer wg.Done()
+			proveSubtrie(left.head, left.paths, left.proofs, wg)
 		}()
-
-		addSiblingTrieHashToProofs(head.LeftChild(), depth, rproofs)
-		prove(head.RightChild(), rpaths, rproofs)
-		wg.Wait()
+		stack = append(stack, right)
 	}
 }
 
@@ -397,8 +536,13 @@ func addSiblingTrieHashToProofs(siblingTrie *node.Node, depth int, proofs []*led
 	// TODO: On update, prune subtries which only contain empty registers.
 	//       Then, a child is nil if and only if the subtrie is empty.
 
-	nodeHash := siblingTrie.Hash()
-	isDef := nodeHash == ledger.GetDefaultHashForHeight(siblingTrie.Height())
+	addSiblingHashToProofs(siblingTrie.Hash(), siblingTrie.Height(), depth, proofs)
+}
+
+// addSiblingHashToProofs adds the given root hash of a sibling trie at the given height
+// to the proofs, if it is a non-default hash.
+func addSiblingHashToProofs(nodeHash hash.Hash, height int, depth int, proofs []*ledger.TrieProof) {
+	isDef := nodeHash == ledger.GetDefaultHashForHeight(height)
 	if !isDef { // in proofs, we only provide non-default value hashes
 		for _, p := range proofs {
 			bitutils.SetBit(p.Flags, depth)
@@ -459,6 +603,112 @@ func dumpAsJSON(n *node.Node, encoder *json.Encoder) error {
 	return nil
 }
 
+// IteratePayloads calls fn with the path and payload of every register of the trie in ascending
+// order of paths, walking the trie without collecting the payloads in memory. The iteration
+// stops at the first error returned by fn.
+func (mt *MTrie) IteratePayloads(fn func(path ledger.Path, payload *ledger.Payload) error) error {
+	return iteratePayloads(mt.root, fn)
+}
+
+// iteratePayloads calls fn with every payload of the sub-trie with root n
+func iteratePayloads(n *node.Node, fn func(path ledger.Path, payload *ledger.Payload) error) error {
+	if n == nil {
+		return nil
+	}
+	if n.IsLeaf() {
+		payload := n.Payload()
+		if payload == nil {
+			return nil
+		}
+		return fn(*n.Path(), payload)
+	}
+
+	err := iteratePayloads(n.LeftChild(), fn)
+	if err != nil {
+		return err
+	}
+	return iteratePayloads(n.RightChild(), fn)
+}
+
+// DiffPayloads calls fn with the path and the payloads before and after of every register whose value
+// differs between the tries, in ascending order of paths. The payload is nil if the register doesn't
+// exist in a trie. Sub-tries with equal hashes have the same registers and are skipped, so the walk
+// only visits the parts of the tries which differ. The walk stops at the first error returned by fn.
+func DiffPayloads(before, after *MTrie, fn func(path ledger.Path, before, after *ledger.Payload) error) error {
+	return diffPayloads(before.root, after.root, fn)
+}
+
+// diffPayloads calls fn with the differing payloads of the sub-tries with roots a and b at the same
+// position in their tries
+func diffPayloads(a, b *node.Node, fn func(path ledger.Path, before, after *ledger.Payload) error) error {
+	if a == nil && b == nil {
+		return nil
+	}
+	if a != nil && b != nil && a.Hash() == b.Hash() {
+		return nil
+	}
+	if a != nil && !a.IsLeaf() && b != nil && !b.IsLeaf() {
+		err := diffPayloads(a.LeftChild(), b.LeftChild(), fn)
+		if err != nil {
+			return err
+		}
+		return diffPayloads(a.RightChild(), b.RightChild(), fn)
+	}
+
+	// one of the sub-tries is empty or a compact leaf, holding at most one register, so the registers
+	// of both sub-tries are compared in order of their paths
+	beforePayloads := subtriePayloads(a)
+	afterPayloads := subtriePayloads(b)
+	for len(beforePayloads) > 0 || len(afterPayloads) > 0 {
+		var path ledger.Path
+		var before, after *ledger.Payload
+		switch {
+		case len(afterPayloads) == 0 ||
+			(len(beforePayloads) > 0 && bytes.Compare(beforePayloads[0].path[:], afterPayloads[0].path[:]) < 0):
+			path, before = beforePayloads[0].path, beforePayloads[0].payload
+			beforePayloads = beforePayloads[1:]
+		case len(beforePayloads) == 0 ||
+			bytes.Compare(afterPayloads[0].path[:], beforePayloads[0].path[:]) < 0:
+			path, after = afterPayloads[0].path, afterPayloads[0].payload
+			afterPayloads = afterPayloads[1:]
+		default:
+			path, before, after = beforePayloads[0].path, beforePayloads[0].payload, afterPayloads[0].payload
+			beforePayloads, afterPayloads = beforePayloads[1:], afterPayloads[1:]
+		}
+
+		if bytes.Equal(payloadValue(before), payloadValue(after)) {
+			continue
+		}
+		err := fn(path, before, after)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type pathPayload struct {
+	path    ledger.Path
+	payload *ledger.Payload
+}
+
+// subtriePayloads returns the payloads of the sub-trie with root n in ascending order of paths
+func subtriePayloads(n *node.Node) []pathPayload {
+	var payloads []pathPayload
+	_ = iteratePayloads(n, func(path ledger.Path, payload *ledger.Payload) error {
+		payloads = append(payloads, pathPayload{path: path, payload: payload})
+		return nil
+	})
+	return payloads
+}
+
+func payloadValue(payload *ledger.Payload) ledger.Value {
+	if payload == nil {
+		return nil
+	}
+	return payload.Value
+}
+
 // EmptyTrieRootHash returns the rootHash of an empty Trie for the specified path size [bytes]
 func EmptyTrieRootHash() ledger.RootHash {
 	return ledger.RootHash(ledger.GetDefaultHashForHeight(ledger.NodeMaxHeight))
@@ -207,6 +207,63 @@ func Test_UnallocateRegisters(t *testing.T) {
 // simple Linear congruential RNG
 // https://en.wikipedia.org/wiki/Linear_congruential_generator
 // with configuration for 16bit output used by Microsoft Visual Basic 6 and earlier
+// Test_DiffPayloads tests that the diff of two tries holds exactly the registers whose values differ,
+// in ascending order of paths.
+func Test_DiffPayloads(t *testing.T) {
+	rng := &LinearCongruentialGenerator{seed: 0}
+	paths, payloads := deduplicateWrites(sampleRandomRegisterWrites(rng, 1000))
+	before, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads)
+	require.NoError(t, err)
+
+	values := make(map[ledger.Path]ledger.Value)
+	for i, path := range paths {
+		values[path] = payloads[i].Value
+	}
+
+	// update and add random registers, and rewrite some registers with their current values
+	updatedPaths, updatedPayloads := sampleRandomRegisterWrites(rng, 100)
+	updatedPaths = append(updatedPaths, paths[:10]...)
+	updatedPayloads = append(updatedPayloads, payloads[:10]...)
+	updatedPaths, updatedPayloads = deduplicateWrites(updatedPaths, updatedPayloads)
+	after, err := trie.NewTrieWithUpdatedRegisters(before, updatedPaths, updatedPayloads)
+	require.NoError(t, err)
+
+	expected := make(map[ledger.Path]bool)
+	for i, path := range updatedPaths {
+		if !bytes.Equal(values[path], updatedPayloads[i].Value) {
+			expected[path] = true
+		}
+	}
+	require.NotEmpty(t, expected)
+
+	var previous *ledger.Path
+	err = trie.DiffPayloads(before, after, func(path ledger.Path, beforePayload, afterPayload *ledger.Payload) error {
+		require.True(t, expected[path])
+		delete(expected, path)
+
+		if value, ok := values[path]; ok {
+			require.Equal(t, value, beforePayload.Value)
+		} else {
+			require.Nil(t, beforePayload)
+		}
+		require.NotNil(t, afterPayload)
+		if previous != nil {
+			require.Equal(t, -1, bytes.Compare(previous[:], path[:]))
+		}
+		previous = &path
+		return nil
+	})
+	require.NoError(t, err)
+	require.Empty(t, expected)
+
+	// identical tries don't differ
+	err = trie.DiffPayloads(after, after, func(ledger.Path, *ledger.Payload, *ledger.Payload) error {
+		t.Fatal("identical tries should not differ")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 type LinearCongruentialGenerator struct {
 	seed uint64
 }
@@ -0,0 +1,146 @@
+package complete
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+// pendingState is a pending state of the ledger, backed by a trie which is not added to the forest.
+// The trie of each update is constructed from the trie of the previous pending state, so deriving
+// consecutive updates only hashes the registers changed by each update, and the tries share all
+// unchanged nodes with each other and with the trie of the ledger state they are derived from.
+//
+// The pending state keeps the writes of all updates it was derived by, so that it can be applied
+// as a single update of the ledger state it was derived from.
+type pendingState struct {
+	ledger   *Ledger
+	base     ledger.RootHash // root hash of the ledger state the pending state was derived from
+	trie     *trie.MTrie
+	paths    []ledger.Path
+	payloads []*ledger.Payload
+}
+
+var _ ledger.PendingState = (*pendingState)(nil)
+
+// NewPendingState returns the pending state of the given state, which must be held by the ledger.
+func (l *Ledger) NewPendingState(state ledger.State) (ledger.PendingState, error) {
+	stateTrie, err := l.forest.GetTrie(ledger.RootHash(state))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find state %x: %w", state, err)
+	}
+	return &pendingState{
+		ledger: l,
+		base:   stateTrie.RootHash(),
+		trie:   stateTrie,
+	}, nil
+}
+
+func (p *pendingState) State() ledger.State {
+	return ledger.State(p.trie.RootHash())
+}
+
+func (p *pendingState) Update(update *ledger.Update) (ledger.PendingState, error) {
+	if update.State() != p.State() {
+		return nil, fmt.Errorf("cannot apply update on state %x to pending state %x", update.State(), p.State())
+	}
+	if update.Size() == 0 {
+		return p, nil
+	}
+
+	trieUpdate, err := pathfinder.UpdateToTrieUpdate(update, p.ledger.pathFinderVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	prepared, err := p.ledger.forest.PrepareUpdate(trieUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedTrie, err := trie.NewTrieWithUpdatedRegisters(p.trie, prepared.Paths, prepared.Payloads)
+	if err != nil {
+		return nil, fmt.Errorf("constructing updated trie failed: %w", err)
+	}
+
+	// the writes are appended to copies, as pending states derived from the same state share them
+	return &pendingState{
+		ledger:   p.ledger,
+		base:     p.base,
+		trie:     updatedTrie,
+		paths:    append(p.paths[:len(p.paths):len(p.paths)], trieUpdate.Paths...),
+		payloads: append(p.payloads[:len(p.payloads):len(p.payloads)], trieUpdate.Payloads...),
+	}, nil
+}
+
+func (p *pendingState) Prove(query *ledger.Query) (ledger.Proof, error) {
+	if query.State() != p.State() {
+		return nil, fmt.Errorf("cannot prove query on state %x at pending state %x", query.State(), p.State())
+	}
+
+	paths, err := pathfinder.KeysToPaths(query.Keys(), p.ledger.pathFinderVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	batchProof, err := p.ledger.forest.TrieProofs(query.Context(), p.trie, paths)
+	if err != nil {
+		return nil, fmt.Errorf("could not get proofs: %w", err)
+	}
+
+	proofToGo := encoding.EncodeTrieBatchProof(batchProof)
+
+	if len(paths) > 0 {
+		p.ledger.metrics.ProofSize(uint32(len(proofToGo) / len(paths)))
+	}
+
+	return ledger.Proof(proofToGo), nil
+}
+
+// Apply adds the trie of the pending state to the forest. The writes of all updates the pending state
+// was derived by are recorded in the WAL as a single update of the state it was derived from, which
+// derives the same trie when the WAL is replayed, as later writes to a register overwrite earlier ones.
+func (p *pendingState) Apply() (ledger.State, error) {
+	if len(p.paths) == 0 {
+		return p.State(), nil
+	}
+
+	l := p.ledger
+	start := time.Now()
+
+	trieUpdate := &ledger.TrieUpdate{
+		RootHash: p.base,
+		Paths:    p.paths,
+		Payloads: p.payloads,
+	}
+
+	l.metrics.UpdateCount()
+	l.metrics.UpdateValuesNumber(uint64(len(trieUpdate.Paths)))
+
+	err := l.wal.RecordUpdate(trieUpdate)
+	if err != nil {
+		return ledger.State(hash.DummyHash), fmt.Errorf("error while writing LedgerWAL: %w", err)
+	}
+
+	err = l.forest.AddTrie(p.trie)
+	if err != nil {
+		return ledger.State(hash.DummyHash), fmt.Errorf("adding pending trie to forest failed: %w", err)
+	}
+
+	newRootHash := p.trie.RootHash()
+	l.publishToReplica(newRootHash)
+	l.reportMemoryUsage(newRootHash)
+
+	l.metrics.UpdateDuration(time.Since(start))
+
+	l.logger.Info().Hex("from", p.base[:]).
+		Hex("to", newRootHash[:]).
+		Int("update_size", len(trieUpdate.Paths)).
+		Msg("pending ledger state applied")
+	return ledger.State(newRootHash), nil
+}
@@ -0,0 +1,192 @@
+package pruner
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// Pruner removes the tries of historical states from the forest of a ledger, once their blocks
+// are more than a configurable number of blocks below the latest sealed block. Tries share the
+// subtries which are unchanged between them, so removing a trie only releases the nodes which are
+// not reachable from any retained trie, which are then reclaimed by the garbage collector.
+//
+// The pruner only removes tries of states it tracked as end states of executed blocks. Pinned
+// tries are never removed, they are pruned once they are unpinned.
+type Pruner struct {
+	log     zerolog.Logger
+	forest  *mtrie.Forest
+	retain  uint64 // number of blocks below the sealed block whose states are retained
+	metrics module.LedgerPrunerMetrics
+
+	mu           sync.Mutex
+	heights      map[ledger.RootHash]uint64 // highest height of the blocks with each tracked end state
+	sealedHeight uint64
+}
+
+// New creates a pruner for the given forest, which retains the states of the blocks up to
+// retainedBlocks below the latest sealed block.
+func New(log zerolog.Logger, forest *mtrie.Forest, retainedBlocks uint64, metrics module.LedgerPrunerMetrics) *Pruner {
+	return &Pruner{
+		log:     log.With().Str("subcomponent", "pruner").Logger(),
+		forest:  forest,
+		retain:  retainedBlocks,
+		metrics: metrics,
+		heights: make(map[ledger.RootHash]uint64),
+	}
+}
+
+// BlockExecuted tracks the end state of the executed block, so that it can be pruned later on.
+func (p *Pruner) BlockExecuted(header *flow.Header, endState flow.StateCommitment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// empty blocks don't change the state of their parent, so a state is retained
+	// as long as the highest block with this end state
+	rootHash := ledger.RootHash(endState)
+	if height, ok := p.heights[rootHash]; !ok || height < header.Height {
+		p.heights[rootHash] = header.Height
+	}
+}
+
+// BlockSealed prunes the tracked states of all blocks more than the retained number of blocks
+// below the sealed block.
+func (p *Pruner) BlockSealed(header *flow.Header) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if header.Height <= p.sealedHeight {
+		return nil
+	}
+	p.sealedHeight = header.Height
+
+	if header.Height <= p.retain {
+		return nil
+	}
+	return p.prune(header.Height - p.retain)
+}
+
+// prune removes the tries of the tracked states below the given height.
+// Caller must hold the lock.
+func (p *Pruner) prune(height uint64) error {
+	tries, err := p.forest.GetTries()
+	if err != nil {
+		return fmt.Errorf("cannot get tries of forest: %w", err)
+	}
+
+	var pruned, retained []*trie.MTrie
+	for _, t := range tries {
+		rootHash := t.RootHash()
+		stateHeight, tracked := p.heights[rootHash]
+		if !tracked || stateHeight >= height || p.forest.IsPinned(rootHash) {
+			retained = append(retained, t)
+			continue
+		}
+		pruned = append(pruned, t)
+	}
+
+	// tries are ordered from the least recently used, the most recently used one is always retained
+	if len(retained) == 0 && len(pruned) > 0 {
+		retained = pruned[len(pruned)-1:]
+		pruned = pruned[:len(pruned)-1]
+	}
+
+	if len(pruned) > 0 {
+		nodes, bytes := reclaimed(pruned, retained)
+		for _, t := range pruned {
+			p.forest.RemoveTrie(t.RootHash())
+		}
+
+		p.metrics.TriesPruned(len(pruned), nodes, bytes)
+		p.log.Debug().
+			Uint64("height", height).
+			Int("tries", len(pruned)).
+			Uint64("nodes", nodes).
+			Uint64("bytes", bytes).
+			Msg("pruned tries below height")
+	}
+
+	// stop tracking the pruned states, and the states which were evicted from the forest
+	kept := make(map[ledger.RootHash]struct{}, len(retained))
+	for _, t := range retained {
+		kept[t.RootHash()] = struct{}{}
+	}
+	for rootHash, stateHeight := range p.heights {
+		if _, ok := kept[rootHash]; !ok && stateHeight < height {
+			delete(p.heights, rootHash)
+		}
+	}
+
+	return nil
+}
+
+// reclaimed returns the number and approximate size in bytes of the nodes which are only
+// reachable from the pruned tries. A node is at the same position in all tries containing it,
+// so the pruned tries are walked in parallel with the retained tries, and the walk stops at
+// the nodes shared with a retained trie instead of visiting all nodes of the retained tries.
+func reclaimed(pruned []*trie.MTrie, retained []*trie.MTrie) (nodes uint64, bytes uint64) {
+	visited := make(map[*node.Node]struct{})
+
+	var walk func(n *node.Node, others []*node.Node)
+	walk = func(n *node.Node, others []*node.Node) {
+		if n == nil {
+			return
+		}
+		if _, ok := visited[n]; ok {
+			return
+		}
+		for _, other := range others {
+			if other == n {
+				return
+			}
+		}
+		visited[n] = struct{}{}
+
+		nodes++
+		bytes += trie.NodeMemorySize(n)
+
+		walk(n.LeftChild(), children(others, (*node.Node).LeftChild))
+		walk(n.RightChild(), children(others, (*node.Node).RightChild))
+	}
+
+	roots := make([]*node.Node, 0, len(retained))
+	for _, t := range retained {
+		roots = append(roots, t.RootNode())
+	}
+	roots = children(roots, func(n *node.Node) *node.Node { return n })
+
+	for _, t := range pruned {
+		walk(t.RootNode(), roots)
+	}
+
+	return nodes, bytes
+}
+
+// children returns the distinct non-nil children of the given nodes
+func children(nodes []*node.Node, child func(*node.Node) *node.Node) []*node.Node {
+	result := make([]*node.Node, 0, len(nodes))
+	seen := make(map[*node.Node]struct{}, len(nodes))
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		c := child(n)
+		if c == nil {
+			continue
+		}
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+		result = append(result, c)
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package pruner_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/pruner"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	modulemock "github.com/onflow/flow-go/module/mock"
+)
+
+func TestPruner(t *testing.T) {
+
+	forest, err := mtrie.NewForest(100, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	pathA := utils.PathByUint8(0)
+	pathB := utils.PathByUint8(128)
+
+	// the tries share the leaf of path A, only the leaf of path B changes
+	t1, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), []ledger.Path{pathA, pathB}, []ledger.Payload{*utils.LightPayload8('A', 1), *utils.LightPayload8('B', 1)})
+	require.NoError(t, err)
+	t2, err := trie.NewTrieWithUpdatedRegisters(t1, []ledger.Path{pathB}, []ledger.Payload{*utils.LightPayload8('B', 2)})
+	require.NoError(t, err)
+	t3, err := trie.NewTrieWithUpdatedRegisters(t2, []ledger.Path{pathB}, []ledger.Payload{*utils.LightPayload8('B', 3)})
+	require.NoError(t, err)
+	require.NoError(t, forest.AddTries([]*trie.MTrie{t1, t2, t3}))
+
+	prunerMetrics := &modulemock.LedgerPrunerMetrics{}
+	p := pruner.New(zerolog.Nop(), forest, 1, prunerMetrics)
+
+	for height, tr := range []*trie.MTrie{t1, t2, t3} {
+		p.BlockExecuted(&flow.Header{Height: uint64(height + 1)}, flow.StateCommitment(tr.RootHash()))
+	}
+
+	// sealing block 1 retains all states
+	require.NoError(t, p.BlockSealed(&flow.Header{Height: 1}))
+	require.Equal(t, 4, forest.Size())
+
+	// sealing block 3 prunes the state of block 1, reclaiming its root and leaf of path B
+	prunerMetrics.On("TriesPruned", 1, uint64(2), mock.Anything).Once()
+	require.NoError(t, p.BlockSealed(&flow.Header{Height: 3}))
+	_, err = forest.GetTrie(t1.RootHash())
+	require.Error(t, err)
+	_, err = forest.GetTrie(t2.RootHash())
+	require.NoError(t, err)
+	require.Equal(t, 3, forest.Size())
+
+	// pinned states are retained
+	require.NoError(t, forest.Pin(t2.RootHash()))
+	prunerMetrics.On("TriesPruned", 1, uint64(2), mock.Anything).Once()
+	require.NoError(t, p.BlockSealed(&flow.Header{Height: 5}))
+	_, err = forest.GetTrie(t2.RootHash())
+	require.NoError(t, err)
+	_, err = forest.GetTrie(t3.RootHash())
+	require.Error(t, err)
+
+	// and pruned once unpinned, together with the leaf of path A
+	require.NoError(t, forest.Unpin(t2.RootHash()))
+	prunerMetrics.On("TriesPruned", 1, uint64(3), mock.Anything).Once()
+	require.NoError(t, p.BlockSealed(&flow.Header{Height: 6}))
+	_, err = forest.GetTrie(t2.RootHash())
+	require.Error(t, err)
+
+	// the empty trie was never tracked, so it's retained
+	require.Equal(t, 1, forest.Size())
+
+	prunerMetrics.AssertExpectations(t)
+}
@@ -0,0 +1,249 @@
+package complete
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+// ErrReadOnly is returned when updating a read-only ledger.
+var ErrReadOnly = fmt.Errorf("ledger is read-only")
+
+// Replica is a read-only replica of the forest of a ledger. It serves queries from its own
+// forest, so that query load doesn't contend with block execution on the forest of the ledger.
+//
+// The replica is updated asynchronously: the ledger publishes every new trie to the replica,
+// which adds it to its forest in the background. As tries are immutable, the replica shares
+// their nodes with the ledger instead of copying them. A query for a state which is published
+// but not added yet waits for it as long as the state is younger than the staleness bound of
+// the replica. Queries for states the replica doesn't hold within the bound, e.g. because they
+// were evicted from the replica, fall back to the ledger.
+type Replica struct {
+	unit    *engine.Unit
+	log     zerolog.Logger
+	primary *Ledger
+	forest  *mtrie.Forest
+	maxLag  time.Duration
+	metrics module.LedgerReplicaMetrics
+
+	mu      sync.Mutex
+	pending map[ledger.RootHash]*publishedTrie // published tries not added yet
+	queue   []*publishedTrie                   // published tries in order of publication
+	notify  chan struct{}
+}
+
+// publishedTrie is a trie published by the ledger to the replica.
+type publishedTrie struct {
+	trie      *trie.MTrie
+	published time.Time
+	added     chan struct{} // closed once the trie is added to the replica
+}
+
+// NewReplica creates a read-only replica of the ledger, holding up to capacity tries. Queries wait
+// for published states for at most maxLag, before falling back to the ledger. The replica holds
+// the current tries of the ledger initially, and is updated with all tries added to the ledger
+// afterwards. A ledger has at most one replica.
+func (l *Ledger) NewReplica(capacity int, maxLag time.Duration, replicaMetrics module.LedgerReplicaMetrics) (*Replica, error) {
+	forest, err := mtrie.NewForest(capacity, &metrics.NoopCollector{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create replica forest: %w", err)
+	}
+	forest.SetReadLimits(l.readLimits)
+
+	tries, err := l.forest.GetTries()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get tries of ledger: %w", err)
+	}
+	// tries are ordered from the least recently used, so the most recent ones are retained
+	err = forest.AddTries(tries)
+	if err != nil {
+		return nil, fmt.Errorf("cannot add tries to replica: %w", err)
+	}
+
+	r := &Replica{
+		unit:    engine.NewUnit(),
+		log:     l.logger.With().Str("subcomponent", "replica").Logger(),
+		primary: l,
+		forest:  forest,
+		maxLag:  maxLag,
+		metrics: replicaMetrics,
+		pending: make(map[ledger.RootHash]*publishedTrie),
+		notify:  make(chan struct{}, 1),
+	}
+
+	l.replicaMu.Lock()
+	defer l.replicaMu.Unlock()
+	if l.replica != nil {
+		return nil, fmt.Errorf("ledger already has a replica")
+	}
+	l.replica = r
+
+	return r, nil
+}
+
+// Ready starts adding the tries published by the ledger to the replica.
+func (r *Replica) Ready() <-chan struct{} {
+	r.unit.Launch(r.loop)
+	return r.unit.Ready()
+}
+
+// Done stops updating the replica.
+func (r *Replica) Done() <-chan struct{} {
+	return r.unit.Done()
+}
+
+// publish queues the trie to be added to the replica.
+func (r *Replica) publish(t *trie.MTrie) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	published := &publishedTrie{
+		trie:      t,
+		published: time.Now(),
+		added:     make(chan struct{}),
+	}
+	r.pending[t.RootHash()] = published
+	r.queue = append(r.queue, published)
+
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Replica) loop() {
+	for {
+		select {
+		case <-r.unit.Quit():
+			return
+		case <-r.notify:
+		}
+
+		r.mu.Lock()
+		queue := r.queue
+		r.queue = nil
+		r.mu.Unlock()
+
+		for _, published := range queue {
+			err := r.forest.AddTrie(published.trie)
+			if err != nil {
+				r.log.Error().Err(err).Msg("could not add trie to replica")
+			}
+
+			r.mu.Lock()
+			delete(r.pending, published.trie.RootHash())
+			r.reportLag()
+			r.mu.Unlock()
+
+			close(published.added)
+		}
+	}
+}
+
+// reportLag reports the number of pending tries and the age of the oldest one.
+// Caller must hold the lock.
+func (r *Replica) reportLag() {
+	if len(r.pending) == 0 {
+		r.metrics.ReplicaLag(0, 0)
+		return
+	}
+	var oldest time.Time
+	for _, published := range r.pending {
+		if oldest.IsZero() || published.published.Before(oldest) {
+			oldest = published.published
+		}
+	}
+	r.metrics.ReplicaLag(len(r.pending), time.Since(oldest))
+}
+
+// holds returns whether the replica holds the trie with the given root hash, waiting for it if it
+// is published but not added yet, as long as it was published within the staleness bound.
+func (r *Replica) holds(rootHash ledger.RootHash) bool {
+	_, err := r.forest.GetTrie(rootHash)
+	if err == nil {
+		return true
+	}
+
+	r.mu.Lock()
+	published, ok := r.pending[rootHash]
+	r.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	wait := r.maxLag - time.Since(published.published)
+	if wait <= 0 {
+		return false
+	}
+	select {
+	case <-published.added:
+		_, err := r.forest.GetTrie(rootHash)
+		return err == nil
+	case <-time.After(wait):
+		return false
+	}
+}
+
+// InitialState returns the state of an empty ledger
+func (r *Replica) InitialState() ledger.State {
+	return r.primary.InitialState()
+}
+
+// Get reads the values of the given keys at the given state from the replica, or from
+// the ledger if the replica doesn't hold the state within its staleness bound.
+func (r *Replica) Get(query *ledger.Query) (values []ledger.Value, err error) {
+	if !r.holds(ledger.RootHash(query.State())) {
+		r.metrics.ReplicaQuery(false)
+		return r.primary.Get(query)
+	}
+	r.metrics.ReplicaQuery(true)
+
+	paths, err := pathfinder.KeysToPaths(query.Keys(), r.primary.pathFinderVersion)
+	if err != nil {
+		return nil, err
+	}
+	trieRead := &ledger.TrieRead{RootHash: ledger.RootHash(query.State()), Paths: paths}
+	payloads, err := r.forest.Read(query.Context(), trieRead)
+	if err != nil {
+		return nil, err
+	}
+	return pathfinder.PayloadsToValues(payloads)
+}
+
+// Prove provides proofs for the query from the replica, or from the ledger if the replica
+// doesn't hold the state within its staleness bound.
+func (r *Replica) Prove(query *ledger.Query) (proof ledger.Proof, err error) {
+	if !r.holds(ledger.RootHash(query.State())) {
+		r.metrics.ReplicaQuery(false)
+		return r.primary.Prove(query)
+	}
+	r.metrics.ReplicaQuery(true)
+
+	paths, err := pathfinder.KeysToPaths(query.Keys(), r.primary.pathFinderVersion)
+	if err != nil {
+		return nil, err
+	}
+	trieRead := &ledger.TrieRead{RootHash: ledger.RootHash(query.State()), Paths: paths}
+	batchProof, err := r.forest.Proofs(query.Context(), trieRead)
+	if err != nil {
+		return nil, fmt.Errorf("could not get proofs: %w", err)
+	}
+	return ledger.Proof(encoding.EncodeTrieBatchProof(batchProof)), nil
+}
+
+// Set always fails, as the replica is read-only.
+func (r *Replica) Set(*ledger.Update) (ledger.State, error) {
+	return ledger.State(hash.DummyHash), ErrReadOnly
+}
@@ -0,0 +1,143 @@
+package complete_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// replicaQueryCounter counts the queries served by the replica and by the ledger
+type replicaQueryCounter struct {
+	metrics.NoopCollector
+	mu        sync.Mutex
+	byReplica int
+	byLedger  int
+}
+
+func (c *replicaQueryCounter) ReplicaQuery(servedByReplica bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if servedByReplica {
+		c.byReplica++
+		return
+	}
+	c.byLedger++
+}
+
+func (c *replicaQueryCounter) counts() (byReplica int, byLedger int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.byReplica, c.byLedger
+}
+
+func TestReplica(t *testing.T) {
+
+	newLedger := func(t *testing.T) *complete.Ledger {
+		led, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		return led
+	}
+
+	update := func(t *testing.T, led *complete.Ledger) (*ledger.Update, ledger.State) {
+		u := utils.UpdateFixture()
+		u.SetState(led.InitialState())
+		newState, err := led.Set(u)
+		require.NoError(t, err)
+		return u, newState
+	}
+
+	t.Run("serves published states", func(t *testing.T) {
+		led := newLedger(t)
+		counter := &replicaQueryCounter{}
+		replica, err := led.NewReplica(10, time.Minute, counter)
+		require.NoError(t, err)
+		unittest.AssertClosesBefore(t, replica.Ready(), time.Second)
+		defer func() { unittest.AssertClosesBefore(t, replica.Done(), time.Second) }()
+
+		u, newState := update(t, led)
+
+		q, err := ledger.NewQuery(newState, u.Keys())
+		require.NoError(t, err)
+		values, err := replica.Get(q)
+		require.NoError(t, err)
+		assert.Equal(t, u.Values(), values)
+
+		proof, err := replica.Prove(q)
+		require.NoError(t, err)
+		expected, err := led.Prove(q)
+		require.NoError(t, err)
+		assert.Equal(t, expected, proof)
+
+		byReplica, byLedger := counter.counts()
+		assert.Equal(t, 2, byReplica)
+		assert.Equal(t, 0, byLedger)
+	})
+
+	t.Run("holds states of ledger when created", func(t *testing.T) {
+		led := newLedger(t)
+		u, newState := update(t, led)
+
+		counter := &replicaQueryCounter{}
+		replica, err := led.NewReplica(10, 0, counter)
+		require.NoError(t, err)
+
+		q, err := ledger.NewQuery(newState, u.Keys())
+		require.NoError(t, err)
+		values, err := replica.Get(q)
+		require.NoError(t, err)
+		assert.Equal(t, u.Values(), values)
+
+		byReplica, _ := counter.counts()
+		assert.Equal(t, 1, byReplica)
+	})
+
+	t.Run("falls back to ledger beyond staleness bound", func(t *testing.T) {
+		led := newLedger(t)
+		counter := &replicaQueryCounter{}
+		// the replica is not started, so published states are never added
+		replica, err := led.NewReplica(10, 10*time.Millisecond, counter)
+		require.NoError(t, err)
+
+		u, newState := update(t, led)
+
+		q, err := ledger.NewQuery(newState, u.Keys())
+		require.NoError(t, err)
+		values, err := replica.Get(q)
+		require.NoError(t, err)
+		assert.Equal(t, u.Values(), values)
+
+		byReplica, byLedger := counter.counts()
+		assert.Equal(t, 0, byReplica)
+		assert.Equal(t, 1, byLedger)
+	})
+
+	t.Run("is read-only", func(t *testing.T) {
+		led := newLedger(t)
+		replica, err := led.NewReplica(10, 0, &metrics.NoopCollector{})
+		require.NoError(t, err)
+
+		u := utils.UpdateFixture()
+		u.SetState(led.InitialState())
+		_, err = replica.Set(u)
+		require.ErrorIs(t, err, complete.ErrReadOnly)
+	})
+
+	t.Run("at most one replica", func(t *testing.T) {
+		led := newLedger(t)
+		_, err := led.NewReplica(10, 0, &metrics.NoopCollector{})
+		require.NoError(t, err)
+		_, err = led.NewReplica(10, 0, &metrics.NoopCollector{})
+		require.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package wal
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/hash"
 	"github.com/onflow/flow-go/ledger/complete/mtrie"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
@@ -30,6 +32,20 @@ const VersionV1 uint16 = 0x01
 // Version 3 contains a file checksum for detecting corrupted checkpoint files.
 const VersionV3 uint16 = 0x03
 
+// VersionIncremental is the version of incremental checkpoints, which only contain the nodes
+// created since a base checkpoint. They contain a file checksum, as version 3 does.
+const VersionIncremental uint16 = 0x04
+
+// VersionV5 contains, after the tries, an index of the root hashes of the tries, which allows to
+// load a single trie without deserializing the nodes of the other tries. It also contains a file
+// checksum, as version 3 does.
+const VersionV5 uint16 = 0x05
+
+// rootIndexEntrySize is the size of an entry of the root hash index of version 5 checkpoints:
+// the root hash of the trie, the index of its root node, and the offset of the end of its root
+// node in the file, i.e. the number of bytes to read to get all the nodes of the trie.
+const rootIndexEntrySize = 32 + 8 + 8
+
 type Checkpointer struct {
 	dir            string
 	wal            *DiskWAL
@@ -201,6 +217,131 @@ func (c *Checkpointer) Checkpoint(to int, targetWriter func() (io.WriteCloser, e
 	return err
 }
 
+// IncrementalCheckpoint creates a new checkpoint stopping at given segment, which only contains the
+// nodes created since the latest checkpoint, and references the unchanged subtries of the latest
+// checkpoint by hash. Loading the checkpoint requires the latest checkpoint.
+func (c *Checkpointer) IncrementalCheckpoint(to int, targetWriter func() (io.WriteCloser, error)) error {
+
+	_, notCheckpointedTo, err := c.NotCheckpointedSegments()
+	if err != nil {
+		return fmt.Errorf("cannot get not checkpointed segments: %w", err)
+	}
+
+	latestCheckpoint, err := c.LatestCheckpoint()
+	if err != nil {
+		return fmt.Errorf("cannot get latest checkpoint: %w", err)
+	}
+
+	if latestCheckpoint == to {
+		return nil //nothing to do
+	}
+
+	if latestCheckpoint == -1 {
+		return fmt.Errorf("no checkpoint to base incremental checkpoint to %d on", to)
+	}
+
+	if notCheckpointedTo < to {
+		return fmt.Errorf("no segments to checkpoint to %d, latests not checkpointed segment: %d", to, notCheckpointedTo)
+	}
+
+	forest, err := mtrie.NewForest(c.forestCapacity, &metrics.NoopCollector{}, func(evictedTrie *trie.MTrie) error {
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create Forest: %w", err)
+	}
+
+	forestSequencing, err := c.LoadCheckpoint(latestCheckpoint)
+	if err != nil {
+		return fmt.Errorf("cannot load base checkpoint %d: %w", latestCheckpoint, err)
+	}
+	baseTries, err := flattener.RebuildTries(forestSequencing)
+	if err != nil {
+		return fmt.Errorf("cannot rebuild tries of base checkpoint %d: %w", latestCheckpoint, err)
+	}
+	err = forest.AddTries(baseTries)
+	if err != nil {
+		return fmt.Errorf("cannot add tries of base checkpoint %d: %w", latestCheckpoint, err)
+	}
+
+	err = c.wal.replay(latestCheckpoint+1, to,
+		func(forestSequencing *flattener.FlattenedForest) error {
+			return nil
+		},
+		func(update *ledger.TrieUpdate) error {
+			_, err := forest.Update(update)
+			return err
+		}, func(rootHash ledger.RootHash) error {
+			return nil
+		}, false)
+
+	if err != nil {
+		return fmt.Errorf("cannot replay WAL: %w", err)
+	}
+
+	increment, err := flattener.FlattenForestIncrement(forest, baseTries)
+	if err != nil {
+		return fmt.Errorf("cannot get storables: %w", err)
+	}
+
+	writer, err := targetWriter()
+	if err != nil {
+		return fmt.Errorf("cannot generate writer: %w", err)
+	}
+	defer writer.Close()
+
+	err = StoreIncrementalCheckpoint(latestCheckpoint, increment, writer)
+
+	return err
+}
+
+// CheckpointBase returns the number of the checkpoint the given checkpoint is based on,
+// or -1 if it is a full checkpoint.
+func (c *Checkpointer) CheckpointBase(checkpoint int) (int, error) {
+	filepath := path.Join(c.dir, NumberToFilename(checkpoint))
+	file, err := os.Open(filepath)
+	if err != nil {
+		return -1, fmt.Errorf("cannot open checkpoint file %s: %w", filepath, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, 4+8)
+	_, err = io.ReadFull(file, header)
+	if err != nil {
+		return -1, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+
+	magicBytes, pos := readUint16(header, 0)
+	version, pos := readUint16(header, pos)
+	if magicBytes != MagicBytes {
+		return -1, fmt.Errorf("unknown file format. Magic constant %x does not match expected %x", magicBytes, MagicBytes)
+	}
+	if version != VersionIncremental {
+		return -1, nil
+	}
+	base, _ := readUint64(header, pos)
+	return int(base), nil
+}
+
+// IncrementalChainLength returns the number of consecutive incremental checkpoints ending at
+// the given checkpoint, i.e. 0 for a full checkpoint.
+func (c *Checkpointer) IncrementalChainLength(checkpoint int) (int, error) {
+	length := 0
+	for {
+		base, err := c.CheckpointBase(checkpoint)
+		if err != nil {
+			return 0, err
+		}
+		if base == -1 {
+			return length, nil
+		}
+		length++
+		checkpoint = base
+	}
+}
+
 func NumberToFilenamePart(n int) string {
 	return fmt.Sprintf("%08d", n)
 }
@@ -240,7 +381,11 @@ func CreateCheckpointWriterForFile(dir, filename string) (io.WriteCloser, error)
 	}, nil
 }
 
-// StoreCheckpoint writes the given checkpoint to disk, and also append with a CRC32 file checksum for integrity check.
+// StoreCheckpoint writes the given checkpoint to disk, followed by an index of the root hashes of
+// its tries, and also append with a CRC32 file checksum for integrity check.
+// The index is written after the tries, and its offset is stored right before the checksum:
+//   * for each trie: root hash (32 bytes), root node index (8 bytes), end offset of the root node (8 bytes)
+//   * offset of the index (8 bytes)
 func StoreCheckpoint(forestSequencing *flattener.FlattenedForest, writer io.Writer) error {
 	storableNodes := forestSequencing.Nodes
 	storableTries := forestSequencing.Tries
@@ -249,7 +394,90 @@ func StoreCheckpoint(forestSequencing *flattener.FlattenedForest, writer io.Writ
 	crc32Writer := NewCRC32Writer(writer)
 
 	pos := writeUint16(header, 0, MagicBytes)
-	pos = writeUint16(header, pos, VersionV3)
+	pos = writeUint16(header, pos, VersionV5)
+	pos = writeUint64(header, pos, uint64(len(storableNodes)-1)) // -1 to account for 0 node meaning nil
+	writeUint16(header, pos, uint16(len(storableTries)))
+
+	_, err := crc32Writer.Write(header)
+	if err != nil {
+		return fmt.Errorf("cannot write checkpoint header: %w", err)
+	}
+
+	// remember where the root nodes end, for the index
+	rootEnds := make(map[uint64]uint64, len(storableTries))
+	for _, storableTrie := range storableTries {
+		rootEnds[storableTrie.RootIndex] = 0
+	}
+	offset := uint64(len(header))
+	rootEnds[0] = offset // tries with a nil root need no nodes
+
+	// 0 element = nil, we don't need to store it
+	for i := 1; i < len(storableNodes); i++ {
+		bytes := flattener.EncodeStorableNode(storableNodes[i])
+		_, err = crc32Writer.Write(bytes)
+		if err != nil {
+			return fmt.Errorf("error while writing node date: %w", err)
+		}
+		offset += uint64(len(bytes))
+		if _, ok := rootEnds[uint64(i)]; ok {
+			rootEnds[uint64(i)] = offset
+		}
+	}
+
+	for _, storableTrie := range storableTries {
+		bytes := flattener.EncodeStorableTrie(storableTrie)
+		_, err = crc32Writer.Write(bytes)
+		if err != nil {
+			return fmt.Errorf("error while writing trie date: %w", err)
+		}
+		offset += uint64(len(bytes))
+	}
+
+	entry := make([]byte, rootIndexEntrySize)
+	for _, storableTrie := range storableTries {
+		var rootHash ledger.RootHash
+		copy(rootHash[:], storableTrie.RootHash)
+		pos := copy(entry, rootHash[:])
+		pos = writeUint64(entry, pos, storableTrie.RootIndex)
+		writeUint64(entry, pos, rootEnds[storableTrie.RootIndex])
+		_, err = crc32Writer.Write(entry)
+		if err != nil {
+			return fmt.Errorf("error while writing root hash index: %w", err)
+		}
+	}
+
+	footer := make([]byte, 8)
+	writeUint64(footer, 0, offset)
+	_, err = crc32Writer.Write(footer)
+	if err != nil {
+		return fmt.Errorf("error while writing root hash index offset: %w", err)
+	}
+
+	// add CRC32 sum
+	crc32buf := make([]byte, 4)
+	writeUint32(crc32buf, 0, crc32Writer.Crc32())
+
+	_, err = writer.Write(crc32buf)
+	if err != nil {
+		return fmt.Errorf("cannot write crc32: %w", err)
+	}
+
+	return nil
+}
+
+// StoreIncrementalCheckpoint writes the given incremental checkpoint based on the given checkpoint
+// to disk, and also append with a CRC32 file checksum for integrity check.
+func StoreIncrementalCheckpoint(base int, increment *flattener.FlattenedForestIncrement, writer io.Writer) error {
+	storableNodes := increment.Nodes
+	storableTries := increment.Tries
+	header := make([]byte, 4+8+8+8+2)
+
+	crc32Writer := NewCRC32Writer(writer)
+
+	pos := writeUint16(header, 0, MagicBytes)
+	pos = writeUint16(header, pos, VersionIncremental)
+	pos = writeUint64(header, pos, uint64(base))
+	pos = writeUint64(header, pos, uint64(len(increment.Refs)))
 	pos = writeUint64(header, pos, uint64(len(storableNodes)-1)) // -1 to account for 0 node meaning nil
 	writeUint16(header, pos, uint16(len(storableTries)))
 
@@ -258,6 +486,13 @@ func StoreCheckpoint(forestSequencing *flattener.FlattenedForest, writer io.Writ
 		return fmt.Errorf("cannot write checkpoint header: %w", err)
 	}
 
+	for _, ref := range increment.Refs {
+		_, err = crc32Writer.Write(ref[:])
+		if err != nil {
+			return fmt.Errorf("error while writing reference data: %w", err)
+		}
+	}
+
 	// 0 element = nil, we don't need to store it
 	for i := 1; i < len(storableNodes); i++ {
 		bytes := flattener.EncodeStorableNode(storableNodes[i])
@@ -292,6 +527,13 @@ func (c *Checkpointer) LoadCheckpoint(checkpoint int) (*flattener.FlattenedFores
 	return LoadCheckpoint(filepath)
 }
 
+// LoadCheckpointTrie loads the trie with the given root hash from the given checkpoint. Only
+// the nodes of the trie are returned.
+func (c *Checkpointer) LoadCheckpointTrie(checkpoint int, rootHash ledger.RootHash) (*flattener.FlattenedForest, error) {
+	filepath := path.Join(c.dir, NumberToFilename(checkpoint))
+	return LoadCheckpointTrie(filepath, rootHash)
+}
+
 func (c *Checkpointer) LoadRootCheckpoint() (*flattener.FlattenedForest, error) {
 	filepath := path.Join(c.dir, bootstrap.FilenameWALRootCheckpoint)
 	return LoadCheckpoint(filepath)
@@ -311,6 +553,8 @@ func (c *Checkpointer) RemoveCheckpoint(checkpoint int) error {
 	return os.Remove(path.Join(c.dir, NumberToFilename(checkpoint)))
 }
 
+// LoadCheckpoint loads the checkpoint from the given file. Incremental checkpoints are resolved
+// against the checkpoints they are based on, which must be in the same directory.
 func LoadCheckpoint(filepath string) (*flattener.FlattenedForest, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
@@ -320,7 +564,295 @@ func LoadCheckpoint(filepath string) (*flattener.FlattenedForest, error) {
 		_ = file.Close()
 	}()
 
-	return ReadCheckpoint(file)
+	reader := bufio.NewReader(file)
+	header, err := reader.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+	version, _ := readUint16(header, 2)
+	if version != VersionIncremental {
+		return ReadCheckpoint(reader)
+	}
+
+	base, increment, err := ReadIncrementalCheckpoint(reader)
+	if err != nil {
+		return nil, err
+	}
+	baseForest, err := LoadCheckpoint(path.Join(path.Dir(filepath), NumberToFilename(base)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot load base checkpoint %d: %w", base, err)
+	}
+	return flattener.ApplyIncrement(baseForest, increment)
+}
+
+// LoadCheckpointTrie loads the trie with the given root hash from the given checkpoint file,
+// e.g. to only load the latest trie at startup. Only the nodes of the trie are returned.
+// For version 5 checkpoints, the nodes listed after the root node of the trie are not
+// deserialized. Checkpoints of older versions, and incremental checkpoints, are loaded entirely.
+func LoadCheckpointTrie(filepath string, rootHash ledger.RootHash) (*flattener.FlattenedForest, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open checkpoint file %s: %w", filepath, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, 4)
+	_, err = io.ReadFull(file, header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+	version, _ := readUint16(header, 2)
+	if version == VersionV5 {
+		return ReadCheckpointTrie(file, rootHash)
+	}
+
+	forestSequencing, err := LoadCheckpoint(filepath)
+	if err != nil {
+		return nil, err
+	}
+	for _, storableTrie := range forestSequencing.Tries {
+		if bytes.Equal(storableTrie.RootHash, rootHash[:]) {
+			return singleTrieForest(forestSequencing.Nodes, storableTrie)
+		}
+	}
+	return nil, fmt.Errorf("checkpoint contains no trie with root hash %x", rootHash)
+}
+
+// ReadCheckpointTrie reads the trie with the given root hash from a version 5 checkpoint, using
+// its root hash index to only read the nodes listed up to the root node of the trie. The rest of
+// the file is still read to verify the file checksum, but it is not deserialized.
+func ReadCheckpointTrie(r io.ReadSeeker, rootHash ledger.RootHash) (*flattener.FlattenedForest, error) {
+
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get checkpoint size: %w", err)
+	}
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("cannot seek to checkpoint header: %w", err)
+	}
+
+	header := make([]byte, 4+8+2)
+	_, err = io.ReadFull(r, header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+
+	magicBytes, pos := readUint16(header, 0)
+	version, pos := readUint16(header, pos)
+	nodesCount, pos := readUint64(header, pos)
+	triesCount, _ := readUint16(header, pos)
+
+	if magicBytes != MagicBytes {
+		return nil, fmt.Errorf("unknown file format. Magic constant %x does not match expected %x", magicBytes, MagicBytes)
+	}
+	if version != VersionV5 {
+		return nil, fmt.Errorf("unsupported file version %x ", version)
+	}
+
+	indexSize := int64(triesCount)*rootIndexEntrySize + 8
+	if size < int64(len(header))+indexSize+4 {
+		return nil, fmt.Errorf("checkpoint file too short (%d bytes) for its root hash index", size)
+	}
+
+	footer := make([]byte, 8)
+	_, err = r.Seek(size-4-8, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("cannot seek to root hash index offset: %w", err)
+	}
+	_, err = io.ReadFull(r, footer)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read root hash index offset: %w", err)
+	}
+	indexOffset, _ := readUint64(footer, 0)
+	if indexOffset != uint64(size-4-indexSize) {
+		return nil, fmt.Errorf("invalid root hash index offset %d", indexOffset)
+	}
+
+	_, err = r.Seek(int64(indexOffset), io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("cannot seek to root hash index: %w", err)
+	}
+	index := make([]byte, int(triesCount)*rootIndexEntrySize)
+	_, err = io.ReadFull(r, index)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read root hash index: %w", err)
+	}
+
+	var storableTrie *flattener.StorableTrie
+	var rootEnd uint64
+	for pos := 0; pos < len(index); pos += rootIndexEntrySize {
+		if !bytes.Equal(index[pos:pos+32], rootHash[:]) {
+			continue
+		}
+		rootIndex, next := readUint64(index, pos+32)
+		rootEnd, _ = readUint64(index, next)
+		storableTrie = &flattener.StorableTrie{
+			RootIndex: rootIndex,
+			RootHash:  append([]byte(nil), rootHash[:]...),
+		}
+		break
+	}
+	if storableTrie == nil {
+		return nil, fmt.Errorf("checkpoint contains no trie with root hash %x", rootHash)
+	}
+	if storableTrie.RootIndex > nodesCount || rootEnd < uint64(len(header)) || rootEnd > indexOffset {
+		return nil, fmt.Errorf("invalid root hash index entry for trie %x", rootHash)
+	}
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("cannot seek to checkpoint start: %w", err)
+	}
+
+	bufReader := bufio.NewReader(r)
+	crcReader := NewCRC32Reader(bufReader)
+	var reader io.Reader = crcReader
+
+	// the header is read again, as it is part of the checksum
+	_, err = io.ReadFull(reader, header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+
+	nodes := make([]*flattener.StorableNode, storableTrie.RootIndex+1) //+1 for 0 index meaning nil
+	for i := uint64(1); i <= storableTrie.RootIndex; i++ {
+		storableNode, err := flattener.ReadStorableNode(reader)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read storable node %d: %w", i, err)
+		}
+		nodes[i] = storableNode
+	}
+
+	// the rest of the file is only needed for the checksum
+	_, err = io.CopyN(ioutil.Discard, reader, size-4-int64(rootEnd))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read remaining checkpoint data: %w", err)
+	}
+
+	crc32buf := make([]byte, 4)
+	_, err = io.ReadFull(bufReader, crc32buf)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading CRC32 checksum: %w", err)
+	}
+	readCrc32, _ := readUint32(crc32buf, 0)
+
+	calculatedCrc32 := crcReader.Crc32()
+
+	if calculatedCrc32 != readCrc32 {
+		return nil, fmt.Errorf("checkpoint checksum failed! File contains %x but read data checksums to %x", readCrc32, calculatedCrc32)
+	}
+
+	return singleTrieForest(nodes, storableTrie)
+}
+
+// singleTrieForest returns a flattened forest with the given trie only. The nodes which are not
+// reachable from the root of the trie are dropped, so that they are not rebuilt.
+func singleTrieForest(nodes []*flattener.StorableNode, storableTrie *flattener.StorableTrie) (*flattener.FlattenedForest, error) {
+	root := storableTrie.RootIndex
+	if root >= uint64(len(nodes)) {
+		return nil, fmt.Errorf("root index %d out of range", root)
+	}
+
+	reachable := make([]bool, root+1)
+	reachable[root] = true
+	// children are always listed before their parents, so iterating backwards from
+	// the root visits every parent before its children
+	for i := root; i > 0; i-- {
+		if !reachable[i] {
+			nodes[i] = nil
+			continue
+		}
+		n := nodes[i]
+		if n.LIndex >= i || n.RIndex >= i {
+			return nil, fmt.Errorf("sequence of StorableNodes does not satisfy Descendents-First-Relationship")
+		}
+		reachable[n.LIndex] = true
+		reachable[n.RIndex] = true
+	}
+
+	return &flattener.FlattenedForest{
+		Nodes: nodes[:root+1],
+		Tries: []*flattener.StorableTrie{storableTrie},
+	}, nil
+}
+
+// ReadIncrementalCheckpoint reads an incremental checkpoint, and returns the number of the
+// checkpoint it is based on.
+func ReadIncrementalCheckpoint(r io.Reader) (int, *flattener.FlattenedForestIncrement, error) {
+
+	bufReader := bufio.NewReader(r)
+	crcReader := NewCRC32Reader(bufReader)
+	var reader io.Reader = crcReader
+
+	header := make([]byte, 4+8+8+8+2)
+
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return -1, nil, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+
+	magicBytes, pos := readUint16(header, 0)
+	version, pos := readUint16(header, pos)
+	base, pos := readUint64(header, pos)
+	refsCount, pos := readUint64(header, pos)
+	nodesCount, pos := readUint64(header, pos)
+	triesCount, _ := readUint16(header, pos)
+
+	if magicBytes != MagicBytes {
+		return -1, nil, fmt.Errorf("unknown file format. Magic constant %x does not match expected %x", magicBytes, MagicBytes)
+	}
+	if version != VersionIncremental {
+		return -1, nil, fmt.Errorf("unsupported file version %x ", version)
+	}
+
+	refs := make([]hash.Hash, refsCount)
+	nodes := make([]*flattener.StorableNode, nodesCount+1) //+1 for 0 index meaning nil
+	tries := make([]*flattener.StorableTrie, triesCount)
+
+	for i := uint64(0); i < refsCount; i++ {
+		_, err := io.ReadFull(reader, refs[i][:])
+		if err != nil {
+			return -1, nil, fmt.Errorf("cannot read reference %d: %w", i, err)
+		}
+	}
+
+	for i := uint64(1); i <= nodesCount; i++ {
+		storableNode, err := flattener.ReadStorableNode(reader)
+		if err != nil {
+			return -1, nil, fmt.Errorf("cannot read storable node %d: %w", i, err)
+		}
+		nodes[i] = storableNode
+	}
+
+	for i := uint16(0); i < triesCount; i++ {
+		storableTrie, err := flattener.ReadStorableTrie(reader)
+		if err != nil {
+			return -1, nil, fmt.Errorf("cannot read storable trie %d: %w", i, err)
+		}
+		tries[i] = storableTrie
+	}
+
+	crc32buf := make([]byte, 4)
+	_, err = io.ReadFull(bufReader, crc32buf)
+	if err != nil {
+		return -1, nil, fmt.Errorf("error while reading CRC32 checksum: %w", err)
+	}
+	readCrc32, _ := readUint32(crc32buf, 0)
+
+	calculatedCrc32 := crcReader.Crc32()
+
+	if calculatedCrc32 != readCrc32 {
+		return -1, nil, fmt.Errorf("checkpoint checksum failed! File contains %x but read data checksums to %x", readCrc32, calculatedCrc32)
+	}
+
+	return int(base), &flattener.FlattenedForestIncrement{
+		Refs:  refs,
+		Nodes: nodes,
+		Tries: tries,
+	}, nil
 }
 
 func ReadCheckpoint(r io.Reader) (*flattener.FlattenedForest, error) {
@@ -344,11 +876,11 @@ func ReadCheckpoint(r io.Reader) (*flattener.FlattenedForest, error) {
 	if magicBytes != MagicBytes {
 		return nil, fmt.Errorf("unknown file format. Magic constant %x does not match expected %x", magicBytes, MagicBytes)
 	}
-	if version != VersionV1 && version != VersionV3 {
+	if version != VersionV1 && version != VersionV3 && version != VersionV5 {
 		return nil, fmt.Errorf("unsupported file version %x ", version)
 	}
 
-	if version != VersionV3 {
+	if version == VersionV1 {
 		reader = bufReader //switch back to plain reader
 	}
 
@@ -372,7 +904,16 @@ func ReadCheckpoint(r io.Reader) (*flattener.FlattenedForest, error) {
 		tries[i] = storableTrie
 	}
 
-	if version == VersionV3 {
+	if version == VersionV5 {
+		// the root hash index is only needed to load single tries
+		index := make([]byte, int(triesCount)*rootIndexEntrySize+8)
+		_, err := io.ReadFull(reader, index)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read root hash index: %w", err)
+		}
+	}
+
+	if version != VersionV1 {
 		crc32buf := make([]byte, 4)
 		_, err := bufReader.Read(crc32buf)
 		if err != nil {
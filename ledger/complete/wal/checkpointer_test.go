@@ -2,6 +2,7 @@ package wal_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"math/rand"
@@ -240,13 +241,13 @@ func Test_Checkpointing(t *testing.T) {
 					paths = append(paths, path)
 				}
 
-				payloads1, err := f.Read(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+				payloads1, err := f.Read(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
 				require.NoError(t, err)
 
-				payloads2, err := f2.Read(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+				payloads2, err := f2.Read(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
 				require.NoError(t, err)
 
-				payloads3, err := f3.Read(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+				payloads3, err := f3.Read(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
 				require.NoError(t, err)
 
 				for i, path := range paths {
@@ -323,10 +324,10 @@ func Test_Checkpointing(t *testing.T) {
 			trieRead, err := pathfinder.QueryToTrieRead(query, pathFinderVersion)
 			require.NoError(t, err)
 
-			payloads, err := f.Read(trieRead)
+			payloads, err := f.Read(context.Background(), trieRead)
 			require.NoError(t, err)
 
-			payloads5, err := f5.Read(trieRead)
+			payloads5, err := f5.Read(context.Background(), trieRead)
 			require.NoError(t, err)
 
 			for i := range keys2 {
@@ -405,10 +406,10 @@ func Test_Checkpointing(t *testing.T) {
 			trieRead, err := pathfinder.QueryToTrieRead(query, pathFinderVersion)
 			require.NoError(t, err)
 
-			payloads, err := f.Read(trieRead)
+			payloads, err := f.Read(context.Background(), trieRead)
 			require.NoError(t, err)
 
-			payloads6, err := f6.Read(trieRead)
+			payloads6, err := f6.Read(context.Background(), trieRead)
 			require.NoError(t, err)
 
 			for i := range keys2 {
@@ -491,6 +492,300 @@ func Test_StoringLoadingCheckpoints(t *testing.T) {
 
 }
 
+func Test_LoadingCheckpointTrie(t *testing.T) {
+
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		f, err := mtrie.NewForest(size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+		require.NoError(t, err)
+
+		rootHash := f.GetEmptyRootHash()
+		rootHashes := []ledger.RootHash{rootHash}
+		savedData := make(map[ledger.RootHash]map[ledger.Path]*ledger.Payload)
+
+		for i := 0; i < size; i++ {
+			keys := utils.RandomUniqueKeys(numInsPerStep, keyNumberOfParts, keyPartMinByteSize, keyPartMaxByteSize)
+			values := utils.RandomValues(numInsPerStep, 1, 100)
+			update, err := ledger.NewUpdate(ledger.State(rootHash), keys, values)
+			require.NoError(t, err)
+
+			trieUpdate, err := pathfinder.UpdateToTrieUpdate(update, pathFinderVersion)
+			require.NoError(t, err)
+
+			rootHash, err = f.Update(trieUpdate)
+			require.NoError(t, err)
+			rootHashes = append(rootHashes, rootHash)
+
+			data := make(map[ledger.Path]*ledger.Payload, len(trieUpdate.Paths))
+			for j, path := range trieUpdate.Paths {
+				data[path] = trieUpdate.Payloads[j]
+			}
+			savedData[rootHash] = data
+		}
+
+		forestSequencing, err := flattener.FlattenForest(f)
+		require.NoError(t, err)
+
+		writer, err := realWAL.CreateCheckpointWriter(dir, 1)
+		require.NoError(t, err)
+		err = realWAL.StoreCheckpoint(forestSequencing, writer)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		filepath := path.Join(dir, realWAL.NumberToFilename(1))
+
+		t.Run("loads every trie on its own", func(t *testing.T) {
+			for _, rootHash := range rootHashes {
+				single, err := realWAL.LoadCheckpointTrie(filepath, rootHash)
+				require.NoError(t, err)
+				require.Len(t, single.Tries, 1)
+
+				tries, err := flattener.RebuildTries(single)
+				require.NoError(t, err)
+				require.Equal(t, rootHash, tries[0].RootHash())
+
+				loaded, err := mtrie.NewForest(size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+				require.NoError(t, err)
+				require.NoError(t, loaded.AddTries(tries))
+
+				data, ok := savedData[rootHash]
+				if !ok {
+					continue // empty trie
+				}
+				paths := make([]ledger.Path, 0, len(data))
+				for path := range data {
+					paths = append(paths, path)
+				}
+				payloads, err := loaded.Read(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
+				require.NoError(t, err)
+				for i, path := range paths {
+					require.True(t, data[path].Equals(payloads[i]))
+				}
+			}
+		})
+
+		t.Run("only keeps the nodes of the trie", func(t *testing.T) {
+			single, err := realWAL.LoadCheckpointTrie(filepath, rootHashes[1])
+			require.NoError(t, err)
+
+			count := 0
+			for _, n := range single.Nodes {
+				if n != nil {
+					count++
+				}
+			}
+			require.Less(t, count, len(forestSequencing.Nodes)-1)
+		})
+
+		t.Run("full checkpoint can still be read", func(t *testing.T) {
+			loaded, err := realWAL.LoadCheckpoint(filepath)
+			require.NoError(t, err)
+			require.Equal(t, forestSequencing, loaded)
+		})
+
+		t.Run("unknown root hash", func(t *testing.T) {
+			_, err := realWAL.LoadCheckpointTrie(filepath, ledger.RootHash(unittest.StateCommitmentFixture()))
+			require.Error(t, err)
+		})
+
+		t.Run("detects modified data", func(t *testing.T) {
+			// the checksum covers the data which isn't deserialized when loading a single trie
+			randomlyModifyFile(t, filepath)
+
+			_, err := realWAL.LoadCheckpointTrie(filepath, rootHashes[1])
+			require.Error(t, err)
+		})
+	})
+}
+
+func Test_IncrementalCheckpointing(t *testing.T) {
+
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		f, err := mtrie.NewForest(size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+		require.NoError(t, err)
+
+		var rootHash = f.GetEmptyRootHash()
+
+		//saved data after updates
+		savedData := make(map[ledger.RootHash]map[ledger.Path]*ledger.Payload)
+
+		recordUpdates := func(t *testing.T) int {
+			wal, err := realWAL.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, segmentSize)
+			require.NoError(t, err)
+
+			for i := 0; i < size; i++ {
+				keys := utils.RandomUniqueKeys(numInsPerStep, keyNumberOfParts, 1600, 1600)
+				values := utils.RandomValues(numInsPerStep, valueMaxByteSize/2, valueMaxByteSize)
+				update, err := ledger.NewUpdate(ledger.State(rootHash), keys, values)
+				require.NoError(t, err)
+
+				trieUpdate, err := pathfinder.UpdateToTrieUpdate(update, pathFinderVersion)
+				require.NoError(t, err)
+
+				err = wal.RecordUpdate(trieUpdate)
+				require.NoError(t, err)
+
+				rootHash, err = f.Update(trieUpdate)
+				require.NoError(t, err)
+
+				data := make(map[ledger.Path]*ledger.Payload, len(trieUpdate.Paths))
+				for j, path := range trieUpdate.Paths {
+					data[path] = trieUpdate.Payloads[j]
+				}
+				savedData[rootHash] = data
+			}
+
+			_, last, err := wal.Segments()
+			require.NoError(t, err)
+			<-wal.Done()
+			return last
+		}
+
+		var base, to int
+		var fullCheckpointSize int
+
+		t.Run("create full and incremental checkpoints", func(t *testing.T) {
+			base = recordUpdates(t)
+
+			wal, err := realWAL.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, segmentSize)
+			require.NoError(t, err)
+			checkpointer, err := wal.NewCheckpointer()
+			require.NoError(t, err)
+
+			// no checkpoint to base an incremental checkpoint on yet
+			err = checkpointer.IncrementalCheckpoint(base, func() (io.WriteCloser, error) {
+				return checkpointer.CheckpointWriter(base)
+			})
+			require.Error(t, err)
+
+			err = checkpointer.Checkpoint(base, func() (io.WriteCloser, error) {
+				return checkpointer.CheckpointWriter(base)
+			})
+			require.NoError(t, err)
+			<-wal.Done()
+
+			to = recordUpdates(t)
+
+			wal, err = realWAL.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, segmentSize)
+			require.NoError(t, err)
+			checkpointer, err = wal.NewCheckpointer()
+			require.NoError(t, err)
+
+			full := &closingBuffer{}
+			err = checkpointer.Checkpoint(to, func() (io.WriteCloser, error) {
+				return full, nil
+			})
+			require.NoError(t, err)
+			fullCheckpointSize = full.Len()
+
+			err = checkpointer.IncrementalCheckpoint(to, func() (io.WriteCloser, error) {
+				return checkpointer.CheckpointWriter(to)
+			})
+			require.NoError(t, err)
+
+			<-wal.Done()
+		})
+
+		t.Run("incremental checkpoint references its base", func(t *testing.T) {
+			wal, err := realWAL.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, segmentSize)
+			require.NoError(t, err)
+			defer func() { <-wal.Done() }()
+			checkpointer, err := wal.NewCheckpointer()
+			require.NoError(t, err)
+
+			checkpointBase, err := checkpointer.CheckpointBase(to)
+			require.NoError(t, err)
+			require.Equal(t, base, checkpointBase)
+
+			checkpointBase, err = checkpointer.CheckpointBase(base)
+			require.NoError(t, err)
+			require.Equal(t, -1, checkpointBase)
+
+			chain, err := checkpointer.IncrementalChainLength(to)
+			require.NoError(t, err)
+			require.Equal(t, 1, chain)
+
+			info, err := os.Stat(path.Join(dir, realWAL.NumberToFilename(to)))
+			require.NoError(t, err)
+			require.Less(t, int(info.Size()), fullCheckpointSize)
+		})
+
+		t.Run("replay incremental checkpoint", func(t *testing.T) {
+			f2, err := mtrie.NewForest(size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+			require.NoError(t, err)
+
+			wal, err := realWAL.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, segmentSize)
+			require.NoError(t, err)
+
+			err = wal.Replay(
+				func(forestSequencing *flattener.FlattenedForest) error {
+					return loadIntoForest(f2, forestSequencing)
+				},
+				func(update *ledger.TrieUpdate) error {
+					return fmt.Errorf("I should fail as there should be no updates")
+				},
+				func(rootHash ledger.RootHash) error {
+					return fmt.Errorf("I should fail as there should be no deletions")
+				},
+			)
+			require.NoError(t, err)
+			<-wal.Done()
+
+			for rootHash, data := range savedData {
+				paths := make([]ledger.Path, 0, len(data))
+				for path := range data {
+					paths = append(paths, path)
+				}
+
+				payloads, err := f2.Read(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
+				require.NoError(t, err)
+
+				for i, path := range paths {
+					require.True(t, data[path].Equals(payloads[i]))
+				}
+			}
+		})
+
+		t.Run("compactor keeps base of incremental checkpoint", func(t *testing.T) {
+			wal, err := realWAL.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, segmentSize)
+			require.NoError(t, err)
+			defer func() { <-wal.Done() }()
+			checkpointer, err := wal.NewCheckpointer()
+			require.NoError(t, err)
+
+			// large distance, so that no checkpoint is created
+			compactor := realWAL.NewCompactor(checkpointer, time.Second, 1000, 1, realWAL.WithCheckpointMode(realWAL.CheckpointIncremental, 10))
+			err = compactor.Run()
+			require.NoError(t, err)
+
+			checkpoints, err := checkpointer.Checkpoints()
+			require.NoError(t, err)
+			require.Equal(t, []int{base, to}, checkpoints)
+		})
+	})
+}
+
+func Test_ParseCheckpointMode(t *testing.T) {
+	for _, mode := range []realWAL.CheckpointMode{realWAL.CheckpointFull, realWAL.CheckpointIncremental} {
+		parsed, err := realWAL.ParseCheckpointMode(mode.String())
+		require.NoError(t, err)
+		require.Equal(t, mode, parsed)
+	}
+
+	_, err := realWAL.ParseCheckpointMode("differential")
+	require.Error(t, err)
+}
+
+// closingBuffer is an in-memory checkpoint target
+type closingBuffer struct {
+	bytes.Buffer
+}
+
+func (b *closingBuffer) Close() error {
+	return nil
+}
+
 func loadIntoForest(forest *mtrie.Forest, forestSequencing *flattener.FlattenedForest) error {
 	tries, err := flattener.RebuildTries(forestSequencing)
 	if err != nil {
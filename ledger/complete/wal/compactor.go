@@ -1,14 +1,58 @@
 package wal
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"sync"
 	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
 )
 
+// CheckpointMode determines how the compactor creates checkpoints.
+type CheckpointMode int
+
+const (
+	// CheckpointFull creates checkpoints containing the whole forest.
+	CheckpointFull CheckpointMode = iota
+	// CheckpointIncremental creates checkpoints containing only the nodes created since the
+	// previous checkpoint, so that they are faster to create and smaller. Loading them
+	// requires the chain of checkpoints back to the latest full checkpoint.
+	CheckpointIncremental
+)
+
+func (m CheckpointMode) String() string {
+	switch m {
+	case CheckpointFull:
+		return "full"
+	case CheckpointIncremental:
+		return "incremental"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(m))
+	}
+}
+
+// ParseCheckpointMode parses the name of a checkpoint mode, as returned by CheckpointMode.String.
+func ParseCheckpointMode(name string) (CheckpointMode, error) {
+	for _, mode := range []CheckpointMode{CheckpointFull, CheckpointIncremental} {
+		if mode.String() == name {
+			return mode, nil
+		}
+	}
+	return CheckpointFull, fmt.Errorf("unknown checkpoint mode: %s", name)
+}
+
+// LoadMeter reports the current execution load of the node.
+type LoadMeter interface {
+	// BlockRate returns the number of blocks per second over a recent window.
+	BlockRate() float64
+}
+
 type Compactor struct {
 	checkpointer *Checkpointer
+	log          zerolog.Logger
 	done         chan struct{}
 	stopc        chan struct{}
 	wg           sync.WaitGroup
@@ -16,20 +60,81 @@ type Compactor struct {
 	interval           time.Duration
 	checkpointDistance uint
 	checkpointsToKeep  uint
+
+	// writeLimiter limits the rate of checkpoint writes, nil if unlimited
+	writeLimiter *rate.Limiter
+
+	// off-peak scheduling: a due checkpoint is postponed while the block rate is above
+	// maxBlockRate, but at most for maxDelay
+	load         LoadMeter
+	maxBlockRate float64
+	maxDelay     time.Duration
+	dueSince     time.Time
+
+	// incremental checkpoints: at most maxIncrementalChain consecutive incremental
+	// checkpoints are created before a full checkpoint
+	mode                CheckpointMode
+	maxIncrementalChain uint
 }
 
-func NewCompactor(checkpointer *Checkpointer, interval time.Duration, checkpointDistance uint, checkpointsToKeep uint) *Compactor {
+// CompactorOption configures a compactor.
+type CompactorOption func(*Compactor)
+
+// WithLogger sets the logger of the compactor, which doesn't log by default.
+func WithLogger(log zerolog.Logger) CompactorOption {
+	return func(c *Compactor) {
+		c.log = log.With().Str("component", "compactor").Logger()
+	}
+}
+
+// WithWriteRateLimit limits the rate at which checkpoints are written to disk, so that
+// creating a checkpoint doesn't saturate the disk used by the execution.
+func WithWriteRateLimit(bytesPerSecond int) CompactorOption {
+	return func(c *Compactor) {
+		if bytesPerSecond > 0 {
+			c.writeLimiter = rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)
+		}
+	}
+}
+
+// WithOffPeakScheduling postpones due checkpoints while the block rate reported by the load
+// meter is above maxBlockRate. A checkpoint is created regardless once it is due for maxDelay,
+// so that the WAL can't grow without bound under sustained load.
+func WithOffPeakScheduling(load LoadMeter, maxBlockRate float64, maxDelay time.Duration) CompactorOption {
+	return func(c *Compactor) {
+		c.load = load
+		c.maxBlockRate = maxBlockRate
+		c.maxDelay = maxDelay
+	}
+}
+
+// WithCheckpointMode creates checkpoints in the given mode. In incremental mode, a full checkpoint
+// is created whenever the latest checkpoint is preceded by maxIncrementalChain incremental ones,
+// which bounds the number of checkpoints to load when restoring the forest.
+func WithCheckpointMode(mode CheckpointMode, maxIncrementalChain uint) CompactorOption {
+	return func(c *Compactor) {
+		c.mode = mode
+		c.maxIncrementalChain = maxIncrementalChain
+	}
+}
+
+func NewCompactor(checkpointer *Checkpointer, interval time.Duration, checkpointDistance uint, checkpointsToKeep uint, opts ...CompactorOption) *Compactor {
 	if checkpointDistance < 1 {
 		checkpointDistance = 1
 	}
-	return &Compactor{
+	c := &Compactor{
 		checkpointer:       checkpointer,
+		log:                zerolog.Nop(),
 		done:               make(chan struct{}),
 		stopc:              make(chan struct{}),
 		interval:           interval,
 		checkpointDistance: checkpointDistance,
 		checkpointsToKeep:  checkpointsToKeep,
 	}
+	for _, apply := range opts {
+		apply(c)
+	}
+	return c
 }
 
 // Ready periodically fires Run function, every `interval`
@@ -95,24 +200,75 @@ func (c *Compactor) createCheckpoints() error {
 		return fmt.Errorf("cannot get latest checkpoint: %w", err)
 	}
 
-	fmt.Printf("creating a checkpoint from segment %d to segment %d\n", from, to)
+	c.log.Debug().Int("from", from).Int("to", to).Msg("creating a checkpoint")
 
 	// more then one segment means we can checkpoint safely up to `to`-1
 	// presumably last segment is being written to
 	if to-from > int(c.checkpointDistance) {
+		if c.postpone(time.Now()) {
+			c.log.Info().Float64("max_block_rate", c.maxBlockRate).Msg("postponing checkpoint due to high block rate")
+			return nil
+		}
+
 		checkpointNumber := to - 1
-		fmt.Printf("checkpointing to %d\n", checkpointNumber)
+		c.log.Info().Int("checkpoint", checkpointNumber).Msg("checkpointing")
 
-		err = c.checkpointer.Checkpoint(checkpointNumber, func() (io.WriteCloser, error) {
-			return c.checkpointer.CheckpointWriter(checkpointNumber)
+		err = c.checkpoint(checkpointNumber, func() (io.WriteCloser, error) {
+			writer, err := c.checkpointer.CheckpointWriter(checkpointNumber)
+			if err != nil || c.writeLimiter == nil {
+				return writer, err
+			}
+			return &rateLimitedWriter{WriteCloser: writer, limiter: c.writeLimiter}, nil
 		})
 		if err != nil {
 			return fmt.Errorf("error creating checkpoint (%d): %w", checkpointNumber, err)
 		}
+		c.dueSince = time.Time{}
 	}
 	return nil
 }
 
+// checkpoint creates a checkpoint stopping at the given segment, incremental if the mode
+// allows for it.
+func (c *Compactor) checkpoint(to int, targetWriter func() (io.WriteCloser, error)) error {
+	if c.mode != CheckpointIncremental {
+		return c.checkpointer.Checkpoint(to, targetWriter)
+	}
+
+	latestCheckpoint, err := c.checkpointer.LatestCheckpoint()
+	if err != nil {
+		return fmt.Errorf("cannot get latest checkpoint: %w", err)
+	}
+	if latestCheckpoint == -1 {
+		return c.checkpointer.Checkpoint(to, targetWriter)
+	}
+
+	chain, err := c.checkpointer.IncrementalChainLength(latestCheckpoint)
+	if err != nil {
+		return fmt.Errorf("cannot get chain of checkpoint %d: %w", latestCheckpoint, err)
+	}
+	if chain >= int(c.maxIncrementalChain) {
+		c.log.Info().Int("incremental_checkpoints", chain).Msg("creating a full checkpoint")
+		return c.checkpointer.Checkpoint(to, targetWriter)
+	}
+
+	return c.checkpointer.IncrementalCheckpoint(to, targetWriter)
+}
+
+// postpone returns whether a due checkpoint should be postponed, as the node is under load.
+func (c *Compactor) postpone(now time.Time) bool {
+	if c.load == nil {
+		return false
+	}
+	if c.dueSince.IsZero() {
+		c.dueSince = now
+	}
+	if now.Sub(c.dueSince) >= c.maxDelay {
+		return false
+	}
+	return c.load.BlockRate() > c.maxBlockRate
+}
+
 func (c *Compactor) cleanupCheckpoints() error {
 	// don't bother listing checkpoints if we keep them all
 	if c.checkpointsToKeep == 0 {
@@ -125,7 +281,26 @@ func (c *Compactor) cleanupCheckpoints() error {
 	if len(checkpoints) > int(c.checkpointsToKeep) {
 		checkpointsToRemove := checkpoints[:len(checkpoints)-int(c.checkpointsToKeep)] // if condition guarantees this never fails
 
+		// the checkpoints which kept incremental checkpoints are based on must be kept as well
+		required := make(map[int]struct{})
+		for _, checkpoint := range checkpoints[len(checkpointsToRemove):] {
+			for {
+				base, err := c.checkpointer.CheckpointBase(checkpoint)
+				if err != nil {
+					return fmt.Errorf("cannot get base of checkpoint %d: %w", checkpoint, err)
+				}
+				if base == -1 {
+					break
+				}
+				required[base] = struct{}{}
+				checkpoint = base
+			}
+		}
+
 		for _, checkpoint := range checkpointsToRemove {
+			if _, ok := required[checkpoint]; ok {
+				continue
+			}
 			err := c.checkpointer.RemoveCheckpoint(checkpoint)
 			if err != nil {
 				return fmt.Errorf("cannot remove checkpoint %d: %w", checkpoint, err)
@@ -134,3 +309,30 @@ func (c *Compactor) cleanupCheckpoints() error {
 	}
 	return nil
 }
+
+// rateLimitedWriter limits the rate of writes to the underlying writer.
+type rateLimitedWriter struct {
+	io.WriteCloser
+	limiter *rate.Limiter
+}
+
+func (w *rateLimitedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		// the limiter doesn't allow waiting for more than its burst at once
+		n := len(p) - written
+		if n > w.limiter.Burst() {
+			n = w.limiter.Burst()
+		}
+		err := w.limiter.WaitN(context.Background(), n)
+		if err != nil {
+			return written, err
+		}
+		m, err := w.WriteCloser.Write(p[written : written+n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -1,7 +1,10 @@
 package wal
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"math/rand"
 	"os"
 	"path"
 	"testing"
@@ -164,10 +167,10 @@ func Test_Compactor(t *testing.T) {
 				}
 
 				read := &ledger.TrieRead{RootHash: rootHash, Paths: paths}
-				payloads, err := f.Read(read)
+				payloads, err := f.Read(context.Background(), read)
 				require.NoError(t, err)
 
-				payloads2, err := f2.Read(read)
+				payloads2, err := f2.Read(context.Background(), read)
 				require.NoError(t, err)
 
 				for i, path := range paths {
@@ -312,3 +315,62 @@ func loadIntoForest(forest *mtrie.Forest, forestSequencing *flattener.FlattenedF
 	}
 	return nil
 }
+
+type fixedLoad float64
+
+func (l fixedLoad) BlockRate() float64 {
+	return float64(l)
+}
+
+func Test_CompactorPostponesCheckpoints(t *testing.T) {
+
+	start := time.Now()
+
+	t.Run("not postponed without load meter", func(t *testing.T) {
+		compactor := NewCompactor(nil, time.Second, 1, 0)
+		require.False(t, compactor.postpone(start))
+	})
+
+	t.Run("postponed under load", func(t *testing.T) {
+		compactor := NewCompactor(nil, time.Second, 1, 0, WithOffPeakScheduling(fixedLoad(2), 1, time.Minute))
+		require.True(t, compactor.postpone(start))
+		require.True(t, compactor.postpone(start.Add(30*time.Second)))
+
+		// the checkpoint is created once it was postponed long enough
+		require.False(t, compactor.postpone(start.Add(time.Minute)))
+	})
+
+	t.Run("not postponed without load", func(t *testing.T) {
+		compactor := NewCompactor(nil, time.Second, 1, 0, WithOffPeakScheduling(fixedLoad(0.5), 1, time.Minute))
+		require.False(t, compactor.postpone(start))
+	})
+}
+
+type closingBuffer struct {
+	bytes.Buffer
+}
+
+func (b *closingBuffer) Close() error {
+	return nil
+}
+
+func Test_RateLimitedWriter(t *testing.T) {
+
+	const bytesPerSecond = 1000
+
+	var buffer closingBuffer
+	compactor := NewCompactor(nil, time.Second, 1, 0, WithWriteRateLimit(bytesPerSecond))
+	writer := &rateLimitedWriter{WriteCloser: &buffer, limiter: compactor.writeLimiter}
+
+	data := make([]byte, 3*bytesPerSecond)
+	rand.Read(data)
+
+	start := time.Now()
+	n, err := writer.Write(data)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	require.Equal(t, data, buffer.Bytes())
+
+	// the burst is written immediately, the remaining bytes at the limited rate
+	require.GreaterOrEqual(t, time.Since(start), 1900*time.Millisecond)
+}
@@ -51,7 +51,18 @@ func EncodeDelete(rootHash ledger.RootHash) []byte {
 	return buf
 }
 
+// Decode decodes a record in either the legacy format, or prefixed with a header as described in record.go.
+// Records whose header or checksum fails validation are reported as CorruptedRecordError. Records which pass
+// validation but can't be decoded are not considered corrupted, as they point to a bug rather than a torn write.
 func Decode(data []byte) (operation WALOperation, rootHash ledger.RootHash, update *ledger.TrieUpdate, err error) {
+	data, err = decodeRecord(data)
+	if err != nil {
+		return
+	}
+	return decodeOperation(data)
+}
+
+func decodeOperation(data []byte) (operation WALOperation, rootHash ledger.RootHash, update *ledger.TrieUpdate, err error) {
 	if len(data) < 4 { // 1 byte op + 2 size + actual data = 4 minimum
 		err = fmt.Errorf("data corrupted, too short to represent operation - hexencoded data: %x", data)
 		return
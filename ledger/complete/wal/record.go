@@ -0,0 +1,121 @@
+package wal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/DataDog/zstd"
+)
+
+/*
+Records are either written in the legacy format described in encoding.go, or prefixed with a header:
+
+1 byte header version | 1 byte compression | 4 bytes Big Endian uint32 CRC32 of the data | data
+
+where data is the legacy record, compressed according to the compression byte. The header version is
+chosen to never collide with a WAL operation, so both formats can be read from the same segments.
+*/
+
+const (
+	recordHeaderV1   byte = 0xF1
+	recordHeaderSize      = 1 + 1 + 4
+)
+
+const (
+	compressionNone byte = 0
+	compressionZstd byte = 1
+)
+
+// RecordConfig configures the format of the records written to the write-ahead log.
+// Records of all formats can be read regardless of the configuration.
+type RecordConfig struct {
+	// Checksum prefixes records with a header holding a CRC32 checksum of the record.
+	Checksum bool
+	// Compress compresses records with zstd. Compressed records always hold a checksum.
+	Compress bool
+	// TruncateCorrupted discards the first corrupted record and all records after it when
+	// replaying the WAL on a forest, instead of failing the replay. Only records failing the
+	// validation of their header or checksum are considered corrupted.
+	TruncateCorrupted bool
+}
+
+// DefaultRecordConfig writes records in the legacy format, which can be read by older versions.
+func DefaultRecordConfig() RecordConfig {
+	return RecordConfig{}
+}
+
+// CorruptedRecordError is returned when the header or checksum of a record of the WAL fails validation.
+// All records in the segment before the corrupted record are valid.
+type CorruptedRecordError struct {
+	Segment int // segment of the corrupted record, -1 if not known
+	Record  int // index of the corrupted record within the segment, -1 if not known
+	Err     error
+}
+
+func newCorruptedRecordError(err error) *CorruptedRecordError {
+	return &CorruptedRecordError{Segment: -1, Record: -1, Err: err}
+}
+
+func (e *CorruptedRecordError) Error() string {
+	return fmt.Sprintf("corrupted WAL record %d in segment %d: %v", e.Record, e.Segment, e.Err)
+}
+
+func (e *CorruptedRecordError) Unwrap() error {
+	return e.Err
+}
+
+// encodeRecord prefixes the legacy encoded record with a header according to the configuration.
+func encodeRecord(data []byte, config RecordConfig) ([]byte, error) {
+	if !config.Checksum && !config.Compress {
+		return data, nil
+	}
+
+	compression := compressionNone
+	if config.Compress {
+		compressed, err := zstd.Compress(nil, data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot compress record: %w", err)
+		}
+		data = compressed
+		compression = compressionZstd
+	}
+
+	buf := make([]byte, recordHeaderSize, recordHeaderSize+len(data))
+	buf[0] = recordHeaderV1
+	buf[1] = compression
+	binary.BigEndian.PutUint32(buf[2:], crc32.Checksum(data, crc32Table))
+	return append(buf, data...), nil
+}
+
+// decodeRecord validates the header of a record and returns the legacy encoded record.
+// Records without a header are returned unchanged.
+func decodeRecord(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != recordHeaderV1 {
+		return data, nil
+	}
+	if len(data) < recordHeaderSize {
+		return nil, newCorruptedRecordError(fmt.Errorf("record too short for header: %d bytes", len(data)))
+	}
+
+	compression := data[1]
+	checksum := binary.BigEndian.Uint32(data[2:])
+	data = data[recordHeaderSize:]
+
+	if actual := crc32.Checksum(data, crc32Table); actual != checksum {
+		return nil, newCorruptedRecordError(fmt.Errorf("checksum mismatch: expected %x, got %x", checksum, actual))
+	}
+
+	switch compression {
+	case compressionNone:
+		return data, nil
+	case compressionZstd:
+		decompressed, err := zstd.Decompress(nil, data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress record: %w", err)
+		}
+		return decompressed, nil
+	default:
+		return nil, newCorruptedRecordError(fmt.Errorf("unknown compression: %d", compression))
+	}
+}
@@ -0,0 +1,128 @@
+package wal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestRecordEncoding(t *testing.T) {
+	var rootHash ledger.RootHash
+	copy(rootHash[:], []byte{2, 1, 3, 7})
+	data := EncodeDelete(rootHash)
+
+	configs := map[string]RecordConfig{
+		"legacy":     {},
+		"checksum":   {Checksum: true},
+		"compressed": {Compress: true},
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			record, err := encodeRecord(data, config)
+			require.NoError(t, err)
+
+			operation, decodedHash, _, err := Decode(record)
+			require.NoError(t, err)
+			require.Equal(t, WALDelete, operation)
+			require.Equal(t, rootHash, decodedHash)
+		})
+	}
+
+	t.Run("corrupted", func(t *testing.T) {
+		record, err := encodeRecord(data, RecordConfig{Checksum: true})
+		require.NoError(t, err)
+		record[len(record)-1]++
+
+		_, _, _, err = Decode(record)
+		var corrupted *CorruptedRecordError
+		require.True(t, errors.As(err, &corrupted))
+	})
+
+	t.Run("undecodable", func(t *testing.T) {
+		// records passing validation which can't be decoded are not corrupted
+		record, err := encodeRecord([]byte{byte(WALDelete), 0, 1}, RecordConfig{Checksum: true})
+		require.NoError(t, err)
+
+		_, _, _, err = Decode(record)
+		require.Error(t, err)
+		var corrupted *CorruptedRecordError
+		require.False(t, errors.As(err, &corrupted))
+	})
+}
+
+func TestTruncateCorruptedRecord(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		config := RecordConfig{Checksum: true, TruncateCorrupted: true}
+
+		forest, err := mtrie.NewForest(10, &metrics.NoopCollector{}, nil)
+		require.NoError(t, err)
+
+		update := func(i uint16) *ledger.TrieUpdate {
+			return &ledger.TrieUpdate{
+				RootHash: forest.GetEmptyRootHash(),
+				Paths:    []ledger.Path{utils.PathByUint16(i)},
+				Payloads: []*ledger.Payload{utils.LightPayload(i, i)},
+			}
+		}
+
+		wal, err := NewDiskWALWithConfig(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize, DefaultSyncConfig(), config)
+		require.NoError(t, err)
+
+		for i := uint16(0); i < 3; i++ {
+			require.NoError(t, wal.RecordUpdate(update(i)))
+		}
+
+		// records a record whose checksum doesn't match, followed by valid records
+		corrupted, err := encodeRecord(EncodeUpdate(update(3)), config)
+		require.NoError(t, err)
+		corrupted[len(corrupted)-1]++
+		_, err = wal.wal.Log(corrupted)
+		require.NoError(t, err)
+
+		for i := uint16(4); i < 6; i++ {
+			require.NoError(t, wal.RecordUpdate(update(i)))
+		}
+		<-wal.Done()
+
+		// replaying without truncation fails at the corrupted record
+		wal, err = NewDiskWALWithConfig(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize, DefaultSyncConfig(), DefaultRecordConfig())
+		require.NoError(t, err)
+		err = wal.ReplayOnForest(forest)
+		var corruptedErr *CorruptedRecordError
+		require.True(t, errors.As(err, &corruptedErr))
+		require.Equal(t, 0, corruptedErr.Segment)
+		require.Equal(t, 3, corruptedErr.Record)
+		<-wal.Done()
+
+		// replaying with truncation discards the corrupted record and all records after it
+		wal, err = NewDiskWALWithConfig(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize, DefaultSyncConfig(), config)
+		require.NoError(t, err)
+		require.NoError(t, wal.ReplayOnForest(forest))
+		<-wal.Done()
+
+		wal, err = NewDiskWALWithConfig(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize, DefaultSyncConfig(), DefaultRecordConfig())
+		require.NoError(t, err)
+		replayed := 0
+		err = wal.Replay(
+			func(*flattener.FlattenedForest) error { return nil },
+			func(*ledger.TrieUpdate) error {
+				replayed++
+				return nil
+			},
+			func(ledger.RootHash) error { return nil },
+		)
+		require.NoError(t, err)
+		require.Equal(t, 3, replayed)
+		<-wal.Done()
+	})
+}
@@ -0,0 +1,363 @@
+package wal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	prometheusWAL "github.com/m4ksio/wal/wal"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module"
+)
+
+// SyncPolicy determines when records of the write-ahead log are synced to disk.
+type SyncPolicy int
+
+const (
+	// SyncNone leaves flushing records to the operating system. Segments are only synced once
+	// they are completed, so the most recent records can be lost on a crash.
+	SyncNone SyncPolicy = iota
+	// SyncPerRecord syncs every record before recording it returns.
+	SyncPerRecord
+	// SyncPerBatch syncs every record before recording it returns, but records of concurrent
+	// writers are made durable by a single sync (group commit).
+	SyncPerBatch
+	// SyncPeriodic syncs the recorded records at a fixed interval. Recording returns without
+	// waiting for the sync, so records of the last interval can be lost on a crash.
+	SyncPeriodic
+)
+
+// ErrSyncerClosed is returned when recording with a WAL which is already closed.
+var ErrSyncerClosed = errors.New("WAL syncer is closed")
+
+func (p SyncPolicy) String() string {
+	switch p {
+	case SyncNone:
+		return "none"
+	case SyncPerRecord:
+		return "record"
+	case SyncPerBatch:
+		return "batch"
+	case SyncPeriodic:
+		return "periodic"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(p))
+	}
+}
+
+// ParseSyncPolicy parses the name of a sync policy, as returned by SyncPolicy.String.
+func ParseSyncPolicy(name string) (SyncPolicy, error) {
+	for _, policy := range []SyncPolicy{SyncNone, SyncPerRecord, SyncPerBatch, SyncPeriodic} {
+		if policy.String() == name {
+			return policy, nil
+		}
+	}
+	return SyncNone, fmt.Errorf("unknown WAL sync policy: %s", name)
+}
+
+// SyncConfig configures the durability of the records of the write-ahead log.
+type SyncConfig struct {
+	Policy SyncPolicy
+	// Interval between syncs, only used by SyncPeriodic.
+	Interval time.Duration
+}
+
+// DefaultSyncConfig leaves syncing to the operating system, as the WAL did before sync policies were added.
+func DefaultSyncConfig() SyncConfig {
+	return SyncConfig{Policy: SyncNone}
+}
+
+func (c SyncConfig) validate() error {
+	switch c.Policy {
+	case SyncNone, SyncPerRecord, SyncPerBatch:
+		return nil
+	case SyncPeriodic:
+		if c.Interval <= 0 {
+			return fmt.Errorf("periodic WAL sync requires a positive interval, got %s", c.Interval)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown WAL sync policy: %s", c.Policy)
+	}
+}
+
+type syncRequest struct {
+	segment int
+	done    chan error
+}
+
+// syncer makes records of the WAL durable according to its sync policy. The WAL writes
+// records into the page cache of the current segment file, so records are synced by
+// syncing the segment file they were written to.
+type syncer struct {
+	config  SyncConfig
+	dir     string
+	log     zerolog.Logger
+	metrics module.WALMetrics
+
+	// syncMu guards the open segment file
+	syncMu  sync.Mutex
+	file    *os.File
+	segment int
+
+	// mu guards the records pending a periodic sync and the error of the last one
+	mu           sync.Mutex
+	pending      int
+	firstPending int
+	lastPending  int
+	err          error
+
+	requests chan syncRequest
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+func newSyncer(config SyncConfig, dir string, log zerolog.Logger, metrics module.WALMetrics) (*syncer, error) {
+	err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &syncer{
+		config:   config,
+		dir:      dir,
+		log:      log,
+		metrics:  metrics,
+		segment:  -1,
+		requests: make(chan syncRequest),
+		stop:     make(chan struct{}),
+	}
+
+	switch config.Policy {
+	case SyncPerBatch:
+		s.wg.Add(1)
+		go s.groupCommit()
+	case SyncPeriodic:
+		s.wg.Add(1)
+		go s.periodic()
+	}
+
+	return s, nil
+}
+
+// recorded is called once a record was written to the given segment. Depending on the
+// policy, it returns once the record is durable.
+func (s *syncer) recorded(segment int) error {
+	switch s.config.Policy {
+	case SyncPerRecord:
+		return s.sync(segment, segment, 1)
+
+	case SyncPerBatch:
+		done := make(chan error, 1)
+		select {
+		case s.requests <- syncRequest{segment: segment, done: done}:
+		case <-s.stop:
+			return ErrSyncerClosed
+		}
+		return <-done
+
+	case SyncPeriodic:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.err != nil {
+			// surface a failed sync to the writers, as their records might not be durable
+			err := s.err
+			s.err = nil
+			return err
+		}
+		if s.pending == 0 {
+			s.firstPending = segment
+		}
+		s.pending++
+		s.lastPending = segment
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+// groupCommit syncs the records of all writers waiting for a sync at once. Writers
+// recording while a sync is in progress are batched into the next one.
+func (s *syncer) groupCommit() {
+	defer s.wg.Done()
+
+	for {
+		var batch []syncRequest
+		select {
+		case <-s.stop:
+			return
+		case req := <-s.requests:
+			batch = append(batch, req)
+		}
+
+	collect:
+		for {
+			select {
+			case req := <-s.requests:
+				batch = append(batch, req)
+			default:
+				break collect
+			}
+		}
+
+		first, last := batch[0].segment, batch[0].segment
+		for _, req := range batch[1:] {
+			if req.segment < first {
+				first = req.segment
+			}
+			if req.segment > last {
+				last = req.segment
+			}
+		}
+
+		err := s.sync(first, last, len(batch))
+		for _, req := range batch {
+			req.done <- err
+		}
+	}
+}
+
+// periodic syncs the records recorded since the previous sync once per interval.
+func (s *syncer) periodic() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			err := s.syncPending()
+			if err != nil {
+				s.log.Error().Err(err).Msg("periodic WAL sync failed")
+				s.mu.Lock()
+				s.err = err
+				s.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (s *syncer) syncPending() error {
+	s.mu.Lock()
+	records, first, last := s.pending, s.firstPending, s.lastPending
+	s.pending = 0
+	s.mu.Unlock()
+
+	if records == 0 {
+		return nil
+	}
+	return s.sync(first, last, records)
+}
+
+// sync syncs the segments from first to last, which hold the given number of unsynced records.
+func (s *syncer) sync(first, last int, records int) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	start := time.Now()
+
+	// records of previous segments might not have been synced yet when the WAL moved on to
+	// the next segment
+	for segment := first; segment < last; segment++ {
+		var err error
+		if segment == s.segment && s.file != nil {
+			err = s.closeFile()
+		} else {
+			err = syncSegment(s.dir, segment)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if last < s.segment {
+		err := syncSegment(s.dir, last)
+		if err != nil {
+			return err
+		}
+		s.metrics.WALSynced(time.Since(start), records)
+		return nil
+	}
+
+	if last != s.segment && s.file != nil {
+		err := s.closeFile()
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.file == nil {
+		file, err := os.OpenFile(prometheusWAL.SegmentName(s.dir, last), os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("could not open WAL segment %d: %w", last, err)
+		}
+		s.file = file
+		s.segment = last
+	}
+
+	err := s.file.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync WAL segment %d: %w", last, err)
+	}
+
+	s.metrics.WALSynced(time.Since(start), records)
+	return nil
+}
+
+func (s *syncer) closeFile() error {
+	err := s.file.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync WAL segment %d: %w", s.segment, err)
+	}
+	err = s.file.Close()
+	if err != nil {
+		return fmt.Errorf("could not close WAL segment %d: %w", s.segment, err)
+	}
+	s.file = nil
+	return nil
+}
+
+// close stops syncing and syncs records still pending a periodic sync.
+func (s *syncer) close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+
+	if s.config.Policy == SyncPeriodic {
+		err := s.syncPending()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	return s.closeFile()
+}
+
+func syncSegment(dir string, segment int) error {
+	file, err := os.OpenFile(prometheusWAL.SegmentName(dir, segment), os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("could not open WAL segment %d: %w", segment, err)
+	}
+	defer file.Close()
+
+	err = file.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync WAL segment %d: %w", segment, err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package wal
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// syncCounter counts the syncs of a WAL and the records they made durable
+type syncCounter struct {
+	metrics.NoopCollector
+	mu      sync.Mutex
+	syncs   int
+	records int
+}
+
+func (c *syncCounter) WALSynced(_ time.Duration, records int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.syncs++
+	c.records += records
+}
+
+func (c *syncCounter) counts() (syncs int, records int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.syncs, c.records
+}
+
+func Test_SyncPolicies(t *testing.T) {
+
+	const writers = 8
+	const updatesPerWriter = 10
+	const updates = writers * updatesPerWriter
+
+	record := func(t *testing.T, w *DiskWAL) {
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < updatesPerWriter; j++ {
+					update := &ledger.TrieUpdate{
+						Paths:    utils.RandomPaths(1),
+						Payloads: utils.RandomPayloads(1, 10, 1000),
+					}
+					require.NoError(t, w.RecordUpdate(update))
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	replayed := func(t *testing.T, w *DiskWAL) int {
+		count := 0
+		err := w.ReplayLogsOnly(
+			func(*flattener.FlattenedForest) error { return nil },
+			func(*ledger.TrieUpdate) error {
+				count++
+				return nil
+			},
+			func(ledger.RootHash) error { return nil },
+		)
+		require.NoError(t, err)
+		return count
+	}
+
+	run := func(t *testing.T, config SyncConfig, f func(t *testing.T, w *DiskWAL, counter *syncCounter)) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			counter := &syncCounter{}
+			// small segments, so records are synced across segments
+			w, err := NewDiskWALWithSync(zerolog.Nop(), nil, counter, dir, 10, pathByteSize, segmentSize, config)
+			require.NoError(t, err)
+
+			record(t, w)
+			f(t, w, counter)
+
+			<-w.Done()
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		run(t, SyncConfig{Policy: SyncNone}, func(t *testing.T, w *DiskWAL, counter *syncCounter) {
+			syncs, _ := counter.counts()
+			require.Zero(t, syncs)
+			require.Equal(t, updates, replayed(t, w))
+		})
+	})
+
+	t.Run("per record", func(t *testing.T) {
+		run(t, SyncConfig{Policy: SyncPerRecord}, func(t *testing.T, w *DiskWAL, counter *syncCounter) {
+			syncs, records := counter.counts()
+			require.Equal(t, updates, syncs)
+			require.Equal(t, updates, records)
+			require.Equal(t, updates, replayed(t, w))
+		})
+	})
+
+	t.Run("per batch", func(t *testing.T) {
+		run(t, SyncConfig{Policy: SyncPerBatch}, func(t *testing.T, w *DiskWAL, counter *syncCounter) {
+			// every record is synced once, but concurrent records can share a sync
+			syncs, records := counter.counts()
+			require.LessOrEqual(t, syncs, updates)
+			require.Equal(t, updates, records)
+			require.Equal(t, updates, replayed(t, w))
+		})
+	})
+
+	t.Run("periodic", func(t *testing.T) {
+		run(t, SyncConfig{Policy: SyncPeriodic, Interval: 10 * time.Millisecond}, func(t *testing.T, w *DiskWAL, counter *syncCounter) {
+			require.Eventually(t, func() bool {
+				_, records := counter.counts()
+				return records == updates
+			}, time.Second, 10*time.Millisecond)
+			require.Equal(t, updates, replayed(t, w))
+		})
+	})
+
+	t.Run("periodic syncs pending records when closed", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			counter := &syncCounter{}
+			w, err := NewDiskWALWithSync(zerolog.Nop(), nil, counter, dir, 10, pathByteSize, segmentSize, SyncConfig{Policy: SyncPeriodic, Interval: time.Hour})
+			require.NoError(t, err)
+
+			record(t, w)
+			<-w.Done()
+
+			_, records := counter.counts()
+			require.Equal(t, updates, records)
+		})
+	})
+
+	t.Run("periodic requires interval", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			_, err := NewDiskWALWithSync(zerolog.Nop(), nil, &syncCounter{}, dir, 10, pathByteSize, segmentSize, SyncConfig{Policy: SyncPeriodic})
+			require.Error(t, err)
+		})
+	})
+}
+
+func Test_ParseSyncPolicy(t *testing.T) {
+	for _, policy := range []SyncPolicy{SyncNone, SyncPerRecord, SyncPerBatch, SyncPeriodic} {
+		parsed, err := ParseSyncPolicy(policy.String())
+		require.NoError(t, err)
+		require.Equal(t, policy, parsed)
+	}
+
+	_, err := ParseSyncPolicy("always")
+	require.Error(t, err)
+}
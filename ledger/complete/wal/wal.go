@@ -1,7 +1,9 @@
 package wal
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"sort"
 	"time"
 
@@ -28,12 +30,31 @@ type DiskWAL struct {
 	diskUpdateLimiter *time.Ticker
 	metrics           module.WALMetrics
 	dir               string
+	segmentSize       int
+	syncer            *syncer
+	recordConfig      RecordConfig
 }
 
 // TODO use real logger and metrics, but that would require passing them to Trie storage
 func NewDiskWAL(logger zerolog.Logger, reg prometheus.Registerer, metrics module.WALMetrics, dir string, forestCapacity int, pathByteSize int, segmentSize int) (*DiskWAL, error) {
+	return NewDiskWALWithSync(logger, reg, metrics, dir, forestCapacity, pathByteSize, segmentSize, DefaultSyncConfig())
+}
+
+// NewDiskWALWithSync creates a WAL which makes its records durable according to the given sync configuration.
+func NewDiskWALWithSync(logger zerolog.Logger, reg prometheus.Registerer, metrics module.WALMetrics, dir string, forestCapacity int, pathByteSize int, segmentSize int, syncConfig SyncConfig) (*DiskWAL, error) {
+	return NewDiskWALWithConfig(logger, reg, metrics, dir, forestCapacity, pathByteSize, segmentSize, syncConfig, DefaultRecordConfig())
+}
+
+// NewDiskWALWithConfig creates a WAL which makes its records durable according to the given sync configuration,
+// and writes its records in the format of the given record configuration.
+func NewDiskWALWithConfig(logger zerolog.Logger, reg prometheus.Registerer, metrics module.WALMetrics, dir string, forestCapacity int, pathByteSize int, segmentSize int, syncConfig SyncConfig, recordConfig RecordConfig) (*DiskWAL, error) {
+	s, err := newSyncer(syncConfig, dir, logger, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WAL sync configuration: %w", err)
+	}
 	w, err := prometheusWAL.NewSize(logger, reg, dir, segmentSize, false)
 	if err != nil {
+		_ = s.close()
 		return nil, err
 	}
 	return &DiskWAL{
@@ -45,6 +66,9 @@ func NewDiskWAL(logger zerolog.Logger, reg prometheus.Registerer, metrics module
 		diskUpdateLimiter: time.NewTicker(5 * time.Second),
 		metrics:           metrics,
 		dir:               dir,
+		segmentSize:       segmentSize,
+		syncer:            s,
+		recordConfig:      recordConfig,
 	}, nil
 }
 
@@ -61,14 +85,22 @@ func (w *DiskWAL) RecordUpdate(update *ledger.TrieUpdate) error {
 		return nil
 	}
 
-	bytes := EncodeUpdate(update)
+	bytes, err := encodeRecord(EncodeUpdate(update), w.recordConfig)
+	if err != nil {
+		return fmt.Errorf("error while encoding update for LedgerWAL: %w", err)
+	}
 
-	_, err := w.wal.Log(bytes)
+	segment, err := w.wal.Log(bytes)
 
 	if err != nil {
 		return fmt.Errorf("error while recording update in LedgerWAL: %w", err)
 	}
 
+	err = w.syncer.recorded(segment)
+	if err != nil {
+		return fmt.Errorf("error while syncing update in LedgerWAL: %w", err)
+	}
+
 	select {
 	case <-w.diskUpdateLimiter.C:
 		diskSize, err := w.DiskSize()
@@ -93,24 +125,54 @@ func (w *DiskWAL) RecordDelete(rootHash ledger.RootHash) error {
 		return nil
 	}
 
-	bytes := EncodeDelete(rootHash)
+	bytes, err := encodeRecord(EncodeDelete(rootHash), w.recordConfig)
+	if err != nil {
+		return fmt.Errorf("error while encoding delete for LedgerWAL: %w", err)
+	}
 
-	_, err := w.wal.Log(bytes)
+	segment, err := w.wal.Log(bytes)
 
 	if err != nil {
 		return fmt.Errorf("error while recording delete in LedgerWAL: %w", err)
 	}
+
+	err = w.syncer.recorded(segment)
+	if err != nil {
+		return fmt.Errorf("error while syncing delete in LedgerWAL: %w", err)
+	}
 	return nil
 }
 
+// ReplayOnForest replays the WAL on the forest. If truncating corrupted records is configured, the replay stops
+// at the first corrupted record, which is discarded together with all records after it.
 func (w *DiskWAL) ReplayOnForest(forest *mtrie.Forest) error {
+	err := w.replayOnForest(forest)
+
+	var corrupted *CorruptedRecordError
+	if !w.recordConfig.TruncateCorrupted || !errors.As(err, &corrupted) {
+		return err
+	}
+
+	w.log.Warn().Err(corrupted).
+		Int("segment", corrupted.Segment).
+		Int("record", corrupted.Record).
+		Msg("truncating WAL at corrupted record")
+
+	err = w.Truncate(corrupted.Segment, corrupted.Record)
+	if err != nil {
+		return fmt.Errorf("cannot truncate WAL at corrupted record: %w", err)
+	}
+	return nil
+}
+
+func (w *DiskWAL) replayOnForest(forest *mtrie.Forest) error {
 	return w.Replay(
 		func(forestSequencing *flattener.FlattenedForest) error {
 			rebuiltTries, err := flattener.RebuildTries(forestSequencing)
 			if err != nil {
 				return fmt.Errorf("rebuilding forest from sequenced nodes failed: %w", err)
 			}
-			err = forest.AddTries(rebuiltTries)
+			err = forest.AddTriesAtomically(rebuiltTries)
 			if err != nil {
 				return fmt.Errorf("adding rebuilt tries to forest failed: %w", err)
 			}
@@ -255,10 +317,23 @@ func (w *DiskWAL) replay(
 
 	defer sr.Close()
 
+	// position of the current record, to report corrupted records
+	segment, index := -1, -1
+
 	for reader.Next() {
+		if reader.Segment() != segment {
+			segment, index = reader.Segment(), 0
+		} else {
+			index++
+		}
+
 		record := reader.Record()
 		operation, rootHash, update, err := Decode(record)
 		if err != nil {
+			var corrupted *CorruptedRecordError
+			if errors.As(err, &corrupted) {
+				corrupted.Segment, corrupted.Record = segment, index
+			}
 			return fmt.Errorf("cannot decode LedgerWAL record: %w", err)
 		}
 
@@ -281,11 +356,125 @@ func (w *DiskWAL) replay(
 		}
 	}
 
+	err = reader.Err()
+	if err != nil {
+		var corruption *prometheusWAL.CorruptionErr
+		if errors.As(err, &corruption) {
+			// the records of the segment before the corruption were read, and decoded successfully
+			record := 0
+			if corruption.Segment == segment {
+				record = index + 1
+			}
+			err = &CorruptedRecordError{Segment: corruption.Segment, Record: record, Err: err}
+		}
+		return fmt.Errorf("cannot read LedgerWAL: %w", err)
+	}
+
 	w.log.Debug().Msgf("finished replaying WAL from %d to %d", from, to)
 
 	return nil
 }
 
+// Truncate discards the record at the given index of the segment, and all records after it.
+// Checkpoints of the discarded segments are removed. The WAL is reopened to append after the
+// last retained record, without registering its metrics again.
+func (w *DiskWAL) Truncate(segment int, record int) error {
+	if segment < 0 || record < 0 {
+		return fmt.Errorf("invalid position of first truncated record: segment %d, record %d", segment, record)
+	}
+
+	retained, err := w.readRecords(segment, record)
+	if err != nil {
+		return fmt.Errorf("cannot read retained records of segment %d: %w", segment, err)
+	}
+
+	err = w.wal.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close WAL: %w", err)
+	}
+
+	_, last, err := w.Segments()
+	if err != nil {
+		return fmt.Errorf("cannot list segments: %w", err)
+	}
+	for s := segment; s <= last; s++ {
+		err = os.Remove(prometheusWAL.SegmentName(w.dir, s))
+		if err != nil {
+			return fmt.Errorf("cannot remove segment %d: %w", s, err)
+		}
+	}
+
+	checkpointer, err := w.NewCheckpointer()
+	if err != nil {
+		return fmt.Errorf("cannot create checkpointer: %w", err)
+	}
+	checkpoints, err := checkpointer.Checkpoints()
+	if err != nil {
+		return fmt.Errorf("cannot list checkpoints: %w", err)
+	}
+	for _, checkpoint := range checkpoints {
+		if checkpoint < segment {
+			continue
+		}
+		err = checkpointer.RemoveCheckpoint(checkpoint)
+		if err != nil {
+			return fmt.Errorf("cannot remove checkpoint %d: %w", checkpoint, err)
+		}
+	}
+
+	// the reopened WAL starts writing to the segment following the last retained one
+	w.wal, err = prometheusWAL.NewSize(w.log, nil, w.dir, w.segmentSize, false)
+	if err != nil {
+		return fmt.Errorf("cannot reopen WAL: %w", err)
+	}
+
+	for _, data := range retained {
+		_, err = w.wal.Log(data)
+		if err != nil {
+			return fmt.Errorf("cannot rewrite retained record: %w", err)
+		}
+	}
+
+	w.log.Info().
+		Int("segment", segment).
+		Int("retained_records", len(retained)).
+		Int("removed_segments", last-segment+1).
+		Msg("WAL truncated")
+
+	return nil
+}
+
+// readRecords reads the given number of records from the start of the segment.
+func (w *DiskWAL) readRecords(segment int, count int) ([][]byte, error) {
+	records := make([][]byte, 0, count)
+	if count == 0 {
+		return records, nil
+	}
+
+	sr, err := prometheusWAL.NewSegmentsRangeReader(prometheusWAL.SegmentRange{
+		Dir:   w.wal.Dir(),
+		First: segment,
+		Last:  segment,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create segment reader: %w", err)
+	}
+	defer sr.Close()
+
+	reader := prometheusWAL.NewReader(sr)
+	for len(records) < count && reader.Next() {
+		// the reader reuses its buffer for the next record
+		record := make([]byte, len(reader.Record()))
+		copy(record, reader.Record())
+		records = append(records, record)
+	}
+	if len(records) < count {
+		return nil, fmt.Errorf("segment has %d records, expected at least %d: %v", len(records), count, reader.Err())
+	}
+
+	return records, nil
+}
+
 func getPossibleCheckpoints(allCheckpoints []int, from, to int) []int {
 	// list of checkpoints is sorted
 	indexFrom := sort.SearchInts(allCheckpoints, from)
@@ -323,7 +512,11 @@ func (w *DiskWAL) Ready() <-chan struct{} {
 // Done implements interface module.ReadyDoneAware
 // it closes all the open write-ahead log files.
 func (w *DiskWAL) Done() <-chan struct{} {
-	err := w.wal.Close()
+	err := w.syncer.close()
+	if err != nil {
+		w.log.Err(err).Msg("error while syncing WAL")
+	}
+	err = w.wal.Close()
 	if err != nil {
 		w.log.Err(err).Msg("error while closing WAL")
 	}
@@ -1,5 +1,7 @@
 package ledger
 
+import "fmt"
+
 // ErrLedgerConstruction is returned upon a failure in ledger creation steps
 type ErrLedgerConstruction struct {
 	Err error
@@ -40,6 +42,48 @@ func (e ErrMissingKeys) Is(other error) bool {
 	return ok
 }
 
+// ErrInvalidProofForPath is returned when the proofs of some paths fail verification
+// this is mostly used when constructing a partial ledger
+type ErrInvalidProofForPath struct {
+	Paths []Path
+}
+
+func (e ErrInvalidProofForPath) Error() string {
+	str := "proofs failed verification for paths: \n"
+	for _, p := range e.Paths {
+		str += "\t" + p.String() + "\n"
+	}
+	return str
+}
+
+// Is returns true if the type of errors are the same
+func (e ErrInvalidProofForPath) Is(other error) bool {
+	_, ok := other.(ErrInvalidProofForPath)
+	return ok
+}
+
+// ErrQueryTooLarge is returned when a query exceeds the read limits of the ledger
+type ErrQueryTooLarge struct {
+	Limit   string
+	Size    int
+	Maximum int
+}
+
+func (e ErrQueryTooLarge) Error() string {
+	return fmt.Sprintf("query too large: %s (%d) exceeds the maximum (%d)", e.Limit, e.Size, e.Maximum)
+}
+
+// Is returns true if the type of errors are the same
+func (e ErrQueryTooLarge) Is(other error) bool {
+	_, ok := other.(ErrQueryTooLarge)
+	return ok
+}
+
+// NewErrQueryTooLarge constructs a new query too large error
+func NewErrQueryTooLarge(limit string, size int, maximum int) *ErrQueryTooLarge {
+	return &ErrQueryTooLarge{Limit: limit, Size: size, Maximum: maximum}
+}
+
 // TODO add more errors
 // ErrorFetchQuery
 // ErrorCommitChanges
@@ -2,6 +2,7 @@ package ledger
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -33,8 +34,52 @@ type Ledger interface {
 	Prove(query *Query) (proof Proof, err error)
 }
 
+// PendingUpdateLedger is a ledger which can derive the states and proofs resulting from consecutive
+// updates without applying them. This allows applying consecutive updates as a single update, while
+// still deriving the intermediate states between them.
+type PendingUpdateLedger interface {
+	Ledger
+
+	// NewPendingState returns the pending state of the given state of the ledger, on top of which
+	// updates can be derived without applying them
+	NewPendingState(state State) (PendingState, error)
+}
+
+// PendingState is a state derived from a state of the ledger by updates which are not applied to the
+// ledger. Pending states are immutable: each update derives a new pending state from the previous one.
+// The pending states are only known to their users until they are applied, they can't be read from the
+// ledger, and applying the same updates to the ledger with Set results in the same state.
+type PendingState interface {
+	// State returns the state
+	State() State
+
+	// Update returns the pending state resulting from the update, which must be on this state
+	Update(update *Update) (PendingState, error)
+
+	// Prove returns proofs for the keys of the query, which must be on this state
+	Prove(query *Query) (proof Proof, err error)
+
+	// Apply adds the pending state to the ledger, as a single update of the state it was derived from,
+	// and returns the state
+	Apply() (State, error)
+}
+
+// PinningLedger is a ledger which retains the states pinned by its users, while older states
+// are dropped as the ledger reaches its capacity.
+type PinningLedger interface {
+	Ledger
+
+	// Pin retains the given state until it is unpinned as often as it was pinned
+	Pin(state State) error
+
+	// Unpin releases a pin of the given state. The state might be retained until it can
+	// be recovered without replaying the updates which led to it.
+	Unpin(state State) error
+}
+
 // Query holds all data needed for a ledger read or ledger proof
 type Query struct {
+	ctx   context.Context
 	state State
 	keys  []Key
 }
@@ -69,6 +114,20 @@ func (q *Query) SetState(s State) {
 	q.state = s
 }
 
+// Context returns the context of the query, which cancels the query when done.
+// It defaults to the background context.
+func (q *Query) Context() context.Context {
+	if q.ctx == nil {
+		return context.Background()
+	}
+	return q.ctx
+}
+
+// SetContext sets the context of the query
+func (q *Query) SetContext(ctx context.Context) {
+	q.ctx = ctx
+}
+
 // Update holds all data needed for a ledger update
 type Update struct {
 	state  State
@@ -262,6 +321,24 @@ func (kp *KeyPart) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (kp *KeyPart) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Type  uint16
+		Value string
+	}
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	value, err := hex.DecodeString(v.Value)
+	if err != nil {
+		return fmt.Errorf("could not decode key part value: %w", err)
+	}
+	kp.Type = v.Type
+	kp.Value = value
+	return nil
+}
+
 // Value holds the value part of a ledger key value pair
 type Value []byte
 
@@ -293,6 +370,20 @@ func (v Value) MarshalJSON() ([]byte, error) {
 	return json.Marshal(hex.EncodeToString(v))
 }
 
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	value, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("could not decode value: %w", err)
+	}
+	*v = value
+	return nil
+}
+
 // Migration defines how to convert the given slice of input payloads into an slice of output payloads
 type Migration func(payloads []Payload) ([]Payload, error)
 
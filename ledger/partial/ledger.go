@@ -6,6 +6,7 @@ import (
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/common/proof"
 	"github.com/onflow/flow-go/ledger/partial/ptrie"
 )
 
@@ -17,31 +18,65 @@ const DefaultPathFinderVersion = 1
 type Ledger struct {
 	ptrie             *ptrie.PSMT
 	state             ledger.State
-	proof             ledger.Proof
+	proofs            []ledger.Proof
 	pathFinderVersion uint8
 }
 
-// NewLedger creates a new in-memory trie-backed ledger storage with persistence.
-func NewLedger(proof ledger.Proof, s ledger.State, pathFinderVer uint8) (*Ledger, error) {
+// NewLedger creates a new in-memory partial ledger from one or more batch proofs for the given state.
+//
+// Every proof is verified individually against the state. If some proofs fail verification,
+// the ledger is constructed from the valid proofs only, and is returned together with an
+// ErrInvalidProofForPath listing the paths whose proofs failed, so callers can tell exactly
+// which registers can't be trusted. If no proof is valid, no ledger is returned.
+func NewLedger(proofs []ledger.Proof, s ledger.State, pathFinderVer uint8) (*Ledger, error) {
 
-	// Decode proof encodings
-	if len(proof) < 1 {
+	if len(proofs) < 1 {
 		return nil, fmt.Errorf("at least a proof is needed to be able to contruct a partial trie")
 	}
-	batchProof, err := encoding.DecodeTrieBatchProof(proof)
-	if err != nil {
-		return nil, fmt.Errorf("decoding proof failed: %w", err)
+
+	// decode and verify proofs
+	validProofs := ledger.NewTrieBatchProof()
+	seen := make(map[ledger.Path]struct{})
+	var invalidPaths []ledger.Path
+	for i, encProof := range proofs {
+		if len(encProof) < 1 {
+			return nil, fmt.Errorf("proof at index %d is empty", i)
+		}
+		batchProof, err := encoding.DecodeTrieBatchProof(encProof)
+		if err != nil {
+			return nil, fmt.Errorf("decoding proof at index %d failed: %w", i, err)
+		}
+		for j, pr := range batchProof.Proofs {
+			if pr == nil {
+				return nil, fmt.Errorf("proof at index %d of batch proof at index %d is nil", j, i)
+			}
+			if _, ok := seen[pr.Path]; ok {
+				// paths can be proven by several batch proofs
+				continue
+			}
+			seen[pr.Path] = struct{}{}
+			if !proof.VerifyTrieProof(pr, s) {
+				invalidPaths = append(invalidPaths, pr.Path)
+				continue
+			}
+			validProofs.AppendProof(pr)
+		}
 	}
 
-	// decode proof
-	psmt, err := ptrie.NewPSMT(ledger.RootHash(s), batchProof)
+	var invalidErr error
+	if len(invalidPaths) > 0 {
+		invalidErr = &ledger.ErrInvalidProofForPath{Paths: invalidPaths}
+		if validProofs.Size() == 0 {
+			return nil, invalidErr
+		}
+	}
 
+	psmt, err := ptrie.NewPSMT(ledger.RootHash(s), validProofs)
 	if err != nil {
-		// TODO provide more details based on the error type
 		return nil, ledger.NewErrLedgerConstruction(err)
 	}
 
-	return &Ledger{ptrie: psmt, proof: proof, state: s, pathFinderVersion: pathFinderVer}, nil
+	return &Ledger{ptrie: psmt, proofs: proofs, state: s, pathFinderVersion: pathFinderVer}, invalidErr
 }
 
 // Ready implements interface module.ReadyDoneAware
@@ -10,6 +10,7 @@ import (
 	executionState "github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/ledger/common/utils"
 	"github.com/onflow/flow-go/ledger/complete"
 	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
@@ -38,7 +39,7 @@ func TestFunctionalityWithCompleteTrie(t *testing.T) {
 	proof, err := l.Prove(query)
 	require.NoError(t, err)
 
-	pled, err := partial.NewLedger(proof, newState, partial.DefaultPathFinderVersion)
+	pled, err := partial.NewLedger([]ledger.Proof{proof}, newState, partial.DefaultPathFinderVersion)
 	assert.NoError(t, err)
 	assert.Equal(t, pled.InitialState(), newState)
 
@@ -102,7 +103,7 @@ func TestProofsForEmptyRegisters(t *testing.T) {
 	proof, err := l.Prove(proofQuery)
 	require.NoError(t, err)
 
-	pled, err := partial.NewLedger(proof, newState, partial.DefaultPathFinderVersion)
+	pled, err := partial.NewLedger([]ledger.Proof{proof}, newState, partial.DefaultPathFinderVersion)
 	assert.NoError(t, err)
 	assert.Equal(t, pled.InitialState(), emptyState)
 
@@ -116,3 +117,74 @@ func TestProofsForEmptyRegisters(t *testing.T) {
 	require.Empty(t, results[0])
 
 }
+
+func TestMultipleProofsWithInvalidPaths(t *testing.T) {
+
+	l, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	state := l.InitialState()
+	keys := utils.RandomUniqueKeys(3, 2, 2, 4)
+	values := utils.RandomValues(3, 1, 32)
+	update, err := ledger.NewUpdate(state, keys, values)
+	require.NoError(t, err)
+
+	newState, err := l.Set(update)
+	require.NoError(t, err)
+
+	prove := func(keys []ledger.Key) ledger.Proof {
+		query, err := ledger.NewQuery(newState, keys)
+		require.NoError(t, err)
+		proof, err := l.Prove(query)
+		require.NoError(t, err)
+		return proof
+	}
+
+	t.Run("valid proofs", func(t *testing.T) {
+		pled, err := partial.NewLedger([]ledger.Proof{prove(keys[0:2]), prove(keys[1:3])}, newState, partial.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		query, err := ledger.NewQuery(newState, keys)
+		require.NoError(t, err)
+		retValues, err := pled.Get(query)
+		require.NoError(t, err)
+		require.Equal(t, values, retValues)
+	})
+
+	t.Run("invalid proof", func(t *testing.T) {
+		// tamper with the value of the third key
+		batchProof, err := encoding.DecodeTrieBatchProof(prove(keys[2:3]))
+		require.NoError(t, err)
+		batchProof.Proofs[0].Payload.Value = ledger.Value("tampered")
+		tampered := encoding.EncodeTrieBatchProof(batchProof)
+
+		pled, err := partial.NewLedger([]ledger.Proof{prove(keys[0:2]), tampered}, newState, partial.DefaultPathFinderVersion)
+		require.ErrorIs(t, err, ledger.ErrInvalidProofForPath{})
+
+		e, ok := err.(*ledger.ErrInvalidProofForPath)
+		require.True(t, ok)
+		require.Equal(t, []ledger.Path{batchProof.Proofs[0].Path}, e.Paths)
+
+		// registers with valid proofs are still available
+		query, err := ledger.NewQuery(newState, keys[0:2])
+		require.NoError(t, err)
+		retValues, err := pled.Get(query)
+		require.NoError(t, err)
+		require.Equal(t, values[0:2], retValues)
+
+		query, err = ledger.NewQuery(newState, keys[2:3])
+		require.NoError(t, err)
+		_, err = pled.Get(query)
+		require.ErrorIs(t, err, ledger.ErrMissingKeys{})
+	})
+
+	t.Run("only invalid proofs", func(t *testing.T) {
+		batchProof, err := encoding.DecodeTrieBatchProof(prove(keys[0:1]))
+		require.NoError(t, err)
+		batchProof.Proofs[0].Payload.Value = ledger.Value("tampered")
+
+		pled, err := partial.NewLedger([]ledger.Proof{encoding.EncodeTrieBatchProof(batchProof)}, newState, partial.DefaultPathFinderVersion)
+		require.ErrorIs(t, err, ledger.ErrInvalidProofForPath{})
+		require.Nil(t, pled)
+	})
+}
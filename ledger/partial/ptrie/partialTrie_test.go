@@ -1,6 +1,7 @@
 package ptrie
 
 import (
+	"context"
 	"math/rand"
 	"testing"
 	"time"
@@ -40,7 +41,7 @@ func TestPartialTrieEmptyTrie(t *testing.T) {
 
 		rootHash := f.GetEmptyRootHash()
 		r := &ledger.TrieRead{RootHash: rootHash, Paths: paths}
-		bp, err := f.Proofs(r)
+		bp, err := f.Proofs(context.Background(), r)
 		require.NoError(t, err, "error getting proofs values")
 
 		psmt, err := NewPSMT(rootHash, bp)
@@ -89,7 +90,7 @@ func TestPartialTrieLeafUpdates(t *testing.T) {
 		require.NoError(t, err, "error updating trie")
 
 		r := &ledger.TrieRead{RootHash: rootHash, Paths: paths}
-		bp, err := f.Proofs(r)
+		bp, err := f.Proofs(context.Background(), r)
 		require.NoError(t, err, "error getting batch proof")
 
 		psmt, err := NewPSMT(rootHash, bp)
@@ -128,7 +129,7 @@ func TestPartialTrieMiddleBranching(t *testing.T) {
 		payloads := []*ledger.Payload{payload1, payload2, payload3}
 
 		rootHash := f.GetEmptyRootHash()
-		bp, err := f.Proofs(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+		bp, err := f.Proofs(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
 		require.NoError(t, err, "error getting batch proof")
 
 		psmt, err := NewPSMT(rootHash, bp)
@@ -172,7 +173,7 @@ func TestPartialTrieRootUpdates(t *testing.T) {
 		payloads := []*ledger.Payload{payload1, payload2}
 
 		rootHash := f.GetEmptyRootHash()
-		bp, err := f.Proofs(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+		bp, err := f.Proofs(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
 		require.NoError(t, err, "error getting batch proof")
 
 		psmt, err := NewPSMT(rootHash, bp)
@@ -221,7 +222,7 @@ func TestMixProof(t *testing.T) {
 
 		paths = []ledger.Path{path1, path2, path3}
 
-		bp, err := f.Proofs(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+		bp, err := f.Proofs(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
 		require.NoError(t, err, "error getting batch proof")
 
 		psmt, err := NewPSMT(rootHash, bp)
@@ -271,7 +272,7 @@ func TestRandomProofs(t *testing.T) {
 				payloads[i], payloads[j] = payloads[j], payloads[i]
 			})
 
-			bp, err := f.Proofs(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+			bp, err := f.Proofs(context.Background(), &ledger.TrieRead{RootHash: rootHash, Paths: paths})
 			require.NoError(t, err, "error getting batch proof")
 
 			psmt, err := NewPSMT(rootHash, bp)
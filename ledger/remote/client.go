@@ -0,0 +1,142 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	remoteledger "github.com/onflow/flow-go/ledger/remote/protobuf"
+	grpcutils "github.com/onflow/flow-go/utils/grpc"
+)
+
+// TrieInfo holds information about the trie of a state of a remote ledger.
+type TrieInfo struct {
+	State         ledger.State
+	RegisterCount uint64
+	MaxDepth      uint16
+	Pinned        bool
+}
+
+// Client is a read-only ledger, which queries the ledger of an execution node over gRPC.
+type Client struct {
+	rpcClient remoteledger.LedgerAPIClient
+	close     func() error
+}
+
+var _ ledger.Ledger = (*Client)(nil)
+
+// NewClient creates a client for the ledger served at the given address.
+func NewClient(addr string) (*Client, error) {
+
+	conn, err := grpc.Dial(addr,
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(grpcutils.DefaultMaxMsgSize)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		rpcClient: remoteledger.NewLedgerAPIClient(conn),
+		close:     func() error { return conn.Close() },
+	}, nil
+}
+
+// Ready implements interface module.ReadyDoneAware
+func (c *Client) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
+// Done implements interface module.ReadyDoneAware
+// it closes the connection to the remote ledger.
+func (c *Client) Done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		_ = c.close()
+		close(done)
+	}()
+	return done
+}
+
+// InitialState returns the state of an empty ledger
+func (c *Client) InitialState() ledger.State {
+	return ledger.State(trie.EmptyTrieRootHash())
+}
+
+// Get reads the values of the given keys at the given state from the remote ledger.
+func (c *Client) Get(query *ledger.Query) ([]ledger.Value, error) {
+	state := query.State()
+	resp, err := c.rpcClient.Read(query.Context(), &remoteledger.ReadRequest{
+		State: state[:],
+		Keys:  encodeKeys(query.Keys()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read values from remote ledger: %w", err)
+	}
+
+	if len(resp.GetValues()) != query.Size() {
+		return nil, fmt.Errorf("remote ledger returned %d values for %d keys", len(resp.GetValues()), query.Size())
+	}
+
+	values := make([]ledger.Value, len(resp.GetValues()))
+	for i, value := range resp.GetValues() {
+		values[i] = value
+	}
+	return values, nil
+}
+
+// Set always fails, as the remote ledger is read-only.
+func (c *Client) Set(*ledger.Update) (ledger.State, error) {
+	return ledger.State(hash.DummyHash), complete.ErrReadOnly
+}
+
+// Prove returns proofs for the given keys at the given state from the remote ledger.
+func (c *Client) Prove(query *ledger.Query) (ledger.Proof, error) {
+	state := query.State()
+	resp, err := c.rpcClient.Prove(query.Context(), &remoteledger.ProveRequest{
+		State: state[:],
+		Keys:  encodeKeys(query.Keys()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get proofs from remote ledger: %w", err)
+	}
+	return ledger.Proof(resp.GetProof()), nil
+}
+
+// TrieInfo returns information about the trie of the given state in the remote ledger.
+func (c *Client) TrieInfo(ctx context.Context, state ledger.State) (*TrieInfo, error) {
+	resp, err := c.rpcClient.GetTrieInfo(ctx, &remoteledger.GetTrieInfoRequest{
+		State: state[:],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get trie info from remote ledger: %w", err)
+	}
+
+	respState, err := ledger.ToState(resp.GetState())
+	if err != nil {
+		return nil, fmt.Errorf("remote ledger returned invalid state: %w", err)
+	}
+
+	return &TrieInfo{
+		State:         respState,
+		RegisterCount: resp.GetRegisterCount(),
+		MaxDepth:      uint16(resp.GetMaxDepth()),
+		Pinned:        resp.GetPinned(),
+	}, nil
+}
+
+func encodeKeys(keys []ledger.Key) [][]byte {
+	encoded := make([][]byte, len(keys))
+	for i := range keys {
+		encoded[i] = encoding.EncodeKey(&keys[i])
+	}
+	return encoded
+}
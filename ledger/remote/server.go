@@ -0,0 +1,188 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/complete"
+	remoteledger "github.com/onflow/flow-go/ledger/remote/protobuf"
+	grpcutils "github.com/onflow/flow-go/utils/grpc"
+)
+
+// Config defines the configurable options for the gRPC server.
+type Config struct {
+	ListenAddr string
+	MaxMsgSize int // In bytes
+}
+
+// Server serves read-only queries and proofs of a ledger over gRPC.
+type Server struct {
+	unit    *engine.Unit
+	log     zerolog.Logger
+	handler *handler     // the gRPC service implementation
+	server  *grpc.Server // the gRPC server
+	config  Config
+}
+
+// NewServer returns a new server for the given ledger.
+func NewServer(log zerolog.Logger, l *complete.Ledger, config Config) *Server {
+	if config.MaxMsgSize == 0 {
+		config.MaxMsgSize = grpcutils.DefaultMaxMsgSize
+	}
+
+	s := &Server{
+		unit: engine.NewUnit(),
+		log:  log.With().Str("component", "ledger_rpc").Logger(),
+		handler: &handler{
+			ledger: l,
+		},
+		server: grpc.NewServer(
+			grpc.MaxRecvMsgSize(config.MaxMsgSize),
+			grpc.MaxSendMsgSize(config.MaxMsgSize),
+		),
+		config: config,
+	}
+
+	remoteledger.RegisterLedgerAPIServer(s.server, s.handler)
+
+	return s
+}
+
+// Ready returns a ready channel that is closed once the server has started.
+func (s *Server) Ready() <-chan struct{} {
+	s.unit.Launch(s.serve)
+	return s.unit.Ready()
+}
+
+// Done returns a done channel that is closed once the server has stopped.
+func (s *Server) Done() <-chan struct{} {
+	return s.unit.Done(s.server.GracefulStop)
+}
+
+// serve starts the gRPC server.
+func (s *Server) serve() {
+	s.log.Info().Msgf("starting server on address %s", s.config.ListenAddr)
+
+	l, err := net.Listen("tcp", s.config.ListenAddr)
+	if err != nil {
+		s.log.Err(err).Msg("failed to start server")
+		return
+	}
+
+	err = s.server.Serve(l)
+	if err != nil {
+		s.log.Err(err).Msg("fatal error in server")
+	}
+}
+
+// handler implements the gRPC API of the ledger.
+type handler struct {
+	ledger *complete.Ledger
+}
+
+func (h *handler) Read(ctx context.Context, req *remoteledger.ReadRequest) (*remoteledger.ReadResponse, error) {
+	query, err := decodeQuery(ctx, req.GetState(), req.GetKeys())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	values, err := h.ledger.Get(query)
+	if err != nil {
+		return nil, statusError(fmt.Errorf("could not read values: %w", err))
+	}
+
+	encoded := make([][]byte, len(values))
+	for i, value := range values {
+		encoded[i] = value
+	}
+
+	return &remoteledger.ReadResponse{
+		Values: encoded,
+	}, nil
+}
+
+func (h *handler) Prove(ctx context.Context, req *remoteledger.ProveRequest) (*remoteledger.ProveResponse, error) {
+	query, err := decodeQuery(ctx, req.GetState(), req.GetKeys())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	proof, err := h.ledger.Prove(query)
+	if err != nil {
+		return nil, statusError(fmt.Errorf("could not prove values: %w", err))
+	}
+
+	return &remoteledger.ProveResponse{
+		Proof: proof,
+	}, nil
+}
+
+func (h *handler) GetTrieInfo(_ context.Context, req *remoteledger.GetTrieInfoRequest) (*remoteledger.GetTrieInfoResponse, error) {
+	state, err := ledger.ToState(req.GetState())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	forest := h.ledger.Forest()
+	trie, err := forest.GetTrie(ledger.RootHash(state))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &remoteledger.GetTrieInfoResponse{
+		State:         state[:],
+		RegisterCount: trie.AllocatedRegCount(),
+		MaxDepth:      uint32(trie.MaxDepth()),
+		Pinned:        forest.IsPinned(ledger.RootHash(state)),
+	}, nil
+}
+
+// decodeQuery constructs a query of the ledger from the encoded state and keys,
+// which is cancelled with the context of the request.
+func decodeQuery(ctx context.Context, encodedState []byte, encodedKeys [][]byte) (*ledger.Query, error) {
+	state, err := ledger.ToState(encodedState)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state: %w", err)
+	}
+
+	keys := make([]ledger.Key, len(encodedKeys))
+	for i, encodedKey := range encodedKeys {
+		key, err := encoding.DecodeKey(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key at index %d: %w", i, err)
+		}
+		keys[i] = *key
+	}
+
+	query, err := ledger.NewQuery(state, keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	query.SetContext(ctx)
+
+	return query, nil
+}
+
+// statusError converts errors of the ledger to gRPC status errors.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, ledger.ErrQueryTooLarge{}):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,70 @@
+package remote
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/proof"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	remoteledger "github.com/onflow/flow-go/ledger/remote/protobuf"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+func TestHandler(t *testing.T) {
+	l, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+	h := &handler{ledger: l}
+
+	update := utils.UpdateFixture()
+	update.SetState(l.InitialState())
+	state, err := l.Set(update)
+	require.NoError(t, err)
+
+	keys := encodeKeys(update.Keys())
+
+	t.Run("read", func(t *testing.T) {
+		resp, err := h.Read(context.Background(), &remoteledger.ReadRequest{State: state[:], Keys: keys})
+		require.NoError(t, err)
+		require.Len(t, resp.Values, len(update.Values()))
+		for i, value := range update.Values() {
+			require.Equal(t, []byte(value), resp.Values[i])
+		}
+	})
+
+	t.Run("prove", func(t *testing.T) {
+		resp, err := h.Prove(context.Background(), &remoteledger.ProveRequest{State: state[:], Keys: keys})
+		require.NoError(t, err)
+
+		batchProof, err := encoding.DecodeTrieBatchProof(resp.Proof)
+		require.NoError(t, err)
+		require.True(t, proof.VerifyTrieBatchProof(batchProof, state))
+	})
+
+	t.Run("trie info", func(t *testing.T) {
+		resp, err := h.GetTrieInfo(context.Background(), &remoteledger.GetTrieInfoRequest{State: state[:]})
+		require.NoError(t, err)
+		require.Equal(t, state[:], resp.State)
+		require.Equal(t, uint64(len(keys)), resp.RegisterCount)
+		require.False(t, resp.Pinned)
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		unknown := ledger.State{1, 2, 3}
+		_, err := h.GetTrieInfo(context.Background(), &remoteledger.GetTrieInfoRequest{State: unknown[:]})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("invalid state", func(t *testing.T) {
+		_, err := h.Read(context.Background(), &remoteledger.ReadRequest{State: []byte{1}, Keys: keys})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -200,7 +200,9 @@ func ToPath(pathBytes []byte) (Path, error) {
 	return path, nil
 }
 
-// Payload is the smallest immutable storable unit in ledger
+// Payload is the smallest immutable storable unit in ledger.
+// Payloads stored in tries are shared with readers instead of copied, so a payload, its key
+// and its value must never be modified once created. Use DeepCopy to get a modifiable copy.
 type Payload struct {
 	Key   Key
 	Value Value
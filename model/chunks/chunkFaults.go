@@ -111,3 +111,92 @@ func NewCFInvalidVerifiableChunk(reason string, err error, chInx uint64, execRes
 		chunkIndex: chInx,
 		execResID:  execResID}
 }
+
+// CFInvalidEventsCollection is returned when the hash of the events emitted by the transactions
+// of the chunk doesn't match the event collection hash provided by the chunk
+type CFInvalidEventsCollection struct {
+	expected   flow.Identifier
+	computed   flow.Identifier
+	chunkIndex uint64
+	execResID  flow.Identifier
+}
+
+func (cf CFInvalidEventsCollection) String() string {
+	return fmt.Sprintf("events collection hash doesn't match, expected [%x] but computed [%x]", cf.expected, cf.computed)
+}
+
+// ChunkIndex returns chunk index of the faulty chunk
+func (cf CFInvalidEventsCollection) ChunkIndex() uint64 {
+	return cf.chunkIndex
+}
+
+// ExecutionResultID returns the execution result identifier including the faulty chunk
+func (cf CFInvalidEventsCollection) ExecutionResultID() flow.Identifier {
+	return cf.execResID
+}
+
+// NewCFInvalidEventsCollection creates a new instance of Chunk Fault (InvalidEventsCollection)
+func NewCFInvalidEventsCollection(expected flow.Identifier, computed flow.Identifier, chInx uint64, execResID flow.Identifier) *CFInvalidEventsCollection {
+	return &CFInvalidEventsCollection{expected: expected,
+		computed:   computed,
+		chunkIndex: chInx,
+		execResID:  execResID}
+}
+
+// CFInvalidServiceEvents is returned when the service events claimed by the execution result
+// don't match the service events emitted by the transactions of the chunk
+type CFInvalidServiceEvents struct {
+	reason     string
+	chunkIndex uint64
+	execResID  flow.Identifier
+}
+
+func (cf CFInvalidServiceEvents) String() string {
+	return fmt.Sprint("service events don't match the ones emitted by the chunk due to ", cf.reason)
+}
+
+// ChunkIndex returns chunk index of the faulty chunk
+func (cf CFInvalidServiceEvents) ChunkIndex() uint64 {
+	return cf.chunkIndex
+}
+
+// ExecutionResultID returns the execution result identifier including the faulty chunk
+func (cf CFInvalidServiceEvents) ExecutionResultID() flow.Identifier {
+	return cf.execResID
+}
+
+// NewCFInvalidServiceEvents creates a new instance of Chunk Fault (InvalidServiceEvents)
+func NewCFInvalidServiceEvents(reason string, chInx uint64, execResID flow.Identifier) *CFInvalidServiceEvents {
+	return &CFInvalidServiceEvents{reason: reason,
+		chunkIndex: chInx,
+		execResID:  execResID}
+}
+
+// CFInvalidChunkMetadata is returned when the number of transactions or the computation used
+// provided by the chunk don't match the outcome of executing the transactions of the chunk
+type CFInvalidChunkMetadata struct {
+	reason     string
+	chunkIndex uint64
+	execResID  flow.Identifier
+}
+
+func (cf CFInvalidChunkMetadata) String() string {
+	return fmt.Sprint("chunk metadata doesn't match the execution of the chunk due to ", cf.reason)
+}
+
+// ChunkIndex returns chunk index of the faulty chunk
+func (cf CFInvalidChunkMetadata) ChunkIndex() uint64 {
+	return cf.chunkIndex
+}
+
+// ExecutionResultID returns the execution result identifier including the faulty chunk
+func (cf CFInvalidChunkMetadata) ExecutionResultID() flow.Identifier {
+	return cf.execResID
+}
+
+// NewCFInvalidChunkMetadata creates a new instance of Chunk Fault (InvalidChunkMetadata)
+func NewCFInvalidChunkMetadata(reason string, chInx uint64, execResID flow.Identifier) *CFInvalidChunkMetadata {
+	return &CFInvalidChunkMetadata{reason: reason,
+		chunkIndex: chInx,
+		execResID:  execResID}
+}
@@ -0,0 +1,58 @@
+package chunks
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TransactionReport holds the outcome of executing a single transaction of a chunk
+// during its verification.
+type TransactionReport struct {
+	TransactionID   flow.Identifier
+	Failed          bool
+	ErrorMessage    string // empty unless the transaction failed
+	ComputationUsed uint64
+	RegisterTouches int // number of registers read or written by the transaction
+	RegisterUpdates int // number of registers written by the transaction
+	Duration        time.Duration
+}
+
+// ChunkVerificationReport holds the outcome of verifying a chunk, broken down per
+// transaction of the chunk. It is produced whenever the transactions of the chunk have
+// been executed, regardless of whether the chunk is found to be faulty afterwards.
+type ChunkVerificationReport struct {
+	ChunkIndex   uint64
+	ResultID     flow.Identifier
+	Transactions []TransactionReport
+	Duration     time.Duration // time spent verifying the chunk, including its transactions
+}
+
+// FailedTransactions returns the number of transactions of the chunk that failed.
+func (r *ChunkVerificationReport) FailedTransactions() int {
+	failed := 0
+	for _, tx := range r.Transactions {
+		if tx.Failed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// ComputationUsed returns the computation used by all transactions of the chunk.
+func (r *ChunkVerificationReport) ComputationUsed() uint64 {
+	computation := uint64(0)
+	for _, tx := range r.Transactions {
+		computation += tx.ComputationUsed
+	}
+	return computation
+}
+
+// RegisterTouches returns the number of register touches of all transactions of the chunk.
+func (r *ChunkVerificationReport) RegisterTouches() int {
+	touches := 0
+	for _, tx := range r.Transactions {
+		touches += tx.RegisterTouches
+	}
+	return touches
+}
@@ -68,6 +68,35 @@ func (a *Assignment) Len() int {
 	return len(a.verifiersForChunk)
 }
 
+// AssignmentExplanation is the chunk assignment of an execution result along with the inputs of
+// the Public Chunk Assignment algorithm it is computed from, so that the assignment can be audited.
+type AssignmentExplanation struct {
+	ResultID  flow.Identifier
+	BlockID   flow.Identifier     // block whose source of randomness seeds the assignment
+	Seed      []byte              // seed of the random generator the verifiers are sampled with
+	Alpha     uint                // number of verifiers assigned to each chunk
+	Verifiers flow.IdentifierList // staked verification nodes the chunks are assigned among, in canonical order
+	Chunks    []ChunkVerifiers    // verifiers assigned to each chunk, ordered by chunk index
+}
+
+// ChunkVerifiers is the list of verifiers assigned to a chunk.
+type ChunkVerifiers struct {
+	ChunkIndex uint64
+	ChunkID    flow.Identifier
+	Verifiers  flow.IdentifierList // sorted by identifier
+}
+
+// ChunksOf returns the indices of the chunks assigned to the given verifier, in increasing order.
+func (e *AssignmentExplanation) ChunksOf(verifierID flow.Identifier) []uint64 {
+	var chunks []uint64
+	for _, chunk := range e.Chunks {
+		if chunk.Verifiers.Contains(verifierID) {
+			chunks = append(chunks, chunk.ChunkIndex)
+		}
+	}
+	return chunks
+}
+
 // AssignmentDataPack
 //
 // AssignmentDataPack provides a storable representation of chunk assignments on
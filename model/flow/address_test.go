@@ -316,3 +316,26 @@ func TestUint48(t *testing.T) {
 		assert.Equal(t, r, res)
 	}
 }
+
+func TestChainID(t *testing.T) {
+	for _, chainID := range ChainIDs() {
+		assert.Equal(t, chainID, chainID.Chain().ChainID())
+	}
+}
+
+func TestChainIDValidate(t *testing.T) {
+	for _, chainID := range ChainIDs() {
+		assert.NoError(t, chainID.Validate(), chainID.String())
+	}
+
+	assert.Error(t, ChainID("flow-unknown").Validate())
+	assert.Error(t, ChainID("").Validate())
+
+	// an address generator not generating the service address of the chain is detected
+	expected := serviceAddresses[Emulator]
+	defer func() {
+		serviceAddresses[Emulator] = expected
+	}()
+	serviceAddresses[Emulator] = Testnet.Chain().ServiceAddress()
+	assert.Error(t, Emulator.Validate())
+}
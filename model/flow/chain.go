@@ -2,6 +2,7 @@ package flow
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -178,6 +179,78 @@ func (c ChainID) String() string {
 	return string(c)
 }
 
+// ChainIDs returns the IDs of all chains supported by this build.
+func ChainIDs() []ChainID {
+	return []ChainID{Mainnet, Testnet, Emulator, MonotonicEmulator}
+}
+
+// Validate checks that the chain ID is supported by this build, and that the address generator of
+// the chain generates the addresses the chain was bootstrapped with. Nodes run this check against
+// the chain ID of their protocol state on startup, so that they don't generate wrong addresses
+// when pointed at the state of another chain.
+func (c ChainID) Validate() error {
+	supported := false
+	for _, chainID := range ChainIDs() {
+		if chainID == c {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("chain ID [%s] is not supported by this build", c)
+	}
+
+	// the first address generated for the chain is its service address, which must match the
+	// service address the chain was bootstrapped with
+	chain := c.Chain()
+	address, err := chain.NewAddressGenerator().NextAddress()
+	if err != nil {
+		return fmt.Errorf("could not generate address for chain [%s]: %w", c, err)
+	}
+	expected := serviceAddresses[c]
+	if address != expected {
+		return fmt.Errorf("address generator of chain [%s] generates service address %s, expected %s", c, address, expected)
+	}
+	if !chain.IsValid(address) {
+		return fmt.Errorf("service address %s has an invalid format for chain [%s]", address, c)
+	}
+	return nil
+}
+
+// serviceAddresses are the service addresses of the supported chains. Addresses are part of the
+// state of a chain, so they must never change for an existing chain.
+var serviceAddresses = map[ChainID]Address{
+	Mainnet:           HexToAddress("e467b9dd11fa00df"),
+	Testnet:           HexToAddress("8c5303eaa26202d6"),
+	Emulator:          HexToAddress("f8d6e0586b0a20c7"),
+	MonotonicEmulator: HexToAddress("0000000000000001"),
+}
+
+// MaxCollectionByteSize returns the protocol-level maximum for the declared byte size of a
+// guaranteed collection on the chain. Consensus nodes reject payloads with guarantees for larger
+// collections, as execution nodes might not be able to handle them. The maximum must never be
+// below DefaultMaxCollectionByteSize, the limit collection nodes build collections with.
+func (c ChainID) MaxCollectionByteSize() uint64 {
+	switch c {
+	case Mainnet:
+		return mainnetMaxCollectionByteSize
+	case Testnet:
+		return testnetMaxCollectionByteSize
+	case Emulator, MonotonicEmulator:
+		return emulatorMaxCollectionByteSize
+	default:
+		return DefaultMaxCollectionByteSize
+	}
+}
+
+// BlockTimestampTolerance returns the protocol-level duration a block timestamp may be ahead of
+// the local clock of a consensus node validating the block. It bounds how far proposers can skew
+// the timestamps of their blocks into the future, while tolerating the clock skew between nodes.
+// All supported chains currently use DefaultBlockTimestampTolerance.
+func (c ChainID) BlockTimestampTolerance() time.Duration {
+	return DefaultBlockTimestampTolerance
+}
+
 // Chain is the interface for address generation implementations.
 type Chain interface {
 	NewAddressGenerator() AddressGenerator
@@ -187,6 +260,7 @@ type Chain interface {
 	IsValid(Address) bool
 	IndexFromAddress(address Address) (uint64, error)
 	String() string
+	ChainID() ChainID
 	// required for tests
 	zeroAddress() Address
 	newAddressGeneratorAtIndex(index uint64) AddressGenerator
@@ -231,3 +305,8 @@ func (id *addressedChain) BytesToAddressGenerator(b []byte) AddressGenerator {
 func (id *addressedChain) String() string {
 	return string(id.chain())
 }
+
+// ChainID returns the ID of the chain.
+func (id *addressedChain) ChainID() ChainID {
+	return id.chain()
+}
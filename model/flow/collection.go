@@ -29,10 +29,21 @@ func (c Collection) Light() LightCollection {
 // Guarantee returns a collection guarantee for this collection.
 func (c *Collection) Guarantee() CollectionGuarantee {
 	return CollectionGuarantee{
-		CollectionID: c.ID(),
+		CollectionID:     c.ID(),
+		TransactionCount: uint(c.Len()),
+		ByteSize:         c.ByteSize(),
 	}
 }
 
+// ByteSize returns the total byte size of the transactions of the collection.
+func (c Collection) ByteSize() uint64 {
+	var size uint64
+	for _, tx := range c.Transactions {
+		size += uint64(tx.ByteSize())
+	}
+	return size
+}
+
 func (c Collection) ID() Identifier {
 	return c.Light().ID()
 }
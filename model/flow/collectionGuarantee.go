@@ -8,18 +8,58 @@ import (
 
 // CollectionGuarantee is a signed hash for a collection, which is used
 // to announce collections to consensus nodes.
+//
+// The guarantee declares the number of transactions and the byte size of the
+// collection, so that consensus nodes can budget payloads and execution nodes
+// can prepare for the collection before fetching it. The metadata is derived
+// from the collection the cluster voted on. It is covered by the payload hash
+// of the blocks including the guarantee, so it can't be changed once the
+// guarantee is included, and execution nodes don't accept collections that
+// don't match the metadata declared for them.
 type CollectionGuarantee struct {
 	CollectionID     Identifier       // ID of the collection being guaranteed
 	ReferenceBlockID Identifier       // defines expiry of the collection
+	TransactionCount uint             // declared number of transactions in the collection
+	ByteSize         uint64           // declared byte size of the collection's transactions
 	SignerIDs        []Identifier     // list of guarantors
 	Signature        crypto.Signature // guarantor signatures
 }
 
-// ID returns the fingerprint of the collection guarantee.
+// ID returns the fingerprint of the collection guarantee, which is the ID of the
+// guaranteed collection. It doesn't cover the declared metadata of the collection,
+// see PayloadID for the identifier covering it.
 func (cg *CollectionGuarantee) ID() Identifier {
 	return cg.CollectionID
 }
 
+// PayloadID returns the identifier of the guarantee in the payload hash of blocks.
+// It covers the declared metadata of the collection besides the collection ID, so
+// that the block ID, and thereby the votes for the block, commit to the metadata.
+// Guarantees without metadata, which were included before the metadata was declared,
+// are identified by the collection ID, so that the IDs of their blocks are kept.
+func (cg *CollectionGuarantee) PayloadID() Identifier {
+	if cg.IsLegacy() {
+		return cg.CollectionID
+	}
+
+	body := struct {
+		CollectionID     Identifier
+		TransactionCount uint
+		ByteSize         uint64
+	}{
+		CollectionID:     cg.CollectionID,
+		TransactionCount: cg.TransactionCount,
+		ByteSize:         cg.ByteSize,
+	}
+	return MakeID(body)
+}
+
+// IsLegacy returns true if the guarantee doesn't declare the metadata of its collection,
+// which is the case for guarantees included before the metadata was declared.
+func (cg *CollectionGuarantee) IsLegacy() bool {
+	return cg.TransactionCount == 0 && cg.ByteSize == 0
+}
+
 // Checksum returns a checksum of the collection guarantee including the
 // signatures.
 func (cg *CollectionGuarantee) Checksum() Identifier {
@@ -12,6 +12,24 @@ var GenesisTime = time.Date(2018, time.December, 19, 22, 32, 30, 42, time.UTC)
 // in blocks. Equivalent to 10 minutes for a 1-second block time.
 const DefaultTransactionExpiry = 10 * 60
 
+// ExpiryLimit returns the lowest height of a reference block which is not expired at the given
+// height, with the given expiry in blocks. Transactions and collections with a reference block
+// below it can't be included anymore, so it bounds how far back builders look for duplicates.
+func ExpiryLimit(height uint64, expiry uint64) uint64 {
+	if height < expiry {
+		return 0
+	}
+	return height - expiry
+}
+
+// IsExpired returns whether a transaction or a collection with a reference block at refHeight is
+// expired at the given height, with the given expiry in blocks. The collection and consensus
+// builders, and the validation of transactions and guarantees, all share it, so that their
+// expiry rules can't drift apart. A reference block above the given height is never expired.
+func IsExpired(refHeight uint64, height uint64, expiry uint64) bool {
+	return refHeight < ExpiryLimit(height, expiry)
+}
+
 // DefaultTransactionExpiryBuffer is the default buffer time between a transaction being ingested by a
 // collection node and being included in a collection and block.
 const DefaultTransactionExpiryBuffer = 30
@@ -25,6 +43,24 @@ const DefaultMaxTransactionByteSize = 1_500_000
 // DefaultMaxCollectionByteSize is the default maximum value for a collection byte size.
 const DefaultMaxCollectionByteSize = 3_000_000 // ~3MB. This is should always be higher than the limit on single tx size.
 
+// Maximum collection byte sizes enforced by consensus per chain. Testnet allows for larger
+// collections than mainnet, so that execution of larger collections is exercised there before
+// the mainnet maximum is raised. Emulator chains execute on a single machine and are used to
+// test transactions close to the size limits. All maximums must stay well below the maximum
+// size of unicast network messages, with which collections are requested.
+const (
+	mainnetMaxCollectionByteSize  = DefaultMaxCollectionByteSize
+	testnetMaxCollectionByteSize  = 4_500_000 // ~4.5MB
+	emulatorMaxCollectionByteSize = 6_000_000 // ~6MB
+)
+
+// DefaultMaxPayloadByteSize is the default maximum value for the byte size of a block payload.
+const DefaultMaxPayloadByteSize = 4_000_000 // ~4MB. This should always be lower than the max size of broadcast network messages.
+
+// DefaultBlockTimestampTolerance is the default duration a block timestamp may be ahead of the
+// local clock of a consensus node, before the node rejects the block as being from the future.
+const DefaultBlockTimestampTolerance = 10 * time.Second
+
 // DefaultMaxCollectionTotalGas is the default maximum value for total gas allowed to be included in a collection.
 const DefaultMaxCollectionTotalGas = 10_000_000 // 10M
 
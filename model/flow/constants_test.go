@@ -8,7 +8,28 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 )
 
+func TestExpiry(t *testing.T) {
+	assert.Equal(t, uint64(0), flow.ExpiryLimit(5, 10))
+	assert.Equal(t, uint64(0), flow.ExpiryLimit(10, 10))
+	assert.Equal(t, uint64(90), flow.ExpiryLimit(100, 10))
+
+	assert.False(t, flow.IsExpired(0, 5, 10))
+	assert.False(t, flow.IsExpired(90, 100, 10))
+	assert.True(t, flow.IsExpired(89, 100, 10))
+	assert.False(t, flow.IsExpired(110, 100, 10))
+}
+
 func TestDomainTags(t *testing.T) {
 	assert.Len(t, flow.TransactionDomainTag, flow.DomainTagLength)
 	assert.Len(t, flow.UserDomainTag, flow.DomainTagLength)
 }
+
+func TestMaxCollectionByteSize(t *testing.T) {
+	assert.Equal(t, uint64(flow.DefaultMaxCollectionByteSize), flow.Mainnet.MaxCollectionByteSize())
+
+	// collection nodes build collections up to the default size, consensus must accept them
+	for _, chainID := range flow.ChainIDs() {
+		assert.GreaterOrEqual(t, chainID.MaxCollectionByteSize(), uint64(flow.DefaultMaxCollectionByteSize), chainID.String())
+	}
+	assert.Greater(t, flow.Emulator.MaxCollectionByteSize(), flow.Mainnet.MaxCollectionByteSize())
+}
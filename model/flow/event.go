@@ -12,10 +12,11 @@ import (
 
 // List of built-in event types.
 const (
-	EventAccountCreated EventType = "flow.AccountCreated"
-	EventAccountUpdated EventType = "flow.AccountUpdated"
-	EventEpochSetup     EventType = "flow.EpochSetup"
-	EventEpochCommit    EventType = "flow.EpochCommit"
+	EventAccountCreated           EventType = "flow.AccountCreated"
+	EventAccountUpdated           EventType = "flow.AccountUpdated"
+	EventEpochSetup               EventType = "flow.EpochSetup"
+	EventEpochCommit              EventType = "flow.EpochCommit"
+	EventSystemContractCallFailed EventType = "flow.SystemContractCallFailed"
 )
 
 type EventType string
@@ -73,6 +74,39 @@ func wrapEvent(e Event) eventWrapper {
 	}
 }
 
+// EventsList is a list of events, such as the events emitted by the transactions of a chunk.
+type EventsList []Event
+
+// Hash returns the hash committing to the events of the list, in order. Unlike the
+// identifiers of the events, it covers their types and payloads, so it changes with any
+// change in the content of the events.
+func (el EventsList) Hash() Identifier {
+	ids := make([]Identifier, 0, len(el))
+	for i, event := range el {
+		ids = append(ids, MakeID(eventContent{
+			Position:         uint32(i),
+			Type:             string(event.Type),
+			TxID:             event.TransactionID[:],
+			TransactionIndex: event.TransactionIndex,
+			EventIndex:       event.EventIndex,
+			Payload:          event.Payload,
+		}))
+	}
+	return MerkleRoot(ids...)
+}
+
+// eventContent holds the full content of an event, as committed to by the hash of an
+// events list. The position of the event in the list is included, as the merkle root of
+// the identifiers doesn't depend on their order.
+type eventContent struct {
+	Position         uint32
+	Type             string
+	TxID             []byte
+	TransactionIndex uint32
+	EventIndex       uint32
+	Payload          []byte
+}
+
 // BlockEvents contains events emitted in a single block.
 type BlockEvents struct {
 	BlockID        Identifier
@@ -30,3 +30,23 @@ func TestEventFingerprint(t *testing.T) {
 	rlp.NewEncoder().MustDecode(data, &decoded)
 	assert.Equal(t, wrapEvent(evt), decoded)
 }
+
+// TestEventsListHash verifies that the hash of an events list commits to the order and
+// the full content of its events.
+func TestEventsListHash(t *testing.T) {
+	txID := unittest.IdentifierFixture()
+	first := unittest.EventFixture(flow.EventAccountCreated, 1, 0, txID)
+	second := unittest.EventFixture(flow.EventAccountCreated, 1, 1, txID)
+	list := flow.EventsList{first, second}
+
+	assert.Equal(t, list.Hash(), flow.EventsList{first, second}.Hash())
+	assert.NotEqual(t, list.Hash(), flow.EventsList{second, first}.Hash())
+	assert.NotEqual(t, list.Hash(), flow.EventsList{first}.Hash())
+	assert.NotEqual(t, list.Hash(), flow.EventsList{}.Hash())
+
+	// events with the same identifier, but a different payload
+	modified := second
+	modified.Payload = []byte("modified payload")
+	assert.Equal(t, second.ID(), modified.ID())
+	assert.NotEqual(t, list.Hash(), flow.EventsList{first, modified}.Hash())
+}
@@ -0,0 +1,41 @@
+package flow
+
+import (
+	"sort"
+)
+
+// ExecutionMetadata describes the configuration of the virtual machine an execution node
+// executed a block with. Coordinated changes of the execution behaviour within a spork are
+// rolled out as new versions or feature flags, and verification nodes use the metadata of
+// the receipts to re-execute chunks with the same configuration.
+type ExecutionMetadata struct {
+	Version   uint32     // version of the execution behaviour
+	FlagsHash Identifier // hash of the enabled feature flags, as computed by FeatureFlagsHash
+}
+
+// IsLegacy returns true if the metadata is empty, which is the case for receipts of execution
+// nodes not reporting their configuration.
+func (m ExecutionMetadata) IsLegacy() bool {
+	return m == ExecutionMetadata{}
+}
+
+// FeatureFlagsHash returns the hash of the given set of feature flags. The hash neither depends
+// on the order nor on the multiplicity of the flags, and is the ZeroID for an empty set, so that
+// nodes running without any feature flags report legacy metadata.
+func FeatureFlagsHash(flags []string) Identifier {
+	if len(flags) == 0 {
+		return ZeroID
+	}
+
+	unique := make(map[string]struct{}, len(flags))
+	for _, flag := range flags {
+		unique[flag] = struct{}{}
+	}
+	sorted := make([]string, 0, len(unique))
+	for flag := range unique {
+		sorted = append(sorted, flag)
+	}
+	sort.Strings(sorted)
+
+	return MakeID(sorted)
+}
@@ -12,6 +12,7 @@ type ExecutionReceipt struct {
 	ExecutorID        Identifier
 	ExecutionResult   ExecutionResult
 	Spocks            []crypto.Signature
+	ExecutionMetadata ExecutionMetadata
 	ExecutorSignature crypto.Signature
 }
 
@@ -31,6 +32,7 @@ func (er *ExecutionReceipt) Meta() *ExecutionReceiptMeta {
 		ExecutorID:        er.ExecutorID,
 		ResultID:          er.ExecutionResult.ID(),
 		Spocks:            er.Spocks,
+		ExecutionMetadata: er.ExecutionMetadata,
 		ExecutorSignature: er.ExecutorSignature,
 	}
 }
@@ -44,6 +46,7 @@ type ExecutionReceiptMeta struct {
 	ExecutorID        Identifier
 	ResultID          Identifier
 	Spocks            []crypto.Signature
+	ExecutionMetadata ExecutionMetadata
 	ExecutorSignature crypto.Signature
 }
 
@@ -52,21 +55,39 @@ func ExecutionReceiptFromMeta(meta ExecutionReceiptMeta, result ExecutionResult)
 		ExecutorID:        meta.ExecutorID,
 		ExecutionResult:   result,
 		Spocks:            meta.Spocks,
+		ExecutionMetadata: meta.ExecutionMetadata,
 		ExecutorSignature: meta.ExecutorSignature,
 	}
 }
 
 // ID returns the canonical ID of the execution receipt.
-// It is identical to the ID of the full receipt.
+// It is identical to the ID of the full receipt. As the execution metadata is part of
+// the ID, it is covered by the signature of the executor. Receipts with legacy metadata
+// keep the ID they had before the metadata was introduced.
 func (er *ExecutionReceiptMeta) ID() Identifier {
+	if er.ExecutionMetadata.IsLegacy() {
+		body := struct {
+			ExecutorID Identifier
+			ResultID   Identifier
+			Spocks     []crypto.Signature
+		}{
+			ExecutorID: er.ExecutorID,
+			ResultID:   er.ResultID,
+			Spocks:     er.Spocks,
+		}
+		return MakeID(body)
+	}
+
 	body := struct {
-		ExecutorID Identifier
-		ResultID   Identifier
-		Spocks     []crypto.Signature
+		ExecutorID        Identifier
+		ResultID          Identifier
+		Spocks            []crypto.Signature
+		ExecutionMetadata ExecutionMetadata
 	}{
-		ExecutorID: er.ExecutorID,
-		ResultID:   er.ResultID,
-		Spocks:     er.Spocks,
+		ExecutorID:        er.ExecutorID,
+		ResultID:          er.ResultID,
+		Spocks:            er.Spocks,
+		ExecutionMetadata: er.ExecutionMetadata,
 	}
 	return MakeID(body)
 }
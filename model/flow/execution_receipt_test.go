@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -74,3 +76,53 @@ func TestExecutionReceiptMetaGroupBy(t *testing.T) {
 	unknown := groups.GetGroup(unittest.IdentifierFixture())
 	assert.Equal(t, 0, unknown.Size())
 }
+
+// TestExecutionReceiptMetadata verifies that the execution metadata is part of the receipt ID,
+// and preserved when splitting a receipt into its meta data and result.
+func TestExecutionReceiptMetadata(t *testing.T) {
+	receipt := unittest.ExecutionReceiptFixture()
+	legacyID := receipt.ID()
+
+	receipt.ExecutionMetadata = flow.ExecutionMetadata{
+		Version:   1,
+		FlagsHash: flow.FeatureFlagsHash([]string{"flag"}),
+	}
+	assert.NotEqual(t, legacyID, receipt.ID())
+	assert.Equal(t, receipt.ID(), receipt.Meta().ID())
+
+	restored := flow.ExecutionReceiptFromMeta(*receipt.Meta(), receipt.ExecutionResult)
+	assert.Equal(t, receipt.ExecutionMetadata, restored.ExecutionMetadata)
+	assert.Equal(t, receipt.ID(), restored.ID())
+}
+
+// TestExecutionReceiptLegacyID verifies that receipts with legacy metadata keep the ID of receipts
+// without metadata, which only covers the executor, the result and the SPoCKs.
+func TestExecutionReceiptLegacyID(t *testing.T) {
+	receipt := unittest.ExecutionReceiptFixture()
+	require.True(t, receipt.ExecutionMetadata.IsLegacy())
+
+	body := struct {
+		ExecutorID flow.Identifier
+		ResultID   flow.Identifier
+		Spocks     []crypto.Signature
+	}{
+		ExecutorID: receipt.ExecutorID,
+		ResultID:   receipt.ExecutionResult.ID(),
+		Spocks:     receipt.Spocks,
+	}
+	assert.Equal(t, flow.MakeID(body), receipt.ID())
+	assert.Equal(t, flow.MakeID(body), receipt.Meta().ID())
+}
+
+// TestFeatureFlagsHash verifies that the hash of feature flags neither depends on the order nor
+// on the multiplicity of the flags, and that no flags hash to the legacy metadata.
+func TestFeatureFlagsHash(t *testing.T) {
+	assert.Equal(t, flow.ZeroID, flow.FeatureFlagsHash(nil))
+	assert.True(t, flow.ExecutionMetadata{FlagsHash: flow.FeatureFlagsHash(nil)}.IsLegacy())
+
+	hash := flow.FeatureFlagsHash([]string{"a", "b"})
+	assert.NotEqual(t, flow.ZeroID, hash)
+	assert.Equal(t, hash, flow.FeatureFlagsHash([]string{"b", "a"}))
+	assert.Equal(t, hash, flow.FeatureFlagsHash([]string{"b", "a", "b"}))
+	assert.NotEqual(t, hash, flow.FeatureFlagsHash([]string{"a"}))
+}
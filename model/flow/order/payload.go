@@ -0,0 +1,105 @@
+package order
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// CanonicalGuarantee represents the canonical ordering for collection guarantees.
+var CanonicalGuarantee = GuaranteeByCollectionIDAsc
+
+// CanonicalSeal represents the canonical ordering for seals.
+var CanonicalSeal = SealByBlockIDAsc
+
+// CanonicalReceipt represents the canonical ordering for execution receipts.
+var CanonicalReceipt = ReceiptByResultIDAsc
+
+// CanonicalResult represents the canonical ordering for execution results.
+var CanonicalResult = ResultByBlockIDAsc
+
+// GuaranteeByCollectionIDAsc orders guarantees by the ID of their collection.
+func GuaranteeByCollectionIDAsc(guarantee1 *flow.CollectionGuarantee, guarantee2 *flow.CollectionGuarantee) bool {
+	return compareIDs(guarantee1.CollectionID, guarantee2.CollectionID) < 0
+}
+
+// SealByBlockIDAsc orders seals by the ID of the sealed block, and seals for the same block by their ID.
+func SealByBlockIDAsc(seal1 *flow.Seal, seal2 *flow.Seal) bool {
+	return compareIDs(seal1.BlockID, seal2.BlockID, seal1.ID(), seal2.ID()) < 0
+}
+
+// ReceiptByResultIDAsc orders receipts by the ID of their result, receipts for the same result by the
+// ID of their executor, and receipts of the same executor by their ID.
+func ReceiptByResultIDAsc(receipt1 *flow.ExecutionReceiptMeta, receipt2 *flow.ExecutionReceiptMeta) bool {
+	return compareIDs(
+		receipt1.ResultID, receipt2.ResultID,
+		receipt1.ExecutorID, receipt2.ExecutorID,
+		receipt1.ID(), receipt2.ID(),
+	) < 0
+}
+
+// ResultByBlockIDAsc orders results by the ID of the executed block, and results for the same block by their ID.
+func ResultByBlockIDAsc(result1 *flow.ExecutionResult, result2 *flow.ExecutionResult) bool {
+	return compareIDs(result1.BlockID, result2.BlockID, result1.ID(), result2.ID()) < 0
+}
+
+// compareIDs compares pairs of identifiers in turn, until a pair differs.
+func compareIDs(pairs ...flow.Identifier) int {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		c := bytes.Compare(pairs[i][:], pairs[i+1][:])
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// SortGuarantees returns a copy of the guarantees in canonical order.
+func SortGuarantees(guarantees []*flow.CollectionGuarantee) []*flow.CollectionGuarantee {
+	dup := append([]*flow.CollectionGuarantee(nil), guarantees...)
+	sort.SliceStable(dup, func(i int, j int) bool {
+		return CanonicalGuarantee(dup[i], dup[j])
+	})
+	return dup
+}
+
+// SortSeals returns a copy of the seals in canonical order.
+func SortSeals(seals []*flow.Seal) []*flow.Seal {
+	dup := append([]*flow.Seal(nil), seals...)
+	sort.SliceStable(dup, func(i int, j int) bool {
+		return CanonicalSeal(dup[i], dup[j])
+	})
+	return dup
+}
+
+// SortReceipts returns a copy of the receipts in canonical order.
+func SortReceipts(receipts flow.ExecutionReceiptMetaList) flow.ExecutionReceiptMetaList {
+	dup := append(flow.ExecutionReceiptMetaList(nil), receipts...)
+	sort.SliceStable(dup, func(i int, j int) bool {
+		return CanonicalReceipt(dup[i], dup[j])
+	})
+	return dup
+}
+
+// SortResults returns a copy of the results in canonical order.
+func SortResults(results flow.ExecutionResultList) flow.ExecutionResultList {
+	dup := append(flow.ExecutionResultList(nil), results...)
+	sort.SliceStable(dup, func(i int, j int) bool {
+		return CanonicalResult(dup[i], dup[j])
+	})
+	return dup
+}
+
+// CanonicalPayload returns a copy of the payload with all entities in canonical order, so that
+// payloads holding the same entities in different orders are equal.
+// CAUTION: the order of the entities is part of the payload hash, so the hash of the returned
+// payload generally differs from the hash of the given payload.
+func CanonicalPayload(payload *flow.Payload) *flow.Payload {
+	return &flow.Payload{
+		Guarantees: SortGuarantees(payload.Guarantees),
+		Seals:      SortSeals(payload.Seals),
+		Receipts:   SortReceipts(payload.Receipts),
+		Results:    SortResults(payload.Results),
+	}
+}
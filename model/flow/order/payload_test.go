@@ -0,0 +1,80 @@
+package order_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestCanonicalPayload(t *testing.T) {
+	result := unittest.ExecutionResultFixture()
+	receipts := []*flow.ExecutionReceipt{
+		unittest.ExecutionReceiptFixture(unittest.WithResult(result)),
+		unittest.ExecutionReceiptFixture(unittest.WithResult(result)),
+		unittest.ExecutionReceiptFixture(),
+		unittest.ExecutionReceiptFixture(),
+	}
+
+	payload, err := flow.NewPayloadBuilder().
+		WithGuarantees(unittest.CollectionGuaranteesFixture(5)...).
+		WithSeals(unittest.Seal.Fixtures(5)...).
+		WithReceipts(receipts...).
+		Build()
+	require.NoError(t, err)
+
+	shuffled := &flow.Payload{
+		Guarantees: append([]*flow.CollectionGuarantee(nil), payload.Guarantees...),
+		Seals:      append([]*flow.Seal(nil), payload.Seals...),
+		Receipts:   append(flow.ExecutionReceiptMetaList(nil), payload.Receipts...),
+		Results:    append(flow.ExecutionResultList(nil), payload.Results...),
+	}
+	rand.Shuffle(len(shuffled.Guarantees), func(i, j int) {
+		shuffled.Guarantees[i], shuffled.Guarantees[j] = shuffled.Guarantees[j], shuffled.Guarantees[i]
+	})
+	rand.Shuffle(len(shuffled.Seals), func(i, j int) {
+		shuffled.Seals[i], shuffled.Seals[j] = shuffled.Seals[j], shuffled.Seals[i]
+	})
+	rand.Shuffle(len(shuffled.Receipts), func(i, j int) {
+		shuffled.Receipts[i], shuffled.Receipts[j] = shuffled.Receipts[j], shuffled.Receipts[i]
+	})
+	rand.Shuffle(len(shuffled.Results), func(i, j int) {
+		shuffled.Results[i], shuffled.Results[j] = shuffled.Results[j], shuffled.Results[i]
+	})
+
+	canonical := order.CanonicalPayload(payload)
+	assert.Equal(t, canonical, order.CanonicalPayload(shuffled))
+
+	assert.True(t, sort.SliceIsSorted(canonical.Guarantees, func(i, j int) bool {
+		return order.CanonicalGuarantee(canonical.Guarantees[i], canonical.Guarantees[j])
+	}))
+	assert.True(t, sort.SliceIsSorted(canonical.Seals, func(i, j int) bool {
+		return order.CanonicalSeal(canonical.Seals[i], canonical.Seals[j])
+	}))
+	assert.True(t, sort.SliceIsSorted(canonical.Receipts, func(i, j int) bool {
+		return order.CanonicalReceipt(canonical.Receipts[i], canonical.Receipts[j])
+	}))
+	assert.True(t, sort.SliceIsSorted(canonical.Results, func(i, j int) bool {
+		return order.CanonicalResult(canonical.Results[i], canonical.Results[j])
+	}))
+
+	// receipts for the same result are ordered by executor
+	var sameResult []*flow.ExecutionReceiptMeta
+	for _, receipt := range canonical.Receipts {
+		if receipt.ResultID == result.ID() {
+			sameResult = append(sameResult, receipt)
+		}
+	}
+	require.Len(t, sameResult, 2)
+	assert.True(t, order.CanonicalReceipt(sameResult[0], sameResult[1]))
+
+	// sorting doesn't modify the given payload
+	assert.Equal(t, len(payload.Guarantees), len(canonical.Guarantees))
+	assert.NotSame(t, &payload.Guarantees[0], &canonical.Guarantees[0])
+}
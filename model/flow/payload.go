@@ -40,13 +40,22 @@ func (p *Payload) MarshalJSON() ([]byte, error) {
 
 // Hash returns the root hash of the payload.
 func (p Payload) Hash() Identifier {
-	collHash := MerkleRoot(GetIDs(p.Guarantees)...)
+	collHash := MerkleRoot(guaranteePayloadIDs(p.Guarantees)...)
 	sealHash := MerkleRoot(GetIDs(p.Seals)...)
 	recHash := MerkleRoot(GetIDs(p.Receipts)...)
 	resHash := MerkleRoot(GetIDs(p.Results)...)
 	return ConcatSum(collHash, sealHash, recHash, resHash)
 }
 
+// guaranteePayloadIDs returns the identifiers of the guarantees in the payload hash.
+func guaranteePayloadIDs(guarantees []*CollectionGuarantee) []Identifier {
+	ids := make([]Identifier, 0, len(guarantees))
+	for _, guarantee := range guarantees {
+		ids = append(ids, guarantee.PayloadID())
+	}
+	return ids
+}
+
 // Index returns the index for the payload.
 func (p Payload) Index() *Index {
 	idx := &Index{
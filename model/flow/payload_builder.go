@@ -0,0 +1,182 @@
+package flow
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PayloadBuilder constructs block payloads and validates their internal consistency
+// when they are built, e.g.:
+//   payload, err := flow.NewPayloadBuilder().
+//       WithGuarantees(guarantees...).
+//       WithReceipts(receipts...).
+//       Build()
+type PayloadBuilder struct {
+	payload Payload
+	results map[Identifier]struct{} // IDs of the results added to the payload
+}
+
+// NewPayloadBuilder creates a builder for an empty payload.
+func NewPayloadBuilder() *PayloadBuilder {
+	return &PayloadBuilder{
+		payload: EmptyPayload(),
+		results: make(map[Identifier]struct{}),
+	}
+}
+
+// WithGuarantees adds the collection guarantees to the payload.
+func (b *PayloadBuilder) WithGuarantees(guarantees ...*CollectionGuarantee) *PayloadBuilder {
+	b.payload.Guarantees = append(b.payload.Guarantees, guarantees...)
+	return b
+}
+
+// WithSeals adds the seals to the payload.
+func (b *PayloadBuilder) WithSeals(seals ...*Seal) *PayloadBuilder {
+	b.payload.Seals = append(b.payload.Seals, seals...)
+	return b
+}
+
+// WithReceipts adds the receipts to the payload, together with their results.
+// Results shared by several receipts are only added once.
+func (b *PayloadBuilder) WithReceipts(receipts ...*ExecutionReceipt) *PayloadBuilder {
+	for _, receipt := range receipts {
+		if receipt == nil {
+			b.payload.Receipts = append(b.payload.Receipts, nil)
+			continue
+		}
+		b.payload.Receipts = append(b.payload.Receipts, receipt.Meta())
+		b.WithResults(&receipt.ExecutionResult)
+	}
+	return b
+}
+
+// WithReceiptMetas adds the receipts to the payload, without their results. The
+// results must either be added with WithResults, or be included in an ancestor block.
+func (b *PayloadBuilder) WithReceiptMetas(receipts ...*ExecutionReceiptMeta) *PayloadBuilder {
+	b.payload.Receipts = append(b.payload.Receipts, receipts...)
+	return b
+}
+
+// WithResults adds the execution results to the payload, unless they were added already.
+func (b *PayloadBuilder) WithResults(results ...*ExecutionResult) *PayloadBuilder {
+	for _, result := range results {
+		if result == nil {
+			b.payload.Results = append(b.payload.Results, nil)
+			continue
+		}
+		resultID := result.ID()
+		if _, ok := b.results[resultID]; ok {
+			continue
+		}
+		b.results[resultID] = struct{}{}
+		b.payload.Results = append(b.payload.Results, result)
+	}
+	return b
+}
+
+// Build validates the payload and returns it. A payload is valid if:
+//   * it contains no nil entities
+//   * it contains no duplicated guarantees, seals, receipts or results, so that the payload
+//     index holds each entity once
+//   * every execution result is committed to by at least one receipt of the payload
+func (b *PayloadBuilder) Build() (*Payload, error) {
+	payload := b.payload
+
+	err := checkEntities("guarantee", payload.Guarantees)
+	if err != nil {
+		return nil, err
+	}
+	err = checkEntities("seal", payload.Seals)
+	if err != nil {
+		return nil, err
+	}
+	err = checkEntities("receipt", payload.Receipts)
+	if err != nil {
+		return nil, err
+	}
+	err = checkEntities("result", payload.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	committed := make(map[Identifier]struct{}, len(payload.Receipts))
+	for _, receipt := range payload.Receipts {
+		committed[receipt.ResultID] = struct{}{}
+	}
+	for _, result := range payload.Results {
+		resultID := result.ID()
+		if _, ok := committed[resultID]; !ok {
+			return nil, fmt.Errorf("result (%x) is not committed to by any receipt of the payload", resultID)
+		}
+	}
+
+	return &payload, nil
+}
+
+// checkEntities checks that none of the entities of the given slice is nil or duplicated.
+func checkEntities(kind string, entities interface{}) error {
+	v := reflect.ValueOf(entities)
+	ids := make(map[Identifier]struct{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		if v.Index(i).IsNil() {
+			return fmt.Errorf("nil %s at index %d", kind, i)
+		}
+		id := v.Index(i).Interface().(Entity).ID()
+		if _, ok := ids[id]; ok {
+			return fmt.Errorf("duplicated %s (%x)", kind, id)
+		}
+		ids[id] = struct{}{}
+	}
+	return nil
+}
+
+// BlockBuilder constructs blocks from a header and a payload built with a PayloadBuilder,
+// and sets the payload hash of the header, e.g.:
+//   block, err := flow.NewBlockBuilder().
+//       WithHeader(header).
+//       WithPayload(flow.NewPayloadBuilder().WithSeals(seals...)).
+//       Build()
+type BlockBuilder struct {
+	header  *Header
+	payload *PayloadBuilder
+}
+
+// NewBlockBuilder creates a builder for a block with an empty payload.
+func NewBlockBuilder() *BlockBuilder {
+	return &BlockBuilder{
+		payload: NewPayloadBuilder(),
+	}
+}
+
+// WithHeader sets the header of the block. The payload hash of the header is
+// overwritten when the block is built.
+func (b *BlockBuilder) WithHeader(header *Header) *BlockBuilder {
+	b.header = header
+	return b
+}
+
+// WithPayload sets the builder of the payload of the block.
+func (b *BlockBuilder) WithPayload(payload *PayloadBuilder) *BlockBuilder {
+	b.payload = payload
+	return b
+}
+
+// Build validates the payload, and returns the block with the payload hash set in its header.
+func (b *BlockBuilder) Build() (*Block, error) {
+	if b.header == nil {
+		return nil, fmt.Errorf("missing block header")
+	}
+
+	payload, err := b.payload.Build()
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	header := *b.header
+	header.PayloadHash = payload.Hash()
+
+	return &Block{
+		Header:  &header,
+		Payload: payload,
+	}, nil
+}
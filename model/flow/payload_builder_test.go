@@ -0,0 +1,98 @@
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestPayloadBuilder(t *testing.T) {
+	t.Run("valid payload", func(t *testing.T) {
+		guarantees := unittest.CollectionGuaranteesFixture(3)
+		seals := unittest.Seal.Fixtures(2)
+		result := unittest.ExecutionResultFixture()
+		receipt1 := unittest.ExecutionReceiptFixture(unittest.WithResult(result))
+		receipt2 := unittest.ExecutionReceiptFixture(unittest.WithResult(result))
+
+		payload, err := flow.NewPayloadBuilder().
+			WithGuarantees(guarantees...).
+			WithSeals(seals...).
+			WithReceipts(receipt1, receipt2).
+			Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, guarantees, payload.Guarantees)
+		assert.Equal(t, seals, payload.Seals)
+		assert.Equal(t, flow.ExecutionReceiptMetaList{receipt1.Meta(), receipt2.Meta()}, payload.Receipts)
+		// the result shared by both receipts is included once
+		require.Len(t, payload.Results, 1)
+		assert.Equal(t, result.ID(), payload.Results[0].ID())
+	})
+
+	t.Run("receipts for results of ancestors", func(t *testing.T) {
+		receipt := unittest.ExecutionReceiptFixture()
+
+		payload, err := flow.NewPayloadBuilder().
+			WithReceiptMetas(receipt.Meta()).
+			Build()
+		require.NoError(t, err)
+		assert.Empty(t, payload.Results)
+	})
+
+	t.Run("duplicated entities", func(t *testing.T) {
+		guarantee := unittest.CollectionGuaranteeFixture()
+		_, err := flow.NewPayloadBuilder().WithGuarantees(guarantee, guarantee).Build()
+		assert.Error(t, err)
+
+		seal := unittest.Seal.Fixture()
+		_, err = flow.NewPayloadBuilder().WithSeals(seal, seal).Build()
+		assert.Error(t, err)
+
+		receipt := unittest.ExecutionReceiptFixture()
+		_, err = flow.NewPayloadBuilder().WithReceipts(receipt, receipt).Build()
+		assert.Error(t, err)
+	})
+
+	t.Run("nil entities", func(t *testing.T) {
+		_, err := flow.NewPayloadBuilder().WithSeals(nil).Build()
+		assert.Error(t, err)
+
+		_, err = flow.NewPayloadBuilder().WithReceipts(nil).Build()
+		assert.Error(t, err)
+	})
+
+	t.Run("result without receipt", func(t *testing.T) {
+		_, err := flow.NewPayloadBuilder().
+			WithReceipts(unittest.ExecutionReceiptFixture()).
+			WithResults(unittest.ExecutionResultFixture()).
+			Build()
+		assert.Error(t, err)
+	})
+}
+
+func TestBlockBuilder(t *testing.T) {
+	header := unittest.BlockHeaderFixture()
+
+	block, err := flow.NewBlockBuilder().
+		WithHeader(&header).
+		WithPayload(flow.NewPayloadBuilder().WithSeals(unittest.Seal.Fixture())).
+		Build()
+	require.NoError(t, err)
+	assert.True(t, block.Valid())
+	assert.Equal(t, block.Payload.Hash(), block.Header.PayloadHash)
+
+	// invalid payloads are rejected
+	_, err = flow.NewBlockBuilder().
+		WithHeader(&header).
+		WithPayload(flow.NewPayloadBuilder().WithSeals(nil)).
+		Build()
+	assert.Error(t, err)
+
+	// the header is required
+	_, err = flow.NewBlockBuilder().Build()
+	assert.Error(t, err)
+}
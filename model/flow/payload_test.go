@@ -37,3 +37,38 @@ func TestPayloadEncodingMsgpack(t *testing.T) {
 	assert.Equal(t, payloadHash, decodedHash)
 	assert.Equal(t, payload, decoded)
 }
+
+// TestPayloadHashGuaranteeMetadata verifies that the payload hash covers the declared metadata of the
+// collections, and that the hash of payloads with guarantees without metadata only covers the collection IDs.
+func TestPayloadHashGuaranteeMetadata(t *testing.T) {
+	guarantee := unittest.CollectionGuaranteeFixture()
+	payload := flow.Payload{Guarantees: []*flow.CollectionGuarantee{guarantee}}
+	hash := payload.Hash()
+
+	t.Run("covers the transaction count", func(t *testing.T) {
+		changed := *guarantee
+		changed.TransactionCount++
+		assert.NotEqual(t, hash, flow.Payload{Guarantees: []*flow.CollectionGuarantee{&changed}}.Hash())
+	})
+
+	t.Run("covers the byte size", func(t *testing.T) {
+		changed := *guarantee
+		changed.ByteSize++
+		assert.NotEqual(t, hash, flow.Payload{Guarantees: []*flow.CollectionGuarantee{&changed}}.Hash())
+	})
+
+	t.Run("keeps the hash of guarantees without metadata", func(t *testing.T) {
+		legacy := *guarantee
+		legacy.TransactionCount = 0
+		legacy.ByteSize = 0
+		assert.Equal(t, legacy.CollectionID, legacy.PayloadID())
+
+		expected := flow.ConcatSum(
+			flow.MerkleRoot(legacy.CollectionID),
+			flow.MerkleRoot(),
+			flow.MerkleRoot(),
+			flow.MerkleRoot(),
+		)
+		assert.Equal(t, expected, flow.Payload{Guarantees: []*flow.CollectionGuarantee{&legacy}}.Hash())
+	})
+}
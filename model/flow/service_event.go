@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/vmihailenco/msgpack/v4"
 )
 
 const (
-	ServiceEventSetup  = "setup"
-	ServiceEventCommit = "commit"
+	ServiceEventSetup                    = "setup"
+	ServiceEventCommit                   = "commit"
+	ServiceEventSystemContractCallFailed = "system_contract_call_failed"
 )
 
 // ConvertServiceEvent converts a service event encoded as the generic
@@ -17,9 +20,44 @@ const (
 // and protocol state. This acts as the conversion from the Cadence type to
 // the flow-go type.
 //
-// TODO implement once Cadence types are defined
+// TODO implement epoch events once Cadence types are defined
 func ConvertServiceEvent(event Event) (*ServiceEvent, error) {
-	return nil, fmt.Errorf("ConvertServiceEvent not implemented")
+	switch event.Type {
+	case EventSystemContractCallFailed:
+		return convertSystemContractCallFailed(event)
+	default:
+		return nil, fmt.Errorf("ConvertServiceEvent not implemented for event type %s", event.Type)
+	}
+}
+
+// convertSystemContractCallFailed converts a flow.SystemContractCallFailed event, which has
+// the name of the failed call and the error message as fields.
+func convertSystemContractCallFailed(event Event) (*ServiceEvent, error) {
+	value, err := jsoncdc.Decode(event.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode event payload: %w", err)
+	}
+
+	cdcEvent, ok := value.(cadence.Event)
+	if !ok || len(cdcEvent.Fields) != 2 {
+		return nil, fmt.Errorf("invalid event payload: %s", value)
+	}
+
+	call, ok := cdcEvent.Fields[0].(cadence.String)
+	if !ok {
+		return nil, fmt.Errorf("invalid call name: %s", cdcEvent.Fields[0])
+	}
+	message, ok := cdcEvent.Fields[1].(cadence.String)
+	if !ok {
+		return nil, fmt.Errorf("invalid error message: %s", cdcEvent.Fields[1])
+	}
+
+	failed := &SystemContractCallFailed{
+		Call:  string(call),
+		Error: string(message),
+	}
+	serviceEvent := failed.ServiceEvent()
+	return &serviceEvent, nil
 }
 
 // ServiceEvent represents a service event, which is a special event that when
@@ -73,6 +111,13 @@ func (se *ServiceEvent) UnmarshalJSON(b []byte) error {
 			return err
 		}
 		event = commit
+	case ServiceEventSystemContractCallFailed:
+		failed := new(SystemContractCallFailed)
+		err = json.Unmarshal(evb, failed)
+		if err != nil {
+			return err
+		}
+		event = failed
 	default:
 		return fmt.Errorf("invalid type: %s", tp)
 	}
@@ -123,6 +168,13 @@ func (se *ServiceEvent) UnmarshalMsgpack(b []byte) error {
 			return err
 		}
 		event = commit
+	case ServiceEventSystemContractCallFailed:
+		failed := new(SystemContractCallFailed)
+		err = msgpack.Unmarshal(evb, failed)
+		if err != nil {
+			return err
+		}
+		event = failed
 	default:
 		return fmt.Errorf("invalid type: %s", tp)
 	}
@@ -119,3 +119,35 @@ func TestEncodeDecode(t *testing.T) {
 		})
 	})
 }
+
+func TestEncodeDecodeSystemContractCallFailed(t *testing.T) {
+
+	failed := &flow.SystemContractCallFailed{
+		Call:  "EpochHeartbeat",
+		Error: "execution reverted",
+	}
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(failed.ServiceEvent())
+		require.Nil(t, err)
+
+		outer := new(flow.ServiceEvent)
+		err = json.Unmarshal(b, outer)
+		require.Nil(t, err)
+		gotFailed, ok := outer.Event.(*flow.SystemContractCallFailed)
+		require.True(t, ok)
+		assert.DeepEqual(t, failed, gotFailed)
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		b, err := msgpack.Marshal(failed.ServiceEvent())
+		require.Nil(t, err)
+
+		outer := new(flow.ServiceEvent)
+		err = msgpack.Unmarshal(b, outer)
+		require.Nil(t, err)
+		gotFailed, ok := outer.Event.(*flow.SystemContractCallFailed)
+		require.True(t, ok)
+		assert.DeepEqual(t, failed, gotFailed)
+	})
+}
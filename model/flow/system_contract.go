@@ -0,0 +1,16 @@
+package flow
+
+// SystemContractCallFailed is a service event reporting that a service contract call
+// made by the system chunk failed. The call is isolated from the other calls of the
+// system chunk, so the event only reports the failure and doesn't change the protocol state.
+type SystemContractCallFailed struct {
+	Call  string // the name of the failed call
+	Error string // the error message of the failed call
+}
+
+func (failed *SystemContractCallFailed) ServiceEvent() ServiceEvent {
+	return ServiceEvent{
+		Type:  ServiceEventSystemContractCallFailed,
+		Event: failed,
+	}
+}
@@ -5,6 +5,13 @@ var (
 	ProtocolConsensusLeaderSelection = []uint32{0, 1, 1}
 	// ProtocolVerificationChunkAssignment is the indices for verification nodes determines chunk assignment
 	ProtocolVerificationChunkAssignment = []uint32{0, 2, 0}
+	// ProtocolExecutionRandomSource is the indices for the source of randomness provided to transactions during execution
+	ProtocolExecutionRandomSource = []uint32{0, 3, 0}
+	// ProtocolExecutionTransactionRandomSource is the indices for the source of randomness of a single transaction,
+	// derived from the source of randomness provided to the transactions of its block
+	ProtocolExecutionTransactionRandomSource = []uint32{0, 3, 1}
+	// ProtocolRequesterSampling is the indices for the selection of providers by the requester engine
+	ProtocolRequesterSampling = []uint32{0, 4, 0}
 )
 
 // ProtocolCollectorClusterLeaderSelection returns the indices for the leader selection for the i-th collector cluster
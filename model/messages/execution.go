@@ -13,6 +13,18 @@ type ChunkDataRequest struct {
 	Nonce   uint64 // so that we aren't deduplicated by the network layer
 }
 
+// MaxChunkDataBatchSize is the maximum number of chunk IDs in a batch request for chunk
+// data packs that execution nodes answer.
+const MaxChunkDataBatchSize = 100
+
+// ChunkDataBatchRequest represents a request for the chunk data packs of multiple
+// chunks, which are specified by their chunk IDs. Each of the chunk data packs is
+// answered with its own ChunkDataResponse.
+type ChunkDataBatchRequest struct {
+	ChunkIDs []flow.Identifier
+	Nonce    uint64 // so that we aren't deduplicated by the network layer
+}
+
 // ChunkDataResponse is the response to a chunk data pack request.
 // It contains the chunk data pack of the interest.
 type ChunkDataResponse struct {
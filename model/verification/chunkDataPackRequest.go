@@ -42,3 +42,36 @@ func (c ChunkDataPackRequest) SampleTargets(count int) flow.IdentifierList {
 	nonResponders := c.Targets.Filter(filter.Not(filter.HasNodeID(c.Disagrees...))).Sample(need).NodeIDs()
 	return append(c.Agrees, nonResponders...)
 }
+
+// SampleTargetsExcluding returns identifier of execution nodes that can be asked for the chunk data pack, similar
+// to SampleTargets, but it prefers the execution nodes that are not in the excluded list, e.g., the nodes that have
+// been asked before without responding. Excluded nodes are only sampled if there are not enough other nodes to pick from.
+func (c ChunkDataPackRequest) SampleTargetsExcluding(count int, excluded flow.IdentifierList) flow.IdentifierList {
+	agrees := c.Targets.Filter(filter.HasNodeID(c.Agrees...))
+	others := c.Targets.Filter(filter.And(
+		filter.Not(filter.HasNodeID(c.Agrees...)),
+		filter.Not(filter.HasNodeID(c.Disagrees...)),
+	))
+
+	// as with SampleTargets, all agree nodes are asked if there are not enough of them, and the rest of the
+	// targets are backups picked from the nodes we haven't received a receipt from.
+	fromAgrees := count
+	if len(agrees) < count {
+		fromAgrees = len(agrees)
+	}
+
+	targets := sampleExcluding(agrees, uint(fromAgrees), excluded)
+	return append(targets, sampleExcluding(others, uint(count-len(targets)), excluded)...)
+}
+
+// sampleExcluding samples size many node identifiers from the identities, and only picks excluded identities if
+// there are not enough others.
+func sampleExcluding(identities flow.IdentityList, size uint, excluded flow.IdentifierList) flow.IdentifierList {
+	fresh := identities.Filter(filter.Not(filter.HasNodeID(excluded...))).Sample(size).NodeIDs()
+	if uint(len(fresh)) >= size {
+		return fresh
+	}
+
+	stale := identities.Filter(filter.HasNodeID(excluded...)).Sample(size - uint(len(fresh))).NodeIDs()
+	return append(fresh, stale...)
+}
@@ -0,0 +1,28 @@
+package verification
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ChunkDataPackVersion is a version of the chunk data pack of a chunk, along with the execution nodes
+// that provided it.
+type ChunkDataPackVersion struct {
+	ChunkDataPack *flow.ChunkDataPack
+	Collection    *flow.Collection
+	OriginIDs     flow.IdentifierList // execution nodes that provided this version
+}
+
+// ConflictingChunkDataPacks is the evidence of execution nodes providing different chunk data packs
+// for the same chunk of an execution result. Both chunk data packs are valid against the chunk, hence
+// at least one of their origins provides a chunk data pack that it did not use to execute the chunk.
+type ConflictingChunkDataPacks struct {
+	ChunkIndex      uint64
+	ExecutionResult *flow.ExecutionResult
+	First           *ChunkDataPackVersion // the version received first
+	Conflicting     *ChunkDataPackVersion // the version conflicting with the first one
+}
+
+// ChunkID returns the identifier of the chunk the chunk data packs conflict on.
+func (c ConflictingChunkDataPacks) ChunkID() flow.Identifier {
+	return c.ExecutionResult.Chunks[c.ChunkIndex].ID()
+}
@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Server is the http server serving the admin endpoints of a node, which expose the internal
+// state of the node to operators, e.g. for inspecting it during incidents.
+// Endpoints are registered by the components of the node before the server starts. The server
+// does not listen if its address is empty.
+type Server struct {
+	server *http.Server
+	mux    *http.ServeMux
+	log    zerolog.Logger
+}
+
+// NewServer creates a new admin server that will listen on the given address.
+func NewServer(log zerolog.Logger, addr string) *Server {
+	mux := http.NewServeMux()
+
+	s := &Server{
+		server: &http.Server{Addr: addr, Handler: mux},
+		mux:    mux,
+		log:    log.With().Str("component", "admin_server").Logger(),
+	}
+
+	return s
+}
+
+// Register registers the handler of the admin endpoint at the given path.
+func (s *Server) Register(path string, handler http.Handler) {
+	s.mux.Handle(path, handler)
+}
+
+// Ready returns a channel that will close when the server has started.
+func (s *Server) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	if s.server.Addr == "" {
+		close(ready)
+		return ready
+	}
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil {
+			// http.ErrServerClosed is returned when Close or Shutdown is called
+			// we don't consider this an error, so print this with debug level instead
+			if errors.Is(err, http.ErrServerClosed) {
+				s.log.Debug().Err(err).Msg("admin server shutdown")
+			} else {
+				s.log.Err(err).Msg("error shutting down admin server")
+			}
+		}
+	}()
+	go func() {
+		close(ready)
+	}()
+	return ready
+}
+
+// Done returns a channel that will close when shutdown is complete.
+func (s *Server) Done() <-chan struct{} {
+	done := make(chan struct{})
+	if s.server.Addr == "" {
+		close(done)
+		return done
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = s.server.Shutdown(ctx)
+		cancel()
+		close(done)
+	}()
+	return done
+}
+
+// JSONHandler returns a handler of GET requests, which responds with the JSON encoding of the
+// value returned by the given function.
+func JSONHandler(value func() (interface{}, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		v, err := value()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(v)
+	})
+}
@@ -32,6 +32,7 @@ type Builder struct {
 	transactions   mempool.Transactions
 	tracer         module.Tracer
 	config         Config
+	heldSince      map[flow.Identifier]time.Time // when transactions were first held back by sequence number ordering
 }
 
 func NewBuilder(
@@ -52,6 +53,7 @@ func NewBuilder(
 		payloads:       payloads,
 		transactions:   transactions,
 		config:         DefaultConfig(),
+		heldSince:      make(map[flow.Identifier]time.Time),
 	}
 
 	for _, apply := range opts {
@@ -122,6 +124,8 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		lookup := newTransactionLookup()
 		// keep track of transactions to enforce rate limiting
 		limiter := newRateLimiter(b.config, parent.Height+1)
+		// keep track of sequence numbers to order transactions by proposal key
+		sequencer := newSequencer(b.config, time.Now(), b.heldSince)
 
 		// look up previously included transactions in UN-FINALIZED ancestors
 		ancestorID := parentID
@@ -145,6 +149,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 			for _, tx := range collection.Transactions {
 				lookup.addUnfinalizedAncestor(tx.ID())
 				limiter.addAncestor(ancestor.Height, tx)
+				sequencer.addAncestor(tx)
 			}
 			ancestorID = ancestor.ParentID
 		}
@@ -156,10 +161,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		//TODO for now we check a fixed # of finalized ancestors - we should
 		// instead look back based on reference block ID and expiry
 		// ref: https://github.com/dapperlabs/flow-go/issues/3556
-		limit := clusterFinal.Height - flow.DefaultTransactionExpiry
-		if limit > clusterFinal.Height { // overflow check
-			limit = 0
-		}
+		limit := flow.ExpiryLimit(clusterFinal.Height, flow.DefaultTransactionExpiry)
 
 		// look up previously included transactions in FINALIZED ancestors
 		ancestorID = clusterFinal.ID()
@@ -178,6 +180,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 			for _, tx := range collection.Transactions {
 				lookup.addFinalizedAncestor(tx.ID())
 				limiter.addAncestor(ancestor.Height, tx)
+				sequencer.addAncestor(tx)
 			}
 
 			ancestorID = ancestor.ParentID
@@ -194,10 +197,29 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		// start with the finalized reference ID (longest expiry time)
 		minRefID := refChainFinalizedID
 
+		// PRIORITY ORDERING: the builder module can be configured to select
+		// the transactions closest to expiry first, so that they aren't
+		// dropped under load.
+		pending := b.transactions.All()
+		if b.config.TransactionOrdering == TransactionOrderPriority {
+			pending, err = prioritize(b.mainHeaders, pending)
+			if err != nil {
+				return fmt.Errorf("could not prioritize transactions: %w", err)
+			}
+		}
+
+		// SEQUENCE NUMBER ORDERING: the builder module can be configured to
+		// order the pending transactions of each proposal key by sequence
+		// number, and to briefly hold back transactions whose sequence number
+		// follows a gap, as they would fail at execution otherwise.
+		if b.config.SequenceNumberOrdering {
+			pending = sequencer.order(pending)
+		}
+
 		var transactions []*flow.TransactionBody
 		var totalByteSize uint64
 		var totalGas uint64
-		for _, tx := range b.transactions.All() {
+		for _, tx := range pending {
 
 			// if we have reached maximum number of transactions, stop
 			if uint(len(transactions)) >= b.config.MaxCollectionSize {
@@ -246,7 +268,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 
 			// ensure the reference block is not too old
 			txID := tx.ID()
-			if refChainFinalizedHeight-refHeader.Height > uint64(flow.DefaultTransactionExpiry-b.config.ExpiryBuffer) {
+			if flow.IsExpired(refHeader.Height, refChainFinalizedHeight, uint64(flow.DefaultTransactionExpiry-b.config.ExpiryBuffer)) {
 				// the transaction is expired, it will never be valid
 				b.transactions.Rem(txID)
 				continue
@@ -582,6 +582,148 @@ func (suite *BuilderSuite) TestBuildOn_EmptyMempool() {
 	suite.Assert().Equal(0, built.Payload.Collection.Len())
 }
 
+// With sequence number ordering, the transactions of a proposal key should be
+// included in the order of their sequence numbers.
+func (suite *BuilderSuite) TestBuildOn_SequenceNumberOrdering() {
+
+	// start with an empty mempool
+	suite.ClearPool()
+
+	suite.builder = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool,
+		builder.WithSequenceNumberOrdering(time.Hour),
+	)
+
+	// fill the pool with transactions of the same proposal key, out of order
+	proposer := unittest.RandomAddressFixture()
+	for _, sequenceNumber := range []uint64{3, 1, 4, 0, 2} {
+		tx := unittest.TransactionBodyFixture()
+		tx.ReferenceBlockID = suite.ProtoStateRoot().ID()
+		tx.ProposalKey = flow.ProposalKey{
+			Address:        proposer,
+			KeyIndex:       0,
+			SequenceNumber: sequenceNumber,
+		}
+		suite.pool.Add(&tx)
+	}
+
+	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().Nil(err)
+
+	var built model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+	suite.Require().Nil(err)
+	suite.Require().Len(built.Payload.Collection.Transactions, 5)
+	for i, tx := range built.Payload.Collection.Transactions {
+		suite.Assert().Equal(uint64(i), tx.ProposalKey.SequenceNumber)
+	}
+}
+
+// With priority ordering, transactions closest to expiry should be included
+// first when there are more pending transactions than fit in a collection.
+func (suite *BuilderSuite) TestBuildOn_PriorityOrdering() {
+
+	// create main-chain blocks, so that transactions can reference blocks
+	// of different heights
+	genesis, err := suite.protoState.Final().Head()
+	suite.Require().Nil(err)
+
+	head := genesis
+	for i := 0; i < 3; i++ {
+		block := unittest.BlockWithParentFixture(head)
+		block.SetPayload(flow.EmptyPayload())
+		err = suite.protoState.Extend(&block)
+		suite.Require().Nil(err)
+		err = suite.protoState.Finalize(block.ID())
+		suite.Require().Nil(err)
+		head = block.Header
+	}
+
+	// start with an empty mempool
+	suite.ClearPool()
+
+	suite.builder = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool,
+		builder.WithMaxCollectionSize(3),
+		builder.WithTransactionOrdering(builder.TransactionOrderPriority),
+	)
+
+	// fill the pool with transactions referencing the head and transactions
+	// referencing genesis, which are closer to expiry
+	expected := make(map[flow.Identifier]struct{})
+	for i := 0; i < 6; i++ {
+		tx := unittest.TransactionBodyFixture()
+		tx.ProposalKey.SequenceNumber = uint64(i)
+		tx.ReferenceBlockID = head.ID()
+		if i%2 == 0 {
+			tx.ReferenceBlockID = genesis.ID()
+			expected[tx.ID()] = struct{}{}
+		}
+		suite.pool.Add(&tx)
+	}
+
+	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().Nil(err)
+
+	var built model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+	suite.Require().Nil(err)
+	suite.Require().Len(built.Payload.Collection.Transactions, 3)
+	for _, tx := range built.Payload.Collection.Transactions {
+		suite.Assert().Contains(expected, tx.ID())
+	}
+}
+
+// With sequence number ordering, transactions following a gap in the sequence
+// numbers of their proposal key should be held back for the configured delay.
+func (suite *BuilderSuite) TestBuildOn_SequenceNumberGap() {
+
+	// start with an empty mempool
+	suite.ClearPool()
+
+	proposer := unittest.RandomAddressFixture()
+	for _, sequenceNumber := range []uint64{0, 1, 3, 4} {
+		tx := unittest.TransactionBodyFixture()
+		tx.ReferenceBlockID = suite.ProtoStateRoot().ID()
+		tx.ProposalKey = flow.ProposalKey{
+			Address:        proposer,
+			KeyIndex:       0,
+			SequenceNumber: sequenceNumber,
+		}
+		suite.pool.Add(&tx)
+	}
+
+	sequenceNumbers := func(header *flow.Header) []uint64 {
+		var built model.Block
+		err := suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+		suite.Require().Nil(err)
+		var numbers []uint64
+		for _, tx := range built.Payload.Collection.Transactions {
+			numbers = append(numbers, tx.ProposalKey.SequenceNumber)
+		}
+		return numbers
+	}
+
+	// transactions following the gap are held back
+	suite.builder = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool,
+		builder.WithSequenceNumberOrdering(time.Hour),
+	)
+	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]uint64{0, 1}, sequenceNumbers(header))
+
+	// the gap is also detected against the transactions of the ancestors
+	header, err = suite.builder.BuildOn(header.ID(), noopSetter)
+	suite.Require().Nil(err)
+	suite.Assert().Empty(sequenceNumbers(header))
+
+	// once the delay has passed, transactions following the gap are included
+	suite.builder = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool,
+		builder.WithSequenceNumberOrdering(0),
+	)
+	header, err = suite.builder.BuildOn(header.ID(), noopSetter)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]uint64{3, 4}, sequenceNumbers(header))
+}
+
 // With rate limiting turned off, we should fill collections as fast as we can
 // regardless of how many transactions with the same payer we include.
 func (suite *BuilderSuite) TestBuildOn_NoRateLimiting() {
@@ -1,6 +1,8 @@
 package collection
 
 import (
+	"time"
+
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -9,6 +11,20 @@ const (
 	DefaultMaxPayerTransactionRate float64 = 0  // no rate limiting
 )
 
+// TransactionOrdering is the policy used to select pending transactions from the
+// mempool, when more transactions are pending than fit in a collection.
+type TransactionOrdering int
+
+const (
+	// TransactionOrderMempool selects transactions in the order they are
+	// returned by the mempool.
+	TransactionOrderMempool TransactionOrdering = iota
+	// TransactionOrderPriority selects the transactions closest to expiry
+	// first. Transactions don't declare a fee yet, so the priority is based
+	// on the height of the reference block only.
+	TransactionOrderPriority
+)
+
 // Config is the configurable options for the collection builder.
 type Config struct {
 
@@ -39,6 +55,23 @@ type Config struct {
 
 	// MaxCollectionTotalGas is the maximum of total of gas per collection (sum of maxGasLimit over transactions)
 	MaxCollectionTotalGas uint64
+
+	// SequenceNumberOrdering enables ordering the pending transactions of each
+	// proposal key by sequence number within a collection, to avoid transactions
+	// failing at execution because of an invalid sequence number.
+	SequenceNumberOrdering bool
+
+	// FutureSequenceNumberDelay is how long transactions following a gap in the
+	// sequence numbers of their proposal key are held back, when sequence number
+	// ordering is enabled. The gap is measured against the transactions of the
+	// key pending in the mempool, or included in recent ancestors.
+	FutureSequenceNumberDelay time.Duration
+
+	// TransactionOrdering is the policy to select the pending transactions
+	// included in a collection. When combined with sequence number ordering,
+	// the transactions of each proposal key are still ordered by sequence
+	// number, taking the positions the policy assigned to the key.
+	TransactionOrdering TransactionOrdering
 }
 
 func DefaultConfig() Config {
@@ -49,6 +82,7 @@ func DefaultConfig() Config {
 		UnlimitedPayers:         make(map[flow.Address]struct{}), // no unlimited payers
 		MaxCollectionByteSize:   flow.DefaultMaxCollectionByteSize,
 		MaxCollectionTotalGas:   flow.DefaultMaxCollectionTotalGas,
+		TransactionOrdering:     TransactionOrderMempool,
 	}
 }
 
@@ -96,3 +130,16 @@ func WithMaxCollectionTotalGas(limit uint64) Opt {
 		c.MaxCollectionTotalGas = limit
 	}
 }
+
+func WithSequenceNumberOrdering(futureDelay time.Duration) Opt {
+	return func(c *Config) {
+		c.SequenceNumberOrdering = true
+		c.FutureSequenceNumberDelay = futureDelay
+	}
+}
+
+func WithTransactionOrdering(policy TransactionOrdering) Opt {
+	return func(c *Config) {
+		c.TransactionOrdering = policy
+	}
+}
@@ -0,0 +1,43 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// prioritize returns the given pending transactions ordered by the height of their
+// reference block, so that the transactions closest to expiry come first. See Config
+// for details. Transactions with an unknown reference block come last, and transactions
+// with the same reference height keep their order.
+func prioritize(headers storage.Headers, transactions []*flow.TransactionBody) ([]*flow.TransactionBody, error) {
+
+	heights := make(map[flow.Identifier]uint64)
+	for _, tx := range transactions {
+		_, ok := heights[tx.ReferenceBlockID]
+		if ok {
+			continue
+		}
+		refHeader, err := headers.ByBlockID(tx.ReferenceBlockID)
+		if errors.Is(err, storage.ErrNotFound) {
+			heights[tx.ReferenceBlockID] = math.MaxUint64
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve reference header: %w", err)
+		}
+		heights[tx.ReferenceBlockID] = refHeader.Height
+	}
+
+	prioritized := make([]*flow.TransactionBody, len(transactions))
+	copy(prioritized, transactions)
+	sort.SliceStable(prioritized, func(i int, j int) bool {
+		return heights[prioritized[i].ReferenceBlockID] < heights[prioritized[j].ReferenceBlockID]
+	})
+
+	return prioritized, nil
+}
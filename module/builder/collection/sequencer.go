@@ -0,0 +1,135 @@
+package collection
+
+import (
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// proposalKey identifies the proposal key of a transaction, which sequence numbers are tracked for.
+type proposalKey struct {
+	address  flow.Address
+	keyIndex uint64
+}
+
+func proposalKeyOf(tx *flow.TransactionBody) proposalKey {
+	return proposalKey{
+		address:  tx.ProposalKey.Address,
+		keyIndex: tx.ProposalKey.KeyIndex,
+	}
+}
+
+// sequencer implements sequence-number-aware ordering of pending transactions. See Config for details.
+//
+// It is a soft hint only: the builder doesn't know the sequence numbers of the proposal keys on
+// chain, so it can only order the transactions it knows of, and hold back transactions following
+// a gap in the sequence numbers for a while, as they would fail at execution with an invalid
+// sequence number if the missing transaction isn't included first.
+type sequencer struct {
+
+	// how long transactions following a gap are held back (from Config)
+	delay time.Duration
+	// the time of the collection we are building
+	now time.Time
+	// when the transactions following a gap were first held back, kept across collections
+	heldSince map[flow.Identifier]time.Time
+
+	// highest sequence number of each proposal key in the ancestry
+	latestSequenceNumber map[proposalKey]uint64
+}
+
+func newSequencer(conf Config, now time.Time, heldSince map[flow.Identifier]time.Time) *sequencer {
+	seq := &sequencer{
+		delay:                conf.FutureSequenceNumberDelay,
+		now:                  now,
+		heldSince:            heldSince,
+		latestSequenceNumber: make(map[proposalKey]uint64),
+	}
+	return seq
+}
+
+// note the existence of a transaction in an ancestor collection.
+func (seq *sequencer) addAncestor(tx *flow.TransactionBody) {
+	key := proposalKeyOf(tx)
+	latest, ok := seq.latestSequenceNumber[key]
+	if !ok || tx.ProposalKey.SequenceNumber > latest {
+		seq.latestSequenceNumber[key] = tx.ProposalKey.SequenceNumber
+	}
+}
+
+// order returns the given pending transactions, where the transactions of each proposal key are
+// ordered by sequence number. The transactions of a proposal key take the positions of the key's
+// transactions in the given order, so that the order across proposal keys is kept. Transactions
+// following a gap in the sequence numbers of their proposal key are left out, unless they have
+// been held back for longer than the configured delay.
+func (seq *sequencer) order(transactions []*flow.TransactionBody) []*flow.TransactionBody {
+
+	byKey := make(map[proposalKey][]*flow.TransactionBody)
+	for _, tx := range transactions {
+		key := proposalKeyOf(tx)
+		byKey[key] = append(byKey[key], tx)
+	}
+
+	pending := make(map[flow.Identifier]struct{}, len(transactions))
+	for key, keyTransactions := range byKey {
+		sort.SliceStable(keyTransactions, func(i int, j int) bool {
+			return keyTransactions[i].ProposalKey.SequenceNumber < keyTransactions[j].ProposalKey.SequenceNumber
+		})
+
+		// without transactions in the ancestry, we can't tell whether the first transaction is
+		// the next one for the key, so we only look for gaps between the pending transactions
+		expected, known := seq.latestSequenceNumber[key]
+		expected++
+		if !known {
+			expected = keyTransactions[0].ProposalKey.SequenceNumber
+		}
+
+		var included []*flow.TransactionBody
+		afterGap := false
+		for _, tx := range keyTransactions {
+			sequenceNumber := tx.ProposalKey.SequenceNumber
+			if sequenceNumber > expected {
+				afterGap = true
+			}
+			if sequenceNumber >= expected {
+				expected = sequenceNumber + 1
+			}
+
+			if afterGap {
+				txID := tx.ID()
+				pending[txID] = struct{}{}
+				since, ok := seq.heldSince[txID]
+				if !ok {
+					since = seq.now
+					seq.heldSince[txID] = since
+				}
+				if seq.now.Sub(since) < seq.delay {
+					continue
+				}
+			}
+			included = append(included, tx)
+		}
+		byKey[key] = included
+	}
+
+	// forget about transactions which aren't held back anymore
+	for txID := range seq.heldSince {
+		if _, ok := pending[txID]; !ok {
+			delete(seq.heldSince, txID)
+		}
+	}
+
+	ordered := make([]*flow.TransactionBody, 0, len(transactions))
+	for _, tx := range transactions {
+		key := proposalKeyOf(tx)
+		keyTransactions := byKey[key]
+		if len(keyTransactions) == 0 {
+			continue
+		}
+		ordered = append(ordered, keyTransactions[0])
+		byKey[key] = keyTransactions[1:]
+	}
+
+	return ordered
+}
@@ -3,15 +3,21 @@
 package consensus
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/dgraph-io/badger/v2"
 
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter/id"
+	"github.com/onflow/flow-go/model/flow/order"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/state/fork"
 	"github.com/onflow/flow-go/state/protocol"
@@ -19,10 +25,13 @@ import (
 	"github.com/onflow/flow-go/storage/badger/operation"
 )
 
+// errDeadlineExceeded is used to stop fork traversals once the build deadline has passed.
+var errDeadlineExceeded = errors.New("build deadline exceeded")
+
 // Builder is the builder for consensus block payloads. Upon providing a payload
 // hash, it also memorizes which entities were included into the payload.
 type Builder struct {
-	metrics   module.MempoolMetrics
+	metrics   module.ConsensusMetrics
 	tracer    module.Tracer
 	db        *badger.DB
 	state     protocol.MutableState
@@ -35,11 +44,15 @@ type Builder struct {
 	sealPool  mempool.IncorporatedResultSeals
 	recPool   mempool.ExecutionTree
 	cfg       Config
+
+	// report explains the payload of the last block built by the builder
+	reportMu sync.Mutex
+	report   *PayloadReport
 }
 
 // NewBuilder creates a new block builder.
 func NewBuilder(
-	metrics module.MempoolMetrics,
+	metrics module.ConsensusMetrics,
 	db *badger.DB,
 	state protocol.MutableState,
 	headers storage.Headers,
@@ -56,12 +69,15 @@ func NewBuilder(
 
 	// initialize default config
 	cfg := Config{
-		minInterval:       500 * time.Millisecond,
-		maxInterval:       10 * time.Second,
-		maxSealCount:      100,
-		maxGuaranteeCount: 100,
-		maxReceiptCount:   200,
-		expiry:            flow.DefaultTransactionExpiry,
+		minInterval:        500 * time.Millisecond,
+		maxInterval:        10 * time.Second,
+		maxSealCount:       100,
+		maxGuaranteeCount:  100,
+		maxReceiptCount:    200,
+		maxPayloadByteSize: flow.DefaultMaxPayloadByteSize,
+		expiry:             flow.DefaultTransactionExpiry,
+		guaranteeOrdering:  GuaranteeOrderMempool,
+		constraints:        []PayloadConstraint{NoDuplicates, ServiceEventOrdering},
 	}
 
 	// apply option parameters
@@ -94,24 +110,40 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 	b.tracer.StartSpan(parentID, trace.CONBuildOn)
 	defer b.tracer.FinishSpan(parentID, trace.CONBuildOn)
 
+	// when the build deadline passes, we stop looking for receipts and seals and use the best
+	// payload assembled so far, so that the proposal doesn't miss its slot
+	var deadline time.Time
+	if b.cfg.buildDeadline > 0 {
+		deadline = time.Now().Add(b.cfg.buildDeadline)
+	}
+
+	// record why each candidate entity is included in the payload or not
+	report := newPayloadReport(parentID)
+
 	// get the collection guarantees to insert in the payload
-	insertableGuarantees, err := b.getInsertableGuarantees(parentID)
+	insertableGuarantees, err := b.getInsertableGuarantees(parentID, report)
 	if err != nil {
 		return nil, fmt.Errorf("could not insert guarantees: %w", err)
 	}
 
 	// get the receipts to insert in the payload
-	insertableReceipts, err := b.getInsertableReceipts(parentID)
+	insertableReceipts, err := b.getInsertableReceipts(parentID, deadline, report)
 	if err != nil {
 		return nil, fmt.Errorf("could not insert receipts: %w", err)
 	}
 
 	// get the seals to insert in the payload
-	insertableSeals, err := b.getInsertableSeals(parentID)
+	insertableSeals, err := b.getInsertableSeals(parentID, deadline, report)
 	if err != nil {
 		return nil, fmt.Errorf("could not insert seals: %w", err)
 	}
 
+	// leave out entities which would make the payload too big
+	insertableGuarantees, insertableSeals, insertableReceipts, err = b.truncatePayload(insertableGuarantees, insertableSeals, insertableReceipts, report)
+	if err != nil {
+		return nil, fmt.Errorf("could not truncate payload: %w", err)
+	}
+
 	// assemble the block proposal
 	proposal, err := b.createProposal(parentID,
 		insertableGuarantees,
@@ -122,6 +154,14 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		return nil, fmt.Errorf("could not assemble proposal: %w", err)
 	}
 
+	// check the payload against the configured constraints
+	for _, constraint := range b.cfg.constraints {
+		err = constraint(proposal.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("payload violates constraint: %w", err)
+		}
+	}
+
 	b.tracer.StartSpan(parentID, trace.CONBuildOnDBInsert)
 	defer b.tracer.FinishSpan(parentID, trace.CONBuildOnDBInsert)
 
@@ -130,9 +170,27 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		return nil, fmt.Errorf("could not extend state with built proposal: %w", err)
 	}
 
+	report.BlockID = proposal.Header.ID()
+	b.reportMu.Lock()
+	b.report = report
+	b.reportMu.Unlock()
+
 	return proposal.Header, nil
 }
 
+// ExplainPayload returns the report explaining why the candidate entities in the mempools were
+// included in or excluded from the payload of the given block. Only the last block built by the
+// builder is explained; for any other block, it returns storage.ErrNotFound.
+func (b *Builder) ExplainPayload(blockID flow.Identifier) (*PayloadReport, error) {
+	b.reportMu.Lock()
+	defer b.reportMu.Unlock()
+
+	if b.report == nil || b.report.BlockID != blockID {
+		return nil, fmt.Errorf("no payload report for block %x: %w", blockID, storage.ErrNotFound)
+	}
+	return b.report, nil
+}
+
 // getInsertableGuarantees returns the list of CollectionGuarantees that should
 // be inserted in the next payload. It looks in the collection mempool and
 // applies the following filters:
@@ -144,7 +202,10 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 // 3) If the referenced block has an expired height, skip.
 //
 // 4) Otherwise, this guarantee can be included in the payload.
-func (b *Builder) getInsertableGuarantees(parentID flow.Identifier) ([]*flow.CollectionGuarantee, error) {
+//
+// At most maxGuaranteeCount guarantees are selected, according to the configured
+// guarantee ordering.
+func (b *Builder) getInsertableGuarantees(parentID flow.Identifier, report *PayloadReport) ([]*flow.CollectionGuarantee, error) {
 	b.tracer.StartSpan(parentID, trace.CONBuildOnCreatePayloadGuarantees)
 	defer b.tracer.FinishSpan(parentID, trace.CONBuildOnCreatePayloadGuarantees)
 
@@ -156,10 +217,7 @@ func (b *Builder) getInsertableGuarantees(parentID flow.Identifier) ([]*flow.Col
 		return nil, fmt.Errorf("could not retrieve parent: %w", err)
 	}
 	height := parent.Height + 1
-	limit := height - uint64(b.cfg.expiry)
-	if limit > height { // overflow check
-		limit = 0
-	}
+	limit := flow.ExpiryLimit(height, uint64(b.cfg.expiry))
 
 	// look up the root height so we don't look too far back
 	// initially this is the genesis block height (aka 0).
@@ -172,8 +230,18 @@ func (b *Builder) getInsertableGuarantees(parentID flow.Identifier) ([]*flow.Col
 		limit = rootHeight
 	}
 
-	// blockLookup keeps track of the blocks from limit to parent
-	blockLookup := make(map[flow.Identifier]struct{})
+	// guarantees may reference any ancestor of the parent, unless we only include guarantees
+	// referencing finalized blocks, which are the ancestors up to the finalized height
+	finalizedHeight := parent.Height
+	if b.cfg.finalizedReferencesOnly {
+		err = b.db.View(operation.RetrieveFinalizedHeight(&finalizedHeight))
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve finalized height: %w", err)
+		}
+	}
+
+	// blockLookup keeps track of the heights of the blocks from limit to parent
+	blockLookup := make(map[flow.Identifier]uint64)
 
 	// receiptLookup keeps track of the receipts contained in blocks between
 	// limit and parent
@@ -183,7 +251,7 @@ func (b *Builder) getInsertableGuarantees(parentID flow.Identifier) ([]*flow.Col
 	// and keep track of blocks and collections visited on the way
 	forkScanner := func(header *flow.Header) error {
 		ancestorID := header.ID()
-		blockLookup[ancestorID] = struct{}{}
+		blockLookup[ancestorID] = header.Height
 
 		index, err := b.index.ByBlockID(ancestorID)
 		if err != nil {
@@ -203,11 +271,15 @@ func (b *Builder) getInsertableGuarantees(parentID flow.Identifier) ([]*flow.Col
 
 	// go through mempool and collect valid collections
 	var guarantees []*flow.CollectionGuarantee
-	for _, guarantee := range b.guarPool.All() {
+	candidates := b.guarPool.All()
+	for i, guarantee := range candidates {
 		// add at most <maxGuaranteeCount> number of collection guarantees in a new block proposal
 		// in order to prevent the block payload from being too big or computationally heavy for the
-		// execution nodes
-		if uint(len(guarantees)) >= b.cfg.maxGuaranteeCount {
+		// execution nodes; with fair ordering, all guarantees are collected and selected below
+		if b.cfg.guaranteeOrdering == GuaranteeOrderMempool && uint(len(guarantees)) >= b.cfg.maxGuaranteeCount {
+			for _, skipped := range candidates[i:] {
+				report.Guarantees[skipped.ID()] = ExcludedLimitReached
+			}
 			break
 		}
 
@@ -216,21 +288,131 @@ func (b *Builder) getInsertableGuarantees(parentID flow.Identifier) ([]*flow.Col
 		// skip collections that are already included in a block on the fork
 		_, duplicated := receiptLookup[collID]
 		if duplicated {
+			report.Guarantees[collID] = ExcludedDuplicate
 			continue
 		}
 
 		// skip collections for blocks that are not within the limit
-		_, ok := blockLookup[guarantee.ReferenceBlockID]
+		refHeight, ok := blockLookup[guarantee.ReferenceBlockID]
 		if !ok {
+			report.Guarantees[collID] = ExcludedExpired
+			continue
+		}
+
+		// skip collections for blocks that are not finalized yet, if not allowed
+		if refHeight > finalizedHeight {
+			report.Guarantees[collID] = ExcludedUnfinalizedReference
 			continue
 		}
 
 		guarantees = append(guarantees, guarantee)
+		report.Guarantees[collID] = Included
+	}
+
+	if b.cfg.guaranteeOrdering == GuaranteeOrderFair {
+		selected, err := b.selectFairGuarantees(guarantees, blockLookup)
+		if err != nil {
+			return nil, fmt.Errorf("could not select guarantees: %w", err)
+		}
+		for _, guarantee := range guarantees {
+			report.Guarantees[guarantee.ID()] = ExcludedLimitReached
+		}
+		for _, guarantee := range selected {
+			report.Guarantees[guarantee.ID()] = Included
+		}
+		guarantees = selected
 	}
 
 	return guarantees, nil
 }
 
+// selectFairGuarantees selects at most maxGuaranteeCount of the given guarantees. The guarantees
+// of each collection cluster are ordered by the height of their reference block, so that the
+// oldest guarantees, which are the closest to expiring, are selected first. The clusters take
+// turns, so that one busy cluster can't starve the others; the cluster with the oldest pending
+// guarantee goes first in each round.
+func (b *Builder) selectFairGuarantees(guarantees []*flow.CollectionGuarantee, heights map[flow.Identifier]uint64) ([]*flow.CollectionGuarantee, error) {
+
+	// group the guarantees by the cluster which guaranteed them
+	clusterings := make(map[flow.Identifier]flow.ClusterList)
+	byCluster := make(map[flow.Identifier][]*flow.CollectionGuarantee)
+	var clusterIDs []flow.Identifier
+	for _, guarantee := range guarantees {
+		clusterID, err := b.clusterOf(guarantee, clusterings)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byCluster[clusterID]; !ok {
+			clusterIDs = append(clusterIDs, clusterID)
+		}
+		byCluster[clusterID] = append(byCluster[clusterID], guarantee)
+	}
+
+	older := func(guarantee1 *flow.CollectionGuarantee, guarantee2 *flow.CollectionGuarantee) bool {
+		height1 := heights[guarantee1.ReferenceBlockID]
+		height2 := heights[guarantee2.ReferenceBlockID]
+		if height1 != height2 {
+			return height1 < height2
+		}
+		return order.GuaranteeByCollectionIDAsc(guarantee1, guarantee2)
+	}
+	for _, clusterGuarantees := range byCluster {
+		clusterGuarantees := clusterGuarantees
+		sort.Slice(clusterGuarantees, func(i int, j int) bool {
+			return older(clusterGuarantees[i], clusterGuarantees[j])
+		})
+	}
+	sort.Slice(clusterIDs, func(i int, j int) bool {
+		return older(byCluster[clusterIDs[i]][0], byCluster[clusterIDs[j]][0])
+	})
+
+	// take one guarantee of each cluster in turn
+	var selected []*flow.CollectionGuarantee
+	for round := 0; uint(len(selected)) < b.cfg.maxGuaranteeCount; round++ {
+		added := false
+		for _, clusterID := range clusterIDs {
+			clusterGuarantees := byCluster[clusterID]
+			if round >= len(clusterGuarantees) {
+				continue
+			}
+			if uint(len(selected)) >= b.cfg.maxGuaranteeCount {
+				break
+			}
+			selected = append(selected, clusterGuarantees[round])
+			added = true
+		}
+		if !added {
+			break
+		}
+	}
+
+	return selected, nil
+}
+
+// clusterOf returns the fingerprint of the collection cluster which guaranteed the collection,
+// according to the clustering of the epoch of the reference block, or a zero ID if the guarantors
+// are not part of any cluster. Clusterings are cached by reference block in the given map.
+func (b *Builder) clusterOf(guarantee *flow.CollectionGuarantee, clusterings map[flow.Identifier]flow.ClusterList) (flow.Identifier, error) {
+	clustering, ok := clusterings[guarantee.ReferenceBlockID]
+	if !ok {
+		var err error
+		clustering, err = b.state.AtBlockID(guarantee.ReferenceBlockID).Epochs().Current().Clustering()
+		if err != nil {
+			return flow.ZeroID, fmt.Errorf("could not get clustering for reference block (%x): %w", guarantee.ReferenceBlockID, err)
+		}
+		clusterings[guarantee.ReferenceBlockID] = clustering
+	}
+
+	if len(guarantee.SignerIDs) == 0 {
+		return flow.ZeroID, nil
+	}
+	cluster, _, ok := clustering.ByNodeID(guarantee.SignerIDs[0])
+	if !ok {
+		return flow.ZeroID, nil
+	}
+	return cluster.Fingerprint(), nil
+}
+
 // getInsertableSeals returns the list of Seals from the mempool that should be
 // inserted in the next payload.
 // Per protocol definition, a specific result is only incorporated _once_ in each fork.
@@ -246,7 +428,9 @@ func (b *Builder) getInsertableGuarantees(parentID flow.Identifier) ([]*flow.Col
 //  (3) The result's parent must have been previously sealed (either by a seal in an ancestor
 //      block or by a seal included earlier in the block that we are constructing).
 // To limit block size, we cap the number of seals to maxSealCount.
-func (b *Builder) getInsertableSeals(parentID flow.Identifier) ([]*flow.Seal, error) {
+// If the deadline passes while walking the fork, the walk is cut short and only the seals
+// collected so far are considered, which still form a valid (possibly empty) chain of seals.
+func (b *Builder) getInsertableSeals(parentID flow.Identifier, deadline time.Time, report *PayloadReport) ([]*flow.Seal, error) {
 	b.tracer.StartSpan(parentID, trace.CONBuildOnCreatePayloadSeals)
 	defer b.tracer.FinishSpan(parentID, trace.CONBuildOnCreatePayloadSeals)
 
@@ -280,6 +464,10 @@ func (b *Builder) getInsertableSeals(parentID flow.Identifier) ([]*flow.Seal, er
 	//    Therefore, we only have to inspect the results incorporated in unsealed blocks.
 	sealsSuperset := make(map[uint64][]*flow.IncorporatedResultSeal) // map: executedBlock.Height -> candidate Seals
 	sealCollector := func(header *flow.Header) error {
+		if isPastDeadline(deadline) {
+			return errDeadlineExceeded
+		}
+
 		block, err := b.blocks.ByID(header.ID())
 		if err != nil {
 			return fmt.Errorf("could not retrieve block %x: %w", header.ID(), err)
@@ -315,6 +503,7 @@ func (b *Builder) getInsertableSeals(parentID flow.Identifier) ([]*flow.Seal, er
 				return fmt.Errorf("could not get header of block %x: %w", incorporatedResult.Result.BlockID, err)
 			}
 			if executedBlock.Height <= latestSealedHeight {
+				report.Seals[irSeal.Seal.ID()] = ExcludedDuplicate
 				continue
 			}
 
@@ -326,7 +515,8 @@ func (b *Builder) getInsertableSeals(parentID flow.Identifier) ([]*flow.Seal, er
 		return nil
 	}
 	err = fork.TraverseBackward(b.headers, parentID, sealCollector, fork.ExcludingBlock(latestSealedBlockID))
-	if err != nil {
+	deadlineExceeded := errors.Is(err, errDeadlineExceeded)
+	if err != nil && !deadlineExceeded {
 		return nil, fmt.Errorf("internal error traversing unsealed section of fork: %w", err)
 	}
 	// All the seals in sealsSuperset are for results that satisfy (0), (1), and (2).
@@ -337,9 +527,11 @@ func (b *Builder) getInsertableSeals(parentID flow.Identifier) ([]*flow.Seal, er
 	// sealed result. If we find such a seal, we can now consider the child block sealed.
 	// We continue until we stop finding a seal for the child.
 	seals := make([]*flow.Seal, 0, len(sealsSuperset))
+	limitReached := false
 	for {
 		// cap the number of seals
 		if uint(len(seals)) >= b.cfg.maxSealCount {
+			limitReached = true
 			break
 		}
 
@@ -352,6 +544,32 @@ func (b *Builder) getInsertableSeals(parentID flow.Identifier) ([]*flow.Seal, er
 		lastSeal = candidateSeal
 		latestSealedHeight += 1
 	}
+
+	// explain the candidate seals which were left out: the ones for results incorporated in the fork
+	// didn't connect to the sealed result or didn't fit, the others are not for the fork at all
+	leftOut := ExcludedMissingParentResult
+	if limitReached {
+		leftOut = ExcludedLimitReached
+	}
+	for _, candidateSeals := range sealsSuperset {
+		for _, irSeal := range candidateSeals {
+			report.Seals[irSeal.Seal.ID()] = leftOut
+		}
+	}
+	for _, seal := range seals {
+		report.Seals[seal.ID()] = Included
+	}
+	notOnFork := ExcludedNotOnFork
+	if deadlineExceeded {
+		notOnFork = ExcludedDeadlineExceeded
+	}
+	for _, irSeal := range b.sealPool.All() {
+		sealID := irSeal.Seal.ID()
+		if _, ok := report.Seals[sealID]; !ok {
+			report.Seals[sealID] = notOnFork
+		}
+	}
+
 	return seals, nil
 }
 
@@ -385,10 +603,16 @@ type InsertableReceipts struct {
 // 3) Otherwise, this receipt can be included in the payload.
 //
 // Receipts have to be ordered by block height.
-func (b *Builder) getInsertableReceipts(parentID flow.Identifier) (*InsertableReceipts, error) {
+// If the deadline passes before all receipts in the fork are known, no receipts are
+// inserted, as we couldn't tell which receipts would be duplicates.
+func (b *Builder) getInsertableReceipts(parentID flow.Identifier, deadline time.Time, report *PayloadReport) (*InsertableReceipts, error) {
 	b.tracer.StartSpan(parentID, trace.CONBuildOnCreatePayloadReceipts)
 	defer b.tracer.FinishSpan(parentID, trace.CONBuildOnCreatePayloadReceipts)
 
+	if isPastDeadline(deadline) {
+		return &InsertableReceipts{}, nil
+	}
+
 	// Get the latest sealed block on this fork, ie the highest block for which
 	// there is a seal in this fork. This block is not necessarily finalized.
 	latestSeal, err := b.seals.ByBlockID(parentID)
@@ -418,6 +642,10 @@ func (b *Builder) getInsertableReceipts(parentID flow.Identifier) (*InsertableRe
 	// loop through the fork backwards, from parent to last sealed (including),
 	// and keep track of blocks and receipts visited on the way.
 	forkScanner := func(ancestor *flow.Header) error {
+		if isPastDeadline(deadline) {
+			return errDeadlineExceeded
+		}
+
 		ancestorID := ancestor.ID()
 		ancestors[ancestorID] = struct{}{}
 
@@ -435,6 +663,9 @@ func (b *Builder) getInsertableReceipts(parentID flow.Identifier) (*InsertableRe
 		return nil
 	}
 	err = fork.TraverseBackward(b.headers, parentID, forkScanner, fork.IncludingBlock(sealedBlockID))
+	if errors.Is(err, errDeadlineExceeded) {
+		return &InsertableReceipts{}, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("internal error building set of CollectionGuarantees on fork: %w", err)
 	}
@@ -449,7 +680,14 @@ func (b *Builder) getInsertableReceipts(parentID flow.Identifier) (*InsertableRe
 		return nil, fmt.Errorf("failed to add sealed result as vertex to ExecutionTree (%x): %w", latestSeal.ResultID, err)
 	}
 	isResultForUnsealedBlock := isResultForBlock(ancestors)
-	isReceiptUniqueAndUnsealed := isNoDupAndNotSealed(includedReceipts, sealedBlockID)
+	isNoDup := isNoDupAndNotSealed(includedReceipts, sealedBlockID)
+	isReceiptUniqueAndUnsealed := func(receipt *flow.ExecutionReceipt) bool {
+		if !isNoDup(receipt) {
+			report.Receipts[receipt.ID()] = ExcludedDuplicate
+			return false
+		}
+		return true
+	}
 	// find all receipts:
 	// 1) whose result connects all the way to the last sealed result
 	// 2) is unique (never seen in unsealed blocks)
@@ -459,6 +697,13 @@ func (b *Builder) getInsertableReceipts(parentID flow.Identifier) (*InsertableRe
 	}
 
 	insertables := toInsertables(receipts, includedResults, b.cfg.maxReceiptCount)
+	for i, receipt := range receipts {
+		if uint(i) < b.cfg.maxReceiptCount {
+			report.Receipts[receipt.ID()] = Included
+		} else {
+			report.Receipts[receipt.ID()] = ExcludedLimitReached
+		}
+	}
 
 	return insertables, nil
 }
@@ -495,6 +740,102 @@ func toInsertables(receipts []*flow.ExecutionReceipt, includedResults map[flow.I
 	}
 }
 
+// truncatePayload limits the byte size of the payload to maxPayloadByteSize, so that block
+// proposals never exceed the max network message size. Entities are added in priority order:
+// guarantees first, then seals, then receipts together with the results they are the first to
+// commit to. Each list is truncated at the first entity which doesn't fit, as seals have to
+// form a chain and receipts must follow the order of the execution tree. The sizes are based on
+// the JSON encoding of the entities, as used for network messages.
+func (b *Builder) truncatePayload(
+	guarantees []*flow.CollectionGuarantee,
+	seals []*flow.Seal,
+	insertableReceipts *InsertableReceipts,
+	report *PayloadReport,
+) ([]*flow.CollectionGuarantee, []*flow.Seal, *InsertableReceipts, error) {
+
+	var total uint64
+	fits := func(entities ...interface{}) (bool, error) {
+		var size uint64
+		for _, entity := range entities {
+			encoded, err := json.Marshal(entity)
+			if err != nil {
+				return false, fmt.Errorf("could not encode payload entity: %w", err)
+			}
+			size += uint64(len(encoded))
+		}
+		if total+size > b.cfg.maxPayloadByteSize {
+			return false, nil
+		}
+		total += size
+		return true, nil
+	}
+
+	for i, guarantee := range guarantees {
+		ok, err := fits(guarantee)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !ok {
+			b.metrics.PayloadTruncated(metrics.ResourceGuarantee, uint(len(guarantees)-i))
+			for _, truncated := range guarantees[i:] {
+				report.Guarantees[truncated.ID()] = ExcludedLimitReached
+			}
+			guarantees = guarantees[:i]
+			break
+		}
+	}
+
+	for i, seal := range seals {
+		ok, err := fits(seal)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !ok {
+			b.metrics.PayloadTruncated(metrics.ResourceSeal, uint(len(seals)-i))
+			for _, truncated := range seals[i:] {
+				report.Seals[truncated.ID()] = ExcludedLimitReached
+			}
+			seals = seals[:i]
+			break
+		}
+	}
+
+	// results are only included with the first receipt committing to them
+	pending := make(map[flow.Identifier]*flow.ExecutionResult, len(insertableReceipts.results))
+	for _, result := range insertableReceipts.results {
+		pending[result.ID()] = result
+	}
+	truncated := &InsertableReceipts{
+		receipts: make([]*flow.ExecutionReceiptMeta, 0, len(insertableReceipts.receipts)),
+		results:  make([]*flow.ExecutionResult, 0, len(insertableReceipts.results)),
+	}
+	for i, receipt := range insertableReceipts.receipts {
+		entities := []interface{}{receipt}
+		result, withResult := pending[receipt.ResultID]
+		if withResult {
+			entities = append(entities, result)
+		}
+		ok, err := fits(entities...)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !ok {
+			b.metrics.PayloadTruncated(metrics.ResourceReceipt, uint(len(insertableReceipts.receipts)-i))
+			for _, truncated := range insertableReceipts.receipts[i:] {
+				report.Receipts[truncated.ID()] = ExcludedLimitReached
+			}
+			break
+		}
+		truncated.receipts = append(truncated.receipts, receipt)
+		if withResult {
+			truncated.results = append(truncated.results, result)
+			delete(pending, receipt.ResultID)
+		}
+	}
+
+	return guarantees, seals, truncated, nil
+}
+
 // createProposal assembles a block with the provided header and payload
 // information
 func (b *Builder) createProposal(parentID flow.Identifier,
@@ -507,11 +848,14 @@ func (b *Builder) createProposal(parentID flow.Identifier,
 	defer b.tracer.FinishSpan(parentID, trace.CONBuildOnCreateHeader)
 
 	// build the payload so we can get the hash
-	payload := &flow.Payload{
-		Guarantees: guarantees,
-		Seals:      seals,
-		Receipts:   insertableReceipts.receipts,
-		Results:    insertableReceipts.results,
+	payload, err := flow.NewPayloadBuilder().
+		WithGuarantees(guarantees...).
+		WithSeals(seals...).
+		WithReceiptMetas(insertableReceipts.receipts...).
+		WithResults(insertableReceipts.results...).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("could not build payload: %w", err)
 	}
 
 	parent, err := b.headers.ByBlockID(parentID)
@@ -572,6 +916,11 @@ func isResultForBlock(blockIDs map[flow.Identifier]struct{}) mempool.BlockFilter
 	}
 }
 
+// isPastDeadline returns whether the given build deadline has passed; the zero deadline never passes.
+func isPastDeadline(deadline time.Time) bool {
+	return !deadline.IsZero() && time.Now().After(deadline)
+}
+
 // isNoDupAndNotSealed constructs a mempool.ReceiptFilter for discarding receipts that
 // * are duplicates
 // * or are for the sealed block
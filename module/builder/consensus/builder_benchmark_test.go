@@ -0,0 +1,281 @@
+package consensus
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	mempoolImpl "github.com/onflow/flow-go/module/mempool/consensus"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	storerr "github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// loadShape describes the synthetic load the builder is benchmarked with.
+type loadShape struct {
+	unsealed        int     // number of unsealed blocks on the fork we build on
+	guarantees      int     // number of collection guarantees in the mempool
+	executionForks  int     // number of conflicting results for each unsealed block
+	approvalRate    float64 // probability of an incorporated result to have a candidate seal
+	forks           int     // number of blocks on a competing fork, whose receipts and seals are in the mempools
+	maxSealCount    uint
+	maxReceiptCount uint
+}
+
+// BenchmarkBuildOn drives the builder with synthetic mempools of different sizes and shapes. Besides the
+// latency of building a payload, it reports the number of entities in the payload and its byte size, which
+// helps tuning the limits of the builder.
+func BenchmarkBuildOn(b *testing.B) {
+	shapes := []struct {
+		name  string
+		shape loadShape
+	}{
+		{"baseline", loadShape{unsealed: 10, guarantees: 100, executionForks: 1, approvalRate: 1, maxSealCount: 100, maxReceiptCount: 200}},
+		{"sealing_backlog", loadShape{unsealed: 500, guarantees: 100, executionForks: 1, approvalRate: 1, maxSealCount: 100, maxReceiptCount: 200}},
+		{"guarantee_backlog", loadShape{unsealed: 10, guarantees: 10000, executionForks: 1, approvalRate: 1, maxSealCount: 100, maxReceiptCount: 200}},
+		{"execution_forks", loadShape{unsealed: 100, guarantees: 100, executionForks: 4, approvalRate: 1, maxSealCount: 100, maxReceiptCount: 200}},
+		{"low_approval_rate", loadShape{unsealed: 100, guarantees: 100, executionForks: 2, approvalRate: 0.5, maxSealCount: 100, maxReceiptCount: 200}},
+		{"competing_fork", loadShape{unsealed: 100, guarantees: 100, executionForks: 1, approvalRate: 1, forks: 500, maxSealCount: 100, maxReceiptCount: 200}},
+		{"low_limits", loadShape{unsealed: 500, guarantees: 1000, executionForks: 2, approvalRate: 1, maxSealCount: 10, maxReceiptCount: 20}},
+	}
+
+	for _, s := range shapes {
+		b.Run(s.name, func(b *testing.B) {
+			benchmarkBuildOn(b, s.shape)
+		})
+	}
+}
+
+func benchmarkBuildOn(b *testing.B, shape loadShape) {
+	unittest.RunWithBadgerDB(b, func(db *badger.DB) {
+		chain := newSyntheticChain(b, db, shape)
+
+		var assembled *flow.Payload
+		state := &protocol.MutableState{}
+		state.On("Extend", mock.Anything).Run(func(args mock.Arguments) {
+			assembled = args.Get(0).(*flow.Block).Payload
+		}).Return(nil)
+
+		build := NewBuilder(
+			metrics.NewNoopCollector(),
+			db,
+			state,
+			chain.headerDB(),
+			chain.sealDB(),
+			chain.indexDB(),
+			chain.blockDB(),
+			chain.resultDB(),
+			chain.guarPool,
+			chain.sealPool,
+			chain.recPool,
+			trace.NewNoopTracer(),
+			WithMaxSealCount(shape.maxSealCount),
+			WithMaxReceiptCount(shape.maxReceiptCount),
+		)
+		setter := func(*flow.Header) error { return nil }
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := build.BuildOn(chain.parentID, setter)
+			require.NoError(b, err)
+		}
+		b.StopTimer()
+
+		encoded, err := json.Marshal(assembled)
+		require.NoError(b, err)
+		b.ReportMetric(float64(len(assembled.Guarantees)), "guarantees/block")
+		b.ReportMetric(float64(len(assembled.Seals)), "seals/block")
+		b.ReportMetric(float64(len(assembled.Receipts)), "receipts/block")
+		b.ReportMetric(float64(len(assembled.Results)), "results/block")
+		b.ReportMetric(float64(len(encoded)), "payload_bytes")
+	})
+}
+
+// syntheticChain is a chain of blocks generated for a load shape, together with mempools holding the
+// guarantees, receipts and candidate seals for it.
+type syntheticChain struct {
+	headers  map[flow.Identifier]*flow.Header
+	index    map[flow.Identifier]*flow.Index
+	blocks   map[flow.Identifier]*flow.Block
+	results  map[flow.Identifier]*flow.ExecutionResult
+	lastSeal *flow.Seal
+	parentID flow.Identifier
+
+	guarPool *stdmap.Guarantees
+	sealPool *stdmap.IncorporatedResultSeals
+	recPool  *mempoolImpl.ExecutionTree
+
+	rng   *rand.Rand
+	shape loadShape
+}
+
+func newSyntheticChain(b *testing.B, db *badger.DB, shape loadShape) *syntheticChain {
+	guarPool, err := stdmap.NewGuarantees(uint(shape.guarantees) + 1)
+	require.NoError(b, err)
+
+	c := &syntheticChain{
+		headers:  make(map[flow.Identifier]*flow.Header),
+		index:    make(map[flow.Identifier]*flow.Index),
+		blocks:   make(map[flow.Identifier]*flow.Block),
+		results:  make(map[flow.Identifier]*flow.ExecutionResult),
+		guarPool: guarPool,
+		sealPool: stdmap.NewIncorporatedResultSeals(),
+		recPool:  mempoolImpl.NewExecutionTree(),
+		rng:      rand.New(rand.NewSource(1)), // deterministic shapes across runs
+		shape:    shape,
+	}
+
+	// the root block is sealed, all of its descendants are unsealed
+	root := unittest.BlockFixture()
+	rootResult := unittest.ExecutionResultFixture(unittest.WithBlock(&root))
+	c.store(&root)
+	c.results[rootResult.ID()] = rootResult
+	c.lastSeal = unittest.Seal.Fixture(unittest.Seal.WithResult(rootResult))
+
+	err = db.Update(operation.InsertRootHeight(root.Header.Height))
+	require.NoError(b, err)
+
+	// the fork we build on, where each block incorporates the results for its parent
+	parent := c.extend(b, &root, rootResult, shape.unsealed)
+	c.parentID = parent.ID()
+
+	// a competing fork, whose receipts and seals are in the mempools but can't be included
+	c.extend(b, &root, rootResult, shape.forks)
+
+	for _, guarantee := range unittest.CollectionGuaranteesFixture(shape.guarantees, unittest.WithCollRef(c.parentID)) {
+		c.guarPool.Add(guarantee)
+	}
+
+	return c
+}
+
+// extend appends the given number of blocks to the parent, and returns the last block. The results for
+// each block are incorporated in its child, except for the results of the last block, whose receipts are
+// only in the mempool. Candidate seals are added for the incorporated results according to the approval rate.
+func (c *syntheticChain) extend(b *testing.B, parent *flow.Block, parentResult *flow.ExecutionResult, count int) *flow.Block {
+	var pending []*flow.ExecutionReceipt
+	for n := 0; n < count; n++ {
+		block := unittest.BlockWithParentFixture(parent.Header)
+		block.Payload.Guarantees = nil
+		block.Payload.Seals = nil
+
+		// incorporate the receipts for the parent, which are pending up to here
+		for _, receipt := range pending {
+			block.Payload.Receipts = append(block.Payload.Receipts, receipt.Meta())
+			block.Payload.Results = append(block.Payload.Results, &receipt.ExecutionResult)
+			if c.rng.Float64() >= c.shape.approvalRate {
+				continue
+			}
+			_, err := c.sealPool.Add(unittest.IncorporatedResultSeal.Fixture(
+				unittest.IncorporatedResultSeal.WithResult(&receipt.ExecutionResult),
+				unittest.IncorporatedResultSeal.WithIncorporatedBlockID(receipt.ExecutionResult.BlockID),
+			))
+			require.NoError(b, err)
+		}
+		block.Header.PayloadHash = block.Payload.Hash()
+		c.store(&block)
+
+		// the results for this block, which conflict with each other if there are execution forks
+		pending = make([]*flow.ExecutionReceipt, 0, c.shape.executionForks)
+		for f := 0; f < c.shape.executionForks; f++ {
+			result := unittest.ExecutionResultFixture(unittest.WithBlock(&block), unittest.WithPreviousResult(*parentResult))
+			receipt := unittest.ExecutionReceiptFixture(unittest.WithResult(result))
+			c.results[result.ID()] = result
+			_, err := c.recPool.AddReceipt(receipt, block.Header)
+			require.NoError(b, err)
+			pending = append(pending, receipt)
+		}
+
+		parent = &block
+		parentResult = &pending[0].ExecutionResult
+	}
+
+	return parent
+}
+
+func (c *syntheticChain) store(block *flow.Block) {
+	blockID := block.ID()
+	c.headers[blockID] = block.Header
+	c.blocks[blockID] = block
+	c.index[blockID] = block.Payload.Index()
+}
+
+func (c *syntheticChain) headerDB() *storage.Headers {
+	headerDB := &storage.Headers{}
+	headerDB.On("ByBlockID", mock.Anything).Return(
+		func(blockID flow.Identifier) *flow.Header {
+			return c.headers[blockID]
+		},
+		func(blockID flow.Identifier) error {
+			if _, exists := c.headers[blockID]; !exists {
+				return storerr.ErrNotFound
+			}
+			return nil
+		},
+	)
+	return headerDB
+}
+
+func (c *syntheticChain) sealDB() *storage.Seals {
+	sealDB := &storage.Seals{}
+	sealDB.On("ByBlockID", mock.Anything).Return(c.lastSeal, nil)
+	return sealDB
+}
+
+func (c *syntheticChain) indexDB() *storage.Index {
+	indexDB := &storage.Index{}
+	indexDB.On("ByBlockID", mock.Anything).Return(
+		func(blockID flow.Identifier) *flow.Index {
+			return c.index[blockID]
+		},
+		func(blockID flow.Identifier) error {
+			if _, exists := c.index[blockID]; !exists {
+				return storerr.ErrNotFound
+			}
+			return nil
+		},
+	)
+	return indexDB
+}
+
+func (c *syntheticChain) blockDB() *storage.Blocks {
+	blockDB := &storage.Blocks{}
+	blockDB.On("ByID", mock.Anything).Return(
+		func(blockID flow.Identifier) *flow.Block {
+			return c.blocks[blockID]
+		},
+		func(blockID flow.Identifier) error {
+			if _, exists := c.blocks[blockID]; !exists {
+				return storerr.ErrNotFound
+			}
+			return nil
+		},
+	)
+	return blockDB
+}
+
+func (c *syntheticChain) resultDB() *storage.ExecutionResults {
+	resultDB := &storage.ExecutionResults{}
+	resultDB.On("ByID", mock.Anything).Return(
+		func(resultID flow.Identifier) *flow.ExecutionResult {
+			return c.results[resultID]
+		},
+		func(resultID flow.Identifier) error {
+			if _, exists := c.results[resultID]; !exists {
+				return storerr.ErrNotFound
+			}
+			return nil
+		},
+	)
+	return resultDB
+}
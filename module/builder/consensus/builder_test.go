@@ -1,9 +1,13 @@
 package consensus
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/rand"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +19,7 @@ import (
 	mempoolImpl "github.com/onflow/flow-go/module/mempool/consensus"
 	mempool "github.com/onflow/flow-go/module/mempool/mock"
 	"github.com/onflow/flow-go/module/metrics"
+	modulemock "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/module/trace"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	storerr "github.com/onflow/flow-go/storage"
@@ -459,6 +464,196 @@ func (bs *BuilderSuite) TestPayloadGuaranteeReferenceExpired() {
 	bs.Assert().ElementsMatch(valid, bs.assembled.Guarantees, "should have valid from mempool in payload")
 }
 
+func (bs *BuilderSuite) TestPayloadGuaranteeReferenceUnfinalized() {
+
+	// create 12 valid guarantees
+	valid := unittest.CollectionGuaranteesFixture(12, unittest.WithCollRef(bs.finalID))
+
+	// create 4 guarantees referencing pending blocks on the fork
+	pending := unittest.CollectionGuaranteesFixture(4, unittest.WithCollRef(bs.pendingBlockIDs[0]))
+
+	// create 2 guarantees referencing a pending block on another fork
+	conflicting := bs.createAndRecordBlock(bs.blocks[bs.finalID])
+	other := unittest.CollectionGuaranteesFixture(2, unittest.WithCollRef(conflicting.ID()))
+
+	// by default, guarantees referencing pending blocks on the fork are included
+	bs.pendingGuarantees = append(append(valid, pending...), other...)
+	_, err := bs.build.BuildOn(bs.parentID, bs.setter)
+	bs.Require().NoError(err)
+	bs.Assert().ElementsMatch(append(valid, pending...), bs.assembled.Guarantees, "should have guarantees referencing blocks on the fork in payload")
+
+	// when configured, guarantees referencing pending blocks are left out
+	bs.build.cfg.finalizedReferencesOnly = true
+	_, err = bs.build.BuildOn(bs.parentID, bs.setter)
+	bs.Require().NoError(err)
+	bs.Assert().ElementsMatch(valid, bs.assembled.Guarantees, "should have only guarantees referencing finalized blocks in payload")
+}
+
+// TestPayloadGuaranteeFairOrdering checks that with fair guarantee ordering, the builder prioritizes
+// guarantees with older reference blocks, and takes turns across clusters, such that a busy cluster
+// does not starve the others.
+func (bs *BuilderSuite) TestPayloadGuaranteeFairOrdering() {
+	bs.build.cfg.guaranteeOrdering = GuaranteeOrderFair
+	bs.build.cfg.maxGuaranteeCount = 6
+
+	collectors := unittest.IdentityListFixture(6, unittest.WithRole(flow.RoleCollection))
+	clustering := flow.ClusterList{collectors[:3], collectors[3:]}
+	signedBy := func(cluster flow.IdentityList) func(*flow.CollectionGuarantee) {
+		return func(guarantee *flow.CollectionGuarantee) {
+			guarantee.SignerIDs = cluster.NodeIDs()
+		}
+	}
+
+	epoch := &protocol.Epoch{}
+	epoch.On("Clustering").Return(clustering, nil)
+	epochs := &protocol.EpochQuery{}
+	epochs.On("Current").Return(epoch)
+	snapshot := &protocol.Snapshot{}
+	snapshot.On("Epochs").Return(epochs)
+	bs.state.On("AtBlockID", mock.Anything).Return(snapshot)
+
+	// the first cluster is busy, and has one guarantee with an older reference block
+	olderRefID := bs.finalizedBlockIDs[len(bs.finalizedBlockIDs)-1]
+	older := unittest.CollectionGuaranteeFixture(unittest.WithCollRef(olderRefID), signedBy(clustering[0]))
+	busy := unittest.CollectionGuaranteesFixture(10, unittest.WithCollRef(bs.finalID), signedBy(clustering[0]))
+	quiet := unittest.CollectionGuaranteesFixture(2, unittest.WithCollRef(bs.finalID), signedBy(clustering[1]))
+
+	bs.pendingGuarantees = append(append(busy, older), quiet...)
+	_, err := bs.build.BuildOn(bs.parentID, bs.setter)
+	bs.Require().NoError(err)
+
+	bs.Require().Len(bs.assembled.Guarantees, 6)
+	bs.Assert().Equal(older, bs.assembled.Guarantees[0], "oldest guarantee should be selected first")
+	bs.Assert().Subset(bs.assembled.Guarantees, quiet, "guarantees of the quiet cluster should not be starved")
+}
+
+// TestPayloadByteSizeLimit checks that the builder leaves out the entities which would make the
+// payload exceed its byte size limit, and records them in the metrics.
+func (bs *BuilderSuite) TestPayloadByteSizeLimit() {
+	bs.pendingGuarantees = unittest.CollectionGuaranteesFixture(16, unittest.WithCollRef(bs.finalID))
+
+	encoded, err := json.Marshal(bs.pendingGuarantees[0])
+	bs.Require().NoError(err)
+	bs.build.cfg.maxPayloadByteSize = uint64(4*len(encoded) + len(encoded)/2)
+
+	conMetrics := &modulemock.ConsensusMetrics{}
+	conMetrics.On("PayloadTruncated", metrics.ResourceGuarantee, uint(12)).Once()
+	bs.build.metrics = conMetrics
+
+	_, err = bs.build.BuildOn(bs.parentID, bs.setter)
+	bs.Require().NoError(err)
+	bs.Assert().Equal(bs.pendingGuarantees[:4], bs.assembled.Guarantees, "should only include the guarantees which fit")
+	conMetrics.AssertExpectations(bs.T())
+}
+
+// TestPayloadBuildDeadline verifies that the builder stops looking for seals once the build
+// deadline has passed, and still proposes the payload assembled so far.
+func (bs *BuilderSuite) TestPayloadBuildDeadline() {
+	bs.pendingGuarantees = unittest.CollectionGuaranteesFixture(4, unittest.WithCollRef(bs.finalID))
+	bs.pendingSeals = bs.irsMap
+	bs.build.cfg.buildDeadline = time.Millisecond
+
+	// make retrieving blocks slow, so that the deadline passes while walking the fork for seals
+	bs.blockDB.ExpectedCalls = nil
+	bs.blockDB.On("ByID", mock.Anything).Return(
+		func(blockID flow.Identifier) *flow.Block {
+			return bs.blocks[blockID]
+		},
+		func(blockID flow.Identifier) error {
+			_, exists := bs.blocks[blockID]
+			if !exists {
+				return storerr.ErrNotFound
+			}
+			return nil
+		},
+	).After(2 * time.Millisecond)
+
+	_, err := bs.build.BuildOn(bs.parentID, bs.setter)
+	bs.Require().NoError(err)
+	bs.Assert().ElementsMatch(bs.pendingGuarantees, bs.assembled.Guarantees, "should include guarantees selected before the deadline")
+	bs.Assert().Empty(bs.assembled.Seals, "should not include seals after the deadline passed")
+}
+
+// TestPayloadConstraintViolated verifies that the builder doesn't extend the state with a
+// payload which violates one of the configured constraints.
+func (bs *BuilderSuite) TestPayloadConstraintViolated() {
+	bs.pendingGuarantees = unittest.CollectionGuaranteesFixture(4, unittest.WithCollRef(bs.finalID))
+	bs.build.cfg.constraints = append(bs.build.cfg.constraints, func(payload *flow.Payload) error {
+		if len(payload.Guarantees) > 2 {
+			return fmt.Errorf("too many guarantees")
+		}
+		return nil
+	})
+
+	_, err := bs.build.BuildOn(bs.parentID, bs.setter)
+	bs.Require().Error(err)
+	bs.Assert().Nil(bs.assembled, "should not extend the state with the payload")
+}
+
+// TestExplainPayload_Guarantees checks that the payload report explains why each guarantee in the
+// mempool was included in the payload or not.
+func (bs *BuilderSuite) TestExplainPayload_Guarantees() {
+	bs.build.cfg.maxGuaranteeCount = 4
+
+	valid := unittest.CollectionGuaranteesFixture(4, unittest.WithCollRef(bs.finalID))
+	overLimit := unittest.CollectionGuaranteesFixture(2, unittest.WithCollRef(bs.finalID))
+	unknown := unittest.CollectionGuaranteeFixture(unittest.WithCollRef(unittest.IdentifierFixture()))
+	duplicated := unittest.CollectionGuaranteeFixture(unittest.WithCollRef(bs.finalID))
+	index := bs.index[bs.pendingBlockIDs[0]]
+	index.CollectionIDs = append(index.CollectionIDs, duplicated.ID())
+
+	bs.pendingGuarantees = append([]*flow.CollectionGuarantee{unknown, duplicated}, append(valid, overLimit...)...)
+	header, err := bs.build.BuildOn(bs.parentID, bs.setter)
+	bs.Require().NoError(err)
+
+	report, err := bs.build.ExplainPayload(header.ID())
+	bs.Require().NoError(err)
+	bs.Assert().Equal(header.ID(), report.BlockID)
+	bs.Assert().Equal(bs.parentID, report.ParentID)
+	bs.Assert().Len(report.Guarantees, len(bs.pendingGuarantees))
+	for _, guarantee := range valid {
+		bs.Assert().Equal(Included, report.Guarantees[guarantee.ID()])
+	}
+	for _, guarantee := range overLimit {
+		bs.Assert().Equal(ExcludedLimitReached, report.Guarantees[guarantee.ID()])
+	}
+	bs.Assert().Equal(ExcludedExpired, report.Guarantees[unknown.ID()])
+	bs.Assert().Equal(ExcludedDuplicate, report.Guarantees[duplicated.ID()])
+
+	// only the last built payload is explained
+	_, err = bs.build.ExplainPayload(bs.parentID)
+	bs.Assert().True(errors.Is(err, storerr.ErrNotFound))
+}
+
+// TestExplainPayload_Seals checks that the payload report explains why each seal in the mempool was
+// included in the payload or not, for the fork
+//   [S] <- [F0] <- [F1] <- [F2] <- [F3] <- [A0] <- [A1] <- [A2] <- [A3]
+// where the candidate seal for [F3] is missing, and a seal for the result of the parent block is
+// pending, which is only incorporated in a child of the parent.
+func (bs *BuilderSuite) TestExplainPayload_Seals() {
+	missing := bs.irsList[3]
+	delete(bs.irsMap, missing.ID())
+
+	bs.createAndRecordBlock(bs.blocks[bs.parentID])
+	otherFork := bs.irsList[len(bs.irsList)-1]
+
+	bs.pendingSeals = bs.irsMap
+	header, err := bs.build.BuildOn(bs.parentID, bs.setter)
+	bs.Require().NoError(err)
+
+	report, err := bs.build.ExplainPayload(header.ID())
+	bs.Require().NoError(err)
+	for _, irSeal := range bs.irsList[:3] {
+		bs.Assert().Equal(Included, report.Seals[irSeal.Seal.ID()])
+	}
+	for _, irSeal := range bs.irsList[4 : len(bs.irsList)-1] {
+		bs.Assert().Equal(ExcludedMissingParentResult, report.Seals[irSeal.Seal.ID()])
+	}
+	bs.Assert().Equal(ExcludedNotOnFork, report.Seals[otherFork.Seal.ID()])
+	_, explained := report.Seals[missing.Seal.ID()]
+	bs.Assert().False(explained, "seal which is not in the mempool should not be explained")
+}
+
 // TestPayloadSeals_AllValid checks that builder seals as many blocks as possible (happy path):
 //  [S] <- [F0] <- [F1] <- [F2] <- [F3] <- [A0] <- [A1] <- [A2] <- [A3]
 // Where block
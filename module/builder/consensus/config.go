@@ -6,6 +6,18 @@ import (
 	"time"
 )
 
+// GuaranteeOrdering is the policy used to select collection guarantees from the mempool, when
+// more guarantees are pending than can be included in a block.
+type GuaranteeOrdering int
+
+const (
+	// GuaranteeOrderMempool selects guarantees in the order they are returned by the mempool.
+	GuaranteeOrderMempool GuaranteeOrdering = iota
+	// GuaranteeOrderFair selects guarantees with older reference blocks first, and takes turns
+	// across collection clusters, so that one busy cluster can't starve the others.
+	GuaranteeOrderFair
+)
+
 type Config struct {
 	minInterval time.Duration
 	maxInterval time.Duration
@@ -13,7 +25,20 @@ type Config struct {
 	maxSealCount      uint
 	maxGuaranteeCount uint
 	maxReceiptCount   uint
-	expiry            uint
+	// the max byte size of a block payload; entities are left out in priority order
+	// (guarantees, then seals, then receipts) once the limit is reached
+	maxPayloadByteSize uint64
+	expiry             uint
+	// the policy to select guarantees when there are more than maxGuaranteeCount
+	guaranteeOrdering GuaranteeOrdering
+	// the max time to spend on selecting the entities of a payload, measured from the start of
+	// BuildOn; once it has passed, receipt and seal selection is cut short (zero means no limit)
+	buildDeadline time.Duration
+	// the constraints checked on each built payload before extending the state
+	constraints []PayloadConstraint
+	// whether only guarantees referencing finalized blocks are included; otherwise, guarantees
+	// may reference blocks which are not finalized yet, as long as they are ancestors of the parent
+	finalizedReferencesOnly bool
 }
 
 func WithMinInterval(minInterval time.Duration) func(*Config) {
@@ -45,3 +70,33 @@ func WithMaxReceiptCount(maxReceiptCount uint) func(*Config) {
 		cfg.maxReceiptCount = maxReceiptCount
 	}
 }
+
+func WithGuaranteeOrdering(ordering GuaranteeOrdering) func(*Config) {
+	return func(cfg *Config) {
+		cfg.guaranteeOrdering = ordering
+	}
+}
+
+func WithMaxPayloadByteSize(maxPayloadByteSize uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxPayloadByteSize = maxPayloadByteSize
+	}
+}
+
+func WithBuildDeadline(buildDeadline time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.buildDeadline = buildDeadline
+	}
+}
+
+func WithPayloadConstraints(constraints ...PayloadConstraint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.constraints = append(cfg.constraints, constraints...)
+	}
+}
+
+func WithFinalizedGuaranteeReferencesOnly(only bool) func(*Config) {
+	return func(cfg *Config) {
+		cfg.finalizedReferencesOnly = only
+	}
+}
@@ -0,0 +1,80 @@
+// (c) 2019 Dapper Labs - ALL RIGHTS RESERVED
+
+package consensus
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// PayloadConstraint is a rule that the builder checks on each payload it builds, before it
+// extends the protocol state with the new block. If a constraint returns an error, the builder
+// aborts building the block. Constraints allow to add new protocol rules for payloads without
+// changing the selection logic of the builder.
+type PayloadConstraint func(*flow.Payload) error
+
+// NoDuplicates requires that the payload doesn't contain any guarantee, seal, receipt or
+// result more than once, and that it doesn't contain more than one seal for the same block.
+func NoDuplicates(payload *flow.Payload) error {
+	err := checkUniqueIDs("guarantee", flow.GetIDs(payload.Guarantees))
+	if err != nil {
+		return err
+	}
+	err = checkUniqueIDs("seal", flow.GetIDs(payload.Seals))
+	if err != nil {
+		return err
+	}
+	err = checkUniqueIDs("receipt", flow.GetIDs(payload.Receipts))
+	if err != nil {
+		return err
+	}
+	err = checkUniqueIDs("result", flow.GetIDs(payload.Results))
+	if err != nil {
+		return err
+	}
+
+	sealedBlockIDs := make([]flow.Identifier, 0, len(payload.Seals))
+	for _, seal := range payload.Seals {
+		sealedBlockIDs = append(sealedBlockIDs, seal.BlockID)
+	}
+	return checkUniqueIDs("sealed block", sealedBlockIDs)
+}
+
+func checkUniqueIDs(kind string, ids []flow.Identifier) error {
+	lookup := make(map[flow.Identifier]struct{}, len(ids))
+	for _, id := range ids {
+		_, duplicated := lookup[id]
+		if duplicated {
+			return fmt.Errorf("duplicate %s (%x) in payload", kind, id)
+		}
+		lookup[id] = struct{}{}
+	}
+	return nil
+}
+
+// ServiceEventOrdering requires that the service events of the results in the payload are
+// ordered: the epoch commit event of an epoch can't come before the epoch setup event of the
+// same epoch, if both are part of the payload. Results for the same block (execution forks)
+// may repeat the same events, so events are compared by epoch counter only.
+func ServiceEventOrdering(payload *flow.Payload) error {
+
+	// epoch counters of the commit events we have seen so far
+	commits := make(map[uint64]struct{})
+
+	for _, result := range payload.Results {
+		for _, event := range result.ServiceEvents {
+			switch ev := event.Event.(type) {
+			case *flow.EpochSetup:
+				_, committed := commits[ev.Counter]
+				if committed {
+					return fmt.Errorf("epoch setup for epoch %d after its epoch commit in payload (result: %x)", ev.Counter, result.ID())
+				}
+			case *flow.EpochCommit:
+				commits[ev.Counter] = struct{}{}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestNoDuplicates(t *testing.T) {
+
+	t.Run("valid payload", func(t *testing.T) {
+		payload := unittest.PayloadFixture(unittest.WithAllTheFixins)
+		assert.NoError(t, NoDuplicates(&payload))
+	})
+
+	t.Run("duplicate guarantee", func(t *testing.T) {
+		guarantee := unittest.CollectionGuaranteeFixture()
+		payload := flow.Payload{Guarantees: []*flow.CollectionGuarantee{guarantee, guarantee}}
+		assert.Error(t, NoDuplicates(&payload))
+	})
+
+	t.Run("two seals for same block", func(t *testing.T) {
+		blockID := unittest.IdentifierFixture()
+		payload := flow.Payload{Seals: []*flow.Seal{
+			unittest.Seal.Fixture(unittest.Seal.WithBlockID(blockID)),
+			unittest.Seal.Fixture(unittest.Seal.WithBlockID(blockID)),
+		}}
+		assert.Error(t, NoDuplicates(&payload))
+	})
+}
+
+func TestServiceEventOrdering(t *testing.T) {
+
+	resultWithEvents := func(events ...flow.ServiceEvent) *flow.ExecutionResult {
+		result := unittest.ExecutionResultFixture()
+		result.ServiceEvents = events
+		return result
+	}
+	setup := unittest.EpochSetupFixture(unittest.SetupWithCounter(2)).ServiceEvent()
+	commit := unittest.EpochCommitFixture(unittest.CommitWithCounter(2)).ServiceEvent()
+
+	t.Run("setup before commit", func(t *testing.T) {
+		payload := flow.Payload{Results: flow.ExecutionResultList{
+			resultWithEvents(setup),
+			resultWithEvents(commit),
+		}}
+		assert.NoError(t, ServiceEventOrdering(&payload))
+	})
+
+	t.Run("setup and commit in same result", func(t *testing.T) {
+		payload := flow.Payload{Results: flow.ExecutionResultList{resultWithEvents(setup, commit)}}
+		assert.NoError(t, ServiceEventOrdering(&payload))
+	})
+
+	t.Run("commit before setup", func(t *testing.T) {
+		payload := flow.Payload{Results: flow.ExecutionResultList{
+			resultWithEvents(commit),
+			resultWithEvents(setup),
+		}}
+		assert.Error(t, ServiceEventOrdering(&payload))
+	})
+}
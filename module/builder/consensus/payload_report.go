@@ -0,0 +1,84 @@
+package consensus
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Inclusion is the decision of the builder about a candidate entity for a payload.
+type Inclusion int
+
+const (
+	// Included means the entity was included in the payload.
+	Included Inclusion = iota
+	// ExcludedDuplicate means the entity was already included in the fork, or, for seals and
+	// receipts, that its block is already sealed in the fork.
+	ExcludedDuplicate
+	// ExcludedExpired means the reference block of the guarantee is unknown or expired.
+	ExcludedExpired
+	// ExcludedMissingParentResult means the seal doesn't connect to the last sealed result in
+	// the fork, as there is no seal for the parent result yet.
+	ExcludedMissingParentResult
+	// ExcludedLimitReached means the entity didn't fit into the payload, as the count limit or
+	// the byte size limit of the payload was reached.
+	ExcludedLimitReached
+	// ExcludedNotOnFork means the seal is for a result which wasn't incorporated in the fork.
+	ExcludedNotOnFork
+	// ExcludedDeadlineExceeded means the build deadline passed before the entity was considered.
+	ExcludedDeadlineExceeded
+	// ExcludedUnfinalizedReference means the reference block of the guarantee is not finalized
+	// yet, and the builder is configured to only include guarantees referencing finalized blocks.
+	ExcludedUnfinalizedReference
+)
+
+func (i Inclusion) String() string {
+	switch i {
+	case Included:
+		return "included"
+	case ExcludedDuplicate:
+		return "duplicate"
+	case ExcludedExpired:
+		return "expired"
+	case ExcludedMissingParentResult:
+		return "missing_parent_result"
+	case ExcludedLimitReached:
+		return "limit_reached"
+	case ExcludedNotOnFork:
+		return "not_on_fork"
+	case ExcludedDeadlineExceeded:
+		return "deadline_exceeded"
+	case ExcludedUnfinalizedReference:
+		return "unfinalized_reference"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(i))
+	}
+}
+
+// MarshalText encodes the inclusion by its name, so that reports are readable when encoded as JSON.
+func (i Inclusion) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// PayloadReport explains why the candidate entities in the mempools were included in or excluded
+// from a payload built by the builder.
+//
+// Guarantees are keyed by collection ID, seals by seal ID and receipts by receipt ID. The report
+// covers all guarantees and seals in the mempools, and the receipts which the execution tree
+// considered when traversing the results descending from the last sealed result.
+type PayloadReport struct {
+	BlockID    flow.Identifier
+	ParentID   flow.Identifier
+	Guarantees map[flow.Identifier]Inclusion
+	Seals      map[flow.Identifier]Inclusion
+	Receipts   map[flow.Identifier]Inclusion
+}
+
+func newPayloadReport(parentID flow.Identifier) *PayloadReport {
+	return &PayloadReport{
+		ParentID:   parentID,
+		Guarantees: make(map[flow.Identifier]Inclusion),
+		Seals:      make(map[flow.Identifier]Inclusion),
+		Receipts:   make(map[flow.Identifier]Inclusion),
+	}
+}
@@ -14,21 +14,29 @@ type ChunkAssigner interface {
 	//    (which contains the block's source of randomness)
 	//  * unexpected errors should be considered symptoms of internal bugs
 	Assign(result *flow.ExecutionResult, blockID flow.Identifier) (*chmodels.Assignment, error)
+
+	// Explain returns the assignment along with the source of randomness and the verifiers it is
+	// computed from, so that verification operators can audit why their node was assigned chunks or not.
+	// It returns the same errors as Assign.
+	Explain(result *flow.ExecutionResult, blockID flow.Identifier) (*chmodels.AssignmentExplanation, error)
 }
 
 // ChunkVerifier provides functionality to verify chunks
 type ChunkVerifier interface {
 	// Verify verifies the given VerifiableChunk by executing it and checking the final state commitment
-	// It returns a Spock Secret as a byte array, verification fault of the chunk, and an error.
+	// It returns a Spock Secret as a byte array, a report of the execution of the chunk's transactions,
+	// verification fault of the chunk, and an error. The report is nil if the transactions of the chunk
+	// could not be executed.
 	// Note: Verify should only be executed on non-system chunks. It returns an error if it is invoked on
 	// system chunk.
 	// TODO return challenges plus errors
-	Verify(ch *verification.VerifiableChunkData) ([]byte, chmodels.ChunkFault, error)
+	Verify(ch *verification.VerifiableChunkData) ([]byte, *chmodels.ChunkVerificationReport, chmodels.ChunkFault, error)
 
 	// VerifySystemChunk verifies a given VerifiableChunk corresponding to a system chunk.
 	// by executing it and checking the final state commitment
-	// It returns a Spock Secret as a byte array, verification fault of the chunk, and an error.
+	// It returns a Spock Secret as a byte array, a report of the execution of the chunk's transactions,
+	// verification fault of the chunk, and an error.
 	// Note: Verify should only be executed on system chunks. It returns an error if it is invoked on
 	// non-system chunks.
-	SystemChunkVerify(ch *verification.VerifiableChunkData) ([]byte, chmodels.ChunkFault, error)
+	SystemChunkVerify(ch *verification.VerifiableChunkData) ([]byte, *chmodels.ChunkVerificationReport, chmodels.ChunkFault, error)
 }
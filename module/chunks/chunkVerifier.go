@@ -3,6 +3,7 @@ package chunks
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	executionState "github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/fvm/programs"
@@ -15,6 +16,7 @@ import (
 	"github.com/onflow/flow-go/ledger/partial"
 	chmodels "github.com/onflow/flow-go/model/chunks"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/seed"
 )
 
 type VirtualMachine interface {
@@ -23,89 +25,137 @@ type VirtualMachine interface {
 
 // ChunkVerifier is a verifier based on the current definitions of the flow network
 type ChunkVerifier struct {
-	vm             VirtualMachine
-	vmCtx          fvm.Context
-	systemChunkCtx fvm.Context
+	vm       VirtualMachine
+	vmCtx    fvm.Context
+	registry *ContextRegistry
 }
 
 // NewChunkVerifier creates a chunk verifier containing a flow virtual machine
 func NewChunkVerifier(vm VirtualMachine, vmCtx fvm.Context) *ChunkVerifier {
+	return NewChunkVerifierWithRegistry(vm, vmCtx, NewContextRegistry())
+}
+
+// NewChunkVerifierWithRegistry creates a chunk verifier which verifies chunks in the context
+// registered for the execution metadata of the receipts committing to their results, derived
+// from the given base context.
+func NewChunkVerifierWithRegistry(vm VirtualMachine, vmCtx fvm.Context, registry *ContextRegistry) *ChunkVerifier {
 	return &ChunkVerifier{
-		vm:    vm,
-		vmCtx: vmCtx,
-		systemChunkCtx: fvm.NewContextFromParent(vmCtx,
-			fvm.WithRestrictedAccountCreation(false),
-			fvm.WithRestrictedDeployment(false),
-			fvm.WithServiceEventCollectionEnabled(),
-			fvm.WithTransactionProcessors(fvm.NewTransactionInvocator(vmCtx.Logger)),
-		),
+		vm:       vm,
+		vmCtx:    vmCtx,
+		registry: registry,
 	}
 }
 
+// contextFor returns the context to verify the given chunk in, which is the base context
+// configured with the options registered for the execution metadata of the chunk.
+func (fcv *ChunkVerifier) contextFor(vc *verification.VerifiableChunkData) (fvm.Context, error) {
+	options, err := fcv.registry.Select(vc.ExecutionMetadata)
+	if err != nil {
+		return fvm.Context{}, fmt.Errorf("could not select verification context: %w", err)
+	}
+	return fvm.NewContextFromParent(fcv.vmCtx, options...), nil
+}
+
 // Verify verifies a given VerifiableChunk corresponding to a non-system chunk.
 // by executing it and checking the final state commitment
-// It returns a Spock Secret as a byte array, verification fault of the chunk, and an error.
+// It returns a Spock Secret as a byte array, a report of the execution of the chunk's transactions,
+// verification fault of the chunk, and an error.
 // Note: Verify should only be executed on non-system chunks. It returns an error if it is invoked on
 // system chunks.
-func (fcv *ChunkVerifier) Verify(vc *verification.VerifiableChunkData) ([]byte, chmodels.ChunkFault, error) {
+func (fcv *ChunkVerifier) Verify(vc *verification.VerifiableChunkData) ([]byte, *chmodels.ChunkVerificationReport, chmodels.ChunkFault, error) {
 	if vc.IsSystemChunk {
-		return nil, nil, fmt.Errorf("wrong method invoked for verifying system chunk")
+		return nil, nil, nil, fmt.Errorf("wrong method invoked for verifying system chunk")
 	}
 
+	// transactions are indexed within the block, so they are offset by the
+	// transactions of the collections preceding the chunk in the block
 	transactions := make([]*fvm.TransactionProcedure, 0)
 	for i, txBody := range vc.Collection.Transactions {
-		tx := fvm.Transaction(txBody, uint32(i))
+		tx := fvm.Transaction(txBody, vc.TxOffset+uint32(i))
 		transactions = append(transactions, tx)
 	}
 
-	return fcv.verifyTransactions(vc.Chunk, vc.ChunkDataPack, vc.Result, vc.Header, transactions, vc.EndState)
+	ctx, err := fcv.contextFor(vc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return fcv.verifyTransactions(ctx, vc.Chunk, vc.ChunkDataPack, vc.Result, vc.Header, transactions, vc.EndState)
 }
 
 // SystemChunkVerify verifies a given VerifiableChunk corresponding to a system chunk.
 // by executing it and checking the final state commitment
-// It returns a Spock Secret as a byte array, verification fault of the chunk, and an error.
+// It returns a Spock Secret as a byte array, a report of the execution of the chunk's transactions,
+// verification fault of the chunk, and an error.
 // Note: SystemChunkVerify should only be executed on system chunks. It returns an error if it is invoked on
 // non-system chunks.
-func (fcv *ChunkVerifier) SystemChunkVerify(vc *verification.VerifiableChunkData) ([]byte, chmodels.ChunkFault, error) {
+func (fcv *ChunkVerifier) SystemChunkVerify(vc *verification.VerifiableChunkData) ([]byte, *chmodels.ChunkVerificationReport, chmodels.ChunkFault, error) {
 	if !vc.IsSystemChunk {
-		return nil, nil, fmt.Errorf("wrong method invoked for verifying non-system chunk")
+		return nil, nil, nil, fmt.Errorf("wrong method invoked for verifying non-system chunk")
+	}
+
+	ctx, err := fcv.contextFor(vc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// transactions of system chunk, one per system contract call activated for the epoch of the block
+	systemContracts := fvm.SystemContractsForChain(ctx.Chain)
+	calls := systemContracts.Calls(vc.EpochCounter)
+	transactions := make([]*fvm.TransactionProcedure, 0, len(calls))
+	for i, call := range calls {
+		transactions = append(transactions, fvm.Transaction(call.Transaction(systemContracts.Chain), vc.TxOffset+uint32(i)))
 	}
 
-	// transaction body of system chunk
-	txBody := fvm.SystemChunkTransaction(fcv.vmCtx.Chain.ServiceAddress())
-	tx := fvm.Transaction(txBody, uint32(0))
-	transactions := []*fvm.TransactionProcedure{tx}
+	randomSource, err := seed.ExecutionRandomSource(vc.Header)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not derive random source for block: %w", err)
+	}
 
-	systemChunkContext := fvm.NewContextFromParent(fcv.systemChunkCtx,
+	systemChunkContext := fvm.NewContextFromParent(ctx,
+		fvm.WithRestrictedAccountCreation(false),
+		fvm.WithRestrictedDeployment(false),
+		fvm.WithServiceEventCollectionEnabled(),
+		fvm.WithTransactionProcessors(fvm.NewTransactionInvocator(ctx.Logger)),
 		fvm.WithBlockHeader(vc.Header),
+		fvm.WithBlockRandomSource(randomSource),
 	)
 
-	return fcv.verifyTransactionsInContext(systemChunkContext, vc.Chunk, vc.ChunkDataPack, vc.Result, transactions, vc.EndState)
+	return fcv.verifyTransactionsInContext(systemChunkContext, vc.Chunk, vc.ChunkDataPack, vc.Result, transactions, calls, vc.EndState)
 }
 
+// verifyTransactionsInContext executes the given transactions of the chunk in the given context,
+// and checks the outcome against the chunk. The system contract calls are only given for the
+// system chunk, one per transaction, in which case the service events claimed by the result are
+// checked against the ones emitted by the chunk.
 func (fcv *ChunkVerifier) verifyTransactionsInContext(context fvm.Context, chunk *flow.Chunk,
 	chunkDataPack *flow.ChunkDataPack,
 	result *flow.ExecutionResult,
 	transactions []*fvm.TransactionProcedure,
-	endState flow.StateCommitment) ([]byte, chmodels.ChunkFault, error) {
+	systemCalls []fvm.SystemContractCall,
+	endState flow.StateCommitment) ([]byte, *chmodels.ChunkVerificationReport, chmodels.ChunkFault, error) {
 
 	// TODO check collection hash to match
 	// TODO check datapack hash to match
-	// TODO check the number of transactions and computation used
 
+	start := time.Now()
 	chIndex := chunk.Index
 	execResID := result.ID()
 
 	if chunkDataPack == nil {
-		return nil, nil, fmt.Errorf("missing chunk data pack")
+		return nil, nil, nil, fmt.Errorf("missing chunk data pack")
 	}
 
 	// constructing a partial trie given chunk data package
-	psmt, err := partial.NewLedger(chunkDataPack.Proof, ledger.State(chunkDataPack.StartState), partial.DefaultPathFinderVersion)
+	psmt, err := partial.NewLedger([]ledger.Proof{chunkDataPack.Proof}, ledger.State(chunkDataPack.StartState), partial.DefaultPathFinderVersion)
 
+	if errors.Is(err, ledger.ErrInvalidProofForPath{}) {
+		return nil, nil, chmodels.NewCFInvalidVerifiableChunk("invalid proofs of register touches: ", err, chIndex, execResID),
+			nil
+	}
 	if err != nil {
 		// TODO provide more details based on the error type
-		return nil, chmodels.NewCFInvalidVerifiableChunk("error constructing partial trie: ", err, chIndex, execResID),
+		return nil, nil, chmodels.NewCFInvalidVerifiableChunk("error constructing partial trie: ", err, chIndex, execResID),
 			nil
 	}
 
@@ -153,23 +203,34 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(context fvm.Context, chunk
 
 	chunkView := delta.NewView(getRegister)
 
+	report := &chmodels.ChunkVerificationReport{
+		ChunkIndex:   chIndex,
+		ResultID:     execResID,
+		Transactions: make([]chmodels.TransactionReport, 0, len(transactions)),
+	}
+	// the report covers the whole verification of the chunk, whichever way it ends
+	defer func() {
+		report.Duration = time.Since(start)
+	}()
+
 	// executes all transactions in this chunk
 	for i, tx := range transactions {
 		txView := chunkView.NewChild()
-
-		// tx := fvm.Transaction(txBody, uint32(i))
+		txStart := time.Now()
 
 		err := fcv.vm.Run(context, tx, txView, programs)
 		if err != nil {
 			// this covers unexpected and very rare cases (e.g. system memory issues...),
 			// so we shouldn't be here even if transaction naturally fails (e.g. permission, runtime ... )
-			return nil, nil, fmt.Errorf("failed to execute transaction: %d (%w)", i, err)
+			return nil, nil, nil, fmt.Errorf("failed to execute transaction: %d (%w)", i, err)
 		}
 
+		report.Transactions = append(report.Transactions, transactionReport(tx, txView, time.Since(txStart)))
+
 		// always merge back the tx view (fvm is responsible for changes on tx errors)
 		err = chunkView.MergeView(txView)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to execute transaction: %d (%w)", i, err)
+			return nil, nil, nil, fmt.Errorf("failed to execute transaction: %d (%w)", i, err)
 		}
 	}
 
@@ -179,7 +240,39 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(context fvm.Context, chunk
 		for _, key := range unknownRegTouch {
 			missingRegs = append(missingRegs, key.String())
 		}
-		return nil, chmodels.NewCFMissingRegisterTouch(missingRegs, chIndex, execResID), nil
+		return nil, report, chmodels.NewCFMissingRegisterTouch(missingRegs, chIndex, execResID), nil
+	}
+
+	// check the number of transactions and the computation used against the chunk
+	if uint64(len(report.Transactions)) != chunk.NumberOfTransactions {
+		reason := fmt.Sprintf("%d transactions claimed, but %d executed", chunk.NumberOfTransactions, len(report.Transactions))
+		return nil, report, chmodels.NewCFInvalidChunkMetadata(reason, chIndex, execResID), nil
+	}
+	if report.ComputationUsed() != chunk.TotalComputationUsed {
+		reason := fmt.Sprintf("computation used of %d claimed, but %d used", chunk.TotalComputationUsed, report.ComputationUsed())
+		return nil, report, chmodels.NewCFInvalidChunkMetadata(reason, chIndex, execResID), nil
+	}
+
+	// check the events emitted by the transactions against the event collection hash of the chunk
+	events := make(flow.EventsList, 0)
+	for _, tx := range transactions {
+		events = append(events, tx.Events...)
+	}
+	eventsHash := events.Hash()
+	if eventsHash != chunk.EventCollection {
+		return nil, report, chmodels.NewCFInvalidEventsCollection(chunk.EventCollection, eventsHash, chIndex, execResID), nil
+	}
+
+	// only the system chunk emits service events, so the service events claimed by the result
+	// are all emitted by the system chunk
+	if systemCalls != nil {
+		reason, err := checkServiceEvents(result.ServiceEvents, transactions, systemCalls)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot check service events: %w", err)
+		}
+		if reason != "" {
+			return nil, report, chmodels.NewCFInvalidServiceEvents(reason, chIndex, execResID), nil
+		}
 	}
 
 	// applying chunk delta (register updates at chunk level) to the partial trie
@@ -193,7 +286,7 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(context fvm.Context, chunk
 		executionState.RegisterValuesToValues(values),
 	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot create ledger update: %w", err)
+		return nil, nil, nil, fmt.Errorf("cannot create ledger update: %w", err)
 	}
 
 	expEndStateComm, err := psmt.Set(update)
@@ -205,29 +298,92 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(context fvm.Context, chunk
 			for i, key := range keys {
 				stringKeys[i] = key.String()
 			}
-			return nil, chmodels.NewCFMissingRegisterTouch(stringKeys, chIndex, execResID), nil
+			return nil, report, chmodels.NewCFMissingRegisterTouch(stringKeys, chIndex, execResID), nil
 		}
-		return nil, chmodels.NewCFMissingRegisterTouch(nil, chIndex, execResID), nil
+		return nil, report, chmodels.NewCFMissingRegisterTouch(nil, chIndex, execResID), nil
 	}
 
 	// TODO check if exec node provided register touches that was not used (no read and no update)
 	// check if the end state commitment mentioned in the chunk matches
 	// what the partial trie is providing.
 	if flow.StateCommitment(expEndStateComm) != endState {
-		return nil, chmodels.NewCFNonMatchingFinalState(flow.StateCommitment(expEndStateComm), endState, chIndex, execResID), nil
+		return nil, report, chmodels.NewCFNonMatchingFinalState(flow.StateCommitment(expEndStateComm), endState, chIndex, execResID), nil
 	}
-	return chunkView.SpockSecret(), nil, nil
+	return chunkView.SpockSecret(), report, nil, nil
 }
 
-func (fcv *ChunkVerifier) verifyTransactions(chunk *flow.Chunk,
+func (fcv *ChunkVerifier) verifyTransactions(ctx fvm.Context,
+	chunk *flow.Chunk,
 	chunkDataPack *flow.ChunkDataPack,
 	result *flow.ExecutionResult,
 	header *flow.Header,
 	transactions []*fvm.TransactionProcedure,
-	endState flow.StateCommitment) ([]byte, chmodels.ChunkFault, error) {
+	endState flow.StateCommitment) ([]byte, *chmodels.ChunkVerificationReport, chmodels.ChunkFault, error) {
+
+	// the random source is derived from the header, the same way as on the execution node
+	randomSource, err := seed.ExecutionRandomSource(header)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not derive random source for block: %w", err)
+	}
 
 	// build a block context
-	blockCtx := fvm.NewContextFromParent(fcv.vmCtx, fvm.WithBlockHeader(header))
+	blockCtx := fvm.NewContextFromParent(
+		ctx,
+		fvm.WithBlockHeader(header),
+		fvm.WithBlockRandomSource(randomSource),
+	)
+
+	return fcv.verifyTransactionsInContext(blockCtx, chunk, chunkDataPack, result, transactions, nil, endState)
+}
 
-	return fcv.verifyTransactionsInContext(blockCtx, chunk, chunkDataPack, result, transactions, endState)
+// checkServiceEvents checks the service events claimed by a result against the ones emitted by
+// the transactions of its system chunk, executed for the given system contract calls. The service
+// events are emitted the same way as on execution nodes, including the events reporting failed
+// calls. It returns the reason for which the claimed service events are invalid, or an empty
+// string if they are valid.
+func checkServiceEvents(claimed []flow.ServiceEvent, transactions []*fvm.TransactionProcedure, calls []fvm.SystemContractCall) (string, error) {
+	emitted := make([]flow.Event, 0, len(claimed))
+	for i, tx := range transactions {
+		emitted = append(emitted, tx.ServiceEvents...)
+		if tx.Err == nil {
+			continue
+		}
+		event, err := fvm.SystemContractCallFailedEvent(calls[i], tx)
+		if err != nil {
+			return "", fmt.Errorf("could not create event for failed system contract call %s: %w", calls[i].Name, err)
+		}
+		emitted = append(emitted, event)
+	}
+
+	if len(emitted) != len(claimed) {
+		return fmt.Sprintf("%d service events claimed, but %d emitted", len(claimed), len(emitted)), nil
+	}
+	for i, event := range emitted {
+		converted, err := flow.ConvertServiceEvent(event)
+		if err != nil {
+			return fmt.Sprintf("emitted service event %d (%s) cannot be converted: %v", i, event.Type, err), nil
+		}
+		if flow.MakeID(*converted) != flow.MakeID(claimed[i]) {
+			return fmt.Sprintf("claimed service event %d (%s) doesn't match the emitted one (%s)", i, claimed[i].Type, converted.Type), nil
+		}
+	}
+
+	return "", nil
+}
+
+// transactionReport summarizes the outcome of executing the given transaction in the given view.
+func transactionReport(tx *fvm.TransactionProcedure, txView state.View, duration time.Duration) chmodels.TransactionReport {
+	updates, _ := txView.RegisterUpdates()
+	report := chmodels.TransactionReport{
+		TransactionID:   tx.ID,
+		ComputationUsed: tx.GasUsed,
+		RegisterTouches: len(txView.AllRegisters()),
+		RegisterUpdates: len(updates),
+		Duration:        duration,
+	}
+	if tx.Err != nil {
+		report.Failed = true
+		report.ErrorMessage = tx.Err.Error()
+	}
+	return report
 }
@@ -53,10 +53,24 @@ func TestChunkVerifier(t *testing.T) {
 func (s *ChunkVerifierTestSuite) TestHappyPath() {
 	vch := GetBaselineVerifiableChunk(s.T(), []byte{})
 	assert.NotNil(s.T(), vch)
-	spockSecret, chFaults, err := s.verifier.Verify(vch)
+	spockSecret, report, chFaults, err := s.verifier.Verify(vch)
 	assert.Nil(s.T(), err)
 	assert.Nil(s.T(), chFaults)
 	assert.NotNil(s.T(), spockSecret)
+
+	require.NotNil(s.T(), report)
+	assert.Equal(s.T(), vch.Chunk.Index, report.ChunkIndex)
+	assert.Equal(s.T(), vch.Result.ID(), report.ResultID)
+	require.Len(s.T(), report.Transactions, len(vch.Collection.Transactions))
+	for i, tx := range report.Transactions {
+		assert.Equal(s.T(), vch.Collection.Transactions[i].ID(), tx.TransactionID)
+		assert.False(s.T(), tx.Failed)
+		// each transaction reads two registers and updates one of them
+		assert.Equal(s.T(), 2, tx.RegisterTouches)
+		assert.Equal(s.T(), 1, tx.RegisterUpdates)
+	}
+	assert.Equal(s.T(), 0, report.FailedTransactions())
+	assert.Equal(s.T(), 2*len(report.Transactions), report.RegisterTouches())
 }
 
 // TestMissingRegisterTouchForUpdate tests verification given a chunkdatapack missing a register touch (update)
@@ -67,7 +81,7 @@ func (s *ChunkVerifierTestSuite) TestMissingRegisterTouchForUpdate() {
 	assert.NotNil(s.T(), vch)
 	// remove the second register touch
 	//vch.ChunkDataPack.RegisterTouches = vch.ChunkDataPack.RegisterTouches[:1]
-	spockSecret, chFaults, err := s.verifier.Verify(vch)
+	spockSecret, _, chFaults, err := s.verifier.Verify(vch)
 	assert.Nil(s.T(), err)
 	assert.NotNil(s.T(), chFaults)
 	assert.Nil(s.T(), spockSecret)
@@ -82,7 +96,7 @@ func (s *ChunkVerifierTestSuite) TestMissingRegisterTouchForRead() {
 	assert.NotNil(s.T(), vch)
 	// remove the second register touch
 	//vch.ChunkDataPack.RegisterTouches = vch.ChunkDataPack.RegisterTouches[1:]
-	spockSecret, chFaults, err := s.verifier.Verify(vch)
+	spockSecret, _, chFaults, err := s.verifier.Verify(vch)
 	assert.Nil(s.T(), err)
 	assert.NotNil(s.T(), chFaults)
 	assert.Nil(s.T(), spockSecret)
@@ -96,12 +110,15 @@ func (s *ChunkVerifierTestSuite) TestMissingRegisterTouchForRead() {
 func (s *ChunkVerifierTestSuite) TestWrongEndState() {
 	vch := GetBaselineVerifiableChunk(s.T(), []byte("wrongEndState"))
 	assert.NotNil(s.T(), vch)
-	spockSecret, chFaults, err := s.verifier.Verify(vch)
+	spockSecret, report, chFaults, err := s.verifier.Verify(vch)
 	assert.Nil(s.T(), err)
 	assert.NotNil(s.T(), chFaults)
 	assert.Nil(s.T(), spockSecret)
 	_, ok := chFaults.(*chunksmodels.CFNonMatchingFinalState)
 	assert.True(s.T(), ok)
+	// the transactions were executed before the fault was found, so they are reported
+	require.NotNil(s.T(), report)
+	assert.Len(s.T(), report.Transactions, len(vch.Collection.Transactions))
 }
 
 // TestFailedTx tests verification behavior in case
@@ -110,10 +127,83 @@ func (s *ChunkVerifierTestSuite) TestWrongEndState() {
 func (s *ChunkVerifierTestSuite) TestFailedTx() {
 	vch := GetBaselineVerifiableChunk(s.T(), []byte("failedTx"))
 	assert.NotNil(s.T(), vch)
-	spockSecret, chFaults, err := s.verifier.Verify(vch)
+	// the failed transaction uses computation
+	vch.Chunk.TotalComputationUsed = 7
+	spockSecret, report, chFaults, err := s.verifier.Verify(vch)
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), chFaults)
+	assert.NotNil(s.T(), spockSecret)
+
+	// the failed transaction is reported with its error and computation
+	require.NotNil(s.T(), report)
+	assert.Equal(s.T(), 1, report.FailedTransactions())
+	failed := report.Transactions[3]
+	assert.True(s.T(), failed.Failed)
+	assert.NotEmpty(s.T(), failed.ErrorMessage)
+	assert.Equal(s.T(), uint64(7), failed.ComputationUsed)
+	assert.Equal(s.T(), uint64(7), report.ComputationUsed())
+}
+
+// TestEventsMismatch tests verification behavior in case the events emitted by the
+// transactions don't match the event collection hash of the chunk.
+func (s *ChunkVerifierTestSuite) TestEventsMismatch() {
+	vch := GetBaselineVerifiableChunk(s.T(), []byte("eventsMismatch"))
+	assert.NotNil(s.T(), vch)
+	spockSecret, _, chFaults, err := s.verifier.Verify(vch)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), chFaults)
+	assert.Nil(s.T(), spockSecret)
+	_, ok := chFaults.(*chunksmodels.CFInvalidEventsCollection)
+	assert.True(s.T(), ok)
+}
+
+// TestServiceEvents tests verification of the service events claimed by the result of a
+// system chunk, which must match the ones emitted by the chunk.
+func (s *ChunkVerifierTestSuite) TestServiceEvents() {
+	vch := GetBaselineVerifiableChunk(s.T(), []byte{})
+	vch.IsSystemChunk = true
+	vch.Collection = nil
+	vch.Chunk.NumberOfTransactions = uint64(len(fvm.SystemContractsForChain(flow.Mainnet.Chain()).Calls(vch.EpochCounter)))
+
+	// the mocked vm emits no service events
+	spockSecret, _, chFaults, err := s.verifier.SystemChunkVerify(vch)
 	assert.Nil(s.T(), err)
 	assert.Nil(s.T(), chFaults)
 	assert.NotNil(s.T(), spockSecret)
+
+	vch.Result.ServiceEvents = []flow.ServiceEvent{unittest.EpochSetupFixture().ServiceEvent()}
+	spockSecret, _, chFaults, err = s.verifier.SystemChunkVerify(vch)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), chFaults)
+	assert.Nil(s.T(), spockSecret)
+	_, ok := chFaults.(*chunksmodels.CFInvalidServiceEvents)
+	assert.True(s.T(), ok)
+}
+
+// TestChunkMetadataMismatch tests verification behavior in case the number of transactions
+// or the computation used provided by the chunk don't match the execution of the chunk.
+func (s *ChunkVerifierTestSuite) TestChunkMetadataMismatch() {
+	s.Run("number of transactions", func() {
+		vch := GetBaselineVerifiableChunk(s.T(), []byte{})
+		vch.Chunk.NumberOfTransactions++
+		spockSecret, report, chFaults, err := s.verifier.Verify(vch)
+		assert.Nil(s.T(), err)
+		assert.Nil(s.T(), spockSecret)
+		assert.NotNil(s.T(), report)
+		_, ok := chFaults.(*chunksmodels.CFInvalidChunkMetadata)
+		assert.True(s.T(), ok)
+	})
+
+	s.Run("computation used", func() {
+		// the failed transaction uses computation, which the chunk doesn't account for
+		vch := GetBaselineVerifiableChunk(s.T(), []byte("failedTx"))
+		spockSecret, report, chFaults, err := s.verifier.Verify(vch)
+		assert.Nil(s.T(), err)
+		assert.Nil(s.T(), spockSecret)
+		assert.NotNil(s.T(), report)
+		_, ok := chFaults.(*chunksmodels.CFInvalidChunkMetadata)
+		assert.True(s.T(), ok)
+	})
 }
 
 // TestVerifyWrongChunkType evaluates that following invocations return an error:
@@ -125,7 +215,7 @@ func (s *ChunkVerifierTestSuite) TestVerifyWrongChunkType() {
 		IsSystemChunk: true,
 	}
 	// invoking Verify method with system chunk should return an error
-	_, _, err := s.verifier.Verify(svc)
+	_, _, _, err := s.verifier.Verify(svc)
 	require.Error(s.T(), err)
 
 	// defines verifiable chunk for a non-system chunk
@@ -133,7 +223,7 @@ func (s *ChunkVerifierTestSuite) TestVerifyWrongChunkType() {
 		IsSystemChunk: false,
 	}
 	// invoking SystemChunkVerify method with a non-system chunk should return an error
-	_, _, err = s.verifier.SystemChunkVerify(vc)
+	_, _, _, err = s.verifier.SystemChunkVerify(vc)
 	require.Error(s.T(), err)
 }
 
@@ -144,13 +234,56 @@ func (s *ChunkVerifierTestSuite) TestEmptyCollection() {
 	assert.NotNil(s.T(), vch)
 	col := unittest.CollectionFixture(0)
 	vch.Collection = &col
+	vch.Chunk.NumberOfTransactions = 0
 	vch.EndState = vch.ChunkDataPack.StartState
-	spockSecret, chFaults, err := s.verifier.Verify(vch)
+	spockSecret, _, chFaults, err := s.verifier.Verify(vch)
 	assert.Nil(s.T(), err)
 	assert.Nil(s.T(), chFaults)
 	assert.NotNil(s.T(), spockSecret)
 }
 
+// TestUnknownExecutionMetadata tests that verifying a chunk whose receipts only report execution
+// metadata unknown to the verifier fails with an error rather than a chunk fault.
+func (s *ChunkVerifierTestSuite) TestUnknownExecutionMetadata() {
+	vch := GetBaselineVerifiableChunk(s.T(), []byte{})
+	vch.ExecutionMetadata = []flow.ExecutionMetadata{{Version: 1, FlagsHash: unittest.IdentifierFixture()}}
+	spockSecret, _, chFaults, err := s.verifier.Verify(vch)
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), chFaults)
+	assert.Nil(s.T(), spockSecret)
+}
+
+// TestRegisteredExecutionMetadata tests that chunks are verified in the context registered for
+// the execution metadata of their receipts, and in the base context for legacy metadata.
+func TestRegisteredExecutionMetadata(t *testing.T) {
+	registry := chunks.NewContextRegistry()
+	metadata, err := registry.RegisterFeatureFlags(1, []string{fvm.FeatureAccountFreeze})
+	require.NoError(t, err)
+
+	vm := &contextRecordingVMMock{}
+	verifier := chunks.NewChunkVerifierWithRegistry(vm, fvm.NewContext(zerolog.Nop()), registry)
+
+	vch := GetBaselineVerifiableChunk(t, []byte{})
+	vch.ExecutionMetadata = []flow.ExecutionMetadata{{Version: 2, FlagsHash: unittest.IdentifierFixture()}, metadata}
+	_, _, chFaults, err := verifier.Verify(vch)
+	require.NoError(t, err)
+	require.Nil(t, chFaults)
+	require.NotEmpty(t, vm.contexts)
+	for _, ctx := range vm.contexts {
+		assert.True(t, ctx.AccountFreezeAvailable)
+	}
+
+	vm.contexts = nil
+	vch = GetBaselineVerifiableChunk(t, []byte{})
+	_, _, chFaults, err = verifier.Verify(vch)
+	require.NoError(t, err)
+	require.Nil(t, chFaults)
+	require.NotEmpty(t, vm.contexts)
+	for _, ctx := range vm.contexts {
+		assert.False(t, ctx.AccountFreezeAvailable)
+	}
+}
+
 // GetBaselineVerifiableChunk returns a verifiable chunk and sets the script
 // of a transaction in the middle of the collection to some value to signal the
 // mocked vm on what to return as tx exec outcome.
@@ -233,7 +366,10 @@ func GetBaselineVerifiableChunk(t *testing.T, script []byte) *verification.Verif
 		ChunkBody: flow.ChunkBody{
 			CollectionIndex: 0,
 			StartState:      flow.StateCommitment(startState),
+			EventCollection: flow.EventsList{}.Hash(), // the mocked vm emits no events
 			BlockID:         blockID,
+			// the mocked vm uses no computation, unless the transaction fails
+			NumberOfTransactions: uint64(len(coll.Transactions)),
 		},
 		Index: 0,
 	}
@@ -276,10 +412,15 @@ func (vm *vmMock) Run(ctx fvm.Context, proc fvm.Procedure, led state.View, progr
 		// add updates to the ledger
 		_ = led.Set("00", "", "", []byte{'F'})
 		tx.Logs = []string{"log1", "log2"}
+	case "eventsMismatch":
+		_ = led.Set("05", "", "", []byte{'B'})
+		tx.Events = []flow.Event{unittest.EventFixture(flow.EventAccountCreated, tx.TxIndex, 0, tx.ID)}
 	case "failedTx":
 		// add updates to the ledger
 		_ = led.Set("05", "", "", []byte{'B'})
-		tx.Err = &fvmErrors.CadenceRuntimeError{} // inside the runtime (e.g. div by zero, access account)
+		// the error is reported by the verifier, so it needs to be printable
+		tx.Err = fvmErrors.NewStorageCapacityExceededError(flow.EmptyAddress, 200, 100)
+		tx.GasUsed = 7
 	default:
 		_, _ = led.Get("00", "", "")
 		_, _ = led.Get("05", "", "")
@@ -289,3 +430,14 @@ func (vm *vmMock) Run(ctx fvm.Context, proc fvm.Procedure, led state.View, progr
 
 	return nil
 }
+
+// contextRecordingVMMock behaves like vmMock, and records the contexts it runs procedures in.
+type contextRecordingVMMock struct {
+	vmMock
+	contexts []fvm.Context
+}
+
+func (vm *contextRecordingVMMock) Run(ctx fvm.Context, proc fvm.Procedure, led state.View, programs *programs.Programs) error {
+	vm.contexts = append(vm.contexts, ctx)
+	return vm.vmMock.Run(ctx, proc, led, programs)
+}
@@ -2,6 +2,7 @@ package chunks
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/onflow/flow-go/crypto/hash"
 	"github.com/onflow/flow-go/crypto/random"
@@ -9,10 +10,10 @@ import (
 	"github.com/onflow/flow-go/model/encoding"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
-	"github.com/onflow/flow-go/model/indices"
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/seed"
 )
 
 // DefaultChunkAssignmentAlpha is the default number of verifiers that should be
@@ -71,11 +72,9 @@ func (p *ChunkAssigner) Assign(result *flow.ExecutionResult, blockID flow.Identi
 	}
 
 	// Get a list of verifiers at block that is being sealed
-	verifiers, err := p.protocolState.AtBlockID(result.BlockID).Identities(filter.And(filter.HasRole(flow.RoleVerification),
-		filter.HasStake(true),
-		filter.Not(filter.Ejected)))
+	verifiers, err := p.verifiers(result)
 	if err != nil {
-		return nil, fmt.Errorf("could not get verifiers: %w", err)
+		return nil, err
 	}
 
 	// create RNG for assignment
@@ -96,15 +95,67 @@ func (p *ChunkAssigner) Assign(result *flow.ExecutionResult, blockID flow.Identi
 	return a, nil
 }
 
+// Explain returns the assignment of the chunks of the result, along with the seed and the verifiers
+// the assignment is computed from.
+// error returns are the same as for Assign.
+func (p *ChunkAssigner) Explain(result *flow.ExecutionResult, blockID flow.Identifier) (*chunkmodels.AssignmentExplanation, error) {
+	verifiers, err := p.verifiers(result)
+	if err != nil {
+		return nil, err
+	}
+
+	assignmentSeed, err := seed.ChunkAssignment(p.protocolState.AtBlockID(blockID)) // potentially returns NoValidChildBlockError
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := p.Assign(result, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]chunkmodels.ChunkVerifiers, 0, len(result.Chunks))
+	for _, chunk := range result.Chunks {
+		assigned := assignment.Verifiers(chunk)
+		sort.Sort(assigned)
+		chunks = append(chunks, chunkmodels.ChunkVerifiers{
+			ChunkIndex: chunk.Index,
+			ChunkID:    chunk.ID(),
+			Verifiers:  assigned,
+		})
+	}
+
+	return &chunkmodels.AssignmentExplanation{
+		ResultID:  result.ID(),
+		BlockID:   blockID,
+		Seed:      assignmentSeed,
+		Alpha:     uint(p.alpha),
+		Verifiers: verifiers.NodeIDs(),
+		Chunks:    chunks,
+	}, nil
+}
+
+// verifiers returns the staked verification nodes at the block of the result, which its chunks
+// are assigned among.
+func (p *ChunkAssigner) verifiers(result *flow.ExecutionResult) (flow.IdentityList, error) {
+	verifiers, err := p.protocolState.AtBlockID(result.BlockID).Identities(filter.And(filter.HasRole(flow.RoleVerification),
+		filter.HasStake(true),
+		filter.Not(filter.Ejected)))
+	if err != nil {
+		return nil, fmt.Errorf("could not get verifiers: %w", err)
+	}
+	return verifiers, nil
+}
+
 func (p *ChunkAssigner) rngByBlockID(stateSnapshot protocol.Snapshot) (random.Rand, error) {
 	// TODO: rng could be cached to optimize performance
 
-	seed, err := stateSnapshot.Seed(indices.ProtocolVerificationChunkAssignment...) // potentially returns NoValidChildBlockError
+	assignmentSeed, err := seed.ChunkAssignment(stateSnapshot) // potentially returns NoValidChildBlockError
 	if err != nil {
 		return nil, err
 	}
 
-	rng, err := random.NewRand(seed)
+	rng, err := random.NewRand(assignmentSeed)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate random generator: %w", err)
 	}
@@ -324,6 +324,42 @@ func (a *PublicAssignmentTestSuite) TestCacheAssignment() {
 	require.Equal(a.T(), assigner.Size(), uint(2))
 }
 
+// TestExplain evaluates that the explanation of an assignment lists the assigned verifiers of each chunk,
+// along with the seed and the verifiers the assignment is computed from.
+func (a *PublicAssignmentTestSuite) TestExplain() {
+	head, snapshot, state := a.SetupTest(5)
+
+	result := a.CreateResult(head, 10, a.T())
+	seed := a.HashResult(result, a.T())
+	snapshot.On("Seed", mock.Anything, mock.Anything, mock.Anything).Return(seed, nil)
+
+	nodes := unittest.IdentityListFixture(5)
+	snapshot.On("Identities", mock.Anything).Return(nodes, nil)
+
+	assigner, err := NewChunkAssigner(2, state)
+	require.NoError(a.T(), err)
+	assignment, err := assigner.Assign(result, head.ID())
+	require.NoError(a.T(), err)
+
+	explanation, err := assigner.Explain(result, head.ID())
+	require.NoError(a.T(), err)
+	require.Equal(a.T(), result.ID(), explanation.ResultID)
+	require.Equal(a.T(), head.ID(), explanation.BlockID)
+	require.Equal(a.T(), seed, explanation.Seed)
+	require.Equal(a.T(), uint(2), explanation.Alpha)
+	require.Equal(a.T(), nodes.NodeIDs(), explanation.Verifiers)
+
+	require.Len(a.T(), explanation.Chunks, len(result.Chunks))
+	for i, chunk := range result.Chunks {
+		require.Equal(a.T(), chunk.Index, explanation.Chunks[i].ChunkIndex)
+		require.Equal(a.T(), chunk.ID(), explanation.Chunks[i].ChunkID)
+		require.ElementsMatch(a.T(), assignment.Verifiers(chunk), explanation.Chunks[i].Verifiers)
+	}
+	for _, node := range nodes {
+		require.ElementsMatch(a.T(), assignment.ByNodeID(node.NodeID), explanation.ChunksOf(node.NodeID))
+	}
+}
+
 // CreateChunk creates and returns num chunks. It only fills the Index part of
 // chunks to make them distinct from each other.
 func (a *PublicAssignmentTestSuite) CreateChunks(num int, t *testing.T) flow.ChunkList {
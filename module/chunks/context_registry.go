@@ -0,0 +1,87 @@
+package chunks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ContextRegistry maps the execution metadata reported in execution receipts to the options
+// of the virtual machine context the blocks were executed with. It allows verification nodes
+// to keep verifying chunks across coordinated changes of the execution behaviour within a
+// spork, as long as the configurations of all versions in use are registered.
+//
+// Legacy metadata is always compatible with the base context of the chunk verifier, unless
+// options are registered for it explicitly.
+type ContextRegistry struct {
+	sync.RWMutex
+	options map[flow.ExecutionMetadata][]fvm.Option
+}
+
+// NewContextRegistry creates an empty registry.
+func NewContextRegistry() *ContextRegistry {
+	return &ContextRegistry{
+		options: make(map[flow.ExecutionMetadata][]fvm.Option),
+	}
+}
+
+// Register registers the context options for the given execution metadata, replacing any
+// options registered for it before.
+func (r *ContextRegistry) Register(metadata flow.ExecutionMetadata, options ...fvm.Option) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.options[metadata] = options
+}
+
+// RegisterFeatureFlags registers the options enabling the given feature flags for the
+// metadata of the given version and flags, which it returns. It returns an error if any
+// of the flags is unknown.
+func (r *ContextRegistry) RegisterFeatureFlags(version uint32, flags []string) (flow.ExecutionMetadata, error) {
+	options, err := fvm.FeatureFlagOptions(flags)
+	if err != nil {
+		return flow.ExecutionMetadata{}, fmt.Errorf("could not register feature flags of version %d: %w", version, err)
+	}
+
+	metadata := flow.ExecutionMetadata{
+		Version:   version,
+		FlagsHash: flow.FeatureFlagsHash(flags),
+	}
+	r.Register(metadata, options...)
+
+	return metadata, nil
+}
+
+// Options returns the context options registered for the given execution metadata, and
+// whether the metadata is compatible with the registry.
+func (r *ContextRegistry) Options(metadata flow.ExecutionMetadata) ([]fvm.Option, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	options, ok := r.options[metadata]
+	if !ok && metadata.IsLegacy() {
+		return nil, true
+	}
+	return options, ok
+}
+
+// Select returns the context options for the first of the given execution metadata which is
+// compatible with the registry. Verifying a chunk with any compatible metadata is fine, as
+// all receipts commit to the same result. No metadata at all is treated as legacy metadata.
+func (r *ContextRegistry) Select(metadata []flow.ExecutionMetadata) ([]fvm.Option, error) {
+	if len(metadata) == 0 {
+		options, _ := r.Options(flow.ExecutionMetadata{})
+		return options, nil
+	}
+
+	for _, m := range metadata {
+		options, ok := r.Options(m)
+		if ok {
+			return options, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no verification context registered for execution metadata %v", metadata)
+}
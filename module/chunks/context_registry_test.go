@@ -0,0 +1,58 @@
+package chunks_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/chunks"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestContextRegistry_Options verifies that the registry returns the options registered for the
+// metadata of a version and feature flags, and accepts legacy metadata without any options.
+func TestContextRegistry_Options(t *testing.T) {
+	registry := chunks.NewContextRegistry()
+
+	metadata, err := registry.RegisterFeatureFlags(1, []string{fvm.FeatureTransactionFees, fvm.FeatureAccountFreeze})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), metadata.Version)
+	assert.Equal(t, flow.FeatureFlagsHash([]string{fvm.FeatureAccountFreeze, fvm.FeatureTransactionFees}), metadata.FlagsHash)
+
+	options, ok := registry.Options(metadata)
+	require.True(t, ok)
+	assert.Len(t, options, 2)
+
+	options, ok = registry.Options(flow.ExecutionMetadata{})
+	require.True(t, ok)
+	assert.Empty(t, options)
+
+	_, ok = registry.Options(flow.ExecutionMetadata{Version: 2, FlagsHash: metadata.FlagsHash})
+	assert.False(t, ok)
+
+	_, err = registry.RegisterFeatureFlags(1, []string{"unknown"})
+	assert.Error(t, err)
+}
+
+// TestContextRegistry_Select verifies that the registry selects the options of the first
+// compatible execution metadata, and fails if none of them is compatible.
+func TestContextRegistry_Select(t *testing.T) {
+	registry := chunks.NewContextRegistry()
+	metadata, err := registry.RegisterFeatureFlags(1, []string{fvm.FeatureAccountFreeze})
+	require.NoError(t, err)
+	unknown := flow.ExecutionMetadata{Version: 2, FlagsHash: unittest.IdentifierFixture()}
+
+	options, err := registry.Select(nil)
+	require.NoError(t, err)
+	assert.Empty(t, options)
+
+	options, err = registry.Select([]flow.ExecutionMetadata{unknown, metadata})
+	require.NoError(t, err)
+	assert.Len(t, options, 1)
+
+	_, err = registry.Select([]flow.ExecutionMetadata{unknown})
+	assert.Error(t, err)
+}
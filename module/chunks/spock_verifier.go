@@ -0,0 +1,279 @@
+package chunks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// DefaultSpockVerificationCacheSize is the default number of pairwise SPoCK verifications cached.
+const DefaultSpockVerificationCacheSize = 10000
+
+// SpockMismatchError indicates that the SPoCK of a node for a chunk doesn't match the SPoCKs of
+// the execution nodes which committed to the same result. As the SPoCKs of the same chunk only
+// match if they were generated from the same execution trace, at least one of the nodes did not
+// execute the chunk to the result it committed to.
+type SpockMismatchError struct {
+	ResultID   flow.Identifier
+	ChunkIndex uint64
+	NodeID     flow.Identifier     // node whose SPoCK was verified
+	Mismatched flow.IdentifierList // execution nodes whose SPoCKs don't match the SPoCK of the node
+}
+
+func (e SpockMismatchError) Error() string {
+	return fmt.Sprintf("SPoCK of node %x for chunk %d of result %x does not match SPoCKs of execution nodes %v",
+		e.NodeID, e.ChunkIndex, e.ResultID, e.Mismatched)
+}
+
+// IsSpockMismatchError returns whether the error is a SpockMismatchError.
+func IsSpockMismatchError(err error) bool {
+	var mismatchErr SpockMismatchError
+	return errors.As(err, &mismatchErr)
+}
+
+// executorSpocks are the SPoCKs of the chunks of a result, as committed to by an execution node.
+type executorSpocks struct {
+	executorID flow.Identifier
+	spocks     []crypto.Signature
+}
+
+// resultSpocks are the SPoCKs committed to by the execution nodes for a result.
+type resultSpocks struct {
+	blockID   flow.Identifier
+	height    uint64
+	executors []executorSpocks
+}
+
+// spockPair is the key of a pairwise SPoCK verification in the cache.
+type spockPair struct {
+	NodeID1 flow.Identifier
+	Spock1  crypto.Signature
+	NodeID2 flow.Identifier
+	Spock2  crypto.Signature
+}
+
+// SpockVerifier collects the SPoCKs of execution results from the execution receipts, and
+// verifies the SPoCKs of receipts and result approvals for consistency with them. As the same
+// SPoCKs are verified against each other for each receipt and approval of a result, the outcomes
+// of the pairwise verifications are cached.
+// It is concurrency safe.
+type SpockVerifier struct {
+	sync.Mutex
+	state   protocol.State
+	metrics module.CacheMetrics
+	results map[flow.Identifier]*resultSpocks // SPoCKs of the receipts, by result ID
+	cache   *lru.Cache                        // outcomes of pairwise verifications, by ID of the spockPair
+}
+
+// NewSpockVerifier creates a new SPoCK verifier, which caches the outcomes of up to the given
+// number of pairwise verifications.
+func NewSpockVerifier(state protocol.State, metrics module.CacheMetrics, cacheSize uint) (*SpockVerifier, error) {
+	cache, err := lru.New(int(cacheSize))
+	if err != nil {
+		return nil, fmt.Errorf("could not create cache of SPoCK verifications: %w", err)
+	}
+	return &SpockVerifier{
+		state:   state,
+		metrics: metrics,
+		results: make(map[flow.Identifier]*resultSpocks),
+		cache:   cache,
+	}, nil
+}
+
+// AddReceipt records the SPoCKs of the receipt for the block at the given height, and verifies
+// them against the SPoCKs of the receipts committing to the same result added before. The SPoCKs
+// are recorded regardless of the outcome of the verification.
+// Returns:
+// - SpockMismatchError if SPoCKs of the receipt don't match the SPoCKs of earlier receipts
+// - exception in case of any other error, usually this is not expected
+// - nil on success
+func (v *SpockVerifier) AddReceipt(receipt *flow.ExecutionReceipt, height uint64) error {
+	result := &receipt.ExecutionResult
+	resultID := result.ID()
+
+	v.Lock()
+	spocks, ok := v.results[resultID]
+	if !ok {
+		spocks = &resultSpocks{
+			blockID: result.BlockID,
+			height:  height,
+		}
+		v.results[resultID] = spocks
+	}
+	for _, executor := range spocks.executors {
+		if executor.executorID == receipt.ExecutorID {
+			v.Unlock()
+			return nil
+		}
+	}
+	others := spocks.executors
+	spocks.executors = append(spocks.executors, executorSpocks{
+		executorID: receipt.ExecutorID,
+		spocks:     receipt.Spocks,
+	})
+	v.Unlock()
+
+	for index := range result.Chunks {
+		mismatched, err := v.mismatching(result.BlockID, receipt.ExecutorID, spockOf(receipt.Spocks, uint64(index)), others, uint64(index))
+		if err != nil {
+			return fmt.Errorf("could not verify SPoCK of chunk %d: %w", index, err)
+		}
+		if len(mismatched) > 0 {
+			return SpockMismatchError{
+				ResultID:   resultID,
+				ChunkIndex: uint64(index),
+				NodeID:     receipt.ExecutorID,
+				Mismatched: mismatched,
+			}
+		}
+	}
+
+	return nil
+}
+
+// VerifyApproval verifies the SPoCK of the approval against the SPoCKs of the receipts committing
+// to the approved result. The approval is consistent if its SPoCK matches the SPoCK of at least one
+// of the receipts, as receipts with mismatching SPoCKs might have been added for the result. It is
+// consistent as well if no receipts were added for the result, as there is nothing to verify
+// against.
+// Returns:
+// - SpockMismatchError if the SPoCK of the approval matches none of the receipts
+// - exception in case of any other error, usually this is not expected
+// - nil on success
+func (v *SpockVerifier) VerifyApproval(approval *flow.ResultApproval) error {
+	body := approval.Body
+
+	v.Lock()
+	spocks, ok := v.results[body.ExecutionResultID]
+	var executors []executorSpocks
+	if ok {
+		executors = spocks.executors
+	}
+	v.Unlock()
+
+	if len(executors) == 0 {
+		return nil
+	}
+
+	mismatched, err := v.mismatching(body.BlockID, body.ApproverID, body.Spock, executors, body.ChunkIndex)
+	if err != nil {
+		return fmt.Errorf("could not verify SPoCK of approval: %w", err)
+	}
+	if len(mismatched) < len(executors) {
+		return nil
+	}
+
+	return SpockMismatchError{
+		ResultID:   body.ExecutionResultID,
+		ChunkIndex: body.ChunkIndex,
+		NodeID:     body.ApproverID,
+		Mismatched: mismatched,
+	}
+}
+
+// Executors returns the execution nodes whose SPoCKs were recorded for the result.
+func (v *SpockVerifier) Executors(resultID flow.Identifier) flow.IdentifierList {
+	v.Lock()
+	defer v.Unlock()
+
+	spocks, ok := v.results[resultID]
+	if !ok {
+		return nil
+	}
+	executorIDs := make(flow.IdentifierList, 0, len(spocks.executors))
+	for _, executor := range spocks.executors {
+		executorIDs = append(executorIDs, executor.executorID)
+	}
+	return executorIDs
+}
+
+// PruneUpToHeight drops the SPoCKs of the results for blocks up to the given height.
+func (v *SpockVerifier) PruneUpToHeight(height uint64) {
+	v.Lock()
+	defer v.Unlock()
+
+	for resultID, spocks := range v.results {
+		if spocks.height <= height {
+			delete(v.results, resultID)
+		}
+	}
+}
+
+// mismatching returns the execution nodes whose SPoCKs for the chunk with the given index don't
+// match the given SPoCK of the node.
+func (v *SpockVerifier) mismatching(blockID flow.Identifier, nodeID flow.Identifier, spock crypto.Signature, executors []executorSpocks, index uint64) (flow.IdentifierList, error) {
+	var mismatched flow.IdentifierList
+	for _, executor := range executors {
+		match, err := v.verifyPair(blockID, nodeID, spock, executor.executorID, spockOf(executor.spocks, index))
+		if err != nil {
+			return nil, fmt.Errorf("could not verify SPoCK against execution node %x: %w", executor.executorID, err)
+		}
+		if !match {
+			mismatched = append(mismatched, executor.executorID)
+		}
+	}
+	return mismatched, nil
+}
+
+// verifyPair verifies whether the SPoCKs of the two nodes were generated from the same secret,
+// serving the outcome from the cache if the pair was verified before. Missing SPoCKs don't match.
+func (v *SpockVerifier) verifyPair(blockID flow.Identifier, nodeID1 flow.Identifier, spock1 crypto.Signature, nodeID2 flow.Identifier, spock2 crypto.Signature) (bool, error) {
+	if len(spock1) == 0 || len(spock2) == 0 {
+		return false, nil
+	}
+
+	// the verification is symmetric, so both orders of the pair share the cache entry
+	pair := spockPair{NodeID1: nodeID1, Spock1: spock1, NodeID2: nodeID2, Spock2: spock2}
+	if bytes.Compare(nodeID1[:], nodeID2[:]) > 0 {
+		pair = spockPair{NodeID1: nodeID2, Spock1: spock2, NodeID2: nodeID1, Spock2: spock1}
+	}
+	key := flow.MakeID(pair)
+	if match, ok := v.cache.Get(key); ok {
+		v.metrics.CacheHit(metrics.ResourceSpockVerification)
+		return match.(bool), nil
+	}
+	v.metrics.CacheMiss(metrics.ResourceSpockVerification)
+
+	pk1, err := v.stakingKey(blockID, pair.NodeID1)
+	if err != nil {
+		return false, err
+	}
+	pk2, err := v.stakingKey(blockID, pair.NodeID2)
+	if err != nil {
+		return false, err
+	}
+	match, err := crypto.SPOCKVerify(pk1, pair.Spock1, pk2, pair.Spock2)
+	if err != nil {
+		return false, fmt.Errorf("could not verify SPoCKs: %w", err)
+	}
+
+	v.cache.Add(key, match)
+	v.metrics.CacheEntries(metrics.ResourceSpockVerification, uint(v.cache.Len()))
+	return match, nil
+}
+
+// stakingKey returns the staking key of the node at the given block.
+func (v *SpockVerifier) stakingKey(blockID flow.Identifier, nodeID flow.Identifier) (crypto.PublicKey, error) {
+	identity, err := v.state.AtBlockID(blockID).Identity(nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get identity of node %x at block %x: %w", nodeID, blockID, err)
+	}
+	return identity.StakingPubKey, nil
+}
+
+// spockOf returns the SPoCK of the chunk with the given index, or nil if it is missing.
+func spockOf(spocks []crypto.Signature, index uint64) crypto.Signature {
+	if index >= uint64(len(spocks)) {
+		return nil
+	}
+	return spocks[index]
+}
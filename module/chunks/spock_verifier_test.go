@@ -0,0 +1,112 @@
+package chunks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/encoding"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	protocolMock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSpockVerifier verifies that the SPoCKs of receipts and approvals are verified against the
+// SPoCKs of the receipts for the same result, and that the pairwise verifications are cached.
+func TestSpockVerifier(t *testing.T) {
+	keys, err := unittest.StakingKeys(3)
+	require.NoError(t, err)
+	identities := unittest.IdentityListFixture(3)
+	executor1, executor2, verifier := identities[0], identities[1], identities[2]
+	privateKeys := make(map[flow.Identifier]crypto.PrivateKey)
+	for i, identity := range identities {
+		identity.StakingPubKey = keys[i].PublicKey()
+		privateKeys[identity.NodeID] = keys[i]
+	}
+
+	snapshot := &protocolMock.Snapshot{}
+	for _, identity := range identities {
+		snapshot.On("Identity", identity.NodeID).Return(identity, nil)
+	}
+	state := &protocolMock.State{}
+	state.On("AtBlockID", mock.Anything).Return(snapshot)
+
+	result := unittest.ExecutionResultFixture(unittest.WithChunks(2))
+	resultID := result.ID()
+	secrets := [][]byte{unittest.SeedFixture(32), unittest.SeedFixture(32)}
+	wrongSecret := unittest.SeedFixture(32)
+
+	spock := func(nodeID flow.Identifier, secret []byte) crypto.Signature {
+		sig, err := crypto.SPOCKProve(privateKeys[nodeID], secret, crypto.NewBLSKMAC(encoding.SPOCKTag))
+		require.NoError(t, err)
+		return sig
+	}
+	receipt := func(executorID flow.Identifier, secrets ...[]byte) *flow.ExecutionReceipt {
+		r := unittest.ExecutionReceiptFixture(unittest.WithResult(result), unittest.WithExecutorID(executorID))
+		for _, secret := range secrets {
+			r.Spocks = append(r.Spocks, spock(executorID, secret))
+		}
+		return r
+	}
+	approval := func(index uint64, secret []byte) *flow.ResultApproval {
+		a := unittest.ResultApprovalFixture(
+			unittest.WithApproverID(verifier.NodeID),
+			unittest.WithExecutionResultID(resultID),
+			unittest.WithBlockID(result.BlockID),
+			unittest.WithChunk(index),
+		)
+		a.Body.Spock = spock(verifier.NodeID, secret)
+		return a
+	}
+
+	v, err := NewSpockVerifier(state, metrics.NewNoopCollector(), DefaultSpockVerificationCacheSize)
+	require.NoError(t, err)
+
+	// approvals for results without receipts can't be verified
+	require.NoError(t, v.VerifyApproval(approval(0, wrongSecret)))
+
+	require.NoError(t, v.AddReceipt(receipt(executor1.NodeID, secrets...), 10))
+
+	// the second execution node executed the second chunk differently
+	err = v.AddReceipt(receipt(executor2.NodeID, secrets[0], wrongSecret), 10)
+	require.True(t, IsSpockMismatchError(err))
+	var mismatchErr SpockMismatchError
+	require.ErrorAs(t, err, &mismatchErr)
+	assert.Equal(t, uint64(1), mismatchErr.ChunkIndex)
+	assert.Equal(t, flow.IdentifierList{executor1.NodeID}, mismatchErr.Mismatched)
+	assert.ElementsMatch(t, flow.IdentifierList{executor1.NodeID, executor2.NodeID}, v.Executors(resultID))
+
+	t.Run("consistent approvals", func(t *testing.T) {
+		require.NoError(t, v.VerifyApproval(approval(0, secrets[0])))
+		// matches the first execution node only
+		require.NoError(t, v.VerifyApproval(approval(1, secrets[1])))
+	})
+
+	t.Run("mismatching approval", func(t *testing.T) {
+		err := v.VerifyApproval(approval(0, wrongSecret))
+		require.True(t, IsSpockMismatchError(err))
+		require.ErrorAs(t, err, &mismatchErr)
+		assert.Equal(t, verifier.NodeID, mismatchErr.NodeID)
+		assert.ElementsMatch(t, flow.IdentifierList{executor1.NodeID, executor2.NodeID}, mismatchErr.Mismatched)
+	})
+
+	t.Run("cached verifications", func(t *testing.T) {
+		a := approval(0, secrets[0])
+		require.NoError(t, v.VerifyApproval(a))
+		calls := len(state.Calls)
+		require.NoError(t, v.VerifyApproval(a))
+		assert.Len(t, state.Calls, calls)
+	})
+
+	t.Run("pruning", func(t *testing.T) {
+		v.PruneUpToHeight(9)
+		assert.Len(t, v.Executors(resultID), 2)
+		v.PruneUpToHeight(10)
+		assert.Empty(t, v.Executors(resultID))
+		require.NoError(t, v.VerifyApproval(approval(0, wrongSecret)))
+	})
+}
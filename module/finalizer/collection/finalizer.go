@@ -150,6 +150,8 @@ func (f *Finalizer) MakeFinal(blockID flow.Identifier) error {
 				Guarantee: flow.CollectionGuarantee{
 					CollectionID:     payload.Collection.ID(),
 					ReferenceBlockID: payload.ReferenceBlockID,
+					TransactionCount: uint(payload.Collection.Len()),
+					ByteSize:         payload.Collection.ByteSize(),
 					SignerIDs:        step.ParentVoterIDs,
 					Signature:        step.ParentVoterSig,
 				},
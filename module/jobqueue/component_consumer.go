@@ -0,0 +1,89 @@
+package jobqueue
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ComponentConsumer wraps a consumer to be started and stopped as a component of a node.
+// The consumer resumes from the persisted processed index of the given progress on startup,
+// and starts from the default index if there is none yet. Several consumers can consume the
+// same jobs, as long as each of them persists its progress under a distinct name.
+type ComponentConsumer struct {
+	consumer     *Consumer
+	defaultIndex uint64
+}
+
+// NewComponentConsumer creates a consumer of the jobs, which runs them with the worker.
+func NewComponentConsumer(
+	log zerolog.Logger,
+	jobs module.Jobs,
+	progress storage.ConsumerProgress,
+	worker Worker,
+	maxProcessing int64,
+	defaultIndex uint64,
+	opts ...ConsumerOption,
+) *ComponentConsumer {
+	return &ComponentConsumer{
+		consumer:     NewConsumer(log, jobs, progress, worker, maxProcessing, opts...),
+		defaultIndex: defaultIndex,
+	}
+}
+
+// NotifyJobIsDone is invoked by the worker to let the consumer know that it is done
+// processing a job.
+func (c *ComponentConsumer) NotifyJobIsDone(jobID module.JobID) {
+	c.consumer.NotifyJobIsDone(jobID)
+}
+
+// Check notifies the consumer that new jobs might be available.
+func (c *ComponentConsumer) Check() {
+	c.consumer.Check()
+}
+
+// Ready starts the consumer.
+func (c *ComponentConsumer) Ready() <-chan struct{} {
+	err := c.consumer.Start(c.defaultIndex)
+	if err != nil {
+		panic(fmt.Errorf("could not start the consumer: %w", err))
+	}
+
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
+// Done stops the consumer, and waits for the running jobs to finish.
+func (c *ComponentConsumer) Done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		c.consumer.Stop()
+		close(done)
+	}()
+	return done
+}
+
+// LogDeadLetters reports the jobs which failed on all attempts to the log.
+type LogDeadLetters struct {
+	log zerolog.Logger
+}
+
+// NewLogDeadLetters creates dead letters which are reported to the given log.
+func NewLogDeadLetters(log zerolog.Logger) *LogDeadLetters {
+	return &LogDeadLetters{
+		log: log.With().Str("sub_module", "dead_letters").Logger(),
+	}
+}
+
+// Report logs the failed job.
+func (d *LogDeadLetters) Report(job module.Job, attempts uint, err error) {
+	d.log.Error().
+		Err(err).
+		Str("job_id", string(job.ID())).
+		Uint("attempts", attempts).
+		Msg("job failed on all attempts")
+}
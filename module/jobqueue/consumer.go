@@ -13,10 +13,28 @@ import (
 )
 
 type Worker interface {
-	// returned error must be unexpected fatal error
+	// returned error must be unexpected fatal error, unless the consumer retries failed jobs
 	Run(job module.Job) error
 }
 
+// DeadLetters receives the jobs which failed on all attempts of the consumer to run them.
+type DeadLetters interface {
+	Report(job module.Job, attempts uint, err error)
+}
+
+// ConsumerOption configures the optional behavior of a consumer.
+type ConsumerOption func(*Consumer)
+
+// WithRetries makes the consumer run a job whose worker returns an error again, up to the given number
+// of attempts in total. Jobs which fail on all attempts are reported to the dead letters, and considered
+// done, so that the consumer can move on to the next jobs.
+func WithRetries(maxAttempts uint, deadLetters DeadLetters) ConsumerOption {
+	return func(c *Consumer) {
+		c.maxAttempts = maxAttempts
+		c.deadLetters = deadLetters
+	}
+}
+
 type Consumer struct {
 	mu  sync.Mutex
 	log zerolog.Logger
@@ -29,7 +47,9 @@ type Consumer struct {
 	worker Worker // to process job and notify consumer when finish processing a job
 
 	// Config
-	maxProcessing int64 // max number of jobs to be processed concurrently
+	maxProcessing int64       // max number of jobs to be processed concurrently
+	maxAttempts   uint        // max number of attempts to run a job, 0 if failed jobs are fatal
+	deadLetters   DeadLetters // to report jobs which failed on all attempts
 
 	// State Variables
 	running bool // a signal to control whether to start processing more jobs. Useful for waiting
@@ -50,8 +70,9 @@ func NewConsumer(
 	progress storage.ConsumerProgress,
 	worker Worker,
 	maxProcessing int64,
+	opts ...ConsumerOption,
 ) *Consumer {
-	return &Consumer{
+	c := &Consumer{
 		log: log.With().Str("sub_module", "job_queue").Logger(),
 
 		// store dependency
@@ -69,6 +90,12 @@ func NewConsumer(
 		processings:      make(map[uint64]*jobStatus),
 		processingsIndex: make(map[module.JobID]uint64),
 	}
+
+	for _, apply := range opts {
+		apply(c)
+	}
+
+	return c
 }
 
 // Start starts consuming the jobs from the job queue.
@@ -196,6 +223,15 @@ func (c *Consumer) run() (int64, error) {
 		Bool("running", c.running).
 		Msg("running")
 
+	// the processed index is persisted before any state is updated or new job is dispatched,
+	// so that the progress in storage and in memory never diverge
+	if processedTo != c.processedIndex {
+		err = c.progress.SetProcessedIndex(processedTo)
+		if err != nil {
+			return 0, fmt.Errorf("could not set processed index %v, %w", processedTo, err)
+		}
+	}
+
 	for _, indexedJob := range processables {
 		jobID := indexedJob.job.ID()
 
@@ -207,19 +243,11 @@ func (c *Consumer) run() (int64, error) {
 
 		c.runningJobs.Add(1)
 		go func(j *jobAtIndex) {
-			err := c.worker.Run(j.job)
-			if err != nil {
-				c.log.Fatal().Err(err).Msg("could not run the job")
-			}
+			c.runJob(j.job)
 			c.runningJobs.Done()
 		}(indexedJob)
 	}
 
-	err = c.progress.SetProcessedIndex(processedTo)
-	if err != nil {
-		return 0, fmt.Errorf("could not set processed index %v, %w", processedTo, err)
-	}
-
 	for index := c.processedIndex + 1; index <= processedTo; index++ {
 		jobStatus, ok := c.processings[index]
 		if !ok {
@@ -235,6 +263,45 @@ func (c *Consumer) run() (int64, error) {
 	return int64(len(processables)), nil
 }
 
+// runJob runs the job with the worker. Failed jobs are retried up to the max number of attempts,
+// and reported to the dead letters if they fail on all attempts.
+func (c *Consumer) runJob(job module.Job) {
+	for attempt := uint(1); ; attempt++ {
+		err := c.worker.Run(job)
+		if err == nil {
+			return
+		}
+
+		log := c.log.With().
+			Err(err).
+			Str("job_id", string(job.ID())).
+			Uint("attempt", attempt).
+			Logger()
+
+		if c.maxAttempts == 0 {
+			log.Fatal().Msg("could not run the job")
+			return
+		}
+
+		if attempt >= c.maxAttempts {
+			log.Error().Msg("could not run the job on any attempt, reporting it as dead letter")
+			c.deadLetters.Report(job, attempt, err)
+			c.NotifyJobIsDone(job.ID())
+			return
+		}
+
+		// a stopped consumer doesn't retry, the job is run again after restarting
+		c.mu.Lock()
+		running := c.running
+		c.mu.Unlock()
+		if !running {
+			return
+		}
+
+		log.Warn().Msg("could not run the job, retrying")
+	}
+}
+
 func (c *Consumer) processableJobs() ([]*jobAtIndex, uint64, error) {
 	processables, processedTo, err := processableJobs(
 		c.jobs,
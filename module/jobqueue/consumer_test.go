@@ -158,6 +158,71 @@ func TestProcessedIndexDeletion(t *testing.T) {
 	})
 }
 
+// Test failed jobs are retried up to the max number of attempts, and reported as dead letters
+// if they fail on all attempts, without blocking the consumer.
+func TestRetries(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badgerdb.DB) {
+		log := unittest.Logger().With().Str("module", "consumer").Logger()
+		jobs := NewMockJobs()
+		progress := badger.NewConsumerProgress(db, "consumer")
+		deadLetters := &mockDeadLetters{}
+
+		// job 1 fails on the first two attempts, job 2 fails on all attempts
+		worker := &failingWorker{failures: map[module.JobID]int{
+			JobIDAtIndex(1): 2,
+			JobIDAtIndex(2): 10,
+		}, attempts: make(map[module.JobID]int)}
+		c := NewConsumer(log, jobs, progress, worker, 3, WithRetries(3, deadLetters))
+		worker.consumer = c
+
+		require.NoError(t, jobs.PushN(3))
+		require.NoError(t, c.Start(0))
+
+		require.Eventually(t, func() bool {
+			processed, err := progress.ProcessedIndex()
+			return err == nil && processed == uint64(3)
+		}, 2*time.Second, 10*time.Millisecond)
+
+		worker.Lock()
+		require.Equal(t, 3, worker.attempts[JobIDAtIndex(1)])
+		require.Equal(t, 3, worker.attempts[JobIDAtIndex(2)])
+		require.Equal(t, 1, worker.attempts[JobIDAtIndex(3)])
+		worker.Unlock()
+
+		deadLetters.Lock()
+		require.Equal(t, []module.JobID{JobIDAtIndex(2)}, deadLetters.jobs)
+		deadLetters.Unlock()
+	})
+}
+
+// Test several consumers process the same jobs independently, each with its own progress.
+func TestMultipleConsumers(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badgerdb.DB) {
+		log := unittest.Logger().With().Str("module", "consumer").Logger()
+		jobs := NewMockJobs()
+		require.NoError(t, jobs.PushN(10))
+
+		progresses := []storage.ConsumerProgress{
+			badger.NewConsumerProgress(db, "first"),
+			badger.NewConsumerProgress(db, "second"),
+		}
+		for _, progress := range progresses {
+			worker := newMockWorker()
+			c := NewConsumer(log, jobs, progress, worker, 3)
+			worker.WithConsumer(c)
+			require.NoError(t, c.Start(0))
+		}
+
+		for _, progress := range progresses {
+			progress := progress
+			require.Eventually(t, func() bool {
+				processed, err := progress.ProcessedIndex()
+				return err == nil && processed == uint64(10)
+			}, 2*time.Second, 10*time.Millisecond)
+		}
+	})
+}
+
 func assertJobs(t *testing.T, expectedIndex []uint64, jobsToRun []*jobAtIndex) {
 	actualIndex := make([]uint64, 0, len(jobsToRun))
 	for _, jobAtIndex := range jobsToRun {
@@ -301,3 +366,35 @@ func (w *mockWorker) Run(job module.Job) error {
 	w.consumer.NotifyJobIsDone(job.ID())
 	return nil
 }
+
+// failingWorker fails to run jobs the given number of times before succeeding
+type failingWorker struct {
+	sync.Mutex
+	consumer *Consumer
+	failures map[module.JobID]int
+	attempts map[module.JobID]int
+}
+
+func (w *failingWorker) Run(job module.Job) error {
+	w.Lock()
+	w.attempts[job.ID()]++
+	failed := w.attempts[job.ID()] <= w.failures[job.ID()]
+	w.Unlock()
+
+	if failed {
+		return fmt.Errorf("job %v failed", job.ID())
+	}
+	w.consumer.NotifyJobIsDone(job.ID())
+	return nil
+}
+
+type mockDeadLetters struct {
+	sync.Mutex
+	jobs []module.JobID
+}
+
+func (d *mockDeadLetters) Report(job module.Job, _ uint, _ error) {
+	d.Lock()
+	defer d.Unlock()
+	d.jobs = append(d.jobs, job.ID())
+}
@@ -47,6 +47,25 @@ func IncrementalAttemptUpdater() ChunkRequestHistoryUpdaterFunc {
 	}
 }
 
+// ChunkRequestStatus is a snapshot of a chunk request and its request history.
+type ChunkRequestStatus struct {
+	Request     *verification.ChunkDataPackRequest
+	Added       time.Time     // time the chunk request was added to the memory pool.
+	LastAttempt time.Time     // time the chunk was last requested, zero if it was never requested.
+	RetryAfter  time.Duration // interval until the request should be retried.
+	Attempts    uint64        // number of times the chunk has been requested.
+}
+
+// ChunkRequestsSummary aggregates the request histories of all chunk requests in a memory pool,
+// which helps diagnosing chunks whose requests are stuck.
+type ChunkRequestsSummary struct {
+	Pending       uint            // number of chunk requests in the memory pool.
+	MaxAttempts   uint64          // highest number of attempts of any chunk request.
+	OldestChunkID flow.Identifier // chunk ID of the oldest chunk request, zero if there are none.
+	OldestAge     time.Duration   // time since the oldest chunk request was added.
+	Attempts      map[uint64]uint // number of chunk requests by their number of attempts.
+}
+
 // ChunkRequests is an in-memory storage for maintaining chunk data pack requests.
 type ChunkRequests interface {
 	// ByID returns a chunk request by its chunk ID.
@@ -92,6 +111,14 @@ type ChunkRequests interface {
 	// All returns all chunk requests stored in this memory pool.
 	All() []*verification.ChunkDataPackRequest
 
+	// Status returns a snapshot of the chunk request with the specified chunk ID and its
+	// request history, and whether such chunk request exists in the memory pool.
+	Status(chunkID flow.Identifier) (*ChunkRequestStatus, bool)
+
+	// Summary returns aggregate statistics about the request histories of all chunk requests
+	// in the memory pool, taken as a consistent snapshot.
+	Summary() ChunkRequestsSummary
+
 	// Size returns total number of chunk requests in the memory pool.
 	Size() uint
 }
@@ -1,7 +1,9 @@
 package consensus
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/onflow/flow-go/model/flow"
@@ -21,8 +23,17 @@ import (
 // For an in-depth discussion of the core algorithm, see ./Fork-Aware_Mempools.md
 type ExecutionTree struct {
 	sync.RWMutex
-	forest forest.LevelledForest
-	size   uint
+	forest        forest.LevelledForest
+	size          uint
+	highestHeight uint64 // height of the highest block with results in the tree
+
+	// PruneUnsealable only inspects the heights which were not inspected before:
+	// all heights below uncheckedHeight were checked against the finalized blocks,
+	// except for the heights in recheck, where results were added since. The height
+	// of the sealed block is inspected again whenever it differs from checkedSealedHeight.
+	uncheckedHeight     uint64
+	recheck             map[uint64]struct{}
+	checkedSealedHeight uint64
 }
 
 // NewExecutionTree instantiates a ExecutionTree
@@ -31,6 +42,7 @@ func NewExecutionTree() *ExecutionTree {
 		RWMutex: sync.RWMutex{},
 		forest:  *forest.NewLevelledForest(0),
 		size:    0,
+		recheck: make(map[uint64]struct{}),
 	}
 }
 
@@ -77,6 +89,12 @@ func (et *ExecutionTree) getEquivalenceClass(result *flow.ExecutionResult, block
 			return nil, fmt.Errorf("failed to store receipt's equivalence class: %w", err)
 		}
 		et.forest.AddVertex(receiptsForResult)
+		if block.Height > et.highestHeight {
+			et.highestHeight = block.Height
+		}
+		if block.Height < et.uncheckedHeight {
+			et.recheck[block.Height] = struct{}{}
+		}
 		// this Receipt Equivalence class is empty (no receipts); hence we don't need to adjust the mempool size
 		return receiptsForResult, nil
 	}
@@ -183,7 +201,7 @@ func (et *ExecutionTree) reachableReceipts(vertex forest.Vertex, blockFilter mem
 // PruneUpToHeight prunes all results for all blocks with height up to but
 // NOT INCLUDING `newLowestHeight`. Errors if newLowestHeight is lower than
 // the previous value (as we cannot recover previously pruned results).
-func (et *ExecutionTree) PruneUpToHeight(limit uint64) error {
+func (et *ExecutionTree) PruneUpToHeight(limit uint64) (mempool.PrunedReceipts, error) {
 	et.Lock()
 	defer et.Unlock()
 
@@ -200,11 +218,179 @@ func (et *ExecutionTree) PruneUpToHeight(limit uint64) error {
 	// remove vertices and adjust size
 	err := et.forest.PruneUpToLevel(limit)
 	if err != nil {
-		return fmt.Errorf("pruning Levelled Forest up to height (aka level) %d failed: %w", limit, err)
+		return mempool.PrunedReceipts{}, fmt.Errorf("pruning Levelled Forest up to height (aka level) %d failed: %w", limit, err)
 	}
 	et.size -= numberReceiptsRemoved
 
-	return nil
+	return mempool.PrunedReceipts{BelowLowestHeight: numberReceiptsRemoved}, nil
+}
+
+// PruneUnsealable prunes the results which can never be sealed: results for blocks
+// conflicting with the finalized block at their height (orphaned forks), and results
+// for the sealed block conflicting with the sealed result, as well as all results
+// descending from either. Only heights with a finalized block are inspected, so
+// results for unfinalized blocks are retained. Heights are only inspected again if
+// results were added at them since, or if they became the sealed height.
+func (et *ExecutionTree) PruneUnsealable(sealedResultID flow.Identifier, sealedHeight uint64, finalized mempool.FinalizedBlockLookup) (mempool.PrunedReceipts, error) {
+	et.Lock()
+	defer et.Unlock()
+
+	var pruned mempool.PrunedReceipts
+	removed := make(map[flow.Identifier]struct{})
+
+	// inspect prunes the results at the given height, if its block is finalized
+	inspect := func(l uint64) bool {
+		finalizedID, ok := finalized(l)
+		if !ok {
+			return false
+		}
+
+		iterator := et.forest.GetVerticesAtLevel(l)
+		for iterator.HasNext() {
+			receiptsForResult := iterator.NextVertex().(*ReceiptsOfSameResult)
+			resultID := receiptsForResult.VertexID()
+			if _, ok := removed[resultID]; ok {
+				continue
+			}
+			switch {
+			case receiptsForResult.blockHeader.ID() != finalizedID:
+				pruned.Orphaned += et.collectSubtree(receiptsForResult, removed)
+			case l == sealedHeight && resultID != sealedResultID:
+				pruned.ConflictingSeal += et.collectSubtree(receiptsForResult, removed)
+			}
+		}
+		return true
+	}
+
+	// heights which were inspected before are only inspected again if results were
+	// added at them since, or if they became the sealed height
+	if sealedHeight != et.checkedSealedHeight && sealedHeight < et.uncheckedHeight {
+		et.recheck[sealedHeight] = struct{}{}
+	}
+	for l := range et.recheck {
+		if l >= et.forest.LowestLevel && et.forest.GetNumberOfVerticesAtLevel(l) > 0 && !inspect(l) {
+			continue
+		}
+		delete(et.recheck, l)
+	}
+
+	l := et.uncheckedHeight
+	if l < et.forest.LowestLevel {
+		l = et.forest.LowestLevel
+	}
+	for ; l <= et.highestHeight; l++ {
+		if et.forest.GetNumberOfVerticesAtLevel(l) == 0 {
+			continue
+		}
+		if !inspect(l) {
+			// as heights are finalized in order, no higher height is finalized either
+			break
+		}
+		et.uncheckedHeight = l + 1
+	}
+	et.checkedSealedHeight = sealedHeight
+
+	if len(removed) == 0 {
+		return pruned, nil
+	}
+
+	// the levelled forest doesn't support removing individual vertices, hence we rebuild
+	// it from the retained vertices, with parents added before their children
+	retained := forest.NewLevelledForest(et.forest.LowestLevel)
+	for l := et.forest.LowestLevel; l <= et.highestHeight; l++ {
+		iterator := et.forest.GetVerticesAtLevel(l)
+		for iterator.HasNext() {
+			vertex := iterator.NextVertex()
+			if _, ok := removed[vertex.VertexID()]; ok {
+				continue
+			}
+			retained.AddVertex(vertex)
+		}
+	}
+	et.forest = *retained
+	et.size -= pruned.Total()
+
+	return pruned, nil
+}
+
+// collectSubtree adds the given result and all results descending from it to `removed`,
+// and returns the number of their receipts.
+func (et *ExecutionTree) collectSubtree(receiptsForResult *ReceiptsOfSameResult, removed map[flow.Identifier]struct{}) uint {
+	removed[receiptsForResult.VertexID()] = struct{}{}
+	count := receiptsForResult.Size()
+
+	children := et.forest.GetChildren(receiptsForResult.VertexID())
+	for children.HasNext() {
+		child := children.NextVertex().(*ReceiptsOfSameResult)
+		if _, ok := removed[child.VertexID()]; ok {
+			continue
+		}
+		count += et.collectSubtree(child, removed)
+	}
+	return count
+}
+
+// Export returns a serializable export of the results stored in the Execution Tree,
+// with the result for `sealedResultID` marked as the sealed root. Results at the same
+// height are ordered by their ID, so that the export is deterministic.
+func (et *ExecutionTree) Export(sealedResultID flow.Identifier) mempool.ExecutionForks {
+	et.RLock()
+	defer et.RUnlock()
+
+	forks := mempool.ExecutionForks{
+		SealedResultID: sealedResultID,
+		LowestHeight:   et.forest.LowestLevel,
+	}
+	for l := et.forest.LowestLevel; l <= et.highestHeight; l++ {
+		var results []mempool.ExecutionForkResult
+		iterator := et.forest.GetVerticesAtLevel(l)
+		for iterator.HasNext() {
+			receiptsForResult := iterator.NextVertex().(*ReceiptsOfSameResult)
+			results = append(results, exportResult(receiptsForResult, sealedResultID))
+		}
+		sort.Slice(results, func(i, j int) bool {
+			return bytes.Compare(results[i].ResultID[:], results[j].ResultID[:]) < 0
+		})
+
+		for _, result := range results {
+			forks.Results = append(forks.Results, result)
+
+			var children flow.IdentifierList
+			iterator := et.forest.GetChildren(result.ResultID)
+			for iterator.HasNext() {
+				children = append(children, iterator.NextVertex().VertexID())
+			}
+			sort.Sort(children)
+			for _, childID := range children {
+				forks.Edges = append(forks.Edges, mempool.ExecutionForkEdge{
+					ParentResultID: result.ResultID,
+					ResultID:       childID,
+				})
+			}
+		}
+	}
+
+	return forks
+}
+
+// exportResult returns the export of the result of the equivalence class, along with the
+// executors of its receipts.
+func exportResult(receiptsForResult *ReceiptsOfSameResult, sealedResultID flow.Identifier) mempool.ExecutionForkResult {
+	executorIDs := make(flow.IdentifierList, 0, len(receiptsForResult.receipts))
+	for _, meta := range receiptsForResult.receipts {
+		executorIDs = append(executorIDs, meta.ExecutorID)
+	}
+	sort.Sort(executorIDs)
+
+	resultID := receiptsForResult.VertexID()
+	return mempool.ExecutionForkResult{
+		ResultID:       resultID,
+		ParentResultID: receiptsForResult.result.PreviousResultID,
+		BlockID:        receiptsForResult.blockHeader.ID(),
+		Height:         receiptsForResult.blockHeader.Height,
+		ExecutorIDs:    executorIDs,
+		Sealed:         resultID == sealedResultID,
+	}
 }
 
 // Size returns the number of receipts stored in the mempool
@@ -356,8 +356,9 @@ func (et *ExecutionTreeTestSuite) Test_Prune() {
 	assert.Equal(et.T(), uint64(0), et.Forest.LowestHeight())
 
 	// prunes all receipts for blocks with height _smaller_ than 12
-	err := et.Forest.PruneUpToHeight(12)
+	pruned, err := et.Forest.PruneUpToHeight(12)
 	assert.NoError(et.T(), err)
+	assert.Equal(et.T(), uint(8), pruned.BelowLowestHeight)
 	assert.Equal(et.T(), uint(4), et.Forest.Size())
 
 	// now, searching results from r[B11] should fail as the receipts were pruned
@@ -371,6 +372,160 @@ func (et *ExecutionTreeTestSuite) Test_Prune() {
 	et.Assert().True(reflect.DeepEqual(expected, et.receiptSet(collectedReceipts, receipts)))
 }
 
+// Test_PruneUnsealable_Orphaned verifies that results for blocks conflicting with finalized
+// blocks are pruned together with their descendants, while results for unfinalized heights
+// are retained.
+func (et *ExecutionTreeTestSuite) Test_PruneUnsealable_Orphaned() {
+	blocks, results, receipts := et.createExecutionTree()
+	et.addReceipts2ReceiptsForest(receipts, blocks)
+
+	// B10 is sealed and B11 is finalized
+	finalized := finalizedLookup(blocks["B10"], blocks["B11"])
+	pruned, err := et.Forest.PruneUnsealable(results["r[B10]"].ID(), 10, finalized)
+	et.Require().NoError(err)
+
+	// r[A10] and r[A11] are pruned as A10 is orphaned, and r[C11] as C11 is orphaned
+	et.Assert().Equal(mempool.PrunedReceipts{Orphaned: 4}, pruned)
+	et.Assert().Equal(uint(8), et.Forest.Size())
+
+	_, err = et.Forest.ReachableReceipts(results["r[A10]"].ID(), anyBlock(), anyReceipt())
+	et.Assert().Error(err)
+	collectedReceipts, err := et.Forest.ReachableReceipts(results["r[B10]"].ID(), anyBlock(), anyReceipt())
+	et.Require().NoError(err)
+	expected := et.toSet("ER[r[B10]]", "ER[r[B11]_1]_1", "ER[r[B11]_1]_2", "ER[r[B12]_1]", "ER[r[B11]_2]", "ER[r[B12]_2]")
+	et.Assert().True(reflect.DeepEqual(expected, et.receiptSet(collectedReceipts, receipts)))
+}
+
+// Test_PruneUnsealable_ConflictingSeal verifies that results conflicting with the sealed result
+// are pruned together with their descendants.
+func (et *ExecutionTreeTestSuite) Test_PruneUnsealable_ConflictingSeal() {
+	blocks, results, receipts := et.createExecutionTree()
+	et.addReceipts2ReceiptsForest(receipts, blocks)
+
+	pruned, err := et.Forest.PruneUpToHeight(11)
+	et.Require().NoError(err)
+	et.Assert().Equal(mempool.PrunedReceipts{BelowLowestHeight: 2}, pruned)
+
+	// r[B11]_1 is sealed and B12 is finalized
+	finalized := finalizedLookup(blocks["B10"], blocks["B11"], blocks["B12"])
+	pruned, err = et.Forest.PruneUnsealable(results["r[B11_1]"].ID(), 11, finalized)
+	et.Require().NoError(err)
+
+	// r[B11]_2 and r[B12]_2 conflict with the sealed result, r[A11] and r[C11] are orphaned
+	et.Assert().Equal(mempool.PrunedReceipts{ConflictingSeal: 2, Orphaned: 3}, pruned)
+	et.Assert().Equal(uint(5), et.Forest.Size())
+
+	collectedReceipts, err := et.Forest.ReachableReceipts(results["r[B11_1]"].ID(), anyBlock(), anyReceipt())
+	et.Require().NoError(err)
+	expected := et.toSet("ER[r[B11]_1]_1", "ER[r[B11]_1]_2", "ER[r[B12]_1]")
+	et.Assert().True(reflect.DeepEqual(expected, et.receiptSet(collectedReceipts, receipts)))
+
+	// results for unfinalized heights are retained
+	collectedReceipts, err = et.Forest.ReachableReceipts(results["r[C13]"].ID(), anyBlock(), anyReceipt())
+	et.Require().NoError(err)
+	et.Assert().True(reflect.DeepEqual(et.toSet("ER[r[C13]]"), et.receiptSet(collectedReceipts, receipts)))
+}
+
+// Test_PruneUnsealable_Incremental verifies that heights are only inspected again if results were
+// added at them since, or if they became the sealed height.
+func (et *ExecutionTreeTestSuite) Test_PruneUnsealable_Incremental() {
+	blocks, results, receipts := et.createExecutionTree()
+	orphaned := receipts["ER[r[C11]]_1"]
+	delete(receipts, "ER[r[C11]]_1")
+	delete(receipts, "ER[r[C11]]_2")
+	delete(receipts, "ER[r[B12]_2]")
+	et.addReceipts2ReceiptsForest(receipts, blocks)
+
+	// B12 is finalized, and the lookup records the inspected heights
+	var inspected []uint64
+	lookup := finalizedLookup(blocks["B10"], blocks["B11"], blocks["B12"])
+	finalized := func(height uint64) (flow.Identifier, bool) {
+		inspected = append(inspected, height)
+		return lookup(height)
+	}
+
+	// r[A10] and r[A11] are pruned as A10 is orphaned
+	pruned, err := et.Forest.PruneUnsealable(results["r[B10]"].ID(), 10, finalized)
+	et.Require().NoError(err)
+	et.Assert().Equal(mempool.PrunedReceipts{Orphaned: 2}, pruned)
+	et.Assert().Equal([]uint64{10, 11, 12, 13}, inspected)
+
+	// only the unfinalized height is inspected again
+	inspected = nil
+	pruned, err = et.Forest.PruneUnsealable(results["r[B10]"].ID(), 10, finalized)
+	et.Require().NoError(err)
+	et.Assert().Equal(mempool.PrunedReceipts{}, pruned)
+	et.Assert().Equal([]uint64{13}, inspected)
+
+	// heights where results were added since are inspected again
+	_, err = et.Forest.AddReceipt(orphaned, blocks["C11"].Header)
+	et.Require().NoError(err)
+	inspected = nil
+	pruned, err = et.Forest.PruneUnsealable(results["r[B10]"].ID(), 10, finalized)
+	et.Require().NoError(err)
+	et.Assert().Equal(mempool.PrunedReceipts{Orphaned: 1}, pruned)
+	et.Assert().Equal([]uint64{11, 13}, inspected)
+
+	// the new sealed height is inspected again, r[B11]_2 conflicts with the sealed result
+	inspected = nil
+	pruned, err = et.Forest.PruneUnsealable(results["r[B11_1]"].ID(), 11, finalized)
+	et.Require().NoError(err)
+	et.Assert().Equal(mempool.PrunedReceipts{ConflictingSeal: 1}, pruned)
+	et.Assert().Equal([]uint64{11, 13}, inspected)
+}
+
+// Test_Export verifies that the export of the Execution Tree lists all stored results with the
+// executors of their receipts, the edges between the stored results, and marks the sealed result.
+func (et *ExecutionTreeTestSuite) Test_Export() {
+	blocks, results, receipts := et.createExecutionTree()
+	et.addReceipts2ReceiptsForest(receipts, blocks)
+
+	forks := et.Forest.Export(results["r[B10]"].ID())
+	et.Assert().Equal(results["r[B10]"].ID(), forks.SealedResultID)
+	et.Assert().Equal(uint64(0), forks.LowestHeight)
+	et.Require().Len(forks.Results, len(results)-1) // r[C12] has no receipts
+
+	exported := make(map[flow.Identifier]mempool.ExecutionForkResult)
+	for i, result := range forks.Results {
+		if i > 0 {
+			et.Assert().LessOrEqual(forks.Results[i-1].Height, result.Height)
+		}
+		exported[result.ResultID] = result
+	}
+	b11 := exported[results["r[B11_1]"].ID()]
+	et.Assert().Equal(results["r[B10]"].ID(), b11.ParentResultID)
+	et.Assert().Equal(blocks["B11"].ID(), b11.BlockID)
+	et.Assert().Equal(uint64(11), b11.Height)
+	et.Assert().ElementsMatch(flow.IdentifierList{receipts["ER[r[B11]_1]_1"].ExecutorID, receipts["ER[r[B11]_1]_2"].ExecutorID}, b11.ExecutorIDs)
+	et.Assert().False(b11.Sealed)
+	et.Assert().True(exported[results["r[B10]"].ID()].Sealed)
+
+	// r[C13] and r[D13] are not connected to any stored result, as r[C12] is not stored
+	edge := func(parent string, child string) mempool.ExecutionForkEdge {
+		return mempool.ExecutionForkEdge{ParentResultID: results[parent].ID(), ResultID: results[child].ID()}
+	}
+	et.Assert().ElementsMatch([]mempool.ExecutionForkEdge{
+		edge("r[A10]", "r[A11]"),
+		edge("r[B10]", "r[B11_1]"),
+		edge("r[B10]", "r[B11_2]"),
+		edge("r[B10]", "r[C11]"),
+		edge("r[B11_1]", "r[B12_1]"),
+		edge("r[B11_2]", "r[B12_2]"),
+	}, forks.Edges)
+}
+
+// finalizedLookup returns a lookup of the finalized blocks, which are the given blocks.
+func finalizedLookup(blocks ...*flow.Block) mempool.FinalizedBlockLookup {
+	return func(height uint64) (flow.Identifier, bool) {
+		for _, block := range blocks {
+			if block.Header.Height == height {
+				return block.ID(), true
+			}
+		}
+		return flow.ZeroID, false
+	}
+}
+
 func anyBlock() mempool.BlockFilter {
 	return func(*flow.Header) bool { return true }
 }
@@ -0,0 +1,207 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// PersistentSeals is a wrapper around a conventional mempool.IncorporatedResultSeals mempool,
+// which persists the seals in the database, so that a restart of the consensus node doesn't
+// lose the candidate seals constructed from the approvals collected so far, and stall sealing
+// until the approvals are collected again.
+//   * Each seal added to the wrapped mempool is persisted, and removed from the database when it
+//     is removed from or ejected by the wrapped mempool. Hence, the number of persisted seals is
+//     bounded by the limit of the wrapped mempool.
+//   * On startup, Replay adds the persisted seals to the wrapped mempool, and compacts the
+//     database by removing the seals which can't be included in blocks anymore.
+// Implementation is concurrency safe.
+type PersistentSeals struct {
+	mutex sync.Mutex
+	seals mempool.IncorporatedResultSeals
+	db    *badger.DB
+	log   zerolog.Logger
+}
+
+// NewPersistentSeals creates a wrapper persisting the seals of the given mempool in the database.
+func NewPersistentSeals(seals mempool.IncorporatedResultSeals, db *badger.DB, log zerolog.Logger) *PersistentSeals {
+	s := &PersistentSeals{
+		seals: seals,
+		db:    db,
+		log:   log.With().Str("mempool", "PersistentSeals").Logger(),
+	}
+	seals.RegisterEjectionCallbacks(s.onEject)
+	return s
+}
+
+// onEject is the callback, which the wrapped mempool should call whenever it ejects an element
+func (s *PersistentSeals) onEject(entity flow.Entity) {
+	// uncaught type assertion; should never panic as mempool.IncorporatedResultSeals only stores IncorporatedResultSeal
+	irSeal := entity.(*flow.IncorporatedResultSeal)
+	s.forget(irSeal.ID())
+}
+
+// Replay adds the seals persisted before a restart to the wrapped mempool, and returns the
+// number of seals added. Seals for blocks at or below the given sealed height, or for unknown
+// blocks, can't be included in blocks anymore, and are removed from the database. The remaining
+// seals are added in the order of the heights of their blocks, so that a wrapped mempool ejecting
+// seals of higher blocks once it is full keeps the seals needed to extend the chain of seals.
+func (s *PersistentSeals) Replay(headers storage.Headers, sealedHeight uint64) (uint, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var persisted []*flow.IncorporatedResultSeal
+	err := s.db.View(operation.FindPendingIncorporatedResultSeals(&persisted))
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve persisted seals: %w", err)
+	}
+
+	heights := make(map[flow.Identifier]uint64, len(persisted))
+	replayable := make([]*flow.IncorporatedResultSeal, 0, len(persisted))
+	for _, irSeal := range persisted {
+		header, err := headers.ByBlockID(irSeal.Seal.BlockID)
+		if errors.Is(err, storage.ErrNotFound) {
+			s.forget(irSeal.ID())
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("could not retrieve sealed block %x: %w", irSeal.Seal.BlockID, err)
+		}
+		if header.Height <= sealedHeight {
+			s.forget(irSeal.ID())
+			continue
+		}
+		heights[irSeal.ID()] = header.Height
+		replayable = append(replayable, irSeal)
+	}
+
+	sort.Slice(replayable, func(i int, j int) bool {
+		return heights[replayable[i].ID()] < heights[replayable[j].ID()]
+	})
+
+	var replayed uint
+	for _, irSeal := range replayable {
+		// the approvals collected for the incorporated result are not persisted, so the
+		// incorporated result is re-created with empty approvals
+		irSeal.IncorporatedResult = flow.NewIncorporatedResult(irSeal.IncorporatedResult.IncorporatedBlockID, irSeal.IncorporatedResult.Result)
+
+		added, err := s.seals.Add(irSeal)
+		if err != nil {
+			return replayed, fmt.Errorf("could not replay seal %x: %w", irSeal.ID(), err)
+		}
+		if !added {
+			s.forget(irSeal.ID())
+			continue
+		}
+		replayed++
+	}
+
+	s.log.Info().
+		Int("persisted", len(persisted)).
+		Uint("replayed", replayed).
+		Msg("replayed persisted seals")
+
+	return replayed, nil
+}
+
+// Add adds the given seal to the wrapped mempool, and persists it if the mempool accepted it.
+func (s *PersistentSeals) Add(irSeal *flow.IncorporatedResultSeal) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	added, err := s.seals.Add(irSeal)
+	if err != nil || !added {
+		return added, err
+	}
+
+	// the wrapped mempool might have ejected the seal right away
+	irSealID := irSeal.ID()
+	if _, exists := s.seals.ByID(irSealID); !exists {
+		return added, nil
+	}
+
+	err = operation.RetryOnConflict(s.db.Update, func(tx *badger.Txn) error {
+		err := operation.InsertPendingIncorporatedResultSeal(irSeal)(tx)
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return added, fmt.Errorf("could not persist seal %x: %w", irSealID, err)
+	}
+
+	return added, nil
+}
+
+// All returns all the IncorporatedResultSeals in the mempool
+func (s *PersistentSeals) All() []*flow.IncorporatedResultSeal {
+	return s.seals.All()
+}
+
+// ByID returns an IncorporatedResultSeal by its ID
+func (s *PersistentSeals) ByID(id flow.Identifier) (*flow.IncorporatedResultSeal, bool) {
+	return s.seals.ByID(id)
+}
+
+// Rem removes the IncorporatedResultSeal with id from the mempool and the database
+func (s *PersistentSeals) Rem(id flow.Identifier) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	removed := s.seals.Rem(id)
+	s.forget(id)
+	return removed
+}
+
+// Size returns the number of items in the mempool
+func (s *PersistentSeals) Size() uint {
+	return s.seals.Size()
+}
+
+// Limit returns the size limit of the mempool
+func (s *PersistentSeals) Limit() uint {
+	return s.seals.Limit()
+}
+
+// Clear removes all entities from the pool and the database.
+func (s *PersistentSeals) Clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, irSeal := range s.seals.All() {
+		s.forget(irSeal.ID())
+	}
+	s.seals.Clear()
+}
+
+// RegisterEjectionCallbacks adds the provided OnEjection callbacks
+func (s *PersistentSeals) RegisterEjectionCallbacks(callbacks ...mempool.OnEjection) {
+	s.seals.RegisterEjectionCallbacks(callbacks...)
+}
+
+// forget removes the seal with the given ID from the database. Failures are logged, as the
+// seal is removed from the database on the next replay at the latest, once it is sealed.
+func (s *PersistentSeals) forget(irSealID flow.Identifier) {
+	err := operation.RetryOnConflict(s.db.Update, func(tx *badger.Txn) error {
+		err := operation.RemovePendingIncorporatedResultSeal(irSealID)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		s.log.Error().Err(err).
+			Hex("seal_id", irSealID[:]).
+			Msg("could not remove persisted seal")
+	}
+}
@@ -0,0 +1,117 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// Test_PersistentSeals_ImplementsInterfaces is a compile-time check:
+// verifies that PersistentSeals implements mempool.IncorporatedResultSeals interface
+func Test_PersistentSeals_ImplementsInterfaces(t *testing.T) {
+	var _ mempool.IncorporatedResultSeals = &PersistentSeals{}
+}
+
+// Test_PersistentSeals_AddRem verifies that seals added to the mempool are persisted, and
+// removed from the database when they are removed from the mempool
+func Test_PersistentSeals_AddRem(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		seals := NewPersistentSeals(stdmap.NewIncorporatedResultSeals(stdmap.WithLimit(10)), db, zerolog.Nop())
+
+		irSeals := unittest.IncorporatedResultSeal.Fixtures(3)
+		for _, irSeal := range irSeals {
+			added, err := seals.Add(irSeal)
+			require.NoError(t, err)
+			require.True(t, added)
+		}
+		requirePersisted(t, db, irSeals...)
+
+		require.True(t, seals.Rem(irSeals[0].ID()))
+		requirePersisted(t, db, irSeals[1:]...)
+
+		seals.Clear()
+		requirePersisted(t, db)
+	})
+}
+
+// Test_PersistentSeals_Ejection verifies that seals ejected by the wrapped mempool are removed
+// from the database
+func Test_PersistentSeals_Ejection(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		seals := NewPersistentSeals(stdmap.NewIncorporatedResultSeals(stdmap.WithLimit(2)), db, zerolog.Nop())
+
+		irSeals := unittest.IncorporatedResultSeal.Fixtures(5)
+		for _, irSeal := range irSeals {
+			_, err := seals.Add(irSeal)
+			require.NoError(t, err)
+		}
+		require.Equal(t, uint(2), seals.Size())
+		requirePersisted(t, db, seals.All()...)
+	})
+}
+
+// Test_PersistentSeals_Replay verifies that replaying the persisted seals adds the seals for
+// unsealed blocks to the wrapped mempool, and removes the seals for sealed or unknown blocks
+// from the database
+func Test_PersistentSeals_Replay(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		seals := NewPersistentSeals(stdmap.NewIncorporatedResultSeals(stdmap.WithLimit(10)), db, zerolog.Nop())
+
+		sealed := unittest.IncorporatedResultSeal.Fixture()
+		unsealed := unittest.IncorporatedResultSeal.Fixture()
+		unknown := unittest.IncorporatedResultSeal.Fixture()
+		for _, irSeal := range []*flow.IncorporatedResultSeal{sealed, unsealed, unknown} {
+			_, err := seals.Add(irSeal)
+			require.NoError(t, err)
+		}
+
+		sealedHeader := unittest.BlockHeaderFixture()
+		sealedHeader.Height = 10
+		unsealedHeader := unittest.BlockHeaderFixture()
+		unsealedHeader.Height = 11
+
+		headers := &storagemock.Headers{}
+		headers.On("ByBlockID", sealed.Seal.BlockID).Return(&sealedHeader, nil)
+		headers.On("ByBlockID", unsealed.Seal.BlockID).Return(&unsealedHeader, nil)
+		headers.On("ByBlockID", mock.Anything).Return(nil, storage.ErrNotFound)
+
+		// replay into a fresh mempool, as after a restart
+		restarted := NewPersistentSeals(stdmap.NewIncorporatedResultSeals(stdmap.WithLimit(10)), db, zerolog.Nop())
+		replayed, err := restarted.Replay(headers, sealedHeader.Height)
+		require.NoError(t, err)
+		require.Equal(t, uint(1), replayed)
+
+		_, exists := restarted.ByID(unsealed.ID())
+		require.True(t, exists)
+		require.Equal(t, uint(1), restarted.Size())
+		requirePersisted(t, db, unsealed)
+	})
+}
+
+// requirePersisted requires the database to hold exactly the given seals
+func requirePersisted(t *testing.T, db *badger.DB, expected ...*flow.IncorporatedResultSeal) {
+	var persisted []*flow.IncorporatedResultSeal
+	err := db.View(operation.FindPendingIncorporatedResultSeals(&persisted))
+	require.NoError(t, err)
+
+	expectedIDs := make([]flow.Identifier, 0, len(expected))
+	for _, irSeal := range expected {
+		expectedIDs = append(expectedIDs, irSeal.ID())
+	}
+	persistedIDs := make([]flow.Identifier, 0, len(persisted))
+	for _, irSeal := range persisted {
+		persistedIDs = append(persistedIDs, irSeal.ID())
+	}
+	require.ElementsMatch(t, expectedIDs, persistedIDs)
+}
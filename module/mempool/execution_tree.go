@@ -77,11 +77,56 @@ type ExecutionTree interface {
 	// PruneUpToHeight prunes all results for all blocks with height up to but
 	// NOT INCLUDING `newLowestHeight`. Errors if newLowestHeight is smaller than
 	// the previous value (as we cannot recover previously pruned results).
-	PruneUpToHeight(newLowestHeight uint64) error
+	// Returns the numbers of pruned receipts.
+	PruneUpToHeight(newLowestHeight uint64) (PrunedReceipts, error)
+
+	// PruneUnsealable prunes the results which can never be sealed, so that the
+	// mempool doesn't grow with orphaned execution forks while sealing is stalled:
+	// * results for blocks which conflict with the finalized block at their height,
+	//   i.e. results for orphaned forks;
+	// * results for the sealed block at `sealedHeight`, which conflict with the
+	//   sealed result;
+	// as well as all results descending from either. Heights which were inspected
+	// before are skipped, unless results were added at them since, or they became
+	// the sealed height. Returns the numbers of pruned receipts by reason.
+	PruneUnsealable(sealedResultID flow.Identifier, sealedHeight uint64, finalized FinalizedBlockLookup) (PrunedReceipts, error)
 
 	// LowestHeight returns the lowest height, where results are still
 	// stored in the mempool.
 	LowestHeight() uint64
+
+	// Export returns a serializable export of the results stored in the mempool,
+	// along with the receipts committing to them and the edges between them, so
+	// that the execution forks can be inspected. The result with ID
+	// `sealedResultID` is marked as the sealed root of the Execution Tree.
+	Export(sealedResultID flow.Identifier) ExecutionForks
+}
+
+// ExecutionForks is a serializable export of the Execution Tree. The results
+// are its vertices, while an edge points from a result to a result derived from
+// it. Edges are only listed if both results are stored in the mempool.
+type ExecutionForks struct {
+	SealedResultID flow.Identifier       // ID of the latest sealed result, the root of the sealed fork
+	LowestHeight   uint64                // lowest height, where results are stored in the mempool
+	Results        []ExecutionForkResult // results ordered by the height of their block
+	Edges          []ExecutionForkEdge   // edges ordered by the height of the block of their parent result
+}
+
+// ExecutionForkResult is a result in the export of the Execution Tree.
+type ExecutionForkResult struct {
+	ResultID       flow.Identifier
+	ParentResultID flow.Identifier
+	BlockID        flow.Identifier
+	Height         uint64              // height of the block the result is for
+	ExecutorIDs    flow.IdentifierList // executors of the receipts committing to the result
+	Sealed         bool                // whether the result is the latest sealed result
+}
+
+// ExecutionForkEdge is an edge in the export of the Execution Tree, pointing
+// from a parent result to a result derived from it.
+type ExecutionForkEdge struct {
+	ParentResultID flow.Identifier
+	ResultID       flow.Identifier
 }
 
 // BlockFilter is used for controlling the ExecutionTree's Execution Tree search.
@@ -90,6 +135,23 @@ type ExecutionTree interface {
 // sub-tree of derived results is not traversed.
 type BlockFilter func(header *flow.Header) bool
 
+// FinalizedBlockLookup returns the ID of the finalized block at the given height,
+// or false if no block is finalized at the height yet.
+type FinalizedBlockLookup func(height uint64) (flow.Identifier, bool)
+
+// PrunedReceipts counts the receipts pruned from the ExecutionTree by the reason
+// for pruning them.
+type PrunedReceipts struct {
+	BelowLowestHeight uint // receipts for blocks below the lowest height
+	ConflictingSeal   uint // receipts for results conflicting with the sealed result, or descending from them
+	Orphaned          uint // receipts for results of blocks conflicting with finalized blocks, or descending from them
+}
+
+// Total returns the number of pruned receipts.
+func (p PrunedReceipts) Total() uint {
+	return p.BelowLowestHeight + p.ConflictingSeal + p.Orphaned
+}
+
 // ReceiptFilter is used to drop specific receipts from. It does NOT
 // affect the ExecutionTree's Execution Tree search.
 type ReceiptFilter func(receipt *flow.ExecutionReceipt) bool
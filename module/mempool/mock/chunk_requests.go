@@ -148,6 +148,43 @@ func (_m *ChunkRequests) Size() uint {
 	return r0
 }
 
+// Status provides a mock function with given fields: chunkID
+func (_m *ChunkRequests) Status(chunkID flow.Identifier) (*mempool.ChunkRequestStatus, bool) {
+	ret := _m.Called(chunkID)
+
+	var r0 *mempool.ChunkRequestStatus
+	if rf, ok := ret.Get(0).(func(flow.Identifier) *mempool.ChunkRequestStatus); ok {
+		r0 = rf(chunkID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mempool.ChunkRequestStatus)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(flow.Identifier) bool); ok {
+		r1 = rf(chunkID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// Summary provides a mock function with given fields:
+func (_m *ChunkRequests) Summary() mempool.ChunkRequestsSummary {
+	ret := _m.Called()
+
+	var r0 mempool.ChunkRequestsSummary
+	if rf, ok := ret.Get(0).(func() mempool.ChunkRequestsSummary); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(mempool.ChunkRequestsSummary)
+	}
+
+	return r0
+}
+
 // UpdateRequestHistory provides a mock function with given fields: chunkID, updater
 func (_m *ChunkRequests) UpdateRequestHistory(chunkID flow.Identifier, updater mempool.ChunkRequestHistoryUpdaterFunc) (uint64, time.Time, time.Duration, bool) {
 	ret := _m.Called(chunkID, updater)
@@ -49,6 +49,20 @@ func (_m *ExecutionTree) AddResult(result *flow.ExecutionResult, block *flow.Hea
 	return r0
 }
 
+// Export provides a mock function with given fields: sealedResultID
+func (_m *ExecutionTree) Export(sealedResultID flow.Identifier) mempool.ExecutionForks {
+	ret := _m.Called(sealedResultID)
+
+	var r0 mempool.ExecutionForks
+	if rf, ok := ret.Get(0).(func(flow.Identifier) mempool.ExecutionForks); ok {
+		r0 = rf(sealedResultID)
+	} else {
+		r0 = ret.Get(0).(mempool.ExecutionForks)
+	}
+
+	return r0
+}
+
 // LowestHeight provides a mock function with given fields:
 func (_m *ExecutionTree) LowestHeight() uint64 {
 	ret := _m.Called()
@@ -63,18 +77,46 @@ func (_m *ExecutionTree) LowestHeight() uint64 {
 	return r0
 }
 
+// PruneUnsealable provides a mock function with given fields: sealedResultID, sealedHeight, finalized
+func (_m *ExecutionTree) PruneUnsealable(sealedResultID flow.Identifier, sealedHeight uint64, finalized mempool.FinalizedBlockLookup) (mempool.PrunedReceipts, error) {
+	ret := _m.Called(sealedResultID, sealedHeight, finalized)
+
+	var r0 mempool.PrunedReceipts
+	if rf, ok := ret.Get(0).(func(flow.Identifier, uint64, mempool.FinalizedBlockLookup) mempool.PrunedReceipts); ok {
+		r0 = rf(sealedResultID, sealedHeight, finalized)
+	} else {
+		r0 = ret.Get(0).(mempool.PrunedReceipts)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(flow.Identifier, uint64, mempool.FinalizedBlockLookup) error); ok {
+		r1 = rf(sealedResultID, sealedHeight, finalized)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PruneUpToHeight provides a mock function with given fields: newLowestHeight
-func (_m *ExecutionTree) PruneUpToHeight(newLowestHeight uint64) error {
+func (_m *ExecutionTree) PruneUpToHeight(newLowestHeight uint64) (mempool.PrunedReceipts, error) {
 	ret := _m.Called(newLowestHeight)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64) error); ok {
+	var r0 mempool.PrunedReceipts
+	if rf, ok := ret.Get(0).(func(uint64) mempool.PrunedReceipts); ok {
 		r0 = rf(newLowestHeight)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(mempool.PrunedReceipts)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(newLowestHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // ReachableReceipts provides a mock function with given fields: resultID, blockFilter, receiptFilter
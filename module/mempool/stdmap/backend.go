@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/mempool"
 )
 
@@ -103,7 +104,11 @@ type Backend struct {
 	Backdata
 	limit             uint
 	eject             EjectFunc
+	policy            EvictionPolicy // optional, tracks the entities for the eject function
+	ejectionReason    string         // labels the ejections of the eject function in the metrics
 	ejectionCallbacks []mempool.OnEjection
+	metrics           module.MempoolMetrics
+	resource          string
 }
 
 // NewBackend creates a new memory pool backend.
@@ -112,6 +117,7 @@ func NewBackend(options ...OptionFunc) *Backend {
 		Backdata:          NewBackdata(),
 		limit:             uint(math.MaxUint32),
 		eject:             EjectTrueRandom,
+		ejectionReason:    EvictionRandom,
 		ejectionCallbacks: nil,
 	}
 	for _, option := range options {
@@ -133,6 +139,9 @@ func (b *Backend) Add(entity flow.Entity) bool {
 	b.Lock()
 	defer b.Unlock()
 	added := b.Backdata.Add(entity)
+	if added && b.policy != nil {
+		b.policy.Track(entity.ID(), entity)
+	}
 	b.reduce()
 	return added
 }
@@ -142,6 +151,9 @@ func (b *Backend) Rem(entityID flow.Identifier) bool {
 	b.Lock()
 	defer b.Unlock()
 	removed := b.Backdata.Rem(entityID)
+	if removed && b.policy != nil {
+		b.policy.Untrack(entityID)
+	}
 	return removed
 }
 
@@ -150,7 +162,12 @@ func (b *Backend) Rem(entityID flow.Identifier) bool {
 func (b *Backend) Adjust(entityID flow.Identifier, f func(flow.Entity) flow.Entity) (flow.Entity, bool) {
 	b.Lock()
 	defer b.Unlock()
-	return b.Backdata.Adjust(entityID, f)
+	entity, adjusted := b.Backdata.Adjust(entityID, f)
+	if adjusted && b.policy != nil {
+		b.policy.Untrack(entityID)
+		b.policy.Track(entity.ID(), entity)
+	}
+	return entity, adjusted
 }
 
 // ByID returns the given item from the pool.
@@ -193,6 +210,11 @@ func (b *Backend) All() []flow.Entity {
 func (b *Backend) Clear() {
 	b.Lock()
 	defer b.Unlock()
+	if b.policy != nil {
+		for entityID := range b.Backdata.entities {
+			b.policy.Untrack(entityID)
+		}
+	}
 	b.Backdata.Clear()
 }
 
@@ -219,15 +241,23 @@ func (b *Backend) reduce() {
 
 		// get the key from the eject function
 		key, _ := b.eject(b.entities)
+		reason := b.ejectionReason
 
 		// if the key is not actually part of the map, use stupid fallback eject
 		entity, ok := b.entities[key]
 		if !ok {
-			key, _ = EjectFakeRandom(b.entities)
+			key, entity = EjectFakeRandom(b.entities)
+			reason = EvictionRandom
 		}
 
 		// remove the key
 		delete(b.entities, key)
+		if b.policy != nil {
+			b.policy.Untrack(key)
+		}
+		if b.metrics != nil {
+			b.metrics.MempoolEjection(b.resource, reason)
+		}
 
 		// notify callback
 		for _, callback := range b.ejectionCallbacks {
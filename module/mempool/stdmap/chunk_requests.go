@@ -67,6 +67,7 @@ func (cs *ChunkRequests) RequestHistory(chunkID flow.Identifier) (uint64, time.T
 func (cs *ChunkRequests) Add(request *verification.ChunkDataPackRequest) bool {
 	status := &chunkRequestStatus{
 		ChunkDataPackRequest: request,
+		Added:                time.Now(),
 	}
 	return cs.Backend.Add(status)
 }
@@ -146,6 +147,54 @@ func (cs *ChunkRequests) All() []*verification.ChunkDataPackRequest {
 	return requests
 }
 
+// Status returns a snapshot of the chunk request with the specified chunk ID and its
+// request history, and whether such chunk request exists in the memory pool.
+func (cs *ChunkRequests) Status(chunkID flow.Identifier) (*mempool.ChunkRequestStatus, bool) {
+	var snapshot *mempool.ChunkRequestStatus
+	err := cs.Backend.Run(func(backdata map[flow.Identifier]flow.Entity) error {
+		entity, exists := backdata[chunkID]
+		if !exists {
+			return fmt.Errorf("not exist")
+		}
+		snapshot = toChunkRequestStatus(entity).snapshot()
+		return nil
+	})
+
+	return snapshot, err == nil
+}
+
+// Summary returns aggregate statistics about the request histories of all chunk requests
+// in the memory pool, taken as a consistent snapshot.
+func (cs *ChunkRequests) Summary() mempool.ChunkRequestsSummary {
+	summary := mempool.ChunkRequestsSummary{
+		Attempts: make(map[uint64]uint),
+	}
+
+	now := time.Now()
+	_ = cs.Backend.Run(func(backdata map[flow.Identifier]flow.Entity) error {
+		var oldest time.Time
+		for chunkID, entity := range backdata {
+			status := toChunkRequestStatus(entity)
+
+			summary.Pending++
+			summary.Attempts[status.Attempt]++
+			if status.Attempt > summary.MaxAttempts {
+				summary.MaxAttempts = status.Attempt
+			}
+			if oldest.IsZero() || status.Added.Before(oldest) {
+				oldest = status.Added
+				summary.OldestChunkID = chunkID
+			}
+		}
+		if !oldest.IsZero() {
+			summary.OldestAge = now.Sub(oldest)
+		}
+		return nil
+	})
+
+	return summary
+}
+
 // Size returns total number of chunk requests in the memory pool.
 func (cs ChunkRequests) Size() uint {
 	return cs.Backend.Size()
@@ -155,11 +204,23 @@ func (cs ChunkRequests) Size() uint {
 // some auxiliary attributes that are internal to ChunkRequests.
 type chunkRequestStatus struct {
 	*verification.ChunkDataPackRequest
+	Added       time.Time     // timestamp of adding the request to the mempool.
 	LastAttempt time.Time     // timestamp of last request dispatched for this chunk id.
 	RetryAfter  time.Duration // interval until request should be retried.
 	Attempt     uint64        // number of times this chunk request has been dispatched in the network.
 }
 
+// snapshot returns a copy of the request history, which is safe to use outside of the mempool.
+func (c chunkRequestStatus) snapshot() *mempool.ChunkRequestStatus {
+	return &mempool.ChunkRequestStatus{
+		Request:     c.ChunkDataPackRequest,
+		Added:       c.Added,
+		LastAttempt: c.LastAttempt,
+		RetryAfter:  c.RetryAfter,
+		Attempts:    c.Attempt,
+	}
+}
+
 func (c chunkRequestStatus) ID() flow.Identifier {
 	return c.ChunkID
 }
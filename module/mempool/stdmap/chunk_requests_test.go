@@ -188,3 +188,49 @@ func TestFailingUpdater(t *testing.T) {
 	}
 	unittest.RequireReturnsBefore(t, wg.Wait, 1*time.Second, "could not finish updating requests on time")
 }
+
+// TestChunkRequests_StatusAndSummary evaluates that the status of a chunk request reflects its request history,
+// and that the summary aggregates the request histories of all chunk requests.
+func TestChunkRequests_StatusAndSummary(t *testing.T) {
+	requests := stdmap.NewChunkRequests(10)
+
+	summary := requests.Summary()
+	require.Equal(t, uint(0), summary.Pending)
+	require.Equal(t, flow.ZeroID, summary.OldestChunkID)
+	require.Empty(t, summary.Attempts)
+
+	chunkReqs := unittest.ChunkDataPackRequestListFixture(3)
+	for _, request := range chunkReqs {
+		require.True(t, requests.Add(request))
+	}
+	_, exists := requests.Status(unittest.IdentifierFixture())
+	require.False(t, exists)
+
+	// requests the first chunk twice, and the second chunk once
+	updater := mempool.IncrementalAttemptUpdater()
+	for _, chunkID := range []flow.Identifier{chunkReqs[0].ChunkID, chunkReqs[0].ChunkID, chunkReqs[1].ChunkID} {
+		_, _, _, ok := requests.UpdateRequestHistory(chunkID, updater)
+		require.True(t, ok)
+	}
+
+	status, exists := requests.Status(chunkReqs[0].ChunkID)
+	require.True(t, exists)
+	require.Equal(t, chunkReqs[0], status.Request)
+	require.Equal(t, uint64(2), status.Attempts)
+	require.False(t, status.LastAttempt.Before(status.Added))
+
+	summary = requests.Summary()
+	require.Equal(t, uint(3), summary.Pending)
+	require.Equal(t, uint64(2), summary.MaxAttempts)
+	require.Equal(t, map[uint64]uint{0: 1, 1: 1, 2: 1}, summary.Attempts)
+	require.True(t, summary.OldestAge >= 0)
+
+	// no chunk request was added before the oldest one
+	oldest, exists := requests.Status(summary.OldestChunkID)
+	require.True(t, exists)
+	for _, request := range chunkReqs {
+		status, exists := requests.Status(request.ChunkID)
+		require.True(t, exists)
+		require.False(t, status.Added.Before(oldest.Added))
+	}
+}
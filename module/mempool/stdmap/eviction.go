@@ -0,0 +1,166 @@
+package stdmap
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Names of the eviction policies, used to label ejections in the mempool metrics.
+const (
+	EvictionRandom         = "random"
+	EvictionCustom         = "custom"
+	EvictionOldestInserted = "oldest_inserted"
+	EvictionLowestHeight   = "lowest_height"
+	EvictionLowestPriority = "lowest_priority"
+)
+
+// EvictionPolicy picks the entity to evict from a memory pool backend which overflows
+// its limit. In contrast to a plain EjectFunc, the backend notifies the policy about
+// the entities added to and removed from it, so that the policy can keep track of the
+// information it bases its choice on.
+type EvictionPolicy interface {
+	// Name returns the name of the policy, which labels the ejections in the metrics.
+	Name() string
+
+	// Track is called whenever the entity is added to the backend.
+	Track(entityID flow.Identifier, entity flow.Entity)
+
+	// Untrack is called whenever the entity is removed from the backend.
+	Untrack(entityID flow.Identifier)
+
+	// Eject picks the entity to evict from the entities of the backend. Entities
+	// modified through Backend.Run are not tracked, so that policies have to handle
+	// entities they don't know.
+	Eject(entities map[flow.Identifier]flow.Entity) (flow.Identifier, flow.Entity)
+}
+
+// OldestInsertedFirst is an eviction policy evicting the entity which was added
+// first to the backend.
+type OldestInsertedFirst struct {
+	ejector *LRUEjector
+}
+
+// NewOldestInsertedFirst creates a policy evicting the oldest entity first.
+func NewOldestInsertedFirst() *OldestInsertedFirst {
+	return &OldestInsertedFirst{
+		ejector: NewLRUEjector(),
+	}
+}
+
+func (p *OldestInsertedFirst) Name() string {
+	return EvictionOldestInserted
+}
+
+func (p *OldestInsertedFirst) Track(entityID flow.Identifier, _ flow.Entity) {
+	p.ejector.Track(entityID)
+}
+
+func (p *OldestInsertedFirst) Untrack(entityID flow.Identifier) {
+	p.ejector.Untrack(entityID)
+}
+
+func (p *OldestInsertedFirst) Eject(entities map[flow.Identifier]flow.Entity) (flow.Identifier, flow.Entity) {
+	return p.ejector.Eject(entities)
+}
+
+// PriorityFunc returns the priority of keeping the entity in the memory pool, and
+// whether the priority is known. For instance, the priority of a seal might be the
+// height of its block, which is unknown as long as the block is not.
+type PriorityFunc func(entity flow.Entity) (uint64, bool)
+
+// LowestPriorityFirst is an eviction policy evicting the entity with the lowest
+// priority. Entities with unknown priority are evicted before all others, and ties
+// are broken by the entity IDs, so that the choice is deterministic. The priorities
+// are computed once, when an entity is added, so that priority functions looking up
+// data in storage aren't invoked for every ejection.
+type LowestPriorityFirst struct {
+	sync.Mutex
+	name       string
+	priority   PriorityFunc
+	priorities map[flow.Identifier]uint64
+}
+
+// NewLowestPriorityFirst creates a policy evicting the entity with the lowest priority first.
+func NewLowestPriorityFirst(priority PriorityFunc) *LowestPriorityFirst {
+	return newLowestPriorityFirst(EvictionLowestPriority, priority)
+}
+
+// NewLowestHeightFirst creates a policy evicting the entity with the lowest height
+// first, like the collection guarantee with the oldest reference block.
+func NewLowestHeightFirst(height PriorityFunc) *LowestPriorityFirst {
+	return newLowestPriorityFirst(EvictionLowestHeight, height)
+}
+
+func newLowestPriorityFirst(name string, priority PriorityFunc) *LowestPriorityFirst {
+	return &LowestPriorityFirst{
+		name:       name,
+		priority:   priority,
+		priorities: make(map[flow.Identifier]uint64),
+	}
+}
+
+func (p *LowestPriorityFirst) Name() string {
+	return p.name
+}
+
+func (p *LowestPriorityFirst) Track(entityID flow.Identifier, entity flow.Entity) {
+	priority, known := p.priority(entity)
+
+	p.Lock()
+	defer p.Unlock()
+
+	if !known {
+		// will be retried on ejection
+		delete(p.priorities, entityID)
+		return
+	}
+	p.priorities[entityID] = priority
+}
+
+func (p *LowestPriorityFirst) Untrack(entityID flow.Identifier) {
+	p.Lock()
+	defer p.Unlock()
+
+	delete(p.priorities, entityID)
+}
+
+func (p *LowestPriorityFirst) Eject(entities map[flow.Identifier]flow.Entity) (flow.Identifier, flow.Entity) {
+	p.Lock()
+	defer p.Unlock()
+
+	// the priorities are rebuilt from the entities of the backend, which drops the
+	// priorities of the entities removed through Backend.Run
+	priorities := make(map[flow.Identifier]uint64, len(entities))
+
+	var lowestID flow.Identifier
+	var lowestPriority uint64
+	lowestKnown := true
+	found := false
+	for entityID, entity := range entities {
+		priority, known := p.priorities[entityID]
+		if !known {
+			priority, known = p.priority(entity)
+		}
+		if known {
+			priorities[entityID] = priority
+		}
+
+		lower := !found ||
+			(lowestKnown && !known) ||
+			(lowestKnown == known && priority < lowestPriority) ||
+			(lowestKnown == known && priority == lowestPriority && bytes.Compare(entityID[:], lowestID[:]) < 0)
+		if lower {
+			lowestID = entityID
+			lowestPriority = priority
+			lowestKnown = known
+			found = true
+		}
+	}
+
+	delete(priorities, lowestID)
+	p.priorities = priorities
+
+	return lowestID, entities[lowestID]
+}
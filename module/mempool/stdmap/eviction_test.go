@@ -0,0 +1,145 @@
+package stdmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// prioritized is an entity with a priority, which might be unknown.
+type prioritized struct {
+	id       flow.Identifier
+	priority uint64
+	known    bool
+}
+
+func (p *prioritized) ID() flow.Identifier {
+	return p.id
+}
+
+func (p *prioritized) Checksum() flow.Identifier {
+	return p.id
+}
+
+func priorityOf(entity flow.Entity) (uint64, bool) {
+	p := entity.(*prioritized)
+	return p.priority, p.known
+}
+
+func prioritizedFixture(priority uint64) *prioritized {
+	return &prioritized{
+		id:       unittest.IdentifierFixture(),
+		priority: priority,
+		known:    true,
+	}
+}
+
+// TestOldestInsertedFirst verifies that the backend evicts the entities in the order
+// they were added with the oldest insertion first policy.
+func TestOldestInsertedFirst(t *testing.T) {
+	pool := NewBackend(WithLimit(3), WithEvictionPolicy(NewOldestInsertedFirst()))
+
+	entities := make([]*prioritized, 0, 5)
+	for i := 0; i < 5; i++ {
+		entity := prioritizedFixture(0)
+		entities = append(entities, entity)
+		require.True(t, pool.Add(entity))
+	}
+
+	require.Equal(t, uint(3), pool.Size())
+	for i, entity := range entities {
+		assert.Equal(t, i >= 2, pool.Has(entity.ID()))
+	}
+}
+
+// TestLowestPriorityFirst verifies that the backend evicts the entities with the lowest
+// priority with the lowest priority first policy, and entities with unknown priority
+// before all others.
+func TestLowestPriorityFirst(t *testing.T) {
+	pool := NewBackend(WithLimit(3), WithEvictionPolicy(NewLowestPriorityFirst(priorityOf)))
+
+	high := prioritizedFixture(30)
+	medium := prioritizedFixture(20)
+	low := prioritizedFixture(10)
+	unknown := &prioritized{id: unittest.IdentifierFixture()}
+	for _, entity := range []*prioritized{high, unknown, medium, low} {
+		require.True(t, pool.Add(entity))
+	}
+
+	// the entity with unknown priority is evicted first
+	require.Equal(t, uint(3), pool.Size())
+	assert.False(t, pool.Has(unknown.ID()))
+
+	// the lowest priority is evicted next, even if it was just added
+	lowest := prioritizedFixture(5)
+	require.True(t, pool.Add(lowest))
+	assert.False(t, pool.Has(lowest.ID()))
+
+	highest := prioritizedFixture(40)
+	require.True(t, pool.Add(highest))
+	assert.False(t, pool.Has(low.ID()))
+	assert.True(t, pool.Has(medium.ID()))
+	assert.True(t, pool.Has(high.ID()))
+	assert.True(t, pool.Has(highest.ID()))
+}
+
+// TestLowestPriorityFirst_Untracked verifies that the lowest priority first policy handles
+// entities which were added to the backend without being tracked.
+func TestLowestPriorityFirst_Untracked(t *testing.T) {
+	pool := NewBackend(WithLimit(2), WithEvictionPolicy(NewLowestPriorityFirst(priorityOf)))
+
+	high := prioritizedFixture(30)
+	low := prioritizedFixture(10)
+	require.True(t, pool.Add(high))
+	err := pool.Run(func(entities map[flow.Identifier]flow.Entity) error {
+		entities[low.ID()] = low
+		return nil
+	})
+	require.NoError(t, err)
+
+	medium := prioritizedFixture(20)
+	require.True(t, pool.Add(medium))
+	assert.False(t, pool.Has(low.ID()))
+	assert.True(t, pool.Has(medium.ID()))
+	assert.True(t, pool.Has(high.ID()))
+}
+
+// TestEjectionMetrics verifies that the backend reports ejections labeled with the name
+// of the eviction policy.
+func TestEjectionMetrics(t *testing.T) {
+	metrics := &mock.MempoolMetrics{}
+	metrics.On("MempoolEjection", "resource", EvictionLowestHeight).Return().Once()
+
+	pool := NewBackend(
+		WithLimit(1),
+		WithEvictionPolicy(NewLowestHeightFirst(priorityOf)),
+		WithEjectionMetrics("resource", metrics),
+	)
+	require.True(t, pool.Add(prioritizedFixture(1)))
+	require.True(t, pool.Add(prioritizedFixture(2)))
+
+	metrics.AssertExpectations(t)
+}
+
+// TestEjectionCallbacks_Fallback verifies that the ejection callbacks are notified about the
+// entity ejected by the fallback, if the eject function picks an unknown entity.
+func TestEjectionCallbacks_Fallback(t *testing.T) {
+	pool := NewBackend(WithLimit(1), WithEject(func(map[flow.Identifier]flow.Entity) (flow.Identifier, flow.Entity) {
+		return flow.ZeroID, nil
+	}))
+
+	var ejected []flow.Entity
+	pool.RegisterEjectionCallbacks(func(entity flow.Entity) {
+		ejected = append(ejected, entity)
+	})
+
+	require.True(t, pool.Add(prioritizedFixture(1)))
+	require.True(t, pool.Add(prioritizedFixture(2)))
+	require.Len(t, ejected, 1)
+	assert.NotNil(t, ejected[0])
+}
@@ -13,9 +13,9 @@ type Guarantees struct {
 }
 
 // NewGuarantees creates a new memory pool for collection guarantees.
-func NewGuarantees(limit uint) (*Guarantees, error) {
+func NewGuarantees(limit uint, opts ...OptionFunc) (*Guarantees, error) {
 	g := &Guarantees{
-		Backend: NewBackend(WithLimit(limit)),
+		Backend: NewBackend(append(opts, WithLimit(limit))...),
 	}
 
 	return g, nil
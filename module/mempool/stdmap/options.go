@@ -2,6 +2,10 @@
 
 package stdmap
 
+import (
+	"github.com/onflow/flow-go/module"
+)
+
 // OptionFunc is a function that can be provided to the backend on creation in
 // order to set a certain custom option.
 type OptionFunc func(*Backend)
@@ -20,5 +24,27 @@ func WithLimit(limit uint) OptionFunc {
 func WithEject(eject EjectFunc) OptionFunc {
 	return func(be *Backend) {
 		be.eject = eject
+		be.policy = nil
+		be.ejectionReason = EvictionCustom
+	}
+}
+
+// WithEvictionPolicy can be provided to the backend on creation in order to pick
+// the entity to be evicted upon overflow with the given policy, which the backend
+// notifies about the entities added to and removed from it.
+func WithEvictionPolicy(policy EvictionPolicy) OptionFunc {
+	return func(be *Backend) {
+		be.eject = policy.Eject
+		be.policy = policy
+		be.ejectionReason = policy.Name()
+	}
+}
+
+// WithEjectionMetrics can be provided to the backend on creation in order to report
+// the ejections of entities upon overflow as ejections of the given resource.
+func WithEjectionMetrics(resource string, metrics module.MempoolMetrics) OptionFunc {
+	return func(be *Backend) {
+		be.resource = resource
+		be.metrics = metrics
 	}
 }
@@ -38,6 +38,27 @@ type NetworkMetrics interface {
 
 	// InboundConnections updates the metric tracking the number of inbound connections of this node
 	InboundConnections(connectionCount uint)
+
+	// UnicastStreamCreated increments the metric tracking the number of streams created for unicast messages
+	UnicastStreamCreated()
+
+	// UnicastStreamReused increments the metric tracking the number of unicast messages sent on a reused stream
+	UnicastStreamReused()
+
+	// UnicastStreamsIdle updates the metric tracking the number of idle unicast streams kept for reuse
+	UnicastStreamsIdle(streamCount uint)
+
+	// DNSLookupDuration tracks the time spent resolving a hostname of a node address
+	DNSLookupDuration(duration time.Duration)
+
+	// OnDNSCacheHit increments the metric tracking the number of hostnames resolved from the cache
+	OnDNSCacheHit()
+
+	// OnDNSCacheMiss increments the metric tracking the number of hostnames not found in the cache
+	OnDNSCacheMiss()
+
+	// OnDNSLookupFailed increments the metric tracking the number of failed lookups of hostnames
+	OnDNSLookupFailed()
 }
 
 type EngineMetrics interface {
@@ -54,6 +75,16 @@ type ComplianceMetrics interface {
 	BlockProposalDuration(duration time.Duration)
 }
 
+type ClockSkewMetrics interface {
+	// ClockSkewObserved reports the skew of a timestamp created by another node relative to the
+	// local clock, which is positive if the timestamp is ahead of the local clock.
+	ClockSkewObserved(skew time.Duration)
+
+	// ClockSkewEstimated reports the estimated skew of the clocks of the other nodes relative to
+	// the local clock, which is the median of their recently observed skews.
+	ClockSkewEstimated(skew time.Duration)
+}
+
 type CleanerMetrics interface {
 	RanGC(took time.Duration)
 }
@@ -71,6 +102,9 @@ type CacheMetrics interface {
 
 type MempoolMetrics interface {
 	MempoolEntries(resource string, entries uint)
+	// MempoolEjection reports that the mempool of the resource ejected an entity after exceeding
+	// its limit, labeled with the eviction policy which picked the entity.
+	MempoolEjection(resource string, reason string)
 	Register(resource string, entriesFunc EntriesFunc) error
 }
 
@@ -99,6 +133,14 @@ type HotstuffMetrics interface {
 	// SetTimeout sets the current timeout duration
 	SetTimeout(duration time.Duration)
 
+	// CountTimeoutIncrease reports the number of times the replica timeout increased
+	// after the replica timed out in a view.
+	CountTimeoutIncrease()
+
+	// ReplicaTimeoutDuration measures the distribution of the replica timeout durations,
+	// i.e. of how long the replica waits for the block of each view before timing out.
+	ReplicaTimeoutDuration(duration time.Duration)
+
 	// CommitteeProcessingDuration measures the time which the HotStuff's core logic
 	// spends in the hotstuff.Committee component, i.e. the time determining consensus
 	// committee relations.
@@ -148,6 +190,10 @@ type ConsensusMetrics interface {
 	// EmergencySeal increments the number of seals that were created in emergency mode
 	EmergencySeal()
 
+	// ApprovalThresholdDecayed increments the number of times the approvals required to seal
+	// an unsealed result decayed
+	ApprovalThresholdDecayed()
+
 	// OnReceiptProcessingDuration records the number of seconds spent processing a receipt
 	OnReceiptProcessingDuration(duration time.Duration)
 
@@ -156,6 +202,10 @@ type ConsensusMetrics interface {
 
 	// CheckSealingDuration records absolute time for the full sealing check by the consensus match engine
 	CheckSealingDuration(duration time.Duration)
+
+	// PayloadTruncated records the number of entities of the given resource which were left out of a
+	// block payload, because the payload would have exceeded its byte size limit
+	PayloadTruncated(resource string, dropped uint)
 }
 
 type VerificationMetrics interface {
@@ -193,6 +243,10 @@ type VerificationMetrics interface {
 	// at assigner engine. Note that it assumes blocks are coming to assigner engine in strictly increasing order of their height.
 	OnFinalizedBlockArrivedAtAssigner(height uint64)
 
+	// OnBlockConsumerProgress sets the gauges that keep track of the height of the last finalized block whose results
+	// have been processed for assignment, and of the number of finalized blocks still pending for assignment.
+	OnBlockConsumerProgress(processedHeight uint64, backlog uint64)
+
 	// OnChunksAssignmentDoneAtAssigner increments a counter that keeps track of the total number of assigned chunks to
 	// the verification node.
 	OnChunksAssignmentDoneAtAssigner(chunks int)
@@ -228,8 +282,17 @@ type VerificationMetrics interface {
 	// requester engine.
 	OnChunkDataPackArrivedAtFetcher()
 
+	// OnConflictingChunkDataPacksAtFetcher increments a counter that keeps track of number of chunk data packs arrived at fetcher engine
+	// that conflict with a chunk data pack of the same chunk received from another execution node.
+	OnConflictingChunkDataPacksAtFetcher()
+
 	// OnVerifiableChunkSentToVerifier increments a counter that keeps track of number of verifiable chunks fetcher engine sent to verifier engine.
 	OnVerifiableChunkSentToVerifier()
+
+	// OnChunkVerifiedAtVerifier is called whenever the verifier engine executed the transactions of a chunk. It increments
+	// the counters that keep track of the transactions executed by the verifier engine, of the failed ones among them, and of
+	// the computation they used, and records the time spent verifying the chunk.
+	OnChunkVerifiedAtVerifier(transactions int, failedTransactions int, computationUsed uint64, duration time.Duration)
 }
 
 // LedgerMetrics provides an interface to record Ledger Storage metrics.
@@ -242,6 +305,15 @@ type LedgerMetrics interface {
 	// ForestNumberOfTrees current number of trees in a forest (in memory)
 	ForestNumberOfTrees(number uint64)
 
+	// ForestInterimNodeCount records the number of interim nodes of the forest, counting nodes shared by several trees once
+	ForestInterimNodeCount(number uint64)
+
+	// ForestLeafPayloadSize records the size (in bytes) of the payloads held by the leaves of the forest
+	ForestLeafPayloadSize(bytes uint64)
+
+	// ForestSharedMemorySavings records the approximate memory (in bytes) saved by sharing subtries between the trees of the forest
+	ForestSharedMemorySavings(bytes uint64)
+
 	// LatestTrieRegCount records the number of unique register allocated (the latest created trie)
 	LatestTrieRegCount(number uint64)
 
@@ -254,6 +326,9 @@ type LedgerMetrics interface {
 	// LatestTrieMaxDepthDiff records the difference between the max depth of the latest created trie and parent trie
 	LatestTrieMaxDepthDiff(number uint64)
 
+	// LatestTrieApproxMemorySize records approximate memory usage of the latest created trie, including subtries shared with other tries
+	LatestTrieApproxMemorySize(bytes uint64)
+
 	// UpdateCount increase a counter of performed updates
 	UpdateCount()
 
@@ -288,6 +363,22 @@ type LedgerMetrics interface {
 type WALMetrics interface {
 	// DiskSize records the amount of disk space used by the storage (in bytes)
 	DiskSize(uint64)
+
+	// WALSynced records the duration of a sync of the write-ahead log and the number of records it made durable
+	WALSynced(duration time.Duration, records int)
+}
+
+type LedgerReplicaMetrics interface {
+	// ReplicaLag records the number of tries not yet added to the query replica of the ledger and the age of the oldest one
+	ReplicaLag(pending int, lag time.Duration)
+
+	// ReplicaQuery records a query, and whether it was served by the replica or fell back to the ledger
+	ReplicaQuery(servedByReplica bool)
+}
+
+type LedgerPrunerMetrics interface {
+	// TriesPruned records the tries pruned from the ledger, and the number and approximate size in bytes of the nodes reclaimed by pruning them
+	TriesPruned(tries int, nodes uint64, bytes uint64)
 }
 
 type RuntimeMetrics interface {
@@ -305,6 +396,10 @@ type ProviderMetrics interface {
 	// ChunkDataPackRequested is executed every time a chunk data pack request is arrived at execution node.
 	// It increases the request counter by one.
 	ChunkDataPackRequested()
+
+	// ChunkDataPackCacheLookup reports a lookup of a requested chunk data pack in the cache of the chunk
+	// data packs of recent blocks, whether the chunk data pack was found in the cache, and its duration.
+	ChunkDataPackCacheLookup(hit bool, duration time.Duration)
 }
 
 type ExecutionMetrics interface {
@@ -312,6 +407,8 @@ type ExecutionMetrics interface {
 	RuntimeMetrics
 	ProviderMetrics
 	WALMetrics
+	LedgerReplicaMetrics
+	LedgerPrunerMetrics
 
 	// StartBlockReceivedToExecuted starts a span to trace the duration of a block
 	// from being received for execution to execution being finished
@@ -336,6 +433,14 @@ type ExecutionMetrics interface {
 	// ExecutionTotalExecutedTransactions adds num to the total number of executed transactions
 	ExecutionTotalExecutedTransactions(numExecuted int)
 
+	// ExecutionTransactionErrorsPerBlock reports the number of transactions of a block which failed
+	// with the given fvm error code
+	ExecutionTransactionErrorsPerBlock(errorCode uint16, count int)
+
+	// ExecutionTransactionExecuted reports the execution time and the computation used by a transaction.
+	// System transactions report the hash of their script, user transactions report the zero ID.
+	ExecutionTransactionExecuted(dur time.Duration, compUsed uint64, systemScriptHash flow.Identifier)
+
 	// ExecutionCollectionRequestSent reports when a request for a collection is sent to a collection node
 	ExecutionCollectionRequestSent()
 
@@ -371,3 +476,12 @@ type PingMetrics interface {
 	// version is the software version the target node is running
 	NodeReachable(node *flow.Identity, nodeInfo string, rtt time.Duration, version string, sealedHeight uint64)
 }
+
+type ExecutionStateIndexerMetrics interface {
+	// BlockIndexed reports the height of a block which was added to the register index, along with the
+	// number of registers it updated and the time it took to index it
+	BlockIndexed(height uint64, registers int, duration time.Duration)
+
+	// IndexerLag reports the number of sealed blocks which are not yet indexed
+	IndexerLag(blocks uint64)
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type ClockSkewCollector struct {
+	observedSkew  prometheus.Histogram
+	estimatedSkew prometheus.Gauge
+}
+
+func NewClockSkewCollector() *ClockSkewCollector {
+	cc := &ClockSkewCollector{
+		observedSkew: promauto.NewHistogram(prometheus.HistogramOpts{
+			Name:      "observed_clock_skew_seconds",
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemTimeSync,
+			Help:      "the skew of timestamps created by other nodes relative to the local clock",
+			Buckets:   []float64{-30, -10, -5, -2, -1, -0.5, -0.1, 0, 0.1, 0.5, 1, 2, 5, 10, 30},
+		}),
+		estimatedSkew: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "estimated_clock_skew_seconds",
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemTimeSync,
+			Help:      "the median skew of the clocks of other nodes relative to the local clock",
+		}),
+	}
+
+	return cc
+}
+
+func (cc *ClockSkewCollector) ClockSkewObserved(skew time.Duration) {
+	cc.observedSkew.Observe(skew.Seconds())
+}
+
+func (cc *ClockSkewCollector) ClockSkewEstimated(skew time.Duration) {
+	cc.estimatedSkew.Set(skew.Seconds())
+}
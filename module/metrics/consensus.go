@@ -37,6 +37,12 @@ type ConsensusCollector struct {
 
 	// The number of emergency seals
 	emergencySealedBlocks prometheus.Counter
+
+	// The number of times the approvals required to seal a result decayed
+	approvalThresholdDecays prometheus.Counter
+
+	// The number of entities left out of block payloads because of the payload size limit
+	truncatedPayloadEntities *prometheus.CounterVec
 }
 
 // NewConsensusCollector created a new consensus collector
@@ -65,18 +71,34 @@ func NewConsensusCollector(tracer module.Tracer, registerer prometheus.Registere
 		Subsystem: subsystemCompliance,
 		Help:      "the number of blocks sealed in emergency mode",
 	})
+	approvalThresholdDecays := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "approval_threshold_decays_total",
+		Namespace: namespaceConsensus,
+		Subsystem: subsystemMatchEngine,
+		Help:      "the number of times the approvals required to seal an unsealed result decayed",
+	})
+	truncatedPayloadEntities := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "truncated_payload_entities_total",
+		Namespace: namespaceConsensus,
+		Subsystem: subsystemCompliance,
+		Help:      "the number of entities left out of block payloads because of the payload size limit",
+	}, []string{LabelResource})
 	registerer.MustRegister(
 		onReceiptDuration,
 		onApprovalDuration,
 		checkSealingDuration,
 		emergencySealedBlocks,
+		approvalThresholdDecays,
+		truncatedPayloadEntities,
 	)
 	cc := &ConsensusCollector{
-		tracer:                tracer,
-		onReceiptDuration:     onReceiptDuration,
-		onApprovalDuration:    onApprovalDuration,
-		checkSealingDuration:  checkSealingDuration,
-		emergencySealedBlocks: emergencySealedBlocks,
+		tracer:                   tracer,
+		onReceiptDuration:        onReceiptDuration,
+		onApprovalDuration:       onApprovalDuration,
+		checkSealingDuration:     checkSealingDuration,
+		emergencySealedBlocks:    emergencySealedBlocks,
+		approvalThresholdDecays:  approvalThresholdDecays,
+		truncatedPayloadEntities: truncatedPayloadEntities,
 	}
 	return cc
 }
@@ -106,6 +128,11 @@ func (cc *ConsensusCollector) EmergencySeal() {
 	cc.emergencySealedBlocks.Inc()
 }
 
+// ApprovalThresholdDecayed increments the counter of approval threshold decays.
+func (cc *ConsensusCollector) ApprovalThresholdDecayed() {
+	cc.approvalThresholdDecays.Inc()
+}
+
 // OnReceiptProcessingDuration increases the number of seconds spent processing receipts
 func (cc *ConsensusCollector) OnReceiptProcessingDuration(duration time.Duration) {
 	cc.onReceiptDuration.Add(duration.Seconds())
@@ -120,3 +147,8 @@ func (cc *ConsensusCollector) OnApprovalProcessingDuration(duration time.Duratio
 func (cc *ConsensusCollector) CheckSealingDuration(duration time.Duration) {
 	cc.checkSealingDuration.Add(duration.Seconds())
 }
+
+// PayloadTruncated increases the number of entities of the given resource left out of block payloads
+func (cc *ConsensusCollector) PayloadTruncated(resource string, dropped uint) {
+	cc.truncatedPayloadEntities.With(prometheus.Labels{LabelResource: resource}).Add(float64(dropped))
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -17,20 +19,39 @@ const (
 	executionBlockReceivedToExecuted = "execution_block_received_to_executed"
 )
 
+// maxSystemScriptLabels caps the number of system script hashes labeling the transaction metrics, further
+// system scripts are labeled as other scripts.
+const maxSystemScriptLabels = 16
+
+// Labels of the scripts of transactions which aren't labeled by their hash.
+const (
+	scriptUser  = "user"
+	scriptOther = "other_system"
+)
+
 type ExecutionCollector struct {
 	tracer                           module.Tracer
 	gasUsedPerBlock                  prometheus.Histogram
 	stateReadsPerBlock               prometheus.Histogram
 	totalExecutedTransactionsCounter prometheus.Counter
+	transactionErrorsCounter         *prometheus.CounterVec
+	transactionExecutionTime         *prometheus.HistogramVec
+	transactionComputationUsed       *prometheus.HistogramVec
+	systemScriptsLock                sync.Mutex
+	systemScripts                    map[flow.Identifier]string // labels of the known system scripts
 	lastExecutedBlockHeightGauge     prometheus.Gauge
 	stateStorageDiskTotal            prometheus.Gauge
 	storageStateCommitment           prometheus.Gauge
 	forestApproxMemorySize           prometheus.Gauge
 	forestNumberOfTrees              prometheus.Gauge
+	forestInterimNodeCount           prometheus.Gauge
+	forestLeafPayloadSize            prometheus.Gauge
+	forestSharedMemorySavings        prometheus.Gauge
 	latestTrieRegCount               prometheus.Gauge
 	latestTrieRegCountDiff           prometheus.Gauge
 	latestTrieMaxDepth               prometheus.Gauge
 	latestTrieMaxDepthDiff           prometheus.Gauge
+	latestTrieApproxMemorySize       prometheus.Gauge
 	updated                          prometheus.Counter
 	proofSize                        prometheus.Gauge
 	updatedValuesNumber              prometheus.Counter
@@ -47,8 +68,17 @@ type ExecutionCollector struct {
 	transactionCheckTime             prometheus.Histogram
 	transactionInterpretTime         prometheus.Histogram
 	totalChunkDataPackRequests       prometheus.Counter
+	chunkDataPackCacheLookupTime     *prometheus.HistogramVec
 	stateSyncActive                  prometheus.Gauge
 	executionStateDiskUsage          prometheus.Gauge
+	walSyncDuration                  prometheus.Histogram
+	walSyncBatchSize                 prometheus.Histogram
+	replicaPendingTries              prometheus.Gauge
+	replicaLag                       prometheus.Gauge
+	replicaQueries                   *prometheus.CounterVec
+	prunedTries                      prometheus.Counter
+	prunedNodes                      prometheus.Counter
+	prunedBytes                      prometheus.Counter
 }
 
 func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registerer) *ExecutionCollector {
@@ -67,6 +97,27 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 		Help:      "number of trees in memory",
 	})
 
+	forestInterimNodeCount := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemMTrie,
+		Name:      "forest_interim_node_count",
+		Help:      "number of interim nodes in memory, counting nodes shared by several trees once",
+	})
+
+	forestLeafPayloadSize := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemMTrie,
+		Name:      "forest_leaf_payload_size",
+		Help:      "size of the payloads held by the leaves in memory in bytes",
+	})
+
+	forestSharedMemorySavings := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemMTrie,
+		Name:      "forest_shared_memory_savings",
+		Help:      "approximate memory saved by sharing subtries between trees in bytes",
+	})
+
 	latestTrieRegCount := prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: namespaceExecution,
 		Subsystem: subsystemMTrie,
@@ -95,6 +146,13 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 		Help:      "the difference between the max depth of the latest created trie and parent trie",
 	})
 
+	latestTrieApproxMemorySize := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemMTrie,
+		Name:      "latest_trie_approx_memory_size",
+		Help:      "approximate size of the latest created trie in bytes, including subtries shared with other tries",
+	})
+
 	updatedCount := prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: namespaceExecution,
 		Subsystem: subsystemMTrie,
@@ -213,10 +271,14 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 
 	registerer.MustRegister(forestApproxMemorySize)
 	registerer.MustRegister(forestNumberOfTrees)
+	registerer.MustRegister(forestInterimNodeCount)
+	registerer.MustRegister(forestLeafPayloadSize)
+	registerer.MustRegister(forestSharedMemorySavings)
 	registerer.MustRegister(latestTrieRegCount)
 	registerer.MustRegister(latestTrieRegCountDiff)
 	registerer.MustRegister(latestTrieMaxDepth)
 	registerer.MustRegister(latestTrieMaxDepthDiff)
+	registerer.MustRegister(latestTrieApproxMemorySize)
 	registerer.MustRegister(updatedCount)
 	registerer.MustRegister(proofSize)
 	registerer.MustRegister(updatedValuesNumber)
@@ -239,10 +301,14 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 
 		forestApproxMemorySize:     forestApproxMemorySize,
 		forestNumberOfTrees:        forestNumberOfTrees,
+		forestInterimNodeCount:     forestInterimNodeCount,
+		forestLeafPayloadSize:      forestLeafPayloadSize,
+		forestSharedMemorySavings:  forestSharedMemorySavings,
 		latestTrieRegCount:         latestTrieRegCount,
 		latestTrieRegCountDiff:     latestTrieRegCountDiff,
 		latestTrieMaxDepth:         latestTrieMaxDepth,
 		latestTrieMaxDepthDiff:     latestTrieMaxDepthDiff,
+		latestTrieApproxMemorySize: latestTrieApproxMemorySize,
 		updated:                    updatedCount,
 		proofSize:                  proofSize,
 		updatedValuesNumber:        updatedValuesNumber,
@@ -283,6 +349,39 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 			Help:      "the total number of transactions that have been executed",
 		}),
 
+		transactionErrorsCounter: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "transaction_errors_total",
+			Help:      "the total number of executed transactions which failed, by fvm error code",
+		}, []string{LabelErrorCode}),
+
+		chunkDataPackCacheLookupTime: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemProvider,
+			Name:      "chunk_data_pack_cache_lookup_seconds",
+			Help:      "the duration of lookups of requested chunk data packs in the cache of recent chunk data packs, by hit or miss",
+			Buckets:   []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1},
+		}, []string{LabelResult}),
+
+		transactionExecutionTime: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "transaction_execution_time_seconds",
+			Help:      "the execution time of transactions, by hash of their script for system transactions",
+			Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+		}, []string{LabelScript}),
+
+		transactionComputationUsed: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "transaction_computation_used",
+			Help:      "the computation used by transactions, by hash of their script for system transactions",
+			Buckets:   []float64{10, 100, 1000, 10000, 100000, 1000000},
+		}, []string{LabelScript}),
+
+		systemScripts: make(map[flow.Identifier]string),
+
 		lastExecutedBlockHeightGauge: promauto.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespaceExecution,
 			Subsystem: subsystemRuntime,
@@ -317,6 +416,64 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 			Name:      "execution_state_disk_usage",
 			Help:      "disk usage of execution state",
 		}),
+
+		walSyncDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemMTrie,
+			Name:      "wal_sync_duration_seconds",
+			Help:      "duration of syncing the write-ahead log to disk",
+			Buckets:   []float64{0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5},
+		}),
+
+		walSyncBatchSize: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemMTrie,
+			Name:      "wal_sync_batch_size",
+			Help:      "number of write-ahead log records made durable by a single sync",
+			Buckets:   []float64{1, 2, 5, 10, 50, 100},
+		}),
+
+		replicaPendingTries: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemMTrie,
+			Name:      "replica_pending_tries",
+			Help:      "number of tries not yet added to the query replica of the ledger",
+		}),
+
+		replicaLag: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemMTrie,
+			Name:      "replica_lag_seconds",
+			Help:      "age of the oldest trie not yet added to the query replica of the ledger",
+		}),
+
+		replicaQueries: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemMTrie,
+			Name:      "replica_queries_total",
+			Help:      "number of queries to the query replica of the ledger, by whether the replica served them",
+		}, []string{"served_by"}),
+
+		prunedTries: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemMTrie,
+			Name:      "pruned_tries_total",
+			Help:      "number of tries pruned from the ledger",
+		}),
+
+		prunedNodes: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemMTrie,
+			Name:      "pruned_nodes_total",
+			Help:      "number of nodes reclaimed by pruning tries from the ledger",
+		}),
+
+		prunedBytes: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemMTrie,
+			Name:      "pruned_bytes_total",
+			Help:      "approximate size in bytes of the nodes reclaimed by pruning tries from the ledger",
+		}),
 	}
 
 	return ec
@@ -364,6 +521,40 @@ func (ec *ExecutionCollector) ExecutionTotalExecutedTransactions(numberOfTx int)
 	ec.totalExecutedTransactionsCounter.Add(float64(numberOfTx))
 }
 
+// ExecutionTransactionErrorsPerBlock reports the number of failed transactions of a block for the given fvm error code
+func (ec *ExecutionCollector) ExecutionTransactionErrorsPerBlock(errorCode uint16, count int) {
+	ec.transactionErrorsCounter.WithLabelValues(strconv.Itoa(int(errorCode))).Add(float64(count))
+}
+
+// ExecutionTransactionExecuted reports the execution time and the computation used by a transaction. The
+// metrics of system transactions are labeled by the hash of their script, up to a maximum number of
+// scripts, so that the cost of the system chunk can be told apart from the load of users.
+func (ec *ExecutionCollector) ExecutionTransactionExecuted(dur time.Duration, compUsed uint64, systemScriptHash flow.Identifier) {
+	script := ec.scriptLabel(systemScriptHash)
+	ec.transactionExecutionTime.WithLabelValues(script).Observe(dur.Seconds())
+	ec.transactionComputationUsed.WithLabelValues(script).Observe(float64(compUsed))
+}
+
+func (ec *ExecutionCollector) scriptLabel(systemScriptHash flow.Identifier) string {
+	if systemScriptHash == flow.ZeroID {
+		return scriptUser
+	}
+
+	ec.systemScriptsLock.Lock()
+	defer ec.systemScriptsLock.Unlock()
+
+	label, ok := ec.systemScripts[systemScriptHash]
+	if ok {
+		return label
+	}
+	if len(ec.systemScripts) >= maxSystemScriptLabels {
+		return scriptOther
+	}
+	label = systemScriptHash.String()
+	ec.systemScripts[systemScriptHash] = label
+	return label
+}
+
 // ForestApproxMemorySize records approximate memory usage of forest (all in-memory trees)
 func (ec *ExecutionCollector) ForestApproxMemorySize(bytes uint64) {
 	ec.forestApproxMemorySize.Set(float64(bytes))
@@ -374,6 +565,21 @@ func (ec *ExecutionCollector) ForestNumberOfTrees(number uint64) {
 	ec.forestNumberOfTrees.Set(float64(number))
 }
 
+// ForestInterimNodeCount records the number of interim nodes of the forest, counting nodes shared by several trees once
+func (ec *ExecutionCollector) ForestInterimNodeCount(number uint64) {
+	ec.forestInterimNodeCount.Set(float64(number))
+}
+
+// ForestLeafPayloadSize records the size (in bytes) of the payloads held by the leaves of the forest
+func (ec *ExecutionCollector) ForestLeafPayloadSize(bytes uint64) {
+	ec.forestLeafPayloadSize.Set(float64(bytes))
+}
+
+// ForestSharedMemorySavings records the approximate memory (in bytes) saved by sharing subtries between the trees of the forest
+func (ec *ExecutionCollector) ForestSharedMemorySavings(bytes uint64) {
+	ec.forestSharedMemorySavings.Set(float64(bytes))
+}
+
 // LatestTrieRegCount records the number of unique register allocated (the lastest created trie)
 func (ec *ExecutionCollector) LatestTrieRegCount(number uint64) {
 	ec.latestTrieRegCount.Set(float64(number))
@@ -394,6 +600,11 @@ func (ec *ExecutionCollector) LatestTrieMaxDepthDiff(number uint64) {
 	ec.latestTrieMaxDepthDiff.Set(float64(number))
 }
 
+// LatestTrieApproxMemorySize records approximate memory usage of the latest created trie, including subtries shared with other tries
+func (ec *ExecutionCollector) LatestTrieApproxMemorySize(bytes uint64) {
+	ec.latestTrieApproxMemorySize.Set(float64(bytes))
+}
+
 // UpdateCount increase a counter of performed updates
 func (ec *ExecutionCollector) UpdateCount() {
 	ec.updated.Inc()
@@ -473,6 +684,16 @@ func (ec *ExecutionCollector) ChunkDataPackRequested() {
 	ec.totalChunkDataPackRequests.Inc()
 }
 
+// ChunkDataPackCacheLookup reports a lookup of a requested chunk data pack in the cache of recent
+// chunk data packs, labelled by whether it hit or missed.
+func (ec *ExecutionCollector) ChunkDataPackCacheLookup(hit bool, duration time.Duration) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	ec.chunkDataPackCacheLookupTime.WithLabelValues(result).Observe(duration.Seconds())
+}
+
 func (ec *ExecutionCollector) ExecutionSync(syncing bool) {
 	if syncing {
 		ec.stateSyncActive.Set(float64(1))
@@ -484,3 +705,31 @@ func (ec *ExecutionCollector) ExecutionSync(syncing bool) {
 func (ec *ExecutionCollector) DiskSize(bytes uint64) {
 	ec.executionStateDiskUsage.Set(float64(bytes))
 }
+
+// WALSynced records the duration of a sync of the write-ahead log and the number of records it made durable
+func (ec *ExecutionCollector) WALSynced(duration time.Duration, records int) {
+	ec.walSyncDuration.Observe(duration.Seconds())
+	ec.walSyncBatchSize.Observe(float64(records))
+}
+
+// ReplicaLag records the number of tries not yet added to the query replica of the ledger and the age of the oldest one
+func (ec *ExecutionCollector) ReplicaLag(pending int, lag time.Duration) {
+	ec.replicaPendingTries.Set(float64(pending))
+	ec.replicaLag.Set(lag.Seconds())
+}
+
+// ReplicaQuery records a query, and whether it was served by the replica or fell back to the ledger
+func (ec *ExecutionCollector) ReplicaQuery(servedByReplica bool) {
+	if servedByReplica {
+		ec.replicaQueries.WithLabelValues("replica").Inc()
+		return
+	}
+	ec.replicaQueries.WithLabelValues("ledger").Inc()
+}
+
+// TriesPruned records the tries pruned from the ledger, and the number and approximate size in bytes of the nodes reclaimed by pruning them
+func (ec *ExecutionCollector) TriesPruned(tries int, nodes uint64, bytes uint64) {
+	ec.prunedTries.Add(float64(tries))
+	ec.prunedNodes.Add(float64(nodes))
+	ec.prunedBytes.Add(float64(bytes))
+}
@@ -32,6 +32,8 @@ type HotstuffCollector struct {
 	skips                         prometheus.Counter
 	timeouts                      prometheus.Counter
 	timeoutDuration               prometheus.Gauge
+	timeoutIncreases              prometheus.Counter
+	replicaTimeoutDuration        prometheus.Histogram
 	committeeComputationsDuration prometheus.Histogram
 	signerComputationsDuration    prometheus.Histogram
 	validatorComputationsDuration prometheus.Histogram
@@ -109,6 +111,23 @@ func NewHotstuffCollector(chain flow.ChainID) *HotstuffCollector {
 			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
 		}),
 
+		timeoutIncreases: promauto.NewCounter(prometheus.CounterOpts{
+			Name:        "timeout_increases_total",
+			Namespace:   namespaceConsensus,
+			Subsystem:   subsystemHotstuff,
+			Help:        "The number of times the replica timeout increased after a timeout",
+			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
+		}),
+
+		replicaTimeoutDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+			Name:        "replica_timeout_seconds",
+			Namespace:   namespaceConsensus,
+			Subsystem:   subsystemHotstuff,
+			Help:        "duration [seconds; measured with float64 precision] of the replica timeouts started by the pacemaker",
+			Buckets:     []float64{0.5, 1, 2, 5, 10, 30, 60},
+			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
+		}),
+
 		committeeComputationsDuration: promauto.NewHistogram(prometheus.HistogramOpts{
 			Name:        "committee_computations_seconds",
 			Namespace:   namespaceConsensus,
@@ -189,6 +208,16 @@ func (hc *HotstuffCollector) SetTimeout(duration time.Duration) {
 	hc.timeoutDuration.Set(duration.Seconds()) // unit: seconds; with float64 precision
 }
 
+// CountTimeoutIncrease counts the number of times the replica timeout increased.
+func (hc *HotstuffCollector) CountTimeoutIncrease() {
+	hc.timeoutIncreases.Inc()
+}
+
+// ReplicaTimeoutDuration measures the durations of the replica timeouts.
+func (hc *HotstuffCollector) ReplicaTimeoutDuration(duration time.Duration) {
+	hc.replicaTimeoutDuration.Observe(duration.Seconds()) // unit: seconds; with float64 precision
+}
+
 // CommitteeProcessingDuration measures the time which the HotStuff's core logic
 // spends in the hotstuff.Committee component, i.e. the time determining consensus
 // committee relations.
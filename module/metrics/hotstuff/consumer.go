@@ -1,6 +1,8 @@
 package consensus
 
 import (
+	"time"
+
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
 	"github.com/onflow/flow-go/model/flow"
@@ -14,6 +16,9 @@ type MetricsConsumer struct {
 	// inherit from noop consumer in order to satisfy the full interface
 	notifications.NoopConsumer
 	metrics module.HotstuffMetrics
+
+	// replicaTimeout is the duration of the last replica timeout, to detect timeout increases
+	replicaTimeout time.Duration
 }
 
 func NewMetricsConsumer(metrics module.HotstuffMetrics) *MetricsConsumer {
@@ -40,4 +45,13 @@ func (c *MetricsConsumer) OnReachedTimeout(info *model.TimerInfo) {
 
 func (c *MetricsConsumer) OnStartingTimeout(info *model.TimerInfo) {
 	c.metrics.SetTimeout(info.Duration)
+
+	if info.Mode != model.ReplicaTimeout {
+		return
+	}
+	c.metrics.ReplicaTimeoutDuration(info.Duration)
+	if c.replicaTimeout != 0 && info.Duration > c.replicaTimeout {
+		c.metrics.CountTimeoutIncrease()
+	}
+	c.replicaTimeout = info.Duration
 }
@@ -13,6 +13,10 @@ const (
 	LabelNodeInfo    = "nodeinfo"
 	LabelNodeVersion = "nodeversion"
 	LabelPriority    = "priority"
+	LabelErrorCode   = "error_code"
+	LabelReason      = "reason"
+	LabelScript      = "script"
+	LabelResult      = "result"
 )
 
 const (
@@ -74,6 +78,8 @@ const (
 	ResourceApprovalQueue            = "sealing_approval_queue"          // consensus node, sealing engine
 	ResourceReceiptQueue             = "sealing_receipt_queue"           // consensus node, sealing engine
 	ResourceApprovalResponseQueue    = "sealing_approval_response_queue" // consensus node, sealing engine
+	ResourceValidatedResult          = "validated_result"                // consensus node, receipt validator
+	ResourceSpockVerification        = "spock_verification"              // consensus node, sealing engine
 	ResourceBlockProposalQueue       = "compliance_proposal_queue"       // consensus node, compliance engine
 	ResourceBlockVoteQueue           = "compliance_vote_queue"           // consensus node, compliance engine
 	ResourceChunkDataPack            = "chunk_data_pack"                 // execution node
@@ -14,6 +14,7 @@ import (
 type MempoolCollector struct {
 	unit         *engine.Unit
 	entries      *prometheus.GaugeVec
+	ejections    *prometheus.CounterVec
 	interval     time.Duration
 	delay        time.Duration
 	entriesFuncs map[string]module.EntriesFunc // keeps map of registered EntriesFunc of mempools
@@ -33,6 +34,13 @@ func NewMempoolCollector(interval time.Duration) *MempoolCollector {
 			Subsystem: subsystemMempool,
 			Help:      "the number of entries in the mempool",
 		}, []string{LabelResource}),
+
+		ejections: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "ejections_total",
+			Namespace: namespaceStorage,
+			Subsystem: subsystemMempool,
+			Help:      "the number of entities ejected from the mempool after exceeding its limit",
+		}, []string{LabelResource, LabelReason}),
 	}
 
 	return mc
@@ -42,6 +50,10 @@ func (mc *MempoolCollector) MempoolEntries(resource string, entries uint) {
 	mc.entries.With(prometheus.Labels{LabelResource: resource}).Set(float64(entries))
 }
 
+func (mc *MempoolCollector) MempoolEjection(resource string, reason string) {
+	mc.ejections.With(prometheus.Labels{LabelResource: resource, LabelReason: reason}).Inc()
+}
+
 // Register registers entriesFunc for a resource
 func (mc *MempoolCollector) Register(resource string, entriesFunc module.EntriesFunc) error {
 	mc.unit.Lock()
@@ -15,9 +15,12 @@ const (
 // Network subsystems represent the various layers of networking.
 const (
 	// subsystemLibp2p = "libp2p"
-	subsystemGossip = "gossip"
-	subsystemEngine = "engine"
-	subsystemQueue  = "queue"
+	subsystemGossip   = "gossip"
+	subsystemEngine   = "engine"
+	subsystemQueue    = "queue"
+	subsystemTimeSync = "time_sync"
+	subsystemUnicast  = "unicast"
+	subsystemDNS      = "dns"
 )
 
 // Storage subsystems represent the various components of the storage layer.
@@ -31,6 +34,7 @@ const (
 const (
 	subsystemTransactionTiming     = "transaction_timing"
 	subsystemTransactionSubmission = "transaction_submission"
+	subsystemStateIndexer          = "state_indexer"
 )
 
 // Collection subsystem
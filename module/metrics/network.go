@@ -24,6 +24,13 @@ type NetworkCollector struct {
 	inboundProcessTime       *prometheus.CounterVec
 	outboundConnectionCount  prometheus.Gauge
 	inboundConnectionCount   prometheus.Gauge
+	unicastStreamsCreated    prometheus.Counter
+	unicastStreamsReused     prometheus.Counter
+	unicastStreamsIdle       prometheus.Gauge
+	dnsLookupDuration        prometheus.Histogram
+	dnsCacheHits             prometheus.Counter
+	dnsCacheMisses           prometheus.Counter
+	dnsLookupFailures        prometheus.Counter
 }
 
 func NewNetworkCollector() *NetworkCollector {
@@ -88,6 +95,56 @@ func NewNetworkCollector() *NetworkCollector {
 			Name:      "inbound_connection_count",
 			Help:      "the number of inbound connections of this node",
 		}),
+
+		unicastStreamsCreated: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemUnicast,
+			Name:      "streams_created_total",
+			Help:      "the number of streams created for unicast messages",
+		}),
+
+		unicastStreamsReused: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemUnicast,
+			Name:      "streams_reused_total",
+			Help:      "the number of unicast messages sent on a reused stream",
+		}),
+
+		unicastStreamsIdle: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemUnicast,
+			Name:      "idle_streams",
+			Help:      "the number of idle unicast streams kept for reuse",
+		}),
+
+		dnsLookupDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemDNS,
+			Name:      "lookup_duration_seconds",
+			Help:      "duration [seconds; measured with float64 precision] of the lookups of hostnames of node addresses",
+			Buckets:   []float64{0.001, 0.01, 0.1, 0.5, 1, 2, 5},
+		}),
+
+		dnsCacheHits: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemDNS,
+			Name:      "cache_hits_total",
+			Help:      "the number of hostnames resolved from the cache",
+		}),
+
+		dnsCacheMisses: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemDNS,
+			Name:      "cache_misses_total",
+			Help:      "the number of hostnames not found in the cache",
+		}),
+
+		dnsLookupFailures: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemDNS,
+			Name:      "lookup_failures_total",
+			Help:      "the number of failed lookups of hostnames",
+		}),
 	}
 
 	return nc
@@ -134,3 +191,31 @@ func (nc *NetworkCollector) OutboundConnections(connectionCount uint) {
 func (nc *NetworkCollector) InboundConnections(connectionCount uint) {
 	nc.inboundConnectionCount.Set(float64(connectionCount))
 }
+
+func (nc *NetworkCollector) UnicastStreamCreated() {
+	nc.unicastStreamsCreated.Inc()
+}
+
+func (nc *NetworkCollector) UnicastStreamReused() {
+	nc.unicastStreamsReused.Inc()
+}
+
+func (nc *NetworkCollector) UnicastStreamsIdle(streamCount uint) {
+	nc.unicastStreamsIdle.Set(float64(streamCount))
+}
+
+func (nc *NetworkCollector) DNSLookupDuration(duration time.Duration) {
+	nc.dnsLookupDuration.Observe(duration.Seconds())
+}
+
+func (nc *NetworkCollector) OnDNSCacheHit() {
+	nc.dnsCacheHits.Inc()
+}
+
+func (nc *NetworkCollector) OnDNSCacheMiss() {
+	nc.dnsCacheMisses.Inc()
+}
+
+func (nc *NetworkCollector) OnDNSLookupFailed() {
+	nc.dnsLookupFailures.Inc()
+}
@@ -27,6 +27,13 @@ func (nc *NoopCollector) MessageReceived(engine string, message string)
 func (nc *NoopCollector) MessageHandled(engine string, message string)                           {}
 func (nc *NoopCollector) OutboundConnections(_ uint)                                             {}
 func (nc *NoopCollector) InboundConnections(_ uint)                                              {}
+func (nc *NoopCollector) UnicastStreamCreated()                                                  {}
+func (nc *NoopCollector) UnicastStreamReused()                                                   {}
+func (nc *NoopCollector) UnicastStreamsIdle(_ uint)                                              {}
+func (nc *NoopCollector) DNSLookupDuration(duration time.Duration)                               {}
+func (nc *NoopCollector) OnDNSCacheHit()                                                         {}
+func (nc *NoopCollector) OnDNSCacheMiss()                                                        {}
+func (nc *NoopCollector) OnDNSLookupFailed()                                                     {}
 func (nc *NoopCollector) RanGC(duration time.Duration)                                           {}
 func (nc *NoopCollector) BadgerLSMSize(sizeBytes int64)                                          {}
 func (nc *NoopCollector) BadgerVLogSize(sizeBytes int64)                                         {}
@@ -44,11 +51,14 @@ func (nc *NoopCollector) BlockProposed(*flow.Block)
 func (nc *NoopCollector) BlockFinalized(*flow.Block)                                             {}
 func (nc *NoopCollector) BlockSealed(*flow.Block)                                                {}
 func (nc *NoopCollector) BlockProposalDuration(duration time.Duration)                           {}
+func (nc *NoopCollector) ClockSkewObserved(skew time.Duration)                                   {}
+func (nc *NoopCollector) ClockSkewEstimated(skew time.Duration)                                  {}
 func (nc *NoopCollector) CacheEntries(resource string, entries uint)                             {}
 func (nc *NoopCollector) CacheHit(resource string)                                               {}
 func (nc *NoopCollector) CacheNotFound(resource string)                                          {}
 func (nc *NoopCollector) CacheMiss(resource string)                                              {}
 func (nc *NoopCollector) MempoolEntries(resource string, entries uint)                           {}
+func (nc *NoopCollector) MempoolEjection(resource string, reason string)                         {}
 func (nc *NoopCollector) Register(resource string, entriesFunc module.EntriesFunc) error         { return nil }
 func (nc *NoopCollector) HotStuffBusyDuration(duration time.Duration, event string)              {}
 func (nc *NoopCollector) HotStuffIdleDuration(duration time.Duration)                            {}
@@ -58,6 +68,8 @@ func (nc *NoopCollector) SetQCView(view uint64)
 func (nc *NoopCollector) CountSkipped()                                                          {}
 func (nc *NoopCollector) CountTimeout()                                                          {}
 func (nc *NoopCollector) SetTimeout(duration time.Duration)                                      {}
+func (nc *NoopCollector) CountTimeoutIncrease()                                                  {}
+func (nc *NoopCollector) ReplicaTimeoutDuration(duration time.Duration)                          {}
 func (nc *NoopCollector) CommitteeProcessingDuration(duration time.Duration)                     {}
 func (nc *NoopCollector) SignerProcessingDuration(duration time.Duration)                        {}
 func (nc *NoopCollector) ValidatorProcessingDuration(duration time.Duration)                     {}
@@ -70,9 +82,11 @@ func (nc *NoopCollector) FinishCollectionToFinalized(collectionID flow.Identifie
 func (nc *NoopCollector) StartBlockToSeal(blockID flow.Identifier)                               {}
 func (nc *NoopCollector) FinishBlockToSeal(blockID flow.Identifier)                              {}
 func (nc *NoopCollector) EmergencySeal()                                                         {}
+func (nc *NoopCollector) ApprovalThresholdDecayed()                                              {}
 func (nc *NoopCollector) OnReceiptProcessingDuration(duration time.Duration)                     {}
 func (nc *NoopCollector) OnApprovalProcessingDuration(duration time.Duration)                    {}
 func (nc *NoopCollector) CheckSealingDuration(duration time.Duration)                            {}
+func (nc *NoopCollector) PayloadTruncated(resource string, dropped uint)                         {}
 func (nc *NoopCollector) OnExecutionReceiptReceived()                                            {}
 func (nc *NoopCollector) OnExecutionResultSent()                                                 {}
 func (nc *NoopCollector) OnExecutionResultReceived()                                             {}
@@ -82,6 +96,7 @@ func (nc *NoopCollector) OnChunkDataPackReceived()
 func (nc *NoopCollector) OnChunkDataPackRequested()                                              {}
 func (nc *NoopCollector) OnResultApprovalDispatchedInNetwork()                                   {}
 func (nc *NoopCollector) OnFinalizedBlockArrivedAtAssigner(height uint64)                        {}
+func (nc *NoopCollector) OnBlockConsumerProgress(processedHeight uint64, backlog uint64)         {}
 func (nc *NoopCollector) OnChunksAssignmentDoneAtAssigner(chunks int)                            {}
 func (nc *NoopCollector) OnAssignedChunkProcessedAtAssigner()                                    {}
 func (nc *NoopCollector) OnAssignedChunkReceivedAtFetcher()                                      {}
@@ -90,7 +105,9 @@ func (nc *NoopCollector) OnChunkDataPackRequestSentByFetcher()
 func (nc *NoopCollector) OnChunkDataPackRequestReceivedByRequester()                             {}
 func (nc *NoopCollector) OnChunkDataPackArrivedAtFetcher()                                       {}
 func (nc *NoopCollector) OnChunkDataPackSentToFetcher()                                          {}
+func (nc *NoopCollector) OnConflictingChunkDataPacksAtFetcher()                                  {}
 func (nc *NoopCollector) OnVerifiableChunkSentToVerifier()                                       {}
+func (nc *NoopCollector) OnChunkVerifiedAtVerifier(int, int, uint64, time.Duration)              {}
 func (nc *NoopCollector) OnChunkDataPackResponseReceivedFromNetwork()                            {}
 func (nc *NoopCollector) StartBlockReceivedToExecuted(blockID flow.Identifier)                   {}
 func (nc *NoopCollector) FinishBlockReceivedToExecuted(blockID flow.Identifier)                  {}
@@ -100,6 +117,12 @@ func (nc *NoopCollector) ExecutionStateStorageDiskTotal(bytes int64)
 func (nc *NoopCollector) ExecutionStorageStateCommitment(bytes int64)                            {}
 func (nc *NoopCollector) ExecutionLastExecutedBlockHeight(height uint64)                         {}
 func (nc *NoopCollector) ExecutionTotalExecutedTransactions(numberOfTx int)                      {}
+func (nc *NoopCollector) ExecutionTransactionErrorsPerBlock(errorCode uint16, count int)         {}
+func (nc *NoopCollector) ExecutionTransactionExecuted(time.Duration, uint64, flow.Identifier)    {}
+func (nc *NoopCollector) ForestInterimNodeCount(number uint64)                                   {}
+func (nc *NoopCollector) ForestLeafPayloadSize(bytes uint64)                                     {}
+func (nc *NoopCollector) ForestSharedMemorySavings(bytes uint64)                                 {}
+func (nc *NoopCollector) LatestTrieApproxMemorySize(bytes uint64)                                {}
 func (nc *NoopCollector) ForestApproxMemorySize(bytes uint64)                                    {}
 func (nc *NoopCollector) ForestNumberOfTrees(number uint64)                                      {}
 func (nc *NoopCollector) LatestTrieRegCount(number uint64)                                       {}
@@ -127,5 +150,12 @@ func (nc *NoopCollector) TransactionExecuted(txID flow.Identifier, when time.Tim
 func (nc *NoopCollector) TransactionExpired(txID flow.Identifier)                                {}
 func (nc *NoopCollector) TransactionSubmissionFailed()                                           {}
 func (nc *NoopCollector) ChunkDataPackRequested()                                                {}
+func (nc *NoopCollector) ChunkDataPackCacheLookup(bool, time.Duration)                           {}
 func (nc *NoopCollector) ExecutionSync(syncing bool)                                             {}
 func (nc *NoopCollector) DiskSize(uint64)                                                        {}
+func (nc *NoopCollector) WALSynced(duration time.Duration, records int)                          {}
+func (nc *NoopCollector) ReplicaLag(pending int, lag time.Duration)                              {}
+func (nc *NoopCollector) ReplicaQuery(servedByReplica bool)                                      {}
+func (nc *NoopCollector) TriesPruned(tries int, nodes uint64, bytes uint64)                      {}
+func (nc *NoopCollector) BlockIndexed(height uint64, registers int, duration time.Duration)      {}
+func (nc *NoopCollector) IndexerLag(blocks uint64)                                               {}
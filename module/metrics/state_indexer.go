@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type ExecutionStateIndexerCollector struct {
+	indexedHeight    prometheus.Gauge
+	lag              prometheus.Gauge
+	registersIndexed prometheus.Counter
+	indexDuration    prometheus.Histogram
+}
+
+func NewExecutionStateIndexerCollector() *ExecutionStateIndexerCollector {
+	ic := &ExecutionStateIndexerCollector{
+		indexedHeight: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "indexed_height",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemStateIndexer,
+			Help:      "the height of the latest block added to the register index",
+		}),
+		lag: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "sealed_lag_blocks",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemStateIndexer,
+			Help:      "the number of sealed blocks which are not yet added to the register index",
+		}),
+		registersIndexed: promauto.NewCounter(prometheus.CounterOpts{
+			Name:      "registers_indexed_total",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemStateIndexer,
+			Help:      "the number of register updates added to the register index",
+		}),
+		indexDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+			Name:      "block_index_duration_ms",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemStateIndexer,
+			Help:      "the time it took to add the register updates of a block to the register index",
+			Buckets:   []float64{1, 5, 10, 50, 100, 500, 1000},
+		}),
+	}
+
+	return ic
+}
+
+func (ic *ExecutionStateIndexerCollector) BlockIndexed(height uint64, registers int, duration time.Duration) {
+	ic.indexedHeight.Set(float64(height))
+	ic.registersIndexed.Add(float64(registers))
+	ic.indexDuration.Observe(float64(duration.Milliseconds()))
+}
+
+func (ic *ExecutionStateIndexerCollector) IndexerLag(blocks uint64) {
+	ic.lag.Set(float64(blocks))
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/onflow/flow-go/module"
@@ -13,11 +15,15 @@ type VerificationCollector struct {
 	receivedFinalizedHeightAssigner prometheus.Gauge   // the last finalized height received by assigner engine
 	assignedChunkTotalAssigner      prometheus.Counter // total chunks assigned to this verification node
 	processedChunkTotalAssigner     prometheus.Counter // total chunks sent by assigner engine to chunk consumer (i.e., fetcher input)
+	processedHeightAssigner         prometheus.Gauge   // the last finalized height whose results have been processed for assignment
+	backlogBlocksAssigner           prometheus.Gauge   // number of finalized blocks pending for assignment
 
 	// Fetcher Engine
 	//
 	// total assigned chunks received by fetcher engine from assigner engine (through chunk consumer),
 	receivedAssignedChunkTotalFetcher prometheus.Counter
+	// total chunk data packs received by fetcher engine that conflict with a chunk data pack of the same chunk.
+	conflictingChunkDataPackTotalFetcher prometheus.Counter
 
 	// Requester Engine
 	//
@@ -41,8 +47,12 @@ type VerificationCollector struct {
 	requestedChunkDataPackTotal   prometheus.Counter // total number of chunk data packs requested by match engine
 
 	// Verifier Engine
-	receivedVerifiableChunkTotalVerifier prometheus.Counter // total verifiable chunks received by verifier engine
-	sentResultApprovalTotalVerifier      prometheus.Counter // total result approvals sent by verifier engine
+	receivedVerifiableChunkTotalVerifier prometheus.Counter   // total verifiable chunks received by verifier engine
+	sentResultApprovalTotalVerifier      prometheus.Counter   // total result approvals sent by verifier engine
+	executedTransactionTotalVerifier     prometheus.Counter   // total transactions executed by verifier engine
+	failedTransactionTotalVerifier       prometheus.Counter   // total transactions executed by verifier engine that failed
+	computationUsedTotalVerifier         prometheus.Counter   // total computation used by transactions executed by verifier engine
+	chunkVerificationDurationVerifier    prometheus.Histogram // time spent by verifier engine verifying chunks
 
 }
 
@@ -69,6 +79,20 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		Help:      "total number chunks sent by assigner engine to chunk consumer",
 	})
 
+	processedHeight := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "processed_height",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemAssignerEngine,
+		Help:      "the last finalized height whose results have been processed by assigner engine",
+	})
+
+	backlogBlocks := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "backlog_blocks",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemAssignerEngine,
+		Help:      "number of finalized blocks pending for processing by assigner engine",
+	})
+
 	// Fetcher Engine
 	receivedAssignedChunksTotal := prometheus.NewCounter(prometheus.CounterOpts{
 		Name:      "assigned_chunk_received_total",
@@ -77,6 +101,13 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		Help:      "total number of chunks received by fetcher engine from assigner engine through chunk consumer",
 	})
 
+	conflictingChunkDataPacksTotal := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "conflicting_chunk_data_pack_total",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemFetcherEngine,
+		Help:      "total number of chunk data packs received by fetcher engine that conflict with a chunk data pack of the same chunk from another execution node",
+	})
+
 	// Requester Engine
 	receivedChunkDataPackRequestsTotal := prometheus.NewCounter(prometheus.CounterOpts{
 		Name:      "chunk_data_pack_request_received_total",
@@ -175,15 +206,47 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		Help:      "total number of emitted result approvals by verifier engine",
 	})
 
+	executedTransactionsTotal := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "transaction_executed_total",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "total number of transactions executed by verifier engine while verifying chunks",
+	})
+
+	failedTransactionsTotal := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "transaction_failed_total",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "total number of failed transactions among those executed by verifier engine",
+	})
+
+	computationUsedTotal := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "computation_used_total",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "total computation used by transactions executed by verifier engine",
+	})
+
+	chunkVerificationDuration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:      "chunk_verification_duration_seconds",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "time spent by verifier engine verifying a chunk",
+		Buckets:   []float64{0.05, 0.1, 0.5, 1, 2, 5, 10, 30},
+	})
+
 	// registers all metrics and panics if any fails.
 	registerer.MustRegister(
 		// assigner
 		receivedFinalizedHeight,
 		assignedChunksTotal,
 		sentChunksTotal,
+		processedHeight,
+		backlogBlocks,
 
 		// fetcher engine
 		receivedAssignedChunksTotal,
+		conflictingChunkDataPacksTotal,
 
 		// requester engine
 		receivedChunkDataPackRequestsTotal,
@@ -198,7 +261,11 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		receivedChunkDataPackTotal,
 		requestedChunkDataPackTotal,
 		receivedVerifiableChunksTotal,
-		sentResultApprovalTotal)
+		sentResultApprovalTotal,
+		executedTransactionsTotal,
+		failedTransactionsTotal,
+		computationUsedTotal,
+		chunkVerificationDuration)
 
 	vc := &VerificationCollector{
 		tracer:                               tracer,
@@ -208,6 +275,10 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		sentVerifiableChunksTotal:            sentVerifiableChunksTotal,
 		receivedVerifiableChunkTotalVerifier: receivedVerifiableChunksTotal,
 		sentResultApprovalTotalVerifier:      sentResultApprovalTotal,
+		executedTransactionTotalVerifier:     executedTransactionsTotal,
+		failedTransactionTotalVerifier:       failedTransactionsTotal,
+		computationUsedTotalVerifier:         computationUsedTotal,
+		chunkVerificationDurationVerifier:    chunkVerificationDuration,
 		receivedChunkDataPackTotal:           receivedChunkDataPackTotal,
 		requestedChunkDataPackTotal:          requestedChunkDataPackTotal,
 
@@ -215,9 +286,12 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		receivedFinalizedHeightAssigner: receivedFinalizedHeight,
 		assignedChunkTotalAssigner:      assignedChunksTotal,
 		processedChunkTotalAssigner:     sentChunksTotal,
+		processedHeightAssigner:         processedHeight,
+		backlogBlocksAssigner:           backlogBlocks,
 
 		// fetcher
-		receivedAssignedChunkTotalFetcher: receivedAssignedChunksTotal,
+		receivedAssignedChunkTotalFetcher:    receivedAssignedChunksTotal,
+		conflictingChunkDataPackTotalFetcher: conflictingChunkDataPacksTotal,
 
 		// requester
 		receivedChunkDataPackRequestTotalRequester:     receivedChunkDataPackRequestsTotal,
@@ -291,6 +365,13 @@ func (vc *VerificationCollector) OnFinalizedBlockArrivedAtAssigner(height uint64
 	vc.receivedFinalizedHeightAssigner.Set(float64(height))
 }
 
+// OnBlockConsumerProgress sets the gauges that keep track of the height of the last finalized block whose results
+// have been processed for assignment, and of the number of finalized blocks still pending for assignment.
+func (vc *VerificationCollector) OnBlockConsumerProgress(processedHeight uint64, backlog uint64) {
+	vc.processedHeightAssigner.Set(float64(processedHeight))
+	vc.backlogBlocksAssigner.Set(float64(backlog))
+}
+
 // OnChunksAssignmentDoneAtAssigner increments a counter that keeps track of the total number of assigned chunks to
 // the verification node.
 func (vc *VerificationCollector) OnChunksAssignmentDoneAtAssigner(chunks int) {
@@ -344,7 +425,22 @@ func (vc *VerificationCollector) OnChunkDataPackArrivedAtFetcher() {
 	vc.receivedChunkDataPackTotal.Inc()
 }
 
+// OnConflictingChunkDataPacksAtFetcher increments a counter that keeps track of number of chunk data packs arrived at fetcher engine
+// that conflict with a chunk data pack of the same chunk received from another execution node.
+func (vc *VerificationCollector) OnConflictingChunkDataPacksAtFetcher() {
+	vc.conflictingChunkDataPackTotalFetcher.Inc()
+}
+
 // OnVerifiableChunkSentToVerifier increments a counter that keeps track of number of verifiable chunks fetcher engine sent to verifier engine.
 func (vc *VerificationCollector) OnVerifiableChunkSentToVerifier() {
 	vc.sentVerifiableChunksTotal.Inc()
 }
+
+// OnChunkVerifiedAtVerifier increments the counters that keep track of the transactions executed by verifier engine,
+// of the failed ones among them and of the computation they used, and records the time spent verifying the chunk.
+func (vc *VerificationCollector) OnChunkVerifiedAtVerifier(transactions int, failedTransactions int, computationUsed uint64, duration time.Duration) {
+	vc.executedTransactionTotalVerifier.Add(float64(transactions))
+	vc.failedTransactionTotalVerifier.Add(float64(failedTransactions))
+	vc.computationUsedTotalVerifier.Add(float64(computationUsed))
+	vc.chunkVerificationDurationVerifier.Observe(duration.Seconds())
+}
@@ -36,3 +36,26 @@ func (_m *ChunkAssigner) Assign(result *flow.ExecutionResult, blockID flow.Ident
 
 	return r0, r1
 }
+
+// Explain provides a mock function with given fields: result, blockID
+func (_m *ChunkAssigner) Explain(result *flow.ExecutionResult, blockID flow.Identifier) (*chunks.AssignmentExplanation, error) {
+	ret := _m.Called(result, blockID)
+
+	var r0 *chunks.AssignmentExplanation
+	if rf, ok := ret.Get(0).(func(*flow.ExecutionResult, flow.Identifier) *chunks.AssignmentExplanation); ok {
+		r0 = rf(result, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*chunks.AssignmentExplanation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*flow.ExecutionResult, flow.Identifier) error); ok {
+		r1 = rf(result, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -15,7 +15,7 @@ type ChunkVerifier struct {
 }
 
 // SystemChunkVerify provides a mock function with given fields: ch
-func (_m *ChunkVerifier) SystemChunkVerify(ch *verification.VerifiableChunkData) ([]byte, chunks.ChunkFault, error) {
+func (_m *ChunkVerifier) SystemChunkVerify(ch *verification.VerifiableChunkData) ([]byte, *chunks.ChunkVerificationReport, chunks.ChunkFault, error) {
 	ret := _m.Called(ch)
 
 	var r0 []byte
@@ -27,27 +27,36 @@ func (_m *ChunkVerifier) SystemChunkVerify(ch *verification.VerifiableChunkData)
 		}
 	}
 
-	var r1 chunks.ChunkFault
-	if rf, ok := ret.Get(1).(func(*verification.VerifiableChunkData) chunks.ChunkFault); ok {
+	var r1 *chunks.ChunkVerificationReport
+	if rf, ok := ret.Get(1).(func(*verification.VerifiableChunkData) *chunks.ChunkVerificationReport); ok {
 		r1 = rf(ch)
 	} else {
 		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(chunks.ChunkFault)
+			r1 = ret.Get(1).(*chunks.ChunkVerificationReport)
 		}
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func(*verification.VerifiableChunkData) error); ok {
+	var r2 chunks.ChunkFault
+	if rf, ok := ret.Get(2).(func(*verification.VerifiableChunkData) chunks.ChunkFault); ok {
 		r2 = rf(ch)
 	} else {
-		r2 = ret.Error(2)
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(chunks.ChunkFault)
+		}
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(*verification.VerifiableChunkData) error); ok {
+		r3 = rf(ch)
+	} else {
+		r3 = ret.Error(3)
 	}
 
-	return r0, r1, r2
+	return r0, r1, r2, r3
 }
 
 // Verify provides a mock function with given fields: ch
-func (_m *ChunkVerifier) Verify(ch *verification.VerifiableChunkData) ([]byte, chunks.ChunkFault, error) {
+func (_m *ChunkVerifier) Verify(ch *verification.VerifiableChunkData) ([]byte, *chunks.ChunkVerificationReport, chunks.ChunkFault, error) {
 	ret := _m.Called(ch)
 
 	var r0 []byte
@@ -59,21 +68,30 @@ func (_m *ChunkVerifier) Verify(ch *verification.VerifiableChunkData) ([]byte, c
 		}
 	}
 
-	var r1 chunks.ChunkFault
-	if rf, ok := ret.Get(1).(func(*verification.VerifiableChunkData) chunks.ChunkFault); ok {
+	var r1 *chunks.ChunkVerificationReport
+	if rf, ok := ret.Get(1).(func(*verification.VerifiableChunkData) *chunks.ChunkVerificationReport); ok {
 		r1 = rf(ch)
 	} else {
 		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(chunks.ChunkFault)
+			r1 = ret.Get(1).(*chunks.ChunkVerificationReport)
 		}
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func(*verification.VerifiableChunkData) error); ok {
+	var r2 chunks.ChunkFault
+	if rf, ok := ret.Get(2).(func(*verification.VerifiableChunkData) chunks.ChunkFault); ok {
 		r2 = rf(ch)
 	} else {
-		r2 = ret.Error(2)
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(chunks.ChunkFault)
+		}
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(*verification.VerifiableChunkData) error); ok {
+		r3 = rf(ch)
+	} else {
+		r3 = ret.Error(3)
 	}
 
-	return r0, r1, r2
+	return r0, r1, r2, r3
 }
@@ -14,6 +14,11 @@ type ConsensusMetrics struct {
 	mock.Mock
 }
 
+// ApprovalThresholdDecayed provides a mock function with given fields:
+func (_m *ConsensusMetrics) ApprovalThresholdDecayed() {
+	_m.Called()
+}
+
 // CheckSealingDuration provides a mock function with given fields: duration
 func (_m *ConsensusMetrics) CheckSealingDuration(duration time.Duration) {
 	_m.Called(duration)
@@ -44,6 +49,11 @@ func (_m *ConsensusMetrics) OnReceiptProcessingDuration(duration time.Duration)
 	_m.Called(duration)
 }
 
+// PayloadTruncated provides a mock function with given fields: resource, dropped
+func (_m *ConsensusMetrics) PayloadTruncated(resource string, dropped uint) {
+	_m.Called(resource, dropped)
+}
+
 // StartBlockToSeal provides a mock function with given fields: blockID
 func (_m *ConsensusMetrics) StartBlockToSeal(blockID flow.Identifier) {
 	_m.Called(blockID)
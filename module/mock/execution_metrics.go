@@ -14,6 +14,11 @@ type ExecutionMetrics struct {
 	mock.Mock
 }
 
+// ChunkDataPackCacheLookup provides a mock function with given fields: hit, duration
+func (_m *ExecutionMetrics) ChunkDataPackCacheLookup(hit bool, duration time.Duration) {
+	_m.Called(hit, duration)
+}
+
 // ChunkDataPackRequested provides a mock function with given fields:
 func (_m *ExecutionMetrics) ChunkDataPackRequested() {
 	_m.Called()
@@ -64,6 +69,16 @@ func (_m *ExecutionMetrics) ExecutionTotalExecutedTransactions(numExecuted int)
 	_m.Called(numExecuted)
 }
 
+// ExecutionTransactionErrorsPerBlock provides a mock function with given fields: errorCode, count
+func (_m *ExecutionMetrics) ExecutionTransactionErrorsPerBlock(errorCode uint16, count int) {
+	_m.Called(errorCode, count)
+}
+
+// ExecutionTransactionExecuted provides a mock function with given fields: dur, compUsed, systemScriptHash
+func (_m *ExecutionMetrics) ExecutionTransactionExecuted(dur time.Duration, compUsed uint64, systemScriptHash flow.Identifier) {
+	_m.Called(dur, compUsed, systemScriptHash)
+}
+
 // FinishBlockReceivedToExecuted provides a mock function with given fields: blockID
 func (_m *ExecutionMetrics) FinishBlockReceivedToExecuted(blockID flow.Identifier) {
 	_m.Called(blockID)
@@ -74,11 +89,31 @@ func (_m *ExecutionMetrics) ForestApproxMemorySize(bytes uint64) {
 	_m.Called(bytes)
 }
 
+// ForestInterimNodeCount provides a mock function with given fields: number
+func (_m *ExecutionMetrics) ForestInterimNodeCount(number uint64) {
+	_m.Called(number)
+}
+
+// ForestLeafPayloadSize provides a mock function with given fields: bytes
+func (_m *ExecutionMetrics) ForestLeafPayloadSize(bytes uint64) {
+	_m.Called(bytes)
+}
+
 // ForestNumberOfTrees provides a mock function with given fields: number
 func (_m *ExecutionMetrics) ForestNumberOfTrees(number uint64) {
 	_m.Called(number)
 }
 
+// ForestSharedMemorySavings provides a mock function with given fields: bytes
+func (_m *ExecutionMetrics) ForestSharedMemorySavings(bytes uint64) {
+	_m.Called(bytes)
+}
+
+// LatestTrieApproxMemorySize provides a mock function with given fields: bytes
+func (_m *ExecutionMetrics) LatestTrieApproxMemorySize(bytes uint64) {
+	_m.Called(bytes)
+}
+
 // LatestTrieMaxDepth provides a mock function with given fields: number
 func (_m *ExecutionMetrics) LatestTrieMaxDepth(number uint64) {
 	_m.Called(number)
@@ -124,6 +159,16 @@ func (_m *ExecutionMetrics) ReadValuesSize(byte uint64) {
 	_m.Called(byte)
 }
 
+// ReplicaLag provides a mock function with given fields: pending, lag
+func (_m *ExecutionMetrics) ReplicaLag(pending int, lag time.Duration) {
+	_m.Called(pending, lag)
+}
+
+// ReplicaQuery provides a mock function with given fields: servedByReplica
+func (_m *ExecutionMetrics) ReplicaQuery(servedByReplica bool) {
+	_m.Called(servedByReplica)
+}
+
 // StartBlockReceivedToExecuted provides a mock function with given fields: blockID
 func (_m *ExecutionMetrics) StartBlockReceivedToExecuted(blockID flow.Identifier) {
 	_m.Called(blockID)
@@ -144,6 +189,11 @@ func (_m *ExecutionMetrics) TransactionParsed(dur time.Duration) {
 	_m.Called(dur)
 }
 
+// TriesPruned provides a mock function with given fields: tries, nodes, bytes
+func (_m *ExecutionMetrics) TriesPruned(tries int, nodes uint64, bytes uint64) {
+	_m.Called(tries, nodes, bytes)
+}
+
 // UpdateCount provides a mock function with given fields:
 func (_m *ExecutionMetrics) UpdateCount() {
 	_m.Called()
@@ -168,3 +218,8 @@ func (_m *ExecutionMetrics) UpdateValuesNumber(number uint64) {
 func (_m *ExecutionMetrics) UpdateValuesSize(byte uint64) {
 	_m.Called(byte)
 }
+
+// WALSynced provides a mock function with given fields: duration, records
+func (_m *ExecutionMetrics) WALSynced(duration time.Duration, records int) {
+	_m.Called(duration, records)
+}
@@ -28,6 +28,11 @@ func (_m *HotstuffMetrics) CountTimeout() {
 	_m.Called()
 }
 
+// CountTimeoutIncrease provides a mock function with given fields:
+func (_m *HotstuffMetrics) CountTimeoutIncrease() {
+	_m.Called()
+}
+
 // HotStuffBusyDuration provides a mock function with given fields: duration, event
 func (_m *HotstuffMetrics) HotStuffBusyDuration(duration time.Duration, event string) {
 	_m.Called(duration, event)
@@ -48,6 +53,11 @@ func (_m *HotstuffMetrics) PayloadProductionDuration(duration time.Duration) {
 	_m.Called(duration)
 }
 
+// ReplicaTimeoutDuration provides a mock function with given fields: duration
+func (_m *HotstuffMetrics) ReplicaTimeoutDuration(duration time.Duration) {
+	_m.Called(duration)
+}
+
 // SetCurView provides a mock function with given fields: view
 func (_m *HotstuffMetrics) SetCurView(view uint64) {
 	_m.Called(view)
@@ -18,11 +18,31 @@ func (_m *LedgerMetrics) ForestApproxMemorySize(bytes uint64) {
 	_m.Called(bytes)
 }
 
+// ForestInterimNodeCount provides a mock function with given fields: number
+func (_m *LedgerMetrics) ForestInterimNodeCount(number uint64) {
+	_m.Called(number)
+}
+
+// ForestLeafPayloadSize provides a mock function with given fields: bytes
+func (_m *LedgerMetrics) ForestLeafPayloadSize(bytes uint64) {
+	_m.Called(bytes)
+}
+
 // ForestNumberOfTrees provides a mock function with given fields: number
 func (_m *LedgerMetrics) ForestNumberOfTrees(number uint64) {
 	_m.Called(number)
 }
 
+// ForestSharedMemorySavings provides a mock function with given fields: bytes
+func (_m *LedgerMetrics) ForestSharedMemorySavings(bytes uint64) {
+	_m.Called(bytes)
+}
+
+// LatestTrieApproxMemorySize provides a mock function with given fields: bytes
+func (_m *LedgerMetrics) LatestTrieApproxMemorySize(bytes uint64) {
+	_m.Called(bytes)
+}
+
 // LatestTrieMaxDepth provides a mock function with given fields: number
 func (_m *LedgerMetrics) LatestTrieMaxDepth(number uint64) {
 	_m.Called(number)
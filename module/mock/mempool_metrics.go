@@ -12,6 +12,11 @@ type MempoolMetrics struct {
 	mock.Mock
 }
 
+// MempoolEjection provides a mock function with given fields: resource, reason
+func (_m *MempoolMetrics) MempoolEjection(resource string, reason string) {
+	_m.Called(resource, reason)
+}
+
 // MempoolEntries provides a mock function with given fields: resource, entries
 func (_m *MempoolMetrics) MempoolEntries(resource string, entries uint) {
 	_m.Called(resource, entries)
@@ -13,6 +13,11 @@ type NetworkMetrics struct {
 	mock.Mock
 }
 
+// DNSLookupDuration provides a mock function with given fields: duration
+func (_m *NetworkMetrics) DNSLookupDuration(duration time.Duration) {
+	_m.Called(duration)
+}
+
 // InboundConnections provides a mock function with given fields: connectionCount
 func (_m *NetworkMetrics) InboundConnections(connectionCount uint) {
 	_m.Called(connectionCount)
@@ -48,6 +53,21 @@ func (_m *NetworkMetrics) NetworkMessageSent(sizeBytes int, topic string, messag
 	_m.Called(sizeBytes, topic, messageType)
 }
 
+// OnDNSCacheHit provides a mock function with given fields:
+func (_m *NetworkMetrics) OnDNSCacheHit() {
+	_m.Called()
+}
+
+// OnDNSCacheMiss provides a mock function with given fields:
+func (_m *NetworkMetrics) OnDNSCacheMiss() {
+	_m.Called()
+}
+
+// OnDNSLookupFailed provides a mock function with given fields:
+func (_m *NetworkMetrics) OnDNSLookupFailed() {
+	_m.Called()
+}
+
 // OutboundConnections provides a mock function with given fields: connectionCount
 func (_m *NetworkMetrics) OutboundConnections(connectionCount uint) {
 	_m.Called(connectionCount)
@@ -57,3 +77,18 @@ func (_m *NetworkMetrics) OutboundConnections(connectionCount uint) {
 func (_m *NetworkMetrics) QueueDuration(duration time.Duration, priority int) {
 	_m.Called(duration, priority)
 }
+
+// UnicastStreamCreated provides a mock function with given fields:
+func (_m *NetworkMetrics) UnicastStreamCreated() {
+	_m.Called()
+}
+
+// UnicastStreamReused provides a mock function with given fields:
+func (_m *NetworkMetrics) UnicastStreamReused() {
+	_m.Called()
+}
+
+// UnicastStreamsIdle provides a mock function with given fields: streamCount
+func (_m *NetworkMetrics) UnicastStreamsIdle(streamCount uint) {
+	_m.Called(streamCount)
+}
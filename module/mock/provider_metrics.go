@@ -2,13 +2,22 @@
 
 package mock
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // ProviderMetrics is an autogenerated mock type for the ProviderMetrics type
 type ProviderMetrics struct {
 	mock.Mock
 }
 
+// ChunkDataPackCacheLookup provides a mock function with given fields: hit, duration
+func (_m *ProviderMetrics) ChunkDataPackCacheLookup(hit bool, duration time.Duration) {
+	_m.Called(hit, duration)
+}
+
 // ChunkDataPackRequested provides a mock function with given fields:
 func (_m *ProviderMetrics) ChunkDataPackRequested() {
 	_m.Called()
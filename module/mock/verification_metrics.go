@@ -2,7 +2,11 @@
 
 package mock
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // VerificationMetrics is an autogenerated mock type for the VerificationMetrics type
 type VerificationMetrics struct {
@@ -19,6 +23,11 @@ func (_m *VerificationMetrics) OnAssignedChunkReceivedAtFetcher() {
 	_m.Called()
 }
 
+// OnBlockConsumerProgress provides a mock function with given fields: processedHeight, backlog
+func (_m *VerificationMetrics) OnBlockConsumerProgress(processedHeight uint64, backlog uint64) {
+	_m.Called(processedHeight, backlog)
+}
+
 // OnChunkDataPackArrivedAtFetcher provides a mock function with given fields:
 func (_m *VerificationMetrics) OnChunkDataPackArrivedAtFetcher() {
 	_m.Called()
@@ -59,11 +68,21 @@ func (_m *VerificationMetrics) OnChunkDataPackSentToFetcher() {
 	_m.Called()
 }
 
+// OnChunkVerifiedAtVerifier provides a mock function with given fields: transactions, failedTransactions, computationUsed, duration
+func (_m *VerificationMetrics) OnChunkVerifiedAtVerifier(transactions int, failedTransactions int, computationUsed uint64, duration time.Duration) {
+	_m.Called(transactions, failedTransactions, computationUsed, duration)
+}
+
 // OnChunksAssignmentDoneAtAssigner provides a mock function with given fields: chunks
 func (_m *VerificationMetrics) OnChunksAssignmentDoneAtAssigner(chunks int) {
 	_m.Called(chunks)
 }
 
+// OnConflictingChunkDataPacksAtFetcher provides a mock function with given fields:
+func (_m *VerificationMetrics) OnConflictingChunkDataPacksAtFetcher() {
+	_m.Called()
+}
+
 // OnExecutionReceiptReceived provides a mock function with given fields:
 func (_m *VerificationMetrics) OnExecutionReceiptReceived() {
 	_m.Called()
@@ -2,7 +2,11 @@
 
 package mock
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // WALMetrics is an autogenerated mock type for the WALMetrics type
 type WALMetrics struct {
@@ -13,3 +17,8 @@ type WALMetrics struct {
 func (_m *WALMetrics) DiskSize(_a0 uint64) {
 	_m.Called(_a0)
 }
+
+// WALSynced provides a mock function with given fields: duration, records
+func (_m *WALMetrics) WALSynced(duration time.Duration, records int) {
+	_m.Called(duration, records)
+}
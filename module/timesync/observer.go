@@ -0,0 +1,102 @@
+// Package timesync estimates the skew between the clock of the local node and the clocks of the
+// other nodes of the network, from the timestamps the other nodes put into their messages.
+package timesync
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// DefaultMaxAge is the default duration after which the skew observed for a node is no
+// longer taken into account for the estimate.
+const DefaultMaxAge = 10 * time.Minute
+
+// smoothing is the weight of a new observation in the exponential moving average of the
+// skew of a node, which smoothes out the variance of network latencies.
+const smoothing = 0.2
+
+// nodeSkew is the smoothed skew of the clock of a node, and when it was last observed.
+type nodeSkew struct {
+	skew     time.Duration
+	observed time.Time
+}
+
+// Observer estimates the skew of the clocks of other nodes relative to the local clock. The
+// skew of a node is the difference between a timestamp it created and the local time when
+// the timestamp was received, which includes the latency of the message. Hence, the skew of
+// a node with a synchronized clock is slightly negative. The estimated skew is the median of
+// the smoothed skews of all nodes observed recently, so that a few nodes with wrong clocks
+// don't distort it. Observer is safe for concurrent use.
+type Observer struct {
+	sync.Mutex
+	metrics module.ClockSkewMetrics
+	maxAge  time.Duration
+	now     func() time.Time
+	skews   map[flow.Identifier]*nodeSkew
+}
+
+// NewObserver creates a new observer, which forgets the skews of nodes not observed for
+// longer than the given maximum age.
+func NewObserver(metrics module.ClockSkewMetrics, maxAge time.Duration) *Observer {
+	return &Observer{
+		metrics: metrics,
+		maxAge:  maxAge,
+		now:     time.Now,
+		skews:   make(map[flow.Identifier]*nodeSkew),
+	}
+}
+
+// Observe records a timestamp created by the node with the given ID, such as the timestamp of
+// a block proposed by the node, and updates the estimated skew.
+func (o *Observer) Observe(nodeID flow.Identifier, timestamp time.Time) {
+	o.Lock()
+	defer o.Unlock()
+
+	now := o.now()
+	skew := timestamp.Sub(now)
+	o.metrics.ClockSkewObserved(skew)
+
+	previous, ok := o.skews[nodeID]
+	if ok && now.Sub(previous.observed) <= o.maxAge {
+		skew = previous.skew + time.Duration(smoothing*float64(skew-previous.skew))
+	}
+	o.skews[nodeID] = &nodeSkew{skew: skew, observed: now}
+
+	o.metrics.ClockSkewEstimated(o.estimate(now))
+}
+
+// Skew returns the estimated skew of the clocks of the other nodes relative to the local clock,
+// which is positive if the other nodes are ahead of the local clock, and zero if no node was
+// observed recently.
+func (o *Observer) Skew() time.Duration {
+	o.Lock()
+	defer o.Unlock()
+
+	return o.estimate(o.now())
+}
+
+// estimate returns the median of the skews observed recently, and forgets the others.
+func (o *Observer) estimate(now time.Time) time.Duration {
+	skews := make([]time.Duration, 0, len(o.skews))
+	for nodeID, observed := range o.skews {
+		if now.Sub(observed.observed) > o.maxAge {
+			delete(o.skews, nodeID)
+			continue
+		}
+		skews = append(skews, observed.skew)
+	}
+	if len(skews) == 0 {
+		return 0
+	}
+
+	sort.Slice(skews, func(i, j int) bool { return skews[i] < skews[j] })
+	middle := len(skews) / 2
+	if len(skews)%2 == 0 {
+		return (skews[middle-1] + skews[middle]) / 2
+	}
+	return skews[middle]
+}
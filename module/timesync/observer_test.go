@@ -0,0 +1,58 @@
+package timesync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// newTestObserver creates an observer whose clock is controlled by the returned pointer.
+func newTestObserver() (*Observer, *time.Time) {
+	now := time.Now()
+	observer := NewObserver(metrics.NewNoopCollector(), time.Minute)
+	observer.now = func() time.Time { return now }
+	return observer, &now
+}
+
+// TestObserver_Median verifies that the estimated skew is the median of the skews of the
+// observed nodes, which isn't distorted by a single node with a wrong clock.
+func TestObserver_Median(t *testing.T) {
+	observer, now := newTestObserver()
+	assert.Equal(t, time.Duration(0), observer.Skew())
+
+	observer.Observe(unittest.IdentifierFixture(), now.Add(time.Second))
+	observer.Observe(unittest.IdentifierFixture(), now.Add(2*time.Second))
+	observer.Observe(unittest.IdentifierFixture(), now.Add(time.Hour))
+	assert.Equal(t, 2*time.Second, observer.Skew())
+
+	observer.Observe(unittest.IdentifierFixture(), now.Add(-time.Hour))
+	assert.Equal(t, 1500*time.Millisecond, observer.Skew())
+}
+
+// TestObserver_Smoothing verifies that repeated observations of the same node are smoothed.
+func TestObserver_Smoothing(t *testing.T) {
+	observer, now := newTestObserver()
+	nodeID := unittest.IdentifierFixture()
+
+	observer.Observe(nodeID, now.Add(time.Second))
+	observer.Observe(nodeID, now.Add(6*time.Second))
+	assert.Equal(t, 2*time.Second, observer.Skew())
+}
+
+// TestObserver_MaxAge verifies that the skews of nodes not observed recently are forgotten.
+func TestObserver_MaxAge(t *testing.T) {
+	observer, now := newTestObserver()
+	stale := unittest.IdentifierFixture()
+	observer.Observe(stale, now.Add(time.Hour))
+
+	*now = now.Add(2 * time.Minute)
+	assert.Equal(t, time.Duration(0), observer.Skew())
+
+	// an observation after the maximum age isn't smoothed with the stale one
+	observer.Observe(stale, now.Add(time.Second))
+	assert.Equal(t, time.Second, observer.Skew())
+}
@@ -165,6 +165,7 @@ const (
 	FVMDeductTransactionFees         SpanName = "fvm.deductTransactionFees"
 	FVMInvokeContractFunction        SpanName = "fvm.invokeContractFunction"
 	FVMFrozenAccountCheckTransaction SpanName = "fvm.frozenAccountCheckTransaction"
+	FVMSizeLimitCheckTransaction     SpanName = "fvm.sizeLimitCheckTransaction"
 
 	FVMEnvHash                      SpanName = "fvm.env.Hash"
 	FVMEnvValueExists               SpanName = "fvm.env.valueExists"
@@ -189,6 +190,9 @@ const (
 	FVMEnvAddAccountKey             SpanName = "fvm.env.addAccountKey"
 	FVMEnvGetAccountKey             SpanName = "fvm.env.getAccountKey"
 	FVMEnvRemoveAccountKey          SpanName = "fvm.env.removeAccountKey"
+	FVMEnvGetAccountKeyMetadata     SpanName = "fvm.env.getAccountKeyMetadata"
+	FVMEnvSetAccountKeyMetadata     SpanName = "fvm.env.setAccountKeyMetadata"
+	FVMEnvGetActiveAccountKeyCount  SpanName = "fvm.env.getActiveAccountKeyCount"
 	FVMEnvUpdateAccountContractCode SpanName = "fvm.env.updateAccountContractCode"
 	FVMEnvGetAccountContractCode    SpanName = "fvm.env.getAccountContractCode"
 	FVMEnvRemoveAccountContractCode SpanName = "fvm.env.removeAccountContractCode"
@@ -208,4 +212,8 @@ const (
 	EXEInterpretDurationTag     = "runtime.interpretTransactionDuration"
 	EXEValueEncodingDurationTag = "runtime.encodingValueDuration"
 	EXEValueDecodingDurationTag = "runtime.decodingValueDuration"
+
+	// TransactionIDTag tags the spans of the processing of a transaction with the ID of the
+	// transaction, to correlate them across engines.
+	TransactionIDTag = "transaction.ID"
 )
@@ -0,0 +1,282 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/rs/zerolog"
+	"github.com/uber/jaeger-client-go"
+)
+
+const (
+	otlpTracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	otlpEndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+	otlpQueueSize     = 10000
+	otlpBatchSize     = 512
+	otlpFlushInterval = 5 * time.Second
+	otlpTimeout       = 10 * time.Second
+
+	otlpSpanKindInternal = 1
+)
+
+// otlpEndpoint returns the URL to export traces to with OTLP, configured with the standard
+// OpenTelemetry environment variables, or the empty string if OTLP export is not configured.
+func otlpEndpoint() string {
+	endpoint := os.Getenv(otlpTracesEndpointEnv)
+	if endpoint != "" {
+		return endpoint
+	}
+	endpoint = os.Getenv(otlpEndpointEnv)
+	if endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// OTLPReporter reports finished spans to an OpenTelemetry collector, with the OTLP/HTTP protocol in
+// its JSON encoding. Spans are exported in batches by a background routine. They are dropped when
+// the queue is full, so that tracing never blocks the traced code.
+type OTLPReporter struct {
+	log      zerolog.Logger
+	endpoint string
+	resource otlpResource
+	client   *http.Client
+	spans    chan otlpSpan
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewOTLPReporter creates a reporter exporting the spans of the given service to the given URL of
+// an OpenTelemetry collector, usually ending with /v1/traces.
+func NewOTLPReporter(log zerolog.Logger, endpoint string, serviceName string) *OTLPReporter {
+	r := &OTLPReporter{
+		log:      log.With().Str("component", "otlp_reporter").Logger(),
+		endpoint: endpoint,
+		resource: otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", serviceName)}},
+		client:   &http.Client{Timeout: otlpTimeout},
+		spans:    make(chan otlpSpan, otlpQueueSize),
+		quit:     make(chan struct{}),
+	}
+
+	r.wg.Add(1)
+	go r.run()
+
+	return r
+}
+
+// Report queues the finished span for export.
+func (r *OTLPReporter) Report(span *jaeger.Span) {
+	select {
+	case r.spans <- toOTLPSpan(span):
+	default:
+		r.log.Debug().Str("span", span.OperationName()).Msg("span queue full, dropping span")
+	}
+}
+
+// Close exports the queued spans and stops the reporter.
+func (r *OTLPReporter) Close() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *OTLPReporter) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]otlpSpan, 0, otlpBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := r.export(batch)
+		if err != nil {
+			r.log.Warn().Err(err).Int("spans", len(batch)).Msg("could not export spans")
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case span := <-r.spans:
+			batch = append(batch, span)
+			if len(batch) >= otlpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-r.quit:
+			for {
+				select {
+				case span := <-r.spans:
+					batch = append(batch, span)
+					if len(batch) >= otlpBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *OTLPReporter) export(spans []otlpSpan) error {
+	req := otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: r.resource,
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/onflow/flow-go/module/trace"},
+				Spans: spans,
+			}},
+		}},
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("could not encode spans: %w", err)
+	}
+
+	resp, err := r.client.Post(r.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not send spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector answered with status %s", resp.Status)
+	}
+	return nil
+}
+
+// toOTLPSpan converts a finished span of the jaeger tracer to the OTLP representation of spans. The
+// tags of the span become attributes, and its logs become events.
+func toOTLPSpan(span *jaeger.Span) otlpSpan {
+	sc := span.SpanContext()
+	start := span.StartTime()
+
+	s := otlpSpan{
+		TraceID:           otlpTraceID(sc.TraceID()),
+		SpanID:            otlpSpanID(sc.SpanID()),
+		Name:              span.OperationName(),
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: unixNano(start),
+		EndTimeUnixNano:   unixNano(start.Add(span.Duration())),
+	}
+	if sc.ParentID() != 0 {
+		s.ParentSpanID = otlpSpanID(sc.ParentID())
+	}
+
+	for key, value := range span.Tags() {
+		s.Attributes = append(s.Attributes, stringAttribute(key, fmt.Sprint(value)))
+	}
+	for _, record := range span.Logs() {
+		event := otlpEvent{
+			TimeUnixNano: unixNano(record.Timestamp),
+			Name:         "log",
+		}
+		for _, field := range record.Fields {
+			event.Attributes = append(event.Attributes, stringAttribute(field.Key(), fmt.Sprint(field.Value())))
+		}
+		s.Events = append(s.Events, event)
+	}
+	for _, ref := range span.References() {
+		// the parent is referenced by the parent span ID already
+		ctx, ok := ref.ReferencedContext.(jaeger.SpanContext)
+		if !ok || (ref.Type == opentracing.ChildOfRef && ctx.SpanID() == sc.ParentID()) {
+			continue
+		}
+		s.Links = append(s.Links, otlpLink{
+			TraceID: otlpTraceID(ctx.TraceID()),
+			SpanID:  otlpSpanID(ctx.SpanID()),
+		})
+	}
+
+	return s
+}
+
+func otlpTraceID(id jaeger.TraceID) string {
+	return fmt.Sprintf("%016x%016x", id.High, id.Low)
+}
+
+func otlpSpanID(id jaeger.SpanID) string {
+	return fmt.Sprintf("%016x", uint64(id))
+}
+
+// unixNano encodes a time as nanoseconds since the epoch, which are encoded as strings by the JSON
+// encoding of OTLP, as all 64 bits integers.
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func stringAttribute(key string, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
+
+// The following types are the JSON encoding of the messages of the OTLP trace service, limited to
+// the fields set by the reporter.
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Events            []otlpEvent     `json:"events,omitempty"`
+	Links             []otlpLink      `json:"links,omitempty"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	Name         string          `json:"name"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpLink struct {
+	TraceID string `json:"traceId"`
+	SpanID  string `json:"spanId"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
@@ -0,0 +1,56 @@
+package trace
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-client-go"
+)
+
+func TestOTLPReporter(t *testing.T) {
+	requests := make(chan otlpExportRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		var req otlpExportRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+	}))
+	defer server.Close()
+
+	reporter := NewOTLPReporter(zerolog.Nop(), server.URL+"/v1/traces", "execution")
+	tracer, closer := jaeger.NewTracer("execution", jaeger.NewConstSampler(true), reporter)
+
+	txID := "0f5d1e8b0a3c1a4d6a3f2b7c9e1d0c4b5a6f7e8d9c0b1a2f3e4d5c6b7a8f9e0d"
+	parent := tracer.StartSpan(string(EXEComputeTransaction), opentracing.Tag{Key: TransactionIDTag, Value: txID})
+	child := tracer.StartSpan(string(EXEMergeTransactionView), opentracing.ChildOf(parent.Context()))
+	child.Finish()
+	parent.Finish()
+
+	// closing the tracer exports the queued spans
+	require.NoError(t, closer.Close())
+
+	req := <-requests
+	require.Len(t, req.ResourceSpans, 1)
+	assert.Equal(t, []otlpAttribute{stringAttribute("service.name", "execution")}, req.ResourceSpans[0].Resource.Attributes)
+	require.Len(t, req.ResourceSpans[0].ScopeSpans, 1)
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	childSpan, parentSpan := spans[0], spans[1]
+	assert.Equal(t, string(EXEComputeTransaction), parentSpan.Name)
+	assert.Contains(t, parentSpan.Attributes, stringAttribute(TransactionIDTag, txID))
+	assert.Empty(t, parentSpan.ParentSpanID)
+	assert.Len(t, parentSpan.TraceID, 32)
+	assert.Len(t, parentSpan.SpanID, 16)
+
+	assert.Equal(t, string(EXEMergeTransactionView), childSpan.Name)
+	assert.Equal(t, parentSpan.TraceID, childSpan.TraceID)
+	assert.Equal(t, parentSpan.SpanID, childSpan.ParentSpanID)
+	assert.Empty(t, childSpan.Links)
+}
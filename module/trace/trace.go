@@ -39,7 +39,10 @@ func (t traceLogger) Infof(msg string, args ...interface{}) {
 	t.Debug().Msgf(msg, args...)
 }
 
-// NewTracer creates a new tracer.
+// NewTracer creates a new tracer. The tracer is configured with the environment variables of the
+// jaeger client. Spans are exported to an OpenTelemetry collector with OTLP instead of the jaeger
+// agent, if the OTLP endpoint is set with the OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or
+// OTEL_EXPORTER_OTLP_ENDPOINT environment variables.
 func NewTracer(log zerolog.Logger, serviceName string) (*OpenTracer, error) {
 	cfg, err := config.FromEnv()
 	if err != nil {
@@ -50,7 +53,14 @@ func NewTracer(log zerolog.Logger, serviceName string) (*OpenTracer, error) {
 		cfg.ServiceName = serviceName
 	}
 
-	tracer, closer, err := cfg.NewTracer(config.Logger(traceLogger{log}))
+	opts := []config.Option{config.Logger(traceLogger{log})}
+	endpoint := otlpEndpoint()
+	if endpoint != "" {
+		log.Info().Str("endpoint", endpoint).Msg("exporting traces with OTLP")
+		opts = append(opts, config.Reporter(NewOTLPReporter(log, endpoint, cfg.ServiceName)))
+	}
+
+	tracer, closer, err := cfg.NewTracer(opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -21,16 +21,19 @@ type receiptValidator struct {
 	index    storage.Index
 	results  storage.ExecutionResults
 	verifier module.Verifier
+	// validatedResults caches the IDs of results which passed validation
+	validatedResults *ValidatedResults
 }
 
-func NewReceiptValidator(state protocol.State, headers storage.Headers, index storage.Index, results storage.ExecutionResults, seals storage.Seals, verifier module.Verifier) *receiptValidator {
+func NewReceiptValidator(state protocol.State, headers storage.Headers, index storage.Index, results storage.ExecutionResults, seals storage.Seals, verifier module.Verifier, validatedResults *ValidatedResults) *receiptValidator {
 	rv := &receiptValidator{
-		state:    state,
-		headers:  headers,
-		index:    index,
-		results:  results,
-		verifier: verifier,
-		seals:    seals,
+		state:            state,
+		headers:          headers,
+		index:            index,
+		results:          results,
+		verifier:         verifier,
+		seals:            seals,
+		validatedResults: validatedResults,
 	}
 
 	return rv
@@ -152,20 +155,23 @@ func (v *receiptValidator) resultChainCheck(result *flow.ExecutionResult, prevRe
 // * engine.UnverifiableInputError
 //   if receipt's parent result is unknown
 func (v *receiptValidator) Validate(receipt *flow.ExecutionReceipt) error {
-	// TODO: this can be optimized by checking if result was already stored and validated.
-	// This needs to be addressed later since many tests depend on this behavior.
-	prevResult, err := v.fetchResult(receipt.ExecutionResult.PreviousResultID)
-	if err != nil {
-		return fmt.Errorf("error fetching parent result of receipt %v: %w", receipt.ID(), err)
-	}
+	// the result only needs to be validated for the first receipt committing to it
+	resultID := receipt.ExecutionResult.ID()
+	if !v.validatedResults.Has(resultID) {
+		prevResult, err := v.fetchResult(receipt.ExecutionResult.PreviousResultID)
+		if err != nil {
+			return fmt.Errorf("error fetching parent result of receipt %v: %w", receipt.ID(), err)
+		}
 
-	// first validate result to avoid signature check in in `validateReceipt` in case result is invalid.
-	err = v.validateResult(&receipt.ExecutionResult, prevResult)
-	if err != nil {
-		return fmt.Errorf("could not validate single result %v at index: %w", receipt.ExecutionResult.ID(), err)
+		// first validate result to avoid signature check in in `validateReceipt` in case result is invalid.
+		err = v.validateResult(&receipt.ExecutionResult, prevResult)
+		if err != nil {
+			return fmt.Errorf("could not validate single result %v at index: %w", resultID, err)
+		}
+		v.validatedResults.Add(resultID)
 	}
 
-	err = v.validateReceipt(receipt.Meta(), receipt.ExecutionResult.BlockID)
+	err := v.validateReceipt(receipt.Meta(), receipt.ExecutionResult.BlockID)
 	if err != nil {
 		// It's very important that we fail the whole validation if one of the receipts is invalid.
 		// It allows us to make assumptions as stated in previous comment.
@@ -284,10 +290,13 @@ func (v *receiptValidator) ValidatePayload(candidate *flow.Block) error {
 			return engine.NewInvalidInputErrorf("results %v at index %d is for block not on fork (%x)", resultID, i, result.BlockID)
 		}
 
-		// validate result
-		err = v.validateResult(result, prevResult)
-		if err != nil {
-			return fmt.Errorf("could not validate result %v at index %d: %w", resultID, i, err)
+		// validate result, unless it was validated before
+		if !v.validatedResults.Has(resultID) {
+			err = v.validateResult(result, prevResult)
+			if err != nil {
+				return fmt.Errorf("could not validate result %v at index %d: %w", resultID, i, err)
+			}
+			v.validatedResults.Add(resultID)
 		}
 		executionTree[resultID] = result
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
 	mock2 "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -28,7 +29,9 @@ type ReceiptValidationSuite struct {
 func (s *ReceiptValidationSuite) SetupTest() {
 	s.SetupChain()
 	s.verifier = &mock2.Verifier{}
-	s.receiptValidator = NewReceiptValidator(s.State, s.HeadersDB, s.IndexDB, s.ResultsDB, s.SealsDB, s.verifier)
+	validatedResults, err := NewValidatedResults(metrics.NewNoopCollector(), DefaultValidatedResultsCacheSize)
+	s.Require().NoError(err)
+	s.receiptValidator = NewReceiptValidator(s.State, s.HeadersDB, s.IndexDB, s.ResultsDB, s.SealsDB, s.verifier, validatedResults)
 }
 
 // TestReceiptValid try submitting valid receipt
@@ -0,0 +1,55 @@
+package validation
+
+import (
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+// DefaultValidatedResultsCacheSize is the default number of validated result IDs cached.
+const DefaultValidatedResultsCacheSize = 1000
+
+// ValidatedResults is an LRU cache of the IDs of execution results which passed validation, so
+// that the receipts committing to an already validated result don't pay the cost of validating
+// the result again. The validity of a result only depends on its content, which is committed to
+// by its ID. It is concurrency safe, and shared between the receipt validator and sealing.
+type ValidatedResults struct {
+	cache   *lru.Cache
+	metrics module.CacheMetrics
+}
+
+func NewValidatedResults(metrics module.CacheMetrics, limit uint) (*ValidatedResults, error) {
+	cache, err := lru.New(int(limit))
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatedResults{
+		cache:   cache,
+		metrics: metrics,
+	}, nil
+}
+
+// Has returns whether the result with the given ID was validated.
+func (r *ValidatedResults) Has(resultID flow.Identifier) bool {
+	_, ok := r.cache.Get(resultID)
+	if ok {
+		r.metrics.CacheHit(metrics.ResourceValidatedResult)
+	} else {
+		r.metrics.CacheMiss(metrics.ResourceValidatedResult)
+	}
+	return ok
+}
+
+// Add records that the result with the given ID passed validation.
+func (r *ValidatedResults) Add(resultID flow.Identifier) {
+	r.cache.Add(resultID, struct{}{})
+	r.metrics.CacheEntries(metrics.ResourceValidatedResult, uint(r.cache.Len()))
+}
+
+// Invalidate drops all cached result IDs.
+func (r *ValidatedResults) Invalidate() {
+	r.cache.Purge()
+	r.metrics.CacheEntries(metrics.ResourceValidatedResult, 0)
+}
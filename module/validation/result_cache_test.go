@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestValidatedResults(t *testing.T) {
+	validatedResults, err := NewValidatedResults(metrics.NewNoopCollector(), 2)
+	require.NoError(t, err)
+
+	resultIDs := unittest.IdentifierListFixture(3)
+	assert.False(t, validatedResults.Has(resultIDs[0]))
+
+	validatedResults.Add(resultIDs[0])
+	validatedResults.Add(resultIDs[1])
+	assert.True(t, validatedResults.Has(resultIDs[0]))
+	assert.True(t, validatedResults.Has(resultIDs[1]))
+
+	// the least recently used result is evicted
+	validatedResults.Add(resultIDs[2])
+	assert.False(t, validatedResults.Has(resultIDs[0]))
+	assert.True(t, validatedResults.Has(resultIDs[2]))
+
+	validatedResults.Invalidate()
+	for _, resultID := range resultIDs {
+		assert.False(t, validatedResults.Has(resultID))
+	}
+}
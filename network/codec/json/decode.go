@@ -71,6 +71,8 @@ func decode(env Envelope) (interface{}, error) {
 		v = &messages.ChunkDataRequest{}
 	case CodeChunkDataResponse:
 		v = &messages.ChunkDataResponse{}
+	case CodeChunkDataBatchRequest:
+		v = &messages.ChunkDataBatchRequest{}
 
 	case CodeApprovalRequest:
 		v = &messages.ApprovalRequest{}
@@ -70,6 +70,8 @@ func encode(v interface{}) (*Envelope, error) {
 		code = CodeChunkDataRequest
 	case *messages.ChunkDataResponse:
 		code = CodeChunkDataResponse
+	case *messages.ChunkDataBatchRequest:
+		code = CodeChunkDataBatchRequest
 
 	// result approvals
 	case *messages.ApprovalRequest:
@@ -51,6 +51,12 @@ const (
 
 	// testing
 	CodeEcho
+
+	// new codes are only appended, as the codes are part of the wire format and nodes of
+	// different versions must agree on them
+
+	// data exchange for execution of blocks
+	CodeChunkDataBatchRequest
 )
 
 // Envelope is a wrapper to convey type information with JSON encoding without
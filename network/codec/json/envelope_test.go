@@ -0,0 +1,67 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnvelopeCodes pins the codes of the messages, which are part of the wire format, so that
+// adding a message doesn't change the codes of existing messages.
+func TestEnvelopeCodes(t *testing.T) {
+	codes := map[string]uint8{
+		"BlockProposal":             CodeBlockProposal,
+		"BlockVote":                 CodeBlockVote,
+		"SyncRequest":               CodeSyncRequest,
+		"SyncResponse":              CodeSyncResponse,
+		"RangeRequest":              CodeRangeRequest,
+		"BatchRequest":              CodeBatchRequest,
+		"BlockResponse":             CodeBlockResponse,
+		"ClusterBlockProposal":      CodeClusterBlockProposal,
+		"ClusterBlockVote":          CodeClusterBlockVote,
+		"ClusterBlockResponse":      CodeClusterBlockResponse,
+		"CollectionGuarantee":       CodeCollectionGuarantee,
+		"Transaction":               CodeTransaction,
+		"TransactionBody":           CodeTransactionBody,
+		"ExecutionReceipt":          CodeExecutionReceipt,
+		"ResultApproval":            CodeResultApproval,
+		"ExecutionStateSyncRequest": CodeExecutionStateSyncRequest,
+		"ExecutionStateDelta":       CodeExecutionStateDelta,
+		"ChunkDataRequest":          CodeChunkDataRequest,
+		"ChunkDataResponse":         CodeChunkDataResponse,
+		"ApprovalRequest":           CodeApprovalRequest,
+		"ApprovalResponse":          CodeApprovalResponse,
+		"EntityRequest":             CodeEntityRequest,
+		"EntityResponse":            CodeEntityResponse,
+		"Echo":                      CodeEcho,
+		"ChunkDataBatchRequest":     CodeChunkDataBatchRequest,
+	}
+	expected := map[string]uint8{
+		"BlockProposal":             1,
+		"BlockVote":                 2,
+		"SyncRequest":               3,
+		"SyncResponse":              4,
+		"RangeRequest":              5,
+		"BatchRequest":              6,
+		"BlockResponse":             7,
+		"ClusterBlockProposal":      8,
+		"ClusterBlockVote":          9,
+		"ClusterBlockResponse":      10,
+		"CollectionGuarantee":       11,
+		"Transaction":               12,
+		"TransactionBody":           13,
+		"ExecutionReceipt":          14,
+		"ResultApproval":            15,
+		"ExecutionStateSyncRequest": 16,
+		"ExecutionStateDelta":       17,
+		"ChunkDataRequest":          18,
+		"ChunkDataResponse":         19,
+		"ApprovalRequest":           20,
+		"ApprovalResponse":          21,
+		"EntityRequest":             22,
+		"EntityResponse":            23,
+		"Echo":                      24,
+		"ChunkDataBatchRequest":     25,
+	}
+	assert.Equal(t, expected, codes)
+}
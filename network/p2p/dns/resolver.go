@@ -0,0 +1,168 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/module"
+)
+
+// Config configures the caching of the hostnames of node addresses.
+type Config struct {
+	// TTL is the time for which resolved addresses are served from the cache. Once expired,
+	// they are still served while the hostname is resolved again in the background.
+	TTL time.Duration
+	// NegativeTTL is the time for which a failed lookup is cached, before the hostname is
+	// resolved again.
+	NegativeTTL time.Duration
+	// LookupTimeout is the maximum time spent resolving a hostname.
+	LookupTimeout time.Duration
+}
+
+// DefaultConfig is the default configuration of the caching of hostnames.
+var DefaultConfig = Config{
+	TTL:           5 * time.Minute,
+	NegativeTTL:   30 * time.Second,
+	LookupTimeout: 5 * time.Second,
+}
+
+// basicResolver is the lookup of hostnames the Resolver caches, which is implemented by
+// net.Resolver.
+type basicResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// entry is the cached outcome of the lookup of a hostname.
+type entry struct {
+	addrs      []net.IPAddr
+	err        error     // error of the lookup, if it failed and no addresses were ever resolved
+	expiry     time.Time // time after which the hostname is resolved again
+	refreshing bool      // whether the hostname is being resolved again in the background
+}
+
+// Resolver resolves the hostnames of node addresses and caches the resolved addresses, so
+// that nodes can move between IP addresses without updating their identities, while the
+// hostnames aren't resolved on each connection.
+// Expired addresses are served while they are refreshed in the background, and are kept if
+// refreshing them fails, so that an unavailable DNS server doesn't disconnect the nodes.
+type Resolver struct {
+	sync.Mutex
+	unit    *engine.Unit
+	log     zerolog.Logger
+	metrics module.NetworkMetrics
+	config  Config
+	backend basicResolver
+	cache   map[string]*entry
+}
+
+// NewResolver creates a new resolver, which looks up the hostnames with the default resolver
+// of the system.
+func NewResolver(log zerolog.Logger, metrics module.NetworkMetrics, config Config) *Resolver {
+	return newResolver(log, metrics, config, net.DefaultResolver)
+}
+
+func newResolver(log zerolog.Logger, metrics module.NetworkMetrics, config Config, backend basicResolver) *Resolver {
+	return &Resolver{
+		unit:    engine.NewUnit(),
+		log:     log.With().Str("component", "dns_resolver").Logger(),
+		metrics: metrics,
+		config:  config,
+		backend: backend,
+		cache:   make(map[string]*entry),
+	}
+}
+
+// Ready returns a ready channel that is closed once the resolver has started.
+func (r *Resolver) Ready() <-chan struct{} {
+	return r.unit.Ready()
+}
+
+// Done returns a done channel that is closed once the pending background lookups have
+// completed.
+func (r *Resolver) Done() <-chan struct{} {
+	return r.unit.Done()
+}
+
+// LookupIPAddr returns the IP addresses of the given host. IP addresses are returned as is.
+func (r *Resolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IPAddr{{IP: ip}}, nil
+	}
+
+	r.Lock()
+	e, ok := r.cache[host]
+	if ok && time.Now().Before(e.expiry) {
+		r.Unlock()
+		r.metrics.OnDNSCacheHit()
+		return e.addrs, e.err
+	}
+	if ok && e.err == nil {
+		// serves the expired addresses while the hostname is resolved again
+		if !e.refreshing {
+			e.refreshing = true
+			r.unit.Launch(func() {
+				_, _ = r.lookup(r.unit.Ctx(), host)
+			})
+		}
+		r.Unlock()
+		r.metrics.OnDNSCacheHit()
+		return e.addrs, nil
+	}
+	r.Unlock()
+
+	r.metrics.OnDNSCacheMiss()
+	return r.lookup(ctx, host)
+}
+
+// lookup resolves the hostname and caches the outcome. If the lookup fails while addresses
+// were resolved before, the previous addresses are kept and returned.
+func (r *Resolver) lookup(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.config.LookupTimeout)
+	defer cancel()
+
+	start := time.Now()
+	addrs, err := r.backend.LookupIPAddr(ctx, host)
+	r.metrics.DNSLookupDuration(time.Since(start))
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no addresses found")
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	e, ok := r.cache[host]
+	if !ok {
+		e = &entry{}
+	}
+	e.refreshing = false
+
+	if err != nil {
+		r.metrics.OnDNSLookupFailed()
+		r.log.Warn().Err(err).Str("host", host).Msg("could not resolve hostname")
+
+		if e.addrs != nil {
+			e.expiry = time.Now().Add(r.config.NegativeTTL)
+			return e.addrs, nil
+		}
+		// failures caused by the caller giving up aren't cached
+		if ctx.Err() == context.Canceled {
+			return nil, fmt.Errorf("could not resolve %s: %w", host, err)
+		}
+		e.err = fmt.Errorf("could not resolve %s: %w", host, err)
+		e.expiry = time.Now().Add(r.config.NegativeTTL)
+		r.cache[host] = e
+		return nil, e.err
+	}
+
+	e.addrs = addrs
+	e.err = nil
+	e.expiry = time.Now().Add(r.config.TTL)
+	r.cache[host] = e
+	return addrs, nil
+}
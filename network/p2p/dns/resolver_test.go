@@ -0,0 +1,165 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// fakeResolver resolves the hostnames to the addresses currently set for them, and counts
+// the lookups per hostname.
+type fakeResolver struct {
+	sync.Mutex
+	addrs   map[string][]net.IPAddr
+	lookups map[string]int
+}
+
+func newFakeResolver() *fakeResolver {
+	return &fakeResolver{
+		addrs:   make(map[string][]net.IPAddr),
+		lookups: make(map[string]int),
+	}
+}
+
+func (f *fakeResolver) set(host string, ip string) {
+	f.Lock()
+	defer f.Unlock()
+	if ip == "" {
+		delete(f.addrs, host)
+		return
+	}
+	f.addrs[host] = []net.IPAddr{{IP: net.ParseIP(ip)}}
+}
+
+func (f *fakeResolver) count(host string) int {
+	f.Lock()
+	defer f.Unlock()
+	return f.lookups[host]
+}
+
+func (f *fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.lookups[host]++
+	addrs, ok := f.addrs[host]
+	if !ok {
+		return nil, fmt.Errorf("no such host")
+	}
+	return addrs, nil
+}
+
+func newTestResolver(backend basicResolver) *Resolver {
+	return newResolver(zerolog.Nop(), metrics.NewNoopCollector(), Config{
+		TTL:           time.Minute,
+		NegativeTTL:   time.Minute,
+		LookupTimeout: time.Second,
+	}, backend)
+}
+
+// expire makes the cached outcome of the lookup of the hostname expire.
+func expire(r *Resolver, host string) {
+	r.Lock()
+	defer r.Unlock()
+	r.cache[host].expiry = time.Now().Add(-time.Second)
+}
+
+// TestResolver_Cache verifies that resolved addresses are served from the cache until they
+// expire, and are then served while they are refreshed in the background.
+func TestResolver_Cache(t *testing.T) {
+	backend := newFakeResolver()
+	backend.set("node", "10.0.0.1")
+	r := newTestResolver(backend)
+	unittest.RequireCloseBefore(t, r.Ready(), time.Second, "could not start resolver")
+
+	addrs, err := r.LookupIPAddr(context.Background(), "node")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", addrs[0].IP.String())
+
+	// the node moves to another address, which is noticed once the cache expires
+	backend.set("node", "10.0.0.2")
+	addrs, err = r.LookupIPAddr(context.Background(), "node")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", addrs[0].IP.String())
+	assert.Equal(t, 1, backend.count("node"))
+
+	expire(r, "node")
+	addrs, err = r.LookupIPAddr(context.Background(), "node")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", addrs[0].IP.String())
+
+	require.Eventually(t, func() bool {
+		addrs, err := r.LookupIPAddr(context.Background(), "node")
+		return err == nil && addrs[0].IP.String() == "10.0.0.2"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, backend.count("node"))
+
+	unittest.RequireCloseBefore(t, r.Done(), time.Second, "could not stop resolver")
+}
+
+// TestResolver_Fallback verifies that the previously resolved addresses are kept when the
+// hostname can't be resolved anymore.
+func TestResolver_Fallback(t *testing.T) {
+	backend := newFakeResolver()
+	backend.set("node", "10.0.0.1")
+	r := newTestResolver(backend)
+
+	_, err := r.LookupIPAddr(context.Background(), "node")
+	require.NoError(t, err)
+
+	backend.set("node", "")
+	expire(r, "node")
+	_, err = r.LookupIPAddr(context.Background(), "node")
+	require.NoError(t, err)
+
+	// waits for the background lookup to fail
+	require.Eventually(t, func() bool {
+		r.Lock()
+		defer r.Unlock()
+		return !r.cache["node"].refreshing
+	}, time.Second, 10*time.Millisecond)
+
+	addrs, err := r.LookupIPAddr(context.Background(), "node")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", addrs[0].IP.String())
+	assert.Equal(t, 2, backend.count("node"))
+}
+
+// TestResolver_NegativeCache verifies that failed lookups are cached until they expire.
+func TestResolver_NegativeCache(t *testing.T) {
+	backend := newFakeResolver()
+	r := newTestResolver(backend)
+
+	_, err := r.LookupIPAddr(context.Background(), "node")
+	assert.Error(t, err)
+	_, err = r.LookupIPAddr(context.Background(), "node")
+	assert.Error(t, err)
+	assert.Equal(t, 1, backend.count("node"))
+
+	backend.set("node", "10.0.0.1")
+	expire(r, "node")
+	addrs, err := r.LookupIPAddr(context.Background(), "node")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", addrs[0].IP.String())
+	assert.Equal(t, 2, backend.count("node"))
+}
+
+// TestResolver_IP verifies that IP addresses are returned without being looked up.
+func TestResolver_IP(t *testing.T) {
+	backend := newFakeResolver()
+	r := newTestResolver(backend)
+
+	addrs, err := r.LookupIPAddr(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", addrs[0].IP.String())
+	assert.Equal(t, 0, backend.count("10.0.0.1"))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/p2p/dns"
 )
 
 // libp2pConnector is a libp2p based Connector implementation to connect and disconnect from peers
@@ -21,6 +22,7 @@ type libp2pConnector struct {
 	backoffConnector *discovery.BackoffConnector
 	host             host.Host
 	log              zerolog.Logger
+	resolver         *dns.Resolver // used to resolve the hostnames of peer addresses, if any
 }
 
 var _ Connector = &libp2pConnector{}
@@ -51,7 +53,7 @@ func IsUnconvertibleIdentitiesError(err error) bool {
 	return errors.As(err, &errUnconvertableIdentitiesError)
 }
 
-func newLibp2pConnector(host host.Host, log zerolog.Logger) (*libp2pConnector, error) {
+func newLibp2pConnector(host host.Host, log zerolog.Logger, resolver *dns.Resolver) (*libp2pConnector, error) {
 	connector, err := defaultLibp2pBackoffConnector(host)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create libP2P connector: %w", err)
@@ -60,6 +62,7 @@ func newLibp2pConnector(host host.Host, log zerolog.Logger) (*libp2pConnector, e
 		backoffConnector: connector,
 		host:             host,
 		log:              log,
+		resolver:         resolver,
 	}, nil
 }
 
@@ -70,6 +73,11 @@ func (l *libp2pConnector) UpdatePeers(ctx context.Context, ids flow.IdentityList
 	// derive the peer.AddrInfo from each of the flow.Identity
 	pInfos, invalidIDs := peerInfosFromIDs(ids)
 
+	// resolve the hostnames of the peer addresses
+	for i, pInfo := range pInfos {
+		pInfos[i] = resolvePeerAddress(ctx, l.log, l.resolver, pInfo)
+	}
+
 	// connect to each of the peer.AddrInfo in pInfos
 	l.connectToPeers(ctx, pInfos)
 
@@ -31,6 +31,7 @@ import (
 	"github.com/onflow/flow-go/module"
 	flownet "github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/p2p/dns"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
@@ -52,7 +53,7 @@ type LibP2PFactoryFunc func() (*Node, error)
 // DefaultLibP2PNodeFactory is a factory function that receives a middleware instance and generates a libp2p Node by invoking its factory with
 // proper parameters.
 func DefaultLibP2PNodeFactory(log zerolog.Logger, me flow.Identifier, address string, flowKey fcrypto.PrivateKey, rootBlockID string,
-	maxPubSubMsgSize int, metrics module.NetworkMetrics, pingInfoProvider PingInfoProvider) (LibP2PFactoryFunc, error) {
+	maxPubSubMsgSize int, metrics module.NetworkMetrics, pingInfoProvider PingInfoProvider, resolver *dns.Resolver) (LibP2PFactoryFunc, error) {
 	// create PubSub options for libp2p to use
 	psOptions := []pubsub.Option{
 		// skip message signing
@@ -64,7 +65,22 @@ func DefaultLibP2PNodeFactory(log zerolog.Logger, me flow.Identifier, address st
 	}
 
 	return func() (*Node, error) {
-		return NewLibP2PNode(log, me, address, NewConnManager(log, metrics), flowKey, true, rootBlockID, pingInfoProvider, psOptions...)
+		return NewLibP2PNode(log, me, address, NewConnManager(log, metrics), flowKey, true, rootBlockID, pingInfoProvider, resolver, psOptions...)
+	}, nil
+}
+
+// PublicLibP2PNodeFactory returns a LibP2PFactoryFunc which generates the libp2p host of a public network, which
+// accepts connections from unstaked nodes such as observers, and hence does not allowlist its peers.
+func PublicLibP2PNodeFactory(log zerolog.Logger, me flow.Identifier, address string, flowKey fcrypto.PrivateKey, rootBlockID string,
+	maxPubSubMsgSize int, metrics module.NetworkMetrics, pingInfoProvider PingInfoProvider, resolver *dns.Resolver) (LibP2PFactoryFunc, error) {
+	psOptions := []pubsub.Option{
+		pubsub.WithMessageSigning(false),
+		pubsub.WithStrictSignatureVerification(false),
+		pubsub.WithMaxMessageSize(maxPubSubMsgSize),
+	}
+
+	return func() (*Node, error) {
+		return NewLibP2PNode(log, me, address, NewConnManager(log, metrics), flowKey, false, rootBlockID, pingInfoProvider, resolver, psOptions...)
 	}, nil
 }
 
@@ -81,8 +97,11 @@ type Node struct {
 	id                   flow.Identifier                        // used to represent id of flow node running this instance of libP2P node
 	flowLibP2PProtocolID protocol.ID                            // the unique protocol ID
 	pingService          *PingService
+	resolver             *dns.Resolver // used to resolve the hostnames of peer addresses, if any
 }
 
+// NewLibP2PNode creates and starts a libp2p node. If a resolver is given, the hostnames of the addresses of
+// peers are resolved by it, otherwise they are resolved by libp2p when dialing the peers.
 func NewLibP2PNode(logger zerolog.Logger,
 	id flow.Identifier,
 	address string,
@@ -91,6 +110,7 @@ func NewLibP2PNode(logger zerolog.Logger,
 	allowList bool,
 	rootBlockID string,
 	pingInfoProvider PingInfoProvider,
+	resolver *dns.Resolver,
 	psOption ...pubsub.Option) (*Node, error) {
 
 	libp2pKey, err := privKey(key)
@@ -129,6 +149,7 @@ func NewLibP2PNode(logger zerolog.Logger,
 		id:                   id,
 		flowLibP2PProtocolID: flowLibP2PProtocolID,
 		pingService:          pingService,
+		resolver:             resolver,
 	}
 
 	ip, port, err := n.GetIPPort()
@@ -211,6 +232,7 @@ func (n *Node) AddPeer(ctx context.Context, identity flow.Identity) error {
 	if err != nil {
 		return fmt.Errorf("failed to add peer %s: %w", identity.String(), err)
 	}
+	pInfo = resolvePeerAddress(ctx, n.logger, n.resolver, pInfo)
 
 	err = n.host.Connect(ctx, pInfo)
 	if err != nil {
@@ -245,6 +267,16 @@ func (n *Node) CreateStream(ctx context.Context, identity flow.Identity) (libp2p
 	return stream, nil
 }
 
+// CreateStreamToPeer opens a stream to a peer which is connected already, such as an unstaked node which connected
+// to a public network. The existing connection is used, as unstaked peers can't be dialed.
+func (n *Node) CreateStreamToPeer(ctx context.Context, peerID peer.ID) (libp2pnet.Stream, error) {
+	stream, err := n.host.NewStream(ctx, peerID, n.flowLibP2PProtocolID)
+	if err != nil {
+		return nil, flownet.NewPeerUnreachableError(fmt.Errorf("could not create stream (peer_id: %s): %w", peerID, err))
+	}
+	return stream, nil
+}
+
 // tryCreateNewStream makes at most maxAttempts to create a stream with the identity.
 // This was put in as a fix for #2416. PubSub and 1-1 communication compete with each other when trying to connect to
 // remote nodes and once in a while NewStream returns an error 'both yamux endpoints are clients'
@@ -414,6 +446,7 @@ func (n *Node) Ping(ctx context.Context, identity flow.Identity) (message.PingRe
 	if err != nil {
 		return message.PingResponse{}, -1, pingError(err)
 	}
+	targetInfo = resolvePeerAddress(ctx, n.logger, n.resolver, targetInfo)
 
 	// connect to the target node
 	err = n.host.Connect(ctx, targetInfo)
@@ -442,6 +475,10 @@ func (n *Node) UpdateAllowList(identities flow.IdentityList) error {
 		}
 	}
 
+	// nodes of public networks don't allowlist their peers
+	if n.connGater == nil {
+		return nil
+	}
 	n.connGater.update(allowlist)
 	return nil
 }
@@ -608,7 +608,8 @@ func NodeFixture(t *testing.T, log zerolog.Logger, key fcrypto.PrivateKey, rootI
 		key,
 		allowList,
 		rootID,
-		pingInfoProvider)
+		pingInfoProvider,
+		nil)
 	require.NoError(t, err)
 	n.SetFlowProtocolStreamHandler(handlerFunc)
 
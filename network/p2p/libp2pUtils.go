@@ -3,6 +3,7 @@ package p2p
 // All utilities for libp2p not natively provided by the library.
 
 import (
+	"context"
 	"fmt"
 	"net"
 
@@ -13,8 +14,10 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	"github.com/multiformats/go-multiaddr"
+	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/p2p/dns"
 )
 
 var directionLookUp = map[network.Direction]string{
@@ -208,3 +211,60 @@ func peerInfosFromIDs(ids flow.IdentityList) ([]peer.AddrInfo, map[flow.Identifi
 	}
 	return validIDs, invalidIDs
 }
+
+// resolvePeerAddress replaces the DNS multi-addresses of the given peer with the IPv4 multi-addresses its hostname
+// resolves to. A DNS multi-address is kept as is if its hostname can't be resolved, so that libp2p falls back to
+// resolving it when dialing the peer. The peer is returned unchanged if no resolver is given.
+func resolvePeerAddress(ctx context.Context, log zerolog.Logger, resolver *dns.Resolver, pInfo peer.AddrInfo) peer.AddrInfo {
+	if resolver == nil {
+		return pInfo
+	}
+
+	addrs := make([]multiaddr.Multiaddr, 0, len(pInfo.Addrs))
+	for _, addr := range pInfo.Addrs {
+		resolved, err := resolveMultiAddress(ctx, resolver, addr)
+		if err != nil {
+			log.Debug().Err(err).Str("address", addr.String()).Msg("falling back to dns address of peer")
+			addrs = append(addrs, addr)
+			continue
+		}
+		addrs = append(addrs, resolved...)
+	}
+
+	return peer.AddrInfo{ID: pInfo.ID, Addrs: addrs}
+}
+
+// resolveMultiAddress returns the IPv4 multi-addresses the hostname of the given DNS multi-address resolves to.
+// Multi-addresses other than DNS ones are returned as is.
+func resolveMultiAddress(ctx context.Context, resolver *dns.Resolver, addr multiaddr.Multiaddr) ([]multiaddr.Multiaddr, error) {
+	hostname, err := addr.ValueForProtocol(multiaddr.P_DNS4)
+	if err != nil {
+		return []multiaddr.Multiaddr{addr}, nil
+	}
+	port, err := addr.ValueForProtocol(multiaddr.P_TCP)
+	if err != nil {
+		return nil, fmt.Errorf("could not find port of address: %w", err)
+	}
+
+	ips, err := resolver.LookupIPAddr(ctx, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []multiaddr.Multiaddr
+	for _, ip := range ips {
+		if ip.IP.To4() == nil {
+			continue
+		}
+		maddr, err := multiaddr.NewMultiaddr(MultiAddressStr(ip.IP.String(), port))
+		if err != nil {
+			return nil, fmt.Errorf("could not create multi-address of %s: %w", ip.String(), err)
+		}
+		resolved = append(resolved, maddr)
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no ipv4 address found for %s", hostname)
+	}
+
+	return resolved, nil
+}
@@ -3,13 +3,11 @@
 package p2p
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"sync"
 	"time"
 
-	ggio "github.com/gogo/protobuf/io"
 	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
 	"github.com/rs/zerolog"
 
@@ -20,6 +18,7 @@ import (
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/message"
 	"github.com/onflow/flow-go/network/validator"
+	"github.com/onflow/flow-go/utils/logging"
 )
 
 type communicationMode int
@@ -72,15 +71,19 @@ type Middleware struct {
 	rootBlockID       string
 	validators        []network.MessageValidator
 	peerManager       *PeerManager
+	streams           *streamPool
+	public            bool // whether unstaked nodes are allowed to connect, see NewPublicMiddleware
 }
 
 // NewMiddleware creates a new middleware instance with the given config and using the
-// given codec to encode/decode messages to our peers.
+// given codec to encode/decode messages to our peers. The streams of unicast messages are
+// reused for further messages to the same peer, as configured by the stream pool config.
 func NewMiddleware(log zerolog.Logger,
 	libP2PNodeFactory LibP2PFactoryFunc,
 	flowID flow.Identifier,
 	metrics module.NetworkMetrics,
 	rootBlockID string,
+	streamPoolConfig StreamPoolConfig,
 	validators ...network.MessageValidator) *Middleware {
 
 	if len(validators) == 0 {
@@ -101,9 +104,30 @@ func NewMiddleware(log zerolog.Logger,
 		metrics:           metrics,
 		rootBlockID:       rootBlockID,
 		validators:        validators,
+		streams:           newStreamPool(log, metrics, streamPoolConfig),
 	}
 }
 
+// NewPublicMiddleware creates a new middleware instance of a public network, which unstaked nodes such as
+// observers connect to. The libp2p node of a public network should not allowlist its peers (see
+// PublicLibP2PNodeFactory). The middleware doesn't manage the connections to its peers, as they connect
+// to it, and it attributes the unicast messages it receives to the unstaked node IDs of their senders (see
+// UnstakedNodeID), so that they can't impersonate other nodes. As the messages published on pubsub channels
+// can't be attributed to their senders, it doesn't subscribe to them and public networks only support unicast
+// messaging.
+func NewPublicMiddleware(log zerolog.Logger,
+	libP2PNodeFactory LibP2PFactoryFunc,
+	flowID flow.Identifier,
+	metrics module.NetworkMetrics,
+	rootBlockID string,
+	streamPoolConfig StreamPoolConfig,
+	validators ...network.MessageValidator) *Middleware {
+
+	m := NewMiddleware(log, libP2PNodeFactory, flowID, metrics, rootBlockID, streamPoolConfig, validators...)
+	m.public = true
+	return m
+}
+
 func defaultValidators(log zerolog.Logger, flowID flow.Identifier) []network.MessageValidator {
 	return []network.MessageValidator{
 		validator.NewSenderValidator(flowID),      // validator to filter out messages sent by this node itself
@@ -143,7 +167,17 @@ func (m *Middleware) Start(ov network.Overlay) error {
 		return fmt.Errorf("could not update approved peer list: %w", err)
 	}
 
-	libp2pConnector, err := newLibp2pConnector(m.libP2PNode.Host(), m.log)
+	if m.streams.config.MaxIdlePerPeer > 0 {
+		m.wg.Add(1)
+		go m.pruneStreams()
+	}
+
+	// the peers of a public network connect to it, and would be disconnected by the peer manager
+	if m.public {
+		return nil
+	}
+
+	libp2pConnector, err := newLibp2pConnector(m.libP2PNode.Host(), m.log, m.libP2PNode.resolver)
 	if err != nil {
 		return fmt.Errorf("failed to create libp2pConnector: %w", err)
 	}
@@ -159,11 +193,34 @@ func (m *Middleware) Start(ov network.Overlay) error {
 	return nil
 }
 
+// pruneStreams periodically closes the unicast streams which have been idle for too long,
+// until the middleware is stopped.
+func (m *Middleware) pruneStreams() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.streams.config.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.streams.prune(now)
+		}
+	}
+}
+
 // Stop will end the execution of the middleware and wait for it to end.
 func (m *Middleware) Stop() {
 	// stops peer manager
-	<-m.peerManager.Done()
-	m.log.Debug().Msg("peer manager successfully stopped")
+	if m.peerManager != nil {
+		<-m.peerManager.Done()
+		m.log.Debug().Msg("peer manager successfully stopped")
+	}
+
+	// closes the idle unicast streams, so that peers stop reading from them
+	m.streams.closeAll()
 
 	// stops libp2p
 	done, err := m.libP2PNode.Stop()
@@ -237,12 +294,6 @@ func (m *Middleware) chooseMode(_ network.Channel, _ *message.Message, targetIDs
 // Dispatch should be used whenever guaranteed delivery to a specific target is required. Otherwise, Publish is
 // a more efficient candidate.
 func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier) error {
-	// translates identifier to identity
-	targetIdentity, err := m.identity(targetID)
-	if err != nil {
-		return fmt.Errorf("could not find identity for target id: %w", err)
-	}
-
 	maxMsgSize := unicastMaxMsgSize(msg)
 	if msg.Size() > maxMsgSize {
 		// message size goes beyond maximum size that the serializer can handle.
@@ -252,38 +303,50 @@ func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier)
 	}
 
 	maxTimeout := unicastMaxMsgDuration(msg)
-	// pass in a context with timeout to make the unicast call fail fast
-	ctx, cancel := context.WithTimeout(m.ctx, maxTimeout)
-	defer cancel()
-
-	// create new stream
-	// (streams don't need to be reused and are fairly inexpensive to be created for each send.
-	// A stream creation does NOT incur an RTT as stream negotiation happens as part of the first message
-	// sent out the the receiver
-	stream, err := m.libP2PNode.CreateStream(ctx, targetIdentity)
-	if err != nil {
-		return fmt.Errorf("failed to create stream for %s :%w", targetID.String(), err)
+
+	// reuse an idle stream to the target if any, and retry on a new stream if the idle stream
+	// turns out to be broken (e.g. closed by the target in the meantime)
+	ps := m.streams.get(targetID)
+	if ps != nil {
+		err := ps.write(msg, time.Now().Add(maxTimeout))
+		if err != nil {
+			m.log.Debug().Err(err).Hex("target_id", logging.ID(targetID)).Msg("failed to send message on pooled stream, retrying on new stream")
+			m.resetStream(ps.stream)
+			ps = nil
+		}
 	}
 
-	// create a gogo protobuf writer
-	bufw := bufio.NewWriter(stream)
-	writer := ggio.NewDelimitedWriter(bufw)
+	if ps == nil {
+		// pass in a context with timeout to make the unicast call fail fast
+		ctx, cancel := context.WithTimeout(m.ctx, maxTimeout)
+		defer cancel()
 
-	err = writer.WriteMsg(msg)
-	if err != nil {
-		return fmt.Errorf("failed to send message to %s: %w", targetID.String(), err)
-	}
+		// create new stream
+		// (stream negotiation happens as part of the first message sent out the the receiver, so
+		// the creation doesn't incur an RTT, but it adds the target as a peer first)
+		stream, err := m.createStream(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to create stream for %s :%w", targetID.String(), err)
+		}
+		m.metrics.UnicastStreamCreated()
 
-	// flush the stream
-	err = bufw.Flush()
-	if err != nil {
-		return fmt.Errorf("failed to flush stream for %s: %w", targetID.String(), err)
+		ps = newPooledStream(stream)
+		err = ps.write(msg, time.Now().Add(maxTimeout))
+		if err != nil {
+			m.resetStream(stream)
+			return fmt.Errorf("failed to send message to %s: %w", targetID.String(), err)
+		}
 	}
 
-	// close the stream immediately
-	err = stream.Close()
-	if err != nil {
-		return fmt.Errorf("failed to close the stream for %s: %w", targetID.String(), err)
+	if m.streams.config.MaxIdlePerPeer > 0 {
+		// keep the stream for further messages to the target
+		m.streams.put(targetID, ps)
+	} else {
+		// close the stream immediately
+		err := ps.stream.Close()
+		if err != nil {
+			return fmt.Errorf("failed to close the stream for %s: %w", targetID.String(), err)
+		}
 	}
 
 	// OneToOne communication metrics are reported with topic OneToOne
@@ -292,6 +355,34 @@ func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier)
 	return nil
 }
 
+// createStream creates a new stream to the target. The targets which are not part of the identities of the
+// overlay are looked up among the unstaked nodes connected to a public network.
+func (m *Middleware) createStream(ctx context.Context, targetID flow.Identifier) (libp2pnetwork.Stream, error) {
+	// translates identifier to identity
+	targetIdentity, err := m.identity(targetID)
+	if err == nil {
+		return m.libP2PNode.CreateStream(ctx, targetIdentity)
+	}
+	if !m.public {
+		return nil, fmt.Errorf("could not find identity for target id: %w", err)
+	}
+
+	for _, peerID := range m.libP2PNode.Host().Network().Peers() {
+		if unstakedNodeID(peerID) == targetID {
+			return m.libP2PNode.CreateStreamToPeer(ctx, peerID)
+		}
+	}
+	return nil, fmt.Errorf("could not find identity or connected unstaked node for target id %x", targetID)
+}
+
+// resetStream resets a stream which failed, so that it isn't used anymore.
+func (m *Middleware) resetStream(stream libp2pnetwork.Stream) {
+	err := stream.Reset()
+	if err != nil {
+		m.log.Debug().Err(err).Msg("failed to reset stream")
+	}
+}
+
 // identity returns corresponding identity of an identifier based on overlay identity list.
 func (m *Middleware) identity(identifier flow.Identifier) (flow.Identity, error) {
 	// get the node identity map from the overlay
@@ -334,8 +425,24 @@ func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
 
 	log.Info().Msg("incoming connection established")
 
+	callback := m.processMessage
+	if m.public {
+		// the messages of unstaked nodes are only accepted from their own node ID
+		originID := unstakedNodeID(s.Conn().RemotePeer())
+		callback = func(msg *message.Message) {
+			if flow.HashToID(msg.OriginID) != originID {
+				log.Warn().
+					Hex("origin_id", msg.OriginID).
+					Hex("sender_id", logging.ID(originID)).
+					Msg("dropping message with origin other than its sender")
+				return
+			}
+			m.processMessage(msg)
+		}
+	}
+
 	//create a new readConnection with the context of the middleware
-	conn := newReadConnection(m.ctx, s, m.processMessage, log, m.metrics, LargeMsgMaxUnicastMsgSize)
+	conn := newReadConnection(m.ctx, s, callback, log, m.metrics, LargeMsgMaxUnicastMsgSize)
 
 	// kick off the receive loop to continuously receive messages
 	m.wg.Add(1)
@@ -344,6 +451,10 @@ func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
 
 // Subscribe subscribes the middleware to a channel.
 func (m *Middleware) Subscribe(channel network.Channel) error {
+	// public networks only accept unicast messages, see NewPublicMiddleware
+	if m.public {
+		return nil
+	}
 
 	topic := engine.TopicFromChannel(channel, m.rootBlockID)
 
@@ -367,6 +478,10 @@ func (m *Middleware) Subscribe(channel network.Channel) error {
 
 // Unsubscribe unsubscribes the middleware from a channel.
 func (m *Middleware) Unsubscribe(channel network.Channel) error {
+	if m.public {
+		return nil
+	}
+
 	topic := engine.TopicFromChannel(channel, m.rootBlockID)
 	err := m.libP2PNode.UnSubscribe(topic)
 	if err != nil {
@@ -400,6 +515,9 @@ func (m *Middleware) processMessage(msg *message.Message) {
 // a many nodes subscribing to the channel. It does not guarantee the delivery though, and operates on a best
 // effort.
 func (m *Middleware) Publish(msg *message.Message, channel network.Channel) error {
+	if m.public {
+		return fmt.Errorf("could not publish on channel %s: public networks only support unicast messaging", channel)
+	}
 
 	// convert the message to bytes to be put on the wire.
 	data, err := msg.Marshal()
@@ -453,7 +571,9 @@ func (m *Middleware) UpdateAllowList() error {
 	}
 
 	// update peer connections
-	m.peerManager.RequestPeerUpdate()
+	if m.peerManager != nil {
+		m.peerManager.RequestPeerUpdate()
+	}
 
 	return nil
 }
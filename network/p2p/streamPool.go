@@ -0,0 +1,195 @@
+package p2p
+
+import (
+	"bufio"
+	"fmt"
+	"sync"
+	"time"
+
+	ggio "github.com/gogo/protobuf/io"
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/message"
+)
+
+// StreamPoolConfig configures the reuse of the streams of unicast messages. Creating a stream
+// for each message adds the peer and negotiates the stream anew, which dominates the latency of
+// repeated messages to the same peer, such as receipts and chunk data packs.
+type StreamPoolConfig struct {
+	// MaxIdlePerPeer is the maximum number of idle streams kept per peer. Zero disables the reuse
+	// of streams, so that each message is sent on a new stream.
+	MaxIdlePerPeer uint
+	// IdleTimeout is the time after which idle streams are closed.
+	IdleTimeout time.Duration
+}
+
+// DefaultStreamPoolConfig is the default configuration of the reuse of unicast streams.
+var DefaultStreamPoolConfig = StreamPoolConfig{
+	MaxIdlePerPeer: 2,
+	IdleTimeout:    time.Minute,
+}
+
+// pooledStream is an outbound unicast stream along with its writer, which is kept for
+// sending further messages to the same peer.
+type pooledStream struct {
+	stream   libp2pnetwork.Stream
+	bufw     *bufio.Writer
+	writer   ggio.WriteCloser
+	reused   bool      // whether the stream was taken from the pool
+	lastUsed time.Time // time at which the stream was put back into the pool
+}
+
+func newPooledStream(stream libp2pnetwork.Stream) *pooledStream {
+	bufw := bufio.NewWriter(stream)
+	return &pooledStream{
+		stream: stream,
+		bufw:   bufw,
+		writer: ggio.NewDelimitedWriter(bufw),
+	}
+}
+
+// write writes the message to the stream and flushes it, failing if the message could not
+// be written before the given deadline.
+func (ps *pooledStream) write(msg *message.Message, deadline time.Time) error {
+	err := ps.stream.SetWriteDeadline(deadline)
+	if err != nil {
+		return fmt.Errorf("failed to set write deadline: %w", err)
+	}
+
+	err = ps.writer.WriteMsg(msg)
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	err = ps.bufw.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to flush stream: %w", err)
+	}
+
+	// idle streams must not time out
+	err = ps.stream.SetWriteDeadline(time.Time{})
+	if err != nil {
+		return fmt.Errorf("failed to clear write deadline: %w", err)
+	}
+
+	return nil
+}
+
+// streamPool holds the idle outbound unicast streams per peer. A stream is taken from the
+// pool by a single sender at a time, so messages are never interleaved on a stream.
+type streamPool struct {
+	sync.Mutex
+	log     zerolog.Logger
+	metrics module.NetworkMetrics
+	config  StreamPoolConfig
+	idle    map[flow.Identifier][]*pooledStream
+	count   uint // total number of idle streams
+}
+
+func newStreamPool(log zerolog.Logger, metrics module.NetworkMetrics, config StreamPoolConfig) *streamPool {
+	return &streamPool{
+		log:     log.With().Str("component", "stream_pool").Logger(),
+		metrics: metrics,
+		config:  config,
+		idle:    make(map[flow.Identifier][]*pooledStream),
+	}
+}
+
+// get takes the most recently used idle stream to the given peer out of the pool. It returns
+// nil if there is no such stream.
+func (p *streamPool) get(targetID flow.Identifier) *pooledStream {
+	p.Lock()
+	defer p.Unlock()
+
+	streams := p.idle[targetID]
+	if len(streams) == 0 {
+		return nil
+	}
+
+	ps := streams[len(streams)-1]
+	p.remove(targetID, len(streams)-1)
+	ps.reused = true
+	p.metrics.UnicastStreamReused()
+	return ps
+}
+
+// put puts the stream to the given peer back into the pool, once the sender is done with
+// it. The stream is closed instead if the pool already holds enough streams to the peer.
+func (p *streamPool) put(targetID flow.Identifier, ps *pooledStream) {
+	p.Lock()
+	if uint(len(p.idle[targetID])) >= p.config.MaxIdlePerPeer {
+		p.Unlock()
+		p.close(ps)
+		return
+	}
+	ps.reused = false
+	ps.lastUsed = time.Now()
+	p.idle[targetID] = append(p.idle[targetID], ps)
+	p.count++
+	p.metrics.UnicastStreamsIdle(p.count)
+	p.Unlock()
+}
+
+// prune closes the streams which have been idle for longer than the idle timeout.
+func (p *streamPool) prune(now time.Time) {
+	var expired []*pooledStream
+
+	p.Lock()
+	for targetID, streams := range p.idle {
+		// streams are put back in order, so the expired ones come first
+		i := 0
+		for i < len(streams) && now.Sub(streams[i].lastUsed) >= p.config.IdleTimeout {
+			i++
+		}
+		expired = append(expired, streams[:i]...)
+		for ; i > 0; i-- {
+			p.remove(targetID, 0)
+		}
+	}
+	p.Unlock()
+
+	for _, ps := range expired {
+		p.close(ps)
+	}
+}
+
+// closeAll closes all idle streams.
+func (p *streamPool) closeAll() {
+	p.Lock()
+	idle := p.idle
+	p.idle = make(map[flow.Identifier][]*pooledStream)
+	p.count = 0
+	p.metrics.UnicastStreamsIdle(0)
+	p.Unlock()
+
+	for _, streams := range idle {
+		for _, ps := range streams {
+			p.close(ps)
+		}
+	}
+}
+
+// remove removes the idle stream at the given index from the streams to the given peer.
+// It must be called with the lock held.
+func (p *streamPool) remove(targetID flow.Identifier, index int) {
+	streams := p.idle[targetID]
+	streams = append(streams[:index], streams[index+1:]...)
+	if len(streams) == 0 {
+		delete(p.idle, targetID)
+	} else {
+		p.idle[targetID] = streams
+	}
+	p.count--
+	p.metrics.UnicastStreamsIdle(p.count)
+}
+
+// close closes the stream, so that the remote peer stops reading from it.
+func (p *streamPool) close(ps *pooledStream) {
+	err := ps.stream.Close()
+	if err != nil {
+		p.log.Debug().Err(err).Msg("failed to close pooled stream")
+	}
+}
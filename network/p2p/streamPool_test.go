@@ -0,0 +1,105 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// fakeStream is a stream which only records whether it was closed.
+type fakeStream struct {
+	libp2pnetwork.Stream
+	closed bool
+}
+
+func (s *fakeStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func newTestStreamPool(maxIdle uint) *streamPool {
+	return newStreamPool(zerolog.Nop(), metrics.NewNoopCollector(), StreamPoolConfig{
+		MaxIdlePerPeer: maxIdle,
+		IdleTimeout:    time.Minute,
+	})
+}
+
+// TestStreamPool_Reuse verifies that streams put back into the pool are reused for the same
+// peer only, and that the pool keeps at most the configured number of streams per peer.
+func TestStreamPool_Reuse(t *testing.T) {
+	pool := newTestStreamPool(2)
+	targetID := unittest.IdentifierFixture()
+	otherID := unittest.IdentifierFixture()
+
+	assert.Nil(t, pool.get(targetID))
+
+	streams := []*fakeStream{{}, {}, {}}
+	for _, stream := range streams {
+		pool.put(targetID, newPooledStream(stream))
+	}
+	// the third stream exceeds the limit of idle streams per peer
+	assert.False(t, streams[0].closed)
+	assert.False(t, streams[1].closed)
+	assert.True(t, streams[2].closed)
+	assert.Equal(t, uint(2), pool.count)
+
+	assert.Nil(t, pool.get(otherID))
+
+	// the most recently used stream is reused first
+	ps := pool.get(targetID)
+	require.NotNil(t, ps)
+	assert.Equal(t, streams[1], ps.stream)
+	assert.True(t, ps.reused)
+
+	ps = pool.get(targetID)
+	require.NotNil(t, ps)
+	assert.Equal(t, streams[0], ps.stream)
+
+	assert.Nil(t, pool.get(targetID))
+	assert.Equal(t, uint(0), pool.count)
+}
+
+// TestStreamPool_Prune verifies that the streams idle for longer than the idle timeout are
+// closed, while the others are kept.
+func TestStreamPool_Prune(t *testing.T) {
+	pool := newTestStreamPool(2)
+	targetID := unittest.IdentifierFixture()
+
+	old := &fakeStream{}
+	pool.put(targetID, newPooledStream(old))
+	pool.idle[targetID][0].lastUsed = time.Now().Add(-2 * time.Minute)
+	recent := &fakeStream{}
+	pool.put(targetID, newPooledStream(recent))
+
+	pool.prune(time.Now())
+	assert.True(t, old.closed)
+	assert.False(t, recent.closed)
+
+	ps := pool.get(targetID)
+	require.NotNil(t, ps)
+	assert.Equal(t, recent, ps.stream)
+	assert.Nil(t, pool.get(targetID))
+}
+
+// TestStreamPool_CloseAll verifies that closing the pool closes all idle streams.
+func TestStreamPool_CloseAll(t *testing.T) {
+	pool := newTestStreamPool(1)
+
+	streams := []*fakeStream{{}, {}}
+	for _, stream := range streams {
+		pool.put(unittest.IdentifierFixture(), newPooledStream(stream))
+	}
+
+	pool.closeAll()
+	for _, stream := range streams {
+		assert.True(t, stream.closed)
+	}
+	assert.Equal(t, uint(0), pool.count)
+}
@@ -0,0 +1,32 @@
+package p2p
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	fcrypto "github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// UnstakedNodeID returns the node ID of an unstaked node, such as an observer, with the given
+// networking key. Unstaked nodes have no identity in the protocol state, so their node ID is
+// derived from their libp2p peer ID, which allows the public network of staked nodes to tell
+// the node IDs of the peers connecting to it.
+func UnstakedNodeID(key fcrypto.PublicKey) (flow.Identifier, error) {
+	libp2pKey, err := publicKey(key)
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not translate networking key: %w", err)
+	}
+	peerID, err := peer.IDFromPublicKey(libp2pKey)
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not get peer ID: %w", err)
+	}
+	return unstakedNodeID(peerID), nil
+}
+
+// unstakedNodeID returns the node ID of the unstaked node with the given peer ID.
+func unstakedNodeID(peerID peer.ID) flow.Identifier {
+	return flow.HashToID(hash.NewSHA3_256().ComputeHash([]byte(peerID)))
+}
@@ -0,0 +1,30 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestUnstakedNodeID verifies that the node ID of an unstaked node is derived from the peer ID of its
+// networking key, so that the public network can attribute its messages to it.
+func TestUnstakedNodeID(t *testing.T) {
+	keys, err := unittest.NetworkingKeys(2)
+	require.NoError(t, err)
+
+	nodeID, err := UnstakedNodeID(keys[0].PublicKey())
+	require.NoError(t, err)
+	otherID, err := UnstakedNodeID(keys[1].PublicKey())
+	require.NoError(t, err)
+	assert.NotEqual(t, nodeID, otherID)
+
+	libp2pKey, err := publicKey(keys[0].PublicKey())
+	require.NoError(t, err)
+	peerID, err := peer.IDFromPublicKey(libp2pKey)
+	require.NoError(t, err)
+	assert.Equal(t, nodeID, unstakedNodeID(peerID))
+}
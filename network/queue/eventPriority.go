@@ -90,6 +90,8 @@ func getPriorityByType(message interface{}) Priority {
 		return HighPriority
 	case *messages.ChunkDataResponse:
 		return HighPriority
+	case *messages.ChunkDataBatchRequest:
+		return HighPriority
 
 	// request/response for result approvals
 	case *messages.ApprovalRequest:
@@ -80,7 +80,8 @@ func GenerateMiddlewares(t *testing.T, logger zerolog.Logger, identities flow.Id
 			factory,
 			id.NodeID,
 			metrics,
-			rootBlockID)
+			rootBlockID,
+			p2p.DefaultStreamPoolConfig)
 	}
 	return mws
 }
@@ -203,6 +204,7 @@ func generateLibP2PNode(t *testing.T,
 		true,
 		rootBlockID,
 		pingInfoProvider,
+		nil,
 		psOptions...)
 
 	require.NoError(t, err)
@@ -0,0 +1,20 @@
+package topology
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+)
+
+// FullyConnectedTopology is a topology which connects the node to all nodes of the network. It is meant
+// for nodes with only few peers, such as observers connecting to their upstream access nodes.
+type FullyConnectedTopology struct{}
+
+// NewFullyConnectedTopology returns an instance of the FullyConnectedTopology.
+func NewFullyConnectedTopology() *FullyConnectedTopology {
+	return &FullyConnectedTopology{}
+}
+
+// GenerateFanout returns all nodes of the network, regardless of the channels the node is subscribing to.
+func (t FullyConnectedTopology) GenerateFanout(ids flow.IdentityList, _ network.ChannelList) (flow.IdentityList, error) {
+	return ids, nil
+}
@@ -1,15 +1,19 @@
 package badger
 
 import (
+	"time"
+
 	"github.com/onflow/flow-go/model/flow"
 )
 
 type Config struct {
-	transactionExpiry uint64 // how many blocks after the reference block a transaction expires
+	transactionExpiry       uint64        // how many blocks after the reference block a transaction expires
+	blockTimestampTolerance time.Duration // how far block timestamps may be ahead of the local clock
 }
 
 func DefaultConfig() Config {
 	return Config{
-		transactionExpiry: flow.DefaultTransactionExpiry,
+		transactionExpiry:       flow.DefaultTransactionExpiry,
+		blockTimestampTolerance: flow.DefaultBlockTimestampTolerance,
 	}
 }
@@ -5,6 +5,7 @@ package badger
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 
@@ -84,6 +85,14 @@ func NewFullConsensusState(
 	if err != nil {
 		return nil, fmt.Errorf("initialization of Mutable Follower State failed: %w", err)
 	}
+
+	// the tolerance for block timestamps is a parameter of the chain
+	chainID, err := state.Params().ChainID()
+	if err != nil {
+		return nil, fmt.Errorf("could not get chain ID: %w", err)
+	}
+	followerState.cfg.blockTimestampTolerance = chainID.BlockTimestampTolerance()
+
 	return &MutableState{
 		FollowerState:    followerState,
 		receiptValidator: receiptValidator,
@@ -134,6 +143,12 @@ func (m *MutableState) Extend(candidate *flow.Block) error {
 		return fmt.Errorf("header does not compliance the chain state: %w", err)
 	}
 
+	// check if the block timestamp is not too far ahead of the local clock
+	err = m.timestampExtend(candidate)
+	if err != nil {
+		return fmt.Errorf("timestamp does not compliance the local clock: %w", err)
+	}
+
 	// check if the guarantees in the payload is a valid extension of the finalized state
 	err = m.guaranteeExtend(candidate)
 	if err != nil {
@@ -162,6 +177,13 @@ func (m *MutableState) Extend(candidate *flow.Block) error {
 	return nil
 }
 
+// WithBlockTimestampTolerance overrides the tolerance of the chain for block timestamps ahead of
+// the local clock, for nodes whose clocks are known to be skewed.
+func (m *MutableState) WithBlockTimestampTolerance(tolerance time.Duration) *MutableState {
+	m.cfg.blockTimestampTolerance = tolerance
+	return m
+}
+
 // header compliance check to verify if the given block connects to the
 // last finalized block.
 func (m *FollowerState) headerExtend(candidate *flow.Block) error {
@@ -235,6 +257,19 @@ func (m *FollowerState) headerExtend(candidate *flow.Block) error {
 	return nil
 }
 
+// The timestamp compliance check. The timestamp of the block should not be ahead of the local
+// clock by more than the configured tolerance. Blocks from the past are valid, so that the check
+// doesn't prevent catching up with the chain.
+func (m *MutableState) timestampExtend(candidate *flow.Block) error {
+	header := candidate.Header
+	latest := time.Now().Add(m.cfg.blockTimestampTolerance)
+	if header.Timestamp.After(latest) {
+		return state.NewInvalidExtensionErrorf("candidate timestamp is too far in the future (timestamp: %s, tolerance: %s)",
+			header.Timestamp, m.cfg.blockTimestampTolerance)
+	}
+	return nil
+}
+
 // The guarantee part of the payload compliance check.
 // None of the blocks should have included a
 // guarantee that was expired at the block height, nor should it have been
@@ -251,10 +286,7 @@ func (m *MutableState) guaranteeExtend(candidate *flow.Block) error {
 	// we only look as far back for duplicates as the transaction expiry limit;
 	// if a guarantee was included before that, we will disqualify it on the
 	// basis of the reference block anyway
-	limit := header.Height - m.cfg.transactionExpiry
-	if limit > header.Height { // overflow check
-		limit = 0
-	}
+	limit := flow.ExpiryLimit(header.Height, m.cfg.transactionExpiry)
 
 	// look up the root height so we don't look too far back
 	// initially this is the genesis block height (aka 0).
@@ -288,7 +320,10 @@ func (m *MutableState) guaranteeExtend(candidate *flow.Block) error {
 		ancestorID = ancestor.ParentID
 	}
 
-	// check each guarantee included in the payload for duplication and expiry
+	// guarantees for collections larger than the chain allows can't be handled by execution
+	maxByteSize := header.ChainID.MaxCollectionByteSize()
+
+	// check each guarantee included in the payload for duplication, size and expiry
 	for _, guarantee := range payload.Guarantees {
 
 		// if the guarantee was already included before, error
@@ -297,6 +332,17 @@ func (m *MutableState) guaranteeExtend(candidate *flow.Block) error {
 			return state.NewInvalidExtensionErrorf("payload includes duplicate guarantee (%x)", guarantee.ID())
 		}
 
+		// if the guarantee doesn't declare the size of the collection, or if the
+		// collection is too large, error
+		if guarantee.TransactionCount == 0 || guarantee.ByteSize == 0 {
+			return state.NewInvalidExtensionErrorf("payload includes guarantee without collection metadata (%x, transactions: %d, size: %d)",
+				guarantee.ID(), guarantee.TransactionCount, guarantee.ByteSize)
+		}
+		if guarantee.ByteSize > maxByteSize {
+			return state.NewInvalidExtensionErrorf("payload includes guarantee for oversized collection (%x, size: %d, max: %d)",
+				guarantee.ID(), guarantee.ByteSize, maxByteSize)
+		}
+
 		// get the reference block to check expiry
 		ref, err := m.headers.ByBlockID(guarantee.ReferenceBlockID)
 		if err != nil {
@@ -521,6 +567,8 @@ func (m *FollowerState) Finalize(blockID flow.Identifier) error {
 				events = append(events, func() { m.consumer.EpochSetupPhaseStarted(ev.Counter-1, header) })
 			case *flow.EpochCommit:
 				events = append(events, func() { m.consumer.EpochCommittedPhaseStarted(ev.Counter-1, header) })
+			case *flow.SystemContractCallFailed:
+				// failed system contract calls are only reported
 			default:
 				return fmt.Errorf("invalid service event type in payload (%T)", event)
 			}
@@ -540,7 +588,7 @@ func (m *FollowerState) Finalize(blockID flow.Identifier) error {
 	}
 
 	// FINALLY: any block that is finalized is already a valid extension;
-	// in order to make it final, we need to do just three things:
+	// in order to make it final, we need to do just four things:
 	// 1) Map its height to its index; there can no longer be other blocks at
 	// this height, as it becomes immutable.
 	// 2) Forward the last finalized height to its height as well. We now have
@@ -548,6 +596,8 @@ func (m *FollowerState) Finalize(blockID flow.Identifier) error {
 	// 3) Forward the last sealed height to the height of the block its last
 	// seal sealed. This could actually stay the same if it has no seals in its
 	// payload, in which case the parent's seal is the same.
+	// 4) Index the seals of its payload by the blocks they seal, as the seals
+	// are final now.
 
 	err = operation.RetryOnConflict(m.db.Update, func(tx *badger.Txn) error {
 		err = operation.IndexBlockHeight(header.Height, blockID)(tx)
@@ -562,6 +612,12 @@ func (m *FollowerState) Finalize(blockID flow.Identifier) error {
 		if err != nil {
 			return fmt.Errorf("could not update sealed height: %w", err)
 		}
+		for _, seal := range payload.Seals {
+			err = operation.IndexFinalizedSealByBlockID(seal.BlockID, seal.ID())(tx)
+			if err != nil {
+				return fmt.Errorf("could not index seal by sealed block: %w", err)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -780,6 +836,10 @@ func (m *FollowerState) handleServiceEvents(block *flow.Block) ([]func(*transact
 				// we'll insert the commit event when we insert the block
 				ops = append(ops, m.epoch.commits.StoreTx(ev))
 
+			case *flow.SystemContractCallFailed:
+
+				// failed system contract calls are only reported, they don't change the protocol state
+
 			default:
 				return nil, fmt.Errorf("invalid service event type: %s", event.Type)
 			}
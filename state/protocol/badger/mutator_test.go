@@ -69,6 +69,11 @@ func TestBootstrapValid(t *testing.T) {
 		err = db.View(operation.RetrieveSeal(sealID, seal))
 		require.NoError(t, err)
 
+		var finalizedSealID flow.Identifier
+		err = db.View(operation.LookupBySealedBlockID(seal.BlockID, &finalizedSealID))
+		require.NoError(t, err)
+		require.Equal(t, sealID, finalizedSealID)
+
 		block, err := rootSnapshot.Head()
 		require.NoError(t, err)
 		require.Equal(t, block.Height, finalized)
@@ -303,6 +308,70 @@ func TestExtendInvalidChainID(t *testing.T) {
 	})
 }
 
+// TestExtendTimestampInFuture verifies that blocks with a timestamp ahead of the local clock by
+// more than the tolerance are rejected, and accepted once the tolerance is large enough.
+func TestExtendTimestampInFuture(t *testing.T) {
+	rootSnapshot := unittest.RootSnapshotFixture(participants)
+	util.RunWithFullProtocolState(t, rootSnapshot, func(db *badger.DB, state *protocol.MutableState) {
+		head, err := rootSnapshot.Head()
+		require.NoError(t, err)
+
+		block := unittest.BlockWithParentFixture(head)
+		block.SetPayload(flow.EmptyPayload())
+		block.Header.Timestamp = time.Now().Add(2 * head.ChainID.BlockTimestampTolerance())
+
+		err = state.Extend(&block)
+		require.Error(t, err)
+		require.True(t, st.IsInvalidExtensionError(err), err)
+
+		err = state.WithBlockTimestampTolerance(3 * head.ChainID.BlockTimestampTolerance()).Extend(&block)
+		require.NoError(t, err)
+	})
+}
+
+func TestExtendGuaranteeMetadata(t *testing.T) {
+	rootSnapshot := unittest.RootSnapshotFixture(participants)
+	util.RunWithFullProtocolState(t, rootSnapshot, func(db *badger.DB, state *protocol.MutableState) {
+		head, err := rootSnapshot.Head()
+		require.NoError(t, err)
+		maxByteSize := head.ChainID.MaxCollectionByteSize()
+
+		extendWithMetadata := func(transactionCount uint, byteSize uint64) error {
+			guarantee := unittest.CollectionGuaranteeFixture()
+			guarantee.ReferenceBlockID = head.ID()
+			guarantee.TransactionCount = transactionCount
+			guarantee.ByteSize = byteSize
+
+			block := unittest.BlockWithParentFixture(head)
+			block.SetPayload(flow.Payload{Guarantees: []*flow.CollectionGuarantee{guarantee}})
+			return state.Extend(&block)
+		}
+
+		t.Run("missing transaction count", func(t *testing.T) {
+			err := extendWithMetadata(0, 1024)
+			require.Error(t, err)
+			require.True(t, st.IsInvalidExtensionError(err), err)
+		})
+
+		t.Run("missing byte size", func(t *testing.T) {
+			err := extendWithMetadata(1, 0)
+			require.Error(t, err)
+			require.True(t, st.IsInvalidExtensionError(err), err)
+		})
+
+		t.Run("oversized collection", func(t *testing.T) {
+			err := extendWithMetadata(1, maxByteSize+1)
+			require.Error(t, err)
+			require.True(t, st.IsInvalidExtensionError(err), err)
+		})
+
+		t.Run("maximum byte size", func(t *testing.T) {
+			err := extendWithMetadata(1, maxByteSize)
+			require.NoError(t, err)
+		})
+	})
+}
+
 func TestExtendReceiptsNotSorted(t *testing.T) {
 	// Todo: this test needs to be updated:
 	// We don't require the receipts to be sorted by height anymore
@@ -1420,12 +1489,22 @@ func TestSealed(t *testing.T) {
 		})
 		err = state.Extend(&block3)
 		require.NoError(t, err)
+
+		// the seal is only indexed by the sealed block once it is finalized
+		var sealID flow.Identifier
+		err = db.View(operation.LookupBySealedBlockID(block1.ID(), &sealID))
+		require.True(t, errors.Is(err, stoerr.ErrNotFound))
+
 		err = state.Finalize(block3.ID())
 		require.NoError(t, err)
 
 		sealed, err := state.Sealed().Head()
 		require.NoError(t, err)
 		require.Equal(t, block1.ID(), sealed.ID())
+
+		err = db.View(operation.LookupBySealedBlockID(block1.ID(), &sealID))
+		require.NoError(t, err)
+		require.Equal(t, seal1.ID(), sealID)
 	})
 }
 
@@ -741,3 +741,47 @@ func TestSnapshot_PostSporkIdentities(t *testing.T) {
 		assert.ElementsMatch(t, expected, actual)
 	})
 }
+
+// test that the epoch builder transitions through multiple epochs using the
+// participants configured for each epoch
+func TestSnapshot_EpochBuilderMultipleEpochs(t *testing.T) {
+
+	epoch1Identities := unittest.IdentityListFixture(10, unittest.WithAllRoles())
+	epoch2Identities := unittest.IdentityListFixture(10, unittest.WithAllRoles())
+	epoch3Identities := unittest.IdentityListFixture(10, unittest.WithAllRoles())
+
+	rootSnapshot := unittest.RootSnapshotFixture(epoch1Identities)
+	util.RunWithFullProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.MutableState) {
+
+		epochBuilder := unittest.NewEpochBuilder(t, state).
+			UsingEpochParticipants(2, epoch2Identities).
+			UsingEpochParticipants(3, epoch3Identities).
+			BuildEpochs(2)
+
+		// we should be in the staking phase of epoch 3
+		counter, err := state.Final().Epochs().Current().Counter()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), counter)
+		phase, err := state.Final().Phase()
+		require.NoError(t, err)
+		assert.Equal(t, flow.EpochPhaseStaking, phase)
+
+		// each built epoch should have the configured participants
+		epoch1, ok := epochBuilder.EpochHeights(1)
+		require.True(t, ok)
+		epoch2, ok := epochBuilder.EpochHeights(2)
+		require.True(t, ok)
+
+		identities, err := state.AtHeight(epoch1.Staking).Epochs().Current().InitialIdentities()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, epoch1Identities, identities)
+
+		identities, err = state.AtHeight(epoch2.Staking).Epochs().Current().InitialIdentities()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, epoch2Identities, identities)
+
+		identities, err = state.Final().Epochs().Current().InitialIdentities()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, epoch3Identities, identities)
+	})
+}
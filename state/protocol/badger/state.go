@@ -192,6 +192,10 @@ func (state *State) bootstrapSealedResult(root protocol.Snapshot) func(*badger.T
 		if err != nil {
 			return fmt.Errorf("could not index root block seal: %w", err)
 		}
+		err = operation.IndexFinalizedSealByBlockID(seal.BlockID, seal.ID())(tx)
+		if err != nil {
+			return fmt.Errorf("could not index root seal by sealed block: %w", err)
+		}
 
 		return nil
 	}
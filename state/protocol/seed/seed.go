@@ -1,3 +1,8 @@
+// Package seed derives all per-block entropy of the protocol, like the source of randomness of
+// transactions and the seed of the chunk assignment, from the random beacon signatures in the QCs.
+// Each task derives its seed with its own indices, defined in package indices, which serve as domain
+// separation tags, so that seeds of different tasks are independent even if derived from the same
+// signature.
 package seed
 
 import (
@@ -7,7 +12,10 @@ import (
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/crypto/hash"
 	"github.com/onflow/flow-go/model/encodable"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/indices"
 	"github.com/onflow/flow-go/module/signature"
+	"github.com/onflow/flow-go/state/protocol"
 )
 
 // FromParentSignature reads the raw random seed from a combined signature.
@@ -45,3 +53,57 @@ func FromRandomSource(indices []uint32, sor []byte) ([]byte, error) {
 
 	return kmac.ComputeHash(sor), nil
 }
+
+// ExecutionRandomSource returns the source of randomness used when executing the transactions
+// of the given block. It is derived from the random beacon signature of the QC for the parent
+// block, which is included in the block header, so that execution and verification nodes
+// derive the same source without further access to the protocol state.
+func ExecutionRandomSource(header *flow.Header) ([]byte, error) {
+	return FromParentSignature(indices.ProtocolExecutionRandomSource, header.ParentVoterSig)
+}
+
+// TransactionRandomSource returns the source of randomness of the transaction with the given index and ID,
+// derived from the source of randomness of its block, so that every transaction in a block draws from
+// its own deterministic sequence.
+func TransactionRandomSource(blockRandomSource []byte, txIndex uint32, txID flow.Identifier) ([]byte, error) {
+	return FromRandomSource(indices.ProtocolExecutionTransactionRandomSource, transactionRandomSourceData(blockRandomSource, txIndex, txID))
+}
+
+// LegacyTransactionRandomSource returns the source of randomness of the transaction with the given index
+// and ID as derived before TransactionRandomSource, by hashing without domain separation. It is kept so
+// that blocks executed before the switch to TransactionRandomSource can be executed again.
+func LegacyTransactionRandomSource(blockRandomSource []byte, txIndex uint32, txID flow.Identifier) []byte {
+	return hash.NewSHA3_256().ComputeHash(transactionRandomSourceData(blockRandomSource, txIndex, txID))
+}
+
+func transactionRandomSourceData(blockRandomSource []byte, txIndex uint32, txID flow.Identifier) []byte {
+	data := make([]byte, 0, len(blockRandomSource)+4+len(txID))
+	data = append(data, blockRandomSource...)
+	data = append(data, make([]byte, 4)...)
+	binary.BigEndian.PutUint32(data[len(blockRandomSource):], txIndex)
+	data = append(data, txID[:]...)
+	return data
+}
+
+// RequesterSampling returns the seed of the selection of providers by the requester engine of the node
+// with the given ID. It is derived from the QC for the block of the given snapshot, mixed with the ID of
+// the node, so that nodes requesting the same entities spread their requests over different providers.
+func RequesterSampling(snapshot protocol.Snapshot, nodeID flow.Identifier) ([]byte, error) {
+	source, err := snapshot.Seed(indices.ProtocolRequesterSampling...)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, len(source)+len(nodeID))
+	data = append(data, source...)
+	data = append(data, nodeID[:]...)
+
+	return FromRandomSource(indices.ProtocolRequesterSampling, data)
+}
+
+// ChunkAssignment returns the seed of the assignment of the chunks of the results incorporated in
+// the block of the given snapshot to verification nodes. It is derived from the QC for the block,
+// hence it returns a state.NoValidChildBlockError if the block has no valid child yet.
+func ChunkAssignment(snapshot protocol.Snapshot) ([]byte, error) {
+	return snapshot.Seed(indices.ProtocolVerificationChunkAssignment...)
+}
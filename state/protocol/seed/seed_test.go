@@ -0,0 +1,128 @@
+package seed_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/encodable"
+	"github.com/onflow/flow-go/model/indices"
+	"github.com/onflow/flow-go/module/signature"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/state/protocol/seed"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestFromRandomSource(t *testing.T) {
+	source := unittest.RandomBytes(int(encodable.RandomBeaconSigLen))
+
+	first, err := seed.FromRandomSource(indices.ProtocolExecutionRandomSource, source)
+	require.NoError(t, err)
+	second, err := seed.FromRandomSource(indices.ProtocolExecutionRandomSource, source)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	// the indices separate the seeds of different tasks
+	other, err := seed.FromRandomSource(indices.ProtocolVerificationChunkAssignment, source)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+}
+
+func TestExecutionRandomSource(t *testing.T) {
+	beaconSig := unittest.RandomBytes(int(encodable.RandomBeaconSigLen))
+	combiner := signature.NewCombiner(encodable.ConsensusVoteSigLen, encodable.RandomBeaconSigLen)
+	combinedSig, err := combiner.Join(unittest.RandomBytes(int(encodable.ConsensusVoteSigLen)), beaconSig)
+	require.NoError(t, err)
+
+	header := unittest.BlockHeaderFixture()
+	header.ParentVoterSig = combinedSig
+
+	source, err := seed.ExecutionRandomSource(&header)
+	require.NoError(t, err)
+
+	// the source only depends on the random beacon signature
+	expected, err := seed.FromRandomSource(indices.ProtocolExecutionRandomSource, beaconSig)
+	require.NoError(t, err)
+	assert.Equal(t, expected, source)
+}
+
+func TestTransactionRandomSource(t *testing.T) {
+	blockSource := unittest.RandomBytes(32)
+	txID := unittest.IdentifierFixture()
+
+	source, err := seed.TransactionRandomSource(blockSource, 0, txID)
+	require.NoError(t, err)
+
+	t.Run("is deterministic", func(t *testing.T) {
+		again, err := seed.TransactionRandomSource(blockSource, 0, txID)
+		require.NoError(t, err)
+		assert.Equal(t, source, again)
+	})
+
+	t.Run("differs between transaction indices", func(t *testing.T) {
+		other, err := seed.TransactionRandomSource(blockSource, 1, txID)
+		require.NoError(t, err)
+		assert.NotEqual(t, source, other)
+	})
+
+	t.Run("differs between transactions", func(t *testing.T) {
+		other, err := seed.TransactionRandomSource(blockSource, 0, unittest.IdentifierFixture())
+		require.NoError(t, err)
+		assert.NotEqual(t, source, other)
+	})
+
+	t.Run("differs from the block source", func(t *testing.T) {
+		blockSeed, err := seed.FromRandomSource(indices.ProtocolExecutionRandomSource, blockSource)
+		require.NoError(t, err)
+		assert.NotEqual(t, blockSeed, source)
+	})
+}
+
+func TestLegacyTransactionRandomSource(t *testing.T) {
+	blockSource := unittest.RandomBytes(32)
+	txID := unittest.IdentifierFixture()
+
+	// the legacy source hashes the block source, the big endian transaction index and the transaction ID
+	data := append(append(append([]byte{}, blockSource...), 0, 0, 0, 7), txID[:]...)
+	expected := hash.NewSHA3_256().ComputeHash(data)
+	assert.Equal(t, expected, seed.LegacyTransactionRandomSource(blockSource, 7, txID))
+
+	source, err := seed.TransactionRandomSource(blockSource, 7, txID)
+	require.NoError(t, err)
+	assert.NotEqual(t, []byte(expected), source)
+}
+
+func TestRequesterSampling(t *testing.T) {
+	snapshot := &protocol.Snapshot{}
+	snapshot.On("Seed", indices.ProtocolRequesterSampling[0], indices.ProtocolRequesterSampling[1], indices.ProtocolRequesterSampling[2]).
+		Return(unittest.RandomBytes(32), nil)
+
+	nodeID := unittest.IdentifierFixture()
+	first, err := seed.RequesterSampling(snapshot, nodeID)
+	require.NoError(t, err)
+
+	again, err := seed.RequesterSampling(snapshot, nodeID)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	// nodes requesting at the same block sample with different seeds
+	other, err := seed.RequesterSampling(snapshot, unittest.IdentifierFixture())
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+}
+
+func TestChunkAssignment(t *testing.T) {
+	expected := unittest.RandomBytes(32)
+
+	snapshot := &protocol.Snapshot{}
+	snapshot.On("Seed", indices.ProtocolVerificationChunkAssignment[0], indices.ProtocolVerificationChunkAssignment[1], indices.ProtocolVerificationChunkAssignment[2]).
+		Return(expected, nil).
+		Once()
+
+	assignmentSeed, err := seed.ChunkAssignment(snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, expected, assignmentSeed)
+	snapshot.AssertExpectations(t)
+}
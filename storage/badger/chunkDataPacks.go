@@ -1,10 +1,14 @@
 package badger
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/dgraph-io/badger/v2"
 
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
@@ -13,6 +17,12 @@ import (
 	"github.com/onflow/flow-go/storage/badger/transaction"
 )
 
+// ChunkDataPacks stores the chunk data packs of execution nodes. The leaf nodes of the trie proofs of
+// the chunk data packs are stored content-addressed, so that the payloads of the registers proven by
+// several chunk data packs, e.g. by the chunks of a block against consecutive states, are stored only
+// once. The interim nodes of the proofs are kept in the chunk data packs, as they are hashes themselves.
+// Each proof node is reference counted by the chunk data packs referencing it, and removed along with
+// the last of them.
 type ChunkDataPacks struct {
 	db             *badger.DB
 	byChunkIDCache *Cache
@@ -22,16 +32,42 @@ func NewChunkDataPacks(collector module.CacheMetrics, db *badger.DB, byChunkIDCa
 
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		chdp := val.(*flow.ChunkDataPack)
-		return transaction.WithTx(operation.SkipDuplicates(operation.InsertChunkDataPack(chdp)))
+		stored, nodes := splitChunkDataPack(chdp)
+		return func(tx *transaction.Tx) error {
+			// the references of a chunk data pack stored already must not be added twice
+			var existing operation.StoredChunkDataPack
+			err := operation.RetrieveChunkDataPack(chdp.ChunkID, &existing)(tx.DBTxn)
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, storage.ErrNotFound) {
+				return fmt.Errorf("could not check chunk data pack: %w", err)
+			}
+
+			for _, node := range nodes {
+				err := operation.SkipDuplicates(operation.InsertChunkDataPackProofNode(node.id, node.encoded))(tx.DBTxn)
+				if err != nil {
+					return fmt.Errorf("could not store proof node %x: %w", node.id, err)
+				}
+				err = operation.IndexChunkDataPackProofNode(node.id, chdp.ChunkID)(tx.DBTxn)
+				if err != nil {
+					return fmt.Errorf("could not reference proof node %x: %w", node.id, err)
+				}
+			}
+			return operation.InsertChunkDataPack(stored)(tx.DBTxn)
+		}
 	}
 
 	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
 		chunkID := key.(flow.Identifier)
 
-		var c flow.ChunkDataPack
+		var c operation.StoredChunkDataPack
 		return func(tx *badger.Txn) (interface{}, error) {
 			err := operation.RetrieveChunkDataPack(chunkID, &c)(tx)
-			return &c, err
+			if err != nil {
+				return nil, err
+			}
+			return assembleChunkDataPack(tx, &c)
 		}
 	}
 
@@ -56,8 +92,10 @@ func (ch *ChunkDataPacks) Store(c *flow.ChunkDataPack) error {
 	return nil
 }
 
+// Remove removes the chunk data pack with the given chunk ID, along with the proof nodes which are
+// not referenced by any other chunk data pack.
 func (ch *ChunkDataPacks) Remove(chunkID flow.Identifier) error {
-	err := operation.RetryOnConflict(ch.db.Update, operation.RemoveChunkDataPack(chunkID))
+	err := operation.RetryOnConflict(ch.db.Update, removeChunkDataPack(chunkID))
 	if err != nil {
 		return fmt.Errorf("could not remove chunk datapack: %w", err)
 	}
@@ -71,7 +109,18 @@ func (ch *ChunkDataPacks) BatchStore(c *flow.ChunkDataPack, batch storage.BatchS
 	batch.OnSucceed(func() {
 		ch.byChunkIDCache.Insert(c.ChunkID, c)
 	})
-	return operation.BatchInsertChunkDataPack(c)(writeBatch)
+	stored, nodes := splitChunkDataPack(c)
+	for _, node := range nodes {
+		err := operation.BatchInsertChunkDataPackProofNode(node.id, node.encoded)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("could not store proof node %x: %w", node.id, err)
+		}
+		err = operation.BatchIndexChunkDataPackProofNode(node.id, c.ChunkID)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("could not reference proof node %x: %w", node.id, err)
+		}
+	}
+	return operation.BatchInsertChunkDataPack(stored)(writeBatch)
 }
 
 func (ch *ChunkDataPacks) ByChunkID(chunkID flow.Identifier) (*flow.ChunkDataPack, error) {
@@ -89,3 +138,141 @@ func (ch *ChunkDataPacks) retrieveCHDP(chunkID flow.Identifier) func(*badger.Txn
 		return val.(*flow.ChunkDataPack), nil
 	}
 }
+
+// proofNode is an encoded leaf node of the trie proofs of a chunk data pack along with its hash.
+type proofNode struct {
+	id      flow.Identifier
+	encoded []byte
+}
+
+// splitChunkDataPack splits the proof of the chunk data pack into its trie proofs and splits off
+// the leaf nodes of the trie proofs, returning the stored form of the chunk data pack, which
+// references the leaf nodes by hash, along with the distinct leaf nodes. The proof is kept as is if
+// it can't be decoded into trie proofs which encode back to the very same proof, as the checksum of
+// the chunk data pack covers its proof.
+func splitChunkDataPack(c *flow.ChunkDataPack) (*operation.StoredChunkDataPack, []proofNode) {
+	stored := &operation.StoredChunkDataPack{
+		ChunkID:      c.ChunkID,
+		StartState:   c.StartState,
+		CollectionID: c.CollectionID,
+	}
+
+	batchProof, err := encoding.DecodeTrieBatchProof(c.Proof)
+	if err != nil || len(batchProof.Proofs) == 0 || !bytes.Equal(encoding.EncodeTrieBatchProof(batchProof), c.Proof) {
+		stored.Proof = c.Proof
+		return stored, nil
+	}
+
+	nodes := make([]proofNode, 0, len(batchProof.Proofs))
+	seen := make(map[flow.Identifier]struct{}, len(batchProof.Proofs))
+	stored.Proofs = make([]operation.StoredTrieProof, 0, len(batchProof.Proofs))
+	for _, p := range batchProof.Proofs {
+		// the payloads of exclusion proofs are empty, there is nothing to share
+		if p.Payload.IsEmpty() {
+			stored.Proofs = append(stored.Proofs, operation.StoredTrieProof{Proof: encoding.EncodeTrieProof(p)})
+			continue
+		}
+
+		encoded := encoding.EncodePayload(p.Payload)
+		nodeID := flow.MakeID(encoded)
+
+		withoutPayload := *p
+		withoutPayload.Payload = ledger.EmptyPayload()
+		stored.Proofs = append(stored.Proofs, operation.StoredTrieProof{
+			Proof:  encoding.EncodeTrieProof(&withoutPayload),
+			NodeID: nodeID,
+		})
+
+		if _, ok := seen[nodeID]; ok {
+			continue
+		}
+		seen[nodeID] = struct{}{}
+		nodes = append(nodes, proofNode{id: nodeID, encoded: encoded})
+	}
+
+	return stored, nodes
+}
+
+// assembleChunkDataPack retrieves the proof nodes referenced by the stored chunk data pack and
+// reassembles the chunk data pack from them.
+func assembleChunkDataPack(tx *badger.Txn, stored *operation.StoredChunkDataPack) (*flow.ChunkDataPack, error) {
+	c := &flow.ChunkDataPack{
+		ChunkID:      stored.ChunkID,
+		StartState:   stored.StartState,
+		Proof:        stored.Proof,
+		CollectionID: stored.CollectionID,
+	}
+	if len(stored.Proofs) == 0 {
+		return c, nil
+	}
+
+	batchProof := ledger.NewTrieBatchProof()
+	for i, storedProof := range stored.Proofs {
+		p, err := encoding.DecodeTrieProof(storedProof.Proof)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode proof %d: %w", i, err)
+		}
+
+		if storedProof.NodeID != flow.ZeroID {
+			var encoded []byte
+			err = operation.RetrieveChunkDataPackProofNode(storedProof.NodeID, &encoded)(tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not retrieve proof node %x: %w", storedProof.NodeID, err)
+			}
+			p.Payload, err = encoding.DecodePayload(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode proof node %x: %w", storedProof.NodeID, err)
+			}
+		}
+
+		batchProof.Proofs = append(batchProof.Proofs, p)
+	}
+	c.Proof = encoding.EncodeTrieBatchProof(batchProof)
+
+	return c, nil
+}
+
+// removeChunkDataPack removes the stored chunk data pack with the given chunk ID along with its
+// references to proof nodes, and removes the proof nodes which are not referenced anymore.
+func removeChunkDataPack(chunkID flow.Identifier) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var stored operation.StoredChunkDataPack
+		err := operation.RetrieveChunkDataPack(chunkID, &stored)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve chunk data pack: %w", err)
+		}
+
+		removed := make(map[flow.Identifier]struct{}, len(stored.Proofs))
+		for _, storedProof := range stored.Proofs {
+			nodeID := storedProof.NodeID
+			if nodeID == flow.ZeroID {
+				continue
+			}
+			if _, ok := removed[nodeID]; ok {
+				continue
+			}
+			removed[nodeID] = struct{}{}
+
+			err = operation.RemoveChunkDataPackProofNodeReference(nodeID, chunkID)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove reference to proof node %x: %w", nodeID, err)
+			}
+
+			var references []flow.Identifier
+			err = operation.LookupChunkDataPackProofNodeReferences(nodeID, &references)(tx)
+			if err != nil {
+				return fmt.Errorf("could not look up references to proof node %x: %w", nodeID, err)
+			}
+			if len(references) > 0 {
+				continue
+			}
+
+			err = operation.RemoveChunkDataPackProofNode(nodeID)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove proof node %x: %w", nodeID, err)
+			}
+		}
+
+		return operation.RemoveChunkDataPack(chunkID)(tx)
+	}
+}
@@ -0,0 +1,220 @@
+package badger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// ChunkDataPackCache is a size-bounded cache of the chunk data packs of recent blocks. It is held on
+// disk, in a database dedicated to the cache, so that the chunk data pack requests for recent blocks
+// are served without retaining chunk data packs in memory, nor searching the chunk data packs of the
+// whole history. Only the heights, IDs and sizes of the cached chunk data packs are kept in memory, to
+// evict the chunk data packs of the lowest heights when the cache exceeds its size.
+//
+// The chunk data packs held by the cache are not stored in the database of the node. The chunk data
+// packs evicted from the cache are moved to the chunk data pack storage of the node, as they may be
+// requested until their blocks are sealed, while the chunk data packs of sealed blocks are dropped.
+type ChunkDataPackCache struct {
+	mu       sync.Mutex
+	log      zerolog.Logger
+	db       *badger.DB
+	evicted  storage.ChunkDataPacks       // storage of the chunk data packs evicted from the cache
+	maxSize  uint64                       // maximum total size of the cached chunk data packs, in bytes
+	size     uint64                       // total size of the cached chunk data packs, in bytes
+	heights  []uint64                     // heights with cached chunk data packs, in ascending order
+	byHeight map[uint64][]cachedChunk     // cached chunk data packs by height
+	cached   map[flow.Identifier]struct{} // IDs of the cached chunk data packs
+}
+
+type cachedChunk struct {
+	chunkID flow.Identifier
+	size    uint64
+}
+
+// NewChunkDataPackCache creates a cache of chunk data packs of the given maximum size in bytes, over
+// the given dedicated database, which is closed when the cache is done. The chunk data packs cached
+// in the database are kept. The chunk data packs evicted from the cache are moved to the given
+// storage.
+func NewChunkDataPackCache(log zerolog.Logger, db *badger.DB, maxSize uint64, evicted storage.ChunkDataPacks) (*ChunkDataPackCache, error) {
+	c := &ChunkDataPackCache{
+		log:      log.With().Str("component", "chunk_data_pack_cache").Logger(),
+		db:       db,
+		evicted:  evicted,
+		maxSize:  maxSize,
+		byHeight: make(map[uint64][]cachedChunk),
+		cached:   make(map[flow.Identifier]struct{}),
+	}
+
+	err := db.View(operation.TraverseCachedChunkDataPacks(func(height uint64, chunkID flow.Identifier, size uint64) error {
+		c.add(height, chunkID, size)
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("could not load index of cached chunk data packs: %w", err)
+	}
+
+	return c, nil
+}
+
+// Ready returns a channel which is closed once the cache is ready, which it is from its creation.
+func (c *ChunkDataPackCache) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
+// Done returns a channel which is closed once the database of the cache is closed.
+func (c *ChunkDataPackCache) Done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		err := c.db.Close()
+		if err != nil {
+			c.log.Error().Err(err).Msg("could not close chunk data pack cache database")
+		}
+	}()
+	return done
+}
+
+func (c *ChunkDataPackCache) Store(height uint64, chunkDataPacks []*flow.ChunkDataPack) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the chunk data packs of a block are written in a single batch, which, unlike a transaction, is
+	// not limited in size by the proofs of the block
+	batch := NewBatch(c.db)
+	writer := batch.GetWriter()
+	added := make([]cachedChunk, 0, len(chunkDataPacks))
+	for _, chunkDataPack := range chunkDataPacks {
+		if _, ok := c.cached[chunkDataPack.ChunkID]; ok {
+			continue
+		}
+
+		size := chunkDataPackSize(chunkDataPack)
+		err := operation.BatchInsertCachedChunkDataPack(chunkDataPack)(writer)
+		if err != nil {
+			return fmt.Errorf("could not insert chunk data pack %x: %w", chunkDataPack.ChunkID, err)
+		}
+		err = operation.BatchIndexCachedChunkDataPack(height, chunkDataPack.ChunkID, size)(writer)
+		if err != nil {
+			return fmt.Errorf("could not index chunk data pack %x: %w", chunkDataPack.ChunkID, err)
+		}
+		added = append(added, cachedChunk{chunkID: chunkDataPack.ChunkID, size: size})
+	}
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not cache chunk data packs: %w", err)
+	}
+	for _, chunk := range added {
+		c.add(height, chunk.chunkID, chunk.size)
+	}
+
+	// the chunk data packs of the stored height, and of the heights above, are never evicted
+	for c.size > c.maxSize && len(c.heights) > 0 && c.heights[0] < height {
+		err := c.removeHeight(c.heights[0], true)
+		if err != nil {
+			return fmt.Errorf("could not evict chunk data packs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *ChunkDataPackCache) ByChunkID(chunkID flow.Identifier) (*flow.ChunkDataPack, error) {
+	var chunkDataPack flow.ChunkDataPack
+	err := c.db.View(operation.RetrieveCachedChunkDataPack(chunkID, &chunkDataPack))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve cached chunk data pack: %w", err)
+	}
+	return &chunkDataPack, nil
+}
+
+func (c *ChunkDataPackCache) PruneUpToHeight(height uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.heights) > 0 && c.heights[0] <= height {
+		err := c.removeHeight(c.heights[0], false)
+		if err != nil {
+			return fmt.Errorf("could not prune chunk data packs: %w", err)
+		}
+	}
+	return nil
+}
+
+// Size returns the total size of the cached chunk data packs, in bytes.
+func (c *ChunkDataPackCache) Size() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// add adds the chunk data pack to the in-memory index of the cache.
+func (c *ChunkDataPackCache) add(height uint64, chunkID flow.Identifier, size uint64) {
+	if _, ok := c.byHeight[height]; !ok {
+		i := sort.Search(len(c.heights), func(i int) bool { return c.heights[i] > height })
+		c.heights = append(c.heights, 0)
+		copy(c.heights[i+1:], c.heights[i:])
+		c.heights[i] = height
+	}
+	c.byHeight[height] = append(c.byHeight[height], cachedChunk{chunkID: chunkID, size: size})
+	c.cached[chunkID] = struct{}{}
+	c.size += size
+}
+
+// removeHeight removes the chunk data packs of the given height, which must be the lowest height of
+// the cache, moving them to the storage of evicted chunk data packs if evict is set.
+func (c *ChunkDataPackCache) removeHeight(height uint64, evict bool) error {
+	batch := NewBatch(c.db)
+	writer := batch.GetWriter()
+	for _, chunk := range c.byHeight[height] {
+		if evict {
+			var chunkDataPack flow.ChunkDataPack
+			err := c.db.View(operation.RetrieveCachedChunkDataPack(chunk.chunkID, &chunkDataPack))
+			if err != nil {
+				return fmt.Errorf("could not retrieve cached chunk data pack %x: %w", chunk.chunkID, err)
+			}
+			err = c.evicted.Store(&chunkDataPack)
+			if err != nil {
+				return fmt.Errorf("could not store evicted chunk data pack %x: %w", chunk.chunkID, err)
+			}
+		}
+
+		err := operation.BatchRemoveCachedChunkDataPack(chunk.chunkID)(writer)
+		if err != nil {
+			return fmt.Errorf("could not remove chunk data pack %x: %w", chunk.chunkID, err)
+		}
+		err = operation.BatchRemoveCachedChunkDataPackIndex(height, chunk.chunkID)(writer)
+		if err != nil {
+			return fmt.Errorf("could not remove index of chunk data pack %x: %w", chunk.chunkID, err)
+		}
+	}
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not remove cached chunk data packs: %w", err)
+	}
+
+	for _, chunk := range c.byHeight[height] {
+		delete(c.cached, chunk.chunkID)
+		c.size -= chunk.size
+	}
+	delete(c.byHeight, height)
+	c.heights = c.heights[1:]
+	return nil
+}
+
+// chunkDataPackSize approximates the size of a chunk data pack by the size of its proof, which
+// dominates it, and of its identifiers.
+func chunkDataPackSize(c *flow.ChunkDataPack) uint64 {
+	return uint64(len(c.Proof) + len(c.ChunkID) + len(c.StartState) + len(c.CollectionID))
+}
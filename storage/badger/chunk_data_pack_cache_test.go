@@ -0,0 +1,119 @@
+package badger_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestChunkDataPackCache(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(nodeDB *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			db := unittest.BadgerDB(t, dir)
+			chunkDataPacks := badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, nodeDB, 100)
+
+			// chunk data packs of 1000 bytes of proof, so that the cache holds the chunk data packs of two
+			// blocks of two chunks each
+			packs := make(map[uint64][]*flow.ChunkDataPack)
+			for height := uint64(10); height < 14; height++ {
+				for i := 0; i < 2; i++ {
+					pack := unittest.ChunkDataPackFixture(unittest.IdentifierFixture(), func(cdp *flow.ChunkDataPack) {
+						cdp.Proof = make([]byte, 1000)
+					})
+					packs[height] = append(packs[height], pack)
+				}
+			}
+			size := uint64(1000 + 3*32)
+
+			cache, err := badgerstorage.NewChunkDataPackCache(zerolog.Nop(), db, 4*size, chunkDataPacks)
+			require.NoError(t, err)
+
+			_, err = cache.ByChunkID(unittest.IdentifierFixture())
+			assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+			cached := func(height uint64) bool {
+				for _, pack := range packs[height] {
+					actual, err := cache.ByChunkID(pack.ChunkID)
+					if errors.Is(err, storage.ErrNotFound) {
+						return false
+					}
+					require.NoError(t, err)
+					require.Equal(t, pack, actual)
+				}
+				return true
+			}
+
+			// stored reports whether the chunk data packs of the height are held by the storage of the node
+			stored := func(height uint64) bool {
+				for _, pack := range packs[height] {
+					actual, err := chunkDataPacks.ByChunkID(pack.ChunkID)
+					if errors.Is(err, storage.ErrNotFound) {
+						return false
+					}
+					require.NoError(t, err)
+					require.Equal(t, pack, actual)
+				}
+				return true
+			}
+
+			t.Run("evicts lowest heights to storage", func(t *testing.T) {
+				for height := uint64(10); height < 13; height++ {
+					require.NoError(t, cache.Store(height, packs[height]))
+				}
+				// storing again is a no-op
+				require.NoError(t, cache.Store(12, packs[12]))
+
+				assert.False(t, cached(10))
+				assert.True(t, stored(10))
+				assert.True(t, cached(11))
+				assert.False(t, stored(11))
+				assert.True(t, cached(12))
+				assert.False(t, stored(12))
+				assert.Equal(t, 4*size, cache.Size())
+			})
+
+			t.Run("restores index from database", func(t *testing.T) {
+				var err error
+				cache, err = badgerstorage.NewChunkDataPackCache(zerolog.Nop(), db, 4*size, chunkDataPacks)
+				require.NoError(t, err)
+				assert.Equal(t, 4*size, cache.Size())
+
+				require.NoError(t, cache.Store(13, packs[13]))
+				assert.False(t, cached(11))
+				assert.True(t, stored(11))
+				assert.True(t, cached(12))
+				assert.True(t, cached(13))
+			})
+
+			t.Run("drops pruned heights", func(t *testing.T) {
+				require.NoError(t, cache.PruneUpToHeight(12))
+				assert.False(t, cached(12))
+				assert.False(t, stored(12))
+				assert.True(t, cached(13))
+				assert.Equal(t, 2*size, cache.Size())
+			})
+
+			t.Run("closes database when done", func(t *testing.T) {
+				unittest.AssertClosesBefore(t, cache.Done(), time.Second)
+
+				// the database can only be opened again once it is closed
+				db = unittest.BadgerDB(t, dir)
+				defer db.Close()
+				cache, err = badgerstorage.NewChunkDataPackCache(zerolog.Nop(), db, 4*size, chunkDataPacks)
+				require.NoError(t, err)
+				assert.True(t, cached(13))
+			})
+		})
+	})
+}
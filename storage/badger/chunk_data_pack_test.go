@@ -6,9 +6,15 @@ import (
 
 	"github.com/dgraph-io/badger/v2"
 
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/utils/unittest"
 
 	"github.com/stretchr/testify/assert"
@@ -39,3 +45,185 @@ func TestChunkDataPack(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+// TestChunkDataPack_SharedProofs verifies that the proof nodes shared by chunk data packs are
+// stored only once, while the chunk data packs are retrieved with their full proofs.
+func TestChunkDataPack_SharedProofs(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, db, 100)
+
+		shared, _ := utils.TrieProofFixture()
+		other, _ := utils.TrieProofFixture()
+		other.Path = utils.PathByUint16(331)
+
+		// the first chunk data pack touches the shared register twice
+		first := unittest.ChunkDataPackFixture(unittest.IdentifierFixture(), func(cdp *flow.ChunkDataPack) {
+			cdp.Proof = encoding.EncodeTrieBatchProof(&ledger.TrieBatchProof{Proofs: []*ledger.TrieProof{shared, shared}})
+		})
+		second := unittest.ChunkDataPackFixture(unittest.IdentifierFixture(), func(cdp *flow.ChunkDataPack) {
+			cdp.Proof = encoding.EncodeTrieBatchProof(&ledger.TrieBatchProof{Proofs: []*ledger.TrieProof{other, shared}})
+		})
+
+		require.NoError(t, store.Store(first))
+		batch := badgerstorage.NewBatch(db)
+		require.NoError(t, store.BatchStore(second, batch))
+		require.NoError(t, batch.Flush())
+
+		// chunk data packs are retrieved from the database rather than the cache
+		store = badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, db, 100)
+		for _, expected := range []*flow.ChunkDataPack{first, second} {
+			actual, err := store.ByChunkID(expected.ChunkID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+			assert.Equal(t, expected.Checksum(), actual.Checksum())
+		}
+
+		sharedNodeID := flow.MakeID(encoding.EncodePayload(shared.Payload))
+		var stored operation.StoredChunkDataPack
+		require.NoError(t, db.View(operation.RetrieveChunkDataPack(second.ChunkID, &stored)))
+		assert.Empty(t, stored.Proof)
+		require.Len(t, stored.Proofs, 2)
+		assert.Equal(t, sharedNodeID, stored.Proofs[1].NodeID)
+
+		// the shared node is referenced once by each chunk data pack
+		var references []flow.Identifier
+		require.NoError(t, db.View(operation.LookupChunkDataPackProofNodeReferences(sharedNodeID, &references)))
+		assert.ElementsMatch(t, []flow.Identifier{first.ChunkID, second.ChunkID}, references)
+
+		// the shared node is kept when a chunk data pack sharing it is removed
+		require.NoError(t, store.Remove(first.ChunkID))
+		_, err := store.ByChunkID(first.ChunkID)
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+		actual, err := store.ByChunkID(second.ChunkID)
+		require.NoError(t, err)
+		assert.Equal(t, second, actual)
+
+		// and removed along with the last chunk data pack referencing it
+		require.NoError(t, store.Remove(second.ChunkID))
+		var node []byte
+		err = db.View(operation.RetrieveChunkDataPackProofNode(sharedNodeID, &node))
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+	})
+}
+
+// TestChunkDataPack_ConsecutiveTries verifies that the chunk data packs proving the same registers
+// against consecutive tries share the leaf nodes of the registers which were not updated, even though
+// the trie proofs differ, and that removing the chunk data packs removes the nodes they don't share.
+func TestChunkDataPack_ConsecutiveTries(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, db, 100)
+
+		paths := make([]ledger.Path, 0, 8)
+		payloads := make([]ledger.Payload, 0, 8)
+		for i := uint16(0); i < 8; i++ {
+			paths = append(paths, utils.PathByUint16(i<<12))
+			payloads = append(payloads, *utils.LightPayload(i, i))
+		}
+		before, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads)
+		require.NoError(t, err)
+		updated := utils.LightPayload(0, 100)
+		after, err := trie.NewTrieWithUpdatedRegisters(before, paths[:1], []ledger.Payload{*updated})
+		require.NoError(t, err)
+
+		first := unittest.ChunkDataPackFixture(unittest.IdentifierFixture(), func(cdp *flow.ChunkDataPack) {
+			cdp.Proof = encoding.EncodeTrieBatchProof(proveRegisters(before, paths))
+		})
+		second := unittest.ChunkDataPackFixture(unittest.IdentifierFixture(), func(cdp *flow.ChunkDataPack) {
+			cdp.Proof = encoding.EncodeTrieBatchProof(proveRegisters(after, paths))
+		})
+		require.NoError(t, store.Store(first))
+		require.NoError(t, store.Store(second))
+
+		nodeExists := func(payload *ledger.Payload) bool {
+			var node []byte
+			err := db.View(operation.RetrieveChunkDataPackProofNode(flow.MakeID(encoding.EncodePayload(payload)), &node))
+			if errors.Is(err, storage.ErrNotFound) {
+				return false
+			}
+			require.NoError(t, err)
+			return true
+		}
+
+		// the proofs of the registers which were not updated differ between the tries, their leaves don't
+		var storedFirst, storedSecond operation.StoredChunkDataPack
+		require.NoError(t, db.View(operation.RetrieveChunkDataPack(first.ChunkID, &storedFirst)))
+		require.NoError(t, db.View(operation.RetrieveChunkDataPack(second.ChunkID, &storedSecond)))
+		require.Len(t, storedFirst.Proofs, len(paths))
+		require.Len(t, storedSecond.Proofs, len(paths))
+		firstNodes := make(map[flow.Identifier][]byte)
+		for _, p := range storedFirst.Proofs {
+			firstNodes[p.NodeID] = p.Proof
+		}
+		shared := 0
+		for _, p := range storedSecond.Proofs {
+			proof, ok := firstNodes[p.NodeID]
+			if !ok {
+				continue
+			}
+			shared++
+			assert.NotEqual(t, proof, p.Proof)
+
+			var references []flow.Identifier
+			require.NoError(t, db.View(operation.LookupChunkDataPackProofNodeReferences(p.NodeID, &references)))
+			assert.ElementsMatch(t, []flow.Identifier{first.ChunkID, second.ChunkID}, references)
+		}
+		assert.Equal(t, len(paths)-1, shared)
+
+		for _, expected := range []*flow.ChunkDataPack{first, second} {
+			actual, err := badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, db, 100).ByChunkID(expected.ChunkID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		}
+
+		// removing the first chunk data pack removes only the leaf of the updated register it proves
+		require.NoError(t, store.Remove(first.ChunkID))
+		assert.False(t, nodeExists(&payloads[0]))
+		assert.True(t, nodeExists(updated))
+		for i := range payloads[1:] {
+			assert.True(t, nodeExists(&payloads[i+1]))
+		}
+		actual, err := badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, db, 100).ByChunkID(second.ChunkID)
+		require.NoError(t, err)
+		assert.Equal(t, second, actual)
+
+		// removing the second chunk data pack removes all remaining leaves
+		require.NoError(t, store.Remove(second.ChunkID))
+		assert.False(t, nodeExists(updated))
+		for i := range payloads {
+			assert.False(t, nodeExists(&payloads[i]))
+		}
+	})
+}
+
+// proveRegisters returns the batch proof of the registers with the given paths in the given trie.
+func proveRegisters(mt *trie.MTrie, paths []ledger.Path) *ledger.TrieBatchProof {
+	// proving permutes the paths in place
+	paths = append([]ledger.Path{}, paths...)
+	batchProof := ledger.NewTrieBatchProofWithEmptyProofs(len(paths))
+	for _, p := range batchProof.Proofs {
+		p.Flags = make([]byte, ledger.PathLen)
+		p.Inclusion = false
+	}
+	mt.UnsafeProofs(paths, batchProof.Proofs)
+	return batchProof
+}
+
+// TestChunkDataPack_UndecodableProof verifies that chunk data packs with proofs which are not trie
+// batch proofs are stored as is.
+func TestChunkDataPack_UndecodableProof(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, db, 100)
+
+		expected := unittest.ChunkDataPackFixture(unittest.IdentifierFixture())
+		require.NoError(t, store.Store(expected))
+
+		var stored operation.StoredChunkDataPack
+		require.NoError(t, db.View(operation.RetrieveChunkDataPack(expected.ChunkID, &stored)))
+		assert.Equal(t, expected.Proof, stored.Proof)
+		assert.Empty(t, stored.Proofs)
+
+		actual, err := store.ByChunkID(expected.ChunkID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
@@ -0,0 +1,56 @@
+package badger
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// ExecutionJournal persists the checkpoints of the executed collections of blocks being executed.
+type ExecutionJournal struct {
+	db *badger.DB
+}
+
+func NewExecutionJournal(db *badger.DB) *ExecutionJournal {
+	return &ExecutionJournal{
+		db: db,
+	}
+}
+
+func (j *ExecutionJournal) Checkpoint(blockID flow.Identifier, checkpoint *execution.CollectionCheckpoint) error {
+	err := operation.RetryOnConflict(j.db.Update, operation.InsertCollectionCheckpoint(blockID, checkpoint))
+	if err != nil {
+		return fmt.Errorf("could not insert checkpoint of collection %d: %w", checkpoint.CollectionIndex, err)
+	}
+	return nil
+}
+
+func (j *ExecutionJournal) Checkpoints(blockID flow.Identifier) ([]*execution.CollectionCheckpoint, error) {
+	var checkpoints []*execution.CollectionCheckpoint
+	err := j.db.View(operation.LookupCollectionCheckpoints(blockID, &checkpoints))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve checkpoints: %w", err)
+	}
+	return checkpoints, nil
+}
+
+func (j *ExecutionJournal) Discard(blockID flow.Identifier) error {
+	return operation.RetryOnConflict(j.db.Update, func(tx *badger.Txn) error {
+		var checkpoints []*execution.CollectionCheckpoint
+		err := operation.LookupCollectionCheckpoints(blockID, &checkpoints)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve checkpoints: %w", err)
+		}
+		for _, checkpoint := range checkpoints {
+			err = operation.RemoveCollectionCheckpoint(blockID, checkpoint.CollectionIndex)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove checkpoint of collection %d: %w", checkpoint.CollectionIndex, err)
+			}
+		}
+		return nil
+	})
+}
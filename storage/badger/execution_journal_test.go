@@ -0,0 +1,56 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestExecutionJournal(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		journal := bstorage.NewExecutionJournal(db)
+
+		blockID := unittest.IdentifierFixture()
+		otherBlockID := unittest.IdentifierFixture()
+
+		for i := 0; i < 3; i++ {
+			err := journal.Checkpoint(blockID, &execution.CollectionCheckpoint{
+				CollectionIndex:      i,
+				NextTransactionIndex: uint32(2 * (i + 1)),
+				StateSnapshot:        &delta.SpockSnapshot{SpockSecret: []byte{byte(i)}},
+			})
+			require.NoError(t, err)
+		}
+		err := journal.Checkpoint(otherBlockID, &execution.CollectionCheckpoint{
+			StateSnapshot: &delta.SpockSnapshot{},
+		})
+		require.NoError(t, err)
+
+		checkpoints, err := journal.Checkpoints(blockID)
+		require.NoError(t, err)
+		require.Len(t, checkpoints, 3)
+		for i, checkpoint := range checkpoints {
+			require.Equal(t, i, checkpoint.CollectionIndex)
+			require.Equal(t, uint32(2*(i+1)), checkpoint.NextTransactionIndex)
+			require.Equal(t, []byte{byte(i)}, checkpoint.StateSnapshot.SpockSecret)
+		}
+
+		// discarding the checkpoints of a block keeps the checkpoints of other blocks
+		err = journal.Discard(blockID)
+		require.NoError(t, err)
+
+		checkpoints, err = journal.Checkpoints(blockID)
+		require.NoError(t, err)
+		require.Empty(t, checkpoints)
+
+		checkpoints, err = journal.Checkpoints(otherBlockID)
+		require.NoError(t, err)
+		require.Len(t, checkpoints, 1)
+	})
+}
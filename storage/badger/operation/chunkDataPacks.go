@@ -6,18 +6,36 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 )
 
+// StoredChunkDataPack is the stored form of a chunk data pack. The leaf nodes of the trie proofs
+// of the chunk data pack are stored separately, keyed by their hash, so that the leaves proven by
+// several chunk data packs, e.g. against consecutive states, are stored only once.
+type StoredChunkDataPack struct {
+	ChunkID      flow.Identifier
+	StartState   flow.StateCommitment
+	Proof        flow.StorageProof // proof of the chunk data pack, if it is not split into Proofs
+	Proofs       []StoredTrieProof // trie proofs the proof of the chunk data pack is made of
+	CollectionID flow.Identifier
+}
+
+// StoredTrieProof is the stored form of a trie proof of a chunk data pack. The proof is encoded
+// without its payload, which is stored as a proof node referenced by NodeID, unless it is empty.
+type StoredTrieProof struct {
+	Proof  []byte
+	NodeID flow.Identifier
+}
+
 // InsertChunkDataPack inserts a chunk data pack keyed by chunk ID.
-func InsertChunkDataPack(c *flow.ChunkDataPack) func(*badger.Txn) error {
+func InsertChunkDataPack(c *StoredChunkDataPack) func(*badger.Txn) error {
 	return insert(makePrefix(codeChunkDataPack, c.ChunkID), c)
 }
 
 // BatchInsertChunkDataPack upserts a chunk data pack keyed by chunk ID into a batch
-func BatchInsertChunkDataPack(c *flow.ChunkDataPack) func(batch *badger.WriteBatch) error {
+func BatchInsertChunkDataPack(c *StoredChunkDataPack) func(batch *badger.WriteBatch) error {
 	return batchInsert(makePrefix(codeChunkDataPack, c.ChunkID), c)
 }
 
 // RetrieveChunkDataPack retrieves a chunk data pack by chunk ID.
-func RetrieveChunkDataPack(chunkID flow.Identifier, c *flow.ChunkDataPack) func(*badger.Txn) error {
+func RetrieveChunkDataPack(chunkID flow.Identifier, c *StoredChunkDataPack) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeChunkDataPack, chunkID), c)
 }
 
@@ -25,3 +43,52 @@ func RetrieveChunkDataPack(chunkID flow.Identifier, c *flow.ChunkDataPack) func(
 func RemoveChunkDataPack(chunkID flow.Identifier) func(*badger.Txn) error {
 	return remove(makePrefix(codeChunkDataPack, chunkID))
 }
+
+// InsertChunkDataPackProofNode inserts an encoded leaf node of the trie proofs of chunk data packs
+// keyed by its hash.
+func InsertChunkDataPackProofNode(nodeID flow.Identifier, node []byte) func(*badger.Txn) error {
+	return insert(makePrefix(codeChunkDataPackProofNode, nodeID), node)
+}
+
+// BatchInsertChunkDataPackProofNode upserts an encoded leaf node of the trie proofs of chunk data
+// packs keyed by its hash into a batch.
+func BatchInsertChunkDataPackProofNode(nodeID flow.Identifier, node []byte) func(batch *badger.WriteBatch) error {
+	return batchInsert(makePrefix(codeChunkDataPackProofNode, nodeID), node)
+}
+
+// RetrieveChunkDataPackProofNode retrieves an encoded leaf node of the trie proofs of chunk data
+// packs by its hash.
+func RetrieveChunkDataPackProofNode(nodeID flow.Identifier, node *[]byte) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeChunkDataPackProofNode, nodeID), node)
+}
+
+// RemoveChunkDataPackProofNode removes the leaf node of the trie proofs of chunk data packs with
+// the given hash.
+func RemoveChunkDataPackProofNode(nodeID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeChunkDataPackProofNode, nodeID))
+}
+
+// IndexChunkDataPackProofNode indexes a reference of the chunk data pack with the given chunk ID
+// to the proof node with the given hash. The references of a node are its reference count, which
+// is kept as an index, rather than a counter, so that references can be added in batches.
+func IndexChunkDataPackProofNode(nodeID flow.Identifier, chunkID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codeChunkDataPackProofNodeRef, nodeID, chunkID), chunkID)
+}
+
+// BatchIndexChunkDataPackProofNode indexes a reference of the chunk data pack with the given chunk
+// ID to the proof node with the given hash into a batch.
+func BatchIndexChunkDataPackProofNode(nodeID flow.Identifier, chunkID flow.Identifier) func(batch *badger.WriteBatch) error {
+	return batchInsert(makePrefix(codeChunkDataPackProofNodeRef, nodeID, chunkID), chunkID)
+}
+
+// LookupChunkDataPackProofNodeReferences finds the IDs of all chunks whose chunk data packs
+// reference the proof node with the given hash.
+func LookupChunkDataPackProofNodeReferences(nodeID flow.Identifier, chunkIDs *[]flow.Identifier) func(*badger.Txn) error {
+	return traverse(makePrefix(codeChunkDataPackProofNodeRef, nodeID), lookup(chunkIDs))
+}
+
+// RemoveChunkDataPackProofNodeReference removes the reference of the chunk data pack with the
+// given chunk ID to the proof node with the given hash.
+func RemoveChunkDataPackProofNodeReference(nodeID flow.Identifier, chunkID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeChunkDataPackProofNodeRef, nodeID, chunkID))
+}
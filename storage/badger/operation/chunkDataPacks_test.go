@@ -13,10 +13,15 @@ import (
 
 func TestChunkDataPack(t *testing.T) {
 	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		expected := unittest.ChunkDataPackFixture(unittest.IdentifierFixture())
+		expected := &StoredChunkDataPack{
+			ChunkID:      unittest.IdentifierFixture(),
+			StartState:   unittest.StateCommitmentFixture(),
+			Proofs:       []StoredTrieProof{{Proof: []byte{'p'}, NodeID: unittest.IdentifierFixture()}},
+			CollectionID: unittest.IdentifierFixture(),
+		}
 
 		t.Run("Retrieve non-existent", func(t *testing.T) {
-			var actual flow.ChunkDataPack
+			var actual StoredChunkDataPack
 			err := db.View(RetrieveChunkDataPack(expected.ChunkID, &actual))
 			assert.Error(t, err)
 		})
@@ -25,7 +30,7 @@ func TestChunkDataPack(t *testing.T) {
 			err := db.Update(InsertChunkDataPack(expected))
 			require.NoError(t, err)
 
-			var actual flow.ChunkDataPack
+			var actual StoredChunkDataPack
 			err = db.View(RetrieveChunkDataPack(expected.ChunkID, &actual))
 			assert.NoError(t, err)
 
@@ -36,9 +41,54 @@ func TestChunkDataPack(t *testing.T) {
 			err := db.Update(RemoveChunkDataPack(expected.ChunkID))
 			require.NoError(t, err)
 
-			var actual flow.ChunkDataPack
+			var actual StoredChunkDataPack
 			err = db.View(RetrieveChunkDataPack(expected.ChunkID, &actual))
 			assert.Error(t, err)
 		})
 	})
 }
+
+func TestChunkDataPackProofNode(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		expected := []byte{'n'}
+		nodeID := flow.MakeID(expected)
+
+		var actual []byte
+		err := db.View(RetrieveChunkDataPackProofNode(nodeID, &actual))
+		assert.Error(t, err)
+
+		err = db.Update(InsertChunkDataPackProofNode(nodeID, expected))
+		require.NoError(t, err)
+
+		err = db.View(RetrieveChunkDataPackProofNode(nodeID, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		err = db.Update(RemoveChunkDataPackProofNode(nodeID))
+		require.NoError(t, err)
+
+		err = db.View(RetrieveChunkDataPackProofNode(nodeID, &actual))
+		assert.Error(t, err)
+	})
+}
+
+func TestChunkDataPackProofNodeReferences(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		nodeID := unittest.IdentifierFixture()
+		otherNodeID := unittest.IdentifierFixture()
+		chunkIDs := unittest.IdentifierListFixture(2)
+
+		for _, chunkID := range chunkIDs {
+			require.NoError(t, db.Update(IndexChunkDataPackProofNode(nodeID, chunkID)))
+		}
+		require.NoError(t, db.Update(IndexChunkDataPackProofNode(otherNodeID, unittest.IdentifierFixture())))
+
+		var references []flow.Identifier
+		require.NoError(t, db.View(LookupChunkDataPackProofNodeReferences(nodeID, &references)))
+		assert.ElementsMatch(t, chunkIDs, references)
+
+		require.NoError(t, db.Update(RemoveChunkDataPackProofNodeReference(nodeID, chunkIDs[0])))
+		require.NoError(t, db.View(LookupChunkDataPackProofNodeReferences(nodeID, &references)))
+		assert.Equal(t, []flow.Identifier{chunkIDs[1]}, references)
+	})
+}
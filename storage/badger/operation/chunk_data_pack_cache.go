@@ -0,0 +1,63 @@
+package operation
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func BatchInsertCachedChunkDataPack(c *flow.ChunkDataPack) func(batch *badger.WriteBatch) error {
+	return batchInsert(makePrefix(codeCachedChunkDataPack, c.ChunkID), c)
+}
+
+func RetrieveCachedChunkDataPack(chunkID flow.Identifier, c *flow.ChunkDataPack) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeCachedChunkDataPack, chunkID), c)
+}
+
+func BatchRemoveCachedChunkDataPack(chunkID flow.Identifier) func(batch *badger.WriteBatch) error {
+	return batchRemove(makePrefix(codeCachedChunkDataPack, chunkID))
+}
+
+// BatchIndexCachedChunkDataPack indexes the cached chunk data pack by the height of its block, along
+// with its size, into a batch.
+func BatchIndexCachedChunkDataPack(height uint64, chunkID flow.Identifier, size uint64) func(batch *badger.WriteBatch) error {
+	return batchInsert(makePrefix(codeCachedChunkDataPackByHeight, height, chunkID), size)
+}
+
+func BatchRemoveCachedChunkDataPackIndex(height uint64, chunkID flow.Identifier) func(batch *badger.WriteBatch) error {
+	return batchRemove(makePrefix(codeCachedChunkDataPackByHeight, height, chunkID))
+}
+
+// TraverseCachedChunkDataPacks calls the given function with the height, chunk ID and size of each
+// indexed chunk data pack of the cache, in ascending order of height.
+func TraverseCachedChunkDataPacks(fn func(height uint64, chunkID flow.Identifier, size uint64) error) func(*badger.Txn) error {
+	iterationFunc := func() (checkFunc, createFunc, handleFunc) {
+		var height uint64
+		var chunkID flow.Identifier
+		check := func(key []byte) bool {
+			// key is the code, followed by the height and the chunk ID
+			if len(key) != 1+8+len(chunkID) {
+				return false
+			}
+			height = binary.BigEndian.Uint64(key[1:9])
+			copy(chunkID[:], key[9:])
+			return true
+		}
+		var size uint64
+		create := func() interface{} {
+			return &size
+		}
+		handle := func() error {
+			err := fn(height, chunkID, size)
+			if err != nil {
+				return fmt.Errorf("could not handle cached chunk data pack %x: %w", chunkID, err)
+			}
+			return nil
+		}
+		return check, create, handle
+	}
+	return traverse(makePrefix(codeCachedChunkDataPackByHeight), iterationFunc)
+}
@@ -44,6 +44,18 @@ func batchInsert(key []byte, entity interface{}) func(writeBatch *badger.WriteBa
 	}
 }
 
+// batchRemove removes the entry under the given key in the badger write batch. If the key
+// doesn't exist, it does nothing.
+func batchRemove(key []byte) func(writeBatch *badger.WriteBatch) error {
+	return func(writeBatch *badger.WriteBatch) error {
+		err := writeBatch.Delete(key)
+		if err != nil {
+			return fmt.Errorf("could not delete data: %w", err)
+		}
+		return nil
+	}
+}
+
 // insert will encode the given entity using msgpack and will insert the resulting
 // binary data in the badger DB under the provided key. It will error if the
 // key already exists.
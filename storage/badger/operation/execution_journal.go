@@ -0,0 +1,36 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func InsertCollectionCheckpoint(blockID flow.Identifier, checkpoint *execution.CollectionCheckpoint) func(*badger.Txn) error {
+	return insert(makePrefix(codeCollectionCheckpoint, blockID, uint32(checkpoint.CollectionIndex)), checkpoint)
+}
+
+func RemoveCollectionCheckpoint(blockID flow.Identifier, collectionIndex int) func(*badger.Txn) error {
+	return remove(makePrefix(codeCollectionCheckpoint, blockID, uint32(collectionIndex)))
+}
+
+// LookupCollectionCheckpoints retrieves the checkpoints of the executed collections of a block, in
+// order of their collections.
+func LookupCollectionCheckpoints(blockID flow.Identifier, checkpoints *[]*execution.CollectionCheckpoint) func(*badger.Txn) error {
+	iterationFunc := func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var checkpoint execution.CollectionCheckpoint
+		create := func() interface{} {
+			return &checkpoint
+		}
+		handle := func() error {
+			*checkpoints = append(*checkpoints, &checkpoint)
+			return nil
+		}
+		return check, create, handle
+	}
+	return traverse(makePrefix(codeCollectionCheckpoint, blockID), iterationFunc)
+}
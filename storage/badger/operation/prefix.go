@@ -18,6 +18,7 @@ const (
 	codeStartedView           = 10 // latest view hotstuff started
 	codeVotedView             = 11 // latest view hotstuff voted on
 	codeRootQuorumCertificate = 12
+	codeVotedBlock            = 13 // block hotstuff voted for at the latest voted view
 
 	// code for heights with special meaning
 	codeFinalizedHeight         = 20 // latest finalized block height
@@ -26,6 +27,7 @@ const (
 	codeExecutedBlock           = 23 // latest executed block with max height
 	codeRootHeight              = 24 // the height of the first loaded block
 	codeLastCompleteBlockHeight = 25 // the height of the last block for which all collections were received
+	codeRegisterIndexHeight     = 26 // the height of the last block indexed by the register index
 
 	// codes for single entity storage
 	// 31 was used for identities before epochs
@@ -44,6 +46,7 @@ const (
 	codeBlockToSeal         = 41 // index mapping a block its last payload seal
 	codeCollectionReference = 42 // index reference block ID for collection
 	codeBlockValidity       = 43 // validity of block per HotStuff
+	codeBySealedBlockID     = 44 // index mapping a sealed block to the seal of it in a finalized block
 
 	// codes for indexing multiple identifiers by identifier
 	// NOTE: 51 was used for identity indexes before epochs
@@ -67,6 +70,15 @@ const (
 	codeJobQueue             = 71
 	codeJobQueuePointer      = 72
 
+	// durable queues of engines
+	codeDurableQueueElement = 75
+
+	// mempool entities persisted across restarts
+	codePendingIncorporatedResultSeal = 76
+
+	// register index
+	codeRegisterValue = 80 // register values, keyed by register and height of the block they were written in
+
 	// legacy codes (should be cleaned up)
 	codeChunkDataPack                = 100
 	codeCommit                       = 101
@@ -75,6 +87,12 @@ const (
 	codeTransactionResult            = 104
 	codeFinalizedCluster             = 105
 	codeServiceEvent                 = 106
+	codeTransactionResultIndex       = 107
+	codeChunkDataPackProofNode       = 108 // leaf nodes of the trie proofs of chunk data packs, keyed by their hash
+	codeCollectionCheckpoint         = 109 // checkpoints of the executed collections of blocks being executed
+	codeCachedChunkDataPack          = 110 // chunk data packs of recent blocks, in the chunk data pack cache
+	codeCachedChunkDataPackByHeight  = 111 // index of the chunk data pack cache, mapping height and chunk ID to size
+	codeChunkDataPackProofNodeRef    = 112 // references of chunk data packs to the proof nodes, keyed by node hash and chunk ID
 	codeIndexCollection              = 200
 	codeIndexExecutionResultByBlock  = 202
 	codeIndexCollectionByTransaction = 203
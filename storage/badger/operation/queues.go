@@ -0,0 +1,75 @@
+package operation
+
+import (
+	"encoding/binary"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// queuePrefix returns the prefix shared by all elements of the durable queue with the given name.
+// Queues are keyed by the hash of their name, so that all keys have the same length and the
+// elements of one queue can't be confused with the elements of another.
+func queuePrefix(queue string) []byte {
+	return makePrefix(codeDurableQueueElement, flow.MakeID(queue))
+}
+
+// InsertQueueElement inserts the element at the given index of the durable queue.
+func InsertQueueElement(queue string, index uint64, element []byte) func(*badger.Txn) error {
+	return insert(append(queuePrefix(queue), b(index)...), element)
+}
+
+// RetrieveQueueElement retrieves the element at the given index of the durable queue.
+func RetrieveQueueElement(queue string, index uint64, element *[]byte) func(*badger.Txn) error {
+	return retrieve(append(queuePrefix(queue), b(index)...), element)
+}
+
+// RemoveQueueElement removes the element at the given index of the durable queue.
+func RemoveQueueElement(queue string, index uint64) func(*badger.Txn) error {
+	return remove(append(queuePrefix(queue), b(index)...))
+}
+
+// LookupQueueBounds retrieves the lowest and the highest index of the elements stored for the
+// durable queue. It returns storage.ErrNotFound if the queue holds no elements.
+func LookupQueueBounds(queue string, first *uint64, last *uint64) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		prefix := queuePrefix(queue)
+
+		lookup := func(reverse bool) (uint64, bool) {
+			opts := badger.DefaultIteratorOptions
+			opts.PrefetchValues = false
+			opts.Reverse = reverse
+			opts.Prefix = prefix
+
+			it := tx.NewIterator(opts)
+			defer it.Close()
+
+			// in reverse mode, seek moves to the last key which is smaller or equal to the given key,
+			// so we seek past all indices of the queue
+			seek := prefix
+			if reverse {
+				seek = append(append([]byte{}, prefix...), b(^uint64(0))...)
+			}
+
+			it.Seek(seek)
+			if !it.ValidForPrefix(prefix) {
+				return 0, false
+			}
+
+			key := it.Item().Key()
+			return binary.BigEndian.Uint64(key[len(prefix):]), true
+		}
+
+		lowest, ok := lookup(false)
+		if !ok {
+			return storage.ErrNotFound
+		}
+		highest, _ := lookup(true)
+
+		*first = lowest
+		*last = highest
+		return nil
+	}
+}
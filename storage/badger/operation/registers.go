@@ -0,0 +1,73 @@
+package operation
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// registerPrefix returns the prefix shared by all indexed values of the given register.
+// Registers are keyed by their identifier, so that all keys of the index have the
+// same length and heights can be compared lexicographically.
+func registerPrefix(registerID flow.RegisterID) []byte {
+	return makePrefix(codeRegisterValue, flow.MakeID(registerID))
+}
+
+// IndexRegisterValue indexes the value a register was set to in the block at the given height.
+func IndexRegisterValue(registerID flow.RegisterID, height uint64, value flow.RegisterValue) func(*badger.Txn) error {
+	return insert(append(registerPrefix(registerID), b(height)...), value)
+}
+
+// BatchIndexRegisterValue indexes the value a register was set to in the block at the given height
+// as part of the given write batch.
+func BatchIndexRegisterValue(registerID flow.RegisterID, height uint64, value flow.RegisterValue) func(*badger.WriteBatch) error {
+	return batchInsert(append(registerPrefix(registerID), b(height)...), value)
+}
+
+// LookupRegisterValueAtHeight retrieves the value of the given register at the given height,
+// which is the value written in the highest indexed block at or below that height.
+// It returns storage.ErrNotFound if the register was not written at or before the height.
+func LookupRegisterValueAtHeight(registerID flow.RegisterID, height uint64, value *flow.RegisterValue) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		prefix := registerPrefix(registerID)
+
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = true
+		opts.Prefix = prefix
+
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		// in reverse mode, seek moves to the last key which is smaller or equal to the given key,
+		// i.e. the value of the register at the highest height not above the requested one
+		it.Seek(append(prefix, b(height)...))
+		if !it.ValidForPrefix(prefix) {
+			return storage.ErrNotFound
+		}
+
+		err := it.Item().Value(func(val []byte) error {
+			return msgpack.Unmarshal(val, value)
+		})
+		if err != nil {
+			return fmt.Errorf("could not decode register value: %w", err)
+		}
+
+		return nil
+	}
+}
+
+func InsertRegisterIndexHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeRegisterIndexHeight), height)
+}
+
+func UpdateRegisterIndexHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeRegisterIndexHeight), height)
+}
+
+func RetrieveRegisterIndexHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeRegisterIndexHeight), height)
+}
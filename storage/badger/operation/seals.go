@@ -46,6 +46,16 @@ func LookupBlockSeal(blockID flow.Identifier, sealID *flow.Identifier) func(*bad
 	return retrieve(makePrefix(codeBlockToSeal, blockID), &sealID)
 }
 
+// IndexFinalizedSealByBlockID indexes the seal of the given sealed block, included in a finalized block.
+func IndexFinalizedSealByBlockID(sealedBlockID flow.Identifier, sealID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codeBySealedBlockID, sealedBlockID), sealID)
+}
+
+// LookupBySealedBlockID finds the seal of the given sealed block, included in a finalized block.
+func LookupBySealedBlockID(sealedBlockID flow.Identifier, sealID *flow.Identifier) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeBySealedBlockID, sealedBlockID), sealID)
+}
+
 func InsertExecutionForkEvidence(conflictingSeals []*flow.IncorporatedResultSeal) func(*badger.Txn) error {
 	return insert(makePrefix(codeExecutionFork), conflictingSeals)
 }
@@ -57,3 +67,31 @@ func RemoveExecutionForkEvidence() func(*badger.Txn) error {
 func RetrieveExecutionForkEvidence(conflictingSeals *[]*flow.IncorporatedResultSeal) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeExecutionFork), conflictingSeals)
 }
+
+// InsertPendingIncorporatedResultSeal persists a candidate seal of the seals mempool.
+func InsertPendingIncorporatedResultSeal(irSeal *flow.IncorporatedResultSeal) func(*badger.Txn) error {
+	return insert(makePrefix(codePendingIncorporatedResultSeal, irSeal.ID()), irSeal)
+}
+
+// RemovePendingIncorporatedResultSeal removes a persisted candidate seal of the seals mempool.
+func RemovePendingIncorporatedResultSeal(irSealID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codePendingIncorporatedResultSeal, irSealID))
+}
+
+// FindPendingIncorporatedResultSeals retrieves all persisted candidate seals of the seals mempool.
+func FindPendingIncorporatedResultSeals(irSeals *[]*flow.IncorporatedResultSeal) func(*badger.Txn) error {
+	return traverse(makePrefix(codePendingIncorporatedResultSeal), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var irSeal flow.IncorporatedResultSeal
+		create := func() interface{} {
+			return &irSeal
+		}
+		handle := func() error {
+			*irSeals = append(*irSeals, &irSeal)
+			return nil
+		}
+		return check, create, handle
+	})
+}
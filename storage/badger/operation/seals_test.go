@@ -3,6 +3,7 @@
 package operation
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/dgraph-io/badger/v2"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -59,3 +61,67 @@ func TestSealIndexAndLookup(t *testing.T) {
 		assert.Equal(t, expected, actual)
 	})
 }
+
+func TestPendingIncorporatedResultSealInsertFindRemove(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		irSeals := unittest.IncorporatedResultSeal.Fixtures(3)
+
+		// the approvals collected for the incorporated results are not persisted, so the seals
+		// are compared by their seals and results
+		sealsOf := func(irSeals []*flow.IncorporatedResultSeal) []*flow.Seal {
+			seals := make([]*flow.Seal, 0, len(irSeals))
+			for _, irSeal := range irSeals {
+				seals = append(seals, irSeal.Seal)
+			}
+			return seals
+		}
+		idsOf := func(irSeals []*flow.IncorporatedResultSeal) []flow.Identifier {
+			ids := make([]flow.Identifier, 0, len(irSeals))
+			for _, irSeal := range irSeals {
+				ids = append(ids, irSeal.ID())
+			}
+			return ids
+		}
+
+		err := db.Update(func(tx *badger.Txn) error {
+			for _, irSeal := range irSeals {
+				if err := InsertPendingIncorporatedResultSeal(irSeal)(tx); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.Nil(t, err)
+
+		var actual []*flow.IncorporatedResultSeal
+		err = db.View(FindPendingIncorporatedResultSeals(&actual))
+		require.Nil(t, err)
+		assert.ElementsMatch(t, idsOf(irSeals), idsOf(actual))
+		assert.ElementsMatch(t, sealsOf(irSeals), sealsOf(actual))
+
+		err = db.Update(RemovePendingIncorporatedResultSeal(irSeals[0].ID()))
+		require.Nil(t, err)
+
+		actual = nil
+		err = db.View(FindPendingIncorporatedResultSeals(&actual))
+		require.Nil(t, err)
+		assert.ElementsMatch(t, idsOf(irSeals[1:]), idsOf(actual))
+	})
+}
+
+func TestSealIndexBySealedBlockID(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		seal := unittest.Seal.Fixture()
+
+		var sealID flow.Identifier
+		err := db.View(LookupBySealedBlockID(seal.BlockID, &sealID))
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+		err = db.Update(IndexFinalizedSealByBlockID(seal.BlockID, seal.ID()))
+		require.NoError(t, err)
+
+		err = db.View(LookupBySealedBlockID(seal.BlockID, &sealID))
+		require.NoError(t, err)
+		assert.Equal(t, seal.ID(), sealID)
+	})
+}
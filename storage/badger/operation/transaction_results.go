@@ -16,6 +16,12 @@ func BatchInsertTransactionResult(blockID flow.Identifier, transactionResult *fl
 	return batchInsert(makePrefix(codeTransactionResult, blockID, transactionResult.TransactionID), transactionResult)
 }
 
+// BatchIndexTransactionResult indexes the result of the transaction at the given index of the block's
+// execution order.
+func BatchIndexTransactionResult(blockID flow.Identifier, txIndex uint32, transactionResult *flow.TransactionResult) func(batch *badger.WriteBatch) error {
+	return batchInsert(makePrefix(codeTransactionResultIndex, blockID, txIndex), transactionResult)
+}
+
 func RetrieveTransactionResult(blockID flow.Identifier, transactionID flow.Identifier, transactionResult *flow.TransactionResult) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeTransactionResult, blockID, transactionID), transactionResult)
 }
@@ -39,3 +45,41 @@ func LookupTransactionResultsByBlockID(blockID flow.Identifier, txResults *[]flo
 
 	return traverse(makePrefix(codeTransactionResult, blockID), txErrIterFunc)
 }
+
+// LookupTransactionResultsByBlockIDUsingIndex retrieves the transaction results of the block in
+// execution order.
+func LookupTransactionResultsByBlockIDUsingIndex(blockID flow.Identifier, txResults *[]flow.TransactionResult) func(*badger.Txn) error {
+
+	txErrIterFunc := func() (checkFunc, createFunc, handleFunc) {
+		check := func(_ []byte) bool {
+			return true
+		}
+		var val flow.TransactionResult
+		create := func() interface{} {
+			return &val
+		}
+		handle := func() error {
+			*txResults = append(*txResults, val)
+			return nil
+		}
+		return check, create, handle
+	}
+
+	return traverse(makePrefix(codeTransactionResultIndex, blockID), txErrIterFunc)
+}
+
+// LookupTransactionResultsByBlockIDInExecutionOrder retrieves the transaction results of the block in
+// execution order. Blocks executed before the results were indexed by their position only hold the
+// results keyed by transaction ID, which are returned in the order of the transaction IDs instead.
+func LookupTransactionResultsByBlockIDInExecutionOrder(blockID flow.Identifier, txResults *[]flow.TransactionResult) func(*badger.Txn) error {
+	return func(txn *badger.Txn) error {
+		err := LookupTransactionResultsByBlockIDUsingIndex(blockID, txResults)(txn)
+		if err != nil {
+			return err
+		}
+		if len(*txResults) > 0 {
+			return nil
+		}
+		return LookupTransactionResultsByBlockID(blockID, txResults)(txn)
+	}
+}
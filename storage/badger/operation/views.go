@@ -35,3 +35,18 @@ func UpdateVotedView(chainID flow.ChainID, view uint64) func(*badger.Txn) error
 func RetrieveVotedView(chainID flow.ChainID, view *uint64) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeVotedView, chainID), view)
 }
+
+// InsertVotedBlock inserts the ID of the block voted for at the latest voted view into the database.
+func InsertVotedBlock(chainID flow.ChainID, blockID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codeVotedBlock, chainID), blockID)
+}
+
+// UpdateVotedBlock updates the ID of the block voted for at the latest voted view in the database.
+func UpdateVotedBlock(chainID flow.ChainID, blockID flow.Identifier) func(*badger.Txn) error {
+	return update(makePrefix(codeVotedBlock, chainID), blockID)
+}
+
+// RetrieveVotedBlock retrieves the ID of the block voted for at the latest voted view from the database.
+func RetrieveVotedBlock(chainID flow.ChainID, blockID *flow.Identifier) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeVotedBlock, chainID), blockID)
+}
@@ -0,0 +1,82 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// RegisterIndex implements the register index on top of badger. The index
+// keeps every value a register was written to, so that reads at any indexed
+// height are served without re-executing blocks.
+type RegisterIndex struct {
+	db *badger.DB
+}
+
+func NewRegisterIndex(db *badger.DB) *RegisterIndex {
+	return &RegisterIndex{
+		db: db,
+	}
+}
+
+// Store indexes the register updates in a write batch, as the first indexed block can hold the
+// full execution state, which doesn't fit in a single transaction. The height is only marked as
+// indexed once all updates are written, so that the updates of a partially stored block are never
+// read and are overwritten when the block is stored again.
+func (r *RegisterIndex) Store(height uint64, entries flow.RegisterEntries) error {
+	var latest uint64
+	err := r.db.View(operation.RetrieveRegisterIndexHeight(&latest))
+	first := errors.Is(err, storage.ErrNotFound)
+	if err != nil && !first {
+		return fmt.Errorf("could not retrieve latest indexed height: %w", err)
+	}
+	if !first && height != latest+1 {
+		return fmt.Errorf("can not index height %d, expected height %d", height, latest+1)
+	}
+
+	batch := NewBatch(r.db)
+	for _, entry := range entries {
+		err := operation.BatchIndexRegisterValue(entry.Key, height, entry.Value)(batch.GetWriter())
+		if err != nil {
+			return fmt.Errorf("could not index register (%s): %w", entry.Key.String(), err)
+		}
+	}
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush register updates: %w", err)
+	}
+
+	if first {
+		err = operation.RetryOnConflict(r.db.Update, operation.InsertRegisterIndexHeight(height))
+	} else {
+		err = operation.RetryOnConflict(r.db.Update, operation.UpdateRegisterIndexHeight(height))
+	}
+	if err != nil {
+		return fmt.Errorf("could not update latest indexed height: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RegisterIndex) ValueAtHeight(registerID flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+	var value flow.RegisterValue
+	err := r.db.View(operation.LookupRegisterValueAtHeight(registerID, height, &value))
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (r *RegisterIndex) LatestHeight() (uint64, error) {
+	var height uint64
+	err := r.db.View(operation.RetrieveRegisterIndexHeight(&height))
+	if err != nil {
+		return 0, err
+	}
+	return height, nil
+}
@@ -0,0 +1,84 @@
+package badger_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+// TestRegisterIndexValueAtHeight tests that register values are served from the highest
+// indexed height at or below the requested one
+func TestRegisterIndexValueAtHeight(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		index := badgerstorage.NewRegisterIndex(db)
+
+		_, err := index.LatestHeight()
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+		reg := flow.NewRegisterID("owner", "controller", "key")
+		other := flow.NewRegisterID("owner", "controller", "other")
+
+		err = index.Store(10, flow.RegisterEntries{{Key: reg, Value: []byte("a")}})
+		require.NoError(t, err)
+		err = index.Store(11, flow.RegisterEntries{{Key: other, Value: []byte("x")}})
+		require.NoError(t, err)
+		err = index.Store(12, flow.RegisterEntries{{Key: reg, Value: []byte("b")}})
+		require.NoError(t, err)
+
+		latest, err := index.LatestHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12), latest)
+
+		// before the first write, the register is unknown
+		_, err = index.ValueAtHeight(reg, 9)
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+		value, err := index.ValueAtHeight(reg, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("a"), value)
+
+		value, err = index.ValueAtHeight(reg, 11)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("a"), value)
+
+		value, err = index.ValueAtHeight(reg, 12)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("b"), value)
+
+		value, err = index.ValueAtHeight(reg, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("b"), value)
+
+		value, err = index.ValueAtHeight(other, 12)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("x"), value)
+	})
+}
+
+// TestRegisterIndexStoreOutOfOrder tests that heights must be indexed without gaps
+func TestRegisterIndexStoreOutOfOrder(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		index := badgerstorage.NewRegisterIndex(db)
+
+		err := index.Store(5, nil)
+		require.NoError(t, err)
+
+		err = index.Store(7, nil)
+		require.Error(t, err)
+
+		err = index.Store(5, nil)
+		require.Error(t, err)
+
+		err = index.Store(6, nil)
+		require.NoError(t, err)
+	})
+}
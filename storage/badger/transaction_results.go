@@ -63,15 +63,21 @@ func NewTransactionResults(collector module.CacheMetrics, db *badger.DB, transac
 	}
 }
 
-// BatchStore will store the transaction results for the given block ID in a batch
+// BatchStore will store the transaction results for the given block ID in a batch. The results are
+// expected in execution order, which is preserved by ByBlockID.
 func (tr *TransactionResults) BatchStore(blockID flow.Identifier, transactionResults []flow.TransactionResult, batch storage.BatchStorage) error {
 	writeBatch := batch.GetWriter()
 
-	for _, result := range transactionResults {
+	for i, result := range transactionResults {
 		err := operation.BatchInsertTransactionResult(blockID, &result)(writeBatch)
 		if err != nil {
 			return fmt.Errorf("cannot batch insert tx result: %w", err)
 		}
+
+		err = operation.BatchIndexTransactionResult(blockID, uint32(i), &result)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("cannot batch index tx result: %w", err)
+		}
 	}
 
 	batch.OnSucceed(func() {
@@ -99,3 +105,14 @@ func (tr *TransactionResults) ByBlockIDTransactionID(blockID flow.Identifier, tx
 	}
 	return &transactionResult, nil
 }
+
+// ByBlockID returns the transaction results for the given block ID, in execution order. The results of
+// blocks executed before the results were indexed by their position are ordered by transaction ID.
+func (tr *TransactionResults) ByBlockID(blockID flow.Identifier) ([]flow.TransactionResult, error) {
+	var txResults []flow.TransactionResult
+	err := tr.db.View(operation.LookupTransactionResultsByBlockIDInExecutionOrder(blockID, &txResults))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve transaction results: %w", err)
+	}
+	return txResults, nil
+}
@@ -1,8 +1,10 @@
 package badger_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/dgraph-io/badger/v2"
@@ -15,6 +17,7 @@ import (
 	"github.com/onflow/flow-go/utils/unittest"
 
 	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
 )
 
 func TestBatchStoringTransactionResults(t *testing.T) {
@@ -52,6 +55,44 @@ func TestBatchStoringTransactionResults(t *testing.T) {
 			require.Nil(t, err)
 			assert.Equal(t, txResult, *actual)
 		}
+
+		// the results of the block are returned in the order they were stored in
+		actual, err := newStore.ByBlockID(blockID)
+		require.NoError(t, err)
+		assert.Equal(t, txResults, actual)
+	})
+}
+
+// TestTransactionResultsByBlockIDWithoutIndex checks that the results of blocks stored before the results
+// were indexed by their position are still returned, ordered by transaction ID.
+func TestTransactionResultsByBlockIDWithoutIndex(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		metrics := metrics.NewNoopCollector()
+		store := bstorage.NewTransactionResults(metrics, db, 1000)
+
+		blockID := unittest.IdentifierFixture()
+		txResults := make([]flow.TransactionResult, 0)
+		writeBatch := bstorage.NewBatch(db)
+		for i := 0; i < 10; i++ {
+			result := flow.TransactionResult{
+				TransactionID: unittest.IdentifierFixture(),
+				ErrorMessage:  fmt.Sprintf("a runtime error %d", i),
+			}
+			txResults = append(txResults, result)
+
+			err := operation.BatchInsertTransactionResult(blockID, &result)(writeBatch.GetWriter())
+			require.NoError(t, err)
+		}
+		err := writeBatch.Flush()
+		require.NoError(t, err)
+
+		sort.Slice(txResults, func(i, j int) bool {
+			return bytes.Compare(txResults[i].TransactionID[:], txResults[j].TransactionID[:]) < 0
+		})
+
+		actual, err := store.ByBlockID(blockID)
+		require.NoError(t, err)
+		assert.Equal(t, txResults, actual)
 	})
 }
 
@@ -19,3 +19,19 @@ type ChunkDataPacks interface {
 	// ByChunkID returns the chunk data for the given a chunk ID.
 	ByChunkID(chunkID flow.Identifier) (*flow.ChunkDataPack, error)
 }
+
+// ChunkDataPackCache is a size-bounded cache of the chunk data packs of recent blocks, from which
+// execution nodes serve the chunk data pack requests of verification nodes. The chunk data packs
+// held by the cache are not held by the ChunkDataPacks storage.
+type ChunkDataPackCache interface {
+
+	// Store adds the chunk data packs of the block at the given height. The chunk data packs of the
+	// lowest heights are evicted to the ChunkDataPacks storage while the cache exceeds its size.
+	Store(height uint64, chunkDataPacks []*flow.ChunkDataPack) error
+
+	// ByChunkID returns the cached chunk data pack for the given chunk ID, or ErrNotFound.
+	ByChunkID(chunkID flow.Identifier) (*flow.ChunkDataPack, error)
+
+	// PruneUpToHeight drops the chunk data packs of the blocks up to the given height, included.
+	PruneUpToHeight(height uint64) error
+}
@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ExecutionJournal persists the checkpoints of the executed collections of blocks being executed, so
+// that their execution can be resumed from the last executed collection after a restart.
+type ExecutionJournal interface {
+
+	// Checkpoint persists the checkpoint of an executed collection of the block.
+	Checkpoint(blockID flow.Identifier, checkpoint *execution.CollectionCheckpoint) error
+
+	// Checkpoints returns the checkpoints of the executed collections of the block, in order of their
+	// collections.
+	Checkpoints(blockID flow.Identifier) ([]*execution.CollectionCheckpoint, error)
+
+	// Discard removes the checkpoints of the block.
+	Discard(blockID flow.Identifier) error
+}
@@ -28,6 +28,29 @@ func (_m *TransactionResults) BatchStore(blockID flow.Identifier, transactionRes
 	return r0
 }
 
+// ByBlockID provides a mock function with given fields: blockID
+func (_m *TransactionResults) ByBlockID(blockID flow.Identifier) ([]flow.TransactionResult, error) {
+	ret := _m.Called(blockID)
+
+	var r0 []flow.TransactionResult
+	if rf, ok := ret.Get(0).(func(flow.Identifier) []flow.TransactionResult); ok {
+		r0 = rf(blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flow.TransactionResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(flow.Identifier) error); ok {
+		r1 = rf(blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ByBlockIDTransactionID provides a mock function with given fields: blockID, transactionID
 func (_m *TransactionResults) ByBlockIDTransactionID(blockID flow.Identifier, transactionID flow.Identifier) (*flow.TransactionResult, error) {
 	ret := _m.Called(blockID, transactionID)
@@ -0,0 +1,19 @@
+package storage
+
+import "github.com/onflow/flow-go/model/flow"
+
+// RegisterIndex represents persistent storage for the history of register values,
+// indexed by the height of the block in which they were written.
+type RegisterIndex interface {
+
+	// Store indexes the register updates of the block at the given height and marks it as the
+	// latest indexed height. Blocks must be indexed in order of height, without gaps.
+	Store(height uint64, entries flow.RegisterEntries) error
+
+	// ValueAtHeight returns the value of the register at the given height, which is the
+	// value it was last written to in a block at or below the given height.
+	ValueAtHeight(registerID flow.RegisterID, height uint64) (flow.RegisterValue, error)
+
+	// LatestHeight returns the height of the latest indexed block.
+	LatestHeight() (uint64, error)
+}
@@ -18,4 +18,8 @@ type Seals interface {
 
 	// ByBlockID retrieves the last seal in the chain of seals for the block.
 	ByBlockID(sealedID flow.Identifier) (*flow.Seal, error)
+
+	// FinalizedSealForBlock retrieves the seal of the given block, which is included in a finalized
+	// block. It returns storage.ErrNotFound if the block is not sealed by a finalized block yet.
+	FinalizedSealForBlock(blockID flow.Identifier) (*flow.Seal, error)
 }
@@ -10,4 +10,7 @@ type TransactionResults interface {
 
 	// ByBlockIDTransactionID returns the transaction result for the given block ID and transaction ID
 	ByBlockIDTransactionID(blockID flow.Identifier, transactionID flow.Identifier) (*flow.TransactionResult, error)
+
+	// ByBlockID returns all transaction results for the given block ID, in execution order
+	ByBlockID(blockID flow.Identifier) ([]flow.TransactionResult, error)
 }
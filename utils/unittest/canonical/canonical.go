@@ -0,0 +1,181 @@
+// Package canonical provides deterministic fixtures for every entity type of
+// the flow model that implements ID(). The fixtures are used to detect changes
+// to the hashed representation of the model, which would change the ID of
+// existing entities and break compatibility with data produced by previous
+// releases.
+package canonical
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/rand"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/onflow/flow-go/model/encoding/json"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Seed is the seed of the random source used to build the canonical fixture of
+// each entity type. It must never be changed, as doing so changes every
+// canonical ID.
+const Seed = 20210517
+
+// Entity is an entity type of the flow model. Not all entity types implement
+// flow.Entity, as some of them don't have a checksum.
+type Entity interface {
+	// ID returns the ID of the entity.
+	ID() flow.Identifier
+}
+
+// Case describes how to build fixtures for one entity type.
+type Case struct {
+	// Name is the name of the entity type in the flow package.
+	Name string
+	// Generate builds a fixture for the entity type with all contents drawn
+	// from the given random source. It always returns a pointer to the entity.
+	Generate func(r *rand.Rand) Entity
+}
+
+// Fixture returns the canonical fixture of the entity type. It is identical on
+// every call, as long as the generator of the case is left unchanged.
+func (c Case) Fixture() Entity {
+	return c.Generate(rand.New(rand.NewSource(Seed)))
+}
+
+// Cases returns the cases of all entity types, sorted by name.
+func Cases() []Case {
+	cases := []Case{
+		{Name: "Attestation", Generate: func(r *rand.Rand) Entity { return attestation(r) }},
+		{Name: "Block", Generate: func(r *rand.Rand) Entity { return block(r) }},
+		{Name: "Chunk", Generate: func(r *rand.Rand) Entity { return chunk(r, 0) }},
+		{Name: "ChunkDataPack", Generate: func(r *rand.Rand) Entity { return chunkDataPack(r) }},
+		{Name: "Collection", Generate: func(r *rand.Rand) Entity { return collection(r) }},
+		{Name: "CollectionGuarantee", Generate: func(r *rand.Rand) Entity { return guarantee(r) }},
+		{Name: "EpochCommit", Generate: func(r *rand.Rand) Entity { return epochCommit(r) }},
+		{Name: "EpochSetup", Generate: func(r *rand.Rand) Entity { return epochSetup(r) }},
+		{Name: "Event", Generate: func(r *rand.Rand) Entity { return event(r) }},
+		{Name: "ExecutionReceipt", Generate: func(r *rand.Rand) Entity { return receipt(r) }},
+		{Name: "ExecutionReceiptMeta", Generate: func(r *rand.Rand) Entity { return receipt(r).Meta() }},
+		{Name: "ExecutionResult", Generate: func(r *rand.Rand) Entity { return result(r) }},
+		{Name: "Header", Generate: func(r *rand.Rand) Entity { return header(r) }},
+		{Name: "Identity", Generate: func(r *rand.Rand) Entity { return identity(r) }},
+		{Name: "IncorporatedResult", Generate: func(r *rand.Rand) Entity { return incorporatedResult(r) }},
+		{Name: "IncorporatedResultSeal", Generate: func(r *rand.Rand) Entity { return incorporatedResultSeal(r) }},
+		{Name: "LightCollection", Generate: func(r *rand.Rand) Entity { return lightCollection(r) }},
+		{Name: "ResultApproval", Generate: func(r *rand.Rand) Entity { return approval(r) }},
+		{Name: "ResultApprovalBody", Generate: func(r *rand.Rand) Entity { return approvalBody(r) }},
+		{Name: "RoleList", Generate: func(r *rand.Rand) Entity { return roles(r) }},
+		{Name: "Seal", Generate: func(r *rand.Rand) Entity { return seal(r) }},
+		{Name: "TransactionBody", Generate: func(r *rand.Rand) Entity { return transaction(r) }},
+		{Name: "TransactionResult", Generate: func(r *rand.Rand) Entity { return transactionResult(r) }},
+		{Name: "TransactionTiming", Generate: func(r *rand.Rand) Entity { return transactionTiming(r) }},
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		return cases[i].Name < cases[j].Name
+	})
+	return cases
+}
+
+// IDs returns the IDs of the canonical fixtures of all entity types, keyed by
+// the name of the entity type.
+func IDs() map[string]flow.Identifier {
+	ids := make(map[string]flow.Identifier)
+	for _, c := range Cases() {
+		ids[c.Name] = c.Fixture().ID()
+	}
+	return ids
+}
+
+// CheckDeterminism verifies that the ID of the given entity does not change
+// when it is computed repeatedly, or after the entity went through a JSON or a
+// msgpack encoding round trip. The entity must be a pointer, as returned by the
+// generators of the cases.
+func CheckDeterminism(entity Entity) error {
+	id := entity.ID()
+	if again := entity.ID(); again != id {
+		return fmt.Errorf("repeated ID computation is not stable (%x != %x)", again, id)
+	}
+
+	codec := json.NewEncoder()
+	data, err := codec.Encode(entity)
+	if err != nil {
+		return fmt.Errorf("could not encode json: %w", err)
+	}
+	decoded := empty(entity)
+	err = codec.Decode(data, decoded)
+	if err != nil {
+		return fmt.Errorf("could not decode json: %w", err)
+	}
+	if decodedID := decoded.ID(); decodedID != id {
+		return fmt.Errorf("json round trip changed ID (%x != %x)", decodedID, id)
+	}
+
+	data, err = msgpack.Marshal(entity)
+	if err != nil {
+		return fmt.Errorf("could not encode msgpack: %w", err)
+	}
+	decoded = empty(entity)
+	err = msgpack.Unmarshal(data, decoded)
+	if err != nil {
+		return fmt.Errorf("could not decode msgpack: %w", err)
+	}
+	if decodedID := decoded.ID(); decodedID != id {
+		return fmt.Errorf("msgpack round trip changed ID (%x != %x)", decodedID, id)
+	}
+
+	return nil
+}
+
+// empty returns a pointer to a new zero value of the type the given entity
+// points to.
+func empty(entity Entity) Entity {
+	return reflect.New(reflect.TypeOf(entity).Elem()).Interface().(Entity)
+}
+
+// EntityTypes parses the Go sources of the flow model in the given directory
+// and returns the sorted names of all types with an `ID() Identifier` method.
+func EntityTypes(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	sources := func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, dir, sources, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse model sources: %w", err)
+	}
+
+	var names []string
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv == nil || fn.Name.Name != "ID" {
+					continue
+				}
+				if fn.Type.Params.NumFields() != 0 || fn.Type.Results.NumFields() != 1 {
+					continue
+				}
+				res, ok := fn.Type.Results.List[0].Type.(*ast.Ident)
+				if !ok || res.Name != "Identifier" {
+					continue
+				}
+				recv := fn.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); ok {
+					names = append(names, ident.Name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,100 @@
+package canonical_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest/canonical"
+)
+
+// update regenerates the golden IDs from the current canonical fixtures. It
+// should only be used when a change to the hashed representation of an entity
+// is intentional, in which case the change must be noted in the release.
+var update = flag.Bool("update", false, "update the golden entity IDs")
+
+var goldenPath = filepath.Join("testdata", "ids.json")
+
+// fuzzRounds is the number of random fixtures checked for each entity type.
+const fuzzRounds = 20
+
+// TestCases_CoverAllEntities checks that there is a case for every entity type
+// of the flow model, so that new entities are covered by the ID checks.
+func TestCases_CoverAllEntities(t *testing.T) {
+	types, err := canonical.EntityTypes(filepath.Join("..", "..", "..", "model", "flow"))
+	require.NoError(t, err)
+	require.NotEmpty(t, types)
+
+	var names []string
+	for _, c := range canonical.Cases() {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, types, names, "every entity type with an ID needs a canonical case")
+}
+
+// TestCases_FixturesAreStable checks that building a canonical fixture twice
+// results in the same ID.
+func TestCases_FixturesAreStable(t *testing.T) {
+	for _, c := range canonical.Cases() {
+		assert.Equal(t, c.Fixture().ID(), c.Fixture().ID(), c.Name)
+	}
+}
+
+// TestCanonicalIDs checks the IDs of the canonical fixtures against the golden
+// values. A failure means that the hashed representation of an entity changed.
+func TestCanonicalIDs(t *testing.T) {
+	ids := canonical.IDs()
+
+	if *update {
+		data, err := json.MarshalIndent(ids, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0755))
+		require.NoError(t, ioutil.WriteFile(goldenPath, append(data, '\n'), 0644))
+		return
+	}
+
+	// the golden IDs are part of the repository, a missing file must not let changed IDs pass unnoticed
+	data, err := ioutil.ReadFile(goldenPath)
+	require.NoError(t, err, "could not read golden IDs at %s, run with -update to generate them", goldenPath)
+
+	var golden map[string]flow.Identifier
+	require.NoError(t, json.Unmarshal(data, &golden))
+
+	for name, id := range ids {
+		expected, ok := golden[name]
+		if !assert.True(t, ok, "missing golden ID for %s, run with -update", name) {
+			continue
+		}
+		assert.Equal(t, expected, id, "ID of canonical %s changed", name)
+	}
+	for name := range golden {
+		assert.Contains(t, ids, name, "golden ID for unknown entity %s", name)
+	}
+}
+
+// TestIDDeterminism checks that IDs of randomly generated entities are stable
+// across repeated computation and encoding round trips.
+func TestIDDeterminism(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("random seed: %d", seed)
+	r := rand.New(rand.NewSource(seed))
+
+	for _, c := range canonical.Cases() {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			require.NoError(t, canonical.CheckDeterminism(c.Fixture()))
+			for i := 0; i < fuzzRounds; i++ {
+				require.NoError(t, canonical.CheckDeterminism(c.Generate(r)))
+			}
+		})
+	}
+}
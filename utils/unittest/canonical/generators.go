@@ -0,0 +1,315 @@
+package canonical
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// NOTE: the generators below must only draw from the given random source, so
+// that fixtures built from the same seed are always identical. Changing the
+// order in which values are drawn changes the canonical fixtures.
+
+func identifier(r *rand.Rand) flow.Identifier {
+	var id flow.Identifier
+	_, _ = r.Read(id[:])
+	return id
+}
+
+func identifiers(r *rand.Rand, n int) []flow.Identifier {
+	ids := make([]flow.Identifier, 0, n)
+	for i := 0; i < n; i++ {
+		ids = append(ids, identifier(r))
+	}
+	return ids
+}
+
+func commitment(r *rand.Rand) flow.StateCommitment {
+	var commit flow.StateCommitment
+	_, _ = r.Read(commit[:])
+	return commit
+}
+
+func address(r *rand.Rand) flow.Address {
+	var addr flow.Address
+	_, _ = r.Read(addr[:])
+	return addr
+}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	_, _ = r.Read(b)
+	return b
+}
+
+func signature(r *rand.Rand) crypto.Signature {
+	return randomBytes(r, crypto.SignatureLenBLSBLS12381)
+}
+
+func signatures(r *rand.Rand, n int) []crypto.Signature {
+	sigs := make([]crypto.Signature, 0, n)
+	for i := 0; i < n; i++ {
+		sigs = append(sigs, signature(r))
+	}
+	return sigs
+}
+
+func timestamp(r *rand.Rand) time.Time {
+	return time.Unix(0, r.Int63()).UTC()
+}
+
+func publicKey(r *rand.Rand, algo crypto.SigningAlgorithm, seedLen int) crypto.PublicKey {
+	key, err := crypto.GeneratePrivateKey(algo, randomBytes(r, seedLen))
+	if err != nil {
+		panic(fmt.Sprintf("could not generate %s key: %s", algo, err))
+	}
+	return key.PublicKey()
+}
+
+func stakingKey(r *rand.Rand) crypto.PublicKey {
+	return publicKey(r, crypto.BLSBLS12381, crypto.KeyGenSeedMinLenBLSBLS12381)
+}
+
+func networkingKey(r *rand.Rand) crypto.PublicKey {
+	return publicKey(r, crypto.ECDSAP256, crypto.KeyGenSeedMinLenECDSAP256)
+}
+
+func header(r *rand.Rand) *flow.Header {
+	return &flow.Header{
+		ChainID:        flow.Mainnet,
+		ParentID:       identifier(r),
+		Height:         r.Uint64(),
+		PayloadHash:    identifier(r),
+		Timestamp:      timestamp(r),
+		View:           r.Uint64(),
+		ParentVoterIDs: identifiers(r, 3),
+		ParentVoterSig: signature(r),
+		ProposerID:     identifier(r),
+		ProposerSig:    signature(r),
+	}
+}
+
+func block(r *rand.Rand) *flow.Block {
+	rcpt := receipt(r)
+	payload := flow.Payload{
+		Guarantees: []*flow.CollectionGuarantee{guarantee(r), guarantee(r)},
+		Seals:      []*flow.Seal{seal(r)},
+		Receipts:   flow.ExecutionReceiptMetaList{rcpt.Meta()},
+		Results:    flow.ExecutionResultList{&rcpt.ExecutionResult},
+	}
+	b := &flow.Block{
+		Header: header(r),
+	}
+	b.SetPayload(payload)
+	return b
+}
+
+func guarantee(r *rand.Rand) *flow.CollectionGuarantee {
+	return &flow.CollectionGuarantee{
+		CollectionID:     identifier(r),
+		ReferenceBlockID: identifier(r),
+		TransactionCount: uint(r.Uint32()),
+		ByteSize:         r.Uint64(),
+		SignerIDs:        identifiers(r, 3),
+		Signature:        signature(r),
+	}
+}
+
+func transaction(r *rand.Rand) *flow.TransactionBody {
+	payer := address(r)
+	return &flow.TransactionBody{
+		ReferenceBlockID: identifier(r),
+		Script:           []byte("transaction { execute { log(\"canonical\") } }"),
+		Arguments:        [][]byte{randomBytes(r, 16), randomBytes(r, 16)},
+		GasLimit:         r.Uint64(),
+		ProposalKey: flow.ProposalKey{
+			Address:        payer,
+			KeyIndex:       r.Uint64(),
+			SequenceNumber: r.Uint64(),
+		},
+		Payer:       payer,
+		Authorizers: []flow.Address{payer, address(r)},
+		PayloadSignatures: []flow.TransactionSignature{
+			{Address: address(r), SignerIndex: 1, KeyIndex: r.Uint64(), Signature: randomBytes(r, 64)},
+		},
+		EnvelopeSignatures: []flow.TransactionSignature{
+			{Address: payer, SignerIndex: 0, KeyIndex: r.Uint64(), Signature: randomBytes(r, 64)},
+		},
+	}
+}
+
+func collection(r *rand.Rand) *flow.Collection {
+	return &flow.Collection{
+		Transactions: []*flow.TransactionBody{transaction(r), transaction(r)},
+	}
+}
+
+func lightCollection(r *rand.Rand) *flow.LightCollection {
+	return &flow.LightCollection{
+		Transactions: identifiers(r, 3),
+	}
+}
+
+func event(r *rand.Rand) *flow.Event {
+	return &flow.Event{
+		Type:             flow.EventType("A.0000000000000001.Canonical.Event"),
+		TransactionID:    identifier(r),
+		TransactionIndex: r.Uint32(),
+		EventIndex:       r.Uint32(),
+		Payload:          randomBytes(r, 32),
+	}
+}
+
+func transactionResult(r *rand.Rand) *flow.TransactionResult {
+	return &flow.TransactionResult{
+		TransactionID: identifier(r),
+		ErrorMessage:  fmt.Sprintf("canonical error %d", r.Uint32()),
+	}
+}
+
+func transactionTiming(r *rand.Rand) *flow.TransactionTiming {
+	return &flow.TransactionTiming{
+		TransactionID: identifier(r),
+		Received:      timestamp(r),
+		Finalized:     timestamp(r),
+		Executed:      timestamp(r),
+	}
+}
+
+func identity(r *rand.Rand) *flow.Identity {
+	all := flow.Roles()
+	return &flow.Identity{
+		NodeID:        identifier(r),
+		Address:       fmt.Sprintf("node-%d.flow.local:3569", r.Uint32()),
+		Role:          all[r.Intn(len(all))],
+		Stake:         r.Uint64(),
+		StakingPubKey: stakingKey(r),
+		NetworkPubKey: networkingKey(r),
+	}
+}
+
+func roles(r *rand.Rand) *flow.RoleList {
+	list := flow.RoleList{}
+	for _, role := range flow.Roles() {
+		if r.Intn(2) == 0 {
+			list = append(list, role)
+		}
+	}
+	return &list
+}
+
+func chunk(r *rand.Rand, index uint64) *flow.Chunk {
+	return &flow.Chunk{
+		ChunkBody: flow.ChunkBody{
+			CollectionIndex:      uint(index),
+			StartState:           commitment(r),
+			EventCollection:      identifier(r),
+			BlockID:              identifier(r),
+			TotalComputationUsed: r.Uint64(),
+			NumberOfTransactions: r.Uint64(),
+		},
+		Index:    index,
+		EndState: commitment(r),
+	}
+}
+
+func chunkDataPack(r *rand.Rand) *flow.ChunkDataPack {
+	return &flow.ChunkDataPack{
+		ChunkID:      identifier(r),
+		StartState:   commitment(r),
+		Proof:        randomBytes(r, 64),
+		CollectionID: identifier(r),
+	}
+}
+
+func epochSetup(r *rand.Rand) *flow.EpochSetup {
+	participants := flow.IdentityList{identity(r), identity(r), identity(r)}
+	firstView := r.Uint64() >> 1
+	return &flow.EpochSetup{
+		Counter:      r.Uint64(),
+		FirstView:    firstView,
+		FinalView:    firstView + uint64(r.Uint32()),
+		Participants: participants,
+		Assignments:  flow.AssignmentList{{participants[0].NodeID}, {participants[1].NodeID, participants[2].NodeID}},
+		RandomSource: randomBytes(r, flow.EpochSetupRandomSourceLength),
+	}
+}
+
+func epochCommit(r *rand.Rand) *flow.EpochCommit {
+	return &flow.EpochCommit{
+		Counter: r.Uint64(),
+		ClusterQCs: []flow.ClusterQCVoteData{
+			{SigData: signature(r), VoterIDs: identifiers(r, 2)},
+			{SigData: signature(r), VoterIDs: identifiers(r, 2)},
+		},
+		DKGGroupKey:        stakingKey(r),
+		DKGParticipantKeys: []crypto.PublicKey{stakingKey(r), stakingKey(r)},
+	}
+}
+
+func result(r *rand.Rand) *flow.ExecutionResult {
+	return &flow.ExecutionResult{
+		PreviousResultID: identifier(r),
+		BlockID:          identifier(r),
+		Chunks:           flow.ChunkList{chunk(r, 0), chunk(r, 1)},
+		ServiceEvents:    []flow.ServiceEvent{epochSetup(r).ServiceEvent(), epochCommit(r).ServiceEvent()},
+	}
+}
+
+func receipt(r *rand.Rand) *flow.ExecutionReceipt {
+	return &flow.ExecutionReceipt{
+		ExecutorID:        identifier(r),
+		ExecutionResult:   *result(r),
+		Spocks:            signatures(r, 2),
+		ExecutorSignature: signature(r),
+	}
+}
+
+func incorporatedResult(r *rand.Rand) *flow.IncorporatedResult {
+	return flow.NewIncorporatedResult(identifier(r), result(r))
+}
+
+func seal(r *rand.Rand) *flow.Seal {
+	return &flow.Seal{
+		BlockID:    identifier(r),
+		ResultID:   identifier(r),
+		FinalState: commitment(r),
+		AggregatedApprovalSigs: []flow.AggregatedSignature{
+			{VerifierSignatures: signatures(r, 2), SignerIDs: identifiers(r, 2)},
+		},
+	}
+}
+
+func incorporatedResultSeal(r *rand.Rand) *flow.IncorporatedResultSeal {
+	return &flow.IncorporatedResultSeal{
+		IncorporatedResult: incorporatedResult(r),
+		Seal:               seal(r),
+	}
+}
+
+func attestation(r *rand.Rand) *flow.Attestation {
+	return &flow.Attestation{
+		BlockID:           identifier(r),
+		ExecutionResultID: identifier(r),
+		ChunkIndex:        r.Uint64(),
+	}
+}
+
+func approvalBody(r *rand.Rand) *flow.ResultApprovalBody {
+	return &flow.ResultApprovalBody{
+		Attestation:          *attestation(r),
+		ApproverID:           identifier(r),
+		AttestationSignature: signature(r),
+		Spock:                signature(r),
+	}
+}
+
+func approval(r *rand.Rand) *flow.ResultApproval {
+	return &flow.ResultApproval{
+		Body:              *approvalBody(r),
+		VerifierSignature: signature(r),
+	}
+}
@@ -0,0 +1,150 @@
+package canonical
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Vector is the JSON representation of an entity shared with other
+// implementations of the flow model, such as the SDKs. Each vector holds the
+// JSON encoding of the entity together with its ID, so that other
+// implementations can check that they decode the entity and compute its ID the
+// same way.
+type Vector struct {
+	// Type is the name of the entity type in the flow package.
+	Type string `json:"type"`
+	// ID is the ID of the entity.
+	ID flow.Identifier `json:"id"`
+	// Entity is the JSON encoding of the entity.
+	Entity json.RawMessage `json:"entity"`
+}
+
+// NewVector creates the vector of the given entity, which must be a pointer to
+// an entity type covered by the cases, as returned by the fixture functions of
+// the unittest package.
+func NewVector(entity Entity) (*Vector, error) {
+	typ := reflect.TypeOf(entity)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("entity must be a pointer (got %T)", entity)
+	}
+	name := typ.Elem().Name()
+	_, err := newEntity(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode %s: %w", name, err)
+	}
+
+	v := &Vector{
+		Type:   name,
+		ID:     entity.ID(),
+		Entity: data,
+	}
+	return v, nil
+}
+
+// Decode decodes the entity of the vector, and checks that its ID matches the
+// ID of the vector.
+func (v *Vector) Decode() (Entity, error) {
+	entity, err := newEntity(v.Type)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(v.Entity, entity)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", v.Type, err)
+	}
+	if id := entity.ID(); id != v.ID {
+		return nil, fmt.Errorf("ID of decoded %s does not match vector (%x != %x)", v.Type, id, v.ID)
+	}
+	return entity, nil
+}
+
+// WriteVector writes the vector of the given entity as an indented JSON file to
+// the given path.
+func WriteVector(path string, entity Entity) error {
+	v, err := NewVector(entity)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode vector: %w", err)
+	}
+	err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write vector: %w", err)
+	}
+	return nil
+}
+
+// ReadVector reads the vector at the given path and returns its decoded entity.
+func ReadVector(path string) (Entity, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read vector: %w", err)
+	}
+	var v Vector
+	err = json.Unmarshal(data, &v)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode vector %s: %w", path, err)
+	}
+	entity, err := v.Decode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid vector %s: %w", path, err)
+	}
+	return entity, nil
+}
+
+// Export writes the vectors of the canonical fixtures of all entity types to
+// the given directory, one file per entity type named after the type.
+func Export(dir string) error {
+	for _, c := range Cases() {
+		err := WriteVector(filepath.Join(dir, c.Name+".json"), c.Fixture())
+		if err != nil {
+			return fmt.Errorf("could not export %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Load reads all vectors in the given directory and returns their decoded
+// entities, keyed by the file name without the extension.
+func Load(dir string) (map[string]Entity, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("could not list vectors: %w", err)
+	}
+	sort.Strings(paths)
+
+	entities := make(map[string]Entity, len(paths))
+	for _, path := range paths {
+		entity, err := ReadVector(path)
+		if err != nil {
+			return nil, err
+		}
+		entities[strings.TrimSuffix(filepath.Base(path), ".json")] = entity
+	}
+	return entities, nil
+}
+
+// newEntity returns a pointer to a new zero value of the entity type with the
+// given name.
+func newEntity(name string) (Entity, error) {
+	for _, c := range Cases() {
+		if c.Name == name {
+			return empty(c.Fixture()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown entity type %q", name)
+}
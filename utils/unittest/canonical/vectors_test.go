@@ -0,0 +1,104 @@
+package canonical_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+	"github.com/onflow/flow-go/utils/unittest/canonical"
+)
+
+// export writes the vectors of the canonical fixtures to the given directory,
+// for use by the test suites of other implementations of the flow model.
+var export = flag.String("export", "", "directory to export the canonical fixtures to as JSON vectors")
+
+// TestExport_RoundTrip checks that the exported vectors of the canonical
+// fixtures load back to entities with the canonical IDs.
+func TestExport_RoundTrip(t *testing.T) {
+	if *export != "" {
+		checkExport(t, *export)
+		return
+	}
+	unittest.RunWithTempDir(t, func(dir string) {
+		checkExport(t, dir)
+	})
+}
+
+func checkExport(t *testing.T, dir string) {
+	require.NoError(t, canonical.Export(dir))
+
+	entities, err := canonical.Load(dir)
+	require.NoError(t, err)
+
+	ids := canonical.IDs()
+	require.Len(t, entities, len(ids))
+	for name, id := range ids {
+		entity, ok := entities[name]
+		if !assert.True(t, ok, "missing vector for %s", name) {
+			continue
+		}
+		assert.Equal(t, id, entity.ID(), "ID of loaded %s changed", name)
+	}
+}
+
+// TestVector_Fixtures checks that fixtures of the unittest package can be
+// written as vectors and read back.
+func TestVector_Fixtures(t *testing.T) {
+	block := unittest.BlockFixture()
+	fixtures := map[string]flow.Entity{
+		"block":           &block,
+		"receipt":         unittest.ExecutionReceiptFixture(),
+		"seal":            unittest.Seal.Fixture(),
+		"chunk_data_pack": unittest.ChunkDataPackFixture(unittest.IdentifierFixture()),
+	}
+
+	unittest.RunWithTempDir(t, func(dir string) {
+		for name, entity := range fixtures {
+			path := filepath.Join(dir, name+".json")
+			require.NoError(t, canonical.WriteVector(path, entity))
+
+			loaded, err := canonical.ReadVector(path)
+			require.NoError(t, err)
+			assert.Equal(t, entity.ID(), loaded.ID(), name)
+		}
+
+		entities, err := canonical.Load(dir)
+		require.NoError(t, err)
+		assert.Len(t, entities, len(fixtures))
+	})
+}
+
+// TestVector_Invalid checks that vectors are rejected when the decoded entity
+// does not match the ID of the vector, or its type is unknown.
+func TestVector_Invalid(t *testing.T) {
+	v, err := canonical.NewVector(unittest.Seal.Fixture())
+	require.NoError(t, err)
+	assert.Equal(t, "Seal", v.Type)
+
+	_, err = v.Decode()
+	require.NoError(t, err)
+
+	v.ID = unittest.IdentifierFixture()
+	_, err = v.Decode()
+	assert.Error(t, err)
+
+	v.Type = "Unknown"
+	_, err = v.Decode()
+	assert.Error(t, err)
+
+	_, err = canonical.NewVector(flow.Seal{})
+	assert.Error(t, err)
+
+	unittest.RunWithTempDir(t, func(dir string) {
+		path := filepath.Join(dir, "broken.json")
+		require.NoError(t, ioutil.WriteFile(path, []byte(`{"type":"Seal","id":`), 0644))
+		_, err := canonical.ReadVector(path)
+		assert.Error(t, err)
+	})
+}
@@ -54,21 +54,23 @@ func (epoch EpochHeights) CommittedRange() []uint64 {
 
 // EpochBuilder is a testing utility for building epochs into chain state.
 type EpochBuilder struct {
-	t          *testing.T
-	state      protocol.MutableState
-	blocks     map[flow.Identifier]*flow.Block
-	built      map[uint64]EpochHeights
-	setupOpts  []func(*flow.EpochSetup)  // options to apply to the EpochSetup event
-	commitOpts []func(*flow.EpochCommit) // options to apply to the EpochCommit event
+	t            *testing.T
+	state        protocol.MutableState
+	blocks       map[flow.Identifier]*flow.Block
+	built        map[uint64]EpochHeights
+	participants map[uint64]flow.IdentityList // participants for specific epochs, keyed by epoch counter
+	setupOpts    []func(*flow.EpochSetup)     // options to apply to the EpochSetup event
+	commitOpts   []func(*flow.EpochCommit)    // options to apply to the EpochCommit event
 }
 
 func NewEpochBuilder(t *testing.T, state protocol.MutableState) *EpochBuilder {
 
 	builder := &EpochBuilder{
-		t:      t,
-		state:  state,
-		blocks: make(map[flow.Identifier]*flow.Block),
-		built:  make(map[uint64]EpochHeights),
+		t:            t,
+		state:        state,
+		blocks:       make(map[flow.Identifier]*flow.Block),
+		built:        make(map[uint64]EpochHeights),
+		participants: make(map[uint64]flow.IdentityList),
 	}
 	return builder
 }
@@ -89,6 +91,15 @@ func (builder *EpochBuilder) UsingCommitOpts(opts ...func(*flow.EpochCommit)) *E
 	return builder
 }
 
+// UsingEpochParticipants sets the participants for the epoch with the given
+// counter. When the EpochSetup event for that epoch is built, these participants
+// are used in place of the participants of the current epoch. Participants set
+// with UsingSetupOpts take precedence over those set here.
+func (builder *EpochBuilder) UsingEpochParticipants(counter uint64, participants flow.IdentityList) *EpochBuilder {
+	builder.participants[counter] = participants
+	return builder
+}
+
 // EpochHeights returns heights of each phase within about a built epoch.
 func (builder *EpochBuilder) EpochHeights(counter uint64) (EpochHeights, bool) {
 	epoch, ok := builder.built[counter]
@@ -174,9 +185,15 @@ func (builder *EpochBuilder) BuildEpoch() *EpochBuilder {
 		}
 	}
 
+	// use the participants configured for the next epoch, if any
+	participants, ok := builder.participants[counter+1]
+	if !ok {
+		participants = identities
+	}
+
 	// defaults for the EpochSetup event
 	setupDefaults := []func(*flow.EpochSetup){
-		WithParticipants(identities),
+		WithParticipants(participants),
 		SetupWithCounter(counter + 1),
 		WithFirstView(finalView + 1),
 		WithFinalView(finalView + 1000),
@@ -336,6 +353,17 @@ func (builder *EpochBuilder) CompleteEpoch() *EpochBuilder {
 	return builder
 }
 
+// BuildEpochs builds and completes n consecutive epochs, starting from the
+// current epoch. We must be in the Staking phase to call BuildEpochs. After it
+// returns, the finalized state is in the Staking phase of the epoch n epochs
+// after the current one.
+func (builder *EpochBuilder) BuildEpochs(n int) *EpochBuilder {
+	for i := 0; i < n; i++ {
+		builder.BuildEpoch().CompleteEpoch()
+	}
+	return builder
+}
+
 // addBlock adds the given block to the state by: extending the state,
 // finalizing the block, marking the block as valid, and caching the block.
 func (builder *EpochBuilder) addBlock(block *flow.Block) {
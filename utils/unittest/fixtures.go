@@ -122,24 +122,26 @@ func BlockFixture() flow.Block {
 
 func FullBlockFixture() flow.Block {
 	block := BlockFixture()
-	payload := block.Payload
-	payload.Seals = Seal.Fixtures(10)
-	payload.Results = []*flow.ExecutionResult{
-		ExecutionResultFixture(),
-		ExecutionResultFixture(),
-	}
-	payload.Receipts = []*flow.ExecutionReceiptMeta{
-		ExecutionReceiptFixture(WithResult(payload.Results[0])).Meta(),
-		ExecutionReceiptFixture(WithResult(payload.Results[1])).Meta(),
-	}
-
-	header := block.Header
-	header.PayloadHash = payload.Hash()
-
-	return flow.Block{
-		Header:  header,
-		Payload: payload,
+	payload := flow.NewPayloadBuilder().
+		WithSeals(Seal.Fixtures(10)...).
+		WithReceipts(
+			ExecutionReceiptFixture(WithResult(ExecutionResultFixture())),
+			ExecutionReceiptFixture(WithResult(ExecutionResultFixture())),
+		)
+
+	return blockFixture(block.Header, payload)
+}
+
+// blockFixture builds the block with the given header and payload, and panics if the payload is invalid.
+func blockFixture(header *flow.Header, payload *flow.PayloadBuilder) flow.Block {
+	block, err := flow.NewBlockBuilder().
+		WithHeader(header).
+		WithPayload(payload).
+		Build()
+	if err != nil {
+		panic(err)
 	}
+	return *block
 }
 
 func BlockFixtures(number int) []*flow.Block {
@@ -233,13 +235,8 @@ func WithReceipts(receipts ...*flow.ExecutionReceipt) func(*flow.Payload) {
 }
 
 func BlockWithParentFixture(parent *flow.Header) flow.Block {
-	payload := PayloadFixture()
 	header := BlockHeaderWithParentFixture(parent)
-	header.PayloadHash = payload.Hash()
-	return flow.Block{
-		Header:  &header,
-		Payload: &payload,
-	}
+	return blockFixture(&header, flow.NewPayloadBuilder())
 }
 
 func WithoutGuarantee(payload *flow.Payload) {
@@ -261,20 +258,16 @@ func BlockWithParentAndProposerFixture(parent *flow.Header, proposer flow.Identi
 
 func BlockWithParentAndSeal(
 	parent *flow.Header, sealed *flow.Header) *flow.Block {
-	block := BlockWithParentFixture(parent)
-	payload := flow.Payload{
-		Guarantees: nil,
-	}
+	header := BlockHeaderWithParentFixture(parent)
+	payload := flow.NewPayloadBuilder()
 
 	if sealed != nil {
-		payload.Seals = []*flow.Seal{
-			Seal.Fixture(
-				Seal.WithBlockID(sealed.ID()),
-			),
-		}
+		payload.WithSeals(Seal.Fixture(
+			Seal.WithBlockID(sealed.ID()),
+		))
 	}
 
-	block.SetPayload(payload)
+	block := blockFixture(&header, payload)
 	return &block
 }
 
@@ -407,14 +400,18 @@ func WithCollRef(refID flow.Identifier) func(*flow.CollectionGuarantee) {
 func WithCollection(collection *flow.Collection) func(guarantee *flow.CollectionGuarantee) {
 	return func(guarantee *flow.CollectionGuarantee) {
 		guarantee.CollectionID = collection.ID()
+		guarantee.TransactionCount = uint(collection.Len())
+		guarantee.ByteSize = collection.ByteSize()
 	}
 }
 
 func CollectionGuaranteeFixture(options ...func(*flow.CollectionGuarantee)) *flow.CollectionGuarantee {
 	guarantee := &flow.CollectionGuarantee{
-		CollectionID: IdentifierFixture(),
-		SignerIDs:    IdentifierListFixture(16),
-		Signature:    SignatureFixture(),
+		CollectionID:     IdentifierFixture(),
+		TransactionCount: 1,
+		ByteSize:         1024,
+		SignerIDs:        IdentifierListFixture(16),
+		Signature:        SignatureFixture(),
 	}
 	for _, option := range options {
 		option(guarantee)
@@ -453,10 +450,13 @@ func CollectionFixture(n int) flow.Collection {
 
 func CompleteCollectionFixture() *entity.CompleteCollection {
 	txBody := TransactionBodyFixture()
+	collection := flow.Collection{Transactions: []*flow.TransactionBody{&txBody}}
 	return &entity.CompleteCollection{
 		Guarantee: &flow.CollectionGuarantee{
-			CollectionID: flow.Collection{Transactions: []*flow.TransactionBody{&txBody}}.ID(),
-			Signature:    SignatureFixture(),
+			CollectionID:     collection.ID(),
+			TransactionCount: uint(collection.Len()),
+			ByteSize:         collection.ByteSize(),
+			Signature:        SignatureFixture(),
 		},
 		Transactions: []*flow.TransactionBody{&txBody},
 	}
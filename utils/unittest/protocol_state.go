@@ -68,6 +68,23 @@ func FinalizedProtocolStateWithParticipants(participants flow.IdentityList) (
 	return &block, snapshot, state, sealedSnapshot
 }
 
+// ProtocolStateAtEpoch returns a protocol state in which every block is part of the epoch
+// with the given counter.
+func ProtocolStateAtEpoch(counter uint64) *mockprotocol.State {
+	epoch := &mockprotocol.Epoch{}
+	epoch.On("Counter").Return(counter, nil)
+
+	epochs := &mockprotocol.EpochQuery{}
+	epochs.On("Current").Return(epoch)
+
+	snapshot := &mockprotocol.Snapshot{}
+	snapshot.On("Epochs").Return(epochs)
+
+	state := &mockprotocol.State{}
+	state.On("AtBlockID", mock.Anything).Return(snapshot)
+	return state
+}
+
 // SealBlock seals a block by building two blocks on it, the first containing
 // a receipt for the block, the second containing a seal for the block.
 // Returns the block containing the seal.